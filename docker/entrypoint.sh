@@ -0,0 +1,25 @@
+#!/bin/sh
+#
+# Entrypoint for the mcp-devtools container images.
+#
+#   docker run -i --rm IMAGE stdio [flags]          Serve MCP over stdin/stdout for a local MCP client
+#   docker run -p 18080:18080 IMAGE [http [flags]]  Serve Streamable HTTP on $PORT (the default)
+#   docker run IMAGE <command or flags>             Run mcp-devtools with the given arguments
+#
+# Nothing may be written to stdout here, in stdio mode it carries the MCP protocol.
+
+set -eu
+
+case "${1:-http}" in
+    stdio)
+        shift
+        exec /app/mcp-devtools --transport stdio "$@"
+        ;;
+    http)
+        [ $# -gt 0 ] && shift
+        exec /app/mcp-devtools --transport http --port "${PORT:-18080}" --base-url "${BASE_URL:-http://0.0.0.0}" "$@"
+        ;;
+    *)
+        exec /app/mcp-devtools "$@"
+        ;;
+esac
@@ -164,6 +164,11 @@ detect_arch() {
     esac
 }
 
+# Detect whether the C library is musl (e.g. Alpine), which needs the static musl build
+is_musl() {
+    [ -f /etc/alpine-release ] || ldd --version 2>&1 | grep -qi musl
+}
+
 # Get latest release version from GitHub
 get_latest_version() {
     local api_url="https://api.github.com/repos/${GITHUB_REPO}/releases/latest"
@@ -283,10 +288,12 @@ install_binary() {
     local install_dir="$4"
 
     # Construct filename based on OS
-    # Format: mcp-devtools-{os}-{arch} or mcp-devtools-windows-{arch}.exe
+    # Format: mcp-devtools-{os}-{arch}, mcp-devtools-linux-{arch}-musl or mcp-devtools-windows-{arch}.exe
     local filename
     if [ "$os" = "windows" ]; then
         filename="${BINARY_NAME}-${os}-${arch}.exe"
+    elif [ "$os" = "linux" ] && is_musl; then
+        filename="${BINARY_NAME}-${os}-${arch}-musl"
     else
         filename="${BINARY_NAME}-${os}-${arch}"
     fi
@@ -0,0 +1,332 @@
+// Package artifacts stores large tool outputs, such as converted documents, exports and crawl results,
+// on disk so that agents and users can retrieve them after the tool call that produced them. Each
+// artifact belongs to the MCP session that saved it and is removed once it expires.
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultTTL is how long artifacts are kept after they were saved
+	DefaultTTL = 7 * 24 * time.Hour
+
+	// TTLEnvVar overrides DefaultTTL, in hours
+	TTLEnvVar = "ARTIFACTS_TTL_HOURS"
+
+	// metadataFileName is the file in each artifact's directory describing it
+	metadataFileName = "metadata.json"
+
+	// localSession is the directory for artifacts saved outside an MCP session
+	localSession = "local"
+
+	// ScheduledSession is the directory for the outputs of scheduled tool runs, which belong to no
+	// MCP session
+	ScheduledSession = "scheduled"
+
+	// cacheDirName is the directory for tool caches, which are shared by every session
+	cacheDirName = "cache"
+)
+
+// sessionKey is the context key for a session set with WithSession
+type sessionKey struct{}
+
+// idPattern matches artifact IDs, so an ID from a tool argument cannot escape the session directory
+var idPattern = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// ErrNotFound is returned for an artifact that does not exist, has expired or belongs to another session
+var ErrNotFound = errors.New("artifact not found")
+
+// Artifact describes a stored tool output
+type Artifact struct {
+	ID          string    `json:"id"`
+	Tool        string    `json:"tool"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Description string    `json:"description,omitempty"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Path        string    `json:"path"`
+}
+
+// IsText reports whether the artifact's content can be returned as text
+func (a *Artifact) IsText() bool {
+	return strings.HasPrefix(a.ContentType, "text/") || slices.Contains([]string{
+		"application/json", "application/xml", "application/yaml", "application/x-ndjson",
+	}, a.ContentType)
+}
+
+// mu serialises saves and cleanups, so a cleanup does not remove a directory being written
+var mu sync.Mutex
+
+// Dir returns the directory artifacts are stored in
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "artifacts"), nil
+}
+
+// CacheDir returns the directory for a tool's cache of outputs that can be reused by any session, such
+// as converted documents. The tool manages and expires its own entries, which are not artifacts and
+// are not listed or removed with them.
+func CacheDir(tool string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, cacheDirName, safeName(tool)), nil
+}
+
+// TTL returns how long artifacts are kept
+func TTL() time.Duration {
+	if value := os.Getenv(TTLEnvVar); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
+			return time.Duration(hours) * time.Hour
+		}
+	}
+	return DefaultTTL
+}
+
+// Save stores data as an artifact of the calling session. The name is used as the file name, so
+// it should have an extension matching the content type, e.g. report.md.
+func Save(ctx context.Context, tool, name, contentType, description string, data []byte) (*Artifact, error) {
+	dir, err := sessionDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	cleanupLocked()
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	artifactDir := filepath.Join(dir, id)
+	if err := os.MkdirAll(artifactDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	now := time.Now()
+	artifact := &Artifact{
+		ID:          id,
+		Tool:        tool,
+		Name:        safeName(name),
+		ContentType: contentType,
+		Description: description,
+		Size:        int64(len(data)),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(TTL()),
+	}
+	artifact.Path = filepath.Join(artifactDir, artifact.Name)
+	if err := os.WriteFile(artifact.Path, data, 0600); err != nil {
+		_ = os.RemoveAll(artifactDir)
+		return nil, fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	metadata, err := json.MarshalIndent(artifact, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(artifactDir, metadataFileName), metadata, 0600)
+	}
+	if err != nil {
+		_ = os.RemoveAll(artifactDir)
+		return nil, fmt.Errorf("failed to write artifact metadata: %w", err)
+	}
+	return artifact, nil
+}
+
+// List returns the calling session's artifacts, newest first
+func List(ctx context.Context) ([]Artifact, error) {
+	dir, err := sessionDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	cleanupLocked()
+	mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts: %w", err)
+	}
+
+	var result []Artifact
+	for _, entry := range entries {
+		if !entry.IsDir() || !idPattern.MatchString(entry.Name()) {
+			continue
+		}
+		if artifact, err := readMetadata(filepath.Join(dir, entry.Name())); err == nil {
+			result = append(result, *artifact)
+		}
+	}
+	slices.SortFunc(result, func(a, b Artifact) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return result, nil
+}
+
+// Get returns one of the calling session's artifacts. The content is read separately with Read, as
+// artifacts may be too large to hold in memory at once.
+func Get(ctx context.Context, id string) (*Artifact, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrNotFound
+	}
+	dir, err := sessionDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := readMetadata(filepath.Join(dir, id))
+	if err != nil || time.Now().After(artifact.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return artifact, nil
+}
+
+// Read returns up to length bytes of an artifact's content starting at offset
+func Read(artifact *Artifact, offset, length int64) ([]byte, error) {
+	file, err := os.Open(artifact.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if offset >= artifact.Size {
+		return nil, nil
+	}
+	buf := make([]byte, min(length, artifact.Size-offset))
+	n, err := file.ReadAt(buf, offset)
+	if n < len(buf) && err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	return buf[:n], nil
+}
+
+// Cleanup removes expired artifacts from every session
+func Cleanup() {
+	mu.Lock()
+	defer mu.Unlock()
+	cleanupLocked()
+}
+
+// cleanupLocked removes expired artifacts and empty session directories. Caller must hold mu.
+func cleanupLocked() {
+	root, err := Dir()
+	if err != nil {
+		return
+	}
+	sessions, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if !session.IsDir() || session.Name() == cacheDirName {
+			continue
+		}
+		sessionPath := filepath.Join(root, session.Name())
+		entries, err := os.ReadDir(sessionPath)
+		if err != nil {
+			continue
+		}
+		remaining := len(entries)
+		for _, entry := range entries {
+			artifactDir := filepath.Join(sessionPath, entry.Name())
+			artifact, err := readMetadata(artifactDir)
+			// Directories without readable metadata are left alone, as they may not be ours
+			if err == nil && now.After(artifact.ExpiresAt) && os.RemoveAll(artifactDir) == nil {
+				remaining--
+			}
+		}
+		if remaining == 0 {
+			_ = os.Remove(sessionPath)
+		}
+	}
+}
+
+// readMetadata reads the metadata of the artifact in dir
+func readMetadata(dir string) (*Artifact, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metadataFileName))
+	if err != nil {
+		return nil, err
+	}
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, err
+	}
+	// The path is derived rather than trusted, in case the directory was moved
+	artifact.Path = filepath.Join(dir, safeName(artifact.Name))
+	return &artifact, nil
+}
+
+// WithSession returns a context whose artifacts are saved to and read from session rather than the
+// calling MCP session, such as ScheduledSession
+func WithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// sessionDir returns the directory for the calling session's artifacts
+func sessionDir(ctx context.Context) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	session := localSession
+	if override, ok := ctx.Value(sessionKey{}).(string); ok && override != "" {
+		session = safeName(override)
+	} else if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil && clientSession.SessionID() != "" {
+		session = safeName(clientSession.SessionID())
+	}
+	if session == cacheDirName {
+		session = "_" + cacheDirName
+	}
+	return filepath.Join(root, session), nil
+}
+
+// safeName reduces a name to characters that are safe in a file name
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, filepath.Base(name))
+	if strings.Trim(name, ".") == "" || name == metadataFileName {
+		return "artifact"
+	}
+	return name
+}
+
+// newID returns a unique, time ordered artifact ID
+func newID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,199 @@
+// Package bench runs representative tool workloads and reports their timings and allocations, so that
+// performance regressions can be caught between releases.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Workload is a representative operation to time
+type Workload struct {
+	Name        string
+	Description string
+	// Setup prepares any input files in dir and returns the operation to time. It returns a
+	// SkipError when the workload cannot run in this environment.
+	Setup func(ctx context.Context, dir string) (func(ctx context.Context) error, error)
+}
+
+// SkipError reports that a workload cannot run, e.g. because a prerequisite is missing
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return "skipped: " + e.Reason
+}
+
+// Result is the measurement of one workload
+type Result struct {
+	Name        string `json:"name"`
+	Iterations  int    `json:"iterations,omitempty"`
+	NsPerOp     int64  `json:"ns_per_op,omitempty"` // Median time per run
+	MinNs       int64  `json:"min_ns,omitempty"`
+	MaxNs       int64  `json:"max_ns,omitempty"`
+	BytesPerOp  uint64 `json:"bytes_per_op,omitempty"`
+	AllocsPerOp uint64 `json:"allocs_per_op,omitempty"`
+	Skipped     string `json:"skipped,omitempty"`
+}
+
+// Report is the result of a benchmark run, saved to compare against later runs
+type Report struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Platform  string    `json:"platform"`
+	Timestamp time.Time `json:"timestamp"`
+	Results   []Result  `json:"results"`
+}
+
+// Regression is a workload that got slower or allocates more than the baseline allowed
+type Regression struct {
+	Name     string  `json:"name"`
+	Metric   string  `json:"metric"` // ns_per_op or allocs_per_op
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"` // Percentage increase over the baseline
+}
+
+// Run times each workload over the given number of iterations, after one untimed warm-up run.
+// Input files are created in a temporary directory that is removed afterwards.
+func Run(ctx context.Context, workloads []Workload, iterations int, version string) (*Report, error) {
+	if iterations < 1 {
+		return nil, fmt.Errorf("iterations must be at least 1")
+	}
+
+	dir, err := os.MkdirTemp("", "mcp-devtools-bench-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	report := &Report{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Timestamp: time.Now().UTC(),
+	}
+	for _, workload := range workloads {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := runWorkload(ctx, workload, dir, iterations)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", workload.Name, err)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// runWorkload sets up and measures a single workload
+func runWorkload(ctx context.Context, workload Workload, dir string, iterations int) (Result, error) {
+	result := Result{Name: workload.Name}
+
+	workDir, err := os.MkdirTemp(dir, "workload-")
+	if err != nil {
+		return result, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	op, err := workload.Setup(ctx, workDir)
+	if skip, ok := errors.AsType[*SkipError](err); ok {
+		result.Skipped = skip.Reason
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("setup failed: %w", err)
+	}
+
+	// Warm up caches and lazily initialised state so the first timed run is not an outlier
+	if err := op(ctx); err != nil {
+		return result, err
+	}
+
+	durations := make([]int64, 0, iterations)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for range iterations {
+		start := time.Now()
+		if err := op(ctx); err != nil {
+			return result, err
+		}
+		durations = append(durations, time.Since(start).Nanoseconds())
+	}
+	runtime.ReadMemStats(&after)
+
+	slices.Sort(durations)
+	result.Iterations = iterations
+	result.NsPerOp = durations[len(durations)/2]
+	result.MinNs = durations[0]
+	result.MaxNs = durations[len(durations)-1]
+	result.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(iterations)
+	result.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(iterations)
+	return result, nil
+}
+
+// LoadReport reads a report saved with SaveReport
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark report: %w", err)
+	}
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// SaveReport writes a report as JSON
+func SaveReport(report *Report, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal benchmark report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write benchmark report: %w", err)
+	}
+	return nil
+}
+
+// Compare returns the workloads whose median time or allocations grew by more than threshold percent
+// over the baseline. Workloads that were skipped in either report are not compared.
+func Compare(baseline, current *Report, threshold float64) []Regression {
+	var regressions []Regression
+	for _, result := range current.Results {
+		index := slices.IndexFunc(baseline.Results, func(r Result) bool { return r.Name == result.Name })
+		if index < 0 || result.Skipped != "" || baseline.Results[index].Skipped != "" {
+			continue
+		}
+		base := baseline.Results[index]
+		for _, metric := range []struct {
+			name              string
+			baseline, current float64
+		}{
+			{"ns_per_op", float64(base.NsPerOp), float64(result.NsPerOp)},
+			{"allocs_per_op", float64(base.AllocsPerOp), float64(result.AllocsPerOp)},
+		} {
+			if metric.baseline <= 0 {
+				continue
+			}
+			change := (metric.current - metric.baseline) / metric.baseline * 100
+			if change > threshold {
+				regressions = append(regressions, Regression{
+					Name:     result.Name,
+					Metric:   metric.name,
+					Baseline: metric.baseline,
+					Current:  metric.current,
+					Change:   change,
+				})
+			}
+		}
+	}
+	return regressions
+}
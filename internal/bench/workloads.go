@@ -0,0 +1,191 @@
+package bench
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/excel"
+	"github.com/sammcj/mcp-devtools/internal/tools/filesystem"
+	"github.com/sirupsen/logrus"
+	"github.com/xuri/excelize/v2"
+)
+
+// Workload sizes, chosen to take tens to hundreds of milliseconds on a laptop
+const (
+	excelRows          = 20000
+	excelColumns       = 10
+	treeDirectories    = 20
+	treeSubdirectories = 10
+	treeFilesPerDir    = 10
+	securityPayloadKB  = 900
+)
+
+// reportFixture is the document converted by the process_document workload
+//
+//go:embed fixtures/report.html
+var reportFixture []byte
+
+// Workloads returns the standard benchmark workloads
+func Workloads() []Workload {
+	return []Workload{
+		{
+			Name:        "excel_read_all_data",
+			Description: fmt.Sprintf("Read a %d row by %d column workbook as CSV", excelRows, excelColumns),
+			Setup:       setupExcelRead,
+		},
+		{
+			Name:        "filesystem_directory_tree",
+			Description: fmt.Sprintf("Build the directory tree of %d files", treeDirectories*treeSubdirectories*treeFilesPerDir),
+			Setup:       setupDirectoryTree,
+		},
+		{
+			Name:        "process_document_html",
+			Description: "Convert the bundled HTML report to Markdown with docling",
+			Setup:       setupDocumentConversion,
+		},
+		{
+			Name:        "security_scan",
+			Description: fmt.Sprintf("Evaluate the default security rules against a %dKB payload", securityPayloadKB),
+			Setup:       setupSecurityScan,
+		},
+	}
+}
+
+// quietLogger returns a logger that discards output, as tools log each call
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func setupExcelRead(_ context.Context, dir string) (func(ctx context.Context) error, error) {
+	path := filepath.Join(dir, "large.xlsx")
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	writer, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, err
+	}
+	for row := 1; row <= excelRows; row++ {
+		values := make([]any, excelColumns)
+		for col := range values {
+			if col%2 == 0 {
+				values[col] = fmt.Sprintf("item-%d-%d", row, col)
+			} else {
+				values[col] = float64(row*col) / 7
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.SetRow(cell, values); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err := f.SaveAs(path); err != nil {
+		return nil, err
+	}
+
+	tool := &excel.ExcelTool{}
+	logger := quietLogger()
+	return func(ctx context.Context) error {
+		_, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+			"function": "read_all_data",
+			"filepath": path,
+			"options":  map[string]any{"format": "csv"},
+		})
+		return err
+	}, nil
+}
+
+func setupDirectoryTree(_ context.Context, dir string) (func(ctx context.Context) error, error) {
+	for i := range treeDirectories {
+		for j := range treeSubdirectories {
+			subdir := filepath.Join(dir, fmt.Sprintf("package-%02d", i), fmt.Sprintf("module-%02d", j))
+			if err := os.MkdirAll(subdir, 0700); err != nil {
+				return nil, err
+			}
+			for k := range treeFilesPerDir {
+				if err := os.WriteFile(filepath.Join(subdir, fmt.Sprintf("file-%02d.go", k)), []byte("package main\n"), 0600); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	tool := &filesystem.FileSystemTool{}
+	tool.LoadSecurityConfig()
+	tool.SetAllowedDirectories([]string{dir})
+	logger := quietLogger()
+	return func(ctx context.Context) error {
+		_, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+			"function": "directory_tree",
+			"options":  map[string]any{"path": dir},
+		})
+		return err
+	}, nil
+}
+
+func setupDocumentConversion(_ context.Context, dir string) (func(ctx context.Context) error, error) {
+	// The tool is only available when docling is installed
+	status, ok := registry.GetToolStatus("process_document")
+	if !ok || status.Tool == nil {
+		reason := "process_document is not available"
+		if ok {
+			reason = status.Reason
+		}
+		return nil, &SkipError{Reason: reason}
+	}
+
+	path := filepath.Join(dir, "report.html")
+	if err := os.WriteFile(path, reportFixture, 0600); err != nil {
+		return nil, err
+	}
+
+	tool := status.Tool
+	logger := quietLogger()
+	return func(ctx context.Context) error {
+		_, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+			"source":             path,
+			"profile":            "basic",
+			"return_inline_only": true,
+			"clear_file_cache":   true,
+		})
+		return err
+	}, nil
+}
+
+func setupSecurityScan(_ context.Context, _ string) (func(ctx context.Context) error, error) {
+	engine, err := security.NewRuleEngineFromConfig([]byte(security.GenerateDefaultConfig()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Typical fetched documentation: prose, shell snippets and code, none of which should match
+	var payload strings.Builder
+	for i := 0; payload.Len() < securityPayloadKB*1024; i++ {
+		fmt.Fprintf(&payload, "## Section %d\n\nThis section explains how to configure the service for production use.\n", i)
+		fmt.Fprintf(&payload, "```bash\ngo build -o bin/service-%d ./cmd/service\n```\n", i)
+		fmt.Fprintf(&payload, "```go\nfunc handler%d(w http.ResponseWriter, r *http.Request) { w.WriteHeader(%d) }\n```\n\n", i, 200+i%100)
+	}
+	content := payload.String()
+	source := security.SourceContext{Domain: "docs.example.com", URL: "https://docs.example.com/guide", Tool: "fetch_url"}
+
+	return func(context.Context) error {
+		_, err := engine.EvaluateContent(content, source)
+		return err
+	}, nil
+}
@@ -0,0 +1,203 @@
+// Package credentials stores the tokens and secrets that tools and the proxy authenticate with in the
+// OS keyring (macOS Keychain, Windows Credential Manager or the Secret Service on Linux), so that they
+// need not be kept in MCP client configuration or environment variables. Credentials are managed with
+// the `mcp-devtools credentials` command.
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Service is the service name credentials are stored under in the OS keyring
+const Service = "mcp-devtools"
+
+// Well-known credential names, each used when its environment variable is not set
+const (
+	// AlphaVantageKey authenticates the finance tool's Alpha Vantage requests, instead of ALPHAVANTAGE_API_KEY
+	AlphaVantageKey = "alphavantage-key"
+	// GitHubToken authenticates the github and release notes tools, instead of GITHUB_TOKEN
+	GitHubToken = "github-token"
+	// OpenMeteoKey authenticates the weather tool with Open-Meteo's commercial API, instead of OPEN_METEO_API_KEY
+	OpenMeteoKey = "open-meteo-key"
+	// StackExchangeKey raises the stackexchange tool's daily quota, instead of STACKEXCHANGE_API_KEY
+	StackExchangeKey = "stackexchange-key"
+)
+
+// ErrNotFound is returned when no credential has the requested name
+var ErrNotFound = errors.New("credential not found")
+
+var (
+	// indexMu serialises updates to the index of credential names
+	indexMu sync.Mutex
+
+	// lookups caches keyring lookups, as each can start a helper process or D-Bus call
+	lookups sync.Map
+)
+
+// lookupResult is a cached keyring lookup
+type lookupResult struct {
+	value string
+	err   error
+}
+
+// ProxyClientSecret returns the name of the OAuth client secret for a proxy upstream, used when the
+// upstream's configuration has a client ID but no client secret
+func ProxyClientSecret(upstream string) string {
+	return "proxy-" + upstream + "-client-secret"
+}
+
+// Get returns the credential with the given name. Credentials are read from the keyring once per
+// process, or again after this process changes them.
+func Get(name string) (string, error) {
+	if cached, ok := lookups.Load(name); ok {
+		result := cached.(lookupResult)
+		return result.value, result.err
+	}
+	value, err := keyring.Get(Service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		err = fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	lookups.Store(name, lookupResult{value: value, err: err})
+	return value, err
+}
+
+// Lookup returns the value of the environment variable envVar if it is set, otherwise the credential
+// with the given name, or an empty string if neither is available
+func Lookup(envVar, name string) string {
+	if value := os.Getenv(envVar); value != "" {
+		return value
+	}
+	value, err := Get(name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// Set stores a credential, replacing any existing credential with the same name
+func Set(name, value string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("credential %s has no value", name)
+	}
+	lookups.Delete(name)
+	if err := keyring.Set(Service, name, value); err != nil {
+		return fmt.Errorf("failed to store credential %s in the OS keyring: %w", name, err)
+	}
+	return updateIndex(func(names []string) []string {
+		if slices.Contains(names, name) {
+			return names
+		}
+		return append(names, name)
+	})
+}
+
+// Delete removes a credential
+func Delete(name string) error {
+	lookups.Delete(name)
+	err := keyring.Delete(Service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		err = fmt.Errorf("%w: %s", ErrNotFound, name)
+	} else if err != nil {
+		return fmt.Errorf("failed to delete credential %s from the OS keyring: %w", name, err)
+	}
+	// Drop the name from the index even if the keyring entry was already removed
+	if indexErr := updateIndex(func(names []string) []string {
+		return slices.DeleteFunc(names, func(existing string) bool { return existing == name })
+	}); indexErr != nil {
+		return indexErr
+	}
+	return err
+}
+
+// List returns the names of the stored credentials, sorted. The OS keyrings cannot be listed by
+// service, so the names (never the values) are kept in ~/.mcp-devtools/credentials.json.
+func List() ([]string, error) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	names, err := readIndex()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// validateName checks that a credential name is usable as a keyring account name
+func validateName(name string) error {
+	if name == "" || strings.TrimSpace(name) != name || strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("invalid credential name %q", name)
+	}
+	return nil
+}
+
+// updateIndex applies update to the index of credential names and saves it
+func updateIndex(update func([]string) []string) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	names, err := readIndex()
+	if err != nil {
+		return err
+	}
+	return writeIndex(update(names))
+}
+
+// indexPath returns the path of the index of credential names
+func indexPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "credentials.json"), nil
+}
+
+// readIndex reads the index of credential names, which is empty if it does not exist yet
+func readIndex() ([]string, error) {
+	path, err := indexPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials index: %w", err)
+	}
+	var index struct {
+		Names []string `json:"names"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials index %s: %w", path, err)
+	}
+	return index.Names, nil
+}
+
+// writeIndex saves the index of credential names
+func writeIndex(names []string) error {
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials index directory: %w", err)
+	}
+	data, err := json.MarshalIndent(struct {
+		Names []string `json:"names"`
+	}{Names: names}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
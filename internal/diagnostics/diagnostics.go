@@ -0,0 +1,192 @@
+// Package diagnostics keeps recent log entries and tool calls in memory so that an agent can find out
+// why a tool call failed without the user reading the log files. Entries are attributed to a session
+// by the tool that logged them and the time of that session's calls to the tool.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// Buffer sizes, enough for the recent history of a busy server without holding on to much memory
+const (
+	maxEntries = 1000
+	maxCalls   = 1000
+	// maxMessageLength truncates long log messages, e.g. ones that include a response body
+	maxMessageLength = 2000
+	// captureLevel is the most verbose level captured, so debug logging does not crowd out warnings
+	captureLevel = logrus.InfoLevel
+)
+
+// Entry is a captured log entry
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Tool    string         `json:"tool,omitempty"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// call is the time span of a tool call made by a session
+type call struct {
+	session string
+	tool    string
+	start   time.Time
+	end     time.Time
+}
+
+var (
+	enabled atomic.Bool
+	mu      sync.Mutex
+	entries []Entry
+	calls   []call
+)
+
+// Enable starts capturing entries logged by logger and recording tool calls. Tool loggers created
+// from logger afterwards inherit the capture.
+func Enable(logger *logrus.Logger) {
+	if enabled.Swap(true) {
+		return
+	}
+	logger.AddHook(&Hook{})
+}
+
+// Enabled reports whether diagnostics are being captured
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// SessionID returns the MCP session ID from the context, or an empty string outside a session
+func SessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// RecordCall records that a session called a tool between start and now
+func RecordCall(sessionID, tool string, start time.Time) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	calls = appendBounded(calls, call{session: sessionID, tool: tool, start: start, end: time.Now()}, maxCalls)
+}
+
+// Hook captures log entries at info level and above into memory. It only sees the entries the
+// logger's own level lets through.
+type Hook struct{}
+
+// Levels returns the levels the hook captures
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels[:captureLevel+1]
+}
+
+// Fire captures the entry
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	captured := Entry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: truncate(entry.Message),
+	}
+	for key, value := range entry.Data {
+		if key == "tool" {
+			captured.Tool = fmt.Sprint(value)
+			continue
+		}
+		if captured.Fields == nil {
+			captured.Fields = make(map[string]any, len(entry.Data))
+		}
+		// Errors are stored as text, as they do not marshal to JSON
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		captured.Fields[key] = value
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	entries = appendBounded(entries, captured, maxEntries)
+	return nil
+}
+
+// RecentEntries returns up to limit of the most recent log entries relevant to a session, oldest first:
+// entries logged by a tool during one of the session's calls to it, and server entries at warning level
+// or above since the session's first call. With an empty session ID, all recent entries are returned.
+func RecentEntries(sessionID string, limit int) []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var sessionCalls []call
+	var since time.Time
+	if sessionID != "" {
+		for _, c := range calls {
+			if c.session == sessionID {
+				sessionCalls = append(sessionCalls, c)
+			}
+		}
+		if len(sessionCalls) == 0 {
+			return nil
+		}
+		since = sessionCalls[0].start
+	}
+
+	var result []Entry
+	for i := len(entries) - 1; i >= 0 && len(result) < limit; i-- {
+		entry := entries[i]
+		if sessionID == "" || relevant(entry, sessionCalls, since) {
+			result = append(result, entry)
+		}
+	}
+
+	// Collected newest first
+	slices.Reverse(result)
+	return result
+}
+
+// relevant reports whether an entry belongs to one of a session's calls or is a server warning since
+// the session started
+func relevant(entry Entry, sessionCalls []call, since time.Time) bool {
+	if entry.Tool == "" {
+		level, err := logrus.ParseLevel(entry.Level)
+		return err == nil && level <= logrus.WarnLevel && !entry.Time.Before(since)
+	}
+	for _, c := range sessionCalls {
+		if c.tool == entry.Tool && !entry.Time.Before(c.start) && !entry.Time.After(c.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset clears the captured entries and calls
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = nil
+	calls = nil
+}
+
+// appendBounded appends to a slice, dropping the oldest items beyond limit
+func appendBounded[T any](items []T, item T, limit int) []T {
+	if len(items) >= limit {
+		items = append(items[:0], items[len(items)-limit+1:]...)
+	}
+	return append(items, item)
+}
+
+// truncate shortens a message to maxMessageLength
+func truncate(message string) string {
+	if len(message) <= maxMessageLength {
+		return message
+	}
+	return message[:maxMessageLength] + "... (truncated)"
+}
@@ -0,0 +1,65 @@
+// Package doctor checks the runtime prerequisites of tools, such as external programs, credentials and
+// upstream services, so that problems can be fixed before an agent runs into them.
+package doctor
+
+import (
+	"context"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+)
+
+// Result is a prerequisite check for a tool
+type Result struct {
+	Tool string `json:"tool"`
+	tools.PrerequisiteCheck
+}
+
+// Run checks the prerequisites of the enabled tools, or of every known tool when all is set. Tools
+// that could not be created because a prerequisite is missing are reported as failed when the
+// environment enables them.
+func Run(ctx context.Context, all bool) []Result {
+	var results []Result
+	for _, status := range registry.GetToolStatuses() {
+		if status.Tool == nil {
+			if all || registry.ShouldRegisterTool(status.Name) {
+				results = append(results, Result{
+					Tool: status.Name,
+					PrerequisiteCheck: tools.PrerequisiteCheck{
+						Name:   "availability",
+						Status: tools.PrerequisiteFailed,
+						Detail: status.Reason,
+					},
+				})
+			}
+			continue
+		}
+		if !status.Enabled && !all {
+			continue
+		}
+
+		checker, ok := status.Tool.(tools.PrerequisiteChecker)
+		if !ok {
+			continue
+		}
+		for _, check := range checker.CheckPrerequisites(ctx) {
+			results = append(results, Result{Tool: status.Name, PrerequisiteCheck: check})
+		}
+	}
+	return results
+}
+
+// Summarise counts the results by status
+func Summarise(results []Result) (ok, warnings, failed int) {
+	for _, result := range results {
+		switch result.Status {
+		case tools.PrerequisiteOK:
+			ok++
+		case tools.PrerequisiteWarning:
+			warnings++
+		default:
+			failed++
+		}
+	}
+	return ok, warnings, failed
+}
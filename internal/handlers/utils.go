@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,9 +22,7 @@ type HTTPClient interface {
 
 var (
 	// DefaultHTTPClient is the default HTTP client
-	DefaultHTTPClient HTTPClient = &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	DefaultHTTPClient HTTPClient = httpclient.NewHTTPClientWithProxy(30 * time.Second)
 )
 
 // MakeRequest makes an HTTP request and returns the response body
@@ -4,34 +4,81 @@ import (
 	// Standard tools - always available
 	_ "github.com/sammcj/mcp-devtools/internal/tools/api"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/aws_documentation"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/calc"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/calculator"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/checklinks"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/claudeagent"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/code_rename"
 
 	// codeskim is conditionally imported in tools_codeskim.go based on platform support
 	_ "github.com/sammcj/mcp-devtools/internal/tools/aceternityui"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/buildtargets"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/clipboard"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/codexagent"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/containers"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/copilotagent"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/csvtool"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/database"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/depgraph"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/diagram"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/docsindex"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/docslookup"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/encode"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/envinfo"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/excel"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/feed"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/filelength"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/filesystem"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/finance"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/forge"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/geminiagent"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/generateid"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/github"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/imagetool"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/internetsearch/unified"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/kiroagent"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/lint"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/logtool"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/m2e"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/magicui"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/markdown"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/memory"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/netdiag"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/notify"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/openapi"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/packagedocs"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/packageversions/unified"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/pdf"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/powerpoint"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/proofread"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/proxy"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/regextest"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/releasenotes"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/scheduledruns"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/screenshot"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/securityoverride"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/securitytest"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/sequentialthinking"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/shadcnui"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/stackexchange"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/tasks"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/terraform"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/terraform_documentation"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/testrunner"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/textdiff"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/think"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/timetool"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/transform"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/usagetool"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/artifacts"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/batch"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/clearcache"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/diagnostics"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/exportsession"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/fetchmore"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/utilities/toolhelp"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/weather"
 	_ "github.com/sammcj/mcp-devtools/internal/tools/webfetch"
+	_ "github.com/sammcj/mcp-devtools/internal/tools/wikipedia"
 )
@@ -0,0 +1,203 @@
+// Package logging configures where and how the server logs: the log file and its rotation, the log
+// format, and per-tool log levels and files. Logs are only ever written to files so that stdio mode
+// never writes to stdout or stderr.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults for log rotation
+const (
+	DefaultMaxSizeMB  = 10
+	DefaultMaxBackups = 5
+)
+
+// LogFileName is the name of the main application log file
+const LogFileName = "mcp-devtools.log"
+
+// Config is the logging configuration, read from the environment by ConfigFromEnv
+type Config struct {
+	Format    string // text or json
+	Rotation  RotationConfig
+	ToolFiles bool // Write each tool's logs to its own file under tools/
+}
+
+// ConfigFromEnv reads the logging configuration from LOG_FORMAT, LOG_MAX_SIZE_MB, LOG_MAX_BACKUPS,
+// LOG_ROTATE_INTERVAL and LOG_TOOL_FILES. Invalid values fall back to the defaults.
+func ConfigFromEnv() Config {
+	config := Config{
+		Format: "text",
+		Rotation: RotationConfig{
+			MaxSize:    DefaultMaxSizeMB * 1024 * 1024,
+			MaxBackups: DefaultMaxBackups,
+		},
+		ToolFiles: os.Getenv("LOG_TOOL_FILES") == "true",
+	}
+
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("LOG_FORMAT")), "json") {
+		config.Format = "json"
+	}
+	if value, err := strconv.Atoi(os.Getenv("LOG_MAX_SIZE_MB")); err == nil && value >= 0 {
+		config.Rotation.MaxSize = int64(value) * 1024 * 1024
+	}
+	if value, err := strconv.Atoi(os.Getenv("LOG_MAX_BACKUPS")); err == nil && value >= 0 {
+		config.Rotation.MaxBackups = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("LOG_ROTATE_INTERVAL")); err == nil && value > 0 {
+		config.Rotation.Interval = value
+	}
+	return config
+}
+
+// Formatter returns the logrus formatter for the configured format
+func (c Config) Formatter() logrus.Formatter {
+	if c.Format == "json" {
+		return &logrus.JSONFormatter{}
+	}
+	return &logrus.TextFormatter{FullTimestamp: true}
+}
+
+// Dir returns the directory logs are written to, ~/.mcp-devtools/logs
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "logs"), nil
+}
+
+// ToolLogPath returns the path of a tool's own log file, used when LOG_TOOL_FILES is enabled
+func ToolLogPath(dir, tool string) string {
+	return filepath.Join(dir, "tools", tool+".log")
+}
+
+// ParseLevel parses a log level name, reporting false for unknown names
+func ParseLevel(value string) (logrus.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return logrus.DebugLevel, true
+	case "info":
+		return logrus.InfoLevel, true
+	case "warn", "warning":
+		return logrus.WarnLevel, true
+	case "error":
+		return logrus.ErrorLevel, true
+	case "fatal":
+		return logrus.FatalLevel, true
+	case "panic":
+		return logrus.PanicLevel, true
+	default:
+		return logrus.WarnLevel, false
+	}
+}
+
+// ToolLevelEnvVar returns the environment variable that overrides a tool's log level, e.g.
+// LOG_LEVEL_EXCEL for excel and LOG_LEVEL_INTERNET_SEARCH for internet_search
+func ToolLevelEnvVar(tool string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, tool)
+	return "LOG_LEVEL_" + strings.ToUpper(name)
+}
+
+var (
+	toolLoggersMu sync.Mutex
+	toolLoggers   = make(map[string]*logrus.Logger)
+	toolFiles     []*RotatingFile
+	toolConfig    *Config
+	toolDir       string
+)
+
+// ConfigureTools enables per-tool log files in dir when config.ToolFiles is set. It must be called
+// before the first ForTool call for the files to be used.
+func ConfigureTools(config Config, dir string) {
+	toolLoggersMu.Lock()
+	defer toolLoggersMu.Unlock()
+	toolConfig = &config
+	toolDir = dir
+}
+
+// ForTool returns the logger passed to a tool's Execute. It writes to the same output as base,
+// or to the tool's own file when enabled, with the same hooks, adds a tool field to each entry,
+// and uses the level from the tool's LOG_LEVEL_<TOOL> variable when set. Loggers are created once
+// per tool. A nil base, as before the registry is initialised, gives a logger that discards output.
+func ForTool(base *logrus.Logger, tool string) *logrus.Logger {
+	if base == nil {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		return logger
+	}
+
+	toolLoggersMu.Lock()
+	defer toolLoggersMu.Unlock()
+
+	if logger, ok := toolLoggers[tool]; ok {
+		return logger
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(base.Out)
+	logger.SetFormatter(base.Formatter)
+	logger.SetLevel(base.GetLevel())
+	// The tool field is added first so that hooks inherited from base see it
+	logger.AddHook(toolFieldHook{tool: tool})
+	for _, level := range logrus.AllLevels {
+		logger.Hooks[level] = append(logger.Hooks[level], base.Hooks[level]...)
+	}
+
+	if level, ok := ParseLevel(os.Getenv(ToolLevelEnvVar(tool))); ok {
+		logger.SetLevel(level)
+	}
+	if toolConfig != nil && toolConfig.ToolFiles && toolDir != "" {
+		// Fall back to the shared output if the tool's file cannot be opened
+		if file, err := OpenRotatingFile(ToolLogPath(toolDir, tool), toolConfig.Rotation); err == nil {
+			toolFiles = append(toolFiles, file)
+			logger.SetOutput(file)
+		} else {
+			base.WithError(err).WithField("tool", tool).Warn("Failed to open tool log file")
+		}
+	}
+
+	toolLoggers[tool] = logger
+	return logger
+}
+
+// Close closes the per-tool log files
+func Close() {
+	toolLoggersMu.Lock()
+	defer toolLoggersMu.Unlock()
+	for _, file := range toolFiles {
+		_ = file.Close()
+	}
+	toolFiles = nil
+	clear(toolLoggers)
+}
+
+// toolFieldHook adds the tool name to every entry logged by a tool
+type toolFieldHook struct {
+	tool string
+}
+
+func (h toolFieldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h toolFieldHook) Fire(entry *logrus.Entry) error {
+	if _, ok := entry.Data["tool"]; !ok {
+		entry.Data["tool"] = h.tool
+	}
+	return nil
+}
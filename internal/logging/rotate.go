@@ -0,0 +1,178 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RotationConfig controls when a log file is rotated and how many rotated files are kept
+type RotationConfig struct {
+	MaxSize    int64         // Rotate once the file would grow beyond this many bytes, 0 disables
+	Interval   time.Duration // Rotate once the file has been written to for this long, 0 disables
+	MaxBackups int           // Number of rotated files to keep, e.g. mcp-devtools.log.1 to .5
+}
+
+// RotatingFile is a log file that is rotated by size and age. Rotated files are renamed with a numeric
+// suffix, .1 being the most recent, and the oldest beyond MaxBackups are removed.
+type RotatingFile struct {
+	path   string
+	config RotationConfig
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+	closed  bool
+}
+
+// OpenRotatingFile opens or creates the log file at path, rotating it first if it is already due
+func OpenRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	r := &RotatingFile{path: path, config: config}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	// A file left by a previous run is aged from its last write, as creation times are not portable
+	if info, err := r.file.Stat(); err == nil && info.Size() > 0 {
+		r.started = info.ModTime()
+		if r.due(0) {
+			if err := r.rotate(); err != nil {
+				_ = r.file.Close()
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// Path returns the path of the current log file
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// Write appends p to the log file, rotating it first if needed
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.due(int64(len(p))) {
+		// Another server sharing the log directory may have rotated it already, in which case
+		// writing continues in the file it created
+		if r.replaced() {
+			_ = r.file.Close()
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		if r.due(int64(len(p))) {
+			// A failed rotation leaves the existing file open, which is still written to
+			if err := r.rotate(); err != nil && r.file == nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens the log file for appending. Caller must hold r.mu or own r exclusively.
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	r.started = time.Now()
+	return nil
+}
+
+// due reports whether writing n more bytes calls for a rotation. An empty file is never rotated.
+func (r *RotatingFile) due(n int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.config.MaxSize > 0 && r.size+n > r.config.MaxSize {
+		return true
+	}
+	return r.config.Interval > 0 && time.Since(r.started) >= r.config.Interval
+}
+
+// replaced reports whether the path now refers to a different file than the one open
+func (r *RotatingFile) replaced() bool {
+	current, err := r.file.Stat()
+	if err != nil {
+		return true
+	}
+	onDisk, err := os.Stat(r.path)
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(current, onDisk)
+}
+
+// rotate shifts the rotated files along, moves the current file to .1 and opens a new one
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file for rotation: %w", err)
+	}
+	r.file = nil
+
+	if r.config.MaxBackups < 1 {
+		_ = os.Remove(r.path)
+	} else {
+		_ = os.Remove(r.backupPath(r.config.MaxBackups))
+		for i := r.config.MaxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(r.backupPath(i), r.backupPath(i+1))
+		}
+		if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+			// Keep logging to the existing file rather than losing entries
+			if openErr := r.open(); openErr != nil {
+				return openErr
+			}
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	return r.open()
+}
+
+// backupPath returns the path of the nth most recent rotated file
+func (r *RotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// followInterval is how often a followed log file is checked for new lines
+const followInterval = 500 * time.Millisecond
+
+// LastLines returns up to n of the last lines of the file at path
+func LastLines(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if n <= 0 {
+			continue
+		}
+		if len(lines) == n {
+			lines = append(lines[:0], lines[1:]...)
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log file: %w", err)
+	}
+	return lines, nil
+}
+
+// Tail writes the last n lines of the log file at path to w. When follow is set it keeps writing
+// lines as they are appended, reopening the file when it is rotated, until ctx is cancelled.
+func Tail(ctx context.Context, path string, n int, follow bool, w io.Writer) error {
+	lines, err := LastLines(path, n)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if !follow {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to seek log file: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	// drain writes complete lines only, leaving a partial write for the next check
+	drain := func() error {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				if len(line) > 0 {
+					if _, seekErr := file.Seek(-int64(len(line)), io.SeekCurrent); seekErr == nil {
+						reader.Reset(file)
+					}
+				}
+				return nil
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	for {
+		if err := drain(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// After a rotation the path refers to a new file, which is read from the start once the
+		// last lines of the rotated file are written
+		current, statErr := file.Stat()
+		onDisk, err := os.Stat(path)
+		if err != nil || statErr != nil || os.SameFile(current, onDisk) {
+			continue
+		}
+		next, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if err := drain(); err != nil {
+			_ = next.Close()
+			return err
+		}
+		_ = file.Close()
+		file = next
+		reader.Reset(file)
+	}
+}
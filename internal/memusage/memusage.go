@@ -0,0 +1,199 @@
+// Package memusage samples the server's memory use, logs tool calls that allocate more than a
+// per-call budget, and refuses memory intensive tool calls while the process is close to its Go
+// memory limit (GOMEMLIMIT), so one large document or spreadsheet does not leave the garbage
+// collector thrashing, and every other session waiting, as the server approaches the limit.
+package memusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BudgetEnvVar overrides defaultBudgetMB, with 0 turning budget logging off
+	BudgetEnvVar = "MEMORY_CALL_BUDGET_MB"
+
+	// ThrottleEnvVar overrides defaultThrottlePercent, with 0 turning throttling off
+	ThrottleEnvVar = "MEMORY_THROTTLE_PERCENT"
+
+	// defaultBudgetMB is how much a single tool call may allocate, in MB, before it is logged
+	defaultBudgetMB = 512
+
+	// defaultThrottlePercent is the share of the memory limit, in percent, above which memory
+	// intensive calls are refused
+	defaultThrottlePercent = 90
+
+	// sampleInterval is how often Start samples memory use
+	sampleInterval = 5 * time.Second
+)
+
+// The runtime/metrics samples read. The memory limit counts all memory the runtime holds, less
+// heap memory it has returned to the operating system.
+const (
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+	totalMetric       = "/memory/classes/total:bytes"
+	releasedMetric    = "/memory/classes/heap/released:bytes"
+	allocsMetric      = "/gc/heap/allocs:bytes"
+)
+
+// ErrLowMemory is returned for memory intensive calls made while the server is close to its memory limit
+var ErrLowMemory = errors.New("server is low on memory")
+
+var (
+	peakInUse     atomic.Uint64
+	rejectedCalls atomic.Int64
+	overBudget    atomic.Int64
+
+	// sampleMu serialises reads of the runtime metrics into samples
+	sampleMu sync.Mutex
+	samples  = []metrics.Sample{
+		{Name: heapObjectsMetric},
+		{Name: totalMetric},
+		{Name: releasedMetric},
+	}
+)
+
+// Stats are the server's memory figures, in bytes
+type Stats struct {
+	HeapBytes       uint64 `json:"heap_bytes"`               // Heap objects, live or not yet collected
+	InUseBytes      uint64 `json:"in_use_bytes"`             // Memory counted against the memory limit
+	PeakInUseBytes  uint64 `json:"peak_in_use_bytes"`        // Highest InUseBytes seen since the server started
+	LimitBytes      int64  `json:"limit_bytes,omitempty"`    // The Go memory limit, omitted when there is none
+	ThrottleBytes   int64  `json:"throttle_bytes,omitempty"` // Memory intensive calls are refused above this
+	Throttling      bool   `json:"throttling"`
+	RejectedCalls   int64  `json:"rejected_calls"`
+	OverBudgetCalls int64  `json:"over_budget_calls"`
+}
+
+// Read samples the server's memory use now
+func Read() Stats {
+	sampleMu.Lock()
+	metrics.Read(samples)
+	heap := samples[0].Value.Uint64()
+	inUse := samples[1].Value.Uint64() - samples[2].Value.Uint64()
+	sampleMu.Unlock()
+
+	for {
+		peak := peakInUse.Load()
+		if inUse <= peak || peakInUse.CompareAndSwap(peak, inUse) {
+			break
+		}
+	}
+
+	stats := Stats{
+		HeapBytes:       heap,
+		InUseBytes:      inUse,
+		PeakInUseBytes:  peakInUse.Load(),
+		RejectedCalls:   rejectedCalls.Load(),
+		OverBudgetCalls: overBudget.Load(),
+	}
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		stats.LimitBytes = limit
+		if percent := throttlePercent(); percent > 0 {
+			stats.ThrottleBytes = limit / 100 * int64(percent)
+			stats.Throttling = inUse >= uint64(stats.ThrottleBytes)
+		}
+	}
+	return stats
+}
+
+// CheckHeadroom returns an error wrapping ErrLowMemory if the server is too close to its memory
+// limit to start a memory intensive call
+func CheckHeadroom() error {
+	stats := Read()
+	if !stats.Throttling {
+		return nil
+	}
+	rejectedCalls.Add(1)
+	return fmt.Errorf("%w: %s of its %s memory limit is in use, so memory intensive calls are refused until other calls finish; retry shortly",
+		ErrLowMemory, formatMB(stats.InUseBytes), formatMB(uint64(stats.LimitBytes)))
+}
+
+// Allocated returns the bytes allocated by the whole process so far, to measure a call with CheckBudget
+func Allocated() uint64 {
+	sample := []metrics.Sample{{Name: allocsMetric}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
+
+// CheckBudget logs a warning if the server allocated more than the per-call budget since start,
+// a value from Allocated taken when the call began. Calls running at the same time are counted
+// together, so the figure is an upper bound for any one of them.
+func CheckBudget(logger *logrus.Logger, name string, start uint64) {
+	budgetMB := envInt(BudgetEnvVar, defaultBudgetMB)
+	allocated := Allocated() - start
+	if budgetMB <= 0 || allocated <= uint64(budgetMB)<<20 {
+		return
+	}
+	overBudget.Add(1)
+	logger.WithFields(logrus.Fields{
+		"tool":         name,
+		"allocated_mb": allocated >> 20,
+		"budget_mb":    budgetMB,
+	}).Warn("Tool call exceeded its memory budget")
+}
+
+// Start samples memory use until ctx is done, so the peak is recorded between calls, and logs
+// when the server starts and stops refusing memory intensive calls
+func Start(ctx context.Context, logger *logrus.Logger) {
+	go func() {
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+		throttling := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			stats := Read()
+			if stats.Throttling == throttling {
+				continue
+			}
+			throttling = stats.Throttling
+			fields := logrus.Fields{
+				"in_use_mb": stats.InUseBytes >> 20,
+				"limit_mb":  stats.LimitBytes >> 20,
+			}
+			if throttling {
+				logger.WithFields(fields).Warn("Memory use is close to the memory limit, refusing memory intensive tool calls")
+			} else {
+				logger.WithFields(fields).Info("Memory use has fallen, accepting memory intensive tool calls again")
+			}
+		}
+	}()
+}
+
+// envInt returns a non-negative integer environment variable, or def when it is unset or invalid
+func envInt(name string, def int) int {
+	if value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name))); err == nil && value >= 0 {
+		return value
+	}
+	return def
+}
+
+// throttlePercent returns the share of the memory limit above which memory intensive calls are
+// refused, or 0 when throttling is off
+func throttlePercent() int {
+	if percent := envInt(ThrottleEnvVar, defaultThrottlePercent); percent <= 100 {
+		return percent
+	}
+	return defaultThrottlePercent
+}
+
+// formatMB formats a number of bytes in whole MB
+func formatMB(bytes uint64) string {
+	return fmt.Sprintf("%d MB", bytes>>20)
+}
@@ -0,0 +1,287 @@
+// Package access maps OAuth token claims to per-user tool permissions, allowed directories and call
+// quotas, so that a single HTTP deployment can serve several users with different levels of access.
+package access
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/oauth/types"
+	"gopkg.in/yaml.v3"
+)
+
+// quotaWindow is the period call quotas apply to
+const quotaWindow = time.Hour
+
+// Rule describes what a user, group or unmatched caller may do
+type Rule struct {
+	Tools              []string `yaml:"tools,omitempty"`               // Tools that may be used, "*" for all
+	DenyTools          []string `yaml:"deny_tools,omitempty"`          // Tools that may not be used, even when matched by tools
+	AllowedDirectories []string `yaml:"allowed_directories,omitempty"` // Limits filesystem access, empty for no additional limit
+	CallsPerHour       int      `yaml:"calls_per_hour,omitempty"`      // Tool call quota, 0 for unlimited
+}
+
+// Policy is the access policy file. A caller matching a user entry gets that rule, otherwise the
+// rules of every matching group apply, otherwise the default rule.
+type Policy struct {
+	Default Rule            `yaml:"default"`
+	Users   map[string]Rule `yaml:"users,omitempty"`  // Keyed by the sub or username claim
+	Groups  map[string]Rule `yaml:"groups,omitempty"` // Keyed by the groups or authorities claims
+
+	quotas   map[string]*quotaUsage
+	quotasMu sync.Mutex
+}
+
+// quotaUsage counts a caller's tool calls in the current window
+type quotaUsage struct {
+	windowStart time.Time
+	calls       int
+}
+
+// Permissions are the rules that apply to a caller
+type Permissions struct {
+	Identity string // The caller's sub or username claim, empty when unauthenticated
+	rules    []Rule
+}
+
+var (
+	currentPolicy   *Policy
+	currentPolicyMu sync.RWMutex
+)
+
+// LoadPolicy reads and validates an access policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access policy: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses and validates an access policy
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse access policy: %w", err)
+	}
+
+	if err := policy.Default.normalise(); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	for name, rule := range policy.Users {
+		if err := rule.normalise(); err != nil {
+			return nil, fmt.Errorf("user %s: %w", name, err)
+		}
+		policy.Users[name] = rule
+	}
+	for name, rule := range policy.Groups {
+		if err := rule.normalise(); err != nil {
+			return nil, fmt.Errorf("group %s: %w", name, err)
+		}
+		policy.Groups[name] = rule
+	}
+	policy.quotas = make(map[string]*quotaUsage)
+	return policy, nil
+}
+
+// normalise validates a rule and expands its allowed directories to clean absolute paths
+func (r *Rule) normalise() error {
+	if r.CallsPerHour < 0 {
+		return fmt.Errorf("calls_per_hour must not be negative")
+	}
+	for i, dir := range r.AllowedDirectories {
+		if strings.HasPrefix(dir, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			dir = filepath.Join(home, dir[2:])
+		}
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("allowed directory %s must be an absolute path", dir)
+		}
+		r.AllowedDirectories[i] = filepath.Clean(dir)
+	}
+	return nil
+}
+
+// SetPolicy sets the policy enforced for tool calls, nil disables per-user access control
+func SetPolicy(policy *Policy) {
+	currentPolicyMu.Lock()
+	defer currentPolicyMu.Unlock()
+	currentPolicy = policy
+}
+
+// CurrentPolicy returns the enforced policy, or nil when per-user access control is disabled
+func CurrentPolicy() *Policy {
+	currentPolicyMu.RLock()
+	defer currentPolicyMu.RUnlock()
+	return currentPolicy
+}
+
+// PermissionsFor returns the rules that apply to the holder of the given claims. Nil claims get the
+// default rule.
+func (p *Policy) PermissionsFor(claims *types.TokenClaims) Permissions {
+	if claims == nil {
+		return Permissions{rules: []Rule{p.Default}}
+	}
+
+	permissions := Permissions{Identity: claims.Subject}
+	if permissions.Identity == "" {
+		permissions.Identity = claims.Username
+	}
+
+	// A user entry takes precedence over groups
+	for _, key := range []string{claims.Subject, claims.Username} {
+		if rule, ok := p.Users[key]; ok && key != "" {
+			permissions.rules = []Rule{rule}
+			return permissions
+		}
+	}
+
+	for _, group := range slices.Concat(claims.Groups, claims.Authorities) {
+		if rule, ok := p.Groups[group]; ok {
+			permissions.rules = append(permissions.rules, rule)
+		}
+	}
+	if len(permissions.rules) == 0 {
+		permissions.rules = []Rule{p.Default}
+	}
+	return permissions
+}
+
+// AllowsTool reports whether any of the caller's rules allows the tool
+func (p Permissions) AllowsTool(name string) bool {
+	for _, rule := range p.rules {
+		if slices.Contains(rule.DenyTools, name) {
+			continue
+		}
+		if slices.Contains(rule.Tools, "*") || slices.Contains(rule.Tools, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedDirectories returns the directories the caller's filesystem access is limited to, or nil
+// when their rules do not limit it
+func (p Permissions) AllowedDirectories() []string {
+	var dirs []string
+	for _, rule := range p.rules {
+		if len(rule.AllowedDirectories) == 0 {
+			return nil
+		}
+		for _, dir := range rule.AllowedDirectories {
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// CallsPerHour returns the caller's tool call quota, 0 when unlimited
+func (p Permissions) CallsPerHour() int {
+	limit := 0
+	for _, rule := range p.rules {
+		if rule.CallsPerHour == 0 {
+			return 0
+		}
+		limit = max(limit, rule.CallsPerHour)
+	}
+	return limit
+}
+
+// consumeQuota records a tool call for the caller, returning an error when their quota is used up
+func (p *Policy) consumeQuota(permissions Permissions, now time.Time) error {
+	limit := permissions.CallsPerHour()
+	if limit == 0 {
+		return nil
+	}
+	// Unauthenticated callers share a quota
+	identity := permissions.Identity
+
+	p.quotasMu.Lock()
+	defer p.quotasMu.Unlock()
+
+	usage, exists := p.quotas[identity]
+	if !exists || now.Sub(usage.windowStart) >= quotaWindow {
+		usage = &quotaUsage{windowStart: now}
+		p.quotas[identity] = usage
+	}
+	if usage.calls >= limit {
+		retryIn := usage.windowStart.Add(quotaWindow).Sub(now).Round(time.Minute)
+		return fmt.Errorf("tool call quota of %d calls per hour reached, try again in %s", limit, retryIn)
+	}
+	usage.calls++
+	return nil
+}
+
+// claimsFromContext returns the OAuth claims of the caller, if authenticated
+func claimsFromContext(ctx context.Context) *types.TokenClaims {
+	claims, _ := ctx.Value(types.OAuthClaimsKey).(*types.TokenClaims)
+	return claims
+}
+
+// Authorise checks that the caller may use the tool and records the call against their quota. It
+// always succeeds when no policy is set.
+func Authorise(ctx context.Context, toolName string) error {
+	policy := CurrentPolicy()
+	if policy == nil {
+		return nil
+	}
+	return policy.authorise(claimsFromContext(ctx), toolName, time.Now())
+}
+
+func (p *Policy) authorise(claims *types.TokenClaims, toolName string, now time.Time) error {
+	permissions := p.PermissionsFor(claims)
+	if !permissions.AllowsTool(toolName) {
+		return fmt.Errorf("access denied: you are not permitted to use the %s tool", toolName)
+	}
+	return p.consumeQuota(permissions, now)
+}
+
+// AllowedDirectories returns the directories the caller's filesystem access is limited to, or nil
+// when no policy is set or the caller's rules do not limit it
+func AllowedDirectories(ctx context.Context) []string {
+	policy := CurrentPolicy()
+	if policy == nil {
+		return nil
+	}
+	return policy.PermissionsFor(claimsFromContext(ctx)).AllowedDirectories()
+}
+
+// FilterTools hides tools the caller may not use from tools/list, for use with server.WithToolFilter
+func FilterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	policy := CurrentPolicy()
+	if policy == nil {
+		return tools
+	}
+	permissions := policy.PermissionsFor(claimsFromContext(ctx))
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if permissions.AllowsTool(tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// Middleware enforces the policy on every tool call, including proxied tools, for use with
+// server.WithToolHandlerMiddleware
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := Authorise(ctx, request.Params.Name); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return next(ctx, request)
+	}
+}
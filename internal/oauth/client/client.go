@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/sammcj/mcp-devtools/internal/oauth/types"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -32,9 +33,7 @@ func NewOAuth2Client(config *OAuth2ClientConfig, logger *logrus.Logger) (OAuth2C
 		config:          config,
 		logger:          logger,
 		browserLauncher: NewBrowserLauncher(),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:      httpclient.NewHTTPClientWithProxyAndLogger(30*time.Second, logger),
 	}, nil
 }
 
@@ -27,6 +27,7 @@ type TokenClaims struct {
 	ClientID    string   `json:"client_id,omitempty"`
 	Username    string   `json:"username,omitempty"`
 	Authorities []string `json:"authorities,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
 }
 
 // TokenValidator interface for token validation
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -51,11 +52,9 @@ func NewJWKSClient(jwksURL string, logger *logrus.Logger) (*JWKSClient, error) {
 	}
 
 	return &JWKSClient{
-		jwksURL: jwksURL,
-		logger:  logger,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		jwksURL:    jwksURL,
+		logger:     logger,
+		httpClient: httpclient.NewHTTPClientWithProxyAndLogger(10*time.Second, logger),
 		cache: &jwksCache{
 			ttl: 5 * time.Minute, // Cache JWKS for 5 minutes
 		},
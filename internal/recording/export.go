@@ -0,0 +1,106 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Transcript is the JSON export of a recorded session
+type Transcript struct {
+	Session    string    `json:"session"`
+	ExportedAt time.Time `json:"exported_at"`
+	Calls      []Call    `json:"calls"`
+}
+
+// Export formats the calls of a session as a "markdown" or "json" transcript. Results are left out
+// unless includeResults is set.
+func Export(id string, calls []Call, format string, includeResults bool) (string, error) {
+	if !includeResults {
+		calls = slices.Clone(calls)
+		for i := range calls {
+			calls[i].Result, calls[i].Truncated = "", false
+		}
+	}
+	switch format {
+	case "", "markdown":
+		return markdown(id, calls, includeResults), nil
+	case "json":
+		if calls == nil {
+			calls = []Call{}
+		}
+		data, err := json.MarshalIndent(Transcript{Session: id, ExportedAt: time.Now(), Calls: calls}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s, use markdown or json", format)
+	}
+}
+
+// markdown formats calls as a Markdown transcript with a summary and a section per call
+func markdown(id string, calls []Call, includeResults bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session %s\n\n", id)
+	if len(calls) == 0 {
+		b.WriteString("No tool calls were recorded.\n")
+		return b.String()
+	}
+
+	var tools []string
+	failed := 0
+	for _, call := range calls {
+		if !slices.Contains(tools, call.Tool) {
+			tools = append(tools, call.Tool)
+		}
+		if call.IsError {
+			failed++
+		}
+	}
+	last := calls[len(calls)-1]
+	fmt.Fprintf(&b, "- **Started:** %s\n", calls[0].Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Ended:** %s\n", last.Time.Add(time.Duration(last.DurationMs)*time.Millisecond).Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Tool calls:** %d (%d failed)\n", len(calls), failed)
+	fmt.Fprintf(&b, "- **Tools:** %s\n", "`"+strings.Join(tools, "`, `")+"`")
+
+	for i, call := range calls {
+		fmt.Fprintf(&b, "\n## %d. `%s`\n\n", i+1, call.Tool)
+		status := "succeeded"
+		if call.IsError {
+			status = "failed"
+		}
+		fmt.Fprintf(&b, "%s, %s in %dms\n", call.Time.Format("15:04:05"), status, call.DurationMs)
+		if len(call.Arguments) > 0 {
+			arguments, _ := json.MarshalIndent(call.Arguments, "", "  ")
+			b.WriteString("\n**Arguments:**\n\n")
+			writeCodeBlock(&b, "json", string(arguments))
+		}
+		if includeResults && call.Result != "" {
+			b.WriteString("\n**Result:**\n\n")
+			writeCodeBlock(&b, "text", call.Result)
+			if call.Truncated {
+				fmt.Fprintf(&b, "\n_Result truncated to %d bytes._\n", maxResultLength)
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeCodeBlock writes content in a fenced code block whose fence is longer than any run of
+// backticks in the content
+func writeCodeBlock(b *strings.Builder, language, content string) {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, language, strings.TrimRight(content, "\n"), fence)
+}
@@ -0,0 +1,305 @@
+// Package recording records the tool calls of each MCP session when SESSION_RECORDING is enabled, so
+// that a session can be exported as a transcript to attach to a pull request or an incident
+// timeline. Arguments and results are recorded with secrets redacted.
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/diagnostics"
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+const (
+	// EnvVar enables recording when set to true
+	EnvVar = "SESSION_RECORDING"
+
+	// RetentionEnvVar overrides defaultRetention, in days
+	RetentionEnvVar = "SESSION_RECORDING_RETENTION_DAYS"
+
+	// defaultRetention is how long recordings are kept after their last call
+	defaultRetention = 30 * 24 * time.Hour
+
+	// maxResultLength truncates long results, so a recording stays readable in a transcript
+	maxResultLength = 64 * 1024
+	// maxArgumentLength truncates long string arguments, such as file contents
+	maxArgumentLength = 8 * 1024
+	// maxLineLength is the longest line read from a recording, allowing for escaped characters
+	maxLineLength = 4 * 1024 * 1024
+
+	// ExportToolName is the tool that exports recordings, whose results are not recorded
+	ExportToolName = "export_session"
+
+	// fileExtension is the extension of recording files, which hold one JSON call per line
+	fileExtension = ".jsonl"
+)
+
+// ErrNotFound is returned for a session that has no recording
+var ErrNotFound = errors.New("session recording not found")
+
+// Call is a recorded tool call
+type Call struct {
+	Time       time.Time      `json:"time"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+	IsError    bool           `json:"is_error,omitempty"`
+	Result     string         `json:"result,omitempty"`
+	Truncated  bool           `json:"truncated,omitempty"`
+}
+
+// Session describes a recorded session
+type Session struct {
+	ID        string    `json:"id"`
+	Calls     int       `json:"calls"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var (
+	// mu serialises writes to recording files
+	mu sync.Mutex
+	// cleanupOnce prunes old recordings the first time a call is recorded
+	cleanupOnce sync.Once
+	// processSession names the recording of a stdio server, whose MCP session ID is the same in
+	// every process
+	processSession = "stdio-" + time.Now().Format("20060102-150405") + "-" + strconv.Itoa(os.Getpid())
+)
+
+// Enabled reports whether session recording is enabled
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvVar))
+	return enabled
+}
+
+// Dir returns the directory recordings are kept in
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "sessions"), nil
+}
+
+// SessionID returns the ID a session's calls are recorded under. Outside an MCP session, and with
+// the stdio transport, it is unique to the server process.
+func SessionID(ctx context.Context) string {
+	session := diagnostics.SessionID(ctx)
+	if session == "" || session == "stdio" {
+		return processSession
+	}
+	return safeName(session)
+}
+
+// Record records a tool call made in the session of ctx, if recording is enabled. The result must
+// already have been sanitised and redacted; arguments are redacted here. Failures are returned for
+// logging and never affect the call.
+func Record(ctx context.Context, tool string, args map[string]any, start time.Time, result *mcp.CallToolResult) error {
+	if !Enabled() {
+		return nil
+	}
+	call := Call{Time: start, Tool: tool, DurationMs: time.Since(start).Milliseconds()}
+	if redacted, ok := redactValue(args).(map[string]any); ok && len(redacted) > 0 {
+		call.Arguments = redacted
+	}
+	// A transcript is not recorded again, or each export would hold every earlier one
+	if result != nil && tool == ExportToolName {
+		call.IsError = result.IsError
+	} else if result != nil {
+		call.IsError = result.IsError
+		call.Result, call.Truncated = resultText(result)
+	}
+	line, err := json.Marshal(call)
+	if err != nil {
+		return fmt.Errorf("failed to marshal call: %w", err)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	cleanupOnce.Do(func() { cleanup(dir) })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, SessionID(ctx)+fileExtension), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// Load returns the calls recorded in a session, oldest first. Lines that cannot be parsed are
+// skipped.
+func Load(id string) ([]Call, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || safeName(id) != id {
+		return nil, ErrNotFound
+	}
+	file, err := os.Open(filepath.Join(dir, id+fileExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var calls []Call
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		var call Call
+		if json.Unmarshal(scanner.Bytes(), &call) == nil && call.Tool != "" {
+			calls = append(calls, call)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return calls, nil
+}
+
+// List returns the recorded sessions, most recently updated first
+func List() ([]Session, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	var sessions []Session
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), fileExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		calls, err := Load(id)
+		if err != nil || len(calls) == 0 {
+			continue
+		}
+		sessions = append(sessions, Session{
+			ID:        id,
+			Calls:     len(calls),
+			StartedAt: calls[0].Time,
+			UpdatedAt: calls[len(calls)-1].Time,
+		})
+	}
+	slices.SortFunc(sessions, func(a, b Session) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	return sessions, nil
+}
+
+// retention returns how long recordings are kept after their last call
+func retention() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv(RetentionEnvVar)); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return defaultRetention
+}
+
+// cleanup removes recordings that have not been written to within the retention period
+func cleanup(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-retention())
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExtension) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// resultText joins the text of a result, truncated to maxResultLength
+func resultText(result *mcp.CallToolResult) (string, bool) {
+	var texts []string
+	for _, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			texts = append(texts, content.Text)
+		case mcp.ImageContent:
+			texts = append(texts, fmt.Sprintf("[image: %s]", content.MIMEType))
+		case mcp.AudioContent:
+			texts = append(texts, fmt.Sprintf("[audio: %s]", content.MIMEType))
+		}
+	}
+	text := strings.Join(texts, "\n")
+	if len(text) <= maxResultLength {
+		return text, false
+	}
+	return strings.ToValidUTF8(text[:maxResultLength], ""), true
+}
+
+// redactValue returns a copy of an argument value with secrets redacted from its strings and long
+// strings truncated
+func redactValue(value any) any {
+	switch value := value.(type) {
+	case string:
+		redacted, _ := security.Redact(value)
+		if len(redacted) > maxArgumentLength {
+			redacted = strings.ToValidUTF8(redacted[:maxArgumentLength], "") + "… [truncated]"
+		}
+		return redacted
+	case map[string]any:
+		redacted := make(map[string]any, len(value))
+		for key, item := range value {
+			redacted[key] = redactValue(item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, item := range value {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// safeName reduces a session ID to characters that are safe in a file name
+func safeName(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+}
@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"fmt"
+	"maps"
 	"os"
 	"sort"
 	"strings"
@@ -45,6 +47,20 @@ var (
 	// proxiedTools tracks tools proxied from upstream MCP servers
 	proxiedTools = make(map[string]bool)
 
+	// declaredTools tracks tools declared in the user's configuration, such as plugins and
+	// pipelines, with the reason they are enabled
+	declaredTools = make(map[string]string)
+
+	// knownTools records every tool that registered, whether or not it is enabled, for reporting
+	knownTools = make(map[string]tools.Tool)
+
+	// unavailableTools records tools that could not register and why, e.g. a missing prerequisite
+	unavailableTools = make(map[string]string)
+
+	// availabilityChecks are slow checks of whether registered tools can run, which are left to
+	// the tools' first use when serving and run by CheckAvailability for commands reporting status
+	availabilityChecks = make(map[string]func() string)
+
 	// disabledTools is a set of tool names to disable
 	disabledTools = make(map[string]bool)
 
@@ -222,6 +238,10 @@ func Register(tool tools.Tool) {
 
 	toolName := tool.Definition().Name
 
+	registryMu.Lock()
+	knownTools[toolName] = tool
+	registryMu.Unlock()
+
 	// Check if tool should be registered
 	if !ShouldRegisterTool(toolName) {
 		if logger != nil {
@@ -236,6 +256,43 @@ func Register(tool tools.Tool) {
 	}
 }
 
+// RegisterUnavailable records a tool that cannot be registered because a prerequisite is missing, so
+// that the reason can be reported to users
+func RegisterUnavailable(toolName, reason string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	unavailableTools[toolName] = reason
+}
+
+// RegisterAvailabilityCheck records a slow check of whether a registered tool can run, such as
+// looking for a Python install, which returns why the tool cannot run or "". The server does not run
+// it, so that startup stays quick and the tool reports the problem when called.
+func RegisterAvailabilityCheck(toolName string, check func() string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	availabilityChecks[toolName] = check
+}
+
+// CheckAvailability runs the availability checks of registered tools, recording those that cannot
+// run as unavailable, for commands that report tool status
+func CheckAvailability() {
+	registryMu.RLock()
+	checks := maps.Clone(availabilityChecks)
+	registryMu.RUnlock()
+
+	for toolName, check := range checks {
+		reason := check()
+		if reason == "" {
+			continue
+		}
+		registryMu.Lock()
+		delete(toolRegistry, toolName)
+		delete(knownTools, toolName)
+		unavailableTools[toolName] = reason
+		registryMu.Unlock()
+	}
+}
+
 // RegisterProxiedTool adds a tool proxied from an upstream MCP server to the registry.
 // Only called if `proxy` tool is enabled via ENABLE_ADDITIONAL_TOOLS and configured with upstreams.
 // This is used for tools discovered from upstream proxy servers. The caller (RegisterUpstreamTools)
@@ -267,6 +324,60 @@ func RegisterProxiedTool(tool tools.Tool) {
 	}
 }
 
+// UnregisterProxiedTool removes a tool proxied from an upstream MCP server, for tools advertised from
+// a cached tool list that the upstream no longer offers
+func UnregisterProxiedTool(toolName string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if !proxiedTools[toolName] {
+		return
+	}
+	delete(toolRegistry, toolName)
+	delete(proxiedTools, toolName)
+}
+
+// RegisterPluginTool adds a tool provided by an external plugin executable to the registry.
+// Only called if `plugins` is enabled via ENABLE_ADDITIONAL_TOOLS, so plugin tools bypass the normal
+// ENABLE_ADDITIONAL_TOOLS check as each was declared explicitly, but still respect DISABLED_TOOLS.
+// Plugins cannot replace a built-in or proxied tool.
+func RegisterPluginTool(tool tools.Tool) error {
+	return registerDeclaredTool(tool, "Plugin", "external plugin declared in plugins.yaml")
+}
+
+// RegisterPipelineTool adds a pipeline of tool calls declared in pipelines.yaml to the registry, on
+// the same terms as RegisterPluginTool
+func RegisterPipelineTool(tool tools.Tool) error {
+	return registerDeclaredTool(tool, "Pipeline", "pipeline declared in pipelines.yaml")
+}
+
+// registerDeclaredTool adds a tool declared in the user's configuration, which cannot replace
+// another tool
+func registerDeclaredTool(tool tools.Tool, kind, reason string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	toolName := tool.Definition().Name
+	if _, exists := knownTools[toolName]; exists || proxiedTools[toolName] || declaredTools[toolName] != "" {
+		return fmt.Errorf("a tool named %s already exists", toolName)
+	}
+	knownTools[toolName] = tool
+	declaredTools[toolName] = reason
+
+	if isToolDisabled(toolName) {
+		if logger != nil {
+			logger.WithField("tool", toolName).Debugf("%s tool not registered (explicitly disabled)", kind)
+		}
+		return nil
+	}
+
+	toolRegistry[toolName] = tool
+	if logger != nil {
+		logger.WithField("tool", toolName).Debugf("%s tool registered", kind)
+	}
+	return nil
+}
+
 // GetTool retrieves a tool by name, returns false if disabled.
 // Safe to call concurrently with RegisterProxiedTool (protected by registryMu).
 func GetTool(name string) (tools.Tool, bool) {
@@ -305,8 +416,8 @@ func GetEnabledTools() map[string]tools.Tool {
 			continue
 		}
 
-		// Include proxied tools (bypass enablement check)
-		if proxiedTools[name] {
+		// Include proxied and declared tools (bypass enablement check)
+		if proxiedTools[name] || declaredTools[name] != "" {
 			filteredTools[name] = tool
 			continue
 		}
@@ -342,8 +453,8 @@ func GetEnabledToolNames() []string {
 			continue
 		}
 
-		// Include proxied tools (bypass enablement check)
-		if proxiedTools[name] {
+		// Include proxied and declared tools (bypass enablement check)
+		if proxiedTools[name] || declaredTools[name] != "" {
 			names = append(names, name)
 			continue
 		}
@@ -385,6 +496,70 @@ func GetToolNamesWithExtendedHelp() []string {
 	return names
 }
 
+// ToolStatus describes whether a tool is enabled and why
+type ToolStatus struct {
+	Name    string
+	Enabled bool
+	Reason  string     // Why the tool is or is not enabled
+	Tool    tools.Tool // Nil when the tool could not be created
+}
+
+// GetToolStatuses returns the status of every known tool, including tools that are not enabled, sorted by name
+func GetToolStatuses() []ToolStatus {
+	registryMu.RLock()
+	statuses := make([]ToolStatus, 0, len(knownTools)+len(unavailableTools))
+	for name, tool := range knownTools {
+		statuses = append(statuses, toolStatus(name, tool))
+	}
+	for name, tool := range toolRegistry {
+		if _, known := knownTools[name]; !known {
+			statuses = append(statuses, toolStatus(name, tool))
+		}
+	}
+	for name, reason := range unavailableTools {
+		if _, known := knownTools[name]; !known {
+			statuses = append(statuses, ToolStatus{Name: name, Reason: reason})
+		}
+	}
+	registryMu.RUnlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// GetToolStatus returns the status of a known tool
+func GetToolStatus(name string) (ToolStatus, bool) {
+	for _, status := range GetToolStatuses() {
+		if status.Name == name || normaliseName(status.Name) == normaliseName(name) {
+			return status, true
+		}
+	}
+	return ToolStatus{}, false
+}
+
+// toolStatus works out why a registered tool is or is not enabled. The caller must hold registryMu.
+func toolStatus(name string, tool tools.Tool) ToolStatus {
+	status := ToolStatus{Name: name, Tool: tool}
+	switch {
+	case isToolDisabled(name):
+		status.Reason = "disabled by DISABLED_TOOLS"
+	case proxiedTools[name]:
+		status.Enabled = true
+		status.Reason = "proxied from an upstream MCP server"
+	case declaredTools[name] != "":
+		status.Enabled = true
+		status.Reason = declaredTools[name]
+	case enabledByDefault(name):
+		status.Enabled = true
+		status.Reason = "enabled by default"
+	case isToolEnabled(name):
+		status.Enabled = true
+		status.Reason = "enabled by ENABLE_ADDITIONAL_TOOLS"
+	default:
+		status.Reason = fmt.Sprintf("not enabled by default, add %s to ENABLE_ADDITIONAL_TOOLS", name)
+	}
+	return status
+}
+
 // isToolEnabled checks if a tool is enabled via the cached ENABLE_ADDITIONAL_TOOLS set.
 func isToolEnabled(toolName string) bool {
 	ensureEnabledToolsParsed()
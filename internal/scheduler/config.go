@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/cron"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultTimeout is how long a scheduled run may take, in seconds
+	defaultTimeout = 600
+	// maxTimeout is the longest timeout a schedule may set, in seconds
+	maxTimeout = 6 * 60 * 60
+)
+
+// namePattern matches valid schedule names, which are used in artifact file names
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Config represents the schedules declared in schedules.yaml
+type Config struct {
+	Schedules []Schedule `yaml:"schedules"`
+}
+
+// Job declares a tool call, which schedules and hooks run
+type Job struct {
+	Tool      string         `yaml:"tool"`      // name of an enabled tool
+	Arguments map[string]any `yaml:"arguments"` // arguments passed to the tool
+	Timeout   int            `yaml:"timeout"`   // timeout in seconds, default 600
+}
+
+// Schedule declares a tool call to run on a cron schedule
+type Schedule struct {
+	Name     string `yaml:"name"`
+	Cron     string `yaml:"schedule"` // five-field cron expression or a macro such as @daily
+	Timezone string `yaml:"timezone"` // IANA time zone, default the server's
+	Disabled bool   `yaml:"disabled"` // keep the schedule without running it
+	Job      `yaml:",inline"`
+
+	cron     *cron.Schedule
+	location *time.Location
+}
+
+// ConfigPath returns the path of schedules.yaml
+func ConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "schedules.yaml"), nil
+}
+
+// LoadConfig loads the schedule configuration. A missing file declares no schedules. Schedules are
+// not validated, so that one mistake can be reported without hiding the others.
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	return &config, nil
+}
+
+// validate validates a schedule, parses its cron expression and sets defaults
+func (s *Schedule) validate() error {
+	if !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("invalid name %q, use up to 64 letters, digits, underscores or hyphens", s.Name)
+	}
+	schedule, err := cron.Parse(s.Cron)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	s.cron = schedule
+
+	s.location = time.Local
+	if s.Timezone != "" {
+		if s.location, err = time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+		}
+	}
+	return s.Job.validate()
+}
+
+// validate validates a job and sets defaults
+func (j *Job) validate() error {
+	if strings.TrimSpace(j.Tool) == "" {
+		return fmt.Errorf("tool is required")
+	}
+	if j.Timeout <= 0 {
+		j.Timeout = defaultTimeout
+	}
+	if j.Timeout > maxTimeout {
+		return fmt.Errorf("timeout must be at most %d seconds", maxTimeout)
+	}
+
+	// YAML numbers decode as ints, but tools expect arguments as they arrive from JSON
+	data, err := json.Marshal(j.Arguments)
+	if err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	j.Arguments = nil
+	if err := json.Unmarshal(data, &j.Arguments); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if j.Arguments == nil {
+		j.Arguments = make(map[string]any)
+	}
+	return nil
+}
+
+// Next returns the first time after t that the schedule runs, or false if it does not run in the
+// next five years
+func (s *Schedule) Next(t time.Time) (time.Time, bool) {
+	return s.cron.Next(t.In(s.location))
+}
+
+// Explain describes when the schedule runs in English
+func (s *Schedule) Explain() string {
+	return s.cron.Explain()
+}
+
+// Validated returns the schedules in config that are valid, with an error for each that is not.
+// Duplicate names are invalid after the first.
+func (c *Config) Validated() ([]*Schedule, map[string]error) {
+	var valid []*Schedule
+	invalid := make(map[string]error)
+	seen := make(map[string]bool)
+	for i := range c.Schedules {
+		schedule := c.Schedules[i]
+		name := schedule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		err := schedule.validate()
+		if err == nil && seen[schedule.Name] {
+			err = fmt.Errorf("a schedule named %s is already declared", schedule.Name)
+		}
+		if err != nil {
+			invalid[name] = err
+			continue
+		}
+		seen[schedule.Name] = true
+		valid = append(valid, &schedule)
+	}
+	return valid, invalid
+}
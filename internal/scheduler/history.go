@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// maxHistory is how many runs are kept in the history
+	maxHistory = 1000
+
+	// Run statuses
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Run records one run of a schedule or a hook
+type Run struct {
+	ID         string    `json:"id"`
+	Schedule   string    `json:"schedule,omitempty"`
+	Hook       string    `json:"hook,omitempty"`
+	Tool       string    `json:"tool"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	ArtifactID string    `json:"artifact_id,omitempty"`
+	Size       int64     `json:"size,omitempty"`
+}
+
+// historyMu serialises writes to the history file
+var historyMu sync.Mutex
+
+// HistoryPath returns the path of the run history, which holds one JSON run per line
+func HistoryPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "schedule_history.jsonl"), nil
+}
+
+// record appends a run to the history, dropping the oldest runs once it holds twice maxHistory
+func record(run *Run) error {
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open run history: %w", err)
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write run history: %w", err)
+	}
+
+	// Trimming in batches keeps appends cheap
+	runs, err := readHistory(path)
+	if err != nil || len(runs) <= 2*maxHistory {
+		return err
+	}
+	var buffer bytes.Buffer
+	for _, run := range runs[len(runs)-maxHistory:] {
+		line, _ := json.Marshal(run)
+		buffer.Write(append(line, '\n'))
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0600)
+}
+
+// History returns up to limit runs, newest first, only of the named schedule or hook unless name is
+// empty
+func History(name string, limit int) ([]Run, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	historyMu.Lock()
+	runs, err := readHistory(path)
+	historyMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Run
+	for _, run := range slices.Backward(runs) {
+		if len(result) >= limit {
+			break
+		}
+		if name == "" || run.Schedule == name || run.Hook == name {
+			result = append(result, run)
+		}
+	}
+	return result, nil
+}
+
+// FindRun returns the run with the given ID
+func FindRun(id string) (*Run, error) {
+	runs, err := History("", maxHistory*2)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.ID == id {
+			return &run, nil
+		}
+	}
+	return nil, fmt.Errorf("run %s not found in the history", id)
+}
+
+// readHistory reads every run in the history file, oldest first. Lines that cannot be parsed are
+// skipped. Caller must hold historyMu.
+func readHistory(path string) ([]Run, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run history: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if json.Unmarshal(scanner.Bytes(), &run) == nil && run.ID != "" {
+			runs = append(runs, run)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run history: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,399 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// HooksPathPrefix is the path hooks are served under, as /hooks/<name>
+	HooksPathPrefix = "/hooks/"
+
+	// maxHookBody is the largest request body a hook accepts, in bytes
+	maxHookBody = 64 * 1024
+	// hookWorkers is how many hook runs may run at once
+	hookWorkers = 2
+	// hookQueueSize is how many hook runs may wait to run before requests are refused
+	hookQueueSize = 32
+
+	// Statuses of hook runs that have not finished
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+)
+
+// HooksConfig represents the hooks declared in hooks.yaml
+type HooksConfig struct {
+	Hooks []Hook `yaml:"hooks"`
+}
+
+// Hook declares a tool call that an authenticated HTTP request can trigger, such as a CI job
+type Hook struct {
+	Name       string   `yaml:"name"`
+	Parameters []string `yaml:"parameters"` // arguments a request may set, in addition to the fixed arguments
+	TokenEnv   string   `yaml:"token_env"`  // environment variable holding the hook's bearer token, default the server's auth token
+	Disabled   bool     `yaml:"disabled"`   // keep the hook without serving it
+	Job        `yaml:",inline"`
+
+	token string
+}
+
+// hookRun is a hook run waiting for a worker
+type hookRun struct {
+	run *Run
+	job Job
+}
+
+// Hooks serves the hooks declared in hooks.yaml and runs their tool calls in the background
+type Hooks struct {
+	logger *logrus.Logger
+	hooks  map[string]*Hook
+	queue  chan hookRun
+
+	mu      sync.Mutex
+	pending map[string]*Run // runs that are queued or running, by ID
+}
+
+// HooksConfigPath returns the path of hooks.yaml
+func HooksConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "hooks.yaml"), nil
+}
+
+// LoadHooksConfig loads the hook configuration. A missing file declares no hooks. Hooks are not
+// validated, so that one mistake can be reported without hiding the others.
+func LoadHooksConfig(configPath string) (*HooksConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &HooksConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config HooksConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	return &config, nil
+}
+
+// validate validates a hook, resolves its token and sets defaults. serverToken is the server's auth
+// token, used by hooks without a token of their own.
+func (h *Hook) validate(serverToken string) error {
+	if !namePattern.MatchString(h.Name) {
+		return fmt.Errorf("invalid name %q, use up to 64 letters, digits, underscores or hyphens", h.Name)
+	}
+	if err := h.Job.validate(); err != nil {
+		return err
+	}
+	for i, parameter := range h.Parameters {
+		if strings.TrimSpace(parameter) == "" {
+			return fmt.Errorf("parameter names must not be empty")
+		}
+		if slices.Contains(h.Parameters[:i], parameter) {
+			return fmt.Errorf("parameter %s is listed more than once", parameter)
+		}
+		// Fixed arguments cannot be overridden by a request
+		if _, ok := h.Arguments[parameter]; ok {
+			return fmt.Errorf("parameter %s is also a fixed argument", parameter)
+		}
+	}
+
+	h.token = serverToken
+	if h.TokenEnv != "" {
+		if h.token = os.Getenv(h.TokenEnv); h.token == "" {
+			return fmt.Errorf("token_env %s is not set", h.TokenEnv)
+		}
+	}
+	if h.token == "" {
+		return fmt.Errorf("the hook has no token, set token_env or start the server with --auth-token")
+	}
+	return nil
+}
+
+// Validated returns the hooks in config that are valid, with an error for each that is not.
+// Duplicate names are invalid after the first.
+func (c *HooksConfig) Validated(serverToken string) ([]*Hook, map[string]error) {
+	var valid []*Hook
+	invalid := make(map[string]error)
+	seen := make(map[string]bool)
+	for i := range c.Hooks {
+		hook := c.Hooks[i]
+		name := hook.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		err := hook.validate(serverToken)
+		if err == nil && seen[hook.Name] {
+			err = fmt.Errorf("a hook named %s is already declared", hook.Name)
+		}
+		if err != nil {
+			invalid[name] = err
+			continue
+		}
+		seen[hook.Name] = true
+		valid = append(valid, &hook)
+	}
+	return valid, invalid
+}
+
+// NewHooks returns hooks for the valid, enabled hooks in config, logging and skipping the others.
+// serverToken is the server's auth token, used by hooks without a token of their own.
+func NewHooks(config *HooksConfig, serverToken string, logger *logrus.Logger) *Hooks {
+	valid, invalid := config.Validated(serverToken)
+	for name, err := range invalid {
+		logger.WithError(err).WithField("hook", name).Warn("Skipping hook")
+	}
+	h := &Hooks{
+		logger:  logger,
+		hooks:   make(map[string]*Hook),
+		queue:   make(chan hookRun, hookQueueSize),
+		pending: make(map[string]*Run),
+	}
+	for _, hook := range valid {
+		if !hook.Disabled {
+			h.hooks[hook.Name] = hook
+		}
+	}
+	return h
+}
+
+// StartHooks loads hooks.yaml and runs triggered hooks in the background until ctx is cancelled. It
+// returns nil when no hooks are declared.
+func StartHooks(ctx context.Context, serverToken string, logger *logrus.Logger) (*Hooks, error) {
+	configPath, err := HooksConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadHooksConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hook configuration: %w", err)
+	}
+	if len(config.Hooks) == 0 {
+		return nil, nil
+	}
+	h := NewHooks(config, serverToken, logger)
+	logger.WithField("hooks", len(h.hooks)).Infof("Hooks available at %s<name>", HooksPathPrefix)
+	go h.Run(ctx)
+	return h, nil
+}
+
+// Run runs queued hook runs until ctx is cancelled, then records the runs still queued as failed
+func (h *Hooks) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range hookWorkers {
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case queued := <-h.queue:
+					h.perform(ctx, queued)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	for {
+		select {
+		case queued := <-h.queue:
+			queued.run.Status = StatusFailed
+			queued.run.Error = "the server stopped before the run started"
+			if err := record(queued.run); err != nil {
+				h.logger.WithError(err).WithField("hook", queued.run.Hook).Warn("Failed to record run")
+			}
+			h.finish(queued.run)
+		default:
+			return
+		}
+	}
+}
+
+// perform runs a queued hook run and records it in the history
+func (h *Hooks) perform(ctx context.Context, queued hookRun) {
+	h.mu.Lock()
+	queued.run.Status = StatusRunning
+	h.mu.Unlock()
+
+	run := *queued.run
+	perform(ctx, h.logger, &run, run.Hook, &queued.job)
+	fields := logrus.Fields{"hook": run.Hook, "run": run.ID, "status": run.Status}
+	if run.Status == StatusFailed {
+		h.logger.WithFields(fields).Warnf("Hook run failed: %s", run.Error)
+	} else {
+		h.logger.WithFields(fields).Info("Hook run finished")
+	}
+	h.finish(&run)
+}
+
+// finish forgets a run once it is in the history
+func (h *Hooks) finish(run *Run) {
+	h.mu.Lock()
+	delete(h.pending, run.ID)
+	h.mu.Unlock()
+}
+
+// Names returns the names of the hooks being served
+func (h *Hooks) Names() []string {
+	return slices.Sorted(maps.Keys(h.hooks))
+}
+
+// ServeHTTP triggers a hook with POST /hooks/<name> and reports a run with
+// GET /hooks/<name>/runs/<id>. Both require the hook's bearer token.
+func (h *Hooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, HooksPathPrefix), "/")
+	hook, ok := h.hooks[parts[0]]
+	if !ok || (len(parts) != 1 && (len(parts) != 3 || parts[1] != "runs")) {
+		writeHookError(w, http.StatusNotFound, "hook not found")
+		return
+	}
+	if !authorised(r, hook.token) {
+		h.logger.WithFields(logrus.Fields{"hook": hook.Name, "remote": r.RemoteAddr}).Warn("Rejected hook request with an invalid token")
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-devtools"`)
+		writeHookError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+		return
+	}
+
+	if len(parts) == 3 {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeHookError(w, http.StatusMethodNotAllowed, "use GET to read a run")
+			return
+		}
+		h.serveRun(w, hook, parts[2])
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeHookError(w, http.StatusMethodNotAllowed, "use POST to trigger a hook")
+		return
+	}
+	h.trigger(w, r, hook)
+}
+
+// trigger queues a run of a hook with the parameters in the request body
+func (h *Hooks) trigger(w http.ResponseWriter, r *http.Request, hook *Hook) {
+	parameters, err := readParameters(w, r)
+	if err != nil {
+		writeHookError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for name := range parameters {
+		if !slices.Contains(hook.Parameters, name) {
+			message := fmt.Sprintf("parameter %s is not allowed, hook %s accepts no parameters", name, hook.Name)
+			if len(hook.Parameters) > 0 {
+				message = fmt.Sprintf("parameter %s is not allowed, hook %s accepts: %s", name, hook.Name, strings.Join(hook.Parameters, ", "))
+			}
+			writeHookError(w, http.StatusBadRequest, message)
+			return
+		}
+	}
+
+	job := hook.Job
+	job.Arguments = maps.Clone(hook.Arguments)
+	maps.Copy(job.Arguments, parameters)
+	run := &Run{ID: newRunID(), Hook: hook.Name, Tool: hook.Tool, Status: StatusQueued}
+
+	h.mu.Lock()
+	select {
+	case h.queue <- hookRun{run: run, job: job}:
+		h.pending[run.ID] = run
+		h.mu.Unlock()
+	default:
+		h.mu.Unlock()
+		w.Header().Set("Retry-After", "60")
+		writeHookError(w, http.StatusServiceUnavailable, "too many hook runs are queued, try again later")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"hook": hook.Name, "run": run.ID, "remote": r.RemoteAddr}).Info("Hook triggered")
+	writeHookJSON(w, http.StatusAccepted, map[string]string{
+		"run_id":     run.ID,
+		"status":     StatusQueued,
+		"status_url": HooksPathPrefix + hook.Name + "/runs/" + run.ID,
+	})
+}
+
+// serveRun reports a run of a hook, from the history once it has finished
+func (h *Hooks) serveRun(w http.ResponseWriter, hook *Hook, id string) {
+	h.mu.Lock()
+	pending, ok := h.pending[id]
+	var run Run
+	if ok {
+		run = *pending
+	}
+	h.mu.Unlock()
+
+	if !ok {
+		found, err := FindRun(id)
+		if err != nil || found.Hook != hook.Name {
+			writeHookError(w, http.StatusNotFound, "run not found")
+			return
+		}
+		run = *found
+	}
+	if run.Hook != hook.Name {
+		writeHookError(w, http.StatusNotFound, "run not found")
+		return
+	}
+	writeHookJSON(w, http.StatusOK, run)
+}
+
+// readParameters reads the JSON object of parameters in a request body, which may be empty
+func readParameters(w http.ResponseWriter, r *http.Request) (map[string]any, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookBody))
+	if err != nil {
+		if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			return nil, fmt.Errorf("the request body is larger than %d bytes", maxHookBody)
+		}
+		return nil, fmt.Errorf("failed to read the request body: %w", err)
+	}
+	parameters := make(map[string]any)
+	if strings.TrimSpace(string(data)) == "" {
+		return parameters, nil
+	}
+	if err := json.Unmarshal(data, &parameters); err != nil || parameters == nil {
+		return nil, fmt.Errorf("the request body must be a JSON object of parameters")
+	}
+	return parameters, nil
+}
+
+// authorised reports whether a request carries the expected bearer token
+func authorised(r *http.Request, token string) bool {
+	header := r.Header.Get("Authorization")
+	const bearerPrefix = "Bearer "
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, bearerPrefix)), []byte(token)) == 1
+}
+
+// writeHookJSON writes a JSON response
+func writeHookJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeHookError writes a JSON error response
+func writeHookError(w http.ResponseWriter, status int, message string) {
+	writeHookJSON(w, status, map[string]string{"error": message})
+}
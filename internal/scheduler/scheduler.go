@@ -0,0 +1,209 @@
+// Package scheduler runs tool calls declared in ~/.mcp-devtools/schedules.yaml on cron schedules,
+// such as a nightly documentation export or a weekly vulnerability scan. It runs while the server is
+// serving an HTTP transport, which also serves the hooks declared in ~/.mcp-devtools/hooks.yaml so
+// that CI systems can trigger tool calls. Each run's output is saved as an artifact and the run is
+// recorded in a history that the scheduled_runs tool reads.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sirupsen/logrus"
+)
+
+// active records whether a scheduler is running in this server
+var active atomic.Bool
+
+// Active reports whether a scheduler is running in this server
+func Active() bool {
+	return active.Load()
+}
+
+// Scheduler runs schedules when they are due
+type Scheduler struct {
+	logger    *logrus.Logger
+	schedules []*Schedule
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// New returns a scheduler for the valid schedules in config, logging and skipping the others
+func New(config *Config, logger *logrus.Logger) *Scheduler {
+	schedules, invalid := config.Validated()
+	for name, err := range invalid {
+		logger.WithError(err).WithField("schedule", name).Warn("Skipping schedule")
+	}
+	return &Scheduler{logger: logger, schedules: schedules, running: make(map[string]bool)}
+}
+
+// Start loads schedules.yaml and runs its schedules in the background until ctx is cancelled. It
+// does nothing when no schedules are declared.
+func Start(ctx context.Context, logger *logrus.Logger) error {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load schedule configuration: %w", err)
+	}
+	if len(config.Schedules) == 0 {
+		return nil
+	}
+	s := New(config, logger)
+	logger.WithField("schedules", len(s.schedules)).Info("Scheduler started")
+	go s.Run(ctx)
+	return nil
+}
+
+// Run runs schedules as they become due, returning once ctx is cancelled and running schedules
+// have finished
+func (s *Scheduler) Run(ctx context.Context) {
+	active.Store(true)
+	defer active.Store(false)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	due := make(map[*Schedule]time.Time)
+	now := time.Now()
+	for _, schedule := range s.schedules {
+		if next, ok := schedule.Next(now); ok && !schedule.Disabled {
+			due[schedule] = next
+		}
+	}
+
+	for len(due) > 0 {
+		earliest := time.Time{}
+		for _, next := range due {
+			if earliest.IsZero() || next.Before(earliest) {
+				earliest = next
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for schedule, next := range due {
+			if next.After(now) {
+				continue
+			}
+			wg.Go(func() { s.runDue(ctx, schedule) })
+			if following, ok := schedule.Next(now); ok {
+				due[schedule] = following
+			} else {
+				delete(due, schedule)
+			}
+		}
+	}
+}
+
+// runDue runs a schedule that has become due, unless its previous run is still going
+func (s *Scheduler) runDue(ctx context.Context, schedule *Schedule) {
+	s.mu.Lock()
+	if s.running[schedule.Name] {
+		s.mu.Unlock()
+		s.logger.WithField("schedule", schedule.Name).Warn("Skipping scheduled run, the previous run has not finished")
+		return
+	}
+	s.running[schedule.Name] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, schedule.Name)
+		s.mu.Unlock()
+	}()
+
+	run := s.Execute(ctx, schedule)
+	fields := logrus.Fields{"schedule": schedule.Name, "run": run.ID, "status": run.Status}
+	if run.Status == StatusFailed {
+		s.logger.WithFields(fields).Warnf("Scheduled run failed: %s", run.Error)
+	} else {
+		s.logger.WithFields(fields).Info("Scheduled run finished")
+	}
+}
+
+// Execute runs a schedule's tool call now, saves its output as an artifact and records the run in
+// the history
+func (s *Scheduler) Execute(ctx context.Context, schedule *Schedule) *Run {
+	run := &Run{ID: newRunID(), Schedule: schedule.Name, Tool: schedule.Tool}
+	perform(ctx, s.logger, run, schedule.Name, &schedule.Job)
+	return run
+}
+
+// Schedules returns the scheduler's valid schedules
+func (s *Scheduler) Schedules() []*Schedule {
+	return s.schedules
+}
+
+// perform runs a job, saves its output as an artifact named after name and records the run in the
+// history
+func perform(ctx context.Context, logger *logrus.Logger, run *Run, name string, job *Job) {
+	run.StartedAt = time.Now()
+	output, err := call(ctx, logger, job)
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	if err == nil && output != "" {
+		var artifact *artifacts.Artifact
+		artifact, err = saveOutput(ctx, name, job.Tool, run, output)
+		if artifact != nil {
+			run.ArtifactID = artifact.ID
+			run.Size = artifact.Size
+		}
+	}
+	run.Status = StatusSucceeded
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error, _ = security.Redact(err.Error())
+	}
+	if err := record(run); err != nil {
+		logger.WithError(err).WithField("job", name).Warn("Failed to record run")
+	}
+}
+
+// call runs a job's tool through toolcall, which checks and queues it and sanitises and redacts its
+// output in the same way as a call made by a client
+func call(ctx context.Context, logger *logrus.Logger, job *Job) (string, error) {
+	tool, err := toolcall.Enabled(job.Tool)
+	if err != nil {
+		return "", err
+	}
+	return toolcall.Call(ctx, logger, job.Tool, tool, job.Arguments, time.Duration(job.Timeout)*time.Second)
+}
+
+// saveOutput saves a run's output as an artifact of the scheduled session
+func saveOutput(ctx context.Context, name, tool string, run *Run, output string) (*artifacts.Artifact, error) {
+	contentType, extension := "text/plain", ".txt"
+	if trimmed := strings.TrimSpace(output); (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		contentType, extension = "application/json", ".json"
+	}
+	artifact, err := artifacts.Save(artifacts.WithSession(ctx, artifacts.ScheduledSession), tool,
+		name+extension, contentType, fmt.Sprintf("Output of run %s of %s", run.ID, name), []byte(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save output: %w", err)
+	}
+	return artifact, nil
+}
+
+// newRunID returns a unique, time ordered run ID
+func newRunID() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,97 @@
+// Package secrets resolves references to secrets held outside the server's configuration, so that
+// tokens, client secrets and API keys do not have to be pasted into MCP client configuration files.
+// A configuration value may contain references of the form ${env:VAR}, ${file:/path} or
+// ${keychain:item}, which are replaced with the secret at startup.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+)
+
+// referencePattern matches a ${source:name} reference
+var referencePattern = regexp.MustCompile(`\$\{(env|file|keychain):([^}]+)\}`)
+
+// IsReference reports whether value contains a secret reference
+func IsReference(value string) bool {
+	return referencePattern.MatchString(value)
+}
+
+// Resolve returns value with each secret reference replaced by the secret it refers to
+func Resolve(value string) (string, error) {
+	var errs []error
+	resolved := referencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		match := referencePattern.FindStringSubmatch(reference)
+		secret, err := lookup(match[1], strings.TrimSpace(match[2]))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", reference, err))
+		}
+		return secret
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return resolved, nil
+}
+
+// ResolveEnvironment resolves the secret references in every environment variable of the process, so
+// that configuration read from the environment or from flags backed by it sees the secrets. Variables
+// whose references cannot be resolved are unset rather than left holding the reference, and the
+// errors are returned for logging once logging is configured.
+func ResolveEnvironment() error {
+	var errs []error
+	for _, entry := range os.Environ() {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !IsReference(value) {
+			continue
+		}
+		resolved, err := Resolve(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			_ = os.Unsetenv(name)
+			continue
+		}
+		_ = os.Setenv(name, resolved)
+	}
+	return errors.Join(errs...)
+}
+
+// lookup returns the secret name refers to in source
+func lookup(source, name string) (string, error) {
+	switch source {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable is not set")
+		}
+		return value, nil
+	case "file":
+		data, err := os.ReadFile(expandHome(name))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case "keychain":
+		return credentials.Get(name)
+	default:
+		return "", fmt.Errorf("unknown secret source %q", source)
+	}
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
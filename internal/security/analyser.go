@@ -52,7 +52,7 @@ func (a *SecurityAdvisor) performAnalysis(content string, source SourceContext)
 
 	originalContentLength := len(content)
 	// Check size limits
-	if len(content) > a.config.MaxScanSize {
+	if a.config.MaxScanSize > 0 && len(content) > a.config.MaxScanSize {
 		// For large content, only scan the first portion
 		content = content[:a.config.MaxScanSize]
 		if logrus.GetLevel() <= logrus.DebugLevel {
@@ -194,6 +194,25 @@ func (a *SecurityAdvisor) performAnalysis(content string, source SourceContext)
 		return ruleResult, nil
 	}
 
+	// Check for prompt injection unless an allow or ignore rule matched
+	if ruleResult.ID == "" {
+		injectionContents := []string{content}
+		if contentWasModified {
+			injectionContents = append(injectionContents, processedContent)
+		}
+		if injectionResult := a.ruleEngine.EvaluatePromptInjection(source, injectionContents...); injectionResult != nil {
+			if logrus.GetLevel() <= logrus.DebugLevel {
+				logrus.WithFields(logrus.Fields{
+					"injection_action":  injectionResult.Action,
+					"injection_message": injectionResult.Message,
+				}).Debug("Prompt injection stage flagged content")
+			}
+			analysis.RiskFactors = append(analysis.RiskFactors, "prompt injection")
+			injectionResult.Analysis = analysis
+			return injectionResult, nil
+		}
+	}
+
 	// Return analysis-based result
 	finalResult := &SecurityResult{
 		Safe:      analysis.RiskScore < a.config.ThreatThreshold,
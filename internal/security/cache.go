@@ -1,11 +1,30 @@
 package security
 
 import (
-	"crypto/sha1"
-	"fmt"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
 	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/telemetry"
 )
 
+// securityCacheName identifies the security cache in metrics
+const securityCacheName = "security"
+
+// NewCache creates a security result cache holding up to maxSize entries for up to maxAge
+func NewCache(maxSize int, maxAge time.Duration) *Cache {
+	return &Cache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		maxSize: maxSize,
+		maxAge:  maxAge,
+	}
+}
+
 // StartCleanup starts the periodic cache cleanup routine
 func (c *Cache) StartCleanup() {
 	go func() {
@@ -18,94 +37,180 @@ func (c *Cache) StartCleanup() {
 	}()
 }
 
-// Get retrieves a cached security result
+// Get retrieves a copy of a cached security result
 func (c *Cache) Get(key string) (*SecurityResult, bool) {
-	if val, exists := c.data.Load(key); exists {
-		entry := val.(*CacheEntry)
-
-		// Simple TTL check
-		if time.Since(entry.Created) > c.maxAge {
-			c.data.Delete(key)
-			c.size.Add(-1)
-			return nil, false
-		}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		c.recordLookup(false)
+		return nil, false
+	}
 
-		return entry.Result, true
+	entry := element.Value.(*CacheEntry)
+	// Entries from before the cache was cleared or past their age are treated as misses
+	if entry.Generation != c.generation || time.Since(entry.Created) > c.maxAge {
+		c.removeElement(element)
+		c.recordLookup(false)
+		return nil, false
 	}
-	return nil, false
+
+	c.order.MoveToFront(element)
+	c.recordLookup(true)
+	return cloneResult(entry.Result), true
 }
 
-// Set stores a security result in the cache
+// Set stores a copy of a security result, evicting the least recently used entries when full
 func (c *Cache) Set(key string, result *SecurityResult) {
-	// Simple size limit - if over limit, don't cache (fail open)
-	if c.size.Load() >= int64(c.maxSize) {
-		return // Skip caching when full - simpler than complex eviction
+	c.mutex.Lock()
+	generation := c.generation
+	c.mutex.Unlock()
+	c.set(key, result, generation)
+}
+
+// set stores a result produced during the given generation. A result produced before the
+// cache was cleared is dropped rather than cached under the new generation.
+func (c *Cache) set(key string, result *SecurityResult, generation uint64) {
+	if c.maxSize <= 0 || result == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if generation != c.generation {
+		return
 	}
 
 	entry := &CacheEntry{
-		Result:  result,
-		Created: time.Now(),
+		Key:        key,
+		Result:     cloneResult(result),
+		Created:    time.Now(),
+		Generation: generation,
+	}
+
+	if element, exists := c.entries[key]; exists {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
 	}
 
-	// Only increment if this is a new key
-	if _, loaded := c.data.LoadOrStore(key, entry); !loaded {
-		c.size.Add(1)
+	for c.order.Len() >= c.maxSize {
+		c.removeElement(c.order.Back())
+		c.evictions.Add(1)
+		telemetry.RecordCacheEviction(context.Background(), securityCacheName, "capacity")
 	}
+	c.entries[key] = c.order.PushFront(entry)
 }
 
 // cleanup removes expired entries from the cache
 func (c *Cache) cleanup() {
-	now := time.Now()
-	c.data.Range(func(key, value any) bool {
-		entry := value.(*CacheEntry)
-		if now.Sub(entry.Created) > c.maxAge {
-			c.data.Delete(key)
-			c.size.Add(-1)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Entries are ordered by use, not age, so check them all
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if time.Since(element.Value.(*CacheEntry).Created) > c.maxAge {
+			c.removeElement(element)
+			telemetry.RecordCacheEviction(context.Background(), securityCacheName, "expired")
 		}
-		return true
-	})
+		element = next
+	}
 }
 
-// Clear removes all entries from the cache
+// Clear removes all entries from the cache. Used when rules or overrides change, and results
+// being generated at the time are not cached, so a stale result is never served afterwards.
 func (c *Cache) Clear() {
-	c.data.Range(func(key, value any) bool {
-		c.data.Delete(key)
-		return true
-	})
-	c.size.Store(0)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
 }
 
 // Size returns the current number of cached entries
 func (c *Cache) Size() int {
-	return int(c.size.Load())
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the cache size and hit, miss and eviction counters
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		Size:      c.Size(),
+		MaxSize:   c.maxSize,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// removeElement deletes an entry. Callers must hold the mutex.
+func (c *Cache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*CacheEntry).Key)
+}
+
+func (c *Cache) recordLookup(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	telemetry.RecordCacheOperation(context.Background(), securityCacheName, "get", hit)
 }
 
-// GenerateCacheKey generates a cache key from content and source
-func GenerateCacheKey(content string, sourceURL string) string {
-	hasher := sha1.New()
-	hasher.Write([]byte(content))
-	hasher.Write([]byte(sourceURL))
-	return fmt.Sprintf("%x", hasher.Sum(nil))[:16] // Short hash
+// GenerateCacheKey generates a cache key from content and its source. Each field is length
+// prefixed and the full SHA-256 digest is used, so different inputs cannot be made to collide.
+func GenerateCacheKey(content string, source SourceContext) string {
+	hasher := sha256.New()
+	for _, field := range []string{content, source.URL, source.Domain, source.ContentType, source.Tool} {
+		_ = binary.Write(hasher, binary.BigEndian, uint64(len(field)))
+		hasher.Write([]byte(field))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
 }
 
 // GetWithGeneration retrieves or generates a cached result
 func (c *Cache) GetWithGeneration(content string, source SourceContext, generator func() (*SecurityResult, error)) (*SecurityResult, error) {
-	// Generate cache key
-	key := GenerateCacheKey(content, source.URL)
+	key := GenerateCacheKey(content, source)
 
 	// Try to get from cache first
 	if result, found := c.Get(key); found {
 		return result, nil
 	}
 
+	c.mutex.Lock()
+	generation := c.generation
+	c.mutex.Unlock()
+
 	// Generate new result
 	result, err := generator()
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache the result
-	c.Set(key, result)
+	// Cache the result unless the cache was cleared while it was generated
+	c.set(key, result, generation)
 
 	return result, nil
 }
+
+// cloneResult copies a result so callers cannot alter what is cached
+func cloneResult(result *SecurityResult) *SecurityResult {
+	if result == nil {
+		return nil
+	}
+	clone := *result
+	if result.Analysis != nil {
+		analysis := *result.Analysis
+		analysis.Commands = slices.Clone(analysis.Commands)
+		analysis.RiskFactors = slices.Clone(analysis.RiskFactors)
+		clone.Analysis = &analysis
+	}
+	return &clone
+}
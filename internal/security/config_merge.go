@@ -0,0 +1,314 @@
+package security
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Markers around the summary written at the top of a merged configuration file
+const (
+	mergeSummaryStart = "# --- security-config-diff --update summary ---"
+	mergeSummaryEnd   = "# --- end of update summary ---"
+)
+
+// mergeSkippedPaths are left as the user has them, as they describe the file rather than configure it
+var mergeSkippedPaths = []string{"metadata"}
+
+// MergeReport lists what a configuration merge changed, by dotted path such as rules.aws_keys
+type MergeReport struct {
+	Added     []string // New defaults added to the user config
+	Updated   []string // Values the user had not changed, updated to the new default
+	Kept      []string // Values the user changed, kept although the default differs
+	Removed   []string // Defaults no longer shipped, kept in the user config and marked with a comment
+	Declined  []string // Defaults the user deleted, which are not added back
+	HasBase   bool     // Whether the previous default was available to tell user changes from old defaults
+	Timestamp time.Time
+}
+
+// Changed reports whether the merge altered the user config
+func (r MergeReport) Changed() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0 || len(r.Removed) > 0
+}
+
+// Lines describes the merge, one line per kind of change
+func (r MergeReport) Lines() []string {
+	var lines []string
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"Added new defaults", r.Added},
+		{"Updated unchanged values to new defaults", r.Updated},
+		{"Kept your changes where the default differs", r.Kept},
+		{"Marked defaults that are no longer shipped", r.Removed},
+		{"Left out defaults you had deleted", r.Declined},
+	} {
+		if len(group.paths) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", group.label, strings.Join(group.paths, ", ")))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No changes needed")
+	}
+	if !r.HasBase {
+		lines = append(lines, "No record of the previous default was found, so every value that differs from the new default was kept as your change")
+	}
+	return lines
+}
+
+// summary renders the report as the comment block written at the top of the merged file
+func (r MergeReport) summary() string {
+	var sb strings.Builder
+	sb.WriteString(mergeSummaryStart + "\n")
+	fmt.Fprintf(&sb, "# Merged with the default configuration on %s\n", r.Timestamp.Format(time.RFC3339))
+	for _, line := range r.Lines() {
+		sb.WriteString("# " + line + "\n")
+	}
+	sb.WriteString(mergeSummaryEnd + "\n\n")
+	return sb.String()
+}
+
+// BaseConfigPath returns where the default configuration a user config was last based on is recorded
+func BaseConfigPath(rulesPath string) string {
+	return filepath.Join(filepath.Dir(rulesPath), "security_base.yaml")
+}
+
+// writeBaseConfig records the default configuration a user config is based on, for later merges
+func writeBaseConfig(rulesPath, defaultConfig string) error {
+	return os.WriteFile(BaseConfigPath(rulesPath), []byte(defaultConfig), 0600)
+}
+
+// MergeConfigUpdate merges a new default configuration into the user's configuration, saves it to
+// rulesPath and then records the new default as the base for the next merge. The base is only
+// recorded once the user's file is saved, so a failed save leaves the next merge with the old base.
+func MergeConfigUpdate(rulesPath string, user []byte, latest string) ([]byte, MergeReport, error) {
+	base, err := os.ReadFile(BaseConfigPath(rulesPath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, MergeReport{}, fmt.Errorf("failed to read previous default config: %w", err)
+	}
+
+	merged, report, err := MergeConfig(base, user, []byte(latest))
+	if err != nil {
+		return nil, report, err
+	}
+	if err := os.WriteFile(rulesPath, merged, 0600); err != nil {
+		return nil, report, fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := writeBaseConfig(rulesPath, latest); err != nil {
+		return nil, report, fmt.Errorf("failed to record default config: %w", err)
+	}
+	return merged, report, nil
+}
+
+// MergeConfig performs a three-way merge of a security configuration. base is the default the user
+// config was created from and may be empty, user is the user's config and latest is the new default.
+// Values the user has not changed follow the new default, user changes are kept, new defaults are added,
+// and defaults that are no longer shipped are kept but marked with a comment. Comments in the user
+// config are preserved and a summary of the merge is written at the top of the result.
+func MergeConfig(base, user, latest []byte) ([]byte, MergeReport, error) {
+	report := MergeReport{HasBase: len(bytes.TrimSpace(base)) > 0, Timestamp: time.Now()}
+
+	userRoot, err := parseMappingDocument(stripMergeSummary(user))
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to parse user config: %w", err)
+	}
+	latestRoot, err := parseMappingDocument(latest)
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to parse default config: %w", err)
+	}
+	var baseRoot *yaml.Node
+	if report.HasBase {
+		if baseRoot, err = parseMappingDocument(base); err != nil {
+			return nil, report, fmt.Errorf("failed to parse previous default config: %w", err)
+		}
+	}
+
+	mergeMapping("", baseRoot, mappingValue(userRoot), mappingValue(latestRoot), &report)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(userRoot); err != nil {
+		return nil, report, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, report, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+
+	merged := append([]byte(report.summary()), buf.Bytes()...)
+	if _, err := ValidateSecurityConfig(merged); err != nil {
+		return nil, report, fmt.Errorf("merged config is invalid: %w", err)
+	}
+	return merged, report, nil
+}
+
+// parseMappingDocument parses YAML whose top level is a mapping
+func parseMappingDocument(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if mappingValue(&doc) == nil {
+		return nil, fmt.Errorf("top level is not a mapping")
+	}
+	return &doc, nil
+}
+
+// mappingValue returns the mapping held by a document or mapping node, or nil
+func mappingValue(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	return node
+}
+
+// mergeMapping merges latest into user key by key, in place
+func mergeMapping(path string, base, user, latest *yaml.Node, report *MergeReport) {
+	base = mappingValue(base)
+
+	for i := 0; i+1 < len(latest.Content); i += 2 {
+		key, latestValue := latest.Content[i], latest.Content[i+1]
+		keyPath := joinPath(path, key.Value)
+		if isSkippedPath(keyPath) {
+			continue
+		}
+
+		_, userValue := lookupKey(user, key.Value)
+		_, baseValue := lookupKey(base, key.Value)
+		switch {
+		case userValue == nil && baseValue != nil:
+			// The user deleted a default, so leave it out
+			report.Declined = append(report.Declined, keyPath)
+		case userValue == nil:
+			user.Content = append(user.Content, copyNode(key), copyNode(latestValue))
+			report.Added = append(report.Added, keyPath)
+		default:
+			mergeValue(keyPath, baseValue, userValue, latestValue, report)
+		}
+	}
+
+	for i := 0; i+1 < len(user.Content); i += 2 {
+		key := user.Content[i]
+		keyPath := joinPath(path, key.Value)
+		if isSkippedPath(keyPath) {
+			continue
+		}
+		if latestKey, _ := lookupKey(latest, key.Value); latestKey != nil {
+			continue
+		}
+		// Keys that were in the previous default but not the new one were dropped upstream.
+		// Keys that were never defaults are the user's own additions and are left alone.
+		if baseKey, _ := lookupKey(base, key.Value); baseKey != nil {
+			if !strings.Contains(key.HeadComment, "no longer in the default") {
+				key.HeadComment = joinComments(key.HeadComment, "# "+key.Value+" is no longer in the default configuration; remove it if you do not need it")
+			}
+			report.Removed = append(report.Removed, keyPath)
+		}
+	}
+}
+
+// mergeValue merges a single value. Mappings are merged recursively; scalars and lists are replaced whole.
+func mergeValue(path string, base, user, latest *yaml.Node, report *MergeReport) {
+	if user.Kind == yaml.MappingNode && latest.Kind == yaml.MappingNode {
+		mergeMapping(path, base, user, latest, report)
+		return
+	}
+	if nodesEqual(user, latest) {
+		return
+	}
+
+	if base != nil && nodesEqual(user, base) {
+		// Unchanged by the user, so follow the new default but keep any comment the user added
+		replacement := copyNode(latest)
+		if replacement.LineComment == "" {
+			replacement.LineComment = user.LineComment
+		}
+		replacement.HeadComment = joinComments(user.HeadComment, replacement.HeadComment)
+		*user = *replacement
+		report.Updated = append(report.Updated, path)
+		return
+	}
+
+	// Changed by the user. Only worth reporting where the default itself moved, or when there is
+	// no base to tell the difference.
+	if base == nil || !nodesEqual(base, latest) {
+		report.Kept = append(report.Kept, path)
+	}
+}
+
+// lookupKey returns the key and value nodes for a key in a mapping node
+func lookupKey(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// nodesEqual compares the values of two nodes, ignoring comments and formatting
+func nodesEqual(a, b *yaml.Node) bool {
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// copyNode deep copies a node so the merged document does not share nodes with its inputs
+func copyNode(node *yaml.Node) *yaml.Node {
+	clone := *node
+	clone.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		clone.Content[i] = copyNode(child)
+	}
+	return &clone
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isSkippedPath(path string) bool {
+	return slices.Contains(mergeSkippedPaths, path)
+}
+
+func joinComments(first, second string) string {
+	switch {
+	case first == "":
+		return second
+	case second == "":
+		return first
+	}
+	return first + "\n" + second
+}
+
+// stripMergeSummary removes the summary left at the top of the file by a previous merge
+func stripMergeSummary(data []byte) []byte {
+	text := string(data)
+	if !strings.HasPrefix(text, mergeSummaryStart) {
+		return data
+	}
+	end := strings.Index(text, mergeSummaryEnd)
+	if end < 0 {
+		return data
+	}
+	return []byte(strings.TrimLeft(text[end+len(mergeSummaryEnd):], "\n"))
+}
@@ -10,7 +10,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
-	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -60,9 +60,8 @@ func (o *Operations) SafeHTTPGet(ctx context.Context, urlStr string) (*SafeHTTPR
 		return nil, err
 	}
 
-	// 4. Use instrumented HTTP client
-	client := &http.Client{}
-	client = telemetry.WrapHTTPClient(client)
+	// 4. Use shared HTTP client
+	client := httpclient.New(httpclient.Options{})
 
 	// 5. Fetch content normally (no modifications)
 	resp, err := client.Do(req)
@@ -142,9 +141,8 @@ func (o *Operations) SafeHTTPPost(ctx context.Context, urlStr string, body io.Re
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	// 4. Use instrumented HTTP client
-	client := &http.Client{}
-	client = telemetry.WrapHTTPClient(client)
+	// 4. Use shared HTTP client
+	client := httpclient.New(httpclient.Options{})
 
 	// 5. Fetch content normally (no modifications)
 	resp, err := client.Do(req)
@@ -228,9 +226,8 @@ func (o *Operations) SafeHTTPGetWithHeaders(ctx context.Context, urlStr string,
 		req.Header.Set(key, value)
 	}
 
-	// 4. Use instrumented HTTP client
-	client := &http.Client{}
-	client = telemetry.WrapHTTPClient(client)
+	// 4. Use shared HTTP client
+	client := httpclient.New(httpclient.Options{})
 
 	// 5. Execute request
 	resp, err := client.Do(req)
@@ -320,7 +317,7 @@ func (o *Operations) SafeHTTPPostWithHeaders(ctx context.Context, urlStr string,
 	}
 
 	// 4. Execute request
-	client := &http.Client{}
+	client := httpclient.New(httpclient.Options{})
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
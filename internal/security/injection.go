@@ -0,0 +1,232 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prompt injection defaults
+const (
+	defaultInjectionAction    = "warn"
+	defaultInjectionThreshold = 1.0
+)
+
+// Source trust levels for the prompt injection stage
+const (
+	TrustHigh   = "high"
+	TrustMedium = "medium"
+	TrustLow    = "low"
+)
+
+// trustMultipliers scale the injection score, so less trusted sources reach the threshold sooner
+var trustMultipliers = map[string]float64{
+	TrustHigh:   0.5,
+	TrustMedium: 1.0,
+	TrustLow:    1.5,
+}
+
+// injectionSignal is one kind of prompt injection indicator. Each signal adds its weight to
+// the score once, however many times it matches.
+type injectionSignal struct {
+	name     string
+	weight   float64
+	patterns []*regexp.Regexp
+	match    func(content string) bool // Used instead of patterns when set
+}
+
+// injectionSignals is the built-in prompt injection rule pack
+var injectionSignals = []injectionSignal{
+	{name: "instruction_override", weight: 1.0, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\s+(?:all\s+|any\s+|the\s+|your\s+|these\s+|of\s+)*(?:previous|prior|above|earlier|preceding|original|system)\s+(?:instructions?|prompts?|directions|rules|guidelines)`),
+		regexp.MustCompile(`(?i)\b(?:new|updated|real|actual)\s+(?:system\s+)?instructions\s*:`),
+		regexp.MustCompile(`(?i)\bforget\s+(?:everything|all)\s+(?:you|that)\b`),
+	}},
+	{name: "role_override", weight: 0.6, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(?:a|an|in|the)\b`),
+		regexp.MustCompile(`(?i)\b(?:developer|god|jailbreak|dan)\s+mode\b`),
+		regexp.MustCompile(`(?i)\bfrom\s+now\s+on,?\s+you\s+(?:will|must|are)\b`),
+		regexp.MustCompile(`(?i)\bpretend\s+(?:to\s+be|you\s+are)\b`),
+	}},
+	{name: "chat_delimiters", weight: 0.7, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`<\|(?:im_start|im_end|system|user|assistant|endoftext)\|>`),
+		regexp.MustCompile(`\[/?INST\]|<</?SYS>>`),
+		regexp.MustCompile(`(?i)</?(?:system|system_prompt|system-prompt)>`),
+	}},
+	{name: "tool_lure", weight: 0.5, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:call|invoke|execute|trigger)\s+the\s+[\w.-]+\s+tool\b`),
+		regexp.MustCompile(`(?i)<(?:tool_call|function_calls?|invoke\s+name=)`),
+		regexp.MustCompile(`(?i)"(?:tool_name|function_call|tool_calls)"\s*:`),
+	}},
+	{name: "exfiltration_lure", weight: 0.8, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:send|post|upload|forward|transmit|exfiltrate|append)\b[^.\n]{0,80}\b(?:api\s*keys?|tokens?|credentials|passwords?|secrets?|conversation|chat\s+history|system\s+prompt|environment\s+variables|ssh\s+keys?)\b[^.\n]{0,80}\b(?:to|into)\s+(?:https?://|the\s+(?:url|endpoint|server|webhook))`),
+		regexp.MustCompile(`!\[[^\]]*\]\(https?://[^)\s]*[?&][\w-]+=(?:\{|\$|%7B)`),
+	}},
+	{name: "concealment", weight: 0.5, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:do\s+not|don't|never)\s+(?:tell|inform|mention|reveal|show|alert|notify)\s+(?:this\s+to\s+)?(?:the\s+)?user\b`),
+		regexp.MustCompile(`(?i)\bwithout\s+(?:telling|informing|asking|alerting|notifying)\s+the\s+user\b`),
+	}},
+	{name: "addressed_to_ai", weight: 0.4, patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:if\s+you\s+are|attention|note\s+to|message\s+for|instructions\s+for)\s+(?:an?\s+|the\s+|any\s+)?(?:ai|llm|language\s+model|assistant|agent|chatbot)s?\b`),
+		regexp.MustCompile(`(?i)\b(?:ai|llm)\s+(?:assistants?|agents?|models?)\s+(?:reading|processing|summari[sz]ing)\s+this\b`),
+	}},
+	{name: "hidden_html", weight: 1.0, match: hasHiddenHTMLInstructions},
+}
+
+var (
+	// htmlComment captures the body of HTML comments
+	htmlComment = regexp.MustCompile(`<!--([\s\S]{0,1000}?)-->`)
+	// hiddenElement captures the text directly inside an element styled or marked to be invisible
+	hiddenElement = regexp.MustCompile(`(?i)<[a-z][a-z0-9]*\b[^>]*(?:(?:display\s*:\s*none|visibility\s*:\s*hidden|font-size\s*:\s*0(?:px|em|rem|pt)?\s*[;"']|opacity\s*:\s*0(?:\.0+)?\s*[;"'])[^>]*>|\shidden(?:[\s=/][^>]*)?>)([^<]{1,1000})`)
+	// instructionWords suggests hidden text is addressed to a model rather than being markup or tracking code
+	instructionWords = regexp.MustCompile(`(?i)\b(?:ignore|disregard|instructions?|assistant|ai|llm|language\s+model|system\s+prompt|you\s+must|you\s+should|do\s+not\s+tell)\b`)
+)
+
+// hasHiddenHTMLInstructions reports whether HTML comments or invisible elements contain instruction-like text
+func hasHiddenHTMLInstructions(content string) bool {
+	if !strings.Contains(content, "<") {
+		return false
+	}
+	for _, re := range []*regexp.Regexp{htmlComment, hiddenElement} {
+		for _, match := range re.FindAllStringSubmatch(content, -1) {
+			if instructionWords.MatchString(match[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InjectionSignalNames returns the names of the built-in prompt injection signals, for use in disabled_signals
+func InjectionSignalNames() []string {
+	names := make([]string, 0, len(injectionSignals))
+	for _, signal := range injectionSignals {
+		names = append(names, signal.name)
+	}
+	return names
+}
+
+// scoreInjection returns the combined weight and names of the enabled signals found in content
+func scoreInjection(content string, disabled []string) (float64, []string) {
+	score := 0.0
+	var matched []string
+	for _, signal := range injectionSignals {
+		if slices.Contains(disabled, signal.name) {
+			continue
+		}
+		found := false
+		if signal.match != nil {
+			found = signal.match(content)
+		} else {
+			found = slices.ContainsFunc(signal.patterns, func(re *regexp.Regexp) bool {
+				return re.MatchString(content)
+			})
+		}
+		if found {
+			score += signal.weight
+			matched = append(matched, signal.name)
+		}
+	}
+	return score, matched
+}
+
+// validateInjectionConfig checks the prompt_injection section of the rules file
+func validateInjectionConfig(config InjectionConfig) error {
+	switch config.Action {
+	case "", "block", "warn_high", "warn", "notify":
+	default:
+		return fmt.Errorf("prompt_injection.action must be block, warn_high, warn or notify, got %q", config.Action)
+	}
+	if config.Threshold < 0 {
+		return fmt.Errorf("prompt_injection.threshold must not be negative")
+	}
+	for _, name := range config.DisabledSignals {
+		if !slices.Contains(InjectionSignalNames(), name) {
+			return fmt.Errorf("prompt_injection.disabled_signals has unknown signal %s (valid signals: %s)", name, strings.Join(InjectionSignalNames(), ", "))
+		}
+	}
+	for source, level := range config.SourceTrust {
+		if _, ok := trustMultipliers[level]; !ok {
+			return fmt.Errorf("prompt_injection.source_trust for %s must be high, medium or low, got %q", source, level)
+		}
+	}
+	return nil
+}
+
+// sourceTrustLevel returns the trust level for a source. Tool names take precedence over domains,
+// and the longest matching domain pattern wins. Trusted domains default to high trust.
+func (r *YAMLRuleEngine) sourceTrustLevel(source SourceContext, config InjectionConfig) string {
+	if level, ok := config.SourceTrust[source.Tool]; ok && source.Tool != "" {
+		return level
+	}
+
+	domain := strings.ToLower(source.Domain)
+	level, longest := "", 0
+	for pattern, patternLevel := range config.SourceTrust {
+		if domain != "" && r.domainMatches(domain, strings.ToLower(pattern)) && len(pattern) > longest {
+			level, longest = patternLevel, len(pattern)
+		}
+	}
+	if level != "" {
+		return level
+	}
+
+	if domain != "" && slices.ContainsFunc(r.rules.TrustedDomains, func(trusted string) bool {
+		return r.domainMatches(domain, trusted)
+	}) {
+		return TrustHigh
+	}
+	return TrustMedium
+}
+
+// EvaluatePromptInjection scores content against the prompt injection signals, adjusted for the
+// trust level of its source. It returns nil when the stage is disabled or the threshold is not reached.
+func (r *YAMLRuleEngine) EvaluatePromptInjection(source SourceContext, contents ...string) *SecurityResult {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.rules == nil || !r.rules.Injection.IsEnabled() {
+		return nil
+	}
+	config := r.rules.Injection
+
+	// Score each version of the content, e.g. original and decoded, and keep the highest
+	score, matched := 0.0, []string(nil)
+	for _, content := range contents {
+		if contentScore, contentMatched := scoreInjection(content, config.DisabledSignals); contentScore > score {
+			score, matched = contentScore, contentMatched
+		}
+	}
+	if score == 0 {
+		return nil
+	}
+
+	threshold := config.Threshold
+	if threshold <= 0 {
+		threshold = defaultInjectionThreshold
+	}
+	level := r.sourceTrustLevel(source, config)
+	if score*trustMultipliers[level] < threshold {
+		return nil
+	}
+
+	action := config.Action
+	if action == "" {
+		action = defaultInjectionAction
+	}
+	securityID := GenerateSecurityID(action)
+	rule := Rule{
+		Description: fmt.Sprintf("Possible prompt injection (%s; score %.1f from a %s trust source)", strings.Join(matched, ", "), score, level),
+		Action:      action,
+	}
+
+	return &SecurityResult{
+		Safe:      false,
+		Action:    mapRuleActionToSecurityAction(action),
+		Message:   r.formatSecurityMessage(rule, securityID),
+		ID:        securityID,
+		Timestamp: time.Now(),
+	}
+}
@@ -33,18 +33,19 @@ func init() {
 func NewSecurityManagerWithRules(rules *SecurityRules) (*SecurityManager, error) {
 	// Create test config
 	config := &SecurityConfig{
-		Enabled:      rules.Settings.Enabled,
-		RulesPath:    ":memory:",
-		LogPath:      ":memory:",
-		CacheMaxSize: 1000,
-		CacheMaxAge:  1 * time.Hour,
+		Enabled:              rules.Settings.Enabled,
+		RulesPath:            ":memory:",
+		LogPath:              ":memory:",
+		CacheMaxSize:         1000,
+		CacheMaxAge:          1 * time.Hour,
+		TrustedDomains:       rules.TrustedDomains,
+		DenyDomains:          rules.AccessControl.DenyDomains,
+		AllowlistOnly:        rules.AccessControl.AllowlistOnly,
+		BlockPrivateNetworks: rules.AccessControl.BlockPrivateNetworks,
 	}
 
 	// Create cache
-	cache := &Cache{
-		maxSize: config.CacheMaxSize,
-		maxAge:  config.CacheMaxAge,
-	}
+	cache := NewCache(config.CacheMaxSize, config.CacheMaxAge)
 
 	// Create rule engine with provided rules
 	ruleEngine := &YAMLRuleEngine{
@@ -60,10 +61,11 @@ func NewSecurityManagerWithRules(rules *SecurityRules) (*SecurityManager, error)
 	}
 
 	// Create override manager with temporary paths
-	overrideManager, err := NewOverrideManager(os.TempDir()+"/test_overrides.yaml", os.TempDir()+"/test_security.log")
+	overrideManager, err := NewOverrideManager(filepath.Join(os.TempDir(), "test_overrides.yaml"), filepath.Join(os.TempDir(), "test_security.log"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create override manager: %w", err)
 	}
+	overrideManager.onChange = cache.Clear
 
 	// Create source trust manager
 	sourceTrust := &SourceTrust{
@@ -117,10 +119,7 @@ func NewSecurityManager() (*SecurityManager, error) {
 
 	// Create cache
 	logrus.Debug("Creating security cache")
-	cache := &Cache{
-		maxSize: config.CacheMaxSize,
-		maxAge:  config.CacheMaxAge,
-	}
+	cache := NewCache(config.CacheMaxSize, config.CacheMaxAge)
 
 	// Create rule engine
 	logrus.WithField("rules_path", config.RulesPath).Debug("Creating YAML rule engine")
@@ -139,6 +138,7 @@ func NewSecurityManager() (*SecurityManager, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create override manager: %w", err)
 	}
+	overrideManager.onChange = cache.Clear
 	logrus.Debug("Override manager created successfully")
 
 	// Create source trust manager
@@ -380,7 +380,7 @@ func loadSecurityConfig() (*SecurityConfig, error) {
 		RulesPath:              rulesPath,
 		LogPath:                logPath,
 		AutoReload:             settings.AutoReload,
-		MaxScanSize:            settings.MaxScanSize,
+		MaxScanSize:            settings.MaxScanSize * 1024, // Settings are in KB
 		ThreatThreshold:        settings.ThreatThreshold,
 		EnableDestinationCheck: true, // Keep this enabled by default
 		EnableSecretDetection:  true, // Keep this enabled by default
@@ -394,6 +394,8 @@ func loadSecurityConfig() (*SecurityConfig, error) {
 		SuspiciousDomains:      []string{}, // Not configurable via YAML currently
 		DenyFiles:              rules.AccessControl.DenyFiles,
 		DenyDomains:            rules.AccessControl.DenyDomains,
+		AllowlistOnly:          rules.AccessControl.AllowlistOnly,
+		BlockPrivateNetworks:   rules.AccessControl.BlockPrivateNetworks,
 	}
 
 	return config, nil
@@ -405,7 +407,24 @@ func InitGlobalSecurityManager() error {
 
 	globalManagerMutex.Lock()
 	defer globalManagerMutex.Unlock()
+	return initGlobalSecurityManager()
+}
+
+// StartGlobalSecurityManager initialises the global security manager in the background, so that
+// loading and compiling the rules does not hold up the server starting, then calls done with the
+// result. The manager's lock is taken before it returns, so security checks made before
+// initialisation finishes wait for it rather than running without the manager.
+func StartGlobalSecurityManager(done func(error)) {
+	globalManagerMutex.Lock()
+	go func() {
+		err := initGlobalSecurityManager()
+		globalManagerMutex.Unlock()
+		done(err)
+	}()
+}
 
+// initGlobalSecurityManager initialises the global security manager, with its lock held
+func initGlobalSecurityManager() error {
 	// Check if already initialised to avoid double initialisation
 	if GlobalSecurityManager != nil {
 		logrus.Debug("Security system already initialised, skipping")
@@ -440,6 +459,14 @@ func InitGlobalSecurityManager() error {
 
 // Global convenience functions for easy integration
 
+// GetGlobalSecurityManager returns the global security manager, waiting for it to finish
+// initialising, or nil when the security system is not enabled
+func GetGlobalSecurityManager() *SecurityManager {
+	globalManagerMutex.RLock()
+	defer globalManagerMutex.RUnlock()
+	return GlobalSecurityManager
+}
+
 // IsEnabled returns whether the global security system is enabled
 func IsEnabled() bool {
 	globalManagerMutex.RLock()
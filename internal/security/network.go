@@ -0,0 +1,107 @@
+package security
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+func init() {
+	// Apply the outbound network policy to every client built by the shared HTTP client factory
+	httpclient.SetNetworkPolicy(networkPolicy{})
+}
+
+// networkPolicy applies the global security manager's outbound network policy to the shared HTTP client
+type networkPolicy struct{}
+
+func (networkPolicy) CheckHost(host string) error {
+	return CheckNetworkHost(host)
+}
+
+func (networkPolicy) CheckAddress(addr netip.Addr) error {
+	return CheckNetworkAddress(addr)
+}
+
+// CheckNetworkHost verifies an outbound request to host is allowed. The host must not be in the domain
+// deny list and, in allowlist mode, must be one of the trusted domains or a subdomain of one.
+func (m *SecurityManager) CheckNetworkHost(host string) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if err := m.CheckDomainAccess(host); err != nil {
+		return err
+	}
+
+	if m.config.AllowlistOnly && !slices.ContainsFunc(m.config.TrustedDomains, func(trusted string) bool {
+		return allowlistMatches(host, trusted)
+	}) {
+		LogAccessControlBlock("domain_not_allowlisted", host, "http")
+		return fmt.Errorf("access denied: %s is not in trusted_domains and only trusted domains are allowed. This is an access control policy that cannot be overridden by agents. The user may change this behaviour in their MCP DevTools configuration if required", host)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return m.CheckNetworkAddress(addr)
+	}
+	return nil
+}
+
+// CheckNetworkAddress verifies an outbound connection to addr is allowed, which it is unless private
+// networks are blocked and addr is a private, loopback, link-local or unspecified address
+func (m *SecurityManager) CheckNetworkAddress(addr netip.Addr) error {
+	if !m.IsEnabled() || !m.config.BlockPrivateNetworks {
+		return nil
+	}
+
+	if isPrivateAddress(addr) {
+		LogAccessControlBlock("network_address_denied", addr.String(), "http")
+		return fmt.Errorf("access denied: %s is a private, loopback or link-local address and private networks are blocked. This is an access control policy that cannot be overridden by agents. The user may change this behaviour in their MCP DevTools configuration if required", addr)
+	}
+	return nil
+}
+
+// CheckNetworkHost checks an outbound request's host via global manager
+func CheckNetworkHost(host string) error {
+	globalManagerMutex.RLock()
+	manager := GlobalSecurityManager
+	globalManagerMutex.RUnlock()
+
+	if manager == nil {
+		return nil
+	}
+	return manager.CheckNetworkHost(host)
+}
+
+// CheckNetworkAddress checks an outbound connection's address via global manager
+func CheckNetworkAddress(addr netip.Addr) error {
+	globalManagerMutex.RLock()
+	manager := GlobalSecurityManager
+	globalManagerMutex.RUnlock()
+
+	if manager == nil {
+		return nil
+	}
+	return manager.CheckNetworkAddress(addr)
+}
+
+// allowlistMatches checks if host is the trusted domain, a subdomain of it, or matches its wildcard
+func allowlistMatches(host, trusted string) bool {
+	trusted = strings.ToLower(strings.TrimPrefix(trusted, "*."))
+	return host == trusted || strings.HasSuffix(host, "."+trusted)
+}
+
+// isPrivateAddress reports whether addr is on a private (RFC 1918 or IPv6 unique local), loopback,
+// link-local or unspecified network, including cloud metadata endpoints such as 169.254.169.254
+func isPrivateAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsPrivate() ||
+		addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified()
+}
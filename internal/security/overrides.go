@@ -101,10 +101,20 @@ func (o *OverrideManager) SaveOverride(override SecurityOverride, securityID str
 		o.addToAllowlist(override)
 	}
 
+	// Results cached before the override must not be served afterwards
+	o.notifyChange()
+
 	// Save to file
 	return o.saveOverrides()
 }
 
+// notifyChange calls the change callback, if any, after overrides are added or removed
+func (o *OverrideManager) notifyChange() {
+	if o.onChange != nil {
+		o.onChange()
+	}
+}
+
 // addToAllowlist adds patterns to the allowlist based on override
 func (o *OverrideManager) addToAllowlist(override SecurityOverride) {
 	// Add pattern to appropriate allowlist based on type
@@ -271,6 +281,7 @@ func (o *OverrideManager) CleanupOldOverrides(maxAge time.Duration) error {
 	}
 
 	if updated {
+		o.notifyChange()
 		return o.saveOverrides()
 	}
 
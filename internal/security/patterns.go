@@ -363,6 +363,10 @@ func (m *EntropyMatcher) String() string {
 	return "entropy:" + string(rune(m.threshold))
 }
 
+// DefaultRegexTimeout is how long a regex rule pattern may take to match before it is treated as not
+// matching, to prevent ReDoS
+const DefaultRegexTimeout = 100 * time.Millisecond
+
 // RegexMatcher matches using regular expressions with timeout protection
 type RegexMatcher struct {
 	pattern string
@@ -379,7 +383,7 @@ func NewRegexMatcher(pattern string) (*RegexMatcher, error) {
 	return &RegexMatcher{
 		pattern: pattern,
 		regex:   regex,
-		timeout: 100 * time.Millisecond, // Default 100ms timeout to prevent ReDoS
+		timeout: DefaultRegexTimeout,
 	}, nil
 }
 
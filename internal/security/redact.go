@@ -0,0 +1,280 @@
+package security
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleActionRedact is the action of rules whose matches are redacted from tool output, rather than
+// used to warn about or block content
+const RuleActionRedact = "redact"
+
+// redactionRule detects one kind of secret, built from a rule with the redact action. When a pattern
+// has a capture group only the first group is replaced, leaving surrounding context such as the
+// variable name visible.
+type redactionRule struct {
+	name     string
+	patterns []*regexp.Regexp
+	entropy  *EntropyMatcher // when set, only matches at or above its threshold are redacted
+}
+
+// compileRedactionRules builds the redaction rules from the rules with the redact action and their
+// compiled patterns. Rules with an entropy pattern are generic, so they are applied after the others
+// and specific token formats are labelled first.
+func compileRedactionRules(rules *SecurityRules, compiled map[string]PatternMatcher) []redactionRule {
+	var redaction []redactionRule
+	for name, rule := range rules.Rules {
+		if rule.Action != RuleActionRedact {
+			continue
+		}
+		redactionRule := redactionRule{name: name}
+		for i := range rule.Patterns {
+			switch matcher := compiled[fmt.Sprintf("%s_%d", name, i)].(type) {
+			case *RegexMatcher:
+				redactionRule.patterns = append(redactionRule.patterns, matcher.regex)
+			case *EntropyMatcher:
+				redactionRule.entropy = matcher
+			}
+		}
+		redaction = append(redaction, redactionRule)
+	}
+	slices.SortFunc(redaction, func(a, b redactionRule) int {
+		return cmp.Or(cmp.Compare(boolRank(a.entropy != nil), boolRank(b.entropy != nil)), strings.Compare(a.name, b.name))
+	})
+	return redaction
+}
+
+// validateRedactionRule checks a redact rule has regex patterns to find secrets with and at most one
+// entropy pattern to filter them by
+func validateRedactionRule(name string, rule Rule) error {
+	regexes, entropies := 0, 0
+	for i, pattern := range rule.Patterns {
+		switch {
+		case pattern.Regex != "":
+			regexes++
+		case pattern.Entropy > 0:
+			entropies++
+		default:
+			return fmt.Errorf("rule %s pattern %d must be a regex or entropy pattern, as the rule redacts", name, i)
+		}
+	}
+	if regexes == 0 || entropies > 1 {
+		return fmt.Errorf("rule %s redacts, so needs at least one regex pattern and at most one entropy pattern", name)
+	}
+	return nil
+}
+
+// boolRank orders false before true
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// defaultRedactionRules are the redaction rules of the default configuration. They are used for
+// configurations with no redact rules, such as those created before redaction rules were part of the
+// rules section, so that redaction stays on by default.
+var defaultRedactionRules = sync.OnceValue(func() []redactionRule {
+	var rules SecurityRules
+	if err := yaml.Unmarshal([]byte(defaultConfigTemplate), &rules); err != nil {
+		return nil
+	}
+	engine := &YAMLRuleEngine{}
+	if err := engine.compilePatterns(&rules); err != nil {
+		return nil
+	}
+	return engine.redaction
+})
+
+// redactionRuleNames returns the names of the redaction rules a configuration uses, for checking
+// disabled_rules
+func redactionRuleNames(rules *SecurityRules) []string {
+	var names []string
+	for name, rule := range rules.Rules {
+		if rule.Action == RuleActionRedact {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		for _, rule := range defaultRedactionRules() {
+			names = append(names, rule.name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+var (
+	placeholderKey     []byte
+	placeholderKeyOnce sync.Once
+)
+
+// placeholder returns a stable placeholder for a secret. The digest is keyed with a random
+// per-process key, so the same secret maps to the same placeholder for the life of the server
+// while short secrets cannot be recovered by hashing guesses.
+func placeholder(ruleName, secret string) string {
+	placeholderKeyOnce.Do(func() {
+		placeholderKey = make([]byte, 32)
+		_, _ = rand.Read(placeholderKey)
+	})
+	mac := hmac.New(sha256.New, placeholderKey)
+	mac.Write([]byte(secret))
+	return "[REDACTED:" + ruleName + ":" + hex.EncodeToString(mac.Sum(nil))[:8] + "]"
+}
+
+// redactText replaces secrets matched by the enabled rules and returns the count of replacements
+func redactText(text string, rules []redactionRule, config RedactionConfig) (string, int) {
+	count := 0
+	for _, rule := range rules {
+		if slices.Contains(config.DisabledRules, rule.name) {
+			continue
+		}
+		threshold := 0.0
+		if rule.entropy != nil {
+			threshold = cmp.Or(config.EntropyThreshold, rule.entropy.threshold)
+		}
+		for _, pattern := range rule.patterns {
+			text = replaceMatches(text, pattern, func(secret string) (string, bool) {
+				// Already redacted by an earlier rule
+				if strings.HasPrefix(secret, "[REDACTED:") {
+					return "", false
+				}
+				if rule.entropy != nil && rule.entropy.calculateEntropy(secret) < threshold {
+					return "", false
+				}
+				count++
+				return placeholder(rule.name, secret), true
+			})
+		}
+	}
+	return text, count
+}
+
+// replaceMatches replaces each match of a pattern, or its first capture group, with the result of
+// replace
+func replaceMatches(text string, pattern *regexp.Regexp, replace func(secret string) (string, bool)) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	group := min(pattern.NumSubexp(), 1)
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 {
+			continue
+		}
+		replacement, ok := replace(text[start:end])
+		if !ok {
+			continue
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(replacement)
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Redact replaces secrets in text with placeholders when redaction is enabled
+func (m *SecurityManager) Redact(text string) (string, int) {
+	if !m.IsEnabled() || m.ruleEngine == nil || text == "" {
+		return text, 0
+	}
+	m.ruleEngine.mutex.RLock()
+	var config RedactionConfig
+	if m.ruleEngine.rules != nil {
+		config = m.ruleEngine.rules.Redaction
+	}
+	rules := m.ruleEngine.redaction
+	m.ruleEngine.mutex.RUnlock()
+
+	if !config.IsEnabled() {
+		return text, 0
+	}
+	if len(rules) == 0 {
+		rules = defaultRedactionRules()
+	}
+	return redactText(text, rules, config)
+}
+
+// Redact replaces secrets in text via the global manager
+func Redact(text string) (string, int) {
+	globalManagerMutex.RLock()
+	manager := GlobalSecurityManager
+	globalManagerMutex.RUnlock()
+
+	if manager == nil {
+		return text, 0
+	}
+	return manager.Redact(text)
+}
+
+// RedactToolResult redacts secrets from the text, embedded text resources and structured
+// content of a tool result in place, returning the number of secrets replaced
+func RedactToolResult(toolName string, result *mcp.CallToolResult) int {
+	total := 0
+	transformToolResult(result, func(text string) string {
+		redacted, count := Redact(text)
+		total += count
+		return redacted
+	})
+
+	if total > 0 {
+		logrus.WithFields(logrus.Fields{
+			"tool":    toolName,
+			"secrets": total,
+		}).Debug("Redacted secrets from tool output")
+	}
+	return total
+}
+
+// transformToolResult applies transform in place to the text content, embedded text resources
+// and structured content of a tool result
+func transformToolResult(result *mcp.CallToolResult, transform func(string) string) {
+	if result == nil {
+		return
+	}
+
+	for i, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			c.Text = transform(c.Text)
+			result.Content[i] = c
+		case mcp.EmbeddedResource:
+			if resource, ok := c.Resource.(mcp.TextResourceContents); ok {
+				resource.Text = transform(resource.Text)
+				c.Resource = resource
+				result.Content[i] = c
+			}
+		}
+	}
+
+	if result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			// Replacements contain no JSON metacharacters, so the transformed document stays valid
+			if transformed := transform(string(data)); transformed != string(data) {
+				var structured any
+				if err := json.Unmarshal([]byte(transformed), &structured); err == nil {
+					result.StructuredContent = structured
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package security
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RuleCheckMatch is a rule that matched sample content
+type RuleCheckMatch struct {
+	Rule        string   `json:"rule"`
+	Description string   `json:"description"`
+	Action      string   `json:"action"`   // The rule's configured action
+	Priority    int      `json:"priority"` // Lower priorities are evaluated first
+	Patterns    []string `json:"patterns"` // The rule's patterns that matched
+}
+
+// RuleCheckReport describes how the security rules treat sample content
+type RuleCheckReport struct {
+	Matches         []RuleCheckMatch `json:"matches"`                    // Matching rules in evaluation order
+	DecidedBy       string           `json:"decided_by,omitempty"`       // The rule or stage that decides the action
+	Action          string           `json:"action"`                     // The resulting action: allow, warn or block
+	SizeLimit       string           `json:"size_limit,omitempty"`       // Set when the content exceeds a size limit
+	PromptInjection string           `json:"prompt_injection,omitempty"` // Set when the prompt injection stage triggers
+}
+
+// CheckRules reports every rule that matches content from the given source, in the order rules are
+// evaluated, along with the action the security system would take. When ruleName is set only that
+// rule is checked. Unlike AnalyseContent nothing is cached or written to the security log, so it can be
+// used to try out rules.
+func (r *YAMLRuleEngine) CheckRules(content string, source SourceContext, ruleName string) (*RuleCheckReport, error) {
+	report, err := r.checkRules(content, source, ruleName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The prompt injection stage only runs when no rule matched
+	if report.DecidedBy == "" && ruleName == "" {
+		if result := r.EvaluatePromptInjection(source, content); result != nil {
+			report.DecidedBy = "prompt_injection"
+			report.Action = result.Action
+			report.PromptInjection = result.Message
+		}
+	}
+	return report, nil
+}
+
+func (r *YAMLRuleEngine) checkRules(content string, source SourceContext, ruleName string) (*RuleCheckReport, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	report := &RuleCheckReport{Matches: []RuleCheckMatch{}, Action: ActionAllow}
+	if r.rules == nil {
+		return report, nil
+	}
+	if ruleName != "" {
+		if _, exists := r.rules.Rules[ruleName]; !exists {
+			names := make([]string, 0, len(r.rules.Rules))
+			for name := range r.rules.Rules {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return nil, fmt.Errorf("rule %s not found (available rules: %s)", ruleName, strings.Join(names, ", "))
+		}
+	}
+
+	if sizeResult := r.checkSizeLimits(content, source); sizeResult != nil {
+		report.SizeLimit = sizeResult.Message
+		report.DecidedBy = "size_limit"
+		report.Action = sizeResult.Action
+	}
+
+	config := &SecurityConfig{
+		EnableBase64Scanning: r.rules.Settings.EnableBase64Scanning,
+		MaxBase64DecodedSize: r.rules.Settings.MaxBase64DecodedSize,
+	}
+	evaluationContent := r.applyContentSizeLimits(content)
+
+	for _, ruleInfo := range r.sortRulesByPriority() {
+		if ruleName != "" && ruleInfo.Name != ruleName {
+			continue
+		}
+		if !r.evaluateRuleWithConfig(ruleInfo.Name, ruleInfo.Rule, evaluationContent, source, config) {
+			continue
+		}
+
+		match := RuleCheckMatch{
+			Rule:        ruleInfo.Name,
+			Description: ruleInfo.Rule.Description,
+			Action:      ruleInfo.Rule.Action,
+			Priority:    ruleInfo.Priority,
+		}
+		contentToMatch := r.ruleContent(ruleInfo.Rule, evaluationContent, config)
+		for i, pattern := range ruleInfo.Rule.Patterns {
+			if matcher, exists := r.compiled[fmt.Sprintf("%s_%d", ruleInfo.Name, i)]; exists && matcher.Match(contentToMatch) {
+				match.Patterns = append(match.Patterns, describePattern(pattern))
+			}
+		}
+		report.Matches = append(report.Matches, match)
+
+		// The first match decides the action, as in EvaluateContent
+		if report.DecidedBy == "" {
+			report.DecidedBy = ruleInfo.Name
+			report.Action = mapRuleActionToSecurityAction(ruleInfo.Rule.Action)
+		}
+	}
+	return report, nil
+}
+
+// describePattern renders a pattern as it appears in the rules file, e.g. contains: "curl | sh"
+func describePattern(pattern PatternConfig) string {
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"literal", pattern.Literal},
+		{"contains", pattern.Contains},
+		{"starts_with", pattern.StartsWith},
+		{"ends_with", pattern.EndsWith},
+		{"file_path", pattern.FilePath},
+		{"url", pattern.URL},
+		{"regex", pattern.Regex},
+		{"glob", pattern.Glob},
+	} {
+		if field.value != "" {
+			return fmt.Sprintf("%s: %q", field.name, field.value)
+		}
+	}
+	if pattern.Entropy > 0 {
+		return fmt.Sprintf("entropy: %g", pattern.Entropy)
+	}
+	return "(empty pattern)"
+}
+
+// CheckRules reports how the manager's current rules treat sample content
+func (m *SecurityManager) CheckRules(content string, source SourceContext, ruleName string) (*RuleCheckReport, error) {
+	if m.ruleEngine == nil {
+		return nil, fmt.Errorf("security rules are not loaded")
+	}
+	return m.ruleEngine.CheckRules(content, source, ruleName)
+}
+
+// CheckRulesFromConfig reports how the rules in a security configuration file treat sample content,
+// without loading them into the running security system
+func CheckRulesFromConfig(configData []byte, content string, source SourceContext, ruleName string) (*RuleCheckReport, error) {
+	engine, err := NewRuleEngineFromConfig(configData)
+	if err != nil {
+		return nil, err
+	}
+	return engine.CheckRules(content, source, ruleName)
+}
+
+// NewRuleEngineFromConfig creates a rule engine for the rules in a security configuration file. The
+// engine is not watched for changes or attached to the running security system.
+func NewRuleEngineFromConfig(configData []byte) (*YAMLRuleEngine, error) {
+	rules, err := ValidateSecurityConfig(configData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security configuration: %w", err)
+	}
+
+	engine := &YAMLRuleEngine{
+		rules:     rules,
+		compiled:  make(map[string]PatternMatcher),
+		rulesPath: ":memory:",
+		mutex:     sync.RWMutex{},
+	}
+	if err := engine.compilePatterns(rules); err != nil {
+		return nil, fmt.Errorf("failed to compile patterns: %w", err)
+	}
+	return engine, nil
+}
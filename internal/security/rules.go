@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -73,6 +74,11 @@ func (r *YAMLRuleEngine) ensureRulesFile() error {
 			return fmt.Errorf("failed to create default rules: %w", err)
 		}
 
+		// Record the default the file started from so later updates can be merged into it
+		if err := writeBaseConfig(r.rulesPath, defaultRules); err != nil {
+			logrus.WithError(err).Warn("Failed to record default security configuration")
+		}
+
 		// Only log if not in stdio mode (stdio mode sets ErrorLevel to prevent MCP protocol pollution)
 		if logrus.GetLevel() >= logrus.InfoLevel {
 			logrus.Infof("Created default security rules at %s", r.rulesPath)
@@ -344,6 +350,10 @@ func (r *YAMLRuleEngine) validateRules(rules *SecurityRules) error {
 		switch rule.Action {
 		case "allow", "warn", "warn_high", "block", "notify", "ignore":
 			// Valid actions
+		case RuleActionRedact:
+			if err := validateRedactionRule(name, rule); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("rule %s has invalid action: %s", name, rule.Action)
 		}
@@ -356,6 +366,24 @@ func (r *YAMLRuleEngine) validateRules(rules *SecurityRules) error {
 		}
 	}
 
+	// Validate redaction rule names so a typo does not silently leave a rule enabled
+	redactionNames := redactionRuleNames(rules)
+	for _, name := range rules.Redaction.DisabledRules {
+		if !slices.Contains(redactionNames, name) {
+			return fmt.Errorf("redaction.disabled_rules has unknown rule %s (valid rules: %s)", name, strings.Join(redactionNames, ", "))
+		}
+	}
+
+	switch rules.Unicode.Mode {
+	case "", UnicodeModeOff, UnicodeModeFlag, UnicodeModeStrip:
+	default:
+		return fmt.Errorf("unicode_sanitisation.mode must be %s, %s or %s, got %q", UnicodeModeOff, UnicodeModeFlag, UnicodeModeStrip, rules.Unicode.Mode)
+	}
+
+	if err := validateInjectionConfig(rules.Injection); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -429,6 +457,7 @@ func (r *YAMLRuleEngine) compilePatterns(rules *SecurityRules) error {
 			r.compiled[key] = matcher
 		}
 	}
+	r.redaction = compileRedactionRules(rules, r.compiled)
 
 	return nil
 }
@@ -584,8 +613,11 @@ type RuleInfo struct {
 func (r *YAMLRuleEngine) sortRulesByPriority() []RuleInfo {
 	var ruleInfos []RuleInfo
 
-	// Convert map to slice and assign priorities
+	// Convert map to slice and assign priorities. Redaction rules apply to tool output, not content checks.
 	for ruleName, rule := range r.rules.Rules {
+		if rule.Action == RuleActionRedact {
+			continue
+		}
 		priority := r.getActionPriority(rule.Action)
 		ruleInfos = append(ruleInfos, RuleInfo{
 			Name:     ruleName,
@@ -746,25 +778,7 @@ func (r *YAMLRuleEngine) evaluateRuleWithConfig(ruleName string, rule Rule, cont
 		logic = "any"
 	}
 
-	// Check if base64 decode_base64 is enabled for this rule
-	decodeAndScan := false
-	if rule.Options != nil {
-		if val, exists := rule.Options["decode_base64"]; exists {
-			if boolVal, ok := val.(bool); ok {
-				decodeAndScan = boolVal
-			}
-		}
-	}
-
-	// Process content for pattern matching
-	contentToMatch := content
-	if decodeAndScan && config != nil && config.EnableBase64Scanning {
-		// Detect and decode base64 content, append to original content
-		decodedContent := r.detectAndDecodeBase64ContentWithConfig(content, config)
-		if decodedContent != "" {
-			contentToMatch = content + "\n" + decodedContent
-		}
-	}
+	contentToMatch := r.ruleContent(rule, content, config)
 
 	matchCount := 0
 	for i := range rule.Patterns {
@@ -786,6 +800,29 @@ func (r *YAMLRuleEngine) evaluateRuleWithConfig(ruleName string, rule Rule, cont
 	return logic == "all" && matchCount == len(rule.Patterns)
 }
 
+// ruleContent returns the content a rule's patterns are matched against, with decoded base64
+// appended when the rule sets decode_base64 and base64 scanning is enabled
+func (r *YAMLRuleEngine) ruleContent(rule Rule, content string, config *SecurityConfig) string {
+	// Check if base64 decode_base64 is enabled for this rule
+	decodeAndScan := false
+	if rule.Options != nil {
+		if val, exists := rule.Options["decode_base64"]; exists {
+			if boolVal, ok := val.(bool); ok {
+				decodeAndScan = boolVal
+			}
+		}
+	}
+
+	if decodeAndScan && config != nil && config.EnableBase64Scanning {
+		// Detect and decode base64 content, append to original content
+		decodedContent := r.detectAndDecodeBase64ContentWithConfig(content, config)
+		if decodedContent != "" {
+			return content + "\n" + decodedContent
+		}
+	}
+	return content
+}
+
 // isSourceExcepted checks if source is in exception list
 func (r *YAMLRuleEngine) isSourceExcepted(source SourceContext, exceptions []string) bool {
 	for _, exception := range exceptions {
@@ -11,6 +11,9 @@ type SecurityRules struct {
 	AccessControl  AccessControl   `yaml:"access_control"`
 	Rules          map[string]Rule `yaml:"rules"`
 	AdvancedRules  map[string]Rule `yaml:"advanced_rules,omitempty"`
+	Redaction      RedactionConfig `yaml:"redaction,omitempty"`
+	Unicode        UnicodeConfig   `yaml:"unicode_sanitisation,omitempty"`
+	Injection      InjectionConfig `yaml:"prompt_injection,omitempty"`
 }
 
 // RuleMetadata contains rule file metadata
@@ -40,17 +43,51 @@ type Settings struct {
 	MaxBase64DecodedSize  int     `yaml:"max_base64_decoded_size"` // Maximum size of decoded base64 content (KB)
 }
 
+// RedactionConfig controls replacing secrets in tool output with placeholders
+type RedactionConfig struct {
+	Enabled          *bool    `yaml:"enabled,omitempty"`           // Defaults to true when omitted
+	DisabledRules    []string `yaml:"disabled_rules,omitempty"`    // Built-in rules to skip, e.g. jwt
+	EntropyThreshold float64  `yaml:"entropy_threshold,omitempty"` // Minimum entropy for secret_assignment matches
+}
+
+// IsEnabled reports whether redaction is on, which it is unless explicitly disabled
+func (c RedactionConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// UnicodeConfig controls sanitising invisible and lookalike characters in tool output
+type UnicodeConfig struct {
+	Mode  string   `yaml:"mode,omitempty"`  // off (default), flag or strip
+	Tools []string `yaml:"tools,omitempty"` // Tools to sanitise, all tools when empty
+}
+
+// InjectionConfig controls the prompt injection analyser stage
+type InjectionConfig struct {
+	Enabled         *bool             `yaml:"enabled,omitempty"`          // Defaults to true when omitted
+	Action          string            `yaml:"action,omitempty"`           // block, warn_high, warn (default) or notify
+	Threshold       float64           `yaml:"threshold,omitempty"`        // Score that triggers the action for medium trust sources
+	DisabledSignals []string          `yaml:"disabled_signals,omitempty"` // Built-in signals to skip, e.g. tool_lure
+	SourceTrust     map[string]string `yaml:"source_trust,omitempty"`     // Domain or tool name to trust level: high, medium or low
+}
+
+// IsEnabled reports whether the prompt injection stage is on, which it is unless explicitly disabled
+func (c InjectionConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
 // AccessControl defines file and domain access restrictions
 type AccessControl struct {
-	DenyFiles   []string `yaml:"deny_files"`
-	DenyDomains []string `yaml:"deny_domains"`
+	DenyFiles            []string `yaml:"deny_files"`
+	DenyDomains          []string `yaml:"deny_domains"`
+	AllowlistOnly        bool     `yaml:"allowlist_only,omitempty"`         // Only allow outbound requests to trusted_domains
+	BlockPrivateNetworks bool     `yaml:"block_private_networks,omitempty"` // Block outbound requests to private, loopback and link-local addresses
 }
 
 // Rule represents a security rule with patterns and actions
 type Rule struct {
 	Description string          `yaml:"description"`
 	Patterns    []PatternConfig `yaml:"patterns"`
-	Action      string          `yaml:"action"` // "block", "warn_high", "warn", "notify", "ignore"
+	Action      string          `yaml:"action"` // "block", "warn_high", "warn", "notify", "ignore", "redact"
 	Severity    string          `yaml:"severity,omitempty"`
 	Exceptions  []string        `yaml:"exceptions,omitempty"`
 	Logic       string          `yaml:"logic,omitempty"` // "any" or "all"
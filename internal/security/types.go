@@ -1,6 +1,7 @@
 package security
 
 import (
+	"container/list"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,6 +46,7 @@ type SourceTrust struct {
 type YAMLRuleEngine struct {
 	rules        *SecurityRules
 	compiled     map[string]PatternMatcher
+	redaction    []redactionRule
 	rulesPath    string
 	lastModified time.Time
 	mutex        sync.RWMutex
@@ -64,21 +66,39 @@ type OverrideManager struct {
 	overridesPath string
 	logPath       string
 	overrides     *OverrideConfig
+	onChange      func() // Called after overrides change, e.g. to invalidate cached results
 	mutex         sync.RWMutex
 }
 
-// Cache provides in-memory security analysis caching
+// Cache provides in-memory security analysis caching, evicting the least recently used entry when full
 type Cache struct {
-	data    sync.Map
-	maxSize int
-	maxAge  time.Duration
-	size    atomic.Int64
+	mutex      sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // Most recently used at the front
+	maxSize    int
+	maxAge     time.Duration
+	generation uint64 // Incremented when the cache is cleared, so older results are not stored
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
 }
 
 // CacheEntry represents a cached security analysis result
 type CacheEntry struct {
-	Result  *SecurityResult
-	Created time.Time
+	Key        string
+	Result     *SecurityResult
+	Created    time.Time
+	Generation uint64
+}
+
+// CacheStats contains security cache counters
+type CacheStats struct {
+	Size      int   `json:"size"`
+	MaxSize   int   `json:"max_size"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
 }
 
 // SecurityResult contains the outcome of security analysis
@@ -164,6 +184,8 @@ type SecurityConfig struct {
 	SuspiciousDomains      []string      `json:"suspicious_domains"`
 	DenyFiles              []string      `json:"deny_files"`
 	DenyDomains            []string      `json:"deny_domains"`
+	AllowlistOnly          bool          `json:"allowlist_only"`
+	BlockPrivateNetworks   bool          `json:"block_private_networks"`
 }
 
 // PatternMatcher interface for different pattern matching strategies
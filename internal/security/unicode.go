@@ -0,0 +1,266 @@
+package security
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// Unicode sanitisation modes
+const (
+	UnicodeModeOff   = "off"   // Leave content untouched
+	UnicodeModeFlag  = "flag"  // Report suspicious characters without altering content
+	UnicodeModeStrip = "strip" // Remove invisible characters and replace lookalikes
+)
+
+// maxReportedWords limits how many altered words are listed in a report
+const maxReportedWords = 10
+
+// confusables maps Cyrillic and Greek letters to the Latin letters they imitate.
+// They are only replaced inside words that also contain Latin letters, so ordinary Russian
+// or Greek text is left alone.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'к': 'k', 'о': 'o', 'р': 'p', 'с': 'c',
+	'ѕ': 's', 'у': 'y', 'х': 'x', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'І': 'I', 'Ј': 'J', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Ѕ': 'S', 'Т': 'T', 'Х': 'X', 'У': 'Y', 'Ԁ': 'D', 'Ԛ': 'Q', 'Ԝ': 'W',
+	// Greek
+	'α': 'a', 'ο': 'o', 'ρ': 'p', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'υ': 'u',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M', 'Ν': 'N',
+	'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// UnicodeReport describes the characters found or altered by Unicode sanitisation
+type UnicodeReport struct {
+	ZeroWidth   int              // Zero-width and other invisible formatting characters
+	Bidi        int              // Bidirectional text controls
+	Tags        int              // Unicode tag characters, which can smuggle hidden ASCII
+	Confusables []ConfusableWord // Words mixing Latin letters with lookalikes
+}
+
+// ConfusableWord is a word containing lookalike characters and its Latin equivalent
+type ConfusableWord struct {
+	Original    string
+	Replacement string
+}
+
+// Empty reports whether nothing suspicious was found
+func (r UnicodeReport) Empty() bool {
+	return r.ZeroWidth == 0 && r.Bidi == 0 && r.Tags == 0 && len(r.Confusables) == 0
+}
+
+func (r *UnicodeReport) add(other UnicodeReport) {
+	r.ZeroWidth += other.ZeroWidth
+	r.Bidi += other.Bidi
+	r.Tags += other.Tags
+	for _, word := range other.Confusables {
+		if !slices.Contains(r.Confusables, word) {
+			r.Confusables = append(r.Confusables, word)
+		}
+	}
+}
+
+// Summary describes the report for the model. Original words are escaped so the lookalike
+// characters are visible rather than reproduced.
+func (r UnicodeReport) Summary(mode string) string {
+	var parts []string
+	for _, count := range []struct {
+		n    int
+		name string
+	}{
+		{r.ZeroWidth, "zero-width character"},
+		{r.Bidi, "bidi control character"},
+		{r.Tags, "tag character"},
+	} {
+		if count.n == 1 {
+			parts = append(parts, "1 "+count.name)
+		} else if count.n > 1 {
+			parts = append(parts, fmt.Sprintf("%d %ss", count.n, count.name))
+		}
+	}
+
+	var sb strings.Builder
+	if mode == UnicodeModeStrip {
+		sb.WriteString("[Unicode sanitisation altered this content")
+		if len(parts) > 0 {
+			sb.WriteString(": removed " + strings.Join(parts, ", "))
+		}
+	} else {
+		sb.WriteString("[Unicode sanitisation found suspicious characters (content not altered)")
+		if len(parts) > 0 {
+			sb.WriteString(": " + strings.Join(parts, ", "))
+		}
+	}
+
+	if len(r.Confusables) > 0 {
+		if len(parts) > 0 {
+			sb.WriteString(";")
+		} else {
+			sb.WriteString(":")
+		}
+		fmt.Fprintf(&sb, " lookalike characters in %d word", len(r.Confusables))
+		if len(r.Confusables) > 1 {
+			sb.WriteString("s")
+		}
+		for i, word := range r.Confusables[:min(len(r.Confusables), maxReportedWords)] {
+			if i == 0 {
+				sb.WriteString(" - ")
+			} else {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(strconv.QuoteToASCII(word.Original) + " -> " + strconv.Quote(word.Replacement))
+		}
+		if len(r.Confusables) > maxReportedWords {
+			fmt.Fprintf(&sb, " and %d more", len(r.Confusables)-maxReportedWords)
+		}
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// invisibleCategory classifies a rune as a zero-width, bidi or tag character, returning "" otherwise
+func invisibleCategory(r rune) string {
+	switch {
+	case r == '\u200B', r == '\u200C', r == '\u200D', r == '\u2060', r == '\uFEFF', r == '\u180E', r == '\u00AD',
+		r >= '\u2061' && r <= '\u2064':
+		return "zero_width"
+	case r == '\u061C', r == '\u200E', r == '\u200F', r >= '\u202A' && r <= '\u202E', r >= '\u2066' && r <= '\u2069':
+		return "bidi"
+	case r >= 0xE0000 && r <= 0xE007F:
+		return "tag"
+	}
+	return ""
+}
+
+// isEmoji reports whether a rune can take part in an emoji sequence joined by U+200D
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 || unicode.Is(unicode.So, r) || r == '\uFE0F'
+}
+
+// SanitiseUnicode finds zero-width characters, bidi controls, tag characters and words that
+// mix Latin letters with lookalikes. With strip set it returns the text with invisible characters
+// removed and lookalikes replaced, otherwise the text is returned unchanged.
+func SanitiseUnicode(text string, strip bool) (string, UnicodeReport) {
+	var report UnicodeReport
+	runes := []rune(text)
+	cleaned := make([]rune, 0, len(runes))
+
+	for i, r := range runes {
+		category := invisibleCategory(r)
+		// Keep joiners inside emoji sequences and tag characters in subdivision flags
+		if r == '\u200D' && i > 0 && i+1 < len(runes) && isEmoji(runes[i-1]) && isEmoji(runes[i+1]) {
+			category = ""
+		}
+		if category == "tag" && len(cleaned) > 0 && (cleaned[len(cleaned)-1] == 0x1F3F4 || invisibleCategory(cleaned[len(cleaned)-1]) == "tag") {
+			category = ""
+		}
+
+		switch category {
+		case "zero_width":
+			report.ZeroWidth++
+		case "bidi":
+			report.Bidi++
+		case "tag":
+			report.Tags++
+		default:
+			cleaned = append(cleaned, r)
+		}
+	}
+
+	// Lookalikes are checked after invisible characters are removed so they cannot split words
+	for start := 0; start < len(cleaned); {
+		if !unicode.IsLetter(cleaned[start]) && !unicode.IsDigit(cleaned[start]) {
+			start++
+			continue
+		}
+		end := start
+		hasLatin, hasConfusable := false, false
+		for end < len(cleaned) && (unicode.IsLetter(cleaned[end]) || unicode.IsDigit(cleaned[end])) {
+			if cleaned[end] < unicode.MaxASCII && unicode.IsLetter(cleaned[end]) {
+				hasLatin = true
+			}
+			if _, ok := confusables[cleaned[end]]; ok {
+				hasConfusable = true
+			}
+			end++
+		}
+
+		if hasLatin && hasConfusable {
+			original := string(cleaned[start:end])
+			for i := start; i < end; i++ {
+				if latin, ok := confusables[cleaned[i]]; ok {
+					cleaned[i] = latin
+				}
+			}
+			word := ConfusableWord{Original: original, Replacement: string(cleaned[start:end])}
+			if !slices.Contains(report.Confusables, word) {
+				report.Confusables = append(report.Confusables, word)
+			}
+		}
+		start = end
+	}
+
+	if !strip || report.Empty() {
+		return text, report
+	}
+	return string(cleaned), report
+}
+
+// unicodeConfig returns the configured Unicode sanitisation settings
+func (m *SecurityManager) unicodeConfig() UnicodeConfig {
+	if !m.IsEnabled() || m.ruleEngine == nil {
+		return UnicodeConfig{}
+	}
+	m.ruleEngine.mutex.RLock()
+	defer m.ruleEngine.mutex.RUnlock()
+	if m.ruleEngine.rules == nil {
+		return UnicodeConfig{}
+	}
+	return m.ruleEngine.rules.Unicode
+}
+
+// SanitiseToolResult applies the configured Unicode sanitisation mode to a tool result in place.
+// When anything is found a note describing it is appended to the result's content.
+func SanitiseToolResult(toolName string, result *mcp.CallToolResult) UnicodeReport {
+	globalManagerMutex.RLock()
+	manager := GlobalSecurityManager
+	globalManagerMutex.RUnlock()
+
+	var report UnicodeReport
+	if manager == nil || result == nil {
+		return report
+	}
+
+	config := manager.unicodeConfig()
+	if config.Mode != UnicodeModeFlag && config.Mode != UnicodeModeStrip {
+		return report
+	}
+	if len(config.Tools) > 0 && !slices.Contains(config.Tools, toolName) {
+		return report
+	}
+
+	transformToolResult(result, func(text string) string {
+		sanitised, found := SanitiseUnicode(text, config.Mode == UnicodeModeStrip)
+		report.add(found)
+		return sanitised
+	})
+
+	if !report.Empty() {
+		result.Content = append(result.Content, mcp.NewTextContent(report.Summary(config.Mode)))
+		logrus.WithFields(logrus.Fields{
+			"tool":        toolName,
+			"mode":        config.Mode,
+			"zero_width":  report.ZeroWidth,
+			"bidi":        report.Bidi,
+			"tags":        report.Tags,
+			"confusables": len(report.Confusables),
+		}).Debug("Unicode sanitisation found suspicious characters in tool output")
+	}
+	return report
+}
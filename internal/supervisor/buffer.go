@@ -0,0 +1,53 @@
+package supervisor
+
+import "sync"
+
+// CappedBuffer keeps the first, or with keepTail the last, limit bytes written to it while counting
+// all of them, so a process is never blocked writing output that will not be kept
+type CappedBuffer struct {
+	mu       sync.Mutex
+	buf      []byte
+	limit    int // Negative keeps everything
+	keepTail bool
+	total    int64
+}
+
+// NewCappedBuffer returns a buffer keeping limit bytes, or everything when limit is negative
+func NewCappedBuffer(limit int, keepTail bool) *CappedBuffer {
+	return &CappedBuffer{limit: limit, keepTail: keepTail}
+}
+
+// Write records p within the limit
+func (b *CappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total += int64(len(p))
+	switch {
+	case b.limit < 0:
+		b.buf = append(b.buf, p...)
+	case b.keepTail:
+		b.buf = append(b.buf, p...)
+		if len(b.buf) > b.limit {
+			b.buf = b.buf[len(b.buf)-b.limit:]
+		}
+	default:
+		if remaining := b.limit - len(b.buf); remaining > 0 {
+			b.buf = append(b.buf, p[:min(len(p), remaining)]...)
+		}
+	}
+	return len(p), nil
+}
+
+// String returns the recorded output
+func (b *CappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+// Total returns the number of bytes written, including those not kept
+func (b *CappedBuffer) Total() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total
+}
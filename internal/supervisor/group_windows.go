@@ -0,0 +1,18 @@
+//go:build windows
+
+package supervisor
+
+import "os/exec"
+
+// terminate kills the process, as Windows has no signal asking a process to exit
+func terminate(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
+
+// killGroup kills the process. Windows cannot signal a whole process group, so processes it
+// started are left running.
+func killGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
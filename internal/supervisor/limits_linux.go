@@ -0,0 +1,22 @@
+package supervisor
+
+import "golang.org/x/sys/unix"
+
+// applyLimits sets a started process's resource limits with prlimit, before it has had time to
+// start any processes of its own
+func applyLimits(pid int, limits Limits) error {
+	for resource, value := range map[int]int64{
+		unix.RLIMIT_AS:     limits.MemoryBytes,
+		unix.RLIMIT_CPU:    limits.CPUSeconds,
+		unix.RLIMIT_NOFILE: limits.OpenFiles,
+	} {
+		if value <= 0 {
+			continue
+		}
+		limit := &unix.Rlimit{Cur: uint64(value), Max: uint64(value)}
+		if err := unix.Prlimit(pid, resource, limit, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
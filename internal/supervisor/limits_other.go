@@ -0,0 +1,8 @@
+//go:build !linux
+
+package supervisor
+
+// applyLimits does nothing, as other platforms cannot set another process's resource limits
+func applyLimits(int, Limits) error {
+	return nil
+}
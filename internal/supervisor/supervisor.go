@@ -0,0 +1,427 @@
+// Package supervisor runs the programs tools depend on, such as the Python document converter,
+// language servers and plugins, so that each is handled the same way: its output is captured up
+// to a cap, its resource limits are applied, it and every process it started are stopped and
+// waited for, and a long-running process that crashes can be restarted.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/procgroup"
+)
+
+const (
+	// DefaultMaxStdout is how much of a command's output Run keeps when Spec.MaxStdout is 0
+	DefaultMaxStdout = 10 << 20
+
+	// DefaultMaxStderr is how much of the end of a process's error output is kept when
+	// Spec.MaxStderr is 0
+	DefaultMaxStderr = 64 << 10
+
+	// maxBackoff caps the doubling wait between restarts
+	maxBackoff = 30 * time.Second
+)
+
+// Spec describes a program to run
+type Spec struct {
+	Name string // Names the process in errors and Snapshot
+	Path string
+	Args []string
+	Dir  string
+	Env  []string // nil inherits the server's environment
+
+	// Stdin is given to the command by Run. Processes from Start are written to with Stdin instead.
+	Stdin io.Reader
+
+	// MaxStdout is how much output Run keeps from the start, and MaxStderr how much error output is
+	// kept from the end. 0 uses the defaults, and a negative value keeps everything.
+	MaxStdout int
+	MaxStderr int
+
+	Limits  Limits
+	Restart RestartPolicy // Used by Start only
+}
+
+// Limits are resource limits applied to a process and inherited by the processes it starts. They
+// are applied on Linux and ignored elsewhere. Zero values are not limited.
+type Limits struct {
+	MemoryBytes int64 // Address space (RLIMIT_AS)
+	CPUSeconds  int64 // CPU time (RLIMIT_CPU)
+	OpenFiles   int64 // Open file descriptors (RLIMIT_NOFILE)
+}
+
+// RestartPolicy restarts a process from Start that exits without being stopped
+type RestartPolicy struct {
+	MaxRestarts int           // 0 never restarts
+	Backoff     time.Duration // Wait before the first restart, doubled for each one after
+	// OnRestart is called with the process once it has restarted, to reconnect to its new pipes
+	OnRestart func(*Process)
+}
+
+// Result is the outcome of a command run with Run
+type Result struct {
+	Stdout      string
+	Stderr      string
+	StdoutBytes int64 // All output written, including any not kept
+	StderrBytes int64
+	ExitCode    int // -1 if the command did not exit normally
+	Duration    time.Duration
+}
+
+// StdoutTruncated reports whether output beyond MaxStdout was dropped
+func (r *Result) StdoutTruncated() bool {
+	return r.StdoutBytes > int64(len(r.Stdout))
+}
+
+// Run runs a command to completion and returns its output. Cancelling ctx kills the command and
+// every process it started, as does the command exiting, so nothing it started is left behind. The
+// result is returned with any error, including *exec.ExitError, so callers can report its output.
+func Run(ctx context.Context, spec Spec) (*Result, error) {
+	cmd := spec.command(ctx)
+	cmd.Stdin = spec.Stdin
+	stdout := NewCappedBuffer(bufferLimit(spec.MaxStdout, DefaultMaxStdout), false)
+	stderr := NewCappedBuffer(bufferLimit(spec.MaxStderr, DefaultMaxStderr), true)
+	// The output pipes are made here so that Wait returns once the command exits, rather than
+	// waiting for processes it left behind to close them
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+		return nil, err
+	}
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	var copying sync.WaitGroup
+	copying.Go(func() { copyAndClose(stdout, stdoutReader) })
+	copying.Go(func() { copyAndClose(stderr, stderrReader) })
+
+	start := time.Now()
+	err = cmd.Start()
+	_ = stdoutWriter.Close()
+	_ = stderrWriter.Close()
+	if err == nil {
+		if limitErr := applyLimits(cmd.Process.Pid, spec.Limits); limitErr != nil {
+			killGroup(cmd)
+			_ = cmd.Wait()
+			copying.Wait()
+			return nil, fmt.Errorf("failed to apply resource limits to %s: %w", spec.Name, limitErr)
+		}
+		err = cmd.Wait()
+		killGroup(cmd)
+	}
+	// Once the group is killed its processes no longer hold the pipes open, except on Windows, where
+	// processes the command started outlive it, so the output is only waited for so long
+	copied := make(chan struct{})
+	go func() {
+		copying.Wait()
+		close(copied)
+	}()
+	select {
+	case <-copied:
+	case <-time.After(procgroup.WaitDelay):
+		_ = stdoutReader.Close()
+		_ = stderrReader.Close()
+		<-copied
+	}
+
+	result := &Result{
+		Stdout:      stdout.String(),
+		Stderr:      stderr.String(),
+		StdoutBytes: stdout.Total(),
+		StderrBytes: stderr.Total(),
+		ExitCode:    -1,
+		Duration:    time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	return result, err
+}
+
+// copyAndClose copies a pipe's output to a buffer until every writer has closed it
+func copyAndClose(dst io.Writer, src *os.File) {
+	_, _ = io.Copy(dst, src)
+	_ = src.Close()
+}
+
+// command builds the exec.Cmd for a spec, in its own process group
+func (spec Spec) command(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, spec.Path, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Env = spec.Env
+	procgroup.Set(cmd)
+	return cmd
+}
+
+// bufferLimit returns the limit for a Spec output field: the default for 0, and no limit for
+// negative values
+func bufferLimit(value, def int) int {
+	switch {
+	case value == 0:
+		return def
+	case value < 0:
+		return -1
+	}
+	return value
+}
+
+// Process is a long-running process started with Start
+type Process struct {
+	spec Spec
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stdout   io.ReadCloser
+	stderr   *CappedBuffer
+	started  time.Time
+	restarts int
+	stopped  bool
+	err      error
+	stop     chan struct{} // Closed by Stop
+	done     chan struct{} // Closed when the process has exited and will not be restarted
+	exited   chan struct{} // Closed when the current instance has exited and been waited for
+}
+
+// errStopped is returned when a restart is abandoned because the process was stopped
+var errStopped = errors.New("process was stopped")
+
+// Info describes a running process, for diagnostics
+type Info struct {
+	Name     string    `json:"name"`
+	PID      int       `json:"pid"`
+	Started  time.Time `json:"started"`
+	Restarts int       `json:"restarts"`
+}
+
+var (
+	processesMu sync.Mutex
+	processes   = map[*Process]struct{}{}
+)
+
+// Start starts a long-running process with pipes to its standard input and output. It runs until
+// Stop is called, and is restarted following spec.Restart if it exits before then. Processes still
+// running at shutdown are stopped by StopAll.
+func Start(spec Spec) (*Process, error) {
+	p := &Process{spec: spec, stop: make(chan struct{}), done: make(chan struct{})}
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+	processesMu.Lock()
+	processes[p] = struct{}{}
+	processesMu.Unlock()
+	go p.supervise()
+	return p, nil
+}
+
+// start starts a new instance of the process. The caller must not hold p.mu.
+func (p *Process) start() error {
+	// The process lives until it is stopped, not for the call that started it
+	cmd := p.spec.command(context.Background())
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	// The output pipe is made here rather than with StdoutPipe, as Wait closes that pipe when the
+	// process exits, losing output not yet read
+	stdout, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = stdoutWriter
+	stderr := NewCappedBuffer(bufferLimit(p.spec.MaxStderr, DefaultMaxStderr), true)
+	cmd.Stderr = stderr
+	err = cmd.Start()
+	_ = stdoutWriter.Close()
+	if err != nil {
+		_ = stdout.Close()
+		return fmt.Errorf("failed to start %s: %w", p.spec.Name, err)
+	}
+	if err := applyLimits(cmd.Process.Pid, p.spec.Limits); err != nil {
+		killGroup(cmd)
+		_ = cmd.Wait()
+		_ = stdout.Close()
+		return fmt.Errorf("failed to apply resource limits to %s: %w", p.spec.Name, err)
+	}
+
+	p.mu.Lock()
+	if p.stopped {
+		// Stopped while restarting
+		p.mu.Unlock()
+		killGroup(cmd)
+		_ = cmd.Wait()
+		_ = stdout.Close()
+		return errStopped
+	}
+	if p.cmd != nil {
+		p.restarts++
+	}
+	p.cmd, p.stdin, p.stdout, p.stderr = cmd, stdin, stdout, stderr
+	p.started = time.Now()
+	p.exited = make(chan struct{})
+	p.mu.Unlock()
+	return nil
+}
+
+// supervise waits for each instance of the process to exit, and restarts it if the policy allows
+func (p *Process) supervise() {
+	defer func() {
+		processesMu.Lock()
+		delete(processes, p)
+		processesMu.Unlock()
+		close(p.done)
+	}()
+
+	backoff := p.spec.Restart.Backoff
+	for {
+		p.mu.Lock()
+		cmd, exited := p.cmd, p.exited
+		p.mu.Unlock()
+
+		err := cmd.Wait()
+		killGroup(cmd)
+		close(exited)
+
+		p.mu.Lock()
+		p.err = err
+		restart := !p.stopped && p.restarts < p.spec.Restart.MaxRestarts
+		p.mu.Unlock()
+		if !restart {
+			return
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+		if err := p.start(); err != nil {
+			if !errors.Is(err, errStopped) {
+				p.mu.Lock()
+				p.err = err
+				p.mu.Unlock()
+			}
+			return
+		}
+		if p.spec.Restart.OnRestart != nil {
+			p.spec.Restart.OnRestart(p)
+		}
+	}
+}
+
+// Stdin returns the current instance's standard input
+func (p *Process) Stdin() io.WriteCloser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stdin
+}
+
+// Stdout returns the current instance's standard output
+func (p *Process) Stdout() io.ReadCloser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stdout
+}
+
+// Stderr returns the end of the current instance's error output
+func (p *Process) Stderr() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stderr.String()
+}
+
+// PID returns the current instance's process ID
+func (p *Process) PID() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cmd.Process.Pid
+}
+
+// Restarts returns how many times the process has been restarted
+func (p *Process) Restarts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.restarts
+}
+
+// Done is closed once the process has exited and will not be restarted
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns how the process last exited, once Done is closed
+func (p *Process) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Stop asks the process to exit, and kills it and every process it started if it has not exited
+// after grace. It returns once the process has been waited for.
+func (p *Process) Stop(grace time.Duration) {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.stop)
+	}
+	cmd, exited := p.cmd, p.exited
+	p.mu.Unlock()
+
+	select {
+	case <-exited:
+	default:
+		terminate(cmd)
+		select {
+		case <-exited:
+		case <-time.After(grace):
+			killGroup(cmd)
+		}
+	}
+	<-p.done
+}
+
+// StopAll stops every process from Start that is still running, giving each grace to exit
+func StopAll(grace time.Duration) {
+	var wg sync.WaitGroup
+	for _, p := range running() {
+		wg.Go(func() { p.Stop(grace) })
+	}
+	wg.Wait()
+}
+
+// Snapshot describes the processes from Start that are running, sorted by name
+func Snapshot() []Info {
+	var infos []Info
+	for _, p := range running() {
+		p.mu.Lock()
+		infos = append(infos, Info{Name: p.spec.Name, PID: p.cmd.Process.Pid, Started: p.started, Restarts: p.restarts})
+		p.mu.Unlock()
+	}
+	slices.SortFunc(infos, func(a, b Info) int { return strings.Compare(a.Name, b.Name) })
+	return infos
+}
+
+// running returns the processes from Start that have not finished
+func running() []*Process {
+	processesMu.Lock()
+	defer processesMu.Unlock()
+	var list []*Process
+	for p := range processes {
+		list = append(list, p)
+	}
+	return list
+}
@@ -2,11 +2,13 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/memusage"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -44,7 +46,8 @@ var (
 	sessionToolCountHist metric.Int64Histogram
 
 	// Phase 3: Cache Metrics
-	cacheOpsCounter metric.Int64Counter
+	cacheOpsCounter       metric.Int64Counter
+	cacheEvictionsCounter metric.Int64Counter
 	// cacheHitRatioGauge metric.Float64Gauge // Reserved for future implementation
 
 	// Phase 3: Security Metrics (opt-in)
@@ -70,8 +73,9 @@ func InitMetrics(logger *logrus.Logger) (func() error, error) {
 			"tool":     true,
 			"session":  true,
 			"security": true,
+			"memory":   true,
 		}
-		logger.Debug("OTEL Metrics: Using default groups (tool, session)")
+		logger.Debug("OTEL Metrics: Using default groups (tool, session, security, memory)")
 	}
 
 	// Check if metrics are enabled (same endpoint as tracing)
@@ -276,6 +280,16 @@ func initMetricInstruments(logger *logrus.Logger) error {
 			return err
 		}
 
+		cacheEvictionsCounter, err = meter.Int64Counter(
+			"cache.evictions",
+			metric.WithDescription("Cache entries evicted for capacity or age"),
+			metric.WithUnit("{entry}"),
+		)
+		if err != nil {
+			logger.WithError(err).Error("OTEL Metrics: Failed to create cache.evictions counter")
+			return err
+		}
+
 		// Note: cache.hit_ratio gauge would be implemented here
 		// For now, hit ratio can be calculated from cache.operations counter in queries
 
@@ -318,9 +332,72 @@ func initMetricInstruments(logger *logrus.Logger) error {
 		logger.Debug("OTEL Metrics: Security metrics initialised")
 	}
 
+	// Memory Metrics
+	if enabledMetricGroups["memory"] {
+		if err = initMemoryInstruments(meter); err != nil {
+			logger.WithError(err).Error("OTEL Metrics: Failed to create memory instruments")
+			return err
+		}
+
+		logger.Debug("OTEL Metrics: Memory metrics initialised")
+	}
+
 	return nil
 }
 
+// initMemoryInstruments creates gauges of memory use and counters of throttled and over budget
+// calls, all read from a single memusage sample each time metrics are collected
+func initMemoryInstruments(meter metric.Meter) error {
+	heap, err := meter.Int64ObservableGauge("mcp.memory.heap",
+		metric.WithDescription("Heap objects, live or not yet collected"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	inUse, err := meter.Int64ObservableGauge("mcp.memory.in_use",
+		metric.WithDescription("Memory counted against the Go memory limit"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	limit, err := meter.Int64ObservableGauge("mcp.memory.limit",
+		metric.WithDescription("Go memory limit (GOMEMLIMIT)"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	rejected, err := meter.Int64ObservableCounter("mcp.memory.rejected_calls",
+		metric.WithDescription("Memory intensive calls refused near the memory limit"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return err
+	}
+	overBudget, err := meter.Int64ObservableCounter("mcp.memory.over_budget_calls",
+		metric.WithDescription("Tool calls that allocated more than the per-call memory budget"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		stats := memusage.Read()
+		observer.ObserveInt64(heap, int64(stats.HeapBytes))
+		observer.ObserveInt64(inUse, int64(stats.InUseBytes))
+		if stats.LimitBytes > 0 {
+			observer.ObserveInt64(limit, stats.LimitBytes)
+		}
+		observer.ObserveInt64(rejected, stats.RejectedCalls)
+		observer.ObserveInt64(overBudget, stats.OverBudgetCalls)
+		return nil
+	}, heap, inUse, limit, rejected, overBudget)
+	return err
+}
+
 // IsMetricsEnabled returns true if metrics collection is enabled
 func IsMetricsEnabled() bool {
 	metricsMutex.RLock()
@@ -392,6 +469,10 @@ func CategoriseToolError(err error) string {
 		return ""
 	}
 
+	if errors.Is(err, memusage.ErrLowMemory) {
+		return "memory"
+	}
+
 	errStr := err.Error()
 
 	// Network errors
@@ -498,6 +579,22 @@ func RecordCacheOperation(ctx context.Context, toolName string, operation string
 	}
 }
 
+// RecordCacheEviction records a cache entry being evicted, with the reason such as capacity or expired
+func RecordCacheEviction(ctx context.Context, cacheName string, reason string) {
+	if !IsMetricsEnabled() || !isMetricGroupEnabled("cache") {
+		return
+	}
+
+	if cacheEvictionsCounter != nil {
+		cacheEvictionsCounter.Add(ctx, 1,
+			metric.WithAttributes(
+				attribute.String("cache.name", cacheName),
+				attribute.String("reason", reason),
+			),
+		)
+	}
+}
+
 // RecordSecurityCheck records a security framework check metric
 func RecordSecurityCheck(ctx context.Context, action string, sourceType string, durationMs float64) {
 	if !IsMetricsEnabled() || !isMetricGroupEnabled("security") {
@@ -0,0 +1,146 @@
+// Package toolcall calls a tool on behalf of another tool, such as batch or a pipeline, with the
+// checks and output handling the server applies to a call made directly by a client.
+package toolcall
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/logging"
+	"github.com/sammcj/mcp-devtools/internal/memusage"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workpool"
+	"github.com/sirupsen/logrus"
+)
+
+// Enabled returns an enabled tool by name
+func Enabled(name string) (tools.Tool, error) {
+	tool, ok := registry.GetEnabledTools()[name]
+	if !ok {
+		return nil, fmt.Errorf("tool %s is not enabled", name)
+	}
+	return tool, nil
+}
+
+// IsReadOnly reports whether a tool's annotations declare that it does not change anything
+func IsReadOnly(tool tools.Tool) bool {
+	readOnly := tool.Definition().Annotations.ReadOnlyHint
+	return readOnly != nil && *readOnly
+}
+
+// CheckMemory refuses a memory intensive call while the server is close to its memory limit
+func CheckMemory(tool tools.Tool, args map[string]any) error {
+	if heavy, ok := tool.(tools.MemoryIntensive); ok && heavy.IsMemoryIntensive(args) {
+		return memusage.CheckHeadroom()
+	}
+	return nil
+}
+
+// Queue waits for a worker for a heavy call and returns a function that frees it, or a no-op for
+// calls that run at once. While the call waits, progress notifications with the given token tell
+// the client its place in the queue.
+func Queue(ctx context.Context, tool tools.Tool, args map[string]any, progressToken mcp.ProgressToken) (release func(), err error) {
+	heavy, ok := tool.(tools.HeavyWorkload)
+	if !ok {
+		return func() {}, nil
+	}
+	queue := heavy.WorkQueue(args)
+	if queue == "" {
+		return func() {}, nil
+	}
+
+	// Progress counts the places moved up the queue, out of the starting position
+	start := 0
+	notify := func(position int, message string) {
+		srv := mcpserver.ServerFromContext(ctx)
+		if progressToken == nil || srv == nil {
+			return
+		}
+		_ = srv.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+			"progressToken": progressToken,
+			"progress":      start - position,
+			"total":         start,
+			"message":       message,
+		})
+	}
+	release, err = workpool.Acquire(ctx, queue, func(position int) {
+		start = max(start, position)
+		notify(position, fmt.Sprintf("Waiting for a %s worker: position %d in the queue", queue, position))
+	})
+	if err == nil && start > 0 {
+		notify(0, "Started")
+	}
+	return release, err
+}
+
+// Call calls a tool and returns its text output. The call is authorised and counted against the
+// caller's access policy, and its output is sanitised and redacted. A result marked as an error, a
+// panic and running past timeout are all returned as errors.
+func Call(ctx context.Context, logger *logrus.Logger, name string, tool tools.Tool, args map[string]any, timeout time.Duration) (output string, err error) {
+	if err := access.Authorise(ctx, name); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// A panicking tool must not stop the calling tool or the server
+	defer func() {
+		if r := recover(); r != nil {
+			logger.WithField("tool", name).Errorf("Tool panicked: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("tool %s panicked: %v", name, r)
+		}
+	}()
+
+	// Each call gets its own copy of the arguments, as tools may modify them
+	var callArgs map[string]any
+	data, _ := json.Marshal(args)
+	if err := json.Unmarshal(data, &callArgs); err != nil || callArgs == nil {
+		callArgs = make(map[string]any)
+	}
+	release, err := Queue(ctx, tool, callArgs, nil)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	if err := CheckMemory(tool, callArgs); err != nil {
+		return "", err
+	}
+	toolLogger := logging.ForTool(registry.GetLogger(), name)
+	allocated := memusage.Allocated()
+	result, err := tool.Execute(ctx, toolLogger, registry.GetCache(), callArgs)
+	memusage.CheckBudget(toolLogger, name, allocated)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		return "", err
+	}
+	if result == nil {
+		return "", nil
+	}
+
+	security.SanitiseToolResult(name, result)
+	security.RedactToolResult(name, result)
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	output = strings.Join(texts, "\n")
+	if result.IsError {
+		return "", errors.New(cmp.Or(output, "the tool reported an error"))
+	}
+	return output, nil
+}
@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// AgentCostLimitEnvVar is the environment variable for the most an MCP session may spend on agent
+	// tools, in US dollars
+	AgentCostLimitEnvVar = "AGENT_SESSION_COST_LIMIT"
+
+	// AgentModelPricesEnvVar is the environment variable for model prices that add to or replace the
+	// defaults, e.g. "gemini-2.5-pro=1.25/10,gemini-2.5-flash=0.30/2.50"
+	AgentModelPricesEnvVar = "AGENT_MODEL_PRICES"
+
+	// AgentUsageMetaKey is the key of an agent call's usage in the _meta of its tool result
+	AgentUsageMetaKey = "agent_usage"
+
+	// charsPerToken approximates the token count of text when an agent does not report it
+	charsPerToken = 4
+)
+
+// ModelPrice is the price of a model in US dollars per million tokens
+type ModelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// defaultModelPrices are the list prices of the models agent tools default to. Costs are estimates:
+// cached input is priced at the full rate, and prompts of more than 200k tokens at the lower one.
+var defaultModelPrices = map[string]ModelPrice{
+	"gemini-2.5-pro":        {Input: 1.25, Output: 10},
+	"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+	"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+}
+
+// AgentUsage is the token usage and estimated cost of an agent call
+type AgentUsage struct {
+	Model        string `json:"model"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+	CachedTokens int    `json:"cached_tokens,omitempty"`
+	// CostUSD is nil when the price of the model is unknown
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+	// Estimated is set when the agent did not report token counts and they were estimated from the
+	// length of the prompt and the response
+	Estimated bool `json:"tokens_estimated,omitempty"`
+}
+
+// AgentSessionUsage is the usage of agent calls in one MCP session
+type AgentSessionUsage struct {
+	Calls   int     `json:"calls"`
+	CostUSD float64 `json:"cost_usd"`
+	// LimitUSD is the session's cost ceiling, 0 without one
+	LimitUSD float64 `json:"limit_usd,omitempty"`
+}
+
+var (
+	agentSessionsMu sync.Mutex
+	agentSessions   = make(map[string]*AgentSessionUsage)
+)
+
+// GetAgentModelPrice returns the price of a model from AGENT_MODEL_PRICES or the defaults
+func GetAgentModelPrice(model string) (ModelPrice, bool) {
+	for entry := range strings.SplitSeq(os.Getenv(AgentModelPricesEnvVar), ",") {
+		name, prices, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), model) {
+			continue
+		}
+		input, output, ok := strings.Cut(prices, "/")
+		if !ok {
+			continue
+		}
+		inputPrice, inputErr := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		outputPrice, outputErr := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if inputErr == nil && outputErr == nil && inputPrice >= 0 && outputPrice >= 0 {
+			return ModelPrice{Input: inputPrice, Output: outputPrice}, true
+		}
+	}
+	price, ok := defaultModelPrices[strings.ToLower(model)]
+	return price, ok
+}
+
+// GetAgentCostLimit returns the cost ceiling of a session in US dollars, 0 when there is none
+func GetAgentCostLimit() float64 {
+	limit, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv(AgentCostLimitEnvVar)), 64)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// EstimateAgentTokens approximates the token count of text
+func EstimateAgentTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// Price sets the usage's cost from the price of its model, leaving it nil when the price is unknown
+func (u *AgentUsage) Price() {
+	price, ok := GetAgentModelPrice(u.Model)
+	if !ok {
+		u.CostUSD = nil
+		return
+	}
+	cost := (float64(u.InputTokens)*price.Input + float64(u.OutputTokens)*price.Output) / 1_000_000
+	u.CostUSD = &cost
+}
+
+// agentSessionKey returns the key of the calling MCP session, with all stdio calls in one session
+func agentSessionKey(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// CheckAgentCostLimit returns an error when the calling session has reached its cost ceiling, so an
+// agent tool can refuse a call before it is made
+func CheckAgentCostLimit(ctx context.Context) error {
+	limit := GetAgentCostLimit()
+	if limit == 0 {
+		return nil
+	}
+	agentSessionsMu.Lock()
+	defer agentSessionsMu.Unlock()
+	if session := agentSessions[agentSessionKey(ctx)]; session != nil && session.CostUSD >= limit {
+		return fmt.Errorf("this session has spent an estimated $%.4f on agent calls, reaching its cost limit of $%.2f set with %s. Continue without delegating to agents",
+			session.CostUSD, limit, AgentCostLimitEnvVar)
+	}
+	return nil
+}
+
+// RecordAgentUsage adds agent calls' usage to the calling session's total and returns the total
+func RecordAgentUsage(ctx context.Context, usages ...AgentUsage) AgentSessionUsage {
+	agentSessionsMu.Lock()
+	defer agentSessionsMu.Unlock()
+	key := agentSessionKey(ctx)
+	session := agentSessions[key]
+	if session == nil {
+		session = &AgentSessionUsage{}
+		agentSessions[key] = session
+	}
+	session.Calls++
+	for _, usage := range usages {
+		if usage.CostUSD != nil {
+			session.CostUSD += *usage.CostUSD
+		}
+	}
+	session.LimitUSD = GetAgentCostLimit()
+	return *session
+}
+
+// RegisterAgentCost drops each session's agent usage when the session ends
+func RegisterAgentCost(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		agentSessionsMu.Lock()
+		defer agentSessionsMu.Unlock()
+		delete(agentSessions, session.SessionID())
+	})
+}
+
+// WithAgentUsage adds the usage of an agent call, and the session's total, to the _meta of its result
+func WithAgentUsage(result *mcp.CallToolResult, usages []AgentUsage, session AgentSessionUsage) *mcp.CallToolResult {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	usage := map[string]any{"models": usages, "session": session}
+	// The call's cost is only given when the price of every model it used is known
+	total := 0.0
+	for _, model := range usages {
+		if model.CostUSD == nil {
+			total = -1
+			break
+		}
+		total += *model.CostUSD
+	}
+	if total >= 0 {
+		usage["cost_usd"] = total
+	}
+	result.Meta.AdditionalFields[AgentUsageMetaKey] = usage
+	return result
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/registry"
@@ -16,6 +18,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// regionCodePattern matches AWS region codes such as us-east-1, ap-southeast-2 or us-gov-west-1
+var regionCodePattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+
 // AWSDocumentationTool implements the unified AWS documentation functionality
 type AWSDocumentationTool struct {
 	client            *Client
@@ -64,6 +69,12 @@ func (t *AWSDocumentationTool) Definition() mcp.Tool {
 		mcp.WithNumber("max_results",
 			mcp.Description("Max pricing results to return (optional, default: 10)"),
 		),
+		mcp.WithString("region",
+			mcp.Description("AWS region code to price in, e.g. 'us-east-1' (optional for 'get_service_pricing' action, adds a regionCode filter)"),
+		),
+		mcp.WithString("instance_type",
+			mcp.Description("Instance type to price, e.g. 'm7g.large' or 'db.r6g.xlarge' (optional for 'get_service_pricing' action, adds an instanceType filter)"),
+		),
 		// Read-only annotations for AWS documentation fetching tool
 		mcp.WithReadOnlyHintAnnotation(true),     // Only fetches AWS documentation and pricing, doesn't modify environment
 		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
@@ -96,22 +107,22 @@ func (t *AWSDocumentationTool) Execute(ctx context.Context, logger *logrus.Logge
 	// Dispatch to appropriate handler
 	switch action {
 	case "search":
-		return t.executeSearch(ctx, args)
+		return t.executeSearch(ctx, cache, args)
 	case "fetch":
-		return t.executeFetch(ctx, args)
+		return t.executeFetch(ctx, cache, args)
 	case "recommend":
 		return t.executeRecommend(ctx, args)
 	case "list_pricing_services":
-		return t.executeListPricingServices(ctx, logger, args)
+		return t.executeListPricingServices(ctx, logger, cache, args)
 	case "get_service_pricing":
-		return t.executeGetServicePricing(ctx, logger, args)
+		return t.executeGetServicePricing(ctx, logger, cache, args)
 	default:
 		return nil, fmt.Errorf("invalid action: %s. Must be one of: search, fetch, recommend, list_pricing_services, get_service_pricing", action)
 	}
 }
 
 // executeSearch performs documentation search
-func (t *AWSDocumentationTool) executeSearch(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+func (t *AWSDocumentationTool) executeSearch(ctx context.Context, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
 	// Parse search phrase
 	searchPhrase, ok := args["search_phrase"].(string)
 	if !ok {
@@ -132,10 +143,16 @@ func (t *AWSDocumentationTool) executeSearch(ctx context.Context, args map[strin
 		}
 	}
 
-	// Perform search
-	results, err := t.client.SearchDocumentation(ctx, searchPhrase, limit)
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+	// Perform search, reusing recent results for the same query
+	cacheKey := fmt.Sprintf("search:%d:%s", limit, strings.ToLower(searchPhrase))
+	results, ok := loadCached[[]SearchResult](cache, cacheKey, docsCacheTTL)
+	if !ok {
+		var err error
+		results, err = t.client.SearchDocumentation(ctx, searchPhrase, limit)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		storeCached(cache, cacheKey, results)
 	}
 
 	// Format results
@@ -156,7 +173,7 @@ func (t *AWSDocumentationTool) executeSearch(ctx context.Context, args map[strin
 }
 
 // executeFetch performs documentation fetching and conversion
-func (t *AWSDocumentationTool) executeFetch(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+func (t *AWSDocumentationTool) executeFetch(ctx context.Context, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
 	// Parse the URL parameter
 	urlRaw, ok := args["url"].(string)
 	if !ok {
@@ -179,22 +196,25 @@ func (t *AWSDocumentationTool) executeFetch(ctx context.Context, args map[string
 		return nil, err
 	}
 
-	// Fetch the documentation
-	htmlContent, err := t.client.FetchDocumentation(ctx, urlRaw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch documentation: %w", err)
-	}
-
-	// Check if content is HTML
-	contentType := "text/html" // AWS docs are always HTML
-	var markdownContent string
-	if IsHTMLContent(htmlContent, contentType) {
-		markdownContent, err = t.parser.ConvertHTMLToMarkdown(htmlContent)
+	// Fetch the documentation, reusing converted pages so pagination doesn't refetch
+	markdownContent, ok := loadCached[string](cache, "fetch:"+urlRaw, docsCacheTTL)
+	if !ok {
+		htmlContent, err := t.client.FetchDocumentation(ctx, urlRaw)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert HTML to markdown: %w", err)
+			return nil, fmt.Errorf("failed to fetch documentation: %w", err)
 		}
-	} else {
-		markdownContent = htmlContent
+
+		// Check if content is HTML
+		contentType := "text/html" // AWS docs are always HTML
+		if IsHTMLContent(htmlContent, contentType) {
+			markdownContent, err = t.parser.ConvertHTMLToMarkdown(htmlContent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert HTML to markdown: %w", err)
+			}
+		} else {
+			markdownContent = htmlContent
+		}
+		storeCached(cache, "fetch:"+urlRaw, markdownContent)
 	}
 
 	// Format result with pagination
@@ -286,7 +306,35 @@ func validateAWSDocumentationURL(url string) error {
 }
 
 // executeListPricingServices lists all AWS services with available pricing
-func (t *AWSDocumentationTool) executeListPricingServices(ctx context.Context, logger *logrus.Logger, _ map[string]any) (*mcp.CallToolResult, error) {
+func (t *AWSDocumentationTool) executeListPricingServices(ctx context.Context, logger *logrus.Logger, cache *sync.Map, _ map[string]any) (*mcp.CallToolResult, error) {
+	serviceCodes, ok := loadCached[[]string](cache, "pricing_services", pricingCacheTTL)
+	if !ok {
+		var err error
+		serviceCodes, err = t.listPricingServiceCodes(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		storeCached(cache, "pricing_services", serviceCodes)
+	}
+
+	// Format results
+	result := map[string]any{
+		"action":         "list_pricing_services",
+		"services_count": len(serviceCodes),
+		"services":       serviceCodes,
+	}
+
+	// Convert result to JSON string
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// listPricingServiceCodes fetches the service codes that have pricing data
+func (t *AWSDocumentationTool) listPricingServiceCodes(ctx context.Context, logger *logrus.Logger) ([]string, error) {
 	// Initialise pricing client if needed (thread-safe)
 	t.pricingClientOnce.Do(func() {
 		t.pricingClient, t.pricingClientErr = pricing.NewClient(ctx, logger)
@@ -308,25 +356,11 @@ func (t *AWSDocumentationTool) executeListPricingServices(ctx context.Context, l
 			serviceCodes = append(serviceCodes, *svc.ServiceCode)
 		}
 	}
-
-	// Format results
-	result := map[string]any{
-		"action":         "list_pricing_services",
-		"services_count": len(serviceCodes),
-		"services":       serviceCodes,
-	}
-
-	// Convert result to JSON string
-	jsonBytes, err := json.Marshal(result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal result: %w", err)
-	}
-
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return serviceCodes, nil
 }
 
 // executeGetServicePricing gets pricing for a specific AWS service
-func (t *AWSDocumentationTool) executeGetServicePricing(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+func (t *AWSDocumentationTool) executeGetServicePricing(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
 	// Parse service_code (required) - validate BEFORE initialising AWS client
 	serviceCode, ok := args["service_code"].(string)
 	if !ok {
@@ -339,6 +373,7 @@ func (t *AWSDocumentationTool) executeGetServicePricing(ctx context.Context, log
 	}
 
 	// Parse max_results (optional) - validate BEFORE initialising AWS client
+	var err error
 	maxResults := int32(10)
 	if maxResultsRaw, ok := args["max_results"].(float64); ok {
 		maxResults = int32(maxResultsRaw)
@@ -385,6 +420,27 @@ func (t *AWSDocumentationTool) executeGetServicePricing(ctx context.Context, log
 		}
 	}
 
+	// Convenience filters for the most common lookups
+	if region, ok := args["region"].(string); ok && strings.TrimSpace(region) != "" {
+		region = strings.TrimSpace(region)
+		if !regionCodePattern.MatchString(region) {
+			return nil, fmt.Errorf("region must be an AWS region code such as 'us-east-1' or 'ap-southeast-2', got: %s", region)
+		}
+		if awsFilters, err = addConvenienceFilter(awsFilters, "regionCode", region); err != nil {
+			return nil, err
+		}
+	}
+	if instanceType, ok := args["instance_type"].(string); ok && strings.TrimSpace(instanceType) != "" {
+		if awsFilters, err = addConvenienceFilter(awsFilters, "instanceType", strings.TrimSpace(instanceType)); err != nil {
+			return nil, err
+		}
+	}
+
+	cacheKey := pricingCacheKey(serviceCode, awsFilters, maxResults)
+	if priceList, ok := loadCached[[]string](cache, cacheKey, pricingCacheTTL); ok {
+		return pricingResult(serviceCode, priceList, true)
+	}
+
 	// Initialise pricing client if needed (thread-safe) - only AFTER parameter validation
 	t.pricingClientOnce.Do(func() {
 		t.pricingClient, t.pricingClientErr = pricing.NewClient(ctx, logger)
@@ -398,13 +454,44 @@ func (t *AWSDocumentationTool) executeGetServicePricing(ctx context.Context, log
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service pricing: %w", err)
 	}
+	storeCached(cache, cacheKey, priceList)
+
+	return pricingResult(serviceCode, priceList, false)
+}
+
+// addConvenienceFilter adds a TERM_MATCH filter unless the same field is already filtered
+func addConvenienceFilter(filters []types.Filter, field, value string) ([]types.Filter, error) {
+	for _, f := range filters {
+		if f.Field != nil && strings.EqualFold(*f.Field, field) {
+			return nil, fmt.Errorf("%s is already set in filters - use either the filter or the convenience parameter, not both", field)
+		}
+	}
+	return append(filters, types.Filter{
+		Field: &field,
+		Value: &value,
+		Type:  types.FilterTypeTermMatch,
+	}), nil
+}
+
+// pricingCacheKey builds a cache key that is independent of filter order
+func pricingCacheKey(serviceCode string, filters []types.Filter, maxResults int32) string {
+	parts := make([]string, 0, len(filters))
+	for _, f := range filters {
+		parts = append(parts, fmt.Sprintf("%s=%s=%s", f.Type, aws.ToString(f.Field), aws.ToString(f.Value)))
+	}
+	slices.Sort(parts)
+	return fmt.Sprintf("pricing:%s:%d:%s", serviceCode, maxResults, strings.Join(parts, "&"))
+}
 
+// pricingResult formats a get_service_pricing response
+func pricingResult(serviceCode string, priceList []string, cached bool) (*mcp.CallToolResult, error) {
 	// Format results
 	result := map[string]any{
 		"action":        "get_service_pricing",
 		"service_code":  serviceCode,
 		"product_count": len(priceList),
 		"price_list":    priceList,
+		"cached":        cached,
 	}
 
 	// Convert result to JSON string
@@ -464,6 +551,22 @@ func (t *AWSDocumentationTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 				},
 				ExpectedResult: "Pricing information for EC2 instances in us-east-1 with product details and pricing",
 			},
+			{
+				Description: "Get on-demand pricing for an instance type in a region",
+				Arguments: map[string]any{
+					"action":        "get_service_pricing",
+					"service_code":  "AmazonEC2",
+					"region":        "ap-southeast-2",
+					"instance_type": "m7g.large",
+					"filters": []map[string]any{
+						{"field": "operatingSystem", "value": "Linux"},
+						{"field": "tenancy", "value": "Shared"},
+						{"field": "capacitystatus", "value": "Used"},
+						{"field": "preInstalledSw", "value": "NA"},
+					},
+				},
+				ExpectedResult: "Pricing for Linux m7g.large instances in Sydney",
+			},
 			{
 				Description: "Get S3 pricing with storage class filter",
 				Arguments: map[string]any{
@@ -484,6 +587,8 @@ func (t *AWSDocumentationTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"Documentation: For large documents, use pagination with start_index and max_length",
 			"Pricing: Use 'list_pricing_services' to discover available AWS services",
 			"Pricing: Use 'get_service_pricing' with filters to find specific pricing (instance types, storage classes, etc.)",
+			"Pricing: Use region and instance_type instead of writing regionCode and instanceType filters by hand",
+			"Results are cached (documentation for 1 hour, pricing for 24 hours), so repeated lookups and fetch pagination are fast",
 		},
 		Troubleshooting: []tools.TroubleshootingTip{
 			{
@@ -496,7 +601,7 @@ func (t *AWSDocumentationTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			},
 			{
 				Problem:  "Pricing request is slow",
-				Solution: "Pricing requests fetch data directly from AWS API. Response time depends on AWS API performance and the number of results",
+				Solution: "The first request for a query goes to the AWS API and depends on its performance and the number of results. Repeated requests are served from the cache",
 			},
 			{
 				Problem:  "Too many pricing results returned",
@@ -513,6 +618,8 @@ func (t *AWSDocumentationTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"service_code":  "Required for get_service_pricing - AWS service code like 'AmazonEC2' or 'AmazonS3'",
 			"filters":       "Optional for get_service_pricing - array of filter objects with 'field' and 'value' properties (e.g., location, instanceType, storageClass)",
 			"max_results":   "Optional for get_service_pricing - limit number of products returned (default: 10)",
+			"region":        "Optional for get_service_pricing - AWS region code such as 'us-east-1', added as a regionCode filter",
+			"instance_type": "Optional for get_service_pricing - instance type such as 'm7g.large' or 'db.r6g.xlarge', added as an instanceType filter",
 		},
 		WhenToUse:    "Use for AWS documentation search/fetch/recommendations (no credentials needed) and AWS pricing information (requires AWS credentials)",
 		WhenNotToUse: "Don't use for non-AWS documentation or when you need AWS account-specific pricing (use AWS Cost Explorer instead)",
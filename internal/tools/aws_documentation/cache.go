@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keys and TTLs. Documentation changes rarely and list prices change at most a few
+// times a month, so results are reused across calls, including fetch pagination.
+const (
+	cacheKeyPrefix  = "aws_documentation:"
+	docsCacheTTL    = 1 * time.Hour
+	pricingCacheTTL = 24 * time.Hour
+)
+
+// CacheEntry stores a cached result with the time it was fetched
+type CacheEntry struct {
+	Data      any
+	Timestamp time.Time
+}
+
+// loadCached returns a cached value if it exists and is younger than ttl
+func loadCached[T any](cache *sync.Map, key string, ttl time.Duration) (T, bool) {
+	var zero T
+	if cache == nil {
+		return zero, false
+	}
+	cached, ok := cache.Load(cacheKeyPrefix + key)
+	if !ok {
+		return zero, false
+	}
+	entry, ok := cached.(CacheEntry)
+	if !ok || time.Since(entry.Timestamp) >= ttl {
+		return zero, false
+	}
+	value, ok := entry.Data.(T)
+	return value, ok
+}
+
+// storeCached stores a value in the cache
+func storeCached(cache *sync.Map, key string, value any) {
+	if cache == nil {
+		return
+	}
+	cache.Store(cacheKeyPrefix+key, CacheEntry{Data: value, Timestamp: time.Now()})
+}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sammcj/mcp-devtools/internal/security"
-	"github.com/sammcj/mcp-devtools/internal/telemetry"
 	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
@@ -35,7 +34,7 @@ type Client struct {
 func NewClient(logger *logrus.Logger) *Client {
 	return &Client{
 		sessionUUID: uuid.New().String(),
-		httpClient:  httpclient.NewHTTPClientWithProxyAndLogger(requestTimeout, logger),
+		httpClient:  httpclient.New(httpclient.Options{Timeout: requestTimeout, Logger: logger, Cache: true}),
 		logger:      logger,
 		ops:         security.NewOperations("aws"),
 	}
@@ -112,9 +111,7 @@ func (c *Client) SearchDocumentation(ctx context.Context, query string, limit in
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("X-MCP-Session-Id", c.sessionUUID)
 
-	// Execute request with telemetry
-	client := telemetry.WrapHTTPClient(c.httpClient)
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("search request failed: %w", err)
 	}
@@ -202,9 +199,7 @@ func (c *Client) GetRecommendations(ctx context.Context, url string) ([]Recommen
 
 	req.Header.Set("User-Agent", userAgent)
 
-	// Execute request with telemetry
-	client := telemetry.WrapHTTPClient(c.httpClient)
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("recommendations request failed: %w", err)
 	}
@@ -0,0 +1,400 @@
+package buildtargets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AllowRunEnvVar enables the run function when set to true
+	AllowRunEnvVar = "BUILD_TARGETS_ALLOW_RUN"
+
+	// defaultTimeout is how long a target may run by default, in seconds
+	defaultTimeout = 300
+
+	// maxTimeout is the longest a target may run, in seconds
+	maxTimeout = 1800
+
+	// defaultMaxOutputLines is how many lines of output are returned by default
+	defaultMaxOutputLines = 200
+
+	// maxMaxOutputLines is the most lines of output that can be returned
+	maxMaxOutputLines = 2000
+
+	// maxListedTargets is how many target names an error for an unknown target suggests
+	maxListedTargets = 30
+)
+
+// variablePattern matches a variable assignment such as VERSION=1.2.0
+var variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// BuildTargetsTool lists and runs the targets of Makefiles, Taskfiles and justfiles
+type BuildTargetsTool struct{}
+
+// init registers the build_targets tool
+func init() {
+	registry.Register(&BuildTargetsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *BuildTargetsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"build_targets",
+		mcp.WithDescription(`List the targets of a project's Makefile, Taskfile.yml and justfile with their descriptions, dependencies and parameters, and run a named target with its output captured. Use list before guessing at make or task target names.
+
+Functions:
+- list: each build file with its targets, default target and whether make, task or just is installed
+- run: run a target and return its exit code and output - only available when the server sets BUILD_TARGETS_ALLOW_RUN=true`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("list", "run"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the project directory, or to a Makefile, Taskfile or justfile"),
+		),
+		mcp.WithString("system",
+			mcp.Description("Only use this build tool. Needed by run when the target is defined in more than one build file"),
+			mcp.Enum(systemNames()...),
+		),
+		mcp.WithString("target",
+			mcp.Description("run: the target, task or recipe to run, as named by list"),
+		),
+		mcp.WithArray("variables",
+			mcp.Description("run: variables to set as NAME=value, such as VERSION=1.2.0"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("arguments",
+			mcp.Description("run: arguments for a just recipe's parameters, or a task's CLI_ARGS"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("run: seconds before the target is stopped (default: %d, max: %d)", defaultTimeout, maxTimeout)),
+		),
+		mcp.WithNumber("max_output_lines",
+			mcp.Description(fmt.Sprintf("run: most lines of output to return, from the start and end (default: %d, max: %d)", defaultMaxOutputLines, maxMaxOutputLines)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // run executes the project's build targets when enabled
+		mcp.WithDestructiveHintAnnotation(true), // Targets such as clean or deploy can delete or change anything
+		mcp.WithIdempotentHintAnnotation(false), // Running a target again can have different effects
+		mcp.WithOpenWorldHintAnnotation(true),   // Targets can download dependencies or deploy
+	)
+}
+
+// Execute runs the requested function
+func (t *BuildTargetsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	only, _ := args["system"].(string)
+	if only != "" && !slices.Contains(systemNames(), only) {
+		return nil, fmt.Errorf("invalid system %q: must be one of %s", only, strings.Join(systemNames(), ", "))
+	}
+
+	resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path: %w", err)
+	}
+
+	var result any
+	switch function {
+	case "list":
+		var list *ListResult
+		list, err = listTargets(resolved, info.IsDir(), only)
+		result = list
+	case "run":
+		if !runAllowed() {
+			return nil, fmt.Errorf("run is disabled - the build_targets tool only lists targets unless the server sets %s=true", AllowRunEnvVar)
+		}
+		var list *ListResult
+		if list, err = listTargets(resolved, info.IsDir(), only); err == nil {
+			logger.WithFields(logrus.Fields{"dir": list.Dir, "target": args["target"]}).Debug("Running build target")
+			result, err = runTarget(ctx, list, args)
+		}
+	default:
+		return nil, fmt.Errorf("unknown function: %s", function)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep commands and output, such as "a && b", readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Descriptions and output come from the project, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "build_targets",
+		URL:         "file://" + resolved,
+		ContentType: "build_output",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// runAllowed reports whether the run function is enabled
+func runAllowed() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(AllowRunEnvVar)))
+	return value == "true" || value == "1"
+}
+
+// listTargets parses the build files in a directory, or the build file path names
+func listTargets(path string, isDir bool, only string) (*ListResult, error) {
+	dir, name := path, ""
+	if !isDir {
+		dir, name = filepath.Dir(path), filepath.Base(path)
+	}
+
+	type found struct {
+		system system
+		file   string
+	}
+	var files []found
+	if name != "" {
+		s, ok := systemFor(name)
+		if only != "" {
+			s, ok = systemNamed(only), true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s is not a Makefile, Taskfile or justfile. Set system to read it as one", name)
+		}
+		files = append(files, found{s, name})
+	} else {
+		for _, s := range systems {
+			if only != "" && s.name != only {
+				continue
+			}
+			if file := findFile(dir, s.files); file != "" {
+				files = append(files, found{s, file})
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no Makefile, Taskfile or justfile found in %s", dir)
+		}
+	}
+
+	result := &ListResult{Dir: dir, BuildFiles: []BuildFile{}, RunEnabled: runAllowed()}
+	var notes []string
+	for _, f := range files {
+		if err := security.CheckFileAccess(filepath.Join(dir, f.file)); err != nil {
+			notes = append(notes, fmt.Sprintf("%s was not read: %v.", f.file, err))
+			continue
+		}
+		buildFile, err := f.system.parse(dir, f.file)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s could not be read: %v.", f.file, err))
+			continue
+		}
+		buildFile.Runner = f.system.runner()
+		if buildFile.Runner == "" {
+			notes = append(notes, fmt.Sprintf("%s is not installed, so the targets in %s cannot be run.", f.system.name, f.file))
+		}
+		result.BuildFiles = append(result.BuildFiles, buildFile)
+	}
+	if len(result.BuildFiles) == 0 {
+		return nil, fmt.Errorf("no build file could be read: %s", strings.Join(notes, " "))
+	}
+	result.Note = strings.Join(notes, " ")
+	return result, nil
+}
+
+// runTarget runs a listed target with the requested variables and arguments
+func runTarget(ctx context.Context, list *ListResult, args map[string]any) (*RunResult, error) {
+	target, _ := args["target"].(string)
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("missing required parameter: target")
+	}
+	if strings.HasPrefix(target, "-") {
+		return nil, fmt.Errorf("invalid target %q: targets cannot start with '-'", target)
+	}
+
+	var matches []BuildFile
+	var available []string
+	for _, buildFile := range list.BuildFiles {
+		for _, t := range buildFile.Targets {
+			if !slices.Contains(available, t.Name) {
+				available = append(available, t.Name)
+			}
+			if t.Name == target || slices.Contains(t.Aliases, target) {
+				matches = append(matches, buildFile)
+				break
+			}
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		if len(available) > maxListedTargets {
+			available = append(available[:maxListedTargets], "...")
+		}
+		return nil, fmt.Errorf("target %q not found. Available targets: %s", target, strings.Join(available, ", "))
+	case len(matches) > 1:
+		var files []string
+		for _, buildFile := range matches {
+			files = append(files, buildFile.File)
+		}
+		return nil, fmt.Errorf("target %q is defined in more than one build file (%s). Set system to choose one", target, strings.Join(files, ", "))
+	}
+	buildFile := matches[0]
+	if buildFile.Runner == "" {
+		return nil, fmt.Errorf("%s is not installed, so %s cannot be run", buildFile.System, target)
+	}
+
+	variables, err := stringList(args, "variables")
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range variables {
+		if !variablePattern.MatchString(variable) {
+			return nil, fmt.Errorf("invalid variable %q: expected NAME=value", strings.SplitN(variable, "=", 2)[0])
+		}
+	}
+	arguments, err := stringList(args, "arguments")
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) > 0 && buildFile.System == "make" {
+		return nil, fmt.Errorf("make targets take no arguments. Pass values as variables instead")
+	}
+	for _, argument := range arguments {
+		// Arguments are passed singly, so refusing a leading dash stops them being read as options
+		if strings.HasPrefix(argument, "-") {
+			return nil, fmt.Errorf("invalid argument %q: arguments cannot start with '-'", argument)
+		}
+	}
+
+	timeout := defaultTimeout * time.Second
+	if raw, ok := args["timeout"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxTimeout {
+			return nil, fmt.Errorf("invalid 'timeout' parameter: must be from 1 to %d seconds", maxTimeout)
+		}
+		timeout = time.Duration(value * float64(time.Second))
+	}
+	maxLines := defaultMaxOutputLines
+	if raw, ok := args["max_output_lines"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxMaxOutputLines || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'max_output_lines' parameter: must be a whole number from 1 to %d", maxMaxOutputLines)
+		}
+		maxLines = int(value)
+	}
+
+	s := systemNamed(buildFile.System)
+	result, err := execute(ctx, list.Dir, buildFile.Runner, s.command(buildFile.File, target, variables, arguments), timeout, maxLines)
+	if err != nil {
+		return nil, err
+	}
+	result.System, result.Target = buildFile.System, target
+	return &result, nil
+}
+
+// stringList reads an optional array of strings from the arguments
+func stringList(args map[string]any, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the build_targets tool
+func (t *BuildTargetsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Finding out how a project is built, tested and released before running make, task or just commands, instead of guessing target names. Running a named target and reading its output when the server allows it.",
+		WhenNotToUse: "Projects built with other tools such as npm scripts, cargo or gradle. Running tests to get per-test results - use run_tests. Long-running targets such as dev servers that never exit.",
+		CommonPatterns: []string{
+			"List the targets, then run the one whose description matches what you need",
+			"Pass settings a Makefile reads with variables: ['VERSION=1.2.0']",
+			"Give a just recipe's parameters with arguments: ['release']",
+		},
+		ParameterDetails: map[string]string{
+			"path":      "A directory is searched for GNUmakefile, makefile or Makefile, a Taskfile (Taskfile.yml, Taskfile.yaml and their dist variants) and a justfile. A file path reads only that file.",
+			"system":    "Limits list to one build tool. A target named in more than one build file, such as test in both a Makefile and a justfile, needs system to run.",
+			"target":    "Must be a name or alias that list returns. Included Taskfile tasks are named namespace:task.",
+			"variables": "make sets them as command line variables, task as template variables and just as overrides of justfile variables.",
+			"arguments": "Only just and task targets take arguments. just fills the recipe's parameters in order; task receives them as CLI_ARGS.",
+			"timeout":   "The target and every process it started are stopped at the timeout, and timed_out is set.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "List a project's build targets",
+				Arguments:      map[string]any{"function": "list", "path": "/home/user/service"},
+				ExpectedResult: "Each build file with its default target, the runner's path and its targets' descriptions, dependencies and line numbers",
+			},
+			{
+				Description: "Run a make target with a variable",
+				Arguments: map[string]any{
+					"function":  "run",
+					"path":      "/home/user/service",
+					"target":    "build",
+					"variables": []string{"VERSION=1.2.0"},
+				},
+				ExpectedResult: "The command run, whether it succeeded, its exit code, duration and the start and end of its output",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "run is disabled",
+				Solution: "Running targets executes the project's commands, so it needs the server to be started with BUILD_TARGETS_ALLOW_RUN=true.",
+			},
+			{
+				Problem:  "A target is missing from the list",
+				Solution: "Pattern rules, targets named with variables, internal tasks, private just recipes and files included through variables or remote URLs are not listed.",
+			},
+			{
+				Problem:  "The output is cut",
+				Solution: "Lines from the middle are left out beyond max_output_lines; output_lines gives the full count. Raise max_output_lines to see more.",
+			},
+		},
+	}
+}
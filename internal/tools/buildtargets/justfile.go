@@ -0,0 +1,192 @@
+package buildtargets
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// justFiles are the names just looks for
+var justFiles = []string{"justfile", "Justfile", "JUSTFILE", ".justfile"}
+
+var (
+	// justRecipePattern matches the start of a recipe header or assignment, such as "@build target:"
+	justRecipePattern = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)(.*)$`)
+
+	// justAliasPattern matches an alias, such as "alias b := build"
+	justAliasPattern = regexp.MustCompile(`^alias\s+([A-Za-z_][A-Za-z0-9_-]*)\s*:=\s*([A-Za-z_][A-Za-z0-9_-]*)`)
+
+	// justDocPattern matches a doc attribute, such as [doc("Build the site")]
+	justDocPattern = regexp.MustCompile(`doc\(\s*(?:'([^']*)'|"([^"]*)")\s*\)`)
+
+	// justAttributeNamePattern matches the names of the attributes in an attribute line
+	justAttributeNamePattern = regexp.MustCompile(`(?:^\[|,)\s*([a-z-]+)`)
+)
+
+// justKeywords start lines that are never recipes
+var justKeywords = []string{"set", "export", "unexport", "import", "mod", "alias"}
+
+// parseJustfile reads the recipes of a justfile. Private recipes, which start with an underscore
+// or have the [private] attribute, are left out.
+func parseJustfile(dir, file string) (BuildFile, error) {
+	f, err := os.Open(filepath.Join(dir, file))
+	if err != nil {
+		return BuildFile{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	result := BuildFile{System: "just", File: file, Targets: []Target{}}
+	aliases := map[string][]string{}
+	var comment string
+	var attributes []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			comment, attributes = "", nil
+			continue
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			// Recipe bodies
+			comment, attributes = "", nil
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			// just describes a recipe with the comment line directly above it
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if strings.HasPrefix(trimmed, "#!") {
+				comment = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			attributes = append(attributes, trimmed)
+			continue
+		}
+
+		if match := justAliasPattern.FindStringSubmatch(trimmed); match != nil {
+			aliases[match[2]] = append(aliases[match[2]], match[1])
+		} else if word, _, _ := strings.Cut(trimmed, " "); !slices.Contains(justKeywords, word) {
+			if t, ok := justRecipe(trimmed, number); ok {
+				if result.Default == "" || slices.ContainsFunc(attributes, func(a string) bool { return slices.Contains(justAttributes(a), "default") }) {
+					result.Default = t.Name
+				}
+				if !strings.HasPrefix(t.Name, "_") && !slices.ContainsFunc(attributes, func(a string) bool { return slices.Contains(justAttributes(a), "private") }) {
+					t.Description = comment
+					for _, attribute := range attributes {
+						if match := justDocPattern.FindStringSubmatch(attribute); match != nil {
+							t.Description = match[1] + match[2]
+						}
+					}
+					result.Targets = append(result.Targets, t)
+				}
+			}
+		}
+		comment, attributes = "", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return BuildFile{}, err
+	}
+	for i := range result.Targets {
+		result.Targets[i].Aliases = aliases[result.Targets[i].Name]
+	}
+	return result, nil
+}
+
+// justRecipe reads a recipe header such as "build target='release' *flags: clean (compile target)".
+// Assignments, whose first unquoted colon is followed by =, are not recipes.
+func justRecipe(line string, number int) (Target, bool) {
+	match := justRecipePattern.FindStringSubmatch(line)
+	if match == nil {
+		return Target{}, false
+	}
+	rest := match[2]
+	colon := unquotedIndex(rest, ':')
+	if colon < 0 || strings.HasPrefix(rest[colon+1:], "=") {
+		return Target{}, false
+	}
+
+	t := Target{Name: match[1], Line: number, Parameters: justFields(rest[:colon])}
+	body, _, _ := strings.Cut(rest[colon+1:], "#")
+	for _, dependency := range justFields(body) {
+		if dependency == "&&" {
+			continue
+		}
+		// Dependencies with arguments are written (name args)
+		if inner, ok := strings.CutPrefix(dependency, "("); ok {
+			dependency, _, _ = strings.Cut(strings.TrimSuffix(inner, ")"), " ")
+		}
+		t.Dependencies = append(t.Dependencies, dependency)
+	}
+	return t, true
+}
+
+// justAttributes returns the names of the attributes in an attribute line such as "[no-cd, private]"
+func justAttributes(line string) []string {
+	var names []string
+	for _, match := range justAttributeNamePattern.FindAllStringSubmatch(line, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// justFields splits text on spaces outside quotes, backticks and parentheses
+func justFields(text string) []string {
+	var fields []string
+	var field strings.Builder
+	var quote rune
+	depth := 0
+	for _, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth = max(0, depth-1)
+		case (r == ' ' || r == '\t') && depth == 0:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteRune(r)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// unquotedIndex returns the index of the first c outside quotes, backticks and parentheses, or -1
+func unquotedIndex(text string, c byte) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch b := text[i]; {
+		case quote != 0:
+			if b == quote {
+				quote = 0
+			}
+		case b == '\'' || b == '"' || b == '`':
+			quote = b
+		case b == '(':
+			depth++
+		case b == ')':
+			depth = max(0, depth-1)
+		case b == c && depth == 0:
+			return i
+		}
+	}
+	return -1
+}
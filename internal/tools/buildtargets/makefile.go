@@ -0,0 +1,209 @@
+package buildtargets
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// maxIncludedFiles is the most files read through include directives
+const maxIncludedFiles = 20
+
+// makeFiles are the names GNU make looks for, in the order it looks
+var makeFiles = []string{"GNUmakefile", "makefile", "Makefile"}
+
+var (
+	// makeRulePattern matches a rule's targets and the rest of the line, such as "build test: deps"
+	makeRulePattern = regexp.MustCompile(`^([^\t:#=][^:#=]*?)\s*(::?)(.*)$`)
+
+	// makeDefaultGoalPattern matches an assignment to .DEFAULT_GOAL
+	makeDefaultGoalPattern = regexp.MustCompile(`^\.DEFAULT_GOAL\s*(?::{1,2}|\?)?=\s*(\S+)`)
+
+	// makeIncludePattern matches an include directive and its files
+	makeIncludePattern = regexp.MustCompile(`^(?:-include|sinclude|include)\s+(.+)$`)
+)
+
+// makeDirectives start lines that are never rules
+var makeDirectives = []string{"ifeq", "ifneq", "ifdef", "ifndef", "else", "endif", "export", "unexport", "override", "vpath", "undefine", "private", "define", "endef"}
+
+// makeParser collects targets from a Makefile and the files it includes
+type makeParser struct {
+	dir         string
+	targets     []*Target
+	byName      map[string]*Target
+	defaultGoal string
+	firstTarget string
+	included    []string
+	seen        map[string]bool
+}
+
+// parseMakefile reads the targets of a Makefile. Included files are read too, relative to dir as
+// make resolves them, when their names do not use variables.
+func parseMakefile(dir, file string) (BuildFile, error) {
+	p := &makeParser{dir: dir, byName: map[string]*Target{}, seen: map[string]bool{}}
+	if err := p.parse(file, true); err != nil {
+		return BuildFile{}, err
+	}
+	result := BuildFile{System: "make", File: file, Default: cmp.Or(p.defaultGoal, p.firstTarget), Includes: p.included, Targets: []Target{}}
+	for _, t := range p.targets {
+		result.Targets = append(result.Targets, *t)
+	}
+	return result, nil
+}
+
+// parse reads one file's rules
+func (p *makeParser) parse(file string, main bool) error {
+	p.seen[file] = true
+	f, err := os.Open(filepath.Join(p.dir, file))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	var comment []string
+	inDefine := false
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		start := number
+		line := scanner.Text()
+		// Join continued lines
+		for strings.HasSuffix(line, "\\") && scanner.Scan() {
+			number++
+			line = strings.TrimSuffix(line, "\\") + " " + strings.TrimSpace(scanner.Text())
+		}
+
+		trimmed := strings.TrimSpace(line)
+		word, _, _ := strings.Cut(trimmed, " ")
+		switch {
+		case inDefine:
+			inDefine = word != "endef"
+			continue
+		case word == "define":
+			inDefine = true
+			continue
+		case strings.HasPrefix(line, "\t"):
+			// Recipe lines
+			comment = nil
+			continue
+		case trimmed == "":
+			comment = nil
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comment = append(comment, trimmed)
+			continue
+		}
+
+		if match := makeDefaultGoalPattern.FindStringSubmatch(trimmed); match != nil {
+			p.defaultGoal = match[1]
+		} else if match := makeIncludePattern.FindStringSubmatch(trimmed); match != nil {
+			p.include(match[1])
+		} else if !slices.Contains(makeDirectives, word) {
+			p.rule(line, file, start, main, comment)
+		}
+		comment = nil
+	}
+	return scanner.Err()
+}
+
+// rule records the targets of a rule line
+func (p *makeParser) rule(line, file string, number int, main bool, comment []string) {
+	match := makeRulePattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	rest := match[3]
+	// := and ::= are assignments, and target: VAR = value sets a target-specific variable
+	if strings.HasPrefix(rest, "=") || strings.Contains(strings.SplitN(rest, "#", 2)[0], "=") {
+		return
+	}
+
+	prerequisites, inline, _ := strings.Cut(rest, "#")
+	prerequisites, _, _ = strings.Cut(prerequisites, ";")
+	description := ""
+	switch {
+	case strings.HasPrefix(inline, "#"):
+		// The self-documenting "target: ## description" convention
+		description = strings.TrimSpace(strings.TrimLeft(inline, "#"))
+	case len(comment) > 0:
+		description = commentText(comment)
+	default:
+		description = strings.TrimSpace(inline)
+	}
+
+	var dependencies []string
+	for _, dependency := range strings.Fields(prerequisites) {
+		if dependency != "|" {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	for name := range strings.FieldsSeq(match[1]) {
+		// Special targets such as .PHONY, pattern rules and names built from variables are left out
+		if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$") {
+			continue
+		}
+		if p.firstTarget == "" {
+			p.firstTarget = name
+		}
+		if t := p.byName[name]; t != nil {
+			// Rules for the same target add to its prerequisites
+			for _, dependency := range dependencies {
+				if !slices.Contains(t.Dependencies, dependency) {
+					t.Dependencies = append(t.Dependencies, dependency)
+				}
+			}
+			t.Description = cmp.Or(t.Description, description)
+			continue
+		}
+		t := &Target{Name: name, Description: description, Dependencies: slices.Clone(dependencies), Line: number}
+		if !main {
+			t.File = file
+		}
+		p.byName[name] = t
+		p.targets = append(p.targets, t)
+	}
+}
+
+// include reads the files named by an include directive
+func (p *makeParser) include(names string) {
+	for name := range strings.FieldsSeq(names) {
+		if strings.Contains(name, "$") {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(p.dir, name))
+		for _, match := range matches {
+			rel, err := filepath.Rel(p.dir, match)
+			rel = filepath.ToSlash(rel)
+			if err != nil || !inside(rel) || p.seen[rel] || len(p.included) >= maxIncludedFiles || security.CheckFileAccess(match) != nil {
+				continue
+			}
+			p.included = append(p.included, rel)
+			// Missing and unreadable includes are skipped, as -include does
+			_ = p.parse(rel, false)
+		}
+	}
+}
+
+// commentText joins the comment lines above a target into a description, leaving out section
+// headers such as "##@ Build" and lines of only #
+func commentText(lines []string) string {
+	var words []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "##@") {
+			continue
+		}
+		if text := strings.TrimSpace(strings.TrimLeft(line, "#")); text != "" {
+			words = append(words, text)
+		}
+	}
+	return strings.Join(words, " ")
+}
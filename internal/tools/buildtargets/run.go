@@ -0,0 +1,249 @@
+package buildtargets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/procgroup"
+)
+
+// maxLineLength is the longest line of output kept. Longer lines are cut.
+const maxLineLength = 1000
+
+// ansiPattern matches terminal colour and cursor escape sequences
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// system is a build tool whose files can be listed and whose targets can be run
+type system struct {
+	name     string
+	files    []string
+	binaries []string
+	parse    func(dir, file string) (BuildFile, error)
+	// command returns the arguments that run a target with variables and extra arguments
+	command func(file, target string, variables, arguments []string) []string
+}
+
+// systems are the supported build tools, in the order they are listed
+var systems = []system{
+	{
+		name:     "make",
+		files:    makeFiles,
+		binaries: []string{"make"},
+		parse:    parseMakefile,
+		command: func(file, target string, variables, _ []string) []string {
+			return append([]string{"-f", file, target}, variables...)
+		},
+	},
+	{
+		name:  "task",
+		files: taskFiles,
+		// Some package managers install Task as go-task
+		binaries: []string{"task", "go-task"},
+		parse:    parseTaskfile,
+		command: func(file, target string, variables, arguments []string) []string {
+			args := append([]string{"--taskfile", file, target}, variables...)
+			// Task passes what follows -- to the task as CLI_ARGS
+			if len(arguments) > 0 {
+				args = append(append(args, "--"), arguments...)
+			}
+			return args
+		},
+	},
+	{
+		name:     "just",
+		files:    justFiles,
+		binaries: []string{"just"},
+		parse:    parseJustfile,
+		command: func(file, target string, variables, arguments []string) []string {
+			// Variable overrides come before the recipe, and its arguments after it
+			args := append(append([]string{"--justfile", file}, variables...), target)
+			return append(args, arguments...)
+		},
+	},
+}
+
+// systemNames returns the names of the supported build tools
+func systemNames() []string {
+	var names []string
+	for _, s := range systems {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// systemFor returns the build tool a file name belongs to
+func systemFor(name string) (system, bool) {
+	for _, s := range systems {
+		if slices.Contains(s.files, name) {
+			return s, true
+		}
+	}
+	return system{}, false
+}
+
+// systemNamed returns the build tool with a supported name
+func systemNamed(name string) system {
+	return systems[slices.Index(systemNames(), name)]
+}
+
+// runner returns the path of the build tool's binary, or "" when it is not installed
+func (s system) runner() string {
+	for _, binary := range s.binaries {
+		if path, err := exec.LookPath(binary); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// findFile returns the first of names that is in dir. Names are compared exactly, so the result is
+// the file's real name on case-insensitive file systems.
+func findFile(dir string, names []string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	present := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			present[entry.Name()] = true
+		}
+	}
+	for _, name := range names {
+		if present[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// inside reports whether a slash-separated path relative to the project directory stays inside it
+func inside(rel string) bool {
+	return rel != ".." && !strings.HasPrefix(rel, "../") && !strings.HasPrefix(rel, "/")
+}
+
+// execute runs a build tool in dir, keeping at most maxLines lines of its combined output
+func execute(ctx context.Context, dir, binary string, args []string, timeout time.Duration, maxLines int) (RunResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "NO_COLOR=1")
+	// Targets start compilers, servers and test runners, which must stop with the run
+	procgroup.Set(cmd)
+	output := newLineBuffer(maxLines)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return RunResult{}, fmt.Errorf("failed to start %s: %w", binary, err)
+	}
+	runErr := cmd.Wait()
+
+	output.flush()
+	result := RunResult{
+		Command:     strings.Join(append([]string{binary}, args...), " "),
+		Duration:    time.Since(start).Round(time.Millisecond).String(),
+		Output:      output.String(),
+		OutputLines: output.total,
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.TimedOut, result.ExitCode = true, -1
+		return result, nil
+	}
+	if err := context.Cause(ctx); err != nil {
+		return result, err
+	}
+	if runErr != nil {
+		exitErr, ok := errors.AsType[*exec.ExitError](runErr)
+		if !ok {
+			return result, fmt.Errorf("failed to run %s: %w", binary, runErr)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Succeeded = result.ExitCode == 0
+	return result, nil
+}
+
+// lineBuffer keeps the first and last lines written to it, counting those dropped in between, so
+// that both the start of a build and its final error survive when it prints a lot
+type lineBuffer struct {
+	partial []byte
+	head    []string
+	tail    []string
+	headMax int
+	tailMax int
+	dropped int
+	total   int
+}
+
+// newLineBuffer returns a buffer keeping at most maxLines lines: a third from the start and the
+// rest from the end
+func newLineBuffer(maxLines int) *lineBuffer {
+	headMax := max(1, maxLines/3)
+	return &lineBuffer{headMax: headMax, tailMax: max(1, maxLines-headMax)}
+}
+
+// Write records the complete lines in p and holds back a trailing partial line
+func (b *lineBuffer) Write(p []byte) (int, error) {
+	b.partial = append(b.partial, p...)
+	for {
+		i := slices.Index(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		b.add(string(b.partial[:i]))
+		b.partial = b.partial[i+1:]
+	}
+	// A command that never ends its line still cannot hold unlimited output
+	if len(b.partial) > 4*maxLineLength {
+		b.add(string(b.partial))
+		b.partial = nil
+	}
+	return len(p), nil
+}
+
+// flush records a trailing partial line
+func (b *lineBuffer) flush() {
+	if len(b.partial) > 0 {
+		b.add(string(b.partial))
+		b.partial = nil
+	}
+}
+
+// add records a line without colour codes, cut to maxLineLength
+func (b *lineBuffer) add(line string) {
+	line = ansiPattern.ReplaceAllString(strings.TrimRight(line, "\r"), "")
+	if len(line) > maxLineLength {
+		line = line[:maxLineLength] + " ..."
+	}
+	line = strings.ToValidUTF8(line, "�")
+	b.total++
+	if len(b.head) < b.headMax {
+		b.head = append(b.head, line)
+		return
+	}
+	b.tail = append(b.tail, line)
+	if len(b.tail) > b.tailMax {
+		b.tail = b.tail[1:]
+		b.dropped++
+	}
+}
+
+// String joins the recorded lines, marking where lines were dropped
+func (b *lineBuffer) String() string {
+	lines := slices.Clone(b.head)
+	if b.dropped > 0 {
+		lines = append(lines, fmt.Sprintf("... [%d lines omitted] ...", b.dropped))
+	}
+	return strings.Join(append(lines, b.tail...), "\n")
+}
@@ -0,0 +1,185 @@
+package buildtargets
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth is how deeply Taskfile includes are followed
+const maxIncludeDepth = 3
+
+// taskFiles are the names Task looks for, in the order it looks
+var taskFiles = []string{"Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml", "Taskfile.dist.yml", "taskfile.dist.yml", "Taskfile.dist.yaml", "taskfile.dist.yaml"}
+
+// taskParser collects tasks from a Taskfile and the Taskfiles it includes
+type taskParser struct {
+	dir      string
+	targets  []Target
+	included []string
+	seen     map[string]bool
+}
+
+// parseTaskfile reads the tasks of a Taskfile. Included Taskfiles inside dir are read too, with
+// their tasks named by namespace as Task names them.
+func parseTaskfile(dir, file string) (BuildFile, error) {
+	p := &taskParser{dir: dir, seen: map[string]bool{}}
+	if err := p.parse(file, "", 0); err != nil {
+		return BuildFile{}, err
+	}
+	result := BuildFile{System: "task", File: file, Includes: p.included, Targets: p.targets}
+	if result.Targets == nil {
+		result.Targets = []Target{}
+	}
+	for _, t := range result.Targets {
+		if t.Name == "default" {
+			result.Default = "default"
+		}
+	}
+	return result, nil
+}
+
+// parse reads one Taskfile's tasks, naming them with prefix
+func (p *taskParser) parse(file, prefix string, depth int) error {
+	p.seen[file] = true
+	data, err := os.ReadFile(filepath.Join(p.dir, file))
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	root := doc.Content[0]
+
+	if tasks := mappingValue(root, "tasks"); tasks != nil && tasks.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(tasks.Content); i += 2 {
+			if t, ok := taskTarget(tasks.Content[i], tasks.Content[i+1], prefix); ok {
+				if depth > 0 {
+					t.File = file
+				}
+				p.targets = append(p.targets, t)
+			}
+		}
+	}
+
+	includes := mappingValue(root, "includes")
+	if includes == nil || includes.Kind != yaml.MappingNode || depth >= maxIncludeDepth {
+		return nil
+	}
+	for i := 0; i+1 < len(includes.Content); i += 2 {
+		namespace, value := includes.Content[i].Value, includes.Content[i+1]
+		taskfile := value.Value
+		nested := prefix + namespace + ":"
+		if value.Kind == yaml.MappingNode {
+			taskfile = scalarValue(value, "taskfile")
+			if scalarValue(value, "internal") == "true" {
+				continue
+			}
+			if scalarValue(value, "flatten") == "true" {
+				nested = prefix
+			}
+		}
+		// Paths built from variables and remote Taskfiles are not followed
+		if taskfile == "" || strings.Contains(taskfile, "{{") || strings.Contains(taskfile, "://") {
+			continue
+		}
+		included := p.resolve(path.Join(path.Dir(file), filepath.ToSlash(taskfile)))
+		if included == "" || p.seen[included] {
+			continue
+		}
+		p.included = append(p.included, included)
+		// Optional and unreadable includes are skipped
+		_ = p.parse(included, nested, depth+1)
+	}
+	return nil
+}
+
+// resolve returns the Taskfile an include refers to, relative to dir, or "" when it is outside dir
+// or cannot be read. A directory refers to the Taskfile inside it.
+func (p *taskParser) resolve(rel string) string {
+	if !inside(rel) {
+		return ""
+	}
+	full := filepath.Join(p.dir, filepath.FromSlash(rel))
+	if info, err := os.Stat(full); err == nil && info.IsDir() {
+		name := findFile(full, taskFiles)
+		if name == "" {
+			return ""
+		}
+		rel, full = path.Join(rel, name), filepath.Join(full, name)
+	}
+	if security.CheckFileAccess(full) != nil {
+		return ""
+	}
+	return rel
+}
+
+// taskTarget describes a task. Internal tasks, which cannot be run by name, are left out.
+func taskTarget(key, value *yaml.Node, prefix string) (Target, bool) {
+	t := Target{Name: prefix + key.Value, Line: key.Line}
+	// A task can be a single command or a list of commands
+	if value.Kind != yaml.MappingNode {
+		return t, true
+	}
+	if scalarValue(value, "internal") == "true" {
+		return t, false
+	}
+	t.Description = scalarValue(value, "desc")
+	if t.Description == "" {
+		summary, _, _ := strings.Cut(strings.TrimSpace(scalarValue(value, "summary")), "\n")
+		t.Description = summary
+	}
+	if deps := mappingValue(value, "deps"); deps != nil && deps.Kind == yaml.SequenceNode {
+		for _, dep := range deps.Content {
+			name := dep.Value
+			if dep.Kind == yaml.MappingNode {
+				name = scalarValue(dep, "task")
+			}
+			if name != "" {
+				t.Dependencies = append(t.Dependencies, taskName(name, prefix))
+			}
+		}
+	}
+	if aliases := mappingValue(value, "aliases"); aliases != nil && aliases.Kind == yaml.SequenceNode {
+		for _, alias := range aliases.Content {
+			t.Aliases = append(t.Aliases, prefix+alias.Value)
+		}
+	}
+	return t, true
+}
+
+// taskName names a dependency as it would be run from the root Taskfile. A leading colon refers
+// to a root task; other names are in the including Taskfile's namespace.
+func taskName(name, prefix string) string {
+	if rest, ok := strings.CutPrefix(name, ":"); ok {
+		return rest
+	}
+	return prefix + name
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the value of key in a mapping node when it is a scalar
+func scalarValue(node *yaml.Node, key string) string {
+	if value := mappingValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package buildtargets
+
+// ListResult lists the targets of a project's build files
+type ListResult struct {
+	Dir        string      `json:"dir"`
+	BuildFiles []BuildFile `json:"build_files"`
+	// RunEnabled reports whether the server allows targets to be run
+	RunEnabled bool   `json:"run_enabled"`
+	Note       string `json:"note,omitempty"`
+}
+
+// BuildFile is a Makefile, Taskfile or justfile and the targets it defines
+type BuildFile struct {
+	// System is make, task or just
+	System string `json:"system"`
+	File   string `json:"file"`
+	// Runner is the path of the make, task or just binary, empty when it is not installed
+	Runner  string `json:"runner,omitempty"`
+	Default string `json:"default_target,omitempty"`
+	// Includes lists the other files targets were read from
+	Includes []string `json:"includes,omitempty"`
+	Targets  []Target `json:"targets"`
+}
+
+// Target is a make target, task or just recipe
+type Target struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Parameters are a just recipe's parameters, with their defaults
+	Parameters []string `json:"parameters,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+	// File is set when the target is defined in an included file
+	File string `json:"file,omitempty"`
+	Line int    `json:"line"`
+}
+
+// RunResult is the outcome of running a target
+type RunResult struct {
+	System    string `json:"system"`
+	Target    string `json:"target"`
+	Command   string `json:"command"`
+	Succeeded bool   `json:"succeeded"`
+	ExitCode  int    `json:"exit_code"`
+	TimedOut  bool   `json:"timed_out,omitempty"`
+	Duration  string `json:"duration"`
+	// Output is the command's combined stdout and stderr, with lines from the middle left out when
+	// there are more than max_output_lines
+	Output      string `json:"output"`
+	OutputLines int    `json:"output_lines"`
+}
@@ -0,0 +1,309 @@
+// Package calc implements the calc tool, which evaluates expressions exactly with units, currencies and
+// dates. It is kept apart from the default calculator tool, whose float results and short description
+// clients already rely on. docs/tools/calc.md explains the split.
+package calc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPrecision is how many decimal places results are rounded to by default
+	defaultPrecision = 10
+
+	// maxPrecision is the most decimal places a result can be shown with
+	maxPrecision = 50
+
+	// maxExpressionLength is the longest expression accepted
+	maxExpressionLength = 1000
+)
+
+// CalcTool evaluates expressions exactly, with units, currencies and dates, for engineering estimates
+// and capacity planning
+type CalcTool struct{}
+
+// Result is the result of evaluating one expression
+type Result struct {
+	Expression  string `json:"expression"`
+	Result      string `json:"result"`
+	Value       string `json:"value"`
+	Unit        string `json:"unit,omitempty"`
+	Approximate bool   `json:"approximate,omitempty"`
+}
+
+// init registers the calc tool
+func init() {
+	registry.Register(&CalcTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (c *CalcTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"calc",
+		mcp.WithDescription(`Exact decimal calculator with units and dates, for estimates and capacity planning. Supports + - * / % ^ and parentheses; byte sizes (MB, GiB, Mbit), durations (ms, h, days, months), currency codes (static approximate rates, or live rates once the finance tool has fetched them), dates (2026-03-01, today, now), and conversion with 'to', e.g. '1.5 TB / 100 Mbps to h', 'today + 90 days', '250 EUR to AUD'.`),
+		mcp.WithString("expression",
+			mcp.Description("Single expression to evaluate, e.g. '3 * 512 GiB to TB'"),
+		),
+		mcp.WithArray("expressions",
+			mcp.Description("Array of expressions to evaluate"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("precision",
+			mcp.Description(fmt.Sprintf("Decimal places to round results to (default: %d)", defaultPrecision)),
+		),
+		// Read-only annotations for pure computation tool
+		mcp.WithReadOnlyHintAnnotation(true),     // Doesn't modify environment
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(false),  // 'today' and 'now' change over time
+		mcp.WithOpenWorldHintAnnotation(false),   // Static or already fetched currency rates, no external interactions
+	)
+}
+
+// Execute evaluates one or more expressions
+func (c *CalcTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	precision := defaultPrecision
+	if precisionRaw, ok := args["precision"]; ok {
+		value, ok := precisionRaw.(float64)
+		if !ok || value < 0 || value > maxPrecision || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'precision' parameter: must be a whole number from 0 to %d", maxPrecision)
+		}
+		precision = int(value)
+	}
+
+	var expressions []string
+	single := false
+	if expressionRaw, ok := args["expression"]; ok {
+		expression, ok := expressionRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'expression' parameter: must be a string. Example: {\"expression\": \"1.5 TB / 100 Mbps to h\"}")
+		}
+		expressions = []string{expression}
+		single = true
+	} else if expressionsRaw, ok := args["expressions"]; ok {
+		items, ok := expressionsRaw.([]any)
+		if !ok || len(items) == 0 {
+			return nil, fmt.Errorf("invalid 'expressions' parameter: must be a non-empty array of strings. Example: {\"expressions\": [\"3 * 512 GiB to TB\", \"today + 90 days\"]}")
+		}
+		for i, item := range items {
+			expression, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid expression at index %d: must be a string", i)
+			}
+			expressions = append(expressions, expression)
+		}
+	} else {
+		return nil, fmt.Errorf("missing required parameter. Provide either 'expression' (e.g., {\"expression\": \"2 GiB to MB\"}) or 'expressions' (e.g., {\"expressions\": [\"2 GiB to MB\", \"today + 2 weeks\"]})")
+	}
+
+	logger.WithField("expressions", len(expressions)).Debug("Executing calc")
+
+	results := make([]Result, 0, len(expressions))
+	var rates rateUse
+	for i, expression := range expressions {
+		result, used, err := evaluateExpression(expression, precision)
+		if err != nil {
+			if single {
+				return nil, err
+			}
+			return nil, fmt.Errorf("error in expression %d: %w", i, err)
+		}
+		rates.merge(used)
+		results = append(results, result)
+	}
+
+	note := rates.note()
+
+	var response any
+	if single {
+		response = struct {
+			Result
+			Note string `json:"note,omitempty"`
+		}{results[0], note}
+	} else {
+		response = struct {
+			Results []Result `json:"results"`
+			Note    string   `json:"note,omitempty"`
+		}{results, note}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// evaluateExpression evaluates an expression and formats its result, reporting which currency rates it
+// used
+func evaluateExpression(expression string, precision int) (Result, rateUse, error) {
+	result := Result{Expression: expression}
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return result, rateUse{}, fmt.Errorf("expression cannot be empty. Provide an expression such as \"2 GiB to MB\"")
+	}
+	if len(expression) > maxExpressionLength {
+		return result, rateUse{}, fmt.Errorf("expression exceeds maximum length of %d characters", maxExpressionLength)
+	}
+
+	p := newParser(expression)
+	v, err := p.evaluate()
+	if err != nil {
+		return result, rateUse{}, fmt.Errorf("failed to evaluate expression '%s': %w", expression, err)
+	}
+
+	if v.isDate {
+		result.Value = formatDate(v.date)
+		result.Result = fmt.Sprintf("%s (%s)", result.Value, v.date.Weekday())
+		return result, p.rates, nil
+	}
+
+	display := displayUnit(v)
+	number, exact := formatNumber(new(big.Rat).Quo(v.num, display.scale), precision)
+	result.Value = number
+	result.Result = number
+	if display.symbol != "" {
+		result.Unit = display.symbol
+		result.Result = number + " " + display.symbol
+	}
+	result.Approximate = !exact || p.approximate
+	return result, p.rates, nil
+}
+
+// displayUnit returns the unit a result is shown in: the unit it was converted to or written in, or
+// else the largest unit it is at least one of
+func displayUnit(v value) unit {
+	if v.hint != nil {
+		return *v.hint
+	}
+	if v.dims == dimensionless {
+		return unit{scale: big.NewRat(1, 1)}
+	}
+
+	candidates := autoUnits[v.dims]
+	for i, symbol := range candidates {
+		u, err := parseUnit(symbol)
+		if err != nil {
+			continue
+		}
+		magnitude := new(big.Rat).Abs(new(big.Rat).Quo(v.num, u.scale))
+		if magnitude.Cmp(big.NewRat(1, 1)) >= 0 || i == len(candidates)-1 {
+			return u
+		}
+	}
+	return unit{symbol: baseUnitSymbol(v.dims), scale: big.NewRat(1, 1)}
+}
+
+// formatNumber formats a number in decimal, exactly if it fits in precision decimal places and
+// rounded otherwise, reporting whether it is exact
+func formatNumber(r *big.Rat, precision int) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+
+	// A fraction has a finite decimal expansion if its denominator has no prime factors but 2 and 5
+	denom := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		divisor := big.NewInt(factor)
+		remainder := new(big.Int)
+		for {
+			quotient, rem := new(big.Int).QuoRem(denom, divisor, remainder)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = quotient
+			count++
+		}
+		places = max(places, count)
+	}
+	if denom.Cmp(big.NewInt(1)) == 0 && places <= precision {
+		return r.FloatString(places), true
+	}
+
+	text := r.FloatString(precision)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	if text == "-0" {
+		text = "0"
+	}
+	return text, false
+}
+
+// formatDate formats a date, leaving out the time at midnight UTC
+func formatDate(date time.Time) string {
+	if _, offset := date.Zone(); offset == 0 && date.Equal(date.Truncate(24*time.Hour)) {
+		return date.Format(time.DateOnly)
+	}
+	return date.Format(time.RFC3339)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the calc tool
+func (c *CalcTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Engineering estimates and capacity planning: storage and transfer sizes, transfer times, durations, costs in different currencies and deadlines. Results are exact, so 0.1 + 0.2 is 0.3.",
+		WhenNotToUse: "Current exchange rates (the rates are static approximations unless the finance tool has fetched live ones), scientific functions (sqrt, log, trigonometry), or units other than bytes, bits, durations and currencies.",
+		CommonPatterns: []string{
+			"Convert with 'to': \"1536 MiB to GiB\", \"90 min to h\"",
+			"Transfer time: \"500 GB / 1 Gbps to min\"",
+			"Storage growth: \"120 GB * 1.05^12\"",
+			"Monthly cost: \"0.023 USD/GB * 40 TB to USD\"",
+			"Deadlines: \"2026-03-01 + 6 weeks\", \"today + 3 months\"",
+			"Time between dates: \"2026-12-25 - today\"",
+			"Batch related estimates with 'expressions'",
+		},
+		ParameterDetails: map[string]string{
+			"expression":  "One expression. Numbers can be followed by a unit, and an expression can end with 'to <unit>' to choose the unit of the result.",
+			"expressions": "Array of expressions evaluated independently.",
+			"precision":   "Decimal places inexact results are rounded to. Results that fit are shown exactly, and 'approximate' is true when a result was rounded.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "How long a backup takes to copy over a link",
+				Arguments:      map[string]any{"expression": "1.5 TB / 100 Mbps to h"},
+				ExpectedResult: `{"expression": "1.5 TB / 100 Mbps to h", "result": "33.3333333333 h", "value": "33.3333333333", "unit": "h", "approximate": true}`,
+			},
+			{
+				Description:    "Disk needed for three replicas",
+				Arguments:      map[string]any{"expression": "3 * 512 GiB to TB"},
+				ExpectedResult: `{"expression": "3 * 512 GiB to TB", "result": "1.6492674417 TB", "value": "1.6492674417", "unit": "TB", "approximate": true}`,
+			},
+			{
+				Description:    "A date 90 days from a start date",
+				Arguments:      map[string]any{"expression": "2026-03-01 + 90 days"},
+				ExpectedResult: `{"expression": "2026-03-01 + 90 days", "result": "2026-05-30 (Saturday)", "value": "2026-05-30"}`,
+			},
+			{
+				Description:    "Convert a price between currencies",
+				Arguments:      map[string]any{"expression": "250 EUR to AUD"},
+				ExpectedResult: "The price in AUD, with a note that the rates are static approximations.",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "'unknown unit or name' error",
+				Solution: "Only byte and bit sizes, durations and currency codes are supported. Byte units ignore case, so MB and Mb are both megabytes - use Mbit or Mbps for bits.",
+			},
+			{
+				Problem:  "'they measure different things' error",
+				Solution: "Values being added or converted must measure the same thing, e.g. bytes with bytes. Divide a size by a rate to get a time.",
+			},
+			{
+				Problem:  "A date minus a number was read as arithmetic",
+				Solution: "YYYY-MM-DD is always read as a date. Put spaces around subtraction between plain numbers.",
+			},
+		},
+	}
+}
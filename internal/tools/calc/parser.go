@@ -0,0 +1,630 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxExponent is the largest power a number can be raised to, or written with in scientific notation
+	maxExponent = 1000
+
+	// maxResultBits is the largest numerator and denominator a power can produce, to keep exact
+	// results to a reasonable size
+	maxResultBits = 100_000
+)
+
+// dateLiteral matches ISO 8601 dates with an optional time, e.g. 2026-03-01 or 2026-03-01T09:30:00Z
+var dateLiteral = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}(?::\d{2})?(?:Z|[+-]\d{2}:\d{2})?)?`)
+
+// tokenKind is the kind of a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokDate
+	tokOp
+)
+
+// token is a lexical token of an expression
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// value is the result of evaluating part of an expression: a quantity in base units, or a date
+type value struct {
+	num    *big.Rat
+	dims   dims
+	isDate bool
+	date   time.Time
+	// hint is the unit the quantity is shown in, if the expression gives one
+	hint *unit
+}
+
+// parser evaluates an expression as it parses it
+type parser struct {
+	input string
+	pos   int
+	tok   token
+	now   time.Time
+	// rates records which currency rates the expression uses
+	rates rateUse
+	// approximate records whether a step could not be calculated exactly
+	approximate bool
+}
+
+// newParser creates a parser for an expression
+func newParser(input string) *parser {
+	return &parser{input: input, now: time.Now().UTC()}
+}
+
+// next reads the next token
+func (p *parser) next() error {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokEOF}
+		return nil
+	}
+
+	rest := p.input[p.pos:]
+	r, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case unicode.IsDigit(r) || (r == '.' && len(rest) > 1 && unicode.IsDigit(rune(rest[1]))):
+		if date := dateLiteral.FindString(rest); date != "" {
+			p.tok = token{kind: tokDate, text: date}
+			p.pos += len(date)
+			return nil
+		}
+		end := scanNumber(rest)
+		p.tok = token{kind: tokNumber, text: rest[:end]}
+		p.pos += end
+	case unicode.IsLetter(r) || r == 'µ':
+		end := 0
+		for end < len(rest) {
+			r, size := utf8.DecodeRuneInString(rest[end:])
+			if !unicode.IsLetter(r) && r != 'µ' {
+				break
+			}
+			end += size
+		}
+		p.tok = token{kind: tokIdent, text: rest[:end]}
+		p.pos += end
+	case strings.ContainsRune("+-*/%^()", r):
+		p.tok = token{kind: tokOp, text: string(r)}
+		p.pos += size
+	default:
+		return fmt.Errorf("unexpected character '%c' at position %d", r, p.pos+1)
+	}
+	return nil
+}
+
+// scanNumber returns the length of the number at the start of text, e.g. 1_000, 2.5 or 1.5e6
+func scanNumber(text string) int {
+	end := 0
+	for end < len(text) && (isDigit(text[end]) || text[end] == '_' || text[end] == '.') {
+		end++
+	}
+	// An exponent must be followed by digits, so 2EB is two exabytes rather than a malformed number
+	if end < len(text) && (text[end] == 'e' || text[end] == 'E') {
+		exp := end + 1
+		if exp < len(text) && (text[exp] == '+' || text[exp] == '-') {
+			exp++
+		}
+		if exp < len(text) && isDigit(text[exp]) {
+			for exp < len(text) && isDigit(text[exp]) {
+				exp++
+			}
+			end = exp
+		}
+	}
+	return end
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// isOp reports whether the current token is the operator op
+func (p *parser) isOp(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+// isKeyword reports whether the current token is one of the given words, ignoring case
+func (p *parser) isKeyword(words ...string) bool {
+	if p.tok.kind != tokIdent {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(p.tok.text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate evaluates a complete expression, with an optional conversion such as "to GiB"
+func (p *parser) evaluate() (value, error) {
+	if err := p.next(); err != nil {
+		return value{}, err
+	}
+	result, err := p.parseExpression()
+	if err != nil {
+		return result, err
+	}
+
+	if p.isKeyword("to", "in", "as") {
+		if err := p.next(); err != nil {
+			return result, err
+		}
+		target, ok, err := p.parseUnitExpr()
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			return result, fmt.Errorf("expected a unit after 'to', e.g. '1536 MiB to GiB'")
+		}
+		if result.isDate {
+			return result, fmt.Errorf("cannot convert a date to %s", target.symbol)
+		}
+		if result.dims != target.dims {
+			return result, fmt.Errorf("cannot convert %s to %s: they measure different things", describe(result), target.symbol)
+		}
+		result.hint = &target
+	}
+
+	if p.tok.kind != tokEOF {
+		return result, fmt.Errorf("unexpected '%s' at position %d. Check for typos, unknown units or missing operators", p.tok.text, p.pos-len(p.tok.text)+1)
+	}
+	return result, nil
+}
+
+// parseExpression parses addition and subtraction
+func (p *parser) parseExpression() (value, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return left, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		subtract := p.isOp("-")
+		if err := p.next(); err != nil {
+			return left, err
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return left, err
+		}
+		if left, err = add(left, right, subtract); err != nil {
+			return left, err
+		}
+	}
+	return left, nil
+}
+
+// parseTerm parses multiplication, division and remainders
+func (p *parser) parseTerm() (value, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return left, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return left, err
+		}
+		switch op {
+		case "*":
+			left, err = multiply(left, right, false)
+		case "/":
+			left, err = multiply(left, right, true)
+		default:
+			left, err = remainder(left, right)
+		}
+		if err != nil {
+			return left, err
+		}
+	}
+	return left, nil
+}
+
+// parseUnary parses unary plus and minus
+func (p *parser) parseUnary() (value, error) {
+	if p.isOp("-") || p.isOp("+") {
+		negate := p.isOp("-")
+		if err := p.next(); err != nil {
+			return value{}, err
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return operand, err
+		}
+		if operand.isDate {
+			return operand, fmt.Errorf("a date cannot be negated")
+		}
+		if negate {
+			operand.num = new(big.Rat).Neg(operand.num)
+		}
+		return operand, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses exponentiation, which is right-associative: 2^3^2 is 2^9
+func (p *parser) parsePower() (value, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return base, err
+	}
+	if !p.isOp("^") {
+		return base, nil
+	}
+	if err := p.next(); err != nil {
+		return base, err
+	}
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return base, err
+	}
+	return p.power(base, exponent)
+}
+
+// parsePrimary parses numbers with optional units, bare units, dates and parentheses
+func (p *parser) parsePrimary() (value, error) {
+	switch p.tok.kind {
+	case tokNumber:
+		num, err := parseNumber(p.tok.text)
+		if err != nil {
+			return value{}, err
+		}
+		if err := p.next(); err != nil {
+			return value{}, err
+		}
+		return p.applyUnit(value{num: num})
+
+	case tokDate:
+		date, err := parseDate(p.tok.text)
+		if err != nil {
+			return value{}, err
+		}
+		if err := p.next(); err != nil {
+			return value{}, err
+		}
+		return value{isDate: true, date: date}, nil
+
+	case tokIdent:
+		switch {
+		case p.isKeyword("today"):
+			if err := p.next(); err != nil {
+				return value{}, err
+			}
+			return value{isDate: true, date: p.now.Truncate(24 * time.Hour)}, nil
+		case p.isKeyword("now"):
+			if err := p.next(); err != nil {
+				return value{}, err
+			}
+			return value{isDate: true, date: p.now}, nil
+		}
+		result, err := p.applyUnit(value{num: big.NewRat(1, 1)})
+		if err == nil && result.hint == nil {
+			err = fmt.Errorf("unknown unit or name '%s'. Supported: byte and bit sizes, durations, currency codes, 'today' and 'now'", p.tok.text)
+		}
+		return result, err
+
+	case tokOp:
+		if p.isOp("(") {
+			if err := p.next(); err != nil {
+				return value{}, err
+			}
+			inner, err := p.parseExpression()
+			if err != nil {
+				return inner, err
+			}
+			if !p.isOp(")") {
+				return inner, fmt.Errorf("expected closing parenthesis ')' - check that all opening '(' have matching closing ')'")
+			}
+			if err := p.next(); err != nil {
+				return inner, err
+			}
+			if inner.isDate {
+				return inner, nil
+			}
+			return p.applyUnit(inner)
+		}
+	}
+
+	if p.tok.kind == tokEOF {
+		return value{}, fmt.Errorf("unexpected end of expression")
+	}
+	return value{}, fmt.Errorf("unexpected '%s' at position %d", p.tok.text, p.pos-len(p.tok.text)+1)
+}
+
+// applyUnit multiplies a number by the unit that follows it, if there is one
+func (p *parser) applyUnit(v value) (value, error) {
+	u, ok, err := p.parseUnitExpr()
+	if err != nil || !ok {
+		return v, err
+	}
+	if v.dims != dimensionless {
+		return v, fmt.Errorf("unit %s follows a value that already has a unit", u.symbol)
+	}
+	return value{num: new(big.Rat).Mul(v.num, u.scale), dims: u.dims, hint: &u}, nil
+}
+
+// parseUnitExpr parses a unit such as GiB, MB/s or USD per month, returning false if the current
+// token is not a unit. A / or * only continues the unit when a unit follows it, so 10 GB / 2 is a
+// division.
+func (p *parser) parseUnitExpr() (unit, bool, error) {
+	u, ok := p.lookupUnit()
+	if !ok {
+		return unit{}, false, nil
+	}
+	if err := p.next(); err != nil {
+		return u, true, err
+	}
+
+	for p.isOp("/") || p.isOp("*") || p.isKeyword("per") {
+		divide := !p.isOp("*")
+		savedPos, savedTok := p.pos, p.tok
+		if err := p.next(); err != nil {
+			return u, true, err
+		}
+		next, ok := p.lookupUnit()
+		if !ok {
+			p.pos, p.tok = savedPos, savedTok
+			break
+		}
+		u = u.combine(next, divide)
+		if err := p.next(); err != nil {
+			return u, true, err
+		}
+	}
+	return u, true, nil
+}
+
+// lookupUnit returns the unit named by the current token
+func (p *parser) lookupUnit() (unit, bool) {
+	if p.tok.kind != tokIdent {
+		return unit{}, false
+	}
+	u, ok := units[strings.ToLower(p.tok.text)]
+	if ok && isCurrency(u.symbol) || !ok && isCurrencyCode(p.tok.text) {
+		if live, source, found := liveCurrencyUnit(strings.ToUpper(p.tok.text)); found {
+			p.rates.addLive(source)
+			return live, true
+		}
+		// The US dollar is the base unit of money, so it needs no rate
+		p.rates.static = p.rates.static || ok && u.symbol != baseSymbols[dimMoney]
+	}
+	return u, ok
+}
+
+// parseNumber parses a decimal number exactly, e.g. 1_000, 0.1 or 1.5e6
+func parseNumber(text string) (*big.Rat, error) {
+	clean := strings.ReplaceAll(text, "_", "")
+	if i := strings.IndexAny(clean, "eE"); i >= 0 {
+		if exp, err := strconv.Atoi(clean[i+1:]); err != nil || exp > maxExponent || exp < -maxExponent {
+			return nil, fmt.Errorf("exponent in '%s' is out of range (at most %d)", text, maxExponent)
+		}
+	}
+	num, ok := new(big.Rat).SetString(clean)
+	if !ok {
+		return nil, fmt.Errorf("invalid number '%s'", text)
+	}
+	return num, nil
+}
+
+// parseDate parses a date literal, in UTC unless it has an offset
+func parseDate(text string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04", time.DateOnly} {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s': use YYYY-MM-DD or YYYY-MM-DDTHH:MM:SSZ", text)
+}
+
+// add adds or subtracts two values, including moving a date by a duration and the time between dates
+func add(left, right value, subtract bool) (value, error) {
+	switch {
+	case left.isDate && right.isDate:
+		if !subtract {
+			return left, fmt.Errorf("cannot add two dates. Subtract them to get the time between them")
+		}
+		seconds := new(big.Rat).SetInt64(left.date.Unix() - right.date.Unix())
+		seconds.Add(seconds, big.NewRat(int64(left.date.Nanosecond()-right.date.Nanosecond()), 1_000_000_000))
+		return value{num: seconds, dims: dims{dimTime: 1}}, nil
+	case left.isDate:
+		return shiftDate(left, right, subtract)
+	case right.isDate:
+		if subtract {
+			return right, fmt.Errorf("cannot subtract a date from %s", describe(left))
+		}
+		return shiftDate(right, left, false)
+	}
+
+	if left.dims != right.dims {
+		return left, fmt.Errorf("cannot combine %s and %s: they measure different things", describe(left), describe(right))
+	}
+	result := value{num: new(big.Rat), dims: left.dims, hint: left.hint}
+	if result.hint == nil {
+		result.hint = right.hint
+	}
+	if subtract {
+		result.num.Sub(left.num, right.num)
+	} else {
+		result.num.Add(left.num, right.num)
+	}
+	return result, nil
+}
+
+// shiftDate moves a date by a duration. Whole months and years move by calendar months and years,
+// keeping the day of the month where the target month has it, so 2026-01-31 + 1 month is 2026-02-28.
+func shiftDate(date, duration value, backwards bool) (value, error) {
+	if duration.dims != (dims{dimTime: 1}) {
+		return date, fmt.Errorf("only durations can be added to or subtracted from a date, not %s", describe(duration))
+	}
+	sign := int64(1)
+	if backwards {
+		sign = -1
+	}
+
+	if duration.hint != nil && (duration.hint.symbol == "month" || duration.hint.symbol == "year") {
+		if count := new(big.Rat).Quo(duration.num, duration.hint.scale); count.IsInt() && count.Num().IsInt64() {
+			n := int(sign * count.Num().Int64())
+			if duration.hint.symbol == "year" {
+				n *= 12
+			}
+			return value{isDate: true, date: addMonths(date.date, n)}, nil
+		}
+	}
+
+	nanoseconds := new(big.Rat).Mul(duration.num, big.NewRat(sign*1_000_000_000, 1))
+	whole := new(big.Int).Quo(nanoseconds.Num(), nanoseconds.Denom())
+	if !whole.IsInt64() {
+		return date, fmt.Errorf("duration is too long to add to a date")
+	}
+	return value{isDate: true, date: date.date.Add(time.Duration(whole.Int64()))}, nil
+}
+
+// addMonths moves a date by calendar months. Unlike time.AddDate, which would carry 31 January into
+// March, a day past the end of the target month is clamped to its last day.
+func addMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	hour, minute, second := date.Clock()
+	// Day 0 of the following month is the last day of the target month
+	last := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, date.Location()).Day()
+	return time.Date(year, month+time.Month(months), min(day, last), hour, minute, second, date.Nanosecond(), date.Location())
+}
+
+// multiply multiplies or divides two values
+func multiply(left, right value, divide bool) (value, error) {
+	if left.isDate || right.isDate {
+		return left, fmt.Errorf("dates can only be added to, subtracted from or subtracted from each other")
+	}
+
+	result := value{num: new(big.Rat), dims: left.dims}
+	if divide {
+		if right.num.Sign() == 0 {
+			return left, fmt.Errorf("division by zero - cannot divide by zero")
+		}
+		result.num.Quo(left.num, right.num)
+		for i := range result.dims {
+			result.dims[i] -= right.dims[i]
+		}
+	} else {
+		result.num.Mul(left.num, right.num)
+		for i := range result.dims {
+			result.dims[i] += right.dims[i]
+		}
+	}
+
+	// Scaling a quantity keeps its unit, e.g. 3 * 2 GiB is 6 GiB
+	switch {
+	case right.dims == dimensionless:
+		result.hint = left.hint
+	case left.dims == dimensionless && !divide:
+		result.hint = right.hint
+	}
+	return result, nil
+}
+
+// remainder returns what is left of left after taking away whole multiples of right, e.g. 100 min %
+// 1 h is 40 min
+func remainder(left, right value) (value, error) {
+	if left.isDate || right.isDate {
+		return left, fmt.Errorf("dates can only be added to, subtracted from or subtracted from each other")
+	}
+	if left.dims != right.dims {
+		return left, fmt.Errorf("cannot take the remainder of %s by %s: they measure different things", describe(left), describe(right))
+	}
+	if right.num.Sign() == 0 {
+		return left, fmt.Errorf("modulo by zero - cannot calculate remainder when dividing by zero")
+	}
+
+	quotient := new(big.Rat).Quo(left.num, right.num)
+	floor := new(big.Int).Div(quotient.Num(), quotient.Denom())
+	result := new(big.Rat).Mul(right.num, new(big.Rat).SetInt(floor))
+	return value{num: result.Sub(left.num, result), dims: left.dims, hint: left.hint}, nil
+}
+
+// power raises a value to a power, exactly for whole exponents
+func (p *parser) power(base, exponent value) (value, error) {
+	if base.isDate || exponent.isDate {
+		return base, fmt.Errorf("dates cannot be raised to a power")
+	}
+	if exponent.dims != dimensionless {
+		return base, fmt.Errorf("an exponent cannot have a unit")
+	}
+
+	if exponent.num.IsInt() {
+		n := exponent.num.Num()
+		if !n.IsInt64() || n.Int64() > maxExponent || n.Int64() < -maxExponent {
+			return base, fmt.Errorf("exponent is out of range (at most %d)", maxExponent)
+		}
+		e := n.Int64()
+		if (base.num.Num().BitLen()+base.num.Denom().BitLen())*int(max(e, -e)) > maxResultBits {
+			return base, fmt.Errorf("result is too large to calculate exactly")
+		}
+		if e < 0 && base.num.Sign() == 0 {
+			return base, fmt.Errorf("division by zero - zero cannot be raised to a negative power")
+		}
+
+		abs := big.NewInt(max(e, -e))
+		num := new(big.Int).Exp(base.num.Num(), abs, nil)
+		denom := new(big.Int).Exp(base.num.Denom(), abs, nil)
+		if e < 0 {
+			num, denom = denom, num
+		}
+		result := value{num: new(big.Rat).SetFrac(num, denom)}
+		for i := range result.dims {
+			result.dims[i] = base.dims[i] * int(e)
+		}
+		if e == 1 {
+			result.hint = base.hint
+		}
+		return result, nil
+	}
+
+	// Fractional powers such as square roots are rarely exact, so are calculated in floating point
+	if base.dims != dimensionless {
+		return base, fmt.Errorf("a value with a unit can only be raised to a whole power")
+	}
+	b, _ := base.num.Float64()
+	e, _ := exponent.num.Float64()
+	result := math.Pow(b, e)
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return base, fmt.Errorf("%s ^ %s has no real result", base.num.FloatString(6), exponent.num.FloatString(6))
+	}
+	p.approximate = true
+	return value{num: new(big.Rat).SetFloat64(result)}, nil
+}
+
+// describe names what a value measures, for error messages
+func describe(v value) string {
+	switch {
+	case v.isDate:
+		return "a date"
+	case v.hint != nil:
+		return "a value in " + v.hint.symbol
+	case v.dims == dimensionless:
+		return "a plain number"
+	default:
+		return "a value in " + baseUnitSymbol(v.dims)
+	}
+}
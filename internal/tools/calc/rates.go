@@ -0,0 +1,106 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// liveRateMaxAge is how long published live rates are used in place of the static rates
+const liveRateMaxAge = 24 * time.Hour
+
+// liveRate is the US dollar value of one unit of a currency, published by another tool
+type liveRate struct {
+	usd         *big.Rat
+	source      string
+	publishedAt time.Time
+}
+
+// rateUse records which currency rates an expression used
+type rateUse struct {
+	static bool
+	// live are the sources of the live rates used
+	live []string
+}
+
+// addLive records the use of live rates from a source
+func (r *rateUse) addLive(source string) {
+	if !slices.Contains(r.live, source) {
+		r.live = append(r.live, source)
+	}
+}
+
+// merge adds the rates another expression used
+func (r *rateUse) merge(other rateUse) {
+	r.static = r.static || other.static
+	for _, source := range other.live {
+		r.addLive(source)
+	}
+}
+
+// note describes the rates used, for the result's note
+func (r rateUse) note() string {
+	var notes []string
+	if len(r.live) > 0 {
+		notes = append(notes, fmt.Sprintf("Currency conversions use live rates: %s.", strings.Join(r.live, "; ")))
+	}
+	switch {
+	case r.static && len(r.live) > 0:
+		notes = append(notes, fmt.Sprintf("Currencies without live rates use approximate static rates from %s.", currencyRatesDate))
+	case r.static:
+		notes = append(notes, fmt.Sprintf("Currency conversions use approximate static rates from %s, not live exchange rates.", currencyRatesDate))
+	}
+	return strings.Join(notes, " ")
+}
+
+var (
+	liveRatesMu sync.RWMutex
+	// liveRates are published live rates by currency code
+	liveRates = map[string]liveRate{}
+)
+
+// SetCurrencyRates publishes live exchange rates, as the US dollar value of one unit of each currency,
+// for calc to use in place of its static rates for the next 24 hours. source describes the rates in
+// calc's results, e.g. "Frankfurter (ECB) rates for 2026-10-16". The finance tool publishes the current
+// rates it fetches.
+func SetCurrencyRates(usdValues map[string]*big.Rat, source string) {
+	liveRatesMu.Lock()
+	defer liveRatesMu.Unlock()
+	now := time.Now()
+	for code, usd := range usdValues {
+		if usd == nil || usd.Sign() <= 0 {
+			continue
+		}
+		liveRates[strings.ToUpper(code)] = liveRate{usd: new(big.Rat).Set(usd), source: source, publishedAt: now}
+	}
+}
+
+// liveCurrencyUnit returns a currency as a unit at its live rate, if one was published within
+// liveRateMaxAge
+func liveCurrencyUnit(code string) (unit, string, bool) {
+	liveRatesMu.RLock()
+	rate, ok := liveRates[code]
+	liveRatesMu.RUnlock()
+	if !ok || time.Since(rate.publishedAt) > liveRateMaxAge {
+		return unit{}, "", false
+	}
+	return unit{symbol: code, scale: rate.usd, dims: dims{dimMoney: 1}}, rate.source, true
+}
+
+// isCurrencyCode reports whether text is written as an ISO 4217 currency code, e.g. THB. Only codes
+// written in capitals are looked up in the live rates, so that words such as "try" are not read as
+// currencies.
+func isCurrencyCode(text string) bool {
+	if len(text) != 3 {
+		return false
+	}
+	for _, r := range text {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
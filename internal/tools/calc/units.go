@@ -0,0 +1,192 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Dimension indexes. Quantities are held in base units: bytes, seconds and US dollars.
+const (
+	dimBytes = iota
+	dimTime
+	dimMoney
+	dimCount
+)
+
+// dims holds the exponent of each base dimension, e.g. bytes per second is {1, -1, 0}
+type dims [dimCount]int
+
+// dimensionless is the dimension of a plain number
+var dimensionless dims
+
+// baseSymbols are the symbols of the base unit of each dimension
+var baseSymbols = [dimCount]string{"B", "s", "USD"}
+
+// unit is a unit of measure such as GiB or MB/s, with its size in base units
+type unit struct {
+	symbol string
+	scale  *big.Rat
+	dims   dims
+}
+
+// currencyRatesDate is when the static currency rates were taken
+const currencyRatesDate = "June 2025"
+
+// currencyRates are approximate US dollar values of one unit of each currency. They are static
+// reference rates for estimates, not live exchange rates.
+var currencyRates = map[string]string{
+	"USD": "1",
+	"EUR": "1.15",
+	"GBP": "1.35",
+	"AUD": "0.65",
+	"NZD": "0.60",
+	"CAD": "0.73",
+	"CHF": "1.23",
+	"JPY": "0.0069",
+	"CNY": "0.139",
+	"HKD": "0.1274",
+	"SGD": "0.78",
+	"INR": "0.0117",
+	"KRW": "0.00073",
+	"SEK": "0.104",
+	"NOK": "0.099",
+	"DKK": "0.154",
+	"BRL": "0.18",
+	"MXN": "0.052",
+	"ZAR": "0.056",
+}
+
+// units maps lower-case unit names to units. Byte and bit units are case-insensitive, so MB and Mb
+// are both megabytes; use Mbit for megabits.
+var units = buildUnits()
+
+// buildUnits builds the unit table
+func buildUnits() map[string]unit {
+	table := map[string]unit{}
+	add := func(symbol string, scale *big.Rat, d dims, names ...string) {
+		u := unit{symbol: symbol, scale: scale, dims: d}
+		table[strings.ToLower(symbol)] = u
+		for _, name := range names {
+			table[name] = u
+		}
+	}
+	ratio := func(num, denom int64) *big.Rat { return big.NewRat(num, denom) }
+	power := func(base, exp int64) *big.Rat {
+		return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(base), big.NewInt(exp), nil))
+	}
+	bytes := dims{dimBytes: 1}
+	seconds := dims{dimTime: 1}
+	bitRate := dims{dimBytes: 1, dimTime: -1}
+
+	// Bytes, with decimal and binary multiples
+	add("B", ratio(1, 1), bytes, "byte", "bytes")
+	for i, prefix := range []string{"K", "M", "G", "T", "P", "E"} {
+		add(prefix+"B", power(1000, int64(i+1)), bytes)
+		add(prefix+"iB", power(1024, int64(i+1)), bytes)
+	}
+
+	// Bits and bit rates
+	add("bit", ratio(1, 8), bytes, "bits")
+	add("bps", ratio(1, 8), bitRate)
+	for i, prefix := range []string{"K", "M", "G", "T"} {
+		scale := new(big.Rat).Mul(power(1000, int64(i+1)), ratio(1, 8))
+		add(prefix+"bit", scale, bytes)
+		add(prefix+"bps", scale, bitRate)
+	}
+
+	// Durations. Months and years are average Gregorian lengths, except when added to a date.
+	add("ns", ratio(1, 1_000_000_000), seconds, "nanosecond", "nanoseconds")
+	add("us", ratio(1, 1_000_000), seconds, "µs", "microsecond", "microseconds")
+	add("ms", ratio(1, 1000), seconds, "millisecond", "milliseconds")
+	add("s", ratio(1, 1), seconds, "sec", "secs", "second", "seconds")
+	add("min", ratio(60, 1), seconds, "mins", "minute", "minutes")
+	add("h", ratio(3600, 1), seconds, "hr", "hrs", "hour", "hours")
+	add("d", ratio(86400, 1), seconds, "day", "days")
+	add("week", ratio(7*86400, 1), seconds, "w", "wk", "wks", "weeks")
+	add("month", ratio(2629746, 1), seconds, "mo", "months")
+	add("year", ratio(31556952, 1), seconds, "y", "yr", "yrs", "years")
+
+	// Currencies
+	for code, rate := range currencyRates {
+		scale, _ := new(big.Rat).SetString(rate)
+		add(code, scale, dims{dimMoney: 1})
+	}
+
+	return table
+}
+
+// isCurrency reports whether a unit symbol is a currency code
+func isCurrency(symbol string) bool {
+	_, ok := currencyRates[symbol]
+	return ok
+}
+
+// autoUnits are the units a result is shown in when no unit was given, largest first
+var autoUnits = map[dims][]string{
+	{dimBytes: 1}:               {"PB", "TB", "GB", "MB", "KB", "B"},
+	{dimTime: 1}:                {"d", "h", "min", "s", "ms", "us", "ns"},
+	{dimBytes: 1, dimTime: -1}:  {"GB/s", "MB/s", "KB/s", "B/s"},
+	{dimMoney: 1}:               {"USD"},
+	{dimMoney: 1, dimTime: -1}:  {"USD/month"},
+	{dimMoney: 1, dimBytes: -1}: {"USD/GB"},
+}
+
+// combine multiplies (divide false) or divides (divide true) a unit by another, e.g. MB and s into
+// MB/s
+func (c unit) combine(u unit, divide bool) unit {
+	result := unit{scale: new(big.Rat), dims: c.dims}
+	if divide {
+		result.symbol = c.symbol + "/" + u.symbol
+		result.scale.Quo(c.scale, u.scale)
+		for i := range result.dims {
+			result.dims[i] -= u.dims[i]
+		}
+	} else {
+		result.symbol = c.symbol + "*" + u.symbol
+		result.scale.Mul(c.scale, u.scale)
+		for i := range result.dims {
+			result.dims[i] += u.dims[i]
+		}
+	}
+	return result
+}
+
+// parseUnit parses a unit such as GiB, MB/s or USD per month
+func parseUnit(text string) (unit, error) {
+	p := newParser(text)
+	p.next()
+	c, ok, err := p.parseUnitExpr()
+	if err != nil {
+		return c, err
+	}
+	if !ok || p.tok.kind != tokEOF {
+		return c, fmt.Errorf("unknown unit '%s'", text)
+	}
+	return c, nil
+}
+
+// baseUnitSymbol describes a dimension in base units, e.g. B/s, for results with no named unit
+func baseUnitSymbol(d dims) string {
+	var numerator, denominator []string
+	for i, exp := range d {
+		switch {
+		case exp == 1:
+			numerator = append(numerator, baseSymbols[i])
+		case exp > 1:
+			numerator = append(numerator, fmt.Sprintf("%s^%d", baseSymbols[i], exp))
+		case exp == -1:
+			denominator = append(denominator, baseSymbols[i])
+		case exp < -1:
+			denominator = append(denominator, fmt.Sprintf("%s^%d", baseSymbols[i], -exp))
+		}
+	}
+	symbol := strings.Join(numerator, "*")
+	if symbol == "" {
+		symbol = "1"
+	}
+	for _, part := range denominator {
+		symbol += "/" + part
+	}
+	return symbol
+}
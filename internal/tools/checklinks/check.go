@@ -0,0 +1,225 @@
+package checklinks
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/markdown"
+)
+
+var (
+	// scheme starts an absolute URL, such as https: or mailto:
+	scheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+	// reservedDomains are kept for documentation and testing, so links to them are examples
+	reservedDomains = []string{"example.com", "example.net", "example.org", "example", "test", "invalid", "localhost", "local"}
+)
+
+// use is a link in a file, kept until its external URL has been checked
+type use struct {
+	name string
+	link link
+}
+
+// checker checks the links in a set of pages
+type checker struct {
+	// siteRoot is the directory links starting with / in HTML are resolved from
+	siteRoot string
+	// repoRoot is the directory links starting with / in Markdown are resolved from, or "" when
+	// there is none
+	repoRoot string
+	external bool
+	ignore   []string
+
+	result   *Result
+	problems []Problem
+	// urls lists the external URLs found in order, with the links using each
+	urls []string
+	uses map[string][]use
+	// anchors caches the anchors of each page linked to, by absolute path
+	anchors map[string]map[string]bool
+}
+
+// newChecker returns a checker resolving root-relative links from siteRoot and repoRoot
+func newChecker(siteRoot, repoRoot string, external bool, ignore []string, result *Result) *checker {
+	return &checker{siteRoot: siteRoot, repoRoot: repoRoot, external: external, ignore: ignore, result: result, uses: map[string][]use{}, anchors: map[string]map[string]bool{}}
+}
+
+// check checks a page's internal links and gathers its external ones
+func (c *checker) check(path, name string, p *page) {
+	c.anchors[path] = p.anchors
+	for _, lk := range p.links {
+		c.result.Links++
+		target := lk.target
+		if strings.HasPrefix(target, "//") {
+			// Protocol-relative URLs are checked over HTTPS
+			target = "https:" + target
+		}
+		switch {
+		case target == "":
+			c.result.Skipped++
+		case scheme.MatchString(target):
+			c.externalLink(name, lk, target)
+		default:
+			c.internalLink(path, name, p, lk)
+		}
+	}
+}
+
+// internalLink checks that a relative link's file exists, and that its anchor is in that file
+func (c *checker) internalLink(path, name string, p *page, lk link) {
+	file, anchor, _ := strings.Cut(lk.target, "#")
+	file, _, _ = strings.Cut(file, "?")
+	if decoded, err := url.PathUnescape(anchor); err == nil {
+		anchor = decoded
+	}
+	if decoded, err := url.PathUnescape(file); err == nil {
+		file = decoded
+	}
+	if file == "" {
+		c.result.Internal++
+		// #top goes to the top of an HTML page without an element of that ID
+		if anchor != "" && !(p.isHTML && anchor == "top") && !markdown.HasAnchor(p.anchors, anchor) {
+			c.add(name, lk, "missing-anchor", fmt.Sprintf("No heading or ID %q in this file", "#"+anchor))
+		}
+		return
+	}
+
+	var resolved string
+	switch {
+	case strings.HasPrefix(file, "/"):
+		root := c.repoRoot
+		if p.isHTML {
+			root = c.siteRoot
+		}
+		if root == "" {
+			// Without a repository root there is nothing to resolve the link from
+			c.result.Skipped++
+			return
+		}
+		resolved = filepath.Join(root, filepath.FromSlash(file))
+	default:
+		resolved = filepath.Join(filepath.Dir(path), filepath.FromSlash(file))
+	}
+	c.result.Internal++
+	info, err := os.Stat(resolved)
+	if err != nil && p.isHTML && filepath.Ext(resolved) == "" {
+		// Sites often serve about.html as /about
+		if info, err = os.Stat(resolved + ".html"); err == nil {
+			resolved += ".html"
+		}
+	}
+	if err != nil {
+		c.add(name, lk, "missing-file", fmt.Sprintf("Link target %s does not exist", file))
+		return
+	}
+	if info.IsDir() && p.isHTML {
+		// A web server shows a directory's index page
+		index := filepath.Join(resolved, "index.html")
+		if info, err = os.Stat(index); err != nil {
+			c.add(name, lk, "missing-file", fmt.Sprintf("Link target %s is a directory without an index.html", file))
+			return
+		}
+		resolved = index
+	}
+	if anchor == "" || info.IsDir() || !isPageFile(resolved) {
+		return
+	}
+	anchors, ok := c.anchors[resolved]
+	if !ok {
+		if security.CheckFileAccess(resolved) != nil {
+			return
+		}
+		content, err := readFile(resolved)
+		if err != nil {
+			return
+		}
+		anchors = parsePage(resolved, content).anchors
+		c.anchors[resolved] = anchors
+	}
+	if !markdown.HasAnchor(anchors, anchor) {
+		c.add(name, lk, "missing-anchor", fmt.Sprintf("No heading or ID %q in %s", "#"+anchor, file))
+	}
+}
+
+// externalLink gathers an http(s) link to check once every file has been read
+func (c *checker) externalLink(name string, lk link, target string) {
+	lower := strings.ToLower(target)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		// mailto:, tel:, javascript: and other schemes cannot be checked
+		c.result.Skipped++
+		return
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		c.add(name, lk, "invalid", "Not a valid URL")
+		return
+	}
+	// Anchors on other sites are not checked, so URLs differing only by anchor are checked once
+	u.Fragment, u.RawFragment = "", ""
+	key := u.String()
+	if !c.external || c.ignored(u, key) {
+		c.result.Skipped++
+		return
+	}
+	if _, seen := c.uses[key]; !seen {
+		c.urls = append(c.urls, key)
+	}
+	c.uses[key] = append(c.uses[key], use{name: name, link: lk})
+}
+
+// ignored reports whether a URL is on a reserved host or matches an ignore entry: a URL prefix, or a
+// host name that also covers its subdomains
+func (c *checker) ignored(u *url.URL, key string) bool {
+	host := strings.ToLower(u.Hostname())
+	matches := func(domain string) bool {
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+	if slices.ContainsFunc(reservedDomains, matches) {
+		return true
+	}
+	for _, entry := range c.ignore {
+		if strings.Contains(entry, "://") {
+			if strings.HasPrefix(strings.ToLower(key), entry) {
+				return true
+			}
+		} else if matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// record adds a problem for each use of each external URL that did not work, leaving out permanent
+// redirects unless they are reported
+func (c *checker) record(outcomes map[string]outcome, redirects bool) {
+	for _, key := range c.urls {
+		o, ok := outcomes[key]
+		if !ok || o.status == "" || (o.status == "redirect" && !redirects) {
+			continue
+		}
+		for _, u := range c.uses[key] {
+			c.problems = append(c.problems, Problem{
+				File:     u.name,
+				Line:     u.link.line,
+				Column:   u.link.column,
+				Link:     u.link.target,
+				Status:   o.status,
+				Code:     o.code,
+				Message:  o.message,
+				Location: o.location,
+			})
+		}
+	}
+}
+
+// add records a problem with a link
+func (c *checker) add(name string, lk link, status, message string) {
+	c.problems = append(c.problems, Problem{File: name, Line: lk.line, Column: lk.column, Link: lk.target, Status: status, Message: message})
+}
@@ -0,0 +1,317 @@
+package checklinks
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultConcurrency = 8
+	maxConcurrency     = 32
+	defaultTimeout     = 10 * time.Second
+	maxTimeout         = 60 * time.Second
+	defaultMaxURLs     = 500
+	maxURLsLimit       = 2000
+	defaultMaxProblems = 200
+	maxProblemsLimit   = 2000
+	// maxFiles is the most files checked in a directory
+	maxFiles = 1000
+	// maxFileBytes is the largest file checked
+	maxFileBytes = 2 * 1024 * 1024
+)
+
+// CheckLinksTool finds broken links in Markdown and HTML documentation
+type CheckLinksTool struct{}
+
+// init registers the check_links tool
+func init() {
+	registry.Register(&CheckLinksTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *CheckLinksTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"check_links",
+		mcp.WithDescription(`Find broken links in a docs directory or a single Markdown or HTML file, such as a README, a docs folder or a generated static site.
+
+Internal links are checked against the files on disk, including #anchors to headings and IDs. External http(s) URLs are requested with bounded concurrency, respecting the security domain policy, and results are cached for 30 minutes. Returns a report of each broken, unreachable or unverifiable link with its file, line and column.`),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path of a directory whose Markdown and HTML files are checked, or of one file"),
+		),
+		mcp.WithBoolean("external",
+			mcp.Description("Check external http(s) URLs as well as internal links (default: true)"),
+		),
+		mcp.WithString("ignore",
+			mcp.Description("Comma-separated hosts (which cover their subdomains) or URL prefixes not to check, e.g. 'internal.corp,https://github.com/org/private'. Reserved hosts such as localhost and example.com are never checked."),
+		),
+		mcp.WithBoolean("report_redirects",
+			mcp.Description("Also report external URLs that are permanently redirected, with where they now point (default: false)"),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("Most external URLs checked at once, with at most %d per host (default: %d, max: %d)", maxPerHost, defaultConcurrency, maxConcurrency)),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("Seconds to wait for each external URL (default: %d, max: %d)", int(defaultTimeout.Seconds()), int(maxTimeout.Seconds()))),
+		),
+		mcp.WithNumber("max_urls",
+			mcp.Description(fmt.Sprintf("Most distinct external URLs checked (default: %d, max: %d)", defaultMaxURLs, maxURLsLimit)),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Check external URLs again instead of using results cached in the last 30 minutes (default: false)"),
+		),
+		mcp.WithNumber("max_problems",
+			mcp.Description(fmt.Sprintf("Most problems to return (default: %d, max: %d)", defaultMaxProblems, maxProblemsLimit)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads files and requests URLs
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // Checking again gives the same report while links are unchanged
+		mcp.WithOpenWorldHintAnnotation(true),    // Requests external URLs
+	)
+}
+
+// Execute checks the links and returns the report
+func (t *CheckLinksTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	root, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() && !isPageFile(root) {
+		return nil, fmt.Errorf("%s is not a Markdown or HTML file", root)
+	}
+	external := true
+	if value, ok := args["external"].(bool); ok {
+		external = value
+	}
+	redirects, _ := args["report_redirects"].(bool)
+	refresh, _ := args["refresh"].(bool)
+	concurrency, err := number(args, "concurrency", defaultConcurrency, maxConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := number(args, "timeout", int(defaultTimeout.Seconds()), int(maxTimeout.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	maxURLs, err := number(args, "max_urls", defaultMaxURLs, maxURLsLimit)
+	if err != nil {
+		return nil, err
+	}
+	maxProblems, err := number(args, "max_problems", defaultMaxProblems, maxProblemsLimit)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := ignoreList(args)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"path": root, "external": external}).Debug("Checking links")
+
+	result := &Result{Counts: map[string]int{}, Problems: []Problem{}}
+	siteRoot := root
+	if !info.IsDir() {
+		siteRoot = filepath.Dir(root)
+	}
+	c := newChecker(siteRoot, findRepoRoot(siteRoot), external, ignore, result)
+	if err := eachFile(ctx, root, info.IsDir(), result, c.check); err != nil {
+		return nil, err
+	}
+
+	if len(c.urls) > maxURLs {
+		for _, key := range c.urls[maxURLs:] {
+			result.Skipped += len(c.uses[key])
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d of %d external URLs were checked. Raise max_urls or check a subdirectory to see the rest.", maxURLs, len(c.urls)))
+		c.urls = c.urls[:maxURLs]
+	}
+	if len(c.urls) > 0 {
+		outcomes, cached := newProber(time.Duration(seconds)*time.Second, concurrency, logger).probeAll(ctx, cache, c.urls, refresh)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result.External = len(c.urls)
+		result.Cached = cached
+		c.record(outcomes, redirects)
+	}
+
+	slices.SortStableFunc(c.problems, func(a, b Problem) int {
+		return cmp.Or(strings.Compare(a.File, b.File), a.Line-b.Line, a.Column-b.Column)
+	})
+	for _, problem := range c.problems {
+		result.Counts[problem.Status]++
+	}
+	result.Problems = c.problems[:min(len(c.problems), maxProblems)]
+	if len(c.problems) > maxProblems {
+		result.Truncated = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d of %d problems are listed. Raise max_problems or check a subdirectory to see the rest.", maxProblems, len(c.problems)))
+	}
+	if result.Problems == nil {
+		result.Problems = []Problem{}
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Links are quoted from the files, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "check_links",
+		URL:         "file://" + root,
+		ContentType: "text",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// eachFile parses a file, or each Markdown and HTML file in a directory, and passes it to fn with its
+// name in results: the file's path, or its path relative to the directory
+func eachFile(ctx context.Context, root string, isDir bool, result *Result, fn func(path, name string, p *page)) error {
+	if !isDir {
+		content, err := readFile(root)
+		if err != nil {
+			return err
+		}
+		fn(root, root, parsePage(root, content))
+		result.Files = 1
+		return nil
+	}
+	files, warnings, err := findFiles(root)
+	if err != nil {
+		return err
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		content, err := readFile(file)
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+			continue
+		}
+		name, _ := filepath.Rel(root, file)
+		fn(file, filepath.ToSlash(name), parsePage(file, content))
+		result.Files++
+	}
+	if result.Files == 0 {
+		result.Warnings = append(result.Warnings, "No Markdown or HTML files were found.")
+	}
+	return nil
+}
+
+// number reads a positive whole number parameter, capped at limit
+func number(args map[string]any, key string, fallback, limit int) (int, error) {
+	value, ok := args[key].(float64)
+	if !ok {
+		return fallback, nil
+	}
+	if value < 1 || value != float64(int(value)) {
+		return 0, fmt.Errorf("%s must be a whole number of at least 1, got: %v", key, value)
+	}
+	return min(int(value), limit), nil
+}
+
+// ignoreList reads the comma-separated hosts and URL prefixes not to check
+func ignoreList(args map[string]any) ([]string, error) {
+	raw, _ := args["ignore"].(string)
+	var entries []string
+	for entry := range strings.SplitSeq(raw, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "://") && strings.ContainsAny(entry, "/ ") {
+			return nil, fmt.Errorf("ignore entry %q must be a host name or a URL prefix starting with http:// or https://", entry)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the check_links tool
+func (t *CheckLinksTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Before publishing or releasing documentation, after moving or renaming docs files or headings, when auditing an older docs site for link rot, or after generating a static site to check its output.",
+		WhenNotToUse: "Linting Markdown structure such as heading levels and bare URLs - use markdown. Fetching a page's content - use fetch_url. Checking a single URL's DNS or TLS - use netdiag.",
+		CommonPatterns: []string{
+			"Check a docs directory with external=false first for fast internal results, then with external checking",
+			"Fix the problems reported, then check again - external results are cached, so only the fixed URLs cost a request with refresh=true",
+			"Use report_redirects=true to find URLs that have moved permanently and update them",
+			"Point it at a built static site (for example public/ or site/) to check links as they are served",
+		},
+		ParameterDetails: map[string]string{
+			"path":     "Markdown (.md, .markdown, .mdx) and HTML (.html, .htm) files are checked, leaving out hidden directories, node_modules and vendor. Relative links are resolved from each file's directory. Links starting with / are resolved from the repository root (found by looking for .git) in Markdown, and from the checked directory in HTML, as a site root.",
+			"status":   "missing-file: a relative link's file does not exist. missing-anchor: a #fragment is not a heading, {#id} or HTML id in the linked file. http-error: the server answered 404, 410, 500 and so on. unverified: the server refused the check (401, 403, 429), which often happens to automated clients, so check it in a browser. unreachable: no response, a timeout or an unknown host. blocked: the security policy does not allow the request. invalid: the URL cannot be parsed. redirect: permanently moved, only with report_redirects.",
+			"external": "Each distinct URL is requested once, whatever anchor it has, with HEAD and then GET when HEAD fails. Anchors on other sites are not checked. mailto:, tel: and other schemes are skipped.",
+			"ignore":   "Use for hosts that need a login, are on a private network, or rate limit automated clients. Hosts reserved for examples (example.com, .test, .invalid, .example) and localhost are always skipped.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Check a project's documentation",
+				Arguments:      map[string]any{"path": "/home/user/project/docs"},
+				ExpectedResult: "Counts of files, links and external URLs checked, with each problem's file, line, column, link, status and message",
+			},
+			{
+				Description:    "Check internal links only",
+				Arguments:      map[string]any{"path": "/home/user/project/README.md", "external": false},
+				ExpectedResult: "Missing files and anchors, without any network requests",
+			},
+			{
+				Description:    "Check a generated site, skipping a private host",
+				Arguments:      map[string]any{"path": "/home/user/site/public", "ignore": "intranet.example.org", "report_redirects": true},
+				ExpectedResult: "Broken links in the site's HTML, and URLs that have moved with where they now point",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Many links are reported as unverified",
+				Solution: "Some sites refuse automated requests. Check a few in a browser, then add the host to ignore.",
+			},
+			{
+				Problem:  "Links are reported as blocked",
+				Solution: "The security configuration denies requests to the host or its address, for example private networks. The user can change this in their MCP DevTools configuration.",
+			},
+			{
+				Problem:  "A fixed link is still reported as broken",
+				Solution: "External results are cached for 30 minutes. Check again with refresh=true.",
+			},
+		},
+	}
+}
@@ -0,0 +1,213 @@
+package checklinks
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/markdown"
+	"golang.org/x/net/html"
+)
+
+var (
+	// htmlExtensions are the HTML files checked in a directory
+	htmlExtensions = []string{".html", ".htm"}
+
+	// skippedDirs are dependency directories not checked. Build output such as dist is kept, as a
+	// generated site is often what is checked.
+	skippedDirs = []string{"node_modules", "vendor", "__pycache__", "venv"}
+
+	// linkAttributes are the attributes of each HTML element that hold a link
+	linkAttributes = map[string]string{
+		"a": "href", "area": "href", "link": "href",
+		"img": "src", "script": "src", "source": "src", "iframe": "src", "audio": "src", "video": "src", "track": "src", "embed": "src",
+	}
+
+	// hintRels are link relations that name an origin to connect to early, rather than a resource
+	hintRels = []string{"preconnect", "dns-prefetch"}
+)
+
+// link is a link target in a page
+type link struct {
+	// line and column are 1-based, with column counted in characters
+	line   int
+	column int
+	target string
+}
+
+// page is a parsed Markdown or HTML file
+type page struct {
+	links   []link
+	anchors map[string]bool
+	isHTML  bool
+}
+
+// parsePage parses a file's content by its extension
+func parsePage(path, content string) *page {
+	if isHTMLFile(path) {
+		return parseHTML(content)
+	}
+	p := &page{anchors: markdown.Anchors(content)}
+	for _, lk := range markdown.Links(content) {
+		p.links = append(p.links, link{line: lk.Line, column: lk.Column, target: lk.Target})
+	}
+	return p
+}
+
+// parseHTML finds the links in an HTML document, and the ids and names they can point to
+func parseHTML(content string) *page {
+	p := &page{anchors: map[string]bool{}, isHTML: true}
+	starts := lineStarts(content)
+	z := html.NewTokenizer(strings.NewReader(content))
+	for offset := 0; ; {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// The end of the document, as a string reader cannot fail
+			return p
+		}
+		raw := string(z.Raw())
+		start := offset
+		offset += len(raw)
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		tag := string(name)
+		attrs := map[string]string{}
+		for hasAttr {
+			var key, value []byte
+			key, value, hasAttr = z.TagAttr()
+			attrs[string(key)] = string(value)
+		}
+		if id := attrs["id"]; id != "" {
+			p.anchors[id] = true
+		}
+		if name := attrs["name"]; name != "" && tag == "a" {
+			p.anchors[name] = true
+		}
+		key, ok := linkAttributes[tag]
+		if !ok {
+			continue
+		}
+		target, has := attrs[key]
+		rels := strings.Fields(strings.ToLower(attrs["rel"]))
+		if !has || slices.ContainsFunc(rels, func(rel string) bool { return slices.Contains(hintRels, rel) }) {
+			continue
+		}
+		// The link is placed at its value, or at the tag when entities in the value were decoded
+		at := start
+		if i := strings.Index(raw, target); target != "" && i >= 0 {
+			at += i
+		}
+		line, column := position(content, starts, at)
+		p.links = append(p.links, link{line: line, column: column, target: strings.TrimSpace(target)})
+	}
+}
+
+// lineStarts returns the offset of the start of each line in content
+func lineStarts(content string) []int {
+	starts := []int{0}
+	for i := range len(content) {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position returns the 1-based line and character column of an offset in content
+func position(content string, starts []int, offset int) (int, int) {
+	i, found := slices.BinarySearch(starts, offset)
+	if !found {
+		i--
+	}
+	return i + 1, len([]rune(content[starts[i]:offset])) + 1
+}
+
+// findFiles returns the Markdown and HTML files under root, leaving out hidden and dependency
+// directories, with warnings for files left out
+func findFiles(root string) ([]string, []string, error) {
+	var files, warnings []string
+	skipped := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			warnings = append(warnings, fmt.Sprintf("Could not read %s: %v", path, err))
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !isPageFile(name) {
+			return nil
+		}
+		if security.CheckFileAccess(path) != nil {
+			skipped++
+			return nil
+		}
+		if len(files) == maxFiles {
+			warnings = append(warnings, fmt.Sprintf("Only the first %d files were checked. Check a subdirectory to see the rest.", maxFiles))
+			return filepath.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if skipped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files were not checked because the security policy denies access to them.", skipped))
+	}
+	return files, warnings, nil
+}
+
+// readFile reads a page, refusing large ones
+func readFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if info.Size() > maxFileBytes {
+		return "", fmt.Errorf("%s was not checked because it is larger than %d MB", path, maxFileBytes/1024/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// isPageFile reports whether a file is Markdown or HTML
+func isPageFile(name string) bool {
+	return markdown.IsMarkdownFile(name) || isHTMLFile(name)
+}
+
+// isHTMLFile reports whether a file has an HTML extension
+func isHTMLFile(name string) bool {
+	return slices.Contains(htmlExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+// findRepoRoot returns the nearest directory at or above dir containing .git, or "" when there is
+// none
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
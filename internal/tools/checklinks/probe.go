@@ -0,0 +1,234 @@
+package checklinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxPerHost is the most requests made to one host at a time, so large sites are not hammered
+	maxPerHost = 2
+	// maxRedirects is the most redirects followed from a URL
+	maxRedirects = 10
+	userAgent    = "mcp-devtools/check_links"
+
+	// cacheKeyPrefix namespaces the tool's entries in the shared cache
+	cacheKeyPrefix = "check_links:"
+	// cacheTTL is how long an external URL's outcome is reused before it is checked again
+	cacheTTL = 30 * time.Minute
+)
+
+// outcome is the result of checking an external URL, with an empty status when it works
+type outcome struct {
+	status  string
+	code    int
+	message string
+	// location is where the URL now points, when it was permanently redirected
+	location string
+}
+
+// cacheEntry is a cached outcome with the time it was stored
+type cacheEntry struct {
+	outcome  outcome
+	storedAt time.Time
+}
+
+// prober checks external URLs
+type prober struct {
+	timeout     time.Duration
+	concurrency int
+	logger      *logrus.Logger
+
+	mu sync.Mutex
+	// hosts limits the requests in flight to each host
+	hosts map[string]chan struct{}
+}
+
+// newProber returns a prober making at most concurrency requests at once, each limited to timeout
+func newProber(timeout time.Duration, concurrency int, logger *logrus.Logger) *prober {
+	return &prober{timeout: timeout, concurrency: concurrency, logger: logger, hosts: map[string]chan struct{}{}}
+}
+
+// probeAll checks each URL, reusing outcomes from the cache unless refreshing, and returns the outcome
+// of each URL with the number taken from the cache
+func (p *prober) probeAll(ctx context.Context, cache *sync.Map, urls []string, refresh bool) (map[string]outcome, int) {
+	outcomes := make(map[string]outcome, len(urls))
+	cached := 0
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.concurrency)
+	for _, rawURL := range urls {
+		if !refresh {
+			if o, ok := loadCached(cache, rawURL); ok {
+				outcomes[rawURL] = o
+				cached++
+				continue
+			}
+		}
+		wg.Go(func() {
+			// The host's slot is taken first, so requests waiting on a busy host do not hold up others
+			host := p.hostSlot(rawURL)
+			host <- struct{}{}
+			defer func() { <-host }()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			o := p.probe(ctx, rawURL)
+			mu.Lock()
+			outcomes[rawURL] = o
+			mu.Unlock()
+			if ctx.Err() == nil {
+				storeCached(cache, rawURL, o)
+			}
+		})
+	}
+	wg.Wait()
+	return outcomes, cached
+}
+
+// hostSlot returns the channel limiting requests to a URL's host
+func (p *prober) hostSlot(rawURL string) chan struct{} {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		host = strings.ToLower(u.Host)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slot, ok := p.hosts[host]
+	if !ok {
+		slot = make(chan struct{}, maxPerHost)
+		p.hosts[host] = slot
+	}
+	return slot
+}
+
+// probe checks a URL with a HEAD request, falling back to GET for servers that do not answer HEAD
+// requests properly
+func (p *prober) probe(ctx context.Context, rawURL string) outcome {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return outcome{status: "invalid", message: fmt.Sprintf("Invalid URL: %v", err)}
+	}
+	if err := security.CheckNetworkHost(u.Hostname()); err != nil {
+		return outcome{status: "blocked", message: fmt.Sprintf("Not checked: the security policy does not allow requests to %s", u.Hostname())}
+	}
+	code, location, err := p.request(ctx, http.MethodHead, rawURL)
+	if err == nil && code >= 400 {
+		code, location, err = p.request(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return failure(err, p.timeout)
+	}
+	o := outcome{code: code}
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden || code == 999:
+		// 999 is LinkedIn's refusal of automated requests
+		o.status = "unverified"
+		o.message = fmt.Sprintf("The server refused the request (%d), as sites often do for automated clients or pages that need a login; check it in a browser", code)
+	case code == http.StatusTooManyRequests:
+		o.status = "unverified"
+		o.message = "The server is rate limiting requests (429); check again later"
+	case code >= 400:
+		o.status = "http-error"
+		o.message = strings.TrimSpace(fmt.Sprintf("HTTP %d %s", code, http.StatusText(code)))
+	case location != "":
+		o.status = "redirect"
+		o.message = fmt.Sprintf("Permanently redirected to %s; link to it directly", location)
+		o.location = location
+	}
+	p.logger.WithFields(logrus.Fields{"url": rawURL, "code": code}).Debug("Checked link")
+	return o
+}
+
+// request makes a request and returns its final status code and, when the URL was permanently
+// redirected, the URL the redirects ended at
+func (p *prober) request(ctx context.Context, method, rawURL string) (int, string, error) {
+	client := httpclient.New(httpclient.Options{Timeout: p.timeout, MaxRetries: -1, Logger: p.logger})
+	permanent := false
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirected to unsupported scheme: %s", req.URL.Scheme)
+		}
+		if len(via) == 1 {
+			code := req.Response.StatusCode
+			permanent = code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+		}
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "*/*")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	// Only the status matters, so the body is not read
+	_ = resp.Body.Close()
+	location := ""
+	if permanent {
+		location = resp.Request.URL.String()
+	}
+	return resp.StatusCode, location, nil
+}
+
+// failure describes a request that got no response
+func failure(err error, timeout time.Duration) outcome {
+	if strings.Contains(err.Error(), "access denied: ") {
+		// A redirect, or the address the host resolved to, is denied by the security policy
+		return outcome{status: "blocked", message: "Not checked: the security policy does not allow a request this link needs"}
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return outcome{status: "unreachable", message: fmt.Sprintf("No response within %s", timeout)}
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return outcome{status: "unreachable", message: fmt.Sprintf("Host %s was not found", dnsErr.Name)}
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return outcome{status: "unreachable", message: err.Error()}
+}
+
+// loadCached returns a URL's cached outcome if it is younger than cacheTTL
+func loadCached(cache *sync.Map, rawURL string) (outcome, bool) {
+	if cache == nil {
+		return outcome{}, false
+	}
+	cached, ok := cache.Load(cacheKeyPrefix + rawURL)
+	if !ok {
+		return outcome{}, false
+	}
+	entry, ok := cached.(cacheEntry)
+	if !ok || time.Since(entry.storedAt) >= cacheTTL {
+		return outcome{}, false
+	}
+	return entry.outcome, true
+}
+
+// storeCached stores a URL's outcome in the cache
+func storeCached(cache *sync.Map, rawURL string, o outcome) {
+	if cache == nil {
+		return
+	}
+	cache.Store(cacheKeyPrefix+rawURL, cacheEntry{outcome: o, storedAt: time.Now()})
+}
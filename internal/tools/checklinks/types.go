@@ -0,0 +1,38 @@
+package checklinks
+
+// Result is the broken-link report for the files checked
+type Result struct {
+	Files int `json:"files"`
+	// Links is the number of links found, counting each use of an external URL
+	Links int `json:"links"`
+	// Internal is the number of links to local files and anchors checked
+	Internal int `json:"internal"`
+	// External is the number of distinct external URLs checked, of which Cached were already known
+	External int `json:"external"`
+	Cached   int `json:"cached,omitempty"`
+	// Skipped is the number of links not checked: other schemes such as mailto:, ignored or
+	// reserved hosts, and external links when external checking is off
+	Skipped int `json:"skipped"`
+	// Counts is the number of problems of each status, including any left out of Problems
+	Counts    map[string]int `json:"counts"`
+	Problems  []Problem      `json:"problems"`
+	Truncated bool           `json:"truncated,omitempty"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// Problem is a link that is broken, could not be checked or has moved
+type Problem struct {
+	File string `json:"file"`
+	// Line and Column are 1-based, with Column counted in characters
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+	Link   string `json:"link"`
+	// Status is missing-file, missing-anchor, http-error, unverified, unreachable, blocked, invalid
+	// or redirect
+	Status string `json:"status"`
+	// Code is the HTTP status code of an external link's response
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message"`
+	// Location is where a permanently redirected link now points
+	Location string `json:"location,omitempty"`
+}
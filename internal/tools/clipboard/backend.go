@@ -0,0 +1,165 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// ReadCommandEnvVar overrides the command that prints the clipboard's text
+	ReadCommandEnvVar = "CLIPBOARD_READ_COMMAND"
+	// WriteCommandEnvVar overrides the command that copies its standard input to the clipboard
+	WriteCommandEnvVar = "CLIPBOARD_WRITE_COMMAND"
+
+	commandTimeout = 10 * time.Second
+	// exitDelay is how long to wait for the output of a command that has exited. xclip and
+	// wl-copy leave a child process holding the clipboard, which keeps the pipes open.
+	exitDelay = time.Second
+)
+
+// backend is the pair of commands that read and write the clipboard
+type backend struct {
+	read  []string
+	write []string
+	// newline is set when the read command ends its output with a line break of its own
+	newline bool
+}
+
+// findBackend returns the commands for the platform's clipboard, using overrides where set
+func findBackend() (*backend, error) {
+	b, err := platformBackend()
+	readOverride := strings.Fields(os.Getenv(ReadCommandEnvVar))
+	writeOverride := strings.Fields(os.Getenv(WriteCommandEnvVar))
+	if len(readOverride) == 0 && len(writeOverride) == 0 {
+		return b, err
+	}
+	if b == nil {
+		b = &backend{}
+	}
+	if len(writeOverride) > 0 {
+		b.write = writeOverride
+	}
+	if len(readOverride) > 0 {
+		b.read = readOverride
+		b.newline = false
+	}
+	return b, nil
+}
+
+// platformBackend finds the clipboard commands for the operating system and display server
+func platformBackend() (*backend, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &backend{read: []string{"pbpaste"}, write: []string{"pbcopy"}}, nil
+	case "windows":
+		// PowerShell's console encoding is not UTF-8 by default
+		return &backend{
+			newline: true,
+			read:    []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw"},
+			write:   []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"},
+		}, nil
+	}
+
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := exec.LookPath("wl-paste"); err == nil {
+			return &backend{read: []string{"wl-paste", "--no-newline"}, write: []string{"wl-copy"}}, nil
+		}
+	}
+	if os.Getenv("DISPLAY") != "" {
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return &backend{read: []string{"xclip", "-selection", "clipboard", "-out"}, write: []string{"xclip", "-selection", "clipboard", "-in"}}, nil
+		}
+		if _, err := exec.LookPath("xsel"); err == nil {
+			return &backend{read: []string{"xsel", "--clipboard", "--output"}, write: []string{"xsel", "--clipboard", "--input"}}, nil
+		}
+	}
+	if os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("DISPLAY") == "" {
+		return nil, fmt.Errorf("no graphical session was found (neither WAYLAND_DISPLAY nor DISPLAY is set), so there is no clipboard to use; set %s and %s to use another command", ReadCommandEnvVar, WriteCommandEnvVar)
+	}
+	return nil, fmt.Errorf("no clipboard command was found - install wl-clipboard (Wayland), xclip or xsel (X11), or set %s and %s", ReadCommandEnvVar, WriteCommandEnvVar)
+}
+
+// limitedBuffer keeps the first limit bytes written to it and counts them all
+type limitedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+	total  int
+}
+
+// Write keeps what fits and discards the rest, so a huge clipboard is never held in memory
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.total += len(p)
+	if room := b.limit - b.buffer.Len(); room > 0 {
+		b.buffer.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// readText runs the read command, returning up to limit bytes of its output and the full size
+func readText(ctx context.Context, b *backend, limit int) ([]byte, int, error) {
+	if len(b.read) == 0 {
+		return nil, 0, fmt.Errorf("no command to read the clipboard; set %s", ReadCommandEnvVar)
+	}
+	stdout := &limitedBuffer{limit: limit}
+	if err := run(ctx, b.read, nil, stdout); err != nil {
+		return nil, 0, err
+	}
+	text, total := stdout.buffer.Bytes(), stdout.total
+	if b.newline && total <= limit {
+		trimmed := bytes.TrimSuffix(bytes.TrimSuffix(text, []byte("\n")), []byte("\r"))
+		total -= len(text) - len(trimmed)
+		text = trimmed
+	}
+	return text, total, nil
+}
+
+// writeText runs the write command with the text on its standard input
+func writeText(ctx context.Context, b *backend, text string) error {
+	if len(b.write) == 0 {
+		return fmt.Errorf("no command to write the clipboard; set %s", WriteCommandEnvVar)
+	}
+	return run(ctx, b.write, strings.NewReader(text), nil)
+}
+
+// run runs a clipboard command, including its error output in errors
+func run(ctx context.Context, command []string, stdin *strings.Reader, stdout *limitedBuffer) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+	cmd.WaitDelay = exitDelay
+
+	err := cmd.Run()
+	if err == nil || errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	name := filepath.Base(command[0])
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s timed out after %s", name, commandTimeout)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	message := strings.TrimSpace(stderr.buffer.String())
+	if message == "" {
+		message = err.Error()
+	}
+	return fmt.Errorf("%s failed: %s", name, message)
+}
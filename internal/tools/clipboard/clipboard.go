@@ -0,0 +1,255 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxBytesEnvVar sets the most bytes read from or written to the clipboard
+	MaxBytesEnvVar = "CLIPBOARD_MAX_BYTES"
+
+	defaultMaxBytes  = 1024 * 1024
+	defaultReadBytes = 64 * 1024
+)
+
+// ClipboardTool reads and writes the system clipboard's text
+type ClipboardTool struct{}
+
+// init registers the clipboard tool
+func init() {
+	registry.Register(&ClipboardTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ClipboardTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"clipboard",
+		mcp.WithDescription(`Read or write the text on the user's system clipboard, so they can hand over content such as a log, stack trace or document by copying it instead of pasting it into the chat, and receive results to paste elsewhere.
+
+Functions:
+- read: the clipboard's text, up to max_bytes
+- write: replace the clipboard's text with content
+
+Content is scanned by the security framework in both directions. Only use write when the user asks for something to be copied.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("read", "write"),
+		),
+		mcp.WithString("content",
+			mcp.Description("write: text to put on the clipboard"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description(fmt.Sprintf("read: most bytes of text to return; longer text is truncated (default: %d)", defaultReadBytes)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // write changes the clipboard
+		mcp.WithDestructiveHintAnnotation(true), // write replaces whatever the user had copied
+		mcp.WithIdempotentHintAnnotation(true),  // Writing the same text again leaves the clipboard the same
+		mcp.WithOpenWorldHintAnnotation(false),  // Uses the local clipboard only
+	)
+}
+
+// Execute reads or writes the clipboard
+func (t *ClipboardTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+	if function != "read" && function != "write" {
+		return nil, fmt.Errorf("unknown function: %s", function)
+	}
+	limit, err := maxBytes()
+	if err != nil {
+		return nil, err
+	}
+	b, err := findBackend()
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("function", function).Debug("Using clipboard")
+
+	if function == "write" {
+		content, _ := args["content"].(string)
+		return write(ctx, b, content, limit)
+	}
+
+	readBytes := min(defaultReadBytes, limit)
+	if value, ok := args["max_bytes"].(float64); ok {
+		if value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("max_bytes must be a whole number of at least 1, got: %v", value)
+		}
+		readBytes = min(int(value), limit)
+	}
+	return read(ctx, b, readBytes)
+}
+
+// read returns up to limit bytes of the clipboard's text, scanned before it is returned
+func read(ctx context.Context, b *backend, limit int) (*mcp.CallToolResult, error) {
+	text, total, err := readText(ctx, b, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Function: "read", Bytes: total, Command: filepath.Base(b.read[0])}
+	if total > len(text) {
+		// Drop a character cut in half so the text stays valid UTF-8
+		for i := len(text) - 1; i >= max(0, len(text)-utf8.UTFMax); i-- {
+			if utf8.RuneStart(text[i]) {
+				if !utf8.FullRune(text[i:]) {
+					text = text[:i]
+				}
+				break
+			}
+		}
+		result.Truncated = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d of %d bytes are included. Raise max_bytes to read more.", len(text), total))
+	}
+	if !utf8.Valid(text) || bytes.IndexByte(text, 0) >= 0 {
+		return nil, fmt.Errorf("the clipboard does not hold text; it may hold an image or files")
+	}
+	result.Content = string(text)
+	result.Lines = countLines(result.Content)
+	if total == 0 {
+		result.Warnings = append(result.Warnings, "The clipboard is empty or does not hold text.")
+	}
+
+	output, err := encode(result)
+	if err != nil {
+		return nil, err
+	}
+	// The clipboard can hold anything the user copied, so scan it like fetched content
+	if analysis, err := security.AnalyseContent(result.Content, sourceContext()); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// write replaces the clipboard's text with content, unless the security policy blocks it
+func write(ctx context.Context, b *backend, content string, limit int) (*mcp.CallToolResult, error) {
+	if content == "" {
+		return nil, fmt.Errorf("missing required parameter: content")
+	}
+	if len(content) > limit {
+		return nil, fmt.Errorf("content is %d bytes, more than the %d bytes allowed; set %s to allow more", len(content), limit, MaxBytesEnvVar)
+	}
+	// The user may paste the text into a terminal, so scan it before it is copied
+	prefix := ""
+	if analysis, err := security.AnalyseContent(content, sourceContext()); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			prefix = security.FormatSecurityWarningPrefix(analysis)
+		}
+	}
+	if err := writeText(ctx, b, content); err != nil {
+		return nil, err
+	}
+	output, err := encode(&Result{Function: "write", Bytes: len(content), Lines: countLines(content), Command: filepath.Base(b.write[0])})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(prefix + output), nil
+}
+
+// maxBytes returns the most bytes that may be read or written
+func maxBytes() (int, error) {
+	raw := strings.TrimSpace(os.Getenv(MaxBytesEnvVar))
+	if raw == "" {
+		return defaultMaxBytes, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("%s must be a whole number of bytes, got: %s", MaxBytesEnvVar, raw)
+	}
+	return value, nil
+}
+
+// countLines returns the number of lines in text, counting a final line with no line break
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(text, "\n"), "\n") + 1
+}
+
+// sourceContext describes the clipboard to the security framework
+func sourceContext() security.SourceContext {
+	return security.SourceContext{
+		Tool:        "clipboard",
+		URL:         "clipboard://",
+		ContentType: "text",
+	}
+}
+
+// encode formats a result as indented JSON
+func encode(result *Result) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the clipboard tool
+func (t *ClipboardTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "When the user says they have copied something for you, such as a log, stack trace, config or document, or asks for a result to be put on their clipboard to paste elsewhere.",
+		WhenNotToUse: "Reading files the user could name instead - use the filesystem tools. Writing to the clipboard without the user asking, as it replaces what they had copied. Headless servers and containers, which have no clipboard.",
+		CommonPatterns: []string{
+			"Read the clipboard when the user says 'I've copied the error' instead of asking them to paste it",
+			"Read with a small max_bytes first to see what a large clipboard holds, then read more if needed",
+			"Write a generated command, snippet or message when the user asks for it on their clipboard",
+		},
+		ParameterDetails: map[string]string{
+			"function":  "read returns the clipboard's text. write replaces it with content.",
+			"max_bytes": fmt.Sprintf("Longer text is cut at a character boundary and truncated is set; bytes always gives the full size. The server's %s (default %d) is the most that can be read or written.", MaxBytesEnvVar, defaultMaxBytes),
+			"content":   "Plain text. Images and files cannot be written.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Read what the user copied",
+				Arguments:      map[string]any{"function": "read"},
+				ExpectedResult: "The clipboard's text with its size in bytes and lines",
+			},
+			{
+				Description:    "Copy a command for the user to paste",
+				Arguments:      map[string]any{"function": "write", "content": "git rebase --onto main feature~3 feature"},
+				ExpectedResult: "The number of bytes and lines copied",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No graphical session or clipboard command was found",
+				Solution: fmt.Sprintf("On Linux, install wl-clipboard for Wayland or xclip or xsel for X11. Over SSH or in a container there is no clipboard; the user can set %s and %s to other commands.", ReadCommandEnvVar, WriteCommandEnvVar),
+			},
+			{
+				Problem:  "The clipboard does not hold text",
+				Solution: "The user copied an image or files. Ask them to copy text, or to save the image and use its path.",
+			},
+		},
+	}
+}
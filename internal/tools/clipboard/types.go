@@ -0,0 +1,16 @@
+package clipboard
+
+// Result reports what was read from or written to the clipboard
+type Result struct {
+	Function string `json:"function"`
+	// Bytes is the size of the clipboard's text, or of the text written
+	Bytes int `json:"bytes"`
+	// Lines is the number of lines in content, or in the text written
+	Lines   int    `json:"lines"`
+	Content string `json:"content,omitempty"`
+	// Truncated is set when only the start of the clipboard's text is in content
+	Truncated bool `json:"truncated,omitempty"`
+	// Command is the clipboard command that was run, such as pbpaste or wl-copy
+	Command  string   `json:"command"`
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -142,7 +142,7 @@ func getOrCreateLSPClient(
 			age := time.Since(cachedClient.createdAt)
 			if age < 1*time.Minute {
 				// Check if client connection is still alive
-				if cachedClient.client != nil && cachedClient.client.conn != nil {
+				if cachedClient.client != nil && cachedClient.client.conn != nil && cachedClient.client.alive() {
 					cachedClient.lastUsed = time.Now()
 					logger.WithFields(logrus.Fields{
 						"language": server.Language,
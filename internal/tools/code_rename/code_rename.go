@@ -17,6 +17,7 @@ import (
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 	"go.lsp.dev/protocol"
 )
@@ -86,7 +87,7 @@ type renameParams struct {
 }
 
 // validateAndPrepareParams validates and prepares parameters from tool arguments
-func validateAndPrepareParams(args map[string]any) (*renameParams, error) {
+func validateAndPrepareParams(ctx context.Context, args map[string]any) (*renameParams, error) {
 	// Parse required parameters
 	filePath, ok := args["file_path"].(string)
 	if !ok || filePath == "" {
@@ -134,14 +135,14 @@ func validateAndPrepareParams(args map[string]any) (*renameParams, error) {
 		return nil, fmt.Errorf("line and column must be provided together for position-based lookup")
 	}
 
-	// Make path absolute
-	absPath, err := filepath.Abs(filePath)
+	// Make path absolute, relative to the session workspace when there is one
+	absPath, err := filepath.Abs(workspace.ResolvePath(ctx, filePath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
 	}
 
 	// Security: Check file access permission
-	if err := security.CheckFileAccess(absPath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, absPath); err != nil {
 		return nil, err
 	}
 
@@ -284,7 +285,7 @@ func performLSPRename(
 // Execute executes the tool's logic
 func (t *CodeRenameTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
 	// Validate and prepare parameters
-	params, err := validateAndPrepareParams(args)
+	params, err := validateAndPrepareParams(ctx, args)
 	if err != nil {
 		return nil, err
 	}
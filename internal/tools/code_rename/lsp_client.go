@@ -7,13 +7,14 @@ import (
 	"io"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 	"go.lsp.dev/jsonrpc2"
 	"go.lsp.dev/protocol"
@@ -45,15 +46,14 @@ func (rwc *readWriteCloser) Close() error {
 
 // LSPClient wraps an LSP server connection
 type LSPClient struct {
-	server       *LanguageServer
-	conn         jsonrpc2.Conn
-	cmd          *exec.Cmd
-	rootURI      string
-	logger       *logrus.Logger
-	serverCancel context.CancelFunc
-	openDocs     map[string]bool
-	docVersions  map[string]int32 // Track document versions for didChange
-	docMu        sync.Mutex
+	server      *LanguageServer
+	conn        jsonrpc2.Conn
+	process     *supervisor.Process
+	rootURI     string
+	logger      *logrus.Logger
+	openDocs    map[string]bool
+	docVersions map[string]int32 // Track document versions for didChange
+	docMu       sync.Mutex
 }
 
 // NewLSPClient creates and initialises a new LSP client
@@ -66,51 +66,18 @@ func NewLSPClient(ctx context.Context, logger *logrus.Logger, server *LanguageSe
 
 	rootURI := pathToURI(rootPath)
 
-	// Start LSP server process with background context
-	// Use background context so server lifetime isn't tied to MCP tool execution timeout
-	serverCtx, serverCancel := context.WithCancel(context.Background())
-	cmd := exec.CommandContext(serverCtx, server.Command, server.Args...)
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		serverCancel()
-		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
-	}
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		serverCancel()
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
-
-	stderr, err := cmd.StderrPipe()
+	// Start the LSP server under the supervisor, which keeps it running beyond the MCP tool call's
+	// timeout and stops it at shutdown if the client is never closed. A crashed server is not
+	// restarted, as the client cache sees it has exited and starts a new client.
+	process, err := supervisor.Start(supervisor.Spec{
+		Name: "lsp " + server.Command,
+		Path: server.Command,
+		Args: server.Args,
+	})
 	if err != nil {
-		serverCancel()
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		serverCancel()
 		return nil, fmt.Errorf("failed to start LSP server: %w", err)
 	}
-
-	// Register process with tracker
-	tracker := GetProcessTracker(logger)
-	tracker.Register(cmd.Process.Pid, server.Command, cmd.Process)
-
-	// Log stderr output in background for debugging
-	go func() {
-		buf := make([]byte, 1024)
-		for {
-			n, err := stderr.Read(buf)
-			if n > 0 {
-				logger.WithField("server", server.Command).Debugf("LSP stderr: %s", string(buf[:n]))
-			}
-			if err != nil {
-				break
-			}
-		}
-	}()
+	stdin, stdout := process.Stdin(), process.Stdout()
 
 	// Create JSON-RPC connection
 	// Combine stdout (reader) and stdin (writer) into a single ReadWriteCloser
@@ -118,14 +85,13 @@ func NewLSPClient(ctx context.Context, logger *logrus.Logger, server *LanguageSe
 	conn := jsonrpc2.NewConn(jsonrpc2.NewStream(stream))
 
 	client := &LSPClient{
-		server:       server,
-		conn:         conn,
-		cmd:          cmd,
-		rootURI:      rootURI,
-		logger:       logger,
-		serverCancel: serverCancel,
-		openDocs:     make(map[string]bool),
-		docVersions:  make(map[string]int32),
+		server:      server,
+		conn:        conn,
+		process:     process,
+		rootURI:     rootURI,
+		logger:      logger,
+		openDocs:    make(map[string]bool),
+		docVersions: make(map[string]int32),
 	}
 
 	// Start the message pump with a handler for server->client messages
@@ -209,7 +175,7 @@ func (c *LSPClient) openDocument(ctx context.Context, filePath string) error {
 	defer c.docMu.Unlock()
 
 	// Security: Check file access permission
-	if err := security.CheckFileAccess(filePath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, filePath); err != nil {
 		return fmt.Errorf("access denied: %w", err)
 	}
 
@@ -254,7 +220,7 @@ func (c *LSPClient) SyncDocument(ctx context.Context, filePath string) error {
 	}
 
 	// Security: Check file access permission
-	if err := security.CheckFileAccess(filePath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, filePath); err != nil {
 		return fmt.Errorf("access denied: %w", err)
 	}
 
@@ -396,24 +362,13 @@ func (c *LSPClient) Close() (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			c.logger.WithField("panic", r).Error("Panic during LSP client close, attempting cleanup")
-			// Cancel context if not already done
-			if c.serverCancel != nil {
-				c.serverCancel()
-			}
-			// Still try to kill the process
-			if c.cmd != nil && c.cmd.Process != nil {
-				_ = c.cmd.Process.Kill()
-				tracker := GetProcessTracker(c.logger)
-				tracker.Deregister(c.cmd.Process.Pid)
+			// Still try to stop the process
+			if c.process != nil {
+				c.process.Stop(0)
 			}
 		}
 	}()
 
-	pid := 0
-	if c.cmd != nil && c.cmd.Process != nil {
-		pid = c.cmd.Process.Pid
-	}
-
 	if c.conn != nil {
 		// Send shutdown request
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -424,35 +379,27 @@ func (c *LSPClient) Close() (err error) {
 		_ = c.conn.Close()
 	}
 
-	// Cancel the server context to signal graceful shutdown
-	if c.serverCancel != nil {
-		c.serverCancel()
-	}
-
-	if c.cmd != nil && c.cmd.Process != nil {
-		// Wait briefly for graceful shutdown
-		done := make(chan error, 1)
-		go func() {
-			done <- c.cmd.Wait()
-		}()
-
-		select {
-		case <-time.After(1 * time.Second):
-			// Timeout waiting for graceful shutdown, force kill
-			_ = c.cmd.Process.Kill()
-			_ = c.cmd.Wait()
-		case <-done:
-			// Process exited gracefully
+	if c.process != nil {
+		// Give the server a moment to exit after the exit notification before it is killed
+		c.process.Stop(1 * time.Second)
+		if stderr := c.process.Stderr(); stderr != "" {
+			c.logger.WithField("server", c.server.Command).Debugf("LSP stderr: %s", stderr)
 		}
-
-		// Deregister from tracker
-		tracker := GetProcessTracker(c.logger)
-		tracker.Deregister(pid)
 	}
 
 	return nil
 }
 
+// alive reports whether the LSP server process is still running
+func (c *LSPClient) alive() bool {
+	select {
+	case <-c.process.Done():
+		return false
+	default:
+		return true
+	}
+}
+
 // findWorkspaceRoot attempts to find the workspace root
 // It looks for common markers like .git, go.mod, package.json, etc.
 func findWorkspaceRoot(filePath string) (string, error) {
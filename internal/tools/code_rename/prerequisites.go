@@ -0,0 +1,47 @@
+package code_rename
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/tools"
+)
+
+// CheckPrerequisites reports which LSP servers are installed. Each missing server only affects its own
+// languages, so it is a warning unless no server is installed at all.
+func (t *CodeRenameTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	// Group languages by server command, keeping the order servers are listed in
+	var commands []string
+	languages := make(map[string][]string)
+	for _, server := range SupportedServers {
+		if _, seen := languages[server.Command]; !seen {
+			commands = append(commands, server.Command)
+		}
+		languages[server.Command] = append(languages[server.Command], server.Language)
+	}
+
+	var checks []tools.PrerequisiteCheck
+	found := 0
+	for _, command := range commands {
+		check := tools.PrerequisiteCheck{Name: command}
+		if path, err := exec.LookPath(command); err == nil {
+			found++
+			check.Status = tools.PrerequisiteOK
+			check.Detail = fmt.Sprintf("found at %s (%s)", path, strings.Join(languages[command], ", "))
+		} else {
+			check.Status = tools.PrerequisiteWarning
+			check.Detail = fmt.Sprintf("not found on PATH, renaming is unavailable for %s", strings.Join(languages[command], ", "))
+			check.Remediation = getInstallCommand(languages[command][0])
+		}
+		checks = append(checks, check)
+	}
+
+	if found == 0 {
+		for i := range checks {
+			checks[i].Status = tools.PrerequisiteFailed
+		}
+	}
+	return checks
+}
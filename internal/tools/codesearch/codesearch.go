@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/tools"
 	"github.com/sammcj/mcp-devtools/internal/tools/codesearch/filetracker"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -79,6 +81,11 @@ func (t *CodeSearchTool) Execute(ctx context.Context, logger *logrus.Logger, cac
 	if err != nil {
 		return nil, err
 	}
+	for _, source := range req.Source {
+		if err := workspace.CheckFileAccess(ctx, source); err != nil {
+			return nil, err
+		}
+	}
 
 	// Initialise components on first use
 	t.initOnce.Do(func() {
@@ -191,6 +198,10 @@ func (t *CodeSearchTool) executeSearch(ctx context.Context, req *SearchRequest,
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
 
+	// The index can hold code from paths other callers indexed, so hits this caller may not read are left out
+	results = slices.DeleteFunc(results, func(result SearchResult) bool {
+		return workspace.CheckFileAccess(ctx, result.Path) != nil
+	})
 	response := &SearchResponse{
 		Results: results,
 	}
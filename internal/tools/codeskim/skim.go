@@ -15,8 +15,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/registry"
-	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -80,7 +80,7 @@ func (t *CodeSkimTool) Execute(ctx context.Context, logger *logrus.Logger, cache
 	startTime := time.Now()
 
 	// Parse request
-	req, err := t.parseRequest(args)
+	req, err := t.parseRequest(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +131,7 @@ func (t *CodeSkimTool) processFilesParallel(ctx context.Context, files []string,
 	// If only one file, process directly without goroutines
 	if len(files) == 1 {
 		filePath := files[0]
-		if err := security.CheckFileAccess(filePath); err != nil {
+		if err := workspace.CheckFileAccess(ctx, filePath); err != nil {
 			return []FileResult{{
 				Path:  filePath,
 				Error: fmt.Sprintf("access denied: %v", err),
@@ -178,7 +178,7 @@ func (t *CodeSkimTool) processFilesParallel(ctx context.Context, files []string,
 		wg.Go(func() {
 			for j := range jobs {
 				// Security check for each file
-				if err := security.CheckFileAccess(j.path); err != nil {
+				if err := workspace.CheckFileAccess(ctx, j.path); err != nil {
 					results[j.index] = FileResult{
 						Path:  j.path,
 						Error: fmt.Sprintf("access denied: %v", err),
@@ -411,7 +411,7 @@ func (t *CodeSkimTool) processFile(ctx context.Context, filePath string, req *Sk
 }
 
 // parseRequest parses and validates the tool arguments
-func (t *CodeSkimTool) parseRequest(args map[string]any) (*SkimRequest, error) {
+func (t *CodeSkimTool) parseRequest(ctx context.Context, args map[string]any) (*SkimRequest, error) {
 	req := &SkimRequest{}
 
 	// Parse source (required) - array of strings
@@ -443,8 +443,10 @@ func (t *CodeSkimTool) parseRequest(args map[string]any) (*SkimRequest, error) {
 		sources = append(sources, str)
 	}
 
-	// Convert all sources to absolute paths
+	// Convert all sources to absolute paths, relative to the session workspace when there is one
 	for i, source := range sources {
+		source = workspace.ResolvePath(ctx, source)
+		sources[i] = source
 		if !filepath.IsAbs(source) {
 			absPath, err := filepath.Abs(source)
 			if err != nil {
@@ -0,0 +1,582 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RuntimeEnvVar overrides container CLI discovery with an explicit docker or podman path
+	RuntimeEnvVar = "CONTAINERS_RUNTIME"
+	// AllowManageEnvVar enables the run and stop functions when set to true
+	AllowManageEnvVar = "CONTAINERS_ALLOW_MANAGE"
+
+	commandTimeout    = 60 * time.Second
+	defaultTailLines  = 100
+	maxTailLines      = 2000
+	maxLogBytes       = 128 * 1024
+	maxDockerfileSize = 1024 * 1024
+	maxLayerCommand   = 200
+)
+
+var (
+	// portMappingPattern matches publish specs such as 8080:80, 127.0.0.1:8080:80 or 53:53/udp
+	portMappingPattern = regexp.MustCompile(`^[0-9.:\[\]a-fA-F]+(/(tcp|udp|sctp))?$`)
+	// envAssignmentPattern matches NAME=value environment assignments
+	envAssignmentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+	// containerNamePattern matches names accepted by docker run --name
+	containerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	// sinceDurationPattern matches relative durations such as 10m or 2h30m
+	sinceDurationPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h)([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))*$`)
+)
+
+// ContainersTool inspects local container images, containers and Dockerfiles
+type ContainersTool struct{}
+
+// init registers the containers tool
+func init() {
+	registry.Register(&ContainersTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ContainersTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"containers",
+		mcp.WithDescription(`Inspect local Docker or Podman images and containers, tail container logs and summarise Dockerfile build stages.
+
+Functions:
+- list_images: local images
+- list_containers: running containers (all: true includes stopped ones)
+- inspect_image: image config, labels, environment (secrets redacted) and layers
+- logs: the last lines of a container's logs
+- dockerfile: build stages, base images and common issues in a Dockerfile
+- run / stop: start or stop containers - only available when the server sets CONTAINERS_ALLOW_MANAGE=true`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("list_images", "list_containers", "inspect_image", "logs", "dockerfile", "run", "stop"),
+		),
+		mcp.WithString("image",
+			mcp.Description("Image reference for inspect_image and run (e.g. nginx:1.27)"),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container name or ID for logs and stop"),
+		),
+		mcp.WithBoolean("all",
+			mcp.Description("list_containers: include stopped containers"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("tail",
+			mcp.Description(fmt.Sprintf("logs: number of lines from the end (default: %d, max: %d)", defaultTailLines, maxTailLines)),
+		),
+		mcp.WithString("since",
+			mcp.Description("logs: only show logs newer than a relative duration (e.g. 10m, 2h)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("dockerfile: absolute path to the Dockerfile"),
+		),
+		mcp.WithString("name",
+			mcp.Description("run: container name"),
+		),
+		mcp.WithArray("ports",
+			mcp.Description("run: port mappings such as 8080:80"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("env",
+			mcp.Description("run: environment variables as NAME=value"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("command",
+			mcp.Description("run: command and arguments to run instead of the image default"),
+			mcp.WithStringItems(),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // run and stop change container state when enabled
+		mcp.WithDestructiveHintAnnotation(true), // stop can terminate running containers when enabled
+		mcp.WithIdempotentHintAnnotation(false), // run creates a new container each time
+		mcp.WithOpenWorldHintAnnotation(true),   // run may pull images from registries
+	)
+}
+
+// Execute runs the requested function
+func (t *ContainersTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+
+	// Dockerfile summaries do not need a container runtime
+	if function == "dockerfile" {
+		return t.dockerfile(ctx, args)
+	}
+
+	runtime, err := findRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"function": function,
+		"runtime":  runtimeName(runtime),
+	}).Debug("Running containers function")
+
+	var result any
+	switch function {
+	case "list_images":
+		result, err = listImages(ctx, runtime)
+	case "list_containers":
+		all, _ := args["all"].(bool)
+		result, err = listContainers(ctx, runtime, all)
+	case "inspect_image":
+		image, _ := args["image"].(string)
+		result, err = inspectImage(ctx, runtime, strings.TrimSpace(image))
+	case "logs":
+		result, err = containerLogs(ctx, logger, runtime, args)
+	case "run", "stop":
+		if !manageAllowed() {
+			return nil, fmt.Errorf("%s is disabled - the containers tool is read-only unless the server sets %s=true", function, AllowManageEnvVar)
+		}
+		if function == "run" {
+			result, err = runContainer(ctx, runtime, args)
+		} else {
+			container, _ := args["container"].(string)
+			result, err = stopContainer(ctx, runtime, strings.TrimSpace(container))
+		}
+	default:
+		return nil, fmt.Errorf("unknown function: %s", function)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// manageAllowed reports whether run and stop are enabled
+func manageAllowed() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(AllowManageEnvVar)))
+	return value == "true" || value == "1"
+}
+
+// dockerfile summarises a Dockerfile's build stages
+func (t *ContainersTool) dockerfile(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", path)
+	}
+	if err := workspace.CheckFileAccess(ctx, path); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+
+	summary, err := parseDockerfile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// listImages returns the local images
+func listImages(ctx context.Context, runtime string) ([]ImageSummary, error) {
+	output, err := runCLI(ctx, runtime, "images", "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeJSONLines(output)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]ImageSummary, 0, len(items))
+	for _, item := range items {
+		images = append(images, ImageSummary{
+			Repository: stringField(item, "Repository"),
+			Tag:        stringField(item, "Tag"),
+			ID:         shortID(stringField(item, "ID", "Id")),
+			Size:       stringField(item, "Size"),
+			Created:    stringField(item, "CreatedSince", "CreatedAt", "Created"),
+		})
+	}
+	return images, nil
+}
+
+// listContainers returns running containers, or all containers when all is set
+func listContainers(ctx context.Context, runtime string, all bool) ([]ContainerSummary, error) {
+	cliArgs := []string{"ps", "--format", "{{json .}}"}
+	if all {
+		cliArgs = append(cliArgs, "--all")
+	}
+	output, err := runCLI(ctx, runtime, cliArgs...)
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeJSONLines(output)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]ContainerSummary, 0, len(items))
+	for _, item := range items {
+		containers = append(containers, ContainerSummary{
+			Name:   stringField(item, "Names", "Name"),
+			ID:     shortID(stringField(item, "ID", "Id")),
+			Image:  stringField(item, "Image"),
+			State:  stringField(item, "State"),
+			Status: stringField(item, "Status"),
+			Ports:  stringField(item, "Ports"),
+		})
+	}
+	return containers, nil
+}
+
+// inspectImage summarises an image's configuration and layer history
+func inspectImage(ctx context.Context, runtime, image string) (*ImageDetails, error) {
+	if err := validateRef("image", image); err != nil {
+		return nil, err
+	}
+
+	output, err := runCLI(ctx, runtime, "image", "inspect", image)
+	if err != nil {
+		return nil, err
+	}
+	var inspected []struct {
+		ID           string   `json:"Id"`
+		RepoTags     []string `json:"RepoTags"`
+		RepoDigests  []string `json:"RepoDigests"`
+		Created      string   `json:"Created"`
+		Os           string   `json:"Os"`
+		Architecture string   `json:"Architecture"`
+		Variant      string   `json:"Variant"`
+		Size         int64    `json:"Size"`
+		Config       struct {
+			User         string            `json:"User"`
+			WorkingDir   string            `json:"WorkingDir"`
+			Entrypoint   []string          `json:"Entrypoint"`
+			Cmd          []string          `json:"Cmd"`
+			ExposedPorts map[string]any    `json:"ExposedPorts"`
+			Env          []string          `json:"Env"`
+			Labels       map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal(output, &inspected); err != nil {
+		return nil, fmt.Errorf("failed to parse image inspect output: %w", err)
+	}
+	if len(inspected) == 0 {
+		return nil, fmt.Errorf("image %s not found", image)
+	}
+	info := inspected[0]
+
+	details := &ImageDetails{
+		ID:         shortID(info.ID),
+		Tags:       info.RepoTags,
+		Digests:    info.RepoDigests,
+		Created:    formatTimestamp(info.Created),
+		SizeBytes:  info.Size,
+		User:       info.Config.User,
+		WorkingDir: info.Config.WorkingDir,
+		Entrypoint: info.Config.Entrypoint,
+		Cmd:        info.Config.Cmd,
+		Env:        redactEnv(info.Config.Env),
+		Labels:     info.Config.Labels,
+	}
+	if info.Os != "" {
+		details.Platform = info.Os + "/" + info.Architecture
+		if info.Variant != "" {
+			details.Platform += "/" + info.Variant
+		}
+	}
+	for port := range info.Config.ExposedPorts {
+		details.ExposedPorts = append(details.ExposedPorts, port)
+	}
+	slices.Sort(details.ExposedPorts)
+
+	history, err := runCLI(ctx, runtime, "history", "--no-trunc", "--format", "{{json .}}", image)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := decodeJSONLines(history)
+	if err != nil {
+		return nil, err
+	}
+	// History is newest first; layers are listed in build order
+	for _, layer := range slices.Backward(layers) {
+		createdBy := strings.Join(strings.Fields(stringField(layer, "CreatedBy")), " ")
+		createdBy = strings.TrimPrefix(createdBy, "/bin/sh -c #(nop) ")
+		if len([]rune(createdBy)) > maxLayerCommand {
+			createdBy = string([]rune(createdBy)[:maxLayerCommand]) + "…"
+		}
+		details.Layers = append(details.Layers, ImageLayer{CreatedBy: createdBy, Size: stringField(layer, "Size")})
+	}
+
+	return details, nil
+}
+
+// containerLogs returns the tail of a container's logs, capped in lines and bytes
+func containerLogs(ctx context.Context, logger *logrus.Logger, runtime string, args map[string]any) (*LogsResponse, error) {
+	container, _ := args["container"].(string)
+	container = strings.TrimSpace(container)
+	if err := validateRef("container", container); err != nil {
+		return nil, err
+	}
+
+	tail := defaultTailLines
+	if n, ok := args["tail"].(float64); ok {
+		if n < 1 || n > maxTailLines {
+			return nil, fmt.Errorf("tail must be between 1 and %d", maxTailLines)
+		}
+		tail = int(n)
+	}
+	cliArgs := []string{"logs", "--tail", fmt.Sprint(tail)}
+	if since, _ := args["since"].(string); since != "" {
+		if !sinceDurationPattern.MatchString(since) {
+			return nil, fmt.Errorf("since must be a relative duration such as 10m or 2h, got: %s", since)
+		}
+		cliArgs = append(cliArgs, "--since", since)
+	}
+	cliArgs = append(cliArgs, container)
+
+	output, err := runLogs(ctx, runtime, cliArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LogsResponse{Container: container}
+	logs := string(output)
+	if len(logs) > maxLogBytes {
+		// Keep the most recent output, starting at a line boundary
+		logs = logs[len(logs)-maxLogBytes:]
+		if i := strings.IndexByte(logs, '\n'); i >= 0 {
+			logs = logs[i+1:]
+		}
+		response.Truncated = true
+	}
+	response.Logs = strings.ToValidUTF8(logs, "�")
+	response.Lines = strings.Count(strings.TrimRight(response.Logs, "\n"), "\n")
+	if response.Logs != "" {
+		response.Lines++
+	}
+
+	sourceCtx := security.SourceContext{
+		Tool:        "containers",
+		Domain:      container,
+		ContentType: "container_logs",
+	}
+	if result, err := security.AnalyseContent(response.Logs, sourceCtx); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			logger.WithField("security_id", result.ID).Warn("Security warning for container logs")
+		}
+	}
+
+	return response, nil
+}
+
+// runContainer starts a detached container from an image
+func runContainer(ctx context.Context, runtime string, args map[string]any) (*ActionResponse, error) {
+	image, _ := args["image"].(string)
+	image = strings.TrimSpace(image)
+	if err := validateRef("image", image); err != nil {
+		return nil, err
+	}
+
+	cliArgs := []string{"run", "--detach"}
+	if name, _ := args["name"].(string); name != "" {
+		if !containerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid container name: %s", name)
+		}
+		cliArgs = append(cliArgs, "--name", name)
+	}
+	ports, err := stringList(args, "ports")
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range ports {
+		if !portMappingPattern.MatchString(port) {
+			return nil, fmt.Errorf("invalid port mapping: %s (expected a form such as 8080:80)", port)
+		}
+		cliArgs = append(cliArgs, "--publish", port)
+	}
+	env, err := stringList(args, "env")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range env {
+		if !envAssignmentPattern.MatchString(entry) {
+			return nil, fmt.Errorf("invalid environment variable: %s (expected NAME=value)", strings.SplitN(entry, "=", 2)[0])
+		}
+		cliArgs = append(cliArgs, "--env", entry)
+	}
+	command, err := stringList(args, "command")
+	if err != nil {
+		return nil, err
+	}
+	// Everything after the image is passed to the container, not parsed as CLI flags
+	cliArgs = append(cliArgs, image)
+	cliArgs = append(cliArgs, command...)
+
+	output, err := runCLI(ctx, runtime, cliArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionResponse{Action: "run", Container: shortID(strings.TrimSpace(string(output))), Message: "Container started from " + image}, nil
+}
+
+// stopContainer stops a running container
+func stopContainer(ctx context.Context, runtime, container string) (*ActionResponse, error) {
+	if err := validateRef("container", container); err != nil {
+		return nil, err
+	}
+	if _, err := runCLI(ctx, runtime, "stop", container); err != nil {
+		return nil, err
+	}
+	return &ActionResponse{Action: "stop", Container: container, Message: "Container stopped"}, nil
+}
+
+// stringList reads an optional array of strings from the arguments
+func stringList(args map[string]any, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// stringField returns the first non-empty field, joining arrays such as Podman's Names
+func stringField(item map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch v := item[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case []any:
+			parts := make([]string, 0, len(v))
+			for _, p := range v {
+				parts = append(parts, fmt.Sprint(p))
+			}
+			if len(parts) > 0 {
+				return strings.Join(parts, ", ")
+			}
+		case float64:
+			return fmt.Sprint(int64(v))
+		}
+	}
+	return ""
+}
+
+// shortID trims a sha256: prefix and shortens full-length IDs to 12 characters
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) == 64 {
+		return id[:12]
+	}
+	return id
+}
+
+// ProvideExtendedInfo provides detailed usage information for the containers tool
+func (t *ContainersTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "List running and stopped containers",
+				Arguments: map[string]any{
+					"function": "list_containers",
+					"all":      true,
+				},
+				ExpectedResult: "Container names, IDs, images, states and port mappings",
+			},
+			{
+				Description: "Inspect an image's layers, labels and environment",
+				Arguments: map[string]any{
+					"function": "inspect_image",
+					"image":    "nginx:1.27",
+				},
+				ExpectedResult: "Image config with secret-looking environment values redacted and layers in build order",
+			},
+			{
+				Description: "Read recent logs from a failing container",
+				Arguments: map[string]any{
+					"function":  "logs",
+					"container": "api",
+					"tail":      200,
+					"since":     "15m",
+				},
+				ExpectedResult: "Up to 200 lines from the last 15 minutes",
+			},
+			{
+				Description: "Review a multi-stage Dockerfile",
+				Arguments: map[string]any{
+					"function": "dockerfile",
+					"path":     "/Users/name/project/Dockerfile",
+				},
+				ExpectedResult: "Stages with base images, COPY --from dependencies, exposed ports, users and warnings",
+			},
+		},
+		CommonPatterns: []string{
+			"Use list_containers with all: true to find containers that exited, then logs to see why",
+			"Run dockerfile before building to spot unpinned base images and root users",
+			"Use inspect_image to confirm the entrypoint, user and exposed ports of a built image",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No container runtime found",
+				Solution: "Install Docker or Podman, or set CONTAINERS_RUNTIME to the CLI path",
+			},
+			{
+				Problem:  "run or stop is disabled",
+				Solution: "The tool is read-only by default. Set CONTAINERS_ALLOW_MANAGE=true in the server environment to enable them",
+			},
+			{
+				Problem:  "Cannot connect to the Docker daemon",
+				Solution: "Start Docker Desktop or the Docker service, or check that DOCKER_HOST points at a running daemon",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"tail":    "Logs are also capped at 128KB, keeping the most recent output",
+			"command": "Passed to the container after the image, so entries are never interpreted as runtime flags",
+			"env":     "For run only. Image environment shown by inspect_image has values for names like PASSWORD, TOKEN or SECRET redacted",
+		},
+		WhenToUse:    "Debugging local containers, reviewing images and Dockerfiles, and checking container logs",
+		WhenNotToUse: "Building images, managing Kubernetes workloads or remote registries",
+	}
+}
@@ -0,0 +1,200 @@
+package containers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// logicalLine is a Dockerfile instruction with continuation lines joined
+type logicalLine struct {
+	number      int // line number where the instruction starts
+	instruction string
+	args        string
+}
+
+// parseDockerfile summarises the build stages of a Dockerfile
+func parseDockerfile(path string) (*DockerfileSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+	if len(data) > maxDockerfileSize {
+		return nil, fmt.Errorf("dockerfile is %d bytes, maximum is %d", len(data), maxDockerfileSize)
+	}
+
+	summary := &DockerfileSummary{Path: path}
+	lines, syntax := splitInstructions(string(data))
+	summary.Syntax = syntax
+
+	var stage *DockerfileStage
+	for _, line := range lines {
+		if line.instruction == "FROM" {
+			summary.Stages = append(summary.Stages, parseFrom(line, len(summary.Stages), summary.Stages))
+			stage = &summary.Stages[len(summary.Stages)-1]
+			continue
+		}
+		if stage == nil {
+			if line.instruction == "ARG" {
+				summary.GlobalArgs = append(summary.GlobalArgs, line.args)
+			}
+			continue
+		}
+
+		stage.Instructions[line.instruction]++
+		switch line.instruction {
+		case "COPY", "ADD":
+			for field := range strings.FieldsSeq(line.args) {
+				if from, ok := strings.CutPrefix(field, "--from="); ok && !slices.Contains(stage.CopiesFrom, from) {
+					stage.CopiesFrom = append(stage.CopiesFrom, from)
+				}
+			}
+		case "EXPOSE":
+			stage.Exposes = append(stage.Exposes, strings.Fields(line.args)...)
+		case "USER":
+			stage.User = line.args
+		case "WORKDIR":
+			stage.Workdir = line.args
+		case "ENTRYPOINT":
+			stage.Entrypoint = line.args
+		case "CMD":
+			stage.Cmd = line.args
+		case "HEALTHCHECK":
+			stage.Healthcheck = !strings.EqualFold(strings.TrimSpace(line.args), "NONE")
+		case "ENV", "ARG":
+			for _, name := range declaredNames(line.instruction, line.args) {
+				if secretEnvPattern.MatchString(name) {
+					summary.Warnings = append(summary.Warnings, fmt.Sprintf("line %d: %s %s may bake a secret into the image - use build secrets instead", line.number, line.instruction, name))
+				}
+			}
+		}
+	}
+
+	if len(summary.Stages) == 0 {
+		return nil, fmt.Errorf("no FROM instruction found in %s", path)
+	}
+
+	final := summary.Stages[len(summary.Stages)-1]
+	summary.FinalStage = final.Name
+	if summary.FinalStage == "" {
+		summary.FinalStage = fmt.Sprintf("stage %d", final.Index)
+	}
+	for _, s := range summary.Stages {
+		if s.BaseStage == "" && s.BaseImage != "scratch" && !hasPinnedTag(s.BaseImage) {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("line %d: base image %s is not pinned to a tag or digest", s.Line, s.BaseImage))
+		}
+	}
+	if final.User == "" || final.User == "root" || final.User == "0" {
+		summary.Warnings = append(summary.Warnings, "final stage runs as root - add a USER instruction")
+	}
+
+	return summary, nil
+}
+
+// splitInstructions joins continuation lines, drops comments and returns the syntax directive
+func splitInstructions(content string) ([]logicalLine, string) {
+	escape := '\\'
+	syntax := ""
+	var lines []logicalLine
+	var current strings.Builder
+	start := 0
+	directives := true
+
+	for i, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+
+		// Parser directives are only recognised before any other content
+		if directives && strings.HasPrefix(trimmed, "#") {
+			key, value, found := strings.Cut(strings.TrimSpace(trimmed[1:]), "=")
+			if found {
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "syntax":
+					syntax = strings.TrimSpace(value)
+					continue
+				case "escape":
+					if v := strings.TrimSpace(value); v == "`" {
+						escape = '`'
+					}
+					continue
+				}
+			}
+		}
+		directives = false
+
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && current.Len() == 0) {
+			continue
+		}
+		if current.Len() == 0 {
+			start = i + 1
+		}
+
+		if strings.HasSuffix(trimmed, string(escape)) {
+			current.WriteString(strings.TrimSuffix(trimmed, string(escape)) + " ")
+			continue
+		}
+		current.WriteString(trimmed)
+
+		text := strings.TrimSpace(current.String())
+		current.Reset()
+		if text == "" {
+			continue
+		}
+		instruction, args, _ := strings.Cut(text, " ")
+		lines = append(lines, logicalLine{number: start, instruction: strings.ToUpper(instruction), args: strings.Join(strings.Fields(args), " ")})
+	}
+	return lines, syntax
+}
+
+// parseFrom parses a FROM instruction into a new stage
+func parseFrom(line logicalLine, index int, previous []DockerfileStage) DockerfileStage {
+	stage := DockerfileStage{Index: index, Line: line.number, Instructions: make(map[string]int)}
+	fields := strings.Fields(line.args)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case strings.HasPrefix(field, "--platform="):
+			stage.Platform = strings.TrimPrefix(field, "--platform=")
+		case strings.HasPrefix(field, "--"):
+			continue
+		case strings.EqualFold(field, "AS") && i+1 < len(fields):
+			stage.Name = fields[i+1]
+			i++
+		case stage.BaseImage == "":
+			stage.BaseImage = field
+		}
+	}
+	for _, p := range previous {
+		if p.Name != "" && strings.EqualFold(p.Name, stage.BaseImage) {
+			stage.BaseStage = p.Name
+		}
+	}
+	return stage
+}
+
+// declaredNames returns the variable names declared by an ENV or ARG instruction
+func declaredNames(instruction, args string) []string {
+	fields := strings.Fields(args)
+	// Legacy "ENV NAME value" form declares a single variable
+	if instruction == "ENV" && len(fields) > 1 && !strings.Contains(fields[0], "=") {
+		return fields[:1]
+	}
+	var names []string
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, "=")
+		if name != "" && !strings.ContainsAny(name, `"'`) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasPinnedTag reports whether an image reference has an explicit, non-latest tag or a digest
+func hasPinnedTag(image string) bool {
+	if strings.Contains(image, "@") || strings.Contains(image, "$") {
+		return true // digests and build-arg substitutions are treated as pinned
+	}
+	lastSegment := image[strings.LastIndex(image, "/")+1:]
+	_, tag, found := strings.Cut(lastSegment, ":")
+	return found && tag != "latest"
+}
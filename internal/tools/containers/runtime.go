@@ -0,0 +1,149 @@
+package containers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// secretEnvPattern matches environment variable names whose values should not be shown
+var secretEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTH)`)
+
+// containerRefPattern restricts image and container references to characters the
+// runtime accepts, so a reference can never be mistaken for a flag
+var containerRefPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/@+-]*$`)
+
+// findRuntime locates the container CLI, preferring CONTAINERS_RUNTIME, then docker, then podman
+func findRuntime() (string, error) {
+	if runtime := os.Getenv(RuntimeEnvVar); runtime != "" {
+		path, err := exec.LookPath(runtime)
+		if err != nil {
+			return "", fmt.Errorf("%s is set to %s but it cannot be found: %w", RuntimeEnvVar, runtime, err)
+		}
+		return path, nil
+	}
+	for _, name := range []string{"docker", "podman"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no container runtime found - install Docker or Podman, or set %s to the CLI path", RuntimeEnvVar)
+}
+
+// runCLI runs the container CLI and returns stdout, including stderr in errors
+func runCLI(ctx context.Context, runtime string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, runtime, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s %s timed out after %s", runtimeName(runtime), args[0], commandTimeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("%s %s failed: %s", runtimeName(runtime), args[0], message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// runLogs runs a logs command, which writes the container's stdout and stderr to the
+// matching streams, and returns both interleaved
+func runLogs(ctx context.Context, runtime string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, runtime, args...)
+	var combined bytes.Buffer
+	cmd.Stdout = &combined
+	cmd.Stderr = &combined
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s logs timed out after %s", runtimeName(runtime), commandTimeout)
+		}
+		return nil, fmt.Errorf("%s logs failed: %s", runtimeName(runtime), lastLine(combined.String(), err))
+	}
+	return combined.Bytes(), nil
+}
+
+// decodeJSONLines decodes the one-object-per-line output of --format '{{json .}}'
+func decodeJSONLines(data []byte) ([]map[string]any, error) {
+	var items []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item map[string]any
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("failed to parse runtime output: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
+
+// validateRef checks an image or container reference before it is passed to the CLI
+func validateRef(kind, ref string) error {
+	if ref == "" {
+		return fmt.Errorf("missing required parameter: %s", kind)
+	}
+	if !containerRefPattern.MatchString(ref) {
+		return fmt.Errorf("invalid %s reference: %s", kind, ref)
+	}
+	return nil
+}
+
+// redactEnv hides the values of environment variables that look like secrets
+func redactEnv(env []string) []string {
+	redacted := make([]string, 0, len(env))
+	for _, entry := range env {
+		name, _, found := strings.Cut(entry, "=")
+		if found && secretEnvPattern.MatchString(name) {
+			entry = name + "=[REDACTED]"
+		}
+		redacted = append(redacted, entry)
+	}
+	return redacted
+}
+
+// runtimeName returns the CLI's base name for messages
+func runtimeName(runtime string) string {
+	if i := strings.LastIndexAny(runtime, `/\`); i >= 0 {
+		return runtime[i+1:]
+	}
+	return runtime
+}
+
+// lastLine returns the final non-empty line of output, or the error when there is none
+func lastLine(output string, err error) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if line := strings.TrimSpace(lines[len(lines)-1]); line != "" {
+		return line
+	}
+	return err.Error()
+}
+
+// formatTimestamp normalises RFC 3339 timestamps from inspect output
+func formatTimestamp(value string) string {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return value
+}
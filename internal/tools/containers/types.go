@@ -0,0 +1,87 @@
+package containers
+
+// ImageSummary is a local image from the image list
+type ImageSummary struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	ID         string `json:"id"`
+	Size       string `json:"size"`
+	Created    string `json:"created"`
+}
+
+// ContainerSummary is a container from the container list
+type ContainerSummary struct {
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Image  string `json:"image"`
+	State  string `json:"state,omitempty"`
+	Status string `json:"status"`
+	Ports  string `json:"ports,omitempty"`
+}
+
+// ImageDetails is the summarised result of inspecting an image
+type ImageDetails struct {
+	ID           string            `json:"id"`
+	Tags         []string          `json:"tags,omitempty"`
+	Digests      []string          `json:"digests,omitempty"`
+	Created      string            `json:"created,omitempty"`
+	Platform     string            `json:"platform,omitempty"`
+	SizeBytes    int64             `json:"size_bytes"`
+	User         string            `json:"user,omitempty"`
+	WorkingDir   string            `json:"working_dir,omitempty"`
+	Entrypoint   []string          `json:"entrypoint,omitempty"`
+	Cmd          []string          `json:"cmd,omitempty"`
+	ExposedPorts []string          `json:"exposed_ports,omitempty"`
+	Env          []string          `json:"env,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Layers       []ImageLayer      `json:"layers,omitempty"`
+}
+
+// ImageLayer is one entry from the image history
+type ImageLayer struct {
+	CreatedBy string `json:"created_by"`
+	Size      string `json:"size"`
+}
+
+// LogsResponse holds the tail of a container's logs
+type LogsResponse struct {
+	Container string `json:"container"`
+	Lines     int    `json:"lines"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Logs      string `json:"logs"`
+}
+
+// DockerfileSummary describes the build stages of a Dockerfile
+type DockerfileSummary struct {
+	Path       string            `json:"path"`
+	Syntax     string            `json:"syntax,omitempty"`
+	GlobalArgs []string          `json:"global_args,omitempty"`
+	Stages     []DockerfileStage `json:"stages"`
+	FinalStage string            `json:"final_stage"`
+	Warnings   []string          `json:"warnings,omitempty"`
+}
+
+// DockerfileStage is a single FROM stage in a Dockerfile
+type DockerfileStage struct {
+	Index        int            `json:"index"`
+	Name         string         `json:"name,omitempty"`
+	BaseImage    string         `json:"base_image"`
+	BaseStage    string         `json:"base_stage,omitempty"`
+	Platform     string         `json:"platform,omitempty"`
+	Line         int            `json:"line"`
+	Instructions map[string]int `json:"instructions"`
+	CopiesFrom   []string       `json:"copies_from,omitempty"`
+	Exposes      []string       `json:"exposes,omitempty"`
+	User         string         `json:"user,omitempty"`
+	Workdir      string         `json:"workdir,omitempty"`
+	Entrypoint   string         `json:"entrypoint,omitempty"`
+	Cmd          string         `json:"cmd,omitempty"`
+	Healthcheck  bool           `json:"healthcheck,omitempty"`
+}
+
+// ActionResponse reports the outcome of running or stopping a container
+type ActionResponse struct {
+	Action    string `json:"action"`
+	Container string `json:"container"`
+	Message   string `json:"message,omitempty"`
+}
@@ -0,0 +1,323 @@
+package csvtool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultSampleRows is how many rows a profile includes by default
+	defaultSampleRows = 5
+
+	// maxSampleRows is the most rows a profile can include
+	maxSampleRows = 50
+
+	// defaultInlineRows is how many filtered rows are returned by default when no output file is given
+	defaultInlineRows = 20
+
+	// maxInlineRows is the most filtered rows returned without an output file
+	maxInlineRows = 200
+)
+
+// CSVTool profiles CSV files and filters them into smaller files
+type CSVTool struct{}
+
+// init registers the csv tool
+func init() {
+	registry.Register(&CSVTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (c *CSVTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"csv",
+		mcp.WithDescription(`Profile and filter CSV (and TSV) files without loading them into the conversation.
+
+Functions:
+- profile: row count, and for each column its inferred type (integer, float, boolean, date, datetime, string), null rate, distinct count, min/max/mean and most common values, plus sample rows
+- filter: select columns and rows matching conditions, returned inline or written to output_path as a smaller CSV
+
+The delimiter is detected automatically.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("profile", "filter"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the CSV file"),
+		),
+		mcp.WithString("delimiter",
+			mcp.Description("Field delimiter, e.g. ',', ';', '|' or 'tab' (default: detected)"),
+		),
+		mcp.WithBoolean("has_header",
+			mcp.Description("Whether the first row holds column names. Without one, columns are named column_1, column_2, ... (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("sample_rows",
+			mcp.Description(fmt.Sprintf("profile: rows to include as a sample (default: %d, max: %d)", defaultSampleRows, maxSampleRows)),
+		),
+		mcp.WithArray("columns",
+			mcp.Description("filter: columns to keep, in order (default: all)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("where",
+			mcp.Description(`filter: conditions on column values, e.g. [{"column": "status", "op": "eq", "value": "active"}, {"column": "amount", "op": "gt", "value": 100}]. Ops: eq, ne, gt, gte, lt, lte, contains, starts_with, ends_with, regex, empty, not_empty`),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithString("match",
+			mcp.Description("filter: whether rows must meet all conditions or any of them"),
+			mcp.Enum("all", "any"),
+			mcp.DefaultString("all"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("filter: most matching rows to output (default: all when writing output_path, otherwise %d, max %d inline)", defaultInlineRows, maxInlineRows)),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("filter: absolute path to write the filtered CSV to. Without it, rows are returned inline"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("filter: overwrite output_path if it exists"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // filter can write output files
+		mcp.WithDestructiveHintAnnotation(true), // Can overwrite files when overwrite is true
+		mcp.WithIdempotentHintAnnotation(true),  // Same input produces the same output
+		mcp.WithOpenWorldHintAnnotation(false),  // Local file operations only
+	)
+}
+
+// Execute profiles or filters the CSV file
+func (c *CSVTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function != "profile" && function != "filter" {
+		return nil, fmt.Errorf("missing or invalid 'function': must be profile or filter")
+	}
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	delimiterArg, _ := args["delimiter"].(string)
+	delimiter, err := parseDelimiter(delimiterArg)
+	if err != nil {
+		return nil, err
+	}
+	hasHeader := true
+	if value, ok := args["has_header"].(bool); ok {
+		hasHeader = value
+	}
+
+	data, resolved, warning, err := loadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := openTable(data, delimiter, hasHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"function": function,
+		"path":     resolved,
+		"size":     len(data),
+	}).Debug("Executing csv")
+
+	var result any
+	if function == "profile" {
+		result, err = profile(t, resolved, hasHeader, args)
+	} else {
+		result, err = filter(ctx, t, resolved, hasHeader, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := string(jsonBytes)
+	if warning != nil {
+		output = security.FormatSecurityWarningPrefix(warning) + output
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// profile summarises every column of the table
+func profile(t *table, path string, hasHeader bool, args map[string]any) (*ProfileResponse, error) {
+	sampleRows := defaultSampleRows
+	if raw, ok := args["sample_rows"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 0 || value > maxSampleRows || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'sample_rows' parameter: must be a whole number from 0 to %d", maxSampleRows)
+		}
+		sampleRows = int(value)
+	}
+
+	columns, samples, rows, err := profileTable(t, sampleRows)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileResponse{
+		Path:       path,
+		Delimiter:  delimiterName(t.delimiter),
+		HasHeader:  hasHeader,
+		Rows:       rows,
+		RaggedRows: t.ragged,
+		Columns:    columns,
+		SampleRows: samples,
+	}, nil
+}
+
+// filter selects columns and matching rows, returning them or writing them to output_path
+func filter(ctx context.Context, t *table, path string, hasHeader bool, args map[string]any) (*FilterResponse, error) {
+	columns := make([]int, 0, len(t.header))
+	if raw, ok := args["columns"].([]any); ok && len(raw) > 0 {
+		for _, item := range raw {
+			name, _ := item.(string)
+			index, err := t.columnIndex(name)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, index)
+		}
+	} else {
+		for i := range t.header {
+			columns = append(columns, i)
+		}
+	}
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = t.header[column]
+	}
+
+	conditions, err := parseConditions(t, args["where"])
+	if err != nil {
+		return nil, err
+	}
+	match, _ := args["match"].(string)
+	if match != "" && match != "all" && match != "any" {
+		return nil, fmt.Errorf("invalid 'match' parameter: must be all or any")
+	}
+
+	var outputPath string
+	if raw, ok := args["output_path"].(string); ok && raw != "" {
+		if outputPath, err = workspace.ResolveToolPath(ctx, "output_path", raw); err != nil {
+			return nil, err
+		}
+		if outputPath == path {
+			return nil, fmt.Errorf("output_path must differ from path, so the original file is kept")
+		}
+	}
+
+	limit := 0
+	if outputPath == "" {
+		limit = defaultInlineRows
+	}
+	if raw, ok := args["limit"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'limit' parameter: must be a whole number of at least 1")
+		}
+		limit = int(value)
+		if outputPath == "" && limit > maxInlineRows {
+			return nil, fmt.Errorf("limit is %d, but at most %d rows are returned inline. Give output_path to write more", limit, maxInlineRows)
+		}
+	}
+
+	rows, scanned, matched, err := filterTable(t, columns, conditions, match == "any", limit)
+	if err != nil {
+		return nil, err
+	}
+	response := &FilterResponse{
+		Path:        path,
+		Columns:     names,
+		RowsScanned: scanned,
+		RowsMatched: matched,
+		RowsWritten: len(rows),
+		Truncated:   len(rows) < matched,
+	}
+	if outputPath == "" {
+		response.Rows = rows
+		if response.Rows == nil {
+			response.Rows = [][]string{}
+		}
+		return response, nil
+	}
+
+	var header []string
+	if hasHeader {
+		header = names
+	}
+	overwrite, _ := args["overwrite"].(bool)
+	if err := writeCSV(outputPath, overwrite, t.delimiter, header, rows); err != nil {
+		return nil, err
+	}
+	response.OutputPath = outputPath
+	return response, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the csv tool
+func (c *CSVTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Understanding an unfamiliar CSV or TSV export before working with it, checking data quality (types, missing values, ranges), or cutting a large file down to the rows and columns a task needs.",
+		WhenNotToUse: "Excel workbooks - use the excel tool. Joins, aggregation or SQL queries across files - load the data into a database instead.",
+		CommonPatterns: []string{
+			"Start with profile to learn the columns and their types, then filter using the column names it reports",
+			"Check data quality: look at null_rate, and at min/max for values out of range",
+			"Extract a subset: filter with columns, where and output_path, then profile the new file",
+			"Preview matching rows: filter without output_path returns the first rows inline",
+		},
+		ParameterDetails: map[string]string{
+			"path":        fmt.Sprintf("Absolute path, or relative to the client's workspace root. Files up to %d MB, UTF-8 text. A byte order mark is ignored.", maxFileSize/(1024*1024)),
+			"delimiter":   "Detected from the first lines by trying comma, tab, semicolon and pipe. Give it when detection picks the wrong one.",
+			"has_header":  "Set false when the first row is data. Columns are then named column_1, column_2, ...",
+			"sample_rows": "Rows shown as they appear in the file, after the header.",
+			"where":       "Each condition is {column, op, value}. gt, gte, lt, lte and eq compare as numbers when both sides are numbers, as times when both are ISO dates or times, and as text otherwise. empty and not_empty need no value, and treat '', null, NA, N/A, NaN, None and nil as empty.",
+			"match":       "all (default) keeps rows meeting every condition; any keeps rows meeting at least one.",
+			"output_path": "The filtered rows are written with the input's delimiter, and a header when the input has one. Must not be the input file.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Profile a file",
+				Arguments:      map[string]any{"function": "profile", "path": "/Users/name/data/orders.csv"},
+				ExpectedResult: "Row count, each column's type, null rate, distinct count, min/max/mean, and 5 sample rows",
+			},
+			{
+				Description: "Write large open orders to a smaller file",
+				Arguments: map[string]any{
+					"function":    "filter",
+					"path":        "/Users/name/data/orders.csv",
+					"columns":     []string{"order_id", "customer", "amount"},
+					"where":       []map[string]any{{"column": "status", "op": "eq", "value": "open"}, {"column": "amount", "op": "gte", "value": 1000}},
+					"output_path": "/Users/name/data/large-open-orders.csv",
+				},
+				ExpectedResult: "Counts of rows scanned, matched and written to the new file",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Every row is one column",
+				Solution: "The delimiter was not detected. Give it with the delimiter parameter.",
+			},
+			{
+				Problem:  "A numeric column is reported as string",
+				Solution: "At least one value is not a number, such as '1,234' or '$5'. Check the column's top_values or filter it with op regex to find them.",
+			},
+			{
+				Problem:  "ragged_rows is reported",
+				Solution: "Some rows have more or fewer fields than the header, often from unquoted delimiters inside values. Missing fields are treated as empty and extra fields are ignored.",
+			},
+		},
+	}
+}
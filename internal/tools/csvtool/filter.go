@@ -0,0 +1,220 @@
+package csvtool
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// operators are the comparisons a condition can use
+var operators = []string{"eq", "ne", "gt", "gte", "lt", "lte", "contains", "starts_with", "ends_with", "regex", "empty", "not_empty"}
+
+// condition is one filter on a column's values
+type condition struct {
+	column int
+	op     string
+	value  string
+	re     *regexp.Regexp
+}
+
+// parseConditions reads the where parameter: a list of {column, op, value} objects
+func parseConditions(t *table, raw any) ([]condition, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("'where' must be an array of conditions, e.g. [{\"column\": \"status\", \"op\": \"eq\", \"value\": \"active\"}]")
+	}
+
+	conditions := make([]condition, 0, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("condition %d must be an object with column, op and value", i+1)
+		}
+		name, _ := object["column"].(string)
+		column, err := t.columnIndex(name)
+		if err != nil {
+			return nil, fmt.Errorf("condition %d: %w", i+1, err)
+		}
+		op, _ := object["op"].(string)
+		if !slices.Contains(operators, op) {
+			return nil, fmt.Errorf("condition %d: invalid op %q: must be one of %s", i+1, op, strings.Join(operators, ", "))
+		}
+
+		c := condition{column: column, op: op}
+		switch value := object["value"].(type) {
+		case nil:
+			if op != "empty" && op != "not_empty" {
+				return nil, fmt.Errorf("condition %d: op %q needs a value", i+1, op)
+			}
+		case string:
+			c.value = value
+		case float64:
+			c.value = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			c.value = strconv.FormatBool(value)
+		default:
+			return nil, fmt.Errorf("condition %d: value must be a string, number or boolean", i+1)
+		}
+		if op == "regex" {
+			c.re, err = regexp.Compile(c.value)
+			if err != nil {
+				return nil, fmt.Errorf("condition %d: invalid regex: %w", i+1, err)
+			}
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// matches reports whether a row's value meets the condition
+func (c condition) matches(record []string) bool {
+	cell := strings.TrimSpace(record[c.column])
+	switch c.op {
+	case "eq":
+		return compareValues(cell, c.value) == 0
+	case "ne":
+		return compareValues(cell, c.value) != 0
+	case "gt":
+		return cell != "" && compareValues(cell, c.value) > 0
+	case "gte":
+		return cell != "" && compareValues(cell, c.value) >= 0
+	case "lt":
+		return cell != "" && compareValues(cell, c.value) < 0
+	case "lte":
+		return cell != "" && compareValues(cell, c.value) <= 0
+	case "contains":
+		return strings.Contains(cell, c.value)
+	case "starts_with":
+		return strings.HasPrefix(cell, c.value)
+	case "ends_with":
+		return strings.HasSuffix(cell, c.value)
+	case "regex":
+		return c.re.MatchString(cell)
+	case "empty":
+		return slices.Contains(nullValues, strings.ToLower(cell))
+	case "not_empty":
+		return !slices.Contains(nullValues, strings.ToLower(cell))
+	}
+	return false
+}
+
+// compareValues compares two values as numbers if both are numbers, as times if both are dates or
+// times, and otherwise as text
+func compareValues(a, b string) int {
+	an, aErr := strconv.ParseFloat(a, 64)
+	bn, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	}
+	if at, ok := parseTime(a); ok {
+		if bt, ok := parseTime(b); ok {
+			return at.Compare(bt)
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// parseTime parses a value with the recognised date and time layouts
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterTable reads every row, keeping the selected columns of rows that match the conditions, up to
+// limit rows (no limit when it is zero). The returned count is every matching row.
+func filterTable(t *table, columns []int, conditions []condition, matchAny bool, limit int) ([][]string, int, int, error) {
+	var rows [][]string
+	scanned, matched := 0, 0
+	for {
+		record, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		scanned++
+		if !rowMatches(record, conditions, matchAny) {
+			continue
+		}
+		matched++
+		if limit > 0 && len(rows) >= limit {
+			continue
+		}
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = record[column]
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanned, matched, nil
+}
+
+// rowMatches reports whether a row meets all the conditions, or any of them when matchAny is set
+func rowMatches(record []string, conditions []condition, matchAny bool) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+	for _, c := range conditions {
+		if c.matches(record) == matchAny {
+			return matchAny
+		}
+	}
+	return !matchAny
+}
+
+// writeCSV writes rows, with a header when one is given, to a new file after the security framework's
+// access check
+func writeCSV(outputPath string, overwrite bool, delimiter rune, header []string, rows [][]string) error {
+	if err := security.CheckFileAccess(outputPath); err != nil {
+		return fmt.Errorf("file access denied: %w", err)
+	}
+	if !overwrite {
+		if _, err := os.Stat(outputPath); err == nil {
+			return fmt.Errorf("output file %s already exists - set overwrite to true or choose another output_path", outputPath)
+		}
+	}
+
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	writer.Comma = delimiter
+	if header != nil {
+		_ = writer.Write(header)
+	}
+	_ = writer.WriteAll(rows)
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, out.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
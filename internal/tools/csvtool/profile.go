@@ -0,0 +1,238 @@
+package csvtool
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxDistinct is how many distinct values are counted per column before counting stops
+	maxDistinct = 10_000
+
+	// topValueCount is how many of the most common values are listed for text columns
+	topValueCount = 5
+)
+
+// nullValues are the values, in lower case, treated as missing
+var nullValues = []string{"", "null", "na", "n/a", "nan", "none", "nil"}
+
+// timeLayouts are the date and time formats recognised when inferring column types
+var timeLayouts = []string{time.DateOnly, time.RFC3339Nano, time.DateTime, "2006-01-02T15:04:05", "2006-01-02 15:04:05.999999999"}
+
+// columnStats accumulates a column's values. Each is* flag stays true while every value seen so far
+// parses as that type.
+type columnStats struct {
+	nonNull, nulls int
+
+	isBool, isInt, isFloat, isDate, isDateTime bool
+
+	intMin, intMax     int64
+	floatMin, floatMax float64
+	sum                float64
+
+	timeMin, timeMax       time.Time
+	timeMinRaw, timeMaxRaw string
+
+	minLength, maxLength int
+
+	counts map[string]int
+	capped bool
+}
+
+// newColumnStats returns stats that accept every type until a value rules it out
+func newColumnStats() *columnStats {
+	return &columnStats{isBool: true, isInt: true, isFloat: true, isDate: true, isDateTime: true, counts: map[string]int{}}
+}
+
+// add records one value
+func (s *columnStats) add(raw string) {
+	value := strings.TrimSpace(raw)
+	if slices.Contains(nullValues, strings.ToLower(value)) {
+		s.nulls++
+		return
+	}
+	first := s.nonNull == 0
+	s.nonNull++
+
+	if _, exists := s.counts[value]; exists || len(s.counts) < maxDistinct {
+		s.counts[value]++
+	} else {
+		s.capped = true
+	}
+
+	length := utf8.RuneCountInString(value)
+	if first || length < s.minLength {
+		s.minLength = length
+	}
+	if first || length > s.maxLength {
+		s.maxLength = length
+	}
+
+	if s.isBool {
+		lower := strings.ToLower(value)
+		s.isBool = lower == "true" || lower == "false"
+	}
+	if s.isInt {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if first || n < s.intMin {
+				s.intMin = n
+			}
+			if first || n > s.intMax {
+				s.intMax = n
+			}
+		} else {
+			s.isInt = false
+		}
+	}
+	if s.isFloat {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			if first || f < s.floatMin {
+				s.floatMin = f
+			}
+			if first || f > s.floatMax {
+				s.floatMax = f
+			}
+			s.sum += f
+		} else {
+			s.isFloat = false
+		}
+	}
+	if s.isDateTime {
+		s.addTime(value, first)
+	}
+}
+
+// addTime records a date or time value. A column stays a date column while every value is a date
+// without a time, and a datetime column while every value is a date or a time.
+func (s *columnStats) addTime(value string, first bool) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		s.isDate = s.isDate && layout == time.DateOnly
+		if first || t.Before(s.timeMin) {
+			s.timeMin, s.timeMinRaw = t, value
+		}
+		if first || t.After(s.timeMax) {
+			s.timeMax, s.timeMaxRaw = t, value
+		}
+		return
+	}
+	s.isDate, s.isDateTime = false, false
+}
+
+// columnType names the narrowest type every value fits
+func (s *columnStats) columnType() string {
+	switch {
+	case s.nonNull == 0:
+		return "empty"
+	case s.isBool:
+		return "boolean"
+	case s.isInt:
+		return "integer"
+	case s.isFloat:
+		return "float"
+	case s.isDate:
+		return "date"
+	case s.isDateTime:
+		return "datetime"
+	}
+	return "string"
+}
+
+// profile summarises the column's values out of the given number of rows
+func (s *columnStats) profile(name string, rows int) ColumnProfile {
+	p := ColumnProfile{
+		Name:           name,
+		Type:           s.columnType(),
+		NonNull:        s.nonNull,
+		Nulls:          s.nulls,
+		Distinct:       len(s.counts),
+		DistinctCapped: s.capped,
+	}
+	if rows > 0 {
+		p.NullRate = math.Round(float64(s.nulls)/float64(rows)*10_000) / 10_000
+	}
+
+	switch p.Type {
+	case "integer":
+		p.Min, p.Max = s.intMin, s.intMax
+		mean := roundMean(s.sum / float64(s.nonNull))
+		p.Mean = &mean
+	case "float":
+		p.Min, p.Max = s.floatMin, s.floatMax
+		mean := roundMean(s.sum / float64(s.nonNull))
+		p.Mean = &mean
+	case "date", "datetime":
+		p.Min, p.Max = s.timeMinRaw, s.timeMaxRaw
+	case "string", "boolean":
+		if p.Type == "string" {
+			p.MinLength, p.MaxLength = &s.minLength, &s.maxLength
+		}
+		// Counts are incomplete once values stop being counted
+		if !s.capped && len(s.counts) < s.nonNull {
+			p.TopValues = topValues(s.counts)
+		}
+	}
+	return p
+}
+
+// topValues returns the most common values, most common first
+func topValues(counts map[string]int) []Count {
+	all := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		all = append(all, Count{Value: value, Count: count})
+	}
+	slices.SortFunc(all, func(a, b Count) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return all[:min(len(all), topValueCount)]
+}
+
+// roundMean rounds a mean to six decimal places
+func roundMean(mean float64) float64 {
+	return math.Round(mean*1e6) / 1e6
+}
+
+// profileTable reads every row of the table and profiles each column, keeping the first sampleRows rows
+func profileTable(t *table, sampleRows int) ([]ColumnProfile, [][]string, int, error) {
+	stats := make([]*columnStats, len(t.header))
+	for i := range stats {
+		stats[i] = newColumnStats()
+	}
+	samples := [][]string{}
+	rows := 0
+	for {
+		record, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		rows++
+		for i, value := range record {
+			stats[i].add(value)
+		}
+		if len(samples) < sampleRows {
+			samples = append(samples, slices.Clone(record))
+		}
+	}
+
+	profiles := make([]ColumnProfile, len(stats))
+	for i, s := range stats {
+		profiles[i] = s.profile(t.header[i], rows)
+	}
+	return profiles, samples, rows, nil
+}
@@ -0,0 +1,214 @@
+package csvtool
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+)
+
+// maxFileSize is the largest CSV file read
+const maxFileSize = 100 * 1024 * 1024
+
+// delimiterCandidates are the delimiters detected automatically, in order of preference
+var delimiterCandidates = []rune{',', '\t', ';', '|'}
+
+// table is a CSV file's header and a way to read its rows
+type table struct {
+	header    []string
+	delimiter rune
+	reader    *csv.Reader
+	first     []string
+	ragged    int
+}
+
+// loadFile reads a CSV file after the security framework's access and content checks, returning its
+// content, resolved path and any security warning
+func loadFile(ctx context.Context, path string) ([]byte, string, *security.SecurityResult, error) {
+	resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("cannot access CSV file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, "", nil, fmt.Errorf("%s is not a regular file", resolved)
+	}
+	if info.Size() > maxFileSize {
+		return nil, "", nil, fmt.Errorf("file is %.1fMB, maximum is %dMB", float64(info.Size())/(1024*1024), maxFileSize/(1024*1024))
+	}
+
+	safeFile, err := security.NewOperations("csv").SafeFileRead(resolved)
+	if err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return nil, "", nil, security.FormatSecurityBlockError(secErr)
+		}
+		return nil, "", nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	var warning *security.SecurityResult
+	if safeFile.SecurityResult != nil && safeFile.SecurityResult.Action == security.ActionWarn {
+		warning = safeFile.SecurityResult
+	}
+	return safeFile.Content, resolved, warning, nil
+}
+
+// openTable reads the header of CSV data, detecting the delimiter unless one is given. Files without a
+// header get columns named column_1, column_2 and so on.
+func openTable(data []byte, delimiter rune, hasHeader bool) (*table, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("the file is not UTF-8 text. Convert it to UTF-8 first, e.g. with iconv")
+	}
+	if delimiter == 0 {
+		delimiter = detectDelimiter(data)
+	}
+
+	t := &table{delimiter: delimiter, reader: newReader(data, delimiter)}
+	first, err := t.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, parseError(err)
+	}
+	// The reader reuses its record slice, so keep a copy
+	first = append([]string(nil), first...)
+
+	if !hasHeader {
+		t.first = first
+		for i := range first {
+			t.header = append(t.header, fmt.Sprintf("column_%d", i+1))
+		}
+		return t, nil
+	}
+
+	seen := make(map[string]bool, len(first))
+	for i, name := range first {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		seen[name] = true
+		t.header = append(t.header, name)
+	}
+	return t, nil
+}
+
+// next returns the next row, padded or cut to the header's width, or io.EOF at the end
+func (t *table) next() ([]string, error) {
+	var record []string
+	if t.first != nil {
+		record, t.first = t.first, nil
+	} else {
+		var err error
+		record, err = t.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, parseError(err)
+		}
+	}
+	if len(record) != len(t.header) {
+		t.ragged++
+		for len(record) < len(t.header) {
+			record = append(record, "")
+		}
+		record = record[:len(t.header)]
+	}
+	return record, nil
+}
+
+// columnIndex returns the index of a column by name
+func (t *table) columnIndex(name string) (int, error) {
+	for i, column := range t.header {
+		if column == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column %q. Columns: %s", name, strings.Join(t.header, ", "))
+}
+
+// newReader returns a CSV reader that allows rows of different lengths and reuses its record slice
+func newReader(data []byte, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	return reader
+}
+
+// detectDelimiter picks the candidate delimiter that splits the first lines into the most columns, the
+// same number on every line. A comma is assumed when none does.
+func detectDelimiter(data []byte) rune {
+	sample := data
+	if len(sample) > 64*1024 {
+		// Cut the sample at a line break so its last row is whole
+		sample = sample[:64*1024]
+		sample = sample[:bytes.LastIndexByte(sample, '\n')+1]
+	}
+	best, bestColumns := ',', 1
+	for _, candidate := range delimiterCandidates {
+		reader := newReader(sample, candidate)
+		columns := 0
+		consistent := true
+		for range 20 {
+			record, err := reader.Read()
+			if err != nil {
+				break
+			}
+			if columns == 0 {
+				columns = len(record)
+			} else if len(record) != columns {
+				consistent = false
+				break
+			}
+		}
+		if consistent && columns > bestColumns {
+			best, bestColumns = candidate, columns
+		}
+	}
+	return best
+}
+
+// parseDelimiter reads the delimiter parameter, accepting "tab" and "\t" for a tab
+func parseDelimiter(value string) (rune, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("invalid delimiter %q: must be a single character other than a quote or line break", value)
+	}
+	return r, nil
+}
+
+// delimiterName describes a delimiter for the response
+func delimiterName(delimiter rune) string {
+	if delimiter == '\t' {
+		return "tab"
+	}
+	return string(delimiter)
+}
+
+// parseError explains a CSV syntax error with its line
+func parseError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("invalid CSV on line %d, column %d: %v", parseErr.Line, parseErr.Column, parseErr.Err)
+	}
+	return fmt.Errorf("failed to read CSV: %w", err)
+}
@@ -0,0 +1,47 @@
+package csvtool
+
+// ColumnProfile summarises one column's values
+type ColumnProfile struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	NonNull        int      `json:"non_null"`
+	Nulls          int      `json:"nulls"`
+	NullRate       float64  `json:"null_rate"`
+	Distinct       int      `json:"distinct"`
+	DistinctCapped bool     `json:"distinct_capped,omitempty"`
+	Min            any      `json:"min,omitempty"`
+	Max            any      `json:"max,omitempty"`
+	Mean           *float64 `json:"mean,omitempty"`
+	MinLength      *int     `json:"min_length,omitempty"`
+	MaxLength      *int     `json:"max_length,omitempty"`
+	TopValues      []Count  `json:"top_values,omitempty"`
+}
+
+// Count is a value and how many times it appears
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ProfileResponse is the result of profiling a CSV file
+type ProfileResponse struct {
+	Path       string          `json:"path"`
+	Delimiter  string          `json:"delimiter"`
+	HasHeader  bool            `json:"has_header"`
+	Rows       int             `json:"rows"`
+	RaggedRows int             `json:"ragged_rows,omitempty"`
+	Columns    []ColumnProfile `json:"columns"`
+	SampleRows [][]string      `json:"sample_rows"`
+}
+
+// FilterResponse is the result of filtering a CSV file
+type FilterResponse struct {
+	Path        string     `json:"path"`
+	OutputPath  string     `json:"output_path,omitempty"`
+	Columns     []string   `json:"columns"`
+	RowsScanned int        `json:"rows_scanned"`
+	RowsMatched int        `json:"rows_matched"`
+	RowsWritten int        `json:"rows_written"`
+	Rows        [][]string `json:"rows,omitempty"`
+	Truncated   bool       `json:"truncated,omitempty"`
+}
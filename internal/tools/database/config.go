@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config represents the database connection configuration file
+type Config struct {
+	Connections map[string]ConnectionConfig `yaml:"connections"`
+}
+
+// ConnectionConfig defines a single named database connection
+type ConnectionConfig struct {
+	Driver      string `yaml:"driver"`       // "postgres", "mysql" or "sqlite"
+	DSN         string `yaml:"dsn"`          // connection string, may reference env vars as $VAR or ${VAR}
+	DSNEnv      string `yaml:"dsn_env"`      // environment variable containing the connection string
+	Description string `yaml:"description"`  // shown when listing connections
+	AllowWrites bool   `yaml:"allow_writes"` // permit statements other than SELECT/EXPLAIN, default false
+	MaxRows     int    `yaml:"max_rows"`     // maximum rows returned per query, default 200
+	MaxBytes    int    `yaml:"max_bytes"`    // maximum size of formatted results, default 64KB
+	Timeout     int    `yaml:"timeout"`      // query timeout in seconds, default 30
+}
+
+// configPath returns the configuration file location, honouring DATABASE_CONFIG_PATH
+func configPath() (string, error) {
+	if path := os.Getenv(ConfigPathEnvVar); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "databases.yaml"), nil
+}
+
+// LoadConfig loads and validates the database configuration from the specified file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no database configuration found at %s - create it or set %s", path, ConfigPathEnvVar)
+		}
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &config, nil
+}
+
+// validate validates the configuration and sets defaults
+func (c *Config) validate() error {
+	if len(c.Connections) == 0 {
+		return fmt.Errorf("no connections defined")
+	}
+
+	for name, conn := range c.Connections {
+		driver, err := driverName(conn.Driver)
+		if err != nil {
+			return fmt.Errorf("connection '%s': %w", name, err)
+		}
+		conn.Driver = driver
+
+		if conn.DSN == "" && conn.DSNEnv == "" {
+			return fmt.Errorf("connection '%s': dsn or dsn_env is required", name)
+		}
+
+		if conn.MaxRows <= 0 {
+			conn.MaxRows = defaultMaxRows
+		}
+		conn.MaxRows = min(conn.MaxRows, maxRowsLimit)
+		if conn.MaxBytes <= 0 {
+			conn.MaxBytes = defaultMaxBytes
+		}
+		conn.MaxBytes = min(conn.MaxBytes, maxBytesLimit)
+		if conn.Timeout <= 0 {
+			conn.Timeout = defaultTimeoutSeconds
+		}
+
+		c.Connections[name] = conn
+	}
+
+	return nil
+}
+
+// resolveDSN returns the connection string with environment variables expanded
+func (c *ConnectionConfig) resolveDSN() (string, error) {
+	if c.DSNEnv != "" {
+		dsn := os.Getenv(c.DSNEnv)
+		if dsn == "" {
+			return "", fmt.Errorf("environment variable %s is not set", c.DSNEnv)
+		}
+		return dsn, nil
+	}
+	return os.ExpandEnv(c.DSN), nil
+}
+
+// driverName maps a configured driver to its database/sql driver name
+func driverName(driver string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "postgres", "postgresql", "pgx":
+		return driverPostgres, nil
+	case "mysql", "mariadb":
+		return driverMySQL, nil
+	case "sqlite", "sqlite3":
+		return driverSQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported driver '%s', must be one of: postgres, mysql, sqlite", driver)
+	}
+}
+
+// driverLabel returns a human-readable driver name
+func driverLabel(driver string) string {
+	switch driver {
+	case driverPostgres:
+		return "postgres"
+	case driverMySQL:
+		return "mysql"
+	default:
+		return driver
+	}
+}
@@ -0,0 +1,463 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+
+	// Database drivers
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// ConfigPathEnvVar overrides the default ~/.mcp-devtools/databases.yaml location
+	ConfigPathEnvVar = "DATABASE_CONFIG_PATH"
+
+	driverPostgres = "pgx"
+	driverMySQL    = "mysql"
+	driverSQLite   = "sqlite"
+
+	defaultMaxRows        = 200
+	maxRowsLimit          = 10000
+	defaultMaxBytes       = 64 * 1024
+	maxBytesLimit         = 1024 * 1024
+	defaultTimeoutSeconds = 30
+	maxCellChars          = 1000
+)
+
+// DatabaseTool runs SQL queries against configured database connections
+type DatabaseTool struct{}
+
+// init registers the database tool
+func init() {
+	registry.Register(&DatabaseTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *DatabaseTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"database",
+		mcp.WithDescription(`Query Postgres, MySQL and SQLite databases defined in ~/.mcp-devtools/databases.yaml. Connections are read-only unless allow_writes is set for that connection: only SELECT, WITH, EXPLAIN, SHOW and DESCRIBE statements run, inside a read-only transaction.
+
+Functions:
+- list_connections: configured connection names, drivers and modes
+- query: run one SQL statement, with optional positional params ($1 for Postgres, ? for MySQL and SQLite)
+- schema: list tables, or describe a table's columns when table is set
+
+Results are capped by rows and bytes and returned as a markdown table or CSV.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("list_connections", "query", "schema"),
+		),
+		mcp.WithString("connection",
+			mcp.Description("Connection name from the configuration (required for query and schema)"),
+		),
+		mcp.WithString("sql",
+			mcp.Description("query: a single SQL statement. Use placeholders for values rather than string concatenation"),
+		),
+		mcp.WithArray("params",
+			mcp.Description("query: positional values for the statement's placeholders"),
+		),
+		mcp.WithString("table",
+			mcp.Description("schema: table to describe, optionally schema-qualified (e.g. public.orders)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Result format"),
+			mcp.Enum("markdown", "csv"),
+			mcp.DefaultString("markdown"),
+		),
+		mcp.WithNumber("max_rows",
+			mcp.Description("Maximum rows to return, up to the connection's max_rows (default: the connection's max_rows)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // Writes are possible when a connection allows them
+		mcp.WithDestructiveHintAnnotation(true), // Write-enabled connections can modify data
+		mcp.WithIdempotentHintAnnotation(false), // Query results change as data changes
+		mcp.WithOpenWorldHintAnnotation(true),   // Connects to external databases
+	)
+}
+
+// Execute runs the requested function
+func (t *DatabaseTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if function == "list_connections" {
+		return mcp.NewToolResultText(listConnections(config)), nil
+	}
+
+	name, _ := args["connection"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("missing required parameter: connection")
+	}
+	conn, ok := config.Connections[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown connection '%s' - available: %s", name, strings.Join(connectionNames(config), ", "))
+	}
+
+	maxRows := conn.MaxRows
+	if requested, ok := args["max_rows"].(float64); ok {
+		if requested < 1 {
+			return nil, fmt.Errorf("max_rows must be at least 1")
+		}
+		maxRows = min(int(requested), conn.MaxRows)
+	}
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "csv" {
+		return nil, fmt.Errorf("invalid format '%s', must be markdown or csv", format)
+	}
+
+	var query string
+	var params []any
+	var stmt *statement
+	switch function {
+	case "query":
+		query, _ = args["sql"].(string)
+		if strings.TrimSpace(query) == "" {
+			return nil, fmt.Errorf("missing required parameter: sql")
+		}
+		stmt, err = analyseStatement(query)
+		if err != nil {
+			return nil, err
+		}
+		if !conn.AllowWrites {
+			if err := stmt.checkReadOnly(); err != nil {
+				return nil, err
+			}
+		}
+		if raw, ok := args["params"].([]any); ok {
+			params = normaliseParams(raw)
+		}
+	case "schema":
+		table, _ := args["table"].(string)
+		query, params = schemaQuery(conn.Driver, strings.TrimSpace(table))
+		stmt = &statement{keyword: "SELECT"}
+	default:
+		return nil, fmt.Errorf("unknown function: %s (expected list_connections, query or schema)", function)
+	}
+
+	db, err := openDatabase(&conn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(conn.Timeout)*time.Second)
+	defer cancel()
+
+	logger.WithFields(logrus.Fields{
+		"connection": name,
+		"driver":     conn.Driver,
+		"function":   function,
+		"read_only":  !conn.AllowWrites,
+	}).Debug("Running database statement")
+
+	output, err := run(ctx, db, &conn, stmt, query, params, maxRows, format)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("query timed out after %d seconds", conn.Timeout)
+		}
+		return nil, err
+	}
+
+	sourceCtx := security.SourceContext{
+		Tool:        "database",
+		Domain:      name,
+		ContentType: "database_result",
+	}
+	if result, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			logger.WithField("security_id", result.ID).Warn("Security warning for database results")
+		}
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+// openDatabase opens a single-connection pool for the configured connection. SQLite
+// databases on read-only connections are opened in read-only, query-only mode.
+func openDatabase(conn *ConnectionConfig) (*sql.DB, error) {
+	dsn, err := conn.resolveDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.Driver == driverSQLite {
+		dsn, err = sqliteDSN(dsn, !conn.AllowWrites)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open(conn.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s connection: %w", driverLabel(conn.Driver), err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// sqliteDSN validates a SQLite database path and builds a file: URI for it
+func sqliteDSN(dsn string, readOnly bool) (string, error) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("sqlite dsn must be an absolute file path, got: %s", path)
+	}
+	if err := security.CheckFileAccess(path); err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+
+	var options []string
+	if query != "" {
+		options = append(options, query)
+	}
+	if readOnly {
+		options = append(options, "mode=ro", "_pragma=query_only(1)")
+	}
+	uri := "file:" + strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path)
+	if len(options) > 0 {
+		uri += "?" + strings.Join(options, "&")
+	}
+	return uri, nil
+}
+
+// run executes a statement in a transaction and formats the result. Read-only
+// connections use a read-only transaction that is always rolled back.
+func run(ctx context.Context, db *sql.DB, conn *ConnectionConfig, stmt *statement, query string, params []any, maxRows int, format string) (string, error) {
+	readOnly := !conn.AllowWrites
+	txOpts := &sql.TxOptions{ReadOnly: readOnly}
+	if conn.Driver == driverSQLite {
+		// SQLite enforces read-only access through the connection URI instead
+		txOpts = nil
+	}
+
+	tx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if !stmt.returnsRows() {
+		res, err := tx.ExecContext(ctx, query, params...)
+		if err != nil {
+			return "", fmt.Errorf("statement failed: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return "", fmt.Errorf("failed to commit: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return "Statement executed.\n", nil
+		}
+		return fmt.Sprintf("Statement executed. Rows affected: %d\n", affected), nil
+	}
+
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		return "", fmt.Errorf("query failed: %w", err)
+	}
+	result, err := readRows(rows, maxRows, conn.MaxBytes)
+	_ = rows.Close()
+	if err != nil {
+		return "", err
+	}
+	if !readOnly {
+		// Statements such as INSERT ... RETURNING need their changes committed
+		if err := tx.Commit(); err != nil {
+			return "", fmt.Errorf("failed to commit: %w", err)
+		}
+	}
+
+	if format == "csv" {
+		return result.csv()
+	}
+	return result.markdown(), nil
+}
+
+// schemaQuery returns the catalogue query listing tables, or describing one table's columns
+func schemaQuery(driver, table string) (string, []any) {
+	if table == "" {
+		switch driver {
+		case driverPostgres:
+			return `SELECT table_schema, table_name, table_type FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema') ORDER BY table_schema, table_name`, nil
+		case driverMySQL:
+			return `SELECT table_schema, table_name, table_type FROM information_schema.tables
+WHERE table_schema = DATABASE() ORDER BY table_name`, nil
+		default:
+			return `SELECT name AS table_name, type AS table_type FROM sqlite_master
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`, nil
+		}
+	}
+
+	schema, name, qualified := strings.Cut(table, ".")
+	if !qualified {
+		name, schema = schema, ""
+	}
+	switch driver {
+	case driverPostgres:
+		if schema == "" {
+			return `SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns
+WHERE table_name = $1 AND table_schema = current_schema() ORDER BY ordinal_position`, []any{name}
+		}
+		return `SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns
+WHERE table_name = $1 AND table_schema = $2 ORDER BY ordinal_position`, []any{name, schema}
+	case driverMySQL:
+		if schema == "" {
+			return `SELECT column_name, column_type, is_nullable, column_default, column_key FROM information_schema.columns
+WHERE table_name = ? AND table_schema = DATABASE() ORDER BY ordinal_position`, []any{name}
+		}
+		return `SELECT column_name, column_type, is_nullable, column_default, column_key FROM information_schema.columns
+WHERE table_name = ? AND table_schema = ? ORDER BY ordinal_position`, []any{name, schema}
+	default:
+		return `SELECT name AS column_name, type AS data_type, CASE "notnull" WHEN 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+dflt_value AS column_default, pk FROM pragma_table_info(?) ORDER BY cid`, []any{name}
+	}
+}
+
+// normaliseParams converts JSON numbers without a fractional part to integers so they
+// bind cleanly to integer columns
+func normaliseParams(raw []any) []any {
+	params := make([]any, len(raw))
+	for i, v := range raw {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			params[i] = int64(f)
+			continue
+		}
+		params[i] = v
+	}
+	return params
+}
+
+// listConnections renders the configured connections without their connection strings
+func listConnections(config *Config) string {
+	var sb strings.Builder
+	sb.WriteString("| Connection | Driver | Mode | Description |\n|---|---|---|---|\n")
+	for _, name := range connectionNames(config) {
+		conn := config.Connections[name]
+		mode := "read-only"
+		if conn.AllowWrites {
+			mode = "read-write"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", name, driverLabel(conn.Driver), mode, escapeCell(conn.Description))
+	}
+	return sb.String()
+}
+
+// connectionNames returns the configured connection names in sorted order
+func connectionNames(config *Config) []string {
+	names := make([]string, 0, len(config.Connections))
+	for name := range config.Connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProvideExtendedInfo provides detailed usage information for the database tool
+func (t *DatabaseTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "See which databases are configured",
+				Arguments: map[string]any{
+					"function": "list_connections",
+				},
+				ExpectedResult: "A table of connection names, drivers and whether each is read-only",
+			},
+			{
+				Description: "Find recent orders for a customer with a parameterised Postgres query",
+				Arguments: map[string]any{
+					"function":   "query",
+					"connection": "app_dev",
+					"sql":        "SELECT id, status, total FROM orders WHERE customer_id = $1 ORDER BY created_at DESC LIMIT 20",
+					"params":     []any{42},
+				},
+				ExpectedResult: "A markdown table of up to 20 orders",
+			},
+			{
+				Description: "Describe a table's columns",
+				Arguments: map[string]any{
+					"function":   "schema",
+					"connection": "app_dev",
+					"table":      "public.orders",
+				},
+				ExpectedResult: "Column names, types, nullability and defaults",
+			},
+			{
+				Description: "Export rows as CSV from a SQLite database",
+				Arguments: map[string]any{
+					"function":   "query",
+					"connection": "local_cache",
+					"sql":        "SELECT * FROM events WHERE kind = ?",
+					"params":     []any{"login"},
+					"format":     "csv",
+				},
+				ExpectedResult: "A row count summary followed by CSV data",
+			},
+		},
+		CommonPatterns: []string{
+			"Call schema without a table to list tables, then with a table to see its columns before writing queries",
+			"Always pass user-supplied values through params rather than embedding them in the SQL",
+			"Add LIMIT and WHERE clauses to keep results under the row and byte caps",
+			"Use EXPLAIN to check a query plan before running an expensive query",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Statements are not allowed on a read-only connection",
+				Solution: "The connection is read-only by default. Set allow_writes: true for that connection in databases.yaml if writes are intended",
+			},
+			{
+				Problem:  "Results are truncated",
+				Solution: "Narrow the query with WHERE and LIMIT, select fewer columns, or raise max_rows/max_bytes in the connection config",
+			},
+			{
+				Problem:  "Placeholder or parameter errors",
+				Solution: "Postgres uses $1, $2, ... placeholders; MySQL and SQLite use ?. The number of params must match the placeholders",
+			},
+			{
+				Problem:  "Environment variable is not set",
+				Solution: "The connection uses dsn_env - export the variable in the MCP server's environment",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"sql":      "Exactly one statement. Read-only connections accept SELECT, WITH, EXPLAIN, SHOW and DESCRIBE, and reject data-modifying keywords anywhere in the statement",
+			"params":   "Bound as positional parameters by the database driver, so values are never interpolated into SQL",
+			"max_rows": "Can only lower the limit set by the connection's max_rows",
+		},
+		WhenToUse:    "Inspecting development or staging data, checking schemas and debugging queries against configured databases",
+		WhenNotToUse: "Bulk data export, schema migrations or production changes - use proper migration and ETL tooling instead",
+	}
+}
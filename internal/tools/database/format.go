@@ -0,0 +1,170 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// resultSet holds rows read from a query, already converted to display strings
+type resultSet struct {
+	Columns   []string
+	Rows      [][]sql.NullString // Valid is false for NULL values
+	Truncated bool
+	Reason    string // why the results were truncated
+}
+
+// readRows reads up to maxRows rows, stopping early once the formatted size reaches maxBytes
+func readRows(rows *sql.Rows, maxRows, maxBytes int) (*resultSet, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	result := &resultSet{Columns: columns}
+	size := 0
+	for _, c := range columns {
+		size += len(c) + 3
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if len(result.Rows) >= maxRows {
+			result.Truncated = true
+			result.Reason = fmt.Sprintf("row limit of %d reached", maxRows)
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		row := make([]sql.NullString, len(values))
+		rowSize := 0
+		for i, v := range values {
+			if v != nil {
+				row[i] = sql.NullString{String: formatValue(v), Valid: true}
+			}
+			rowSize += len(row[i].String) + 3
+		}
+		if size+rowSize > maxBytes && len(result.Rows) > 0 {
+			result.Truncated = true
+			result.Reason = fmt.Sprintf("output limit of %d bytes reached", maxBytes)
+			break
+		}
+		size += rowSize
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+	return result, nil
+}
+
+// formatValue converts a non-NULL scanned value into display text
+func formatValue(v any) string {
+	var s string
+	switch val := v.(type) {
+	case []byte:
+		if !utf8.Valid(val) {
+			return fmt.Sprintf("<binary %d bytes>", len(val))
+		}
+		s = string(val)
+	case string:
+		s = val
+	case time.Time:
+		s = val.Format(time.RFC3339Nano)
+	case float64:
+		s = strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(val), 'f', -1, 32)
+	default:
+		s = fmt.Sprint(val)
+	}
+	if utf8.RuneCountInString(s) > maxCellChars {
+		s = string([]rune(s)[:maxCellChars]) + "…"
+	}
+	return s
+}
+
+// markdown renders the result set as a markdown table
+func (r *resultSet) markdown() string {
+	var sb strings.Builder
+	sb.WriteString(r.heading())
+	if len(r.Columns) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n|")
+	for _, c := range r.Columns {
+		sb.WriteString(" " + escapeCell(c) + " |")
+	}
+	sb.WriteString("\n|")
+	for range r.Columns {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+	for _, row := range r.Rows {
+		sb.WriteString("|")
+		for _, cell := range row {
+			text := "NULL"
+			if cell.Valid {
+				text = escapeCell(cell.String)
+			}
+			sb.WriteString(" " + text + " |")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// csv renders the result set as CSV after a one-line summary, with NULL as an empty field
+func (r *resultSet) csv() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(r.heading())
+	buf.WriteString("\n")
+	w := csv.NewWriter(&buf)
+	if err := w.Write(r.Columns); err != nil {
+		return "", err
+	}
+	for _, row := range r.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = cell.String
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// heading summarises the number of rows and any truncation
+func (r *resultSet) heading() string {
+	noun := "rows"
+	if len(r.Rows) == 1 {
+		noun = "row"
+	}
+	heading := fmt.Sprintf("%d %s", len(r.Rows), noun)
+	if r.Truncated {
+		heading += fmt.Sprintf(" (truncated: %s - add a WHERE clause or LIMIT to narrow the query)", r.Reason)
+	}
+	return heading + "\n"
+}
+
+// escapeCell makes a value safe to place inside a markdown table cell
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
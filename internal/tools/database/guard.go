@@ -0,0 +1,124 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// readOnlyKeywords are the leading keywords permitted on read-only connections
+var readOnlyKeywords = []string{"SELECT", "WITH", "EXPLAIN", "SHOW", "DESCRIBE", "DESC"}
+
+// forbiddenReadOnlyPattern matches keywords that modify data or schema anywhere in a
+// statement, catching data-modifying CTEs and SELECT ... INTO on read-only connections
+var forbiddenReadOnlyPattern = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|UPSERT|DROP|ALTER|CREATE|TRUNCATE|GRANT|REVOKE|ATTACH|DETACH|COPY|CALL|LOCK|VACUUM|REINDEX|INTO)\b`)
+
+// returningPattern matches a RETURNING clause on a write statement
+var returningPattern = regexp.MustCompile(`(?i)\bRETURNING\b`)
+
+// statement is a single SQL statement with comments and string literals masked out
+type statement struct {
+	keyword string // leading keyword in upper case
+	code    string // statement text without comments and with literals blanked
+}
+
+// analyseStatement strips comments, masks string literals and rejects multiple statements.
+// Backslash escapes inside literals differ between databases, so the statement is
+// masked both with and without them and must pass the checks either way.
+func analyseStatement(query string) (*statement, error) {
+	var variants []string
+	for _, backslashEscapes := range []bool{false, true} {
+		if masked, ok := maskSQL(query, backslashEscapes); ok {
+			variants = append(variants, masked)
+		}
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("unterminated quoted string in SQL")
+	}
+
+	for i, text := range variants {
+		text = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(text), "; \t\r\n"))
+		if text == "" {
+			return nil, fmt.Errorf("SQL statement is empty")
+		}
+		if strings.Contains(text, ";") {
+			return nil, fmt.Errorf("multiple statements are not allowed - send one statement per call")
+		}
+		variants[i] = text
+	}
+
+	keyword := strings.ToUpper(strings.FieldsFunc(variants[0], func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '('
+	})[0])
+	return &statement{keyword: keyword, code: strings.Join(variants, "\n")}, nil
+}
+
+// maskSQL removes comments and blanks quoted literals and identifiers. It reports false
+// when a quote is left unterminated.
+func maskSQL(query string, backslashEscapes bool) (string, bool) {
+	var code strings.Builder
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			code.WriteRune(' ')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && (runes[i] != '*' || i+1 >= len(runes) || runes[i+1] != '/') {
+				i++
+			}
+			i++
+			code.WriteRune(' ')
+		case r == '\'' || r == '"' || r == '`':
+			// Doubled quotes inside a literal are escapes
+			quote := r
+			i++
+			for i < len(runes) {
+				if backslashEscapes && runes[i] == '\\' {
+					i += 2
+					continue
+				}
+				if runes[i] == quote {
+					if i+1 < len(runes) && runes[i+1] == quote {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return "", false
+			}
+			code.WriteRune(quote)
+			code.WriteRune(quote)
+		default:
+			code.WriteRune(r)
+		}
+	}
+	return code.String(), true
+}
+
+// checkReadOnly rejects statements that are not permitted on read-only connections
+func (s *statement) checkReadOnly() error {
+	if !slices.Contains(readOnlyKeywords, s.keyword) {
+		return fmt.Errorf("%s statements are not allowed on a read-only connection - only %s are permitted (set allow_writes on the connection to enable writes)",
+			s.keyword, strings.Join(readOnlyKeywords, ", "))
+	}
+	if match := forbiddenReadOnlyPattern.FindString(s.code); match != "" {
+		return fmt.Errorf("%s is not allowed on a read-only connection", strings.ToUpper(match))
+	}
+	return nil
+}
+
+// returnsRows reports whether the statement produces a result set
+func (s *statement) returnsRows() bool {
+	return slices.Contains(readOnlyKeywords, s.keyword) ||
+		slices.Contains([]string{"PRAGMA", "VALUES", "TABLE"}, s.keyword) ||
+		returningPattern.MatchString(s.code)
+}
@@ -0,0 +1,522 @@
+package depgraph
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxManifestSize is the largest manifest or lockfile read
+	maxManifestSize = 50 * 1024 * 1024
+
+	// defaultMaxPaths is how many dependency chains the why function shows per package by default
+	defaultMaxPaths = 10
+
+	// maxMaxPaths is the most dependency chains the why function can show per package
+	maxMaxPaths = 100
+
+	// defaultGraphDepth is how many edges from the start packages a drawing reaches by default
+	defaultGraphDepth = 2
+
+	// maxDepth is the largest depth that can be requested
+	maxDepth = 10
+
+	// maxListed is the most packages listed by the dependencies and dependents functions
+	maxListed = 500
+
+	// topDependedOnCount is how many of the most depended-on packages a summary lists
+	topDependedOnCount = 10
+)
+
+// manifests maps each supported file name to its ecosystem, in the order a directory is checked
+var manifests = []struct {
+	file, ecosystem string
+}{
+	{"go.mod", "go"},
+	{"package-lock.json", "npm"},
+	{"pnpm-lock.yaml", "pnpm"},
+}
+
+// DepGraphTool answers questions about a project's dependency graph
+type DepGraphTool struct{}
+
+// init registers the dep_graph tool
+func init() {
+	registry.Register(&DepGraphTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (d *DepGraphTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"dep_graph",
+		mcp.WithDescription(`Explore a project's dependency graph from go.mod/go.sum, package-lock.json or pnpm-lock.yaml: why a package is included, what depends on it, what it pulls in, and DOT or Mermaid drawings limited to a depth.
+
+Functions:
+- summary: package counts, direct dependencies, packages at several versions and the most depended-on packages
+- why: chains of dependencies from the project to a package
+- dependents: packages that depend on a package, directly or within depth
+- dependencies: packages a package depends on, directly or within depth
+- graph: DOT or Mermaid graph from the project or a package, optionally reversed
+
+Go module edges come from each dependency's go.mod, read from the local module cache or proxy.golang.org and checked against go.sum.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("What to report"),
+			mcp.Enum("summary", "why", "dependents", "dependencies", "graph"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the project directory or to a go.mod, go.sum, package-lock.json or pnpm-lock.yaml"),
+		),
+		mcp.WithString("package",
+			mcp.Description("Package or module name, optionally with @version, e.g. golang.org/x/net or @babel/core@7.24.0. Required for why, dependents and dependencies; for graph, draws from this package instead of the project"),
+		),
+		mcp.WithString("ecosystem",
+			mcp.Description("Which lockfile to read when a directory has several (default: go, then npm, then pnpm)"),
+			mcp.Enum("go", "npm", "pnpm"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description(fmt.Sprintf("Most edges from the package to follow (default: 1 for dependents and dependencies, %d for graph; max: %d). 0 follows every edge for dependents and dependencies", defaultGraphDepth, maxDepth)),
+		),
+		mcp.WithString("format",
+			mcp.Description("Graph format (default: mermaid)"),
+			mcp.Enum("mermaid", "dot"),
+			mcp.DefaultString("mermaid"),
+		),
+		mcp.WithBoolean("reverse",
+			mcp.Description("For graph, draw the packages that depend on the package instead of its dependencies (default: false)"),
+		),
+		mcp.WithBoolean("include_dev",
+			mcp.Description("Include packages only needed for development in npm and pnpm projects (default: true)"),
+		),
+		mcp.WithNumber("max_paths",
+			mcp.Description(fmt.Sprintf("For why, most dependency chains per package (default: %d, max: %d)", defaultMaxPaths, maxMaxPaths)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads manifests and lockfiles
+		mcp.WithDestructiveHintAnnotation(false), // Does not modify anything
+		mcp.WithIdempotentHintAnnotation(true),   // Same lockfile produces the same answer
+		mcp.WithOpenWorldHintAnnotation(true),    // Fetches go.mod files from the Go module proxy
+	)
+}
+
+// Execute loads the dependency graph and runs the requested function
+func (d *DepGraphTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	query, _ := args["package"].(string)
+	query = strings.TrimSpace(query)
+	switch function {
+	case "summary", "graph":
+	case "why", "dependents", "dependencies":
+		if query == "" {
+			return nil, fmt.Errorf("missing required parameter for %s: package", function)
+		}
+	case "":
+		return nil, fmt.Errorf("missing required parameter: function")
+	default:
+		return nil, fmt.Errorf("unknown function %q: must be summary, why, dependents, dependencies or graph", function)
+	}
+
+	depth := 1
+	if function == "graph" {
+		depth = defaultGraphDepth
+	}
+	if raw, ok := args["depth"]; ok {
+		value, ok := raw.(float64)
+		lowest := 0.0
+		if function == "graph" {
+			lowest = 1
+		}
+		if !ok || value < lowest || value > maxDepth || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'depth' parameter: must be a whole number from %d to %d", int(lowest), maxDepth)
+		}
+		depth = int(value)
+	}
+	maxPaths := defaultMaxPaths
+	if raw, ok := args["max_paths"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxMaxPaths || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'max_paths' parameter: must be a whole number from 1 to %d", maxMaxPaths)
+		}
+		maxPaths = int(value)
+	}
+	format := "mermaid"
+	if value, ok := args["format"].(string); ok && value != "" {
+		if value != "mermaid" && value != "dot" {
+			return nil, fmt.Errorf("invalid format %q: must be mermaid or dot", value)
+		}
+		format = value
+	}
+	ecosystem, _ := args["ecosystem"].(string)
+	reverse, _ := args["reverse"].(bool)
+	includeDev := true
+	if value, ok := args["include_dev"].(bool); ok {
+		includeDev = value
+	}
+
+	resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, ecosystem, note, err := findManifest(resolved, ecosystem)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"manifest": manifest,
+		"function": function,
+	}).Debug("Loading dependency graph")
+
+	var g *graph
+	switch ecosystem {
+	case "go":
+		g, err = loadGo(ctx, logger, cache, manifest)
+	case "npm":
+		g, err = loadNPM(manifest)
+	case "pnpm":
+		g, err = loadPNPM(manifest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if note != "" {
+		g.warnings = append([]string{note}, g.warnings...)
+	}
+	if !includeDev {
+		g.dropDev()
+	}
+
+	var result any
+	switch function {
+	case "summary":
+		result = g.summary()
+	case "why":
+		result, err = g.why(query, maxPaths)
+	case "dependents", "dependencies":
+		result, err = g.list(query, depth, function == "dependents")
+	case "graph":
+		result, err = g.draw(query, depth, reverse, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep graph arrows such as -> readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Package names come from files the project downloaded, so scan them as other file content is
+	sourceCtx := security.SourceContext{
+		Tool:        "dep_graph",
+		URL:         "file://" + manifest,
+		ContentType: "dependencies",
+	}
+	if result, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(result) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// findManifest returns the manifest to read for a path and its ecosystem. A directory is searched
+// for each supported file, and a note is returned when more than one is found.
+func findManifest(path, ecosystem string) (string, string, string, error) {
+	if err := security.CheckFileAccess(path); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return "", "", "", security.FormatSecurityBlockError(secErr)
+		}
+		return "", "", "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", "", fmt.Errorf("cannot access %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		base := filepath.Base(path)
+		if base == "go.sum" {
+			path, base = filepath.Join(filepath.Dir(path), "go.mod"), "go.mod"
+		}
+		for _, m := range manifests {
+			if m.file == base {
+				return path, m.ecosystem, "", nil
+			}
+		}
+		return "", "", "", fmt.Errorf("unsupported file %s: use go.mod, go.sum, package-lock.json or pnpm-lock.yaml, or the project directory", base)
+	}
+
+	var found []string
+	var chosen, chosenEcosystem string
+	for _, m := range manifests {
+		if ecosystem != "" && m.ecosystem != ecosystem {
+			continue
+		}
+		candidate := filepath.Join(path, m.file)
+		if info, err := os.Stat(candidate); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		found = append(found, m.file)
+		if chosen == "" {
+			chosen, chosenEcosystem = candidate, m.ecosystem
+		}
+	}
+	if chosen == "" {
+		if ecosystem != "" {
+			return "", "", "", fmt.Errorf("no %s manifest or lockfile found in %s", ecosystem, path)
+		}
+		return "", "", "", fmt.Errorf("no go.mod, package-lock.json or pnpm-lock.yaml found in %s", path)
+	}
+	note := ""
+	if len(found) > 1 {
+		note = fmt.Sprintf("%s also has %s. Set ecosystem to read another.", path, strings.Join(found[1:], " and "))
+	}
+	return chosen, chosenEcosystem, note, nil
+}
+
+// readManifest reads a manifest or lockfile after checking it may be accessed. Errors from os.Stat
+// are returned unwrapped so that callers can check for missing files.
+func readManifest(path string) ([]byte, error) {
+	if err := security.CheckFileAccess(path); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return nil, security.FormatSecurityBlockError(secErr)
+		}
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > maxManifestSize {
+		return nil, fmt.Errorf("%s is too large (%d bytes, limit %d)", path, info.Size(), maxManifestSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// summary counts the graph's packages and lists the ones most worth knowing about
+func (g *graph) summary() Summary {
+	s := Summary{
+		Ecosystem: g.ecosystem,
+		Manifest:  g.manifest,
+		Roots:     g.labels(g.roots),
+		Packages:  len(g.nodes) - len(g.roots),
+		Warnings:  g.warnings,
+	}
+
+	direct := map[string]bool{}
+	versions := map[string][]string{}
+	var counts []DependentCount
+	for _, id := range slices.Sorted(maps.Keys(g.nodes)) {
+		n := g.nodes[id]
+		s.Edges += len(n.deps)
+		if n.root {
+			for _, dep := range n.deps {
+				direct[dep] = true
+			}
+			continue
+		}
+		if n.dev {
+			s.DevPackages++
+		}
+		versions[n.name] = append(versions[n.name], n.version)
+		counts = append(counts, DependentCount{Package: n.label(), Dependents: len(g.reverse(id))})
+	}
+	for _, id := range g.roots {
+		delete(direct, id)
+	}
+	s.Direct = g.labels(slices.Sorted(maps.Keys(direct)))
+
+	for _, name := range slices.Sorted(maps.Keys(versions)) {
+		if len(versions[name]) > 1 {
+			s.Duplicates = append(s.Duplicates, Duplicate{Name: name, Versions: versions[name]})
+		}
+	}
+	slices.SortStableFunc(counts, func(a, b DependentCount) int { return b.Dependents - a.Dependents })
+	for _, c := range counts[:min(len(counts), topDependedOnCount)] {
+		if c.Dependents > 1 {
+			s.MostDependedOn = append(s.MostDependedOn, c)
+		}
+	}
+	return s
+}
+
+// why lists the chains of dependencies leading to each version of a package
+func (g *graph) why(query string, maxPaths int) (WhyResult, error) {
+	matches, err := g.find(query)
+	if err != nil {
+		return WhyResult{}, err
+	}
+	result := WhyResult{Ecosystem: g.ecosystem, Manifest: g.manifest, Warnings: g.warnings}
+	for _, n := range matches {
+		paths := g.pathsTo(n.id, len(g.nodes))
+		reason := Reason{Package: n.label(), Dev: n.dev, TotalPaths: len(paths), Paths: [][]string{}}
+		for _, path := range paths[:min(len(paths), maxPaths)] {
+			reason.Paths = append(reason.Paths, g.labels(path))
+			if len(path) == 2 {
+				reason.Direct = true
+			}
+		}
+		result.Matches = append(result.Matches, reason)
+	}
+	return result, nil
+}
+
+// list returns the packages within depth of each version of a package, following dependencies
+// or dependents
+func (g *graph) list(query string, depth int, dependents bool) (ListResult, error) {
+	matches, err := g.find(query)
+	if err != nil {
+		return ListResult{}, err
+	}
+	result := ListResult{Ecosystem: g.ecosystem, Manifest: g.manifest, Direction: "dependencies", Depth: depth, Warnings: g.warnings}
+	if dependents {
+		result.Direction = "dependents"
+	}
+	for _, n := range matches {
+		order, distance, parent := g.walk([]string{n.id}, depth, dependents)
+		order = order[1:]
+		listing := Listing{Package: n.label(), Count: len(order), Packages: []Related{}}
+		if len(order) > maxListed {
+			order, listing.Truncated = order[:maxListed], true
+		}
+		// Breadth-first order is by depth, so sort by name within each depth
+		slices.SortStableFunc(order, func(a, b string) int {
+			return cmp.Or(cmp.Compare(distance[a], distance[b]), cmp.Compare(a, b))
+		})
+		for _, id := range order {
+			related := Related{Package: g.nodes[id].label(), Depth: distance[id]}
+			if parent[id] != n.id {
+				related.Via = g.nodes[parent[id]].label()
+			}
+			listing.Packages = append(listing.Packages, related)
+		}
+		result.Matches = append(result.Matches, listing)
+	}
+	return result, nil
+}
+
+// draw renders the graph from the project, or from a package, in DOT or Mermaid
+func (g *graph) draw(query string, depth int, reverse bool, format string) (GraphResult, error) {
+	start := g.roots
+	if query != "" {
+		matches, err := g.find(query)
+		if err != nil {
+			return GraphResult{}, err
+		}
+		start = nil
+		for _, n := range matches {
+			start = append(start, n.id)
+		}
+	} else if reverse {
+		return GraphResult{}, fmt.Errorf("reverse needs a package to draw the dependents of")
+	}
+
+	s := g.collect(start, depth, reverse)
+	result := GraphResult{
+		Ecosystem: g.ecosystem,
+		Manifest:  g.manifest,
+		Format:    format,
+		Nodes:     len(s.ids),
+		Edges:     len(s.edges),
+		Truncated: s.truncated,
+		Warnings:  g.warnings,
+	}
+	if s.truncated {
+		result.Warnings = append(slices.Clone(result.Warnings), fmt.Sprintf("The graph was limited to %d packages. Reduce depth or start from a package.", maxGraphNodes))
+	}
+	if format == "dot" {
+		result.Graph = g.renderDOT(s, start)
+	} else {
+		result.Graph = g.renderMermaid(s, start)
+	}
+	return result, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the dep_graph tool
+func (d *DepGraphTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Finding out why a package or module is in a project, what would be affected by upgrading or removing it, which packages are installed at several versions, or drawing part of the dependency tree for documentation or review.",
+		WhenNotToUse: "Checking for newer versions - use the package version tools. Checking for known vulnerabilities - use a security scanner. Projects without a lockfile, or ecosystems other than Go, npm and pnpm.",
+		CommonPatterns: []string{
+			"Start with summary to see direct dependencies, duplicates and the most depended-on packages",
+			"Use why with a package from a vulnerability report to see which direct dependency brings it in",
+			"Use dependents with depth 0 to see everything affected by upgrading a package",
+			"Use graph with a package and reverse to draw what depends on it",
+		},
+		ParameterDetails: map[string]string{
+			"path":        "A project directory, or the manifest itself. go.sum is read next to go.mod. For npm, package-lock.json must be lockfileVersion 2 or 3 (npm 7 and later). For pnpm, lockfile versions 6 and 9 (pnpm 8 and later) are supported.",
+			"package":     "Exact name as it appears in the lockfile: Go module paths, or npm names including the @scope. Add @version to pick one version when several are installed. Unknown names return similar names.",
+			"depth":       "Counted in edges from the package, so depth 1 is direct dependencies or dependents. Graphs are limited to 300 packages.",
+			"include_dev": "npm and pnpm only. Dev packages are those only reachable through devDependencies, and are drawn dashed.",
+			"max_paths":   "why shows the shortest chain through each direct dependent of the package, shortest first. total_paths counts them all.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Why a transitive module is included",
+				Arguments:      map[string]any{"function": "why", "path": "/home/user/project", "package": "golang.org/x/sys"},
+				ExpectedResult: "Chains of modules from the project to golang.org/x/sys, such as project -> github.com/spf13/cobra -> golang.org/x/sys",
+			},
+			{
+				Description:    "Everything that depends on a package, at any depth",
+				Arguments:      map[string]any{"function": "dependents", "path": "/home/user/web/package-lock.json", "package": "semver", "depth": 0},
+				ExpectedResult: "Each installed version of semver, with the packages depending on it, their depth and the package they reach it through",
+			},
+			{
+				Description:    "Mermaid graph of the project's dependencies two levels deep",
+				Arguments:      map[string]any{"function": "graph", "path": "/home/user/web", "format": "mermaid", "depth": 2, "include_dev": false},
+				ExpectedResult: "A flowchart LR diagram of the project's production dependencies and theirs",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Go modules have no dependencies and there are warnings about missing go.mod files",
+				Solution: "The module's go.mod could not be read from the module cache or proxy.golang.org. Run go mod download, or check network access and the security domain rules.",
+			},
+			{
+				Problem:  "Checksum mismatch for a go.mod",
+				Solution: "The go.mod read does not match go.sum. Run go mod verify in the project; the module cache may be corrupt.",
+			},
+			{
+				Problem:  "lockfileVersion 1 is not supported",
+				Solution: "Run npm install with npm 7 or later to upgrade package-lock.json to version 2 or 3.",
+			},
+		},
+	}
+}
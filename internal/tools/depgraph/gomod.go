@@ -0,0 +1,325 @@
+package depgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	// goProxyURL is the module proxy that go.mod files are fetched from when they are not in the
+	// local module cache, as the package version tools use
+	goProxyURL = "https://proxy.golang.org"
+
+	// goFetchWorkers is how many go.mod files are read at once
+	goFetchWorkers = 8
+
+	// maxGoModules is the most modules added to a graph for go.mod files from before Go 1.17, which
+	// do not list every module in the build
+	maxGoModules = 2000
+)
+
+// goLoader builds the module graph of a Go project
+type goLoader struct {
+	logger *logrus.Logger
+	cache  *sync.Map
+	client packageversions.HTTPClient
+	dir    string
+	// sums maps "path version" to the go.sum hash of that version's go.mod
+	sums map[string]string
+	// sumVersions lists the versions of each module that go.sum has source hashes for
+	sumVersions map[string][]string
+	replace     map[string]module.Version
+	modCache    string
+}
+
+// goModule is a module whose go.mod has been read
+type goModule struct {
+	id   string
+	file *modfile.File
+}
+
+// loadGo builds the module graph of the project whose go.mod is at path. Edges come from each
+// module's own go.mod, read from the local module cache or the Go module proxy and checked against
+// go.sum, so the graph matches `go mod graph` limited to the modules in the build.
+func loadGo(ctx context.Context, logger *logrus.Logger, cache *sync.Map, path string) (*graph, error) {
+	data, err := readManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.Module == nil {
+		return nil, fmt.Errorf("%s has no module directive", path)
+	}
+
+	l := &goLoader{
+		logger:      logger,
+		cache:       cache,
+		client:      packageversions.DefaultHTTPClient,
+		dir:         filepath.Dir(path),
+		sums:        map[string]string{},
+		sumVersions: map[string][]string{},
+		replace:     map[string]module.Version{},
+		modCache:    goModCache(),
+	}
+	sumPath := filepath.Join(l.dir, "go.sum")
+	if sumData, err := readManifest(sumPath); err == nil {
+		l.parseSums(sumData)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, r := range file.Replace {
+		key := r.Old.Path
+		if r.Old.Version != "" {
+			key += "@" + r.Old.Version
+		}
+		l.replace[key] = r.New
+	}
+
+	g := newGraph("go", path)
+	root := g.add(&node{id: file.Module.Mod.Path, name: file.Module.Mod.Path, root: true})
+	var pending []string
+	for _, r := range file.Require {
+		g.add(&node{id: r.Mod.Path, name: r.Mod.Path, version: r.Mod.Version})
+		if !r.Indirect {
+			g.link(root.id, r.Mod.Path)
+		}
+		pending = append(pending, r.Mod.Path)
+	}
+	if len(l.sums) == 0 && slices.ContainsFunc(file.Require, func(r *modfile.Require) bool { return !l.isLocal(r.Mod) }) {
+		g.warnings = append(g.warnings, "No go.sum was found next to go.mod, so go.mod files read from the module proxy were not verified.")
+	}
+
+	// Since Go 1.17, go.mod lists every module in the build, so only edges need adding. Older files
+	// list direct dependencies only, so modules are found by following requirements.
+	complete := file.Go != nil && semver.Compare("v"+file.Go.Version, "v1.17") >= 0
+	var modules []goModule
+	for len(pending) > 0 {
+		read := l.readAll(ctx, g, pending)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		modules = append(modules, read...)
+		pending = nil
+		if complete {
+			break
+		}
+		for _, m := range read {
+			for _, r := range m.file.Require {
+				if _, ok := g.nodes[r.Mod.Path]; ok || len(g.nodes) >= maxGoModules {
+					continue
+				}
+				g.add(&node{id: r.Mod.Path, name: r.Mod.Path, version: l.selectedVersion(r.Mod)})
+				pending = append(pending, r.Mod.Path)
+			}
+		}
+	}
+	if !complete && len(g.nodes) >= maxGoModules {
+		g.warnings = append(g.warnings, fmt.Sprintf("The graph was limited to %d modules.", maxGoModules))
+	}
+
+	for _, m := range modules {
+		for _, r := range m.file.Require {
+			if _, ok := g.nodes[r.Mod.Path]; ok {
+				g.link(m.id, r.Mod.Path)
+			}
+		}
+	}
+	g.finish()
+	return g, nil
+}
+
+// parseSums reads go.sum, keeping go.mod hashes and the versions with source hashes
+func (l *goLoader) parseSums(data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		if version, ok := strings.CutSuffix(fields[1], "/go.mod"); ok {
+			l.sums[fields[0]+" "+version] = fields[2]
+		} else {
+			l.sumVersions[fields[0]] = append(l.sumVersions[fields[0]], fields[1])
+		}
+	}
+}
+
+// selectedVersion picks the version of a module found by following requirements: the highest
+// version go.sum has source for, as the go command selects, or else the required version
+func (l *goLoader) selectedVersion(required module.Version) string {
+	selected := ""
+	for _, version := range l.sumVersions[required.Path] {
+		selected = semver.Max(selected, version)
+	}
+	if selected == "" {
+		return required.Version
+	}
+	return selected
+}
+
+// readAll reads the go.mod files of the given modules concurrently. Modules whose go.mod cannot be
+// read are reported as warnings and have no edges.
+func (l *goLoader) readAll(ctx context.Context, g *graph, ids []string) []goModule {
+	jobs := make(chan int)
+	results := make([]*modfile.File, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for range min(goFetchWorkers, len(ids)) {
+		wg.Go(func() {
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				n := g.nodes[ids[i]]
+				results[i], errs[i] = l.readGoMod(module.Version{Path: n.name, Version: n.version})
+			}
+		})
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var modules []goModule
+	for i, id := range ids {
+		if errs[i] != nil {
+			g.warnings = append(g.warnings, fmt.Sprintf("Dependencies of %s are missing: %v", g.nodes[id].label(), errs[i]))
+			continue
+		}
+		if results[i] != nil {
+			modules = append(modules, goModule{id: id, file: results[i]})
+		}
+	}
+	return modules
+}
+
+// replacement returns the replace directive that applies to a module version, if any
+func (l *goLoader) replacement(mod module.Version) (module.Version, bool) {
+	if target, ok := l.replace[mod.Path+"@"+mod.Version]; ok {
+		return target, true
+	}
+	target, ok := l.replace[mod.Path]
+	return target, ok
+}
+
+// isLocal reports whether a module is replaced by a directory on disk
+func (l *goLoader) isLocal(mod module.Version) bool {
+	target, ok := l.replacement(mod)
+	return ok && modfile.IsDirectoryPath(target.Path)
+}
+
+// readGoMod reads a module's go.mod, following replace directives. Local replacements are read
+// from disk, and other modules from the module cache or proxy.
+func (l *goLoader) readGoMod(mod module.Version) (*modfile.File, error) {
+	target, replaced := l.replacement(mod)
+	if replaced && modfile.IsDirectoryPath(target.Path) {
+		dir := target.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(l.dir, dir)
+		}
+		path := filepath.Join(dir, "go.mod")
+		data, err := readManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		return modfile.Parse(path, data, nil)
+	}
+	if replaced {
+		mod = target
+	}
+
+	data, err := l.fetchGoMod(mod)
+	if err != nil {
+		return nil, err
+	}
+	// Older modules may have no go.mod, in which case the proxy serves a file with only a module
+	// directive, so parse leniently
+	return modfile.ParseLax(mod.Path+"@"+mod.Version+"/go.mod", data, nil)
+}
+
+// fetchGoMod returns a module version's go.mod, checked against go.sum when it has the hash.
+// Unchecked files are cached too, so they are checked again for projects whose go.sum has them.
+func (l *goLoader) fetchGoMod(mod module.Version) ([]byte, error) {
+	var data []byte
+	cacheKey := "depgraph:gomod:" + mod.Path + "@" + mod.Version
+	if cached, ok := l.cache.Load(cacheKey); ok {
+		data = cached.([]byte)
+	} else {
+		var err error
+		if data, err = l.downloadGoMod(mod); err != nil {
+			return nil, err
+		}
+	}
+
+	if want, ok := l.sums[mod.Path+" "+mod.Version]; ok {
+		got, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if got != want {
+			return nil, fmt.Errorf("checksum mismatch for %s@%s/go.mod: go.sum has %s but the downloaded file has %s", mod.Path, mod.Version, want, got)
+		}
+	}
+	l.cache.Store(cacheKey, data)
+	return data, nil
+}
+
+// downloadGoMod reads a module version's go.mod from the module cache, or from the proxy when the
+// module has not been downloaded
+func (l *goLoader) downloadGoMod(mod module.Version) ([]byte, error) {
+	escapedPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.modCache != "" {
+		cached := filepath.Join(l.modCache, "cache", "download", filepath.FromSlash(escapedPath), "@v", escapedVersion+".mod")
+		if security.CheckFileAccess(cached) == nil {
+			if data, err := os.ReadFile(cached); err == nil {
+				return data, nil
+			}
+		}
+	}
+	url := fmt.Sprintf("%s/%s/@v/%s.mod", goProxyURL, escapedPath, escapedVersion)
+	return packageversions.MakeRequestWithLogger(l.client, l.logger, "GET", url, nil)
+}
+
+// goModCache locates the module cache as the go command does, without running it
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return ""
+}
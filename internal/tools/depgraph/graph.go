@@ -0,0 +1,212 @@
+package depgraph
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// node is a package in the dependency graph
+type node struct {
+	id      string
+	name    string
+	version string
+	dev     bool
+	root    bool
+	deps    []string
+}
+
+// label names a node with its version
+func (n *node) label() string {
+	if n.version == "" {
+		return n.name
+	}
+	return n.name + "@" + n.version
+}
+
+// graph is a project's dependency graph, from its root packages to every package they depend on
+type graph struct {
+	ecosystem string
+	manifest  string
+	roots     []string
+	nodes     map[string]*node
+	warnings  []string
+	// dependents maps each node to the nodes depending on it, built on first use
+	dependents map[string][]string
+}
+
+// newGraph returns an empty graph
+func newGraph(ecosystem, manifest string) *graph {
+	return &graph{ecosystem: ecosystem, manifest: manifest, nodes: map[string]*node{}}
+}
+
+// add adds a node unless one with its ID exists, returning the node in the graph
+func (g *graph) add(n *node) *node {
+	if existing, ok := g.nodes[n.id]; ok {
+		return existing
+	}
+	g.nodes[n.id] = n
+	if n.root {
+		g.roots = append(g.roots, n.id)
+	}
+	return n
+}
+
+// link adds an edge from one node to another, when both are in the graph
+func (g *graph) link(from, to string) {
+	n := g.nodes[from]
+	if n == nil || g.nodes[to] == nil || from == to || slices.Contains(n.deps, to) {
+		return
+	}
+	n.deps = append(n.deps, to)
+}
+
+// finish sorts edges and roots so that output is stable
+func (g *graph) finish() {
+	for _, n := range g.nodes {
+		slices.Sort(n.deps)
+	}
+	slices.Sort(g.roots)
+}
+
+// dropDev removes dev-only packages and the edges to them
+func (g *graph) dropDev() {
+	maps.DeleteFunc(g.nodes, func(_ string, n *node) bool { return n.dev })
+	for _, n := range g.nodes {
+		n.deps = slices.DeleteFunc(n.deps, func(id string) bool { return g.nodes[id] == nil })
+	}
+	g.dependents = nil
+}
+
+// reverse returns the nodes that depend on a node
+func (g *graph) reverse(id string) []string {
+	if g.dependents == nil {
+		g.dependents = map[string][]string{}
+		for _, n := range g.nodes {
+			for _, dep := range n.deps {
+				g.dependents[dep] = append(g.dependents[dep], n.id)
+			}
+		}
+		for _, ids := range g.dependents {
+			slices.Sort(ids)
+		}
+	}
+	return g.dependents[id]
+}
+
+// find returns the nodes matching a package name, optionally with @version, sorted by ID
+func (g *graph) find(query string) ([]*node, error) {
+	name, version := splitQuery(query)
+	var matches []*node
+	for _, n := range g.nodes {
+		if n.name == name && (version == "" || n.version == version || strings.TrimPrefix(n.version, "v") == strings.TrimPrefix(version, "v")) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s is not in the dependency graph of %s%s", query, g.manifest, g.suggest(name))
+	}
+	slices.SortFunc(matches, func(a, b *node) int { return cmp.Compare(a.id, b.id) })
+	return matches, nil
+}
+
+// suggest lists packages whose names contain the query, to help with typos and partial names
+func (g *graph) suggest(name string) string {
+	var similar []string
+	lower := strings.ToLower(name)
+	for _, n := range g.nodes {
+		if strings.Contains(strings.ToLower(n.name), lower) && !slices.Contains(similar, n.name) {
+			similar = append(similar, n.name)
+		}
+	}
+	if len(similar) == 0 {
+		return ""
+	}
+	slices.Sort(similar)
+	return ". Similar packages: " + strings.Join(similar[:min(len(similar), 10)], ", ")
+}
+
+// splitQuery separates a package query into a name and an optional version. Scoped npm names start
+// with @, so only a later @ starts the version.
+func splitQuery(query string) (string, string) {
+	query = strings.TrimSpace(query)
+	if i := strings.LastIndex(query, "@"); i > 0 {
+		return query[:i], query[i+1:]
+	}
+	return query, ""
+}
+
+// walk visits nodes breadth first from the start nodes, following dependencies or, when reverse is
+// set, dependents, up to depth edges away. It returns each node reached with its distance and the
+// node it was first reached from.
+func (g *graph) walk(start []string, depth int, reverse bool) (order []string, distance map[string]int, parent map[string]string) {
+	distance = map[string]int{}
+	parent = map[string]string{}
+	queue := slices.Clone(start)
+	for _, id := range start {
+		distance[id] = 0
+	}
+	order = slices.Clone(start)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if depth > 0 && distance[id] >= depth {
+			continue
+		}
+		next := g.nodes[id].deps
+		if reverse {
+			next = g.reverse(id)
+		}
+		for _, to := range next {
+			if _, seen := distance[to]; seen {
+				continue
+			}
+			distance[to] = distance[id] + 1
+			parent[to] = id
+			order = append(order, to)
+			queue = append(queue, to)
+		}
+	}
+	return order, distance, parent
+}
+
+// pathsTo returns the shortest path from a root to the target through each of its dependents, up
+// to limit paths, shortest first
+func (g *graph) pathsTo(target string, limit int) [][]string {
+	_, distance, parent := g.walk(g.roots, 0, false)
+	if _, ok := distance[target]; !ok {
+		return nil
+	}
+	if slices.Contains(g.roots, target) {
+		return [][]string{{target}}
+	}
+
+	var paths [][]string
+	for _, via := range g.reverse(target) {
+		if _, ok := distance[via]; !ok {
+			continue
+		}
+		path := []string{target}
+		for id := via; ; id = parent[id] {
+			path = append(path, id)
+			if _, ok := parent[id]; !ok {
+				break
+			}
+		}
+		slices.Reverse(path)
+		paths = append(paths, path)
+	}
+	slices.SortStableFunc(paths, func(a, b []string) int { return len(a) - len(b) })
+	return paths[:min(len(paths), limit)]
+}
+
+// labels converts node IDs to labels
+func (g *graph) labels(ids []string) []string {
+	labels := make([]string, len(ids))
+	for i, id := range ids {
+		labels[i] = g.nodes[id].label()
+	}
+	return labels
+}
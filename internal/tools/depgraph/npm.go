@@ -0,0 +1,136 @@
+package depgraph
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// npmLock is the part of package-lock.json that describes installed packages
+type npmLock struct {
+	Name            string                `json:"name"`
+	LockfileVersion int                   `json:"lockfileVersion"`
+	Packages        map[string]npmPackage `json:"packages"`
+}
+
+// npmPackage is an entry in package-lock.json's packages map, keyed by install location
+type npmPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	DevOptional          bool              `json:"devOptional"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// loadNPM builds the dependency graph from a package-lock.json. Lockfile versions 2 and 3 record
+// where every package is installed, so dependencies are resolved as Node.js does, by looking in
+// node_modules directories from the requiring package upwards.
+func loadNPM(lockPath string) (*graph, error) {
+	data, err := readManifest(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	var lock npmLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", lockPath, err)
+	}
+	if lock.LockfileVersion < 2 || lock.Packages == nil {
+		return nil, fmt.Errorf("%s uses lockfileVersion %d, which has no packages section. Run npm install with npm 7 or later to upgrade it", lockPath, lock.LockfileVersion)
+	}
+
+	g := newGraph("npm", lockPath)
+	locations := slices.Sorted(maps.Keys(lock.Packages))
+	ids := make(map[string]string, len(locations))
+	for _, location := range locations {
+		entry := lock.Packages[location]
+		if entry.Link {
+			continue
+		}
+		n := &node{name: npmName(location, entry, lock.Name), version: entry.Version, dev: entry.Dev || entry.DevOptional}
+		// The project and its workspaces are the only packages outside node_modules
+		n.root = !strings.Contains(location, "node_modules/")
+		if n.root {
+			n.dev = false
+		}
+		n.id = n.label()
+		ids[location] = g.add(n).id
+	}
+	// Workspaces are linked into node_modules, so requiring them resolves to the workspace itself
+	for _, location := range locations {
+		if entry := lock.Packages[location]; entry.Link {
+			if id, ok := ids[entry.Resolved]; ok {
+				ids[location] = id
+			}
+		}
+	}
+
+	missing := 0
+	for _, location := range locations {
+		entry := lock.Packages[location]
+		if entry.Link {
+			continue
+		}
+		from := ids[location]
+		required := []map[string]string{entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies}
+		if g.nodes[from].root {
+			// Only the project's and workspaces' own dev dependencies are installed
+			required = append(required, entry.DevDependencies)
+		}
+		for i, deps := range required {
+			for _, name := range slices.Sorted(maps.Keys(deps)) {
+				installed, ok := resolveNPM(lock.Packages, location, name)
+				if id, known := ids[installed]; ok && known {
+					g.link(from, id)
+				} else if i == 0 {
+					// Optional and peer dependencies are often not installed
+					missing++
+				}
+			}
+		}
+	}
+	if missing > 0 {
+		g.warnings = append(g.warnings, fmt.Sprintf("%d dependencies are not installed according to the lockfile. Run npm install to update it.", missing))
+	}
+	g.finish()
+	return g, nil
+}
+
+// npmName returns a package's name: the project's own name for the root, a workspace's name from
+// its package.json, or the directory it is installed in
+func npmName(location string, entry npmPackage, projectName string) string {
+	if location == "" {
+		return cmp.Or(entry.Name, projectName, "(root)")
+	}
+	if i := strings.LastIndex(location, "node_modules/"); i >= 0 {
+		return location[i+len("node_modules/"):]
+	}
+	return cmp.Or(entry.Name, path.Base(location))
+}
+
+// resolveNPM finds where a dependency of the package at location is installed, checking the
+// package's own node_modules and then each parent's, as require does
+func resolveNPM(packages map[string]npmPackage, location, name string) (string, bool) {
+	for {
+		candidate := path.Join(location, "node_modules", name)
+		if _, ok := packages[candidate]; ok {
+			return candidate, true
+		}
+		if location == "" {
+			return "", false
+		}
+		if i := strings.LastIndex(location, "/node_modules/"); i >= 0 {
+			location = location[:i]
+		} else {
+			location = ""
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package depgraph
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pnpmLock is the part of pnpm-lock.yaml that describes the dependency graph
+type pnpmLock struct {
+	LockfileVersion any                     `yaml:"lockfileVersion"`
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	// Projects without workspaces list their dependencies at the top level in lockfile version 6
+	pnpmImporter `yaml:",inline"`
+	Packages     map[string]pnpmPackage `yaml:"packages"`
+	// Lockfile version 9 moved the dependencies of each package from packages to snapshots
+	Snapshots map[string]pnpmPackage `yaml:"snapshots"`
+}
+
+// pnpmImporter is a project or workspace in the lockfile
+type pnpmImporter struct {
+	Dependencies         map[string]pnpmDependency `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmDependency `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmDependency `yaml:"optionalDependencies"`
+}
+
+// pnpmDependency is a project's dependency with the version it resolved to
+type pnpmDependency struct {
+	Version string `yaml:"version"`
+}
+
+// pnpmPackage is an installed package's dependencies, as name to resolved version
+type pnpmPackage struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// loadPNPM builds the dependency graph from a pnpm-lock.yaml, for lockfile versions 6 and 9.
+// pnpm installs a package once for each set of peer dependencies, which are merged here.
+func loadPNPM(lockPath string) (*graph, error) {
+	data, err := readManifest(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", lockPath, err)
+	}
+	version, _ := strconv.ParseFloat(strings.TrimSuffix(fmt.Sprint(lock.LockfileVersion), "-inlineSpecifiers"), 64)
+	if version < 6 {
+		return nil, fmt.Errorf("%s uses lockfileVersion %v. Only versions 6 and 9, written by pnpm 8 and later, are supported", lockPath, lock.LockfileVersion)
+	}
+	if lock.Importers == nil {
+		lock.Importers = map[string]pnpmImporter{".": lock.pnpmImporter}
+	}
+	packages := lock.Snapshots
+	if packages == nil {
+		packages = lock.Packages
+	}
+
+	g := newGraph("pnpm", lockPath)
+	importers := map[string]string{}
+	for _, dir := range slices.Sorted(maps.Keys(lock.Importers)) {
+		name := dir
+		if dir == "." {
+			name = filepath.Base(filepath.Dir(lockPath))
+		}
+		importers[dir] = g.add(&node{id: name, name: name, root: true}).id
+	}
+	for _, key := range slices.Sorted(maps.Keys(packages)) {
+		name, version := splitPNPMKey(key)
+		g.add(&node{id: name + "@" + version, name: name, version: version})
+	}
+
+	// Packages only reachable through dev dependencies are marked dev afterwards
+	var production []string
+	for _, dir := range slices.Sorted(maps.Keys(lock.Importers)) {
+		importer := lock.Importers[dir]
+		from := importers[dir]
+		for _, deps := range []map[string]pnpmDependency{importer.Dependencies, importer.OptionalDependencies, importer.DevDependencies} {
+			for _, name := range slices.Sorted(maps.Keys(deps)) {
+				id := pnpmTarget(g, importers, dir, name, deps[name].Version)
+				g.link(from, id)
+				if _, dev := importer.DevDependencies[name]; !dev && id != "" {
+					production = append(production, id)
+				}
+			}
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(packages)) {
+		name, version := splitPNPMKey(key)
+		from := name + "@" + version
+		for _, deps := range []map[string]string{packages[key].Dependencies, packages[key].OptionalDependencies} {
+			for _, dep := range slices.Sorted(maps.Keys(deps)) {
+				g.link(from, pnpmTarget(g, importers, "", dep, deps[dep]))
+			}
+		}
+	}
+
+	order, _, _ := g.walk(production, 0, false)
+	reached := make(map[string]bool, len(order))
+	for _, id := range order {
+		reached[id] = true
+	}
+	for _, n := range g.nodes {
+		n.dev = !n.root && !reached[n.id]
+	}
+	g.finish()
+	return g, nil
+}
+
+// splitPNPMKey reads a package's name and version from its key in the lockfile, such as
+// /@scope/name@1.0.0(react@18.2.0) in version 6 or name@1.0.0(react@18.2.0) in version 9
+func splitPNPMKey(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i > 0 {
+		key = key[:i]
+	}
+	return splitQuery(key)
+}
+
+// pnpmTarget returns the node a dependency resolved to, or "" if it is not in the graph. Versions
+// may have a peer dependency suffix, name another package for aliases such as string-width-cjs:
+// string-width@4.2.3, or link to a workspace relative to the importer's directory.
+func pnpmTarget(g *graph, importers map[string]string, dir, name, version string) string {
+	if target, ok := strings.CutPrefix(version, "link:"); ok {
+		return importers[path.Join(dir, target)]
+	}
+	if i := strings.Index(version, "("); i > 0 {
+		version = version[:i]
+	}
+	id := name + "@" + version
+	if i := strings.LastIndex(version, "@"); i > 0 {
+		id = strings.TrimPrefix(version, "/")
+	}
+	if _, ok := g.nodes[id]; !ok {
+		return ""
+	}
+	return id
+}
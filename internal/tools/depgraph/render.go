@@ -0,0 +1,95 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxGraphNodes is the most nodes drawn in a DOT or Mermaid graph
+const maxGraphNodes = 300
+
+// subgraph is the part of a graph that is drawn
+type subgraph struct {
+	ids       []string
+	edges     [][2]string
+	truncated bool
+}
+
+// collect selects the nodes within depth of the start nodes, and the edges between them
+func (g *graph) collect(start []string, depth int, reverse bool) subgraph {
+	order, _, _ := g.walk(start, depth, reverse)
+	s := subgraph{ids: order}
+	if len(s.ids) > maxGraphNodes {
+		s.ids, s.truncated = s.ids[:maxGraphNodes], true
+	}
+	included := make(map[string]bool, len(s.ids))
+	for _, id := range s.ids {
+		included[id] = true
+	}
+	for _, id := range s.ids {
+		for _, dep := range g.nodes[id].deps {
+			if included[dep] {
+				s.edges = append(s.edges, [2]string{id, dep})
+			}
+		}
+	}
+	return s
+}
+
+// renderDOT draws a subgraph in Graphviz DOT. Start nodes are boxes and dev-only packages are dashed.
+func (g *graph) renderDOT(s subgraph, start []string) string {
+	var out strings.Builder
+	out.WriteString("digraph dependencies {\n")
+	out.WriteString("  rankdir=LR;\n")
+	out.WriteString("  node [shape=ellipse, fontsize=10];\n")
+	for _, id := range s.ids {
+		n := g.nodes[id]
+		var attributes []string
+		attributes = append(attributes, "label="+strconv.Quote(n.label()))
+		if slices.Contains(start, id) {
+			attributes = append(attributes, "shape=box")
+		}
+		if n.dev {
+			attributes = append(attributes, "style=dashed")
+		}
+		fmt.Fprintf(&out, "  %s [%s];\n", strconv.Quote(id), strings.Join(attributes, ", "))
+	}
+	for _, edge := range s.edges {
+		fmt.Fprintf(&out, "  %s -> %s;\n", strconv.Quote(edge[0]), strconv.Quote(edge[1]))
+	}
+	out.WriteString("}\n")
+	return out.String()
+}
+
+// renderMermaid draws a subgraph as a Mermaid flowchart. Node IDs are numbered, as package names
+// contain characters Mermaid does not allow in IDs.
+func (g *graph) renderMermaid(s subgraph, start []string) string {
+	index := make(map[string]string, len(s.ids))
+	var out strings.Builder
+	out.WriteString("flowchart LR\n")
+	for i, id := range s.ids {
+		index[id] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(g.nodes[id].label(), `"`, "#quot;")
+		if slices.Contains(start, id) {
+			fmt.Fprintf(&out, "  %s[\"%s\"]\n", index[id], label)
+		} else {
+			fmt.Fprintf(&out, "  %s(\"%s\")\n", index[id], label)
+		}
+	}
+	for _, edge := range s.edges {
+		fmt.Fprintf(&out, "  %s --> %s\n", index[edge[0]], index[edge[1]])
+	}
+	var dev []string
+	for _, id := range s.ids {
+		if g.nodes[id].dev {
+			dev = append(dev, index[id])
+		}
+	}
+	if len(dev) > 0 {
+		out.WriteString("  classDef dev stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&out, "  class %s dev\n", strings.Join(dev, ","))
+	}
+	return out.String()
+}
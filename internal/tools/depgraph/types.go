@@ -0,0 +1,83 @@
+package depgraph
+
+// Summary describes a project's dependency graph as a whole
+type Summary struct {
+	Ecosystem      string           `json:"ecosystem"`
+	Manifest       string           `json:"manifest"`
+	Roots          []string         `json:"roots"`
+	Packages       int              `json:"packages"`
+	DevPackages    int              `json:"dev_packages,omitempty"`
+	Edges          int              `json:"edges"`
+	Direct         []string         `json:"direct"`
+	Duplicates     []Duplicate      `json:"duplicates,omitempty"`
+	MostDependedOn []DependentCount `json:"most_depended_on,omitempty"`
+	Warnings       []string         `json:"warnings,omitempty"`
+}
+
+// Duplicate is a package installed at more than one version
+type Duplicate struct {
+	Name     string   `json:"name"`
+	Versions []string `json:"versions"`
+}
+
+// DependentCount is a package with how many packages depend on it directly
+type DependentCount struct {
+	Package    string `json:"package"`
+	Dependents int    `json:"dependents"`
+}
+
+// WhyResult explains why a package is in the graph
+type WhyResult struct {
+	Ecosystem string   `json:"ecosystem"`
+	Manifest  string   `json:"manifest"`
+	Matches   []Reason `json:"matches"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// Reason lists the chains of dependencies from the project to one version of a package
+type Reason struct {
+	Package string `json:"package"`
+	Direct  bool   `json:"direct"`
+	Dev     bool   `json:"dev,omitempty"`
+	// Paths holds the shortest chain through each package that depends on this one directly
+	Paths      [][]string `json:"paths"`
+	TotalPaths int        `json:"total_paths"`
+}
+
+// ListResult lists the dependencies or dependents of a package
+type ListResult struct {
+	Ecosystem string    `json:"ecosystem"`
+	Manifest  string    `json:"manifest"`
+	Direction string    `json:"direction"`
+	Depth     int       `json:"depth"`
+	Matches   []Listing `json:"matches"`
+	Warnings  []string  `json:"warnings,omitempty"`
+}
+
+// Listing is the packages reached from one version of a package
+type Listing struct {
+	Package   string    `json:"package"`
+	Count     int       `json:"count"`
+	Packages  []Related `json:"packages"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// Related is a package reached from another, with how many edges away it is and the package it
+// was reached through
+type Related struct {
+	Package string `json:"package"`
+	Depth   int    `json:"depth"`
+	Via     string `json:"via,omitempty"`
+}
+
+// GraphResult is a drawing of part of the graph
+type GraphResult struct {
+	Ecosystem string   `json:"ecosystem"`
+	Manifest  string   `json:"manifest"`
+	Format    string   `json:"format"`
+	Nodes     int      `json:"nodes"`
+	Edges     int      `json:"edges"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Graph     string   `json:"graph"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
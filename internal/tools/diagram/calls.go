@@ -0,0 +1,192 @@
+package diagram
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// callLine matches a call in the text form: "caller -> callee: label", with --> for a reply and ~>
+// for an asynchronous call
+var callLine = regexp.MustCompile(`^(.+?)\s*(-->|->|~>)\s*(.+?)\s*(?::\s*(.*))?$`)
+
+// parseCalls reads a call list given as a JSON array of calls or as one call per line
+func parseCalls(input string) ([]Call, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf("input is required for calls: a JSON array such as [{\"from\": \"Client\", \"to\": \"API\", \"label\": \"GET /orders\"}] or lines such as 'Client -> API: GET /orders'")
+	}
+	var calls []Call
+	if strings.HasPrefix(input, "[") {
+		if err := json.Unmarshal([]byte(input), &calls); err != nil {
+			return nil, fmt.Errorf("failed to parse calls as JSON: %w", err)
+		}
+	} else {
+		number := 0
+		for line := range strings.Lines(input) {
+			number++
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+				continue
+			}
+			match := callLine.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("line %d is not a call, expected 'caller -> callee: label': %s", number, line)
+			}
+			calls = append(calls, Call{From: match[1], To: match[3], Label: match[4], Reply: match[2] == "-->", Async: match[2] == "~>"})
+		}
+	}
+	for i, call := range calls {
+		if strings.TrimSpace(call.From) == "" || strings.TrimSpace(call.To) == "" {
+			return nil, fmt.Errorf("call %d needs both from and to", i+1)
+		}
+		calls[i].From, calls[i].To = strings.TrimSpace(call.From), strings.TrimSpace(call.To)
+	}
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("input has no calls")
+	}
+	return calls, nil
+}
+
+// splitMember splits a qualified function name such as orders.Service.Create into its owner,
+// orders.Service, and its name, Create. A name without a dot, or any name when split is false, is
+// its own owner.
+func splitMember(name string, split bool) (owner, member string) {
+	if i := strings.LastIndex(name, "."); split && i > 0 && i < len(name)-1 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// callsSequence draws the calls in order between their owners, with a call's method as its message
+// when it has no label
+func callsSequence(calls []Call, title string, split bool) *Result {
+	b := newBuilder(title, "sequenceDiagram", "p")
+	var body []string
+	for _, call := range calls {
+		from, _ := splitMember(call.From, split)
+		to, member := splitMember(call.To, split)
+		if call.Reply {
+			member = ""
+		}
+		fromID, ok := b.id(from)
+		if !ok {
+			continue
+		}
+		toID, ok := b.id(to)
+		if !ok {
+			continue
+		}
+		label := call.Label
+		if label == "" && member != "" {
+			label = member + "()"
+		}
+		arrow := "->>"
+		switch {
+		case call.Reply:
+			arrow = "-->>"
+		case call.Async:
+			arrow = "-)"
+		}
+		body = append(body, fmt.Sprintf("%s%s%s: %s", fromID, arrow, toID, messageReplacer.Replace(label)))
+		b.edges++
+		if call.Return != "" && !call.Reply {
+			body = append(body, fmt.Sprintf("%s-->>%s: %s", toID, fromID, messageReplacer.Replace(call.Return)))
+		}
+	}
+	for _, name := range b.order {
+		b.line("participant %s as %s", b.ids[name], messageReplacer.Replace(name))
+	}
+	for _, statement := range body {
+		b.line("%s", statement)
+	}
+	return b.result("calls", "sequence")
+}
+
+// callsFlow draws each function as a node with an edge for each distinct call, labelled with how
+// often it was made when that is more than once
+func callsFlow(calls []Call, title, direction string) *Result {
+	b := newBuilder(title, "flowchart "+direction, "n")
+	type edge struct{ from, to, label string }
+	var edges []edge
+	counts := map[edge]int{}
+	for _, call := range calls {
+		if call.Reply {
+			continue
+		}
+		fromID, ok := b.id(call.From)
+		if !ok {
+			continue
+		}
+		toID, ok := b.id(call.To)
+		if !ok {
+			continue
+		}
+		e := edge{fromID, toID, call.Label}
+		if counts[e] == 0 {
+			edges = append(edges, e)
+		}
+		counts[e]++
+	}
+	for _, name := range b.order {
+		b.line("%s[\"%s\"]", b.ids[name], labelReplacer.Replace(name))
+	}
+	for _, e := range edges {
+		label := e.label
+		if counts[e] > 1 {
+			label = strings.TrimSpace(fmt.Sprintf("%s (%d calls)", label, counts[e]))
+		}
+		if label == "" {
+			b.line("%s --> %s", e.from, e.to)
+		} else {
+			b.line("%s -->|\"%s\"| %s", e.from, labelReplacer.Replace(label), e.to)
+		}
+		b.edges++
+	}
+	return b.result("calls", "flow")
+}
+
+// callsClass draws the owners of the functions as classes with the methods called on them, and a
+// dependency from each caller's owner to each callee's
+func callsClass(calls []Call, title, direction string, split bool) *Result {
+	b := newBuilder(title, "classDiagram\n  direction "+direction, "c")
+	members := map[string][]string{}
+	var dependencies [][2]string
+	for _, call := range calls {
+		if call.Reply {
+			continue
+		}
+		from, callerMember := splitMember(call.From, split)
+		to, member := splitMember(call.To, split)
+		fromID, ok := b.id(from)
+		if !ok {
+			continue
+		}
+		toID, ok := b.id(to)
+		if !ok {
+			continue
+		}
+		if member != "" && !slices.Contains(members[to], member) {
+			members[to] = append(members[to], member)
+		}
+		if callerMember != "" && !slices.Contains(members[from], callerMember) {
+			members[from] = append(members[from], callerMember)
+		}
+		if pair := [2]string{fromID, toID}; fromID != toID && !slices.Contains(dependencies, pair) {
+			dependencies = append(dependencies, pair)
+		}
+	}
+	for _, name := range b.order {
+		b.line("class %s[\"%s\"]", b.ids[name], labelReplacer.Replace(name))
+		for _, member := range members[name] {
+			b.line("%s : +%s()", b.ids[name], memberReplacer.Replace(member))
+		}
+	}
+	for _, pair := range dependencies {
+		b.line("%s ..> %s", pair[0], pair[1])
+		b.edges++
+	}
+	return b.result("calls", "class")
+}
@@ -0,0 +1,306 @@
+package diagram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDepth = 3
+	maxDepth     = 10
+
+	// maxSpecBytes limits the size of a spec read from a file
+	maxSpecBytes = 10 * 1024 * 1024
+)
+
+var (
+	// sources are the kinds of input that can be drawn
+	sources = []string{"openapi", "calls", "directory"}
+
+	// diagrams are the kinds of diagram that can be drawn
+	diagrams = []string{"sequence", "class", "flow"}
+
+	// defaultDiagrams is the diagram drawn from each source when none is requested
+	defaultDiagrams = map[string]string{"openapi": "class", "calls": "sequence", "directory": "flow"}
+)
+
+// MermaidDiagramTool generates Mermaid diagrams from OpenAPI specs, call lists and directory trees
+type MermaidDiagramTool struct{}
+
+// init registers the mermaid_diagram tool
+func init() {
+	registry.Register(&MermaidDiagramTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *MermaidDiagramTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"mermaid_diagram",
+		mcp.WithDescription(`Generate Mermaid diagram source from structured input, for documentation, READMEs and reviews.
+
+Sources:
+- openapi: an OpenAPI 3 or Swagger 2 spec (file or inline) as a class diagram of its schemas, a flow of its tags and endpoints, or a sequence of requests and responses
+- calls: a list of calls between functions or components as a sequence diagram, a call graph (flow) or a class diagram of the types whose methods are called
+- directory: a directory tree as a flowchart
+
+Returns Mermaid text that GitHub, GitLab and most Markdown viewers render in a mermaid code block.`),
+		mcp.WithString("source",
+			mcp.Required(),
+			mcp.Description("Kind of input: "+strings.Join(sources, ", ")),
+			mcp.Enum(sources...),
+		),
+		mcp.WithString("diagram",
+			mcp.Description("Kind of diagram: sequence, class or flow (default: class for openapi, sequence for calls, flow for directory)"),
+			mcp.Enum(diagrams...),
+		),
+		mcp.WithString("input",
+			mcp.Description("calls: a JSON array of {from, to, label, return, async, reply} or one call per line as 'caller -> callee: label' (--> for a reply, ~> for an asynchronous call). openapi: the spec as JSON or YAML, instead of path"),
+		),
+		mcp.WithString("path",
+			mcp.Description("openapi: absolute path of a spec file. directory: absolute path of the directory to draw"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("openapi: only draw operations whose path starts with this or that have this tag, and the schemas they use"),
+		),
+		mcp.WithBoolean("split_members",
+			mcp.Description("calls: treat the part of a name after its last dot as a method of the part before it, so orders.Service.Create is Create on orders.Service (default: true). Set false when names are components with dots, such as host names"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Layout direction for flow and class diagrams: TD (top down) or LR (left to right) (default: TD)"),
+			mcp.Enum("TD", "LR"),
+		),
+		mcp.WithNumber("max_depth",
+			mcp.Description(fmt.Sprintf("directory: levels of subdirectories to draw (default: %d, max: %d)", defaultDepth, maxDepth)),
+		),
+		mcp.WithString("title",
+			mcp.Description("Title shown above the diagram"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the spec file or directory listing
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // The same input draws the same diagram
+		mcp.WithOpenWorldHintAnnotation(false),   // Works on the input and local files only
+	)
+}
+
+// Execute draws the requested diagram
+func (t *MermaidDiagramTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	source, _ := args["source"].(string)
+	if !slices.Contains(sources, source) {
+		return nil, fmt.Errorf("source must be one of %s, got: %q", strings.Join(sources, ", "), source)
+	}
+	kind, _ := args["diagram"].(string)
+	if kind == "" {
+		kind = defaultDiagrams[source]
+	}
+	if !slices.Contains(diagrams, kind) {
+		return nil, fmt.Errorf("diagram must be one of %s, got: %q", strings.Join(diagrams, ", "), kind)
+	}
+	if source == "directory" && kind != "flow" {
+		return nil, fmt.Errorf("a directory can only be drawn as a flow diagram")
+	}
+	direction, _ := args["direction"].(string)
+	if direction == "" {
+		direction = "TD"
+	}
+	if direction != "TD" && direction != "LR" {
+		return nil, fmt.Errorf("direction must be TD or LR, got: %q", direction)
+	}
+	title, _ := args["title"].(string)
+	title = strings.TrimSpace(title)
+	input, _ := args["input"].(string)
+	logger.WithFields(logrus.Fields{"source": source, "diagram": kind}).Debug("Generating Mermaid diagram")
+
+	var result *Result
+	var location string
+	switch source {
+	case "calls":
+		calls, err := parseCalls(input)
+		if err != nil {
+			return nil, err
+		}
+		split := true
+		if value, ok := args["split_members"].(bool); ok {
+			split = value
+		}
+		switch kind {
+		case "sequence":
+			result = callsSequence(calls, title, split)
+		case "flow":
+			result = callsFlow(calls, title, direction)
+		case "class":
+			result = callsClass(calls, title, direction, split)
+		}
+	case "openapi":
+		data := []byte(input)
+		if strings.TrimSpace(input) == "" {
+			path, err := resolvePath(ctx, args, false)
+			if err != nil {
+				return nil, fmt.Errorf("openapi needs the spec as input or a path to it: %w", err)
+			}
+			if data, err = readSpec(path); err != nil {
+				return nil, err
+			}
+			location = path
+		}
+		filter, _ := args["filter"].(string)
+		filter = strings.TrimSpace(filter)
+		s, err := parseSpec(data, filter)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case "class":
+			result = openAPIClass(s, title, direction, filter != "")
+		case "flow":
+			result = openAPIFlow(s, title, direction)
+		case "sequence":
+			result = openAPISequence(s, title)
+		}
+	case "directory":
+		path, err := resolvePath(ctx, args, true)
+		if err != nil {
+			return nil, err
+		}
+		depth := defaultDepth
+		if value, ok := args["max_depth"].(float64); ok {
+			if value < 1 {
+				return nil, fmt.Errorf("max_depth must be at least 1, got: %v", value)
+			}
+			depth = min(int(value), maxDepth)
+		}
+		if result, err = directoryFlow(path, title, direction, depth); err != nil {
+			return nil, err
+		}
+		location = path
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Names and descriptions come from the input, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "mermaid_diagram",
+		ContentType: "diagram",
+	}
+	if location != "" {
+		sourceCtx.URL = "file://" + location
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// resolvePath returns the path parameter as an absolute path the security policy allows, which must
+// be a directory when dir is true and a file otherwise
+func resolvePath(ctx context.Context, args map[string]any, dir bool) (string, error) {
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("cannot access path: %w", err)
+	}
+	if dir && !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", resolved)
+	}
+	if !dir && info.IsDir() {
+		return "", fmt.Errorf("%s is a directory, not a spec file", resolved)
+	}
+	return resolved, nil
+}
+
+// readSpec reads a spec file, refusing very large ones
+func readSpec(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path: %w", err)
+	}
+	if info.Size() > maxSpecBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", path, maxSpecBytes/1024/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the mermaid_diagram tool
+func (t *MermaidDiagramTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Writing or updating documentation, design notes, READMEs or PR descriptions that need a diagram of an API's data model or endpoints, a request flow worked out from reading code or logs, or a project's layout. Saves hand-writing Mermaid syntax and keeps diagrams in step with the spec or code.",
+		WhenNotToUse: "Drawing package dependency graphs - use dep_graph, which writes Mermaid itself. Diagrams of other kinds (Gantt, state, ER) - write the Mermaid directly.",
+		CommonPatterns: []string{
+			"Trace a request through the code, list the calls in order, and draw them with source='calls' for a sequence diagram",
+			"Draw one area of a large API with source='openapi' and filter set to a tag or path prefix such as '/orders'",
+			"Draw a project's top two levels with source='directory' and max_depth=2 for a README",
+			"Put the returned mermaid text in a ```mermaid code block so Markdown viewers render it",
+		},
+		ParameterDetails: map[string]string{
+			"source":        "openapi reads components.schemas (definitions in Swagger 2) and paths. calls reads input. directory reads path, leaving out hidden entries and dependency or build directories such as node_modules, vendor and dist.",
+			"diagram":       "openapi: class (schemas with fields, references and allOf inheritance), flow (API, tags, endpoints) or sequence (each request and its responses). calls: sequence (calls between owners in order), flow (call graph with repeat counts) or class (owners with the methods called on them). directory: flow only.",
+			"input":         "Call list example: 'web.Handler.CreateOrder -> orders.Service.Create' then 'orders.Service.Create -> db.Store.Insert: INSERT orders' then 'db.Store.Insert --> orders.Service.Create: id'. In JSON, return adds a reply straight after a call.",
+			"filter":        "Without a filter, the class diagram draws every schema. With one, only schemas reachable from the matching operations are drawn.",
+			"split_members": "With the default, a sequence diagram has one participant per owner (orders.Service) and uses method names (Create()) as messages when a call has no label.",
+			"max_depth":     fmt.Sprintf("Each directory shows up to %d entries, directories first, with the rest counted.", maxEntriesPerDirectory),
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Draw a request flow as a sequence diagram",
+				Arguments:      map[string]any{"source": "calls", "input": "Browser -> API: POST /orders\nAPI -> Payments: charge\nPayments --> API: ok\nAPI ~> Queue: order.created\nAPI --> Browser: 201 Created"},
+				ExpectedResult: "A sequenceDiagram with Browser, API, Payments and Queue as participants",
+			},
+			{
+				Description:    "Draw the data model behind an API's order endpoints",
+				Arguments:      map[string]any{"source": "openapi", "path": "/home/user/project/openapi.yaml", "filter": "/orders"},
+				ExpectedResult: "A classDiagram of the schemas the /orders endpoints send and return, with their fields and relationships",
+			},
+			{
+				Description:    "Draw a project layout",
+				Arguments:      map[string]any{"source": "directory", "path": "/home/user/project", "max_depth": 2, "direction": "LR"},
+				ExpectedResult: "A flowchart of the top two levels of the project",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The diagram is truncated",
+				Solution: fmt.Sprintf("Diagrams are limited to %d nodes to stay readable. Use filter for specs, fewer calls, or a lower max_depth or a subdirectory.", maxNodes),
+			},
+			{
+				Problem:  "Participants in a sequence diagram are split at dots",
+				Solution: "Set split_members to false when names such as host names contain dots but are not functions.",
+			},
+		},
+	}
+}
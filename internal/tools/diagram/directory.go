@@ -0,0 +1,82 @@
+package diagram
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxEntriesPerDirectory limits the entries drawn for one directory, with the rest counted
+const maxEntriesPerDirectory = 15
+
+// skippedDirs are dependency, build output and tool directories left out of directory diagrams
+var skippedDirs = []string{"node_modules", "vendor", "dist", "build", "target", "__pycache__", "venv", "coverage"}
+
+// directoryFlow draws a directory tree to depth levels, with directories as boxes and files as
+// rounded nodes. Hidden entries and dependency or build directories are left out.
+func directoryFlow(root, title, direction string, depth int) (*Result, error) {
+	b := newBuilder(title, "flowchart "+direction, "n")
+	rootID, _ := b.id(root)
+	b.line("%s[\"%s/\"]", rootID, labelReplacer.Replace(filepath.Base(root)))
+	var unreadable []string
+	var walk func(dir, dirID string, level int) error
+	walk = func(dir, dirID string, level int) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		entries = slices.DeleteFunc(entries, func(e os.DirEntry) bool {
+			return strings.HasPrefix(e.Name(), ".") || (e.IsDir() && slices.Contains(skippedDirs, e.Name()))
+		})
+		// Directories first, so a long list of files is what gets cut short
+		slices.SortStableFunc(entries, func(a, b os.DirEntry) int {
+			switch {
+			case a.IsDir() == b.IsDir():
+				return 0
+			case a.IsDir():
+				return -1
+			default:
+				return 1
+			}
+		})
+		for i, entry := range entries {
+			if i == maxEntriesPerDirectory {
+				moreID, ok := b.id(filepath.Join(dir, "\x00more"))
+				if ok {
+					b.line("%s[/\"%d more\"/]", moreID, len(entries)-i)
+					b.line("%s --> %s", dirID, moreID)
+					b.edges++
+				}
+				break
+			}
+			path := filepath.Join(dir, entry.Name())
+			id, ok := b.id(path)
+			if !ok {
+				continue
+			}
+			if entry.IsDir() {
+				b.line("%s[\"%s/\"]", id, labelReplacer.Replace(entry.Name()))
+			} else {
+				b.line("%s(\"%s\")", id, labelReplacer.Replace(entry.Name()))
+			}
+			b.line("%s --> %s", dirID, id)
+			b.edges++
+			if entry.IsDir() && level < depth {
+				if err := walk(path, id, level+1); err != nil {
+					unreadable = append(unreadable, path)
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(root, rootID, 1); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	result := b.result("directory", "flow")
+	if len(unreadable) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("These directories could not be read: %s.", strings.Join(unreadable, ", ")))
+	}
+	return result, nil
+}
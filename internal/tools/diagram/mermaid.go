@@ -0,0 +1,77 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNodes limits the participants, classes or nodes drawn, beyond which diagrams stop being readable
+const maxNodes = 150
+
+// labelReplacer escapes text for a quoted Mermaid label
+var labelReplacer = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>", "\r", "")
+
+// messageReplacer escapes text for a sequence diagram message, where # starts an entity code and a
+// semicolon ends the statement
+var messageReplacer = strings.NewReplacer("#", "#35;", ";", "#59;", "\n", "<br/>", "\r", "")
+
+// memberReplacer removes characters that end or break a class member line
+var memberReplacer = strings.NewReplacer("{", "(", "}", ")", "\n", " ", "\r", "", `"`, "'")
+
+// builder writes a diagram, giving each distinct name a short identifier so names that are not
+// valid Mermaid identifiers can be used as labels
+type builder struct {
+	out       strings.Builder
+	ids       map[string]string
+	order     []string
+	prefix    string
+	edges     int
+	truncated bool
+}
+
+func newBuilder(title, header, prefix string) *builder {
+	b := &builder{ids: map[string]string{}, prefix: prefix}
+	if title != "" {
+		fmt.Fprintf(&b.out, "---\ntitle: %s\n---\n", labelReplacer.Replace(title))
+	}
+	b.out.WriteString(header + "\n")
+	return b
+}
+
+// id returns the identifier for name, and false when the diagram is full and name is new
+func (b *builder) id(name string) (string, bool) {
+	if id, ok := b.ids[name]; ok {
+		return id, true
+	}
+	if len(b.order) >= maxNodes {
+		b.truncated = true
+		return "", false
+	}
+	id := fmt.Sprintf("%s%d", b.prefix, len(b.order))
+	b.ids[name] = id
+	b.order = append(b.order, name)
+	return id, true
+}
+
+// line writes an indented statement
+func (b *builder) line(format string, args ...any) {
+	b.out.WriteString("  ")
+	fmt.Fprintf(&b.out, format, args...)
+	b.out.WriteString("\n")
+}
+
+// result returns the diagram and its counts
+func (b *builder) result(source, diagram string) *Result {
+	r := &Result{
+		Source:    source,
+		Diagram:   diagram,
+		Nodes:     len(b.order),
+		Edges:     b.edges,
+		Truncated: b.truncated,
+		Mermaid:   b.out.String(),
+	}
+	if b.truncated {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("The diagram was limited to %d nodes. Narrow the input to draw the rest.", maxNodes))
+	}
+	return r
+}
@@ -0,0 +1,337 @@
+package diagram
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the operations of a path item, in the order they are drawn
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// spec is the part of an OpenAPI 3.x or Swagger 2.0 document that is drawn
+type spec struct {
+	title      string
+	schemas    map[string]map[string]any
+	operations []operation
+}
+
+// operation is an endpoint with the schemas it sends and returns
+type operation struct {
+	method    string
+	path      string
+	summary   string
+	tags      []string
+	request   string
+	responses []response
+}
+
+type response struct {
+	status string
+	schema string
+}
+
+// parseSpec reads a JSON or YAML spec, keeping the operations whose path starts with filter or that
+// are tagged with it
+func parseSpec(data []byte, filter string) (*spec, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse the spec as JSON or YAML: %w", err)
+	}
+	s := &spec{schemas: map[string]map[string]any{}}
+	schemas := asMap(asMap(root["components"])["schemas"])
+	if root["swagger"] != nil {
+		schemas = asMap(root["definitions"])
+	}
+	for name, schema := range schemas {
+		s.schemas[name] = asMap(schema)
+	}
+	paths := asMap(root["paths"])
+	if len(paths) == 0 && len(schemas) == 0 {
+		return nil, fmt.Errorf("the spec has no paths or schemas to draw")
+	}
+	s.title = asString(asMap(root["info"])["title"])
+
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item := asMap(paths[path])
+		for _, method := range httpMethods {
+			op := asMap(item[method])
+			if op == nil {
+				continue
+			}
+			o := operation{method: strings.ToUpper(method), path: path, summary: asString(op["summary"])}
+			for _, tag := range asSlice(op["tags"]) {
+				o.tags = append(o.tags, asString(tag))
+			}
+			if filter != "" && !strings.HasPrefix(path, filter) && !slices.Contains(o.tags, filter) {
+				continue
+			}
+			o.request = contentSchema(asMap(op["requestBody"]))
+			for _, param := range asSlice(op["parameters"]) {
+				if p := asMap(param); asString(p["in"]) == "body" {
+					o.request = schemaName(asMap(p["schema"]))
+				}
+			}
+			responses := asMap(op["responses"])
+			for _, status := range slices.Sorted(maps.Keys(responses)) {
+				r := asMap(responses[status])
+				name := contentSchema(r)
+				if name == "" {
+					name = schemaName(asMap(r["schema"]))
+				}
+				o.responses = append(o.responses, response{status: status, schema: name})
+			}
+			s.operations = append(s.operations, o)
+		}
+	}
+	if filter != "" && len(s.operations) == 0 {
+		return nil, fmt.Errorf("no operations have a path starting with or a tag of %q", filter)
+	}
+	return s, nil
+}
+
+// contentSchema returns the schema named by an OpenAPI 3 request body or response, preferring JSON
+func contentSchema(body map[string]any) string {
+	content := asMap(body["content"])
+	if media := asMap(content["application/json"]); media != nil {
+		return schemaName(asMap(media["schema"]))
+	}
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		if name := schemaName(asMap(asMap(content[mediaType])["schema"])); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// schemaName names a schema reference, with [] for an array of one
+func schemaName(schema map[string]any) string {
+	if ref := asString(schema["$ref"]); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	if asString(schema["type"]) == "array" {
+		if name := schemaName(asMap(schema["items"])); name != "" {
+			return name + "[]"
+		}
+	}
+	return ""
+}
+
+// used returns the schemas the operations send and return, and those they refer to in turn
+func (s *spec) used() map[string]bool {
+	found := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		name = strings.TrimSuffix(name, "[]")
+		if name == "" || found[name] || s.schemas[name] == nil {
+			return
+		}
+		found[name] = true
+		for _, rel := range relations(s.schemas[name]) {
+			visit(rel.target)
+		}
+	}
+	for _, o := range s.operations {
+		visit(o.request)
+		for _, r := range o.responses {
+			visit(r.schema)
+		}
+	}
+	return found
+}
+
+// relation is a reference from one schema to another
+type relation struct {
+	target   string
+	field    string
+	many     bool
+	inherits bool
+}
+
+// relations lists a schema's references through its properties and allOf
+func relations(schema map[string]any) []relation {
+	var found []relation
+	for _, part := range asSlice(schema["allOf"]) {
+		if name := schemaName(asMap(part)); name != "" {
+			found = append(found, relation{target: name, inherits: true})
+		} else {
+			found = append(found, relations(asMap(part))...)
+		}
+	}
+	properties := asMap(schema["properties"])
+	for _, field := range slices.Sorted(maps.Keys(properties)) {
+		if name := schemaName(asMap(properties[field])); name != "" {
+			found = append(found, relation{target: strings.TrimSuffix(name, "[]"), field: field, many: strings.HasSuffix(name, "[]")})
+		}
+	}
+	return found
+}
+
+// propertyLines describes a schema's fields as class members, including those from allOf parts that
+// are not references
+func propertyLines(schema map[string]any) []string {
+	var lines []string
+	for _, part := range asSlice(schema["allOf"]) {
+		if schemaName(asMap(part)) == "" {
+			lines = append(lines, propertyLines(asMap(part))...)
+		}
+	}
+	properties := asMap(schema["properties"])
+	for _, field := range slices.Sorted(maps.Keys(properties)) {
+		property := asMap(properties[field])
+		kind := cmp.Or(schemaName(property), propertyType(property))
+		lines = append(lines, "+"+kind+" "+field)
+	}
+	for _, value := range asSlice(schema["enum"]) {
+		lines = append(lines, fmt.Sprint(value))
+	}
+	return lines
+}
+
+// propertyType returns a property's type, or its format when it has one, with [] for arrays
+func propertyType(property map[string]any) string {
+	kind := asString(property["type"])
+	if kind == "array" {
+		return cmp.Or(schemaName(asMap(property["items"])), propertyType(asMap(property["items"]))) + "[]"
+	}
+	if kind == "" {
+		return "any"
+	}
+	if format := asString(property["format"]); format != "" {
+		return format
+	}
+	return kind
+}
+
+// openAPIClass draws the schemas used by the operations as classes, with their fields, references
+// and allOf inheritance
+func openAPIClass(s *spec, title, direction string, filtered bool) *Result {
+	b := newBuilder(title, "classDiagram\n  direction "+direction, "c")
+	names := slices.Sorted(maps.Keys(s.schemas))
+	if filtered {
+		used := s.used()
+		names = slices.DeleteFunc(names, func(name string) bool { return !used[name] })
+	}
+	type link struct {
+		from string
+		relation
+	}
+	var links []link
+	for _, name := range names {
+		id, ok := b.id(name)
+		if !ok {
+			continue
+		}
+		b.line("class %s[\"%s\"]", id, labelReplacer.Replace(name))
+		if s.schemas[name]["enum"] != nil {
+			b.line("<<enumeration>> %s", id)
+		}
+		for _, member := range propertyLines(s.schemas[name]) {
+			b.line("%s : %s", id, memberReplacer.Replace(member))
+		}
+		for _, rel := range relations(s.schemas[name]) {
+			links = append(links, link{from: name, relation: rel})
+		}
+	}
+	for _, l := range links {
+		from, ok := b.ids[l.from]
+		to, found := b.ids[l.target]
+		if !ok || !found {
+			continue
+		}
+		switch {
+		case l.inherits:
+			b.line("%s <|-- %s", to, from)
+		case l.many:
+			b.line("%s --> \"*\" %s : %s", from, to, l.field)
+		default:
+			b.line("%s --> %s : %s", from, to, l.field)
+		}
+		b.edges++
+	}
+	return b.result("openapi", "class")
+}
+
+// openAPIFlow draws the API, its tags and each tag's operations
+func openAPIFlow(s *spec, title, direction string) *Result {
+	b := newBuilder(title, "flowchart "+direction, "n")
+	root, _ := b.id("\x00api")
+	b.line("%s[\"%s\"]", root, labelReplacer.Replace(cmp.Or(s.title, "API")))
+	for _, o := range s.operations {
+		tags := o.tags
+		if len(tags) == 0 {
+			tags = []string{"default"}
+		}
+		label := o.method + " " + o.path
+		opID, ok := b.id(label)
+		if !ok {
+			continue
+		}
+		if o.summary != "" {
+			label += "\n" + o.summary
+		}
+		b.line("%s(\"%s\")", opID, labelReplacer.Replace(label))
+		for _, tag := range tags {
+			tagID, seen := b.ids["\x00tag:"+tag]
+			if !seen {
+				if tagID, ok = b.id("\x00tag:" + tag); !ok {
+					continue
+				}
+				b.line("%s{{\"%s\"}}", tagID, labelReplacer.Replace(tag))
+				b.line("%s --> %s", root, tagID)
+				b.edges++
+			}
+			b.line("%s --> %s", tagID, opID)
+			b.edges++
+		}
+	}
+	return b.result("openapi", "flow")
+}
+
+// openAPISequence draws each operation as a request from a client and the API's responses
+func openAPISequence(s *spec, title string) *Result {
+	b := newBuilder(title, "sequenceDiagram", "p")
+	client, _ := b.id("Client")
+	api, _ := b.id("API")
+	b.line("participant %s as Client", client)
+	b.line("participant %s as %s", api, messageReplacer.Replace(cmp.Or(s.title, "API")))
+	for i, o := range s.operations {
+		if i >= maxNodes {
+			b.truncated = true
+			break
+		}
+		request := o.method + " " + o.path
+		if o.request != "" {
+			request += " (" + o.request + ")"
+		}
+		b.line("%s->>%s: %s", client, api, messageReplacer.Replace(request))
+		b.edges++
+		var replies []string
+		for _, r := range o.responses {
+			replies = append(replies, strings.TrimSpace(r.status+" "+r.schema))
+		}
+		if len(replies) > 0 {
+			b.line("%s-->>%s: %s", api, client, messageReplacer.Replace(strings.Join(replies, ", ")))
+		}
+	}
+	return b.result("openapi", "sequence")
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,28 @@
+package diagram
+
+// Result is a generated Mermaid diagram
+type Result struct {
+	// Source is the kind of input the diagram was drawn from: openapi, calls or directory
+	Source string `json:"source"`
+	// Diagram is the kind of diagram: sequence, class or flow
+	Diagram   string   `json:"diagram"`
+	Nodes     int      `json:"nodes"`
+	Edges     int      `json:"edges"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Mermaid   string   `json:"mermaid"`
+}
+
+// Call is one call in a call list, from a caller to a callee
+type Call struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+	// Return is what the callee returns, drawn as a reply in sequence diagrams
+	Return string `json:"return,omitempty"`
+	// Async marks a call the caller does not wait for
+	Async bool `json:"async,omitempty"`
+	// Reply marks a response to an earlier call rather than a call, drawn dashed in sequence
+	// diagrams and left out of flow and class diagrams
+	Reply bool `json:"reply,omitempty"`
+}
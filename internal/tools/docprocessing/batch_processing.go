@@ -1,6 +1,7 @@
 package docprocessing
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"strings"
@@ -9,10 +10,11 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 )
 
 // executeBatch processes multiple documents concurrently
-func (t *DocumentProcessorTool) executeBatch(args map[string]any, sources []any) (*mcp.CallToolResult, error) {
+func (t *DocumentProcessorTool) executeBatch(ctx context.Context, args map[string]any, sources []any) (*mcp.CallToolResult, error) {
 	startTime := time.Now()
 
 	// Convert sources to strings
@@ -64,6 +66,17 @@ func (t *DocumentProcessorTool) executeBatch(args map[string]any, sources []any)
 					continue
 				}
 
+				// Check the caller may read local sources, as for a single document
+				if !strings.HasPrefix(req.Source, "http://") && !strings.HasPrefix(req.Source, "https://") {
+					if err := workspace.CheckFileAccess(ctx, req.Source); err != nil {
+						resultChan <- &DocumentProcessingResponse{
+							Source: source,
+							Error:  err.Error(),
+						}
+						continue
+					}
+				}
+
 				// Process document
 				response, err := t.processDocument(req)
 				if err != nil {
@@ -5,13 +5,22 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
 	"github.com/sammcj/mcp-devtools/internal/security"
 )
 
+// Actions for the tool's cache argument
+const (
+	CacheActionStats = "stats" // Report the cache's size
+	CacheActionClean = "clean" // Remove expired entries
+	CacheActionClear = "clear" // Remove every entry
+)
+
 // CacheManager handles caching of document processing results
 type CacheManager struct {
 	config *Config
@@ -24,35 +33,60 @@ func NewCacheManager(config *Config) *CacheManager {
 	}
 }
 
-// GenerateCacheKey generates a cache key for the given request
+// GenerateCacheKey generates a cache key for the given request. Local files are identified by a hash
+// of their content rather than their path, so an edited file is converted again and a copy or renamed
+// file reuses the earlier result.
 func (cm *CacheManager) GenerateCacheKey(req *DocumentProcessingRequest) string {
 	// Create a hash based on the request parameters that affect the output
 	keyData := struct {
-		Source               string               `json:"source"`
-		ProcessingMode       ProcessingMode       `json:"processing_mode"`
-		EnableOCR            bool                 `json:"enable_ocr"`
-		OCRLanguages         []string             `json:"ocr_languages"`
-		PreserveImages       bool                 `json:"preserve_images"`
-		OutputFormat         OutputFormat         `json:"output_format"`
-		TableFormerMode      TableFormerMode      `json:"table_former_mode"`
-		CellMatching         *bool                `json:"cell_matching"`
-		VisionMode           VisionProcessingMode `json:"vision_mode"`
-		DiagramDescription   bool                 `json:"diagram_description"`
-		ChartDataExtraction  bool                 `json:"chart_data_extraction"`
-		EnableRemoteServices bool                 `json:"enable_remote_services"`
+		Source                   string               `json:"source,omitempty"`
+		ContentHash              string               `json:"content_hash,omitempty"`
+		Converter                string               `json:"converter"`
+		ProcessingMode           ProcessingMode       `json:"processing_mode"`
+		EnableOCR                bool                 `json:"enable_ocr"`
+		OCRLanguages             []string             `json:"ocr_languages"`
+		PreserveImages           bool                 `json:"preserve_images"`
+		OutputFormat             OutputFormat         `json:"output_format"`
+		TableFormerMode          TableFormerMode      `json:"table_former_mode"`
+		CellMatching             *bool                `json:"cell_matching"`
+		TableFormat              TableFormat          `json:"table_format"`
+		ImageLinks               string               `json:"image_links,omitempty"`
+		DescribeImages           bool                 `json:"describe_images,omitempty"`
+		VisionMode               VisionProcessingMode `json:"vision_mode"`
+		DiagramDescription       bool                 `json:"diagram_description"`
+		ChartDataExtraction      bool                 `json:"chart_data_extraction"`
+		EnableRemoteServices     bool                 `json:"enable_remote_services"`
+		ConvertDiagramsToMermaid bool                 `json:"convert_diagrams_to_mermaid"`
+		GenerateDiagrams         bool                 `json:"generate_diagrams"`
+		ExtractImages            bool                 `json:"extract_images"`
 	}{
-		Source:               req.Source,
-		ProcessingMode:       req.ProcessingMode,
-		EnableOCR:            req.EnableOCR,
-		OCRLanguages:         req.OCRLanguages,
-		PreserveImages:       req.PreserveImages,
-		OutputFormat:         req.OutputFormat,
-		TableFormerMode:      req.TableFormerMode,
-		CellMatching:         req.CellMatching,
-		VisionMode:           req.VisionMode,
-		DiagramDescription:   req.DiagramDescription,
-		ChartDataExtraction:  req.ChartDataExtraction,
-		EnableRemoteServices: req.EnableRemoteServices,
+		Converter:                cm.converter(),
+		ProcessingMode:           req.ProcessingMode,
+		EnableOCR:                req.EnableOCR,
+		OCRLanguages:             req.OCRLanguages,
+		PreserveImages:           req.PreserveImages,
+		OutputFormat:             req.OutputFormat,
+		TableFormerMode:          req.TableFormerMode,
+		CellMatching:             req.CellMatching,
+		TableFormat:              req.TableFormat,
+		DescribeImages:           req.DescribeImages,
+		VisionMode:               req.VisionMode,
+		DiagramDescription:       req.DiagramDescription,
+		ChartDataExtraction:      req.ChartDataExtraction,
+		EnableRemoteServices:     req.EnableRemoteServices,
+		ConvertDiagramsToMermaid: req.ConvertDiagramsToMermaid,
+		GenerateDiagrams:         req.GenerateDiagrams,
+		ExtractImages:            req.ExtractImages,
+	}
+	// URLs, and files that cannot be read, are identified by their source
+	if hash, err := contentHash(req.Source); err == nil {
+		keyData.ContentHash = hash
+	} else {
+		keyData.Source = req.Source
+	}
+	// Figure links are written relative to the saved file, e.g. report_images/figure-1.png, or absolute
+	if req.ImageDir != "" {
+		keyData.ImageLinks = imageLink(req.ImageDir, req.imageLinkDir)
 	}
 
 	// Convert to JSON and hash
@@ -61,6 +95,38 @@ func (cm *CacheManager) GenerateCacheKey(req *DocumentProcessingRequest) string
 	return hex.EncodeToString(hash[:])
 }
 
+// converter names what converts documents, as each gives different results for the same document
+func (cm *CacheManager) converter() string {
+	switch {
+	case cm.config.ServeURL != "":
+		return "docling-serve " + cm.config.ServeURL
+	case cm.config.Native:
+		return ProcessingMethodNative
+	default:
+		return "docling"
+	}
+}
+
+// contentHash returns the SHA-256 of a local file's content
+func contentHash(source string) (string, error) {
+	if !filepath.IsAbs(source) {
+		return "", fmt.Errorf("not a local file: %s", source)
+	}
+	if err := security.CheckFileAccess(source); err != nil {
+		return "", err
+	}
+	file, err := os.Open(source)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // GetCacheFilePath returns the file path for a cache key
 func (cm *CacheManager) GetCacheFilePath(cacheKey string) string {
 	return filepath.Join(cm.config.CacheDir, cacheKey+".json")
@@ -386,10 +452,9 @@ func (cm *CacheManager) isCacheExpired(cached *CachedResponse) bool {
 	return time.Now().After(expirationTime)
 }
 
-// getDefaultTTL returns the default time-to-live for cache entries
+// getDefaultTTL returns the default time-to-live for cache entries, which are kept as long as artifacts
 func (cm *CacheManager) getDefaultTTL() time.Duration {
-	// Default to 24 hours
-	return 24 * time.Hour
+	return artifacts.TTL()
 }
 
 // validateReferencedFiles checks if all files referenced in the cached response still exist
@@ -0,0 +1,170 @@
+package docprocessing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+)
+
+// chunkIndexName is the file listing a chunked document's sections
+const chunkIndexName = "index.md"
+
+// maxSlugLength limits the part of a chunk's file name taken from its heading
+const maxSlugLength = 50
+
+var (
+	// headingPattern matches an ATX markdown heading, capturing its level and text
+	headingPattern = regexp.MustCompile(`^(#{1,6})[ \t]+(.+?)(?:[ \t]+#+)?[ \t]*$`)
+	// fencePattern matches the start or end of a fenced code block, whose lines are not headings
+	fencePattern = regexp.MustCompile("^[ ]{0,3}(```|~~~)")
+	// chunkFilePattern matches the chunk files written for a document, so earlier chunks can be removed
+	chunkFilePattern = regexp.MustCompile(`^[0-9]{3,}-[a-z0-9-]+\.md$`)
+	// slugPattern matches the runs of characters replaced in chunk file names
+	slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// DocumentChunk is one section of a converted document, saved as its own file
+type DocumentChunk struct {
+	Title  string `json:"title"`
+	Level  int    `json:"level"`  // Heading level, or 0 for text before the first heading
+	File   string `json:"file"`   // Path of the chunk's file
+	Tokens int    `json:"tokens"` // Estimated tokens in the chunk
+}
+
+// ChunkIndex describes a converted document saved as one file per section
+type ChunkIndex struct {
+	Directory   string          `json:"directory"`
+	Index       string          `json:"index"` // Path of the index file listing the chunks
+	TotalTokens int             `json:"total_tokens"`
+	Chunks      []DocumentChunk `json:"chunks"`
+}
+
+// markdownSection is a heading and the lines that follow it, up to the next heading that starts a chunk
+type markdownSection struct {
+	title string
+	level int
+	body  string
+}
+
+// splitMarkdown splits markdown at each heading of level or above, e.g. at # and ## for level 2.
+// Deeper headings stay within their section, and text before the first heading is its own section.
+func splitMarkdown(content string, level int) []markdownSection {
+	var sections []markdownSection
+	current := markdownSection{title: "Introduction"}
+	var body strings.Builder
+	flush := func() {
+		current.body = strings.TrimSpace(body.String())
+		if current.body != "" {
+			sections = append(sections, current)
+		}
+		body.Reset()
+	}
+
+	inFence := false
+	for line := range strings.Lines(content) {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if fencePattern.MatchString(trimmed) {
+			inFence = !inFence
+		}
+		if !inFence {
+			if match := headingPattern.FindStringSubmatch(trimmed); match != nil && len(match[1]) <= level {
+				flush()
+				current = markdownSection{title: strings.TrimSpace(match[2]), level: len(match[1])}
+			}
+		}
+		body.WriteString(line)
+	}
+	flush()
+	return sections
+}
+
+// chunkSlug reduces a heading to a short file name
+func chunkSlug(title string) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return "section"
+	}
+	return slug
+}
+
+// chunkDir returns the directory a saved document's chunks are written to, beside the saved file
+func chunkDir(savePath string) string {
+	return strings.TrimSuffix(savePath, filepath.Ext(savePath)) + "_chunks"
+}
+
+// writeChunks saves a converted document as one file per section at level or above, with an index
+// listing each section's title, file and estimated tokens. Chunks from an earlier conversion of the
+// document are replaced.
+func writeChunks(savePath, source, content string, level int) (*ChunkIndex, error) {
+	dir := chunkDir(savePath)
+	if err := security.CheckFileAccess(dir); err != nil {
+		return nil, fmt.Errorf("chunk directory access denied: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create chunk directory %s: %w", dir, err)
+	}
+
+	// Only files written as chunks are removed, in case the directory holds anything else
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && (chunkFilePattern.MatchString(entry.Name()) || entry.Name() == chunkIndexName) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	sections := splitMarkdown(content, level)
+	width := max(3, len(strconv.Itoa(len(sections))))
+	index := &ChunkIndex{Directory: dir, Index: filepath.Join(dir, chunkIndexName), Chunks: []DocumentChunk{}}
+	for i, section := range sections {
+		name := fmt.Sprintf("%0*d-%s.md", width, i+1, chunkSlug(section.title))
+		chunk := DocumentChunk{
+			Title:  section.title,
+			Level:  section.level,
+			File:   filepath.Join(dir, name),
+			Tokens: tools.EstimateAgentTokens(section.body),
+		}
+		if err := os.WriteFile(chunk.File, []byte(section.body+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write chunk %s: %w", chunk.File, err)
+		}
+		index.Chunks = append(index.Chunks, chunk)
+		index.TotalTokens += chunk.Tokens
+	}
+
+	if err := os.WriteFile(index.Index, []byte(chunkIndexMarkdown(source, index)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write chunk index %s: %w", index.Index, err)
+	}
+	return index, nil
+}
+
+// chunkIndexMarkdown lists a document's chunks as nested links, so an agent can read the index and
+// load only the sections it needs
+func chunkIndexMarkdown(source string, index *ChunkIndex) string {
+	topLevel := 6
+	for _, chunk := range index.Chunks {
+		if chunk.Level > 0 {
+			topLevel = min(topLevel, chunk.Level)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", filepath.Base(source))
+	fmt.Fprintf(&sb, "Source: %s\n\n", source)
+	fmt.Fprintf(&sb, "%d sections, about %d tokens in total.\n\n", len(index.Chunks), index.TotalTokens)
+	for _, chunk := range index.Chunks {
+		indent := strings.Repeat("  ", max(0, chunk.Level-topLevel))
+		fmt.Fprintf(&sb, "%s- [%s](%s) (about %d tokens)\n", indent, chunk.Title, filepath.Base(chunk.File), chunk.Tokens)
+	}
+	return sb.String()
+}
@@ -10,8 +10,10 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
 	"github.com/sammcj/mcp-devtools/internal/config"
 	"github.com/sammcj/mcp-devtools/internal/security"
 )
@@ -76,15 +78,28 @@ type Config struct {
 
 	// Certificate Configuration
 	ExtraCACerts string // Path to additional CA certificates file or directory
+
+	// Remote Conversion Configuration
+	ServeURL    string // docling-serve base URL, which replaces local Python when set
+	ServeAPIKey string // docling-serve API key
+
+	// Native converts documents with the built-in converter, as docling is unavailable
+	Native bool
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
-	homeDir, _ := os.UserHomeDir()
-	defaultCacheDir := filepath.Join(homeDir, ".mcp-devtools", "docling-cache")
+	// Converted documents are cached in the artifact store, shared by every session
+	defaultCacheDir, _ := artifacts.CacheDir("process_document")
+
+	// Documents are converted remotely when docling-serve is configured, so local Python is not needed
+	pythonPath := ""
+	if os.Getenv(EnvServeURL) == "" {
+		pythonPath = detectPythonPath()
+	}
 
 	return &Config{
-		PythonPath:           detectPythonPath(),
+		PythonPath:           pythonPath,
 		CacheDir:             defaultCacheDir,
 		CacheEnabled:         true,
 		HardwareAcceleration: HardwareAccelerationAuto,
@@ -117,17 +132,8 @@ func LoadConfig() *Config {
 	}
 
 	// Hardware Configuration
-	if hwAccel := os.Getenv("DOCLING_HARDWARE_ACCELERATION"); hwAccel != "" {
-		switch strings.ToLower(hwAccel) {
-		case "auto":
-			config.HardwareAcceleration = HardwareAccelerationAuto
-		case "mps":
-			config.HardwareAcceleration = HardwareAccelerationMPS
-		case "cuda":
-			config.HardwareAcceleration = HardwareAccelerationCUDA
-		case "cpu":
-			config.HardwareAcceleration = HardwareAccelerationCPU
-		}
+	if hwAccel, ok := ParseHardwareAcceleration(os.Getenv("DOCLING_HARDWARE_ACCELERATION")); ok {
+		config.HardwareAcceleration = hwAccel
 	}
 
 	// Processing Configuration
@@ -168,13 +174,17 @@ func LoadConfig() *Config {
 		config.ExtraCACerts = extraCACerts
 	}
 
+	// Remote Conversion Configuration
+	config.ServeURL = strings.TrimSpace(os.Getenv(EnvServeURL))
+	config.ServeAPIKey = os.Getenv(EnvServeAPIKey)
+
 	return config
 }
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	// Validate Python path
-	if c.PythonPath == "" {
+	// Validate Python path, which is not used when converting with docling-serve
+	if c.PythonPath == "" && c.ServeURL == "" && !c.Native {
 		return fmt.Errorf("docling package not found! `Run pip install -U docling` in the Python environment your MCP client is using. Once installed you can optionally run docling-tools models download to automatically download the advanced vision models")
 	}
 
@@ -233,10 +243,8 @@ func (c *Config) EnsureCacheDir() error {
 func (c *Config) CleanupTemporaryFiles() error {
 	var errors []string
 
-	// Clean up embedded scripts temporary directory
-	if err := CleanupEmbeddedScripts(); err != nil {
-		errors = append(errors, fmt.Sprintf("embedded scripts cleanup failed: %v", err))
-	}
+	// The extracted embedded scripts are left in place, as they are extracted once per process and
+	// conversions running alongside this cleanup are using them
 
 	// Clean up old cache files (older than configured max age)
 	if c.CacheEnabled && c.CacheDir != "" {
@@ -895,8 +903,23 @@ func (c *Config) getDoclingVersion() string {
 	return strings.TrimSpace(output)
 }
 
+// ParseHardwareAcceleration parses a hardware acceleration mode, ignoring case
+func ParseHardwareAcceleration(value string) (HardwareAcceleration, bool) {
+	mode := HardwareAcceleration(strings.ToLower(strings.TrimSpace(value)))
+	if !slices.Contains(HardwareAccelerationModes, mode) {
+		return "", false
+	}
+	return mode, true
+}
+
+// detectedAcceleration remembers the acceleration found for each Python, as importing PyTorch is slow
+var detectedAcceleration sync.Map
+
 // detectAvailableAcceleration detects available hardware acceleration options
 func (c *Config) detectAvailableAcceleration() []HardwareAcceleration {
+	if cached, ok := detectedAcceleration.Load(c.PythonPath); ok {
+		return cached.([]HardwareAcceleration)
+	}
 	available := []HardwareAcceleration{HardwareAccelerationCPU} // CPU is always available
 
 	// Check for MPS (macOS Metal Performance Shaders)
@@ -911,6 +934,7 @@ func (c *Config) detectAvailableAcceleration() []HardwareAcceleration {
 		available = append(available, HardwareAccelerationCUDA)
 	}
 
+	detectedAcceleration.Store(c.PythonPath, available)
 	return available
 }
 
@@ -120,6 +120,7 @@ func (t *DocumentProcessorTool) getDebugInfo() map[string]any {
 		"timeout":           t.config.Timeout,
 		"max_file_size":     t.config.MaxFileSize,
 		"docling_available": t.config.isDoclingAvailable(),
+		"docling_serve_url": t.config.ServeURL,
 	}
 
 	debugInfo["configuration"] = configInfo
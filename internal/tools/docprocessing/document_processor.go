@@ -13,6 +13,8 @@ import (
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workpool"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,25 +22,61 @@ import (
 type DocumentProcessorTool struct {
 	config       *Config
 	cacheManager *CacheManager
+
+	// Finding Python and importing docling takes a second or more, so it is done on first use
+	// rather than when the server starts
+	loadOnce sync.Once
+	loadErr  error
+
+	// nativeReason says why docling is unavailable when documents are converted with the built-in
+	// converter instead
+	nativeReason string
 }
 
-// init registers the document processor tool only if docling is available
+// init registers the document processor tool. Whether docling is available is only checked when the
+// tool is first used, or by commands that report tool status.
 func init() {
-	config := LoadConfig()
+	tool := &DocumentProcessorTool{}
+	registry.Register(tool)
+	registry.RegisterAvailabilityCheck("process_document", func() string {
+		if err := tool.load(); err != nil {
+			return err.Error()
+		}
+		return ""
+	})
+}
 
-	// Only register the tool if docling is available
-	if config.PythonPath != "" && config.isDoclingAvailable() {
-		// Rotate debug logs on startup (keeps logs from past 48 hours)
+// load finds Python with docling, or checks the docling-serve URL, and sets up the cache, once
+func (t *DocumentProcessorTool) load() error {
+	t.loadOnce.Do(func() {
+		config := LoadConfig()
+		if config.ServeURL != "" {
+			serve, err := serveURL(config.ServeURL)
+			if err != nil {
+				t.loadErr = err
+				return
+			}
+			config.ServeURL = serve
+		} else if config.PythonPath == "" {
+			t.loadErr = fmt.Errorf("python not found, install Python with docling or set DOCLING_PYTHON_PATH")
+		} else if !config.isDoclingAvailable() {
+			t.loadErr = fmt.Errorf("docling is not installed for %s, run: pip install docling", config.PythonPath)
+		}
+		if t.loadErr != nil {
+			if !nativeFallbackEnabled() {
+				return
+			}
+			// Simple documents can still be converted without docling
+			t.nativeReason = t.loadErr.Error()
+			t.loadErr = nil
+			config.Native = true
+		}
+		// Rotate debug logs on first use (keeps logs from past 48 hours)
 		rotateDebugLogs()
-
-		cacheManager := NewCacheManager(config)
-		registry.Register(&DocumentProcessorTool{
-			config:       config,
-			cacheManager: cacheManager,
-		})
-	}
-	// Note: We don't log here as this runs during init and could interfere with MCP protocol
-	// The tool will simply not be available if docling is not found
+		t.config = config
+		t.cacheManager = NewCacheManager(config)
+	})
+	return t.loadErr
 }
 
 // Definition returns the MCP tool definition
@@ -70,15 +108,44 @@ func (t *DocumentProcessorTool) Definition() mcp.Tool {
 		mcp.WithString("save_to",
 			mcp.Description("Override the file path for saved content (default: same directory as source file). MUST be a fully qualified absolute path"),
 		),
+		mcp.WithNumber("chunk_level",
+			mcp.Description("Also save the document split into one file per section at this heading level or above (1 = # headings only, 2 = # and ##), with an index.md listing each section and its estimated tokens. Read the index, then load only the sections needed"),
+			mcp.Min(1),
+			mcp.Max(6),
+		),
+		mcp.WithString("image_dir",
+			mcp.Description("Absolute directory to write the document's figures to, linked from the markdown (default: a <name>_images directory beside the saved file). With return_inline_only, figures are only written when this is set, and linked by absolute path"),
+		),
+		mcp.WithBoolean("describe_images",
+			mcp.Description("Write alt text for figures that only have a generic label such as 'Picture 3', using the vision model configured by DOCLING_VLM_API_URL, DOCLING_VLM_MODEL and DOCLING_VLM_API_KEY"),
+		),
+		mcp.WithString("table_format",
+			mcp.Description("Extract tables, each with a confidence score from 0 to 1: 'markdown' (GitHub Flavoured Markdown), 'html' (HTML tables in the content, keeping merged cells) or 'csv' (each table also saved as a CSV file beside the saved document). Use html or csv for financial statements and other complex tables"),
+			mcp.Enum(string(TableFormatMarkdown), string(TableFormatHTML), string(TableFormatCSV)),
+		),
 		mcp.WithNumber("timeout",
 			mcp.Description("Processing timeout in seconds (overrides default)"),
 		),
 		mcp.WithBoolean("clear_file_cache",
 			mcp.Description("Force clear all cache entries the source file before processing"),
 		),
+		mcp.WithBoolean("force",
+			mcp.Description("Convert again even if this content was already converted with the same options, replacing the cached result"),
+		),
+		mcp.WithString("cache",
+			mcp.Description("Manage the cache of converted documents instead of converting one: 'stats' reports its size, 'clean' removes expired entries and 'clear' removes every entry"),
+			mcp.Enum(CacheActionStats, CacheActionClean, CacheActionClear),
+		),
 		mcp.WithBoolean("debug",
 			mcp.Description("Return debug information including environment variables (secrets masked)"),
 		),
+		mcp.WithString("hardware_acceleration",
+			mcp.Description("Device to convert with: auto (default, the best available), mps (Apple silicon), cuda (NVIDIA) or cpu. A device that is not available falls back to the best one that is, and the result reports which was used"),
+			mcp.Enum("auto", "mps", "cuda", "cpu"),
+		),
+		mcp.WithString("python_path",
+			mcp.Description("Absolute path of a Python interpreter with docling to convert with, such as /path/to/project/.venv/bin/python. Only needed when the one found automatically from the document's project is wrong"),
+		),
 
 		// Non-destructive writing annotations
 		mcp.WithReadOnlyHintAnnotation(false),    // Converts documents to new formats
@@ -92,6 +159,9 @@ func (t *DocumentProcessorTool) Definition() mcp.Tool {
 // Execute processes the document using the Python wrapper
 func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
 	// Note: No logging to stdout/stderr in stdio mode to avoid breaking MCP protocol
+	if err := t.load(); err != nil {
+		return nil, err
+	}
 
 	// Perform cache maintenance and temporary file cleanup in background
 	go func() {
@@ -109,9 +179,14 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 		_ = t.config.CleanupTemporaryFiles()
 	}()
 
+	// Cache management converts no documents
+	if action, ok := args["cache"].(string); ok && action != "" {
+		return t.manageCache(action)
+	}
+
 	// Check for batch processing (sources array)
 	if sources, ok := args["sources"].([]any); ok && len(sources) > 0 {
-		return t.executeBatch(args, sources)
+		return t.executeBatch(ctx, args, sources)
 	}
 
 	// Parse and validate arguments for single document
@@ -123,7 +198,7 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 	// Security: Check file access control for local file paths
 	if !strings.HasPrefix(req.Source, "http://") && !strings.HasPrefix(req.Source, "https://") {
 		// Check security system file access control first
-		if err := security.CheckFileAccess(req.Source); err != nil {
+		if err := workspace.CheckFileAccess(ctx, req.Source); err != nil {
 			return nil, err
 		}
 
@@ -142,6 +217,16 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 		}
 		// Note: If file doesn't exist, let the processing handle it (it might be a URL or other valid source)
 	}
+	if req.SaveTo != "" {
+		if err := workspace.CheckFileAccess(ctx, req.SaveTo); err != nil {
+			return nil, err
+		}
+	}
+	if req.ImageDir != "" {
+		if err := workspace.CheckFileAccess(ctx, req.ImageDir); err != nil {
+			return nil, err
+		}
+	}
 
 	// Handle debug mode - return debug information without processing
 	if req.Debug {
@@ -164,20 +249,36 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 		_ = t.cacheManager.ClearFileCache(req.Source)
 	}
 
+	// Decide where figures are written first, as their links are part of the cached content
+	if err := t.resolveImageOutput(req); err != nil {
+		return nil, err
+	}
+
 	// Check cache first
 	cacheEnabled := t.shouldUseCache()
 	var cacheKey string
 	if cacheEnabled {
 		cacheKey = t.cacheManager.GenerateCacheKey(req)
-		if cached, found := t.cacheManager.Get(cacheKey); found {
+	}
+	if cacheEnabled && !req.Force {
+		// A result whose figures are no longer where it links to them is converted again
+		if cached, found := t.cacheManager.Get(cacheKey); found && (req.ImageDir == "" || figuresPresent(cached, req.ImageDir)) {
+			// The result may have been cached for a copy of the file elsewhere
+			cached.Source = req.Source
 			// Handle file saving for cached results
 			if t.shouldSaveToFile(req) && cached.Error == "" {
-				return t.handleSaveToFile(req.SaveTo, cached, "")
+				return t.handleSaveToFile(req, cached, "")
 			}
 			return t.newToolResultJSON(t.formatResponse(cached))
 		}
 	}
 
+	if req.ImageDir != "" {
+		if err := prepareImageDir(req.ImageDir); err != nil {
+			return nil, err
+		}
+	}
+
 	// Process document
 	response, err := t.processDocument(req)
 	if err != nil {
@@ -187,6 +288,12 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 		return t.newToolResultJSON(errorResult)
 	}
 
+	if req.ImageDir != "" && response.Error == "" {
+		if err := t.finishFigures(ctx, req, response); err != nil {
+			return nil, err
+		}
+	}
+
 	// Cache result if successful
 	if cacheEnabled && response.Error == "" {
 		// Cache the result but don't include cache key in response
@@ -217,7 +324,7 @@ func (t *DocumentProcessorTool) Execute(ctx context.Context, logger *logrus.Logg
 
 	// Handle file saving if specified
 	if t.shouldSaveToFile(req) && response.Error == "" {
-		return t.handleSaveToFile(req.SaveTo, response, securityNotice)
+		return t.handleSaveToFile(req, response, securityNotice)
 	}
 
 	// Add security notice to response if needed
@@ -235,6 +342,69 @@ func (t *DocumentProcessorTool) shouldUseCache() bool {
 	return t.config.CacheEnabled
 }
 
+// manageCache reports on or removes cached conversions
+func (t *DocumentProcessorTool) manageCache(action string) (*mcp.CallToolResult, error) {
+	var err error
+	switch action {
+	case CacheActionStats:
+	case CacheActionClean:
+		err = t.cacheManager.CleanExpired()
+	case CacheActionClear:
+		err = t.cacheManager.Clear()
+	default:
+		return nil, fmt.Errorf("invalid cache action %q, must be one of: %s, %s, %s", action, CacheActionStats, CacheActionClean, CacheActionClear)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats, err := t.cacheManager.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	return t.newToolResultJSON(map[string]any{"action": action, "cache": stats})
+}
+
+// IsMemoryIntensive reports that every call is memory intensive, as converted documents, their
+// extracted images and batches are held in memory
+func (t *DocumentProcessorTool) IsMemoryIntensive(map[string]any) bool {
+	return true
+}
+
+// WorkQueue queues every call for a document worker, as conversion and OCR keep the CPU busy
+func (t *DocumentProcessorTool) WorkQueue(map[string]any) string {
+	return workpool.Documents
+}
+
+// CheckPrerequisites reports the Python and docling installation the tool uses
+func (t *DocumentProcessorTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	check := tools.PrerequisiteCheck{Name: "docling", Status: tools.PrerequisiteOK}
+	if err := t.load(); err != nil {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = err.Error()
+		check.Remediation = "Install docling with: pip install docling, or set DOCLING_PYTHON_PATH to a Python that has it, or set DOCLING_SERVE_URL to a docling-serve instance"
+		return []tools.PrerequisiteCheck{check}
+	}
+	if t.config.ServeURL != "" {
+		check.Detail = fmt.Sprintf("documents are converted by docling-serve at %s", t.config.ServeURL)
+		return []tools.PrerequisiteCheck{check}
+	}
+	if t.nativeReason != "" {
+		check.Status = tools.PrerequisiteWarning
+		check.Detail = fmt.Sprintf("%s, so only text is extracted from PDF, Word, Excel, PowerPoint, CSV, HTML and text files with the built-in converter", t.nativeReason)
+		check.Remediation = "Install docling with: pip install docling, or set DOCLING_PYTHON_PATH to a Python that has it, or set DOCLING_SERVE_URL to a docling-serve instance"
+		return []tools.PrerequisiteCheck{check}
+	}
+	pythonVersion, doclingVersion := t.config.getPythonVersion(), t.config.getDoclingVersion()
+	if doclingVersion == "" {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = fmt.Sprintf("docling could not be imported with %s", t.config.PythonPath)
+		check.Remediation = fmt.Sprintf("Run: %s -m pip install docling, or set DOCLING_PYTHON_PATH", t.config.PythonPath)
+		return []tools.PrerequisiteCheck{check}
+	}
+	check.Detail = fmt.Sprintf("docling %s with %s at %s", doclingVersion, pythonVersion, t.config.PythonPath)
+	return []tools.PrerequisiteCheck{check}
+}
+
 // ProvideExtendedInfo provides detailed usage information for the document processing tool
 func (t *DocumentProcessorTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 	return &tools.ExtendedHelp{
@@ -279,12 +449,12 @@ func (t *DocumentProcessorTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			{
 				Description: "Force cache refresh and save to custom location",
 				Arguments: map[string]any{
-					"source":           "/Users/username/docs/presentation.pptx",
-					"profile":          "text-and-image",
-					"clear_file_cache": true,
-					"save_to":          "/Users/username/output/presentation.md",
+					"source":  "/Users/username/docs/presentation.pptx",
+					"profile": "text-and-image",
+					"force":   true,
+					"save_to": "/Users/username/output/presentation.md",
 				},
-				ExpectedResult: "Clears any cached results, reprocesses document, and saves output to specified location",
+				ExpectedResult: "Reprocesses the document even if it was converted before, and saves output to specified location",
 			},
 		},
 		CommonPatterns: []string{
@@ -292,7 +462,11 @@ func (t *DocumentProcessorTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"Use 'basic' profile for faster text-only extraction when images are not needed",
 			"Use 'scanned' profile specifically for PDFs that contain scanned images or poor-quality text",
 			"Use batch processing with 'sources' array for multiple files to improve efficiency",
-			"Set 'clear_file_cache: true' when document content has changed but filename is the same",
+			"Converting the same content with the same options again returns the cached result instantly, even if the file was edited back, copied or renamed. Set 'force: true' to convert it again",
+			"Set 'chunk_level: 2' for long manuals, then read the index.md it writes and load only the sections needed",
+			"Use 'cache: stats' to see how much the cache holds, and 'cache: clear' to empty it",
+			"Figures are saved to a <name>_images directory beside the saved markdown and linked from it. Set 'describe_images: true' to give them alt text when a vision model is configured",
+			"Set 'table_format: csv' for financial documents to get each table as a CSV file, and check each table's confidence before relying on its figures",
 		},
 		Troubleshooting: []tools.TroubleshootingTip{
 			{
@@ -326,7 +500,13 @@ func (t *DocumentProcessorTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"profile":            "Processing profile affects quality vs speed: 'text-and-image' (comprehensive, default), 'basic' (text-only, fast), 'scanned' (OCR for images), 'llm-smoldocling' (vision model), 'llm-external' (if configured).",
 			"return_inline_only": "When true, returns content only in response without saving to file. When false (default), saves processed markdown to file system and returns file path.",
 			"save_to":            "Override output file location (absolute path required). By default, saves to same directory as source with .md extension. Useful for organising output or preventing overwrites.",
-			"clear_file_cache":   "Forces reprocessing by clearing cached results for the source file. Use when document content changed but filename is the same, or when troubleshooting cache issues.",
+			"clear_file_cache":   "Removes every cached result for the source path before processing. Edited files are converted again without it, as results are cached by content.",
+			"chunk_level":        "Splits the saved markdown into one file per section at this heading level or above, written to a <name>_chunks directory with an index.md of sections and estimated tokens. Use for long documents so only the relevant sections need to be read.",
+			"image_dir":          "Where figures are written, as figure-1.png and so on, replacing the figures of an earlier conversion. Defaults to <name>_images beside the saved markdown, with links relative to it. Works with docling, docling-serve, and the built-in converter for Word and PowerPoint files.",
+			"describe_images":    "Sends each figure labelled only 'Picture N' or similar to the configured vision model and uses its reply as the figure's alt text. Figures that cannot be described keep their label, and the reason is given in processing_info.alt_text_error.",
+			"table_format":       "Extracts tables with a confidence score for each. 'markdown' keeps GFM tables, 'html' writes tables as HTML so merged cells and multi-row headers survive, and 'csv' also saves each table to a <name>_tables directory. Applies to docling conversions, not docling-serve or the built-in converter.",
+			"force":              "Converts the document even if the same content was converted with the same options before, replacing the cached result.",
+			"cache":              "Manages the cache instead of converting: 'stats' reports its size, 'clean' removes expired entries and 'clear' removes every entry. No source is needed.",
 			"timeout":            "Processing timeout in seconds. Override default timeouts for complex documents. Larger documents or OCR processing may need longer timeouts.",
 			"debug":              "Returns environment and configuration information without processing. Useful for troubleshooting setup issues or verifying tool configuration.",
 		},
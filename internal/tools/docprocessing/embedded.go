@@ -13,18 +13,26 @@ var embeddedPythonFiles embed.FS
 
 var (
 	extractedScriptPath string
-	extractOnce         sync.Once
-	extractError        error
+	extractMu           sync.Mutex
 )
 
 // GetEmbeddedScriptPath extracts the embedded Python scripts to a temporary directory
 // and returns the path to the main docling_processor.py script.
-// This is thread-safe and only extracts once per process.
+// This is thread-safe and only extracts again if the extracted scripts have been removed.
 func GetEmbeddedScriptPath() (string, error) {
-	extractOnce.Do(func() {
-		extractedScriptPath, extractError = extractEmbeddedScripts()
-	})
-	return extractedScriptPath, extractError
+	extractMu.Lock()
+	defer extractMu.Unlock()
+	if extractedScriptPath != "" {
+		if _, err := os.Stat(extractedScriptPath); err == nil {
+			return extractedScriptPath, nil
+		}
+	}
+	scriptPath, err := extractEmbeddedScripts()
+	if err != nil {
+		return "", err
+	}
+	extractedScriptPath = scriptPath
+	return extractedScriptPath, nil
 }
 
 // extractEmbeddedScripts extracts all embedded Python files to a temporary directory
@@ -79,11 +87,14 @@ func extractEmbeddedScripts() (string, error) {
 // CleanupEmbeddedScripts removes the temporary directory containing extracted scripts
 // This should be called during graceful shutdown, but the OS will clean up temp files anyway
 func CleanupEmbeddedScripts() error {
+	extractMu.Lock()
+	defer extractMu.Unlock()
 	if extractedScriptPath == "" {
 		return nil // Nothing to clean up
 	}
 
 	tempDir := filepath.Dir(extractedScriptPath)
+	extractedScriptPath = ""
 	return os.RemoveAll(tempDir)
 }
 
@@ -0,0 +1,249 @@
+package docprocessing
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// imagePlaceholder marks a figure that was not extracted, as docling writes it
+const imagePlaceholder = "<!-- image -->"
+
+var (
+	// figureFilePattern matches the figures written for a document, so earlier figures can be removed
+	figureFilePattern = regexp.MustCompile(`^figure-[0-9]+\.[a-z]+$`)
+	// embeddedImagePattern matches a markdown image embedded as base64 data, capturing its alt text, type and data
+	embeddedImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(data:(image/[a-z0-9.+-]+);base64,([A-Za-z0-9+/=]+)\)`)
+	// imageLinkPattern matches a markdown image, capturing its alt text and target
+	imageLinkPattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	// genericAltPattern matches alt text that only numbers a figure, such as "Picture 3", which describe_images replaces
+	genericAltPattern = regexp.MustCompile(`(?i)^\s*((extracted )?(picture|image|figure)[ _-]?[0-9]*( \(could not extract\))?)?\s*$`)
+)
+
+// imageMIMETypes maps the file extensions of images found in documents to their types
+var imageMIMETypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".bmp":  "image/bmp",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".emf":  "image/x-emf",
+	".wmf":  "image/x-wmf",
+}
+
+// imageExtensions maps image types to the extension figures of that type are saved with
+var imageExtensions = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/jpg":     ".jpg",
+	"image/gif":     ".gif",
+	"image/bmp":     ".bmp",
+	"image/tiff":    ".tiff",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+	"image/x-emf":   ".emf",
+	"image/x-wmf":   ".wmf",
+}
+
+// ImageDescriber writes alt text for a figure from its image
+type ImageDescriber interface {
+	DescribeImage(ctx context.Context, data []byte, mimeType string) (string, error)
+}
+
+// imageDir returns the directory a saved document's figures are written to, beside the saved file
+func imageDir(savePath string) string {
+	return strings.TrimSuffix(savePath, filepath.Ext(savePath)) + "_images"
+}
+
+// resolveImageOutput sets where a document's figures are written and the directory their links are
+// relative to. Figures of saved documents go beside the saved file unless image_dir is set. Documents
+// only returned inline have their figures written when image_dir is set, linked by absolute path.
+func (t *DocumentProcessorTool) resolveImageOutput(req *DocumentProcessingRequest) error {
+	if !t.shouldSaveToFile(req) {
+		return nil
+	}
+	savePath := req.SaveTo
+	if savePath == "" {
+		generated, err := t.generateSavePath(req.Source)
+		if err != nil {
+			return fmt.Errorf("failed to generate save path: %w", err)
+		}
+		savePath = generated
+	}
+	// Saving to a relative path is refused later, so there is nothing to link figures to
+	if !filepath.IsAbs(savePath) {
+		return nil
+	}
+	req.imageLinkDir = filepath.Dir(savePath)
+	if req.ImageDir == "" {
+		req.ImageDir = imageDir(savePath)
+	}
+	return nil
+}
+
+// prepareImageDir creates a document's image directory and removes figures from an earlier conversion
+func prepareImageDir(dir string) error {
+	if err := security.CheckFileAccess(dir); err != nil {
+		return fmt.Errorf("image directory access denied: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create image directory %s: %w", dir, err)
+	}
+	// Only files written as figures are removed, in case the directory holds anything else
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read image directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && figureFilePattern.MatchString(entry.Name()) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return nil
+}
+
+// imageLink returns the link to a figure: relative to linkDir when the document is saved, otherwise absolute
+func imageLink(file, linkDir string) string {
+	if linkDir == "" {
+		return file
+	}
+	rel, err := filepath.Rel(linkDir, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// markdownAlt makes text safe to use as a markdown image's alt text
+func markdownAlt(text string) string {
+	text = strings.NewReplacer("[", "(", "]", ")", "\r", " ", "\n", " ").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// saveEmbeddedImages writes the images embedded in the content as base64 data, as docling-serve and the
+// built-in converter embed them, to files in the image directory, and links to the files instead
+func saveEmbeddedImages(response *DocumentProcessingResponse, dir, linkDir string) error {
+	var writeErr error
+	number := len(response.Images)
+	response.Content = embeddedImagePattern.ReplaceAllStringFunc(response.Content, func(match string) string {
+		parts := embeddedImagePattern.FindStringSubmatch(match)
+		alt, mimeType, encoded := parts[1], parts[2], parts[3]
+		ext, ok := imageExtensions[mimeType]
+		if !ok || writeErr != nil {
+			return match
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return match
+		}
+
+		number++
+		file := filepath.Join(dir, fmt.Sprintf("figure-%d%s", number, ext))
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			writeErr = fmt.Errorf("failed to write figure %s: %w", file, err)
+			return match
+		}
+		response.Images = append(response.Images, ExtractedImage{
+			ID:       fmt.Sprintf("figure_%d", number),
+			Type:     "figure",
+			AltText:  alt,
+			Format:   strings.ToUpper(strings.TrimPrefix(ext, ".")),
+			Size:     int64(len(data)),
+			FilePath: file,
+		})
+		return fmt.Sprintf("![%s](%s)", alt, imageLink(file, linkDir))
+	})
+	return writeErr
+}
+
+// figuresPresent reports whether the figures of a cached result are still in the image directory, so
+// its links can be reused. Results for a copy of the document saved elsewhere link to another directory.
+func figuresPresent(response *DocumentProcessingResponse, dir string) bool {
+	for _, image := range response.Images {
+		if image.FilePath == "" {
+			continue
+		}
+		rel, err := filepath.Rel(dir, image.FilePath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		if _, err := os.Stat(image.FilePath); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// describeImages writes alt text for figures that only have a generic label such as "Picture 3",
+// updating their links in the content. Figures that cannot be described keep their label, and the
+// first error is returned.
+func describeImages(ctx context.Context, describer ImageDescriber, response *DocumentProcessingResponse, linkDir string) error {
+	var firstErr error
+	described := make(map[string]string)
+	for i, image := range response.Images {
+		if image.FilePath == "" || !genericAltPattern.MatchString(image.AltText) {
+			continue
+		}
+		mimeType, ok := imageMIMETypes[strings.ToLower(filepath.Ext(image.FilePath))]
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(image.FilePath)
+		if err == nil {
+			var alt string
+			if alt, err = describer.DescribeImage(ctx, data, mimeType); err == nil && markdownAlt(alt) != "" {
+				response.Images[i].AltText = markdownAlt(alt)
+				described[filepath.Clean(image.FilePath)] = response.Images[i].AltText
+				continue
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", filepath.Base(image.FilePath), err)
+		}
+	}
+
+	if len(described) > 0 {
+		response.Content = imageLinkPattern.ReplaceAllStringFunc(response.Content, func(match string) string {
+			target := imageLinkPattern.FindStringSubmatch(match)[2]
+			file := filepath.FromSlash(target)
+			if !filepath.IsAbs(file) && linkDir != "" {
+				file = filepath.Join(linkDir, file)
+			}
+			if alt, ok := described[filepath.Clean(file)]; ok {
+				return fmt.Sprintf("![%s](%s)", alt, target)
+			}
+			return match
+		})
+	}
+	return firstErr
+}
+
+// finishFigures writes the figures embedded in a converted document to files, describes them if asked,
+// and removes the image directory again if the document has none
+func (t *DocumentProcessorTool) finishFigures(ctx context.Context, req *DocumentProcessingRequest, response *DocumentProcessingResponse) error {
+	if err := saveEmbeddedImages(response, req.ImageDir, req.imageLinkDir); err != nil {
+		return err
+	}
+	if req.DescribeImages {
+		client, err := NewDiagramLLMClient()
+		if err == nil {
+			err = describeImages(ctx, client, response, req.imageLinkDir)
+		}
+		if err != nil {
+			response.ProcessingInfo.AltTextError = err.Error()
+		}
+	}
+	// Only removed if empty
+	_ = os.Remove(req.ImageDir)
+	return nil
+}
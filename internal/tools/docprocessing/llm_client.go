@@ -2,6 +2,7 @@ package docprocessing
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -48,8 +49,20 @@ It's critical that it's accurate and that you do not make up anything that is no
 You MUST always use British English spelling.
 You MUST never respond with anything other than the diagram inside a markdown codeblock.
 You MUST always follow these rules.`
+
+	DefaultAltTextPrompt = `Write alt text for this figure from a document, for a reader who cannot see it.
+
+Describe what it shows and any figures, labels or trends that matter, in one or two plain sentences of no more than 250 characters.
+
+It's critical that you do not make up anything that is not in the figure.
+
+You MUST always use British English spelling.
+You MUST respond with the alt text only, without quotes or a preamble.`
 )
 
+// maxAltTextTokens limits the length of the alt text written for a figure
+const maxAltTextTokens = 200
+
 // DiagramLLMClient handles LLM-based diagram analysis using OpenAI API
 type DiagramLLMClient struct {
 	client      *openai.Client
@@ -186,6 +199,33 @@ func (c *DiagramLLMClient) AnalyseDiagram(diagram *ExtractedDiagram) (*DiagramAn
 	return analysis, nil
 }
 
+// DescribeImage writes alt text for a figure, sending its image to the vision model
+func (c *DiagramLLMClient) DescribeImage(ctx context.Context, data []byte, mimeType string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	response, err := c.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+				openai.TextContentPart(DefaultAltTextPrompt),
+				openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+					URL: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data),
+				}),
+			}),
+		},
+		MaxTokens:   openai.Int(maxAltTextTokens),
+		Temperature: openai.Float(c.temperature),
+	})
+	if err != nil {
+		return "", fmt.Errorf("alt text generation failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned from LLM")
+	}
+	return strings.Trim(strings.TrimSpace(response.Choices[0].Message.Content), `"'`), nil
+}
+
 // buildDiagramPrompt creates a prompt for diagram analysis using the simplified approach
 func (c *DiagramLLMClient) buildDiagramPrompt(diagram *ExtractedDiagram) string {
 	// Use the single, focused prompt
@@ -0,0 +1,540 @@
+package docprocessing
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/sammcj/mcp-devtools/internal/tools/pdf"
+	"github.com/sirupsen/logrus"
+	"github.com/xuri/excelize/v2"
+)
+
+// NativeFallbackEnvVar turns off converting with the built-in converter when docling is unavailable
+const NativeFallbackEnvVar = "DOCLING_NATIVE_FALLBACK"
+
+// ProcessingMethodNative is the processing method reported for documents converted without docling
+const ProcessingMethodNative = "native"
+
+// maxXMLPartBytes limits how much of an office document's XML part is read, as a guard against zip bombs
+const maxXMLPartBytes = 200 << 20
+
+// nativeFormats are the file types the built-in converter reads
+var nativeFormats = []string{".pdf", ".docx", ".xlsx", ".pptx", ".txt", ".md", ".csv", ".html", ".htm"}
+
+// slideNumber matches a slide's XML part, capturing its number
+var slideNumber = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+// nativeFallbackEnabled reports whether the built-in converter is used when docling is unavailable
+func nativeFallbackEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(NativeFallbackEnvVar))
+	return err != nil || enabled
+}
+
+// processNative converts a local document to markdown with the built-in converter. It extracts text,
+// headings, lists and tables, and figures from Word and PowerPoint files: there is no OCR or layout
+// analysis. Figures are embedded as base64 data when an image directory is set, to be written out
+// with the other converters' figures, and are otherwise left as placeholders.
+func (t *DocumentProcessorTool) processNative(req *DocumentProcessingRequest, sourcePath string) (*DocumentProcessingResponse, error) {
+	if !filepath.IsAbs(sourcePath) {
+		return nil, fmt.Errorf("docling is unavailable (%s), and the built-in converter only reads local files", t.nativeReason)
+	}
+	ext := strings.ToLower(filepath.Ext(sourcePath))
+	if !slices.Contains(nativeFormats, ext) {
+		return nil, fmt.Errorf("docling is unavailable (%s), and the built-in converter cannot read %s files, only %s", t.nativeReason, ext, strings.Join(nativeFormats, ", "))
+	}
+
+	start := time.Now()
+	var content string
+	var pageCount int
+	var err error
+	switch ext {
+	case ".pdf":
+		var pages []string
+		// The PDF tool logs as it goes, which must not reach stderr in stdio mode
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		pages, err = pdf.ExtractText(context.Background(), logger, sourcePath)
+		pageCount = len(pages)
+		var sb strings.Builder
+		for i, page := range pages {
+			fmt.Fprintf(&sb, "## Page %d\n\n%s\n\n", i+1, page)
+		}
+		content = sb.String()
+	case ".docx":
+		content, err = convertDocx(sourcePath, req.ImageDir != "")
+	case ".pptx":
+		content, pageCount, err = convertPptx(sourcePath, req.ImageDir != "")
+	case ".xlsx":
+		content, err = convertXlsx(sourcePath)
+	case ".csv":
+		content, err = convertCSV(sourcePath)
+	case ".html", ".htm":
+		content, err = convertHTML(sourcePath)
+	default:
+		var data []byte
+		data, err = os.ReadFile(sourcePath)
+		content = string(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("built-in converter failed to read %s: %w", filepath.Base(sourcePath), err)
+	}
+	content = strings.TrimSpace(content) + "\n"
+
+	fileSize := int64(0)
+	if info, err := os.Stat(sourcePath); err == nil {
+		fileSize = info.Size()
+	}
+	return &DocumentProcessingResponse{
+		Source:  req.Source,
+		Content: content,
+		Metadata: &DocumentMetadata{
+			Format:    strings.TrimPrefix(ext, "."),
+			PageCount: pageCount,
+			WordCount: len(strings.Fields(content)),
+			FileSize:  fileSize,
+		},
+		ProcessingInfo: ProcessingInfo{
+			ProcessingMode:   req.ProcessingMode,
+			ProcessingMethod: ProcessingMethodNative,
+			ProcessingTime:   time.Since(start).Seconds(),
+			Timestamp:        time.Now(),
+			Fallback:         fmt.Sprintf("docling is unavailable (%s), so the built-in converter was used: text, headings, lists, tables and office document figures only, without OCR", t.nativeReason),
+		},
+	}, nil
+}
+
+// readZipPart reads a part of an office document, limiting its size
+func readZipPart(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(io.LimitReader(rc, maxXMLPartBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxXMLPartBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", file.Name, maxXMLPartBytes>>20)
+	}
+	return data, nil
+}
+
+// attr returns the value of an element's attribute by its local name
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// convertDocx converts a Word document's body to markdown, keeping headings, list items, tables and
+// figures, which are embedded as base64 data if embedFigures is set
+func convertDocx(path string, embedFigures bool) (string, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = archive.Close() }()
+	var body []byte
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			if body, err = readZipPart(file); err != nil {
+				return "", err
+			}
+		}
+	}
+	if body == nil {
+		return "", errors.New("word/document.xml not found, so this is not a Word document")
+	}
+	figure, err := figureResolver(&archive.Reader, "word/document.xml", embedFigures)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	var paragraph strings.Builder
+	var style string
+	var listItem bool
+	var figures []string
+	var figureAlt string
+	var tableDepth int
+	var rows [][]string
+	var row []string
+	var cell []string
+
+	decoder := xml.NewDecoder(strings.NewReader(string(body)))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "p":
+				paragraph.Reset()
+				style, listItem, figures = "", false, nil
+			case "docPr":
+				figureAlt = cmp.Or(attr(element, "descr"), attr(element, "title"))
+			case "blip":
+				figures = append(figures, figure(attr(element, "embed"), figureAlt))
+				figureAlt = ""
+			case "pStyle":
+				style = attr(element, "val")
+			case "numPr":
+				listItem = true
+			case "t":
+				var text string
+				if err := decoder.DecodeElement(&text, &element); err != nil {
+					return "", err
+				}
+				paragraph.WriteString(text)
+			case "tab":
+				paragraph.WriteString("\t")
+			case "br":
+				paragraph.WriteString("\n")
+			case "tbl":
+				tableDepth++
+				if tableDepth == 1 {
+					rows = nil
+				}
+			case "tr":
+				if tableDepth == 1 {
+					row = nil
+				}
+			case "tc":
+				if tableDepth == 1 {
+					cell = nil
+				}
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				if tableDepth > 0 {
+					if text != "" {
+						cell = append(cell, text)
+					}
+					cell = append(cell, figures...)
+					continue
+				}
+				if text != "" {
+					out.WriteString(docxPrefix(style, listItem))
+					out.WriteString(text)
+					out.WriteString("\n\n")
+				}
+				for _, f := range figures {
+					out.WriteString(f)
+					out.WriteString("\n\n")
+				}
+			case "tc":
+				if tableDepth == 1 {
+					row = append(row, strings.Join(cell, " "))
+				}
+			case "tr":
+				if tableDepth == 1 {
+					rows = append(rows, row)
+				}
+			case "tbl":
+				tableDepth--
+				if tableDepth == 0 {
+					out.WriteString(markdownTable(rows))
+					out.WriteString("\n")
+				}
+			}
+		}
+	}
+	return out.String(), nil
+}
+
+// docxPrefix returns the markdown that starts a paragraph with a Word style
+func docxPrefix(style string, listItem bool) string {
+	lower := strings.ToLower(style)
+	switch {
+	case lower == "title":
+		return "# "
+	case strings.HasPrefix(lower, "heading"):
+		if level, err := strconv.Atoi(strings.TrimPrefix(lower, "heading")); err == nil && level > 0 {
+			return strings.Repeat("#", min(level, 6)) + " "
+		}
+	case listItem || strings.HasPrefix(lower, "list"):
+		return "- "
+	}
+	return ""
+}
+
+// convertPptx converts a PowerPoint presentation to markdown, with a section per slide. Pictures are
+// embedded as base64 data if embedFigures is set.
+func convertPptx(path string, embedFigures bool) (string, int, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = archive.Close() }()
+
+	type slide struct {
+		number int
+		file   *zip.File
+	}
+	var slides []slide
+	for _, file := range archive.File {
+		if match := slideNumber.FindStringSubmatch(file.Name); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			slides = append(slides, slide{number: number, file: file})
+		}
+	}
+	if len(slides) == 0 {
+		return "", 0, errors.New("no slides found, so this is not a PowerPoint presentation")
+	}
+	slices.SortFunc(slides, func(a, b slide) int { return a.number - b.number })
+
+	var out strings.Builder
+	for i, s := range slides {
+		data, err := readZipPart(s.file)
+		if err != nil {
+			return "", 0, err
+		}
+		figure, err := figureResolver(&archive.Reader, s.file.Name, embedFigures)
+		if err != nil {
+			return "", 0, err
+		}
+		title, paragraphs, err := slideText(data, figure)
+		if err != nil {
+			return "", 0, fmt.Errorf("slide %d: %w", i+1, err)
+		}
+		if title != "" {
+			fmt.Fprintf(&out, "## Slide %d: %s\n\n", i+1, title)
+		} else {
+			fmt.Fprintf(&out, "## Slide %d\n\n", i+1)
+		}
+		for _, paragraph := range paragraphs {
+			out.WriteString(paragraph)
+			out.WriteString("\n\n")
+		}
+	}
+	return out.String(), len(slides), nil
+}
+
+// slideText returns a slide's title and its other paragraphs, with the markdown figure gives for each
+// picture
+func slideText(data []byte, figure func(id, alt string) string) (string, []string, error) {
+	var title string
+	var paragraphs []string
+	var paragraph strings.Builder
+	var titleShape bool
+	var figureAlt string
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return title, paragraphs, nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "sp":
+				titleShape = false
+			case "ph":
+				// Placeholders of type title and ctrTitle hold the slide's title
+				titleShape = strings.HasSuffix(strings.ToLower(attr(element, "type")), "title")
+			case "p":
+				paragraph.Reset()
+			case "cNvPr":
+				figureAlt = cmp.Or(attr(element, "descr"), attr(element, "title"))
+			case "blip":
+				paragraphs = append(paragraphs, figure(attr(element, "embed"), figureAlt))
+			case "t":
+				var text string
+				if err := decoder.DecodeElement(&text, &element); err != nil {
+					return "", nil, err
+				}
+				paragraph.WriteString(text)
+			case "br":
+				paragraph.WriteString(" ")
+			}
+		case xml.EndElement:
+			if element.Name.Local != "p" {
+				continue
+			}
+			text := strings.TrimSpace(paragraph.String())
+			switch {
+			case text == "":
+			case titleShape && title == "":
+				title = text
+			default:
+				paragraphs = append(paragraphs, text)
+			}
+		}
+	}
+}
+
+// figureResolver returns a function giving the markdown for a figure in an office document part, from
+// the relationship ID of its image: the image embedded as base64 data if embed is set, or a placeholder
+// as docling writes for figures it does not extract
+func figureResolver(archive *zip.Reader, part string, embed bool) (func(id, alt string) string, error) {
+	placeholder := func(string, string) string { return imagePlaceholder }
+	if !embed {
+		return placeholder, nil
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	relsFile, ok := files[path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")]
+	if !ok {
+		return placeholder, nil
+	}
+	data, err := readZipPart(relsFile)
+	if err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("%s: %w", relsFile.Name, err)
+	}
+
+	// Targets are relative to the part, e.g. media/image1.png for word/document.xml, unless absolute
+	targets := make(map[string]*zip.File)
+	for _, rel := range rels.Relationships {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target := path.Join(path.Dir(part), rel.Target)
+		if strings.HasPrefix(rel.Target, "/") {
+			target = strings.TrimPrefix(rel.Target, "/")
+		}
+		if file, ok := files[target]; ok {
+			targets[rel.ID] = file
+		}
+	}
+
+	return func(id, alt string) string {
+		file, ok := targets[id]
+		if !ok {
+			return imagePlaceholder
+		}
+		mimeType, ok := imageMIMETypes[strings.ToLower(path.Ext(file.Name))]
+		if !ok {
+			return imagePlaceholder
+		}
+		data, err := readZipPart(file)
+		if err != nil {
+			return imagePlaceholder
+		}
+		return fmt.Sprintf("![%s](data:%s;base64,%s)", markdownAlt(alt), mimeType, base64.StdEncoding.EncodeToString(data))
+	}, nil
+}
+
+// convertXlsx converts each sheet of a workbook to a markdown table
+func convertXlsx(path string) (string, error) {
+	workbook, err := excelize.OpenFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = workbook.Close() }()
+
+	var out strings.Builder
+	for _, sheet := range workbook.GetSheetList() {
+		rows, err := workbook.GetRows(sheet)
+		if err != nil {
+			return "", fmt.Errorf("sheet %s: %w", sheet, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&out, "## %s\n\n%s\n", sheet, markdownTable(rows))
+	}
+	return out.String(), nil
+}
+
+// convertCSV converts a CSV file to a markdown table
+func convertCSV(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	return markdownTable(rows), nil
+}
+
+// convertHTML converts an HTML file to markdown
+func convertHTML(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return htmltomarkdown.ConvertString(string(data))
+}
+
+// markdownTable renders rows as a markdown table, using the first row as the header
+func markdownTable(rows [][]string) string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for i, row := range rows {
+		sb.WriteString("|")
+		for column := range width {
+			value := ""
+			if column < len(row) {
+				value = row[column]
+			}
+			sb.WriteString(" " + escapeCell(value) + " |")
+		}
+		sb.WriteString("\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// escapeCell makes a value safe to place inside a markdown table cell
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -18,14 +18,14 @@ import time
 # Import our modular components
 try:
     from .image_processing import extract_images, replace_image_placeholders_with_links
-    from .table_processing import extract_tables
+    from .table_processing import extract_tables, TABLE_FORMATS
 except ImportError:
     # Fallback for when script is run directly
     import sys
     import os
     sys.path.insert(0, os.path.dirname(os.path.abspath(__file__)))
     from image_processing import extract_images, replace_image_placeholders_with_links
-    from table_processing import extract_tables
+    from table_processing import extract_tables, TABLE_FORMATS
 
 # Configure logging to both stderr and file
 import os
@@ -95,11 +95,31 @@ def set_memory_limit():
     except Exception as e:
         logger.warning(f"Failed to set memory limit: {e}")
 
-def configure_accelerator():
-    """Configure the accelerator device for Docling with configurable process count."""
+def available_accelerators():
+    """Return the devices PyTorch can use, best first. CPU is always available."""
+    import platform
+
+    available = []
+    try:
+        import torch
+        if platform.system() == 'Darwin' and torch.backends.mps.is_available():
+            available.append("mps")
+        if torch.cuda.is_available():
+            available.append("cuda")
+    except Exception:
+        pass  # PyTorch missing or broken, so only the CPU can be used
+    available.append("cpu")
+    return available
+
+def configure_accelerator(requested='auto'):
+    """Choose the accelerator device for Docling and configure its process count.
+
+    Returns the device, the number of accelerator processes and, when the requested device could not be
+    used, why. A requested device that is not available falls back to the best one that is, rather
+    than failing the conversion.
+    """
     try:
         import os
-        import platform
 
         # Get configurable accelerator processes (default: CPU cores - 1)
         accelerator_processes = None
@@ -116,61 +136,47 @@ def configure_accelerator():
             accelerator_processes = max(1, multiprocessing.cpu_count() - 1)
             logger.info(f"Using default accelerator processes: {accelerator_processes} (CPU cores - 1)")
 
-        # Try to use MPS (Metal Performance Shaders) on macOS first
-        if platform.system() == 'Darwin':
-            try:
-                import torch
-                if torch.backends.mps.is_available():
-                    # Try to configure Docling settings if available
-                    try:
-                        from docling.datamodel.settings import settings
-                        from docling.utils.accelerator_utils import AcceleratorDevice
-                        if hasattr(settings.perf, 'accelerator_device'):
-                            settings.perf.accelerator_device = AcceleratorDevice.MPS
-                        # Set accelerator processes if supported
-                        if hasattr(settings.perf, 'accelerator_processes'):
-                            settings.perf.accelerator_processes = accelerator_processes
-                    except ImportError:
-                        pass  # Settings not available, but MPS is still detected
-                    return "mps"
-            except ImportError:
-                pass
-
-        # Try CUDA if available
-        try:
-            import torch
-            if torch.cuda.is_available():
-                # Try to configure Docling settings if available
-                try:
-                    from docling.datamodel.settings import settings
-                    from docling.utils.accelerator_utils import AcceleratorDevice
-                    if hasattr(settings.perf, 'accelerator_device'):
-                        settings.perf.accelerator_device = AcceleratorDevice.CUDA
-                    # Set accelerator processes if supported
-                    if hasattr(settings.perf, 'accelerator_processes'):
-                        settings.perf.accelerator_processes = accelerator_processes
-                except ImportError:
-                    pass  # Settings not available, but CUDA is still detected
-                return "cuda"
-        except ImportError:
-            pass
+        available = available_accelerators()
+        device = available[0]
+        fallback = None
+        if requested and requested != 'auto':
+            if requested in available:
+                device = requested
+            else:
+                fallback = f"{requested} was requested but is not available (available: {', '.join(available)}), so {device} was used"
+                logger.warning(fallback)
 
-        # Fall back to CPU
+        # Older Docling versions read the device from their global settings
         try:
             from docling.datamodel.settings import settings
             from docling.utils.accelerator_utils import AcceleratorDevice
             if hasattr(settings.perf, 'accelerator_device'):
-                settings.perf.accelerator_device = AcceleratorDevice.CPU
+                settings.perf.accelerator_device = AcceleratorDevice(device)
             # Set accelerator processes if supported
             if hasattr(settings.perf, 'accelerator_processes'):
                 settings.perf.accelerator_processes = accelerator_processes
-        except ImportError:
+        except (ImportError, ValueError):
             pass  # Settings not available
-        return "cpu"
+
+        return device, accelerator_processes, fallback
 
     except Exception as e:
         logger.warning(f"Failed to configure accelerator: {e}")
-        return "unknown"
+        return "unknown", None, None
+
+def accelerator_options(device, processes):
+    """Build the pipeline accelerator options for a device, or None if this Docling has none."""
+    try:
+        try:
+            from docling.datamodel.accelerator_options import AcceleratorOptions, AcceleratorDevice
+        except ImportError:
+            from docling.datamodel.pipeline_options import AcceleratorOptions, AcceleratorDevice
+        options = AcceleratorOptions(device=AcceleratorDevice(device))
+        if processes:
+            options.num_threads = processes
+        return options
+    except Exception:
+        return None
 
 def cleanup_memory():
     """Force garbage collection to free up memory."""
@@ -296,6 +302,20 @@ def clean_markdown_formatting(content: str) -> str:
         logger.warning(f"Failed to clean markdown formatting: {e}")
         return content
 
+def inline_html_tables(content: str, document) -> str:
+    """Replace each table's markdown in the content with HTML, which keeps merged cells and
+    multi-row headers that markdown tables cannot represent."""
+    for table in getattr(document, 'tables', None) or []:
+        try:
+            markdown = clean_markdown_formatting(table.export_to_markdown(doc=document)).strip()
+            html = table.export_to_html(doc=document)
+        except Exception as e:
+            logger.warning(f"Could not export table as HTML: {e}")
+            continue
+        if markdown and html and markdown in content:
+            content = content.replace(markdown, html, 1)
+    return content
+
 def get_processing_method_description(args) -> str:
     """Generate a concise description of the processing method used."""
     components = []
@@ -357,11 +377,14 @@ def process_document(args) -> Dict[str, Any]:
 
         logger.info("Stage 3: Configuring hardware acceleration...")
         # Configure hardware acceleration
-        hardware_acceleration = configure_accelerator()
+        hardware_acceleration, accelerator_processes, acceleration_fallback = configure_accelerator(getattr(args, 'device', 'auto'))
         logger.info(f"Stage 3: Hardware acceleration configured: {hardware_acceleration}")
 
         # Build pipeline options
         pipeline_options = PdfPipelineOptions()
+        device_options = accelerator_options(hardware_acceleration, accelerator_processes)
+        if device_options is not None and hasattr(pipeline_options, 'accelerator_options'):
+            pipeline_options.accelerator_options = device_options
 
         # Configure OCR if enabled
         if args.enable_ocr:
@@ -398,6 +421,7 @@ def process_document(args) -> Dict[str, Any]:
         should_process_images = (
             args.preserve_images or
             getattr(args, 'extract_images', False) or
+            getattr(args, 'image_dir', None) or
             getattr(args, 'diagram_description', False) or
             getattr(args, 'chart_data_extraction', False) or
             args.processing_mode in ['advanced', 'images']
@@ -462,8 +486,24 @@ def process_document(args) -> Dict[str, Any]:
         # Create converter
         converter = DocumentConverter(format_options=format_options)
 
-        # Convert the document
-        result = converter.convert(args.source)
+        # Convert the document. A GPU that fails part way, such as running out of memory or hitting an
+        # unsupported operation, is retried on the CPU rather than failing the conversion
+        try:
+            result = converter.convert(args.source)
+        except Exception as e:
+            if hardware_acceleration in ('cpu', 'unknown'):
+                raise
+            logger.warning(f"Conversion on {hardware_acceleration} failed, retrying on cpu: {e}")
+            acceleration_fallback = f"conversion on {hardware_acceleration} failed ({e}), so cpu was used"
+            hardware_acceleration, _, _ = configure_accelerator('cpu')
+            device_options = accelerator_options('cpu', accelerator_processes)
+            if device_options is not None and hasattr(pipeline_options, 'accelerator_options'):
+                pipeline_options.accelerator_options = device_options
+            converter = DocumentConverter(format_options={
+                InputFormat.PDF: PdfFormatOption(pipeline_options=pipeline_options)
+            })
+            cleanup_memory()
+            result = converter.convert(args.source)
 
         # Check for errors - handle different API versions
         has_error = False
@@ -496,6 +536,8 @@ def process_document(args) -> Dict[str, Any]:
             content_output = result.document.export_to_markdown()
             # Clean up markdown formatting
             content_output = clean_markdown_formatting(content_output)
+            if getattr(args, 'table_format', None) == 'html':
+                content_output = inline_html_tables(content_output, result.document)
 
         if args.output_format in ['json', 'both']:
             # Export structured JSON
@@ -509,6 +551,7 @@ def process_document(args) -> Dict[str, Any]:
         should_extract_images = (
             args.preserve_images or
             getattr(args, 'extract_images', False) or
+            getattr(args, 'image_dir', None) or
             hasattr(args, 'export_file') and args.export_file  # Auto-extract when exporting to file
         )
 
@@ -517,12 +560,14 @@ def process_document(args) -> Dict[str, Any]:
 
             # If we extracted images and we're outputting markdown, replace image placeholders
             if images and args.output_format in ['markdown', 'both']:
-                content_output = replace_image_placeholders_with_links(content_output, images)
+                content_output = replace_image_placeholders_with_links(content_output, images, args)
 
-        # Extract tables if requested
+        # Extract tables if requested, scoring each with docling's page confidence when it is reported
         tables = []
-        if args.processing_mode in ['tables', 'advanced']:
-            tables = extract_tables(result.document)
+        table_format = getattr(args, 'table_format', None)
+        if args.processing_mode == 'tables' or table_format:
+            page_scores = getattr(getattr(result, 'confidence', None), 'pages', None)
+            tables = extract_tables(result.document, table_format or 'markdown', page_scores)
 
         # Extract diagram descriptions if requested
         diagrams = []
@@ -566,6 +611,9 @@ def process_document(args) -> Dict[str, Any]:
             }
         }
 
+        if acceleration_fallback:
+            response["processing_info"]["device_fallback"] = acceleration_fallback
+
         # Only include OCR languages if OCR was actually used
         if args.enable_ocr and args.ocr_languages:
             response["processing_info"]["ocr_languages"] = args.ocr_languages
@@ -652,246 +700,6 @@ def build_optimised_metadata(metadata: Dict[str, Any]) -> Dict[str, Any]:
     return optimised
 
 
-def extract_tables(document) -> List[Dict[str, Any]]:
-    """Extract tables from the document with multiple export formats."""
-    tables = []
-
-    try:
-        # Extract tables from Docling document
-        if hasattr(document, 'tables') and document.tables:
-            for i, table in enumerate(document.tables):
-                table_data = {
-                    "id": f"table_{i+1}",
-                    "page_number": getattr(table, 'page_number', None),
-                    "caption": getattr(table, 'caption', ''),
-                    "headers": [],
-                    "rows": [],
-                    "markdown": "",
-                    "csv": "",
-                    "html": ""
-                }
-
-                # Extract table structure
-                if hasattr(table, 'data') and table.data:
-                    # Convert table data to structured format
-                    table_rows = []
-                    headers = []
-
-                    # Handle different table data formats
-                    if isinstance(table.data, list):
-                        # List of rows format
-                        for row_idx, row in enumerate(table.data):
-                            if isinstance(row, list):
-                                # List of cells
-                                row_data = [str(cell) if cell is not None else "" for cell in row]
-                            elif hasattr(row, 'cells'):
-                                # Row object with cells
-                                row_data = [str(cell.text) if hasattr(cell, 'text') else str(cell) for cell in row.cells]
-                            else:
-                                # Fallback: convert to string
-                                row_data = [str(row)]
-
-                            if row_idx == 0 and not headers:
-                                # First row might be headers
-                                headers = row_data
-                            else:
-                                table_rows.append(row_data)
-
-                    # If no headers were detected, create generic ones
-                    if not headers and table_rows:
-                        headers = [f"Column {i+1}" for i in range(len(table_rows[0]))]
-
-                    table_data["headers"] = headers
-                    table_data["rows"] = table_rows
-
-                    # Generate export formats
-                    table_data["markdown"] = generate_table_markdown(headers, table_rows)
-                    table_data["csv"] = generate_table_csv(headers, table_rows)
-                    table_data["html"] = generate_table_html(headers, table_rows, table_data["caption"])
-
-                # Add bounding box if available
-                if hasattr(table, 'bbox') or hasattr(table, 'bounding_box'):
-                    bbox = getattr(table, 'bbox', None) or getattr(table, 'bounding_box', None)
-                    if bbox:
-                        table_data["bounding_box"] = {
-                            "x": getattr(bbox, 'x', 0),
-                            "y": getattr(bbox, 'y', 0),
-                            "width": getattr(bbox, 'width', 0),
-                            "height": getattr(bbox, 'height', 0)
-                        }
-
-                tables.append(table_data)
-
-        # Alternative: Extract from document elements if tables attribute not available
-        elif hasattr(document, 'elements'):
-            table_count = 0
-            for element in document.elements:
-                if hasattr(element, 'type') and element.type == 'table':
-                    table_count += 1
-                    table_data = extract_table_from_element(element, table_count)
-                    if table_data:
-                        tables.append(table_data)
-
-    except Exception as e:
-        logger.warning(f"Failed to extract tables: {e}")
-
-    return tables
-
-def extract_table_from_element(element, table_id: int) -> Dict[str, Any]:
-    """Extract table data from a document element."""
-    try:
-        table_data = {
-            "id": f"table_{table_id}",
-            "page_number": getattr(element, 'page_number', None),
-            "caption": getattr(element, 'caption', ''),
-            "headers": [],
-            "rows": [],
-            "markdown": "",
-            "csv": "",
-            "html": ""
-        }
-
-        # Extract table content from element
-        if hasattr(element, 'content') and element.content:
-            # Try to parse table content
-            content = element.content
-            if isinstance(content, str):
-                # Parse markdown-style table
-                lines = content.strip().split('\n')
-                if len(lines) >= 2:
-                    # First line as headers
-                    headers = [cell.strip() for cell in lines[0].split('|') if cell.strip()]
-
-                    # Skip separator line (usually contains dashes)
-                    data_lines = lines[2:] if len(lines) > 2 else []
-
-                    rows = []
-                    for line in data_lines:
-                        if '|' in line:
-                            row = [cell.strip() for cell in line.split('|') if cell.strip()]
-                            if row:
-                                rows.append(row)
-
-                    table_data["headers"] = headers
-                    table_data["rows"] = rows
-
-                    # Generate export formats
-                    table_data["markdown"] = generate_table_markdown(headers, rows)
-                    table_data["csv"] = generate_table_csv(headers, rows)
-                    table_data["html"] = generate_table_html(headers, rows, table_data["caption"])
-
-        return table_data
-
-    except Exception as e:
-        logger.warning(f"Failed to extract table from element: {e}")
-        return None
-
-def generate_table_markdown(headers: List[str], rows: List[List[str]]) -> str:
-    """Generate markdown table format."""
-    if not headers and not rows:
-        return ""
-
-    try:
-        lines = []
-
-        # Add headers
-        if headers:
-            lines.append("| " + " | ".join(headers) + " |")
-            lines.append("| " + " | ".join(["---"] * len(headers)) + " |")
-
-        # Add rows
-        for row in rows:
-            # Ensure row has same number of columns as headers
-            padded_row = row + [""] * (len(headers) - len(row)) if headers else row
-            lines.append("| " + " | ".join(padded_row) + " |")
-
-        return "\n".join(lines)
-
-    except Exception as e:
-        logger.warning(f"Failed to generate markdown table: {e}")
-        return ""
-
-def generate_table_csv(headers: List[str], rows: List[List[str]]) -> str:
-    """Generate CSV table format."""
-    if not headers and not rows:
-        return ""
-
-    try:
-        import csv
-        import io
-
-        output = io.StringIO()
-        writer = csv.writer(output, quoting=csv.QUOTE_MINIMAL)
-
-        # Write headers
-        if headers:
-            writer.writerow(headers)
-
-        # Write rows
-        for row in rows:
-            # Ensure row has same number of columns as headers
-            padded_row = row + [""] * (len(headers) - len(row)) if headers else row
-            writer.writerow(padded_row)
-
-        return output.getvalue().strip()
-
-    except Exception as e:
-        logger.warning(f"Failed to generate CSV table: {e}")
-        return ""
-
-def generate_table_html(headers: List[str], rows: List[List[str]], caption: str = "") -> str:
-    """Generate HTML table format."""
-    if not headers and not rows:
-        return ""
-
-    try:
-        html_parts = ["<table>"]
-
-        # Add caption if provided
-        if caption:
-            html_parts.append(f"  <caption>{escape_html(caption)}</caption>")
-
-        # Add headers
-        if headers:
-            html_parts.append("  <thead>")
-            html_parts.append("    <tr>")
-            for header in headers:
-                html_parts.append(f"      <th>{escape_html(header)}</th>")
-            html_parts.append("    </tr>")
-            html_parts.append("  </thead>")
-
-        # Add rows
-        if rows:
-            html_parts.append("  <tbody>")
-            for row in rows:
-                html_parts.append("    <tr>")
-                # Ensure row has same number of columns as headers
-                padded_row = row + [""] * (len(headers) - len(row)) if headers else row
-                for cell in padded_row:
-                    html_parts.append(f"      <td>{escape_html(cell)}</td>")
-                html_parts.append("    </tr>")
-            html_parts.append("  </tbody>")
-
-        html_parts.append("</table>")
-
-        return "\n".join(html_parts)
-
-    except Exception as e:
-        logger.warning(f"Failed to generate HTML table: {e}")
-        return ""
-
-def escape_html(text: str) -> str:
-    """Escape HTML special characters."""
-    if not isinstance(text, str):
-        text = str(text)
-
-    return (text
-            .replace("&", "&amp;")
-            .replace("<", "&lt;")
-            .replace(">", "&gt;")
-            .replace('"', "&quot;")
-            .replace("'", "&#x27;"))
-
 def export_structured_json(document) -> Dict[str, Any]:
     """Export document as structured JSON with full document hierarchy."""
     try:
@@ -2161,7 +1969,7 @@ def extract_diagram_text_elements(figure) -> List[Dict[str, Any]]:
 
     return text_elements
 
-def replace_image_placeholders_with_links(content: str, images: List[Dict[str, Any]]) -> str:
+def replace_image_placeholders_with_links(content: str, images: List[Dict[str, Any]], args=None) -> str:
     """Replace <!-- image --> placeholders with proper markdown image links."""
     try:
         import os
@@ -2187,8 +1995,13 @@ def replace_image_placeholders_with_links(content: str, images: List[Dict[str, A
                     # Get the absolute path of the image
                     abs_image_path = os.path.abspath(image_path)
 
+                    # Link relative to the saved markdown, or by absolute path when it is not saved
+                    if getattr(args, 'link_dir', None):
+                        relative_path = os.path.relpath(abs_image_path, args.link_dir).replace(os.sep, '/')
+                    elif getattr(args, 'image_dir', None):
+                        relative_path = abs_image_path
                     # Get the directory where the markdown file will be saved
-                    if hasattr(args, 'export_file') and args.export_file:
+                    elif hasattr(args, 'export_file') and args.export_file:
                         export_dir = os.path.dirname(os.path.abspath(args.export_file))
                         # Calculate relative path from export directory to image
                         relative_path = os.path.relpath(abs_image_path, export_dir)
@@ -3019,29 +2832,7 @@ def get_system_info() -> Dict[str, Any]:
         info["docling_available"] = False
 
     # Check hardware acceleration availability
-    acceleration_available = []
-
-    # Check MPS (macOS)
-    if platform.system() == 'Darwin':
-        try:
-            import torch
-            if torch.backends.mps.is_available():
-                acceleration_available.append("mps")
-        except ImportError:
-            pass
-
-    # Check CUDA
-    try:
-        import torch
-        if torch.cuda.is_available():
-            acceleration_available.append("cuda")
-    except ImportError:
-        pass
-
-    # CPU is always available
-    acceleration_available.append("cpu")
-
-    info["hardware_acceleration_available"] = acceleration_available
+    info["hardware_acceleration_available"] = available_accelerators()
 
     return info
 
@@ -3091,6 +2882,14 @@ def main():
                                help='Return content inline in the response only (do not save to file)')
     process_parser.add_argument('--extract-images', action='store_true',
                                help='Extract individual images, charts, and diagrams as base64-encoded data with AI recreation prompts')
+    process_parser.add_argument('--image-dir',
+                               help='Directory to write extracted images to')
+    process_parser.add_argument('--link-dir',
+                               help='Directory of the saved markdown, which image links are relative to')
+    process_parser.add_argument('--table-format', choices=TABLE_FORMATS,
+                               help='Extract tables as markdown, html or csv, with a confidence score for each')
+    process_parser.add_argument('--device', default='auto', choices=['auto', 'mps', 'cuda', 'cpu'],
+                               help='Accelerator device, falling back to the best available if it is not')
 
     # System info command
     info_parser = subparsers.add_parser('info', help='Get system information')
@@ -20,8 +20,12 @@ def save_image_to_file(image_data: str, filename: str, args=None) -> str:
         # Determine the output directory
         output_dir = None
 
+        # An image directory, given when the converted document is saved, takes precedence
+        if args and getattr(args, 'image_dir', None):
+            output_dir = args.image_dir
+            os.makedirs(output_dir, mode=0o700, exist_ok=True)
         # If export_file is provided, use its directory
-        if args and hasattr(args, 'export_file') and args.export_file:
+        elif args and hasattr(args, 'export_file') and args.export_file:
             output_dir = os.path.dirname(os.path.abspath(args.export_file))
         elif args and hasattr(args, 'source'):
             source_path = args.source
@@ -52,6 +56,14 @@ def save_image_to_file(image_data: str, filename: str, args=None) -> str:
         return f"failed_to_save_{filename}"
 
 
+def figure_filename(kind: str, number: int, args=None) -> str:
+    """Name an extracted image's file. Images written to an image directory are numbered figures,
+    matching the figures saved by the Go side for other converters."""
+    if args and getattr(args, 'image_dir', None):
+        return f"figure-{number}.png"
+    return f"{kind}_{number}.png"
+
+
 def extract_text_from_image(pil_image) -> List[str]:
     """Extract text from a PIL image using OCR."""
     try:
@@ -203,7 +215,7 @@ def extract_images(document, args=None) -> List[Dict[str, Any]]:
                             logger.debug(f"Failed to extract text from image: {e}")
 
                         # Save image to file
-                        image_filename = f"picture_{picture_counter}.png"
+                        image_filename = figure_filename("picture", picture_counter, args)
                         image_file_path = save_image_to_file(image_data, image_filename, args)
 
                         # Create image record
@@ -263,7 +275,7 @@ def extract_images(document, args=None) -> List[Dict[str, Any]]:
                                 page_number = getattr(element, 'page_number', None)
 
                                 # Save image to file
-                                image_filename = f"image_{picture_counter}.png"
+                                image_filename = figure_filename("image", picture_counter, args)
                                 image_file_path = save_image_to_file(image_data, image_filename, args)
 
                                 # Create image record
@@ -397,7 +409,7 @@ def extract_images_with_pdfimages(source_path: str, args=None) -> List[Dict[str,
                             logger.debug(f"Failed to extract text from pdfimages image: {e}")
 
                         # Save image to final location
-                        image_filename = f"picture_{i+1}.png"
+                        image_filename = figure_filename("picture", i+1, args)
                         image_file_path = save_image_to_file(image_data, image_filename, args)
 
                         # Determine page number (pdfimages doesn't provide this directly)
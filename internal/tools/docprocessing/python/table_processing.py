@@ -7,13 +7,22 @@ This module handles table extraction and formatting.
 
 from typing import List, Dict, Any
 import logging
+import math
 import pandas as pd
 
 logger = logging.getLogger(__name__)
 
 
-def extract_tables(document) -> List[Dict[str, Any]]:
-    """Extract tables from the document with multiple export formats."""
+TABLE_FORMATS = ['markdown', 'html', 'csv']
+
+
+def extract_tables(document, table_format: str = 'markdown', page_scores=None) -> List[Dict[str, Any]]:
+    """Extract tables from the document in the requested format, each with a confidence score.
+
+    Only the requested representation is included: 'markdown' (GitHub Flavoured Markdown), 'html'
+    (which keeps merged cells) or 'csv'. page_scores are docling's per-page confidence scores, when
+    the conversion reported them.
+    """
     tables = []
 
     try:
@@ -22,83 +31,49 @@ def extract_tables(document) -> List[Dict[str, Any]]:
             for i, table in enumerate(document.tables):
                 table_data = {
                     "id": f"table_{i+1}",
-                    "page_number": getattr(table, 'page_number', None),
-                    "caption": getattr(table, 'caption', ''),
+                    "caption": table_caption(table, document),
                     "headers": [],
                     "rows": [],
-                    "markdown": "",
-                    "csv": "",
-                    "html": ""
+                    "merged_cells": 0
                 }
 
-                try:
-                    # Use docling's built-in pandas export for better accuracy
-                    df = table.export_to_dataframe()
-
-                    if not df.empty:
-                        headers = df.columns.tolist()
-                        rows = df.values.tolist()
-
-                        table_data["headers"] = headers
-                        table_data["rows"] = rows
-
-                        # Generate export formats from the dataframe
-                        table_data["markdown"] = df.to_markdown(index=False)
-                        table_data["csv"] = df.to_csv(index=False)
-                        # Pass caption to to_html if it exists
-                        caption = table_data.get("caption")
-                        table_data["html"] = df.to_html(index=False, caption=caption if caption else None)
-
-                except Exception as e:
-                    logger.warning(f"Could not export table {i+1} to dataframe, falling back to manual extraction. Error: {e}")
-                    # Fallback to original logic if export_to_dataframe fails
-                    if hasattr(table, 'data') and table.data:
-                        # Convert table data to structured format
-                        table_rows = []
-                        headers = []
-
-                        # Handle different table data formats
-                        if isinstance(table.data, list):
-                            # List of rows format
-                            for row_idx, row in enumerate(table.data):
-                                if isinstance(row, list):
-                                    # List of cells
-                                    row_data = [str(cell) if cell is not None else "" for cell in row]
-                                elif hasattr(row, 'cells'):
-                                    # Row object with cells
-                                    row_data = [str(cell.text) if hasattr(cell, 'text') else str(cell) for cell in row.cells]
-                                else:
-                                    # Fallback: convert to string
-                                    row_data = [str(row)]
-
-                                if row_idx == 0 and not headers:
-                                    # First row might be headers
-                                    headers = row_data
-                                else:
-                                    table_rows.append(row_data)
-
-                        # If no headers were detected, create generic ones
-                        if not headers and table_rows:
-                            headers = [f"Column {i+1}" for i in range(len(table_rows[0]))]
-
-                        table_data["headers"] = headers
-                        table_data["rows"] = table_rows
-
-                        # Generate export formats
-                        table_data["markdown"] = generate_table_markdown(headers, table_rows)
-                        table_data["csv"] = generate_table_csv(headers, table_rows)
-                        table_data["html"] = generate_table_html(headers, table_rows, table_data["caption"])
-
-                # Add bounding box if available
-                if hasattr(table, 'bbox') or hasattr(table, 'bounding_box'):
-                    bbox = getattr(table, 'bbox', None) or getattr(table, 'bounding_box', None)
-                    if bbox:
-                        table_data["bounding_box"] = {
-                            "x": getattr(bbox, 'x', 0),
-                            "y": getattr(bbox, 'y', 0),
-                            "width": getattr(bbox, 'width', 0),
-                            "height": getattr(bbox, 'height', 0)
-                        }
+                # Read the cell grid, which repeats a merged cell's text in every position it spans
+                rows, header_rows, merged_cells = table_grid(table)
+                if not rows:
+                    rows, header_rows = table_dataframe_rows(table, i)
+
+                headers = combine_header_rows(rows[:header_rows])
+                body = rows[header_rows:]
+                table_data["headers"] = headers
+                table_data["rows"] = body
+                table_data["merged_cells"] = merged_cells
+
+                # Generate the requested export format
+                if table_format == 'html':
+                    table_data["html"] = export_table(table, document, 'export_to_html') or \
+                        generate_table_html(headers, body, table_data["caption"])
+                elif table_format == 'csv':
+                    table_data["csv"] = generate_table_csv(headers, body)
+                else:
+                    table_data["markdown"] = export_table(table, document, 'export_to_markdown') or \
+                        generate_table_markdown(headers, body)
+
+                # Add the page and bounding box of the table's first provenance
+                prov = getattr(table, 'prov', None)
+                page_number = getattr(prov[0], 'page_no', None) if prov else None
+                if page_number:
+                    table_data["page_number"] = page_number
+                bbox = getattr(prov[0], 'bbox', None) if prov else None
+                if bbox is not None:
+                    table_data["bounding_box"] = {
+                        "x": getattr(bbox, 'l', 0),
+                        "y": getattr(bbox, 't', 0),
+                        "width": abs(getattr(bbox, 'r', 0) - getattr(bbox, 'l', 0)),
+                        "height": abs(getattr(bbox, 'b', 0) - getattr(bbox, 't', 0))
+                    }
+
+                table_data["confidence"], table_data["confidence_source"] = table_confidence(
+                    rows, header_rows, merged_cells, page_number, page_scores)
 
                 tables.append(table_data)
 
@@ -118,6 +93,108 @@ def extract_tables(document) -> List[Dict[str, Any]]:
     return tables
 
 
+def table_caption(table, document) -> str:
+    """Return a table's caption text, if it has one."""
+    try:
+        if hasattr(table, 'caption_text'):
+            return table.caption_text(document) or ""
+    except Exception as e:
+        logger.warning(f"Could not read table caption: {e}")
+    caption = getattr(table, 'caption', '')
+    return caption if isinstance(caption, str) else ""
+
+
+def table_grid(table):
+    """Return a table's rows of cell text, how many leading rows are column headers and how many
+    cells span more than one row or column."""
+    data = getattr(table, 'data', None)
+    grid = getattr(data, 'grid', None) or []
+
+    rows = [[(getattr(cell, 'text', '') or '').strip() for cell in row] for row in grid]
+    header_rows = 0
+    for row in grid:
+        if not row or not all(getattr(cell, 'column_header', False) for cell in row):
+            break
+        header_rows += 1
+
+    merged_cells = sum(
+        1 for cell in getattr(data, 'table_cells', None) or []
+        if getattr(cell, 'row_span', 1) > 1 or getattr(cell, 'col_span', 1) > 1
+    )
+    return rows, header_rows, merged_cells
+
+
+def table_dataframe_rows(table, index: int):
+    """Return a table's rows from docling's pandas export, with its columns as a header row, for
+    tables without a cell grid."""
+    try:
+        df = table.export_to_dataframe()
+        if not df.empty:
+            headers = [str(column) for column in df.columns.tolist()]
+            rows = [["" if pd.isna(cell) else str(cell) for cell in row] for row in df.values.tolist()]
+            return [headers] + rows, 1
+    except Exception as e:
+        logger.warning(f"Could not export table {index+1} to dataframe: {e}")
+    return [], 0
+
+
+def combine_header_rows(header_rows: List[List[str]]) -> List[str]:
+    """Combine multi-row column headers into one header per column, e.g. '2024' over 'Q1' becomes
+    '2024 Q1'. A merged header's text repeats across the columns it spans, so it is only taken once."""
+    if not header_rows:
+        return []
+
+    headers = []
+    for column in range(max(len(row) for row in header_rows)):
+        parts = []
+        for row in header_rows:
+            text = row[column] if column < len(row) else ""
+            if text and text not in parts:
+                parts.append(text)
+        headers.append(" ".join(parts))
+    return headers
+
+
+def export_table(table, document, method: str) -> str:
+    """Export a table with one of docling's own serialisers, returning an empty string if it cannot."""
+    try:
+        if hasattr(table, method):
+            return getattr(table, method)(doc=document) or ""
+    except Exception as e:
+        logger.warning(f"Table {method} failed: {e}")
+    return ""
+
+
+def table_confidence(rows: List[List[str]], header_rows: int, merged_cells: int, page_number, page_scores):
+    """Score from 0 to 1 how reliably a table's structure was recognised, and where the score came from.
+
+    docling's table score for the table's page is used when the conversion reported one. Otherwise the
+    score is estimated from the table's shape: sparse tables, tables without a header row and tables with
+    many merged cells are the ones most often misaligned or flattened.
+    """
+    if page_scores and page_number:
+        # docling numbers pages from 0 in its confidence report and from 1 in provenance
+        scores = page_scores.get(page_number - 1)
+        score = getattr(scores, 'table_score', None)
+        if isinstance(score, (int, float)) and math.isfinite(score):
+            return round(max(0.0, min(1.0, float(score))), 2), "docling"
+
+    cells = [cell for row in rows for cell in row]
+    if not cells:
+        return 0.0, "structure"
+
+    columns = max(len(row) for row in rows)
+    filled = sum(1 for cell in cells if cell) / len(cells)
+    ragged = sum(1 for row in rows if len(row) != columns) / len(rows)
+    merged = min(1.0, merged_cells / len(cells))
+
+    score = 0.6 * filled + (0.2 if header_rows else 0.0) + 0.2 * (1 - merged) - 0.2 * ragged
+    if len(rows) < 2 or columns < 2:
+        # A single row or column is often text mistaken for a table
+        score *= 0.5
+    return round(max(0.0, min(1.0, score)), 2), "structure"
+
+
 def extract_table_from_element(element, table_id: int) -> Dict[str, Any]:
     """Extract table data from a document element."""
     try:
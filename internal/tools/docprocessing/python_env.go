@@ -0,0 +1,270 @@
+package docprocessing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
+)
+
+// PythonDiscoveryEnvVar turns off looking for a Python environment near each document when false
+const PythonDiscoveryEnvVar = "DOCLING_PYTHON_DISCOVERY"
+
+// How the Python used for a document was chosen
+const (
+	PythonSourceArgument    = "python_path"         // The call's python_path argument
+	PythonSourceVirtualenv  = "virtualenv"          // A .venv or venv directory in the document's project
+	PythonSourcePoetry      = "poetry"              // The Poetry environment of the document's project
+	PythonSourceConda       = "conda"               // The conda environment named in the project's environment.yml
+	PythonSourceVersionFile = ".python-version"     // The version named in the project's .python-version
+	PythonSourceActive      = "active"              // The virtualenv or conda environment the server was started in
+	PythonSourceEnvVar      = "DOCLING_PYTHON_PATH" // The DOCLING_PYTHON_PATH environment variable
+	PythonSourceDefault     = "default"             // The Python found when the tool was first used
+)
+
+// PythonEnvironment is the Python a document was converted with, and how it was chosen
+type PythonEnvironment struct {
+	Path    string   `json:"path"`
+	Source  string   `json:"source"`
+	Project string   `json:"project,omitempty"` // Directory the environment was found in
+	Skipped []string `json:"skipped,omitempty"` // Environments found first that do not have docling
+}
+
+// doclingPythons remembers the interpreters found to have docling, as importing it takes a second or more
+var doclingPythons sync.Map
+
+// hasDocling reports whether docling can be imported with a Python, remembering the ones that can
+func hasDocling(pythonPath string) bool {
+	if _, ok := doclingPythons.Load(pythonPath); ok {
+		return true
+	}
+	if !isDoclingAvailableInPython(pythonPath) {
+		return false
+	}
+	doclingPythons.Store(pythonPath, struct{}{})
+	return true
+}
+
+// ResolvePythonEnvironment chooses the Python to convert a document in dir with. An explicit
+// pythonPath is used if it has docling. Otherwise, unless DOCLING_PYTHON_PATH is set or discovery is
+// turned off, the nearest project directory from dir up to the home directory is searched for a
+// virtualenv, Poetry or conda environment, or a .python-version file, then the environment the server
+// runs in. Environments without docling are skipped, and fallback is used when none is found.
+func ResolvePythonEnvironment(dir, pythonPath, fallback string) (*PythonEnvironment, error) {
+	if pythonPath != "" {
+		if err := checkPythonPath(pythonPath); err != nil {
+			return nil, err
+		}
+		if !hasDocling(pythonPath) {
+			return nil, fmt.Errorf("docling could not be imported with python_path %s, run: %s -m pip install docling", pythonPath, pythonPath)
+		}
+		return &PythonEnvironment{Path: pythonPath, Source: PythonSourceArgument}, nil
+	}
+
+	if os.Getenv(PythonSourceEnvVar) != "" {
+		return &PythonEnvironment{Path: fallback, Source: PythonSourceEnvVar}, nil
+	}
+
+	env := &PythonEnvironment{Path: fallback, Source: PythonSourceDefault}
+	if enabled, err := strconv.ParseBool(os.Getenv(PythonDiscoveryEnvVar)); err == nil && !enabled {
+		return env, nil
+	}
+
+	try := func(candidate *PythonEnvironment) bool {
+		if security.CheckFileAccess(candidate.Path) != nil {
+			return false
+		}
+		if !hasDocling(candidate.Path) {
+			env.Skipped = append(env.Skipped, fmt.Sprintf("%s %s (docling not installed)", candidate.Source, candidate.Path))
+			return false
+		}
+		candidate.Skipped = env.Skipped
+		env = candidate
+		return true
+	}
+
+	if dir != "" {
+		for _, projectDir := range projectDirs(dir) {
+			for _, candidate := range dirPythons(projectDir) {
+				if try(candidate) {
+					return env, nil
+				}
+			}
+		}
+	}
+	for _, candidate := range activePythons() {
+		if try(candidate) {
+			return env, nil
+		}
+	}
+	return env, nil
+}
+
+// checkPythonPath checks a python_path argument names a Python interpreter the server may run
+func checkPythonPath(pythonPath string) error {
+	if !filepath.IsAbs(pythonPath) {
+		return fmt.Errorf("python_path must be an absolute path: %s", pythonPath)
+	}
+	// Only interpreters may be named, so the argument cannot be used to run other programs
+	if !strings.HasPrefix(strings.ToLower(filepath.Base(pythonPath)), "python") {
+		return fmt.Errorf("python_path must name a Python interpreter, such as /path/to/.venv/bin/python: %s", pythonPath)
+	}
+	if err := security.CheckFileAccess(pythonPath); err != nil {
+		return err
+	}
+	if info, err := os.Stat(pythonPath); err != nil || info.IsDir() {
+		return fmt.Errorf("python_path not found: %s", pythonPath)
+	}
+	return nil
+}
+
+// projectDirs returns dir and its parents up to and including the home directory, nearest first
+func projectDirs(dir string) []string {
+	home, _ := os.UserHomeDir()
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		parent := filepath.Dir(dir)
+		if dir == home || parent == dir {
+			return dirs
+		}
+		dir = parent
+	}
+}
+
+// dirPythons returns the environments a project directory names: its virtualenv, then its Poetry and
+// conda environments, then the Python in its .python-version file
+func dirPythons(dir string) []*PythonEnvironment {
+	var found []*PythonEnvironment
+	for _, name := range []string{".venv", "venv"} {
+		venv := filepath.Join(dir, name)
+		// pyvenv.cfg marks a virtualenv, rather than any directory of that name
+		if _, err := os.Stat(filepath.Join(venv, "pyvenv.cfg")); err != nil {
+			continue
+		}
+		if python := envPython(venv); python != "" {
+			found = append(found, &PythonEnvironment{Path: python, Source: PythonSourceVirtualenv, Project: dir})
+		}
+	}
+	if python := poetryPython(dir); python != "" {
+		found = append(found, &PythonEnvironment{Path: python, Source: PythonSourcePoetry, Project: dir})
+	}
+	if python := condaPython(dir); python != "" {
+		found = append(found, &PythonEnvironment{Path: python, Source: PythonSourceConda, Project: dir})
+	}
+	if version := readVersionFile(filepath.Join(dir, ".python-version")); version != "" {
+		if python := resolvePythonFromVersion(version); python != "" {
+			found = append(found, &PythonEnvironment{Path: python, Source: PythonSourceVersionFile, Project: dir})
+		}
+	}
+	return found
+}
+
+// activePythons returns the virtualenv and conda environment the server was started in
+func activePythons() []*PythonEnvironment {
+	var found []*PythonEnvironment
+	for _, name := range []string{"VIRTUAL_ENV", "CONDA_PREFIX"} {
+		if prefix := os.Getenv(name); prefix != "" {
+			if python := envPython(prefix); python != "" {
+				found = append(found, &PythonEnvironment{Path: python, Source: PythonSourceActive, Project: prefix})
+			}
+		}
+	}
+	return found
+}
+
+// envPython returns the Python in an environment directory, or "" if it has none
+func envPython(prefix string) string {
+	candidates := []string{filepath.Join(prefix, "bin", "python"), filepath.Join(prefix, "bin", "python3")}
+	if runtime.GOOS == "windows" {
+		candidates = []string{filepath.Join(prefix, "Scripts", "python.exe"), filepath.Join(prefix, "python.exe")}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// poetryPython asks Poetry for the environment of a project whose pyproject.toml uses it. Projects that
+// keep their environment in .venv are found as virtualenvs instead.
+func poetryPython(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	if err != nil || !strings.Contains(string(data), "[tool.poetry]") {
+		return ""
+	}
+	poetry, err := findExecutable("poetry")
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := supervisor.Run(ctx, supervisor.Spec{Name: "poetry", Path: poetry, Args: []string{"env", "info", "--path"}, Dir: dir})
+	if err != nil {
+		return ""
+	}
+	return envPython(strings.TrimSpace(result.Stdout))
+}
+
+// condaPython returns the Python of the conda environment named in a project's environment.yml
+func condaPython(dir string) string {
+	var name string
+	for _, file := range []string{"environment.yml", "environment.yaml"} {
+		if name = condaEnvName(filepath.Join(dir, file)); name != "" {
+			break
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	for _, envsDir := range condaEnvsDirs() {
+		if python := envPython(filepath.Join(envsDir, name)); python != "" {
+			return python
+		}
+	}
+	return ""
+}
+
+// condaEnvName reads the top-level name from a conda environment file
+func condaEnvName(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "name:"); ok {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// condaEnvsDirs returns the directories conda keeps named environments in
+func condaEnvsDirs() []string {
+	var dirs []string
+	if envsPath := os.Getenv("CONDA_ENVS_PATH"); envsPath != "" {
+		dirs = append(dirs, filepath.SplitList(envsPath)...)
+	}
+	// CONDA_EXE is <root>/bin/conda, or <root>/condabin/conda
+	if condaExe := os.Getenv("CONDA_EXE"); condaExe != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(filepath.Dir(condaExe)), "envs"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, root := range []string{"miniconda3", "anaconda3", "miniforge3", "mambaforge", ".conda"} {
+			dirs = append(dirs, filepath.Join(home, root, "envs"))
+		}
+	}
+	return append(dirs, "/opt/conda/envs")
+}
@@ -1,18 +1,19 @@
 package docprocessing
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"maps"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
 )
 
 // processDocument processes the document using the Python wrapper
@@ -23,6 +24,23 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 		return nil, fmt.Errorf("failed to resolve source path: %w", err)
 	}
 
+	if t.config.ServeURL != "" {
+		return t.processRemote(req, sourcePath)
+	}
+	if t.config.Native {
+		return t.processNative(req, sourcePath)
+	}
+
+	// Choose the Python for the document's project
+	documentDir := ""
+	if filepath.IsAbs(sourcePath) {
+		documentDir = filepath.Dir(sourcePath)
+	}
+	python, err := ResolvePythonEnvironment(documentDir, req.PythonPath, t.config.PythonPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get and validate script path
 	scriptPath := t.config.GetScriptPath()
 	// Security: Check file access for script path
@@ -67,6 +85,10 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 		}
 	}
 
+	if req.TableFormat != "" {
+		args = append(args, "--table-format", string(req.TableFormat))
+	}
+
 	if req.VisionMode != "" && req.VisionMode != VisionModeStandard {
 		args = append(args, "--vision-mode", string(req.VisionMode))
 	}
@@ -87,11 +109,23 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 		args = append(args, "--convert-diagrams-to-mermaid")
 	}
 
+	// Figures are written to the image directory, linked relative to the saved file
+	if req.ImageDir != "" {
+		args = append(args, "--image-dir", req.ImageDir)
+		if req.imageLinkDir != "" {
+			args = append(args, "--link-dir", req.imageLinkDir)
+		}
+	}
+
 	// Auto-enable image extraction when saving to file or extract_images is true
 	if t.shouldSaveToFile(req) || req.ExtractImages {
 		args = append(args, "--extract-images")
 	}
 
+	// The script falls back to the best available device if the one requested is not available
+	device := cmp.Or(req.HardwareAcceleration, t.config.HardwareAcceleration, HardwareAccelerationAuto)
+	args = append(args, "--device", string(device))
+
 	// Determine timeout
 	timeout := t.config.Timeout
 	if req.Timeout != nil {
@@ -102,41 +136,38 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, t.config.PythonPath, args...)
-
-	// Set working directory to the project root so relative paths work
-	if cwd, err := os.Getwd(); err == nil {
-		cmd.Dir = cwd
-	}
-
 	// Set up environment with certificate configuration and VLM variables
-	cmd.Env = os.Environ() // Start with current environment
-	certEnv := t.config.GetCertificateEnvironment()
-	cmd.Env = append(cmd.Env, certEnv...)
+	env := os.Environ() // Start with current environment
+	env = append(env, t.config.GetCertificateEnvironment()...)
+	env = append(env, t.getVLMEnvironmentVariables()...)
+	if t.config.MaxMemoryLimit > 0 {
+		// The script also limits its own memory, which covers platforms where the supervisor cannot
+		env = append(env, fmt.Sprintf("DOCLING_MAX_MEMORY_LIMIT=%d", t.config.MaxMemoryLimit))
+	}
 
-	// Add VLM Pipeline environment variables
-	vlmEnv := t.getVLMEnvironmentVariables()
-	cmd.Env = append(cmd.Env, vlmEnv...)
+	// Set working directory to the project root so relative paths work
+	cwd, _ := os.Getwd()
 
-	// Apply resource limits to the Python subprocess
-	if err := setProcessResourceLimits(cmd, t.config.MaxMemoryLimit); err != nil {
-		return nil, fmt.Errorf("failed to set process resource limits: %w", err)
+	spec := supervisor.Spec{
+		Name:   "docling",
+		Path:   python.Path,
+		Args:   args,
+		Dir:    cwd,
+		Env:    env,
+		Limits: supervisor.Limits{MemoryBytes: t.config.MaxMemoryLimit},
+		// The converted document is returned whole, so its output is not capped
+		MaxStdout: -1,
 	}
 
-	// Capture both stdout and stderr for better debugging
-	var stdout, stderr strings.Builder
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
 	// Log the command being executed for debugging
-	cmdStr := fmt.Sprintf("%s %s", t.config.PythonPath, strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", python.Path, strings.Join(args, " "))
 
 	// Execute the command
-	err = cmd.Run()
-
-	// Get the outputs
-	stdoutStr := stdout.String()
-	stderrStr := stderr.String()
+	var stdoutStr, stderrStr string
+	result, err := supervisor.Run(ctx, spec)
+	if result != nil {
+		stdoutStr, stderrStr = result.Stdout, result.Stderr
+	}
 
 	// Log outputs for debugging (but not to stdout/stderr to avoid MCP protocol issues)
 	// Write to a debug log file instead
@@ -154,7 +185,7 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 			_, _ = fmt.Fprintf(debugFile, "[%s] Stderr: %s\n", time.Now().Format("2006-01-02 15:04:05"), stderrStr)
 		}
 		_, _ = fmt.Fprintf(debugFile, "[%s] Environment Variables:\n", time.Now().Format("2006-01-02 15:04:05"))
-		for _, env := range cmd.Env {
+		for _, env := range spec.Env {
 			if strings.HasPrefix(env, "DOCLING_") {
 				_, _ = fmt.Fprintf(debugFile, "  %s\n", env)
 			}
@@ -211,6 +242,7 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 	if procInfo, ok := pythonResult["processing_info"].(map[string]any); ok {
 		response.ProcessingInfo = t.parseProcessingInfo(procInfo)
 	}
+	response.ProcessingInfo.Python = python
 
 	// Extract diagrams if available
 	if diagramsData, ok := pythonResult["diagrams"].([]any); ok {
@@ -222,6 +254,11 @@ func (t *DocumentProcessorTool) processDocument(req *DocumentProcessingRequest)
 		response.Images = t.parseImages(imagesData)
 	}
 
+	// Extract tables if requested
+	if tablesData, ok := pythonResult["tables"].([]any); ok {
+		response.Tables = t.parseTables(tablesData)
+	}
+
 	// Enhance diagrams with LLM if requested and configured
 	if req.GenerateDiagrams && len(response.Diagrams) > 0 {
 		enhancedDiagrams, err := t.enhanceDiagramsWithLLM(response.Diagrams)
@@ -285,6 +322,9 @@ func (t *DocumentProcessorTool) parseProcessingInfo(data map[string]any) Process
 	if hwAccel, ok := data["hardware_acceleration"].(string); ok {
 		info.HardwareAcceleration = HardwareAcceleration(hwAccel)
 	}
+	if fallback, ok := data["device_fallback"].(string); ok {
+		info.DeviceFallback = fallback
+	}
 	if ocrEnabled, ok := data["ocr_enabled"].(bool); ok {
 		info.OCREnabled = ocrEnabled
 	}
@@ -483,6 +523,95 @@ func (t *DocumentProcessorTool) parseImages(data []any) []ExtractedImage {
 	return images
 }
 
+// parseTables converts the Python table data to Go structs
+func (t *DocumentProcessorTool) parseTables(data []any) []ExtractedTable {
+	var tables []ExtractedTable
+
+	for _, item := range data {
+		tableData, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		table := ExtractedTable{}
+
+		if id, ok := tableData["id"].(string); ok {
+			table.ID = id
+		}
+		if caption, ok := tableData["caption"].(string); ok {
+			table.Caption = caption
+		}
+		if headers, ok := tableData["headers"].([]any); ok {
+			table.Headers = stringSlice(headers)
+		}
+		if rows, ok := tableData["rows"].([]any); ok {
+			for _, row := range rows {
+				if cells, ok := row.([]any); ok {
+					table.Rows = append(table.Rows, stringSlice(cells))
+				}
+			}
+		}
+		if pageNum, ok := tableData["page_number"].(float64); ok {
+			table.PageNumber = int(pageNum)
+		}
+		if markdown, ok := tableData["markdown"].(string); ok {
+			table.Markdown = markdown
+		}
+		if csv, ok := tableData["csv"].(string); ok {
+			table.CSV = csv
+		}
+		if html, ok := tableData["html"].(string); ok {
+			table.HTML = html
+		}
+		if confidence, ok := tableData["confidence"].(float64); ok {
+			table.Confidence = confidence
+		}
+		if source, ok := tableData["confidence_source"].(string); ok {
+			table.ConfidenceSource = source
+		}
+		if merged, ok := tableData["merged_cells"].(float64); ok {
+			table.MergedCells = int(merged)
+		}
+
+		// Parse bounding box
+		if bboxData, ok := tableData["bounding_box"].(map[string]any); ok {
+			bbox := &BoundingBox{}
+			if x, ok := bboxData["x"].(float64); ok {
+				bbox.X = x
+			}
+			if y, ok := bboxData["y"].(float64); ok {
+				bbox.Y = y
+			}
+			if width, ok := bboxData["width"].(float64); ok {
+				bbox.Width = width
+			}
+			if height, ok := bboxData["height"].(float64); ok {
+				bbox.Height = height
+			}
+			table.BoundingBox = bbox
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+// stringSlice converts a JSON array to strings, writing other values as text
+func stringSlice(values []any) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			result = append(result, v)
+		case nil:
+			result = append(result, "")
+		default:
+			result = append(result, fmt.Sprint(v))
+		}
+	}
+	return result
+}
+
 // enhanceDiagramsWithLLM enhances diagrams using external LLM analysis
 func (t *DocumentProcessorTool) enhanceDiagramsWithLLM(diagrams []ExtractedDiagram) ([]ExtractedDiagram, error) {
 	// Check if LLM is configured
@@ -0,0 +1,205 @@
+package docprocessing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+// Environment variables for converting documents with a docling-serve instance instead of local Python
+const (
+	EnvServeURL    = "DOCLING_SERVE_URL"     // Base URL of docling-serve, e.g. "http://localhost:5001"
+	EnvServeAPIKey = "DOCLING_SERVE_API_KEY" // Sent as X-Api-Key, for instances started with DOCLING_SERVE_API_KEY
+)
+
+// serveConvertPath is docling-serve's endpoint for converting documents given by URL or as base64
+const serveConvertPath = "/v1/convert/source"
+
+// maxServeErrorBody is how much of an error response from docling-serve is included in errors
+const maxServeErrorBody = 2048
+
+// serveRequest is the body of a docling-serve conversion request
+type serveRequest struct {
+	Options serveOptions  `json:"options"`
+	Sources []serveSource `json:"sources"`
+}
+
+// serveOptions are the conversion options docling-serve accepts that the tool's arguments map to
+type serveOptions struct {
+	ToFormats       []string `json:"to_formats"`
+	DoOCR           bool     `json:"do_ocr"`
+	OCRLang         []string `json:"ocr_lang,omitempty"`
+	TableMode       string   `json:"table_mode,omitempty"`
+	ImageExportMode string   `json:"image_export_mode"`
+	IncludeImages   bool     `json:"include_images"`
+	AbortOnError    bool     `json:"abort_on_error"`
+}
+
+// serveSource is a document for docling-serve to convert: a URL it fetches, or a file's content
+type serveSource struct {
+	Kind         string `json:"kind"`
+	URL          string `json:"url,omitempty"`
+	Base64String string `json:"base64_string,omitempty"`
+	Filename     string `json:"filename,omitempty"`
+}
+
+// serveResponse is docling-serve's reply to a conversion request
+type serveResponse struct {
+	Document struct {
+		Filename    string          `json:"filename"`
+		MDContent   string          `json:"md_content"`
+		JSONContent json.RawMessage `json:"json_content"`
+	} `json:"document"`
+	Status string `json:"status"`
+	Errors []struct {
+		ComponentType string `json:"component_type"`
+		ModuleName    string `json:"module_name"`
+		ErrorMessage  string `json:"error_message"`
+	} `json:"errors"`
+	ProcessingTime float64 `json:"processing_time"`
+}
+
+// serveURL returns the configured docling-serve URL, checking it is http or https
+func serveURL(raw string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%s must be an http or https URL", EnvServeURL)
+	}
+	return strings.TrimSuffix(parsed.String(), "/"), nil
+}
+
+// processRemote converts a document with docling-serve. Local files are sent as base64, and URLs are
+// passed on for docling-serve to fetch.
+func (t *DocumentProcessorTool) processRemote(req *DocumentProcessingRequest, sourcePath string) (*DocumentProcessingResponse, error) {
+	body := serveRequest{
+		Options: serveOptions{
+			ToFormats:       []string{"md"},
+			DoOCR:           req.EnableOCR,
+			TableMode:       string(req.TableFormerMode),
+			ImageExportMode: "placeholder",
+		},
+	}
+	if req.EnableOCR {
+		body.Options.OCRLang = req.OCRLanguages
+	}
+	if req.OutputFormat == OutputFormatJSON || req.OutputFormat == OutputFormatBoth {
+		body.Options.ToFormats = append(body.Options.ToFormats, "json")
+	}
+	// Embedded images are written to the image directory once converted
+	if req.PreserveImages || req.ExtractImages || t.shouldSaveToFile(req) || req.ImageDir != "" {
+		body.Options.ImageExportMode = "embedded"
+		body.Options.IncludeImages = true
+	}
+
+	if parsed, err := url.Parse(sourcePath); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		body.Sources = []serveSource{{Kind: "http", URL: sourcePath}}
+	} else {
+		data, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", sourcePath, err)
+		}
+		body.Sources = []serveSource{{Kind: "file", Base64String: base64.StdEncoding.EncodeToString(data), Filename: filepath.Base(sourcePath)}}
+	}
+
+	timeout := t.config.Timeout
+	if req.Timeout != nil {
+		timeout = *req.Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	result, err := t.callServe(ctx, body)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("processing timeout after %d seconds", timeout)
+		}
+		return nil, err
+	}
+
+	if result.Status != "success" && result.Status != "partial_success" {
+		messages := []string{"docling-serve could not convert the document"}
+		for _, e := range result.Errors {
+			messages = append(messages, fmt.Sprintf("%s %s: %s", e.ComponentType, e.ModuleName, e.ErrorMessage))
+		}
+		return &DocumentProcessingResponse{Source: req.Source, Error: strings.Join(messages, "; ")}, nil
+	}
+
+	response := &DocumentProcessingResponse{
+		Source:  req.Source,
+		Content: result.Document.MDContent,
+		Metadata: &DocumentMetadata{
+			Format:    strings.TrimPrefix(strings.ToLower(filepath.Ext(result.Document.Filename)), "."),
+			WordCount: len(strings.Fields(result.Document.MDContent)),
+		},
+		ProcessingInfo: ProcessingInfo{
+			ProcessingMode:   req.ProcessingMode,
+			ProcessingMethod: "docling-serve",
+			OCREnabled:       req.EnableOCR,
+			OCRLanguages:     body.Options.OCRLang,
+			ProcessingTime:   result.ProcessingTime,
+			Timestamp:        time.Now(),
+		},
+	}
+	if req.OutputFormat == OutputFormatJSON && len(result.Document.JSONContent) > 0 {
+		response.Content = string(result.Document.JSONContent)
+	}
+	if result.Status == "partial_success" {
+		response.ProcessingInfo.ProcessingMethod += ":partial"
+	}
+	return response, nil
+}
+
+// callServe sends a conversion request to docling-serve and decodes its reply
+func (t *DocumentProcessorTool) callServe(ctx context.Context, body serveRequest) (*serveResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal docling-serve request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.ServeURL+serveConvertPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docling-serve request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	if t.config.ServeAPIKey != "" {
+		httpReq.Header.Set("X-Api-Key", t.config.ServeAPIKey)
+	}
+
+	// Converted documents with embedded images can be large, so the response size is not limited
+	client := httpclient.New(httpclient.Options{MaxResponseBytes: -1})
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("docling-serve request to %s failed: %w", t.config.ServeURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxServeErrorBody))
+		message := fmt.Sprintf("docling-serve returned HTTP %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(detail)); text != "" {
+			message += ": " + text
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			message += fmt.Sprintf(" (check %s)", EnvServeAPIKey)
+		}
+		return nil, errors.New(message)
+	}
+
+	var result serveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode docling-serve response: %w", err)
+	}
+	return &result, nil
+}
@@ -2,6 +2,8 @@ package docprocessing
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -96,6 +98,14 @@ func (t *DocumentProcessorTool) parseRequest(args map[string]any) (*DocumentProc
 		req.CellMatching = &cellMatching
 	}
 
+	// Optional: table_format
+	if format, ok := args["table_format"].(string); ok && format != "" {
+		req.TableFormat = TableFormat(strings.ToLower(strings.TrimSpace(format)))
+		if !slices.Contains(TableFormats, req.TableFormat) {
+			return nil, fmt.Errorf("invalid table_format %q, must be one of: markdown, html, csv", format)
+		}
+	}
+
 	// Optional: vision_mode
 	if visionMode, ok := args["vision_mode"].(string); ok {
 		req.VisionMode = VisionProcessingMode(visionMode)
@@ -133,6 +143,44 @@ func (t *DocumentProcessorTool) parseRequest(args map[string]any) (*DocumentProc
 		req.ClearFileCache = clearCache
 	}
 
+	// Optional: chunk_level
+	if level, ok := args["chunk_level"].(float64); ok {
+		if level != float64(int(level)) || level < 1 || level > 6 {
+			return nil, fmt.Errorf("chunk_level must be a heading level from 1 to 6, got %v", level)
+		}
+		req.ChunkLevel = int(level)
+		if req.ReturnInlineOnly != nil && *req.ReturnInlineOnly {
+			return nil, fmt.Errorf("chunk_level saves each section as a file, so it cannot be used with return_inline_only")
+		}
+		if req.OutputFormat == OutputFormatJSON {
+			return nil, fmt.Errorf("chunk_level splits markdown, so it cannot be used with output_format json")
+		}
+	}
+
+	// Optional: image_dir
+	if dir, ok := args["image_dir"].(string); ok && strings.TrimSpace(dir) != "" {
+		req.ImageDir = filepath.Clean(strings.TrimSpace(dir))
+		if !filepath.IsAbs(req.ImageDir) {
+			return nil, fmt.Errorf("image_dir must be a fully qualified absolute path, got: %s", dir)
+		}
+	}
+
+	// Optional: describe_images
+	if describe, ok := args["describe_images"].(bool); ok && describe {
+		if !IsLLMConfigured() {
+			return nil, fmt.Errorf("describe_images needs a vision model, configured with %s, %s and %s", EnvOpenAIAPIBase, EnvOpenAIModel, EnvOpenAIAPIKey)
+		}
+		req.DescribeImages = true
+		if req.ReturnInlineOnly != nil && *req.ReturnInlineOnly && req.ImageDir == "" {
+			return nil, fmt.Errorf("describe_images describes the figures written to files, so it needs image_dir when used with return_inline_only")
+		}
+	}
+
+	// Optional: force
+	if force, ok := args["force"].(bool); ok {
+		req.Force = force
+	}
+
 	// Optional: extract_images
 	if extractImages, ok := args["extract_images"].(bool); ok {
 		req.ExtractImages = extractImages
@@ -143,6 +191,20 @@ func (t *DocumentProcessorTool) parseRequest(args map[string]any) (*DocumentProc
 		req.Debug = debug
 	}
 
+	// Optional: python_path
+	if pythonPath, ok := args["python_path"].(string); ok {
+		req.PythonPath = strings.TrimSpace(pythonPath)
+	}
+
+	// Optional: hardware_acceleration
+	if device, ok := args["hardware_acceleration"].(string); ok && device != "" {
+		mode, valid := ParseHardwareAcceleration(device)
+		if !valid {
+			return nil, fmt.Errorf("invalid hardware_acceleration %q, must be one of: auto, mps, cuda, cpu", device)
+		}
+		req.HardwareAcceleration = mode
+	}
+
 	// Apply profile settings first, then allow individual arguments to override
 	if req.Profile != "" {
 		t.applyProfile(req)
@@ -54,9 +54,12 @@ func (t *DocumentProcessorTool) shouldSaveToFile(req *DocumentProcessingRequest)
 	return true
 }
 
-// handleSaveToFile saves the converted content to the specified file and returns a success message
-func (t *DocumentProcessorTool) handleSaveToFile(savePath string, response *DocumentProcessingResponse, securityNotice string) (*mcp.CallToolResult, error) {
+// handleSaveToFile saves the converted content to the specified file, its sections to their own
+// files when chunk_level is set and its tables as CSV files when table_format is csv, and returns a
+// success message
+func (t *DocumentProcessorTool) handleSaveToFile(req *DocumentProcessingRequest, response *DocumentProcessingResponse, securityNotice string) (*mcp.CallToolResult, error) {
 	// Auto-generate save path if not provided
+	savePath := req.SaveTo
 	if savePath == "" {
 		generatedPath, err := t.generateSavePath(response.Source)
 		if err != nil {
@@ -99,6 +102,33 @@ func (t *DocumentProcessorTool) handleSaveToFile(savePath string, response *Docu
 		"processing_info": response.ProcessingInfo,
 	}
 
+	if req.ChunkLevel > 0 {
+		chunks, err := writeChunks(savePath, response.Source, response.Content, req.ChunkLevel)
+		if err != nil {
+			return nil, err
+		}
+		result["chunks"] = chunks
+	}
+
+	if len(response.Images) > 0 {
+		result["images"] = response.Images
+		if req.ImageDir != "" {
+			result["image_dir"] = req.ImageDir
+		}
+	}
+
+	if len(response.Tables) > 0 {
+		tables := response.Tables
+		if req.TableFormat == TableFormatCSV {
+			saved, err := writeTableFiles(savePath, tables)
+			if err != nil {
+				return nil, err
+			}
+			tables = saved
+		}
+		result["tables"] = tableSummaries(tables)
+	}
+
 	// Include document metadata if available
 	if response.Metadata != nil {
 		if metadata, ok := result["metadata"].(map[string]any); ok {
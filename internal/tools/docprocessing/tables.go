@@ -0,0 +1,63 @@
+package docprocessing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// tableFilePattern matches the CSV files written for a document's tables, so earlier tables can be removed
+var tableFilePattern = regexp.MustCompile(`^table-[0-9]+\.csv$`)
+
+// tableDir returns the directory a saved document's CSV tables are written to, beside the saved file
+func tableDir(savePath string) string {
+	return strings.TrimSuffix(savePath, filepath.Ext(savePath)) + "_tables"
+}
+
+// writeTableFiles saves each table of a converted document as a CSV file and returns the tables with
+// the file each was saved to. Tables from an earlier conversion of the document are replaced.
+func writeTableFiles(savePath string, tables []ExtractedTable) ([]ExtractedTable, error) {
+	dir := tableDir(savePath)
+	if err := security.CheckFileAccess(dir); err != nil {
+		return nil, fmt.Errorf("table directory access denied: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create table directory %s: %w", dir, err)
+	}
+
+	// Only files written as tables are removed, in case the directory holds anything else
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && tableFilePattern.MatchString(entry.Name()) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	saved := make([]ExtractedTable, len(tables))
+	for i, table := range tables {
+		table.File = filepath.Join(dir, fmt.Sprintf("table-%d.csv", i+1))
+		if err := os.WriteFile(table.File, []byte(strings.TrimRight(table.CSV, "\r\n")+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write table %s: %w", table.File, err)
+		}
+		saved[i] = table
+	}
+	return saved, nil
+}
+
+// tableSummaries returns the tables without their cells, which are already in the saved files, so the
+// result stays small while still reporting each table's confidence
+func tableSummaries(tables []ExtractedTable) []ExtractedTable {
+	summaries := make([]ExtractedTable, len(tables))
+	for i, table := range tables {
+		table.Rows, table.Markdown, table.CSV, table.HTML = nil, "", "", ""
+		summaries[i] = table
+	}
+	return summaries
+}
@@ -41,6 +41,18 @@ const (
 	TableFormerModeAccurate TableFormerMode = "accurate" // More accurate but slower table processing (default)
 )
 
+// TableFormat defines how tables are extracted from converted documents
+type TableFormat string
+
+const (
+	TableFormatMarkdown TableFormat = "markdown" // GitHub Flavoured Markdown tables (default)
+	TableFormatHTML     TableFormat = "html"     // HTML tables in the content, keeping merged cells
+	TableFormatCSV      TableFormat = "csv"      // Markdown tables in the content, each also saved as a CSV file
+)
+
+// TableFormats lists the table formats that may be requested
+var TableFormats = []TableFormat{TableFormatMarkdown, TableFormatHTML, TableFormatCSV}
+
 // VisionProcessingMode defines the vision model processing mode for enhanced document understanding
 type VisionProcessingMode string
 
@@ -69,6 +81,9 @@ const (
 	HardwareAccelerationCPU  HardwareAcceleration = "cpu"  // CPU-only processing
 )
 
+// HardwareAccelerationModes lists the hardware acceleration modes that may be requested
+var HardwareAccelerationModes = []HardwareAcceleration{HardwareAccelerationAuto, HardwareAccelerationMPS, HardwareAccelerationCUDA, HardwareAccelerationCPU}
+
 // ProcessingProfile defines preset configurations for common document processing scenarios
 type ProcessingProfile string
 
@@ -93,9 +108,14 @@ type DocumentProcessingRequest struct {
 	MaxFileSize              *int                 `json:"max_file_size,omitempty"`               // Maximum file size in MB
 	ReturnInlineOnly         *bool                `json:"return_inline_only,omitempty"`          // Return content inline in the response only. When false (default), the tool will save the processed content to a file in the same directory as the source file, and also return the content inline.
 	SaveTo                   string               `json:"save_to,omitempty"`                     // File path to save content when return_inline_only=false
+	ChunkLevel               int                  `json:"chunk_level,omitempty"`                 // Also save each section at this heading level or above as its own file
+	ImageDir                 string               `json:"image_dir,omitempty"`                   // Directory figures are written to, beside the saved file by default
+	DescribeImages           bool                 `json:"describe_images,omitempty"`             // Write alt text for figures with the vision model configured by DOCLING_VLM_*
 	ClearFileCache           bool                 `json:"clear_file_cache,omitempty"`            // Force clear all cache entries for this source file before processing
+	Force                    bool                 `json:"force,omitempty"`                       // Convert again even if a cached result exists, replacing it
 	TableFormerMode          TableFormerMode      `json:"table_former_mode,omitempty"`           // TableFormer processing mode for table structure recognition
 	CellMatching             *bool                `json:"cell_matching,omitempty"`               // Control table cell matching (true: use PDF cells, false: use predicted cells)
+	TableFormat              TableFormat          `json:"table_format,omitempty"`                // Extract tables in this format, each with a confidence score
 	VisionMode               VisionProcessingMode `json:"vision_mode,omitempty"`                 // Vision processing mode for enhanced document understanding
 	DiagramDescription       bool                 `json:"diagram_description,omitempty"`         // Enable diagram and chart description using vision models
 	ChartDataExtraction      bool                 `json:"chart_data_extraction,omitempty"`       // Enable data extraction from charts and graphs
@@ -104,6 +124,10 @@ type DocumentProcessingRequest struct {
 	GenerateDiagrams         bool                 `json:"generate_diagrams,omitempty"`           // Generate enhanced diagram analysis using external LLM (requires DOCLING_VLM_API_URL, DOCLING_VLM_MODEL, DOCLING_VLM_API_KEY environment variables)
 	ExtractImages            bool                 `json:"extract_images,omitempty"`              // Extract individual images, charts, and diagrams as base64-encoded data with AI recreation prompts
 	Debug                    bool                 `json:"debug,omitempty"`                       // Return debug information including environment variables (secrets masked)
+	PythonPath               string               `json:"python_path,omitempty"`                 // Python to convert with, overriding the one discovered
+	HardwareAcceleration     HardwareAcceleration `json:"hardware_acceleration,omitempty"`       // Device to convert with, overriding DOCLING_HARDWARE_ACCELERATION
+
+	imageLinkDir string // Directory figure links are relative to, that of the saved file, or empty for absolute links
 }
 
 // DocumentProcessingResponse represents the output from document processing
@@ -154,14 +178,19 @@ type ExtractedImage struct {
 
 // ExtractedTable represents a table extracted from the document
 type ExtractedTable struct {
-	ID          string       `json:"id"`                     // Unique table identifier
-	Caption     string       `json:"caption,omitempty"`      // Table caption if available
-	Headers     []string     `json:"headers,omitempty"`      // Column headers
-	Rows        [][]string   `json:"rows"`                   // Table data rows
-	PageNumber  int          `json:"page_number,omitempty"`  // Page number where table appears
-	BoundingBox *BoundingBox `json:"bounding_box,omitempty"` // Position on page
-	Markdown    string       `json:"markdown,omitempty"`     // Markdown representation
-	CSV         string       `json:"csv,omitempty"`          // CSV representation
+	ID               string       `json:"id"`                          // Unique table identifier
+	Caption          string       `json:"caption,omitempty"`           // Table caption if available
+	Headers          []string     `json:"headers,omitempty"`           // Column headers
+	Rows             [][]string   `json:"rows,omitempty"`              // Table data rows
+	PageNumber       int          `json:"page_number,omitempty"`       // Page number where table appears
+	BoundingBox      *BoundingBox `json:"bounding_box,omitempty"`      // Position on page
+	Markdown         string       `json:"markdown,omitempty"`          // Markdown representation
+	CSV              string       `json:"csv,omitempty"`               // CSV representation
+	HTML             string       `json:"html,omitempty"`              // HTML representation, keeping merged cells
+	File             string       `json:"file,omitempty"`              // Path of the CSV file the table was saved to
+	Confidence       float64      `json:"confidence"`                  // How reliably the table's structure was recognised, from 0 to 1
+	ConfidenceSource string       `json:"confidence_source,omitempty"` // docling, or structure when estimated from the table's shape
+	MergedCells      int          `json:"merged_cells,omitempty"`      // Cells spanning more than one row or column
 }
 
 // ExtractedDiagram represents a diagram extracted from the document
@@ -201,6 +230,7 @@ type ProcessingInfo struct {
 	ProcessingMode       ProcessingMode       `json:"processing_mode"`           // Mode used for processing
 	ProcessingMethod     string               `json:"processing_method"`         // Concise description of processing method used
 	HardwareAcceleration HardwareAcceleration `json:"hardware_acceleration"`     // Hardware acceleration used
+	DeviceFallback       string               `json:"device_fallback,omitempty"` // Why the requested device was not used, if it was not
 	VisionModel          string               `json:"vision_model,omitempty"`    // Vision model used (if any)
 	OCREnabled           bool                 `json:"ocr_enabled"`               // Whether OCR was enabled
 	OCRLanguages         []string             `json:"ocr_languages,omitempty"`   // OCR languages used
@@ -210,6 +240,9 @@ type ProcessingInfo struct {
 	CacheKey             string               `json:"cache_key,omitempty"`       // Cache key used
 	Timestamp            time.Time            `json:"timestamp"`                 // Processing timestamp
 	TokenUsage           *TokenUsage          `json:"token_usage,omitempty"`     // Token usage from external LLM (if available)
+	Python               *PythonEnvironment   `json:"python,omitempty"`          // Python the document was converted with
+	Fallback             string               `json:"fallback,omitempty"`        // Why a lower fidelity converter was used, if one was
+	AltTextError         string               `json:"alt_text_error,omitempty"`  // Why alt text could not be written for some figures
 }
 
 // TokenUsage represents token consumption from external LLM providers
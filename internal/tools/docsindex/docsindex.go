@@ -0,0 +1,272 @@
+package docsindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// DocsIndexTool builds a full-text index of documentation and searches it
+type DocsIndexTool struct{}
+
+// The docs_index tool's actions
+const (
+	actionSearch = "search"
+	actionIndex  = "index"
+	actionRemove = "remove"
+)
+
+// init registers the docs_index tool
+func init() {
+	registry.Register(&DocsIndexTool{})
+}
+
+// Definition returns the docs_index tool's definition for MCP registration
+func (t *DocsIndexTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"docs_index",
+		mcp.WithDescription(`Full-text search of documentation. Index a docs directory once, then search it to get the best matching sections ranked by relevance, each with its file, heading, line and a snippet with matching words in bold, so only the relevant parts need to be read.
+
+Actions:
+- search: find sections matching a query. Words are matched by their stem, so "configure" also finds "configuration". Use "quoted phrases" for exact wording and a trailing * for prefixes.
+- index: build or update the index of a path. Indexes Markdown and plain text files (.txt, .rst, .adoc, .org) by section, with titles, headings and front matter tags, and the Markdown that process_document saves beside converted PDFs and Office documents. Indexing again only reads files that have changed. The index is kept between sessions.
+- remove: remove a path and everything under it from the index.`),
+		mcp.WithString("action",
+			mcp.Description("Action to perform (default: search)"),
+			mcp.Enum(actionSearch, actionIndex, actionRemove),
+		),
+		mcp.WithString("query",
+			mcp.Description(`For search: words to search for, e.g. 'rate limit retry', '"connection pool" timeout' or 'auth*'`),
+		),
+		mcp.WithString("path",
+			mcp.Description("For index and remove: absolute path of a docs directory, or of one file. For search: only search files at or under this absolute path (default: everything indexed)"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("For search: only search files with this front matter tag, category or keyword"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("For search: most results to return (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithBoolean("rebuild",
+			mcp.Description("For index: index every file again instead of only those that changed (default: false)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // index and remove write the index database
+		mcp.WithDestructiveHintAnnotation(false), // Only changes the index, never the docs
+		mcp.WithIdempotentHintAnnotation(true),   // Indexing unchanged files again, or repeating a search, changes nothing
+		mcp.WithOpenWorldHintAnnotation(false),   // Reads local files and a local index only
+	)
+}
+
+// Execute runs the requested action
+func (t *DocsIndexTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionSearch
+	if value, ok := args["action"].(string); ok && value != "" {
+		action = value
+	}
+	switch action {
+	case actionSearch:
+		return searchDocs(ctx, logger, args)
+	case actionIndex, actionRemove:
+		return indexDocs(ctx, logger, args, action == actionRemove)
+	default:
+		return nil, fmt.Errorf("invalid action: %s. Must be one of: search, index, remove", action)
+	}
+}
+
+// indexDocs indexes the path, or removes it from the index, and reports what changed
+func indexDocs(ctx context.Context, logger *logrus.Logger, args map[string]any, remove bool) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	root, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	rebuild, _ := args["rebuild"].(bool)
+
+	db, err := openIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	var result *IndexResult
+	if remove {
+		logger.WithField("path", root).Debug("Removing docs from the index")
+		if result, err = removePath(ctx, db, root); err != nil {
+			return nil, err
+		}
+	} else {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access path: %w", err)
+		}
+		logger.WithFields(logrus.Fields{"path": root, "rebuild": rebuild}).Debug("Indexing docs")
+		if result, err = indexPathInto(ctx, db, root, info.IsDir(), rebuild); err != nil {
+			return nil, err
+		}
+		if result.Indexed+result.Unchanged == 0 {
+			result.Warnings = append(result.Warnings, "No Markdown or text files were found.")
+		}
+	}
+	if result.Files, result.Sections, err = totals(ctx, db, root); err != nil {
+		return nil, err
+	}
+	if result.Roots, err = roots(ctx, db); err != nil {
+		return nil, err
+	}
+	return respond(root, result)
+}
+
+// searchDocs searches the index and returns the best matching sections
+func searchDocs(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("missing required parameter: query")
+	}
+	q := searchQuery{terms: parseTerms(query), limit: defaultLimit}
+	if len(q.terms) == 0 {
+		return nil, fmt.Errorf("query must contain at least one word, got: %s", query)
+	}
+	if path, _ := args["path"].(string); strings.TrimSpace(path) != "" {
+		resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+		if err != nil {
+			return nil, err
+		}
+		q.path = resolved
+	}
+	q.tag, _ = args["tag"].(string)
+	q.tag = strings.TrimSpace(q.tag)
+	if value, ok := args["limit"].(float64); ok {
+		if value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("limit must be a whole number of at least 1, got: %v", value)
+		}
+		q.limit = min(int(value), maxLimit)
+	}
+
+	db, err := openIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	logger.WithFields(logrus.Fields{"query": query, "path": q.path}).Debug("Searching docs")
+	result, err := search(ctx, db, q)
+	if err != nil {
+		return nil, err
+	}
+	result.Query = query
+	if len(result.Results) == 0 {
+		indexed, err := roots(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		if len(indexed) == 0 {
+			result.Warnings = append(result.Warnings, "Nothing has been indexed yet. Use action index on a docs directory first.")
+		}
+	}
+	return respond(q.path, result)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the docs_index tool
+func (t *DocsIndexTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Answering questions about a project from its documentation, finding where a topic is documented, or checking what the docs say before changing behaviour they describe. Index the docs at the start of work on a project with substantial documentation, and again after they change; indexing again is quick, as only changed files are read.",
+		WhenNotToUse: "Searching source code - use grep or the client's own search. Documentation of third-party libraries - use resolve_library_id and get_library_documentation. Small docs directories that are quicker to read directly. Converting PDFs and Office documents - use process_document first, then index its output.",
+		CommonPatterns: []string{
+			"Index a project's docs directory once, then search it for each question",
+			"Search with a few distinctive words, then read the file from the returned line for the full section",
+			"Convert PDFs or Word documents with process_document, which saves a .md beside each, then index the directory so they are searchable",
+			"Index again after editing docs; the result's unchanged count shows how many files were skipped",
+			"Narrow results to one project with path when several are indexed",
+			"Use a quoted phrase for exact wording, such as an error message",
+		},
+		ParameterDetails: map[string]string{
+			"action":  "search (default) needs query. index and remove need path. remove takes the path's files out of the index without touching them on disk.",
+			"query":   "Terms are matched by stem and case-insensitively. Sections containing every term are returned first; when none does, sections with any of them are returned and match is \"any\". Titles, headings and tags rank higher than body text.",
+			"path":    "For index, Markdown (.md, .markdown, .mdx) and text (.txt, .rst, .adoc, .asciidoc, .org) files are indexed, leaving out hidden directories and dependency or build directories such as node_modules, vendor and dist. Documents such as PDFs with no .md beside them are listed as unconverted. For search, limits results to files at or under the path.",
+			"tag":     "Matches a word in the tags, categories or keywords front matter fields.",
+			"rebuild": "Reads every file again, for example after upgrading when the way files are split into sections may have changed.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Index a project's documentation",
+				Arguments:      map[string]any{"action": "index", "path": "/home/user/project/docs"},
+				ExpectedResult: "Counts of files indexed, unchanged and removed, totals for the path and the list of indexed paths",
+			},
+			{
+				Description:    "Find how to configure retries",
+				Arguments:      map[string]any{"query": "retry backoff configuration"},
+				ExpectedResult: "Ranked sections with their file, heading, line and a snippet with matching words in bold",
+			},
+			{
+				Description:    "Search one project's docs for an exact phrase",
+				Arguments:      map[string]any{"query": `"connection refused"`, "path": "/home/user/project/docs", "limit": 5},
+				ExpectedResult: "Up to five sections containing the phrase",
+			},
+			{
+				Description:    "Remove a path from the index",
+				Arguments:      map[string]any{"action": "remove", "path": "/home/user/old-project/docs"},
+				ExpectedResult: "The number of files removed",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Results are marked stale",
+				Solution: "The files changed after they were indexed. Index the path again; only the changed files are read.",
+			},
+			{
+				Problem:  "Nothing is found",
+				Solution: "Check the path was indexed (action index lists the indexed roots), try fewer or different words, or use a trailing * for word prefixes.",
+			},
+			{
+				Problem:  "A PDF or Word document is not found",
+				Solution: "Only text is indexed. Convert the document with process_document, which saves a .md beside it, then index the directory again.",
+			},
+			{
+				Problem:  "Some files were not indexed because the security policy denies access to them",
+				Solution: "The security configuration blocks those paths. The user can change this in their MCP DevTools configuration.",
+			},
+		},
+	}
+}
+
+// respond encodes a result as JSON, scanned as it quotes the indexed docs
+func respond(path string, result any) (*mcp.CallToolResult, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	sourceCtx := security.SourceContext{
+		Tool:        "docs_index",
+		URL:         "file://" + path,
+		ContentType: "text",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
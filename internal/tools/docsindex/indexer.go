@@ -0,0 +1,312 @@
+package docsindex
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/markdown"
+)
+
+const (
+	// maxFiles is the most files indexed under one path
+	maxFiles = 5000
+	// maxFileBytes is the largest file indexed
+	maxFileBytes = 2 * 1024 * 1024
+	// chunkLines is roughly how many lines of plain text are indexed together as a section
+	chunkLines = 40
+	// maxUnconverted is the most unconverted documents listed
+	maxUnconverted = 50
+)
+
+var (
+	// textExtensions are plain text files indexed in chunks of paragraphs
+	textExtensions = []string{".txt", ".rst", ".adoc", ".asciidoc", ".org"}
+
+	// convertedExtensions are documents process_document converts to Markdown, which it saves
+	// beside them with a .md extension
+	convertedExtensions = []string{".pdf", ".docx", ".doc", ".pptx", ".ppt", ".xlsx", ".xls", ".rtf"}
+
+	// skippedDirs are dependency and build output directories not indexed
+	skippedDirs = []string{"node_modules", "vendor", "dist", "build", "target", "__pycache__", "venv", "coverage"}
+
+	// tagFields are the front matter fields whose values are indexed as tags
+	tagFields = []string{"tags", "categories", "keywords"}
+)
+
+// document is a file prepared for indexing
+type document struct {
+	title    string
+	source   string
+	tags     string
+	sections []markdown.Section
+}
+
+// indexPathInto brings the index up to date with the files at root, adding new and changed files
+// and removing deleted ones, or replacing all of them when rebuilding
+func indexPathInto(ctx context.Context, db *sql.DB, root string, isDir, rebuild bool) (*IndexResult, error) {
+	result := &IndexResult{Path: root}
+	files := []string{root}
+	if isDir {
+		var err error
+		files, result.Unconverted, result.Warnings, err = findFiles(root)
+		if err != nil {
+			return nil, err
+		}
+	} else if !isIndexable(root) {
+		return nil, fmt.Errorf("%s is not a Markdown or text file; convert documents such as PDFs with process_document first", root)
+	}
+	known, err := indexedFiles(ctx, db, root)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Could not read %s: %v", file, err))
+			continue
+		}
+		current := stamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+		previous, indexed := known[file]
+		delete(known, file)
+		if indexed && previous == current && !rebuild {
+			result.Unchanged++
+			continue
+		}
+		if indexed {
+			if err := removeFile(ctx, tx, file); err != nil {
+				return nil, err
+			}
+		}
+		if info.Size() > maxFileBytes {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s was not indexed because it is larger than %d MB", file, maxFileBytes/1024/1024))
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Could not read %s: %v", file, err))
+			continue
+		}
+		if err := insertFile(ctx, tx, file, current, prepare(file, string(data))); err != nil {
+			return nil, err
+		}
+		result.Indexed++
+	}
+	// Files left were indexed before but are gone
+	for file := range known {
+		if err := removeFile(ctx, tx, file); err != nil {
+			return nil, err
+		}
+		result.Removed++
+	}
+
+	// Paths inside this one are covered by it from now on
+	if _, err := tx.ExecContext(ctx, `DELETE FROM roots WHERE path LIKE ? ESCAPE '\'`, underPath(root)); err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO roots (path, indexed_at) VALUES (?, ?)`, root, time.Now().Unix()); err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	return result, nil
+}
+
+// removePath takes a path and everything indexed under it out of the index
+func removePath(ctx context.Context, db *sql.DB, root string) (*IndexResult, error) {
+	known, err := indexedFiles(ctx, db, root)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for file := range known {
+		if err := removeFile(ctx, tx, file); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM roots WHERE path = ? OR path LIKE ? ESCAPE '\'`, root, underPath(root)); err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update the docs index: %w", err)
+	}
+	return &IndexResult{Path: root, Removed: len(known)}, nil
+}
+
+// insertFile records a file and indexes its sections
+func insertFile(ctx context.Context, tx *sql.Tx, path string, current stamp, doc *document) error {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO files (path, title, source, mod_time, size) VALUES (?, ?, ?, ?, ?)`,
+		path, doc.title, doc.source, current.modTime, current.size); err != nil {
+		return fmt.Errorf("failed to index %s: %w", path, err)
+	}
+	for _, s := range doc.sections {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO sections (title, heading, tags, body, path, line) VALUES (?, ?, ?, ?, ?, ?)`,
+			doc.title, s.Heading, doc.tags, s.Text, path, s.Line); err != nil {
+			return fmt.Errorf("failed to index %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// prepare splits a file into sections and reads its title and tags, from front matter where it
+// has any
+func prepare(path, content string) *document {
+	name := filepath.Base(path)
+	doc := &document{title: strings.TrimSuffix(name, filepath.Ext(name)), source: convertedFrom(path)}
+	if !markdown.IsMarkdownFile(path) {
+		doc.sections = chunks(content)
+		return doc
+	}
+
+	doc.sections = markdown.Sections(content)
+	if i := slices.IndexFunc(doc.sections, func(s markdown.Section) bool { return s.Level == 1 }); i >= 0 {
+		doc.title = doc.sections[i].Heading
+	}
+	// Front matter that does not parse is left out, as the rest of the file is still worth finding
+	fields, _ := markdown.FrontMatter(content)
+	if title, ok := fields["title"].(string); ok && strings.TrimSpace(title) != "" {
+		doc.title = strings.TrimSpace(title)
+	}
+	var tags []string
+	for _, field := range tagFields {
+		switch value := fields[field].(type) {
+		case string:
+			tags = append(tags, strings.Split(value, ",")...)
+		case []any:
+			for _, item := range value {
+				tags = append(tags, fmt.Sprint(item))
+			}
+		}
+	}
+	doc.tags = strings.Join(tags, ", ")
+	for _, field := range []string{"description", "summary"} {
+		if description, ok := fields[field].(string); ok && description != "" {
+			// The description is found with the start of the document
+			if len(doc.sections) == 0 {
+				doc.sections = []markdown.Section{{Line: 1}}
+			}
+			doc.sections[0].Text = strings.TrimSpace(description + "\n\n" + doc.sections[0].Text)
+			break
+		}
+	}
+	return doc
+}
+
+// chunks splits plain text into sections of whole paragraphs, each about chunkLines long
+func chunks(content string) []markdown.Section {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var sections []markdown.Section
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		end := i == len(lines)
+		if !end && (i-start < chunkLines || strings.TrimSpace(lines[i]) != "") {
+			continue
+		}
+		if text := strings.TrimSpace(strings.Join(lines[start:i], "\n")); text != "" {
+			sections = append(sections, markdown.Section{Line: start + 1, Text: text})
+		}
+		start = i + 1
+	}
+	return sections
+}
+
+// convertedFrom returns the document a Markdown file was converted from by process_document,
+// which saves its output beside the document with a .md extension, or "" when there is none
+func convertedFrom(path string) string {
+	if strings.ToLower(filepath.Ext(path)) != ".md" {
+		return ""
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range convertedExtensions {
+		if info, err := os.Stat(base + ext); err == nil && info.Mode().IsRegular() {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// findFiles returns the Markdown and text files under root, leaving out hidden and dependency
+// directories, with the documents that have not been converted to Markdown and warnings for files
+// left out
+func findFiles(root string) ([]string, []string, []string, error) {
+	var files, unconverted, warnings []string
+	skipped, unlisted := 0, 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			warnings = append(warnings, fmt.Sprintf("Could not read %s: %v", path, err))
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if slices.Contains(convertedExtensions, strings.ToLower(filepath.Ext(name))) {
+			if _, err := os.Stat(strings.TrimSuffix(path, filepath.Ext(path)) + ".md"); err != nil {
+				if len(unconverted) < maxUnconverted {
+					unconverted = append(unconverted, path)
+				} else {
+					unlisted++
+				}
+			}
+			return nil
+		}
+		if !isIndexable(name) {
+			return nil
+		}
+		if security.CheckFileAccess(path) != nil {
+			skipped++
+			return nil
+		}
+		if len(files) == maxFiles {
+			warnings = append(warnings, fmt.Sprintf("Only the first %d files were indexed. Index subdirectories separately to include the rest.", maxFiles))
+			return filepath.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if skipped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files were not indexed because the security policy denies access to them.", skipped))
+	}
+	if len(unconverted) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Documents with no Markdown conversion are not searchable (%d found). Convert them with process_document, which saves a .md beside each, then index again.", len(unconverted)+unlisted))
+	}
+	return files, unconverted, warnings, nil
+}
+
+// isIndexable reports whether a file is Markdown or plain text
+func isIndexable(name string) bool {
+	return markdown.IsMarkdownFile(name) || slices.Contains(textExtensions, strings.ToLower(filepath.Ext(name)))
+}
@@ -0,0 +1,166 @@
+package docsindex
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+// searchQuery holds what to search for and where
+type searchQuery struct {
+	terms []string
+	path  string
+	tag   string
+	limit int
+}
+
+// parseTerms splits a query into words and "quoted phrases", keeping a trailing * on a word as a
+// prefix match
+func parseTerms(query string) []string {
+	var terms []string
+	for i, part := range strings.Split(query, `"`) {
+		// Odd parts were inside quotes
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				terms = append(terms, `"`+phrase+`"`)
+			}
+			continue
+		}
+		for word := range strings.FieldsSeq(part) {
+			prefix := strings.HasSuffix(word, "*")
+			word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+			if word == "" {
+				continue
+			}
+			term := `"` + word + `"`
+			if prefix {
+				term += "*"
+			}
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// search returns the sections that contain every term, or when none does, those that contain any
+func search(ctx context.Context, db *sql.DB, q searchQuery) (*SearchResult, error) {
+	result := &SearchResult{Match: "all", Results: []Hit{}}
+	hits, err := find(ctx, db, q, " AND ")
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 && len(q.terms) > 1 {
+		result.Match = "any"
+		if hits, err = find(ctx, db, q, " OR "); err != nil {
+			return nil, err
+		}
+	}
+	if hits != nil {
+		result.Results = hits
+	}
+	if len(result.Results) > 0 && result.Match == "any" {
+		result.Warnings = append(result.Warnings, "No section contains every term, so these contain only some of them.")
+	}
+	if slices.ContainsFunc(result.Results, func(hit Hit) bool { return hit.Stale }) {
+		result.Warnings = append(result.Warnings, "Some files have changed since they were indexed. Index their path again to update the index.")
+	}
+	return result, nil
+}
+
+// find runs the query with its terms joined by operator
+func find(ctx context.Context, db *sql.DB, q searchQuery, operator string) ([]Hit, error) {
+	match := "(" + strings.Join(q.terms, operator) + ")"
+	if q.tag != "" {
+		match += ` AND tags : "` + strings.ReplaceAll(q.tag, `"`, `""`) + `"`
+	}
+	// Title, heading and tag matches count for more than ones in the body
+	query := `SELECT s.path, f.title, f.source, s.heading, s.line, f.mod_time, f.size,
+	snippet(sections, 3, '**', '**', '…', 24), -bm25(sections, 8.0, 4.0, 4.0, 1.0)
+FROM sections s JOIN files f ON f.path = s.path
+WHERE sections MATCH ?`
+	params := []any{match}
+	if q.path != "" {
+		query += ` AND (s.path = ? OR s.path LIKE ? ESCAPE '\')`
+		params = append(params, q.path, underPath(q.path))
+	}
+	query += ` ORDER BY bm25(sections, 8.0, 4.0, 4.0, 1.0) LIMIT ?`
+	// Hits the security policy now denies are dropped, so ask for a few more
+	params = append(params, q.limit*2)
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the docs index: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var hits []Hit
+	for rows.Next() && len(hits) < q.limit {
+		var hit Hit
+		var indexed stamp
+		if err := rows.Scan(&hit.Path, &hit.Title, &hit.Source, &hit.Heading, &hit.Line, &indexed.modTime, &indexed.size, &hit.Snippet, &hit.Score); err != nil {
+			return nil, fmt.Errorf("failed to read the docs index: %w", err)
+		}
+		if workspace.CheckFileAccess(ctx, hit.Path) != nil {
+			continue
+		}
+		hit.Score = math.Round(hit.Score*100) / 100
+		hit.Snippet = strings.Join(strings.Fields(hit.Snippet), " ")
+		info, err := os.Stat(hit.Path)
+		hit.Stale = err != nil || info.ModTime().UnixNano() != indexed.modTime || info.Size() != indexed.size
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// roots returns the paths indexed, with how many files each has
+func roots(ctx context.Context, db *sql.DB) ([]Root, error) {
+	rows, err := db.QueryContext(ctx, `SELECT path, indexed_at FROM roots ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the docs index: %w", err)
+	}
+	result := []Root{}
+	for rows.Next() {
+		var root Root
+		var indexedAt int64
+		if err := rows.Scan(&root.Path, &indexedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to read the docs index: %w", err)
+		}
+		root.IndexedAt = time.Unix(indexedAt, 0).UTC().Format(time.RFC3339)
+		result = append(result, root)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the docs index: %w", err)
+	}
+	// The connection is free again once the rows are closed
+	for i := range result {
+		if result[i].Files, _, err = totals(ctx, db, result[i].Path); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// totals returns the number of files and sections indexed at or under path
+func totals(ctx context.Context, db *sql.DB, path string) (files, sections int, err error) {
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM files WHERE path = ? OR path LIKE ? ESCAPE '\'`, path, underPath(path)).Scan(&files); err != nil {
+		return 0, 0, fmt.Errorf("failed to read the docs index: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sections WHERE path = ? OR path LIKE ? ESCAPE '\'`, path, underPath(path)).Scan(&sections); err != nil {
+		return 0, 0, fmt.Errorf("failed to read the docs index: %w", err)
+	}
+	return files, sections, nil
+}
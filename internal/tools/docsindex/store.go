@@ -0,0 +1,121 @@
+package docsindex
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// IndexPathEnvVar overrides the default ~/.mcp-devtools/docs_index.db location
+	IndexPathEnvVar = "DOCS_INDEX_PATH"
+
+	// schema creates the tables: files records what was indexed so unchanged files are skipped,
+	// roots the paths indexed, and sections the full-text index of each file's sections
+	schema = `
+CREATE TABLE IF NOT EXISTS roots (
+	path TEXT PRIMARY KEY,
+	indexed_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS files (
+	path TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	source TEXT NOT NULL,
+	mod_time INTEGER NOT NULL,
+	size INTEGER NOT NULL
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS sections USING fts5(
+	title, heading, tags, body,
+	path UNINDEXED, line UNINDEXED,
+	tokenize = 'porter unicode61'
+);`
+)
+
+// indexPath returns the index database's location
+func indexPath() (string, error) {
+	if path := os.Getenv(IndexPathEnvVar); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "docs_index.db"), nil
+}
+
+// openIndex opens the index database, creating it readable only by the user if it does not exist
+func openIndex(ctx context.Context) (*sql.DB, error) {
+	path, err := indexPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	// SQLite gives its journal files the database's permissions, so create it first
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docs index %s: %w", path, err)
+	}
+	_ = file.Close()
+
+	uri := "file:" + strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path) +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docs index %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to prepare docs index %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// underPath returns a LIKE pattern matching the paths inside a directory
+func underPath(path string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.TrimSuffix(path, string(filepath.Separator)))
+	return escaped + string(filepath.Separator) + "%"
+}
+
+// removeFile deletes a file's sections and record
+func removeFile(ctx context.Context, tx *sql.Tx, path string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sections WHERE path = ?`, path); err != nil {
+		return fmt.Errorf("failed to remove %s from the index: %w", path, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM files WHERE path = ?`, path); err != nil {
+		return fmt.Errorf("failed to remove %s from the index: %w", path, err)
+	}
+	return nil
+}
+
+// stamp identifies a version of a file by its modification time and size
+type stamp struct {
+	modTime int64
+	size    int64
+}
+
+// indexedFiles returns the stamp recorded for each file at or under path
+func indexedFiles(ctx context.Context, db *sql.DB, path string) (map[string]stamp, error) {
+	rows, err := db.QueryContext(ctx, `SELECT path, mod_time, size FROM files WHERE path = ? OR path LIKE ? ESCAPE '\'`, path, underPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the docs index: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	files := map[string]stamp{}
+	for rows.Next() {
+		var file string
+		var s stamp
+		if err := rows.Scan(&file, &s.modTime, &s.size); err != nil {
+			return nil, fmt.Errorf("failed to read the docs index: %w", err)
+		}
+		files[file] = s
+	}
+	return files, rows.Err()
+}
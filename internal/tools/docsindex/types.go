@@ -0,0 +1,53 @@
+package docsindex
+
+// IndexResult reports the changes indexing made and what is indexed
+type IndexResult struct {
+	Path string `json:"path"`
+	// Indexed is the number of files added or updated, Unchanged those already up to date and
+	// Removed those taken out because they were deleted, or because the path was removed
+	Indexed   int `json:"indexed"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+	// Files and Sections are the totals now indexed under the path
+	Files    int `json:"files"`
+	Sections int `json:"sections"`
+	// Unconverted lists documents, such as PDFs, with no Markdown conversion beside them to index
+	Unconverted []string `json:"unconverted,omitempty"`
+	// Roots are all the paths indexed, which the search action searches
+	Roots    []Root   `json:"roots"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Root is a path that has been indexed
+type Root struct {
+	Path      string `json:"path"`
+	Files     int    `json:"files"`
+	IndexedAt string `json:"indexed_at"`
+}
+
+// SearchResult is the sections that best match a query
+type SearchResult struct {
+	Query string `json:"query"`
+	// Match is "all" when results contain every term, or "any" when no section did and results
+	// contain some of them
+	Match    string   `json:"match"`
+	Results  []Hit    `json:"results"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Hit is a section that matches a query
+type Hit struct {
+	Path string `json:"path"`
+	// Source is the document the file was converted from, such as a PDF beside it
+	Source  string `json:"source,omitempty"`
+	Title   string `json:"title"`
+	Heading string `json:"heading,omitempty"`
+	// Line is the 1-based line the section starts on
+	Line int `json:"line"`
+	// Snippet is the part of the section that best matches, with matching words in **bold**
+	Snippet string `json:"snippet"`
+	// Score ranks the hit, higher being a better match
+	Score float64 `json:"score"`
+	// Stale is set when the file has changed or been deleted since it was indexed
+	Stale bool `json:"stale,omitempty"`
+}
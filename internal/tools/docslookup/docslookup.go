@@ -0,0 +1,215 @@
+package docslookup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cacheTTL          = 1 * time.Hour
+	defaultMaxLength  = 10000
+	minMaxLength      = 500
+	maxMaxLength      = 100000
+	sourceGo          = "go"
+	sourceMDN         = "mdn"
+	sourcePython      = "python"
+	sourceRust        = "rust"
+	sourceReadTheDocs = "readthedocs"
+)
+
+// DocsLookupTool returns a section of a package's API documentation as markdown
+type DocsLookupTool struct {
+	client packageversions.HTTPClient
+}
+
+// lookup is a request for documentation from one source
+type lookup struct {
+	pkg     string
+	symbol  string
+	version string
+}
+
+// init registers the docs lookup tool
+func init() {
+	registry.Register(&DocsLookupTool{})
+}
+
+// NewDocsLookupTool creates a docs lookup tool that uses the given HTTP client
+func NewDocsLookupTool(client packageversions.HTTPClient) *DocsLookupTool {
+	return &DocsLookupTool{client: client}
+}
+
+// httpClient returns the configured client, defaulting to the shared rate-limited client
+func (t *DocsLookupTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return packageversions.DefaultHTTPClient
+	}
+	return t.client
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *DocsLookupTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"docs_lookup",
+		mcp.WithDescription(`Look up API documentation for a package or symbol and return just that section as markdown.
+
+Reads each site's own structure rather than searching the web: pkg.go.dev for Go, MDN for web platform APIs, PyPI for Python projects, docs.rs for Rust crates and Read the Docs (or any Sphinx site) for project documentation. Omit symbol for the package overview and a list of its identifiers.`),
+		mcp.WithString("source",
+			mcp.Required(),
+			mcp.Description("Where to look: go (pkg.go.dev), mdn (web APIs, JavaScript, CSS, HTML), python (PyPI), rust (docs.rs) or readthedocs"),
+			mcp.Enum(sourceGo, sourceMDN, sourcePython, sourceRust, sourceReadTheDocs),
+		),
+		mcp.WithString("package",
+			mcp.Description("Go import path, PyPI project, crate, or Read the Docs project slug or docs URL (e.g. net/http, requests, serde, flask). Not used for mdn"),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Identifier to document, e.g. Client.Do, Array.prototype.map, requests.get, de::Deserialize. Omit for the package overview"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Version to document (default: latest). For readthedocs, the docs version such as stable or latest"),
+		),
+		mcp.WithNumber("max_length",
+			mcp.Description(fmt.Sprintf("Maximum characters of documentation to return (default: %d, max: %d)", defaultMaxLength, maxMaxLength)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads documentation sites
+		mcp.WithDestructiveHintAnnotation(false), // No side effects
+		mcp.WithIdempotentHintAnnotation(true),   // Same lookup gives the same section until the docs change
+		mcp.WithOpenWorldHintAnnotation(true),    // Fetches from documentation sites and registries
+	)
+}
+
+// Execute looks up the requested documentation
+func (t *DocsLookupTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	source, _ := args["source"].(string)
+	if source == "" {
+		return nil, fmt.Errorf("missing required parameter: source")
+	}
+
+	req := lookup{}
+	req.pkg, _ = args["package"].(string)
+	req.pkg = strings.TrimSpace(req.pkg)
+	req.symbol, _ = args["symbol"].(string)
+	req.symbol = strings.TrimSpace(req.symbol)
+	req.version, _ = args["version"].(string)
+	req.version = strings.TrimPrefix(strings.TrimSpace(req.version), "@")
+	if req.pkg == "" && source != sourceMDN {
+		return nil, fmt.Errorf("missing required parameter: package")
+	}
+
+	maxLength := defaultMaxLength
+	if v, ok := args["max_length"].(float64); ok {
+		if v < minMaxLength || v > maxMaxLength {
+			return nil, fmt.Errorf("max_length must be between %d and %d", minMaxLength, maxMaxLength)
+		}
+		maxLength = int(v)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"source":  source,
+		"package": req.pkg,
+		"symbol":  req.symbol,
+		"version": req.version,
+	}).Debug("Looking up documentation")
+
+	var page *docPage
+	var err error
+	switch source {
+	case sourceGo:
+		page, err = t.lookupGo(logger, cache, req)
+	case sourceMDN:
+		page, err = t.lookupMDN(logger, cache, req)
+	case sourcePython:
+		page, err = t.lookupPyPI(logger, cache, req)
+	case sourceRust:
+		page, err = t.lookupDocsRS(logger, cache, req)
+	case sourceReadTheDocs:
+		page, err = t.lookupReadTheDocs(logger, cache, req)
+	default:
+		return nil, fmt.Errorf("source must be one of: go, mdn, python, rust, readthedocs")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(page.render(maxLength)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the docs lookup tool
+func (t *DocsLookupTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Read the documentation for a Go method",
+				Arguments: map[string]any{
+					"source":  "go",
+					"package": "net/http",
+					"symbol":  "Client.Do",
+				},
+				ExpectedResult: "The method's signature and doc comment from pkg.go.dev",
+			},
+			{
+				Description: "Look up a JavaScript built-in on MDN",
+				Arguments: map[string]any{
+					"source": "mdn",
+					"symbol": "Array.prototype.flatMap",
+				},
+				ExpectedResult: "The MDN reference page's syntax, parameters, return value and examples",
+			},
+			{
+				Description: "Document a Rust trait method",
+				Arguments: map[string]any{
+					"source":  "rust",
+					"package": "tokio",
+					"symbol":  "io::AsyncReadExt::read_exact",
+				},
+				ExpectedResult: "The method's declaration and docs from docs.rs",
+			},
+			{
+				Description: "Find a function in a Sphinx documentation site",
+				Arguments: map[string]any{
+					"source":  "readthedocs",
+					"package": "https://docs.python.org/3/",
+					"symbol":  "itertools.batched",
+				},
+				ExpectedResult: "The function's entry from the Python standard library reference",
+			},
+		},
+		CommonPatterns: []string{
+			"Omit symbol first to see the package overview and its identifiers, then look up the one you need",
+			"Use python to find a project's documentation site from PyPI; use readthedocs with that site's URL for later lookups",
+			"Pass a version to match the documentation to the version in your lockfile",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A symbol matches more than one identifier",
+				Solution: "Qualify it with its type or module, as listed in the error, e.g. Client.Get rather than Get.",
+			},
+			{
+				Problem:  "A Python project has no documentation inventory",
+				Solution: "The project's docs are not built with Sphinx or mkdocstrings. The PyPI description is searched instead; use fetch_url on the documentation link for other sites.",
+			},
+			{
+				Problem:  "The section is truncated",
+				Solution: "Look up a narrower symbol, such as a method rather than its type, or raise max_length.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"source":     "go reads pkg.go.dev, mdn searches MDN, python reads PyPI and the project's Sphinx docs, rust reads docs.rs, readthedocs reads a Sphinx or mkdocstrings site's objects.inv inventory.",
+			"package":    "For readthedocs, a project slug (e.g. flask) or the root URL of any Sphinx site (e.g. https://docs.python.org/3/).",
+			"symbol":     "Matched exactly, then ignoring case, then by its last part (Do for Client.Do). For rust, Type::method finds a method on a type. For mdn, an MDN URL or path may be given.",
+			"version":    "Go module version (v1.2.3), PyPI release, crate version or Read the Docs version. Ignored for mdn.",
+			"max_length": "Longer documentation is cut at a line break, with a note of its full length.",
+		},
+		WhenToUse:    "Use to read the reference documentation for a specific function, type or package when you know where it is published.",
+		WhenNotToUse: "Don't use for tutorials or general questions (use internet_search), for libraries indexed by Context7 (use get_library_documentation), or for changes between versions (use release_notes).",
+	}
+}
@@ -0,0 +1,169 @@
+package docslookup
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+)
+
+// crateNamePattern matches crates.io crate names
+var crateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// rustMemberPrefixes are the id prefixes rustdoc gives an item's members, such as method.push
+var rustMemberPrefixes = []string{"method.", "tymethod.", "variant.", "structfield.", "associatedconstant.", "associatedtype."}
+
+// lookupDocsRS returns a crate's front page or an item's documentation from docs.rs. Items are found in
+// the crate's list of all items; Type::member finds a method, variant or field of an item.
+func (t *DocsLookupTool) lookupDocsRS(logger *logrus.Logger, cache *sync.Map, req lookup) (*docPage, error) {
+	if !crateNamePattern.MatchString(req.pkg) {
+		return nil, fmt.Errorf("invalid crate name: %s", req.pkg)
+	}
+	version := req.version
+	if version == "" {
+		version = "latest"
+	}
+	crate := strings.ReplaceAll(req.pkg, "-", "_")
+	root := fmt.Sprintf("https://docs.rs/%s/%s/%s/", req.pkg, version, crate)
+
+	if req.symbol == "" {
+		doc, err := t.fetchDocs(logger, cache, root, req)
+		if err != nil {
+			return nil, err
+		}
+		main := doc.Find("#main-content")
+		main.Find(".main-heading").Remove()
+		content, err := toMarkdown(logger, main)
+		if err != nil {
+			return nil, err
+		}
+		return &docPage{title: "Crate " + crate, url: root, content: content}, nil
+	}
+
+	doc, err := t.fetchDocs(logger, cache, root+"all.html", req)
+	if err != nil {
+		return nil, err
+	}
+	// all.html links every public item by its path within the crate, e.g. de::Deserializer
+	links := make(map[string]string)
+	var names []string
+	doc.Find("#main-content ul.all-items li a").Each(func(_ int, a *goquery.Selection) {
+		name := strings.TrimSpace(a.Text())
+		if _, ok := links[name]; !ok {
+			links[name] = a.AttrOr("href", "")
+			names = append(names, name)
+		}
+	})
+
+	symbol := strings.TrimPrefix(req.symbol, crate+"::")
+	member := ""
+	name, err := matchSymbol(symbol, names, "::")
+	if err != nil {
+		item, last, found := cutLast(symbol, "::")
+		if !found {
+			return nil, fmt.Errorf("%s: %w", req.pkg, err)
+		}
+		if name, err = matchSymbol(item, names, "::"); err != nil {
+			return nil, fmt.Errorf("%s: %w", req.pkg, err)
+		}
+		member = last
+	}
+
+	itemURL := links[name]
+	itemDoc, err := t.fetchDocument(logger, cache, itemURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", itemURL, err)
+	}
+	main := itemDoc.Find("#main-content")
+	members := rustMembers(main)
+
+	page := &docPage{title: crate + "::" + name, url: itemURL}
+	var section *goquery.Selection
+	if member != "" {
+		for _, prefix := range rustMemberPrefixes {
+			target := byID(main, prefix+member)
+			if target.Length() == 0 {
+				continue
+			}
+			// A documented member is a summary, holding its declaration, in a details element with its docs
+			if summary := target.Parent(); goquery.NodeName(summary) == "summary" {
+				section = summary.Parent()
+			} else {
+				section = target.AddSelection(target.NextFiltered(".docblock"))
+			}
+			page.title += "::" + member
+			page.url += "#" + prefix + member
+			break
+		}
+		if section == nil {
+			return nil, fmt.Errorf("%s has no method, variant or field named %s (has: %s)", name, member, strings.Join(limitNames(slices.Concat(members["Methods"], members["Variants"], members["Fields"])), ", "))
+		}
+	} else {
+		section = main.Find(".item-decl").AddSelection(main.Find("details.top-doc").First())
+		if main.Find(".item-decl").Length() == 0 {
+			// Modules have no declaration, only their docs and a table of their items
+			main.Find(".main-heading").Remove()
+			section = main
+		}
+		for _, title := range []string{"Variants", "Fields", "Methods"} {
+			page.addList(title, members[title])
+		}
+	}
+
+	// rustdoc puts declarations in headings, which read better as code
+	section.Find("h4.code-header").Each(func(_ int, heading *goquery.Selection) {
+		heading.ReplaceWithHtml("<pre><code>" + html.EscapeString(heading.Text()) + "</code></pre>")
+	})
+	if page.content, err = toMarkdown(logger, section); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// fetchDocs fetches a docs.rs page, explaining a missing crate or version
+func (t *DocsLookupTool) fetchDocs(logger *logrus.Logger, cache *sync.Map, pageURL string, req lookup) (*goquery.Document, error) {
+	doc, err := t.fetchDocument(logger, cache, pageURL)
+	if isNotFound(err) {
+		if req.version != "" {
+			return nil, fmt.Errorf("version %s of crate %s not found on docs.rs", req.version, req.pkg)
+		}
+		return nil, fmt.Errorf("crate %s not found on docs.rs", req.pkg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	return doc, nil
+}
+
+// rustMembers returns an item's inherent and trait-declared methods, variants and fields by kind.
+// Methods from trait implementations, such as clone and fmt, are left out.
+func rustMembers(main *goquery.Selection) map[string][]string {
+	members := make(map[string][]string)
+	add := func(kind, selector, prefix string) {
+		main.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			name := strings.TrimPrefix(s.AttrOr("id", ""), prefix)
+			if !slices.Contains(members[kind], name) {
+				members[kind] = append(members[kind], name)
+			}
+		})
+	}
+	add("Methods", `#implementations-list [id^="method."]`, "method.")
+	add("Methods", `.methods [id^="tymethod."]`, "tymethod.")
+	add("Methods", `.methods [id^="method."]`, "method.")
+	add("Variants", `[id^="variant."]:not([id*=".field."])`, "variant.")
+	add("Fields", `[id^="structfield."]`, "structfield.")
+	return members
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
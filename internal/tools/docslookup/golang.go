@@ -0,0 +1,88 @@
+package docslookup
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+)
+
+// goPackagePattern matches Go import paths
+var goPackagePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~/-]*$`)
+
+// goDeclarationSelector matches the block pkg.go.dev renders for each documented identifier
+const goDeclarationSelector = ".Documentation-function, .Documentation-type, .Documentation-typeFunc, .Documentation-typeMethod"
+
+// lookupGo returns a package's overview or an identifier's documentation from pkg.go.dev
+func (t *DocsLookupTool) lookupGo(logger *logrus.Logger, cache *sync.Map, req lookup) (*docPage, error) {
+	if !goPackagePattern.MatchString(req.pkg) {
+		return nil, fmt.Errorf("invalid Go import path: %s", req.pkg)
+	}
+	pageURL := "https://pkg.go.dev/" + req.pkg
+	if req.version != "" {
+		pageURL += "@" + req.version
+	}
+
+	doc, err := t.fetchDocument(logger, cache, pageURL)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("package %s not found on pkg.go.dev", req.pkg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	docs := doc.Find(".Documentation-content").First()
+	if docs.Length() == 0 {
+		return nil, fmt.Errorf("no documentation found for %s on pkg.go.dev", req.pkg)
+	}
+
+	// Every function, type and method has a heading whose id is its name, e.g. Client.Do
+	var names, topLevel []string
+	docs.Find("h4[data-kind][id]").Each(func(_ int, heading *goquery.Selection) {
+		id, _ := heading.Attr("id")
+		names = append(names, id)
+		if kind, _ := heading.Attr("data-kind"); kind != "method" {
+			topLevel = append(topLevel, id)
+		}
+	})
+
+	if req.symbol == "" {
+		overview, err := toMarkdown(logger, docs.Find(".Documentation-overview"))
+		if err != nil {
+			return nil, err
+		}
+		page := &docPage{title: "Package " + req.pkg, url: pageURL, content: overview}
+		page.addList("Functions and types", topLevel)
+		return page, nil
+	}
+
+	name, err := matchSymbol(req.symbol, names, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", req.pkg, err)
+	}
+	heading := byID(docs, name)
+	declaration := heading.Closest(goDeclarationSelector)
+	if declaration.Length() == 0 {
+		declaration = heading.Parent()
+	}
+
+	page := &docPage{title: req.pkg + "." + name, url: pageURL + "#" + name}
+	if declaration.HasClass("Documentation-type") {
+		// A type's block holds its constructors and methods, which are listed rather than included
+		var constructors, methods []string
+		declaration.Find(".Documentation-typeFunc h4[id]").Each(func(_ int, s *goquery.Selection) {
+			constructors = append(constructors, s.AttrOr("id", ""))
+		})
+		declaration.Find(".Documentation-typeMethod h4[id]").Each(func(_ int, s *goquery.Selection) {
+			methods = append(methods, s.AttrOr("id", ""))
+		})
+		declaration.Find(".Documentation-typeFunc, .Documentation-typeMethod").Remove()
+		page.addList("Constructors", constructors)
+		page.addList("Methods", methods)
+	}
+	if page.content, err = toMarkdown(logger, declaration); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
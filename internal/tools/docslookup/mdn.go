@@ -0,0 +1,143 @@
+package docslookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	mdnBaseURL = "https://developer.mozilla.org"
+	// maxRelatedResults is the most other search results listed with an MDN page
+	maxRelatedResults = 5
+)
+
+// mdnSearchResponse is the response from MDN's search API
+type mdnSearchResponse struct {
+	Documents []mdnSearchResult `json:"documents"`
+}
+
+// mdnSearchResult is a page found by MDN's search API
+type mdnSearchResult struct {
+	MDNURL  string `json:"mdn_url"`
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// mdnDocument is an MDN page's index.json, holding its content as sections of HTML
+type mdnDocument struct {
+	Doc struct {
+		Title  string `json:"title"`
+		MDNURL string `json:"mdn_url"`
+		Body   []struct {
+			Type  string `json:"type"`
+			Value struct {
+				ID      string `json:"id"`
+				Title   string `json:"title"`
+				IsH3    bool   `json:"isH3"`
+				Content string `json:"content"`
+			} `json:"value"`
+		} `json:"body"`
+	} `json:"doc"`
+}
+
+// lookupMDN finds a page on MDN by name, path or URL and returns its prose sections
+func (t *DocsLookupTool) lookupMDN(logger *logrus.Logger, cache *sync.Map, req lookup) (*docPage, error) {
+	query := req.symbol
+	if query == "" {
+		query = req.pkg
+	}
+	if query == "" {
+		return nil, fmt.Errorf("missing required parameter: symbol")
+	}
+
+	var path string
+	var related []string
+	if strings.HasPrefix(query, "/") || strings.HasPrefix(query, mdnBaseURL+"/") {
+		path = strings.TrimPrefix(query, mdnBaseURL)
+		path, _, _ = strings.Cut(path, "#")
+		path = strings.TrimSuffix(path, "/")
+	} else {
+		body, err := t.fetch(logger, cache, mdnBaseURL+"/api/v1/search?locale=en-US&q="+url.QueryEscape(query), "application/json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to search MDN: %w", err)
+		}
+		var search mdnSearchResponse
+		if err := json.Unmarshal(body, &search); err != nil {
+			return nil, fmt.Errorf("failed to parse MDN search results: %w", err)
+		}
+		if len(search.Documents) == 0 {
+			return nil, fmt.Errorf("no MDN pages found for: %s", query)
+		}
+
+		best := bestMDNResult(query, search.Documents)
+		path = search.Documents[best].MDNURL
+		for i, result := range search.Documents {
+			if i != best && len(related) < maxRelatedResults {
+				related = append(related, fmt.Sprintf("[%s](%s%s): %s", result.Title, mdnBaseURL, result.MDNURL, result.Summary))
+			}
+		}
+	}
+
+	pageURL := mdnBaseURL + path
+	body, err := t.fetch(logger, cache, pageURL+"/index.json", "application/json")
+	if isNotFound(err) {
+		return nil, fmt.Errorf("MDN page not found: %s", pageURL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	var document mdnDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse MDN page %s: %w", pageURL, err)
+	}
+
+	// Browser compatibility tables and specification lists are data rather than prose, so are left out
+	var content strings.Builder
+	for _, section := range document.Doc.Body {
+		if section.Type != "prose" {
+			continue
+		}
+		if title := section.Value.Title; title != "" {
+			tag := "h2"
+			if section.Value.IsH3 {
+				tag = "h3"
+			}
+			fmt.Fprintf(&content, "<%s>%s</%s>\n", tag, html.EscapeString(title), tag)
+		}
+		content.WriteString(section.Value.Content + "\n")
+	}
+	doc, err := parseHTML([]byte(content.String()), pageURL)
+	if err != nil {
+		return nil, err
+	}
+	markdown, err := toMarkdown(logger, doc.Find("body"))
+	if err != nil {
+		return nil, err
+	}
+
+	title := document.Doc.Title
+	if title == "" {
+		title = query
+	}
+	return &docPage{title: title, url: pageURL, content: markdown, related: related}, nil
+}
+
+// bestMDNResult returns the index of the result whose title is the query, ignoring case and a trailing
+// "()", or the first result
+func bestMDNResult(query string, results []mdnSearchResult) int {
+	normalise := func(s string) string {
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "()"))
+	}
+	for i, result := range results {
+		if normalise(result.Title) == normalise(query) {
+			return i
+		}
+	}
+	return 0
+}
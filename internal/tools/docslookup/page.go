@@ -0,0 +1,222 @@
+package docslookup
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxListedNames is the most names shown in each list of identifiers
+	maxListedNames = 200
+	// maxCandidates is the most names suggested when a symbol is ambiguous or not found
+	maxCandidates = 10
+)
+
+// noiseSelector matches parts of documentation pages that mean nothing once converted to markdown
+const noiseSelector = "script, style, noscript, iframe, button, a.headerlink, a.Documentation-idLink, .example-header, .src, .rightside, a.anchor, .out-of-band, #copy-path, .tooltip, summary.hideme"
+
+// docPage is a section of documentation ready to return
+type docPage struct {
+	title   string
+	url     string
+	content string
+	lists   []nameList
+	// related are markdown links to other matches
+	related []string
+}
+
+// nameList is a titled list of identifiers shown after a page's content
+type nameList struct {
+	title string
+	names []string
+}
+
+// cacheEntry stores a fetched response body
+type cacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
+}
+
+// addList adds a list of identifiers when there are any
+func (p *docPage) addList(title string, names []string) {
+	if len(names) > 0 {
+		p.lists = append(p.lists, nameList{title: title, names: names})
+	}
+}
+
+// render returns the page as markdown, cutting its content at maxLength characters
+func (p *docPage) render(maxLength int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\nSource: %s\n\n", p.title, p.url)
+
+	content := strings.TrimSpace(p.content)
+	if len(content) > maxLength {
+		cut := maxLength
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		if newline := strings.LastIndex(content[:cut], "\n"); newline > maxLength/2 {
+			cut = newline
+		}
+		fmt.Fprintf(&b, "%s\n\n[Truncated: showing %d of %d characters. Look up a narrower symbol or raise max_length to see more]\n", strings.TrimSpace(content[:cut]), cut, len(content))
+	} else if content != "" {
+		b.WriteString(content + "\n")
+	}
+
+	for _, list := range p.lists {
+		names := list.names
+		more := ""
+		if len(names) > maxListedNames {
+			more = fmt.Sprintf(" and %d more", len(names)-maxListedNames)
+			names = names[:maxListedNames]
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n`%s`%s\n", list.title, strings.Join(names, "`, `"), more)
+	}
+	if len(p.related) > 0 {
+		b.WriteString("\n## Related\n\n")
+		for _, link := range p.related {
+			b.WriteString("- " + link + "\n")
+		}
+	}
+	return b.String()
+}
+
+// isRuneStart reports whether a byte begins a UTF-8 character
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// fetch returns the body of a URL, cached for cacheTTL
+func (t *DocsLookupTool) fetch(logger *logrus.Logger, cache *sync.Map, rawURL, accept string) ([]byte, error) {
+	cacheKey := "docs_lookup:" + rawURL
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < cacheTTL {
+			return entry.body, nil
+		}
+	}
+
+	body, err := packageversions.MakeRequestWithLogger(t.httpClient(), logger, "GET", rawURL, map[string]string{"Accept": accept})
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(cacheKey, cacheEntry{body: body, fetchedAt: time.Now()})
+	return body, nil
+}
+
+// fetchDocument fetches and parses an HTML page, making its links absolute
+func (t *DocsLookupTool) fetchDocument(logger *logrus.Logger, cache *sync.Map, pageURL string) (*goquery.Document, error) {
+	body, err := t.fetch(logger, cache, pageURL, "text/html")
+	if err != nil {
+		return nil, err
+	}
+	return parseHTML(body, pageURL)
+}
+
+// parseHTML parses an HTML page, resolving its links against pageURL
+func parseHTML(body []byte, pageURL string) (*goquery.Document, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+	if base, err := url.Parse(pageURL); err == nil {
+		doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			href, _ := a.Attr("href")
+			if ref, err := url.Parse(href); err == nil {
+				a.SetAttr("href", base.ResolveReference(ref).String())
+			}
+		})
+	}
+	return doc, nil
+}
+
+// isNotFound reports whether a request failed because the page does not exist
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "status code: 404")
+}
+
+// toMarkdown converts the selected elements to markdown, without page furniture such as anchor links.
+// The fetch_url clean-up is not used, as it drops lines that mention words such as "search", which are
+// often identifiers in documentation.
+func toMarkdown(logger *logrus.Logger, selection *goquery.Selection) (string, error) {
+	selection.Find(noiseSelector).Remove()
+	var b strings.Builder
+	for _, node := range selection.Nodes {
+		html, err := goquery.OuterHtml(goquery.NewDocumentFromNode(node).Selection)
+		if err != nil {
+			return "", fmt.Errorf("failed to read documentation: %w", err)
+		}
+		b.WriteString(html + "\n")
+	}
+	logger.Debug("Converting documentation to markdown")
+	markdown, err := htmltomarkdown.ConvertString(b.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to convert documentation to markdown: %w", err)
+	}
+	return strings.TrimSpace(markdown), nil
+}
+
+// byID returns the first element within a selection with the given id
+func byID(selection *goquery.Selection, id string) *goquery.Selection {
+	return selection.Find("[id]").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		value, _ := s.Attr("id")
+		return value == id
+	}).First()
+}
+
+// matchSymbol finds the name a symbol refers to: an exact match, then one that differs only in case,
+// then one whose last parts match after a separator, such as Do for Client.Do
+func matchSymbol(symbol string, names []string, separators ...string) (string, error) {
+	if slices.Contains(names, symbol) {
+		return symbol, nil
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, symbol) {
+			return name, nil
+		}
+	}
+
+	lower := strings.ToLower(symbol)
+	var matches []string
+	for _, name := range names {
+		for _, separator := range separators {
+			if strings.HasSuffix(strings.ToLower(name), separator+lower) && !slices.Contains(matches, name) {
+				matches = append(matches, name)
+			}
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		var similar []string
+		for _, name := range names {
+			if strings.Contains(strings.ToLower(name), lower) && !slices.Contains(similar, name) {
+				similar = append(similar, name)
+			}
+		}
+		if len(similar) == 0 {
+			return "", fmt.Errorf("symbol not found: %s", symbol)
+		}
+		return "", fmt.Errorf("symbol not found: %s (similar: %s)", symbol, strings.Join(limitNames(similar), ", "))
+	default:
+		return "", fmt.Errorf("symbol %s matches more than one identifier, use one of: %s", symbol, strings.Join(limitNames(matches), ", "))
+	}
+}
+
+// limitNames returns at most maxCandidates names
+func limitNames(names []string) []string {
+	if len(names) > maxCandidates {
+		return names[:maxCandidates]
+	}
+	return names
+}
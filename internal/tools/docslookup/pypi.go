@@ -0,0 +1,167 @@
+package docslookup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// pypiNamePattern matches PyPI project names
+	pypiNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// markdownHeadingPattern matches a markdown heading, capturing its level and text
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+)
+
+// docsURLKeys are the project URL labels PyPI projects commonly use for their documentation, in the
+// order they are preferred
+var docsURLKeys = []string{"documentation", "docs", "api reference", "reference"}
+
+// pypiProject is the part of PyPI's JSON API response used here
+type pypiProject struct {
+	Info struct {
+		Name                   string            `json:"name"`
+		Version                string            `json:"version"`
+		Summary                string            `json:"summary"`
+		Description            string            `json:"description"`
+		DescriptionContentType string            `json:"description_content_type"`
+		RequiresPython         string            `json:"requires_python"`
+		DocsURL                string            `json:"docs_url"`
+		HomePage               string            `json:"home_page"`
+		ProjectURLs            map[string]string `json:"project_urls"`
+	} `json:"info"`
+}
+
+// lookupPyPI returns a Python project's PyPI page, or a symbol's entry in its documentation. Symbols are
+// found through the documentation site's inventory when it has one, then in the project's description.
+func (t *DocsLookupTool) lookupPyPI(logger *logrus.Logger, cache *sync.Map, req lookup) (*docPage, error) {
+	if !pypiNamePattern.MatchString(req.pkg) {
+		return nil, fmt.Errorf("invalid PyPI project name: %s", req.pkg)
+	}
+	apiURL := "https://pypi.org/pypi/" + url.PathEscape(req.pkg)
+	if req.version != "" {
+		apiURL += "/" + url.PathEscape(req.version)
+	}
+	body, err := t.fetch(logger, cache, apiURL+"/json", "application/json")
+	if isNotFound(err) {
+		if req.version != "" {
+			return nil, fmt.Errorf("version %s of %s not found on PyPI", req.version, req.pkg)
+		}
+		return nil, fmt.Errorf("project %s not found on PyPI", req.pkg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from PyPI: %w", req.pkg, err)
+	}
+	var project pypiProject
+	if err := json.Unmarshal(body, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse PyPI metadata for %s: %w", req.pkg, err)
+	}
+	info := project.Info
+	pageURL := "https://pypi.org/project/" + info.Name + "/" + info.Version + "/"
+	docsURL := pypiDocsURL(project)
+
+	if req.symbol == "" {
+		var content strings.Builder
+		if info.Summary != "" {
+			content.WriteString(info.Summary + "\n\n")
+		}
+		fmt.Fprintf(&content, "- Version: %s\n", info.Version)
+		if info.RequiresPython != "" {
+			fmt.Fprintf(&content, "- Requires Python: %s\n", info.RequiresPython)
+		}
+		if docsURL != "" {
+			fmt.Fprintf(&content, "- Documentation: %s\n", docsURL)
+		}
+		content.WriteString("\n" + info.Description)
+		return &docPage{title: info.Name + " " + info.Version, url: pageURL, content: content.String()}, nil
+	}
+
+	var inventoryErr error
+	if docsURL != "" {
+		roots, err := docsRoots(docsURL, "")
+		if err == nil {
+			page, err := t.lookupInventory(logger, cache, roots, req.symbol)
+			if err == nil {
+				return page, nil
+			}
+			inventoryErr = err
+		}
+	}
+
+	if info.DescriptionContentType == "text/markdown" {
+		if section := markdownSection(info.Description, req.symbol); section != "" {
+			return &docPage{title: info.Name + ": " + req.symbol, url: pageURL, content: section}, nil
+		}
+	}
+
+	switch {
+	case inventoryErr != nil && !errors.Is(inventoryErr, errNoInventory):
+		return nil, inventoryErr
+	case docsURL != "":
+		return nil, fmt.Errorf("%s was not found in %s's description, and its documentation at %s has no objects.inv to search; use fetch_url to read it", req.symbol, info.Name, docsURL)
+	default:
+		return nil, fmt.Errorf("%s was not found in %s's description, and PyPI lists no documentation site for it", req.symbol, info.Name)
+	}
+}
+
+// pypiDocsURL returns a project's documentation site from its project URLs
+func pypiDocsURL(project pypiProject) string {
+	labels := make(map[string]string, len(project.Info.ProjectURLs))
+	for label, link := range project.Info.ProjectURLs {
+		labels[strings.ToLower(strings.TrimSpace(label))] = link
+	}
+	for _, key := range docsURLKeys {
+		if link := labels[key]; link != "" {
+			return link
+		}
+	}
+	if project.Info.DocsURL != "" {
+		return project.Info.DocsURL
+	}
+	for _, link := range slices.Sorted(maps.Values(labels)) {
+		if strings.Contains(link, ".readthedocs.io") {
+			return link
+		}
+	}
+	if strings.Contains(project.Info.HomePage, ".readthedocs.io") {
+		return project.Info.HomePage
+	}
+	return ""
+}
+
+// markdownSection returns the first section of a markdown document whose heading mentions the symbol,
+// up to the next heading of the same or a higher level
+func markdownSection(markdown, symbol string) string {
+	lines := strings.Split(markdown, "\n")
+	symbol = strings.ToLower(symbol)
+	start, level := -1, 0
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		match := markdownHeadingPattern.FindStringSubmatch(line)
+		if inFence || match == nil {
+			continue
+		}
+		if start >= 0 && len(match[1]) <= level {
+			return strings.Join(lines[start:i], "\n")
+		}
+		if start < 0 && strings.Contains(strings.ToLower(match[2]), symbol) {
+			start, level = i, len(match[1])
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	return strings.Join(lines[start:], "\n")
+}
@@ -0,0 +1,302 @@
+package docslookup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sammcj/mcp-devtools/internal/tools/webfetch"
+	"github.com/sirupsen/logrus"
+)
+
+// maxInventorySize limits how much of a decompressed inventory is read
+const maxInventorySize = 64 * 1024 * 1024
+
+var (
+	// readTheDocsSlugPattern matches Read the Docs project slugs
+	readTheDocsSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	// inventoryLinePattern matches "name domain:role priority uri display name" lines of objects.inv,
+	// as parsed by Sphinx itself
+	inventoryLinePattern = regexp.MustCompile(`^(.+?)\s+(\S+:\S+)\s+(-?\d+)\s+(\S*)\s+(.*)$`)
+	// headingPattern matches heading element names
+	headingPattern = regexp.MustCompile(`^h[1-6]$`)
+)
+
+// errNoInventory means a documentation site has no objects.inv to find symbols in
+var errNoInventory = errors.New("no objects.inv inventory found")
+
+// inventoryItem is an object listed in a Sphinx objects.inv inventory
+type inventoryItem struct {
+	name    string
+	role    string
+	uri     string
+	display string
+}
+
+// lookupReadTheDocs returns a Read the Docs project's (or any Sphinx site's) front page, or a symbol's
+// entry found through the site's inventory
+func (t *DocsLookupTool) lookupReadTheDocs(logger *logrus.Logger, cache *sync.Map, req lookup) (*docPage, error) {
+	root := req.pkg
+	if !strings.HasPrefix(root, "https://") && !strings.HasPrefix(root, "http://") {
+		if !readTheDocsSlugPattern.MatchString(root) {
+			return nil, fmt.Errorf("package must be a Read the Docs project slug or a documentation URL, got: %s", root)
+		}
+		root = "https://" + root + ".readthedocs.io/"
+	}
+	roots, err := docsRoots(root, req.version)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.symbol != "" {
+		return t.lookupInventory(logger, cache, roots, req.symbol)
+	}
+
+	var lastErr error
+	for _, root := range roots {
+		body, err := t.fetch(logger, cache, root, "text/html")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return frontPage(logger, body, root)
+	}
+	if isNotFound(lastErr) {
+		return nil, fmt.Errorf("documentation not found at %s", roots[0])
+	}
+	return nil, fmt.Errorf("failed to fetch %s: %w", roots[0], lastErr)
+}
+
+// docsRoots returns the URLs a documentation site's pages and inventory may be under. A Read the Docs
+// project without a version in its URL is tried at the requested version, then stable, then latest.
+func docsRoots(docsURL, version string) ([]string, error) {
+	parsed, err := url.Parse(docsURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid documentation URL: %s", docsURL)
+	}
+	parsed.RawQuery, parsed.Fragment = "", ""
+	if strings.HasSuffix(parsed.Path, ".html") {
+		parsed.Path = path.Dir(parsed.Path)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+
+	if parsed.Path != "/" || !strings.HasSuffix(parsed.Hostname(), ".readthedocs.io") {
+		return []string{parsed.String()}, nil
+	}
+	versions := []string{"stable", "latest"}
+	if version != "" {
+		versions = []string{version}
+	}
+	roots := make([]string, 0, len(versions))
+	for _, v := range versions {
+		roots = append(roots, parsed.String()+"en/"+url.PathEscape(v)+"/")
+	}
+	return roots, nil
+}
+
+// frontPage returns the main content of a documentation site's front page
+func frontPage(logger *logrus.Logger, body []byte, pageURL string) (*docPage, error) {
+	doc, err := parseHTML(body, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	page, err := doc.Html()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+	content, err := webfetch.ExtractMainContent(logger, page)
+	if err != nil {
+		return nil, err
+	}
+	contentDoc, err := parseHTML([]byte(content), pageURL)
+	if err != nil {
+		return nil, err
+	}
+	markdown, err := toMarkdown(logger, contentDoc.Find("body"))
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		title = pageURL
+	}
+	return &docPage{title: title, url: pageURL, content: markdown}, nil
+}
+
+// lookupInventory finds a symbol in the objects.inv of the first root that has one and returns its
+// section of the documentation
+func (t *DocsLookupTool) lookupInventory(logger *logrus.Logger, cache *sync.Map, roots []string, symbol string) (*docPage, error) {
+	for _, root := range roots {
+		body, err := t.fetch(logger, cache, root+"objects.inv", "application/octet-stream")
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %sobjects.inv: %w", root, err)
+		}
+		// Some sites answer a missing file with an HTML page rather than a 404
+		if !bytes.HasPrefix(body, []byte("# Sphinx inventory")) {
+			continue
+		}
+		items, err := parseInventory(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %sobjects.inv: %w", root, err)
+		}
+		item, err := findInventoryItem(items, symbol)
+		if err != nil {
+			return nil, err
+		}
+		return t.inventorySection(logger, cache, root, item)
+	}
+	return nil, fmt.Errorf("%w at %s", errNoInventory, roots[0])
+}
+
+// parseInventory reads the objects listed in a version 2 Sphinx inventory, which has four header lines
+// followed by zlib-compressed lines of objects
+func parseInventory(data []byte) ([]inventoryItem, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	header, _ := reader.ReadString('\n')
+	if !strings.HasPrefix(header, "# Sphinx inventory version 2") {
+		return nil, fmt.Errorf("unsupported inventory format: %s", strings.TrimSpace(header))
+	}
+	for range 3 {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, fmt.Errorf("inventory header is incomplete")
+		}
+	}
+
+	decompressed, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress inventory: %w", err)
+	}
+	defer func() { _ = decompressed.Close() }()
+
+	var items []inventoryItem
+	scanner := bufio.NewScanner(io.LimitReader(decompressed, maxInventorySize))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := inventoryLinePattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		item := inventoryItem{name: match[1], role: match[2], uri: match[4], display: match[5]}
+		// A uri ending in $ stands for the same uri ending in the object's name
+		if strings.HasSuffix(item.uri, "$") {
+			item.uri = strings.TrimSuffix(item.uri, "$") + item.name
+		}
+		if item.display == "-" {
+			item.display = item.name
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decompress inventory: %w", err)
+	}
+	return items, nil
+}
+
+// findInventoryItem matches a symbol against an inventory's API objects, then its section labels and
+// page titles
+func findInventoryItem(items []inventoryItem, symbol string) (inventoryItem, error) {
+	var names []string
+	for _, item := range items {
+		if !strings.HasPrefix(item.role, "std:") {
+			names = append(names, item.name)
+		}
+	}
+	name, err := matchSymbol(symbol, names, ".", "::")
+	if err == nil {
+		for _, item := range items {
+			if item.name == name && !strings.HasPrefix(item.role, "std:") {
+				return item, nil
+			}
+		}
+	}
+
+	for _, item := range items {
+		if (item.role == "std:label" || item.role == "std:doc") && (strings.EqualFold(item.name, symbol) || strings.EqualFold(item.display, symbol)) {
+			return item, nil
+		}
+	}
+	return inventoryItem{}, err
+}
+
+// inventorySection fetches the page an inventory item is on and returns the part of it about the item
+func (t *DocsLookupTool) inventorySection(logger *logrus.Logger, cache *sync.Map, root string, item inventoryItem) (*docPage, error) {
+	base, err := url.Parse(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid documentation URL: %s", root)
+	}
+	ref, err := url.Parse(item.uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory link for %s: %s", item.name, item.uri)
+	}
+	itemURL := base.ResolveReference(ref)
+	anchor := itemURL.Fragment
+	itemURL.Fragment = ""
+
+	doc, err := t.fetchDocument(logger, cache, itemURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", itemURL, err)
+	}
+	var section *goquery.Selection
+	if anchor != "" {
+		section = anchorSection(doc, anchor)
+	}
+	if section == nil || section.Length() == 0 {
+		section = doc.Find(`[role="main"], article, main, .body`).First()
+	}
+	if section.Length() == 0 {
+		section = doc.Find("body")
+	}
+
+	content, err := toMarkdown(logger, section)
+	if err != nil {
+		return nil, err
+	}
+	itemURL.Fragment = anchor
+	return &docPage{title: item.display, url: itemURL.String(), content: content}, nil
+}
+
+// anchorSection returns the part of a page an anchor names: a described object (Sphinx's dl, or a
+// mkdocstrings doc-object), or a section and its contents
+func anchorSection(doc *goquery.Document, anchor string) *goquery.Selection {
+	target := byID(doc.Selection, anchor)
+	if target.Length() == 0 {
+		return nil
+	}
+
+	name := goquery.NodeName(target)
+	switch {
+	case name == "dt":
+		return target.Parent()
+	case name == "span" && target.Text() == "":
+		// Sphinx places labels as empty spans just before or inside the section they name
+		if next := target.Next(); next.Is("section, div.section") {
+			return next
+		}
+		return target.Closest("section, div.section")
+	case headingPattern.MatchString(name):
+		if parent := target.Parent(); parent.HasClass("doc-object") {
+			return parent
+		}
+		// A heading outside a section runs until the next heading of the same or a higher level
+		var stop []string
+		for level := '1'; level <= rune(name[1]); level++ {
+			stop = append(stop, "h"+string(level))
+		}
+		return target.AddSelection(target.NextUntil(strings.Join(stop, ", ")))
+	}
+	return target
+}
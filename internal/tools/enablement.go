@@ -13,27 +13,77 @@ import (
 // Example: ENABLE_ADDITIONAL_TOOLS="claude-agent,gemini-agent,filesystem,vulnerability_scan,sbom,aws,api"
 //
 // Supported tool names:
+// - analyse_logs
 // - api
+// - artifacts
 // - aws_documentation
+// - batch
+// - build_targets
+// - calc
 // - changelog
+// - check_links
 // - claude-agent
+// - clear_cache
+// - clipboard
 // - codex-agent
+// - containers
 // - copilot-agent
+// - csv
+// - database
+// - dep_graph
+// - docs_index
+// - docs_lookup
+// - encode
+// - env_info
 // - excel
+// - export_session
+// - feed
+// - fetch_more
 // - filesystem
+// - finance
+// - forge
 // - gemini-agent
+// - generate_id
+// - get_diagnostics
+// - image
 // - kiro-agent
+// - lint
+// - markdown
 // - memory
+// - mermaid_diagram
 // - murican_to_english
+// - netdiag
+// - notify
+// - openapi
 // - pdf
+// - pdf_form
+// - pipelines
+// - plugins
+// - powerpoint
 // - process_document
+// - proofread
+// - regex_test
+// - run_tests
+// - release_notes
 // - sbom
+// - scheduled_runs
+// - screenshot
 // - security
 // - security_override
+// - security_test
 // - sequential-thinking
 // - shadcn
+// - stackexchange
+// - tasks
+// - terraform
 // - terraform_documentation
+// - text_diff
+// - time
+// - transform
+// - usage
 // - vulnerability_scan
+// - weather
+// - wikipedia
 
 // cachedEnabledTools is parsed once from the environment on first access.
 var (
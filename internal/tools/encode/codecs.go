@@ -0,0 +1,131 @@
+package encode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// maxOutputSize is the most bytes decompressing gzip data may produce, so small inputs cannot expand
+// without bound
+const maxOutputSize = 4 * 1024 * 1024
+
+// encodeBase64 encodes data as standard base64 with padding, or as unpadded base64url
+func encodeBase64(data []byte, urlSafe bool) string {
+	if urlSafe {
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding, ignoring whitespace such
+// as the line breaks in PEM and MIME data
+func decodeBase64(input string) ([]byte, error) {
+	cleaned := strings.TrimRight(removeSpace(input), "=")
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(cleaned, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+	data, err := encoding.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("input is not valid base64: %w", err)
+	}
+	return data, nil
+}
+
+// encodeHex encodes data as lowercase hex digits
+func encodeHex(data []byte) string {
+	return hex.EncodeToString(data)
+}
+
+// decodeHex decodes hex digits, ignoring whitespace, colon separators and a 0x prefix
+func decodeHex(input string) ([]byte, error) {
+	cleaned := strings.ReplaceAll(removeSpace(input), ":", "")
+	cleaned = strings.TrimPrefix(strings.TrimPrefix(cleaned, "0x"), "0X")
+	data, err := hex.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("input is not valid hex: %w", err)
+	}
+	return data, nil
+}
+
+// encodeURL percent-encodes text for use in a URL query value, or in a path segment
+func encodeURL(input string, path bool) string {
+	if path {
+		return url.PathEscape(input)
+	}
+	return url.QueryEscape(input)
+}
+
+// decodeURL decodes percent-encoded text. Query values also decode + as a space.
+func decodeURL(input string, path bool) (string, error) {
+	var decoded string
+	var err error
+	if path {
+		decoded, err = url.PathUnescape(input)
+	} else {
+		decoded, err = url.QueryUnescape(input)
+	}
+	if err != nil {
+		return "", fmt.Errorf("input is not valid URL encoding: %w", err)
+	}
+	return decoded, nil
+}
+
+// encodeHTML escapes the characters that are special in HTML: < > & ' and "
+func encodeHTML(input string) string {
+	return html.EscapeString(input)
+}
+
+// decodeHTML replaces named and numeric HTML entities with the characters they stand for
+func decodeHTML(input string) string {
+	return html.UnescapeString(input)
+}
+
+// compressGzip compresses data with gzip at the default level
+func compressGzip(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	writer := gzip.NewWriter(&out)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// decompressGzip decompresses gzip data, stopping at maxOutputSize
+func decompressGzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("input is not gzip data: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	out, err := io.ReadAll(io.LimitReader(reader, maxOutputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	if len(out) > maxOutputSize {
+		return nil, fmt.Errorf("decompressed data is larger than the maximum of %d bytes", maxOutputSize)
+	}
+	return out, nil
+}
+
+// removeSpace removes all whitespace from text
+func removeSpace(input string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, input)
+}
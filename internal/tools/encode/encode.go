@@ -0,0 +1,284 @@
+package encode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// maxInputSize is the largest input accepted
+const maxInputSize = 1024 * 1024
+
+// formats are the encodings the tool handles
+var formats = []string{"base64", "base64url", "url", "url_path", "html", "hex", "jwt", "gzip"}
+
+// EncodeTool encodes and decodes text in common formats, and decodes JWTs
+type EncodeTool struct{}
+
+// Response is the result of encoding or decoding
+type Response struct {
+	Format         string     `json:"format"`
+	Action         string     `json:"action"`
+	Output         string     `json:"output,omitempty"`
+	OutputEncoding string     `json:"output_encoding,omitempty"`
+	InputBytes     int        `json:"input_bytes"`
+	OutputBytes    int        `json:"output_bytes,omitempty"`
+	JWT            *JWTResult `json:"jwt,omitempty"`
+	Note           string     `json:"note,omitempty"`
+}
+
+// init registers the encode tool
+func init() {
+	registry.Register(&EncodeTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (e *EncodeTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"encode",
+		mcp.WithDescription(`Encodes and decodes text. Formats:
+- base64, base64url: decoding accepts either alphabet, with or without padding
+- url (query values, space as +), url_path (path segments, space as %20)
+- html: entities such as &amp; and &#39;
+- hex: decoding ignores whitespace, colons and 0x
+- jwt: decode only. Shows the header, payload and expiry without verifying; give jwks_url or jwks to also verify the signature
+- gzip: encode compresses text to base64, decode decompresses base64 gzip data
+
+Decoded data that is not text is returned as base64.`),
+		mcp.WithString("format",
+			mcp.Required(),
+			mcp.Description("Encoding to use"),
+			mcp.Enum(formats...),
+		),
+		mcp.WithString("action",
+			mcp.Description("Whether to encode or decode the input. Required except for jwt, which only decodes"),
+			mcp.Enum("encode", "decode"),
+		),
+		mcp.WithString("input",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("Text to encode or decode (max %d KiB)", maxInputSize/1024)),
+		),
+		mcp.WithString("jwks_url",
+			mcp.Description("jwt only: URL of a JSON Web Key Set to verify the token's signature with"),
+		),
+		mcp.WithString("jwks",
+			mcp.Description("jwt only: JSON Web Key Set, or a single JWK, to verify the token's signature with"),
+		),
+		// Read-only annotations for encoding and decoding
+		mcp.WithReadOnlyHintAnnotation(true),     // Doesn't modify environment
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(true),   // The same input gives the same output
+		mcp.WithOpenWorldHintAnnotation(true),    // Fetches a JWKS when jwks_url is given
+	)
+}
+
+// Execute encodes or decodes the input
+func (e *EncodeTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	format, ok := args["format"].(string)
+	if !ok || format == "" {
+		return nil, fmt.Errorf("missing required parameter 'format': one of %s", strings.Join(formats, ", "))
+	}
+	if !slices.Contains(formats, format) {
+		return nil, fmt.Errorf("invalid format '%s': must be one of %s", format, strings.Join(formats, ", "))
+	}
+
+	action, _ := args["action"].(string)
+	switch {
+	case format == "jwt" && action == "":
+		action = "decode"
+	case format == "jwt" && action != "decode":
+		return nil, fmt.Errorf("the jwt format only decodes tokens. Creating tokens needs a signing key and is not supported")
+	case action != "encode" && action != "decode":
+		return nil, fmt.Errorf("invalid or missing 'action': must be encode or decode")
+	}
+
+	input, ok := args["input"].(string)
+	if !ok || input == "" {
+		return nil, fmt.Errorf("missing required parameter 'input'")
+	}
+	if len(input) > maxInputSize {
+		return nil, fmt.Errorf("input is %d bytes, more than the maximum of %d", len(input), maxInputSize)
+	}
+
+	jwksURL, _ := args["jwks_url"].(string)
+	jwksJSON, _ := args["jwks"].(string)
+	if format != "jwt" && (jwksURL != "" || jwksJSON != "") {
+		return nil, fmt.Errorf("'jwks_url' and 'jwks' are only used with the jwt format")
+	}
+	if jwksURL != "" && jwksJSON != "" {
+		return nil, fmt.Errorf("provide either 'jwks_url' or 'jwks', not both")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"format":     format,
+		"action":     action,
+		"input_size": len(input),
+	}).Debug("Executing encode")
+
+	response := &Response{Format: format, Action: action, InputBytes: len(input)}
+	var err error
+	if format == "jwt" {
+		err = decodeJWTInput(ctx, response, input, jwksURL, jwksJSON)
+	} else {
+		err = convert(response, format, action, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// convert encodes or decodes the input in one of the text and binary formats
+func convert(response *Response, format, action, input string) error {
+	if action == "encode" {
+		switch format {
+		case "base64", "base64url":
+			response.Output = encodeBase64([]byte(input), format == "base64url")
+		case "url", "url_path":
+			response.Output = encodeURL(input, format == "url_path")
+		case "html":
+			response.Output = encodeHTML(input)
+		case "hex":
+			response.Output = encodeHex([]byte(input))
+		case "gzip":
+			compressed, err := compressGzip([]byte(input))
+			if err != nil {
+				return err
+			}
+			response.Output = encodeBase64(compressed, false)
+			response.OutputEncoding = "base64"
+			response.Note = fmt.Sprintf("Compressed %d bytes to %d bytes, shown as base64.", len(input), len(compressed))
+		}
+		response.OutputBytes = len(response.Output)
+		return nil
+	}
+
+	var decoded []byte
+	var err error
+	switch format {
+	case "base64", "base64url":
+		decoded, err = decodeBase64(input)
+	case "url", "url_path":
+		var text string
+		text, err = decodeURL(input, format == "url_path")
+		decoded = []byte(text)
+	case "html":
+		decoded = []byte(decodeHTML(input))
+	case "hex":
+		decoded, err = decodeHex(input)
+	case "gzip":
+		var compressed []byte
+		compressed, err = decodeBase64(input)
+		if err != nil {
+			return fmt.Errorf("gzip input must be base64 encoded: %w", err)
+		}
+		decoded, err = decompressGzip(compressed)
+	}
+	if err != nil {
+		return err
+	}
+
+	response.OutputBytes = len(decoded)
+	if utf8.Valid(decoded) {
+		response.Output = string(decoded)
+	} else {
+		response.Output = encodeBase64(decoded, false)
+		response.OutputEncoding = "base64"
+		response.Note = "The decoded data is not UTF-8 text, so it is shown as base64."
+	}
+	return nil
+}
+
+// decodeJWTInput decodes a JWT into the response, verifying its signature when a key set is given
+func decodeJWTInput(ctx context.Context, response *Response, input, jwksURL, jwksJSON string) error {
+	token := stripBearer(input)
+	result, err := decodeJWT(token, time.Now())
+	if err != nil {
+		return err
+	}
+	response.JWT = result
+
+	if jwksURL == "" && jwksJSON == "" {
+		response.Note = "The signature was not verified. Give jwks_url or jwks to verify it."
+		return nil
+	}
+	var keySet *jwks
+	if jwksURL != "" {
+		keySet, err = fetchJWKS(ctx, jwksURL)
+	} else {
+		keySet, err = parseJWKS([]byte(jwksJSON))
+	}
+	if err != nil {
+		return err
+	}
+	result.Verification = verifyJWT(token, keySet, result.Header)
+	return nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the encode tool
+func (e *EncodeTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Reading or producing base64, URL-encoded, HTML-escaped, hex or gzip data while debugging, and inspecting JWTs from Authorization headers, cookies or logs.",
+		WhenNotToUse: "Hashing or generating secrets - use generate_id. Converting between JSON, YAML and TOML - use transform. Encrypting data or creating signed JWTs is not supported.",
+		CommonPatterns: []string{
+			"Inspect a token: format 'jwt' with the token, with or without its 'Bearer ' prefix",
+			"Verify a token: add jwks_url, usually https://<issuer>/.well-known/jwks.json",
+			"Read a Kubernetes secret value: format 'base64', action 'decode'",
+			"Build a query string value: format 'url', action 'encode'",
+			"Read a compressed payload from a log: format 'gzip', action 'decode' with the base64 data",
+		},
+		ParameterDetails: map[string]string{
+			"format":   "base64 encodes with padding; base64url encodes without padding, as JWTs and PKCE do. url encodes spaces as +, url_path as %20.",
+			"action":   "encode or decode. jwt only decodes; gzip encode compresses and gzip decode decompresses.",
+			"input":    fmt.Sprintf("Text, up to %d KiB. Encoding uses its UTF-8 bytes. gzip decode takes base64 data, and decompresses to at most %d MiB.", maxInputSize/1024, maxOutputSize/1024/1024),
+			"jwks_url": "http or https URL of the issuer's key set. Subject to the security framework's domain rules.",
+			"jwks":     "Inline key set or single key, for offline verification. RSA, EC (P-256, P-384, P-521), Ed25519 and HMAC (oct) keys are supported.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Decode base64",
+				Arguments:      map[string]any{"format": "base64", "action": "decode", "input": "aGVsbG8gd29ybGQ="},
+				ExpectedResult: "output: 'hello world'",
+			},
+			{
+				Description:    "Encode a query value",
+				Arguments:      map[string]any{"format": "url", "action": "encode", "input": "a&b=c d"},
+				ExpectedResult: "output: 'a%26b%3Dc+d'",
+			},
+			{
+				Description:    "Decode and verify a JWT",
+				Arguments:      map[string]any{"format": "jwt", "input": "eyJhbGciOiJSUzI1NiIsImtpZCI6ImsxIn0...", "jwks_url": "https://auth.example.com/.well-known/jwks.json"},
+				ExpectedResult: "The header and payload, expiry times, and verification with verified true or the reason it failed",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Decoded output is base64 rather than text",
+				Solution: "The decoded bytes are not UTF-8 text, for example an image or compressed data. If it is gzip data, decode it with the gzip format instead.",
+			},
+			{
+				Problem:  "JWT verification fails with 'no key with kid'",
+				Solution: "The token was signed by a key that is not in the key set. Check the JWKS URL belongs to the token's issuer (the iss claim), and that keys have not been rotated.",
+			},
+			{
+				Problem:  "A JWT has five parts",
+				Solution: "It is an encrypted JWE, whose payload can only be read with the recipient's private key.",
+			},
+		},
+	}
+}
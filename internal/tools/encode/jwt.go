@@ -0,0 +1,376 @@
+package encode
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+const (
+	// jwksTimeout is how long fetching a JWKS may take
+	jwksTimeout = 10 * time.Second
+
+	// maxJWKSSize is the largest JWKS document accepted
+	maxJWKSSize = 1024 * 1024
+)
+
+// JWTResult is a decoded JWT
+type JWTResult struct {
+	Header       map[string]any `json:"header"`
+	Payload      any            `json:"payload"`
+	Times        *ClaimTimes    `json:"times,omitempty"`
+	Signed       bool           `json:"signed"`
+	Verification *Verification  `json:"verification,omitempty"`
+	Warnings     []string       `json:"warnings,omitempty"`
+}
+
+// ClaimTimes shows the exp, nbf and iat claims as readable times
+type ClaimTimes struct {
+	IssuedAt  string `json:"issued_at,omitempty"`
+	NotBefore string `json:"not_before,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Expired   bool   `json:"expired"`
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+// Verification is the result of checking a JWT's signature against a JWKS
+type Verification struct {
+	Verified  bool   `json:"verified"`
+	KeyID     string `json:"key_id,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// jwk is a JSON Web Key, with the fields for RSA, EC, OKP (Ed25519) and oct (HMAC) keys
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// jwks is a JSON Web Key Set
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// stripBearer removes whitespace and a "Bearer " prefix copied from an Authorization header
+func stripBearer(token string) string {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	return token
+}
+
+// decodeJWT decodes a JWT's header and payload without verifying it, and reports its times relative to
+// now
+func decodeJWT(token string, now time.Time) (*JWTResult, error) {
+	parts := strings.Split(token, ".")
+	switch len(parts) {
+	case 3:
+	case 5:
+		return nil, fmt.Errorf("the token has five parts, so it is an encrypted JWT (JWE). Only signed JWTs can be decoded")
+	default:
+		return nil, fmt.Errorf("a JWT has three parts separated by dots, but the input has %d", len(parts))
+	}
+
+	var header map[string]any
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("failed to decode the JWT header: %w", err)
+	}
+	var payload any
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode the JWT payload: %w", err)
+	}
+
+	result := &JWTResult{Header: header, Payload: payload, Signed: parts[2] != ""}
+	if alg, _ := header["alg"].(string); strings.EqualFold(alg, "none") || !result.Signed {
+		result.Warnings = append(result.Warnings, "The token is not signed, so its claims cannot be trusted.")
+	}
+	if claims, ok := payload.(map[string]any); ok {
+		result.Times = claimTimes(claims, now)
+	}
+	return result, nil
+}
+
+// decodeJWTPart decodes a base64url JSON part of a JWT, keeping numbers exact
+func decodeJWTPart(part string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// claimTimes converts the registered time claims to RFC 3339 times
+func claimTimes(claims map[string]any, now time.Time) *ClaimTimes {
+	times := &ClaimTimes{}
+	found := false
+	for name, target := range map[string]*string{"iat": &times.IssuedAt, "nbf": &times.NotBefore, "exp": &times.ExpiresAt} {
+		seconds, ok := numericDate(claims[name])
+		if !ok {
+			continue
+		}
+		found = true
+		at := time.Unix(seconds, 0).UTC()
+		*target = at.Format(time.RFC3339)
+		if name == "exp" {
+			times.Expired = !now.Before(at)
+			if !times.Expired {
+				times.ExpiresIn = at.Sub(now).Round(time.Second).String()
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return times
+}
+
+// numericDate reads a JWT NumericDate, which is seconds since the epoch and may have a fraction
+func numericDate(value any) (int64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	if seconds, err := number.Int64(); err == nil {
+		return seconds, true
+	}
+	if seconds, err := number.Float64(); err == nil {
+		return int64(seconds), true
+	}
+	return 0, false
+}
+
+// verifyJWT checks a JWT's signature with a key from the key set. Only the signature is checked; the
+// token's times are reported separately.
+func verifyJWT(token string, keySet *jwks, header map[string]any) *Verification {
+	alg, _ := header["alg"].(string)
+	kid, _ := header["kid"].(string)
+	verification := &Verification{Algorithm: alg}
+	if alg == "" || strings.EqualFold(alg, "none") {
+		verification.Error = "the token is not signed"
+		return verification
+	}
+
+	key, err := findKey(keySet, kid, alg)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	verification.KeyID = key.Kid
+	if key.Alg != "" && key.Alg != alg {
+		// Accepting another algorithm would let a token choose how its signature is checked
+		verification.Error = fmt.Sprintf("key %q is for algorithm %s, but the token uses %s", key.Kid, key.Alg, alg)
+		return verification
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{alg}), jwt.WithoutClaimsValidation())
+	_, err = parser.Parse(token, func(*jwt.Token) (any, error) {
+		return publicKey(key)
+	})
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	verification.Verified = true
+	return verification
+}
+
+// findKey picks the key a token was signed with: the key with the token's kid, or without a kid, the
+// only key that suits the algorithm
+func findKey(keySet *jwks, kid, alg string) (*jwk, error) {
+	if kid != "" {
+		for i := range keySet.Keys {
+			if keySet.Keys[i].Kid == kid {
+				return &keySet.Keys[i], nil
+			}
+		}
+		return nil, fmt.Errorf("the JWKS has no key with kid %q", kid)
+	}
+
+	var candidates []*jwk
+	for i := range keySet.Keys {
+		key := &keySet.Keys[i]
+		if (key.Alg == "" || key.Alg == alg) && keyTypeFor(alg) == key.Kty {
+			candidates = append(candidates, key)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("the JWKS has no %s key for algorithm %s", keyTypeFor(alg), alg)
+	case 1:
+		return candidates[0], nil
+	}
+	return nil, fmt.Errorf("the token has no kid and the JWKS has %d keys for algorithm %s", len(candidates), alg)
+}
+
+// keyTypeFor returns the JWK key type used by a JWS algorithm
+func keyTypeFor(alg string) string {
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		return "RSA"
+	case strings.HasPrefix(alg, "ES"):
+		return "EC"
+	case alg == "EdDSA":
+		return "OKP"
+	case strings.HasPrefix(alg, "HS"):
+		return "oct"
+	}
+	return ""
+}
+
+// publicKey converts a JWK to the key type the jwt package verifies with
+func publicKey(key *jwk) (any, error) {
+	switch key.Kty {
+	case "RSA":
+		n, err := base64URLField(key.N, "n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64URLField(key.E, "e")
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("the key's RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch key.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %q", key.Crv)
+		}
+		x, err := base64URLField(key.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64URLField(key.Y, "y")
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, fmt.Errorf("the key's coordinates are too long for %s", key.Crv)
+		}
+		// An uncompressed point is 0x04 followed by x and y, each padded to the curve's size
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if key.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", key.Crv)
+		}
+		x, err := base64URLField(key.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("an Ed25519 key must be %d bytes", ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return base64URLField(key.K, "k")
+	}
+	return nil, fmt.Errorf("unsupported key type %q", key.Kty)
+}
+
+// base64URLField decodes a base64url field of a JWK
+func base64URLField(value, name string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("the key has no %q field", name)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("the key's %q field is not valid base64url: %w", name, err)
+	}
+	return data, nil
+}
+
+// parseJWKS parses a JWKS document. A single JWK is accepted as a set of one.
+func parseJWKS(data []byte) (*jwks, error) {
+	var keySet jwks
+	if err := json.Unmarshal(data, &keySet); err != nil {
+		return nil, fmt.Errorf("the JWKS is not valid JSON: %w", err)
+	}
+	if len(keySet.Keys) == 0 {
+		var single jwk
+		if err := json.Unmarshal(data, &single); err == nil && single.Kty != "" {
+			keySet.Keys = []jwk{single}
+		}
+	}
+	if len(keySet.Keys) == 0 {
+		return nil, errors.New("the JWKS has no keys")
+	}
+	return &keySet, nil
+}
+
+// fetchJWKS downloads a JWKS, applying the security framework's domain checks
+func fetchJWKS(ctx context.Context, jwksURL string) (*jwks, error) {
+	parsedURL, err := url.Parse(jwksURL)
+	if err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid jwks_url: must be an http or https URL, e.g. https://example.com/.well-known/jwks.json")
+	}
+	if err := security.CheckDomainAccess(parsedURL.Hostname()); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/jwk-set+json")
+
+	client := httpclient.NewHTTPClientWithProxy(jwksTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch JWKS: HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	if len(body) > maxJWKSSize {
+		return nil, fmt.Errorf("JWKS exceeds maximum size of %d bytes", maxJWKSSize)
+	}
+	return parseJWKS(body)
+}
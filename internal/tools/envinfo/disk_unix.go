@@ -0,0 +1,15 @@
+//go:build linux || darwin
+
+package envinfo
+
+import "syscall"
+
+// diskSpace returns the size of the file system holding path and the space free to the user
+func diskSpace(path string) (total, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	// Bavail excludes the blocks reserved for root, which a build cannot use
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), nil
+}
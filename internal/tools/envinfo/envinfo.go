@@ -0,0 +1,230 @@
+package envinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// sections are the parts of the report that can be requested
+var sections = []string{"system", "toolchains", "path", "resources", "environment"}
+
+// EnvInfoTool reports the operating system, toolchains, PATH, resources and environment variables
+type EnvInfoTool struct{}
+
+// init registers the env_info tool
+func init() {
+	registry.Register(&EnvInfoTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *EnvInfoTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"env_info",
+		mcp.WithDescription(`Report the environment commands run in: OS and architecture, installed toolchain versions (go, node, python, docker, kubectl and more), PATH directories, memory and disk space, and relevant environment variables with secret values masked. Use this instead of probing with uname, which and --version commands.`),
+		mcp.WithString("sections",
+			mcp.Description("Comma-separated sections to report: "+strings.Join(sections, ", ")+" (default: all)"),
+		),
+		mcp.WithString("toolchains",
+			mcp.Description("Comma-separated toolchains to check instead of all of them: "+strings.Join(probeNames(), ", ")),
+		),
+		mcp.WithString("env",
+			mcp.Description("Comma-separated extra environment variables to report, by name or prefix ending in *, such as 'MY_APP_*,DATABASE_URL'"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of a directory to report disk space for (default: the workspace root or the server's working directory)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads system information and runs version commands
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // Reports the same environment each time, bar free memory and disk
+		mcp.WithOpenWorldHintAnnotation(false),   // Inspects the local machine only
+	)
+}
+
+// Execute gathers the requested sections
+func (t *EnvInfoTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	wanted, err := list(args, "sections", sections)
+	if err != nil {
+		return nil, err
+	}
+	if len(wanted) == 0 {
+		wanted = sections
+	}
+	toolNames, err := list(args, "toolchains", probeNames())
+	if err != nil {
+		return nil, err
+	}
+	var extra []string
+	if raw, ok := args["env"].(string); ok {
+		for name := range strings.SplitSeq(raw, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if !envNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("invalid env entry %q: expected a variable name or a prefix ending in *", name)
+			}
+			extra = append(extra, name)
+		}
+	}
+	diskPath, err := diskDirectory(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("sections", wanted).Debug("Gathering environment information")
+
+	result := &Result{}
+	var notes []string
+	var wg sync.WaitGroup
+	if slices.Contains(wanted, "toolchains") {
+		// Version commands are the slow part, so run them while the rest is gathered
+		wg.Go(func() {
+			result.Toolchains = toolchains(ctx, toolNames)
+		})
+	}
+	if slices.Contains(wanted, "system") {
+		result.System = system(ctx)
+	}
+	if slices.Contains(wanted, "path") {
+		result.Path = pathInfo()
+	}
+	if slices.Contains(wanted, "resources") {
+		result.Resources = &Resources{}
+		if m, err := memory(ctx); err == nil {
+			result.Resources.Memory = m
+		} else {
+			notes = append(notes, fmt.Sprintf("Memory could not be read: %v.", err))
+		}
+		if total, free, err := diskSpace(diskPath); err == nil {
+			result.Resources.Disk = &Disk{Path: diskPath, Total: formatSize(total), Free: formatSize(free), TotalBytes: total, FreeBytes: free}
+		} else {
+			notes = append(notes, fmt.Sprintf("Disk space for %s could not be read: %v.", diskPath, err))
+		}
+	}
+	if slices.Contains(wanted, "environment") {
+		result.Environment = environment(extra)
+	}
+	wg.Wait()
+	result.Note = strings.Join(notes, " ")
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(strings.TrimSuffix(buffer.String(), "\n")), nil
+}
+
+// system identifies the machine and user
+func system(ctx context.Context) *System {
+	s := &System{OS: runtime.GOOS, Arch: runtime.GOARCH, CPUs: runtime.NumCPU(), Container: inContainer()}
+	s.Version, s.Kernel = osVersion(ctx)
+	s.WSL = runtime.GOOS == "linux" && strings.Contains(strings.ToLower(s.Kernel), "microsoft")
+	s.Hostname, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		s.User = u.Username
+	}
+	s.Home, _ = os.UserHomeDir()
+	s.Shell = os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		s.Shell = os.Getenv("ComSpec")
+	}
+	s.WorkingDir, _ = os.Getwd()
+	return s
+}
+
+// diskDirectory returns the directory to report disk space for
+func diskDirectory(ctx context.Context, args map[string]any) (string, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		if roots := workspace.Roots(ctx); len(roots) > 0 {
+			return roots[0], nil
+		}
+		return os.Getwd()
+	}
+	dir, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// list reads a comma-separated parameter whose entries must be among allowed
+func list(args map[string]any, key string, allowed []string) ([]string, error) {
+	raw, _ := args[key].(string)
+	var values []string
+	for value := range strings.SplitSeq(raw, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(allowed, value) {
+			return nil, fmt.Errorf("unknown %s entry %q: must be one of %s", key, value, strings.Join(allowed, ", "))
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the env_info tool
+func (t *EnvInfoTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "At the start of a task, to learn the OS, which toolchains and versions are installed and how the environment is configured before choosing commands. When a command is missing or behaves differently than expected, to check PATH order and version managers.",
+		WhenNotToUse: "Finding a project's dependencies or language version requirements - read its manifests or use dep_graph. Checking running containers or clusters - use containers or kubectl directly.",
+		CommonPatterns: []string{
+			"Call with no arguments once per session and reuse the answer",
+			"Check one toolchain quickly with sections='toolchains' and toolchains='node'",
+			"Report an application's settings with env='MY_APP_*'",
+		},
+		ParameterDetails: map[string]string{
+			"sections":   "system: OS, version, kernel, host, user, shell, CPUs and whether the server runs in a container or WSL. toolchains: versions and paths. path: PATH in search order with version manager directories named, missing and duplicate entries. resources: memory, any container memory limit, and disk space. environment: variables that change how builds and CLIs behave.",
+			"toolchains": "Each is found on PATH and asked for its version with a command that changes nothing, such as 'go version' or 'kubectl version --client'. docker and kubectl report only their client versions.",
+			"env":        "Added to the default list of build, language, proxy and cloud variables. Values of variables whose names suggest secrets (TOKEN, SECRET, PASSWORD, API_KEY, AUTH, CREDENTIAL) are shown as [REDACTED], and credentials in URLs are masked.",
+			"path":       "Only used for the disk space in resources.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Report the whole environment",
+				Arguments:      map[string]any{},
+				ExpectedResult: "OS details, toolchain versions, PATH entries, memory and disk space, and relevant environment variables",
+			},
+			{
+				Description:    "Check the Go and Node versions",
+				Arguments:      map[string]any{"sections": "toolchains", "toolchains": "go,node"},
+				ExpectedResult: "The version and path of go and node, or installed: false",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A toolchain shows installed: false but works in a terminal",
+				Solution: "The server's PATH can differ from an interactive shell's, for example when version managers are set up in shell profiles. Check the path section and set PATH in the MCP client's configuration for the server.",
+			},
+			{
+				Problem:  "A toolchain has an error instead of a version",
+				Solution: "It was found but its version command failed or took over 10 seconds, often a version manager shim with no version selected for the directory.",
+			},
+		},
+	}
+}
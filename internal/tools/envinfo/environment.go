@@ -0,0 +1,147 @@
+package envinfo
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// redacted replaces the values of variables that hold secrets
+const redacted = "[REDACTED]"
+
+var (
+	// secretEnvPattern matches the names of variables that hold secrets
+	secretEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTH)`)
+
+	// envNamePattern matches a variable name, or a prefix ending in *
+	envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\*?$`)
+)
+
+// environmentNames are the variables reported when they are set: those that change how builds,
+// package managers and cloud CLIs behave
+var environmentNames = []string{
+	"SHELL", "TERM", "LANG", "LC_ALL", "TZ", "EDITOR", "TMPDIR", "CI", "GITHUB_ACTIONS", "GITLAB_CI",
+	"GOPATH", "GOROOT", "GOBIN", "GOFLAGS", "GOPROXY", "GOPRIVATE", "GOTOOLCHAIN", "CGO_ENABLED",
+	"NODE_ENV", "NODE_OPTIONS", "NVM_DIR", "NPM_CONFIG_REGISTRY",
+	"VIRTUAL_ENV", "CONDA_DEFAULT_ENV", "PYTHONPATH", "PYENV_VERSION", "PIP_INDEX_URL", "UV_INDEX_URL",
+	"JAVA_HOME", "CARGO_HOME", "RUSTUP_HOME", "RUSTUP_TOOLCHAIN",
+	"DOCKER_HOST", "DOCKER_CONTEXT", "KUBECONFIG", "AWS_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "CLOUDSDK_CORE_PROJECT",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+}
+
+// pathNotes name well-known PATH directories, matched against slash-separated paths
+var pathNotes = []struct {
+	fragment string
+	note     string
+}{
+	{"/.pyenv/shims", "pyenv shims"},
+	{"/.rbenv/shims", "rbenv shims"},
+	{"/.asdf/shims", "asdf shims"},
+	{"/mise/shims", "mise shims"},
+	{"/mise/installs/", "mise"},
+	{"/.nvm/versions/", "nvm"},
+	{"/fnm_multishells/", "fnm"},
+	{"/.volta/bin", "Volta"},
+	{"/.sdkman/", "SDKMAN"},
+	{"/node_modules/.bin", "project node_modules"},
+	{"/.cargo/bin", "Cargo binaries"},
+	{"/usr/local/go/bin", "Go toolchain"},
+	{"/go/bin", "Go binaries"},
+	{"/.bun/bin", "Bun"},
+	{"/.deno/bin", "Deno"},
+	{"/opt/homebrew/", "Homebrew"},
+	{"/.linuxbrew/", "Homebrew"},
+	{"/.nix-profile/", "Nix"},
+	{"/nix/", "Nix"},
+	{"/miniconda", "Conda"},
+	{"/anaconda", "Conda"},
+	{"/miniforge", "Conda"},
+	{"/.local/bin", "user binaries"},
+}
+
+// pathInfo lists the PATH directories, marking those that do not exist or repeat an earlier one
+func pathInfo() *PathInfo {
+	info := &PathInfo{Entries: []PathEntry{}}
+	seen := map[string]bool{}
+	virtualEnv := os.Getenv("VIRTUAL_ENV")
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entry := PathEntry{Dir: dir, Note: pathNote(dir, virtualEnv)}
+		if !filepath.IsAbs(dir) {
+			entry.Note = "relative - resolved against the working directory"
+		}
+		if stat, err := os.Stat(dir); err != nil || !stat.IsDir() {
+			entry.Missing = true
+		}
+		if key := filepath.Clean(dir); seen[key] {
+			entry.Duplicate = true
+		} else {
+			seen[key] = true
+		}
+		if entry.Missing {
+			info.Missing++
+		}
+		if entry.Duplicate {
+			info.Duplicates++
+		}
+		info.Entries = append(info.Entries, entry)
+	}
+	return info
+}
+
+// pathNote names a known PATH directory
+func pathNote(dir, virtualEnv string) string {
+	if virtualEnv != "" && strings.HasPrefix(filepath.Clean(dir), filepath.Clean(virtualEnv)) {
+		return "active virtualenv"
+	}
+	slashed := filepath.ToSlash(dir)
+	for _, n := range pathNotes {
+		if strings.Contains(slashed+"/", n.fragment) {
+			return n.note
+		}
+	}
+	return ""
+}
+
+// environment returns the relevant variables that are set and those requested by name or prefix,
+// with secret values masked
+func environment(extra []string) map[string]string {
+	values := map[string]string{}
+	for _, name := range environmentNames {
+		if value, ok := os.LookupEnv(name); ok {
+			values[name] = value
+		}
+	}
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		for _, pattern := range extra {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); (ok && strings.HasPrefix(name, prefix)) || name == pattern {
+				values[name] = value
+			}
+		}
+	}
+	for name, value := range values {
+		values[name] = mask(name, value)
+	}
+	return values
+}
+
+// mask hides a variable's value when its name suggests a secret, and credentials in URLs and
+// tokens within other values
+func mask(name, value string) string {
+	if secretEnvPattern.MatchString(name) && value != "" {
+		return redacted
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		u.User = url.User(redacted)
+		value = strings.Replace(u.String(), url.PathEscape(redacted), redacted, 1)
+	}
+	value, _ = security.Redact(value)
+	return value
+}
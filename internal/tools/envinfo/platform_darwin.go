@@ -0,0 +1,71 @@
+//go:build darwin
+
+package envinfo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// vmStatPageSizePattern matches the page size in vm_stat's header
+	vmStatPageSizePattern = regexp.MustCompile(`page size of (\d+) bytes`)
+
+	// vmStatPattern matches a vm_stat page count, such as "Pages free:   12345."
+	vmStatPattern = regexp.MustCompile(`(?m)^Pages (free|inactive|speculative):\s+(\d+)`)
+)
+
+// osVersion returns the macOS version from sw_vers and the Darwin kernel release
+func osVersion(ctx context.Context) (version, kernel string) {
+	if output, err := run(ctx, "sw_vers"); err == nil {
+		var name, number string
+		for line := range strings.Lines(output) {
+			key, value, _ := strings.Cut(line, ":")
+			switch strings.TrimSpace(key) {
+			case "ProductName":
+				name = strings.TrimSpace(value)
+			case "ProductVersion":
+				number = strings.TrimSpace(value)
+			}
+		}
+		version = strings.TrimSpace(name + " " + number)
+	}
+	if output, err := run(ctx, "uname", "-r"); err == nil {
+		kernel = strings.TrimSpace(output)
+	}
+	return version, kernel
+}
+
+// inContainer reports whether the server runs in a container, which macOS does not have
+func inContainer() bool {
+	return false
+}
+
+// memory reads the physical memory from sysctl, and the free, inactive and speculative pages that
+// can be reused without swapping from vm_stat
+func memory(ctx context.Context) (*Memory, error) {
+	output, err := run(ctx, "sysctl", "-n", "hw.memsize")
+	if err != nil {
+		return nil, err
+	}
+	total, err := strconv.ParseUint(strings.TrimSpace(output), 10, 64)
+	if err != nil || total == 0 {
+		return nil, errors.New("unexpected hw.memsize from sysctl")
+	}
+
+	var available uint64
+	if output, err := run(ctx, "vm_stat"); err == nil {
+		pageSize := uint64(4096)
+		if match := vmStatPageSizePattern.FindStringSubmatch(output); match != nil {
+			pageSize, _ = strconv.ParseUint(match[1], 10, 64)
+		}
+		for _, match := range vmStatPattern.FindAllStringSubmatch(output, -1) {
+			pages, _ := strconv.ParseUint(match[2], 10, 64)
+			available += pages * pageSize
+		}
+	}
+	return newMemory(total, available, 0), nil
+}
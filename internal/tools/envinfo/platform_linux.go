@@ -0,0 +1,82 @@
+//go:build linux
+
+package envinfo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// osVersion returns the distribution's name and version from os-release, and the kernel release
+func osVersion(_ context.Context) (version, kernel string) {
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		kernel = strings.TrimSpace(string(data))
+	}
+	for _, file := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for line := range strings.Lines(string(data)) {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "PRETTY_NAME="); ok {
+				return strings.Trim(value, `"'`), kernel
+			}
+		}
+	}
+	return "", kernel
+}
+
+// inContainer reports whether the server runs in a Docker, Podman or Kubernetes container
+func inContainer() bool {
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+// memory reads the physical memory from /proc/meminfo and the cgroup memory limit, if any
+func memory(_ context.Context) (*Memory, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var total, available uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		// Values are in kB
+		value, _ := strconv.ParseUint(fields[1], 10, 64)
+		switch fields[0] {
+		case "MemTotal:":
+			total = value * 1024
+		case "MemAvailable:":
+			available = value * 1024
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("MemTotal missing from /proc/meminfo")
+	}
+
+	var limit uint64
+	// cgroup v2, then v1, which reports a huge number when there is no limit
+	for _, file := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		if data, err := os.ReadFile(file); err == nil {
+			if value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err == nil && value < total {
+				limit = value
+				break
+			}
+		}
+	}
+	return newMemory(total, available, limit), nil
+}
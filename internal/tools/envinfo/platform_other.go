@@ -0,0 +1,31 @@
+//go:build !linux && !darwin && !windows
+
+package envinfo
+
+import (
+	"context"
+	"errors"
+)
+
+// errUnsupported is returned for resources that are not read on this platform
+var errUnsupported = errors.New("not supported on this platform")
+
+// osVersion returns nothing on platforms without a known version source
+func osVersion(_ context.Context) (version, kernel string) {
+	return "", ""
+}
+
+// inContainer reports whether the server runs in a container, which is not detected here
+func inContainer() bool {
+	return false
+}
+
+// memory is not read on this platform
+func memory(_ context.Context) (*Memory, error) {
+	return nil, errUnsupported
+}
+
+// diskSpace is not read on this platform
+func diskSpace(_ string) (total, free uint64, err error) {
+	return 0, 0, errUnsupported
+}
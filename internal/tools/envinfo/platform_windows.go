@@ -0,0 +1,70 @@
+//go:build windows
+
+package envinfo
+
+import (
+	"context"
+	"regexp"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetDiskFreeSpaceExW  = kernel32.NewProc("GetDiskFreeSpaceExW")
+
+	// windowsVersionPattern matches the build number in ver's output
+	windowsVersionPattern = regexp.MustCompile(`\d+\.\d+\.\d+(?:\.\d+)?`)
+)
+
+// memoryStatusEx is the MEMORYSTATUSEX structure
+type memoryStatusEx struct {
+	length               uint32
+	memoryLoad           uint32
+	totalPhys            uint64
+	availPhys            uint64
+	totalPageFile        uint64
+	availPageFile        uint64
+	totalVirtual         uint64
+	availVirtual         uint64
+	availExtendedVirtual uint64
+}
+
+// osVersion returns the Windows version from ver and its build number as the kernel
+func osVersion(ctx context.Context) (version, kernel string) {
+	output, err := run(ctx, "cmd", "/c", "ver")
+	if err != nil {
+		return "", ""
+	}
+	version = firstLine(output)
+	return version, windowsVersionPattern.FindString(version)
+}
+
+// inContainer reports whether the server runs in a container, which is not detected on Windows
+func inContainer() bool {
+	return false
+}
+
+// memory reads the physical memory from GlobalMemoryStatusEx
+func memory(_ context.Context) (*Memory, error) {
+	status := memoryStatusEx{}
+	status.length = uint32(unsafe.Sizeof(status))
+	if ok, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status))); ok == 0 {
+		return nil, err
+	}
+	return newMemory(status.totalPhys, status.availPhys, 0), nil
+}
+
+// diskSpace returns the size of the volume holding path and the space free to the user
+func diskSpace(path string) (total, free uint64, err error) {
+	pointer, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var available, size, totalFree uint64
+	if ok, _, err := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(pointer)), uintptr(unsafe.Pointer(&available)), uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&totalFree))); ok == 0 {
+		return 0, 0, err
+	}
+	return size, available, nil
+}
@@ -0,0 +1,29 @@
+package envinfo
+
+import "fmt"
+
+// newMemory describes memory sizes in bytes
+func newMemory(total, available, limit uint64) *Memory {
+	m := &Memory{Total: formatSize(total), TotalBytes: total, AvailableBytes: available, LimitBytes: limit}
+	if available > 0 {
+		m.Available = formatSize(available)
+	}
+	if limit > 0 {
+		m.Limit = formatSize(limit)
+	}
+	return m
+}
+
+// formatSize formats a size in bytes in binary units
+func formatSize(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,122 @@
+package envinfo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/procgroup"
+)
+
+// probeTimeout is how long a tool has to report its version
+const probeTimeout = 10 * time.Second
+
+// versionPattern matches a version number such as 1.26.0, v22.1.0 or 1.26rc1
+var versionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?(?:[-+]?[A-Za-z][0-9A-Za-z.-]*)?`)
+
+// probe is a toolchain and the command that prints its version without side effects
+type probe struct {
+	name     string
+	binaries []string
+	args     []string
+}
+
+// probes are the toolchains checked, in the order they are reported
+var probes = []probe{
+	{name: "go", binaries: []string{"go"}, args: []string{"version"}},
+	{name: "node", binaries: []string{"node"}, args: []string{"--version"}},
+	{name: "npm", binaries: []string{"npm"}, args: []string{"--version"}},
+	{name: "python", binaries: []string{"python3", "python"}, args: []string{"--version"}},
+	{name: "uv", binaries: []string{"uv"}, args: []string{"--version"}},
+	{name: "rustc", binaries: []string{"rustc"}, args: []string{"--version"}},
+	{name: "cargo", binaries: []string{"cargo"}, args: []string{"--version"}},
+	{name: "git", binaries: []string{"git"}, args: []string{"--version"}},
+	{name: "make", binaries: []string{"make"}, args: []string{"--version"}},
+	// Only the client versions, so no daemon or cluster is contacted
+	{name: "docker", binaries: []string{"docker"}, args: []string{"--version"}},
+	{name: "podman", binaries: []string{"podman"}, args: []string{"--version"}},
+	{name: "kubectl", binaries: []string{"kubectl"}, args: []string{"version", "--client"}},
+}
+
+// probeNames returns the names of the toolchains that can be checked
+func probeNames() []string {
+	var names []string
+	for _, p := range probes {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+// toolchains checks the named toolchains at the same time
+func toolchains(ctx context.Context, names []string) []Tool {
+	var selected []probe
+	for _, p := range probes {
+		if len(names) == 0 || slices.Contains(names, p.name) {
+			selected = append(selected, p)
+		}
+	}
+	results := make([]Tool, len(selected))
+	var wg sync.WaitGroup
+	for i, p := range selected {
+		wg.Go(func() {
+			results[i] = p.check(ctx)
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// check finds the toolchain on PATH and reads its version
+func (p probe) check(ctx context.Context) Tool {
+	tool := Tool{Name: p.name}
+	for _, binary := range p.binaries {
+		if path, err := exec.LookPath(binary); err == nil {
+			tool.Path = path
+			break
+		}
+	}
+	if tool.Path == "" {
+		return tool
+	}
+	tool.Installed = true
+	output, err := run(ctx, tool.Path, p.args...)
+	if err != nil {
+		tool.Error = cmp.Or(firstLine(output), err.Error())
+		return tool
+	}
+	line := firstLine(output)
+	tool.Version = versionPattern.FindString(line)
+	if tool.Version == "" {
+		tool.Version = line
+	}
+	return tool
+}
+
+// run runs a command with a timeout and returns its combined output
+func run(ctx context.Context, binary string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, binary, args...)
+	procgroup.Set(cmd)
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", errors.New("timed out")
+	}
+	return string(output), err
+}
+
+// firstLine returns the first non-empty line of text
+func firstLine(text string) string {
+	for line := range strings.Lines(text) {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package envinfo
+
+// Result describes the environment the server and the commands it runs see
+type Result struct {
+	System     *System    `json:"system,omitempty"`
+	Toolchains []Tool     `json:"toolchains,omitempty"`
+	Path       *PathInfo  `json:"path,omitempty"`
+	Resources  *Resources `json:"resources,omitempty"`
+	// Environment holds the relevant variables that are set, with secret values masked
+	Environment map[string]string `json:"environment,omitempty"`
+	Note        string            `json:"note,omitempty"`
+}
+
+// System identifies the machine and the user the server runs as
+type System struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	// Version is the distribution or product version, such as "Ubuntu 24.04.1 LTS" or "macOS 15.1"
+	Version    string `json:"version,omitempty"`
+	Kernel     string `json:"kernel,omitempty"`
+	Hostname   string `json:"hostname,omitempty"`
+	User       string `json:"user,omitempty"`
+	Home       string `json:"home,omitempty"`
+	Shell      string `json:"shell,omitempty"`
+	WorkingDir string `json:"working_dir,omitempty"`
+	CPUs       int    `json:"cpus"`
+	Container  bool   `json:"container,omitempty"`
+	WSL        bool   `json:"wsl,omitempty"`
+}
+
+// Tool is an installed toolchain and its version
+type Tool struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+	Path      string `json:"path,omitempty"`
+	// Error is set when the tool is installed but did not report its version
+	Error string `json:"error,omitempty"`
+}
+
+// PathInfo lists the PATH directories in the order they are searched
+type PathInfo struct {
+	Entries    []PathEntry `json:"entries"`
+	Missing    int         `json:"missing"`
+	Duplicates int         `json:"duplicates"`
+}
+
+// PathEntry is a PATH directory
+type PathEntry struct {
+	Dir string `json:"dir"`
+	// Note names what the directory holds when it is a known version manager or tool directory
+	Note      string `json:"note,omitempty"`
+	Missing   bool   `json:"missing,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+// Resources reports memory and disk space
+type Resources struct {
+	Memory *Memory `json:"memory,omitempty"`
+	Disk   *Disk   `json:"disk,omitempty"`
+}
+
+// Memory is the machine's physical memory
+type Memory struct {
+	Total          string `json:"total"`
+	Available      string `json:"available,omitempty"`
+	TotalBytes     uint64 `json:"total_bytes"`
+	AvailableBytes uint64 `json:"available_bytes,omitempty"`
+	// Limit is the container's memory limit, when it has one
+	Limit      string `json:"limit,omitempty"`
+	LimitBytes uint64 `json:"limit_bytes,omitempty"`
+}
+
+// Disk is the space on the file system holding a directory
+type Disk struct {
+	Path       string `json:"path"`
+	Total      string `json:"total"`
+	Free       string `json:"free"`
+	TotalBytes uint64 `json:"total_bytes"`
+	FreeBytes  uint64 `json:"free_bytes"`
+}
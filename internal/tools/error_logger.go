@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ type ToolErrorLogEntry struct {
 	Arguments map[string]any `json:"arguments,omitempty"`
 	Error     string         `json:"error"`
 	Transport string         `json:"transport,omitempty"`
+	SessionID string         `json:"session_id,omitempty"`
 }
 
 // ToolErrorLogger handles logging of tool execution errors
@@ -29,16 +31,24 @@ type ToolErrorLogger struct {
 	logger   *logrus.Logger
 	mu       sync.Mutex
 	filePath string
+	// recent holds the latest errors, without arguments, whether or not file logging is enabled
+	recent []ToolErrorLogEntry
 }
 
 var (
 	globalErrorLogger *ToolErrorLogger
 	errorLoggerOnce   sync.Once
+
+	// fallbackErrorLogger is returned before the global error logger is initialised
+	fallbackErrorLogger = &ToolErrorLogger{enabled: false}
 )
 
 const (
 	// DefaultLogRetentionDays is the default number of days to retain error logs
 	DefaultLogRetentionDays = 60
+
+	// maxRecentErrors is the number of errors kept in memory for RecentErrors
+	maxRecentErrors = 200
 )
 
 // InitGlobalErrorLogger initialises the global error logger
@@ -103,33 +113,34 @@ func InitGlobalErrorLogger(logger *logrus.Logger) error {
 func GetGlobalErrorLogger() *ToolErrorLogger {
 	if globalErrorLogger == nil {
 		// Return a disabled logger if not initialised
-		return &ToolErrorLogger{
-			enabled: false,
-		}
+		return fallbackErrorLogger
 	}
 	return globalErrorLogger
 }
 
-// LogToolError logs a tool execution error
-func (l *ToolErrorLogger) LogToolError(toolName string, args map[string]any, err error, transport string) {
-	if !l.enabled {
-		return
+// LogToolError records a tool execution error in memory for RecentErrors, and logs it to the tool
+// error log file when LOG_TOOL_ERRORS is enabled
+func (l *ToolErrorLogger) LogToolError(toolName string, args map[string]any, err error, transport, sessionID string) {
+	entry := ToolErrorLogEntry{
+		Timestamp: time.Now().Format(time.RFC3339),
+		ToolName:  toolName,
+		Error:     err.Error(),
+		Transport: transport,
+		SessionID: sessionID,
 	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if l.logFile == nil {
-		return
+	if len(l.recent) >= maxRecentErrors {
+		l.recent = append(l.recent[:0], l.recent[1:]...)
 	}
+	l.recent = append(l.recent, entry)
 
-	entry := ToolErrorLogEntry{
-		Timestamp: time.Now().Format(time.RFC3339),
-		ToolName:  toolName,
-		Arguments: args,
-		Error:     err.Error(),
-		Transport: transport,
+	if !l.enabled || l.logFile == nil {
+		return
 	}
+	entry.Arguments = args
 
 	// Marshal to JSON
 	jsonData, marshalErr := json.Marshal(entry)
@@ -171,6 +182,22 @@ func (l *ToolErrorLogger) Close() error {
 	return l.logFile.Close()
 }
 
+// RecentErrors returns up to limit of the most recent tool errors, oldest first, optionally only those
+// of one session
+func (l *ToolErrorLogger) RecentErrors(sessionID string, limit int) []ToolErrorLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var result []ToolErrorLogEntry
+	for i := len(l.recent) - 1; i >= 0 && len(result) < limit; i-- {
+		if sessionID == "" || l.recent[i].SessionID == sessionID {
+			result = append(result, l.recent[i])
+		}
+	}
+	slices.Reverse(result)
+	return result
+}
+
 // IsEnabled returns whether error logging is enabled
 func (l *ToolErrorLogger) IsEnabled() bool {
 	return l.enabled
@@ -9,8 +9,9 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/registry"
-	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workpool"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +21,10 @@ type ExcelTool struct{}
 // Configuration
 var excelBasePath string
 
+// largeReadBytes is the file size above which reading a workbook's data is memory intensive, as an
+// xlsx file is compressed and its cells take many times its size once read
+const largeReadBytes = 5 << 20
+
 // init registers the Excel tool and initialises configuration
 func init() {
 	registry.Register(&ExcelTool{})
@@ -297,7 +302,7 @@ func (t *ExcelTool) Execute(ctx context.Context, logger *logrus.Logger, cache *s
 	}
 
 	// Security integration: check file access
-	if err := security.CheckFileAccess(fullPath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, fullPath); err != nil {
 		return nil, fmt.Errorf("file access denied: %w", err)
 	}
 
@@ -379,6 +384,26 @@ func (t *ExcelTool) Execute(ctx context.Context, logger *logrus.Logger, cache *s
 	}
 }
 
+// IsMemoryIntensive reports whether a call reads the data of a large workbook
+func (t *ExcelTool) IsMemoryIntensive(args map[string]any) bool {
+	switch function, _ := args["function"].(string); function {
+	case "read_data", "read_data_with_metadata", "read_all_data":
+	default:
+		return false
+	}
+	path, _ := args["filepath"].(string)
+	info, err := os.Stat(path)
+	return err == nil && info.Size() > largeReadBytes
+}
+
+// WorkQueue queues reads of large workbooks for a spreadsheet worker
+func (t *ExcelTool) WorkQueue(args map[string]any) string {
+	if t.IsMemoryIntensive(args) {
+		return workpool.Spreadsheets
+	}
+	return ""
+}
+
 // resolveExcelPath validates and returns the absolute file path
 func resolveExcelPath(filePath string) (string, error) {
 	if filePath == "" {
@@ -0,0 +1,281 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 200
+	// cacheTTL is how long a fetched feed is reused before it is fetched again
+	cacheTTL       = 15 * time.Minute
+	cacheKeyPrefix = "feed:"
+	// acceptHeader asks for a feed, for servers that choose the format by content negotiation
+	acceptHeader = "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, application/json;q=0.8, */*;q=0.5"
+)
+
+// relativeSince matches a since value counted back from now, e.g. 90m, 24h, 7d or 2w
+var relativeSince = regexp.MustCompile(`^(\d+)\s*(m|h|d|w)$`)
+
+// FeedTool fetches and parses RSS, Atom and JSON feeds
+type FeedTool struct{}
+
+// cacheEntry is a parsed feed and when it was fetched
+type cacheEntry struct {
+	feed      *parsedFeed
+	fetchedAt time.Time
+}
+
+// init registers the feed tool
+func init() {
+	registry.Register(&FeedTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *FeedTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"feed",
+		mcp.WithDescription(`Fetch an RSS, Atom or JSON feed and return its entries, newest first, each with its title, date, link and a short plain-text summary.
+
+Use since to return only the entries published or updated after a time, for monitoring release feeds, changelogs, status pages and blogs. Feeds are cached for 15 minutes.`),
+		mcp.WithString("url",
+			mcp.Required(),
+			mcp.Description("URL of the feed, e.g. https://github.com/owner/repo/releases.atom"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only return entries published or updated after this time: an RFC 3339 timestamp, a date (YYYY-MM-DD), or a time counted back from now such as 90m, 24h, 7d or 2w"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Most entries to return (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Fetch the feed again instead of using a copy cached in the last 15 minutes (default: false)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only fetches the feed
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // The same feed gives the same entries until it is updated
+		mcp.WithOpenWorldHintAnnotation(true),    // Fetches external URLs
+	)
+}
+
+// Execute fetches the feed and returns its entries
+func (t *FeedTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	rawURL, _ := args["url"].(string)
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, fmt.Errorf("missing required parameter: url")
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("url must be an http or https URL, got: %s", rawURL)
+	}
+
+	now := time.Now()
+	var since *time.Time
+	if value, _ := args["since"].(string); strings.TrimSpace(value) != "" {
+		parsed, err := parseSince(value, now)
+		if err != nil {
+			return nil, err
+		}
+		since = &parsed
+	}
+	limit := defaultLimit
+	if value, ok := args["limit"].(float64); ok {
+		if value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("limit must be a whole number of at least 1, got: %v", value)
+		}
+		limit = min(int(value), maxLimit)
+	}
+	refresh, _ := args["refresh"].(bool)
+
+	logger.WithFields(logrus.Fields{"url": rawURL, "refresh": refresh}).Debug("Fetching feed")
+
+	feed, fetchedAt, cached := loadCached(cache, rawURL, refresh)
+	var warning *security.SecurityResult
+	if !cached {
+		ops := security.NewOperations("feed")
+		resp, err := ops.SafeHTTPGetWithHeaders(ctx, rawURL, map[string]string{"Accept": acceptHeader})
+		if err != nil {
+			if secErr, ok := err.(*security.SecurityError); ok {
+				return nil, security.FormatSecurityBlockError(secErr)
+			}
+			return nil, fmt.Errorf("failed to fetch feed: %w", err)
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("failed to fetch feed: HTTP %d", resp.StatusCode)
+		}
+		if resp.SecurityResult != nil && resp.SecurityResult.Action == security.ActionWarn {
+			warning = resp.SecurityResult
+		}
+		feed, err = parseFeed(resp.Content, target)
+		if err != nil {
+			return nil, err
+		}
+		fetchedAt = now
+		// Feeds with security warnings are not cached, so that the warning is shown each time
+		if warning == nil && cache != nil {
+			cache.Store(cacheKeyPrefix+rawURL, cacheEntry{feed: feed, fetchedAt: fetchedAt})
+		}
+	}
+
+	result := filterEntries(feed, since, limit)
+	result.URL = rawURL
+	result.Cached = cached
+	result.FetchedAt = fetchedAt
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+	if warning != nil {
+		output = security.FormatSecurityWarningPrefix(warning) + output
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// loadCached returns a feed fetched in the last cacheTTL, unless refresh is set
+func loadCached(cache *sync.Map, rawURL string, refresh bool) (*parsedFeed, time.Time, bool) {
+	if cache == nil || refresh {
+		return nil, time.Time{}, false
+	}
+	value, ok := cache.Load(cacheKeyPrefix + rawURL)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	entry, ok := value.(cacheEntry)
+	if !ok || time.Since(entry.fetchedAt) >= cacheTTL {
+		return nil, time.Time{}, false
+	}
+	return entry.feed, entry.fetchedAt, true
+}
+
+// filterEntries returns the feed's entries newer than since, newest first, up to limit. Undated
+// entries keep their place in the feed after the dated ones, and are left out when since is set.
+func filterEntries(feed *parsedFeed, since *time.Time, limit int) *Result {
+	result := &Result{
+		Format:      feed.format,
+		Title:       feed.title,
+		Link:        feed.link,
+		Description: feed.description,
+		Updated:     feed.updated,
+		Total:       len(feed.entries),
+		Since:       since,
+		Entries:     []Entry{},
+	}
+
+	var matched []Entry
+	for _, entry := range feed.entries {
+		if since != nil {
+			date := entry.date()
+			if date == nil {
+				result.Undated++
+				continue
+			}
+			if !date.After(*since) {
+				continue
+			}
+		}
+		matched = append(matched, entry)
+	}
+	slices.SortStableFunc(matched, func(a, b Entry) int {
+		dateA, dateB := a.date(), b.date()
+		switch {
+		case dateA == nil && dateB == nil:
+			return 0
+		case dateA == nil:
+			return 1
+		case dateB == nil:
+			return -1
+		}
+		return dateB.Compare(*dateA)
+	})
+
+	result.Matched = len(matched)
+	if len(matched) > limit {
+		matched = matched[:limit]
+		result.Truncated = true
+	}
+	result.Entries = append(result.Entries, matched...)
+	return result
+}
+
+// parseSince parses a since value: an RFC 3339 timestamp, a date, or a time counted back from now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if match := relativeSince.FindStringSubmatch(strings.ToLower(value)); match != nil {
+		count, err := strconv.Atoi(match[1])
+		if err == nil {
+			unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[2]]
+			return now.Add(-time.Duration(count) * unit), nil
+		}
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("since must be an RFC 3339 timestamp, a date (YYYY-MM-DD) or a time such as 24h or 7d, got: %s", value)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the feed tool
+func (t *FeedTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Checking for new releases of a dependency, recent incidents on a status page, or new posts on a blog or changelog, especially to see only what changed since a time such as the last check.",
+		WhenNotToUse: "Reading the full text of an entry - use fetch_url with its link. Comparing changelogs between package versions - use release_notes. Sites without a feed - use fetch_url.",
+		CommonPatterns: []string{
+			"GitHub publishes releases and tags as Atom feeds: https://github.com/owner/repo/releases.atom and https://github.com/owner/repo/tags.atom",
+			"Status pages hosted by Atlassian Statuspage publish /history.rss and /history.atom, e.g. https://www.githubstatus.com/history.rss",
+			"Use since with the time of the last check to see only new entries",
+			"Follow up interesting entries with fetch_url on their link for the full text",
+		},
+		ParameterDetails: map[string]string{
+			"url":   "RSS 2.0, RSS 1.0 (RDF), Atom and JSON Feed (1.0 and 1.1) are recognised from the content, whatever the server's content type. The request respects the security domain policy.",
+			"since": "Entries are compared by the later of their published and updated dates, and only those strictly after since are returned. Entries without a date are left out when since is set, and counted in undated. Dates without a time are midnight local time.",
+			"limit": "Entries are sorted newest first before the limit is applied. matched says how many entries were newer than since in total, and truncated is set when some were left out.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Check a project's recent releases",
+				Arguments:      map[string]any{"url": "https://github.com/golang/go/releases.atom", "since": "30d"},
+				ExpectedResult: "Releases from the last 30 days, newest first, with their titles, dates, links and summaries",
+			},
+			{
+				Description:    "See incidents since a date",
+				Arguments:      map[string]any{"url": "https://www.githubstatus.com/history.rss", "since": "2026-10-01", "limit": 10},
+				ExpectedResult: "Up to 10 incidents published or updated after 1 October 2026",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The URL is reported as not a feed",
+				Solution: "The URL is probably a web page. Look for a link to the feed in the page's <link rel=\"alternate\"> tags, or try common paths such as /feed, /rss or /atom.xml.",
+			},
+			{
+				Problem:  "New entries do not appear",
+				Solution: "Feeds are cached for 15 minutes. Fetch again with refresh=true.",
+			},
+		},
+	}
+}
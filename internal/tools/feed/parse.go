@@ -0,0 +1,410 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// summaryLength is the most characters of an entry's summary returned
+const summaryLength = 400
+
+// blockElements are the HTML elements whose text is separated from the text around them
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Ul: true, atom.Ol: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Table: true, atom.Tr: true, atom.Td: true, atom.Th: true,
+	atom.Section: true, atom.Article: true, atom.Dt: true, atom.Dd: true, atom.Hr: true,
+}
+
+// dateLayouts are the date formats found in feeds. RSS uses RFC 822 dates, often with the day or
+// seconds missing, and Atom and JSON Feed use RFC 3339.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// rssFeed is an RSS 2.0 document, or an RSS 1.0 (RDF) document, whose items sit beside its channel
+type rssFeed struct {
+	Channel struct {
+		Title         string    `xml:"title"`
+		Links         []string  `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		PubDate       string    `xml:"pubDate"`
+		Date          string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+		Items         []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+}
+
+// rssItem is an RSS item
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Links       []string `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	GUID        string   `xml:"guid"`
+	About       string   `xml:"about,attr"`
+	Author      string   `xml:"author"`
+	Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+}
+
+// atomFeed is an Atom feed
+type atomFeed struct {
+	Title    atomText    `xml:"title"`
+	Subtitle atomText    `xml:"subtitle"`
+	Links    []atomLink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+// atomEntry is an Atom entry
+type atomEntry struct {
+	Title     atomText   `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	ID        string     `xml:"id"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+// atomText is an Atom text construct, which holds text, escaped HTML or XHTML elements
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// plain returns the text, without the markup of HTML and XHTML text
+func (t atomText) plain() string {
+	switch t.Type {
+	case "html":
+		return stripHTML(t.Text)
+	case "xhtml":
+		return stripHTML(t.Inner)
+	default:
+		return t.Text
+	}
+}
+
+// atomLink is an Atom link
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// jsonFeed is a JSON Feed, version 1.0 or 1.1
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Description string `json:"description"`
+	Items       []struct {
+		ID            any      `json:"id"`
+		URL           string   `json:"url"`
+		Title         string   `json:"title"`
+		Summary       string   `json:"summary"`
+		ContentText   string   `json:"content_text"`
+		ContentHTML   string   `json:"content_html"`
+		DatePublished string   `json:"date_published"`
+		DateModified  string   `json:"date_modified"`
+		Tags          []string `json:"tags"`
+		Author        struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Authors []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+	} `json:"items"`
+}
+
+// parseFeed parses an RSS, Atom or JSON feed. Relative links are resolved against the feed's URL.
+func parseFeed(data []byte, base *url.URL) (*parsedFeed, error) {
+	trimmed := bytes.TrimLeft(data, "\ufeff \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSONFeed(trimmed, base)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	decoder.CharsetReader = charset.NewReaderLabel
+	// Feeds are often not well formed, such as using HTML entities in their text
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, errors.New("not an RSS, Atom or JSON feed")
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "rss", "RDF":
+			var feed rssFeed
+			if err := decoder.DecodeElement(&feed, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+			}
+			return convertRSS(&feed, base), nil
+		case "feed":
+			var feed atomFeed
+			if err := decoder.DecodeElement(&feed, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+			}
+			return convertAtom(&feed, base), nil
+		default:
+			return nil, fmt.Errorf("not an RSS, Atom or JSON feed: the document is <%s>", start.Name.Local)
+		}
+	}
+}
+
+// convertRSS converts an RSS document
+func convertRSS(feed *rssFeed, base *url.URL) *parsedFeed {
+	channel := feed.Channel
+	result := &parsedFeed{
+		format:      "rss",
+		title:       cleanText(channel.Title),
+		link:        resolve(base, firstNonEmpty(channel.Links...)),
+		description: summarise(channel.Description),
+		updated:     parseDate(firstNonEmpty(channel.LastBuildDate, channel.PubDate, channel.Date)),
+	}
+	for _, item := range append(channel.Items, feed.Items...) {
+		entry := Entry{
+			Title:      cleanText(item.Title),
+			Link:       resolve(base, firstNonEmpty(append(item.Links, item.About)...)),
+			ID:         strings.TrimSpace(firstNonEmpty(item.GUID, item.About)),
+			Published:  parseDate(firstNonEmpty(item.PubDate, item.Date)),
+			Categories: trimAll(item.Categories),
+			Summary:    summarise(firstNonEmpty(item.Description, item.Content)),
+		}
+		entry.Authors = trimAll(append([]string{item.Author}, item.Creators...))
+		result.entries = append(result.entries, entry)
+	}
+	return result
+}
+
+// convertAtom converts an Atom feed
+func convertAtom(feed *atomFeed, base *url.URL) *parsedFeed {
+	result := &parsedFeed{
+		format:      "atom",
+		title:       cleanText(feed.Title.plain()),
+		link:        resolve(base, alternateLink(feed.Links)),
+		description: shorten(feed.Subtitle.plain()),
+		updated:     parseDate(feed.Updated),
+	}
+	for _, item := range feed.Entries {
+		entry := Entry{
+			Title:     cleanText(item.Title.plain()),
+			Link:      resolve(base, alternateLink(item.Links)),
+			ID:        strings.TrimSpace(item.ID),
+			Published: parseDate(item.Published),
+			Updated:   parseDate(item.Updated),
+			Summary:   shorten(firstNonEmpty(item.Summary.plain(), item.Content.plain())),
+		}
+		for _, author := range item.Authors {
+			entry.Authors = append(entry.Authors, author.Name)
+		}
+		entry.Authors = trimAll(entry.Authors)
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, firstNonEmpty(category.Label, category.Term))
+		}
+		entry.Categories = trimAll(entry.Categories)
+		result.entries = append(result.entries, entry)
+	}
+	return result
+}
+
+// parseJSONFeed parses a JSON Feed
+func parseJSONFeed(data []byte, base *url.URL) (*parsedFeed, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+	if !strings.Contains(feed.Version, "jsonfeed.org") {
+		return nil, errors.New("not a JSON Feed: the version is missing or unknown")
+	}
+
+	result := &parsedFeed{
+		format:      "json",
+		title:       cleanText(feed.Title),
+		link:        resolve(base, feed.HomePageURL),
+		description: summarise(feed.Description),
+	}
+	for _, item := range feed.Items {
+		entry := Entry{
+			Title:      cleanText(item.Title),
+			Link:       resolve(base, item.URL),
+			Published:  parseDate(item.DatePublished),
+			Updated:    parseDate(item.DateModified),
+			Categories: trimAll(item.Tags),
+			Summary:    summarise(firstNonEmpty(item.Summary, item.ContentText, item.ContentHTML)),
+		}
+		if item.ID != nil {
+			entry.ID = fmt.Sprint(item.ID)
+		}
+		entry.Authors = []string{item.Author.Name}
+		for _, author := range item.Authors {
+			entry.Authors = append(entry.Authors, author.Name)
+		}
+		entry.Authors = trimAll(entry.Authors)
+		result.entries = append(result.entries, entry)
+	}
+	return result, nil
+}
+
+// alternateLink returns the link to an Atom feed's or entry's web page
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+// parseDate parses a feed date, returning nil for missing or unrecognised dates
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// summarise returns HTML, as RSS and JSON feeds describe their entries, as a short line of text
+func summarise(value string) string {
+	return shorten(stripHTML(value))
+}
+
+// stripHTML returns the text of HTML, separating the text of block elements such as paragraphs and
+// list items so that their words do not run together
+func stripHTML(value string) string {
+	if !strings.Contains(value, "<") && !strings.Contains(value, "&") {
+		return value
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(value), nil)
+	if err != nil {
+		return value
+	}
+	var text strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			return
+		case html.ElementNode:
+			if node.DataAtom == atom.Script || node.DataAtom == atom.Style {
+				return
+			}
+		}
+		block := node.Type == html.ElementNode && blockElements[node.DataAtom]
+		if block {
+			text.WriteString(" ")
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if block {
+			text.WriteString(" ")
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return text.String()
+}
+
+// shorten puts text on one line, shortened to summaryLength characters at a word boundary
+func shorten(value string) string {
+	text := cleanText(value)
+	if utf8.RuneCountInString(text) <= summaryLength {
+		return text
+	}
+	runes := []rune(text)[:summaryLength]
+	if cut := strings.LastIndexByte(string(runes), ' '); cut > summaryLength/2 {
+		return string(runes)[:cut] + "…"
+	}
+	return string(runes) + "…"
+}
+
+// cleanText collapses whitespace
+func cleanText(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// resolve makes a link absolute, relative to the feed's URL
+func resolve(base *url.URL, link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || base == nil {
+		return link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(parsed).String()
+}
+
+// firstNonEmpty returns the first value that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// trimAll returns the values that are not blank, trimmed, or nil if there are none
+func trimAll(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = cleanText(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package feed
+
+import "time"
+
+// Result is a feed's details and its entries, newest first
+type Result struct {
+	URL         string     `json:"url"`
+	Format      string     `json:"format"` // rss, atom or json
+	Title       string     `json:"title,omitempty"`
+	Link        string     `json:"link,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	// Total is the number of entries in the feed, of which Matched are newer than Since
+	Total   int        `json:"total"`
+	Matched int        `json:"matched"`
+	Since   *time.Time `json:"since,omitempty"`
+	// Undated is the number of entries left out because they have no date to compare with Since
+	Undated   int       `json:"undated,omitempty"`
+	Entries   []Entry   `json:"entries"`
+	Truncated bool      `json:"truncated,omitempty"`
+	Cached    bool      `json:"cached,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Entry is one item of a feed
+type Entry struct {
+	Title      string     `json:"title"`
+	Link       string     `json:"link,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	Published  *time.Time `json:"published,omitempty"`
+	Updated    *time.Time `json:"updated,omitempty"`
+	Authors    []string   `json:"authors,omitempty"`
+	Categories []string   `json:"categories,omitempty"`
+	// Summary is the entry's summary, or the start of its content, as plain text
+	Summary string `json:"summary,omitempty"`
+}
+
+// date returns when an entry was last published or updated, or nil if it has no date
+func (e *Entry) date() *time.Time {
+	if e.Updated != nil && (e.Published == nil || e.Updated.After(*e.Published)) {
+		return e.Updated
+	}
+	return e.Published
+}
+
+// parsedFeed is a feed as parsed, before its entries are filtered
+type parsedFeed struct {
+	format      string
+	title       string
+	link        string
+	description string
+	updated     *time.Time
+	entries     []Entry
+}
@@ -16,8 +16,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/registry"
-	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -84,7 +84,7 @@ func (t *FindLongFilesTool) Execute(ctx context.Context, logger *logrus.Logger,
 	logger.Info("Executing find-long-files tool")
 
 	// Parse and validate parameters
-	request, err := t.parseRequest(args)
+	request, err := t.parseRequest(ctx, args)
 	if err != nil {
 		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
@@ -166,7 +166,7 @@ func (t *FindLongFilesTool) Execute(ctx context.Context, logger *logrus.Logger,
 }
 
 // parseRequest parses and validates the tool arguments
-func (t *FindLongFilesTool) parseRequest(args map[string]any) (*FindLongFilesRequest, error) {
+func (t *FindLongFilesTool) parseRequest(ctx context.Context, args map[string]any) (*FindLongFilesRequest, error) {
 	request := &FindLongFilesRequest{
 		LineThreshold:         defaultLineThreshold,
 		AdditionalExcludes:    []string{},
@@ -179,12 +179,11 @@ func (t *FindLongFilesTool) parseRequest(args map[string]any) (*FindLongFilesReq
 		return nil, fmt.Errorf("missing required parameter: path")
 	}
 
-	// Validate that path is absolute
-	if !filepath.IsAbs(pathRaw) {
-		return nil, fmt.Errorf("path must be absolute (e.g., '/Users/username/project'), got: %s", pathRaw)
+	path, err := workspace.ResolveToolPath(ctx, "path", pathRaw)
+	if err != nil {
+		return nil, err
 	}
-
-	request.Path = pathRaw
+	request.Path = path
 
 	// Parse line threshold - check environment variable first
 	if envThreshold := os.Getenv("LONG_FILES_DEFAULT_LENGTH"); envThreshold != "" {
@@ -229,11 +228,6 @@ func (t *FindLongFilesTool) parseRequest(args map[string]any) (*FindLongFilesReq
 		return nil, fmt.Errorf("path does not exist: %s", request.Path)
 	}
 
-	// Security check for file access
-	if err := security.CheckFileAccess(request.Path); err != nil {
-		return nil, err
-	}
-
 	return request, nil
 }
 
@@ -285,6 +279,7 @@ func (t *FindLongFilesTool) findLongFiles(logger *logrus.Logger, request *FindLo
 		// Check file size - skip files larger than 2MB
 		if info.Size() > maxFileSize {
 			relPath, _ := filepath.Rel(request.Path, path)
+			relPath = filepath.ToSlash(relPath)
 			if !strings.HasPrefix(relPath, "./") && !strings.HasPrefix(relPath, "../") {
 				relPath = "./" + relPath
 			}
@@ -320,6 +315,7 @@ func (t *FindLongFilesTool) findLongFiles(logger *logrus.Logger, request *FindLo
 		// Check if file exceeds threshold
 		if lineCount >= request.LineThreshold {
 			relPath, _ := filepath.Rel(request.Path, path)
+			relPath = filepath.ToSlash(relPath)
 			if !strings.HasPrefix(relPath, "./") && !strings.HasPrefix(relPath, "../") {
 				relPath = "./" + relPath
 			}
@@ -327,7 +323,7 @@ func (t *FindLongFilesTool) findLongFiles(logger *logrus.Logger, request *FindLo
 			longFiles = append(longFiles, FileInfo{
 				Path:      relPath,
 				LineCount: lineCount,
-				Directory: filepath.Dir(relPath),
+				Directory: filepath.ToSlash(filepath.Dir(relPath)),
 				SizeBytes: info.Size(),
 			})
 		}
@@ -438,8 +434,9 @@ func (t *FindLongFilesTool) getDefaultExcludePatterns() []string {
 
 // shouldExcludeFile checks if a file should be excluded based on patterns
 func (t *FindLongFilesTool) shouldExcludeFile(path string, gitignorePatterns, additionalExcludes []string) bool {
-	// Get relative path for pattern matching
+	// Patterns use forward slashes on every platform
 	fileName := filepath.Base(path)
+	path = filepath.ToSlash(path)
 
 	// First check default exclusions (most efficient, checks binary files first)
 	defaultExcludes := t.getDefaultExcludePatterns()
@@ -16,9 +16,11 @@ import (
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,6 +35,7 @@ const (
 // FileSystemTool implements filesystem operations with directory access control
 type FileSystemTool struct {
 	allowedDirectories []string
+	workingDirectory   string // Relative paths resolve against this when set, otherwise the process working directory
 	maxFileSize        int64
 	secureFileMode     os.FileMode
 	mu                 sync.RWMutex
@@ -59,29 +62,7 @@ func init() {
 func getAllowedDirectories() []string {
 	// Check for custom allowed directories from environment variable
 	if customDirs := os.Getenv("FILESYSTEM_TOOL_ALLOWED_DIRS"); customDirs != "" {
-		// Split by colon (Unix-style) or semicolon (Windows-style)
-		var dirs []string
-		if strings.Contains(customDirs, ";") {
-			// Windows-style path separator
-			dirs = strings.Split(customDirs, ";")
-		} else {
-			// Unix-style path separator
-			dirs = strings.Split(customDirs, ":")
-		}
-
-		// Clean and validate each directory
-		var validDirs []string
-		for _, dir := range dirs {
-			dir = strings.TrimSpace(dir)
-			if dir != "" {
-				// Convert to absolute path
-				if absDir, err := filepath.Abs(dir); err == nil {
-					validDirs = append(validDirs, absDir)
-				}
-			}
-		}
-
-		if len(validDirs) > 0 {
+		if validDirs := ParseAllowedDirectories(customDirs); len(validDirs) > 0 {
 			return validDirs
 		}
 	}
@@ -90,6 +71,31 @@ func getAllowedDirectories() []string {
 	return getDefaultAllowedDirectories()
 }
 
+// ParseAllowedDirectories parses a FILESYSTEM_TOOL_ALLOWED_DIRS value into absolute directories. Entries
+// are separated by semicolons, or by the platform's list separator when there are none: colons on Unix
+// and semicolons on Windows, so a single Windows path's drive letter is never split off.
+func ParseAllowedDirectories(value string) []string {
+	var dirs []string
+	if strings.Contains(value, ";") {
+		dirs = strings.Split(value, ";")
+	} else {
+		dirs = filepath.SplitList(value)
+	}
+
+	var validDirs []string
+	for _, dir := range dirs {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		// Convert to absolute path
+		if absDir, err := filepath.Abs(dir); err == nil {
+			validDirs = append(validDirs, absDir)
+		}
+	}
+	return validDirs
+}
+
 // getDefaultAllowedDirectories returns default allowed directories
 func getDefaultAllowedDirectories() []string {
 	// Default to current working directory and user home directory
@@ -256,6 +262,9 @@ Functions and their required parameters:
 
 // Execute executes the filesystem tool
 func (t *FileSystemTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	// Scope access and relative paths to the calling session's workspace
+	t = t.forSession(ctx)
+
 	// Create security operations instance
 	ops := security.NewOperations("filesystem")
 
@@ -304,6 +313,40 @@ func (t *FileSystemTool) Execute(ctx context.Context, logger *logrus.Logger, cac
 	}
 }
 
+// forSession returns the tool scoped to the calling session. When the caller's OAuth access policy
+// limits their directories, only those parts of the allowed directories are allowed. When the client
+// shares roots within the allowed directories, only those roots are allowed and relative paths resolve
+// against the first. Otherwise the tool is returned unchanged.
+func (t *FileSystemTool) forSession(ctx context.Context) *FileSystemTool {
+	policyDirs := access.AllowedDirectories(ctx)
+	roots := workspace.Roots(ctx)
+	if policyDirs == nil && len(roots) == 0 {
+		return t
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	allowed := t.allowedDirectories
+	if policyDirs != nil {
+		allowed = workspace.LimitDirectories(policyDirs, allowed)
+		if len(allowed) == 0 {
+			// Nothing is within both, so every path is denied
+			return &FileSystemTool{maxFileSize: t.maxFileSize, secureFileMode: t.secureFileMode}
+		}
+	}
+	allowed = workspace.ScopeDirectories(roots, allowed)
+	if slices.Equal(allowed, t.allowedDirectories) {
+		return t
+	}
+	return &FileSystemTool{
+		allowedDirectories: allowed,
+		workingDirectory:   allowed[0],
+		maxFileSize:        t.maxFileSize,
+		secureFileMode:     t.secureFileMode,
+	}
+}
+
 // validatePath checks if a path is within allowed directories
 func (t *FileSystemTool) validatePath(requestedPath string) (string, error) {
 	t.mu.RLock()
@@ -311,8 +354,8 @@ func (t *FileSystemTool) validatePath(requestedPath string) (string, error) {
 
 	// Note: Security file access control is now handled by helper functions
 
-	// Expand home directory
-	if strings.HasPrefix(requestedPath, "~/") {
+	// Expand home directory, written with either separator on Windows
+	if strings.HasPrefix(requestedPath, "~/") || strings.HasPrefix(requestedPath, "~"+string(filepath.Separator)) {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -320,6 +363,11 @@ func (t *FileSystemTool) validatePath(requestedPath string) (string, error) {
 		requestedPath = filepath.Join(home, requestedPath[2:])
 	}
 
+	// Resolve relative paths against the session workspace when there is one
+	if t.workingDirectory != "" && !filepath.IsAbs(requestedPath) {
+		requestedPath = filepath.Join(t.workingDirectory, requestedPath)
+	}
+
 	// Convert to absolute path
 	absPath, err := filepath.Abs(requestedPath)
 	if err != nil {
@@ -338,7 +386,7 @@ func (t *FileSystemTool) validatePath(requestedPath string) (string, error) {
 		allowedClean := filepath.Clean(allowedAbs)
 
 		// Check if the path is within the allowed directory
-		if cleanPath == allowedClean || strings.HasPrefix(cleanPath+string(filepath.Separator), allowedClean+string(filepath.Separator)) {
+		if workspace.IsWithin(cleanPath, allowedClean) {
 			// Handle symlinks by checking their real path
 			realPath, err := filepath.EvalSymlinks(cleanPath)
 			if err != nil {
@@ -372,7 +420,7 @@ func (t *FileSystemTool) isPathWithinAllowedReal(realPath, allowedClean string)
 	cleanRealPath := filepath.Clean(realPath)
 
 	// Check direct match
-	if cleanRealPath == allowedClean || strings.HasPrefix(cleanRealPath+string(filepath.Separator), allowedClean+string(filepath.Separator)) {
+	if workspace.IsWithin(cleanRealPath, allowedClean) {
 		return true
 	}
 
@@ -380,7 +428,7 @@ func (t *FileSystemTool) isPathWithinAllowedReal(realPath, allowedClean string)
 	allowedReal, err := filepath.EvalSymlinks(allowedClean)
 	if err == nil {
 		allowedRealClean := filepath.Clean(allowedReal)
-		if cleanRealPath == allowedRealClean || strings.HasPrefix(cleanRealPath+string(filepath.Separator), allowedRealClean+string(filepath.Separator)) {
+		if workspace.IsWithin(cleanRealPath, allowedRealClean) {
 			return true
 		}
 	}
@@ -1023,7 +1071,7 @@ func (t *FileSystemTool) allowedBoundary(absDir string) string {
 			continue
 		}
 		allowedClean := filepath.Clean(allowedAbs)
-		if clean == allowedClean || strings.HasPrefix(clean+string(filepath.Separator), allowedClean+string(filepath.Separator)) {
+		if workspace.IsWithin(clean, allowedClean) {
 			return allowedClean
 		}
 
@@ -1031,7 +1079,7 @@ func (t *FileSystemTool) allowedBoundary(absDir string) string {
 		// /tmp -> /private/tmp on macOS, mirroring isPathWithinAllowedReal.
 		if allowedReal, err := filepath.EvalSymlinks(allowedClean); err == nil {
 			allowedRealClean := filepath.Clean(allowedReal)
-			if clean == allowedRealClean || strings.HasPrefix(clean+string(filepath.Separator), allowedRealClean+string(filepath.Separator)) {
+			if workspace.IsWithin(clean, allowedRealClean) {
 				return allowedRealClean
 			}
 		}
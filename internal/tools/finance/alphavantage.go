@@ -0,0 +1,197 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	alphaVantageURL = "https://www.alphavantage.co/query"
+	// compactDays is roughly how far back FX_DAILY's compact output, its latest 100 working days, reaches
+	compactDays = 130
+)
+
+// alphaVantage fetches exchange rates and stock quotes from Alpha Vantage. Each currency pair is a
+// separate request, which counts against the key's daily allowance.
+type alphaVantage struct {
+	tool *FinanceTool
+	key  string
+}
+
+func (alphaVantage) name() string {
+	return "Alpha Vantage"
+}
+
+func (a alphaVantage) rates(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, date string) (rateTable, error) {
+	if len(symbols) == 0 {
+		return rateTable{}, fmt.Errorf("symbols are required with the Alpha Vantage provider, which fetches each currency pair separately")
+	}
+	table := rateTable{base: base, rates: make(map[string]*big.Rat, len(symbols))}
+	for _, symbol := range symbols {
+		var rate *big.Rat
+		var day string
+		var err error
+		if date == "" {
+			rate, day, err = a.latest(ctx, logger, cache, base, symbol)
+		} else {
+			rate, day, err = a.onDate(ctx, logger, cache, base, symbol, date)
+		}
+		if err != nil {
+			return rateTable{}, err
+		}
+		table.rates[symbol] = rate
+		table.date = max(table.date, day)
+	}
+	return table, nil
+}
+
+func (a alphaVantage) series(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, start, end string) ([]rateTable, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbols are required with the Alpha Vantage provider, which fetches each currency pair separately")
+	}
+	byDate := map[string]map[string]*big.Rat{}
+	for _, symbol := range symbols {
+		closes, err := a.daily(ctx, logger, cache, base, symbol, start)
+		if err != nil {
+			return nil, err
+		}
+		for date, rate := range closes {
+			if date < start || date > end {
+				continue
+			}
+			if byDate[date] == nil {
+				byDate[date] = map[string]*big.Rat{}
+			}
+			byDate[date][symbol] = rate
+		}
+	}
+	tables := make([]rateTable, 0, len(byDate))
+	for _, date := range slices.Sorted(maps.Keys(byDate)) {
+		tables = append(tables, rateTable{base: base, date: date, rates: byDate[date]})
+	}
+	return tables, nil
+}
+
+// latest returns the current rate for a currency pair and the day it is from
+func (a alphaVantage) latest(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base, symbol string) (*big.Rat, string, error) {
+	params := url.Values{"function": {"CURRENCY_EXCHANGE_RATE"}, "from_currency": {base}, "to_currency": {symbol}}
+	var response struct {
+		Rate map[string]string `json:"Realtime Currency Exchange Rate"`
+	}
+	if err := a.tool.fetchJSON(ctx, logger, cache, a.request(params, latestTTL), &response); err != nil {
+		return nil, "", err
+	}
+	text, ok := response.Rate["5. Exchange Rate"]
+	if !ok {
+		return nil, "", fmt.Errorf("no Alpha Vantage rate for %s to %s", base, symbol)
+	}
+	rate, err := parseRate(text)
+	if err != nil {
+		return nil, "", err
+	}
+	day, _, _ := strings.Cut(response.Rate["6. Last Refreshed"], " ")
+	return rate, day, nil
+}
+
+// onDate returns the closing rate for a currency pair on a date, or on the last working day before it
+func (a alphaVantage) onDate(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base, symbol, date string) (*big.Rat, string, error) {
+	closes, err := a.daily(ctx, logger, cache, base, symbol, date)
+	if err != nil {
+		return nil, "", err
+	}
+	day := ""
+	for d := range closes {
+		if d <= date && d > day {
+			day = d
+		}
+	}
+	if day == "" {
+		return nil, "", fmt.Errorf("no Alpha Vantage rate for %s to %s on or before %s", base, symbol, date)
+	}
+	return closes[day], day, nil
+}
+
+// daily returns a currency pair's closing rate on each working day, reaching back to at least since
+func (a alphaVantage) daily(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base, symbol, since string) (map[string]*big.Rat, error) {
+	size := "compact"
+	if from, err := time.Parse(dateLayout, since); err == nil && time.Since(from) > compactDays*24*time.Hour {
+		size = "full"
+	}
+	params := url.Values{"function": {"FX_DAILY"}, "from_symbol": {base}, "to_symbol": {symbol}, "outputsize": {size}}
+	var response struct {
+		Series map[string]map[string]string `json:"Time Series FX (Daily)"`
+	}
+	if err := a.tool.fetchJSON(ctx, logger, cache, a.request(params, latestTTL), &response); err != nil {
+		return nil, err
+	}
+	closes := make(map[string]*big.Rat, len(response.Series))
+	for date, day := range response.Series {
+		rate, err := parseRate(day["4. close"])
+		if err != nil {
+			return nil, fmt.Errorf("%s to %s on %s: %w", base, symbol, date, err)
+		}
+		closes[date] = rate
+	}
+	return closes, nil
+}
+
+// quote returns the latest quote for a stock symbol, or false if Alpha Vantage does not know it
+func (a alphaVantage) quote(ctx context.Context, logger *logrus.Logger, cache *sync.Map, symbol string) (stockQuote, bool, error) {
+	params := url.Values{"function": {"GLOBAL_QUOTE"}, "symbol": {symbol}}
+	var response struct {
+		Quote map[string]string `json:"Global Quote"`
+	}
+	if err := a.tool.fetchJSON(ctx, logger, cache, a.request(params, quoteTTL), &response); err != nil {
+		return stockQuote{}, false, err
+	}
+	q := response.Quote
+	if q["05. price"] == "" {
+		return stockQuote{}, false, nil
+	}
+	return stockQuote{
+		Symbol:           q["01. symbol"],
+		Price:            tidy(q["05. price"]),
+		Change:           tidy(q["09. change"]),
+		ChangePercent:    tidy(strings.TrimSuffix(q["10. change percent"], "%")),
+		Open:             tidy(q["02. open"]),
+		High:             tidy(q["03. high"]),
+		Low:              tidy(q["04. low"]),
+		PreviousClose:    tidy(q["08. previous close"]),
+		Volume:           q["06. volume"],
+		LatestTradingDay: q["07. latest trading day"],
+	}, true, nil
+}
+
+// request returns a request to the API with the key added
+func (a alphaVantage) request(params url.Values, ttl time.Duration) apiRequest {
+	return apiRequest{endpoint: alphaVantageURL, params: params, keyParam: "apikey", key: a.key, ttl: ttl, check: checkAlphaVantage}
+}
+
+// checkAlphaVantage returns the error an Alpha Vantage response reports. The API answers invalid
+// requests, and requests beyond the key's allowance, with status 200 and a message in place of the data.
+func checkAlphaVantage(body []byte) error {
+	var response struct {
+		ErrorMessage string `json:"Error Message"`
+		Note         string `json:"Note"`
+		Information  string `json:"Information"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return nil
+	}
+	for _, message := range []string{response.ErrorMessage, response.Note, response.Information} {
+		if message != "" {
+			return fmt.Errorf("request refused by Alpha Vantage: %s", message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+// maxResponseBytes limits the size of an API response
+const maxResponseBytes = 10 * 1024 * 1024
+
+// defaultClient is the shared HTTP client, which counts calls against USAGE_LIMIT_ALPHAVANTAGE
+var defaultClient = sync.OnceValue(func() packageversions.HTTPClient {
+	return httpclient.New(httpclient.Options{Timeout: 30 * time.Second})
+})
+
+// cacheEntry stores a fetched response body
+type cacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
+}
+
+// httpClient returns the configured client, defaulting to the shared client
+func (t *FinanceTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return defaultClient()
+	}
+	return t.client
+}
+
+// apiRequest is a GET request to a provider's API
+type apiRequest struct {
+	endpoint string
+	params   url.Values
+	// keyParam and key add an API key to the query. The key is left out of cache keys, logs and errors.
+	keyParam string
+	key      string
+	// ttl is how long the response is cached for
+	ttl time.Duration
+	// check returns the error a successful response reports in its body, if any, so that it is not cached
+	check func(body []byte) error
+}
+
+// fetch makes a request and returns the response body, cached for the request's ttl
+func (t *FinanceTool) fetch(ctx context.Context, logger *logrus.Logger, cache *sync.Map, r apiRequest) ([]byte, error) {
+	cacheKey := "finance:" + r.endpoint + "?" + r.params.Encode()
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < r.ttl {
+			return entry.body, nil
+		}
+	}
+
+	parsed, err := url.Parse(r.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if err := security.CheckDomainAccess(parsed.Hostname()); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return nil, security.FormatSecurityBlockError(secErr)
+		}
+		return nil, err
+	}
+
+	query := maps.Clone(r.params)
+	if r.key != "" {
+		query.Set(r.keyParam, r.key)
+	}
+	logger.WithFields(logrus.Fields{"endpoint": r.endpoint, "params": r.params.Encode()}).Debug("Fetching financial data")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mcp-devtools/1.0")
+	resp, err := t.httpClient().Do(req)
+	if err != nil {
+		// A url.Error repeats the request URL, API key and all
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("request to %s failed: %w", parsed.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", parsed.Host, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &response) == nil && response.Message != "" {
+			return nil, fmt.Errorf("%s returned status code %d: %s", parsed.Host, resp.StatusCode, response.Message)
+		}
+		return nil, fmt.Errorf("%s returned status code %d", parsed.Host, resp.StatusCode)
+	}
+	if r.check != nil {
+		if err := r.check(body); err != nil {
+			return nil, err
+		}
+	}
+
+	cache.Store(cacheKey, cacheEntry{body: body, fetchedAt: time.Now()})
+	return body, nil
+}
+
+// fetchJSON makes a request and decodes its JSON body into out
+func (t *FinanceTool) fetchJSON(ctx context.Context, logger *logrus.Logger, cache *sync.Map, r apiRequest, out any) error {
+	body, err := t.fetch(ctx, logger, cache, r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", r.endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,653 @@
+// Package finance implements the finance tool, which fetches exchange rates and stock quotes. Current
+// rates it fetches are shared with the calc tool for its currency conversions.
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/calc"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// latestTTL is how long current rates are cached. Reference rates are published once a working day.
+	latestTTL = 1 * time.Hour
+	// historicalTTL is how long rates for past days are cached
+	historicalTTL = 24 * time.Hour
+	// quoteTTL is how long stock quotes are cached
+	quoteTTL = 15 * time.Minute
+
+	defaultBase       = "USD"
+	maxSymbols        = 10
+	maxHistorySymbols = 5
+	maxQuotes         = 5
+	maxHistoryDays    = 366
+	dateLayout        = "2006-01-02"
+
+	actionRates   = "rates"
+	actionConvert = "convert"
+	actionHistory = "history"
+	actionQuote   = "quote"
+)
+
+var (
+	// currencyPattern matches ISO 4217 currency codes
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	// stockPattern matches stock symbols, with an exchange suffix where needed, e.g. AAPL, BRK.B or TSCO.LON
+	stockPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9.:-]{0,19}$`)
+)
+
+// FinanceTool fetches exchange rates and stock quotes
+type FinanceTool struct {
+	client packageversions.HTTPClient
+}
+
+// ratesResult is the result of the rates action
+type ratesResult struct {
+	Provider string            `json:"provider"`
+	Base     string            `json:"base"`
+	Date     string            `json:"date"`
+	Rates    map[string]string `json:"rates"`
+	Note     string            `json:"note,omitempty"`
+}
+
+// convertResult is the result of the convert action
+type convertResult struct {
+	Provider string `json:"provider,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Amount   string `json:"amount"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Rate     string `json:"rate"`
+	Result   string `json:"result"`
+	Note     string `json:"note,omitempty"`
+}
+
+// historyResult is the result of the history action
+type historyResult struct {
+	Provider string                       `json:"provider"`
+	Base     string                       `json:"base"`
+	Start    string                       `json:"start"`
+	End      string                       `json:"end"`
+	Summary  map[string]change            `json:"summary"`
+	Rates    map[string]map[string]string `json:"rates"`
+	Note     string                       `json:"note,omitempty"`
+}
+
+// change summarises a currency's rates over a range of days
+type change struct {
+	First         string `json:"first"`
+	Last          string `json:"last"`
+	Min           string `json:"min"`
+	Max           string `json:"max"`
+	ChangePercent string `json:"change_percent"`
+}
+
+// quoteResult is the result of the quote action
+type quoteResult struct {
+	Provider string       `json:"provider"`
+	Quotes   []stockQuote `json:"quotes"`
+	NotFound []string     `json:"not_found,omitempty"`
+	Note     string       `json:"note"`
+}
+
+// stockQuote is the latest price of a stock
+type stockQuote struct {
+	Symbol           string `json:"symbol"`
+	Price            string `json:"price"`
+	Change           string `json:"change"`
+	ChangePercent    string `json:"change_percent"`
+	Open             string `json:"open"`
+	High             string `json:"high"`
+	Low              string `json:"low"`
+	PreviousClose    string `json:"previous_close"`
+	Volume           string `json:"volume"`
+	LatestTradingDay string `json:"latest_trading_day"`
+}
+
+// init registers the finance tool
+func init() {
+	registry.Register(&FinanceTool{})
+}
+
+// NewFinanceTool creates a finance tool that uses the given HTTP client
+func NewFinanceTool(client packageversions.HTTPClient) *FinanceTool {
+	return &FinanceTool{client: client}
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *FinanceTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"finance",
+		mcp.WithDescription(`Current and historical exchange rates, currency conversion and basic stock quotes.
+
+Actions:
+- rates: exchange rates from a base currency, latest or on a date
+- convert: convert an amount between currencies, at the latest rate or on a date
+- history: daily rates over a date range, with the change over it
+- quote: latest price and daily change for stock symbols (needs an Alpha Vantage API key)
+
+Latest rates that include US dollars are also used by the calc tool for its currency conversions.`),
+		mcp.WithString("action",
+			mcp.Description("What to fetch (default: rates)"),
+			mcp.Enum(actionRates, actionConvert, actionHistory, actionQuote),
+		),
+		mcp.WithString("base",
+			mcp.Description("For rates and history, the currency rates are given from, e.g. EUR (default: USD)"),
+		),
+		mcp.WithArray("symbols",
+			mcp.Description(fmt.Sprintf("Currency codes for rates and history, e.g. [\"EUR\", \"JPY\"] (max: %d, or %d for history), or stock symbols for quote, e.g. [\"AAPL\", \"TSCO.LON\"] (max: %d)", maxSymbols, maxHistorySymbols, maxQuotes)),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("amount",
+			mcp.Description("For convert, the amount to convert (default: 1)"),
+		),
+		mcp.WithString("from",
+			mcp.Description("For convert, the currency to convert from, e.g. GBP"),
+		),
+		mcp.WithString("to",
+			mcp.Description("For convert, the currency to convert to, e.g. AUD"),
+		),
+		mcp.WithString("date",
+			mcp.Description("For rates and convert, a past date (YYYY-MM-DD) to use that day's rates instead of the latest"),
+		),
+		mcp.WithString("start",
+			mcp.Description(fmt.Sprintf("For history, the first day (YYYY-MM-DD). At most %d days before end", maxHistoryDays)),
+		),
+		mcp.WithString("end",
+			mcp.Description("For history, the last day (YYYY-MM-DD, default: today)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads market data
+		mcp.WithDestructiveHintAnnotation(false), // No side effects beyond sharing rates with calc
+		mcp.WithIdempotentHintAnnotation(false),  // Latest rates and quotes change over time
+		mcp.WithOpenWorldHintAnnotation(true),    // Calls exchange rate and market data APIs
+	)
+}
+
+// Execute fetches the requested rates or quotes
+func (t *FinanceTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionRates
+	if a, ok := args["action"].(string); ok && a != "" {
+		action = a
+	}
+	logger.WithField("action", action).Debug("Executing finance")
+
+	var result any
+	var err error
+	switch action {
+	case actionRates:
+		result, err = t.rates(ctx, logger, cache, args)
+	case actionConvert:
+		result, err = t.convert(ctx, logger, cache, args)
+	case actionHistory:
+		result, err = t.history(ctx, logger, cache, args)
+	case actionQuote:
+		result, err = t.quote(ctx, logger, cache, args)
+	default:
+		return nil, fmt.Errorf("action must be one of: rates, convert, history, quote")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// rates returns the rates from a base currency, latest or on a date
+func (t *FinanceTool) rates(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*ratesResult, error) {
+	base, err := currencyArg(args, "base", defaultBase)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := symbolsArg(args, currencyPattern, maxSymbols, "currency code")
+	if err != nil {
+		return nil, err
+	}
+	date, err := dateArg(args, "date")
+	if err != nil {
+		return nil, err
+	}
+	// Providers do not list the base currency's rate to itself
+	requested := slices.DeleteFunc(slices.Clone(symbols), func(s string) bool { return s == base })
+	if len(symbols) > 0 && len(requested) == 0 {
+		return nil, fmt.Errorf("symbols only lists the base currency %s", base)
+	}
+
+	provider, err := t.fxProvider()
+	if err != nil {
+		return nil, err
+	}
+	table, err := provider.rates(ctx, logger, cache, base, requested, date)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ratesResult{Provider: provider.name(), Base: table.base, Date: table.date, Rates: make(map[string]string, len(table.rates))}
+	for code, rate := range table.rates {
+		result.Rates[code] = decimal(rate, 6, 0)
+	}
+	if slices.Contains(symbols, base) {
+		result.Rates[base] = "1"
+	}
+	result.Note = rateNote(date, table.date, date == "" && publish(provider.name(), table))
+	return result, nil
+}
+
+// convert converts an amount between currencies
+func (t *FinanceTool) convert(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*convertResult, error) {
+	from, err := currencyArg(args, "from", "")
+	if err != nil {
+		return nil, err
+	}
+	to, err := currencyArg(args, "to", "")
+	if err != nil {
+		return nil, err
+	}
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("missing required parameters: from and to, e.g. {\"action\": \"convert\", \"amount\": 250, \"from\": \"EUR\", \"to\": \"AUD\"}")
+	}
+	amount := big.NewRat(1, 1)
+	if v, ok := args["amount"].(float64); ok {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			return nil, fmt.Errorf("amount cannot be negative")
+		}
+		// Parse the amount as written, not as its nearest binary fraction
+		amount.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	date, err := dateArg(args, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &convertResult{Amount: decimal(amount, 10, 0), From: from, To: to, Rate: "1", Result: decimal(amount, 10, 2)}
+	if from == to {
+		return result, nil
+	}
+
+	provider, err := t.fxProvider()
+	if err != nil {
+		return nil, err
+	}
+	table, err := provider.rates(ctx, logger, cache, from, []string{to}, date)
+	if err != nil {
+		return nil, err
+	}
+	rate, ok := table.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%s has no rate from %s to %s", provider.name(), from, to)
+	}
+
+	result.Provider = provider.name()
+	result.Date = table.date
+	result.Rate = decimal(rate, 6, 0)
+	result.Result = decimal(new(big.Rat).Mul(amount, rate), 6, 2)
+	result.Note = rateNote(date, table.date, date == "" && publish(provider.name(), table))
+	return result, nil
+}
+
+// history returns daily rates over a range of days with a summary of each currency's change
+func (t *FinanceTool) history(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*historyResult, error) {
+	base, err := currencyArg(args, "base", defaultBase)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := symbolsArg(args, currencyPattern, maxHistorySymbols, "currency code")
+	if err != nil {
+		return nil, err
+	}
+	symbols = slices.DeleteFunc(symbols, func(s string) bool { return s == base })
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("missing required parameter: symbols, the currencies to show the history of, e.g. [\"EUR\", \"JPY\"]")
+	}
+	start, err := dateArg(args, "start")
+	if err != nil {
+		return nil, err
+	}
+	if start == "" {
+		return nil, fmt.Errorf("missing required parameter: start (YYYY-MM-DD)")
+	}
+	end, err := dateArg(args, "end")
+	if err != nil {
+		return nil, err
+	}
+	if end == "" {
+		end = time.Now().UTC().Format(dateLayout)
+	}
+	startDay, _ := time.Parse(dateLayout, start)
+	endDay, _ := time.Parse(dateLayout, end)
+	if endDay.Before(startDay) {
+		return nil, fmt.Errorf("start %s is after end %s", start, end)
+	}
+	if endDay.Sub(startDay) > maxHistoryDays*24*time.Hour {
+		return nil, fmt.Errorf("the range from %s to %s is longer than %d days", start, end, maxHistoryDays)
+	}
+
+	provider, err := t.fxProvider()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := provider.series(ctx, logger, cache, base, symbols, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%s has no rates from %s to %s", provider.name(), start, end)
+	}
+
+	result := &historyResult{
+		Provider: provider.name(),
+		Base:     base,
+		Start:    tables[0].date,
+		End:      tables[len(tables)-1].date,
+		Summary:  make(map[string]change, len(symbols)),
+		Rates:    make(map[string]map[string]string, len(tables)),
+	}
+	for _, table := range tables {
+		day := make(map[string]string, len(table.rates))
+		for code, rate := range table.rates {
+			day[code] = decimal(rate, 6, 0)
+		}
+		result.Rates[table.date] = day
+	}
+	for _, symbol := range symbols {
+		if summary, ok := summarise(tables, symbol); ok {
+			result.Summary[symbol] = summary
+		}
+	}
+	result.Note = "Rates are only published on working days."
+	return result, nil
+}
+
+// quote returns the latest quotes for stock symbols from Alpha Vantage
+func (t *FinanceTool) quote(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*quoteResult, error) {
+	symbols, err := symbolsArg(args, stockPattern, maxQuotes, "stock symbol")
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("missing required parameter: symbols, the stock symbols to quote, e.g. [\"AAPL\", \"TSCO.LON\"]")
+	}
+	provider, err := t.alphaVantageProvider()
+	if err != nil {
+		return nil, fmt.Errorf("quotes need an Alpha Vantage API key: %w", err)
+	}
+
+	result := &quoteResult{
+		Provider: provider.name(),
+		Quotes:   []stockQuote{},
+		Note:     "Prices are in the currency of the exchange the symbol trades on, and may be delayed. Symbols outside the US need an exchange suffix, e.g. TSCO.LON.",
+	}
+	for _, symbol := range symbols {
+		q, found, err := provider.quote(ctx, logger, cache, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote %s: %w", symbol, err)
+		}
+		if !found {
+			result.NotFound = append(result.NotFound, symbol)
+			continue
+		}
+		result.Quotes = append(result.Quotes, q)
+	}
+	return result, nil
+}
+
+// publish shares current rates with calc as the US dollar value of each currency. Rates that include
+// neither a US dollar base nor a US dollar rate cannot be expressed in US dollars and are not shared.
+func publish(provider string, table rateTable) bool {
+	inUSD := big.NewRat(1, 1)
+	if table.base != "USD" {
+		rate, ok := table.rates["USD"]
+		if !ok {
+			return false
+		}
+		inUSD = rate
+	}
+	usdValues := map[string]*big.Rat{table.base: inUSD}
+	for code, rate := range table.rates {
+		usdValues[code] = new(big.Rat).Quo(inUSD, rate)
+	}
+	// The US dollar is calc's reference currency, worth one US dollar whatever the rates
+	delete(usdValues, "USD")
+	calc.SetCurrencyRates(usdValues, fmt.Sprintf("%s for %s", provider, table.date))
+	return true
+}
+
+// rateNote explains the day rates are from, and whether calc now uses them
+func rateNote(requested, date string, published bool) string {
+	var notes []string
+	if requested != "" && date != requested {
+		notes = append(notes, fmt.Sprintf("No rates were published on %s, so these are from %s, the last working day before it.", requested, date))
+	}
+	if published {
+		notes = append(notes, "The calc tool now uses these rates for currency conversions.")
+	}
+	return strings.Join(notes, " ")
+}
+
+// summarise returns the first, last, lowest and highest rate for a currency over a series of days
+func summarise(tables []rateTable, symbol string) (change, bool) {
+	var first, last, low, high *big.Rat
+	for _, table := range tables {
+		rate, ok := table.rates[symbol]
+		if !ok {
+			continue
+		}
+		if first == nil {
+			first, low, high = rate, rate, rate
+		}
+		last = rate
+		if rate.Cmp(low) < 0 {
+			low = rate
+		}
+		if rate.Cmp(high) > 0 {
+			high = rate
+		}
+	}
+	if first == nil {
+		return change{}, false
+	}
+	percent := new(big.Rat).Sub(last, first)
+	percent.Mul(percent.Quo(percent, first), big.NewRat(100, 1))
+	return change{
+		First:         decimal(first, 6, 0),
+		Last:          decimal(last, 6, 0),
+		Min:           decimal(low, 6, 0),
+		Max:           decimal(high, 6, 0),
+		ChangePercent: percent.FloatString(2),
+	}, true
+}
+
+// cacheTTL returns how long rates up to a date are cached. The latest rates, and so any range that
+// reaches today, may still change; earlier ones do not.
+func cacheTTL(date string) time.Duration {
+	if date == "" || date >= time.Now().UTC().AddDate(0, 0, -1).Format(dateLayout) {
+		return latestTTL
+	}
+	return historicalTTL
+}
+
+// currencyArg returns a currency code parameter, or fallback when it is not given
+func currencyArg(args map[string]any, name, fallback string) (string, error) {
+	raw, _ := args[name].(string)
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if code == "" {
+		return fallback, nil
+	}
+	if !currencyPattern.MatchString(code) {
+		return "", fmt.Errorf("invalid %s %q: use a three-letter currency code such as USD or EUR", name, raw)
+	}
+	return code, nil
+}
+
+// symbolsArg returns the symbols parameter, given as an array or a comma-separated string, upper-cased
+// and without duplicates
+func symbolsArg(args map[string]any, pattern *regexp.Regexp, limit int, kind string) ([]string, error) {
+	var items []string
+	switch raw := args["symbols"].(type) {
+	case nil:
+	case string:
+		items = strings.Split(raw, ",")
+	case []any:
+		for i, item := range raw {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid symbol at index %d: must be a string", i)
+			}
+			items = append(items, s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid 'symbols' parameter: must be an array of strings")
+	}
+
+	var symbols []string
+	for _, item := range items {
+		symbol := strings.ToUpper(strings.TrimSpace(item))
+		if symbol == "" || slices.Contains(symbols, symbol) {
+			continue
+		}
+		if !pattern.MatchString(symbol) {
+			return nil, fmt.Errorf("invalid %s %q", kind, item)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) > limit {
+		return nil, fmt.Errorf("too many symbols: at most %d can be given", limit)
+	}
+	return symbols, nil
+}
+
+// dateArg returns a date parameter in YYYY-MM-DD form, or an empty string when it is not given
+func dateArg(args map[string]any, name string) (string, error) {
+	raw, _ := args[name].(string)
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	date, err := time.Parse(dateLayout, raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: use YYYY-MM-DD", name, raw)
+	}
+	if date.After(time.Now()) {
+		return "", fmt.Errorf("%s %s is in the future", name, raw)
+	}
+	return raw, nil
+}
+
+// decimal formats a number to at least the given significant figures and at least minPlaces decimal
+// places, without trailing zeros beyond them
+func decimal(r *big.Rat, significant, minPlaces int) string {
+	places := minPlaces
+	if f, _ := r.Float64(); f != 0 {
+		places = max(places, significant-int(math.Floor(math.Log10(math.Abs(f))))-1)
+	}
+	s := r.FloatString(places)
+	if places > minPlaces {
+		s = strings.TrimRight(s, "0")
+		dot := strings.IndexByte(s, '.')
+		if decimals := len(s) - dot - 1; decimals < minPlaces {
+			s += strings.Repeat("0", minPlaces-decimals)
+		}
+		s = strings.TrimSuffix(s, ".")
+	}
+	return s
+}
+
+// tidy formats a decimal string from an API without trailing zeros, e.g. 139.5300 as 139.53
+func tidy(text string) string {
+	text = strings.TrimSpace(text)
+	if _, err := strconv.ParseFloat(text, 64); err != nil || !strings.Contains(text, ".") {
+		return text
+	}
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
+
+// ProvideExtendedInfo provides detailed usage information for the finance tool
+func (t *FinanceTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Get the latest rates from euros",
+				Arguments: map[string]any{
+					"base":    "EUR",
+					"symbols": []string{"USD", "GBP", "JPY"},
+				},
+				ExpectedResult: "The value of one euro in each currency, with the day the rates are from",
+			},
+			{
+				Description: "Convert an amount at a past date's rate",
+				Arguments: map[string]any{
+					"action": "convert",
+					"amount": 2500,
+					"from":   "GBP",
+					"to":     "AUD",
+					"date":   "2026-03-31",
+				},
+				ExpectedResult: "The amount in Australian dollars at the rate on 31 March 2026",
+			},
+			{
+				Description: "See how a currency moved over a quarter",
+				Arguments: map[string]any{
+					"action":  "history",
+					"base":    "USD",
+					"symbols": []string{"NZD"},
+					"start":   "2026-07-01",
+					"end":     "2026-09-30",
+				},
+				ExpectedResult: "Daily rates with the first, last, lowest and highest rate and the change in percent",
+			},
+			{
+				Description: "Quote stocks",
+				Arguments: map[string]any{
+					"action":  "quote",
+					"symbols": []string{"MSFT", "TSCO.LON"},
+				},
+				ExpectedResult: "Each stock's latest price, daily change, open, high, low and volume",
+			},
+		},
+		CommonPatterns: []string{
+			"Fetch the latest rates with rates, then use calc for sums that mix currencies with other units",
+			"Use convert with a date to value past invoices or expenses at the rate on the day",
+			"Ask only for the symbols needed; with the Alpha Vantage provider each one is a separate request",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No Frankfurter rates for a currency",
+				Solution: "Frankfurter only has the roughly 30 currencies the European Central Bank publishes reference rates for. Set FINANCE_FX_PROVIDER=alphavantage for others.",
+			},
+			{
+				Problem:  "Request refused by Alpha Vantage",
+				Solution: "The free key allows 25 requests a day. Results are cached, and USAGE_LIMIT_ALPHAVANTAGE can cap requests below the plan's allowance.",
+			},
+			{
+				Problem:  "A quote symbol is not found",
+				Solution: "Symbols outside the US need an exchange suffix, e.g. TSCO.LON for London or RELIANCE.BSE for Bombay.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"symbols": "Currency codes for rates and history, stock symbols for quote. Without symbols, rates lists every currency the provider has (Frankfurter only).",
+			"date":    "Rates for a weekend or holiday are those of the last working day before it; the result's date says which day was used.",
+			"amount":  "Results are exact, rounded to six significant figures and at least two decimal places.",
+		},
+		WhenToUse:    "Use for current or past exchange rates, converting amounts between currencies, and the latest price of listed stocks.",
+		WhenNotToUse: "Don't use for trading decisions, intraday prices or crypto assets; reference rates are published once a working day and quotes may be delayed.",
+	}
+}
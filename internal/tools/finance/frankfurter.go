@@ -0,0 +1,105 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// frankfurterURL is the Frankfurter API, which serves the European Central Bank's reference rates
+// without an API key
+const frankfurterURL = "https://api.frankfurter.dev/v1"
+
+// frankfurter fetches exchange rates from Frankfurter
+type frankfurter struct {
+	tool *FinanceTool
+}
+
+// frankfurterRates is Frankfurter's response for one day
+type frankfurterRates struct {
+	Base  string                 `json:"base"`
+	Date  string                 `json:"date"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// frankfurterSeries is Frankfurter's response for a range of days
+type frankfurterSeries struct {
+	Base  string                            `json:"base"`
+	Rates map[string]map[string]json.Number `json:"rates"`
+}
+
+func (frankfurter) name() string {
+	return "Frankfurter (European Central Bank reference rates)"
+}
+
+func (f frankfurter) rates(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, date string) (rateTable, error) {
+	path := "latest"
+	if date != "" {
+		path = date
+	}
+	var response frankfurterRates
+	if err := f.tool.fetchJSON(ctx, logger, cache, f.request(path, base, symbols, cacheTTL(date)), &response); err != nil {
+		return rateTable{}, f.wrap(err, base, symbols)
+	}
+	rates, err := parseRates(response.Rates)
+	if err != nil {
+		return rateTable{}, err
+	}
+	return rateTable{base: response.Base, date: response.Date, rates: rates}, nil
+}
+
+func (f frankfurter) series(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, start, end string) ([]rateTable, error) {
+	var response frankfurterSeries
+	if err := f.tool.fetchJSON(ctx, logger, cache, f.request(start+".."+end, base, symbols, cacheTTL(end)), &response); err != nil {
+		return nil, f.wrap(err, base, symbols)
+	}
+	tables := make([]rateTable, 0, len(response.Rates))
+	for _, date := range slices.Sorted(maps.Keys(response.Rates)) {
+		rates, err := parseRates(response.Rates[date])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, rateTable{base: response.Base, date: date, rates: rates})
+	}
+	return tables, nil
+}
+
+// request returns a request for the rates at a path: latest, a date, or a range of dates
+func (f frankfurter) request(path, base string, symbols []string, ttl time.Duration) apiRequest {
+	params := url.Values{"base": {base}}
+	if len(symbols) > 0 {
+		params.Set("symbols", strings.Join(symbols, ","))
+	}
+	return apiRequest{endpoint: frankfurterURL + "/" + path, params: params, ttl: ttl}
+}
+
+// wrap explains a failed request. Frankfurter answers a currency it does not have with a 404.
+func (frankfurter) wrap(err error, base string, symbols []string) error {
+	if strings.Contains(err.Error(), "status code 404") {
+		return fmt.Errorf("no Frankfurter rates for %s, which only has the currencies the European Central Bank publishes reference rates for (%w)",
+			strings.Join(append([]string{base}, symbols...), ", "), err)
+	}
+	return fmt.Errorf("failed to fetch Frankfurter rates: %w", err)
+}
+
+// parseRates parses rates given as JSON numbers
+func parseRates(numbers map[string]json.Number) (map[string]*big.Rat, error) {
+	rates := make(map[string]*big.Rat, len(numbers))
+	for code, number := range numbers {
+		rate, err := parseRate(number.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", code, err)
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
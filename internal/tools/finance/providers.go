@@ -0,0 +1,75 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// providerEnvVar selects the exchange rate provider
+	providerEnvVar       = "FINANCE_FX_PROVIDER"
+	providerFrankfurter  = "frankfurter"
+	providerAlphaVantage = "alphavantage"
+	// alphaVantageKeyEnvVar holds the Alpha Vantage API key, needed for quotes and the alphavantage provider
+	alphaVantageKeyEnvVar = "ALPHAVANTAGE_API_KEY"
+)
+
+// rateTable is the value of one unit of a base currency in other currencies on one day
+type rateTable struct {
+	base  string
+	date  string
+	rates map[string]*big.Rat
+}
+
+// fxProvider fetches exchange rates
+type fxProvider interface {
+	// name describes the provider and its rates in results
+	name() string
+	// rates returns the rates from base to each symbol, or to every currency the provider has when there
+	// are no symbols. An empty date is the latest rates, otherwise the rates on the date or, when it was
+	// not a working day, the last one before it.
+	rates(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, date string) (rateTable, error)
+	// series returns the rates from base to each symbol on each working day from start to end
+	series(ctx context.Context, logger *logrus.Logger, cache *sync.Map, base string, symbols []string, start, end string) ([]rateTable, error)
+}
+
+// fxProvider returns the exchange rate provider FINANCE_FX_PROVIDER selects
+func (t *FinanceTool) fxProvider() (fxProvider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv(providerEnvVar))); name {
+	case "", providerFrankfurter:
+		return frankfurter{tool: t}, nil
+	case providerAlphaVantage:
+		provider, err := t.alphaVantageProvider()
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown %s %q: use %s or %s", providerEnvVar, name, providerFrankfurter, providerAlphaVantage)
+	}
+}
+
+// alphaVantageProvider returns the Alpha Vantage provider, which needs an API key
+func (t *FinanceTool) alphaVantageProvider() (alphaVantage, error) {
+	key := credentials.Lookup(alphaVantageKeyEnvVar, credentials.AlphaVantageKey)
+	if key == "" {
+		return alphaVantage{}, fmt.Errorf("an Alpha Vantage API key is needed: set %s, or store one with: mcp-devtools credentials set %s", alphaVantageKeyEnvVar, credentials.AlphaVantageKey)
+	}
+	return alphaVantage{tool: t, key: key}, nil
+}
+
+// parseRate parses a rate given as a decimal string
+func parseRate(text string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q", text)
+	}
+	return rate, nil
+}
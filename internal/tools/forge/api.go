@@ -0,0 +1,151 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+const (
+	requestTimeout   = 30 * time.Second
+	maxResponseBytes = 10 * 1024 * 1024
+)
+
+// apiClient makes authenticated requests to a forge's REST API
+type apiClient struct {
+	http    *http.Client
+	baseURL string // API root, e.g. https://gitlab.example.com/api/v4
+	headers map[string]string
+}
+
+// newAPIClient creates a client that honours the proxy settings
+func newAPIClient(baseURL string, headers map[string]string) *apiClient {
+	return &apiClient{
+		http:    httpclient.NewHTTPClientWithProxy(requestTimeout),
+		baseURL: baseURL,
+		headers: headers,
+	}
+}
+
+// request sends a request and returns the response body and headers. Bodies are sent as JSON
+// and responses outside the 2xx range are returned as errors.
+func (c *apiClient) request(ctx context.Context, method, path string, query url.Values, body any) ([]byte, http.Header, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid API URL %s: %w", endpoint, err)
+	}
+	if err := security.CheckDomainAccess(parsed.Hostname()); err != nil {
+		return nil, nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request to %s failed: %w", parsed.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(data) > maxResponseBytes {
+		return nil, nil, fmt.Errorf("response from %s is larger than %d MB", parsed.Host, maxResponseBytes/1024/1024)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, apiError(resp.StatusCode, data)
+	}
+	return data, resp.Header, nil
+}
+
+// getJSON sends a GET request and decodes the JSON response into out
+func (c *apiClient) getJSON(ctx context.Context, path string, query url.Values, out any) (http.Header, error) {
+	data, header, err := c.request(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return header, nil
+}
+
+// postJSON sends a POST request with a JSON body and decodes the JSON response into out
+func (c *apiClient) postJSON(ctx context.Context, path string, body, out any) error {
+	data, _, err := c.request(ctx, http.MethodPost, path, nil, body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// apiError describes an error response, using the forge's own message when there is one
+func apiError(status int, body []byte) error {
+	var payload struct {
+		Message any    `json:"message"` // GitLab sends a string or an object of field errors
+		Error   string `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"` // Bitbucket Server
+	}
+	message := ""
+	if json.Unmarshal(body, &payload) == nil {
+		switch {
+		case len(payload.Errors) > 0:
+			message = payload.Errors[0].Message
+		case payload.Message != nil:
+			message = fmt.Sprint(payload.Message)
+		case payload.Error != "":
+			message = payload.Error
+		}
+	}
+
+	hint := ""
+	switch status {
+	case http.StatusUnauthorized:
+		hint = " - check the host's access token"
+	case http.StatusForbidden:
+		hint = " - the access token lacks permission"
+	case http.StatusNotFound:
+		hint = " - check the repository path, and that the access token can see it"
+	}
+	if message != "" {
+		return fmt.Errorf("API returned %d: %s%s", status, message, hint)
+	}
+	return fmt.Errorf("API returned %d%s", status, hint)
+}
@@ -0,0 +1,423 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// commitSHAPattern matches a full commit SHA, which needs no lookup
+	commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	// highlightPattern strips the markup code search adds around matches
+	highlightPattern = regexp.MustCompile(`</?em>`)
+)
+
+// bitbucketProvider implements Provider with the Bitbucket Server and Data Center REST APIs
+type bitbucketProvider struct {
+	api  *apiClient
+	host Host
+}
+
+// init registers the Bitbucket Server provider
+func init() {
+	RegisterProvider(KindBitbucket, newBitbucketProvider)
+}
+
+// newBitbucketProvider creates a Bitbucket Server provider that authenticates with an HTTP access token
+func newBitbucketProvider(_ context.Context, host Host, _ *logrus.Logger) (Provider, error) {
+	headers := map[string]string{}
+	if host.Token != "" {
+		headers["Authorization"] = "Bearer " + host.Token
+	}
+	return &bitbucketProvider{api: newAPIClient(host.BaseURL+"/rest", headers), host: host}, nil
+}
+
+type bitbucketUser struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
+	CreatedDate int64  `json:"createdDate"`
+	UpdatedDate int64  `json:"updatedDate"`
+	Author      struct {
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		User   bitbucketUser `json:"user"`
+		Status string        `json:"status"` // APPROVED, NEEDS_WORK or UNAPPROVED
+	} `json:"reviewers"`
+	FromRef struct {
+		DisplayID    string `json:"displayId"`
+		LatestCommit string `json:"latestCommit"`
+	} `json:"fromRef"`
+	ToRef struct {
+		DisplayID string `json:"displayId"`
+	} `json:"toRef"`
+}
+
+type bitbucketComment struct {
+	ID          int64              `json:"id"`
+	Text        string             `json:"text"`
+	Author      bitbucketUser      `json:"author"`
+	CreatedDate int64              `json:"createdDate"`
+	Comments    []bitbucketComment `json:"comments"` // replies
+}
+
+type bitbucketActivity struct {
+	Action        string            `json:"action"`
+	CommentAction string            `json:"commentAction"`
+	Comment       *bitbucketComment `json:"comment"`
+	CommentAnchor *struct {
+		Path     string `json:"path"`
+		Line     int    `json:"line"`
+		Orphaned bool   `json:"orphaned"` // the commented lines are no longer in the diff
+	} `json:"commentAnchor"`
+}
+
+// bitbucketPage is Bitbucket Server's paged response envelope
+type bitbucketPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// repository returns the API path of a repository. Personal repositories use ~user as the project key.
+func (p *bitbucketProvider) repository(repo Repository) string {
+	project, slug, _ := strings.Cut(repo.Path, "/")
+	return "/api/1.0/projects/" + url.PathEscape(project) + "/repos/" + url.PathEscape(slug)
+}
+
+// pullRequestURL returns the web address of a pull request
+func (p *bitbucketProvider) pullRequestURL(repo Repository, number int) string {
+	project, slug, _ := strings.Cut(repo.Path, "/")
+	if user, ok := strings.CutPrefix(project, "~"); ok {
+		return fmt.Sprintf("%s/users/%s/repos/%s/pull-requests/%d", p.host.BaseURL, user, slug, number)
+	}
+	return fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", p.host.BaseURL, project, slug, number)
+}
+
+// SearchCode uses the code search API, which needs Bitbucket Data Center with search enabled
+func (p *bitbucketProvider) SearchCode(ctx context.Context, repo Repository, query string, page, perPage int) (*CodeSearchResult, error) {
+	project, slug, _ := strings.Cut(repo.Path, "/")
+	searchQuery := fmt.Sprintf("project:%s repo:%s %s", project, slug, query)
+	body := map[string]any{
+		"query": searchQuery,
+		"entities": map[string]any{
+			"code": map[string]any{"start": (page - 1) * perPage, "limit": perPage},
+		},
+	}
+
+	var response struct {
+		Code struct {
+			Count      int  `json:"count"`
+			IsLastPage bool `json:"isLastPage"`
+			Values     []struct {
+				File        string `json:"file"`
+				HitContexts [][]struct {
+					Line int    `json:"line"`
+					Text string `json:"text"`
+				} `json:"hitContexts"`
+			} `json:"values"`
+		} `json:"code"`
+	}
+	if err := p.api.postJSON(ctx, "/search/latest/search", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to search code (code search needs Bitbucket Data Center with search enabled): %w", err)
+	}
+
+	result := &CodeSearchResult{Query: searchQuery, TotalCount: response.Code.Count, Page: Page{Page: page, PerPage: perPage}}
+	if !response.Code.IsLastPage {
+		result.Page.NextPage = page + 1
+	}
+	browseURL := strings.TrimSuffix(p.pullRequestURL(repo, 0), "/pull-requests/0") + "/browse/"
+	for _, value := range response.Code.Values {
+		match := CodeMatch{Repository: repo.String(), Path: value.File, HTMLURL: browseURL + value.File}
+		for _, context := range value.HitContexts {
+			var lines []string
+			for _, line := range context {
+				lines = append(lines, html.UnescapeString(highlightPattern.ReplaceAllString(line.Text, "")))
+			}
+			if fragment := strings.TrimSpace(strings.Join(lines, "\n")); fragment != "" {
+				match.Fragments = append(match.Fragments, fragment)
+			}
+		}
+		result.Items = append(result.Items, match)
+	}
+	return result, nil
+}
+
+// ListIssues is not available because Bitbucket Server has no issue tracker
+func (p *bitbucketProvider) ListIssues(context.Context, Repository, string, []string, int, int) (*IssueList, error) {
+	return nil, unsupported("Bitbucket Server", "issue tracking", "it uses Jira for issues")
+}
+
+// GetIssue is not available because Bitbucket Server has no issue tracker
+func (p *bitbucketProvider) GetIssue(context.Context, Repository, int, bool) (*Issue, error) {
+	return nil, unsupported("Bitbucket Server", "issue tracking", "it uses Jira for issues")
+}
+
+// CreateIssue is not available because Bitbucket Server has no issue tracker
+func (p *bitbucketProvider) CreateIssue(context.Context, Repository, IssueRequest) (*CreatedIssue, error) {
+	return nil, unsupported("Bitbucket Server", "issue tracking", "it uses Jira for issues")
+}
+
+// GetPullRequest gets a pull request and, optionally, its general comments
+func (p *bitbucketProvider) GetPullRequest(ctx context.Context, repo Repository, number int, includeComments bool) (*PullRequest, error) {
+	pr, err := p.pullRequest(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullRequest{
+		Repository:   repo.String(),
+		Number:       pr.ID,
+		Title:        pr.Title,
+		State:        strings.ToLower(pr.State),
+		Draft:        pr.Draft,
+		Author:       pr.Author.User.Name,
+		Body:         pr.Description,
+		SourceBranch: pr.FromRef.DisplayID,
+		TargetBranch: pr.ToRef.DisplayID,
+		HeadSHA:      pr.FromRef.LatestCommit,
+		URL:          p.pullRequestURL(repo, number),
+		CreatedAt:    millisToTimestamp(pr.CreatedDate),
+		UpdatedAt:    millisToTimestamp(pr.UpdatedDate),
+	}
+	if includeComments {
+		activities, err := p.activities(ctx, repo, number, 0, 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, activity := range activities.Values {
+			if activity.Comment == nil || activity.CommentAnchor != nil || activity.CommentAction != "ADDED" {
+				continue
+			}
+			for _, comment := range flattenComments(*activity.Comment, 0) {
+				result.Comments = append(result.Comments, Comment{
+					Author:    comment.comment.Author.Name,
+					Body:      comment.comment.Text,
+					CreatedAt: millisToTimestamp(comment.comment.CreatedDate),
+					URL:       fmt.Sprintf("%s/overview?commentId=%d", result.URL, comment.comment.ID),
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// GetPullRequestDiff reads the pull request's raw diff and returns a page of its files
+func (p *bitbucketProvider) GetPullRequestDiff(ctx context.Context, repo Repository, number, page, perPage int) (*PullRequestDiff, error) {
+	data, _, err := p.api.request(ctx, http.MethodGet, fmt.Sprintf("%s/pull-requests/%d.diff", p.repository(repo), number), url.Values{"contextLines": {"3"}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	files := parseUnifiedDiff(string(data))
+	for i := range files {
+		files[i].Patch, files[i].PatchTruncated = truncatePatch(files[i].Patch, DefaultMaxPatchLines)
+	}
+	result := &PullRequestDiff{Repository: repo.String(), Number: number}
+	result.Files, result.Page = Paginate(files, page, perPage)
+	return result, nil
+}
+
+// GetReviewComments gets reviewer verdicts and the comments in a page of pull request activity
+func (p *bitbucketProvider) GetReviewComments(ctx context.Context, repo Repository, number, page, perPage int) (*ReviewComments, error) {
+	prURL := p.pullRequestURL(repo, number)
+	result := &ReviewComments{Repository: repo.String(), Number: number, Comments: []ReviewComment{}}
+
+	if page == 1 {
+		pr, err := p.pullRequest(ctx, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		for _, reviewer := range pr.Reviewers {
+			switch reviewer.Status {
+			case "APPROVED":
+				result.Reviews = append(result.Reviews, Review{Login: reviewer.User.Name, State: "APPROVED", HTMLURL: prURL})
+			case "NEEDS_WORK":
+				result.Reviews = append(result.Reviews, Review{Login: reviewer.User.Name, State: "CHANGES_REQUESTED", HTMLURL: prURL})
+			}
+		}
+	}
+
+	activities, err := p.activities(ctx, repo, number, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int64]bool{}
+	for _, activity := range activities.Values {
+		if activity.Action != "COMMENTED" || activity.CommentAction != "ADDED" || activity.Comment == nil {
+			continue
+		}
+		for _, flat := range flattenComments(*activity.Comment, 0) {
+			comment := flat.comment
+			// Replies can appear both nested in their thread and as activities of their own
+			if seen[comment.ID] {
+				continue
+			}
+			seen[comment.ID] = true
+
+			commentURL := fmt.Sprintf("%s/overview?commentId=%d", prURL, comment.ID)
+			if activity.CommentAnchor == nil {
+				result.Reviews = append(result.Reviews, Review{
+					Login:       comment.Author.Name,
+					State:       "COMMENTED",
+					Body:        comment.Text,
+					SubmittedAt: millisToTimestamp(comment.CreatedDate),
+					HTMLURL:     commentURL,
+				})
+				continue
+			}
+			result.Comments = append(result.Comments, ReviewComment{
+				ID:        comment.ID,
+				InReplyTo: flat.parent,
+				Login:     comment.Author.Name,
+				Path:      activity.CommentAnchor.Path,
+				Line:      activity.CommentAnchor.Line,
+				Outdated:  activity.CommentAnchor.Orphaned,
+				Body:      comment.Text,
+				HTMLURL:   commentURL,
+				CreatedAt: millisToTimestamp(comment.CreatedDate),
+			})
+		}
+	}
+
+	result.Page = Page{Page: page, PerPage: perPage}
+	if !activities.IsLastPage {
+		result.Page.NextPage = page + 1
+	}
+	return result, nil
+}
+
+// GetCommitStatus gets the build statuses reported for a commit, branch or tag
+func (p *bitbucketProvider) GetCommitStatus(ctx context.Context, repo Repository, ref string, page, perPage int) (*CommitStatus, error) {
+	sha := ref
+	if !commitSHAPattern.MatchString(ref) {
+		var commit struct {
+			ID string `json:"id"`
+		}
+		if _, err := p.api.getJSON(ctx, p.repository(repo)+"/commits/"+url.PathEscape(ref), nil, &commit); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+		}
+		sha = commit.ID
+	}
+
+	var checks []StatusCheck
+	start := 0
+	for range maxStatusPages {
+		var statuses bitbucketPage[struct {
+			State       string `json:"state"`
+			Key         string `json:"key"`
+			Name        string `json:"name"`
+			URL         string `json:"url"`
+			Description string `json:"description"`
+		}]
+		params := url.Values{"start": {strconv.Itoa(start)}, "limit": {"100"}}
+		if _, err := p.api.getJSON(ctx, "/build-status/1.0/commits/"+sha, params, &statuses); err != nil {
+			return nil, fmt.Errorf("failed to get build statuses: %w", err)
+		}
+		for _, status := range statuses.Values {
+			check := StatusCheck{Name: status.Name, Kind: "status", Status: "completed", Description: status.Description, URL: status.URL}
+			if check.Name == "" {
+				check.Name = status.Key
+			}
+			switch status.State {
+			case "SUCCESSFUL":
+				check.Conclusion = "success"
+			case "FAILED":
+				check.Conclusion = "failure"
+			case "CANCELLED":
+				check.Conclusion = "cancelled"
+			default:
+				check.Status = "in_progress"
+			}
+			checks = append(checks, check)
+		}
+		if statuses.IsLastPage {
+			break
+		}
+		start = statuses.NextPageStart
+	}
+
+	result := SummariseChecks(checks)
+	result.Repository = repo.String()
+	result.Ref = ref
+	result.SHA = sha
+	result.Checks, result.Page = Paginate(result.Checks, page, perPage)
+	return result, nil
+}
+
+// GetFileContents gets a raw file, from the default branch when ref is empty
+func (p *bitbucketProvider) GetFileContents(ctx context.Context, repo Repository, path, ref string) (string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	var params url.Values
+	if ref != "" {
+		params = url.Values{"at": {ref}}
+	}
+	data, _, err := p.api.request(ctx, http.MethodGet, p.repository(repo)+"/raw/"+strings.Join(segments, "/"), params, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// pullRequest gets a pull request
+func (p *bitbucketProvider) pullRequest(ctx context.Context, repo Repository, number int) (*bitbucketPullRequest, error) {
+	var pr bitbucketPullRequest
+	if _, err := p.api.getJSON(ctx, fmt.Sprintf("%s/pull-requests/%d", p.repository(repo), number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	return &pr, nil
+}
+
+// activities gets a page of pull request activity, newest first
+func (p *bitbucketProvider) activities(ctx context.Context, repo Repository, number, start, limit int) (*bitbucketPage[bitbucketActivity], error) {
+	var page bitbucketPage[bitbucketActivity]
+	params := url.Values{"start": {strconv.Itoa(start)}, "limit": {strconv.Itoa(limit)}}
+	if _, err := p.api.getJSON(ctx, fmt.Sprintf("%s/pull-requests/%d/activities", p.repository(repo), number), params, &page); err != nil {
+		return nil, fmt.Errorf("failed to get pull request activity: %w", err)
+	}
+	return &page, nil
+}
+
+// flatComment is a comment with the ID of the comment it replies to
+type flatComment struct {
+	comment bitbucketComment
+	parent  int64
+}
+
+// flattenComments lists a comment thread depth first
+func flattenComments(comment bitbucketComment, parent int64) []flatComment {
+	result := []flatComment{{comment: comment, parent: parent}}
+	for _, reply := range comment.Comments {
+		result = append(result, flattenComments(reply, comment.ID)...)
+	}
+	return result
+}
+
+// millisToTimestamp formats a Bitbucket epoch milliseconds value
+func millisToTimestamp(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return time.UnixMilli(millis).UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,90 @@
+package forge
+
+import "strings"
+
+// diffStats counts the added and removed lines in a patch made up of hunks
+func diffStats(patch string) (additions, deletions int) {
+	for line := range strings.Lines(patch) {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// truncatePatch limits a patch to maxLines lines, reporting whether it was cut
+func truncatePatch(patch string, maxLines int) (string, bool) {
+	lines := strings.Split(patch, "\n")
+	if len(lines) <= maxLines {
+		return patch, false
+	}
+	return strings.Join(lines[:maxLines], "\n"), true
+}
+
+// parseUnifiedDiff splits git diff output into per-file patches holding only the hunks
+func parseUnifiedDiff(text string) []PullRequestFile {
+	var files []PullRequestFile
+	var current *PullRequestFile
+	var patch strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Patch = strings.TrimRight(patch.String(), "\n")
+		current.Additions, current.Deletions = diffStats(current.Patch)
+		if current.Status != "renamed" {
+			current.PreviousFilename = ""
+		}
+		files = append(files, *current)
+		patch.Reset()
+	}
+
+	for line := range strings.Lines(text) {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &PullRequestFile{Status: "modified"}
+			inHunk = false
+			// Paths are read again from the ---/+++ lines, which handle spaces unambiguously
+			if oldPath, newPath, ok := strings.Cut(strings.TrimPrefix(line, "diff --git a/"), " b/"); ok {
+				current.PreviousFilename, current.Filename = oldPath, newPath
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if inHunk {
+			patch.WriteString(line)
+			patch.WriteString("\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			patch.WriteString(line)
+			patch.WriteString("\n")
+		case strings.HasPrefix(line, "new file mode"):
+			current.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.Status = "removed"
+		case strings.HasPrefix(line, "rename from "):
+			current.Status = "renamed"
+			current.PreviousFilename = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.Filename = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "--- a/"):
+			current.PreviousFilename = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "+++ b/"):
+			current.Filename = strings.TrimPrefix(line, "+++ b/")
+		}
+	}
+	flush()
+	return files
+}
@@ -0,0 +1,533 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AllowWriteEnvVar enables create_issue when set to true
+	AllowWriteEnvVar = "FORGE_ALLOW_WRITE"
+
+	defaultPerPage = 30
+	maxPerPage     = 100
+	maxFileLines   = 1000
+	maxFilePaths   = 20
+)
+
+// ForgeTool provides the same repository operations across GitHub, GitLab and Bitbucket Server
+type ForgeTool struct{}
+
+// init registers the forge tool
+func init() {
+	registry.Register(&ForgeTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ForgeTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"forge",
+		mcp.WithDescription(`Read issues, pull/merge requests, diffs, review comments, CI status and files from GitHub, GitLab and Bitbucket Server through one interface.
+
+Repository accepts: a repository, issue or pull/merge request URL, host/path (e.g. gitlab.example.com/group/project), or owner/repo for GitHub.
+Self-hosted servers must be listed in FORGE_HOSTS by the server administrator.
+Paginated functions return markdown and page with options.page and options.limit.
+create_issue is only available when the server sets FORGE_ALLOW_WRITE=true.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Function to execute"),
+			mcp.Enum("search_code", "list_issues", "get_issue", "create_issue", "get_pull_request", "get_pull_request_diff", "get_review_comments", "get_commit_status", "get_file_contents"),
+		),
+		mcp.WithString("repository",
+			mcp.Required(),
+			mcp.Description("Repository, issue or pull/merge request URL, host/path, or GitHub owner/repo"),
+		),
+		mcp.WithObject("options",
+			mcp.Description("Function-specific options"),
+			mcp.Properties(map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search query (for search_code)",
+				},
+				"number": map[string]any{
+					"type":        "number",
+					"description": "Issue or pull/merge request number (not needed if using a full URL)",
+				},
+				"limit": map[string]any{
+					"type":        "number",
+					"description": "Results per page (default 30, max 100)",
+					"default":     defaultPerPage,
+				},
+				"page": map[string]any{
+					"type":        "number",
+					"description": "Page of results (default: 1)",
+					"default":     1,
+				},
+				"include_comments": map[string]any{
+					"type":        "boolean",
+					"description": "Include conversation comments (for get_issue and get_pull_request, default: false)",
+					"default":     false,
+				},
+				"include_closed": map[string]any{
+					"type":        "boolean",
+					"description": "Include closed issues (for list_issues, default: false)",
+					"default":     false,
+				},
+				"labels": map[string]any{
+					"type":        "array",
+					"description": "Labels to filter by (list_issues) or apply (create_issue)",
+					"items":       map[string]any{"type": "string"},
+				},
+				"title": map[string]any{
+					"type":        "string",
+					"description": "Issue title (for create_issue)",
+				},
+				"body": map[string]any{
+					"type":        "string",
+					"description": "Issue body in markdown (for create_issue)",
+				},
+				"assignees": map[string]any{
+					"type":        "array",
+					"description": "Usernames to assign (for create_issue)",
+					"items":       map[string]any{"type": "string"},
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Branch, tag or commit SHA (for get_commit_status and get_file_contents, defaults to the pull request head or the default branch)",
+				},
+				"paths": map[string]any{
+					"type":        "array",
+					"description": "File paths to retrieve (for get_file_contents). Files longer than 1000 lines are truncated with guidance on using line_start",
+					"items":       map[string]any{"type": "string"},
+				},
+				"line_start": map[string]any{
+					"type":        "number",
+					"description": "Starting line number (1-based) to retrieve subsequent sections of truncated files",
+				},
+			}),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // create_issue creates issues when enabled
+		mcp.WithDestructiveHintAnnotation(false), // never modifies or deletes existing data
+		mcp.WithIdempotentHintAnnotation(false),  // create_issue creates a new issue each time
+		mcp.WithOpenWorldHintAnnotation(true),    // calls remote forge APIs
+	)
+}
+
+// Execute runs the requested function against the repository's forge
+func (t *ForgeTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+	repository, _ := args["repository"].(string)
+	options, _ := args["options"].(map[string]any)
+	if options == nil {
+		options = map[string]any{}
+	}
+
+	if function == "create_issue" && !writesAllowed() {
+		return nil, fmt.Errorf("create_issue is disabled - the forge tool is read-only unless the server sets %s=true", AllowWriteEnvVar)
+	}
+
+	hosts, err := LoadHosts()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := ParseRepository(repository, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"function": function,
+		"host":     repo.Host.Name,
+		"kind":     repo.Host.Kind,
+	}).Debug("Running forge function")
+
+	provider, err := newProvider(ctx, repo.Host, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var markdown string
+	switch function {
+	case "search_code":
+		markdown, err = searchCode(ctx, provider, repo, options)
+	case "list_issues":
+		markdown, err = listIssues(ctx, provider, repo, options)
+	case "get_issue":
+		markdown, err = getIssue(ctx, provider, repo, options)
+	case "create_issue":
+		markdown, err = createIssue(ctx, provider, repo, options)
+	case "get_pull_request":
+		markdown, err = getPullRequest(ctx, provider, repo, options)
+	case "get_pull_request_diff":
+		markdown, err = getPullRequestDiff(ctx, provider, repo, options)
+	case "get_review_comments":
+		markdown, err = getReviewComments(ctx, provider, repo, options)
+	case "get_commit_status":
+		markdown, err = getCommitStatus(ctx, provider, repo, options)
+	case "get_file_contents":
+		markdown, err = getFileContents(ctx, provider, repo, options)
+	default:
+		return nil, fmt.Errorf("unsupported function: %s", function)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return markdownResult(markdown, repo.Host.Name, function)
+}
+
+// searchCode searches file contents in the repository
+func searchCode(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	query, _ := options["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("options.query is required for search_code")
+	}
+	page, perPage, err := parsePagination(options)
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.SearchCode(ctx, repo, strings.TrimSpace(query), page, perPage)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// listIssues lists open issues, or all issues with include_closed
+func listIssues(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	state := "open"
+	if includeClosed, _ := options["include_closed"].(bool); includeClosed {
+		state = "all"
+	}
+	page, perPage, err := parsePagination(options)
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.ListIssues(ctx, repo, state, stringOptions(options["labels"]), page, perPage)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getIssue gets an issue with optional comments
+func getIssue(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	number, err := targetNumber(repo, options)
+	if err != nil {
+		return "", err
+	}
+	includeComments, _ := options["include_comments"].(bool)
+	result, err := provider.GetIssue(ctx, repo, number, includeComments)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// createIssue opens an issue
+func createIssue(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	title, _ := options["title"].(string)
+	if strings.TrimSpace(title) == "" {
+		return "", fmt.Errorf("options.title is required for create_issue")
+	}
+	body, _ := options["body"].(string)
+	result, err := provider.CreateIssue(ctx, repo, IssueRequest{
+		Title:     strings.TrimSpace(title),
+		Body:      body,
+		Labels:    stringOptions(options["labels"]),
+		Assignees: stringOptions(options["assignees"]),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getPullRequest gets a pull or merge request with optional comments
+func getPullRequest(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	number, err := targetNumber(repo, options)
+	if err != nil {
+		return "", err
+	}
+	includeComments, _ := options["include_comments"].(bool)
+	result, err := provider.GetPullRequest(ctx, repo, number, includeComments)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getPullRequestDiff gets a page of changed files with patches
+func getPullRequestDiff(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	number, err := targetNumber(repo, options)
+	if err != nil {
+		return "", err
+	}
+	page, perPage, err := parsePagination(options)
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.GetPullRequestDiff(ctx, repo, number, page, perPage)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getReviewComments gets review verdicts and inline comments
+func getReviewComments(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	number, err := targetNumber(repo, options)
+	if err != nil {
+		return "", err
+	}
+	page, perPage, err := parsePagination(options)
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.GetReviewComments(ctx, repo, number, page, perPage)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getCommitStatus gets the CI state of options.ref, or of a pull request's head commit
+func getCommitStatus(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	ref, _ := options["ref"].(string)
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		number, err := targetNumber(repo, options)
+		if err != nil {
+			return "", fmt.Errorf("options.ref is required for get_commit_status (commit SHA, branch or tag), or pass a pull request URL or options.number")
+		}
+		pr, err := provider.GetPullRequest(ctx, repo, number, false)
+		if err != nil {
+			return "", err
+		}
+		ref = pr.HeadSHA
+	}
+
+	page, perPage, err := parsePagination(options)
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.GetCommitStatus(ctx, repo, ref, page, perPage)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// getFileContents reads files, returning up to maxFileLines lines of each from options.line_start.
+// A file that cannot be read is reported without failing the others.
+func getFileContents(ctx context.Context, provider Provider, repo Repository, options map[string]any) (string, error) {
+	paths := stringOptions(options["paths"])
+	if len(paths) == 0 {
+		return "", fmt.Errorf("at least one file path is required in options.paths")
+	}
+	if len(paths) > maxFilePaths {
+		return "", fmt.Errorf("options.paths accepts at most %d files", maxFilePaths)
+	}
+	ref, _ := options["ref"].(string)
+	lineStart := 1
+	if value, ok := options["line_start"].(float64); ok && value > 1 {
+		lineStart = int(value)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Files: %s\n", repo)
+	for _, path := range paths {
+		file := FileContents{Path: path, Ref: ref}
+		content, err := provider.GetFileContents(ctx, repo, path, ref)
+		if err != nil {
+			file.Error = err.Error()
+		} else {
+			file.Content, file.StartLine, file.EndLine, file.TotalLines = lineWindow(content, lineStart, maxFileLines)
+			if file.StartLine > file.TotalLines {
+				file.Error = fmt.Sprintf("line_start %d is past the end of the file (%d lines)", lineStart, file.TotalLines)
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(file.Markdown())
+	}
+	return sb.String(), nil
+}
+
+// lineWindow returns up to maxLines lines of content starting at the 1-based start line
+func lineWindow(content string, start, maxLines int) (window string, first, last, total int) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	total = len(lines)
+	if start > total {
+		return "", start, total, total
+	}
+	end := min(start-1+maxLines, total)
+	return strings.Join(lines[start-1:end], "\n"), start, end, total
+}
+
+// targetNumber reads the issue or pull request number from the repository URL or options.number
+func targetNumber(repo Repository, options map[string]any) (int, error) {
+	if repo.Number > 0 {
+		return repo.Number, nil
+	}
+	if number, ok := options["number"].(float64); ok && number >= 1 {
+		return int(number), nil
+	}
+	return 0, fmt.Errorf("issue or pull request number is required (either in the URL or options.number)")
+}
+
+// parsePagination reads options.page and options.limit, capping the page size at 100
+func parsePagination(options map[string]any) (page, perPage int, err error) {
+	page, perPage = 1, defaultPerPage
+	if p, ok := options["page"].(float64); ok {
+		if p < 1 {
+			return 0, 0, fmt.Errorf("options.page must be 1 or greater")
+		}
+		page = int(p)
+	}
+	if l, ok := options["limit"].(float64); ok && l >= 1 {
+		perPage = min(int(l), maxPerPage)
+	}
+	return page, perPage, nil
+}
+
+// stringOptions reads a list option given as an array or a comma-separated string
+func stringOptions(value any) []string {
+	var items []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+	case []string:
+		items = v
+	case string:
+		items = strings.Split(v, ",")
+	}
+
+	var values []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// writesAllowed reports whether the server permits functions that modify forge data
+func writesAllowed() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(AllowWriteEnvVar)))
+	return value == "true" || value == "1"
+}
+
+// markdownResult runs a markdown summary through security analysis before returning it
+func markdownResult(markdown, domain, contentType string) (*mcp.CallToolResult, error) {
+	source := security.SourceContext{
+		Tool:        "forge",
+		Domain:      domain,
+		ContentType: contentType,
+	}
+	if result, err := security.AnalyseContent(markdown, source); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			markdown = security.FormatSecurityWarningPrefix(result) + markdown
+		}
+	}
+	return mcp.NewToolResultText(markdown), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the forge tool
+func (t *ForgeTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Read a merge request on a self-hosted GitLab with its discussion",
+				Arguments: map[string]any{
+					"function":   "get_pull_request",
+					"repository": "https://gitlab.example.com/platform/api/-/merge_requests/42",
+					"options": map[string]any{
+						"include_comments": true,
+					},
+				},
+				ExpectedResult: "Title, state, branches, head commit and description of merge request !42 with its comments",
+			},
+			{
+				Description: "Check CI for a Bitbucket Server pull request",
+				Arguments: map[string]any{
+					"function":   "get_commit_status",
+					"repository": "https://git.example.com/bitbucket/projects/PLAT/repos/api/pull-requests/7",
+				},
+				ExpectedResult: "Overall state and a table of build statuses for the pull request's latest commit, failing builds first",
+			},
+			{
+				Description: "List open bugs on a GitLab project",
+				Arguments: map[string]any{
+					"function":   "list_issues",
+					"repository": "gitlab.example.com/platform/api",
+					"options": map[string]any{
+						"labels": []string{"bug"},
+					},
+				},
+				ExpectedResult: "A table of open issues labelled bug, most recently updated first",
+			},
+			{
+				Description: "Read files from a branch",
+				Arguments: map[string]any{
+					"function":   "get_file_contents",
+					"repository": "gitlab.example.com/platform/api",
+					"options": map[string]any{
+						"paths": []string{"README.md", ".gitlab-ci.yml"},
+						"ref":   "develop",
+					},
+				},
+				ExpectedResult: "Each file in a code block, with an error in place of any file that could not be read",
+			},
+		},
+		CommonPatterns: []string{
+			"Paste the web URL of an issue or pull/merge request - the host, repository and number are read from it",
+			"Use get_pull_request_diff, then get_review_comments to see what reviewers asked for, then get_commit_status to check CI",
+			"The github tool offers more GitHub-only functions; use forge when the same workflow must also cover GitLab or Bitbucket Server",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "unknown host",
+				Solution: "Add the server to FORGE_HOSTS as host=kind, e.g. gitlab.example.com=gitlab or https://git.example.com/bitbucket=bitbucket for a context path",
+			},
+			{
+				Problem:  "API returned 401 or 404",
+				Solution: "Set the host's token in FORGE_TOKEN_<HOST>, e.g. FORGE_TOKEN_GITLAB_EXAMPLE_COM. GitHub uses GITHUB_TOKEN as for the github tool",
+			},
+			{
+				Problem:  "Issue functions are not supported on Bitbucket Server",
+				Solution: "Bitbucket Server has no issue tracker - its projects use Jira instead",
+			},
+			{
+				Problem:  "create_issue is disabled",
+				Solution: "The tool is read-only by default. Set FORGE_ALLOW_WRITE=true in the server environment to enable it",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"repository":  "GitLab subgroups are supported (group/subgroup/project). Bitbucket Server accepts projects/KEY/repos/slug URLs, users/name/repos/slug for personal repositories, or host/KEY/slug",
+			"ref":         "For get_commit_status, defaults to the pull request's head commit when a pull request URL or options.number is given",
+			"search_code": "GitLab needs advanced or exact code search for good results. Bitbucket needs Data Center with code search enabled",
+		},
+		WhenToUse:    "Reviewing issues, pull/merge requests and CI on GitHub, GitLab or Bitbucket Server with the same calls",
+		WhenNotToUse: "Cloning repositories or reading GitHub Actions logs - use the github tool for GitHub-only features",
+	}
+}
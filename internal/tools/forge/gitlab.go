@@ -0,0 +1,497 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxStatusPages stops fetching commit statuses after 1,000 jobs
+const maxStatusPages = 10
+
+// gitLabProvider implements Provider with the GitLab REST API v4, for gitlab.com and self-hosted instances
+type gitLabProvider struct {
+	api  *apiClient
+	host Host
+}
+
+// init registers the GitLab provider
+func init() {
+	RegisterProvider(KindGitLab, newGitLabProvider)
+}
+
+// newGitLabProvider creates a GitLab provider that authenticates with the host's personal,
+// group or project access token
+func newGitLabProvider(_ context.Context, host Host, _ *logrus.Logger) (Provider, error) {
+	headers := map[string]string{}
+	if host.Token != "" {
+		headers["PRIVATE-TOKEN"] = host.Token
+	}
+	return &gitLabProvider{api: newAPIClient(host.BaseURL+"/api/v4", headers), host: host}, nil
+}
+
+type gitLabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitLabIssue struct {
+	IID            int          `json:"iid"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	State          string       `json:"state"`
+	Author         gitLabUser   `json:"author"`
+	Assignees      []gitLabUser `json:"assignees"`
+	Labels         []string     `json:"labels"`
+	UserNotesCount int          `json:"user_notes_count"`
+	WebURL         string       `json:"web_url"`
+	CreatedAt      string       `json:"created_at"`
+	UpdatedAt      string       `json:"updated_at"`
+}
+
+type gitLabMergeRequest struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	Draft        bool       `json:"draft"`
+	Author       gitLabUser `json:"author"`
+	SourceBranch string     `json:"source_branch"`
+	TargetBranch string     `json:"target_branch"`
+	SHA          string     `json:"sha"`
+	WebURL       string     `json:"web_url"`
+	CreatedAt    string     `json:"created_at"`
+	UpdatedAt    string     `json:"updated_at"`
+}
+
+type gitLabNote struct {
+	ID        int64      `json:"id"`
+	Type      string     `json:"type"` // DiffNote for inline comments
+	Body      string     `json:"body"`
+	Author    gitLabUser `json:"author"`
+	System    bool       `json:"system"`
+	CreatedAt string     `json:"created_at"`
+	Position  *struct {
+		NewPath string `json:"new_path"`
+		OldPath string `json:"old_path"`
+		NewLine int    `json:"new_line"`
+		OldLine int    `json:"old_line"`
+	} `json:"position"`
+}
+
+type gitLabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+type gitLabStatus struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	Description  string `json:"description"`
+	TargetURL    string `json:"target_url"`
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+// project returns the API path of a project, addressed by its URL-encoded full path
+func (p *gitLabProvider) project(repo Repository) string {
+	return "/projects/" + url.PathEscape(repo.Path)
+}
+
+// SearchCode searches the project's default branch
+func (p *gitLabProvider) SearchCode(ctx context.Context, repo Repository, query string, page, perPage int) (*CodeSearchResult, error) {
+	params := pageQuery(page, perPage)
+	params.Set("scope", "blobs")
+	params.Set("search", query)
+
+	var blobs []struct {
+		Path      string `json:"path"`
+		Data      string `json:"data"`
+		Ref       string `json:"ref"`
+		StartLine int    `json:"startline"`
+	}
+	header, err := p.api.getJSON(ctx, p.project(repo)+"/search", params, &blobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search code: %w", err)
+	}
+
+	result := &CodeSearchResult{Query: query, Items: make([]CodeMatch, 0, len(blobs)), Page: gitLabPage(header, page, perPage)}
+	for _, blob := range blobs {
+		match := CodeMatch{
+			Repository: repo.String(),
+			Path:       blob.Path,
+			HTMLURL:    fmt.Sprintf("%s/%s/-/blob/%s/%s#L%d", p.host.BaseURL, repo.Path, blob.Ref, blob.Path, blob.StartLine),
+		}
+		if fragment := strings.TrimSpace(blob.Data); fragment != "" {
+			match.Fragments = []string{fragment}
+		}
+		result.Items = append(result.Items, match)
+	}
+	result.TotalCount, _ = strconv.Atoi(header.Get("X-Total"))
+	if result.TotalCount == 0 {
+		result.TotalCount = len(result.Items)
+	}
+	return result, nil
+}
+
+// ListIssues lists the project's issues, most recently updated first
+func (p *gitLabProvider) ListIssues(ctx context.Context, repo Repository, state string, labels []string, page, perPage int) (*IssueList, error) {
+	params := pageQuery(page, perPage)
+	params.Set("order_by", "updated_at")
+	params.Set("sort", "desc")
+	params.Set("state", "opened")
+	if state == "all" {
+		params.Set("state", "all")
+	}
+	if len(labels) > 0 {
+		params.Set("labels", strings.Join(labels, ","))
+	}
+
+	var issues []gitLabIssue
+	header, err := p.api.getJSON(ctx, p.project(repo)+"/issues", params, &issues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	result := &IssueList{Repository: repo.String(), State: state, Labels: labels, Items: make([]IssueSummary, 0, len(issues)), Page: gitLabPage(header, page, perPage)}
+	for _, issue := range issues {
+		result.Items = append(result.Items, IssueSummary{
+			Number:    issue.IID,
+			Title:     issue.Title,
+			State:     gitLabState(issue.State),
+			Login:     issue.Author.Username,
+			Labels:    issue.Labels,
+			Comments:  issue.UserNotesCount,
+			HTMLURL:   issue.WebURL,
+			UpdatedAt: issue.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// GetIssue gets an issue and, optionally, its comments without system notes
+func (p *gitLabProvider) GetIssue(ctx context.Context, repo Repository, number int, includeComments bool) (*Issue, error) {
+	path := fmt.Sprintf("%s/issues/%d", p.project(repo), number)
+	var issue gitLabIssue
+	if _, err := p.api.getJSON(ctx, path, nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	result := &Issue{
+		Repository: repo.String(),
+		Number:     issue.IID,
+		Title:      issue.Title,
+		State:      gitLabState(issue.State),
+		Author:     issue.Author.Username,
+		Body:       issue.Description,
+		Labels:     issue.Labels,
+		URL:        issue.WebURL,
+		CreatedAt:  issue.CreatedAt,
+		UpdatedAt:  issue.UpdatedAt,
+	}
+	if includeComments {
+		comments, err := p.conversation(ctx, path, issue.WebURL)
+		if err != nil {
+			return nil, err
+		}
+		result.Comments = comments
+	}
+	return result, nil
+}
+
+// CreateIssue opens an issue, looking up assignees' user IDs by username
+func (p *gitLabProvider) CreateIssue(ctx context.Context, repo Repository, request IssueRequest) (*CreatedIssue, error) {
+	body := map[string]any{"title": request.Title}
+	if request.Body != "" {
+		body["description"] = request.Body
+	}
+	if len(request.Labels) > 0 {
+		body["labels"] = strings.Join(request.Labels, ",")
+	}
+	if len(request.Assignees) > 0 {
+		var ids []int64
+		for _, username := range request.Assignees {
+			var users []gitLabUser
+			if _, err := p.api.getJSON(ctx, "/users", url.Values{"username": {username}}, &users); err != nil {
+				return nil, fmt.Errorf("failed to look up assignee %s: %w", username, err)
+			}
+			if len(users) == 0 {
+				return nil, fmt.Errorf("assignee %s not found", username)
+			}
+			ids = append(ids, users[0].ID)
+		}
+		body["assignee_ids"] = ids
+	}
+
+	var issue gitLabIssue
+	if err := p.api.postJSON(ctx, p.project(repo)+"/issues", body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	created := &CreatedIssue{Repository: repo.String(), Number: issue.IID, Title: issue.Title, Labels: issue.Labels, HTMLURL: issue.WebURL}
+	for _, assignee := range issue.Assignees {
+		created.Assignees = append(created.Assignees, assignee.Username)
+	}
+	return created, nil
+}
+
+// GetPullRequest gets a merge request and, optionally, its conversation comments
+func (p *gitLabProvider) GetPullRequest(ctx context.Context, repo Repository, number int, includeComments bool) (*PullRequest, error) {
+	path := fmt.Sprintf("%s/merge_requests/%d", p.project(repo), number)
+	var mr gitLabMergeRequest
+	if _, err := p.api.getJSON(ctx, path, nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	result := &PullRequest{
+		Repository:   repo.String(),
+		Number:       mr.IID,
+		Title:        mr.Title,
+		State:        gitLabState(mr.State),
+		Draft:        mr.Draft,
+		Author:       mr.Author.Username,
+		Body:         mr.Description,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		HeadSHA:      mr.SHA,
+		URL:          mr.WebURL,
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+	}
+	if includeComments {
+		comments, err := p.conversation(ctx, path, mr.WebURL)
+		if err != nil {
+			return nil, err
+		}
+		result.Comments = comments
+	}
+	return result, nil
+}
+
+// GetPullRequestDiff gets a page of a merge request's changed files. It needs GitLab 15.7 or later.
+func (p *gitLabProvider) GetPullRequestDiff(ctx context.Context, repo Repository, number, page, perPage int) (*PullRequestDiff, error) {
+	var diffs []gitLabDiff
+	header, err := p.api.getJSON(ctx, fmt.Sprintf("%s/merge_requests/%d/diffs", p.project(repo), number), pageQuery(page, perPage), &diffs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request diff: %w", err)
+	}
+
+	result := &PullRequestDiff{Repository: repo.String(), Number: number, Files: make([]PullRequestFile, 0, len(diffs)), Page: gitLabPage(header, page, perPage)}
+	for _, diff := range diffs {
+		file := PullRequestFile{Filename: diff.NewPath, Status: "modified"}
+		switch {
+		case diff.NewFile:
+			file.Status = "added"
+		case diff.DeletedFile:
+			file.Status = "removed"
+		case diff.RenamedFile:
+			file.Status = "renamed"
+			file.PreviousFilename = diff.OldPath
+		}
+		patch := strings.TrimRight(diff.Diff, "\n")
+		file.Additions, file.Deletions = diffStats(patch)
+		file.Patch, file.PatchTruncated = truncatePatch(patch, DefaultMaxPatchLines)
+		result.Files = append(result.Files, file)
+	}
+	return result, nil
+}
+
+// GetReviewComments gets approvals and a page of discussions. Threads on the diff become inline
+// comments and other threads are listed with the reviews.
+func (p *gitLabProvider) GetReviewComments(ctx context.Context, repo Repository, number, page, perPage int) (*ReviewComments, error) {
+	path := fmt.Sprintf("%s/merge_requests/%d", p.project(repo), number)
+	webURL := fmt.Sprintf("%s/%s/-/merge_requests/%d", p.host.BaseURL, repo.Path, number)
+	result := &ReviewComments{Repository: repo.String(), Number: number}
+
+	if page == 1 {
+		var approvals struct {
+			ApprovedBy []struct {
+				User gitLabUser `json:"user"`
+			} `json:"approved_by"`
+		}
+		if _, err := p.api.getJSON(ctx, path+"/approvals", nil, &approvals); err != nil {
+			return nil, fmt.Errorf("failed to get approvals: %w", err)
+		}
+		for _, approval := range approvals.ApprovedBy {
+			result.Reviews = append(result.Reviews, Review{Login: approval.User.Username, State: "APPROVED", HTMLURL: webURL})
+		}
+	}
+
+	var discussions []struct {
+		Notes []gitLabNote `json:"notes"`
+	}
+	header, err := p.api.getJSON(ctx, path+"/discussions", pageQuery(page, perPage), &discussions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discussions: %w", err)
+	}
+
+	result.Comments = []ReviewComment{}
+	for _, discussion := range discussions {
+		if len(discussion.Notes) == 0 || discussion.Notes[0].System {
+			continue
+		}
+		first := discussion.Notes[0]
+		for _, note := range discussion.Notes {
+			noteURL := fmt.Sprintf("%s#note_%d", webURL, note.ID)
+			if first.Position == nil {
+				result.Reviews = append(result.Reviews, Review{Login: note.Author.Username, State: "COMMENTED", Body: note.Body, SubmittedAt: note.CreatedAt, HTMLURL: noteURL})
+				continue
+			}
+			comment := ReviewComment{
+				ID:        note.ID,
+				Login:     note.Author.Username,
+				Path:      first.Position.NewPath,
+				Line:      first.Position.NewLine,
+				Body:      note.Body,
+				HTMLURL:   noteURL,
+				CreatedAt: note.CreatedAt,
+			}
+			// Comments on removed lines only have a line number in the old file
+			if comment.Line == 0 {
+				comment.Path = first.Position.OldPath
+				comment.Line = first.Position.OldLine
+			}
+			if note.ID != first.ID {
+				comment.InReplyTo = first.ID
+			}
+			result.Comments = append(result.Comments, comment)
+		}
+	}
+	result.Page = gitLabPage(header, page, perPage)
+	return result, nil
+}
+
+// GetCommitStatus gets the pipeline job and external statuses of a commit, branch or tag
+func (p *gitLabProvider) GetCommitStatus(ctx context.Context, repo Repository, ref string, page, perPage int) (*CommitStatus, error) {
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if _, err := p.api.getJSON(ctx, p.project(repo)+"/repository/commits/"+url.PathEscape(ref), nil, &commit); err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	var checks []StatusCheck
+	params := pageQuery(1, 100)
+	for range maxStatusPages {
+		var statuses []gitLabStatus
+		header, err := p.api.getJSON(ctx, p.project(repo)+"/repository/commits/"+commit.ID+"/statuses", params, &statuses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit statuses: %w", err)
+		}
+		for _, status := range statuses {
+			checks = append(checks, gitLabCheck(status))
+		}
+		next := header.Get("X-Next-Page")
+		if next == "" {
+			break
+		}
+		params.Set("page", next)
+	}
+
+	result := SummariseChecks(checks)
+	result.Repository = repo.String()
+	result.Ref = ref
+	result.SHA = commit.ID
+	result.Checks, result.Page = Paginate(result.Checks, page, perPage)
+	return result, nil
+}
+
+// GetFileContents gets a raw file, from the default branch when ref is empty
+func (p *gitLabProvider) GetFileContents(ctx context.Context, repo Repository, path, ref string) (string, error) {
+	if ref == "" {
+		var project struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if _, err := p.api.getJSON(ctx, p.project(repo), nil, &project); err != nil {
+			return "", fmt.Errorf("failed to get default branch: %w", err)
+		}
+		ref = project.DefaultBranch
+	}
+	data, _, err := p.api.request(ctx, http.MethodGet, p.project(repo)+"/repository/files/"+url.PathEscape(path)+"/raw", url.Values{"ref": {ref}}, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// conversation gets the user comments on an issue or merge request, oldest first
+func (p *gitLabProvider) conversation(ctx context.Context, path, webURL string) ([]Comment, error) {
+	params := pageQuery(1, 100)
+	params.Set("sort", "asc")
+	params.Set("order_by", "created_at")
+	var notes []gitLabNote
+	if _, err := p.api.getJSON(ctx, path+"/notes", params, &notes); err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	var comments []Comment
+	for _, note := range notes {
+		// System notes record events such as label changes, and diff notes are review comments
+		if note.System || note.Type == "DiffNote" {
+			continue
+		}
+		comments = append(comments, Comment{
+			Author:    note.Author.Username,
+			Body:      note.Body,
+			CreatedAt: note.CreatedAt,
+			URL:       fmt.Sprintf("%s#note_%d", webURL, note.ID),
+		})
+	}
+	return comments, nil
+}
+
+// gitLabCheck maps a GitLab job or external status onto the check run model
+func gitLabCheck(status gitLabStatus) StatusCheck {
+	check := StatusCheck{Name: status.Name, Kind: "status", Status: "completed", Description: status.Description, URL: status.TargetURL}
+	switch status.Status {
+	case "success":
+		check.Conclusion = "success"
+	case "failed":
+		check.Conclusion = "failure"
+		if status.AllowFailure {
+			check.Conclusion = "neutral"
+		}
+	case "canceled":
+		check.Conclusion = "cancelled"
+	case "skipped", "manual":
+		check.Conclusion = "skipped"
+	case "running":
+		check.Status = "in_progress"
+	default:
+		// created, pending, preparing, scheduled and waiting_for_resource
+		check.Status = "queued"
+	}
+	return check
+}
+
+// gitLabState maps GitLab's "opened" state to "open"
+func gitLabState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return state
+}
+
+// gitLabPage reads pagination details from GitLab's response headers
+func gitLabPage(header http.Header, page, perPage int) Page {
+	result := Page{Page: page, PerPage: perPage}
+	result.NextPage, _ = strconv.Atoi(header.Get("X-Next-Page"))
+	result.LastPage, _ = strconv.Atoi(header.Get("X-Total-Pages"))
+	return result
+}
+
+// pageQuery returns page-based pagination parameters
+func pageQuery(page, perPage int) url.Values {
+	return url.Values{
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(perPage)},
+	}
+}
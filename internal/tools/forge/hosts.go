@@ -0,0 +1,242 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HostsEnvVar configures self-hosted forges as comma-separated host=kind entries
+	HostsEnvVar = "FORGE_HOSTS"
+	// TokenEnvPrefix prefixes the per-host token variables, e.g. FORGE_TOKEN_GITLAB_EXAMPLE_COM
+	TokenEnvPrefix = "FORGE_TOKEN_"
+)
+
+// segmentPattern restricts repository path segments to characters the forges allow
+var segmentPattern = regexp.MustCompile(`^[A-Za-z0-9_.~-]+$`)
+
+// Host is a code hosting server
+type Host struct {
+	Name    string // host name with any port, e.g. gitlab.example.com
+	Kind    string // github, gitlab or bitbucket
+	BaseURL string // web address including any context path, e.g. https://git.example.com/bitbucket
+	Token   string
+}
+
+// Repository identifies a repository on a host. Number is set when the repository was
+// given as an issue or pull request URL.
+type Repository struct {
+	Host   Host
+	Path   string // owner/repo on GitHub, group/subgroup/project on GitLab, PROJECT/repo on Bitbucket Server
+	Number int
+}
+
+// String returns the repository as host/path
+func (r Repository) String() string {
+	return r.Host.Name + "/" + r.Path
+}
+
+// defaultHosts are available without configuration
+var defaultHosts = []Host{
+	{Name: "github.com", Kind: KindGitHub, BaseURL: "https://github.com"},
+	{Name: "gitlab.com", Kind: KindGitLab, BaseURL: "https://gitlab.com"},
+}
+
+// LoadHosts returns github.com, gitlab.com and the hosts in FORGE_HOSTS, such as
+// "gitlab.example.com=gitlab,https://git.example.com/bitbucket=bitbucket". Hosts without a
+// scheme use https. Each host's token is read from FORGE_TOKEN_<HOST>.
+func LoadHosts() ([]Host, error) {
+	hosts := slices.Clone(defaultHosts)
+	for entry := range strings.SplitSeq(os.Getenv(HostsEnvVar), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		address, kind, ok := strings.Cut(entry, "=")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !ok || !slices.Contains([]string{KindGitHub, KindGitLab, KindBitbucket}, kind) {
+			return nil, fmt.Errorf("invalid %s entry %q - use host=kind where kind is github, gitlab or bitbucket", HostsEnvVar, entry)
+		}
+
+		address = strings.TrimRight(strings.TrimSpace(address), "/")
+		if !strings.Contains(address, "://") {
+			address = "https://" + address
+		}
+		parsed, err := url.Parse(address)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+			return nil, fmt.Errorf("invalid %s entry %q - host must be a host name or http(s) URL", HostsEnvVar, entry)
+		}
+
+		name := strings.ToLower(parsed.Host)
+		host := Host{Name: name, Kind: kind, BaseURL: parsed.Scheme + "://" + name + parsed.Path}
+		// A configured host replaces a default or earlier entry with the same name
+		hosts = slices.DeleteFunc(hosts, func(h Host) bool { return h.Name == name })
+		hosts = append(hosts, host)
+	}
+
+	for i := range hosts {
+		hosts[i].Token = os.Getenv(TokenEnvVar(hosts[i].Name))
+		if hosts[i].Token == "" && hosts[i].Name == "gitlab.com" {
+			hosts[i].Token = os.Getenv("GITLAB_TOKEN")
+		}
+	}
+	return hosts, nil
+}
+
+// TokenEnvVar returns the variable holding a host's token, e.g. FORGE_TOKEN_GITLAB_EXAMPLE_COM
+func TokenEnvVar(hostName string) string {
+	var sb strings.Builder
+	sb.WriteString(TokenEnvPrefix)
+	for _, r := range strings.ToUpper(hostName) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+// ParseRepository resolves a repository URL, host/path, or GitHub owner/repo shorthand to a
+// configured host. Issue, pull request and merge request URLs also set Number.
+func ParseRepository(input string, hosts []Host) (Repository, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Repository{}, fmt.Errorf("repository parameter is required")
+	}
+
+	address := input
+	if !strings.Contains(address, "://") {
+		// owner/repo without a host refers to GitHub
+		if first, _, _ := strings.Cut(address, "/"); !strings.ContainsAny(first, ".:") {
+			address = "github.com/" + address
+		}
+		address = "https://" + address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Host == "" {
+		return Repository{}, fmt.Errorf("invalid repository: %s", input)
+	}
+
+	// Prefer the host with the longest base URL so context paths such as /bitbucket match
+	var host *Host
+	for i, candidate := range hosts {
+		base, err := url.Parse(candidate.BaseURL)
+		if err != nil || !strings.EqualFold(base.Host, parsed.Host) {
+			continue
+		}
+		if base.Path != "" && parsed.Path != base.Path && !strings.HasPrefix(parsed.Path, base.Path+"/") {
+			continue
+		}
+		if host == nil || len(candidate.BaseURL) > len(host.BaseURL) {
+			host = &hosts[i]
+		}
+	}
+	if host == nil {
+		return Repository{}, fmt.Errorf("unknown host %s - add it to %s, e.g. %s=gitlab", parsed.Host, HostsEnvVar, parsed.Host)
+	}
+
+	base, _ := url.Parse(host.BaseURL)
+	rest := strings.Trim(strings.TrimPrefix(parsed.Path, base.Path), "/")
+	rest = strings.TrimSuffix(rest, ".git")
+
+	repo := Repository{Host: *host}
+	switch host.Kind {
+	case KindGitHub:
+		repo.Path, repo.Number, err = parseGitHubPath(rest)
+	case KindGitLab:
+		repo.Path, repo.Number, err = parseGitLabPath(rest)
+	case KindBitbucket:
+		repo.Path, repo.Number, err = parseBitbucketPath(rest)
+	}
+	if err != nil {
+		return Repository{}, fmt.Errorf("invalid repository %s: %w", input, err)
+	}
+	return repo, nil
+}
+
+// parseGitHubPath reads owner/repo[/pull|issues/N]
+func parseGitHubPath(path string) (string, int, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || !validSegments(parts[:2]) {
+		return "", 0, fmt.Errorf("expected owner/repo")
+	}
+	number := 0
+	if len(parts) >= 4 && (parts[2] == "pull" || parts[2] == "issues") {
+		var err error
+		if number, err = parseNumber(parts[3]); err != nil {
+			return "", 0, err
+		}
+	}
+	return parts[0] + "/" + parts[1], number, nil
+}
+
+// parseGitLabPath reads group/subgroup/project[/-/merge_requests|issues/N]
+func parseGitLabPath(path string) (string, int, error) {
+	project, tail, _ := strings.Cut(path, "/-/")
+	segments := strings.Split(project, "/")
+	if len(segments) < 2 || !validSegments(segments) {
+		return "", 0, fmt.Errorf("expected group/project")
+	}
+	number := 0
+	if parts := strings.Split(tail, "/"); len(parts) >= 2 && (parts[0] == "merge_requests" || parts[0] == "issues") {
+		var err error
+		if number, err = parseNumber(parts[1]); err != nil {
+			return "", 0, err
+		}
+	}
+	return project, number, nil
+}
+
+// parseBitbucketPath reads projects/KEY/repos/slug[/pull-requests/N], users/name/repos/slug
+// for personal repositories, or the KEY/slug shorthand
+func parseBitbucketPath(path string) (string, int, error) {
+	parts := strings.Split(path, "/")
+	var repoPath string
+	var tail []string
+	switch {
+	case len(parts) >= 4 && parts[0] == "projects" && parts[2] == "repos":
+		repoPath, tail = parts[1]+"/"+parts[3], parts[4:]
+	case len(parts) >= 4 && parts[0] == "users" && parts[2] == "repos":
+		repoPath, tail = "~"+parts[1]+"/"+parts[3], parts[4:]
+	case len(parts) == 2:
+		repoPath = parts[0] + "/" + parts[1]
+	default:
+		return "", 0, fmt.Errorf("expected projects/KEY/repos/slug or KEY/slug")
+	}
+	if !validSegments(strings.Split(repoPath, "/")) {
+		return "", 0, fmt.Errorf("expected projects/KEY/repos/slug or KEY/slug")
+	}
+	number := 0
+	if len(tail) >= 2 && tail[0] == "pull-requests" {
+		var err error
+		if number, err = parseNumber(tail[1]); err != nil {
+			return "", 0, err
+		}
+	}
+	return repoPath, number, nil
+}
+
+// validSegments reports whether every path segment is non-empty and safe to use in API paths
+func validSegments(segments []string) bool {
+	for _, segment := range segments {
+		if !segmentPattern.MatchString(segment) || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNumber reads an issue or pull request number from a URL
+func parseNumber(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("invalid issue or pull request number: %s", value)
+	}
+	return number, nil
+}
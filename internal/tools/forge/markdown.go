@@ -0,0 +1,305 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBodyChars limits review and comment bodies in markdown summaries
+const maxBodyChars = 1000
+
+// Markdown renders the code search results
+func (r *CodeSearchResult) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Code search: `%s`\n\n", r.Query)
+	fmt.Fprintf(&sb, "%d matching files, showing %d\n", r.TotalCount, len(r.Items))
+	if r.IncompleteResults {
+		sb.WriteString("\n> The search timed out before finishing, so some matches may be missing.\n")
+	}
+
+	for _, item := range r.Items {
+		fmt.Fprintf(&sb, "\n## %s: `%s`\n\n%s\n", item.Repository, item.Path, item.HTMLURL)
+		for _, fragment := range item.Fragments {
+			fence := codeFence(fragment)
+			fmt.Fprintf(&sb, "\n%s\n%s\n%s\n", fence, fragment, fence)
+		}
+	}
+
+	writePageFooter(&sb, r.Page, "results")
+	return sb.String()
+}
+
+// Markdown renders the issue list as a table
+func (l *IssueList) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Issues: %s (%s)\n", l.Repository, l.State)
+	if len(l.Labels) > 0 {
+		fmt.Fprintf(&sb, "\nLabels: %s\n", strings.Join(l.Labels, ", "))
+	}
+
+	if len(l.Items) == 0 {
+		sb.WriteString("\nNo issues found.\n")
+	} else {
+		sb.WriteString("\n| # | Title | State | Author | Labels | Comments | Updated |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, issue := range l.Items {
+			fmt.Fprintf(&sb, "| [#%d](%s) | %s | %s | @%s | %s | %d | %s |\n",
+				issue.Number, issue.HTMLURL, tableCell(issue.Title), issue.State, issue.Login,
+				tableCell(strings.Join(issue.Labels, ", ")), issue.Comments, dateOf(issue.UpdatedAt))
+		}
+	}
+
+	writePageFooter(&sb, l.Page, "issues")
+	return sb.String()
+}
+
+// Markdown renders a confirmation for the created issue
+func (c *CreatedIssue) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Created issue #%d in %s: %s\n\n%s\n", c.Number, c.Repository, c.Title, c.HTMLURL)
+	if len(c.Labels) > 0 || len(c.Assignees) > 0 {
+		sb.WriteString("\n")
+	}
+	if len(c.Labels) > 0 {
+		fmt.Fprintf(&sb, "- Labels: %s\n", strings.Join(c.Labels, ", "))
+	}
+	if len(c.Assignees) > 0 {
+		fmt.Fprintf(&sb, "- Assignees: @%s\n", strings.Join(c.Assignees, ", @"))
+	}
+	return sb.String()
+}
+
+// Markdown renders the changed files with their patches as diff blocks
+func (d *PullRequestDiff) Markdown() string {
+	var sb strings.Builder
+	additions, deletions := 0, 0
+	for _, file := range d.Files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	fmt.Fprintf(&sb, "# Pull request diff: %s#%d\n\n", d.Repository, d.Number)
+	fmt.Fprintf(&sb, "%d files on this page, +%d -%d\n", len(d.Files), additions, deletions)
+
+	for _, file := range d.Files {
+		fmt.Fprintf(&sb, "\n## `%s` (%s, +%d -%d)\n", file.Filename, file.Status, file.Additions, file.Deletions)
+		if file.PreviousFilename != "" {
+			fmt.Fprintf(&sb, "\nRenamed from `%s`\n", file.PreviousFilename)
+		}
+		if file.Patch == "" {
+			sb.WriteString("\nNo text diff - the file is binary, unchanged in content, or too large for the API.\n")
+			continue
+		}
+		fence := codeFence(file.Patch)
+		fmt.Fprintf(&sb, "\n%sdiff\n%s\n%s\n", fence, file.Patch, fence)
+		if file.PatchTruncated {
+			sb.WriteString("\nPatch truncated - view the full diff for this file in the web interface.\n")
+		}
+	}
+
+	writePageFooter(&sb, d.Page, "files")
+	return sb.String()
+}
+
+// Markdown renders reviews, then inline comments grouped by file
+func (r *ReviewComments) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Review comments: %s#%d\n", r.Repository, r.Number)
+
+	if len(r.Reviews) > 0 {
+		sb.WriteString("\n## Reviews\n\n")
+		for _, review := range r.Reviews {
+			fmt.Fprintf(&sb, "- **@%s** %s", review.Login, strings.ToLower(strings.ReplaceAll(review.State, "_", " ")))
+			if review.SubmittedAt != "" {
+				fmt.Fprintf(&sb, " (%s)", dateOf(review.SubmittedAt))
+			}
+			if body := strings.TrimSpace(review.Body); body != "" {
+				fmt.Fprintf(&sb, ": %s", indentBody(body))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(r.Comments) == 0 {
+		sb.WriteString("\nNo inline review comments.\n")
+		writePageFooter(&sb, r.Page, "comments")
+		return sb.String()
+	}
+
+	// Group comments by file, keeping the order in which files were first commented on
+	var paths []string
+	byPath := make(map[string][]ReviewComment)
+	for _, comment := range r.Comments {
+		if _, ok := byPath[comment.Path]; !ok {
+			paths = append(paths, comment.Path)
+		}
+		byPath[comment.Path] = append(byPath[comment.Path], comment)
+	}
+
+	sb.WriteString("\n## Inline comments\n")
+	for _, path := range paths {
+		fmt.Fprintf(&sb, "\n### `%s`\n\n", path)
+		for _, comment := range byPath[path] {
+			prefix := "- "
+			if comment.InReplyTo != 0 {
+				prefix = "  - reply: "
+			}
+			location := fmt.Sprintf("line %d", comment.Line)
+			if comment.Outdated {
+				location += ", outdated"
+			}
+			fmt.Fprintf(&sb, "%s**@%s** (%s, %s): %s\n", prefix, comment.Login, location, dateOf(comment.CreatedAt), indentBody(comment.Body))
+		}
+	}
+
+	writePageFooter(&sb, r.Page, "comments")
+	return sb.String()
+}
+
+// Markdown renders the overall CI state and a table of checks, failing checks first
+func (s *CommitStatus) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# CI status: %s@%s\n\n", s.Repository, s.Ref)
+	if s.SHA != "" && s.SHA != s.Ref {
+		fmt.Fprintf(&sb, "- Commit: %s\n", s.SHA)
+	}
+	if s.TotalChecks == 0 {
+		sb.WriteString("- State: **none** (no checks or statuses reported for this commit)\n")
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "- State: **%s** (%d failing, %d pending, %d passing of %d checks)\n", s.State, s.Failing, s.Pending, s.Passing, s.TotalChecks)
+
+	sb.WriteString("\n| Check | Result | Details |\n")
+	sb.WriteString("|---|---|---|\n")
+	for _, check := range s.Checks {
+		result := check.Conclusion
+		if check.Status != "completed" {
+			result = strings.ReplaceAll(check.Status, "_", " ")
+		}
+		details := tableCell(check.Description)
+		if check.URL != "" {
+			details = strings.TrimSpace(fmt.Sprintf("%s [link](%s)", details, check.URL))
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s |\n", tableCell(check.Name), result, details)
+	}
+
+	writePageFooter(&sb, s.Page, "checks")
+	return sb.String()
+}
+
+// Markdown renders the issue with its description and comments
+func (i *Issue) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Issue %s#%d: %s\n\n", i.Repository, i.Number, i.Title)
+	fmt.Fprintf(&sb, "- State: %s\n- Author: @%s\n- Created: %s, updated %s\n", i.State, i.Author, dateOf(i.CreatedAt), dateOf(i.UpdatedAt))
+	if len(i.Labels) > 0 {
+		fmt.Fprintf(&sb, "- Labels: %s\n", strings.Join(i.Labels, ", "))
+	}
+	fmt.Fprintf(&sb, "- URL: %s\n", i.URL)
+	writeBody(&sb, i.Body)
+	writeComments(&sb, i.Comments)
+	return sb.String()
+}
+
+// Markdown renders the pull request with its description and conversation comments
+func (p *PullRequest) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Pull request %s#%d: %s\n\n", p.Repository, p.Number, p.Title)
+	state := p.State
+	if p.Draft {
+		state += " (draft)"
+	}
+	fmt.Fprintf(&sb, "- State: %s\n- Author: @%s\n", state, p.Author)
+	fmt.Fprintf(&sb, "- Branches: `%s` into `%s`\n", p.SourceBranch, p.TargetBranch)
+	if p.HeadSHA != "" {
+		fmt.Fprintf(&sb, "- Head commit: %s\n", p.HeadSHA)
+	}
+	fmt.Fprintf(&sb, "- Created: %s, updated %s\n- URL: %s\n", dateOf(p.CreatedAt), dateOf(p.UpdatedAt), p.URL)
+	writeBody(&sb, p.Body)
+	writeComments(&sb, p.Comments)
+	return sb.String()
+}
+
+// Markdown renders the file section in a code block, or the error that prevented reading it
+func (f *FileContents) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## `%s`", f.Path)
+	if f.Ref != "" {
+		fmt.Fprintf(&sb, " @ %s", f.Ref)
+	}
+	sb.WriteString("\n\n")
+	if f.Error != "" {
+		fmt.Fprintf(&sb, "Error: %s\n", f.Error)
+		return sb.String()
+	}
+	if f.StartLine > 1 || f.EndLine < f.TotalLines {
+		fmt.Fprintf(&sb, "Lines %d-%d of %d\n\n", f.StartLine, f.EndLine, f.TotalLines)
+	}
+	fence := codeFence(f.Content)
+	fmt.Fprintf(&sb, "%s\n%s\n%s\n", fence, strings.TrimRight(f.Content, "\n"), fence)
+	if f.EndLine < f.TotalLines {
+		fmt.Fprintf(&sb, "\nSet options.line_start to %d to read on.\n", f.EndLine+1)
+	}
+	return sb.String()
+}
+
+// writeBody writes an issue or pull request description
+func writeBody(sb *strings.Builder, body string) {
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(sb, "\n## Description\n\n%s\n", body)
+	}
+}
+
+// writeComments writes conversation comments as a list
+func writeComments(sb *strings.Builder, comments []Comment) {
+	if len(comments) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## Comments (%d)\n\n", len(comments))
+	for _, comment := range comments {
+		fmt.Fprintf(sb, "- **@%s** (%s): %s\n", comment.Author, dateOf(comment.CreatedAt), indentBody(comment.Body))
+	}
+}
+
+// writePageFooter tells the caller how to fetch the next page, if there is one
+func writePageFooter(sb *strings.Builder, page Page, noun string) {
+	switch {
+	case page.NextPage > 0 && page.LastPage > 0:
+		fmt.Fprintf(sb, "\nPage %d of %d. Set options.page to %d for more %s.\n", page.Page, page.LastPage, page.NextPage, noun)
+	case page.NextPage > 0:
+		fmt.Fprintf(sb, "\nPage %d. Set options.page to %d for more %s.\n", page.Page, page.NextPage, noun)
+	case page.Page > 1:
+		fmt.Fprintf(sb, "\nPage %d (last page).\n", page.Page)
+	}
+}
+
+// codeFence returns a backtick fence longer than any backtick run in text
+func codeFence(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence
+}
+
+// tableCell makes text safe for a single markdown table cell
+func tableCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// indentBody truncates a comment body and indents its continuation lines under a list item
+func indentBody(body string) string {
+	body = strings.TrimSpace(body)
+	if runes := []rune(body); len(runes) > maxBodyChars {
+		body = string(runes[:maxBodyChars]) + "…"
+	}
+	return strings.ReplaceAll(body, "\n", "\n    ")
+}
+
+// dateOf trims a timestamp to its date
+func dateOf(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
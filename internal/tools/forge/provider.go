@@ -0,0 +1,149 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	KindGitHub    = "github"
+	KindGitLab    = "gitlab"
+	KindBitbucket = "bitbucket"
+
+	// DefaultMaxPatchLines is the maximum number of patch lines returned per changed file
+	DefaultMaxPatchLines = 500
+)
+
+// ErrUnsupported is returned when a forge has no equivalent of an operation
+var ErrUnsupported = errors.New("not supported")
+
+// Provider is the set of code hosting operations available on every forge. Each forge
+// maps these onto its own API, so pull requests are merge requests on GitLab.
+type Provider interface {
+	// SearchCode searches file contents within a repository
+	SearchCode(ctx context.Context, repo Repository, query string, page, perPage int) (*CodeSearchResult, error)
+	// ListIssues lists issues, most recently updated first. state is "open" or "all".
+	ListIssues(ctx context.Context, repo Repository, state string, labels []string, page, perPage int) (*IssueList, error)
+	// GetIssue gets an issue with optional comments
+	GetIssue(ctx context.Context, repo Repository, number int, includeComments bool) (*Issue, error)
+	// CreateIssue opens a new issue
+	CreateIssue(ctx context.Context, repo Repository, request IssueRequest) (*CreatedIssue, error)
+	// GetPullRequest gets a pull or merge request with optional conversation comments
+	GetPullRequest(ctx context.Context, repo Repository, number int, includeComments bool) (*PullRequest, error)
+	// GetPullRequestDiff gets a page of changed files with their patches
+	GetPullRequestDiff(ctx context.Context, repo Repository, number, page, perPage int) (*PullRequestDiff, error)
+	// GetReviewComments gets review verdicts and a page of inline review comments
+	GetReviewComments(ctx context.Context, repo Repository, number, page, perPage int) (*ReviewComments, error)
+	// GetCommitStatus gets the CI state of a commit, branch or tag
+	GetCommitStatus(ctx context.Context, repo Repository, ref string, page, perPage int) (*CommitStatus, error)
+	// GetFileContents gets the raw contents of a file. An empty ref means the default branch.
+	GetFileContents(ctx context.Context, repo Repository, path, ref string) (string, error)
+}
+
+// ProviderFactory creates a provider for a configured host
+type ProviderFactory func(ctx context.Context, host Host, logger *logrus.Logger) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]ProviderFactory{}
+)
+
+// RegisterProvider makes a forge kind available. Providers that live in other packages,
+// such as GitHub, register themselves from init.
+func RegisterProvider(kind string, factory ProviderFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[kind] = factory
+}
+
+// newProvider creates the provider for a host's kind
+func newProvider(ctx context.Context, host Host, logger *logrus.Logger) (Provider, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[host.Kind]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no provider is available for %s hosts", host.Kind)
+	}
+	return factory(ctx, host, logger)
+}
+
+// unsupported reports an operation that a forge cannot perform
+func unsupported(forge, operation, hint string) error {
+	return fmt.Errorf("%s is %w on %s - %s", operation, ErrUnsupported, forge, hint)
+}
+
+// failingConclusions are check run and commit status results that fail CI
+var failingConclusions = map[string]bool{
+	"failure":         true,
+	"error":           true,
+	"timed_out":       true,
+	"cancelled":       true,
+	"action_required": true,
+	"startup_failure": true,
+}
+
+// SummariseChecks works out the overall CI state and sorts failing checks first, then pending ones
+func SummariseChecks(checks []StatusCheck) *CommitStatus {
+	result := &CommitStatus{TotalChecks: len(checks)}
+	rank := func(check StatusCheck) int {
+		switch {
+		case failingConclusions[check.Conclusion]:
+			return 0
+		case check.Status != "completed":
+			return 1
+		}
+		return 2
+	}
+
+	for _, check := range checks {
+		switch rank(check) {
+		case 0:
+			result.Failing++
+		case 1:
+			result.Pending++
+		default:
+			result.Passing++
+		}
+	}
+
+	switch {
+	case len(checks) == 0:
+		result.State = "none"
+	case result.Failing > 0:
+		result.State = "failure"
+	case result.Pending > 0:
+		result.State = "pending"
+	default:
+		result.State = "success"
+	}
+
+	result.Checks = slices.Clone(checks)
+	slices.SortStableFunc(result.Checks, func(a, b StatusCheck) int {
+		if diff := rank(a) - rank(b); diff != 0 {
+			return diff
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+// Paginate returns one page of a list that has already been fetched in full
+func Paginate[T any](items []T, page, perPage int) ([]T, Page) {
+	result := Page{Page: page, PerPage: perPage}
+	total := len(items)
+	if total > 0 {
+		result.LastPage = (total + perPage - 1) / perPage
+	}
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+	if end < total {
+		result.NextPage = page + 1
+	}
+	return items[start:end], result
+}
@@ -0,0 +1,191 @@
+package forge
+
+// Page describes where a page of results sits in a paginated listing
+type Page struct {
+	Page     int `json:"page"`
+	PerPage  int `json:"per_page"`
+	NextPage int `json:"next_page,omitempty"` // 0 when this is the last page
+	LastPage int `json:"last_page,omitempty"` // 0 when GitHub does not report it
+}
+
+// CodeMatch represents a file matched by code search
+type CodeMatch struct {
+	Repository string   `json:"repository"`
+	Path       string   `json:"path"`
+	HTMLURL    string   `json:"html_url"`
+	Fragments  []string `json:"fragments,omitempty"`
+}
+
+// CodeSearchResult represents a page of code search results
+type CodeSearchResult struct {
+	Query             string      `json:"query"`
+	TotalCount        int         `json:"total_count"`
+	IncompleteResults bool        `json:"incomplete_results"`
+	Items             []CodeMatch `json:"items"`
+	Page              Page        `json:"page"`
+}
+
+// IssueSummary represents an issue in a repository listing
+type IssueSummary struct {
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	State     string   `json:"state"`
+	Login     string   `json:"login"`
+	Labels    []string `json:"labels,omitempty"`
+	Comments  int      `json:"comments"`
+	HTMLURL   string   `json:"html_url"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// IssueList represents a page of repository issues
+type IssueList struct {
+	Repository string         `json:"repository"`
+	State      string         `json:"state"`
+	Labels     []string       `json:"labels,omitempty"`
+	Items      []IssueSummary `json:"items"`
+	Page       Page           `json:"page"`
+}
+
+// CreatedIssue represents an issue created by create_issue
+type CreatedIssue struct {
+	Repository string   `json:"repository"`
+	Number     int      `json:"number"`
+	Title      string   `json:"title"`
+	Labels     []string `json:"labels,omitempty"`
+	Assignees  []string `json:"assignees,omitempty"`
+	HTMLURL    string   `json:"html_url"`
+}
+
+// PullRequestFile represents a file changed by a pull request with its patch
+type PullRequestFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Patch            string `json:"patch,omitempty"`
+	PatchTruncated   bool   `json:"patch_truncated,omitempty"`
+}
+
+// PullRequestDiff represents a page of files changed by a pull request
+type PullRequestDiff struct {
+	Repository string            `json:"repository"`
+	Number     int               `json:"number"`
+	Files      []PullRequestFile `json:"files"`
+	Page       Page              `json:"page"`
+}
+
+// Review represents a submitted pull request review
+type Review struct {
+	Login       string `json:"login"`
+	State       string `json:"state"`
+	Body        string `json:"body,omitempty"`
+	SubmittedAt string `json:"submitted_at,omitempty"`
+	HTMLURL     string `json:"html_url"`
+}
+
+// ReviewComment represents an inline comment on a pull request diff
+type ReviewComment struct {
+	ID        int64  `json:"id"`
+	InReplyTo int64  `json:"in_reply_to,omitempty"`
+	Login     string `json:"login"`
+	Path      string `json:"path"`
+	Line      int    `json:"line,omitempty"`
+	Outdated  bool   `json:"outdated,omitempty"` // the commented lines have since changed
+	Body      string `json:"body"`
+	HTMLURL   string `json:"html_url"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReviewComments represents the reviews and a page of inline review comments on a pull request
+type ReviewComments struct {
+	Repository string          `json:"repository"`
+	Number     int             `json:"number"`
+	Reviews    []Review        `json:"reviews,omitempty"` // only fetched for the first page
+	Comments   []ReviewComment `json:"comments"`
+	Page       Page            `json:"page"`
+}
+
+// StatusCheck represents a commit status or check run
+type StatusCheck struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`   // "check" or "status"
+	Status      string `json:"status"` // queued, in_progress or completed
+	Conclusion  string `json:"conclusion,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// CommitStatus represents the CI state of a commit
+type CommitStatus struct {
+	Repository  string        `json:"repository"`
+	Ref         string        `json:"ref"`
+	SHA         string        `json:"sha"`
+	State       string        `json:"state"` // success, failure, pending or none
+	Failing     int           `json:"failing"`
+	Pending     int           `json:"pending"`
+	Passing     int           `json:"passing"`
+	Checks      []StatusCheck `json:"checks"`
+	TotalChecks int           `json:"total_checks"`
+	Page        Page          `json:"page"`
+}
+
+// IssueRequest describes an issue to create
+type IssueRequest struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string // usernames
+}
+
+// Comment represents a comment on an issue or pull request conversation
+type Comment struct {
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+	URL       string `json:"url,omitempty"`
+}
+
+// Issue represents an issue with its description and optional comments
+type Issue struct {
+	Repository string    `json:"repository"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	State      string    `json:"state"`
+	Author     string    `json:"author"`
+	Body       string    `json:"body,omitempty"`
+	Labels     []string  `json:"labels,omitempty"`
+	URL        string    `json:"url"`
+	CreatedAt  string    `json:"created_at"`
+	UpdatedAt  string    `json:"updated_at"`
+	Comments   []Comment `json:"comments,omitempty"`
+}
+
+// PullRequest represents a pull request, or a merge request on GitLab
+type PullRequest struct {
+	Repository   string    `json:"repository"`
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	State        string    `json:"state"`
+	Draft        bool      `json:"draft,omitempty"`
+	Author       string    `json:"author"`
+	Body         string    `json:"body,omitempty"`
+	SourceBranch string    `json:"source_branch"`
+	TargetBranch string    `json:"target_branch"`
+	HeadSHA      string    `json:"head_sha,omitempty"`
+	URL          string    `json:"url"`
+	CreatedAt    string    `json:"created_at"`
+	UpdatedAt    string    `json:"updated_at"`
+	Comments     []Comment `json:"comments,omitempty"`
+}
+
+// FileContents represents a section of a file read from a repository
+type FileContents struct {
+	Path       string `json:"path"`
+	Ref        string `json:"ref,omitempty"`
+	Content    string `json:"content,omitempty"`
+	StartLine  int    `json:"start_line,omitempty"`
+	EndLine    int    `json:"end_line,omitempty"`
+	TotalLines int    `json:"total_lines,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
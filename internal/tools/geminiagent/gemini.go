@@ -3,17 +3,22 @@ package geminiagent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/usage"
 	"github.com/sirupsen/logrus"
 )
 
@@ -86,16 +91,29 @@ func (t *GeminiTool) Execute(ctx context.Context, logger *logrus.Logger, cache *
 	yoloMode := tools.GetEffectivePermissionsValue(yoloModeParam)
 	includeAllFiles, _ := args["include-all-files"].(bool)
 
+	// Refuse the call once the session has spent its agent budget
+	if err := tools.CheckAgentCostLimit(ctx); err != nil {
+		return nil, err
+	}
+	// Each run of the CLI counts against the gemini usage limit
+	if err := usage.Consume("gemini"); err != nil {
+		return nil, err
+	}
+
 	// Initial attempt
-	output, err := t.runGemini(ctx, logger, time.Duration(timeout)*time.Second, prompt, model, sandbox, yoloMode, includeAllFiles)
+	output, usages, err := t.runGemini(ctx, logger, time.Duration(timeout)*time.Second, prompt, model, sandbox, yoloMode, includeAllFiles)
 	if err != nil {
 		// Check for quota error and attempt fallback to flash model
 		if strings.Contains(err.Error(), "RESOURCE_EXHAUSTED") && model != flashModel {
 			logger.Warnf("Gemini API quota exceeded for model %s, falling back to %s", model, flashModel)
-			output, err = t.runGemini(ctx, logger, time.Duration(timeout)*time.Second, prompt, flashModel, sandbox, yoloMode, includeAllFiles)
+			if err := usage.Consume("gemini"); err != nil {
+				return nil, err
+			}
+			output, usages, err = t.runGemini(ctx, logger, time.Duration(timeout)*time.Second, prompt, flashModel, sandbox, yoloMode, includeAllFiles)
 		} else if err == context.DeadlineExceeded {
 			timeoutMsg := fmt.Sprintf("\n\nThe Gemini Agent hit the configured timeout of %d seconds, output may be truncated!", timeout)
-			return mcp.NewToolResultText(output + timeoutMsg), nil
+			result := mcp.NewToolResultText(output + timeoutMsg)
+			return tools.WithAgentUsage(result, usages, tools.RecordAgentUsage(ctx, usages...)), nil
 		}
 	}
 
@@ -107,14 +125,41 @@ func (t *GeminiTool) Execute(ctx context.Context, logger *logrus.Logger, cache *
 	// Apply response size limits
 	output = t.ApplyResponseSizeLimit(output, logger)
 
-	return mcp.NewToolResultText(output), nil
+	result := mcp.NewToolResultText(output)
+	return tools.WithAgentUsage(result, usages, tools.RecordAgentUsage(ctx, usages...)), nil
+}
+
+// plainOutput is set once the installed CLI is found not to support JSON output
+var plainOutput atomic.Bool
+
+// geminiOutput is the JSON output of the gemini CLI
+type geminiOutput struct {
+	Response string `json:"response"`
+	Stats    struct {
+		Models map[string]struct {
+			Tokens struct {
+				Prompt     int `json:"prompt"`
+				Candidates int `json:"candidates"`
+				Cached     int `json:"cached"`
+				Thoughts   int `json:"thoughts"`
+			} `json:"tokens"`
+		} `json:"models"`
+	} `json:"stats"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
-func (t *GeminiTool) runGemini(ctx context.Context, logger *logrus.Logger, timeout time.Duration, prompt, model string, sandbox, yoloMode, includeAllFiles bool) (string, error) {
+// runGemini runs the gemini CLI and returns its response and the usage of each model it used. Token
+// counts are taken from the CLI's JSON output, or estimated when it gives plain text.
+func (t *GeminiTool) runGemini(ctx context.Context, logger *logrus.Logger, timeout time.Duration, prompt, model string, sandbox, yoloMode, includeAllFiles bool) (string, []tools.AgentUsage, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	cmdArgs := []string{}
+	if !plainOutput.Load() {
+		cmdArgs = append(cmdArgs, "--output-format", "json")
+	}
 	if model != "" {
 		cmdArgs = append(cmdArgs, "-m", model)
 	}
@@ -139,13 +184,34 @@ func (t *GeminiTool) runGemini(ctx context.Context, logger *logrus.Logger, timeo
 	err := cmd.Run()
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return outb.String(), context.DeadlineExceeded
+			return outb.String(), estimateUsage(model, prompt, outb.String()), context.DeadlineExceeded
 		}
 		stderr := errb.String()
+		if strings.Contains(stderr, "output-format") && !plainOutput.Swap(true) {
+			logger.Warn("Gemini CLI does not support --output-format, token counts will be estimated. Upgrade the CLI for exact counts")
+			return t.runGemini(ctx, logger, timeout, prompt, model, sandbox, yoloMode, includeAllFiles)
+		}
+		var parsed geminiOutput
+		if json.Unmarshal(outb.Bytes(), &parsed) == nil && parsed.Error != nil {
+			stderr = strings.TrimSpace(stderr + "\n" + parsed.Error.Message)
+		}
 		if stderr != "" {
-			return "", fmt.Errorf("error: %w, stderr: %s", err, stderr)
+			return "", nil, fmt.Errorf("error: %w, stderr: %s", err, stderr)
 		}
-		return "", fmt.Errorf("error: %w", err)
+		return "", nil, fmt.Errorf("error: %w", err)
+	}
+
+	var parsed geminiOutput
+	if err := json.Unmarshal(outb.Bytes(), &parsed); err == nil && len(parsed.Stats.Models) > 0 {
+		usages := make([]tools.AgentUsage, 0, len(parsed.Stats.Models))
+		for _, name := range slices.Sorted(maps.Keys(parsed.Stats.Models)) {
+			tokens := parsed.Stats.Models[name].Tokens
+			// Thinking is billed as output
+			modelUsage := tools.AgentUsage{Model: name, InputTokens: tokens.Prompt, OutputTokens: tokens.Candidates + tokens.Thoughts, CachedTokens: tokens.Cached}
+			modelUsage.Price()
+			usages = append(usages, modelUsage)
+		}
+		return parsed.Response, usages, nil
 	}
 
 	// Strip unwanted startup messages
@@ -160,7 +226,21 @@ func (t *GeminiTool) runGemini(ctx context.Context, logger *logrus.Logger, timeo
 		cleanLines = append(cleanLines, line)
 	}
 
-	return strings.Join(cleanLines, "\n"), nil
+	output = strings.Join(cleanLines, "\n")
+	return output, estimateUsage(model, prompt, output), nil
+}
+
+// estimateUsage estimates the usage of a run from the length of its prompt and output, for when the
+// CLI does not report token counts
+func estimateUsage(model, prompt, output string) []tools.AgentUsage {
+	estimate := tools.AgentUsage{
+		Model:        model,
+		InputTokens:  tools.EstimateAgentTokens(prompt),
+		OutputTokens: tools.EstimateAgentTokens(output),
+		Estimated:    true,
+	}
+	estimate.Price()
+	return []tools.AgentUsage{estimate}
 }
 
 // GetTimeout returns the configured timeout or default
@@ -271,6 +351,10 @@ func (t *GeminiTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 				Problem:  "RESOURCE_EXHAUSTED quota exceeded error",
 				Solution: "Gemini API quota limits reached. The tool automatically falls back to gemini-2.5-flash when quota is exceeded. Wait for quota reset or upgrade your Google AI API plan.",
 			},
+			{
+				Problem:  "Session has reached its cost limit",
+				Solution: "The user set AGENT_SESSION_COST_LIMIT and this session's estimated spend on agent calls has reached it. Continue the task without delegating to Gemini, or ask the user to raise the limit.",
+			},
 			{
 				Problem:  "Agent timeout after 3 minutes",
 				Solution: "Complex analysis may need more time. Set AGENT_TIMEOUT environment variable to increase timeout (in seconds). Example: AGENT_TIMEOUT=600 for 10 minutes.",
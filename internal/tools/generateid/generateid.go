@@ -0,0 +1,356 @@
+package generateid
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// defaultNanoidLength is the length of a nanoid by default, about as unlikely to collide as a UUIDv4
+	defaultNanoidLength = 21
+
+	// defaultSecretBytes is how many random bytes a hex or base64 secret has by default
+	defaultSecretBytes = 32
+
+	// maxLength is the longest nanoid, in characters, or secret, in bytes, that can be generated
+	maxLength = 1024
+
+	// maxCount is the most values a single call can generate
+	maxCount = 100
+
+	// maxHashInput is the largest input that can be hashed
+	maxHashInput = 1024 * 1024
+
+	// bcryptMaxInput is the most bytes bcrypt uses, it rejects longer input
+	bcryptMaxInput = 72
+
+	// minBcryptCost and maxBcryptCost bound the bcrypt cost, as each step doubles the time taken
+	minBcryptCost = 10
+	maxBcryptCost = 14
+)
+
+// generateTypes are the kinds of random value the tool generates
+var generateTypes = []string{"uuid_v4", "uuid_v7", "ulid", "nanoid", "hex", "base64", "base64url"}
+
+// hashTypes are the hash algorithms the tool applies to input
+var hashTypes = []string{"sha256", "sha512", "bcrypt"}
+
+// GenerateIDTool generates identifiers and secrets from a cryptographically secure random source, and
+// hashes input
+type GenerateIDTool struct{}
+
+// GenerateResponse is the result of generating random values
+type GenerateResponse struct {
+	Type        string   `json:"type"`
+	Values      []string `json:"values"`
+	EntropyBits int      `json:"entropy_bits"`
+}
+
+// HashResponse is the result of hashing input
+type HashResponse struct {
+	Type       string `json:"type"`
+	Hash       string `json:"hash"`
+	Encoding   string `json:"encoding"`
+	InputBytes int    `json:"input_bytes"`
+}
+
+// init registers the generate_id tool
+func init() {
+	registry.Register(&GenerateIDTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (g *GenerateIDTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"generate_id",
+		mcp.WithDescription(`Generates truly random identifiers and secrets, or hashes input. Use this instead of inventing IDs, which are not random.
+
+Types: uuid_v4, uuid_v7 (time-ordered), ulid (time-ordered), nanoid, hex, base64, base64url (secrets of 'length' random bytes), and sha256, sha512, bcrypt (hash 'input').`),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("Kind of value to generate, or hash algorithm to apply to 'input'"),
+			mcp.Enum(slices.Concat(generateTypes, hashTypes)...),
+		),
+		mcp.WithNumber("count",
+			mcp.Description(fmt.Sprintf("Number of values to generate (default: 1, max: %d)", maxCount)),
+		),
+		mcp.WithNumber("length",
+			mcp.Description(fmt.Sprintf("Characters in a nanoid (default: %d), or random bytes in a hex or base64 secret (default: %d). Max: %d", defaultNanoidLength, defaultSecretBytes, maxLength)),
+		),
+		mcp.WithString("alphabet",
+			mcp.Description("Characters a nanoid is made from (default: A-Z, a-z, 0-9, _ and -)"),
+		),
+		mcp.WithString("input",
+			mcp.Description("Text to hash for sha256, sha512 and bcrypt"),
+		),
+		mcp.WithNumber("cost",
+			mcp.Description(fmt.Sprintf("bcrypt cost from %d to %d (default: %d)", minBcryptCost, maxBcryptCost, bcrypt.DefaultCost)),
+		),
+		// Read-only annotations for value generation
+		mcp.WithReadOnlyHintAnnotation(true),     // Doesn't modify environment
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(false),  // Every call generates new random values
+		mcp.WithOpenWorldHintAnnotation(false),   // Uses the local random source, no external interactions
+	)
+}
+
+// Execute generates values or hashes input
+func (g *GenerateIDTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	kind, _ := args["type"].(string)
+	// Only the type is logged, as input may be a password and generated values may be secrets
+	logger.WithField("type", kind).Debug("Executing generate_id")
+
+	var result any
+	var err error
+	switch {
+	case slices.Contains(generateTypes, kind):
+		result, err = generate(kind, args)
+	case slices.Contains(hashTypes, kind):
+		result, err = hash(kind, args)
+	default:
+		return nil, fmt.Errorf("invalid 'type' parameter: must be one of %v or %v", generateTypes, hashTypes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// generate generates count random values of a kind
+func generate(kind string, args map[string]any) (*GenerateResponse, error) {
+	for _, name := range []string{"input", "cost"} {
+		if _, ok := args[name]; ok {
+			return nil, fmt.Errorf("'%s' is only used with the hash types %v. Type '%s' generates random values", name, hashTypes, kind)
+		}
+	}
+	count, err := wholeNumber(args, "count", 1, 1, maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultLength := defaultSecretBytes
+	if kind == "nanoid" {
+		defaultLength = defaultNanoidLength
+	}
+	length, err := wholeNumber(args, "length", defaultLength, 1, maxLength)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := args["length"]; ok && !slices.Contains([]string{"nanoid", "hex", "base64", "base64url"}, kind) {
+		return nil, fmt.Errorf("'length' is only used with nanoid, hex, base64 and base64url. %s values have a fixed length", kind)
+	}
+
+	alphabet := nanoidAlphabet
+	if alphabetRaw, ok := args["alphabet"]; ok {
+		if kind != "nanoid" {
+			return nil, fmt.Errorf("'alphabet' is only used with nanoid")
+		}
+		if alphabet, err = parseAlphabet(alphabetRaw); err != nil {
+			return nil, err
+		}
+	}
+
+	var next func() (string, error)
+	var entropyBits float64
+	switch kind {
+	case "uuid_v4":
+		next = func() (string, error) {
+			id, err := uuid.NewRandom()
+			return id.String(), err
+		}
+		entropyBits = 122
+	case "uuid_v7":
+		next = func() (string, error) {
+			id, err := uuid.NewV7()
+			return id.String(), err
+		}
+		entropyBits = 74
+	case "ulid":
+		generator := &ulidGenerator{}
+		next = func() (string, error) { return generator.next(time.Now()) }
+		entropyBits = 80
+	case "nanoid":
+		next = func() (string, error) { return randomString(alphabet, length) }
+		entropyBits = float64(length) * math.Log2(float64(len(alphabet)))
+	default:
+		next = func() (string, error) {
+			buf, err := randomBytes(length)
+			if err != nil {
+				return "", err
+			}
+			switch kind {
+			case "hex":
+				return hex.EncodeToString(buf), nil
+			case "base64":
+				return base64.StdEncoding.EncodeToString(buf), nil
+			default:
+				return base64.RawURLEncoding.EncodeToString(buf), nil
+			}
+		}
+		entropyBits = float64(length) * 8
+	}
+
+	response := &GenerateResponse{Type: kind, Values: make([]string, 0, count), EntropyBits: int(entropyBits)}
+	for range count {
+		value, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", kind, err)
+		}
+		response.Values = append(response.Values, value)
+	}
+	return response, nil
+}
+
+// hash hashes the input with an algorithm
+func hash(kind string, args map[string]any) (*HashResponse, error) {
+	input, ok := args["input"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing required parameter 'input': the text to hash with %s", kind)
+	}
+	if len(input) > maxHashInput {
+		return nil, fmt.Errorf("input is %d bytes, more than the %d bytes that can be hashed", len(input), maxHashInput)
+	}
+	for _, name := range []string{"count", "length", "alphabet"} {
+		if _, ok := args[name]; ok {
+			return nil, fmt.Errorf("'%s' is not used when hashing with %s", name, kind)
+		}
+	}
+	if _, ok := args["cost"]; ok && kind != "bcrypt" {
+		return nil, fmt.Errorf("'cost' is only used with bcrypt")
+	}
+
+	response := &HashResponse{Type: kind, Encoding: "hex", InputBytes: len(input)}
+	switch kind {
+	case "sha256":
+		sum := sha256.Sum256([]byte(input))
+		response.Hash = hex.EncodeToString(sum[:])
+	case "sha512":
+		sum := sha512.Sum512([]byte(input))
+		response.Hash = hex.EncodeToString(sum[:])
+	case "bcrypt":
+		cost, err := wholeNumber(args, "cost", bcrypt.DefaultCost, minBcryptCost, maxBcryptCost)
+		if err != nil {
+			return nil, err
+		}
+		if len(input) > bcryptMaxInput {
+			return nil, fmt.Errorf("bcrypt only uses the first %d bytes of its input and rejects longer input, which is %d bytes", bcryptMaxInput, len(input))
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(input), cost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash with bcrypt: %w", err)
+		}
+		response.Hash = string(hashed)
+		response.Encoding = "bcrypt"
+	}
+	return response, nil
+}
+
+// wholeNumber reads an optional whole-number parameter, checking it is within range
+func wholeNumber(args map[string]any, name string, defaultValue, minValue, maxValue int) (int, error) {
+	raw, ok := args[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	value, ok := raw.(float64)
+	if !ok || value != math.Trunc(value) || value < float64(minValue) || value > float64(maxValue) {
+		return 0, fmt.Errorf("invalid '%s' parameter: must be a whole number from %d to %d", name, minValue, maxValue)
+	}
+	return int(value), nil
+}
+
+// parseAlphabet checks a nanoid alphabet is at least two distinct printable ASCII characters
+func parseAlphabet(raw any) (string, error) {
+	alphabet, ok := raw.(string)
+	if !ok || len(alphabet) < 2 {
+		return "", fmt.Errorf("invalid 'alphabet' parameter: must be a string of at least 2 characters")
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if r >= utf8.RuneSelf || r < ' ' || r == 0x7f {
+			return "", fmt.Errorf("invalid 'alphabet' parameter: must contain only printable ASCII characters, found %q", r)
+		}
+		if seen[r] {
+			return "", fmt.Errorf("invalid 'alphabet' parameter: character %q appears more than once, which would make it more likely than others", r)
+		}
+		seen[r] = true
+	}
+	return alphabet, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the generate_id tool
+func (g *GenerateIDTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Whenever a real identifier, token or secret is needed - database keys, fixtures, API keys, session secrets, passwords - or to hash a value for a config file or test. Identifiers written by a model look random but are not, and often repeat.",
+		WhenNotToUse: "Verifying passwords against existing hashes, or hashing files (use a checksum command). Secrets returned here pass through the conversation, so generate production secrets on the machine that uses them.",
+		CommonPatterns: []string{
+			"Database keys that sort by creation time: uuid_v7 or ulid",
+			"Short URL-safe IDs: nanoid with a shorter 'length'",
+			"Human-friendly codes: nanoid with 'alphabet' such as 23456789ABCDEFGHJKLMNPQRSTUVWXYZ",
+			"API keys and signing secrets: hex or base64url with length 32",
+			"Test fixtures: set 'count' to generate several values at once",
+			"Password hashes for config files: bcrypt with 'input'",
+		},
+		ParameterDetails: map[string]string{
+			"type":     "uuid_v4 and uuid_v7 are RFC 9562 UUIDs; ulid is a 26-character time-ordered ID; nanoid is a compact URL-safe ID; hex, base64 and base64url encode random bytes; sha256, sha512 and bcrypt hash 'input'.",
+			"count":    "Values to generate. ULIDs and UUIDv7s in one call are in increasing order.",
+			"length":   "For nanoid, the number of characters. For hex, base64 and base64url, the number of random bytes, so hex output is twice as long.",
+			"alphabet": "nanoid characters. Must be distinct printable ASCII. Each character is equally likely.",
+			"input":    fmt.Sprintf("Text to hash. bcrypt accepts at most %d bytes.", bcryptMaxInput),
+			"cost":     fmt.Sprintf("bcrypt work factor from %d to %d. Each step doubles the time to hash and to guess.", minBcryptCost, maxBcryptCost),
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Five time-ordered database keys",
+				Arguments:      map[string]any{"type": "uuid_v7", "count": 5},
+				ExpectedResult: "Five UUIDv7s in increasing order, with entropy_bits 74",
+			},
+			{
+				Description:    "A 256-bit API key",
+				Arguments:      map[string]any{"type": "base64url", "length": 32},
+				ExpectedResult: "A 43-character URL-safe string with entropy_bits 256",
+			},
+			{
+				Description:    "A short ID for a URL",
+				Arguments:      map[string]any{"type": "nanoid", "length": 12},
+				ExpectedResult: "A 12-character ID such as V1StGXR8_Z5j",
+			},
+			{
+				Description:    "Hash a password for a config file",
+				Arguments:      map[string]any{"type": "bcrypt", "input": "correct horse battery staple"},
+				ExpectedResult: `{"type": "bcrypt", "hash": "$2a$10$...", "encoding": "bcrypt", "input_bytes": 28}`,
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "bcrypt rejects the input",
+				Solution: fmt.Sprintf("bcrypt only uses the first %d bytes of input. Hash long input with sha256 or sha512 instead.", bcryptMaxInput),
+			},
+			{
+				Problem:  "hex output is twice the requested length",
+				Solution: "'length' is the number of random bytes for hex and base64, and each byte is two hex characters. Halve 'length' for a fixed number of hex characters.",
+			},
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package generateid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+const (
+	// nanoidAlphabet is the default nanoid alphabet, which is URL-safe
+	nanoidAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+	// crockfordAlphabet is the base32 alphabet ULIDs are encoded with
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// ulidGenerator generates ULIDs that sort in the order they were generated, incrementing the random part
+// when several are generated in the same millisecond
+type ulidGenerator struct {
+	lastTime uint64
+	last     [16]byte
+}
+
+// next returns a new ULID for the given time
+func (g *ulidGenerator) next(now time.Time) (string, error) {
+	ms := uint64(now.UnixMilli())
+	var id [16]byte
+	if ms == g.lastTime && g.lastTime != 0 {
+		id = g.last
+		// Increment the 80-bit random part, failing in the vanishingly unlikely case it overflows
+		carry := true
+		for i := 15; i >= 6 && carry; i-- {
+			id[i]++
+			carry = id[i] == 0
+		}
+		if carry {
+			return "", fmt.Errorf("too many ULIDs generated in one millisecond")
+		}
+	} else {
+		var timestamp [8]byte
+		binary.BigEndian.PutUint64(timestamp[:], ms)
+		copy(id[:6], timestamp[2:])
+		if _, err := rand.Read(id[6:]); err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+	}
+	g.lastTime = ms
+	g.last = id
+	return encodeULID(id), nil
+}
+
+// encodeULID encodes 128 bits as 26 characters of Crockford base32, most significant bits first
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+// randomString returns length characters chosen uniformly from alphabet. Random bytes are masked to the
+// smallest power of two covering the alphabet and values outside it are discarded, so no character is
+// more likely than another.
+func randomString(alphabet string, length int) (string, error) {
+	mask := 1<<bits.Len(uint(len(alphabet)-1)) - 1
+	out := make([]byte, 0, length)
+	buf := make([]byte, length*2)
+	for len(out) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		for _, b := range buf {
+			if index := int(b) & mask; index < len(alphabet) {
+				out = append(out, alphabet[index])
+				if len(out) == length {
+					break
+				}
+			}
+		}
+	}
+	return string(out), nil
+}
+
+// randomBytes returns n random bytes
+func randomBytes(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return buf, nil
+}
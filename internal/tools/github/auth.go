@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/google/go-github/v76/github"
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/tools"
 	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"golang.org/x/oauth2"
 )
@@ -18,8 +20,8 @@ func GetAuthConfig() (*AuthConfig, error) {
 	// Check environment variables for auth method preference
 	authMethod := os.Getenv("GITHUB_AUTH_METHOD")
 
-	// Check for GitHub token
-	token := os.Getenv("GITHUB_TOKEN")
+	// Check for GitHub token, falling back to the one stored with `mcp-devtools credentials set`
+	token := credentials.Lookup("GITHUB_TOKEN", credentials.GitHubToken)
 
 	// If SSH method is explicitly requested, validate SSH keys
 	if authMethod == "ssh" {
@@ -87,6 +89,8 @@ func NewGitHubClient(ctx context.Context, config *AuthConfig) (*github.Client, e
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: config.Token},
 		)
+		// Authenticated requests also go through the shared client, for its proxy, network policy and usage limits
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpclient.NewHTTPClientWithProxy(30*time.Second))
 		tc := oauth2.NewClient(ctx, ts)
 		return github.NewClient(tc), nil
 
@@ -288,3 +292,23 @@ func CreateFileNotFoundError(owner, repo, path, ref string) error {
 			return "main"
 		}())
 }
+
+// CheckPrerequisites reports how the tool will authenticate with GitHub
+func (t *GitHubTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	check := tools.PrerequisiteCheck{Name: "authentication"}
+	config, err := GetAuthConfig()
+	switch {
+	case err != nil:
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = err.Error()
+		check.Remediation = "Set GITHUB_SSH_PRIVATE_KEY_PATH to your SSH private key, or unset GITHUB_AUTH_METHOD to use GITHUB_TOKEN"
+	case config.Method == "none":
+		check.Status = tools.PrerequisiteWarning
+		check.Detail = "GITHUB_TOKEN is not set, so only public repositories can be used and API requests are limited to 60 an hour"
+		check.Remediation = "Set GITHUB_TOKEN to a personal access token, or store one with: mcp-devtools credentials set " + credentials.GitHubToken
+	default:
+		check.Status = tools.PrerequisiteOK
+		check.Detail = "using " + config.Method + " authentication"
+	}
+	return []tools.PrerequisiteCheck{check}
+}
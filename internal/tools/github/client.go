@@ -14,6 +14,8 @@ import (
 
 	"github.com/google/go-github/v76/github"
 	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/forge"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 )
@@ -26,6 +28,9 @@ const (
 	GitHubCoreAPIRateLimitEnvVar   = "GITHUB_CORE_API_RATE_LIMIT"
 	GitHubSearchAPIRateLimitEnvVar = "GITHUB_SEARCH_API_RATE_LIMIT"
 	GitHubMaxLinesEnvVar           = "GITHUB_MAX_LINES"
+	GitHubAllowWriteEnvVar         = "GITHUB_ALLOW_WRITE" // enables create_issue when set to true
+	DefaultMaxPatchLines           = forge.DefaultMaxPatchLines
+	maxCheckRunPages               = 10 // stop after 1,000 check runs for a single commit
 )
 
 // GitHubClient wraps the GitHub API client with additional functionality
@@ -283,6 +288,7 @@ func (gc *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string,
 		HeadLabel: pr.Head.GetLabel(),
 		HeadRepo:  headRepo,
 		HeadRef:   pr.Head.GetRef(),
+		HeadSHA:   pr.Head.GetSHA(),
 		BaseLabel: pr.Base.GetLabel(),
 		BaseRepo:  baseRepo,
 		BaseRef:   pr.Base.GetRef(),
@@ -526,7 +532,7 @@ func (gc *GitHubClient) CloneRepository(ctx context.Context, owner, repo, localP
 
 	// Check file access security using helper function
 	// Note: SafeFileWrite will handle file access checks internally
-	if err := security.CheckFileAccess(localPath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, localPath); err != nil {
 		return nil, err
 	}
 
@@ -650,6 +656,349 @@ func (gc *GitHubClient) GetWorkflowRun(ctx context.Context, owner, repo string,
 	return workflowRun, logs, nil
 }
 
+// SearchCode searches file contents across GitHub, or within one repository when owner is set.
+// GitHub only allows authenticated code searches.
+func (gc *GitHubClient) SearchCode(ctx context.Context, owner, repo, query string, page, perPage int) (*CodeSearchResult, error) {
+	if gc.authConfig.Method != "token" {
+		return nil, fmt.Errorf("code search requires authentication - set GITHUB_TOKEN")
+	}
+
+	// Apply search API rate limiting
+	if err := gc.waitForSearchAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("search API rate limit wait failed: %w", err)
+	}
+
+	searchQuery := query
+	if owner != "" {
+		searchQuery = fmt.Sprintf("repo:%s/%s %s", owner, repo, query)
+	}
+
+	opts := &github.SearchOptions{
+		TextMatch: true,
+		ListOptions: github.ListOptions{
+			Page:    page,
+			PerPage: perPage,
+		},
+	}
+
+	result, resp, err := gc.client.Search.Code(ctx, searchQuery, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search code: %w", err)
+	}
+
+	items := make([]CodeMatch, 0, len(result.CodeResults))
+	for _, code := range result.CodeResults {
+		match := CodeMatch{
+			Repository: code.GetRepository().GetFullName(),
+			Path:       code.GetPath(),
+			HTMLURL:    code.GetHTMLURL(),
+		}
+		for _, textMatch := range code.TextMatches {
+			if fragment := strings.TrimSpace(textMatch.GetFragment()); fragment != "" {
+				match.Fragments = append(match.Fragments, fragment)
+			}
+		}
+		items = append(items, match)
+	}
+
+	return &CodeSearchResult{
+		Query:             searchQuery,
+		TotalCount:        result.GetTotal(),
+		IncompleteResults: result.GetIncompleteResults(),
+		Items:             items,
+		Page:              pageOf(resp, page, perPage),
+	}, nil
+}
+
+// ListIssues lists a repository's issues, most recently updated first
+func (gc *GitHubClient) ListIssues(ctx context.Context, owner, repo, state string, labels []string, page, perPage int) (*IssueList, error) {
+	// Apply core API rate limiting
+	if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	opts := &github.IssueListByRepoOptions{
+		State:     state,
+		Labels:    labels,
+		Sort:      "updated",
+		Direction: "desc",
+		ListOptions: github.ListOptions{
+			Page:    page,
+			PerPage: perPage,
+		},
+	}
+
+	issues, resp, err := gc.client.Issues.ListByRepo(ctx, owner, repo, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	items := make([]IssueSummary, 0, len(issues))
+	for _, issue := range issues {
+		// The issues endpoint also returns pull requests
+		if issue.IsPullRequest() {
+			continue
+		}
+
+		summary := IssueSummary{
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			State:     issue.GetState(),
+			Login:     issue.GetUser().GetLogin(),
+			Comments:  issue.GetComments(),
+			HTMLURL:   issue.GetHTMLURL(),
+			UpdatedAt: issue.GetUpdatedAt().Format("2006-01-02T15:04:05Z"),
+		}
+		for _, label := range issue.Labels {
+			summary.Labels = append(summary.Labels, label.GetName())
+		}
+		items = append(items, summary)
+	}
+
+	return &IssueList{
+		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		State:      state,
+		Labels:     labels,
+		Items:      items,
+		Page:       pageOf(resp, page, perPage),
+	}, nil
+}
+
+// CreateIssue opens a new issue. It requires token authentication with permission to write issues.
+func (gc *GitHubClient) CreateIssue(ctx context.Context, owner, repo, title, body string, labels, assignees []string) (*CreatedIssue, error) {
+	if gc.authConfig.Method != "token" {
+		return nil, fmt.Errorf("creating issues requires GITHUB_TOKEN with permission to write issues")
+	}
+
+	// Apply core API rate limiting
+	if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	issueRequest := &github.IssueRequest{Title: github.Ptr(title)}
+	if body != "" {
+		issueRequest.Body = github.Ptr(body)
+	}
+	if len(labels) > 0 {
+		issueRequest.Labels = &labels
+	}
+	if len(assignees) > 0 {
+		issueRequest.Assignees = &assignees
+	}
+
+	issue, _, err := gc.client.Issues.Create(ctx, owner, repo, issueRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	created := &CreatedIssue{
+		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		Number:     issue.GetNumber(),
+		Title:      issue.GetTitle(),
+		HTMLURL:    issue.GetHTMLURL(),
+	}
+	for _, label := range issue.Labels {
+		created.Labels = append(created.Labels, label.GetName())
+	}
+	for _, assignee := range issue.Assignees {
+		created.Assignees = append(created.Assignees, assignee.GetLogin())
+	}
+
+	return created, nil
+}
+
+// GetPullRequestDiff gets a page of the files changed by a pull request with their patches.
+// Patches longer than maxPatchLines are truncated.
+func (gc *GitHubClient) GetPullRequestDiff(ctx context.Context, owner, repo string, number, page, perPage, maxPatchLines int) (*PullRequestDiff, error) {
+	// Apply core API rate limiting
+	if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	files, resp, err := gc.client.PullRequests.ListFiles(ctx, owner, repo, number, &github.ListOptions{
+		Page:    page,
+		PerPage: perPage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request files: %w", err)
+	}
+
+	diff := &PullRequestDiff{
+		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		Number:     number,
+		Files:      make([]PullRequestFile, 0, len(files)),
+		Page:       pageOf(resp, page, perPage),
+	}
+	for _, file := range files {
+		changed := PullRequestFile{
+			Filename:         file.GetFilename(),
+			PreviousFilename: file.GetPreviousFilename(),
+			Status:           file.GetStatus(),
+			Additions:        file.GetAdditions(),
+			Deletions:        file.GetDeletions(),
+			Patch:            file.GetPatch(),
+		}
+		if lines := strings.Split(changed.Patch, "\n"); len(lines) > maxPatchLines {
+			changed.Patch = strings.Join(lines[:maxPatchLines], "\n")
+			changed.PatchTruncated = true
+		}
+		diff.Files = append(diff.Files, changed)
+	}
+
+	return diff, nil
+}
+
+// GetReviewComments gets a page of inline review comments on a pull request.
+// Review verdicts and summaries are included with the first page.
+func (gc *GitHubClient) GetReviewComments(ctx context.Context, owner, repo string, number, page, perPage int) (*ReviewComments, error) {
+	result := &ReviewComments{
+		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		Number:     number,
+	}
+
+	if page == 1 {
+		// Apply core API rate limiting for reviews
+		if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+			return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+		}
+		reviews, _, err := gc.client.PullRequests.ListReviews(ctx, owner, repo, number, &github.ListOptions{PerPage: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reviews: %w", err)
+		}
+		for _, review := range reviews {
+			// Reviews without a verdict or summary only group inline comments
+			if review.GetState() == "COMMENTED" && strings.TrimSpace(review.GetBody()) == "" {
+				continue
+			}
+			result.Reviews = append(result.Reviews, Review{
+				Login:       review.GetUser().GetLogin(),
+				State:       review.GetState(),
+				Body:        review.GetBody(),
+				SubmittedAt: formatTimestamp(review.SubmittedAt),
+				HTMLURL:     review.GetHTMLURL(),
+			})
+		}
+	}
+
+	// Apply core API rate limiting for comments
+	if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+	comments, resp, err := gc.client.PullRequests.ListComments(ctx, owner, repo, number, &github.PullRequestListCommentsOptions{
+		Sort:      "created",
+		Direction: "asc",
+		ListOptions: github.ListOptions{
+			Page:    page,
+			PerPage: perPage,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review comments: %w", err)
+	}
+
+	result.Comments = make([]ReviewComment, 0, len(comments))
+	for _, comment := range comments {
+		reviewComment := ReviewComment{
+			ID:        comment.GetID(),
+			InReplyTo: comment.GetInReplyTo(),
+			Login:     comment.GetUser().GetLogin(),
+			Path:      comment.GetPath(),
+			Line:      comment.GetLine(),
+			Body:      comment.GetBody(),
+			HTMLURL:   comment.GetHTMLURL(),
+			CreatedAt: formatTimestamp(comment.CreatedAt),
+		}
+		// Comments on lines that have since changed no longer have a current line
+		if reviewComment.Line == 0 {
+			reviewComment.Line = comment.GetOriginalLine()
+			reviewComment.Outdated = true
+		}
+		result.Comments = append(result.Comments, reviewComment)
+	}
+	result.Page = pageOf(resp, page, perPage)
+
+	return result, nil
+}
+
+// GetCommitStatus gets the CI state of a commit from both check runs and commit statuses.
+// All checks are fetched so the overall state is accurate, then a page of them is returned.
+func (gc *GitHubClient) GetCommitStatus(ctx context.Context, owner, repo, ref string, page, perPage int) (*CommitStatus, error) {
+	// Apply core API rate limiting
+	if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	combined, _, err := gc.client.Repositories.GetCombinedStatus(ctx, owner, repo, ref, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit status: %w", err)
+	}
+
+	var checks []StatusCheck
+	for _, status := range combined.Statuses {
+		check := StatusCheck{
+			Name:        status.GetContext(),
+			Kind:        "status",
+			Status:      "completed",
+			Conclusion:  status.GetState(),
+			Description: status.GetDescription(),
+			URL:         status.GetTargetURL(),
+		}
+		if check.Conclusion == "pending" {
+			check.Status = "pending"
+			check.Conclusion = ""
+		}
+		checks = append(checks, check)
+	}
+
+	sha := combined.GetSHA()
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for range maxCheckRunPages {
+		// Apply core API rate limiting for each page of check runs
+		if err := gc.waitForCoreAPIRateLimit(ctx); err != nil {
+			return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+		}
+		runs, resp, err := gc.client.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list check runs: %w", err)
+		}
+		for _, run := range runs.CheckRuns {
+			if sha == "" {
+				sha = run.GetHeadSHA()
+			}
+			checks = append(checks, StatusCheck{
+				Name:        run.GetName(),
+				Kind:        "check",
+				Status:      run.GetStatus(),
+				Conclusion:  run.GetConclusion(),
+				Description: run.GetOutput().GetTitle(),
+				URL:         run.GetHTMLURL(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	result := forge.SummariseChecks(checks)
+	result.Repository = fmt.Sprintf("%s/%s", owner, repo)
+	result.Ref = ref
+	result.SHA = sha
+	result.Checks, result.Page = forge.Paginate(result.Checks, page, perPage)
+
+	return result, nil
+}
+
+// pageOf reads pagination details from a GitHub API response
+func pageOf(resp *github.Response, page, perPage int) Page {
+	result := Page{Page: page, PerPage: perPage}
+	if resp != nil {
+		result.NextPage = resp.NextPage
+		result.LastPage = resp.LastPage
+	}
+	return result
+}
+
 // ExtractWorkflowRunID extracts workflow run ID from GitHub Actions URL
 func ExtractWorkflowRunID(url string) (int64, error) {
 	if len(url) > 19 && url[:19] == "https://github.com/" {
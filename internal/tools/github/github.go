@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 
@@ -28,11 +29,13 @@ func (t *GitHubTool) Definition() mcp.Tool {
 		"github",
 		mcp.WithDescription(`Access GitHub repositories, issues, PRs and workflows.
 
-Repository accepts: owner/repo, GitHub URLs, or full issue/PR/workflow URLs.`),
+Repository accepts: owner/repo, GitHub URLs, or full issue/PR/workflow URLs.
+search_code, list_issues, get_pull_request_diff, get_review_comments and get_commit_status return markdown and page with options.page and options.limit.
+create_issue is only available when the server sets GITHUB_ALLOW_WRITE=true.`),
 		mcp.WithString("function",
 			mcp.Required(),
 			mcp.Description("Function to execute"),
-			mcp.Enum("search_repositories", "search_issues", "search_pull_requests", "get_issue", "get_pull_request", "get_file_contents", "list_directory", "clone_repository", "get_workflow_run"),
+			mcp.Enum("search_repositories", "search_issues", "search_pull_requests", "search_code", "list_issues", "create_issue", "get_issue", "get_pull_request", "get_pull_request_diff", "get_review_comments", "get_commit_status", "get_file_contents", "list_directory", "clone_repository", "get_workflow_run"),
 		),
 		mcp.WithString("repository",
 			mcp.Description("Identifier: owner/repo, GitHub URL, or full URL for specific issue/PR/workflow"),
@@ -46,9 +49,14 @@ Repository accepts: owner/repo, GitHub URLs, or full issue/PR/workflow URLs.`),
 				},
 				"limit": map[string]any{
 					"type":        "number",
-					"description": "Maximum number of results (for search functions, 30)",
+					"description": "Maximum number of results, or results per page for paginated functions (default 30, max 100)",
 					"default":     30,
 				},
+				"page": map[string]any{
+					"type":        "number",
+					"description": "Page of results for search_code, list_issues, get_pull_request_diff, get_review_comments and get_commit_status (default: 1)",
+					"default":     1,
+				},
 				"number": map[string]any{
 					"type":        "number",
 					"description": "Issue or PR number (not needed if using full URL)",
@@ -85,7 +93,29 @@ Repository accepts: owner/repo, GitHub URLs, or full issue/PR/workflow URLs.`),
 				},
 				"ref": map[string]any{
 					"type":        "string",
-					"description": "Git reference - branch, tag, or commit SHA",
+					"description": "Git reference - branch, tag, or commit SHA (for get_commit_status, defaults to the PR head when a PR is given)",
+				},
+				"title": map[string]any{
+					"type":        "string",
+					"description": "Issue title (for create_issue)",
+				},
+				"body": map[string]any{
+					"type":        "string",
+					"description": "Issue body in markdown (for create_issue)",
+				},
+				"labels": map[string]any{
+					"type":        "array",
+					"description": "Labels to filter by (list_issues) or apply (create_issue)",
+					"items": map[string]any{
+						"type": "string",
+					},
+				},
+				"assignees": map[string]any{
+					"type":        "array",
+					"description": "Usernames to assign (for create_issue)",
+					"items": map[string]any{
+						"type": "string",
+					},
 				},
 				"line_start": map[string]any{
 					"type":        "number",
@@ -127,10 +157,22 @@ func (t *GitHubTool) Execute(ctx context.Context, logger *logrus.Logger, cache *
 		return t.handleSearchIssues(ctx, client, request)
 	case "search_pull_requests":
 		return t.handleSearchPullRequests(ctx, client, request)
+	case "search_code":
+		return t.handleSearchCode(ctx, client, request)
+	case "list_issues":
+		return t.handleListIssues(ctx, client, request)
+	case "create_issue":
+		return t.handleCreateIssue(ctx, client, request)
 	case "get_issue":
 		return t.handleGetIssue(ctx, client, request)
 	case "get_pull_request":
 		return t.handleGetPullRequest(ctx, client, request)
+	case "get_pull_request_diff":
+		return t.handleGetPullRequestDiff(ctx, client, request)
+	case "get_review_comments":
+		return t.handleGetReviewComments(ctx, client, request)
+	case "get_commit_status":
+		return t.handleGetCommitStatus(ctx, client, request)
 	case "get_file_contents":
 		return t.handleGetFileContents(ctx, client, request)
 	case "list_directory":
@@ -753,6 +795,273 @@ func (t *GitHubTool) handleGetWorkflowRun(ctx context.Context, client *GitHubCli
 	return mcp.NewToolResultText(jsonString), nil
 }
 
+// handleSearchCode handles code search across GitHub or within a repository
+func (t *GitHubTool) handleSearchCode(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	query, ok := request.Options["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query parameter is required for search_code")
+	}
+
+	var owner, repo string
+	if request.Repository != "" {
+		var err error
+		owner, repo, err = ValidateRepository(request.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository: %w", err)
+		}
+	}
+
+	page, perPage, err := parsePagination(request.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.SearchCode(ctx, owner, repo, query, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search code: %w", err)
+	}
+
+	return t.markdownResult(result.Markdown(), "code_search")
+}
+
+// handleListIssues handles listing a repository's issues
+func (t *GitHubTool) handleListIssues(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	if request.Repository == "" {
+		return nil, fmt.Errorf("repository parameter is required for list_issues")
+	}
+
+	owner, repo, err := ValidateRepository(request.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository: %w", err)
+	}
+
+	state := "open"
+	if ic, ok := request.Options["include_closed"].(bool); ok && ic {
+		state = "all"
+	}
+
+	page, perPage, err := parsePagination(request.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.ListIssues(ctx, owner, repo, state, stringOptions(request.Options["labels"]), page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	return t.markdownResult(result.Markdown(), "issue_list")
+}
+
+// handleCreateIssue handles creating an issue when writes are enabled
+func (t *GitHubTool) handleCreateIssue(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	if !writesAllowed() {
+		return nil, fmt.Errorf("create_issue is disabled - the github tool is read-only unless the server sets %s=true", GitHubAllowWriteEnvVar)
+	}
+	if request.Repository == "" {
+		return nil, fmt.Errorf("repository parameter is required for create_issue")
+	}
+
+	owner, repo, err := ValidateRepository(request.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository: %w", err)
+	}
+
+	title, _ := request.Options["title"].(string)
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("options.title is required for create_issue")
+	}
+	body, _ := request.Options["body"].(string)
+
+	result, err := client.CreateIssue(ctx, owner, repo, title, body, stringOptions(request.Options["labels"]), stringOptions(request.Options["assignees"]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	return mcp.NewToolResultText(result.Markdown()), nil
+}
+
+// handleGetPullRequestDiff handles getting a pull request's changed files and patches
+func (t *GitHubTool) handleGetPullRequestDiff(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	owner, repo, prNumber, err := parsePullRequestTarget(request)
+	if err != nil {
+		return nil, err
+	}
+
+	page, perPage, err := parsePagination(request.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPatchLines := GetEnvInt(GitHubMaxLinesEnvVar, DefaultMaxPatchLines)
+	result, err := client.GetPullRequestDiff(ctx, owner, repo, prNumber, page, perPage, min(maxPatchLines, DefaultMaxPatchLines))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	return t.markdownResult(result.Markdown(), "pull_request_diff")
+}
+
+// handleGetReviewComments handles getting a pull request's reviews and inline review comments
+func (t *GitHubTool) handleGetReviewComments(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	owner, repo, prNumber, err := parsePullRequestTarget(request)
+	if err != nil {
+		return nil, err
+	}
+
+	page, perPage, err := parsePagination(request.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetReviewComments(ctx, owner, repo, prNumber, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review comments: %w", err)
+	}
+
+	return t.markdownResult(result.Markdown(), "review_comments")
+}
+
+// handleGetCommitStatus handles getting the CI state of a commit, branch, tag or pull request head
+func (t *GitHubTool) handleGetCommitStatus(ctx context.Context, client *GitHubClient, request *GitHubRequest) (*mcp.CallToolResult, error) {
+	if request.Repository == "" {
+		return nil, fmt.Errorf("repository parameter is required for get_commit_status")
+	}
+
+	ref, _ := request.Options["ref"].(string)
+	ref = strings.TrimSpace(ref)
+
+	var owner, repo string
+	var err error
+	_, hasNumber := request.Options["number"].(float64)
+	if ref == "" && (strings.Contains(request.Repository, "/pull/") || hasNumber) {
+		// Check the head commit of the pull request
+		var prNumber int
+		owner, repo, prNumber, err = parsePullRequestTarget(request)
+		if err != nil {
+			return nil, err
+		}
+		pullRequest, _, err := client.GetPullRequest(ctx, owner, repo, prNumber, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request: %w", err)
+		}
+		ref = pullRequest.HeadSHA
+	} else {
+		owner, repo, err = ValidateRepository(request.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository: %w", err)
+		}
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("options.ref is required for get_commit_status (commit SHA, branch or tag), or pass a pull request URL or options.number")
+	}
+
+	page, perPage, err := parsePagination(request.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetCommitStatus(ctx, owner, repo, ref, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit status: %w", err)
+	}
+
+	return t.markdownResult(result.Markdown(), "commit_status")
+}
+
+// parsePullRequestTarget reads the repository and pull request number from a PR URL or options.number
+func parsePullRequestTarget(request *GitHubRequest) (owner, repo string, number int, err error) {
+	if request.Repository == "" {
+		return "", "", 0, fmt.Errorf("repository parameter is required for %s", request.Function)
+	}
+
+	owner, repo, err = ValidateRepository(request.Repository)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid repository: %w", err)
+	}
+
+	if strings.Contains(request.Repository, "/pull/") {
+		number, err = ExtractPullRequestNumber(request.Repository)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to extract pull request number: %w", err)
+		}
+		return owner, repo, number, nil
+	}
+
+	num, ok := request.Options["number"].(float64)
+	if !ok {
+		return "", "", 0, fmt.Errorf("pull request number is required (either in URL or options.number)")
+	}
+	return owner, repo, int(num), nil
+}
+
+// parsePagination reads options.page and options.limit, capping the page size at GitHub's maximum of 100
+func parsePagination(options map[string]any) (page, perPage int, err error) {
+	page, perPage = 1, 30
+	if p, ok := options["page"].(float64); ok {
+		if p < 1 {
+			return 0, 0, fmt.Errorf("options.page must be 1 or greater")
+		}
+		page = int(p)
+	}
+	if l, ok := options["limit"].(float64); ok && l >= 1 {
+		perPage = min(int(l), 100)
+	}
+	return page, perPage, nil
+}
+
+// stringOptions reads a list option given as an array or a comma-separated string
+func stringOptions(value any) []string {
+	var values []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for s := range strings.SplitSeq(v, ",") {
+			if strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+	}
+	return values
+}
+
+// writesAllowed reports whether the server permits functions that modify GitHub data
+func writesAllowed() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(GitHubAllowWriteEnvVar)))
+	return value == "true" || value == "1"
+}
+
+// markdownResult runs a markdown summary through security analysis before returning it
+func (t *GitHubTool) markdownResult(markdown, contentType string) (*mcp.CallToolResult, error) {
+	source := security.SourceContext{
+		Tool:        "github",
+		Domain:      "github.com",
+		ContentType: contentType,
+	}
+	if result, err := security.AnalyseContent(markdown, source); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			markdown = security.FormatSecurityWarningPrefix(result) + markdown
+		}
+	}
+
+	return mcp.NewToolResultText(markdown), nil
+}
+
 // convertToJSON converts the response to JSON string for better formatting
 func (t *GitHubTool) convertToJSON(response any) (string, error) {
 	jsonBytes, err := json.MarshalIndent(response, "", "  ")
@@ -854,6 +1163,49 @@ func (t *GitHubTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 				},
 				ExpectedResult: "Returns workflow run details and complete execution logs for run ID 123456789",
 			},
+			{
+				Description: "Find where a function is defined across a repository",
+				Arguments: map[string]any{
+					"function":   "search_code",
+					"repository": "golang/go",
+					"options": map[string]any{
+						"query": "func ParseDuration language:go",
+						"limit": 10,
+					},
+				},
+				ExpectedResult: "Markdown list of matching files with links and the matching code fragments, plus the options.page value for more results",
+			},
+			{
+				Description: "Review a pull request's changes and the feedback on it",
+				Arguments: map[string]any{
+					"function":   "get_pull_request_diff",
+					"repository": "https://github.com/owner/repo/pull/42",
+					"options": map[string]any{
+						"limit": 20,
+					},
+				},
+				ExpectedResult: "Markdown diff blocks for the first 20 changed files. Follow up with get_review_comments on the same URL for review verdicts and inline comments",
+			},
+			{
+				Description: "Check whether CI passed for a pull request",
+				Arguments: map[string]any{
+					"function":   "get_commit_status",
+					"repository": "https://github.com/owner/repo/pull/42",
+				},
+				ExpectedResult: "Overall state for the PR's head commit with failing checks listed first and links to their details",
+			},
+			{
+				Description: "List open bugs",
+				Arguments: map[string]any{
+					"function":   "list_issues",
+					"repository": "owner/repo",
+					"options": map[string]any{
+						"labels": []string{"bug"},
+						"page":   2,
+					},
+				},
+				ExpectedResult: "Markdown table of the second page of open issues labelled bug, most recently updated first",
+			},
 		},
 		CommonPatterns: []string{
 			"ALWAYS start with list_directory to explore repository structure before requesting specific files",
@@ -863,6 +1215,8 @@ func (t *GitHubTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"Use search functions to discover repositories, issues, and PRs before getting specific details",
 			"Search with targeted queries to reduce result noise (e.g., 'is:open label:bug')",
 			"When file requests fail, check the error suggestions and use list_directory to verify paths",
+			"Paginated functions end with the options.page value to request next - keep the same limit between pages",
+			"For PR review: get_pull_request, then get_pull_request_diff, get_review_comments and get_commit_status",
 		},
 		Troubleshooting: []tools.TroubleshootingTip{
 			{
@@ -893,13 +1247,25 @@ func (t *GitHubTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 				Problem:  "Workflow run access denied",
 				Solution: "Workflow runs may require higher permissions than basic repository access. Ensure your GitHub token has 'actions:read' scope.",
 			},
+			{
+				Problem:  "search_code fails with an authentication error",
+				Solution: "GitHub only allows code search for authenticated requests. Set GITHUB_TOKEN in the server environment.",
+			},
+			{
+				Problem:  "create_issue is disabled",
+				Solution: "The tool is read-only by default. Set GITHUB_ALLOW_WRITE=true and use a GITHUB_TOKEN with permission to write issues.",
+			},
+			{
+				Problem:  "A file in get_pull_request_diff has no diff",
+				Solution: "GitHub omits patches for binary files and very large diffs. Use get_file_contents with the PR's head ref to read the file instead.",
+			},
 		},
 		ParameterDetails: map[string]string{
 			"function":   "The GitHub operation to perform. Use list_directory first to explore, then get_file_contents for specific files. Each function has different requirements - see examples.",
 			"repository": "Repository identifier in 'owner/repo' format, full GitHub URLs, or URLs with issue/PR/workflow IDs. The tool automatically extracts relevant information.",
-			"options":    "Function-specific parameters. For list_directory: path (optional), ref (optional). For get_file_contents: paths (required array), ref (optional), line_start (optional, only needed when file is truncated - use value from truncation message). Always check examples for each function.",
+			"options":    "Function-specific parameters. For list_directory: path (optional), ref (optional). For get_file_contents: paths (required array), ref (optional), line_start (optional, only needed when file is truncated - use value from truncation message). For create_issue: title (required), body, labels, assignees. For get_commit_status: ref, or a PR URL/number to check the PR's head commit. search_code, list_issues, get_pull_request_diff, get_review_comments and get_commit_status accept page and limit. Always check examples for each function.",
 		},
 		WhenToUse:    "Use for GitHub repository exploration, file content examination with graceful error handling, repository structure analysis, issue tracking, PR review, and CI/CD debugging. Start with list_directory to understand repository layout.",
-		WhenNotToUse: "Don't use for non-GitHub repositories, private repositories without proper authentication, or when you need to modify GitHub data (this tool is read-only). Use Git commands for actual repository cloning and manipulation.",
+		WhenNotToUse: "Don't use for non-GitHub repositories, private repositories without proper authentication, or when you need to modify GitHub data beyond creating issues (create_issue is the only write and must be enabled by the server). Use Git commands for actual repository cloning and manipulation.",
 	}
 }
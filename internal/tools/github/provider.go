@@ -0,0 +1,209 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v76/github"
+	"github.com/sammcj/mcp-devtools/internal/tools/forge"
+	"github.com/sirupsen/logrus"
+)
+
+// init makes github.com available to the forge tool
+func init() {
+	forge.RegisterProvider(forge.KindGitHub, newForgeProvider)
+}
+
+// forgeProvider adapts GitHubClient to the forge.Provider interface
+type forgeProvider struct {
+	client *GitHubClient
+}
+
+// newForgeProvider creates a provider for github.com using the github tool's authentication
+func newForgeProvider(ctx context.Context, host forge.Host, logger *logrus.Logger) (forge.Provider, error) {
+	if host.Name != "github.com" {
+		return nil, fmt.Errorf("GitHub Enterprise hosts are not supported yet (%s)", host.Name)
+	}
+	client, err := NewGitHubClientWrapper(ctx, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return &forgeProvider{client: client}, nil
+}
+
+// SearchCode searches file contents within the repository
+func (p *forgeProvider) SearchCode(ctx context.Context, repo forge.Repository, query string, page, perPage int) (*forge.CodeSearchResult, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.SearchCode(ctx, owner, name, query, page, perPage)
+}
+
+// ListIssues lists the repository's issues
+func (p *forgeProvider) ListIssues(ctx context.Context, repo forge.Repository, state string, labels []string, page, perPage int) (*forge.IssueList, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.ListIssues(ctx, owner, name, state, labels, page, perPage)
+}
+
+// GetIssue gets an issue with optional comments
+func (p *forgeProvider) GetIssue(ctx context.Context, repo forge.Repository, number int, includeComments bool) (*forge.Issue, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.client.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	issue, _, err := p.client.client.Issues.Get(ctx, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	result := &forge.Issue{
+		Repository: repo.String(),
+		Number:     issue.GetNumber(),
+		Title:      issue.GetTitle(),
+		State:      issue.GetState(),
+		Author:     issue.GetUser().GetLogin(),
+		Body:       issue.GetBody(),
+		URL:        issue.GetHTMLURL(),
+		CreatedAt:  formatTimestamp(issue.CreatedAt),
+		UpdatedAt:  formatTimestamp(issue.UpdatedAt),
+	}
+	for _, label := range issue.Labels {
+		result.Labels = append(result.Labels, label.GetName())
+	}
+	if includeComments && issue.GetComments() > 0 {
+		if result.Comments, err = p.conversation(ctx, owner, name, number); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// CreateIssue opens a new issue
+func (p *forgeProvider) CreateIssue(ctx context.Context, repo forge.Repository, request forge.IssueRequest) (*forge.CreatedIssue, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.CreateIssue(ctx, owner, name, request.Title, request.Body, request.Labels, request.Assignees)
+}
+
+// GetPullRequest gets a pull request with optional conversation comments
+func (p *forgeProvider) GetPullRequest(ctx context.Context, repo forge.Repository, number int, includeComments bool) (*forge.PullRequest, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.client.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	pr, _, err := p.client.client.PullRequests.Get(ctx, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	state := pr.GetState()
+	if pr.GetMerged() {
+		state = "merged"
+	}
+	result := &forge.PullRequest{
+		Repository:   repo.String(),
+		Number:       pr.GetNumber(),
+		Title:        pr.GetTitle(),
+		State:        state,
+		Draft:        pr.GetDraft(),
+		Author:       pr.GetUser().GetLogin(),
+		Body:         pr.GetBody(),
+		SourceBranch: pr.GetHead().GetLabel(),
+		TargetBranch: pr.GetBase().GetRef(),
+		HeadSHA:      pr.GetHead().GetSHA(),
+		URL:          pr.GetHTMLURL(),
+		CreatedAt:    formatTimestamp(pr.CreatedAt),
+		UpdatedAt:    formatTimestamp(pr.UpdatedAt),
+	}
+	if includeComments && pr.GetComments() > 0 {
+		if result.Comments, err = p.conversation(ctx, owner, name, number); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// GetPullRequestDiff gets a page of changed files with their patches
+func (p *forgeProvider) GetPullRequestDiff(ctx context.Context, repo forge.Repository, number, page, perPage int) (*forge.PullRequestDiff, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.GetPullRequestDiff(ctx, owner, name, number, page, perPage, DefaultMaxPatchLines)
+}
+
+// GetReviewComments gets reviews and a page of inline review comments
+func (p *forgeProvider) GetReviewComments(ctx context.Context, repo forge.Repository, number, page, perPage int) (*forge.ReviewComments, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.GetReviewComments(ctx, owner, name, number, page, perPage)
+}
+
+// GetCommitStatus gets the combined statuses and check runs for a ref
+func (p *forgeProvider) GetCommitStatus(ctx context.Context, repo forge.Repository, ref string, page, perPage int) (*forge.CommitStatus, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.GetCommitStatus(ctx, owner, name, ref, page, perPage)
+}
+
+// GetFileContents gets the decoded contents of a file
+func (p *forgeProvider) GetFileContents(ctx context.Context, repo forge.Repository, path, ref string) (string, error) {
+	owner, name, err := ValidateRepository(repo.Path)
+	if err != nil {
+		return "", err
+	}
+	if err := p.client.waitForCoreAPIRateLimit(ctx); err != nil {
+		return "", fmt.Errorf("core API rate limit wait failed: %w", err)
+	}
+
+	file, _, _, err := p.client.client.Repositories.GetContents(ctx, owner, name, CleanPath(path), &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	return file.GetContent()
+}
+
+// conversation gets the first 100 conversation comments on an issue or pull request
+func (p *forgeProvider) conversation(ctx context.Context, owner, repo string, number int) ([]forge.Comment, error) {
+	if err := p.client.waitForCoreAPIRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("core API rate limit wait failed for comments: %w", err)
+	}
+	comments, _, err := p.client.client.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	result := make([]forge.Comment, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, forge.Comment{
+			Author:    comment.GetUser().GetLogin(),
+			Body:      comment.GetBody(),
+			CreatedAt: formatTimestamp(comment.CreatedAt),
+			URL:       comment.GetHTMLURL(),
+		})
+	}
+	return result, nil
+}
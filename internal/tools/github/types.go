@@ -1,6 +1,6 @@
 package github
 
-// No imports needed for types
+import "github.com/sammcj/mcp-devtools/internal/tools/forge"
 
 // GitHubRequest represents the unified request structure for all GitHub operations
 type GitHubRequest struct {
@@ -220,6 +220,7 @@ type FilteredPullRequestDetails struct {
 	HeadLabel string `json:"head_label"` // head.label (e.g., "user:branch")
 	HeadRepo  string `json:"head_repo"`  // head repository full name (e.g., "user/repo")
 	HeadRef   string `json:"head_ref"`   // head branch/ref name (e.g., "branch")
+	HeadSHA   string `json:"head_sha"`   // head commit SHA
 	BaseLabel string `json:"base_label"` // base.label (e.g., "owner:main")
 	BaseRepo  string `json:"base_repo"`  // base repository full name (e.g., "owner/repo")
 	BaseRef   string `json:"base_ref"`   // base branch/ref name (e.g., "main")
@@ -255,3 +256,20 @@ type DirectoryListing struct {
 	Path  string          `json:"path"`
 	Items []DirectoryItem `json:"items"`
 }
+
+// Result types shared with the other forges are defined in the forge package
+type (
+	Page             = forge.Page
+	CodeMatch        = forge.CodeMatch
+	CodeSearchResult = forge.CodeSearchResult
+	IssueSummary     = forge.IssueSummary
+	IssueList        = forge.IssueList
+	CreatedIssue     = forge.CreatedIssue
+	PullRequestFile  = forge.PullRequestFile
+	PullRequestDiff  = forge.PullRequestDiff
+	Review           = forge.Review
+	ReviewComment    = forge.ReviewComment
+	ReviewComments   = forge.ReviewComments
+	StatusCheck      = forge.StatusCheck
+	CommitStatus     = forge.CommitStatus
+)
@@ -0,0 +1,276 @@
+package imagetool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// exifTagNames maps the EXIF tags we surface to readable names, by IFD
+var exifTagNames = map[string]map[uint16]string{
+	"ifd0": {
+		0x010E: "ImageDescription",
+		0x010F: "Make",
+		0x0110: "Model",
+		0x0112: "Orientation",
+		0x011A: "XResolution",
+		0x011B: "YResolution",
+		0x0131: "Software",
+		0x0132: "DateTime",
+		0x013B: "Artist",
+		0x8298: "Copyright",
+	},
+	"exif": {
+		0x829A: "ExposureTime",
+		0x829D: "FNumber",
+		0x8827: "ISOSpeedRatings",
+		0x9003: "DateTimeOriginal",
+		0x9004: "DateTimeDigitized",
+		0x9010: "OffsetTime",
+		0x9209: "Flash",
+		0x920A: "FocalLength",
+		0xA002: "PixelXDimension",
+		0xA003: "PixelYDimension",
+		0xA405: "FocalLengthIn35mmFilm",
+		0xA420: "ImageUniqueID",
+		0xA431: "BodySerialNumber",
+		0xA433: "LensMake",
+		0xA434: "LensModel",
+	},
+	"gps": {
+		0x0001: "GPSLatitudeRef",
+		0x0002: "GPSLatitude",
+		0x0003: "GPSLongitudeRef",
+		0x0004: "GPSLongitude",
+		0x0005: "GPSAltitudeRef",
+		0x0006: "GPSAltitude",
+		0x001D: "GPSDateStamp",
+	},
+}
+
+const (
+	exifIFDPointer = 0x8769
+	gpsIFDPointer  = 0x8825
+	maxIFDEntries  = 1000
+)
+
+// exifData holds parsed EXIF tags plus values derived from them
+type exifData struct {
+	Tags        map[string]any
+	Orientation int
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// extractEXIF locates the raw EXIF (TIFF) block in a JPEG, PNG or WebP file
+func extractEXIF(data []byte, format string) []byte {
+	switch format {
+	case "jpeg":
+		var found []byte
+		_ = walkJPEGSegments(data, func(marker byte, payload []byte) bool {
+			if marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+				found = payload[6:]
+				return false
+			}
+			return true
+		})
+		return found
+	case "png":
+		var found []byte
+		_ = walkPNGChunks(data, func(chunkType string, payload []byte) bool {
+			if chunkType == "eXIf" {
+				found = payload
+				return false
+			}
+			return true
+		})
+		return found
+	case "webp":
+		var found []byte
+		_ = walkWebPChunks(data, func(fourCC string, payload []byte) bool {
+			if fourCC == "EXIF" {
+				found = bytes.TrimPrefix(payload, []byte("Exif\x00\x00"))
+				return false
+			}
+			return true
+		})
+		return found
+	}
+	return nil
+}
+
+// parseEXIF decodes the TIFF structure of an EXIF block
+func parseEXIF(raw []byte) (*exifData, error) {
+	if len(raw) < 8 {
+		return nil, fmt.Errorf("EXIF block too short")
+	}
+
+	var order binary.ByteOrder
+	switch string(raw[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid EXIF byte order marker")
+	}
+	if order.Uint16(raw[2:4]) != 42 {
+		return nil, fmt.Errorf("invalid EXIF TIFF header")
+	}
+
+	result := &exifData{Tags: make(map[string]any), Orientation: 1}
+	p := &tiffParser{data: raw, order: order, visited: make(map[uint32]bool)}
+
+	pointers := p.readIFD(order.Uint32(raw[4:8]), "ifd0", result.Tags)
+	if offset, ok := pointers[exifIFDPointer]; ok {
+		p.readIFD(offset, "exif", result.Tags)
+	}
+	if offset, ok := pointers[gpsIFDPointer]; ok {
+		p.readIFD(offset, "gps", result.Tags)
+	}
+
+	if o, ok := result.Tags["Orientation"].(uint32); ok && o >= 1 && o <= 8 {
+		result.Orientation = int(o)
+	}
+	result.Latitude = gpsCoordinate(result.Tags["GPSLatitude"], result.Tags["GPSLatitudeRef"], "S")
+	result.Longitude = gpsCoordinate(result.Tags["GPSLongitude"], result.Tags["GPSLongitudeRef"], "W")
+
+	return result, nil
+}
+
+// tiffParser reads IFD entries whilst guarding against malformed offsets and loops
+type tiffParser struct {
+	data    []byte
+	order   binary.ByteOrder
+	visited map[uint32]bool
+}
+
+// readIFD reads the named tags from an IFD into tags and returns any sub-IFD pointers
+func (p *tiffParser) readIFD(offset uint32, ifd string, tags map[string]any) map[uint16]uint32 {
+	pointers := make(map[uint16]uint32)
+	if p.visited[offset] || int(offset)+2 > len(p.data) {
+		return pointers
+	}
+	p.visited[offset] = true
+
+	count := int(p.order.Uint16(p.data[offset:]))
+	if count > maxIFDEntries {
+		return pointers
+	}
+	names := exifTagNames[ifd]
+
+	for i := range count {
+		entry := int(offset) + 2 + i*12
+		if entry+12 > len(p.data) {
+			break
+		}
+		tag := p.order.Uint16(p.data[entry:])
+		typ := p.order.Uint16(p.data[entry+2:])
+		n := p.order.Uint32(p.data[entry+4:])
+
+		if ifd == "ifd0" && (tag == exifIFDPointer || tag == gpsIFDPointer) {
+			pointers[tag] = p.order.Uint32(p.data[entry+8:])
+			continue
+		}
+		name, ok := names[tag]
+		if !ok {
+			continue
+		}
+		if value, ok := p.readValue(entry, typ, n); ok {
+			tags[name] = value
+		}
+	}
+	return pointers
+}
+
+// readValue decodes an IFD entry's value, following the offset for values larger than 4 bytes
+func (p *tiffParser) readValue(entry int, typ uint16, count uint32) (any, bool) {
+	sizes := map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+	size, ok := sizes[typ]
+	if !ok || count == 0 || count > 1<<16 {
+		return nil, false
+	}
+	total := size * int(count)
+	start := entry + 8
+	if total > 4 {
+		start = int(p.order.Uint32(p.data[entry+8:]))
+	}
+	if start < 0 || start+total > len(p.data) {
+		return nil, false
+	}
+	raw := p.data[start : start+total]
+
+	switch typ {
+	case 2: // ASCII
+		return strings.TrimSpace(strings.TrimRight(string(raw), "\x00")), true
+	case 7: // UNDEFINED
+		if isPrintable(raw) {
+			return strings.TrimRight(string(raw), "\x00"), true
+		}
+		return fmt.Sprintf("%d bytes", len(raw)), true
+	}
+
+	values := make([]any, 0, count)
+	for i := range int(count) {
+		b := raw[i*size:]
+		switch typ {
+		case 1:
+			values = append(values, uint32(b[0]))
+		case 3:
+			values = append(values, uint32(p.order.Uint16(b)))
+		case 4:
+			values = append(values, p.order.Uint32(b))
+		case 9:
+			values = append(values, int32(p.order.Uint32(b)))
+		case 5:
+			values = append(values, rational(float64(p.order.Uint32(b)), float64(p.order.Uint32(b[4:]))))
+		case 10:
+			values = append(values, rational(float64(int32(p.order.Uint32(b))), float64(int32(p.order.Uint32(b[4:])))))
+		}
+	}
+	if len(values) == 1 {
+		return values[0], true
+	}
+	return values, true
+}
+
+// rational converts a numerator/denominator pair, rounding to a readable precision
+func rational(num, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return math.Round(num/den*1e6) / 1e6
+}
+
+// gpsCoordinate converts degrees/minutes/seconds to signed decimal degrees
+func gpsCoordinate(value, ref any, negativeRef string) *float64 {
+	parts, ok := value.([]any)
+	if !ok || len(parts) != 3 {
+		return nil
+	}
+	var dms [3]float64
+	for i, part := range parts {
+		f, ok := part.(float64)
+		if !ok {
+			return nil
+		}
+		dms[i] = f
+	}
+	decimal := dms[0] + dms[1]/60 + dms[2]/3600
+	if r, ok := ref.(string); ok && strings.EqualFold(r, negativeRef) {
+		decimal = -decimal
+	}
+	decimal = math.Round(decimal*1e6) / 1e6
+	return &decimal
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range bytes.TrimRight(b, "\x00") {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,500 @@
+package imagetool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxFileSize    = 100 * 1024 * 1024 // 100MB input limit
+	maxPixels      = 100_000_000       // 100 megapixel decode limit
+	defaultQuality = 85
+)
+
+// ImageTool implements image inspection and processing
+type ImageTool struct{}
+
+// init registers the image tool
+func init() {
+	registry.Register(&ImageTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ImageTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"image",
+		mcp.WithDescription(`Inspect and process image files: read dimensions, format and EXIF metadata, resize, convert between png/jpeg/webp, compress, and strip metadata (EXIF, GPS, XMP) without re-encoding.
+
+Functions: info (dimensions, format, metadata, EXIF/GPS), resize (width and/or height with fit contain/cover/stretch), convert (to png, jpeg or webp), compress (re-encode, quality applies to jpeg), strip_metadata (lossless removal of EXIF/XMP/text metadata from jpeg, png and webp).
+
+Outputs are written alongside the input with a suffix unless output_path is given. Reads png, jpeg, webp, gif, bmp and tiff.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("info", "resize", "convert", "compress", "strip_metadata"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the input image"),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Absolute output path (default: alongside the input, e.g. photo-resized.jpg)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format (required for convert, optional for resize/compress)"),
+			mcp.Enum(supportedOutputFormats...),
+		),
+		mcp.WithNumber("width",
+			mcp.Description("Target width in pixels for resize (omit to scale from height)"),
+		),
+		mcp.WithNumber("height",
+			mcp.Description("Target height in pixels for resize (omit to scale from width)"),
+		),
+		mcp.WithString("fit",
+			mcp.Description("Resize mode: contain fits within width x height, cover fills and crops the centre, stretch ignores aspect ratio"),
+			mcp.Enum("contain", "cover", "stretch"),
+			mcp.DefaultString("contain"),
+		),
+		mcp.WithNumber("quality",
+			mcp.Description("JPEG quality 1-100 (default: 85). PNG and WebP output is lossless"),
+		),
+		mcp.WithBoolean("auto_orient",
+			mcp.Description("Apply EXIF orientation before re-encoding so the output displays upright (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Overwrite the output file if it exists (required to write over the input)"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // Writes processed images
+		mcp.WithDestructiveHintAnnotation(true), // Can overwrite files when overwrite is true
+		mcp.WithIdempotentHintAnnotation(true),  // Same input produces the same output
+		mcp.WithOpenWorldHintAnnotation(false),  // Local file operations only
+	)
+}
+
+// request holds parsed tool arguments
+type request struct {
+	function   string
+	path       string
+	outputPath string
+	format     string
+	width      int
+	height     int
+	fit        string
+	quality    int
+	autoOrient bool
+	overwrite  bool
+}
+
+// Execute executes the image tool
+func (t *ImageTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	req, err := parseRequest(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := workspace.CheckFileAccess(ctx, req.path); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+	if req.outputPath != "" {
+		if err := workspace.CheckFileAccess(ctx, req.outputPath); err != nil {
+			return nil, fmt.Errorf("file access denied: %w", err)
+		}
+	}
+
+	info, err := os.Stat(req.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access image: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("path is a directory, not an image: %s", req.path)
+	}
+	if info.Size() > maxFileSize {
+		return nil, fmt.Errorf("image is %.1fMB, maximum is %dMB", float64(info.Size())/(1024*1024), maxFileSize/(1024*1024))
+	}
+
+	data, err := os.ReadFile(req.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"function": req.function,
+		"path":     req.path,
+	}).Info("Executing image operation")
+
+	var result any
+	switch req.function {
+	case "info":
+		result, err = inspectImage(req.path, data, info.Size())
+	case "strip_metadata":
+		result, err = stripImage(req, data, info.Size())
+	case "resize", "convert", "compress":
+		result, err = processImage(req, data, info.Size())
+	default:
+		return nil, fmt.Errorf("unknown function: %s", req.function)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseRequest extracts and validates tool arguments
+func parseRequest(args map[string]any) (*request, error) {
+	req := &request{fit: "contain", quality: defaultQuality, autoOrient: true}
+
+	req.function, _ = args["function"].(string)
+	if req.function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+
+	req.path, _ = args["path"].(string)
+	if req.path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	if !filepath.IsAbs(req.path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", req.path)
+	}
+	req.path = filepath.Clean(req.path)
+
+	if outputPath, ok := args["output_path"].(string); ok && outputPath != "" {
+		if !filepath.IsAbs(outputPath) {
+			return nil, fmt.Errorf("output_path must be an absolute path, got: %s", outputPath)
+		}
+		req.outputPath = filepath.Clean(outputPath)
+	}
+
+	if format, ok := args["format"].(string); ok && format != "" {
+		req.format = normaliseFormat(strings.ToLower(strings.TrimSpace(format)))
+		if !slices.Contains(supportedOutputFormats, req.format) {
+			return nil, fmt.Errorf("unsupported output format '%s': use one of %s", format, strings.Join(supportedOutputFormats, ", "))
+		}
+	}
+	if req.function == "convert" && req.format == "" {
+		return nil, fmt.Errorf("convert requires format (png, jpeg or webp)")
+	}
+
+	if width, ok := args["width"].(float64); ok {
+		req.width = int(width)
+	}
+	if height, ok := args["height"].(float64); ok {
+		req.height = int(height)
+	}
+	if req.width < 0 || req.height < 0 {
+		return nil, fmt.Errorf("width and height must be positive")
+	}
+	if req.function == "resize" && req.width == 0 && req.height == 0 {
+		return nil, fmt.Errorf("resize requires width, height or both")
+	}
+
+	if fit, ok := args["fit"].(string); ok && fit != "" {
+		req.fit = strings.ToLower(fit)
+		if !slices.Contains([]string{"contain", "cover", "stretch"}, req.fit) {
+			return nil, fmt.Errorf("invalid fit '%s': use contain, cover or stretch", fit)
+		}
+	}
+	if req.fit != "contain" && (req.width == 0 || req.height == 0) && req.function == "resize" {
+		return nil, fmt.Errorf("fit '%s' requires both width and height", req.fit)
+	}
+
+	if quality, ok := args["quality"].(float64); ok {
+		req.quality = int(quality)
+		if req.quality < 1 || req.quality > 100 {
+			return nil, fmt.Errorf("quality must be between 1 and 100, got %d", req.quality)
+		}
+	}
+	if autoOrient, ok := args["auto_orient"].(bool); ok {
+		req.autoOrient = autoOrient
+	}
+	req.overwrite, _ = args["overwrite"].(bool)
+
+	return req, nil
+}
+
+// inspectImage reports dimensions, format and metadata without decoding pixels
+func inspectImage(path string, data []byte, size int64) (*ImageInfo, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported or corrupt image: %w", err)
+	}
+
+	info := &ImageInfo{
+		Path:        path,
+		Format:      format,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Megapixels:  math.Round(float64(cfg.Width)*float64(cfg.Height)/1e4) / 100,
+		SizeBytes:   size,
+		ColourModel: colourModelName(cfg.ColorModel),
+		Metadata:    listMetadata(data, format),
+	}
+
+	if raw := extractEXIF(data, format); raw != nil {
+		exif, err := parseEXIF(raw)
+		if err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("EXIF present but unreadable: %v", err))
+		} else {
+			info.EXIF = exif.Tags
+			info.Orientation = exif.Orientation
+			if exif.Latitude != nil && exif.Longitude != nil {
+				info.GPS = &GPSLocation{Latitude: *exif.Latitude, Longitude: *exif.Longitude}
+				info.Warnings = append(info.Warnings, "image contains GPS location data - use strip_metadata before sharing")
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// stripImage removes metadata from the container without touching pixel data
+func stripImage(req *request, data []byte, size int64) (*ProcessResult, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported or corrupt image: %w", err)
+	}
+
+	stripped, removed, err := stripMetadata(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	outputPath := req.outputPath
+	if outputPath == "" {
+		outputPath = deriveOutputPath(req.path, "stripped", filepath.Ext(req.path))
+	}
+
+	result := &ProcessResult{
+		Function:          req.function,
+		InputPath:         req.path,
+		OutputPath:        outputPath,
+		Format:            format,
+		Width:             cfg.Width,
+		Height:            cfg.Height,
+		OriginalWidth:     cfg.Width,
+		OriginalHeight:    cfg.Height,
+		SizeBytes:         len(stripped),
+		OriginalSizeBytes: size,
+		SizeChangePercent: sizeChange(size, len(stripped)),
+		RemovedMetadata:   removed,
+	}
+	if len(removed) == 0 {
+		result.Warnings = append(result.Warnings, "no removable metadata found")
+	}
+	if slices.Contains(listMetadata(data, format), "icc") {
+		result.Warnings = append(result.Warnings, "ICC colour profile kept to preserve colour accuracy")
+	}
+
+	if err := writeOutput(req, outputPath, stripped); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// processImage decodes, optionally resizes, and re-encodes an image
+func processImage(req *request, data []byte, size int64) (*ProcessResult, error) {
+	img, inputFormat, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	original := img.Bounds()
+
+	var warnings []string
+	if req.autoOrient {
+		if raw := extractEXIF(data, inputFormat); raw != nil {
+			if exif, err := parseEXIF(raw); err == nil && exif.Orientation > 1 {
+				img = applyOrientation(img, exif.Orientation)
+			}
+		}
+	}
+
+	if req.function == "resize" {
+		if img, err = resizeImage(img, req.width, req.height, req.fit); err != nil {
+			return nil, err
+		}
+	}
+
+	format := req.format
+	if format == "" {
+		format = inputFormat
+		if !slices.Contains(supportedOutputFormats, format) {
+			format = "png"
+			warnings = append(warnings, fmt.Sprintf("%s output is not supported, writing png instead", inputFormat))
+		}
+	}
+
+	encoded, err := encodeImage(img, format, req.quality)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata := listMetadata(data, inputFormat); len(metadata) > 0 {
+		warnings = append(warnings, fmt.Sprintf("re-encoding removed metadata: %s", strings.Join(metadata, ", ")))
+	}
+	if req.function == "compress" && int64(len(encoded)) >= size {
+		warnings = append(warnings, "compressed output is not smaller than the original - try a lower quality or format jpeg/webp")
+	}
+	if req.quality != defaultQuality && format != "jpeg" {
+		warnings = append(warnings, fmt.Sprintf("quality is ignored for lossless %s output", format))
+	}
+
+	outputPath := req.outputPath
+	if outputPath == "" {
+		suffix := map[string]string{"resize": "resized", "compress": "compressed", "convert": "converted"}[req.function]
+		if req.function == "convert" && normaliseFormat(strings.ToLower(filepath.Ext(req.path))) != format {
+			suffix = ""
+		}
+		outputPath = deriveOutputPath(req.path, suffix, formatExtension(format))
+	}
+
+	if err := writeOutput(req, outputPath, encoded); err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &ProcessResult{
+		Function:          req.function,
+		InputPath:         req.path,
+		OutputPath:        outputPath,
+		Format:            format,
+		Width:             bounds.Dx(),
+		Height:            bounds.Dy(),
+		OriginalWidth:     original.Dx(),
+		OriginalHeight:    original.Dy(),
+		SizeBytes:         len(encoded),
+		OriginalSizeBytes: size,
+		SizeChangePercent: sizeChange(size, len(encoded)),
+		Warnings:          warnings,
+	}, nil
+}
+
+// deriveOutputPath builds a sibling path such as /dir/photo-resized.jpg
+func deriveOutputPath(input, suffix, extension string) string {
+	base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	if suffix != "" {
+		base += "-" + suffix
+	}
+	return filepath.Join(filepath.Dir(input), base+extension)
+}
+
+// writeOutput checks access and overwrite rules before writing the result
+func writeOutput(req *request, outputPath string, data []byte) error {
+	if err := security.CheckFileAccess(outputPath); err != nil {
+		return fmt.Errorf("file access denied: %w", err)
+	}
+	if !req.overwrite {
+		if _, err := os.Stat(outputPath); err == nil {
+			return fmt.Errorf("output file %s already exists - set overwrite to true or choose another output_path", outputPath)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+// sizeChange returns the percentage change from the original size
+func sizeChange(original int64, updated int) float64 {
+	if original == 0 {
+		return 0
+	}
+	return math.Round((float64(updated)-float64(original))/float64(original)*1000) / 10
+}
+
+// ProvideExtendedInfo provides detailed usage information for the image tool
+func (t *ImageTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Inspect an image's dimensions and EXIF metadata",
+				Arguments: map[string]any{
+					"function": "info",
+					"path":     "/Users/name/photos/holiday.jpg",
+				},
+				ExpectedResult: "Returns format, width, height, metadata blocks present, EXIF tags and GPS location if any",
+			},
+			{
+				Description: "Resize to a maximum width of 1200px, keeping aspect ratio",
+				Arguments: map[string]any{
+					"function": "resize",
+					"path":     "/Users/name/photos/holiday.jpg",
+					"width":    1200,
+				},
+				ExpectedResult: "Writes /Users/name/photos/holiday-resized.jpg",
+			},
+			{
+				Description: "Create a square thumbnail cropped from the centre",
+				Arguments: map[string]any{
+					"function":    "resize",
+					"path":        "/Users/name/photos/holiday.jpg",
+					"output_path": "/Users/name/photos/thumb.webp",
+					"width":       256,
+					"height":      256,
+					"fit":         "cover",
+					"format":      "webp",
+				},
+				ExpectedResult: "Writes a 256x256 lossless WebP thumbnail",
+			},
+			{
+				Description: "Remove location and camera metadata before sharing",
+				Arguments: map[string]any{
+					"function": "strip_metadata",
+					"path":     "/Users/name/photos/holiday.jpg",
+				},
+				ExpectedResult: "Writes holiday-stripped.jpg with EXIF/XMP removed and pixels untouched",
+			},
+		},
+		CommonPatterns: []string{
+			"Run info first to check dimensions and whether GPS data is present",
+			"Use compress with format jpeg and quality 70-85 to shrink photos; use png or webp for screenshots and diagrams",
+			"Use strip_metadata rather than convert when you only need to remove metadata - it does not re-encode pixels",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Output file already exists",
+				Solution: "Set overwrite to true or choose a different output_path",
+			},
+			{
+				Problem:  "Compressed file is larger than the original",
+				Solution: "The original may already be well compressed. Lower the quality or convert photos to jpeg",
+			},
+			{
+				Problem:  "Transparent areas become white",
+				Solution: "JPEG has no transparency; use png or webp to keep the alpha channel",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"fit":         "contain (default) keeps the whole image within width x height, cover fills the box and crops the centre, stretch distorts to the exact size",
+			"quality":     "JPEG only, 1-100. WebP output is lossless and PNG uses maximum compression",
+			"auto_orient": "Camera photos often rely on the EXIF orientation tag; since re-encoding drops EXIF the rotation is applied to the pixels",
+		},
+		WhenToUse:    "Preparing images for documentation, web pages or sharing: resizing, format conversion, shrinking files and removing private metadata",
+		WhenNotToUse: "Photo editing beyond resizing (colour correction, filters, drawing) or animated images - only the first frame of animated GIFs is processed",
+	}
+}
@@ -0,0 +1,304 @@
+package imagetool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+)
+
+// Metadata stripping works directly on the container format so pixel data is copied
+// byte-for-byte: no re-encoding and no quality loss. ICC colour profiles are kept
+// because removing them changes how colours render.
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+// segment is a metadata-bearing block within an image container
+type segment struct {
+	name    string // JPEG marker as hex, PNG chunk type or WebP FourCC
+	marker  byte
+	start   int
+	end     int
+	payload []byte
+}
+
+// jpegSegments returns the segments before the start of scan and the offset of the SOS marker
+func jpegSegments(data []byte) ([]segment, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, fmt.Errorf("not a valid JPEG file")
+	}
+	var segments []segment
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, 0, fmt.Errorf("invalid JPEG marker at offset %d", i)
+		}
+		start := i
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			break
+		}
+		marker := data[i]
+		i++
+
+		// Standalone markers carry no length
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			continue
+		}
+		if marker == 0xD9 || marker == 0xDA {
+			return segments, start, nil
+		}
+		if i+2 > len(data) {
+			return nil, 0, fmt.Errorf("truncated JPEG segment at offset %d", start)
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, 0, fmt.Errorf("invalid JPEG segment length at offset %d", start)
+		}
+		segments = append(segments, segment{
+			name:    fmt.Sprintf("%02X", marker),
+			marker:  marker,
+			start:   start,
+			end:     i + length,
+			payload: data[i+2 : i+length],
+		})
+		i += length
+	}
+	return nil, 0, fmt.Errorf("JPEG file has no image data")
+}
+
+// pngChunks returns every chunk in a PNG file
+func pngChunks(data []byte) ([]segment, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("not a valid PNG file")
+	}
+	var chunks []segment
+	i := len(pngSignature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("invalid PNG chunk length at offset %d", i)
+		}
+		chunks = append(chunks, segment{name: string(data[i+4 : i+8]), start: i, end: end, payload: data[i+8 : i+8+length]})
+		i = end
+		if chunks[len(chunks)-1].name == "IEND" {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+// webpChunks returns every chunk in a WebP RIFF container
+func webpChunks(data []byte) ([]segment, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("not a valid WebP file")
+	}
+	var chunks []segment
+	i := 12
+	for i+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size
+		if size < 0 || end > len(data) {
+			return nil, fmt.Errorf("invalid WebP chunk length at offset %d", i)
+		}
+		chunks = append(chunks, segment{name: string(data[i : i+4]), start: i, end: end + size%2, payload: data[i+8 : end]})
+		i = min(end+size%2, len(data))
+	}
+	return chunks, nil
+}
+
+func walkJPEGSegments(data []byte, fn func(marker byte, payload []byte) bool) error {
+	segments, _, err := jpegSegments(data)
+	if err != nil {
+		return err
+	}
+	for _, s := range segments {
+		if !fn(s.marker, s.payload) {
+			break
+		}
+	}
+	return nil
+}
+
+func walkPNGChunks(data []byte, fn func(chunkType string, payload []byte) bool) error {
+	chunks, err := pngChunks(data)
+	if err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		if !fn(c.name, c.payload) {
+			break
+		}
+	}
+	return nil
+}
+
+func walkWebPChunks(data []byte, fn func(fourCC string, payload []byte) bool) error {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		if !fn(c.name, c.payload) {
+			break
+		}
+	}
+	return nil
+}
+
+// jpegMetadataKind classifies a JPEG segment, returning "" for non-metadata segments
+func jpegMetadataKind(s segment) string {
+	switch {
+	case s.marker == 0xE1 && bytes.HasPrefix(s.payload, []byte("Exif\x00")):
+		return "exif"
+	case s.marker == 0xE1 && bytes.HasPrefix(s.payload, []byte("http://ns.adobe.com/xap/1.0/")):
+		return "xmp"
+	case s.marker == 0xE2 && bytes.HasPrefix(s.payload, []byte("ICC_PROFILE")):
+		return "icc"
+	case s.marker == 0xED:
+		return "iptc"
+	case s.marker == 0xFE:
+		return "comment"
+	case s.marker == 0xE1 || (s.marker >= 0xE3 && s.marker <= 0xEF && s.marker != 0xEE):
+		// APP14 (0xEE, Adobe) is kept as it affects colour transforms
+		return "app" + s.name
+	}
+	return ""
+}
+
+// pngMetadataKind classifies a PNG chunk, returning "" for non-metadata chunks
+func pngMetadataKind(s segment) string {
+	switch s.name {
+	case "eXIf":
+		return "exif"
+	case "iTXt":
+		if bytes.HasPrefix(s.payload, []byte("XML:com.adobe.xmp")) {
+			return "xmp"
+		}
+		return "text"
+	case "tEXt", "zTXt":
+		return "text"
+	case "tIME":
+		return "timestamp"
+	case "iCCP":
+		return "icc"
+	}
+	return ""
+}
+
+// webpMetadataKind classifies a WebP chunk, returning "" for non-metadata chunks
+func webpMetadataKind(s segment) string {
+	switch s.name {
+	case "EXIF":
+		return "exif"
+	case "XMP ":
+		return "xmp"
+	case "ICCP":
+		return "icc"
+	}
+	return ""
+}
+
+// listMetadata reports which metadata blocks an image contains
+func listMetadata(data []byte, format string) []string {
+	var kinds []string
+	add := func(kind string) {
+		if kind != "" && !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	switch format {
+	case "jpeg":
+		if segments, _, err := jpegSegments(data); err == nil {
+			for _, s := range segments {
+				add(jpegMetadataKind(s))
+			}
+		}
+	case "png":
+		if chunks, err := pngChunks(data); err == nil {
+			for _, c := range chunks {
+				add(pngMetadataKind(c))
+			}
+		}
+	case "webp":
+		if chunks, err := webpChunks(data); err == nil {
+			for _, c := range chunks {
+				add(webpMetadataKind(c))
+			}
+		}
+	}
+	return kinds
+}
+
+// stripMetadata removes EXIF, XMP, IPTC, text and comment metadata without re-encoding pixels
+func stripMetadata(data []byte, format string) ([]byte, []string, error) {
+	var removed []string
+	note := func(kind string) {
+		if !slices.Contains(removed, kind) {
+			removed = append(removed, kind)
+		}
+	}
+
+	var out bytes.Buffer
+	switch format {
+	case "jpeg":
+		segments, scanStart, err := jpegSegments(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		out.Write(data[:2])
+		for _, s := range segments {
+			if kind := jpegMetadataKind(s); kind != "" && kind != "icc" {
+				note(kind)
+				continue
+			}
+			out.Write(data[s.start:s.end])
+		}
+		out.Write(data[scanStart:])
+
+	case "png":
+		chunks, err := pngChunks(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		out.Write(pngSignature)
+		for _, c := range chunks {
+			if kind := pngMetadataKind(c); kind != "" && kind != "icc" {
+				note(kind)
+				continue
+			}
+			out.Write(data[c.start:c.end])
+		}
+
+	case "webp":
+		chunks, err := webpChunks(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		var body bytes.Buffer
+		for _, c := range chunks {
+			if kind := webpMetadataKind(c); kind != "" && kind != "icc" {
+				note(kind)
+				continue
+			}
+			chunk := slices.Clone(data[c.start:c.end])
+			if c.name == "VP8X" && len(chunk) > 8 {
+				// Clear the EXIF (0x08) and XMP (0x04) presence flags
+				chunk[8] &^= 0x08 | 0x04
+			}
+			body.Write(chunk)
+		}
+		out.WriteString("RIFF")
+		_ = binary.Write(&out, binary.LittleEndian, uint32(4+body.Len()))
+		out.WriteString("WEBP")
+		out.Write(body.Bytes())
+
+	default:
+		return nil, nil, fmt.Errorf("metadata stripping supports jpeg, png and webp - convert %s images first", format)
+	}
+
+	return out.Bytes(), removed, nil
+}
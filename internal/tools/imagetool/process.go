@@ -0,0 +1,223 @@
+package imagetool
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"github.com/HugoSmits86/nativewebp"
+	_ "golang.org/x/image/bmp" // Register BMP decoder
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff" // Register TIFF decoder
+	_ "golang.org/x/image/webp" // Register WebP decoder
+)
+
+// supportedOutputFormats lists the formats images can be written as
+var supportedOutputFormats = []string{"png", "jpeg", "webp"}
+
+// normaliseFormat maps user supplied format names and extensions to canonical names
+func normaliseFormat(format string) string {
+	switch format {
+	case "jpg", "jpeg", ".jpg", ".jpeg":
+		return "jpeg"
+	case "png", ".png":
+		return "png"
+	case "webp", ".webp":
+		return "webp"
+	case "gif", ".gif":
+		return "gif"
+	case "bmp", ".bmp":
+		return "bmp"
+	case "tif", "tiff", ".tif", ".tiff":
+		return "tiff"
+	}
+	return format
+}
+
+// formatExtension returns the file extension for an output format
+func formatExtension(format string) string {
+	if format == "jpeg" {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// decodeImage decodes image data after checking its dimensions are within limits
+func decodeImage(data []byte) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unsupported or corrupt image: %w", err)
+	}
+	if err := checkPixelLimit(cfg.Width, cfg.Height); err != nil {
+		return nil, "", err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s image: %w", format, err)
+	}
+	return img, format, nil
+}
+
+// checkPixelLimit guards against decompression bombs
+func checkPixelLimit(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid image dimensions %dx%d", width, height)
+	}
+	if int64(width)*int64(height) > maxPixels {
+		return fmt.Errorf("image is %dx%d pixels, maximum is %d megapixels", width, height, maxPixels/1_000_000)
+	}
+	return nil
+}
+
+// applyOrientation rotates or flips an image according to its EXIF orientation so
+// the result displays correctly once the EXIF block has been dropped by re-encoding
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // mirror horizontal and rotate 270 CW
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = h-1-y, x
+			case 7: // mirror horizontal and rotate 90 CW
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 270 CW
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// targetSize calculates output dimensions for a resize request
+func targetSize(srcW, srcH, width, height int, fit string) (int, int, error) {
+	if width <= 0 && height <= 0 {
+		return 0, 0, fmt.Errorf("resize requires width, height or both")
+	}
+	if width <= 0 {
+		width = max(1, int(math.Round(float64(srcW)*float64(height)/float64(srcH))))
+	}
+	if height <= 0 {
+		height = max(1, int(math.Round(float64(srcH)*float64(width)/float64(srcW))))
+	}
+	if fit == "contain" {
+		scale := min(float64(width)/float64(srcW), float64(height)/float64(srcH))
+		width = max(1, int(math.Round(float64(srcW)*scale)))
+		height = max(1, int(math.Round(float64(srcH)*scale)))
+	}
+	if err := checkPixelLimit(width, height); err != nil {
+		return 0, 0, err
+	}
+	return width, height, nil
+}
+
+// resizeImage scales an image. "contain" fits within the box, "cover" fills the box
+// and crops the overflow from the centre, and "stretch" ignores the aspect ratio.
+func resizeImage(img image.Image, width, height int, fit string) (image.Image, error) {
+	src := img.Bounds()
+	w, h, err := targetSize(src.Dx(), src.Dy(), width, height, fit)
+	if err != nil {
+		return nil, err
+	}
+
+	srcRect := src
+	if fit == "cover" {
+		// Crop the source to the target aspect ratio before scaling
+		targetRatio := float64(w) / float64(h)
+		srcRatio := float64(src.Dx()) / float64(src.Dy())
+		if srcRatio > targetRatio {
+			cropW := int(math.Round(float64(src.Dy()) * targetRatio))
+			x0 := src.Min.X + (src.Dx()-cropW)/2
+			srcRect = image.Rect(x0, src.Min.Y, x0+cropW, src.Max.Y)
+		} else {
+			cropH := int(math.Round(float64(src.Dx()) / targetRatio))
+			y0 := src.Min.Y + (src.Dy()-cropH)/2
+			srcRect = image.Rect(src.Min.X, y0, src.Max.X, y0+cropH)
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, srcRect, draw.Src, nil)
+	return dst, nil
+}
+
+// encodeImage writes an image in the requested format. Quality applies to JPEG only;
+// PNG and WebP output are lossless.
+func encodeImage(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, flattenAlpha(img), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	case "png":
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+	case "webp":
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode webp: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format '%s': use one of png, jpeg, webp", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// flattenAlpha composites transparent images onto white, as JPEG has no alpha channel
+func flattenAlpha(img image.Image) image.Image {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return img
+	}
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// colourModelName describes an image's colour model
+func colourModelName(model color.Model) string {
+	if _, ok := model.(color.Palette); ok {
+		return "paletted"
+	}
+	switch model {
+	case color.RGBAModel, color.RGBA64Model:
+		return "rgba"
+	case color.NRGBAModel, color.NRGBA64Model:
+		return "nrgba"
+	case color.GrayModel, color.Gray16Model:
+		return "grey"
+	case color.CMYKModel:
+		return "cmyk"
+	case color.YCbCrModel:
+		return "ycbcr"
+	case color.AlphaModel, color.Alpha16Model:
+		return "alpha"
+	}
+	return "other"
+}
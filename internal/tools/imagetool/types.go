@@ -0,0 +1,40 @@
+package imagetool
+
+// ImageInfo describes an image's dimensions, format and metadata
+type ImageInfo struct {
+	Path        string         `json:"path"`
+	Format      string         `json:"format"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	Megapixels  float64        `json:"megapixels"`
+	SizeBytes   int64          `json:"size_bytes"`
+	ColourModel string         `json:"colour_model"`
+	Orientation int            `json:"orientation,omitempty"`
+	Metadata    []string       `json:"metadata,omitempty"`
+	EXIF        map[string]any `json:"exif,omitempty"`
+	GPS         *GPSLocation   `json:"gps,omitempty"`
+	Warnings    []string       `json:"warnings,omitempty"`
+}
+
+// GPSLocation is a decimal latitude/longitude pair read from EXIF
+type GPSLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ProcessResult describes the output of a resize, convert, compress or strip operation
+type ProcessResult struct {
+	Function          string   `json:"function"`
+	InputPath         string   `json:"input_path"`
+	OutputPath        string   `json:"output_path"`
+	Format            string   `json:"format"`
+	Width             int      `json:"width"`
+	Height            int      `json:"height"`
+	OriginalWidth     int      `json:"original_width"`
+	OriginalHeight    int      `json:"original_height"`
+	SizeBytes         int      `json:"size_bytes"`
+	OriginalSizeBytes int64    `json:"original_size_bytes"`
+	SizeChangePercent float64  `json:"size_change_percent"`
+	RemovedMetadata   []string `json:"removed_metadata,omitempty"`
+	Warnings          []string `json:"warnings,omitempty"`
+}
@@ -115,6 +115,7 @@ func (p *BraveProvider) executeInternetSearch(ctx context.Context, logger *logru
 			Title:       decodeHTMLEntities(webResult.Title),
 			URL:         webResult.URL,
 			Description: decodeHTMLEntities(webResult.Description),
+			Published:   internetsearch.ParseDate(webResult.PageAge),
 			Metadata:    metadata,
 		})
 	}
@@ -147,23 +148,16 @@ func (p *BraveProvider) executeImageSearch(ctx context.Context, logger *logrus.L
 
 	results := make([]internetsearch.SearchResult, 0, len(response.Results))
 	for _, imageResult := range response.Results {
-		metadata := make(map[string]any)
-		metadata["imageURL"] = imageResult.Properties.URL
-		if imageResult.Properties.Format != "" {
-			metadata["format"] = imageResult.Properties.Format
-		}
-		if imageResult.Properties.Width > 0 {
-			metadata["width"] = imageResult.Properties.Width
-		}
-		if imageResult.Properties.Height > 0 {
-			metadata["height"] = imageResult.Properties.Height
-		}
-
 		results = append(results, internetsearch.SearchResult{
 			Title:       decodeHTMLEntities(imageResult.Title),
 			URL:         imageResult.URL,
 			Description: fmt.Sprintf("Image: %s", decodeHTMLEntities(imageResult.Title)),
-			Metadata:    metadata,
+			Image: &internetsearch.ImageDetails{
+				ImageURL: imageResult.Properties.URL,
+				Format:   imageResult.Properties.Format,
+				Width:    imageResult.Properties.Width,
+				Height:   imageResult.Properties.Height,
+			},
 		})
 	}
 
@@ -200,16 +194,12 @@ func (p *BraveProvider) executeNewsSearch(ctx context.Context, logger *logrus.Lo
 
 	results := make([]internetsearch.SearchResult, 0, len(response.Results))
 	for _, newsResult := range response.Results {
-		metadata := make(map[string]any)
-		if newsResult.Age != "" {
-			metadata["age"] = newsResult.Age
-		}
-
 		results = append(results, internetsearch.SearchResult{
 			Title:       decodeHTMLEntities(newsResult.Title),
 			URL:         newsResult.URL,
 			Description: decodeHTMLEntities(newsResult.Description),
-			Metadata:    metadata,
+			Published:   internetsearch.ParseDate(newsResult.PageAge),
+			News:        &internetsearch.NewsDetails{Age: newsResult.Age},
 		})
 	}
 
@@ -246,22 +236,16 @@ func (p *BraveProvider) executeVideoSearch(ctx context.Context, logger *logrus.L
 
 	results := make([]internetsearch.SearchResult, 0, len(response.Results))
 	for _, videoResult := range response.Results {
-		metadata := make(map[string]any)
-		if videoResult.Video.Duration != "" {
-			metadata["duration"] = videoResult.Video.Duration
-		}
-		if videoResult.Video.Views != nil {
-			metadata["views"] = videoResult.Video.Views
-		}
-		if videoResult.Video.Creator != "" {
-			metadata["creator"] = videoResult.Video.Creator
-		}
-
 		results = append(results, internetsearch.SearchResult{
 			Title:       decodeHTMLEntities(videoResult.Title),
 			URL:         videoResult.URL,
 			Description: fmt.Sprintf("Video: %s", decodeHTMLEntities(videoResult.Title)),
-			Metadata:    metadata,
+			Published:   internetsearch.ParseDate(videoResult.PageAge),
+			Video: &internetsearch.VideoDetails{
+				Duration: videoResult.Video.Duration,
+				Views:    videoResult.Video.Views,
+				Creator:  videoResult.Video.Creator,
+			},
 		})
 	}
 
@@ -315,6 +299,7 @@ func (p *BraveProvider) executeLocalSearch(ctx context.Context, logger *logrus.L
 			Title:       decodeHTMLEntities(webResult.Title),
 			URL:         webResult.URL,
 			Description: decodeHTMLEntities(webResult.Description),
+			Published:   internetsearch.ParseDate(webResult.PageAge),
 			Metadata:    metadata,
 		})
 	}
@@ -387,33 +372,23 @@ func (p *BraveProvider) processLocalResults(ctx context.Context, logger *logrus.
 			break
 		}
 
-		metadata := make(map[string]any)
+		details := &internetsearch.LocalDetails{}
 
 		// Add POI data if available
 		if poi, exists := poiMap[location.ID]; exists {
-			if poi.Address != "" {
-				metadata["address"] = poi.Address
-			}
-			if poi.PhoneNumber != "" {
-				metadata["phone"] = poi.PhoneNumber
-			}
-			if poi.Rating > 0 {
-				metadata["rating"] = poi.Rating
-			}
-			if poi.ReviewCount > 0 {
-				metadata["reviewCount"] = poi.ReviewCount
-			}
-			if poi.Website != "" {
-				metadata["website"] = poi.Website
-			}
+			details.Address = poi.Address
+			details.Phone = poi.PhoneNumber
+			details.Website = poi.Website
+			details.Rating = poi.Rating
+			details.ReviewCount = poi.ReviewCount
 			if len(poi.Hours) > 0 {
-				metadata["hours"] = poi.Hours
+				details.Hours = poi.Hours
 			}
 		}
 
 		// Add coordinates if available
 		if len(location.Coordinates) >= 2 {
-			metadata["coordinates"] = location.Coordinates
+			details.Coordinates = location.Coordinates
 		}
 
 		// Use description from descriptions API if available
@@ -426,7 +401,7 @@ func (p *BraveProvider) processLocalResults(ctx context.Context, logger *logrus.
 			Title:       decodeHTMLEntities(location.Title),
 			URL:         location.URL,
 			Description: decodeHTMLEntities(description),
-			Metadata:    metadata,
+			Local:       details,
 		})
 	}
 
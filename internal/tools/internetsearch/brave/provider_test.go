@@ -0,0 +1,96 @@
+package brave
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testProvider returns a Brave provider whose API is served by handler
+func testProvider(t *testing.T, handler http.HandlerFunc) *BraveProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &BraveProvider{
+		client: &BraveClient{apiKey: "test-key", baseURL: server.URL, httpClient: server.Client()},
+	}
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestBraveProvider_VideoResults(t *testing.T) {
+	provider := testProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/videos/search" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"type": "videos", "results": [{"type": "video_result", "title": "Go &amp; you", "url": "https://example.com/watch", "video": {"duration": "12:34", "views": 1500, "creator": "Gopher"}}]}`))
+	})
+
+	response, err := provider.Search(context.Background(), testLogger(), "video", map[string]any{"query": "golang"})
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if len(response.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(response.Results))
+	}
+
+	result := response.Results[0]
+	if result.Title != "Go & you" {
+		t.Errorf("Expected decoded title, got %q", result.Title)
+	}
+	if result.Video == nil {
+		t.Fatal("Expected video details")
+	}
+	if result.Video.Duration != "12:34" || result.Video.Creator != "Gopher" || result.Video.Views != float64(1500) {
+		t.Errorf("Unexpected video details: %+v", *result.Video)
+	}
+	if result.Image != nil || result.News != nil || result.Local != nil {
+		t.Error("Expected only video details on a video result")
+	}
+}
+
+func TestBraveProvider_LocalResults(t *testing.T) {
+	provider := testProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/web/search":
+			_, _ = w.Write([]byte(`{"type": "search", "locations": {"type": "locations", "results": [{"id": "loc-1", "title": "Corner Cafe", "url": "https://example.com/cafe", "description": "Coffee", "coordinates": [-37.8, 144.98]}]}}`))
+		case "/local/pois":
+			_, _ = w.Write([]byte(`{"type": "local_pois", "results": [{"name": "Corner Cafe", "address": "1 Smith St", "phone_number": "03 9000 0000", "rating": 4.5, "review_count": 120}]}`))
+		case "/local/descriptions":
+			_, _ = w.Write([]byte(`{"type": "local_descriptions", "results": [{"id": "loc-1", "description": "Specialty coffee"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	response, err := provider.Search(context.Background(), testLogger(), "local", map[string]any{"query": "cafe in Fitzroy"})
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if len(response.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(response.Results))
+	}
+
+	result := response.Results[0]
+	if result.Description != "Specialty coffee" {
+		t.Errorf("Expected the location's description, got %q", result.Description)
+	}
+	if result.Local == nil {
+		t.Fatal("Expected local details")
+	}
+	if result.Local.Address != "1 Smith St" || result.Local.Phone != "03 9000 0000" || result.Local.Rating != 4.5 || result.Local.ReviewCount != 120 {
+		t.Errorf("Unexpected local details: %+v", *result.Local)
+	}
+	if len(result.Local.Coordinates) != 2 {
+		t.Errorf("Expected coordinates, got %v", result.Local.Coordinates)
+	}
+}
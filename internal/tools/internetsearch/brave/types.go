@@ -88,6 +88,7 @@ type BraveWebResult struct {
 	URL         string `json:"url"`
 	Description string `json:"description"`
 	Age         string `json:"age,omitempty"`
+	PageAge     string `json:"page_age,omitempty"` // When the page was published, e.g. 2024-05-01T09:30:00
 }
 
 // BraveImageSearchResponse represents the response from Brave image search API
@@ -126,6 +127,7 @@ type BraveNewsResult struct {
 	URL         string `json:"url"`
 	Description string `json:"description"`
 	Age         string `json:"age"`
+	PageAge     string `json:"page_age,omitempty"`
 }
 
 // BraveVideoSearchResponse represents the response from Brave video search API
@@ -137,10 +139,11 @@ type BraveVideoSearchResponse struct {
 
 // BraveVideoResult represents a single video search result
 type BraveVideoResult struct {
-	Type  string         `json:"type"`
-	Title string         `json:"title"`
-	URL   string         `json:"url"`
-	Video BraveVideoData `json:"video"`
+	Type    string         `json:"type"`
+	Title   string         `json:"title"`
+	URL     string         `json:"url"`
+	PageAge string         `json:"page_age,omitempty"`
+	Video   BraveVideoData `json:"video"`
 }
 
 // BraveVideoData contains video metadata
@@ -180,22 +180,17 @@ func (p *GoogleProvider) executeImageSearch(ctx context.Context, logger *logrus.
 
 	results := make([]internetsearch.SearchResult, 0, len(response.Items))
 	for _, item := range response.Items {
-		metadata := make(map[string]any)
-
-		// Add image-specific metadata
+		// The item's link is the image itself, and the image's context link the page it is on, which
+		// is the result's URL as it is for other providers
+		pageURL := item.Link
+		details := &internetsearch.ImageDetails{ImageURL: item.Link}
 		if item.Image != nil {
 			if item.Image.ContextLink != "" {
-				metadata["imageURL"] = item.Image.ContextLink
-			}
-			if item.Image.Height > 0 {
-				metadata["height"] = item.Image.Height
-			}
-			if item.Image.Width > 0 {
-				metadata["width"] = item.Image.Width
-			}
-			if item.Image.ThumbnailLink != "" {
-				metadata["thumbnailURL"] = item.Image.ThumbnailLink
+				pageURL = item.Image.ContextLink
 			}
+			details.ThumbnailURL = item.Image.ThumbnailLink
+			details.Width = item.Image.Width
+			details.Height = item.Image.Height
 		}
 
 		// Use snippet if available, otherwise fall back to title-based description
@@ -206,9 +201,9 @@ func (p *GoogleProvider) executeImageSearch(ctx context.Context, logger *logrus.
 
 		results = append(results, internetsearch.SearchResult{
 			Title:       item.Title,
-			URL:         item.Link,
+			URL:         pageURL,
 			Description: description,
-			Metadata:    metadata,
+			Image:       details,
 		})
 	}
 
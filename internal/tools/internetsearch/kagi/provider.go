@@ -95,7 +95,9 @@ func (p *KagiProvider) executeWebSearch(ctx context.Context, logger *logrus.Logg
 		metadata := make(map[string]any)
 		metadata["rank"] = kagiResult.Rank
 
-		if kagiResult.Published != "" {
+		// Dates that cannot be read are passed on as Kagi gave them
+		published := internetsearch.ParseDate(kagiResult.Published)
+		if published == nil && kagiResult.Published != "" {
 			metadata["published"] = kagiResult.Published
 		}
 
@@ -115,6 +117,7 @@ func (p *KagiProvider) executeWebSearch(ctx context.Context, logger *logrus.Logg
 			Title:       decodeHTMLEntities(kagiResult.Title),
 			URL:         kagiResult.URL,
 			Description: decodeHTMLEntities(kagiResult.Snippet),
+			Published:   published,
 			Metadata:    metadata,
 		})
 	}
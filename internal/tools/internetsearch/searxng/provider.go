@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,9 +33,18 @@ type SearXNGResponse struct {
 
 // SearXNGResult represents a single search result from SearXNG
 type SearXNGResult struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	URL     string `json:"url"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	URL           string `json:"url"`
+	ImgSrc        string `json:"img_src,omitempty"`
+	ThumbnailSrc  string `json:"thumbnail_src,omitempty"`
+	Thumbnail     string `json:"thumbnail,omitempty"`
+	ImgFormat     string `json:"img_format,omitempty"`
+	Resolution    string `json:"resolution,omitempty"`
+	PublishedDate string `json:"publishedDate,omitempty"`
+	Length        any    `json:"length,omitempty"` // A video's duration, as text or seconds
+	Author        string `json:"author,omitempty"`
+	Engine        string `json:"engine,omitempty"`
 }
 
 // NewSearXNGProvider creates a new SearXNG search provider
@@ -239,17 +249,54 @@ func (p *SearXNGProvider) executeSearch(ctx context.Context, logger *logrus.Logg
 			metadata["time_range"] = timeRange
 		}
 
-		results = append(results, internetsearch.SearchResult{
+		result := internetsearch.SearchResult{
 			Title:       searxngResult.Title,
 			URL:         searxngResult.URL,
 			Description: searxngResult.Content,
+			Published:   internetsearch.ParseDate(searxngResult.PublishedDate),
 			Metadata:    metadata,
-		})
+		}
+		switch searchType {
+		case internetsearch.SearchTypeImage:
+			result.Image = &internetsearch.ImageDetails{
+				ImageURL:     searxngResult.ImgSrc,
+				ThumbnailURL: searxngResult.ThumbnailSrc,
+				Format:       searxngResult.ImgFormat,
+			}
+			result.Image.Width, result.Image.Height = parseResolution(searxngResult.Resolution)
+		case internetsearch.SearchTypeNews:
+			result.News = &internetsearch.NewsDetails{Source: searxngResult.Engine}
+		case internetsearch.SearchTypeVideo:
+			result.Video = &internetsearch.VideoDetails{
+				Creator:      searxngResult.Author,
+				ThumbnailURL: searxngResult.Thumbnail,
+			}
+			if searxngResult.Length != nil {
+				result.Video.Duration = fmt.Sprint(searxngResult.Length)
+			}
+		}
+		results = append(results, result)
 	}
 
 	return p.createSuccessResponse(query, results, logger), nil
 }
 
+// parseResolution returns the width and height of a resolution such as "1920 x 1080" or "1920×1080",
+// or zeros if it cannot be read
+func parseResolution(resolution string) (int, int) {
+	resolution = strings.ReplaceAll(strings.ReplaceAll(resolution, " ", ""), "×", "x")
+	width, height, ok := strings.Cut(resolution, "x")
+	if !ok {
+		return 0, 0
+	}
+	w, errW := strconv.Atoi(width)
+	h, errH := strconv.Atoi(height)
+	if errW != nil || errH != nil {
+		return 0, 0
+	}
+	return w, h
+}
+
 // Helper functions
 func (p *SearXNGProvider) createEmptyResponse() *internetsearch.SearchResponse {
 	return &internetsearch.SearchResponse{
@@ -4,12 +4,66 @@ import (
 	"time"
 )
 
-// SearchResult represents a unified search result
+// Search types, in the order they are listed
+const (
+	SearchTypeWeb   = "web"
+	SearchTypeImage = "image"
+	SearchTypeNews  = "news"
+	SearchTypeVideo = "video"
+	SearchTypeLocal = "local"
+)
+
+// SearchTypes lists every search type
+var SearchTypes = []string{SearchTypeWeb, SearchTypeImage, SearchTypeNews, SearchTypeVideo, SearchTypeLocal}
+
+// SearchResult represents a unified search result. Results of image, news, video and local searches
+// carry their type's details in the matching field, and results of other types leave them nil.
 type SearchResult struct {
-	Title       string         `json:"title"`
-	URL         string         `json:"url"`
-	Description string         `json:"description"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	// Published is when the page was published, for results whose provider gives a date
+	Published *time.Time     `json:"published,omitempty"`
+	Image     *ImageDetails  `json:"image,omitempty"`
+	News      *NewsDetails   `json:"news,omitempty"`
+	Video     *VideoDetails  `json:"video,omitempty"`
+	Local     *LocalDetails  `json:"local,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// ImageDetails describes an image search result
+type ImageDetails struct {
+	ImageURL     string `json:"image_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	Format       string `json:"format,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+}
+
+// NewsDetails describes a news search result
+type NewsDetails struct {
+	// Age is how long ago the article was published, as the provider describes it, e.g. "2 hours ago"
+	Age    string `json:"age,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// VideoDetails describes a video search result
+type VideoDetails struct {
+	Duration     string `json:"duration,omitempty"`
+	Views        any    `json:"views,omitempty"` // A number or the provider's text, e.g. "1.2M"
+	Creator      string `json:"creator,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// LocalDetails describes a business or place found by a local search
+type LocalDetails struct {
+	Address     string         `json:"address,omitempty"`
+	Phone       string         `json:"phone,omitempty"`
+	Website     string         `json:"website,omitempty"`
+	Rating      float64        `json:"rating,omitempty"`
+	ReviewCount int            `json:"review_count,omitempty"`
+	Hours       map[string]any `json:"hours,omitempty"`
+	Coordinates []float64      `json:"coordinates,omitempty"`
 }
 
 // SearchResponse represents a unified response structure
@@ -21,15 +75,41 @@ type SearchResponse struct {
 
 // QueryResult represents the result of a single query in a multi-query search
 type QueryResult struct {
-	Query    string         `json:"query"`
-	Results  []SearchResult `json:"results"`
-	Provider string         `json:"provider,omitempty"`
-	Error    string         `json:"error,omitempty"`
+	Query    string          `json:"query"`
+	Results  []SearchResult  `json:"results"`
+	Provider string          `json:"provider,omitempty"`
+	Filtered *FilteredCounts `json:"filtered,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// FilteredCounts counts the results of a query that were left out, by the reason they were left out
+type FilteredCounts struct {
+	Duplicates int `json:"duplicates,omitempty"`
+	// Domain counts results outside include_domains or inside exclude_domains
+	Domain int `json:"domain,omitempty"`
+	// OutOfRange counts results published outside the after and before dates
+	OutOfRange int `json:"out_of_range,omitempty"`
+	// Undated counts results without a publication date to compare with the after and before dates
+	Undated int `json:"undated,omitempty"`
+}
+
+// FetchedPage is the content of a search result's page, fetched with fetch_url
+type FetchedPage struct {
+	Query          string `json:"query"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	Content        string `json:"content,omitempty"`
+	Truncated      bool   `json:"truncated,omitempty"`
+	TotalLength    int    `json:"total_length,omitempty"`
+	SecurityNotice string `json:"security_notice,omitempty"`
+	Error          string `json:"error,omitempty"`
 }
 
 // MultiSearchResponse represents the response for multi-query searches
 type MultiSearchResponse struct {
+	Type     string        `json:"type"`
 	Searches []QueryResult `json:"searches"`
+	Pages    []FetchedPage `json:"pages,omitempty"`
 	Summary  SearchSummary `json:"summary"`
 }
 
@@ -38,4 +118,7 @@ type SearchSummary struct {
 	Total      int `json:"total"`
 	Successful int `json:"successful"`
 	Failed     int `json:"failed"`
+	// PagesFetched and PagesFailed count the pages fetched for fetch_top_n
+	PagesFetched int `json:"pages_fetched,omitempty"`
+	PagesFailed  int `json:"pages_failed,omitempty"`
 }
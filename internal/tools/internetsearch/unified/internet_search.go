@@ -50,6 +50,19 @@ var providerPriorityOrder = []string{"brave", "google", "kagi", "searxng", "duck
 // maxParallelSearches controls how many queries can execute concurrently
 var maxParallelSearches = defaultMaxParallelSearches
 
+// defaultSearchTypes are the search types offered when INTERNET_SEARCH_TYPES is not set. Local search
+// is left out, as it needs a paid Brave plan, makes three API calls for each query and sends the
+// places being searched for to the provider.
+var defaultSearchTypes = []string{
+	internetsearch.SearchTypeWeb,
+	internetsearch.SearchTypeImage,
+	internetsearch.SearchTypeNews,
+	internetsearch.SearchTypeVideo,
+}
+
+// enabledSearchTypes are the search types the tool offers, of those its providers support
+var enabledSearchTypes = defaultSearchTypes
+
 // queryWork represents a single query to be processed by a worker
 type queryWork struct {
 	index int
@@ -63,6 +76,7 @@ func init() {
 			maxParallelSearches = n
 		}
 	}
+	enabledSearchTypes = parseSearchTypes(os.Getenv("INTERNET_SEARCH_TYPES"))
 
 	tool := &InternetSearchTool{
 		providers: make(map[string]SearchProvider),
@@ -91,11 +105,64 @@ func init() {
 	}
 
 	// Only register if we have at least one provider
-	if len(tool.providers) > 0 {
+	switch {
+	case len(tool.providers) == 0:
+		registry.RegisterUnavailable("internet_search", "no search providers are available")
+	case len(tool.offeredTypes()) == 0:
+		registry.RegisterUnavailable("internet_search", "no available search provider supports the search types in INTERNET_SEARCH_TYPES")
+	default:
 		registry.Register(tool)
 	}
 }
 
+// parseSearchTypes returns the search types named in a comma-separated list, in their usual order.
+// "all" enables every type, unknown names are ignored, and the defaults are used when the list names
+// no known type.
+func parseSearchTypes(value string) []string {
+	names := make(map[string]bool)
+	for name := range strings.SplitSeq(value, ",") {
+		names[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	if names["all"] {
+		return internetsearch.SearchTypes
+	}
+
+	var types []string
+	for _, searchType := range internetsearch.SearchTypes {
+		if names[searchType] {
+			types = append(types, searchType)
+		}
+	}
+	if len(types) == 0 {
+		return defaultSearchTypes
+	}
+	return types
+}
+
+// offeredTypes returns the enabled search types that at least one provider supports, in their usual order
+func (t *InternetSearchTool) offeredTypes() []string {
+	var types []string
+	for _, searchType := range enabledSearchTypes {
+		for _, provider := range t.providers {
+			if t.providerSupportsType(provider, searchType) {
+				types = append(types, searchType)
+				break
+			}
+		}
+	}
+	return types
+}
+
+// defaultType returns the search type used when none is given: web search, or the first offered type
+// when web search is disabled
+func (t *InternetSearchTool) defaultType() string {
+	types := t.offeredTypes()
+	if len(types) == 0 || slices.Contains(types, internetsearch.SearchTypeWeb) {
+		return internetsearch.SearchTypeWeb
+	}
+	return types[0]
+}
+
 // Definition returns the tool's definition for MCP registration
 func (t *InternetSearchTool) Definition() mcp.Tool {
 	// Get available providers for description
@@ -104,18 +171,9 @@ func (t *InternetSearchTool) Definition() mcp.Tool {
 		availableProviders = append(availableProviders, name)
 	}
 
-	// Get all supported search types
-	supportedTypes := make(map[string]bool)
-	for _, provider := range t.providers {
-		for _, searchType := range provider.GetSupportedTypes() {
-			supportedTypes[searchType] = true
-		}
-	}
-
-	typesList := make([]string, 0, len(supportedTypes))
-	for searchType := range supportedTypes {
-		typesList = append(typesList, searchType)
-	}
+	// Get the enabled search types the providers support
+	typesList := t.offeredTypes()
+	defaultType := t.defaultType()
 
 	// Default provider based on priority order
 	var defaultProvider string
@@ -156,8 +214,8 @@ Search Types: %v`,
 	toolOptions := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("type",
-			mcp.Description("Search type"),
-			mcp.DefaultString("web"),
+			mcp.Description("Search type. Image, news, video and local results include their type's details in a field of the same name"),
+			mcp.DefaultString(defaultType),
 			mcp.Enum(enumValues...),
 		),
 		mcp.WithArray("query",
@@ -174,6 +232,26 @@ Search Types: %v`,
 			mcp.Description("Number of results per query (limits vary by provider & type)"),
 			mcp.DefaultNumber(5),
 		),
+		mcp.WithBoolean("dedupe",
+			mcp.Description("Leave out results that are the same page as an earlier result, or have the same title on the same site (default: true)"),
+		),
+		mcp.WithArray("include_domains",
+			mcp.Description("Only return results from these domains or their subdomains, e.g. [\"go.dev\", \"github.com\"]"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("exclude_domains",
+			mcp.Description("Leave out results from these domains or their subdomains"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("after",
+			mcp.Description("Only return results published on or after this date (YYYY-MM-DD or RFC 3339). Results without a publication date are left out"),
+		),
+		mcp.WithString("before",
+			mcp.Description("Only return results published before this time (RFC 3339), or up to the end of this date (YYYY-MM-DD). Results without a publication date are left out"),
+		),
+		mcp.WithNumber("fetch_top_n",
+			mcp.Description(fmt.Sprintf("Also fetch the pages of the top N results, taken from each query in turn, and return their content as markdown (0-%d, default: 0)", maxFetchTopN)),
+		),
 	}
 
 	// Add provider-specific parameters only if the provider is available
@@ -236,7 +314,10 @@ func (t *InternetSearchTool) Execute(ctx context.Context, logger *logrus.Logger,
 	// Parse search type (with default)
 	searchType, ok := args["type"].(string)
 	if !ok || searchType == "" {
-		searchType = "web"
+		searchType = t.defaultType()
+	}
+	if slices.Contains(internetsearch.SearchTypes, searchType) && !slices.Contains(enabledSearchTypes, searchType) {
+		return nil, fmt.Errorf("%s search is disabled, add %s to INTERNET_SEARCH_TYPES to enable it", searchType, searchType)
 	}
 
 	// Parse queries array
@@ -245,6 +326,18 @@ func (t *InternetSearchTool) Execute(ctx context.Context, logger *logrus.Logger,
 		return nil, err
 	}
 
+	filters, err := parseFilters(args)
+	if err != nil {
+		return nil, err
+	}
+	var fetcher tools.Tool
+	if filters.fetchTopN > 0 {
+		if fetcher, err = pageFetcher(); err != nil {
+			return nil, err
+		}
+	}
+	providerArgs := filters.providerArgs(args, time.Now())
+
 	// Determine if user explicitly requested a specific provider
 	userRequestedProvider := ""
 	if providerRaw, ok := args["provider"].(string); ok && providerRaw != "" {
@@ -268,7 +361,7 @@ func (t *InternetSearchTool) Execute(ctx context.Context, logger *logrus.Logger,
 	for range numWorkers {
 		wg.Go(func() {
 			for work := range queryChan {
-				result := t.executeSingleSearch(ctx, logger, work.query, searchType, providersToTry, userRequestedProvider, args)
+				result := t.executeSingleSearch(ctx, logger, work.query, searchType, providersToTry, userRequestedProvider, providerArgs, filters)
 				mu.Lock()
 				results[work.index] = result
 				mu.Unlock()
@@ -285,8 +378,14 @@ func (t *InternetSearchTool) Execute(ctx context.Context, logger *logrus.Logger,
 	// Wait for all queries to complete
 	wg.Wait()
 
+	// Fetch the top results' pages
+	var pages []internetsearch.FetchedPage
+	if fetcher != nil {
+		pages = fetchTopPages(ctx, logger, fetcher, results, filters.fetchTopN)
+	}
+
 	// Aggregate results
-	return t.aggregateResults(results)
+	return t.aggregateResults(searchType, results, pages)
 }
 
 // parseQueries extracts the queries array from args
@@ -331,7 +430,7 @@ func (t *InternetSearchTool) parseQueries(args map[string]any) ([]string, error)
 }
 
 // executeSingleSearch performs a search for a single query with provider fallback
-func (t *InternetSearchTool) executeSingleSearch(ctx context.Context, logger *logrus.Logger, query, searchType string, providersToTry []string, userRequestedProvider string, args map[string]any) internetsearch.QueryResult {
+func (t *InternetSearchTool) executeSingleSearch(ctx context.Context, logger *logrus.Logger, query, searchType string, providersToTry []string, userRequestedProvider string, args map[string]any, filters *resultFilters) internetsearch.QueryResult {
 	result := internetsearch.QueryResult{
 		Query:   query,
 		Results: []internetsearch.SearchResult{},
@@ -414,8 +513,8 @@ func (t *InternetSearchTool) executeSingleSearch(ctx context.Context, logger *lo
 			}
 		}
 
-		// Success - populate result
-		result.Results = response.Results
+		// Success - populate result with the results the filters keep
+		result.Results, result.Filtered = filters.apply(response.Results)
 		result.Provider = providerName
 		return result
 	}
@@ -425,7 +524,7 @@ func (t *InternetSearchTool) executeSingleSearch(ctx context.Context, logger *lo
 }
 
 // aggregateResults combines individual query results into a MultiSearchResponse
-func (t *InternetSearchTool) aggregateResults(results []internetsearch.QueryResult) (*mcp.CallToolResult, error) {
+func (t *InternetSearchTool) aggregateResults(searchType string, results []internetsearch.QueryResult, pages []internetsearch.FetchedPage) (*mcp.CallToolResult, error) {
 	successful := 0
 	failed := 0
 	var errors []string
@@ -445,13 +544,22 @@ func (t *InternetSearchTool) aggregateResults(results []internetsearch.QueryResu
 	}
 
 	response := internetsearch.MultiSearchResponse{
+		Type:     searchType,
 		Searches: results,
+		Pages:    pages,
 		Summary: internetsearch.SearchSummary{
 			Total:      len(results),
 			Successful: successful,
 			Failed:     failed,
 		},
 	}
+	for _, page := range pages {
+		if page.Error == "" {
+			response.Summary.PagesFetched++
+		} else {
+			response.Summary.PagesFailed++
+		}
+	}
 
 	return internetsearch.NewToolResultJSON(response)
 }
@@ -523,6 +631,15 @@ func (t *InternetSearchTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			},
 			ExpectedResult: "Returns 3 recent news articles about AI breakthroughs",
 		},
+		{
+			Description: "Search documentation sites and read the top results",
+			Arguments: map[string]any{
+				"query":           []string{"go generics type inference"},
+				"include_domains": []string{"go.dev", "github.com"},
+				"fetch_top_n":     2,
+			},
+			ExpectedResult: "Returns results from go.dev and github.com only, with the markdown content of the top 2 pages",
+		},
 		{
 			Description: "Image search with specific provider",
 			Arguments: map[string]any{
@@ -566,7 +683,7 @@ func (t *InternetSearchTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 
 	commonPatterns := []string{
 		"Use count parameter to control result volume (more results = more context but higher latency)",
-		"Combine with fetch_url tool to get full content from interesting search results",
+		"Combine with fetch_url tool to get full content from interesting search results, or set fetch_top_n to fetch the top results' pages in the same call",
 		"For research workflows: search → analyse results → fetch detailed content → store in memory",
 		"Automatic fallback: If the default provider fails, the tool automatically tries other available providers",
 	}
@@ -584,18 +701,7 @@ func (t *InternetSearchTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 	}
 
 	// Add search type guidance based on available providers
-	supportedTypes := make(map[string]bool)
-	for _, provider := range t.providers {
-		for _, searchType := range provider.GetSupportedTypes() {
-			supportedTypes[searchType] = true
-		}
-	}
-
-	if len(supportedTypes) > 1 {
-		var types []string
-		for searchType := range supportedTypes {
-			types = append(types, searchType)
-		}
+	if types := t.offeredTypes(); len(types) > 1 {
 		commonPatterns = append(commonPatterns, fmt.Sprintf("Available search types with current providers: %s", strings.Join(types, ", ")))
 	}
 
@@ -653,10 +759,15 @@ func (t *InternetSearchTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 
 	parameterDetails := map[string]string{
 		"query": "The search query should be descriptive but not too long. Use natural language rather than keyword stuffing.",
-		"type":  "Internet search is default and most versatile. Use 'news' for current events, 'image' for visual content, 'video' for tutorials.",
-		"count": "More results provide broader coverage but increase latency. Typical range: 3-10 results for focused searches, 10-20 for research.",
+		"type":  "Internet search is default and most versatile. Use 'news' for current events, 'image' for visual content, 'video' for tutorials. Image, news, video and local results describe the image, article, video or place in an 'image', 'news', 'video' or 'local' field. Which types are offered is set by INTERNET_SEARCH_TYPES; local search is disabled by default.",
+		"count": "More results provide broader coverage but increase latency. Typical range: 3-10 results for focused searches, 10-20 for research. Filters are applied to the results the provider returns, so fewer than count may be left.",
 	}
 
+	// Add details of the filters, which apply to every provider
+	parameterDetails["include_domains"] = "Matches the domain and its subdomains, so 'github.com' includes 'docs.github.com'. Results left out are counted in each search's 'filtered'. To search only one site, adding 'site:example.com' to the query returns more results than filtering."
+	parameterDetails["after"] = "Compared with each result's 'published' date. Web results often have no date, and are left out and counted as undated; news search dates most results. Brave is asked for the same range as its freshness, unless freshness is given."
+	parameterDetails["fetch_top_n"] = fmt.Sprintf("Pages are fetched with fetch_url, in parallel, and the first %d characters of each are returned in 'pages'. Use fetch_url with start_index to read further.", fetchedPageLength)
+
 	// Build provider description based on available providers
 	var providerDescriptions []string
 	if t.hasProvider("brave") {
@@ -705,3 +816,36 @@ func (t *InternetSearchTool) hasProvider(providerName string) bool {
 	_, exists := t.providers[providerName]
 	return exists
 }
+
+// CheckPrerequisites reports which search providers are configured
+func (t *InternetSearchTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	var available []string
+	for _, name := range providerPriorityOrder {
+		if t.hasProvider(name) {
+			available = append(available, name)
+		}
+	}
+
+	check := tools.PrerequisiteCheck{
+		Name:   "search providers",
+		Status: tools.PrerequisiteOK,
+		Detail: "using " + strings.Join(available, ", "),
+	}
+	if len(available) == 1 && available[0] == "duckduckgo" {
+		check.Status = tools.PrerequisiteWarning
+		check.Detail = "only DuckDuckGo is available, which is heavily rate limited"
+		check.Remediation = "Set BRAVE_API_KEY, KAGI_API_KEY, SEARXNG_BASE_URL, or GOOGLE_SEARCH_API_KEY and GOOGLE_SEARCH_ID"
+	}
+	checks := []tools.PrerequisiteCheck{check}
+
+	// Google needs both variables, so one on its own is likely a mistake
+	if (os.Getenv("GOOGLE_SEARCH_API_KEY") == "") != (os.Getenv("GOOGLE_SEARCH_ID") == "") {
+		checks = append(checks, tools.PrerequisiteCheck{
+			Name:        "google",
+			Status:      tools.PrerequisiteWarning,
+			Detail:      "only one of GOOGLE_SEARCH_API_KEY and GOOGLE_SEARCH_ID is set, so Google search is disabled",
+			Remediation: "Set both GOOGLE_SEARCH_API_KEY and GOOGLE_SEARCH_ID",
+		})
+	}
+	return checks
+}
@@ -0,0 +1,342 @@
+package unified
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/internetsearch"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxFetchTopN is the most result pages fetch_top_n may fetch
+	maxFetchTopN = 5
+	// fetchedPageLength is the most characters of each fetched page returned
+	fetchedPageLength = 4000
+	// fetchPageTimeout limits how long each page's fetch may take, including converting a document
+	fetchPageTimeout = time.Minute
+)
+
+// trackingParams are query parameters that identify where a link was shared, not the page it links to
+var trackingParams = []string{"fbclid", "gclid", "msclkid", "ref", "ref_src", "mc_cid", "mc_eid"}
+
+// resultFilters are the post-processing options applied to each query's results
+type resultFilters struct {
+	dedupe         bool
+	includeDomains []string
+	excludeDomains []string
+	// after is the earliest publication time kept, and before the time from which results are left out
+	after  *time.Time
+	before *time.Time
+	// fetchTopN is the number of top results whose pages are fetched
+	fetchTopN int
+}
+
+// parseFilters reads the post-processing options from the tool's arguments
+func parseFilters(args map[string]any) (*resultFilters, error) {
+	filters := &resultFilters{dedupe: true}
+	if dedupe, ok := args["dedupe"].(bool); ok {
+		filters.dedupe = dedupe
+	}
+
+	var err error
+	if filters.includeDomains, err = parseDomains(args, "include_domains"); err != nil {
+		return nil, err
+	}
+	if filters.excludeDomains, err = parseDomains(args, "exclude_domains"); err != nil {
+		return nil, err
+	}
+
+	if filters.after, err = parseDateArg(args, "after", false); err != nil {
+		return nil, err
+	}
+	if filters.before, err = parseDateArg(args, "before", true); err != nil {
+		return nil, err
+	}
+	if filters.after != nil && filters.before != nil && !filters.after.Before(*filters.before) {
+		return nil, fmt.Errorf("after must be earlier than before")
+	}
+
+	if n, ok := args["fetch_top_n"].(float64); ok {
+		if n < 0 || n > maxFetchTopN || n != float64(int(n)) {
+			return nil, fmt.Errorf("fetch_top_n must be a whole number from 0 to %d, got: %v", maxFetchTopN, n)
+		}
+		filters.fetchTopN = int(n)
+	}
+	return filters, nil
+}
+
+// parseDomains reads a list of domains, reduced to their host names without "www."
+func parseDomains(args map[string]any, key string) ([]string, error) {
+	var values []string
+	switch v := args[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		values = v
+	case []any:
+		for _, item := range v {
+			value, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be an array of domains", key)
+			}
+			values = append(values, value)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be an array of domains (e.g., [\"go.dev\"])", key)
+	}
+
+	domains := make([]string, 0, len(values))
+	for _, value := range values {
+		domain := strings.ToLower(strings.TrimSpace(value))
+		if strings.Contains(domain, "://") {
+			if parsed, err := url.Parse(domain); err == nil {
+				domain = parsed.Hostname()
+			}
+		}
+		domain, _, _ = strings.Cut(domain, "/")
+		domain = strings.TrimPrefix(strings.TrimSuffix(domain, "."), "www.")
+		if domain == "" {
+			return nil, fmt.Errorf("%s contains an empty domain", key)
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// parseDateArg reads an RFC 3339 timestamp or a date (YYYY-MM-DD, in UTC). A date used as an end
+// includes the whole of that day.
+func parseDateArg(args map[string]any, key string, end bool) (*time.Time, error) {
+	value, _ := args[key].(string)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp, got: %s", key, value)
+	}
+	if end {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, nil
+}
+
+// providerArgs returns the arguments passed to providers. A date range is passed to Brave as its
+// freshness, unless one is given, so that it searches the range rather than only filtering its results.
+func (f *resultFilters) providerArgs(args map[string]any, now time.Time) map[string]any {
+	if f.after == nil && f.before == nil {
+		return args
+	}
+	if freshness, _ := args["freshness"].(string); freshness != "" {
+		return args
+	}
+
+	from := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	if f.after != nil {
+		from = *f.after
+	}
+	to := now
+	if f.before != nil {
+		// before is exclusive, so the range ends on the day before it
+		to = f.before.Add(-time.Nanosecond)
+	}
+	providerArgs := make(map[string]any, len(args)+1)
+	maps.Copy(providerArgs, args)
+	providerArgs["freshness"] = from.UTC().Format("2006-01-02") + "to" + to.UTC().Format("2006-01-02")
+	return providerArgs
+}
+
+// apply returns the results the filters keep, in their order, and counts those left out
+func (f *resultFilters) apply(results []internetsearch.SearchResult) ([]internetsearch.SearchResult, *internetsearch.FilteredCounts) {
+	counts := &internetsearch.FilteredCounts{}
+	kept := make([]internetsearch.SearchResult, 0, len(results))
+	seen := make(map[string]bool)
+
+	for _, result := range results {
+		host := resultHost(result.URL)
+		if (len(f.includeDomains) > 0 && !matchesDomain(host, f.includeDomains)) || matchesDomain(host, f.excludeDomains) {
+			counts.Domain++
+			continue
+		}
+
+		if f.after != nil || f.before != nil {
+			switch {
+			case result.Published == nil:
+				counts.Undated++
+				continue
+			case f.after != nil && result.Published.Before(*f.after), f.before != nil && !result.Published.Before(*f.before):
+				counts.OutOfRange++
+				continue
+			}
+		}
+
+		if f.dedupe {
+			keys := []string{"url:" + canonicalURL(result.URL)}
+			if title := normaliseTitle(result.Title); title != "" {
+				keys = append(keys, "title:"+host+"|"+title)
+			}
+			if slices.ContainsFunc(keys, func(key string) bool { return seen[key] }) {
+				counts.Duplicates++
+				continue
+			}
+			for _, key := range keys {
+				seen[key] = true
+			}
+		}
+
+		kept = append(kept, result)
+	}
+
+	if *counts == (internetsearch.FilteredCounts{}) {
+		return kept, nil
+	}
+	return kept, counts
+}
+
+// resultHost returns a result's host name, without "www." or "m."
+func resultHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, prefix := range []string{"www.", "m."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+// matchesDomain reports whether a host is one of the domains or a subdomain of one
+func matchesDomain(host string, domains []string) bool {
+	if host == "" {
+		return false
+	}
+	return slices.ContainsFunc(domains, func(domain string) bool {
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	})
+}
+
+// canonicalURL returns a URL without the differences that do not change the page: its scheme, "www.",
+// a trailing slash, its fragment and tracking parameters
+func canonicalURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := parsed.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || slices.Contains(trackingParams, strings.ToLower(key)) {
+			query.Del(key)
+		}
+	}
+	canonical := resultHost(rawURL) + strings.TrimSuffix(parsed.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+	return canonical
+}
+
+// normaliseTitle returns a title's words in lower case, without punctuation
+func normaliseTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// fetchTopPages fetches the pages of the top n results with fetch_url. Results are taken from each query
+// in turn, so that every query contributes, and a page found by more than one query is fetched once.
+func fetchTopPages(ctx context.Context, logger *logrus.Logger, fetcher tools.Tool, searches []internetsearch.QueryResult, n int) []internetsearch.FetchedPage {
+	var pages []internetsearch.FetchedPage
+	fetched := make(map[string]bool)
+	for rank := 0; len(pages) < n; rank++ {
+		found := false
+		for _, search := range searches {
+			if rank >= len(search.Results) || len(pages) >= n {
+				continue
+			}
+			found = true
+			result := search.Results[rank]
+			if key := canonicalURL(result.URL); !fetched[key] {
+				fetched[key] = true
+				pages = append(pages, internetsearch.FetchedPage{Query: search.Query, Title: result.Title, URL: result.URL})
+			}
+		}
+		if !found {
+			break
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range pages {
+		wg.Go(func() {
+			fetchPage(ctx, logger, fetcher, &pages[i])
+		})
+	}
+	wg.Wait()
+	return pages
+}
+
+// fetchPage fills in a page's content, or the error fetching it. The fetch goes through toolcall, so it
+// is authorised for the caller like a direct fetch_url call, and a document found by the search waits
+// for a document worker to convert it.
+func fetchPage(ctx context.Context, logger *logrus.Logger, fetcher tools.Tool, page *internetsearch.FetchedPage) {
+	output, err := toolcall.Call(ctx, logger, "fetch_url", fetcher, map[string]any{
+		"url":        page.URL,
+		"max_length": float64(fetchedPageLength),
+	}, fetchPageTimeout)
+	if err != nil {
+		page.Error = err.Error()
+		return
+	}
+	if output == "" {
+		page.Error = "fetch_url returned no content"
+		return
+	}
+
+	var fetched struct {
+		Content        string `json:"content"`
+		Truncated      bool   `json:"truncated"`
+		TotalLength    int    `json:"total_length"`
+		StatusCode     int    `json:"status_code"`
+		SecurityNotice string `json:"security_notice"`
+	}
+	if err := json.Unmarshal([]byte(output), &fetched); err != nil {
+		page.Content = output
+		return
+	}
+	if fetched.StatusCode >= 400 {
+		page.Error = fmt.Sprintf("HTTP %d", fetched.StatusCode)
+		return
+	}
+	page.Content = fetched.Content
+	page.Truncated = fetched.Truncated
+	page.TotalLength = fetched.TotalLength
+	page.SecurityNotice = fetched.SecurityNotice
+
+	logger.WithFields(logrus.Fields{"url": page.URL, "length": len(page.Content)}).Debug("Fetched search result page")
+}
+
+// pageFetcher returns the fetch_url tool used by fetch_top_n
+func pageFetcher() (tools.Tool, error) {
+	fetcher, ok := registry.GetTool("fetch_url")
+	if !ok {
+		return nil, fmt.Errorf("fetch_top_n needs the fetch_url tool, which is disabled")
+	}
+	return fetcher, nil
+}
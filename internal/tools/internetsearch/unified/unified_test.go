@@ -7,7 +7,10 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
 	"github.com/sammcj/mcp-devtools/internal/tools/internetsearch"
 	"github.com/sirupsen/logrus"
 )
@@ -413,3 +416,201 @@ func TestExecute_NoProvidersSupport(t *testing.T) {
 		t.Errorf("Expected error to mention no providers support type, got: %s", err.Error())
 	}
 }
+
+// Test parsing of the INTERNET_SEARCH_TYPES list
+func TestParseSearchTypes(t *testing.T) {
+	tests := map[string]string{
+		"":                 "web,image,news,video",
+		"local, WEB":       "web,local",
+		"all":              "web,image,news,video,local",
+		"news,unknown":     "news",
+		"unknown,mistyped": "web,image,news,video",
+	}
+	for value, expected := range tests {
+		if got := strings.Join(parseSearchTypes(value), ","); got != expected {
+			t.Errorf("parseSearchTypes(%q) = %q, expected %q", value, got, expected)
+		}
+	}
+}
+
+// Test that disabled search types are neither offered nor searched
+func TestDisabledSearchType(t *testing.T) {
+	original := enabledSearchTypes
+	t.Cleanup(func() { enabledSearchTypes = original })
+	enabledSearchTypes = parseSearchTypes("news,video")
+
+	braveProvider := &mockProvider{
+		name:           "brave",
+		supportedTypes: []string{"web", "image", "news", "video", "local"},
+	}
+	tool := &InternetSearchTool{
+		providers: map[string]SearchProvider{"brave": braveProvider},
+	}
+
+	if offered := strings.Join(tool.offeredTypes(), ","); offered != "news,video" {
+		t.Errorf("Expected news and video to be offered, got %q", offered)
+	}
+	// With web search disabled, the first enabled type is the default
+	if tool.defaultType() != "news" {
+		t.Errorf("Expected news to be the default type, got %q", tool.defaultType())
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	_, err := tool.Execute(context.Background(), logger, &sync.Map{}, map[string]any{
+		"query": []any{"coffee"},
+		"type":  "local",
+	})
+	if err == nil || !strings.Contains(err.Error(), "INTERNET_SEARCH_TYPES") {
+		t.Errorf("Expected an error naming INTERNET_SEARCH_TYPES, got: %v", err)
+	}
+	if braveProvider.callCount != 0 {
+		t.Errorf("Expected no search for a disabled type, got %d", braveProvider.callCount)
+	}
+}
+
+// Test that results are deduplicated and filtered by domain and date
+func TestResultFilters(t *testing.T) {
+	date := func(value string) *time.Time {
+		parsed, _ := time.Parse("2006-01-02", value)
+		return &parsed
+	}
+	results := []internetsearch.SearchResult{
+		{Title: "Effective Go", URL: "https://go.dev/doc/effective_go", Published: date("2024-03-01")},
+		{Title: "Effective Go (copy)", URL: "http://www.go.dev/doc/effective_go/?utm_source=feed#top", Published: date("2024-03-01")},
+		{Title: "Effective  go!", URL: "https://go.dev/doc/effective_go?print=1", Published: date("2024-03-02")},
+		{Title: "Go wiki", URL: "https://go.dev/wiki", Published: date("2023-01-01")},
+		{Title: "Go issues", URL: "https://github.com/golang/go/issues", Published: date("2024-05-01")},
+		{Title: "Go discussion", URL: "https://news.ycombinator.com/item?id=1"},
+	}
+
+	filters, err := parseFilters(map[string]any{})
+	if err != nil {
+		t.Fatalf("Expected default filters, got error: %v", err)
+	}
+	kept, counts := filters.apply(results)
+	if len(kept) != 4 || counts == nil || counts.Duplicates != 2 {
+		t.Errorf("Expected the copy and the same titled page to be duplicates, kept %d with counts %+v", len(kept), counts)
+	}
+
+	filters, err = parseFilters(map[string]any{
+		"dedupe":          false,
+		"exclude_domains": []any{"https://www.github.com/golang"},
+		"after":           "2024-01-01",
+		"before":          "2024-03-01",
+	})
+	if err != nil {
+		t.Fatalf("Expected filters, got error: %v", err)
+	}
+	kept, counts = filters.apply(results)
+	if len(kept) != 2 || kept[0].Title != "Effective Go" || kept[1].Title != "Effective Go (copy)" {
+		t.Errorf("Expected the two pages published on 1 March, got %+v", kept)
+	}
+	if counts == nil || counts.Domain != 1 || counts.OutOfRange != 2 || counts.Undated != 1 {
+		t.Errorf("Unexpected filtered counts: %+v", counts)
+	}
+
+	// The date range is passed to Brave as its freshness
+	args := filters.providerArgs(map[string]any{"query": "go"}, time.Now())
+	if args["freshness"] != "2024-01-01to2024-03-01" {
+		t.Errorf("Expected a freshness range, got %v", args["freshness"])
+	}
+
+	filters, _ = parseFilters(map[string]any{"include_domains": []any{"github.com"}})
+	if kept, _ = filters.apply(results); len(kept) != 1 || kept[0].Title != "Go issues" {
+		t.Errorf("Expected only the GitHub result, got %+v", kept)
+	}
+
+	for _, args := range []map[string]any{
+		{"after": "last year"},
+		{"after": "2024-02-01", "before": "2024-01-01"},
+		{"fetch_top_n": float64(6)},
+		{"include_domains": "go.dev"},
+	} {
+		if _, err := parseFilters(args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
+
+// fakeFetcher stands in for fetch_url, returning each page's URL as its content
+type fakeFetcher struct {
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (f *fakeFetcher) Definition() mcp.Tool {
+	return mcp.NewTool("fetch_url")
+}
+
+func (f *fakeFetcher) Execute(_ context.Context, _ *logrus.Logger, _ *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	pageURL := args["url"].(string)
+	f.mu.Lock()
+	f.fetched = append(f.fetched, pageURL)
+	f.mu.Unlock()
+	if strings.HasSuffix(pageURL, "/missing") {
+		return mcp.NewToolResultText(`{"content": "Not found", "status_code": 404}`), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(`{"content": "Page at %s", "truncated": true, "total_length": 9000}`, pageURL)), nil
+}
+
+// Test that the top results' pages are fetched from each query in turn
+func TestFetchTopPages(t *testing.T) {
+	searches := []internetsearch.QueryResult{
+		{Query: "first", Results: []internetsearch.SearchResult{
+			{Title: "A", URL: "https://example.com/a"},
+			{Title: "B", URL: "https://example.com/missing"},
+		}},
+		{Query: "second", Results: []internetsearch.SearchResult{
+			{Title: "A again", URL: "https://www.example.com/a/"},
+			{Title: "C", URL: "https://example.com/c"},
+		}},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	fetcher := &fakeFetcher{}
+	pages := fetchTopPages(context.Background(), logger, fetcher, searches, 3)
+
+	if len(pages) != 3 || len(fetcher.fetched) != 3 {
+		t.Fatalf("Expected 3 pages fetched, got %d pages and %d fetches", len(pages), len(fetcher.fetched))
+	}
+	// The second query's first result is the first query's, so its second result is fetched instead
+	if pages[0].URL != "https://example.com/a" || pages[1].URL != "https://example.com/missing" || pages[2].URL != "https://example.com/c" {
+		t.Errorf("Unexpected pages: %+v", pages)
+	}
+	if pages[0].Content != "Page at https://example.com/a" || !pages[0].Truncated || pages[0].TotalLength != 9000 {
+		t.Errorf("Unexpected page content: %+v", pages[0])
+	}
+	if pages[1].Error != "HTTP 404" {
+		t.Errorf("Expected the missing page to fail, got %+v", pages[1])
+	}
+	if pages[2].Query != "second" {
+		t.Errorf("Expected the last page to come from the second query, got %q", pages[2].Query)
+	}
+}
+
+// Test that a caller who may search but not use fetch_url cannot fetch pages through fetch_top_n
+func TestFetchTopPages_NeedsFetchURLAccess(t *testing.T) {
+	policy, err := access.ParsePolicy([]byte("default:\n  tools: [internet_search]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	access.SetPolicy(policy)
+	t.Cleanup(func() { access.SetPolicy(nil) })
+
+	searches := []internetsearch.QueryResult{
+		{Query: "first", Results: []internetsearch.SearchResult{{Title: "A", URL: "https://example.com/a"}}},
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	fetcher := &fakeFetcher{}
+	pages := fetchTopPages(context.Background(), logger, fetcher, searches, 1)
+
+	if len(fetcher.fetched) != 0 {
+		t.Errorf("Expected no pages fetched, got %v", fetcher.fetched)
+	}
+	if len(pages) != 1 || !strings.Contains(pages[0].Error, "not permitted to use the fetch_url tool") {
+		t.Errorf("Expected the page to be refused, got %+v", pages)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,31 @@ const (
 	InternetSearchRateLimitEnvVar = "INTERNET_SEARCH_RATE_LIMIT"
 )
 
+// dateLayouts are the layouts of the publication dates providers return
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseDate parses a provider's publication date, returning nil if it is empty or not understood.
+// Dates without a time zone are taken to be UTC.
+func ParseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
 // NewToolResultJSON creates a new tool result with JSON content
 func NewToolResultJSON(data any) (*mcp.CallToolResult, error) {
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
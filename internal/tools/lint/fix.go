@@ -0,0 +1,194 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/textdiff"
+)
+
+const (
+	// maxFixFileSize is the largest file autofixes are run on
+	maxFixFileSize = 2 * 1024 * 1024
+
+	// maxFixFiles is the most files fixed in one call
+	maxFixFiles = 200
+
+	// maxDiffBytes is the most diff text returned. Changes beyond it are listed without their diff.
+	maxDiffBytes = 200 * 1024
+)
+
+// fixedFile is a file and its content after autofixes
+type fixedFile struct {
+	file     string
+	path     string
+	mode     os.FileMode
+	original []byte
+	content  []byte
+	linters  []string
+}
+
+// fix runs each linter's fixer over the files it reported fixable problems in, and returns the
+// files that changed. Each fixer is given the content the one before it produced, so formatters
+// format the other linters' fixes. Nothing is written.
+func fix(ctx context.Context, dir string, runs []*linterRun) ([]*fixedFile, []string) {
+	var notes []string
+	files := map[string]*fixedFile{}
+	var order []string
+	skipped := 0
+
+	for _, r := range runs {
+		if r.Status != "ran" {
+			continue
+		}
+		fixable := fixableFiles(r.diagnostics)
+		if r.linter.fix == nil {
+			if len(fixable) > 0 {
+				notes = append(notes, fmt.Sprintf("%s can only fix files in place, so its fixes are not previewed or applied. Run %s run --fix to apply them.", r.Name, r.Name))
+			}
+			continue
+		}
+		for _, file := range fixable {
+			f, seen := files[file]
+			if !seen {
+				if len(order) >= maxFixFiles {
+					skipped++
+					continue
+				}
+				var err error
+				if f, err = load(dir, file); err != nil {
+					notes = append(notes, err.Error())
+				}
+				// A file that could not be loaded is remembered so it is only reported once
+				files[file] = f
+				if f == nil {
+					continue
+				}
+				order = append(order, file)
+			}
+			if f == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				notes = append(notes, "The timeout was reached before all fixes were made.")
+				return changed(files, order), notes
+			}
+
+			out, err := execute(ctx, dir, r.binary, r.linter.fix(file), f.content)
+			if err == nil && out.exitCode > 1 {
+				err = fmt.Errorf("%s", out.problem())
+			}
+			content := out.stdout
+			if err == nil && r.linter.fixed != nil {
+				content, err = r.linter.fixed(out.stdout)
+			}
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("%s could not fix %s: %v", r.Name, file, err))
+				continue
+			}
+			// An empty result for a file that was not empty is a fixer failing quietly, not a fix
+			if content == nil || (len(content) == 0 && len(f.content) > 0) || bytes.Equal(content, f.content) {
+				continue
+			}
+			f.content = content
+			f.linters = append(f.linters, r.Name)
+		}
+	}
+	if skipped > 0 {
+		notes = append(notes, fmt.Sprintf("Only the first %d files were fixed; %d more have fixable problems.", maxFixFiles, skipped))
+	}
+	return changed(files, order), notes
+}
+
+// fixableFiles lists the files with fixable diagnostics, in the order they were reported
+func fixableFiles(diagnostics []Diagnostic) []string {
+	var files []string
+	seen := map[string]bool{}
+	for _, d := range diagnostics {
+		if d.Fixable && !seen[d.File] {
+			seen[d.File] = true
+			files = append(files, d.File)
+		}
+	}
+	return files
+}
+
+// load reads a file to fix. Files outside the project, links and large files are left alone.
+func load(dir, file string) (*fixedFile, error) {
+	if filepath.IsAbs(filepath.FromSlash(file)) || file == ".." || strings.HasPrefix(file, "../") {
+		return nil, fmt.Errorf("skipped %s: it is outside the project directory", file)
+	}
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	if err := security.CheckFileAccess(path); err != nil {
+		return nil, fmt.Errorf("skipped %s: %v", file, err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("skipped %s: %v", file, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("skipped %s: not a regular file", file)
+	}
+	if info.Size() > maxFixFileSize {
+		return nil, fmt.Errorf("skipped %s: larger than %d MB", file, maxFixFileSize/1024/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("skipped %s: %v", file, err)
+	}
+	return &fixedFile{file: file, path: path, mode: info.Mode().Perm(), original: data, content: data}, nil
+}
+
+// changed returns the files whose content was changed, in order
+func changed(files map[string]*fixedFile, order []string) []*fixedFile {
+	var result []*fixedFile
+	for _, file := range order {
+		if f := files[file]; f != nil && !bytes.Equal(f.original, f.content) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// describe returns the changes as unified diffs, leaving out diffs beyond maxDiffBytes
+func describe(files []*fixedFile) ([]Change, bool) {
+	var changes []Change
+	size, truncated := 0, false
+	for _, f := range files {
+		diff, additions, deletions := textdiff.UnifiedDiff(string(f.original), string(f.content), "a/"+f.file, "b/"+f.file, 3)
+		change := Change{File: f.file, Linters: f.linters, Additions: additions, Deletions: deletions}
+		if size+len(diff) <= maxDiffBytes {
+			change.Diff = diff
+			size += len(diff)
+		} else {
+			truncated = true
+		}
+		changes = append(changes, change)
+	}
+	return changes, truncated
+}
+
+// apply writes the fixed files. A file that changed on disk since it was read is left alone, so
+// edits made while the linters ran are not lost.
+func apply(files []*fixedFile) (int, []string) {
+	written := 0
+	var notes []string
+	for _, f := range files {
+		current, err := os.ReadFile(f.path)
+		if err != nil || !bytes.Equal(current, f.original) {
+			notes = append(notes, fmt.Sprintf("Did not fix %s: it changed while the linters ran.", f.file))
+			continue
+		}
+		if err := os.WriteFile(f.path, f.content, f.mode); err != nil {
+			notes = append(notes, fmt.Sprintf("Failed to write %s: %v", f.file, err))
+			continue
+		}
+		written++
+	}
+	return written, notes
+}
@@ -0,0 +1,192 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// goErrorPattern matches a syntax error from gofmt, such as "main.go:3:1: expected declaration"
+	goErrorPattern = regexp.MustCompile(`^(.+?\.go):(\d+):(\d+): (.*)$`)
+
+	// golangciVersionPattern reads the major version from golangci-lint --version
+	golangciVersionPattern = regexp.MustCompile(`version v?(\d+)\.`)
+)
+
+// gofmt reports Go files that are not formatted and formats them
+var gofmt = linter{
+	name:     "gofmt",
+	language: "go",
+	find: func(dir string) (string, string) {
+		if binary := goBinary("gofmt"); binary != "" {
+			return binary, ""
+		}
+		return "", "gofmt was not found. It is installed with Go"
+	},
+	check: func(_ context.Context, _, _ string, targets []string) []string {
+		return append([]string{"-l"}, targets...)
+	},
+	parse: parseGofmt,
+	// gofmt formats stdin to stdout when given no files
+	fix: func(string) []string { return []string{} },
+}
+
+// parseGofmt reads the files gofmt -l lists and the syntax errors it reports
+func parseGofmt(out output, dir string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for line := range strings.Lines(string(out.stdout)) {
+		file := relative(dir, strings.TrimSpace(line))
+		if file == "" || skippedGoPath(file) {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Linter:   "gofmt",
+			File:     file,
+			Severity: "warning",
+			Rule:     "format",
+			Message:  "File is not formatted with gofmt",
+			Fixable:  true,
+		})
+	}
+	for line := range strings.Lines(string(out.stderr)) {
+		match := goErrorPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil || skippedGoPath(relative(dir, match[1])) {
+			continue
+		}
+		d := Diagnostic{Linter: "gofmt", File: relative(dir, match[1]), Severity: "error", Rule: "syntax", Message: match[4]}
+		d.Line, _ = strconv.Atoi(match[2])
+		d.Column, _ = strconv.Atoi(match[3])
+		diagnostics = append(diagnostics, d)
+	}
+	if out.exitCode != 0 && len(diagnostics) == 0 {
+		return nil, fmt.Errorf("%s", out.problem())
+	}
+	return diagnostics, nil
+}
+
+// skippedGoPath reports whether a file is in a directory the go command ignores, which gofmt
+// walks into all the same
+func skippedGoPath(file string) bool {
+	parts := strings.Split(path.Dir(file), "/")
+	return slices.ContainsFunc(parts, func(part string) bool {
+		return part == "vendor" || part == "testdata" || part == "node_modules" ||
+			(len(part) > 1 && (strings.HasPrefix(part, ".") || strings.HasPrefix(part, "_")) && part != "..")
+	})
+}
+
+// golangciLint runs golangci-lint with the project's configuration. It can only fix files in place,
+// so its fixes are reported but not previewed or applied.
+var golangciLint = linter{
+	name:     "golangci-lint",
+	language: "go",
+	find: func(dir string) (string, string) {
+		if binary := goBinary("golangci-lint"); binary != "" {
+			return binary, ""
+		}
+		return "", "golangci-lint is not installed. See https://golangci-lint.run/welcome/install/"
+	},
+	check: func(ctx context.Context, dir, binary string, targets []string) []string {
+		args := []string{"run", "--out-format=json"}
+		if version, err := execute(ctx, dir, binary, []string{"--version"}, nil); err == nil {
+			if match := golangciVersionPattern.FindSubmatch(version.stdout); match != nil && string(match[1]) != "1" {
+				args = []string{"run", "--output.json.path=stdout", "--show-stats=false"}
+			}
+		}
+		args = append(args, "--max-issues-per-linter=0", "--max-same-issues=0")
+		return append(args, goPackages(dir, targets)...)
+	},
+	parse: parseGolangci,
+}
+
+// goPackages turns targets into package patterns: directories and everything below them, and the
+// packages of files
+func goPackages(dir string, targets []string) []string {
+	var patterns []string
+	for _, target := range targets {
+		pattern := filepath.ToSlash(filepath.Clean(target))
+		if info, err := os.Stat(filepath.Join(dir, target)); err == nil && !info.IsDir() {
+			pattern = path.Dir(pattern)
+		} else {
+			pattern = path.Join(pattern, "...")
+		}
+		if !strings.HasPrefix(pattern, ".") && !path.IsAbs(pattern) {
+			pattern = "./" + pattern
+		}
+		if !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// golangciReport is the part of golangci-lint's JSON output that lists issues
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+		// Replacement (version 1) and SuggestedFixes (version 2) are set for fixable issues
+		Replacement    json.RawMessage `json:"Replacement"`
+		SuggestedFixes json.RawMessage `json:"SuggestedFixes"`
+	} `json:"Issues"`
+}
+
+// parseGolangci reads golangci-lint's JSON report, which version 2 may follow with other output
+func parseGolangci(out output, dir string) ([]Diagnostic, error) {
+	start := bytes.IndexByte(out.stdout, '{')
+	if start < 0 {
+		if out.exitCode == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s", out.problem())
+	}
+	var report golangciReport
+	if err := json.NewDecoder(bytes.NewReader(out.stdout[start:])).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse golangci-lint output: %w", err)
+	}
+	if out.exitCode > 1 && len(report.Issues) == 0 {
+		return nil, fmt.Errorf("%s", out.problem())
+	}
+
+	var diagnostics []Diagnostic
+	for _, issue := range report.Issues {
+		severity := "warning"
+		switch {
+		case issue.FromLinter == "typecheck" || strings.EqualFold(issue.Severity, "error"):
+			severity = "error"
+		case strings.EqualFold(issue.Severity, "info"):
+			severity = "info"
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Linter:   "golangci-lint",
+			File:     relative(dir, issue.Pos.Filename),
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+			Severity: severity,
+			Rule:     issue.FromLinter,
+			Message:  issue.Text,
+			Fixable:  present(issue.Replacement) || present(issue.SuggestedFixes),
+		})
+	}
+	return diagnostics, nil
+}
+
+// present reports whether a JSON value is set and not empty
+func present(raw json.RawMessage) bool {
+	value := string(bytes.TrimSpace(raw))
+	return value != "" && value != "null" && value != "[]"
+}
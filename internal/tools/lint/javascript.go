@@ -0,0 +1,178 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// prettierErrorPattern matches a file prettier could not parse, such as
+// "[error] src/app.js: SyntaxError: Unexpected token (3:5)"
+var prettierErrorPattern = regexp.MustCompile(`^\[error\] ([^\s:]+): (.+?)(?: \((\d+):(\d+)\))?$`)
+
+// eslint runs ESLint with the project's configuration and installed plugins
+var eslint = linter{
+	name:     "eslint",
+	language: "javascript",
+	find: func(dir string) (string, string) {
+		binary := nodeBinary(dir, "eslint")
+		if binary == "" {
+			return "", "eslint is not installed in node_modules"
+		}
+		if findUp(dir, "eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts", "eslint.config.mts",
+			".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml", ".eslintrc") == "" &&
+			!packageJSONHas(dir, "eslintConfig") {
+			return "", "no ESLint configuration found"
+		}
+		return binary, ""
+	},
+	check: func(_ context.Context, _, _ string, targets []string) []string {
+		return append([]string{"--format", "json"}, targets...)
+	},
+	parse: parseESLint,
+	fix: func(file string) []string {
+		return []string{"--stdin", "--stdin-filename", file, "--fix-dry-run", "--format", "json"}
+	},
+	fixed: func(stdout []byte) ([]byte, error) {
+		var files []eslintFile
+		if err := json.Unmarshal(stdout, &files); err != nil {
+			return nil, fmt.Errorf("failed to parse eslint output: %w", err)
+		}
+		// output is only set when fixes change the file
+		if len(files) == 0 || files[0].Output == nil {
+			return nil, nil
+		}
+		return []byte(*files[0].Output), nil
+	},
+}
+
+// eslintFile is a file's entry in ESLint's JSON output
+type eslintFile struct {
+	FilePath string `json:"filePath"`
+	Messages []struct {
+		RuleID   *string         `json:"ruleId"`
+		Severity int             `json:"severity"`
+		Message  string          `json:"message"`
+		Line     int             `json:"line"`
+		Column   int             `json:"column"`
+		Fix      json.RawMessage `json:"fix"`
+	} `json:"messages"`
+	Output *string `json:"output"`
+}
+
+// parseESLint reads ESLint's JSON output. Parsing errors have no rule.
+func parseESLint(out output, dir string) ([]Diagnostic, error) {
+	var files []eslintFile
+	if err := json.Unmarshal(out.stdout, &files); err != nil {
+		if out.exitCode > 1 || len(out.stdout) == 0 {
+			return nil, fmt.Errorf("%s", out.problem())
+		}
+		return nil, fmt.Errorf("failed to parse eslint output: %w", err)
+	}
+	var diagnostics []Diagnostic
+	for _, file := range files {
+		for _, m := range file.Messages {
+			d := Diagnostic{
+				Linter:   "eslint",
+				File:     relative(dir, file.FilePath),
+				Line:     m.Line,
+				Column:   m.Column,
+				Severity: "info",
+				Rule:     "syntax",
+				Message:  m.Message,
+				Fixable:  present(m.Fix),
+			}
+			switch m.Severity {
+			case 2:
+				d.Severity = "error"
+			case 1:
+				d.Severity = "warning"
+			}
+			if m.RuleID != nil {
+				d.Rule = *m.RuleID
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics, nil
+}
+
+// prettier reports files that prettier would change and formats them
+var prettier = linter{
+	name:     "prettier",
+	language: "javascript",
+	find: func(dir string) (string, string) {
+		if binary := nodeBinary(dir, "prettier"); binary != "" {
+			return binary, ""
+		}
+		// A global prettier is only used for projects configured for it
+		if binary, err := exec.LookPath("prettier"); err == nil && (packageJSONHas(dir, "prettier") ||
+			findUp(dir, ".prettierrc", ".prettierrc.json", ".prettierrc.yaml", ".prettierrc.yml", ".prettierrc.json5",
+				".prettierrc.js", ".prettierrc.cjs", ".prettierrc.mjs", ".prettierrc.toml",
+				"prettier.config.js", "prettier.config.cjs", "prettier.config.mjs", "prettier.config.ts") != "") {
+			return binary, ""
+		}
+		return "", "prettier is not installed in node_modules"
+	},
+	check: func(_ context.Context, _, _ string, targets []string) []string {
+		return append([]string{"--list-different", "--no-error-on-unmatched-pattern"}, targets...)
+	},
+	parse: parsePrettier,
+	fix: func(file string) []string {
+		return []string{"--stdin-filepath", file}
+	},
+}
+
+// parsePrettier reads the files prettier --list-different lists and the files it could not parse
+func parsePrettier(out output, dir string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for line := range strings.Lines(string(out.stdout)) {
+		if file := strings.TrimSpace(line); file != "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				Linter:   "prettier",
+				File:     relative(dir, file),
+				Severity: "warning",
+				Rule:     "format",
+				Message:  "File is not formatted with prettier",
+				Fixable:  true,
+			})
+		}
+	}
+	for line := range strings.Lines(string(out.stderr)) {
+		match := prettierErrorPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		d := Diagnostic{Linter: "prettier", File: relative(dir, match[1]), Severity: "error", Rule: "syntax", Message: match[2]}
+		d.Line, _ = strconv.Atoi(match[3])
+		d.Column, _ = strconv.Atoi(match[4])
+		diagnostics = append(diagnostics, d)
+	}
+	if out.exitCode > 1 && len(diagnostics) == 0 {
+		return nil, fmt.Errorf("%s", out.problem())
+	}
+	return diagnostics, nil
+}
+
+// packageJSONHas reports whether the nearest package.json has a top-level key
+func packageJSONHas(dir, key string) bool {
+	path := findUp(dir, "package.json")
+	if path == "" {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var manifest map[string]json.RawMessage
+	if json.Unmarshal(data, &manifest) != nil {
+		return false
+	}
+	_, ok := manifest[key]
+	return ok
+}
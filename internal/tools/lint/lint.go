@@ -0,0 +1,376 @@
+package lint
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultTimeout is how long the linters may run by default, in seconds
+	defaultTimeout = 120
+
+	// maxTimeout is the longest the linters may run, in seconds
+	maxTimeout = 600
+
+	// defaultMaxDiagnostics is how many diagnostics are returned by default
+	defaultMaxDiagnostics = 200
+
+	// maxMaxDiagnostics is the most diagnostics that can be returned
+	maxMaxDiagnostics = 2000
+)
+
+// LintTool runs a project's linters and formatters and reports their diagnostics in one form
+type LintTool struct{}
+
+// init registers the lint tool
+func init() {
+	registry.Register(&LintTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *LintTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"lint",
+		mcp.WithDescription(`Run a project's linters and formatters and get their problems back in one list, each with linter, file, line, column, severity, rule and message. Can preview or apply autofixes as unified diffs.
+
+Supported: golangci-lint and gofmt for Go, ruff for Python, eslint and prettier for JavaScript and TypeScript. Linters are chosen from the languages the project uses and the tools installed for it (node_modules/.bin, .venv), and run with the project's own configuration.`),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the project directory to lint"),
+		),
+		mcp.WithString("target",
+			mcp.Description("Files or directories to lint, relative to path and space separated (default: the whole project)"),
+		),
+		mcp.WithString("linters",
+			mcp.Description("Comma-separated linters to run instead of those detected: "+strings.Join(names(), ", ")),
+		),
+		mcp.WithString("fix",
+			mcp.Description("none reports problems, preview also returns the autofixes as diffs without changing files, apply writes them (default: none)"),
+			mcp.Enum("none", "preview", "apply"),
+			mcp.DefaultString("none"),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("Seconds before the linters are stopped (default: %d, max: %d)", defaultTimeout, maxTimeout)),
+		),
+		mcp.WithNumber("max_diagnostics",
+			mcp.Description(fmt.Sprintf("Most diagnostics to return (default: %d, max: %d). Counts include all of them", defaultMaxDiagnostics, maxMaxDiagnostics)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // fix=apply writes the fixed files
+		mcp.WithDestructiveHintAnnotation(true), // fix=apply overwrites files with the linters' fixes
+		mcp.WithIdempotentHintAnnotation(true),  // Fixing files that are already fixed changes nothing
+		mcp.WithOpenWorldHintAnnotation(false),  // Runs locally installed linters
+	)
+}
+
+// options are the validated parameters
+type options struct {
+	targets        []string
+	linters        []string
+	fix            string
+	timeout        time.Duration
+	maxDiagnostics int
+}
+
+// linterRun is a linter selected for the project, with what it found
+type linterRun struct {
+	LinterRun
+	linter      linter
+	binary      string
+	diagnostics []Diagnostic
+}
+
+// Execute runs the linters and returns their diagnostics
+func (t *LintTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+
+	dir, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory. Pass the project directory as path and the file as target", dir)
+	}
+	opts, err := parseOptions(dir, args)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := selectLinters(dir, opts.linters)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{
+		"dir":     dir,
+		"targets": opts.targets,
+		"fix":     opts.fix,
+	}).Debug("Running linters")
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	check(ctx, dir, runs, opts.targets)
+
+	result := &Result{Dir: dir}
+	var notes []string
+	if opts.fix != "none" {
+		files, fixNotes := fix(ctx, dir, runs)
+		notes = append(notes, fixNotes...)
+		var truncated bool
+		result.Changes, truncated = describe(files)
+		if truncated {
+			notes = append(notes, fmt.Sprintf("Diffs beyond %d KB were left out.", maxDiffBytes/1024))
+		}
+		if opts.fix == "apply" && len(files) > 0 {
+			written, applyNotes := apply(files)
+			notes = append(notes, applyNotes...)
+			result.Applied = written > 0
+			if written > 0 {
+				// Report what is left for the linters that ran
+				check(ctx, dir, slices.DeleteFunc(slices.Clone(runs), func(r *linterRun) bool { return r.Status != "ran" }), opts.targets)
+				notes = append(notes, fmt.Sprintf("Fixed %d files. Diagnostics are those left after the fixes.", written))
+			}
+		}
+	}
+
+	for _, r := range runs {
+		result.Linters = append(result.Linters, r.LinterRun)
+		result.Diagnostics = append(result.Diagnostics, r.diagnostics...)
+	}
+	slices.SortFunc(result.Diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column), cmp.Compare(a.Linter, b.Linter))
+	})
+	for _, d := range result.Diagnostics {
+		result.Counts.Total++
+		switch d.Severity {
+		case "error":
+			result.Counts.Errors++
+		case "warning":
+			result.Counts.Warnings++
+		default:
+			result.Counts.Info++
+		}
+		if d.Fixable {
+			result.Counts.Fixable++
+		}
+	}
+	if len(result.Diagnostics) > opts.maxDiagnostics {
+		notes = append(notes, fmt.Sprintf("Showing %d of %d diagnostics.", opts.maxDiagnostics, len(result.Diagnostics)))
+		result.Diagnostics = result.Diagnostics[:opts.maxDiagnostics]
+	}
+	if result.Diagnostics == nil {
+		result.Diagnostics = []Diagnostic{}
+	}
+	result.Note = strings.Join(notes, " ")
+
+	// Keep code in messages and diffs, such as "a < b", readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Messages and diffs quote the project's code, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "lint",
+		URL:         "file://" + dir,
+		ContentType: "lint_output",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// selectLinters returns the requested linters, or those for the languages the project uses, and
+// finds their binaries
+func selectLinters(dir string, requested []string) ([]*linterRun, error) {
+	used := languages(dir)
+	var runs []*linterRun
+	for _, l := range linters {
+		wanted := used[l.language]
+		if len(requested) > 0 {
+			wanted = slices.Contains(requested, l.name)
+		}
+		if !wanted {
+			continue
+		}
+		r := &linterRun{LinterRun: LinterRun{Name: l.name, Language: l.language}, linter: l}
+		if r.binary, r.Reason = l.find(dir); r.binary == "" {
+			r.Status = "not_installed"
+		}
+		runs = append(runs, r)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no Go, Python or JavaScript project found in %s. Set linters to choose linters to run", dir)
+	}
+	return runs, nil
+}
+
+// check runs the installed linters at the same time and records their diagnostics
+func check(ctx context.Context, dir string, runs []*linterRun, targets []string) {
+	var wg sync.WaitGroup
+	for _, r := range runs {
+		if r.binary == "" {
+			continue
+		}
+		wg.Go(func() {
+			args := r.linter.check(ctx, dir, r.binary, targets)
+			r.Command = strings.Join(append([]string{r.binary}, args...), " ")
+			r.diagnostics = nil
+			out, err := execute(ctx, dir, r.binary, args, nil)
+			r.Duration = out.duration.Round(time.Millisecond).String()
+			if err == nil {
+				r.diagnostics, err = r.linter.parse(out, dir)
+			}
+			if err != nil {
+				r.Status, r.Reason, r.Issues = "failed", err.Error(), 0
+				return
+			}
+			r.Status, r.Reason, r.Issues = "ran", "", len(r.diagnostics)
+		})
+	}
+	wg.Wait()
+}
+
+// parseOptions reads and validates the parameters other than path
+func parseOptions(dir string, args map[string]any) (options, error) {
+	opts := options{
+		fix:            "none",
+		timeout:        defaultTimeout * time.Second,
+		maxDiagnostics: defaultMaxDiagnostics,
+	}
+
+	// Targets are passed as single arguments, so refusing a leading dash is enough to stop them
+	// being read as options
+	if target, ok := args["target"].(string); ok {
+		for _, target := range strings.Fields(target) {
+			if strings.HasPrefix(target, "-") {
+				return opts, fmt.Errorf("invalid target %q: targets cannot start with '-'", target)
+			}
+			rel := relative(dir, target)
+			if filepath.IsAbs(filepath.FromSlash(rel)) || rel == ".." || strings.HasPrefix(rel, "../") {
+				return opts, fmt.Errorf("invalid target %q: targets must be inside %s", target, dir)
+			}
+			opts.targets = append(opts.targets, rel)
+		}
+	}
+	if len(opts.targets) == 0 {
+		opts.targets = []string{"."}
+	}
+
+	if requested, ok := args["linters"].(string); ok {
+		for name := range strings.SplitSeq(requested, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(names(), name) {
+				return opts, fmt.Errorf("unknown linter %q: must be one of %s", name, strings.Join(names(), ", "))
+			}
+			opts.linters = append(opts.linters, name)
+		}
+	}
+
+	if fix, ok := args["fix"].(string); ok && fix != "" {
+		if fix != "none" && fix != "preview" && fix != "apply" {
+			return opts, fmt.Errorf("invalid fix %q: must be none, preview or apply", fix)
+		}
+		opts.fix = fix
+	}
+	if raw, ok := args["timeout"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxTimeout {
+			return opts, fmt.Errorf("invalid 'timeout' parameter: must be from 1 to %d seconds", maxTimeout)
+		}
+		opts.timeout = time.Duration(value * float64(time.Second))
+	}
+	if raw, ok := args["max_diagnostics"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxMaxDiagnostics || value != float64(int(value)) {
+			return opts, fmt.Errorf("invalid 'max_diagnostics' parameter: must be a whole number from 1 to %d", maxMaxDiagnostics)
+		}
+		opts.maxDiagnostics = int(value)
+	}
+	return opts, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the lint tool
+func (t *LintTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Checking code after a change with the project's own linters and formatters, finding what CI's lint step will complain about, or formatting files and applying safe fixes with a diff to review.",
+		WhenNotToUse: "Projects whose linters are not installed - the tool does not install them. Type checking with tsc or mypy, or linters other than golangci-lint, gofmt, ruff, eslint and prettier.",
+		CommonPatterns: []string{
+			"Lint the whole project, then re-lint the files you changed with target",
+			"Preview fixes with fix=preview, review the diffs, then apply them with fix=apply",
+			"Run only the formatters with linters='gofmt,prettier'",
+		},
+		ParameterDetails: map[string]string{
+			"path":    "The directory linters run in and find their configuration from, usually the repository or package root.",
+			"target":  "Relative to path. Go directories include their subdirectories for golangci-lint, and a Go file lints its package.",
+			"linters": "Without this, linters are chosen for the languages found: go.mod or .go files, pyproject.toml, setup.py, requirements.txt or .py files, and package.json or .js and .ts files. eslint needs an ESLint configuration; prettier must be installed in node_modules or configured.",
+			"fix":     "Fixes are made on copies in memory, each linter working on the output of the one before, so eslint's fixes are formatted by prettier. apply writes the result and re-runs the linters to report what is left. A file edited while the linters run is not overwritten.",
+			"timeout": "Covers checking and fixing. Linters still running at the timeout are reported as failed.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Lint a project with its detected linters",
+				Arguments:      map[string]any{"path": "/home/user/service"},
+				ExpectedResult: "Each linter's status and command, counts by severity, and diagnostics sorted by file and line",
+			},
+			{
+				Description: "Preview formatting and fixes for two directories",
+				Arguments: map[string]any{
+					"path":   "/home/user/web",
+					"target": "src/components src/utils",
+					"fix":    "preview",
+				},
+				ExpectedResult: "Diagnostics plus a unified diff for each file the fixes would change; no files are written",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A linter is not_installed",
+				Solution: "Install it in the project (npm install, pip install ruff in .venv) or on PATH. golangci-lint is also found in GOBIN, GOPATH/bin and ~/go/bin.",
+			},
+			{
+				Problem:  "A linter failed",
+				Solution: "The reason holds its error output, usually a configuration problem or a plugin that is not installed. Run the command shown to see it in full.",
+			},
+			{
+				Problem:  "golangci-lint fixes are not applied",
+				Solution: "golangci-lint can only fix files in place. Fixable issues are marked, and gofmt formatting is still applied.",
+			},
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// linter describes how to find, run and read one linter or formatter
+type linter struct {
+	name     string
+	language string
+	// find returns the linter's binary, or why it cannot run in dir
+	find func(dir string) (binary, reason string)
+	// check returns the arguments that report problems in targets without changing files
+	check func(ctx context.Context, dir, binary string, targets []string) []string
+	// parse reads diagnostics from the output of check
+	parse func(out output, dir string) ([]Diagnostic, error)
+	// fix returns the arguments that read a file from stdin and write it fixed to stdout, or nil
+	// for linters that can only fix files in place
+	fix func(file string) []string
+	// fixed reads the fixed file from fix's output, for linters that do not write it as is
+	fixed func(stdout []byte) ([]byte, error)
+}
+
+// linters lists the supported linters by language. Linters come before formatters, so that fixes
+// are formatted afterwards.
+var linters = []linter{golangciLint, gofmt, ruff, eslint, prettier}
+
+// names returns the supported linter names
+func names() []string {
+	var list []string
+	for _, l := range linters {
+		list = append(list, l.name)
+	}
+	return list
+}
+
+// languages reports which languages a project uses, from its manifests and the files at its top
+// level. Manifests in parent directories count, so a package inside a repository is detected.
+func languages(dir string) map[string]bool {
+	found := map[string]bool{
+		"go":         findUp(dir, "go.mod") != "",
+		"python":     findUp(dir, "pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "ruff.toml", ".ruff.toml") != "",
+		"javascript": findUp(dir, "package.json") != "",
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".go":
+			found["go"] = true
+		case ".py":
+			found["python"] = true
+		case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+			found["javascript"] = true
+		}
+	}
+	return found
+}
+
+// findUp returns the first of names found in dir or a parent directory
+func findUp(dir string, names ...string) string {
+	for current := dir; ; current = filepath.Dir(current) {
+		for _, name := range names {
+			if candidate := filepath.Join(current, name); exists(candidate) {
+				return candidate
+			}
+		}
+		if filepath.Dir(current) == current {
+			return ""
+		}
+	}
+}
+
+// nodeBinary finds a package's executable in node_modules/.bin in dir or a parent directory
+func nodeBinary(dir, name string) string {
+	if runtime.GOOS == "windows" {
+		name += ".cmd"
+	}
+	return findUp(dir, filepath.Join("node_modules", ".bin", name))
+}
+
+// venvBinary finds an executable in the project's Python virtual environment
+func venvBinary(dir, name string) string {
+	bin := "bin"
+	if runtime.GOOS == "windows" {
+		bin, name = "Scripts", name+".exe"
+	}
+	for _, venv := range []string{".venv", "venv"} {
+		if candidate := filepath.Join(dir, venv, bin, name); exists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// goBinary finds a Go tool on PATH or where go install puts it, which is often not on the PATH the
+// server was started with
+func goBinary(name string) string {
+	if binary, err := exec.LookPath(name); err == nil {
+		return binary
+	}
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	var dirs []string
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		dirs = append(dirs, gobin)
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		dirs = append(dirs, filepath.Join(filepath.SplitList(gopath)[0], "bin"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "go", "bin"))
+	}
+	for _, dir := range dirs {
+		if candidate := filepath.Join(dir, name); exists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// relative returns a linter's path for a file relative to dir, or the path itself when the file is
+// outside dir
+func relative(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// exists reports whether a file or directory exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// ruff runs ruff check with the project's configuration
+var ruff = linter{
+	name:     "ruff",
+	language: "python",
+	find: func(dir string) (string, string) {
+		if binary := venvBinary(dir, "ruff"); binary != "" {
+			return binary, ""
+		}
+		if binary, err := exec.LookPath("ruff"); err == nil {
+			return binary, ""
+		}
+		return "", "ruff is not installed in .venv, venv or on PATH"
+	},
+	check: func(_ context.Context, _, _ string, targets []string) []string {
+		// --no-fix overrides fix = true in the project's configuration. --force-exclude applies
+		// the configured exclusions to files named as targets.
+		return append([]string{"check", "--output-format=json", "--no-fix", "--force-exclude"}, targets...)
+	},
+	parse: parseRuff,
+	// Reading from stdin, ruff writes the fixed file to stdout and remaining problems to stderr
+	fix: func(file string) []string {
+		return []string{"check", "--fix", "--stdin-filename", file, "-"}
+	},
+}
+
+// ruffDiagnostic is an entry in ruff's JSON output
+type ruffDiagnostic struct {
+	Code     *string `json:"code"`
+	Message  string  `json:"message"`
+	Filename string  `json:"filename"`
+	Location struct {
+		Row    int `json:"row"`
+		Column int `json:"column"`
+	} `json:"location"`
+	Fix *struct {
+		Applicability string `json:"applicability"`
+	} `json:"fix"`
+}
+
+// parseRuff reads ruff's JSON output. Syntax errors have no rule code.
+func parseRuff(out output, dir string) ([]Diagnostic, error) {
+	var entries []ruffDiagnostic
+	if err := json.Unmarshal(out.stdout, &entries); err != nil {
+		if out.exitCode > 1 || len(out.stdout) == 0 {
+			return nil, fmt.Errorf("%s", out.problem())
+		}
+		return nil, fmt.Errorf("failed to parse ruff output: %w", err)
+	}
+	var diagnostics []Diagnostic
+	for _, entry := range entries {
+		d := Diagnostic{
+			Linter:   "ruff",
+			File:     relative(dir, entry.Filename),
+			Line:     entry.Location.Row,
+			Column:   entry.Location.Column,
+			Severity: "warning",
+			Message:  entry.Message,
+			// ruff --fix only applies safe fixes
+			Fixable: entry.Fix != nil && entry.Fix.Applicability != "unsafe" && entry.Fix.Applicability != "display-only",
+		}
+		if entry.Code != nil && *entry.Code != "" {
+			d.Rule = *entry.Code
+		} else {
+			d.Severity, d.Rule = "error", "syntax"
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics, nil
+}
@@ -0,0 +1,91 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/procgroup"
+)
+
+// maxOutputBytes is the most output kept from a linter. Reports larger than this are cut and fail
+// to parse, which is reported rather than read partially.
+const maxOutputBytes = 32 * 1024 * 1024
+
+// output is what a command wrote and how it finished
+type output struct {
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	duration time.Duration
+}
+
+// execute runs binary with args in dir, writing stdin to it when set
+func execute(ctx context.Context, dir, binary string, args []string, stdin []byte) (output, error) {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "NO_COLOR=1", "FORCE_COLOR=0")
+	// eslint and prettier run in node, which may start workers of its own
+	procgroup.Set(cmd)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	stdout := &cappedBuffer{limit: maxOutputBytes}
+	stderr := &cappedBuffer{limit: 64 * 1024}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := output{stdout: stdout.Bytes(), stderr: stderr.Bytes(), duration: time.Since(start)}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return result, fmt.Errorf("timed out")
+		}
+		return result, ctxErr
+	}
+	if err != nil {
+		exitErr, ok := errors.AsType[*exec.ExitError](err)
+		if !ok {
+			return result, fmt.Errorf("failed to run %s: %w", binary, err)
+		}
+		result.exitCode = exitErr.ExitCode()
+	}
+	return result, nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest
+type cappedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write records as much of p as fits
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// problem summarises a command's error output for a failed linter, falling back to its exit code
+func (o output) problem() string {
+	var lines []string
+	for line := range strings.Lines(string(o.stderr)) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+		if len(lines) == 5 {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("exited with code %d", o.exitCode)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,59 @@
+package lint
+
+// Result is the outcome of linting a project
+type Result struct {
+	Dir         string       `json:"dir"`
+	Linters     []LinterRun  `json:"linters"`
+	Counts      Counts       `json:"counts"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	// Changes are the edits autofixes make, or made when Applied is set
+	Changes []Change `json:"changes,omitempty"`
+	Applied bool     `json:"applied,omitempty"`
+	Note    string   `json:"note,omitempty"`
+}
+
+// LinterRun describes how one linter ran
+type LinterRun struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	// Status is ran, failed or not_installed
+	Status   string `json:"status"`
+	Command  string `json:"command,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Issues   int    `json:"issues"`
+	// Reason explains a linter that failed or was not run
+	Reason string `json:"reason,omitempty"`
+}
+
+// Counts totals the diagnostics found, including any left out of the response
+type Counts struct {
+	Total    int `json:"total"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+	Fixable  int `json:"fixable"`
+}
+
+// Diagnostic is one problem reported by a linter, in the same form for every linter
+type Diagnostic struct {
+	Linter string `json:"linter"`
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	// Severity is error, warning or info
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+	// Fixable is set when the linter can fix the problem automatically
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+// Change is the autofix edit to one file
+type Change struct {
+	File      string   `json:"file"`
+	Linters   []string `json:"linters"`
+	Additions int      `json:"additions"`
+	Deletions int      `json:"deletions"`
+	// Diff is a unified diff of the edit, left out once the response holds maxDiffBytes of diffs
+	Diff string `json:"diff,omitempty"`
+}
@@ -0,0 +1,321 @@
+package logtool
+
+import (
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineLength is the longest line read. The rest of a longer line is skipped.
+	maxLineLength = 256 * 1024
+
+	// sampleLines is how many non-empty lines are used to detect the format
+	sampleLines = 100
+
+	// topPathCount is how many request paths access log summaries list
+	topPathCount = 10
+
+	// maxTrackedPaths is how many distinct request paths are counted
+	maxTrackedPaths = 10_000
+)
+
+// bucketSizes are the timeline intervals, smallest first
+var bucketSizes = []time.Duration{
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 2 * 24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour,
+}
+
+// options control which entries are analysed and how much is reported
+type options struct {
+	format         string
+	since, until   time.Time
+	minLevel       int
+	signatureLevel int
+	include        *regexp.Regexp
+	exclude        *regexp.Regexp
+	maxSignatures  int
+	buckets        int
+	// year is assumed for syslog timestamps, which have none
+	year int
+}
+
+// minuteCounts are the entries, and errors among them, in one minute
+type minuteCounts struct {
+	entries, errors int
+}
+
+// analyser accumulates the report while lines are read
+type analyser struct {
+	opts       options
+	report     *Report
+	signatures *signatures
+	minutes    map[int64]*minuteCounts
+	first      time.Time
+	last       time.Time
+	statuses   map[string]int
+	paths      map[string]*PathCount
+	bytes      int64
+}
+
+// analyse reads log lines from r and summarises them
+func analyse(ctx context.Context, r io.Reader, report *Report, opts options) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	// Rotated logs are often compressed
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip-compressed log: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		reader = bufio.NewReaderSize(gz, 64*1024)
+	}
+
+	a := &analyser{
+		opts:       opts,
+		report:     report,
+		signatures: newSignatures(),
+		minutes:    map[int64]*minuteCounts{},
+		statuses:   map[string]int{},
+		paths:      map[string]*PathCount{},
+	}
+	report.Levels = map[string]int{}
+
+	// Read a sample to detect the format before parsing anything
+	var sample []string
+	var readErr error
+	for len(sample) < sampleLines {
+		var line string
+		line, readErr = readLine(reader)
+		if readErr != nil {
+			break
+		}
+		sample = append(sample, line)
+	}
+	if opts.format == "auto" {
+		nonEmpty := slices.DeleteFunc(slices.Clone(sample), func(line string) bool { return line == "" })
+		opts.format = detectFormat(nonEmpty, opts.year)
+	}
+	report.Format = opts.format
+	p := parser{format: opts.format, year: opts.year}
+
+	for _, line := range sample {
+		a.add(p, line)
+	}
+	for readErr == nil {
+		if report.Lines%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		var line string
+		line, readErr = readLine(reader)
+		if readErr == nil {
+			a.add(p, line)
+		}
+	}
+	if !errors.Is(readErr, io.EOF) {
+		return fmt.Errorf("failed to read log after line %d: %w", report.Lines, readErr)
+	}
+
+	a.finish()
+	return nil
+}
+
+// readLine returns the next line without its line ending, cut to maxLineLength
+func readLine(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line) < maxLineLength {
+			line = append(line, chunk[:min(len(chunk), maxLineLength-len(line))]...)
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+			return "", err
+		}
+		line = trimLineEnding(line)
+		return strings.ToValidUTF8(string(line), "�"), nil
+	}
+}
+
+// trimLineEnding removes a trailing line feed or carriage return and line feed
+func trimLineEnding(line []byte) []byte {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		line = line[:n-1]
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line
+}
+
+// add parses one line and, when it passes the filters, adds it to the report
+func (a *analyser) add(p parser, line string) {
+	a.report.Lines++
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	e, ok := p.parse(line)
+	if !ok {
+		// Stack traces and other continuation lines belong to the entry before them
+		if isContinuation(line) && a.report.Entries > 0 {
+			return
+		}
+		e = parsePlain(line)
+		if p.format != "plain" {
+			a.report.Unparsed++
+		}
+	}
+	a.report.Entries++
+
+	if !a.matches(e, line) {
+		return
+	}
+	a.report.Matched++
+	a.report.Levels[levelName(e.level)]++
+
+	if !e.time.IsZero() {
+		if a.first.IsZero() || e.time.Before(a.first) {
+			a.first = e.time
+		}
+		if e.time.After(a.last) {
+			a.last = e.time
+		}
+		minute := e.time.Unix() / 60
+		counts := a.minutes[minute]
+		if counts == nil {
+			counts = &minuteCounts{}
+			a.minutes[minute] = counts
+		}
+		counts.entries++
+		if e.level >= levelError {
+			counts.errors++
+		}
+	}
+
+	if e.level >= a.opts.signatureLevel {
+		a.signatures.add(e)
+	}
+	if e.status > 0 {
+		a.addRequest(e)
+	}
+}
+
+// matches reports whether an entry passes the time, level and pattern filters. Entries without a
+// timestamp are left out when filtering by time, and entries without a level when filtering by level.
+func (a *analyser) matches(e entry, line string) bool {
+	if !a.opts.since.IsZero() || !a.opts.until.IsZero() {
+		if e.time.IsZero() || (!a.opts.since.IsZero() && e.time.Before(a.opts.since)) || (!a.opts.until.IsZero() && e.time.After(a.opts.until)) {
+			return false
+		}
+	}
+	if e.level < a.opts.minLevel {
+		return false
+	}
+	if a.opts.include != nil && !a.opts.include.MatchString(line) {
+		return false
+	}
+	if a.opts.exclude != nil && a.opts.exclude.MatchString(line) {
+		return false
+	}
+	return true
+}
+
+// addRequest counts an access log request by status and path
+func (a *analyser) addRequest(e entry) {
+	a.statuses[strconv.Itoa(e.status)]++
+	a.bytes += e.bytes
+	path := signatureOf(e.path)
+	counts := a.paths[path]
+	if counts == nil {
+		if len(a.paths) >= maxTrackedPaths {
+			return
+		}
+		counts = &PathCount{Path: path}
+		a.paths[path] = counts
+	}
+	counts.Count++
+	if e.status >= 500 {
+		counts.Errors++
+	}
+}
+
+// finish completes the report once every line has been read
+func (a *analyser) finish() {
+	r := a.report
+	r.FirstTime = formatTime(a.first)
+	r.LastTime = formatTime(a.last)
+	r.Timeline = a.timeline()
+	r.Signatures = a.signatures.top(a.opts.maxSignatures)
+	r.DistinctSignatures = len(a.signatures.stats)
+
+	var notes []string
+	if a.signatures.untracked > 0 {
+		notes = append(notes, fmt.Sprintf("%d entries were not grouped because more than %d distinct signatures were found.", a.signatures.untracked, maxSignatureTracked))
+	}
+	if r.Entries > 0 && r.Unparsed*10 > r.Entries {
+		notes = append(notes, fmt.Sprintf("%d of %d entries were not in %s format and were read as plain text. Set format if the detected format is wrong.", r.Unparsed, r.Entries, r.Format))
+	}
+	if r.Matched > 0 && a.first.IsZero() {
+		notes = append(notes, "No timestamps were found, so there is no timeline.")
+	}
+	r.Note = strings.Join(notes, " ")
+
+	if len(a.statuses) > 0 {
+		paths := make([]PathCount, 0, len(a.paths))
+		for _, counts := range a.paths {
+			paths = append(paths, *counts)
+		}
+		slices.SortFunc(paths, func(x, y PathCount) int {
+			if x.Count != y.Count {
+				return y.Count - x.Count
+			}
+			return cmp.Compare(x.Path, y.Path)
+		})
+		r.Access = &AccessSummary{
+			Statuses:      a.statuses,
+			TopPaths:      paths[:min(len(paths), topPathCount)],
+			ResponseBytes: a.bytes,
+		}
+	}
+}
+
+// timeline groups the per-minute counts into at most opts.buckets intervals of a round size
+func (a *analyser) timeline() []Bucket {
+	if a.first.IsZero() {
+		return nil
+	}
+	size := bucketSizes[len(bucketSizes)-1]
+	for _, candidate := range bucketSizes {
+		if int(a.last.Sub(a.first.Truncate(candidate))/candidate)+1 <= a.opts.buckets {
+			size = candidate
+			break
+		}
+	}
+
+	start := a.first.Truncate(size)
+	buckets := make([]Bucket, int(a.last.Sub(start)/size)+1)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * size).Format(time.RFC3339)
+	}
+	for minute, counts := range a.minutes {
+		i := int(time.Unix(minute*60, 0).Sub(start) / size)
+		i = max(0, min(i, len(buckets)-1))
+		buckets[i].Entries += counts.entries
+		buckets[i].Errors += counts.errors
+	}
+	return buckets
+}
@@ -0,0 +1,309 @@
+package logtool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxSignatures is how many error signatures are reported by default
+	defaultMaxSignatures = 20
+
+	// maxMaxSignatures is the most error signatures that can be reported
+	maxMaxSignatures = 200
+
+	// defaultBuckets is how many intervals the timeline has at most by default
+	defaultBuckets = 24
+
+	// maxBuckets is the most intervals the timeline can have
+	maxBuckets = 168
+)
+
+// relativeTimePattern matches durations before now such as 30m, 2h or 7d
+var relativeTimePattern = regexp.MustCompile(`^(\d+)(m|h|d)$`)
+
+// AnalyseLogsTool summarises large log files
+type AnalyseLogsTool struct{}
+
+// init registers the analyse_logs tool
+func init() {
+	registry.Register(&AnalyseLogsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (a *AnalyseLogsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"analyse_logs",
+		mcp.WithDescription(`Summarise a log file of any size without reading it into the conversation: entry counts by level, a timeline of entries and errors, and the most frequent error messages grouped into signatures (with numbers, IDs, IPs and quoted values replaced by placeholders).
+
+Formats are detected automatically: JSON lines, logfmt, syslog (RFC 3164 and 5424), common/combined access logs, or plain text with leading timestamps and level words. Gzip-compressed logs are read directly. Filter by time range, minimum level and regex.`),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the log file"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Log format (default: detected from the first lines)"),
+			mcp.Enum("auto", "json", "logfmt", "syslog", "access", "plain"),
+			mcp.DefaultString("auto"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only entries at or after this time: an RFC 3339 time or date, or a duration before now such as 30m, 2h or 7d"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Only entries at or before this time, in the same forms as since"),
+		),
+		mcp.WithString("min_level",
+			mcp.Description("Only entries at this level or more severe. Entries without a recognised level are then left out"),
+			mcp.Enum("trace", "debug", "info", "warn", "error", "fatal"),
+		),
+		mcp.WithString("signature_level",
+			mcp.Description("Group entries at this level or more severe into signatures (default: error)"),
+			mcp.Enum("trace", "debug", "info", "warn", "error", "fatal"),
+			mcp.DefaultString("error"),
+		),
+		mcp.WithString("include",
+			mcp.Description("Only lines matching this regex (RE2 syntax)"),
+		),
+		mcp.WithString("exclude",
+			mcp.Description("Leave out lines matching this regex, e.g. health checks"),
+		),
+		mcp.WithNumber("max_signatures",
+			mcp.Description(fmt.Sprintf("Most signatures to report (default: %d, max: %d)", defaultMaxSignatures, maxMaxSignatures)),
+		),
+		mcp.WithNumber("buckets",
+			mcp.Description(fmt.Sprintf("Most intervals in the timeline (default: %d, max: %d)", defaultBuckets, maxBuckets)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the log file
+		mcp.WithDestructiveHintAnnotation(false), // Does not modify anything
+		mcp.WithIdempotentHintAnnotation(true),   // Same file and filters produce the same report
+		mcp.WithOpenWorldHintAnnotation(false),   // Local file operations only
+	)
+}
+
+// Execute reads the log file and returns the report
+func (a *AnalyseLogsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	opts, err := parseOptions(args, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access log file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", resolved)
+	}
+	// Syslog timestamps have no year, so assume the one the file was last written in
+	opts.year = info.ModTime().Year()
+
+	file, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	logger.WithFields(logrus.Fields{
+		"path": resolved,
+		"size": info.Size(),
+	}).Debug("Analysing log file")
+
+	report := &Report{Path: resolved, SizeBytes: info.Size()}
+	if err := analyse(ctx, file, report, opts); err != nil {
+		return nil, err
+	}
+
+	// Keep placeholders such as <n> readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// The report quotes log messages, so scan it as the file is not read through SafeFileRead
+	sourceCtx := security.SourceContext{
+		Tool:        "analyse_logs",
+		URL:         "file://" + resolved,
+		ContentType: "logs",
+	}
+	if result, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch result.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(result)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(result) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// parseOptions reads the filter and report parameters
+func parseOptions(args map[string]any, now time.Time) (options, error) {
+	opts := options{
+		format:         "auto",
+		minLevel:       levelUnknown,
+		signatureLevel: levelError,
+		maxSignatures:  defaultMaxSignatures,
+		buckets:        defaultBuckets,
+	}
+
+	if format, ok := args["format"].(string); ok && format != "" {
+		if format != "auto" && format != "plain" && !slices.Contains(formats, format) {
+			return opts, fmt.Errorf("invalid format %q: must be auto, json, logfmt, syslog, access or plain", format)
+		}
+		opts.format = format
+	}
+
+	var err error
+	for _, name := range []string{"since", "until"} {
+		value, _ := args[name].(string)
+		if value == "" {
+			continue
+		}
+		t, ok := parseTimeParameter(value, now)
+		if !ok {
+			return opts, fmt.Errorf("invalid '%s' parameter %q: use an RFC 3339 time such as 2026-01-05T09:30:00Z, a date such as 2026-01-05, or a duration such as 30m, 2h or 7d", name, value)
+		}
+		if name == "since" {
+			opts.since = t
+		} else {
+			opts.until = t
+		}
+	}
+	if !opts.since.IsZero() && !opts.until.IsZero() && opts.until.Before(opts.since) {
+		return opts, fmt.Errorf("until is before since")
+	}
+
+	for name, target := range map[string]*int{"min_level": &opts.minLevel, "signature_level": &opts.signatureLevel} {
+		value, _ := args[name].(string)
+		if value == "" {
+			continue
+		}
+		level, ok := validLevel(value)
+		if !ok {
+			return opts, fmt.Errorf("invalid '%s' parameter %q: must be one of %s", name, value, strings.Join(levelNames, ", "))
+		}
+		*target = level
+	}
+
+	for name, target := range map[string]**regexp.Regexp{"include": &opts.include, "exclude": &opts.exclude} {
+		value, _ := args[name].(string)
+		if value == "" {
+			continue
+		}
+		if *target, err = regexp.Compile(value); err != nil {
+			return opts, fmt.Errorf("invalid '%s' regex: %w", name, err)
+		}
+	}
+
+	for name, limit := range map[string]struct {
+		target *int
+		max    int
+	}{"max_signatures": {&opts.maxSignatures, maxMaxSignatures}, "buckets": {&opts.buckets, maxBuckets}} {
+		raw, ok := args[name]
+		if !ok {
+			continue
+		}
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > float64(limit.max) || value != float64(int(value)) {
+			return opts, fmt.Errorf("invalid '%s' parameter: must be a whole number from 1 to %d", name, limit.max)
+		}
+		*limit.target = int(value)
+	}
+	return opts, nil
+}
+
+// parseTimeParameter reads a time, a date, or a duration before now
+func parseTimeParameter(value string, now time.Time) (time.Time, bool) {
+	if match := relativeTimePattern.FindStringSubmatch(value); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[match[2]]
+		return now.Add(-time.Duration(n) * unit).UTC(), true
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true
+	}
+	return parseTime(value)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the analyse_logs tool
+func (a *AnalyseLogsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Investigating incidents or errors in log files too large to read, finding the most frequent errors, seeing when errors started, or summarising access logs by status and path.",
+		WhenNotToUse: "Reading a few specific lines - use the filesystem tool or a search tool. Container logs - use the containers tool's logs function. Querying a log platform such as CloudWatch or Loki.",
+		CommonPatterns: []string{
+			"Start without filters to see the format, levels and top error signatures",
+			"Narrow to an incident window with since and until, then look at the timeline for when errors began",
+			"Use exclude to drop noise such as health checks before reading signatures",
+			"Set signature_level to warn to group warnings as well as errors",
+		},
+		ParameterDetails: map[string]string{
+			"path":            "Absolute path, or relative to the client's workspace root. The file is streamed, so there is no size limit. Files starting with the gzip header are decompressed.",
+			"format":          "Detected from the first 100 lines. Lines not in the detected format are read as plain text and counted in unparsed. Lines starting with whitespace, such as stack trace frames, are treated as part of the previous entry.",
+			"since":           "Times without a zone are UTC, as are log timestamps without one. Durations (m, h, d) count back from now, not from the end of the log. Entries without a timestamp are left out when since or until is set.",
+			"min_level":       "Levels are normalised from common names (warning, err, critical, severe ...), Bunyan/Pino numbers, syslog priorities and HTTP statuses (5xx error, 4xx warn).",
+			"signature_level": "Signatures replace times, UUIDs, IPs, emails, hex IDs, quoted strings and numbers with placeholders, so repeated errors group together. Each signature has an example message and when it was first and last seen.",
+			"include":         "Matched against the whole line, before parsing.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Summarise an application log",
+				Arguments:      map[string]any{"path": "/var/log/app/app.log"},
+				ExpectedResult: "Format, entry counts by level, an hourly or finer timeline, and the 20 most frequent error signatures",
+			},
+			{
+				Description: "Errors in an incident window, without health checks",
+				Arguments: map[string]any{
+					"path":      "/var/log/nginx/access.log.1.gz",
+					"since":     "2026-01-05T09:00:00Z",
+					"until":     "2026-01-05T11:00:00Z",
+					"min_level": "error",
+					"exclude":   "/healthz",
+				},
+				ExpectedResult: "Only 5xx requests in the window, with counts by status, top paths and grouped request signatures",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Many lines are counted as unparsed",
+				Solution: "The file mixes formats, or the wrong format was detected. Set format explicitly, or use plain.",
+			},
+			{
+				Problem:  "Levels are all unknown",
+				Solution: "The log has no level field or words the tool recognises. Use include with a regex such as '(?i)error|exception' to focus on failures instead of min_level.",
+			},
+			{
+				Problem:  "since or until removes everything",
+				Solution: "Check first_time and last_time in an unfiltered report. Relative durations count back from now, so they miss older logs.",
+			},
+		},
+	}
+}
@@ -0,0 +1,506 @@
+package logtool
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Levels from least to most severe. Entries without a recognised level have levelUnknown.
+const (
+	levelUnknown = iota - 1
+	levelTrace
+	levelDebug
+	levelInfo
+	levelWarn
+	levelError
+	levelFatal
+)
+
+// levelNames are the names of the levels, indexed by level
+var levelNames = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// formats are the log formats that can be detected, in the order they are tried
+var formats = []string{"json", "access", "syslog", "logfmt"}
+
+var (
+	// accessPattern matches common and combined access log lines
+	accessPattern = regexp.MustCompile(`^(\S+) \S+ \S+ \[([^\]]+)\] "([^"]*)" (\d{3}) (\S+)`)
+	// syslog5424Pattern matches RFC 5424 syslog lines
+	syslog5424Pattern = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) \S+ (\S+) \S+ \S+ (?:-|(?:\[[^\]]*\])+) ?(.*)$`)
+	// syslog3164Pattern matches RFC 3164 (BSD) syslog lines, with or without a priority
+	syslog3164Pattern = regexp.MustCompile(`^(?:<(\d{1,3})>)?([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) \S+ ([^:\[\s]+)(?:\[\d+\])?: ?(.*)$`)
+	// leadingTimePattern matches a timestamp at the start of a plain line, optionally in brackets
+	leadingTimePattern = regexp.MustCompile(`^\[?(\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\]?\s*`)
+	// levelWordPattern matches a level written in a plain line, in capitals or in brackets
+	levelWordPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|SEVERE|CRITICAL|CRIT|FATAL|PANIC)\b|\[(?i:(trace|debug|info|notice|warn|warning|error|err|crit|critical|alert|emerg|fatal))\]`)
+	// failureWordPattern matches words that mark a plain line without a level as an error
+	failureWordPattern = regexp.MustCompile(`^panic: |\b(Exception|Traceback \(most recent call last\))\b`)
+)
+
+// timeKeys, levelKeys and messageKeys are the fields read from structured (JSON and logfmt) entries
+var (
+	timeKeys    = []string{"time", "timestamp", "ts", "@timestamp", "t", "date", "datetime"}
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "levelname", "loglevel", "@level"}
+	messageKeys = []string{"msg", "message", "@message", "error", "err", "event"}
+)
+
+// timeLayouts are the timestamp formats recognised in log entries
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05.999999999",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// entry is one parsed log entry
+type entry struct {
+	time    time.Time
+	level   int
+	message string
+
+	// Access log requests only
+	status int
+	method string
+	path   string
+	bytes  int64
+}
+
+// parser parses lines in one format
+type parser struct {
+	format string
+	// year is used for syslog timestamps, which have none
+	year int
+}
+
+// parse parses a line, reporting whether it is in the parser's format
+func (p parser) parse(line string) (entry, bool) {
+	switch p.format {
+	case "json":
+		return parseJSON(line)
+	case "logfmt":
+		return parseLogfmt(line)
+	case "access":
+		return parseAccess(line)
+	case "syslog":
+		return parseSyslog(line, p.year)
+	}
+	return parsePlain(line), !isContinuation(line)
+}
+
+// detectFormat picks the format that parses at least half of the sample lines, preferring the one
+// that parses most. Logs in none of them are plain text.
+func detectFormat(sample []string, year int) string {
+	best, bestCount := "plain", 0
+	for _, format := range formats {
+		p := parser{format: format, year: year}
+		count := 0
+		for _, line := range sample {
+			if _, ok := p.parse(line); ok {
+				count++
+			}
+		}
+		if count > bestCount && count*2 >= len(sample) {
+			best, bestCount = format, count
+		}
+	}
+	return best
+}
+
+// isContinuation reports whether a line continues the previous entry, as stack traces do
+func isContinuation(line string) bool {
+	return line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(line, "Caused by:")
+}
+
+// jsonFields are the fields read from JSON entries: the keys in timeKeys, levelKeys and messageKeys.
+// Decoding into a struct skips the other fields, which is much faster than decoding whole lines.
+type jsonFields struct {
+	Time        json.RawMessage `json:"time"`
+	Timestamp   json.RawMessage `json:"timestamp"`
+	TS          json.RawMessage `json:"ts"`
+	AtTimestamp json.RawMessage `json:"@timestamp"`
+	T           json.RawMessage `json:"t"`
+	Date        json.RawMessage `json:"date"`
+	Datetime    json.RawMessage `json:"datetime"`
+
+	Level    json.RawMessage `json:"level"`
+	Lvl      json.RawMessage `json:"lvl"`
+	Severity json.RawMessage `json:"severity"`
+	LogLevel json.RawMessage `json:"log.level"`
+	Log      struct {
+		Level json.RawMessage `json:"level"`
+	} `json:"log"`
+	Levelname json.RawMessage `json:"levelname"`
+	Loglevel  json.RawMessage `json:"loglevel"`
+	AtLevel   json.RawMessage `json:"@level"`
+
+	Msg       json.RawMessage `json:"msg"`
+	Message   json.RawMessage `json:"message"`
+	AtMessage json.RawMessage `json:"@message"`
+	Error     json.RawMessage `json:"error"`
+	Err       json.RawMessage `json:"err"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// parseJSON parses a JSON object line
+func parseJSON(line string) (entry, bool) {
+	if line[0] != '{' {
+		return entry{}, false
+	}
+	var fields jsonFields
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return entry{}, false
+	}
+
+	e := entry{level: levelUnknown}
+	if value, ok := firstJSON(fields.Time, fields.Timestamp, fields.TS, fields.AtTimestamp, fields.T, fields.Date, fields.Datetime); ok {
+		e.time = parseTimeValue(value)
+	}
+	if value, ok := firstJSON(fields.Level, fields.Lvl, fields.Severity, fields.LogLevel, fields.Log.Level, fields.Levelname, fields.Loglevel, fields.AtLevel); ok {
+		e.level = parseLevelValue(value)
+	}
+	if value, ok := firstJSON(fields.Msg, fields.Message, fields.AtMessage, fields.Error, fields.Err, fields.Event); ok {
+		e.message = stringValue(value)
+	}
+	if e.message == "" {
+		e.message = line
+	}
+	return e, true
+}
+
+// firstJSON returns the first of the values that is present and not null: strings as text,
+// numbers as json.Number, and anything else as its JSON
+func firstJSON(values ...json.RawMessage) (any, bool) {
+	for _, raw := range values {
+		switch {
+		case len(raw) == 0 || string(raw) == "null":
+			continue
+		case raw[0] == '"':
+			// Most values have no escapes, so skip decoding them
+			if !bytes.ContainsRune(raw, '\\') {
+				return string(raw[1 : len(raw)-1]), true
+			}
+			var text string
+			if json.Unmarshal(raw, &text) == nil {
+				return text, true
+			}
+		case raw[0] == '-' || (raw[0] >= '0' && raw[0] <= '9'):
+			return json.Number(raw), true
+		default:
+			return string(raw), true
+		}
+	}
+	return nil, false
+}
+
+// lookup returns the first of the keys present in fields
+func lookup(fields map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// stringValue formats a JSON field as text
+func stringValue(value any) string {
+	if number, ok := value.(json.Number); ok {
+		return number.String()
+	}
+	return value.(string)
+}
+
+// parseTimeValue reads a timestamp field, which may be text or Unix seconds or milliseconds
+func parseTimeValue(value any) time.Time {
+	switch v := value.(type) {
+	case string:
+		if t, ok := parseTime(v); ok {
+			return t
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return unixTime(n)
+		}
+	case json.Number:
+		if n, err := v.Float64(); err == nil {
+			return unixTime(n)
+		}
+	}
+	return time.Time{}
+}
+
+// unixTime converts Unix seconds, or milliseconds when the number is too large to be seconds
+func unixTime(n float64) time.Time {
+	if n <= 0 {
+		return time.Time{}
+	}
+	if n > 1e11 {
+		return time.UnixMilli(int64(n)).UTC()
+	}
+	seconds, fraction := math.Modf(n)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+}
+
+// parseTime parses a timestamp in one of the recognised layouts. Timestamps without a zone are UTC.
+func parseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	// Java and Python loggers separate fractional seconds with a comma
+	if len(value) > 19 && value[19] == ',' {
+		value = value[:19] + "." + value[20:]
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseLevelValue reads a level field, which may be a name or a number as Bunyan and Pino write
+func parseLevelValue(value any) int {
+	switch v := value.(type) {
+	case string:
+		return parseLevel(v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return levelUnknown
+		}
+		switch {
+		case n >= 60:
+			return levelFatal
+		case n >= 50:
+			return levelError
+		case n >= 40:
+			return levelWarn
+		case n >= 30:
+			return levelInfo
+		case n >= 20:
+			return levelDebug
+		case n >= 10:
+			return levelTrace
+		}
+	}
+	return levelUnknown
+}
+
+// parseLevel reads a level name, accepting the names used by common loggers
+func parseLevel(name string) int {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "trace", "finest", "finer":
+		return levelTrace
+	case "debug", "fine", "verbose":
+		return levelDebug
+	case "info", "information", "informational", "notice", "config":
+		return levelInfo
+	case "warn", "warning":
+		return levelWarn
+	case "error", "err", "severe", "dpanic":
+		return levelError
+	case "fatal", "critical", "crit", "panic", "alert", "emerg", "emergency":
+		return levelFatal
+	}
+	return levelUnknown
+}
+
+// levelName names a level, including unknown
+func levelName(level int) string {
+	if level == levelUnknown {
+		return "unknown"
+	}
+	return levelNames[level]
+}
+
+// parseLogfmt parses key=value lines. A line must have at least two pairs, one of them a time,
+// level or message key, so that prose containing an equals sign is not mistaken for logfmt.
+func parseLogfmt(line string) (entry, bool) {
+	fields := map[string]string{}
+	pairs := 0
+	for rest := line; rest != ""; {
+		rest = strings.TrimLeft(rest, " ")
+		if rest == "" {
+			break
+		}
+		end := strings.IndexAny(rest, "= ")
+		if end <= 0 || rest[end] != '=' {
+			return entry{}, false
+		}
+		key := rest[:end]
+		rest = rest[end+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := closingQuote(rest)
+			if closing < 0 {
+				return entry{}, false
+			}
+			unquoted, err := strconv.Unquote(rest[:closing+1])
+			if err != nil {
+				unquoted = rest[1:closing]
+			}
+			value, rest = unquoted, rest[closing+1:]
+		} else {
+			value, rest, _ = strings.Cut(rest, " ")
+		}
+		fields[key] = value
+		pairs++
+	}
+	if pairs < 2 {
+		return entry{}, false
+	}
+
+	_, hasTime := lookup(fields, timeKeys)
+	_, hasLevel := lookup(fields, levelKeys)
+	_, hasMessage := lookup(fields, messageKeys)
+	if !hasTime && !hasLevel && !hasMessage {
+		return entry{}, false
+	}
+
+	e := entry{level: levelUnknown}
+	if value, ok := lookup(fields, timeKeys); ok {
+		e.time = parseTimeValue(value)
+	}
+	if value, ok := lookup(fields, levelKeys); ok {
+		e.level = parseLevel(value)
+	}
+	if value, ok := lookup(fields, messageKeys); ok {
+		e.message = value
+	}
+	if e.message == "" {
+		e.message = line
+	}
+	return e, true
+}
+
+// closingQuote returns the index of the quote closing the quoted value at the start of s, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseAccess parses common and combined access log lines. Server errors are logged as errors and
+// client errors as warnings.
+func parseAccess(line string) (entry, bool) {
+	match := accessPattern.FindStringSubmatch(line)
+	if match == nil {
+		return entry{}, false
+	}
+	t, err := time.Parse("02/Jan/2006:15:04:05 -0700", match[2])
+	if err != nil {
+		return entry{}, false
+	}
+	e := entry{time: t.UTC(), level: levelInfo}
+	e.status, _ = strconv.Atoi(match[4])
+	e.bytes, _ = strconv.ParseInt(match[5], 10, 64)
+
+	request := strings.Fields(match[3])
+	if len(request) >= 2 {
+		e.method = request[0]
+		e.path, _, _ = strings.Cut(request[1], "?")
+	} else {
+		e.method, e.path = "-", match[3]
+	}
+	switch {
+	case e.status >= 500:
+		e.level = levelError
+	case e.status >= 400:
+		e.level = levelWarn
+	}
+	e.message = e.method + " " + e.path + " " + match[4]
+	return e, true
+}
+
+// parseSyslog parses RFC 5424 and RFC 3164 syslog lines. The level comes from the priority, or from
+// the message when there is no priority.
+func parseSyslog(line string, year int) (entry, bool) {
+	if match := syslog5424Pattern.FindStringSubmatch(line); match != nil {
+		e := entry{level: syslogLevel(match[1])}
+		if t, ok := parseTime(match[2]); ok {
+			e.time = t
+		}
+		e.message = match[3] + ": " + match[4]
+		return e, true
+	}
+
+	match := syslog3164Pattern.FindStringSubmatch(line)
+	if match == nil {
+		return entry{}, false
+	}
+	t, err := time.Parse("2006 Jan _2 15:04:05", strconv.Itoa(year)+" "+match[2])
+	if err != nil {
+		return entry{}, false
+	}
+	e := entry{time: t, message: match[3] + ": " + match[4], level: syslogLevel(match[1])}
+	if match[1] == "" {
+		e.level = plainLevel(match[4])
+	}
+	return e, true
+}
+
+// syslogLevel converts a syslog priority to a level
+func syslogLevel(priority string) int {
+	n, err := strconv.Atoi(priority)
+	if err != nil {
+		return levelUnknown
+	}
+	switch severity := n % 8; {
+	case severity <= 2:
+		return levelFatal
+	case severity == 3:
+		return levelError
+	case severity == 4:
+		return levelWarn
+	case severity <= 6:
+		return levelInfo
+	}
+	return levelDebug
+}
+
+// parsePlain reads a leading timestamp and a level word from a line of free text
+func parsePlain(line string) entry {
+	e := entry{level: levelUnknown, message: line}
+	if match := leadingTimePattern.FindStringSubmatchIndex(line); match != nil {
+		if t, ok := parseTime(line[match[2]:match[3]]); ok {
+			e.time = t
+			e.message = line[match[1]:]
+		}
+	}
+	e.level = plainLevel(e.message)
+	return e
+}
+
+// plainLevel finds a level word near the start of free text, treating panics and exceptions as errors
+func plainLevel(text string) int {
+	head := text[:min(len(text), 120)]
+	if match := levelWordPattern.FindStringSubmatch(head); match != nil {
+		return parseLevel(match[1] + match[2])
+	}
+	if failureWordPattern.MatchString(text) {
+		return levelError
+	}
+	return levelUnknown
+}
+
+// validLevel reads a level parameter
+func validLevel(name string) (int, bool) {
+	index := slices.Index(levelNames, strings.ToLower(name))
+	return index, index >= 0
+}
@@ -0,0 +1,148 @@
+package logtool
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxSignatureTracked is how many distinct signatures are tracked before new ones are only counted
+	maxSignatureTracked = 10_000
+
+	// maxSignatureLength is the longest signature, in characters
+	maxSignatureLength = 300
+
+	// maxExampleLength is the longest example message, in characters
+	maxExampleLength = 500
+)
+
+// variablePatterns replace the parts of messages that vary between occurrences of the same event,
+// most specific first
+var variablePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b[\w.+-]+@[\w-]+(?:\.[\w-]+)+\b`), "<email>"},
+	{regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`), "<hex>"},
+	{regexp.MustCompile(`"[^"]*"`), `"<str>"`},
+	{regexp.MustCompile(`'[^']*'`), `'<str>'`},
+	// Numbers within words, such as http2 or v1.2, are kept
+	{regexp.MustCompile(`(^|[^\pL\d_.])\d+(?:\.\d+)?`), "${1}<n>"},
+}
+
+// signatureStats accumulates the entries sharing a signature
+type signatureStats struct {
+	level       int
+	count       int
+	first, last time.Time
+	example     string
+}
+
+// signatures groups entries by signature
+type signatures struct {
+	stats map[string]*signatureStats
+	// untracked counts entries whose signatures arrived after the tracking limit was reached
+	untracked int
+}
+
+// newSignatures returns an empty set of signatures
+func newSignatures() *signatures {
+	return &signatures{stats: map[string]*signatureStats{}}
+}
+
+// add records an entry under its signature
+func (s *signatures) add(e entry) {
+	key := signatureOf(e.message)
+	stats, ok := s.stats[key]
+	if !ok {
+		if len(s.stats) >= maxSignatureTracked {
+			s.untracked++
+			return
+		}
+		stats = &signatureStats{level: e.level, example: truncate(e.message, maxExampleLength)}
+		s.stats[key] = stats
+	}
+	stats.count++
+	stats.level = max(stats.level, e.level)
+	if !e.time.IsZero() {
+		if stats.first.IsZero() || e.time.Before(stats.first) {
+			stats.first = e.time
+		}
+		if e.time.After(stats.last) {
+			stats.last = e.time
+		}
+	}
+}
+
+// top returns the most frequent signatures, most frequent first
+func (s *signatures) top(limit int) []Signature {
+	result := make([]Signature, 0, len(s.stats))
+	for key, stats := range s.stats {
+		result = append(result, Signature{
+			Signature: key,
+			Level:     levelName(stats.level),
+			Count:     stats.count,
+			FirstSeen: formatTime(stats.first),
+			LastSeen:  formatTime(stats.last),
+			Example:   stats.example,
+		})
+	}
+	slices.SortFunc(result, func(a, b Signature) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return cmp.Compare(a.Signature, b.Signature)
+	})
+	return result[:min(len(result), limit)]
+}
+
+// signatureOf replaces the variable parts of a message with placeholders
+func signatureOf(message string) string {
+	signature := strings.Join(strings.Fields(message), " ")
+	for _, v := range variablePatterns {
+		if v.replacement == "<hex>" {
+			signature = v.pattern.ReplaceAllStringFunc(signature, func(word string) string {
+				if isHexID(word) {
+					return "<hex>"
+				}
+				return word
+			})
+			continue
+		}
+		signature = v.pattern.ReplaceAllString(signature, v.replacement)
+	}
+	return truncate(signature, maxSignatureLength)
+}
+
+// isHexID reports whether a word of hex digits is an ID rather than a number or a word such as
+// "deadbeef", by having a 0x prefix or both digits and letters
+func isHexID(word string) bool {
+	if len(word) > 2 && (word[:2] == "0x" || word[:2] == "0X") {
+		return true
+	}
+	return strings.ContainsAny(word, "0123456789") && strings.ContainsAny(word, "abcdefABCDEF")
+}
+
+// truncate shortens text to at most limit characters
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
+
+// formatTime formats a time for the report, or returns an empty string for the zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
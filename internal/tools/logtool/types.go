@@ -0,0 +1,51 @@
+package logtool
+
+// Report summarises a log file
+type Report struct {
+	Path               string         `json:"path"`
+	Format             string         `json:"format"`
+	SizeBytes          int64          `json:"size_bytes"`
+	Lines              int            `json:"lines"`
+	Entries            int            `json:"entries"`
+	Unparsed           int            `json:"unparsed,omitempty"`
+	Matched            int            `json:"matched"`
+	FirstTime          string         `json:"first_time,omitempty"`
+	LastTime           string         `json:"last_time,omitempty"`
+	Levels             map[string]int `json:"levels"`
+	Timeline           []Bucket       `json:"timeline,omitempty"`
+	Signatures         []Signature    `json:"signatures"`
+	DistinctSignatures int            `json:"distinct_signatures"`
+	Access             *AccessSummary `json:"access,omitempty"`
+	Note               string         `json:"note,omitempty"`
+}
+
+// Bucket counts the matching entries in one interval of the timeline
+type Bucket struct {
+	Start   string `json:"start"`
+	Entries int    `json:"entries"`
+	Errors  int    `json:"errors"`
+}
+
+// Signature groups entries whose messages differ only in variable parts such as numbers and IDs
+type Signature struct {
+	Signature string `json:"signature"`
+	Level     string `json:"level"`
+	Count     int    `json:"count"`
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
+	Example   string `json:"example"`
+}
+
+// AccessSummary summarises the requests in an access log
+type AccessSummary struct {
+	Statuses      map[string]int `json:"statuses"`
+	TopPaths      []PathCount    `json:"top_paths"`
+	ResponseBytes int64          `json:"response_bytes"`
+}
+
+// PathCount is a request path and how many requests, and failed requests, it received
+type PathCount struct {
+	Path   string `json:"path"`
+	Count  int    `json:"count"`
+	Errors int    `json:"errors"`
+}
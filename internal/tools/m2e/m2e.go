@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +14,7 @@ import (
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -89,7 +91,7 @@ func (m *M2ETool) Execute(ctx context.Context, logger *logrus.Logger, cache *syn
 		return m.executeInlineMode(conv, request, logger)
 	} else if request.FilePath != "" {
 		// Update file mode: file_path provided (default)
-		return m.executeUpdateFileMode(conv, request, logger)
+		return m.executeUpdateFileMode(ctx, conv, request, logger)
 	} else {
 		return nil, fmt.Errorf("either 'text' or 'file_path' parameter must be provided")
 	}
@@ -133,9 +135,9 @@ func (m *M2ETool) executeInlineMode(conv *converter.Converter, request *ConvertR
 }
 
 // executeUpdateFileMode handles file update operations
-func (m *M2ETool) executeUpdateFileMode(conv *converter.Converter, request *ConvertRequest, logger *logrus.Logger) (*mcp.CallToolResult, error) {
+func (m *M2ETool) executeUpdateFileMode(ctx context.Context, conv *converter.Converter, request *ConvertRequest, logger *logrus.Logger) (*mcp.CallToolResult, error) {
 	// Security check for file access (both read and write)
-	if err := security.CheckFileAccess(request.FilePath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, request.FilePath); err != nil {
 		return nil, err
 	}
 
@@ -247,7 +249,7 @@ func (m *M2ETool) parseRequest(args map[string]any) (*ConvertRequest, error) {
 			return nil, fmt.Errorf("file_path parameter cannot be empty")
 		}
 		// Validate that the file path is absolute
-		if !strings.HasPrefix(request.FilePath, "/") {
+		if !filepath.IsAbs(request.FilePath) {
 			return nil, fmt.Errorf("file_path must be a fully qualified absolute path, got: %s", request.FilePath)
 		}
 	}
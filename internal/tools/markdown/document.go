@@ -0,0 +1,271 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// fence opens or closes a fenced code block
+	fence = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	// atxHeading is a heading written with leading #s, with any closing #s left out of the text
+	atxHeading = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	// setextUnderline underlines the line before it as a level 1 (=) or level 2 (-) heading
+	setextUnderline = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	// blockStart is a line that starts a block other than a paragraph, so cannot be a setext heading
+	blockStart = regexp.MustCompile(`^ {0,3}([-*+>|]|\d+[.)]|<)`)
+	// listItem starts a bullet or numbered list item
+	listItem = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+	// customID is an explicit heading ID, as in "## Setup {#setup}"
+	customID = regexp.MustCompile(`\s*\{#([^}\s]+)\}\s*$`)
+	// htmlAnchor is an HTML element with an id or name that links can point to
+	htmlAnchor = regexp.MustCompile(`<[A-Za-z][^>]*?\s(?:id|name)\s*=\s*["']([^"']+)["']`)
+
+	// inlineLink is a link or image with its target, which may be in angle brackets and followed
+	// by a title
+	inlineLink = regexp.MustCompile(`!?\[((?:[^\[\]]|\[[^\]]*\])*)\]\(\s*(<[^>]*>|[^)\s]*)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+	// referenceDefinition defines a reference-style link target
+	referenceDefinition = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(<[^>]*>|\S+)`)
+	// htmlLink is a link or image target in HTML
+	htmlLink = regexp.MustCompile(`<(?:a|img|source)\s[^>]*?(?:href|src)\s*=\s*["']([^"']*)["']`)
+	// htmlTag is an HTML tag
+	htmlTag = regexp.MustCompile(`</?[A-Za-z][^>]*>`)
+	// autolink is a URL or email address in angle brackets
+	autolink = regexp.MustCompile(`<[A-Za-z][A-Za-z0-9+.-]*:[^>\s]*>|<[^>\s@]+@[^>\s]+>`)
+	// emphasis is the markup around emphasised words
+	emphasis = regexp.MustCompile(`(^|[^\w])[*_]+|[*_]+([^\w]|$)`)
+)
+
+// heading is a heading in a document
+type heading struct {
+	line  int
+	level int
+	// text is the heading as written, without its markup
+	text string
+	// markup is the heading's inline Markdown, such as code spans and emphasis, used in a table of
+	// contents
+	markup string
+	slug   string
+}
+
+// link is a link or image target in a document
+type link struct {
+	line   int
+	column int
+	target string
+}
+
+// document is a Markdown file split into lines, with the parts that are not prose marked
+type document struct {
+	lines []string
+	// frontMatter is the number of lines of front matter at the start, including its delimiters
+	frontMatter int
+	// code marks lines in fenced or indented code blocks
+	code     []bool
+	headings []heading
+	// anchors are the IDs links can point to: heading slugs, explicit IDs and HTML anchors
+	anchors map[string]bool
+}
+
+// parse splits content into lines and finds its front matter, code blocks and headings
+func parse(content string) *document {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	d := &document{lines: lines, code: make([]bool, len(lines)), anchors: map[string]bool{}}
+	d.frontMatter = frontMatterEnd(lines)
+
+	var fenceMarker string
+	prevBlank := true
+	slugs := map[string]int{}
+	for i := d.frontMatter; i < len(lines); i++ {
+		line := lines[i]
+		blank := strings.TrimSpace(line) == ""
+		switch {
+		case fenceMarker != "":
+			d.code[i] = true
+			if match := fence.FindStringSubmatch(line); match != nil && match[1][0] == fenceMarker[0] && len(match[1]) >= len(fenceMarker) && strings.TrimSpace(line[len(match[0]):]) == "" {
+				fenceMarker = ""
+			}
+			continue
+		case fence.MatchString(line):
+			fenceMarker = fence.FindStringSubmatch(line)[1]
+			d.code[i] = true
+			continue
+		case !blank && prevBlank && isIndented(line) && !d.inList(i):
+			// An indented code block runs until a line that is neither blank nor indented
+			for ; i < len(lines) && (isIndented(lines[i]) || strings.TrimSpace(lines[i]) == ""); i++ {
+				d.code[i] = true
+			}
+			i--
+			continue
+		}
+		for _, match := range htmlAnchor.FindAllStringSubmatch(line, -1) {
+			d.anchors[match[1]] = true
+		}
+		level, text, at := 0, "", i
+		if match := atxHeading.FindStringSubmatch(line); match != nil {
+			level, text = len(match[1]), match[2]
+		} else if i+1 < len(lines) && !blank && !isIndented(line) && !blockStart.MatchString(line) && setextUnderline.MatchString(lines[i+1]) && (prevBlank || d.isHeading(i-1)) {
+			level, text = 1, strings.TrimSpace(line)
+			if strings.Contains(lines[i+1], "-") {
+				level = 2
+			}
+			i++
+		}
+		if level > 0 {
+			h := heading{line: at, level: level}
+			if match := customID.FindStringSubmatchIndex(text); match != nil {
+				h.slug = text[match[2]:match[3]]
+				text = text[:match[0]]
+			}
+			h.markup = linkText(text)
+			h.text = plain(h.markup)
+			if h.slug == "" {
+				base := slugify(h.text)
+				h.slug = base
+				if n := slugs[base]; n > 0 {
+					h.slug = fmt.Sprintf("%s-%d", base, n)
+				}
+				slugs[base]++
+			}
+			d.anchors[h.slug] = true
+			d.headings = append(d.headings, h)
+		}
+		prevBlank = blank
+	}
+	return d
+}
+
+// isHeading reports whether line i is an ATX heading
+func (d *document) isHeading(i int) bool {
+	return i >= 0 && atxHeading.MatchString(d.lines[i])
+}
+
+// inList reports whether an indented line continues a list item, looking back past blank and
+// indented lines to the last line that is neither
+func (d *document) inList(i int) bool {
+	for j := i - 1; j >= d.frontMatter; j-- {
+		line := d.lines[j]
+		if strings.TrimSpace(line) == "" || isIndented(line) {
+			if listItem.MatchString(line) {
+				return true
+			}
+			continue
+		}
+		return listItem.MatchString(line)
+	}
+	return false
+}
+
+// prose returns line i with inline code masked by spaces, or "" for front matter and code blocks
+func (d *document) prose(i int) string {
+	if i < d.frontMatter || d.code[i] {
+		return ""
+	}
+	return maskInlineCode(d.lines[i])
+}
+
+// links returns the link and image targets outside code
+func (d *document) links() []link {
+	var links []link
+	for i := range d.lines {
+		line := d.prose(i)
+		if line == "" {
+			continue
+		}
+		add := func(start, end int) {
+			target := strings.TrimSuffix(strings.TrimPrefix(line[start:end], "<"), ">")
+			links = append(links, link{line: i, column: runeColumn(line, start), target: target})
+		}
+		if match := referenceDefinition.FindStringSubmatchIndex(line); match != nil {
+			add(match[2], match[3])
+			continue
+		}
+		for _, match := range inlineLink.FindAllStringSubmatchIndex(line, -1) {
+			add(match[4], match[5])
+		}
+		for _, match := range htmlLink.FindAllStringSubmatchIndex(line, -1) {
+			add(match[2], match[3])
+		}
+	}
+	return links
+}
+
+// frontMatterEnd returns the number of lines of front matter at the start of a document: YAML
+// between --- lines, or TOML between +++ lines
+func frontMatterEnd(lines []string) int {
+	if len(lines) == 0 || (lines[0] != "---" && lines[0] != "+++") {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == lines[0] || (lines[0] == "---" && lines[i] == "...") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// maskInlineCode replaces code spans, which open and close with backtick runs of the same length,
+// with spaces
+func maskInlineCode(line string) string {
+	b := []byte(line)
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(b) && b[i+run] == '`' {
+			run++
+		}
+		end := strings.Index(string(b[i+run:]), strings.Repeat("`", run))
+		if end < 0 {
+			i += run
+			continue
+		}
+		end += i + 2*run
+		for j := i; j < end; j++ {
+			b[j] = ' '
+		}
+		i = end
+	}
+	return string(b)
+}
+
+// linkText replaces links and images in heading text with their text, and removes HTML tags
+func linkText(text string) string {
+	text = inlineLink.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(htmlTag.ReplaceAllString(text, ""))
+}
+
+// plain removes code span backticks and emphasis from heading text
+func plain(text string) string {
+	text = strings.ReplaceAll(text, "`", "")
+	return emphasis.ReplaceAllString(text, "$1$2")
+}
+
+// slugify returns the anchor GitHub gives a heading: lower case, with punctuation other than
+// hyphens and underscores removed and spaces replaced by hyphens
+func slugify(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// isIndented reports whether a line is indented enough to be code: four spaces or a tab
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// runeColumn returns the 1-based character column of a byte offset in line
+func runeColumn(line string, offset int) int {
+	return len([]rune(line[:offset])) + 1
+}
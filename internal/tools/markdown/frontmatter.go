@@ -0,0 +1,200 @@
+package markdown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/jsonschema-go/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorLine starts a YAML error with its line, counted from the start of the front matter
+var yamlErrorLine = regexp.MustCompile(`^line (\d+): `)
+
+// frontMatter parses a document's front matter into JSON values. It returns nil when there is no
+// front matter, and with an error, the 1-based line of the problem.
+func frontMatter(d *document) (map[string]any, int, error) {
+	if d.frontMatter == 0 {
+		return nil, 0, nil
+	}
+	body := strings.Join(d.lines[1:d.frontMatter-1], "\n")
+	if d.lines[0] == "+++" {
+		values := map[string]any{}
+		if _, err := toml.Decode(body, &values); err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, parseErr.Position.Line + 1, fmt.Errorf("invalid TOML: %s", parseErr.Message)
+			}
+			return nil, 1, fmt.Errorf("invalid TOML: %w", err)
+		}
+		return jsonValue(values).(map[string]any), 0, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &node); err != nil {
+		line, message := 1, strings.TrimPrefix(err.Error(), "yaml: ")
+		if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			line += n
+			message = message[len(match[0]):]
+		}
+		return nil, line, fmt.Errorf("invalid YAML: %s", message)
+	}
+	if len(node.Content) == 0 {
+		// Empty front matter
+		return map[string]any{}, 0, nil
+	}
+	value, err := yamlValue(node.Content[0])
+	if err != nil {
+		return nil, node.Content[0].Line + 1, err
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		return nil, node.Content[0].Line + 1, fmt.Errorf("front matter must be a mapping of fields to values")
+	}
+	return values, 0, nil
+}
+
+// yamlValue converts a YAML node to a JSON value. Dates and times are kept as they were written,
+// as schemas check them as strings.
+func yamlValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.MappingNode:
+		values := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Kind == yaml.AliasNode || node.Content[i].Tag == "!!merge" {
+				return nil, fmt.Errorf("line %d: merge keys and aliases as keys are not supported", node.Content[i].Line)
+			}
+			value, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			values[node.Content[i].Value] = value
+		}
+		return values, nil
+	case yaml.SequenceNode:
+		values := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			value, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!timestamp" {
+			return node.Value, nil
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		return value, nil
+	}
+	return nil, nil
+}
+
+// jsonValue converts TOML values to JSON values, writing dates and times as strings
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = jsonValue(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = jsonValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = jsonValue(item)
+		}
+		return out
+	case time.Time:
+		// Local dates and times are decoded in zones named for them
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format(time.DateOnly)
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// loadSchema parses a JSON Schema written as JSON or YAML
+func loadSchema(text string) (*jsonschema.Resolved, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &node); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if len(node.Content) == 0 {
+		return nil, fmt.Errorf("schema is empty")
+	}
+	value, err := yamlValue(node.Content[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return resolved, nil
+}
+
+// checkFrontMatter checks one document's front matter against the schema, if there is one,
+// returning the issue found, or nil
+func checkFrontMatter(d *document, name string, schema *jsonschema.Resolved, fields map[string]int) *Issue {
+	values, line, err := frontMatter(d)
+	if err != nil {
+		return &Issue{File: name, Line: line, Rule: "front-matter", Message: err.Error()}
+	}
+	if values == nil {
+		if schema == nil {
+			return &Issue{File: name, Line: 1, Rule: "front-matter", Message: "No front matter"}
+		}
+		values = map[string]any{}
+	}
+	for key := range values {
+		fields[key]++
+	}
+	if schema == nil {
+		return nil
+	}
+	if err := schema.Validate(values); err != nil {
+		return &Issue{File: name, Line: 1, Rule: "front-matter", Message: strings.TrimPrefix(err.Error(), "validating root: ")}
+	}
+	return nil
+}
+
+// FrontMatter returns the fields of a Markdown document's YAML or TOML front matter, or nil when
+// it has none
+func FrontMatter(content string) (map[string]any, error) {
+	values, _, err := frontMatter(parse(content))
+	return values, err
+}
@@ -0,0 +1,65 @@
+package markdown
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Link is a link target found in a Markdown document
+type Link struct {
+	// Line and Column are 1-based, with Column counted in characters
+	Line   int
+	Column int
+	Target string
+}
+
+// Links returns the targets of the links, images, autolinks and bare URLs in Markdown content,
+// leaving out front matter and code, in the order they appear
+func Links(content string) []Link {
+	d := parse(content)
+	var links []Link
+	for _, lk := range d.links() {
+		links = append(links, Link{Line: lk.line + 1, Column: lk.column, Target: lk.target})
+	}
+	for i := range d.lines {
+		line := d.prose(i)
+		if line == "" || referenceDefinition.MatchString(line) {
+			continue
+		}
+		// Inline link targets may be in angle brackets, so are masked before finding autolinks
+		unlinked := inlineLink.ReplaceAllStringFunc(line, func(s string) string { return strings.Repeat(" ", len(s)) })
+		for _, match := range autolink.FindAllStringIndex(unlinked, -1) {
+			target := line[match[0]+1 : match[1]-1]
+			if !scheme.MatchString(target) {
+				// An email address
+				target = "mailto:" + target
+			}
+			links = append(links, Link{Line: i + 1, Column: runeColumn(line, match[0]+1), Target: target})
+		}
+		for _, match := range bareURL.FindAllStringIndex(maskLinks(line), -1) {
+			links = append(links, Link{Line: i + 1, Column: runeColumn(line, match[0]), Target: line[match[0]:match[1]]})
+		}
+	}
+	// Autolinks and bare URLs were found after the other links, so put them back in document order
+	slices.SortStableFunc(links, func(a, b Link) int {
+		return cmp.Or(a.Line-b.Line, a.Column-b.Column)
+	})
+	return links
+}
+
+// Anchors returns the anchors links to a Markdown document can point to: heading anchors as GitHub
+// generates them, {#id} heading IDs and the ids and names of HTML elements
+func Anchors(content string) map[string]bool {
+	return parse(content).anchors
+}
+
+// HasAnchor reports whether anchor is among anchors, allowing for GitHub lower-casing heading anchors
+func HasAnchor(anchors map[string]bool, anchor string) bool {
+	return hasAnchor(anchors, anchor)
+}
+
+// IsMarkdownFile reports whether a file has a Markdown extension
+func IsMarkdownFile(name string) bool {
+	return isMarkdownFile(name)
+}
@@ -0,0 +1,199 @@
+package markdown
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// rules are the lint checks available
+var rules = []string{"heading-increment", "multiple-h1", "broken-link", "broken-anchor", "bare-url", "line-length"}
+
+var (
+	// scheme starts an absolute URL, such as https: or mailto:
+	scheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+	// bareURL is a web address written as plain text
+	bareURL = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+[^\s<>()\[\]"'.,;:!?*_` + "`" + `]`)
+)
+
+// linter checks files and gathers their issues
+type linter struct {
+	rules         []string
+	maxLineLength int
+	maxIssues     int
+	// repoRoot is the directory links starting with / are resolved from, or "" when there is none
+	repoRoot string
+
+	counts map[string]int
+	issues []Issue
+	// anchors caches the anchors of each linked Markdown file, by absolute path
+	anchors map[string]map[string]bool
+}
+
+// newLinter returns a linter for the requested rules
+func newLinter(selected []string, maxLineLength, maxIssues int, repoRoot string) *linter {
+	l := &linter{rules: selected, maxLineLength: maxLineLength, maxIssues: maxIssues, repoRoot: repoRoot, counts: map[string]int{}, anchors: map[string]map[string]bool{}}
+	for _, rule := range selected {
+		l.counts[rule] = 0
+	}
+	return l
+}
+
+// lint checks one parsed file, reporting it by name
+func (l *linter) lint(path, name string, d *document) {
+	start := len(l.issues)
+	defer func() {
+		// Rules are checked in turn, so put the file's issues in line order
+		slices.SortStableFunc(l.issues[start:], func(a, b Issue) int {
+			return cmp.Or(a.Line-b.Line, a.Column-b.Column)
+		})
+	}()
+	l.anchors[path] = d.anchors
+	l.headings(name, d)
+	if slices.Contains(l.rules, "broken-link") || slices.Contains(l.rules, "broken-anchor") {
+		for _, lk := range d.links() {
+			l.link(path, name, d, lk)
+		}
+	}
+	for i := range d.lines {
+		line := d.prose(i)
+		if line == "" {
+			continue
+		}
+		if slices.Contains(l.rules, "bare-url") && !referenceDefinition.MatchString(line) {
+			for _, match := range bareURL.FindAllStringIndex(maskLinks(line), -1) {
+				l.add(name, i, runeColumn(line, match[0]), "bare-url", fmt.Sprintf("Bare URL %s; put it in angle brackets or make it a link", line[match[0]:match[1]]))
+			}
+		}
+		if slices.Contains(l.rules, "line-length") && l.maxLineLength > 0 {
+			l.lineLength(name, d.lines[i], i)
+		}
+	}
+}
+
+// headings checks that heading levels go up one at a time and there is one level 1 heading
+func (l *linter) headings(name string, d *document) {
+	prev, h1 := 0, 0
+	for _, h := range d.headings {
+		if slices.Contains(l.rules, "heading-increment") && prev > 0 && h.level > prev+1 {
+			l.add(name, h.line, 1, "heading-increment", fmt.Sprintf("Heading level %d follows level %d; use level %d", h.level, prev, prev+1))
+		}
+		if h.level == 1 {
+			h1++
+			if h1 > 1 && slices.Contains(l.rules, "multiple-h1") {
+				l.add(name, h.line, 1, "multiple-h1", fmt.Sprintf("Another level 1 heading %q; a document should have one title", h.text))
+			}
+		}
+		prev = h.level
+	}
+}
+
+// link checks that a relative link's file exists, and that its anchor is a heading or ID there
+func (l *linter) link(path, name string, d *document, lk link) {
+	target := lk.target
+	if target == "" || scheme.MatchString(target) || strings.HasPrefix(target, "//") {
+		return
+	}
+	file, anchor, _ := strings.Cut(target, "#")
+	file, _, _ = strings.Cut(file, "?")
+	if decoded, err := url.PathUnescape(anchor); err == nil {
+		anchor = decoded
+	}
+	if file == "" {
+		if anchor != "" && slices.Contains(l.rules, "broken-anchor") && !hasAnchor(d.anchors, anchor) {
+			l.add(name, lk.line, lk.column, "broken-anchor", fmt.Sprintf("No heading or ID %q in this file", "#"+anchor))
+		}
+		return
+	}
+	if decoded, err := url.PathUnescape(file); err == nil {
+		file = decoded
+	}
+	var resolved string
+	switch {
+	case strings.HasPrefix(file, "/") && l.repoRoot == "":
+		// Without a repository root there is nothing to resolve the link from
+		return
+	case strings.HasPrefix(file, "/"):
+		resolved = filepath.Join(l.repoRoot, filepath.FromSlash(file))
+	default:
+		resolved = filepath.Join(filepath.Dir(path), filepath.FromSlash(file))
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		if slices.Contains(l.rules, "broken-link") {
+			l.add(name, lk.line, lk.column, "broken-link", fmt.Sprintf("Link target %s does not exist", file))
+		}
+		return
+	}
+	if anchor == "" || info.IsDir() || !isMarkdownFile(resolved) || !slices.Contains(l.rules, "broken-anchor") {
+		return
+	}
+	anchors, ok := l.anchors[resolved]
+	if !ok {
+		if security.CheckFileAccess(resolved) != nil {
+			return
+		}
+		content, err := readFile(resolved)
+		if err != nil {
+			return
+		}
+		anchors = parse(content).anchors
+		l.anchors[resolved] = anchors
+	}
+	if !hasAnchor(anchors, anchor) {
+		l.add(name, lk.line, lk.column, "broken-anchor", fmt.Sprintf("No heading or ID %q in %s", "#"+anchor, file))
+	}
+}
+
+// lineLength reports a line longer than the limit. Tables, and lines whose last word starts within
+// the limit, such as a long URL, are allowed as they cannot be wrapped.
+func (l *linter) lineLength(name, line string, i int) {
+	length := utf8.RuneCountInString(line)
+	if length <= l.maxLineLength || strings.HasPrefix(strings.TrimSpace(line), "|") || referenceDefinition.MatchString(line) {
+		return
+	}
+	if overflow := string([]rune(line)[l.maxLineLength:]); !strings.ContainsAny(overflow, " \t") {
+		return
+	}
+	l.add(name, i, l.maxLineLength+1, "line-length", fmt.Sprintf("Line is %d characters, more than %d", length, l.maxLineLength))
+}
+
+// add records an issue at a 0-based line, while counting issues past the limit
+func (l *linter) add(name string, line, column int, rule, message string) {
+	l.counts[rule]++
+	if len(l.issues) >= l.maxIssues {
+		return
+	}
+	l.issues = append(l.issues, Issue{File: name, Line: line + 1, Column: column, Rule: rule, Message: message})
+}
+
+// truncated reports whether issues were left out
+func (l *linter) truncated() bool {
+	total := 0
+	for _, count := range l.counts {
+		total += count
+	}
+	return total > len(l.issues)
+}
+
+// maskLinks replaces links, autolinks and HTML tags with spaces, so the URLs left are bare
+func maskLinks(line string) string {
+	for _, re := range []*regexp.Regexp{inlineLink, autolink, htmlTag} {
+		line = re.ReplaceAllStringFunc(line, func(s string) string { return strings.Repeat(" ", len(s)) })
+	}
+	return line
+}
+
+// hasAnchor reports whether an anchor is among anchors. GitHub lower-cases heading anchors, so links
+// to them in other cases still work.
+func hasAnchor(anchors map[string]bool, anchor string) bool {
+	return anchors[anchor] || anchors[strings.ToLower(anchor)]
+}
@@ -0,0 +1,501 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/textdiff"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxLineLength = 120
+	defaultMaxIssues     = 200
+	maxIssuesLimit       = 2000
+	defaultMinLevel      = 2
+	defaultMaxLevel      = 3
+	// maxFiles is the most files checked in a directory
+	maxFiles = 500
+	// maxFileBytes is the largest file checked
+	maxFileBytes = 1024 * 1024
+	// maxSchemaBytes is the largest schema file read
+	maxSchemaBytes = 1024 * 1024
+)
+
+var (
+	// functions are the operations the tool can run
+	functions = []string{"lint", "toc", "front_matter"}
+
+	// markdownExtensions are the files checked in a directory
+	markdownExtensions = []string{".md", ".markdown", ".mdx"}
+
+	// skippedDirs are dependency and build output directories not checked
+	skippedDirs = []string{"node_modules", "vendor", "dist", "build", "target", "__pycache__", "venv", "coverage"}
+)
+
+// MarkdownTool lints Markdown, maintains tables of contents and validates front matter
+type MarkdownTool struct{}
+
+// init registers the markdown tool
+func init() {
+	registry.Register(&MarkdownTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *MarkdownTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"markdown",
+		mcp.WithDescription(`Check and tidy Markdown files, such as docs written by hand or converted with process_document.
+
+lint: report heading levels that skip (## then ####), extra level 1 headings, relative links to missing files or headings, bare URLs and long lines, with file, line and column.
+toc: generate a table of contents from a file's headings and update it in place, between <!-- toc --> comments, under a "Table of Contents" heading, or inserted before the first heading listed.
+front_matter: check that each file's YAML or TOML front matter parses and, given a JSON Schema, has the fields and values it requires.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to run: "+strings.Join(functions, ", ")),
+			mcp.Enum(functions...),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path of a Markdown file, or for lint and front_matter a directory whose Markdown files are checked"),
+		),
+		mcp.WithString("rules",
+			mcp.Description("lint: comma-separated rules to check: "+strings.Join(rules, ", ")+" (default: all, with line-length only when max_line_length is set)"),
+		),
+		mcp.WithNumber("max_line_length",
+			mcp.Description(fmt.Sprintf("lint: longest line allowed in characters, which also turns on the line-length rule (default: %d when line-length is listed in rules)", defaultMaxLineLength)),
+		),
+		mcp.WithNumber("min_level",
+			mcp.Description(fmt.Sprintf("toc: shallowest heading level listed (default: %d)", defaultMinLevel)),
+		),
+		mcp.WithNumber("max_level",
+			mcp.Description(fmt.Sprintf("toc: deepest heading level listed (default: %d)", defaultMaxLevel)),
+		),
+		mcp.WithBoolean("preview",
+			mcp.Description("toc: return the table of contents and diff without changing the file (default: false)"),
+		),
+		mcp.WithString("schema",
+			mcp.Description("front_matter: JSON Schema, as JSON or YAML, that each file's front matter must match"),
+		),
+		mcp.WithString("schema_path",
+			mcp.Description("front_matter: absolute path of a JSON or YAML file containing the JSON Schema, instead of schema"),
+		),
+		mcp.WithNumber("max_issues",
+			mcp.Description(fmt.Sprintf("lint and front_matter: most issues to return (default: %d, max: %d)", defaultMaxIssues, maxIssuesLimit)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // toc writes the table of contents into the file
+		mcp.WithDestructiveHintAnnotation(true), // toc replaces an existing table of contents
+		mcp.WithIdempotentHintAnnotation(true),  // Updating an up to date table of contents changes nothing
+		mcp.WithOpenWorldHintAnnotation(false),  // Reads and writes local files only
+	)
+}
+
+// Execute runs the requested operation
+func (t *MarkdownTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	function = strings.ToLower(strings.TrimSpace(function))
+	if !slices.Contains(functions, function) {
+		return nil, fmt.Errorf("function must be one of %s, got: %q", strings.Join(functions, ", "), function)
+	}
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	root, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path: %w", err)
+	}
+	maxIssues := defaultMaxIssues
+	if value, ok := args["max_issues"].(float64); ok {
+		if value < 1 {
+			return nil, fmt.Errorf("max_issues must be at least 1, got: %v", value)
+		}
+		maxIssues = min(int(value), maxIssuesLimit)
+	}
+	logger.WithFields(logrus.Fields{"function": function, "path": root}).Debug("Running markdown tool")
+
+	var result any
+	switch function {
+	case "lint":
+		result, err = lintFiles(ctx, root, info.IsDir(), args, maxIssues)
+	case "toc":
+		if info.IsDir() {
+			return nil, fmt.Errorf("toc works on one file, but %s is a directory", root)
+		}
+		result, err = updateTOC(root, info.Mode().Perm(), args)
+	case "front_matter":
+		result, err = checkFiles(ctx, root, info.IsDir(), args, maxIssues)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Headings, URLs and front matter are quoted from the files, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "markdown",
+		URL:         "file://" + root,
+		ContentType: "text",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// lintFiles lints a file or the Markdown files in a directory
+func lintFiles(ctx context.Context, root string, isDir bool, args map[string]any, maxIssues int) (*LintResult, error) {
+	selected, err := list(args, "rules", rules)
+	if err != nil {
+		return nil, err
+	}
+	maxLineLength := defaultMaxLineLength
+	value, limited := args["max_line_length"].(float64)
+	if limited {
+		if value < 0 {
+			return nil, fmt.Errorf("max_line_length must be 0 or more, got: %v", value)
+		}
+		maxLineLength = int(value)
+	}
+	if len(selected) == 0 {
+		// Many projects do not wrap Markdown, so line length is only checked when asked for
+		selected = slices.DeleteFunc(slices.Clone(rules), func(rule string) bool {
+			return rule == "line-length" && (!limited || maxLineLength == 0)
+		})
+	}
+	dir := root
+	if !isDir {
+		dir = filepath.Dir(root)
+	}
+	l := newLinter(selected, maxLineLength, maxIssues, findRepoRoot(dir))
+	result := &LintResult{}
+	err = eachFile(ctx, root, isDir, &result.Files, &result.Warnings, l.lint)
+	if err != nil {
+		return nil, err
+	}
+	result.Counts = l.counts
+	result.Issues = l.issues
+	if result.Issues == nil {
+		result.Issues = []Issue{}
+	}
+	if l.truncated() {
+		result.Truncated = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d issues are listed. Raise max_issues, check fewer rules or lint a subdirectory to see the rest.", maxIssues))
+	}
+	return result, nil
+}
+
+// updateTOC generates a file's table of contents and writes it unless previewing
+func updateTOC(path string, mode fs.FileMode, args map[string]any) (*TOCResult, error) {
+	minLevel, err := level(args, "min_level", defaultMinLevel)
+	if err != nil {
+		return nil, err
+	}
+	maxLevel, err := level(args, "max_level", defaultMaxLevel)
+	if err != nil {
+		return nil, err
+	}
+	if minLevel > maxLevel {
+		return nil, fmt.Errorf("min_level (%d) must not be more than max_level (%d)", minLevel, maxLevel)
+	}
+	preview, _ := args["preview"].(bool)
+	original, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines, result, err := toc(parse(original), minLevel, maxLevel)
+	if err != nil {
+		return nil, err
+	}
+	updated := strings.Join(lines, "\n")
+	if strings.Contains(original, "\r\n") {
+		updated = strings.ReplaceAll(updated, "\n", "\r\n")
+	}
+	result.File = path
+	result.Changed = updated != original
+	if !result.Changed {
+		return result, nil
+	}
+	name := filepath.Base(path)
+	result.Diff, _, _ = textdiff.UnifiedDiff(original, updated, "a/"+name, "b/"+name, 3)
+	if !preview {
+		if err := os.WriteFile(path, []byte(updated), mode); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		result.Written = true
+	}
+	return result, nil
+}
+
+// checkFiles checks the front matter of a file or the Markdown files in a directory
+func checkFiles(ctx context.Context, root string, isDir bool, args map[string]any, maxIssues int) (*FrontMatterResult, error) {
+	schemaText, _ := args["schema"].(string)
+	schemaPath, _ := args["schema_path"].(string)
+	if schemaText != "" && schemaPath != "" {
+		return nil, fmt.Errorf("provide schema or schema_path, not both")
+	}
+	if schemaPath != "" {
+		resolved, err := workspace.ResolveToolPath(ctx, "schema_path", schemaPath)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(resolved); err == nil && info.Size() > maxSchemaBytes {
+			return nil, fmt.Errorf("schema file is larger than %d MB", maxSchemaBytes/1024/1024)
+		}
+		data, err := os.ReadFile(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		schemaText = string(data)
+	}
+	var schema *jsonschema.Resolved
+	if strings.TrimSpace(schemaText) != "" {
+		var err error
+		if schema, err = loadSchema(schemaText); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &FrontMatterResult{Issues: []Issue{}, Fields: map[string]int{}}
+	err := eachFile(ctx, root, isDir, &result.Files, &result.Warnings, func(path, name string, d *document) {
+		issue := checkFrontMatter(d, name, schema, result.Fields)
+		if issue == nil {
+			result.Valid++
+			return
+		}
+		result.Invalid++
+		if len(result.Issues) < maxIssues {
+			result.Issues = append(result.Issues, *issue)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Invalid > len(result.Issues) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d issues are listed. Raise max_issues or check a subdirectory to see the rest.", maxIssues))
+	}
+	return result, nil
+}
+
+// eachFile parses a file, or each Markdown file in a directory, and passes it to fn with its name
+// in results: the file's path, or its path relative to the directory
+func eachFile(ctx context.Context, root string, isDir bool, count *int, warnings *[]string, fn func(path, name string, d *document)) error {
+	if !isDir {
+		content, err := readFile(root)
+		if err != nil {
+			return err
+		}
+		fn(root, root, parse(content))
+		*count = 1
+		return nil
+	}
+	files, found, err := findFiles(root)
+	if err != nil {
+		return err
+	}
+	*warnings = append(*warnings, found...)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		content, err := readFile(file)
+		if err != nil {
+			*warnings = append(*warnings, err.Error())
+			continue
+		}
+		name, _ := filepath.Rel(root, file)
+		fn(file, filepath.ToSlash(name), parse(content))
+		*count++
+	}
+	if *count == 0 {
+		*warnings = append(*warnings, fmt.Sprintf("No Markdown files (%s) were found.", strings.Join(markdownExtensions, ", ")))
+	}
+	return nil
+}
+
+// findFiles returns the Markdown files under root, leaving out hidden and dependency directories,
+// with warnings for files left out
+func findFiles(root string) ([]string, []string, error) {
+	var files, warnings []string
+	skipped := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			warnings = append(warnings, fmt.Sprintf("Could not read %s: %v", path, err))
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !isMarkdownFile(name) {
+			return nil
+		}
+		if security.CheckFileAccess(path) != nil {
+			skipped++
+			return nil
+		}
+		if len(files) == maxFiles {
+			warnings = append(warnings, fmt.Sprintf("Only the first %d files were checked. Check a subdirectory to see the rest.", maxFiles))
+			return filepath.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if skipped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files were not checked because the security policy denies access to them.", skipped))
+	}
+	return files, warnings, nil
+}
+
+// readFile reads a Markdown file, refusing large ones
+func readFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if info.Size() > maxFileBytes {
+		return "", fmt.Errorf("%s was not checked because it is larger than %d MB", path, maxFileBytes/1024/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// isMarkdownFile reports whether a file has a Markdown extension
+func isMarkdownFile(name string) bool {
+	return slices.Contains(markdownExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+// findRepoRoot returns the nearest directory at or above dir containing .git, or "" when there is
+// none
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// level reads a heading level parameter
+func level(args map[string]any, key string, fallback int) (int, error) {
+	value, ok := args[key].(float64)
+	if !ok {
+		return fallback, nil
+	}
+	if value < 1 || value > 6 || value != float64(int(value)) {
+		return 0, fmt.Errorf("%s must be a heading level from 1 to 6, got: %v", key, value)
+	}
+	return int(value), nil
+}
+
+// list reads a comma-separated parameter whose entries must be among allowed
+func list(args map[string]any, key string, allowed []string) ([]string, error) {
+	raw, _ := args[key].(string)
+	var values []string
+	for value := range strings.SplitSeq(raw, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(allowed, value) {
+			return nil, fmt.Errorf("unknown %s entry %q: must be one of %s", key, value, strings.Join(allowed, ", "))
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the markdown tool
+func (t *MarkdownTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "After writing or converting documentation (for example with process_document), before committing docs changes, when restructuring headings, or when a docs site needs consistent front matter.",
+		WhenNotToUse: "Spelling and grammar - use proofread. Checking external links - use check_links, as lint only checks relative links to files and headings. Formatting Markdown - use a formatter such as prettier through the lint tool.",
+		CommonPatterns: []string{
+			"Lint a docs directory, fix the broken links and heading levels it reports, then lint again",
+			"Run toc with preview=true to see the table of contents, then without preview to write it",
+			"Run front_matter without a schema to see which fields files use (fields), then write a schema requiring them",
+			"Lint with rules='broken-link,broken-anchor' after renaming files or headings",
+		},
+		ParameterDetails: map[string]string{
+			"rules":           "heading-increment: a heading more than one level below the one before. multiple-h1: more than one level 1 heading. broken-link: a relative link or image whose file does not exist (links starting with / are resolved from the repository root). broken-anchor: a #fragment that is not a heading, {#id} or HTML id in the linked file. bare-url: an http(s) URL that is not a link. line-length: lines over max_line_length, except tables, code and lines whose last word starts within the limit.",
+			"toc":             "Headings are linked with GitHub's anchors. An existing table of contents between <!-- toc --> and <!-- tocstop --> (or <!-- /toc -->) comments, or under a heading named Table of Contents, Contents or TOC, is replaced. Otherwise a '## Table of Contents' heading and list are inserted before the first heading listed. The file is only written when the table of contents changed.",
+			"schema":          "A JSON Schema (draft 2020-12 or draft-07) for the front matter as an object, for example {\"type\": \"object\", \"required\": [\"title\"], \"properties\": {\"title\": {\"type\": \"string\"}, \"date\": {\"type\": \"string\", \"format\": \"date\"}}}. Dates are checked as the strings written. Each file reports the first mismatch found. Without a schema, files with no front matter or front matter that does not parse are reported.",
+			"max_line_length": "Lines are not checked by default, as many projects do not wrap Markdown. Set max_line_length, or list line-length in rules for the default of 120.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Lint a project's documentation",
+				Arguments:      map[string]any{"function": "lint", "path": "/home/user/project/docs"},
+				ExpectedResult: "Issues with file, line, column, rule and message, and counts for each rule",
+			},
+			{
+				Description:    "Update a README's table of contents",
+				Arguments:      map[string]any{"function": "toc", "path": "/home/user/project/README.md", "max_level": 3},
+				ExpectedResult: "The table of contents, where it was placed, whether it changed and a diff of the change",
+			},
+			{
+				Description:    "Check blog posts have a title and date",
+				Arguments:      map[string]any{"function": "front_matter", "path": "/home/user/blog/content/posts", "schema": `{"type": "object", "required": ["title", "date"]}`},
+				ExpectedResult: "Counts of valid and invalid files, with an issue for each invalid file",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A link to a heading is reported as broken but works on another site",
+				Solution: "Anchors follow GitHub's rules. Sites that generate anchors differently may need an explicit {#id} on the heading.",
+			},
+			{
+				Problem:  "Links starting with / are not checked",
+				Solution: "They are resolved from the repository root, found by looking for a .git directory above the path. Outside a repository they are skipped.",
+			},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package markdown
+
+import "strings"
+
+// Section is a heading and the text under it, up to the next heading. Text before the first
+// heading is a section with no heading.
+type Section struct {
+	Heading string
+	Level   int
+	// Line is the 1-based line the section starts on
+	Line int
+	// Text is the section's content with links reduced to their text and HTML tags removed.
+	// Code is kept, as docs are often searched for their examples.
+	Text string
+}
+
+// Sections splits Markdown content into sections at each heading, leaving out front matter and
+// sections with no text or heading
+func Sections(content string) []Section {
+	d := parse(content)
+	var sections []Section
+	add := func(s Section, start, end int) {
+		lines := make([]string, 0, max(end-start, 0))
+		for i := start; i < end; i++ {
+			if d.code[i] {
+				lines = append(lines, d.lines[i])
+			} else {
+				lines = append(lines, linkText(d.lines[i]))
+			}
+		}
+		s.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+		if s.Text != "" || s.Heading != "" {
+			sections = append(sections, s)
+		}
+	}
+	current, start := Section{Line: d.frontMatter + 1}, d.frontMatter
+	for _, h := range d.headings {
+		add(current, start, h.line)
+		current = Section{Heading: h.text, Level: h.level, Line: h.line + 1}
+		start = h.line + 1
+		if !d.isHeading(h.line) {
+			// Past a setext heading's underline
+			start++
+		}
+	}
+	add(current, start, len(d.lines))
+	return sections
+}
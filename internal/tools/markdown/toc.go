@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// tocStart and tocEnd are the comments a table of contents is placed between
+	tocStart = "<!-- toc -->"
+	tocEnd   = "<!-- tocstop -->"
+	// tocTitle is the heading given to a table of contents inserted into a document without one
+	tocTitle = "Table of Contents"
+)
+
+var (
+	// tocStartMarker and tocEndMarker match the comments around a table of contents, in the forms
+	// markdown-toc and other generators write
+	tocStartMarker = regexp.MustCompile(`(?i)^\s*<!--\s*toc\s*-->\s*$`)
+	tocEndMarker   = regexp.MustCompile(`(?i)^\s*<!--\s*(?:tocstop|/toc)\s*-->\s*$`)
+
+	// contentsTitles are headings that introduce a table of contents
+	contentsTitles = []string{"table of contents", "contents", "toc"}
+)
+
+// toc builds a table of contents of the headings from minLevel to maxLevel and puts it in the
+// document, returning the document's new lines and a result describing the table of contents
+func toc(d *document, minLevel, maxLevel int) ([]string, *TOCResult, error) {
+	contents := slices.IndexFunc(d.headings, func(h heading) bool {
+		return slices.Contains(contentsTitles, strings.ToLower(strings.TrimSpace(h.text)))
+	})
+	var listed []heading
+	for i, h := range d.headings {
+		if i != contents && h.level >= minLevel && h.level <= maxLevel {
+			listed = append(listed, h)
+		}
+	}
+	if len(listed) == 0 {
+		return nil, nil, fmt.Errorf("no headings of level %d to %d to list", minLevel, maxLevel)
+	}
+	// Entries are nested under the nearest shallower heading, so skipped levels do not over-indent
+	entries := make([]string, len(listed))
+	var parents []int
+	for i, h := range listed {
+		for len(parents) > 0 && parents[len(parents)-1] >= h.level {
+			parents = parents[:len(parents)-1]
+		}
+		entries[i] = fmt.Sprintf("%s- [%s](#%s)", strings.Repeat("  ", len(parents)), h.markup, h.slug)
+		parents = append(parents, h.level)
+	}
+	block := slices.Concat([]string{""}, entries, []string{""})
+	result := &TOCResult{Headings: len(listed), TOC: strings.Join(entries, "\n")}
+
+	// Between toc comments
+	if start := slices.IndexFunc(d.lines, tocStartMarker.MatchString); start >= 0 && !d.code[start] {
+		result.Placement = "markers"
+		end := start + 1
+		for end < len(d.lines) && !tocEndMarker.MatchString(d.lines[end]) && !d.isHeading(end) {
+			end++
+		}
+		if end == len(d.lines) || !tocEndMarker.MatchString(d.lines[end]) {
+			// Without a closing comment, the old list runs to the next line that is not part of it
+			end = listEnd(d.lines, start+1)
+			return splice(d.lines, start+1, end, slices.Concat(block, []string{tocEnd, ""})), result, nil
+		}
+		return splice(d.lines, start+1, end, block), result, nil
+	}
+
+	// Under a contents heading
+	if contents >= 0 {
+		result.Placement = "heading"
+		h := d.headings[contents]
+		start := h.line + 1
+		if !d.isHeading(h.line) {
+			// Past a setext heading's underline
+			start++
+		}
+		return splice(d.lines, start, listEnd(d.lines, start), block), result, nil
+	}
+
+	// Before the first heading listed
+	result.Placement = "inserted"
+	at := listed[0].line
+	title := fmt.Sprintf("%s %s", strings.Repeat("#", listed[0].level), tocTitle)
+	return splice(d.lines, at, at, slices.Concat([]string{title}, block)), result, nil
+}
+
+// listEnd returns the line after the list and blank lines starting at start
+func listEnd(lines []string, start int) int {
+	end := start
+	for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || listItem.MatchString(lines[end])) {
+		end++
+	}
+	return end
+}
+
+// splice returns lines with lines[start:end] replaced by insert
+func splice(lines []string, start, end int, insert []string) []string {
+	return slices.Concat(lines[:start], insert, lines[end:])
+}
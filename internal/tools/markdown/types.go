@@ -0,0 +1,49 @@
+package markdown
+
+// LintResult lists the problems found in the files linted
+type LintResult struct {
+	Files int `json:"files"`
+	// Counts is the number of issues for each rule checked, including any left out of Issues
+	Counts    map[string]int `json:"counts"`
+	Issues    []Issue        `json:"issues"`
+	Truncated bool           `json:"truncated,omitempty"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// Issue is a problem at a position in a file
+type Issue struct {
+	File string `json:"file"`
+	// Line and Column are 1-based, with Column counted in characters
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// TOCResult is a generated table of contents and where it was put
+type TOCResult struct {
+	File     string `json:"file"`
+	Headings int    `json:"headings"`
+	// Placement is how the table of contents was placed: markers (between <!-- toc --> comments),
+	// heading (under an existing contents heading) or inserted (before the first heading it lists)
+	Placement string `json:"placement"`
+	TOC       string `json:"toc"`
+	// Changed reports whether the file's table of contents was out of date, and Written whether
+	// the file was updated
+	Changed bool   `json:"changed"`
+	Written bool   `json:"written"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// FrontMatterResult reports whether each file's front matter parses and matches the schema
+type FrontMatterResult struct {
+	Files   int `json:"files"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	// Issues has one entry for each file whose front matter is missing, does not parse or does not
+	// match the schema
+	Issues []Issue `json:"issues"`
+	// Fields counts how many files set each front matter field, to help write a schema
+	Fields   map[string]int `json:"fields,omitempty"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
@@ -0,0 +1,142 @@
+package netdiag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// recordTypes are the DNS record types that can be looked up
+var recordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS", "PTR"}
+
+// defaultRecordTypes are looked up for a host name when none are requested
+var defaultRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT"}
+
+// lookupDNS queries the record types for host, with the custom resolver when one is given
+func lookupDNS(ctx context.Context, host, resolverAddress string, types []string, timeout time.Duration) *DNSResult {
+	resolver := net.DefaultResolver
+	result := &DNSResult{Host: host, Resolver: "system", Records: []DNSRecords{}}
+	if resolverAddress != "" {
+		resolver = customResolver(resolverAddress, timeout)
+		result.Resolver = resolverAddress
+	}
+	if len(types) == 0 {
+		types = defaultRecordTypes
+		if _, err := netip.ParseAddr(host); err == nil {
+			types = []string{"PTR"}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	found, notFound := false, 0
+	for _, recordType := range types {
+		values, err := lookup(ctx, resolver, host, recordType)
+		records := DNSRecords{Type: recordType, Values: values}
+		if records.Values == nil {
+			records.Values = []string{}
+		}
+		if dnsErr, ok := errors.AsType[*net.DNSError](err); ok && dnsErr.IsNotFound {
+			notFound++
+		} else if err != nil {
+			records.Error = err.Error()
+		}
+		found = found || len(values) > 0
+		result.Records = append(result.Records, records)
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	if !found && notFound == len(types) {
+		result.Note = fmt.Sprintf("%s does not exist (NXDOMAIN) or has none of these record types.", host)
+	}
+	return result
+}
+
+// lookup returns the values of one record type. A host with no CNAME has itself as its canonical
+// name, which is not reported.
+func lookup(ctx context.Context, resolver *net.Resolver, host, recordType string) ([]string, error) {
+	var values []string
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		addrs, err := resolver.LookupNetIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			values = append(values, addr.Unmap().String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(strings.TrimSuffix(cname, "."), host) {
+			values = append(values, cname)
+		}
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			values = append(values, strconv.Itoa(int(mx.Pref))+" "+mx.Host)
+		}
+	case "TXT":
+		return resolver.LookupTXT(ctx, host)
+	case "NS":
+		records, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			values = append(values, ns.Host)
+		}
+	case "PTR":
+		return resolver.LookupAddr(ctx, host)
+	}
+	slices.Sort(values)
+	return values, nil
+}
+
+// customResolver returns a resolver that sends every query to address, a DNS server's host:port
+func customResolver(address string, timeout time.Duration) *net.Resolver {
+	dialer := &net.Dialer{Timeout: timeout, Control: checkDialAddress}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// resolverAddress validates a DNS server given as an IP address or host name, with an optional
+// port, and returns it as host:port
+func resolverAddress(value string) (string, error) {
+	host, port := value, "53"
+	if h, p, err := net.SplitHostPort(value); err == nil {
+		host, port = h, p
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid resolver port %q", port)
+	}
+	host = strings.Trim(host, "[]")
+	if !hostPattern.MatchString(host) {
+		return "", fmt.Errorf("invalid resolver %q: expected an IP address or host name, such as 1.1.1.1 or 8.8.8.8:53", value)
+	}
+	if err := security.CheckNetworkHost(host); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,313 @@
+package netdiag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	maxTimeout     = 60 * time.Second
+	defaultTLSPort = 443
+)
+
+// functions are the diagnostics the tool can run
+var functions = []string{"dns", "tls", "tcp", "whois"}
+
+// hostPattern matches host names, including underscore labels such as _dmarc, and IP addresses
+var hostPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._:-]*$`)
+
+// NetDiagTool looks up DNS records, inspects TLS certificates and checks TCP connectivity
+type NetDiagTool struct{}
+
+// init registers the netdiag tool
+func init() {
+	registry.Register(&NetDiagTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *NetDiagTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"netdiag",
+		mcp.WithDescription(`Network diagnostics for troubleshooting integrations. dns: look up A, AAAA, CNAME, MX, TXT, NS or PTR records, optionally with a specific resolver. tls: connect and report the protocol, certificate chain, expiry, SANs and whether the certificate is trusted. tcp: check whether a port accepts connections on each of a host's addresses. whois: registration data for a domain or IP address over RDAP or WHOIS, such as registrar, expiry, name servers, network owner and abuse contact, optionally with the IP's abuse reputation. Hosts are limited by the security network policy.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Diagnostic to run: "+strings.Join(functions, ", ")),
+			mcp.Enum(functions...),
+		),
+		mcp.WithString("host",
+			mcp.Required(),
+			mcp.Description("Host name, IP address or URL, such as 'api.example.com', 'example.com:8443' or 'https://example.com/path'"),
+		),
+		mcp.WithString("record_types",
+			mcp.Description("dns: comma-separated record types: "+strings.Join(recordTypes, ", ")+" (default: A, AAAA, CNAME, MX, TXT, or PTR for an IP address)"),
+		),
+		mcp.WithString("resolver",
+			mcp.Description("dns: DNS server to query instead of the system resolver, such as '1.1.1.1' or '8.8.8.8:53'"),
+		),
+		mcp.WithNumber("port",
+			mcp.Description("tls and tcp: port to connect to (tls default: 443 or the port in host; required for tcp unless host includes one)"),
+		),
+		mcp.WithString("server_name",
+			mcp.Description("tls: server name to send (SNI) and verify the certificate against (default: host)"),
+		),
+		mcp.WithBoolean("reputation",
+			mcp.Description("whois: also look up an IP address's abuse reports on AbuseIPDB (requires "+AbuseIPDBKeyEnvVar+")"),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description("Timeout in seconds (default: 10, max: 60)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only queries DNS and registries, and opens connections that send no data
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // Repeating a check has no further effect
+		mcp.WithOpenWorldHintAnnotation(true),    // Contacts DNS servers, registries and remote hosts
+	)
+}
+
+// Execute runs the requested diagnostic
+func (t *NetDiagTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	function = strings.ToLower(strings.TrimSpace(function))
+	if !slices.Contains(functions, function) {
+		return nil, fmt.Errorf("function must be one of %s, got: %q", strings.Join(functions, ", "), function)
+	}
+	rawHost, _ := args["host"].(string)
+	host, hostPort, err := parseHost(rawHost)
+	if err != nil {
+		return nil, err
+	}
+	if err := security.CheckNetworkHost(host); err != nil {
+		return nil, err
+	}
+	port := hostPort
+	if value, ok := args["port"].(float64); ok {
+		if value < 1 || value > 65535 || value != float64(int(value)) {
+			return nil, fmt.Errorf("port must be a whole number from 1 to 65535, got: %v", value)
+		}
+		port = int(value)
+	}
+	timeout := defaultTimeout
+	if value, ok := args["timeout"].(float64); ok {
+		if value <= 0 {
+			return nil, fmt.Errorf("timeout must be a positive number of seconds, got: %v", value)
+		}
+		timeout = min(time.Duration(value*float64(time.Second)), maxTimeout)
+	}
+	logger.WithFields(logrus.Fields{"function": function, "host": host, "port": port}).Debug("Running network diagnostic")
+
+	var result any
+	switch function {
+	case "dns":
+		types, err := recordTypeList(args)
+		if err != nil {
+			return nil, err
+		}
+		resolver := ""
+		if value, _ := args["resolver"].(string); strings.TrimSpace(value) != "" {
+			if resolver, err = resolverAddress(strings.TrimSpace(value)); err != nil {
+				return nil, err
+			}
+		}
+		result = lookupDNS(ctx, host, resolver, types, timeout)
+	case "tls":
+		if port == 0 {
+			port = defaultTLSPort
+		}
+		serverName, _ := args["server_name"].(string)
+		serverName = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(serverName)), ".")
+		if serverName == "" {
+			serverName = host
+		} else if !hostPattern.MatchString(serverName) {
+			return nil, fmt.Errorf("invalid server_name %q: expected a host name", serverName)
+		}
+		if result, err = inspectTLS(ctx, host, port, serverName, timeout); err != nil {
+			return nil, err
+		}
+	case "tcp":
+		if port == 0 {
+			return nil, fmt.Errorf("port is required for tcp, either as the port parameter or in host, such as 'db.example.com:5432'")
+		}
+		if result, err = checkTCP(ctx, host, port, timeout); err != nil {
+			return nil, err
+		}
+	case "whois":
+		reputation, _ := args["reputation"].(bool)
+		if result, err = lookupWhois(ctx, cache, host, reputation, timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// TXT records, certificate names and registration data are set by whoever controls the host, so scan them
+	sourceCtx := security.SourceContext{
+		Tool:        "netdiag",
+		Domain:      host,
+		ContentType: "network_diagnostics",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// parseHost takes the host, and the port if there is one, from a host name, IP address, host:port
+// or URL. The host is returned in lower case without a trailing dot or IPv6 brackets.
+func parseHost(value string) (string, int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", 0, fmt.Errorf("host is required")
+	}
+	host, port := value, ""
+	if strings.Contains(value, "://") {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Hostname() == "" {
+			return "", 0, fmt.Errorf("invalid host URL %q", value)
+		}
+		host, port = parsed.Hostname(), parsed.Port()
+		if port == "" && (parsed.Scheme == "https" || parsed.Scheme == "wss") {
+			port = strconv.Itoa(defaultTLSPort)
+		}
+	} else if h, p, err := net.SplitHostPort(value); err == nil {
+		host, port = h, p
+	}
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+	if !hostPattern.MatchString(host) {
+		return "", 0, fmt.Errorf("invalid host %q: expected a host name, IP address or URL", value)
+	}
+	if port == "" {
+		return host, 0, nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "", 0, fmt.Errorf("invalid port %q in host", port)
+	}
+	return host, n, nil
+}
+
+// recordTypeList reads the record_types parameter
+func recordTypeList(args map[string]any) ([]string, error) {
+	raw, _ := args["record_types"].(string)
+	var types []string
+	for value := range strings.SplitSeq(raw, ",") {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value == "" || slices.Contains(types, value) {
+			continue
+		}
+		if !slices.Contains(recordTypes, value) {
+			return nil, fmt.Errorf("unknown record_types entry %q: must be one of %s", value, strings.Join(recordTypes, ", "))
+		}
+		types = append(types, value)
+	}
+	return types, nil
+}
+
+// checkDialAddress applies the security network policy to the address a host name resolved to, so
+// a permitted name cannot be used to reach a blocked private address
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return nil
+	}
+	return security.CheckNetworkAddress(addr.Unmap())
+}
+
+// ProvideExtendedInfo provides detailed usage information for the netdiag tool
+func (t *NetDiagTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Troubleshooting why an API, webhook, database or registry cannot be reached: whether the name resolves and to what, whether the port accepts connections, and whether the TLS certificate is trusted, expired or issued for another name. Checking SPF, DMARC or domain verification TXT records. Finding who owns a domain or IP address, when a domain expires, or where to report abuse.",
+		WhenNotToUse: "Fetching content from a URL - use fetch_url. Testing an HTTP API's responses - use a configured API tool. Scanning many hosts or ports, which this tool is not for.",
+		CommonPatterns: []string{
+			"Work up the stack: dns to check the name resolves, tcp to check the port is open, then tls to check the certificate",
+			"Compare the system resolver with a public one using resolver='1.1.1.1' to spot split-horizon or stale DNS",
+			"Read SPF or verification records with record_types='TXT', and DMARC with host='_dmarc.example.com'",
+			"Check a certificate behind a load balancer for another name with server_name",
+			"Check a domain's expiry and name servers with whois, or an unknown client IP's owner and abuse reports with whois and reputation=true",
+		},
+		ParameterDetails: map[string]string{
+			"function":     "dns: record lookups. tls: handshake, chain, expiry, SANs, trust and warnings. tcp: connection attempts to each resolved address. whois: registration data from the registry's RDAP service, or its WHOIS server when it has none.",
+			"host":         "A URL's host and port are used, with port 443 for https. The host must be permitted by the security network policy, and connections to private addresses are refused when the policy blocks them.",
+			"record_types": "A, AAAA, CNAME, MX, TXT, NS and PTR. NXDOMAIN is noted when no type is found. PTR needs an IP address as host.",
+			"resolver":     "Queries are sent over UDP (or TCP for large answers) to this server, which is also subject to the network policy.",
+			"port":         "Overrides the port in host.",
+			"server_name":  "Sent as SNI and used for the hostname check. The default is host, which is not sent as SNI when it is an IP address.",
+			"reputation":   "Needs an AbuseIPDB API key in ABUSEIPDB_API_KEY. Reports the abuse confidence score (0-100), report counts, ISP, usage type and whether the address is a Tor exit. A failed lookup is noted without failing the whois result.",
+			"timeout":      "Applies to the whole diagnostic.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Check where a host name points",
+				Arguments:      map[string]any{"function": "dns", "host": "api.example.com"},
+				ExpectedResult: "A, AAAA, CNAME, MX and TXT records for the name",
+			},
+			{
+				Description:    "Inspect a site's certificate",
+				Arguments:      map[string]any{"function": "tls", "host": "https://example.com"},
+				ExpectedResult: "TLS version, cipher, ALPN, the certificate chain with SANs and days until expiry, whether it is trusted, and any warnings",
+			},
+			{
+				Description:    "Check a database port is reachable",
+				Arguments:      map[string]any{"function": "tcp", "host": "db.example.com", "port": 5432},
+				ExpectedResult: "Whether each address of the host accepted a connection, with timings and errors",
+			},
+			{
+				Description:    "Find who an IP address belongs to and whether it is reported for abuse",
+				Arguments:      map[string]any{"function": "whois", "host": "203.0.113.10", "reputation": true},
+				ExpectedResult: "The network's range, owner, country and abuse contact, and its AbuseIPDB score and reports",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The host is blocked by the security policy",
+				Solution: "The security configuration's network policy denies the domain, only allows listed domains, or blocks private addresses. Ask the user to adjust it if the check is needed.",
+			},
+			{
+				Problem:  "tcp attempts time out rather than being refused",
+				Solution: "A firewall is dropping the connection, or the host is not routable from this machine. A refused connection means the host is reachable but nothing listens on the port.",
+			},
+			{
+				Problem:  "tls reports verified: false with an unknown authority error",
+				Solution: "The server does not send its intermediate certificates, or the certificate is issued by a private CA that this machine does not trust. Check chain for the certificates sent.",
+			},
+			{
+				Problem:  "whois reports that a server is rate limiting lookups",
+				Solution: "Registries and AbuseIPDB limit how often they can be queried. Lookups to that server are held off until the time given, and results already fetched are cached for a day (reputation for six hours).",
+			},
+		},
+	}
+}
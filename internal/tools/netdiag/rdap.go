@@ -0,0 +1,334 @@
+package netdiag
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+const (
+	// BootstrapURLEnvVar overrides where the RDAP bootstrap files (dns.json, ipv4.json and ipv6.json)
+	// are fetched from, for networks that mirror them
+	BootstrapURLEnvVar = "NETDIAG_RDAP_BOOTSTRAP_URL"
+
+	defaultBootstrapURL = "https://data.iana.org/rdap/"
+
+	// bootstrapCacheTTL is how long the registry of RDAP servers is reused, which IANA changes rarely
+	bootstrapCacheTTL = 24 * time.Hour
+)
+
+// errNotFound is returned when an RDAP server has no record of the query
+var errNotFound = errors.New("not found")
+
+// rdapClient fetches RDAP data. Retries are left to the caller so a rate limited server is not
+// asked again straight away.
+var rdapClient = sync.OnceValue(func() *http.Client {
+	return httpclient.New(httpclient.Options{Timeout: 30 * time.Second, MaxRetries: -1, MaxResponseBytes: 2 * 1024 * 1024, Cache: true})
+})
+
+// bootstrap maps domain TLDs or IP prefixes to the base URLs of their RDAP servers
+type bootstrap struct {
+	Services [][][]string `json:"services"`
+}
+
+// rdapObject is the part of an RDAP domain or IP network response that is reported
+type rdapObject struct {
+	Handle       string       `json:"handle"`
+	LDHName      string       `json:"ldhName"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	Country      string       `json:"country"`
+	StartAddress string       `json:"startAddress"`
+	EndAddress   string       `json:"endAddress"`
+	ParentHandle string       `json:"parentHandle"`
+	Status       []string     `json:"status"`
+	Events       []rdapEvent  `json:"events"`
+	Entities     []rdapEntity `json:"entities"`
+	Nameservers  []struct {
+		LDHName string `json:"ldhName"`
+	} `json:"nameservers"`
+	SecureDNS *struct {
+		DelegationSigned *bool `json:"delegationSigned"`
+	} `json:"secureDNS"`
+	CIDRs []struct {
+		V4Prefix string `json:"v4prefix"`
+		V6Prefix string `json:"v6prefix"`
+		Length   int    `json:"length"`
+	} `json:"cidr0_cidrs"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles    []string        `json:"roles"`
+	VCard    json.RawMessage `json:"vcardArray"`
+	Entities []rdapEntity    `json:"entities"`
+}
+
+// rdapDomain looks up a domain's registration. A subdomain has no record of its own, so its parent
+// domains are tried in turn.
+func rdapDomain(ctx context.Context, cache *sync.Map, domain string) (*WhoisResult, error) {
+	servers, err := loadBootstrap(ctx, cache, "dns.json")
+	if err != nil {
+		return nil, err
+	}
+	labels := strings.Split(domain, ".")
+	base := ""
+	for i := range labels {
+		if urls := servers[strings.Join(labels[i:], ".")]; len(urls) > 0 {
+			base = urls[0]
+			break
+		}
+	}
+	if base == "" {
+		return nil, errNoRDAPService
+	}
+	for i := 0; i < len(labels)-1; i++ {
+		name := strings.Join(labels[i:], ".")
+		url := base + "domain/" + name
+		object, err := fetchRDAP(ctx, url)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result := describeRDAP(object, domain, "domain", url)
+		if name != domain {
+			result.Note = fmt.Sprintf("%s is not registered itself, so its parent domain %s is shown.", domain, name)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("%s is not registered", domain)
+}
+
+// rdapIP looks up the registered network an IP address belongs to
+func rdapIP(ctx context.Context, cache *sync.Map, addr netip.Addr) (*WhoisResult, error) {
+	file := "ipv4.json"
+	if addr.Is6() {
+		file = "ipv6.json"
+	}
+	servers, err := loadBootstrap(ctx, cache, file)
+	if err != nil {
+		return nil, err
+	}
+	base, bits := "", -1
+	for value, urls := range servers {
+		prefix, err := netip.ParsePrefix(value)
+		if err == nil && prefix.Contains(addr) && prefix.Bits() > bits {
+			base, bits = urls[0], prefix.Bits()
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("no registry has an RDAP service for %s", addr)
+	}
+	url := base + "ip/" + addr.String()
+	object, err := fetchRDAP(ctx, url)
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("%s is not registered", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return describeRDAP(object, addr.String(), "ip", url), nil
+}
+
+// loadBootstrap returns the RDAP server base URLs from an IANA bootstrap file, keyed by TLD or prefix
+func loadBootstrap(ctx context.Context, cache *sync.Map, file string) (map[string][]string, error) {
+	url := strings.TrimSuffix(cmp.Or(os.Getenv(BootstrapURLEnvVar), defaultBootstrapURL), "/") + "/" + file
+	if servers, ok := loadCached[map[string][]string](cache, "bootstrap:"+url, bootstrapCacheTTL); ok {
+		return servers, nil
+	}
+	body, err := get(ctx, url, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the RDAP server list: %w", err)
+	}
+	var parsed bootstrap
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse the RDAP server list %s: %w", url, err)
+	}
+	servers := map[string][]string{}
+	for _, service := range parsed.Services {
+		if len(service) != 2 {
+			continue
+		}
+		// Prefer HTTPS where a registry lists more than one URL
+		var urls, plain []string
+		for _, u := range service[1] {
+			if !strings.HasSuffix(u, "/") {
+				u += "/"
+			}
+			if strings.HasPrefix(u, "https://") {
+				urls = append(urls, u)
+			} else {
+				plain = append(plain, u)
+			}
+		}
+		if urls = append(urls, plain...); len(urls) == 0 {
+			continue
+		}
+		for _, key := range service[0] {
+			servers[strings.ToLower(key)] = urls
+		}
+	}
+	storeCached(cache, "bootstrap:"+url, servers)
+	return servers, nil
+}
+
+// fetchRDAP fetches and decodes an RDAP object
+func fetchRDAP(ctx context.Context, url string) (*rdapObject, error) {
+	body, err := get(ctx, url, "application/rdap+json, application/json")
+	if err != nil {
+		return nil, err
+	}
+	var object rdapObject
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("failed to parse RDAP response from %s: %w", url, err)
+	}
+	return &object, nil
+}
+
+// get fetches a URL, pacing requests to each server and backing off when it reports a rate limit
+func get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", url, err)
+	}
+	if err := limits.wait(ctx, req.URL.Host); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := rdapClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", req.URL.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, limits.rateLimited(req.URL.Host, resp.Header.Get("Retry-After"))
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned HTTP %d", req.URL.Host, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", req.URL.Host, err)
+	}
+	return body, nil
+}
+
+// describeRDAP converts an RDAP object to a result
+func describeRDAP(object *rdapObject, query, kind, source string) *WhoisResult {
+	result := &WhoisResult{
+		Query:    query,
+		Kind:     kind,
+		Protocol: "rdap",
+		Source:   source,
+		Name:     strings.ToLower(object.LDHName),
+		Handle:   object.Handle,
+		Status:   object.Status,
+	}
+	if kind == "ip" {
+		result.Name = object.Name
+		result.Network = &Network{
+			Range:   object.StartAddress + " - " + object.EndAddress,
+			Type:    object.Type,
+			Country: object.Country,
+			Parent:  object.ParentHandle,
+		}
+		for _, cidr := range object.CIDRs {
+			result.Network.CIDRs = append(result.Network.CIDRs, fmt.Sprintf("%s/%d", cmp.Or(cidr.V4Prefix, cidr.V6Prefix), cidr.Length))
+		}
+	}
+	for _, event := range object.Events {
+		switch event.Action {
+		case "registration":
+			result.Registered = event.Date
+		case "last changed":
+			result.Updated = event.Date
+		case "expiration":
+			result.Expires = event.Date
+		}
+	}
+	for _, ns := range object.Nameservers {
+		result.Nameservers = append(result.Nameservers, strings.ToLower(strings.TrimSuffix(ns.LDHName, ".")))
+	}
+	if object.SecureDNS != nil {
+		result.DNSSEC = object.SecureDNS.DelegationSigned
+	}
+	walkEntities(object.Entities, result)
+	return result
+}
+
+// walkEntities collects the registrar, abuse address and contacts from entities and the entities
+// nested in them, such as a registrar's abuse contact
+func walkEntities(entities []rdapEntity, result *WhoisResult) {
+	for _, entity := range entities {
+		contact := parseVCard(entity.VCard)
+		contact.Roles = entity.Roles
+		switch {
+		case slices.Contains(entity.Roles, "registrar"):
+			result.Registrar = cmp.Or(contact.Organisation, contact.Name)
+		case slices.Contains(entity.Roles, "abuse") && contact.Email != "" && result.AbuseEmail == "":
+			result.AbuseEmail = contact.Email
+		case contact.Name != "" || contact.Organisation != "" || contact.Email != "":
+			result.Contacts = append(result.Contacts, contact)
+		}
+		walkEntities(entity.Entities, result)
+	}
+}
+
+// parseVCard reads the name, organisation, email and country from a jCard (RFC 7095), an array of
+// ["vcard", [[property, parameters, type, value...], ...]]
+func parseVCard(raw json.RawMessage) Contact {
+	var contact Contact
+	var card []json.RawMessage
+	if json.Unmarshal(raw, &card) != nil || len(card) != 2 {
+		return contact
+	}
+	var properties [][]json.RawMessage
+	if json.Unmarshal(card[1], &properties) != nil {
+		return contact
+	}
+	for _, property := range properties {
+		if len(property) < 4 {
+			continue
+		}
+		var name string
+		_ = json.Unmarshal(property[0], &name)
+		var value string
+		_ = json.Unmarshal(property[3], &value)
+		switch name {
+		case "fn":
+			contact.Name = value
+		case "org":
+			contact.Organisation = value
+		case "email":
+			contact.Email = value
+		case "adr":
+			var parameters struct {
+				CC string `json:"cc"`
+			}
+			_ = json.Unmarshal(property[1], &parameters)
+			contact.Country = parameters.CC
+		}
+	}
+	return contact
+}
@@ -0,0 +1,117 @@
+package netdiag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+const (
+	// AbuseIPDBKeyEnvVar holds the AbuseIPDB API key that enables reputation lookups
+	AbuseIPDBKeyEnvVar = "ABUSEIPDB_API_KEY"
+
+	abuseIPDBCheckURL = "https://api.abuseipdb.com/api/v2/check"
+
+	// abuseIPDBMaxAgeDays is how far back reports are counted
+	abuseIPDBMaxAgeDays = 90
+
+	// reputationCacheTTL is how long a reputation is reused. The free AbuseIPDB plan allows 1,000
+	// checks a day, and scores move slowly.
+	reputationCacheTTL = 6 * time.Hour
+)
+
+// reputationClient queries the reputation provider. Responses depend on the API key, so they are
+// cached in memory rather than in the shared response cache.
+var reputationClient = sync.OnceValue(func() *http.Client {
+	return httpclient.New(httpclient.Options{Timeout: 30 * time.Second, MaxRetries: -1})
+})
+
+// abuseIPDBResponse is the part of an AbuseIPDB check response that is reported
+type abuseIPDBResponse struct {
+	Data struct {
+		AbuseConfidenceScore int    `json:"abuseConfidenceScore"`
+		TotalReports         int    `json:"totalReports"`
+		NumDistinctUsers     int    `json:"numDistinctUsers"`
+		LastReportedAt       string `json:"lastReportedAt"`
+		ISP                  string `json:"isp"`
+		UsageType            string `json:"usageType"`
+		Domain               string `json:"domain"`
+		CountryCode          string `json:"countryCode"`
+		IsTor                bool   `json:"isTor"`
+		IsWhitelisted        bool   `json:"isWhitelisted"`
+	} `json:"data"`
+}
+
+// lookupReputation returns an IP address's abuse reports from AbuseIPDB
+func lookupReputation(ctx context.Context, cache *sync.Map, addr netip.Addr) (*Reputation, error) {
+	key := strings.TrimSpace(os.Getenv(AbuseIPDBKeyEnvVar))
+	if key == "" {
+		return nil, fmt.Errorf("set %s to an AbuseIPDB API key to look up IP reputation", AbuseIPDBKeyEnvVar)
+	}
+	if cached, ok := loadCached[Reputation](cache, "reputation:"+addr.String(), reputationCacheTTL); ok {
+		cached.Cached = true
+		return &cached, nil
+	}
+
+	query := url.Values{"ipAddress": {addr.String()}, "maxAgeInDays": {fmt.Sprint(abuseIPDBMaxAgeDays)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, abuseIPDBCheckURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.wait(ctx, req.URL.Host); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Key", key)
+	req.Header.Set("Accept", "application/json")
+	resp, err := reputationClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to AbuseIPDB failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		// The daily quota is spent, and Retry-After says when it resets
+		return nil, limits.rateLimited(req.URL.Host, resp.Header.Get("Retry-After"))
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("AbuseIPDB rejected the API key in %s", AbuseIPDBKeyEnvVar)
+	default:
+		return nil, fmt.Errorf("AbuseIPDB returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AbuseIPDB response: %w", err)
+	}
+	var parsed abuseIPDBResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse AbuseIPDB response: %w", err)
+	}
+
+	data := parsed.Data
+	rep := Reputation{
+		Provider:          "AbuseIPDB",
+		AbuseScore:        data.AbuseConfidenceScore,
+		Reports:           data.TotalReports,
+		DistinctReporters: data.NumDistinctUsers,
+		LastReported:      data.LastReportedAt,
+		ISP:               data.ISP,
+		UsageType:         data.UsageType,
+		Domain:            data.Domain,
+		Country:           data.CountryCode,
+		Tor:               data.IsTor,
+		Allowlisted:       data.IsWhitelisted,
+	}
+	storeCached(cache, "reputation:"+addr.String(), rep)
+	return &rep, nil
+}
@@ -0,0 +1,64 @@
+package netdiag
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// maxTCPAddresses limits how many of a host's addresses are tried
+const maxTCPAddresses = 8
+
+// checkTCP tries to connect to port on every address of host at the same time
+func checkTCP(ctx context.Context, host string, port int, timeout time.Duration) (*TCPResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	if len(addrs) > maxTCPAddresses {
+		addrs = addrs[:maxTCPAddresses]
+	}
+
+	result := &TCPResult{Host: host, Port: port, Attempts: make([]TCPAttempt, len(addrs))}
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Go(func() {
+			result.Attempts[i] = dial(ctx, addr.Unmap(), port, timeout)
+		})
+	}
+	wg.Wait()
+	for _, attempt := range result.Attempts {
+		result.Reachable = result.Reachable || attempt.Connected
+	}
+	return result, nil
+}
+
+// dial connects to one address and closes the connection straight away
+func dial(ctx context.Context, addr netip.Addr, port int, timeout time.Duration) TCPAttempt {
+	address := net.JoinHostPort(addr.String(), strconv.Itoa(port))
+	attempt := TCPAttempt{Address: address, Duration: "0s"}
+	if err := security.CheckNetworkAddress(addr); err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	start := time.Now()
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	attempt.Duration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	_ = conn.Close()
+	attempt.Connected = true
+	return attempt
+}
@@ -0,0 +1,163 @@
+package netdiag
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expiryWarningDays is how close to expiry a certificate is warned about
+const expiryWarningDays = 30
+
+// inspectTLS connects to host:port, completes a TLS handshake and describes the certificates. The
+// chain is verified after the handshake, so an untrusted or expired certificate is reported rather
+// than failing the connection.
+func inspectTLS(ctx context.Context, host string, port int, serverName string, timeout time.Duration) (*TLSResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	dialer := &net.Dialer{Timeout: timeout, Control: checkDialAddress}
+	raw, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s port %d: %w", host, port, err)
+	}
+	defer func() { _ = raw.Close() }()
+
+	conn := tls.Client(raw, &tls.Config{
+		ServerName: serverName,
+		NextProtos: []string{"h2", "http/1.1"},
+		// Verification is done after the handshake so that its failure can be reported
+		InsecureSkipVerify: true,
+	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s port %d failed: %w", host, port, err)
+	}
+	state := conn.ConnectionState()
+	result := &TLSResult{
+		Host:        host,
+		Port:        port,
+		ServerName:  serverName,
+		Address:     raw.RemoteAddr().String(),
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ALPN:        state.NegotiatedProtocol,
+		OCSPStapled: len(state.OCSPResponse) > 0,
+		Chain:       []Certificate{},
+		Duration:    time.Since(start).Round(time.Millisecond).String(),
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("%s port %d presented no certificate", host, port)
+	}
+
+	now := time.Now()
+	for _, cert := range state.PeerCertificates {
+		result.Chain = append(result.Chain, describeCertificate(cert, now))
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: serverName, Intermediates: intermediates, CurrentTime: now}); err != nil {
+		result.VerifyError = err.Error()
+	} else {
+		result.Verified = true
+	}
+	result.Warnings = warnings(state, leaf, serverName, now)
+	return result, nil
+}
+
+// warnings lists problems a client would hit or soon hit with the connection
+func warnings(state tls.ConnectionState, leaf *x509.Certificate, serverName string, now time.Time) []string {
+	var found []string
+	switch days := daysUntil(leaf.NotAfter, now); {
+	case now.After(leaf.NotAfter):
+		found = append(found, fmt.Sprintf("The certificate expired on %s.", leaf.NotAfter.UTC().Format(time.DateOnly)))
+	case days <= expiryWarningDays:
+		found = append(found, fmt.Sprintf("The certificate expires in %d days, on %s.", days, leaf.NotAfter.UTC().Format(time.DateOnly)))
+	}
+	if now.Before(leaf.NotBefore) {
+		found = append(found, fmt.Sprintf("The certificate is not valid until %s.", leaf.NotBefore.UTC().Format(time.RFC3339)))
+	}
+	if err := leaf.VerifyHostname(serverName); err != nil {
+		found = append(found, fmt.Sprintf("The certificate is not valid for %s.", serverName))
+	}
+	if len(state.PeerCertificates) == 1 && leaf.CheckSignatureFrom(leaf) == nil {
+		found = append(found, "The certificate is self-signed.")
+	}
+	if state.Version < tls.VersionTLS12 {
+		found = append(found, fmt.Sprintf("The server negotiated %s, which current clients reject.", tls.VersionName(state.Version)))
+	}
+	for _, cert := range state.PeerCertificates {
+		// Roots are trusted by presence, so only signatures below them matter
+		if cert.IsCA && cert.CheckSignatureFrom(cert) == nil {
+			continue
+		}
+		if strings.Contains(cert.SignatureAlgorithm.String(), "SHA1") || strings.Contains(cert.SignatureAlgorithm.String(), "MD5") {
+			found = append(found, fmt.Sprintf("%s is signed with %s, which current clients reject.", commonName(cert.Subject.CommonName, cert.Subject.String()), cert.SignatureAlgorithm))
+		}
+	}
+	return found
+}
+
+// describeCertificate summarises a certificate
+func describeCertificate(cert *x509.Certificate, now time.Time) Certificate {
+	fingerprint := sha256.Sum256(cert.Raw)
+	c := Certificate{
+		Subject:            commonName(cert.Subject.CommonName, cert.Subject.String()),
+		Issuer:             commonName(cert.Issuer.CommonName, cert.Issuer.String()),
+		NotBefore:          cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:           cert.NotAfter.UTC().Format(time.RFC3339),
+		DaysRemaining:      daysUntil(cert.NotAfter, now),
+		SerialNumber:       strings.ToUpper(cert.SerialNumber.Text(16)),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		PublicKey:          publicKey(cert.PublicKey),
+		IsCA:               cert.IsCA,
+		SHA256:             strings.ToUpper(hex.EncodeToString(fingerprint[:])),
+	}
+	c.SANs = append(c.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		c.SANs = append(c.SANs, ip.String())
+	}
+	return c
+}
+
+// commonName returns the common name, or the full name when there is none
+func commonName(name, full string) string {
+	if name != "" {
+		return name
+	}
+	return full
+}
+
+// publicKey describes a public key's algorithm and size
+func publicKey(key any) string {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
+
+// daysUntil returns the whole days from now until t, negative once t has passed
+func daysUntil(t, now time.Time) int {
+	return int(math.Floor(t.Sub(now).Hours() / 24))
+}
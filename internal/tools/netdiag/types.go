@@ -0,0 +1,134 @@
+package netdiag
+
+// DNSResult holds the records found for a host
+type DNSResult struct {
+	Host string `json:"host"`
+	// Resolver is the DNS server queried, or "system" for the operating system's resolver
+	Resolver string       `json:"resolver"`
+	Records  []DNSRecords `json:"records"`
+	Duration string       `json:"duration"`
+	Note     string       `json:"note,omitempty"`
+}
+
+// DNSRecords are the records of one type
+type DNSRecords struct {
+	Type   string   `json:"type"`
+	Values []string `json:"values"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// TLSResult describes a TLS handshake and the certificates the server presented
+type TLSResult struct {
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	ServerName string `json:"server_name"`
+	// Address is the IP address and port connected to
+	Address     string `json:"address"`
+	Version     string `json:"version"`
+	CipherSuite string `json:"cipher_suite"`
+	ALPN        string `json:"alpn,omitempty"`
+	// Verified reports whether the chain is trusted by the system roots and valid for server_name
+	Verified    bool          `json:"verified"`
+	VerifyError string        `json:"verify_error,omitempty"`
+	OCSPStapled bool          `json:"ocsp_stapled"`
+	Chain       []Certificate `json:"chain"`
+	Warnings    []string      `json:"warnings,omitempty"`
+	Duration    string        `json:"duration"`
+}
+
+// Certificate is a certificate in the chain, starting with the server's own
+type Certificate struct {
+	Subject            string   `json:"subject"`
+	Issuer             string   `json:"issuer"`
+	SANs               []string `json:"sans,omitempty"`
+	NotBefore          string   `json:"not_before"`
+	NotAfter           string   `json:"not_after"`
+	DaysRemaining      int      `json:"days_remaining"`
+	SerialNumber       string   `json:"serial_number"`
+	SignatureAlgorithm string   `json:"signature_algorithm"`
+	PublicKey          string   `json:"public_key"`
+	IsCA               bool     `json:"is_ca,omitempty"`
+	SHA256             string   `json:"sha256"`
+}
+
+// TCPResult reports whether each address of a host accepts connections on a port
+type TCPResult struct {
+	Host      string       `json:"host"`
+	Port      int          `json:"port"`
+	Reachable bool         `json:"reachable"`
+	Attempts  []TCPAttempt `json:"attempts"`
+}
+
+// TCPAttempt is a connection attempt to one address
+type TCPAttempt struct {
+	Address   string `json:"address"`
+	Connected bool   `json:"connected"`
+	Duration  string `json:"duration"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WhoisResult holds the registration data of a domain or IP address
+type WhoisResult struct {
+	Query string `json:"query"`
+	// Kind is "domain" or "ip"
+	Kind string `json:"kind"`
+	// Protocol is "rdap", or "whois" for registries without an RDAP service
+	Protocol string `json:"protocol"`
+	// Source is the RDAP URL or WHOIS server the data came from
+	Source          string    `json:"source"`
+	Name            string    `json:"name,omitempty"`
+	Handle          string    `json:"handle,omitempty"`
+	Status          []string  `json:"status,omitempty"`
+	Registrar       string    `json:"registrar,omitempty"`
+	Registered      string    `json:"registered,omitempty"`
+	Updated         string    `json:"updated,omitempty"`
+	Expires         string    `json:"expires,omitempty"`
+	DaysUntilExpiry *int      `json:"days_until_expiry,omitempty"`
+	Nameservers     []string  `json:"nameservers,omitempty"`
+	DNSSEC          *bool     `json:"dnssec,omitempty"`
+	Network         *Network  `json:"network,omitempty"`
+	AbuseEmail      string    `json:"abuse_email,omitempty"`
+	Contacts        []Contact `json:"contacts,omitempty"`
+	// Raw is the WHOIS response, without comments, when the data came from a WHOIS server
+	Raw        string      `json:"raw,omitempty"`
+	Reputation *Reputation `json:"reputation,omitempty"`
+	// Cached reports whether the registration data was served from the cache
+	Cached bool   `json:"cached"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Network is the registered network an IP address belongs to
+type Network struct {
+	Range   string   `json:"range"`
+	CIDRs   []string `json:"cidrs,omitempty"`
+	Type    string   `json:"type,omitempty"`
+	Country string   `json:"country,omitempty"`
+	Parent  string   `json:"parent,omitempty"`
+}
+
+// Contact is a registrant, administrative, technical or abuse contact. Registries often redact
+// personal details, so contacts without any are left out.
+type Contact struct {
+	Roles        []string `json:"roles"`
+	Name         string   `json:"name,omitempty"`
+	Organisation string   `json:"organisation,omitempty"`
+	Email        string   `json:"email,omitempty"`
+	Country      string   `json:"country,omitempty"`
+}
+
+// Reputation is an IP address's abuse history from a reputation provider
+type Reputation struct {
+	Provider string `json:"provider"`
+	// AbuseScore is the provider's confidence, from 0 to 100, that the address is abusive
+	AbuseScore        int    `json:"abuse_score"`
+	Reports           int    `json:"reports"`
+	DistinctReporters int    `json:"distinct_reporters"`
+	LastReported      string `json:"last_reported,omitempty"`
+	ISP               string `json:"isp,omitempty"`
+	UsageType         string `json:"usage_type,omitempty"`
+	Domain            string `json:"domain,omitempty"`
+	Country           string `json:"country,omitempty"`
+	Tor               bool   `json:"tor"`
+	Allowlisted       bool   `json:"allowlisted"`
+	Cached            bool   `json:"cached"`
+}
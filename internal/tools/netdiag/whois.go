@@ -0,0 +1,307 @@
+package netdiag
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// whoisCacheTTL is how long registration data is reused. It changes rarely, and registries
+	// limit how often they can be queried.
+	whoisCacheTTL = 24 * time.Hour
+
+	// ianaWhoisServer is asked which WHOIS server handles a TLD without an RDAP service
+	ianaWhoisServer = "whois.iana.org"
+
+	// maxWhoisBytes limits the WHOIS response read from a server
+	maxWhoisBytes = 64 * 1024
+
+	// maxRawLength limits the WHOIS text returned, after comments are removed
+	maxRawLength = 4000
+
+	// defaultBackoff is how long a server that reports a rate limit without Retry-After is left alone
+	defaultBackoff = time.Minute
+
+	// maxBackoff caps how long a server is left alone after reporting a rate limit
+	maxBackoff = 24 * time.Hour
+
+	// cacheKeyPrefix namespaces the tool's entries in the shared cache
+	cacheKeyPrefix = "netdiag:"
+)
+
+// errNoRDAPService is returned for TLDs whose registry has no RDAP service
+var errNoRDAPService = errors.New("no RDAP service")
+
+// whoisFields maps lower case WHOIS keys, which differ between registries, to the fields they fill
+var whoisFields = map[string]string{
+	"registrar":                              "registrar",
+	"sponsoring registrar":                   "registrar",
+	"registrar name":                         "registrar",
+	"creation date":                          "registered",
+	"created":                                "registered",
+	"registered":                             "registered",
+	"registered on":                          "registered",
+	"registration time":                      "registered",
+	"updated date":                           "updated",
+	"changed":                                "updated",
+	"last modified":                          "updated",
+	"last updated":                           "updated",
+	"last-update":                            "updated",
+	"registry expiry date":                   "expires",
+	"registrar registration expiration date": "expires",
+	"expiry date":                            "expires",
+	"expiration date":                        "expires",
+	"expires":                                "expires",
+	"expires on":                             "expires",
+	"paid-till":                              "expires",
+	"name server":                            "nameserver",
+	"nserver":                                "nameserver",
+	"nameserver":                             "nameserver",
+	"domain status":                          "status",
+	"status":                                 "status",
+	"state":                                  "status",
+}
+
+// whoisLine matches a "key: value" line of a WHOIS response
+var whoisLine = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z /-]*?)\s*:\s*(.+?)\s*$`)
+
+// limits paces requests to RDAP, WHOIS and reputation servers
+var limits = &serverLimits{limiters: map[string]*rate.Limiter{}, backoff: map[string]time.Time{}}
+
+// serverLimits allows each server one request a second, with a burst of two, and holds off a server
+// that has reported a rate limit until it asked to be retried
+type serverLimits struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	backoff  map[string]time.Time
+}
+
+// wait blocks until a request may be sent to server, failing straight away while it is backed off
+// or when the wait would outlast ctx
+func (l *serverLimits) wait(ctx context.Context, server string) error {
+	l.mu.Lock()
+	if until, ok := l.backoff[server]; ok && time.Now().Before(until) {
+		l.mu.Unlock()
+		return fmt.Errorf("%s is rate limiting lookups, try again after %s", server, until.UTC().Format(time.RFC3339))
+	}
+	limiter, ok := l.limiters[server]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Second), 2)
+		l.limiters[server] = limiter
+	}
+	l.mu.Unlock()
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("too many lookups sent to %s, try again shortly: %w", server, err)
+	}
+	return nil
+}
+
+// rateLimited records that server returned 429 and returns the error to report
+func (l *serverLimits) rateLimited(server, retryAfter string) error {
+	delay := defaultBackoff
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		delay = time.Until(date)
+	}
+	until := time.Now().Add(min(max(delay, time.Second), maxBackoff))
+	l.mu.Lock()
+	l.backoff[server] = until
+	l.mu.Unlock()
+	return fmt.Errorf("%s is rate limiting lookups, try again after %s", server, until.UTC().Format(time.RFC3339))
+}
+
+// lookupWhois returns the registration data for a domain or IP address, with its reputation when
+// requested, reusing cached results
+func lookupWhois(ctx context.Context, cache *sync.Map, host string, reputation bool, timeout time.Duration) (*WhoisResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	addr, parseErr := netip.ParseAddr(host)
+	isIP := parseErr == nil
+	if isIP {
+		addr = addr.Unmap()
+		if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+			return nil, fmt.Errorf("%s is a private or reserved address, which has no registration data", addr)
+		}
+		host = addr.String()
+	}
+
+	var result WhoisResult
+	if cached, ok := loadCached[WhoisResult](cache, "whois:"+host, whoisCacheTTL); ok {
+		result = cached
+		result.Cached = true
+	} else {
+		var found *WhoisResult
+		var err error
+		switch {
+		case isIP:
+			found, err = rdapIP(ctx, cache, addr)
+		default:
+			found, err = rdapDomain(ctx, cache, host)
+			if errors.Is(err, errNoRDAPService) {
+				found, err = whoisDomain(ctx, host)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if found.Expires != "" {
+			if expires, err := time.Parse(time.RFC3339, found.Expires); err == nil {
+				days := daysUntil(expires, time.Now())
+				found.DaysUntilExpiry = &days
+			}
+		}
+		storeCached(cache, "whois:"+host, *found)
+		result = *found
+	}
+
+	if reputation {
+		if !isIP {
+			result.Note = strings.TrimSpace(result.Note + " Reputation is only looked up for IP addresses; run dns for the domain's addresses and look those up.")
+		} else if rep, err := lookupReputation(ctx, cache, addr); err != nil {
+			result.Note = strings.TrimSpace(result.Note + fmt.Sprintf(" Reputation lookup failed: %v.", err))
+		} else {
+			result.Reputation = rep
+		}
+	}
+	return &result, nil
+}
+
+// whoisDomain looks up a domain over the WHOIS protocol, asking IANA which server handles its TLD
+func whoisDomain(ctx context.Context, domain string) (*WhoisResult, error) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	referral, err := queryWhois(ctx, ianaWhoisServer, tld)
+	if err != nil {
+		return nil, err
+	}
+	server := ""
+	for line := range strings.Lines(referral) {
+		if key, value, ok := strings.Cut(line, ":"); ok && (strings.EqualFold(key, "refer") || strings.EqualFold(key, "whois")) {
+			server = strings.ToLower(strings.TrimSpace(value))
+			break
+		}
+	}
+	if server == "" || !hostPattern.MatchString(server) {
+		return nil, fmt.Errorf("the .%s registry has neither an RDAP nor a WHOIS service", tld)
+	}
+	text, err := queryWhois(ctx, server, domain)
+	if err != nil {
+		return nil, err
+	}
+	return parseWhois(text, domain, server), nil
+}
+
+// queryWhois sends a query to a WHOIS server on port 43 and returns its response
+func queryWhois(ctx context.Context, server, query string) (string, error) {
+	if err := security.CheckNetworkHost(server); err != nil {
+		return "", err
+	}
+	if err := limits.wait(ctx, server); err != nil {
+		return "", err
+	}
+	dialer := &net.Dialer{Control: checkDialAddress}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server, "43"))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to WHOIS server %s: %w", server, err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", fmt.Errorf("failed to query WHOIS server %s: %w", server, err)
+	}
+	body, err := io.ReadAll(io.LimitReader(bufio.NewReader(conn), maxWhoisBytes))
+	if err != nil && len(body) == 0 {
+		return "", fmt.Errorf("failed to read from WHOIS server %s: %w", server, err)
+	}
+	return string(body), nil
+}
+
+// parseWhois reads the common fields from a WHOIS response. Formats differ between registries, so
+// the response is also returned without its comments.
+func parseWhois(text, domain, server string) *WhoisResult {
+	result := &WhoisResult{Query: domain, Kind: "domain", Protocol: "whois", Source: server, Name: domain}
+	var raw strings.Builder
+	for line := range strings.Lines(text) {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">>>") {
+			continue
+		}
+		if raw.Len() < maxRawLength && (strings.TrimSpace(line) != "" || raw.Len() > 0) {
+			raw.WriteString(line + "\n")
+		}
+		match := whoisLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		value := match[2]
+		switch whoisFields[strings.ToLower(match[1])] {
+		case "registrar":
+			result.Registrar = cmp.Or(result.Registrar, value)
+		case "registered":
+			result.Registered = cmp.Or(result.Registered, value)
+		case "updated":
+			result.Updated = cmp.Or(result.Updated, value)
+		case "expires":
+			result.Expires = cmp.Or(result.Expires, value)
+		case "nameserver":
+			result.Nameservers = append(result.Nameservers, strings.ToLower(strings.TrimSuffix(strings.Fields(value)[0], ".")))
+		case "status":
+			result.Status = append(result.Status, strings.Fields(value)[0])
+		}
+	}
+	result.Raw = strings.TrimSpace(raw.String())
+	if strings.Contains(strings.ToLower(text), "no match") || strings.Contains(strings.ToLower(text), "not found") {
+		result.Note = fmt.Sprintf("%s may not be registered; check raw.", domain)
+	}
+	return result
+}
+
+// cacheEntry is a cached value with the time it was stored
+type cacheEntry struct {
+	value    any
+	storedAt time.Time
+}
+
+// loadCached returns a cached value if it exists and is younger than ttl
+func loadCached[T any](cache *sync.Map, key string, ttl time.Duration) (T, bool) {
+	var zero T
+	if cache == nil {
+		return zero, false
+	}
+	cached, ok := cache.Load(cacheKeyPrefix + key)
+	if !ok {
+		return zero, false
+	}
+	entry, ok := cached.(cacheEntry)
+	if !ok || time.Since(entry.storedAt) >= ttl {
+		return zero, false
+	}
+	value, ok := entry.value.(T)
+	return value, ok
+}
+
+// storeCached stores a value in the cache
+func storeCached(cache *sync.Map, key string, value any) {
+	if cache == nil {
+		return
+	}
+	cache.Store(cacheKeyPrefix+key, cacheEntry{value: value, storedAt: time.Now()})
+}
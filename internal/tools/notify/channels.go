@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ChannelsEnvVar sets the channels used when a notification does not name any
+	ChannelsEnvVar = "NOTIFY_CHANNELS"
+	// SlackWebhookEnvVar is the Slack incoming webhook URL for the slack channel
+	SlackWebhookEnvVar = "NOTIFY_SLACK_WEBHOOK_URL"
+	// TeamsWebhookEnvVar is the Microsoft Teams workflow webhook URL for the teams channel
+	TeamsWebhookEnvVar = "NOTIFY_TEAMS_WEBHOOK_URL"
+	// WebhookEnvVar is the URL the webhook channel posts JSON to
+	WebhookEnvVar = "NOTIFY_WEBHOOK_URL"
+	// DesktopCommandEnvVar overrides the desktop notification command, which is run with the
+	// title and message as its last two arguments
+	DesktopCommandEnvVar = "NOTIFY_DESKTOP_COMMAND"
+
+	commandTimeout = 10 * time.Second
+	webhookTimeout = 15 * time.Second
+	// maxErrorBody is how much of a failed webhook response is included in its error
+	maxErrorBody = 200
+	// windowsAppID shows toasts as coming from PowerShell, as unregistered apps cannot show them
+	windowsAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+)
+
+// channelNames are the channels notifications can be sent to
+var channelNames = []string{"desktop", "slack", "teams", "webhook"}
+
+// webhookEnvVars holds the URL environment variable for each webhook channel
+var webhookEnvVars = map[string]string{
+	"slack":   SlackWebhookEnvVar,
+	"teams":   TeamsWebhookEnvVar,
+	"webhook": WebhookEnvVar,
+}
+
+// windowsToastScript shows a toast with the title and message from the environment, so they are
+// never parsed as PowerShell
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:MCP_NOTIFY_TITLE)) | Out-Null
+$text.Item(1).AppendChild($template.CreateTextNode($env:MCP_NOTIFY_MESSAGE)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:MCP_NOTIFY_APP_ID).Show($toast)`
+
+// sendDesktop shows a desktop notification with the platform's notification command
+func sendDesktop(ctx context.Context, n *notification) error {
+	var command, env []string
+	if custom := strings.Fields(os.Getenv(DesktopCommandEnvVar)); len(custom) > 0 {
+		command = append(custom, n.title, n.message)
+	} else {
+		switch runtime.GOOS {
+		case "darwin":
+			// Passed as arguments so quotes in the text cannot break out of the AppleScript
+			command = []string{"osascript",
+				"-e", "on run argv",
+				"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+				"-e", "end run",
+				n.title, n.message}
+		case "windows":
+			command = []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript}
+			env = []string{"MCP_NOTIFY_TITLE=" + n.title, "MCP_NOTIFY_MESSAGE=" + n.message, "MCP_NOTIFY_APP_ID=" + windowsAppID}
+		default:
+			if _, err := exec.LookPath("notify-send"); err != nil {
+				return fmt.Errorf("notify-send was not found - install libnotify (e.g. libnotify-bin), or set %s", DesktopCommandEnvVar)
+			}
+			urgency := "normal"
+			if n.status == "failure" {
+				urgency = "critical"
+			}
+			command = []string{"notify-send", "--app-name=mcp-devtools", "--urgency=" + urgency, "--", n.title, n.message}
+		}
+	}
+	return runCommand(ctx, command, env)
+}
+
+// runCommand runs a notification command, including its error output in errors
+func runCommand(ctx context.Context, command, env []string) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		name := filepath.Base(command[0])
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %s", name, commandTimeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return fmt.Errorf("%s failed: %s", name, message)
+	}
+	return nil
+}
+
+// sendWebhook posts a notification to a webhook channel in the format it expects
+func sendWebhook(ctx context.Context, logger *logrus.Logger, channel, target string, n *notification) error {
+	var payload any
+	switch channel {
+	case "slack":
+		payload = map[string]any{"text": "*" + n.title + "*\n" + n.message}
+	case "teams":
+		// An Adaptive Card, which Teams workflow webhooks and the older connectors both accept
+		payload = map[string]any{
+			"type": "message",
+			"attachments": []any{map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []any{
+						map[string]any{"type": "TextBlock", "text": n.title, "weight": "Bolder", "size": "Medium", "wrap": true},
+						map[string]any{"type": "TextBlock", "text": n.message, "wrap": true},
+					},
+				},
+			}},
+		}
+	default:
+		payload = map[string]any{
+			"title":    n.title,
+			"message":  n.message,
+			"status":   n.status,
+			"project":  n.project,
+			"duration": n.duration,
+			"host":     n.host,
+			"time":     n.time,
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("the %s URL is not valid", webhookEnvVars[channel])
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := httpclient.New(httpclient.Options{Timeout: webhookTimeout, Logger: logger})
+	resp, err := client.Do(req)
+	if err != nil {
+		// Webhook URLs are secrets, so leave them out of errors
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		message := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(detail)); text != "" {
+			message += ": " + text
+		}
+		return errors.New(message)
+	}
+	return nil
+}
+
+// webhookURL returns a webhook channel's configured URL, checking it is http or https
+func webhookURL(channel string) (string, error) {
+	envVar := webhookEnvVars[channel]
+	raw := strings.TrimSpace(os.Getenv(envVar))
+	if raw == "" {
+		return "", fmt.Errorf("%s is not configured; the server must set %s", channel, envVar)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", fmt.Errorf("%s must be an http or https URL", envVar)
+	}
+	return raw, nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// TitleTemplateEnvVar overrides the template for notification titles
+	TitleTemplateEnvVar = "NOTIFY_TITLE_TEMPLATE"
+	// MessageTemplateEnvVar overrides the template for notification messages
+	MessageTemplateEnvVar = "NOTIFY_MESSAGE_TEMPLATE"
+
+	defaultTitleTemplate   = `{{.Emoji}} {{.Title}}{{if .Project}} - {{.Project}}{{end}}`
+	defaultMessageTemplate = `{{.Message}}{{if .Duration}} (took {{.Duration}}){{end}}`
+
+	maxTitleLength   = 200
+	maxMessageLength = 2000
+)
+
+// statuses are the kinds of notification, with the default title and emoji for each
+var statuses = map[string]struct{ title, emoji string }{
+	"success":   {"Task complete", "✅"},
+	"failure":   {"Task failed", "❌"},
+	"attention": {"Input needed", "🔔"},
+	"info":      {"Notification", "ℹ️"},
+}
+
+// templateData is what title and message templates can use
+type templateData struct {
+	Title    string
+	Message  string
+	Status   string
+	Emoji    string
+	Project  string
+	Duration string
+	Host     string
+	Time     string
+}
+
+// render fills in the title and message templates, shortening either when it is too long
+func render(data templateData) (*notification, []string, error) {
+	title, err := execute(TitleTemplateEnvVar, defaultTitleTemplate, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	message, err := execute(MessageTemplateEnvVar, defaultMessageTemplate, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		title = shorten(title, maxTitleLength)
+		warnings = append(warnings, fmt.Sprintf("The title was shortened to %d characters.", maxTitleLength))
+	}
+	if utf8.RuneCountInString(message) > maxMessageLength {
+		message = shorten(message, maxMessageLength)
+		warnings = append(warnings, fmt.Sprintf("The message was shortened to %d characters.", maxMessageLength))
+	}
+	return &notification{
+		title:    title,
+		message:  message,
+		status:   data.Status,
+		project:  data.Project,
+		duration: data.Duration,
+		host:     data.Host,
+		time:     data.Time,
+	}, warnings, nil
+}
+
+// execute renders the template set in envVar, or fallback when it is not set
+func execute(envVar, fallback string, data templateData) (string, error) {
+	text := fallback
+	if custom := os.Getenv(envVar); custom != "" {
+		text = custom
+	}
+	tmpl, err := template.New(envVar).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a valid template: %w", envVar, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("%s could not be rendered: %w", envVar, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// shorten cuts text to length characters, ending with an ellipsis
+func shorten(text string, length int) string {
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:length-1])) + "…"
+}
+
+// formatDuration formats seconds as a rounded duration such as 4m12s, or "" for under a second
+func formatDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,283 @@
+package notify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// RateLimitEnvVar sets how many notifications can be sent per minute
+	RateLimitEnvVar = "NOTIFY_RATE_LIMIT"
+
+	defaultRateLimit = 5
+	defaultChannels  = "desktop"
+)
+
+// NotifyTool sends notifications to the desktop and to chat webhooks
+type NotifyTool struct {
+	once    sync.Once
+	limiter *rate.Limiter
+	err     error
+}
+
+// init registers the notify tool
+func init() {
+	registry.Register(&NotifyTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *NotifyTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"notify",
+		mcp.WithDescription(`Send the user a notification, such as when a long-running task finishes or fails, or when you need their input to continue.
+
+Channels:
+- desktop: a desktop notification on macOS, Linux or Windows
+- slack, teams, webhook: a post to a webhook the server has configured
+
+Notifications are rate limited. Only notify about things the user would want to be interrupted for.`),
+		mcp.WithString("message",
+			mcp.Required(),
+			mcp.Description("What happened, e.g. 'All 412 tests passed' or 'The migration failed on step 3'"),
+		),
+		mcp.WithString("title",
+			mcp.Description("Short title; defaults to one for the status, e.g. 'Task complete'"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Kind of notification, which sets the default title and emoji (default: info)"),
+			mcp.Enum("success", "failure", "attention", "info"),
+		),
+		mcp.WithString("project",
+			mcp.Description("Project the notification is about (default: the workspace's directory name)"),
+		),
+		mcp.WithNumber("duration",
+			mcp.Description("How long the task took in seconds, shown in the message"),
+		),
+		mcp.WithString("channels",
+			mcp.Description("Comma-separated channels to send to: desktop, slack, teams, webhook (default: the server's choice, usually desktop)"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Shows a notification or posts a message
+		mcp.WithDestructiveHintAnnotation(false), // Nothing is changed or removed
+		mcp.WithIdempotentHintAnnotation(false),  // Each call sends another notification
+		mcp.WithOpenWorldHintAnnotation(true),    // Webhook channels post to external services
+	)
+}
+
+// Execute renders a notification and sends it to each channel
+func (t *NotifyTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	message, _ := args["message"].(string)
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("missing required parameter: message")
+	}
+	status, _ := args["status"].(string)
+	status = cmp.Or(status, "info")
+	kind, ok := statuses[status]
+	if !ok {
+		return nil, fmt.Errorf("status must be one of success, failure, attention or info, got: %s", status)
+	}
+	channels, err := parseChannels(args)
+	if err != nil {
+		return nil, err
+	}
+
+	data := templateData{
+		Title:   strings.TrimSpace(stringArg(args, "title")),
+		Message: strings.TrimSpace(message),
+		Status:  status,
+		Emoji:   kind.emoji,
+		Project: strings.TrimSpace(stringArg(args, "project")),
+		Time:    time.Now().Format(time.RFC3339),
+	}
+	data.Title = cmp.Or(data.Title, kind.title)
+	if data.Project == "" {
+		if roots := workspace.Roots(ctx); len(roots) > 0 {
+			data.Project = filepath.Base(roots[0])
+		}
+	}
+	if value, ok := args["duration"].(float64); ok {
+		if value < 0 {
+			return nil, fmt.Errorf("duration must be a number of seconds, got: %v", value)
+		}
+		data.Duration = formatDuration(value)
+	}
+	data.Host, _ = os.Hostname()
+
+	n, warnings, err := render(data)
+	if err != nil {
+		return nil, err
+	}
+	// Notifications leave the session, so keep secrets out of them
+	redacted := 0
+	var count int
+	n.title, count = security.Redact(n.title)
+	redacted += count
+	n.message, count = security.Redact(n.message)
+	redacted += count
+	if redacted > 0 {
+		warnings = append(warnings, fmt.Sprintf("Secrets were redacted from the notification (%d found).", redacted))
+	}
+
+	if err := t.allow(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Title: n.title, Message: n.message, Warnings: warnings}
+	sent := 0
+	for _, channel := range channels {
+		delivery := Delivery{Channel: channel}
+		if channel == "desktop" {
+			err = sendDesktop(ctx, n)
+		} else {
+			var target string
+			if target, err = webhookURL(channel); err == nil {
+				err = sendWebhook(ctx, logger, channel, target, n)
+			}
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+			logger.WithError(err).WithField("channel", channel).Debug("Failed to send notification")
+		} else {
+			delivery.Sent = true
+			sent++
+		}
+		result.Deliveries = append(result.Deliveries, delivery)
+	}
+	if sent == 0 {
+		errs := make([]string, 0, len(result.Deliveries))
+		for _, delivery := range result.Deliveries {
+			errs = append(errs, delivery.Channel+": "+delivery.Error)
+		}
+		return nil, fmt.Errorf("the notification was not sent: %s", strings.Join(errs, "; "))
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(strings.TrimSuffix(buffer.String(), "\n")), nil
+}
+
+// allow reports an error when a notification would go over the rate limit
+func (t *NotifyTool) allow() error {
+	t.once.Do(func() {
+		perMinute := defaultRateLimit
+		if raw := strings.TrimSpace(os.Getenv(RateLimitEnvVar)); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 1 {
+				t.err = fmt.Errorf("%s must be a whole number of notifications per minute, got: %s", RateLimitEnvVar, raw)
+				return
+			}
+			perMinute = value
+		}
+		t.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	})
+	if t.err != nil {
+		return t.err
+	}
+	// Refuse rather than wait, so a burst of notifications is dropped instead of queued
+	reservation := t.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		return fmt.Errorf("too many notifications, try again in %s", delay.Round(time.Second))
+	}
+	return nil
+}
+
+// parseChannels returns the channels named in args, or the server's default channels
+func parseChannels(args map[string]any) ([]string, error) {
+	raw := strings.TrimSpace(stringArg(args, "channels"))
+	if raw == "" {
+		raw = cmp.Or(strings.TrimSpace(os.Getenv(ChannelsEnvVar)), defaultChannels)
+	}
+	var channels []string
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(channelNames, name) {
+			return nil, fmt.Errorf("unknown channel: %s (expected desktop, slack, teams or webhook)", name)
+		}
+		if !slices.Contains(channels, name) {
+			channels = append(channels, name)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no channels to send to")
+	}
+	return channels, nil
+}
+
+// stringArg returns a string argument, or "" when it is missing
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// ProvideExtendedInfo provides detailed usage information for the notify tool
+func (t *NotifyTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "When a long-running task the user is waiting on finishes or fails, such as a build, test run, migration or large refactor, or when you are blocked and need the user's input.",
+		WhenNotToUse: "Routine progress updates or after every step - notifications interrupt the user and are rate limited. Anything the user has not asked to be told about while they are watching the session.",
+		CommonPatterns: []string{
+			"Send a success notification with the duration when a long task completes",
+			"Send a failure notification with a one-line reason when a task fails",
+			"Send an attention notification when you need a decision before continuing",
+			"Name slack or teams in channels only when the user asked to be notified there",
+		},
+		ParameterDetails: map[string]string{
+			"message":  "One or two sentences saying what happened. Secrets are redacted before sending.",
+			"status":   "success, failure, attention or info. Sets the default title and emoji, and failure notifications are shown as urgent on Linux.",
+			"duration": "Seconds; formatted like 4m12s. Omit for short tasks.",
+			"channels": fmt.Sprintf("Webhook channels need the server to set %s, %s or %s; webhook URLs cannot be passed as parameters. Defaults to %s, or desktop.", SlackWebhookEnvVar, TeamsWebhookEnvVar, WebhookEnvVar, ChannelsEnvVar),
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Tell the user the test suite passed",
+				Arguments:      map[string]any{"message": "All 412 tests passed", "status": "success", "duration": 253},
+				ExpectedResult: "The title and message sent and whether each channel received it",
+			},
+			{
+				Description:    "Ask for input on the desktop and in Slack",
+				Arguments:      map[string]any{"message": "The migration needs a decision on the orders table", "status": "attention", "channels": "desktop,slack"},
+				ExpectedResult: "A delivery for each channel, with an error for any that failed",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "notify-send was not found",
+				Solution: fmt.Sprintf("Install libnotify (libnotify-bin on Debian and Ubuntu). Servers without a desktop, such as over SSH or in a container, should use a webhook channel or set %s.", DesktopCommandEnvVar),
+			},
+			{
+				Problem:  "A channel is not configured",
+				Solution: "Webhook URLs are set by the server's configuration. Use the desktop channel, or ask the user to configure the webhook.",
+			},
+			{
+				Problem:  "Too many notifications",
+				Solution: fmt.Sprintf("Send fewer notifications; the limit is set by %s (default %d per minute).", RateLimitEnvVar, defaultRateLimit),
+			},
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package notify
+
+// Result reports the notification sent and where it was delivered
+type Result struct {
+	Title      string     `json:"title"`
+	Message    string     `json:"message"`
+	Deliveries []Delivery `json:"deliveries"`
+	Warnings   []string   `json:"warnings,omitempty"`
+}
+
+// Delivery is the outcome of sending a notification to one channel
+type Delivery struct {
+	Channel string `json:"channel"`
+	Sent    bool   `json:"sent"`
+	Error   string `json:"error,omitempty"`
+}
+
+// notification is a rendered notification ready to send
+type notification struct {
+	title    string
+	message  string
+	status   string
+	project  string
+	duration string
+	host     string
+	time     string
+}
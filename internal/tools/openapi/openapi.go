@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxSpecSize            = 20 * 1024 * 1024 // 20MB
+	maxOutputChars         = 60000
+	cacheKeyPrefix         = "openapi:"
+	cacheTTL               = 30 * time.Minute
+	fetchTimeout           = 60 * time.Second
+	defaultLimit           = 50
+	maxLimit               = 500
+	defaultSchemaDepth     = 3
+	maxSchemaDepth         = 8
+	maxSummaryEndpoints    = 40
+	maxSummaryDescription  = 500
+	maxEndpointDescription = 800
+	maxListSummary         = 100
+	maxPropertyDescription = 80
+)
+
+// OpenAPITool summarises OpenAPI and Swagger specifications and looks up endpoints
+type OpenAPITool struct{}
+
+// init registers the openapi tool
+func init() {
+	registry.Register(&OpenAPITool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *OpenAPITool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"openapi",
+		mcp.WithDescription(`Query an OpenAPI 3.x or Swagger 2.0 spec (JSON or YAML, from a file or URL) without loading the whole document into context. The parsed spec is cached between calls.
+
+Functions:
+- summary: API title, version, servers, tags and endpoint counts
+- list_endpoints: endpoints filtered by path substring, method, tag or free-text query
+- get_endpoint: parameters, request body and response schemas for one endpoint
+- get_schema: a named component schema (components/schemas or definitions)
+
+Output is compact markdown with schemas in a TypeScript-like notation.`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("summary", "list_endpoints", "get_endpoint", "get_schema"),
+		),
+		mcp.WithString("source",
+			mcp.Required(),
+			mcp.Description("Absolute file path or http(s) URL of the spec"),
+		),
+		mcp.WithString("path",
+			mcp.Description("list_endpoints: path substring filter (e.g. /users). get_endpoint: endpoint path, either templated (/users/{id}) or concrete (/users/42)"),
+		),
+		mcp.WithString("method",
+			mcp.Description("HTTP method filter (e.g. GET, POST). Required by get_endpoint when a path has several methods"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("list_endpoints: only endpoints with this tag"),
+		),
+		mcp.WithString("query",
+			mcp.Description("list_endpoints: case-insensitive text matched against path, operationId, summary, description and tags"),
+		),
+		mcp.WithString("name",
+			mcp.Description("get_schema: schema name (e.g. Order)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("list_endpoints: maximum endpoints to list (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description(fmt.Sprintf("How many levels of nested $ref schemas to expand (default: %d, max: %d)", defaultSchemaDepth, maxSchemaDepth)),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Reload the spec instead of using the cached copy"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads specs
+		mcp.WithDestructiveHintAnnotation(false), // Makes no changes
+		mcp.WithIdempotentHintAnnotation(true),   // Same spec gives the same output
+		mcp.WithOpenWorldHintAnnotation(true),    // Can fetch specs from URLs
+	)
+}
+
+// Execute runs the requested function against the spec
+func (t *OpenAPITool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	if function == "" {
+		return nil, fmt.Errorf("missing required parameter: function")
+	}
+	source, _ := args["source"].(string)
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("missing required parameter: source")
+	}
+
+	depth := defaultSchemaDepth
+	if d, ok := args["depth"].(float64); ok {
+		if d < 0 || d > maxSchemaDepth {
+			return nil, fmt.Errorf("depth must be between 0 and %d", maxSchemaDepth)
+		}
+		depth = int(d)
+	}
+	limit := defaultLimit
+	if l, ok := args["limit"].(float64); ok {
+		if l < 1 || l > maxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		limit = int(l)
+	}
+	refresh, _ := args["refresh"].(bool)
+	path, _ := args["path"].(string)
+	method, _ := args["method"].(string)
+
+	spec, cached, err := loadSpec(ctx, cache, source, refresh)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{
+		"source":    source,
+		"function":  function,
+		"cached":    cached,
+		"endpoints": len(spec.Endpoints),
+	}).Debug("OpenAPI spec loaded")
+
+	var output string
+	switch function {
+	case "summary":
+		output = renderSummary(spec)
+	case "list_endpoints":
+		tag, _ := args["tag"].(string)
+		query, _ := args["query"].(string)
+		output = renderEndpointList(spec.filterEndpoints(path, method, tag, query), limit)
+	case "get_endpoint":
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("missing required parameter: path")
+		}
+		ep, candidates := spec.findEndpoint(method, path)
+		if ep == nil {
+			if len(candidates) > 0 {
+				return nil, fmt.Errorf("no %s operation for %s - available: %s", strings.ToUpper(method), path, strings.Join(candidates, ", "))
+			}
+			return nil, fmt.Errorf("no endpoint matches %s - use list_endpoints to find the correct path", path)
+		}
+		output = renderEndpoint(spec, ep, depth)
+	case "get_schema":
+		name, _ := args["name"].(string)
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		schema, resolvedName := spec.schema(name)
+		if schema == nil {
+			return nil, fmt.Errorf("schema %q not found%s", name, suggestSchemas(spec, name))
+		}
+		output = renderSchemaDetail(spec, resolvedName, schema, depth)
+	default:
+		return nil, fmt.Errorf("unknown function: %s (expected summary, list_endpoints, get_endpoint or get_schema)", function)
+	}
+
+	if len(output) > maxOutputChars {
+		output = output[:maxOutputChars] + "\n\n[Output truncated - use filters or a lower depth to narrow the result]\n"
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// suggestSchemas lists schema names similar to the one requested
+func suggestSchemas(spec *specIndex, name string) string {
+	lower := strings.ToLower(name)
+	var similar []string
+	for _, candidate := range spec.SchemaNames {
+		if strings.Contains(strings.ToLower(candidate), lower) {
+			similar = append(similar, candidate)
+		}
+		if len(similar) == 10 {
+			break
+		}
+	}
+	if len(similar) == 0 {
+		return fmt.Sprintf(" (the spec defines %d schemas)", len(spec.SchemaNames))
+	}
+	return " - did you mean: " + strings.Join(similar, ", ")
+}
+
+// ProvideExtendedInfo provides detailed usage information for the openapi tool
+func (t *OpenAPITool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Get an overview of a remote API",
+				Arguments: map[string]any{
+					"function": "summary",
+					"source":   "https://petstore3.swagger.io/api/v3/openapi.json",
+				},
+				ExpectedResult: "Title, version, servers, endpoint counts per tag and, for small specs, the full endpoint list",
+			},
+			{
+				Description: "List user endpoints",
+				Arguments: map[string]any{
+					"function": "list_endpoints",
+					"source":   "/Users/name/project/api/openapi.yaml",
+					"path":     "/users",
+				},
+				ExpectedResult: "A markdown list of matching endpoints with their summaries",
+			},
+			{
+				Description: "Show request and response schemas for creating an order",
+				Arguments: map[string]any{
+					"function": "get_endpoint",
+					"source":   "/Users/name/project/api/openapi.yaml",
+					"method":   "POST",
+					"path":     "/orders",
+				},
+				ExpectedResult: "Parameters table, request body schema and a schema per response code",
+			},
+			{
+				Description: "Look up a component schema",
+				Arguments: map[string]any{
+					"function": "get_schema",
+					"source":   "/Users/name/project/api/openapi.yaml",
+					"name":     "Order",
+				},
+				ExpectedResult: "The Order schema with nested references expanded to the default depth",
+			},
+		},
+		CommonPatterns: []string{
+			"Start with summary, then list_endpoints with a tag or path filter, then get_endpoint for the operation you need",
+			"Use get_schema for types referenced by name when depth stops expanding them",
+			"Pass a concrete path such as /users/42 to get_endpoint to find the matching /users/{id} template",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A schema shows only its name instead of its fields",
+				Solution: "It was not expanded because of the depth limit or a circular reference - call get_schema with that name or increase depth",
+			},
+			{
+				Problem:  "Changes to a remote spec are not reflected",
+				Solution: "Remote specs are cached for 30 minutes - set refresh to true to reload",
+			},
+			{
+				Problem:  "External $refs (other files) are shown as plain references",
+				Solution: "Only references within the same document are resolved - bundle the spec first or query the referenced file directly",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"source": "Local files are re-read automatically when their modification time changes. URLs are subject to the security domain rules",
+			"depth":  "Controls how many nested $ref levels are expanded inline. Lower values give shorter output for large schemas",
+			"query":  "Matches the path, operationId, summary, description and tags of each endpoint",
+		},
+		WhenToUse:    "Exploring or integrating with an API described by an OpenAPI or Swagger spec, especially large specs that would not fit in context",
+		WhenNotToUse: "Calling the API itself (use the api tool) or validating and linting a spec",
+	}
+}
@@ -0,0 +1,458 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// schemaRenderer renders schemas in a compact, TypeScript-like notation
+type schemaRenderer struct {
+	spec     *specIndex
+	maxDepth int
+}
+
+// render returns a compact representation of a schema, resolving local $refs until
+// maxDepth is reached or a cycle is detected
+func (r *schemaRenderer) render(schema any, depth int, indent string, seen []string) string {
+	m := mapValue(schema)
+	if m == nil {
+		return "any"
+	}
+
+	if ref := stringValue(m["$ref"]); ref != "" {
+		name := refName(ref)
+		if slices.Contains(seen, ref) || depth >= r.maxDepth {
+			return name
+		}
+		resolved := r.spec.resolveRef(ref)
+		if resolved == nil {
+			return ref
+		}
+		body := r.render(resolved, depth, indent, append(seen, ref))
+		if strings.HasPrefix(body, "{") {
+			return name + " " + body
+		}
+		return body
+	}
+
+	for _, combinator := range []struct{ key, sep string }{{"allOf", " & "}, {"oneOf", " | "}, {"anyOf", " | "}} {
+		if parts := sliceValue(m[combinator.key]); len(parts) > 0 {
+			rendered := make([]string, 0, len(parts))
+			for _, part := range parts {
+				rendered = append(rendered, r.render(part, depth, indent, seen))
+			}
+			return strings.Join(rendered, combinator.sep)
+		}
+	}
+
+	if values := sliceValue(m["enum"]); len(values) > 0 {
+		rendered := make([]string, 0, len(values))
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				rendered = append(rendered, fmt.Sprintf("%q", s))
+			} else {
+				rendered = append(rendered, stringValue(v))
+			}
+		}
+		return strings.Join(rendered, " | ")
+	}
+
+	typeName := schemaType(m)
+	switch {
+	case typeName == "array":
+		return "array<" + r.render(m["items"], depth+1, indent, seen) + ">"
+	case typeName == "object" || m["properties"] != nil:
+		properties := mapValue(m["properties"])
+		if len(properties) == 0 {
+			if additional := mapValue(m["additionalProperties"]); additional != nil {
+				return "map<string, " + r.render(additional, depth+1, indent, seen) + ">"
+			}
+			return "object"
+		}
+		if depth >= r.maxDepth {
+			return "{...}"
+		}
+		var required []string
+		for _, name := range sliceValue(m["required"]) {
+			required = append(required, stringValue(name))
+		}
+
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, name := range sortedKeys(properties) {
+			prop := properties[name]
+			optional := "?"
+			if slices.Contains(required, name) {
+				optional = ""
+			}
+			fmt.Fprintf(&sb, "%s  %s%s: %s%s\n", indent, name, optional, r.render(prop, depth+1, indent+"  ", seen), propertyNotes(mapValue(prop)))
+		}
+		sb.WriteString(indent + "}")
+		return sb.String()
+	default:
+		if format := stringValue(m["format"]); format != "" {
+			return typeName + "(" + format + ")"
+		}
+		return typeName
+	}
+}
+
+// schemaType returns the schema type, handling OpenAPI 3.1 type arrays
+func schemaType(m map[string]any) string {
+	if types := sliceValue(m["type"]); len(types) > 0 {
+		names := make([]string, 0, len(types))
+		for _, t := range types {
+			names = append(names, stringValue(t))
+		}
+		return strings.Join(names, " | ")
+	}
+	if t := stringValue(m["type"]); t != "" {
+		if m["nullable"] == true {
+			return t + " | null"
+		}
+		return t
+	}
+	return "any"
+}
+
+// propertyNotes returns a trailing comment with flags and a short description
+func propertyNotes(prop map[string]any) string {
+	var notes []string
+	if prop["readOnly"] == true {
+		notes = append(notes, "read-only")
+	}
+	if prop["writeOnly"] == true {
+		notes = append(notes, "write-only")
+	}
+	if prop["deprecated"] == true {
+		notes = append(notes, "deprecated")
+	}
+	if def, ok := prop["default"]; ok {
+		notes = append(notes, "default "+stringValue(def))
+	}
+	if desc := truncate(stringValue(prop["description"]), maxPropertyDescription); desc != "" {
+		notes = append(notes, desc)
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " // " + strings.Join(notes, "; ")
+}
+
+// refName returns the final segment of a $ref
+func refName(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// truncate collapses whitespace and shortens text to at most n runes
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// renderSummary renders an overview of the specification
+func renderSummary(spec *specIndex) string {
+	var sb strings.Builder
+	title := spec.Title
+	if title == "" {
+		title = "Untitled API"
+	}
+	fmt.Fprintf(&sb, "# %s", title)
+	if spec.APIVersion != "" {
+		fmt.Fprintf(&sb, " (v%s)", spec.APIVersion)
+	}
+	sb.WriteString("\n\n")
+
+	kind := "OpenAPI"
+	if strings.HasPrefix(spec.SpecVersion, "2") {
+		kind = "Swagger"
+	}
+	fmt.Fprintf(&sb, "%s %s · %d endpoints · %d schemas\n", kind, spec.SpecVersion, len(spec.Endpoints), len(spec.SchemaNames))
+	if len(spec.Servers) > 0 {
+		fmt.Fprintf(&sb, "Servers: %s\n", strings.Join(spec.Servers, ", "))
+	}
+	if desc := truncate(spec.Description, maxSummaryDescription); desc != "" {
+		fmt.Fprintf(&sb, "\n%s\n", desc)
+	}
+
+	counts := spec.tagCounts()
+	if len(counts) > 0 {
+		sb.WriteString("\n## Tags\n\n")
+		tags := make([]string, 0, len(counts))
+		for tag := range counts {
+			tags = append(tags, tag)
+		}
+		slices.Sort(tags)
+		for _, tag := range tags {
+			fmt.Fprintf(&sb, "- %s (%d)\n", tag, counts[tag])
+		}
+	}
+
+	if len(spec.Endpoints) <= maxSummaryEndpoints {
+		sb.WriteString("\n## Endpoints\n\n")
+		for _, ep := range spec.Endpoints {
+			sb.WriteString(endpointLine(ep))
+		}
+	} else {
+		fmt.Fprintf(&sb, "\nUse list_endpoints with a path, method, tag or query filter to browse the %d endpoints.\n", len(spec.Endpoints))
+	}
+	return sb.String()
+}
+
+// renderEndpointList renders a filtered list of endpoints
+func renderEndpointList(matches []endpoint, limit int) string {
+	if len(matches) == 0 {
+		return "No endpoints matched the filters."
+	}
+	var sb strings.Builder
+	if len(matches) > limit {
+		fmt.Fprintf(&sb, "Found %d endpoints, showing the first %d. Narrow the filters or raise limit to see more.\n\n", len(matches), limit)
+		matches = matches[:limit]
+	} else {
+		fmt.Fprintf(&sb, "Found %d endpoints.\n\n", len(matches))
+	}
+	for _, ep := range matches {
+		sb.WriteString(endpointLine(ep))
+	}
+	return sb.String()
+}
+
+// endpointLine renders a single endpoint as a markdown list item
+func endpointLine(ep endpoint) string {
+	line := fmt.Sprintf("- `%s %s`", ep.Method, ep.Path)
+	if summary := truncate(ep.Summary, maxListSummary); summary != "" {
+		line += " - " + summary
+	}
+	if ep.Deprecated {
+		line += " (deprecated)"
+	}
+	return line + "\n"
+}
+
+// renderEndpoint renders the parameters, request body and responses of an endpoint
+func renderEndpoint(spec *specIndex, ep *endpoint, maxDepth int) string {
+	r := &schemaRenderer{spec: spec, maxDepth: maxDepth}
+	swagger := strings.HasPrefix(spec.SpecVersion, "2")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s %s\n\n", ep.Method, ep.Path)
+	if ep.Summary != "" {
+		fmt.Fprintf(&sb, "%s\n\n", ep.Summary)
+	}
+	var meta []string
+	if ep.OperationID != "" {
+		meta = append(meta, "operationId: `"+ep.OperationID+"`")
+	}
+	if len(ep.Tags) > 0 {
+		meta = append(meta, "tags: "+strings.Join(ep.Tags, ", "))
+	}
+	if ep.Deprecated {
+		meta = append(meta, "**deprecated**")
+	}
+	if schemes := securitySchemes(spec, ep); len(schemes) > 0 {
+		meta = append(meta, "auth: "+strings.Join(schemes, ", "))
+	}
+	if len(meta) > 0 {
+		sb.WriteString(strings.Join(meta, " · ") + "\n\n")
+	}
+	if desc := truncate(ep.Description, maxEndpointDescription); desc != "" && desc != ep.Summary {
+		fmt.Fprintf(&sb, "%s\n\n", desc)
+	}
+
+	var bodyParam map[string]any
+	var formParams []map[string]any
+	params := collectParameters(spec, ep)
+	var rows []string
+	for _, p := range params {
+		in := stringValue(p["in"])
+		switch {
+		case swagger && in == "body":
+			bodyParam = p
+			continue
+		case swagger && in == "formData":
+			formParams = append(formParams, p)
+			continue
+		}
+		schema := p["schema"]
+		if schema == nil {
+			schema = p // Swagger 2.0 declares types on the parameter itself
+		}
+		required := ""
+		if p["required"] == true {
+			required = "yes"
+		}
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s | %s |", stringValue(p["name"]), in,
+			strings.ReplaceAll(r.render(schema, maxDepth, "", nil), "|", "\\|"), required, truncate(stringValue(p["description"]), maxPropertyDescription)))
+	}
+	if len(rows) > 0 {
+		sb.WriteString("### Parameters\n\n| Name | In | Type | Required | Description |\n|---|---|---|---|---|\n")
+		sb.WriteString(strings.Join(rows, "\n") + "\n\n")
+	}
+
+	switch {
+	case swagger && bodyParam != nil:
+		sb.WriteString(requestBodyHeading(bodyParam["required"] == true, consumes(spec, ep)))
+		fmt.Fprintf(&sb, "```\n%s\n```\n\n", r.render(bodyParam["schema"], 0, "", nil))
+	case swagger && len(formParams) > 0:
+		properties := make(map[string]any)
+		var required []any
+		for _, p := range formParams {
+			properties[stringValue(p["name"])] = p
+			if p["required"] == true {
+				required = append(required, p["name"])
+			}
+		}
+		sb.WriteString(requestBodyHeading(len(required) > 0, consumes(spec, ep)))
+		form := map[string]any{"type": "object", "properties": properties, "required": required}
+		fmt.Fprintf(&sb, "```\n%s\n```\n\n", r.render(form, 0, "", nil))
+	case !swagger:
+		body := mapValue(ep.operation["requestBody"])
+		if ref := stringValue(body["$ref"]); ref != "" {
+			body = mapValue(spec.resolveRef(ref))
+		}
+		if body != nil {
+			content := mapValue(body["content"])
+			sb.WriteString(requestBodyHeading(body["required"] == true, sortedKeys(content)))
+			if schema := firstContentSchema(content); schema != nil {
+				fmt.Fprintf(&sb, "```\n%s\n```\n\n", r.render(schema, 0, "", nil))
+			}
+		}
+	}
+
+	responses := mapValue(ep.operation["responses"])
+	if len(responses) > 0 {
+		sb.WriteString("### Responses\n\n")
+		for _, code := range sortedKeys(responses) {
+			response := mapValue(responses[code])
+			if ref := stringValue(response["$ref"]); ref != "" {
+				response = mapValue(spec.resolveRef(ref))
+			}
+			fmt.Fprintf(&sb, "**%s**", code)
+			if desc := truncate(stringValue(response["description"]), maxPropertyDescription); desc != "" {
+				fmt.Fprintf(&sb, " - %s", desc)
+			}
+			var schema any
+			if swagger {
+				schema = response["schema"]
+			} else {
+				content := mapValue(response["content"])
+				if len(content) > 0 {
+					fmt.Fprintf(&sb, " (%s)", strings.Join(sortedKeys(content), ", "))
+				}
+				schema = firstContentSchema(content)
+			}
+			sb.WriteString("\n\n")
+			if schema != nil {
+				fmt.Fprintf(&sb, "```\n%s\n```\n\n", r.render(schema, 0, "", nil))
+			}
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderSchemaDetail renders a single named schema
+func renderSchemaDetail(spec *specIndex, name string, schema any, maxDepth int) string {
+	r := &schemaRenderer{spec: spec, maxDepth: maxDepth}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n\n", name)
+	if desc := truncate(stringValue(mapValue(schema)["description"]), maxEndpointDescription); desc != "" {
+		fmt.Fprintf(&sb, "%s\n\n", desc)
+	}
+	// Seed seen with the schema's own refs so self-references print by name
+	seen := []string{"#/components/schemas/" + name, "#/definitions/" + name}
+	fmt.Fprintf(&sb, "```\n%s\n```\n", r.render(schema, 0, "", seen))
+	return sb.String()
+}
+
+// collectParameters merges path-level and operation-level parameters, with the
+// operation overriding matching name and location pairs
+func collectParameters(spec *specIndex, ep *endpoint) []map[string]any {
+	var params []map[string]any
+	index := make(map[string]int)
+	for _, source := range [][]any{sliceValue(ep.pathItem["parameters"]), sliceValue(ep.operation["parameters"])} {
+		for _, raw := range source {
+			p := mapValue(raw)
+			if ref := stringValue(p["$ref"]); ref != "" {
+				p = mapValue(spec.resolveRef(ref))
+			}
+			if p == nil {
+				continue
+			}
+			key := stringValue(p["in"]) + ":" + stringValue(p["name"])
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// securitySchemes returns the names of security schemes required by an endpoint
+func securitySchemes(spec *specIndex, ep *endpoint) []string {
+	requirements, ok := ep.operation["security"]
+	if !ok {
+		requirements = spec.root["security"]
+	}
+	var names []string
+	for _, requirement := range sliceValue(requirements) {
+		for _, name := range sortedKeys(mapValue(requirement)) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// consumes returns the Swagger 2.0 request content types for an endpoint
+func consumes(spec *specIndex, ep *endpoint) []string {
+	raw := sliceValue(ep.operation["consumes"])
+	if len(raw) == 0 {
+		raw = sliceValue(spec.root["consumes"])
+	}
+	types := make([]string, 0, len(raw))
+	for _, t := range raw {
+		types = append(types, stringValue(t))
+	}
+	return types
+}
+
+// requestBodyHeading renders the request body heading with its content types
+func requestBodyHeading(required bool, contentTypes []string) string {
+	heading := "### Request Body"
+	if required {
+		heading += " (required)"
+	}
+	if len(contentTypes) > 0 {
+		heading += " - " + strings.Join(contentTypes, ", ")
+	}
+	return heading + "\n\n"
+}
+
+// firstContentSchema returns the schema for the preferred media type, favouring JSON
+func firstContentSchema(content map[string]any) any {
+	keys := sortedKeys(content)
+	for _, mediaType := range keys {
+		if strings.Contains(mediaType, "json") {
+			return mapValue(content[mediaType])["schema"]
+		}
+	}
+	for _, mediaType := range keys {
+		if schema := mapValue(content[mediaType])["schema"]; schema != nil {
+			return schema
+		}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods lists the operation keys of an OpenAPI path item, in display order
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// specIndex is a parsed specification with an endpoint index
+type specIndex struct {
+	Source      string
+	SpecVersion string // e.g. "3.1.0" or "2.0"
+	Title       string
+	APIVersion  string
+	Description string
+	Servers     []string
+	Endpoints   []endpoint
+	SchemaNames []string
+	root        map[string]any
+}
+
+// endpoint is a single operation in the specification
+type endpoint struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	operation   map[string]any
+	pathItem    map[string]any
+}
+
+// cacheEntry stores a parsed specification alongside freshness information
+type cacheEntry struct {
+	index    *specIndex
+	loadedAt time.Time
+	modTime  time.Time
+}
+
+// loadSpec returns a cached index for the source or loads and parses it
+func loadSpec(ctx context.Context, cache *sync.Map, source string, refresh bool) (*specIndex, bool, error) {
+	isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+
+	var modTime time.Time
+	if !isURL {
+		if !filepath.IsAbs(source) {
+			return nil, false, fmt.Errorf("source must be an absolute file path or an http(s) URL, got: %s", source)
+		}
+		if err := workspace.CheckFileAccess(ctx, source); err != nil {
+			return nil, false, fmt.Errorf("file access denied: %w", err)
+		}
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot access spec file: %w", err)
+		}
+		if info.Size() > maxSpecSize {
+			return nil, false, fmt.Errorf("spec file is %d bytes, maximum is %d", info.Size(), maxSpecSize)
+		}
+		modTime = info.ModTime()
+	}
+
+	cacheKey := cacheKeyPrefix + source
+	if !refresh {
+		if cached, ok := cache.Load(cacheKey); ok {
+			if entry, ok := cached.(cacheEntry); ok && time.Since(entry.loadedAt) < cacheTTL && entry.modTime.Equal(modTime) {
+				return entry.index, true, nil
+			}
+		}
+	}
+
+	var data []byte
+	var err error
+	if isURL {
+		data, err = fetchSpec(ctx, source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	index, err := parseSpec(source, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cache.Store(cacheKey, cacheEntry{index: index, loadedAt: time.Now(), modTime: modTime})
+	return index, false, nil
+}
+
+// fetchSpec downloads a specification, applying domain and content security checks
+func fetchSpec(ctx context.Context, source string) ([]byte, error) {
+	parsedURL, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := security.CheckDomainAccess(parsedURL.Hostname()); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml, text/yaml, */*")
+
+	client := httpclient.NewHTTPClientWithProxy(fetchTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch spec: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	if len(body) > maxSpecSize {
+		return nil, fmt.Errorf("spec exceeds maximum size of %d bytes", maxSpecSize)
+	}
+
+	sourceCtx := security.SourceContext{
+		Tool:        "openapi",
+		URL:         source,
+		Domain:      parsedURL.Hostname(),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if result, err := security.AnalyseContent(string(body), sourceCtx); err == nil && result.Action == security.ActionBlock {
+		return nil, security.FormatSecurityBlockErrorFromResult(result)
+	}
+
+	return body, nil
+}
+
+// parseSpec parses a JSON or YAML OpenAPI 3.x or Swagger 2.0 document and indexes it
+func parseSpec(source string, data []byte) (*specIndex, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse spec as JSON or YAML: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("spec is empty")
+	}
+
+	index := &specIndex{Source: source, root: root}
+	switch {
+	case stringValue(root["openapi"]) != "":
+		index.SpecVersion = stringValue(root["openapi"])
+		for _, s := range sliceValue(root["servers"]) {
+			if u := stringValue(mapValue(s)["url"]); u != "" {
+				index.Servers = append(index.Servers, u)
+			}
+		}
+		index.SchemaNames = sortedKeys(mapValue(mapValue(root["components"])["schemas"]))
+	case stringValue(root["swagger"]) != "":
+		index.SpecVersion = stringValue(root["swagger"])
+		if host := stringValue(root["host"]); host != "" {
+			schemes := sliceValue(root["schemes"])
+			scheme := "https"
+			if len(schemes) > 0 {
+				scheme = stringValue(schemes[0])
+			}
+			index.Servers = append(index.Servers, scheme+"://"+host+stringValue(root["basePath"]))
+		}
+		index.SchemaNames = sortedKeys(mapValue(root["definitions"]))
+	default:
+		return nil, fmt.Errorf("document is not an OpenAPI or Swagger spec (missing 'openapi' or 'swagger' field)")
+	}
+
+	info := mapValue(root["info"])
+	index.Title = stringValue(info["title"])
+	index.APIVersion = stringValue(info["version"])
+	index.Description = stringValue(info["description"])
+
+	paths := mapValue(root["paths"])
+	for _, path := range sortedKeys(paths) {
+		pathItem := mapValue(paths[path])
+		if ref := stringValue(pathItem["$ref"]); ref != "" {
+			pathItem = mapValue(index.resolveRef(ref))
+		}
+		for _, method := range httpMethods {
+			op := mapValue(pathItem[method])
+			if op == nil {
+				continue
+			}
+			ep := endpoint{
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				OperationID: stringValue(op["operationId"]),
+				Summary:     stringValue(op["summary"]),
+				Description: stringValue(op["description"]),
+				Deprecated:  op["deprecated"] == true,
+				operation:   op,
+				pathItem:    pathItem,
+			}
+			for _, tag := range sliceValue(op["tags"]) {
+				ep.Tags = append(ep.Tags, stringValue(tag))
+			}
+			index.Endpoints = append(index.Endpoints, ep)
+		}
+	}
+
+	return index, nil
+}
+
+// resolveRef follows a local JSON pointer reference such as #/components/schemas/Order
+func (s *specIndex) resolveRef(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var current any = s.root
+	for part := range strings.SplitSeq(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			part = unescaped
+		}
+		m := mapValue(current)
+		if m == nil {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// schema returns a named schema from components/schemas or definitions
+func (s *specIndex) schema(name string) (any, string) {
+	if schemas := mapValue(mapValue(s.root["components"])["schemas"]); schemas != nil {
+		if schema, ok := schemas[name]; ok {
+			return schema, name
+		}
+	}
+	if schema, ok := mapValue(s.root["definitions"])[name]; ok {
+		return schema, name
+	}
+	// Fall back to a case-insensitive match
+	for _, candidate := range s.SchemaNames {
+		if strings.EqualFold(candidate, name) {
+			return s.schema(candidate)
+		}
+	}
+	return nil, ""
+}
+
+// findEndpoint matches a method and path, accepting concrete paths such as /users/42
+// for templated paths such as /users/{id}
+func (s *specIndex) findEndpoint(method, path string) (*endpoint, []string) {
+	method = strings.ToUpper(method)
+	path = "/" + strings.Trim(strings.TrimSpace(path), "/")
+
+	var candidates []string
+	for i := range s.Endpoints {
+		ep := &s.Endpoints[i]
+		if !pathMatches(ep.Path, path) {
+			continue
+		}
+		if method == "" || ep.Method == method {
+			return ep, nil
+		}
+		candidates = append(candidates, ep.Method+" "+ep.Path)
+	}
+	return nil, candidates
+}
+
+// pathMatches compares a spec path template with a requested path segment by segment
+func pathMatches(template, path string) bool {
+	if strings.TrimRight(template, "/") == strings.TrimRight(path, "/") {
+		return true
+	}
+	tSegs := strings.Split(strings.Trim(template, "/"), "/")
+	pSegs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tSegs) != len(pSegs) {
+		return false
+	}
+	for i, seg := range tSegs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			continue
+		}
+		if seg != pSegs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// filterEndpoints returns endpoints matching every supplied filter
+func (s *specIndex) filterEndpoints(pathFilter, method, tag, query string) []endpoint {
+	method = strings.ToUpper(method)
+	query = strings.ToLower(query)
+	pathFilter = strings.ToLower(pathFilter)
+
+	var matches []endpoint
+	for _, ep := range s.Endpoints {
+		if method != "" && ep.Method != method {
+			continue
+		}
+		if pathFilter != "" && !strings.Contains(strings.ToLower(ep.Path), pathFilter) {
+			continue
+		}
+		if tag != "" && !slices.ContainsFunc(ep.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		if query != "" {
+			haystack := strings.ToLower(strings.Join([]string{ep.Path, ep.OperationID, ep.Summary, ep.Description, strings.Join(ep.Tags, " ")}, " "))
+			if !strings.Contains(haystack, query) {
+				continue
+			}
+		}
+		matches = append(matches, ep)
+	}
+	return matches
+}
+
+// tagCounts returns the number of endpoints per tag
+func (s *specIndex) tagCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, ep := range s.Endpoints {
+		if len(ep.Tags) == 0 {
+			counts["(untagged)"]++
+		}
+		for _, tag := range ep.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
+
+func mapValue(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func sliceValue(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func stringValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
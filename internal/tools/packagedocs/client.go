@@ -68,8 +68,8 @@ func getPackageDocsRateLimit() float64 {
 
 // newRateLimitedHTTPClient creates a new rate-limited HTTP client with proxy support
 func newRateLimitedHTTPClient() *RateLimitedHTTPClient {
-	// Use shared HTTP client factory with proxy support
-	client := httpclient.NewHTTPClientWithProxy(30 * time.Second)
+	// Use shared HTTP client factory with proxy support, caching documentation so repeat lookups are fast
+	client := httpclient.New(httpclient.Options{Timeout: 30 * time.Second, Cache: true})
 
 	rateLimit := getPackageDocsRateLimit()
 	return &RateLimitedHTTPClient{
@@ -53,24 +53,13 @@ func getPackagesRateLimit() float64 {
 func NewRateLimitedHTTPClient() *RateLimitedHTTPClient {
 	rateLimit := getPackagesRateLimit()
 
-	// Use shared HTTP client factory with proxy support
-	client := httpclient.NewHTTPClientWithProxy(30 * time.Second)
-
-	// Ensure transport is configured (proxy factory may not set it if no proxy configured)
-	var transport *http.Transport
-	if client.Transport == nil {
-		transport = http.DefaultTransport.(*http.Transport).Clone()
-		client.Transport = transport
-	} else if t, ok := client.Transport.(*http.Transport); ok {
-		transport = t
-	}
-
-	// Configure transport to prevent connection reuse issues with rapid sequential requests
-	if transport != nil {
-		// Disable keep-alives to prevent connection reuse race conditions that can cause
-		// incomplete reads with large package registry responses
-		transport.DisableKeepAlives = true
-	}
+	// Use shared HTTP client factory with proxy support, with keep-alives disabled to prevent
+	// connection reuse race conditions that can cause incomplete reads with large package registry
+	// responses
+	client := httpclient.New(httpclient.Options{
+		Timeout:           30 * time.Second,
+		DisableKeepAlives: true,
+	})
 
 	return &RateLimitedHTTPClient{
 		client:  client,
@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultOutputPageSize is the largest tool output, in bytes, returned in one response when
+	// pagination is enabled. About 25,000 tokens.
+	DefaultOutputPageSize = 100 * 1024
+
+	// OutputPageSizeEnvVar overrides DefaultOutputPageSize
+	OutputPageSizeEnvVar = "TOOL_OUTPUT_PAGE_SIZE"
+
+	// continuationTTL is how long the rest of an output is kept after it was last read
+	continuationTTL = 30 * time.Minute
+
+	// maxStoredOutputBytes bounds the memory held by stored outputs across all sessions. The oldest
+	// outputs are dropped first, and a single output larger than this is cut short.
+	maxStoredOutputBytes = 100 * 1024 * 1024
+
+	// pageBoundarySlack is how far back from the page size a page may end, to end on a line break
+	pageBoundarySlack = 0.1
+)
+
+// continuation is the unread remainder of a paginated tool output
+type continuation struct {
+	tool      string
+	session   string
+	text      string
+	offset    int
+	truncated bool // The output was cut short at maxStoredOutputBytes
+	lastRead  time.Time
+}
+
+var (
+	paginationEnabled bool
+	continuationsMu   sync.Mutex
+	continuations     = make(map[string]*continuation)
+)
+
+// EnablePagination turns on pagination of oversized tool outputs. It is enabled when the fetch_more
+// tool is, as outputs cannot otherwise be read past the first page.
+func EnablePagination() {
+	continuationsMu.Lock()
+	defer continuationsMu.Unlock()
+	paginationEnabled = true
+}
+
+// OutputPageSize returns the configured page size in bytes, 0 when TOOL_OUTPUT_PAGE_SIZE turns it off.
+// It does not depend on fetch_more being enabled, as summaries and artifact parts use the same size.
+func OutputPageSize() int {
+	if value := os.Getenv(OutputPageSizeEnvVar); value != "" {
+		if size, err := strconv.Atoi(value); err == nil && size >= 0 {
+			return size
+		}
+	}
+	return DefaultOutputPageSize
+}
+
+// Paginate returns the first page of a text result larger than the page size, keeping the rest for
+// fetch_more with a continuation token given at the end of the page. Results with non-text content,
+// structured content or errors are returned unchanged, as are all results when pagination is disabled.
+func Paginate(ctx context.Context, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	continuationsMu.Lock()
+	enabled := paginationEnabled
+	continuationsMu.Unlock()
+
+	pageSize := OutputPageSize()
+	if !enabled {
+		return result
+	}
+	text, ok := oversizedText(result, pageSize)
+	if !ok {
+		return result
+	}
+
+	entry := &continuation{
+		tool:     toolName,
+		session:  sessionID(ctx),
+		text:     text,
+		lastRead: time.Now(),
+	}
+	if len(entry.text) > maxStoredOutputBytes {
+		entry.text = entry.text[:pageEnd(entry.text, 0, maxStoredOutputBytes)]
+		entry.truncated = true
+	}
+	token, err := newContinuationToken()
+	if err != nil {
+		// Without a token the output cannot be paginated, so it is returned whole
+		return result
+	}
+
+	continuationsMu.Lock()
+	storeContinuationLocked(token, entry)
+	page := nextPageLocked(token, entry, pageSize)
+	continuationsMu.Unlock()
+
+	return &mcp.CallToolResult{
+		Result:  result.Result,
+		Content: []mcp.Content{mcp.NewTextContent(page)},
+	}
+}
+
+// FetchMore returns the next page of the output with the given continuation token. Tokens can only be
+// used by the session whose tool call created them.
+func FetchMore(ctx context.Context, token string) (string, error) {
+	continuationsMu.Lock()
+	defer continuationsMu.Unlock()
+
+	expireContinuationsLocked()
+	entry, ok := continuations[token]
+	if !ok || entry.session != sessionID(ctx) {
+		return "", fmt.Errorf("continuation token %q not found, it may have expired after %s or already been read to the end. Call the original tool again", token, continuationTTL)
+	}
+	return nextPageLocked(token, entry, OutputPageSize()), nil
+}
+
+// nextPageLocked returns the next page of an output and advances it, dropping the output once it
+// has been read to the end. Caller must hold continuationsMu.
+func nextPageLocked(token string, entry *continuation, pageSize int) string {
+	if pageSize == 0 {
+		pageSize = DefaultOutputPageSize
+	}
+	start := entry.offset
+	end := pageEnd(entry.text, start, pageSize)
+	entry.offset = end
+	entry.lastRead = time.Now()
+
+	var page strings.Builder
+	page.WriteString(entry.text[start:end])
+	if end < len(entry.text) {
+		fmt.Fprintf(&page, "\n\n[Output from %s truncated: showing bytes %d-%d of %d. Call fetch_more with continuation_token %q for the next part]",
+			entry.tool, start+1, end, len(entry.text), token)
+		return page.String()
+	}
+
+	delete(continuations, token)
+	if entry.truncated {
+		fmt.Fprintf(&page, "\n\n[End of stored output from %s: the rest was discarded as it exceeded %d bytes]", entry.tool, maxStoredOutputBytes)
+	} else if start > 0 {
+		fmt.Fprintf(&page, "\n\n[End of output from %s: bytes %d-%d of %d]", entry.tool, start+1, end, len(entry.text))
+	}
+	return page.String()
+}
+
+// pageEnd returns where a page starting at start ends: at most pageSize bytes on, preferring the end
+// of a line near the limit and never splitting a UTF-8 character
+func pageEnd(text string, start, pageSize int) int {
+	end := start + pageSize
+	if end >= len(text) {
+		return len(text)
+	}
+	minEnd := end - int(float64(pageSize)*pageBoundarySlack)
+	if newline := strings.LastIndexByte(text[minEnd:end], '\n'); newline >= 0 {
+		return minEnd + newline + 1
+	}
+	for end > start && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	if end == start {
+		// A page smaller than one character still makes progress
+		_, width := utf8.DecodeRuneInString(text[start:])
+		end = start + width
+	}
+	return end
+}
+
+// storeContinuationLocked stores an output, dropping expired and then the oldest outputs to stay
+// within maxStoredOutputBytes. Caller must hold continuationsMu.
+func storeContinuationLocked(token string, entry *continuation) {
+	expireContinuationsLocked()
+
+	total := len(entry.text)
+	for _, stored := range continuations {
+		total += len(stored.text)
+	}
+	for total > maxStoredOutputBytes && len(continuations) > 0 {
+		var oldestToken string
+		var oldest *continuation
+		for t, stored := range continuations {
+			if oldest == nil || stored.lastRead.Before(oldest.lastRead) {
+				oldestToken, oldest = t, stored
+			}
+		}
+		total -= len(oldest.text)
+		delete(continuations, oldestToken)
+	}
+	continuations[token] = entry
+}
+
+// expireContinuationsLocked drops outputs that have not been read within continuationTTL. Caller must
+// hold continuationsMu.
+func expireContinuationsLocked() {
+	for token, entry := range continuations {
+		if time.Since(entry.lastRead) > continuationTTL {
+			delete(continuations, token)
+		}
+	}
+}
+
+// newContinuationToken returns a random token that cannot be guessed by other sessions
+func newContinuationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// sessionID returns the MCP session ID from the context, or an empty string outside a session
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Widget annotation flags that keep a field from being drawn
+const (
+	annotFlagHidden = 1 << 1
+	annotFlagNoView = 1 << 5
+)
+
+// flattenForm draws each form field's appearance into its page and removes the fields, leaving a
+// document whose values can no longer be edited
+func flattenForm(ctx *model.Context) error {
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		pageDict, _, inherited, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return err
+		}
+		annots, err := ctx.DereferenceArray(pageDict["Annots"])
+		if err != nil || len(annots) == 0 {
+			continue
+		}
+
+		var kept types.Array
+		var content bytes.Buffer
+		for _, obj := range annots {
+			annot, err := ctx.DereferenceDict(obj)
+			if err != nil || annot == nil || annot.NameEntry("Subtype") == nil || *annot.NameEntry("Subtype") != "Widget" {
+				kept = append(kept, obj)
+				continue
+			}
+			if err := drawWidget(ctx, pageDict, inherited, annot, &content); err != nil {
+				return fmt.Errorf("page %d: %w", pageNr, err)
+			}
+		}
+
+		if len(kept) == len(annots) {
+			continue
+		}
+		if len(kept) > 0 {
+			pageDict["Annots"] = kept
+		} else {
+			delete(pageDict, "Annots")
+		}
+		if content.Len() > 0 {
+			if err := appendPageContent(ctx, pageDict, content.Bytes()); err != nil {
+				return fmt.Errorf("page %d: %w", pageNr, err)
+			}
+		}
+	}
+
+	delete(ctx.RootDict, "AcroForm")
+	return nil
+}
+
+// drawWidget writes the drawing of a widget's current appearance to content, adding the appearance
+// to the page's resources. Hidden widgets and those without an appearance are not drawn.
+func drawWidget(ctx *model.Context, pageDict types.Dict, inherited *model.InheritedPageAttrs, annot types.Dict, content *bytes.Buffer) error {
+	if flags := annot.IntEntry("F"); flags != nil && *flags&(annotFlagHidden|annotFlagNoView) != 0 {
+		return nil
+	}
+	ap, err := ctx.DereferenceDict(annot["AP"])
+	if err != nil || ap == nil {
+		return nil
+	}
+
+	// Checkboxes and radio buttons have an appearance for each state, chosen by AS
+	normal := ap["N"]
+	if states, err := ctx.DereferenceDict(normal); err == nil && states != nil {
+		state := annot.NameEntry("AS")
+		if state == nil {
+			return nil
+		}
+		normal = states[*state]
+	}
+	ref, ok := normal.(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+	sd, _, err := ctx.DereferenceStreamDict(ref)
+	if err != nil || sd == nil {
+		return nil
+	}
+
+	rect, ok := numbers(ctx, annot["Rect"], 4)
+	if !ok {
+		return nil
+	}
+	bbox, ok := numbers(ctx, sd.Dict["BBox"], 4)
+	if !ok {
+		return nil
+	}
+	matrix, ok := numbers(ctx, sd.Dict["Matrix"], 6)
+	if !ok {
+		matrix = []float64{1, 0, 0, 1, 0, 0}
+	}
+	sd.Dict["Type"] = types.Name("XObject")
+	sd.Dict["Subtype"] = types.Name("Form")
+
+	// The appearance's box, once transformed by its matrix, is stretched over the widget's rectangle
+	minX, minY, maxX, maxY := transformedBox(bbox, matrix)
+	if maxX-minX == 0 || maxY-minY == 0 {
+		return nil
+	}
+	scaleX := math.Abs(rect[2]-rect[0]) / (maxX - minX)
+	scaleY := math.Abs(rect[3]-rect[1]) / (maxY - minY)
+	x := math.Min(rect[0], rect[2]) - minX*scaleX
+	y := math.Min(rect[1], rect[3]) - minY*scaleY
+
+	xobjects, err := pageXObjects(ctx, pageDict, inherited)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("FlatField%d", ref.ObjectNumber.Value())
+	xobjects[name] = ref
+
+	fmt.Fprintf(content, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		pdfNumber(scaleX), pdfNumber(scaleY), pdfNumber(x), pdfNumber(y), name)
+	return nil
+}
+
+// transformedBox returns the bounds of a box after applying a matrix to its corners
+func transformedBox(box, m []float64) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{box[0], box[1]}, {box[0], box[3]}, {box[2], box[1]}, {box[2], box[3]}} {
+		x := m[0]*corner[0] + m[2]*corner[1] + m[4]
+		y := m[1]*corner[0] + m[3]*corner[1] + m[5]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// pageXObjects returns the page's XObject resources for adding to, giving the page its own resources
+// when it only inherits them
+func pageXObjects(ctx *model.Context, pageDict types.Dict, inherited *model.InheritedPageAttrs) (types.Dict, error) {
+	resources, err := ctx.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		return nil, err
+	}
+	if resources == nil {
+		resources = types.NewDict()
+		if inherited != nil && inherited.Resources != nil {
+			resources = inherited.Resources.Clone().(types.Dict)
+		}
+		pageDict["Resources"] = resources
+	}
+	xobjects, err := ctx.DereferenceDict(resources["XObject"])
+	if err != nil {
+		return nil, err
+	}
+	if xobjects == nil {
+		xobjects = types.NewDict()
+		resources["XObject"] = xobjects
+	}
+	return xobjects, nil
+}
+
+// appendPageContent draws content over the page. The page's own content is wrapped in a saved
+// graphics state first, so anything it leaves changed cannot move the drawing.
+func appendPageContent(ctx *model.Context, pageDict types.Dict, content []byte) error {
+	newStream := func(data []byte) (types.Object, error) {
+		sd, err := ctx.NewStreamDictForBuf(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := sd.Encode(); err != nil {
+			return nil, err
+		}
+		ref, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			return nil, err
+		}
+		return *ref, nil
+	}
+
+	before, err := newStream([]byte("q\n"))
+	if err != nil {
+		return err
+	}
+	after, err := newStream(append([]byte("Q\n"), content...))
+	if err != nil {
+		return err
+	}
+
+	contents := types.Array{before}
+	switch existing := pageDict["Contents"].(type) {
+	case nil:
+	case types.Array:
+		contents = append(contents, existing...)
+	default:
+		if arr, err := ctx.DereferenceArray(existing); err == nil && arr != nil {
+			contents = append(contents, arr...)
+		} else {
+			contents = append(contents, existing)
+		}
+	}
+	pageDict["Contents"] = append(contents, after)
+	return nil
+}
+
+// numbers returns an array of n numbers, such as a rectangle or matrix
+func numbers(ctx *model.Context, obj types.Object, n int) ([]float64, bool) {
+	arr, err := ctx.DereferenceArray(obj)
+	if err != nil || len(arr) != n {
+		return nil, false
+	}
+	result := make([]float64, n)
+	for i, item := range arr {
+		item, err := ctx.Dereference(item)
+		if err != nil {
+			return nil, false
+		}
+		switch v := item.(type) {
+		case types.Integer:
+			result[i] = float64(v.Value())
+		case types.Float:
+			result[i] = v.Value()
+		default:
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+// pdfNumber writes a number for a content stream, which does not allow exponents, to a precision
+// well beyond what a page shows
+func pdfNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,537 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// PDFFormTool reads and fills the AcroForm fields of PDF forms
+type PDFFormTool struct{}
+
+// init registers the PDF form tool
+func init() {
+	registry.Register(&PDFFormTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *PDFFormTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"pdf_form",
+		mcp.WithDescription(`Read and fill the fields of PDF forms (AcroForms).
+
+Actions:
+- read: list each field's name, type, current value and allowed options
+- fill: set fields from a map of field names to values and write a new PDF, flattened by default so the values can no longer be edited
+
+Read a form first to find its field names. Text and date fields take strings, checkboxes take true or false, radio buttons and combo boxes take one of their options, and list boxes take a list of options.`),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("What to do with the form"),
+			mcp.Enum("read", "fill"),
+		),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the PDF form"),
+		),
+		mcp.WithObject("values",
+			mcp.Description("fill: field names, or IDs, mapped to their values, e.g. {\"name\": \"Jo Bloggs\", \"approved\": true}"),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("fill: absolute path to write the filled PDF to (default: <name>_filled.pdf beside the form)"),
+		),
+		mcp.WithBoolean("flatten",
+			mcp.Description("fill: draw the values into the pages and remove the form fields, so they can no longer be edited (default: true)"),
+			mcp.DefaultBool(true),
+		),
+
+		// Non-destructive writing annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // fill writes a new PDF
+		mcp.WithDestructiveHintAnnotation(false), // Never modifies the source form
+		mcp.WithIdempotentHintAnnotation(true),   // Same values produce the same PDF
+		mcp.WithOpenWorldHintAnnotation(false),   // Works with local files only
+	)
+}
+
+// Execute reads or fills the form
+func (t *PDFFormTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	logger.Debug("Executing PDF form tool")
+
+	request, err := t.ParseRequest(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	if err := workspace.CheckFileAccess(ctx, request.FilePath); err != nil {
+		return nil, err
+	}
+	fileInfo, err := os.Stat(request.FilePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("PDF file does not exist: %s", request.FilePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat PDF file: %w", err)
+	}
+	limits := &PDFTool{}
+	if err := limits.ValidateFileSize(fileInfo.Size()); err != nil {
+		return nil, fmt.Errorf("file size validation failed: %w", err)
+	}
+	data, err := os.ReadFile(request.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF file: %w", err)
+	}
+
+	// Unlike text extraction, forms are read with pdfcpu's default relaxed validation: strict
+	// validation rejects many real forms, including the standard fonts pdfcpu writes when filling
+	conf := model.NewDefaultConfiguration()
+
+	var response *PDFFormResponse
+	switch request.Action {
+	case "read":
+		response, err = t.readForm(data, request, conf)
+	case "fill":
+		if err := workspace.CheckFileAccess(ctx, request.OutputPath); err != nil {
+			return nil, err
+		}
+		response, err = t.fillForm(data, request, conf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"file_path": request.FilePath,
+		"action":    request.Action,
+		"fields":    len(response.Fields),
+	}).Debug("PDF form processed successfully")
+
+	return t.newToolResultJSON(response)
+}
+
+// ParseRequest parses and validates the tool arguments
+func (t *PDFFormTool) ParseRequest(args map[string]any) (*PDFFormRequest, error) {
+	action, _ := args["action"].(string)
+	if action != "read" && action != "fill" {
+		return nil, fmt.Errorf("action must be 'read' or 'fill'")
+	}
+
+	filePath, ok := args["file_path"].(string)
+	if !ok || filePath == "" {
+		return nil, fmt.Errorf("missing or invalid required parameter: file_path")
+	}
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path")
+	}
+	if !strings.HasSuffix(strings.ToLower(filePath), ".pdf") {
+		return nil, fmt.Errorf("file_path must be a PDF file (.pdf extension)")
+	}
+
+	request := &PDFFormRequest{
+		Action:   action,
+		FilePath: filePath,
+		Flatten:  true,
+	}
+	if action == "read" {
+		return request, nil
+	}
+
+	values, ok := args["values"].(map[string]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("values is required for fill: a map of field names to values")
+	}
+	request.Values = values
+
+	if outputPath, ok := args["output_path"].(string); ok && outputPath != "" {
+		if !filepath.IsAbs(outputPath) {
+			return nil, fmt.Errorf("output_path must be an absolute path")
+		}
+		request.OutputPath = filepath.Clean(outputPath)
+	} else {
+		request.OutputPath = strings.TrimSuffix(filePath, filepath.Ext(filePath)) + "_filled.pdf"
+	}
+	if request.OutputPath == filepath.Clean(filePath) {
+		return nil, fmt.Errorf("output_path must differ from file_path, the form is never overwritten")
+	}
+
+	if flatten, ok := args["flatten"].(bool); ok {
+		request.Flatten = flatten
+	}
+
+	return request, nil
+}
+
+// readForm lists the form's fields
+func (t *PDFFormTool) readForm(data []byte, request *PDFFormRequest, conf *model.Configuration) (*PDFFormResponse, error) {
+	f, err := exportForm(data, request.FilePath, conf)
+	if err != nil {
+		return nil, err
+	}
+	fields := formFields(f)
+
+	// Field values come from the document, so are checked like extracted text
+	if content, err := json.Marshal(fields); err == nil {
+		source := security.SourceContext{
+			Tool:        "pdf_form",
+			URL:         request.FilePath,
+			ContentType: "form_fields",
+		}
+		if result, err := security.AnalyseContent(string(content), source); err == nil && result.Action == security.ActionBlock {
+			return nil, fmt.Errorf("content blocked by security policy: %s", result.Message)
+		}
+	}
+
+	return &PDFFormResponse{
+		FilePath: request.FilePath,
+		Fields:   fields,
+	}, nil
+}
+
+// fillForm sets the requested fields, flattens the form if asked and writes the result
+func (t *PDFFormTool) fillForm(data []byte, request *PDFFormRequest, conf *model.Configuration) (*PDFFormResponse, error) {
+	f, err := exportForm(data, request.FilePath, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	filled, err := setFormValues(f, request.Values)
+	if err != nil {
+		return nil, err
+	}
+	formJSON, err := json.Marshal(form.FormGroup{Forms: []form.Form{*f}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode form values: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := api.FillForm(bytes.NewReader(data), bytes.NewReader(formJSON), &out, conf); err != nil {
+		return nil, fmt.Errorf("failed to fill form: %w", err)
+	}
+
+	if request.Flatten {
+		pdfCtx, err := api.ReadAndValidate(bytes.NewReader(out.Bytes()), conf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read filled form: %w", err)
+		}
+		if err := flattenForm(pdfCtx); err != nil {
+			return nil, fmt.Errorf("failed to flatten form: %w", err)
+		}
+		out.Reset()
+		if err := api.WriteContext(pdfCtx, &out); err != nil {
+			return nil, fmt.Errorf("failed to write flattened form: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(request.OutputPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(request.OutputPath, out.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write filled PDF: %w", err)
+	}
+
+	return &PDFFormResponse{
+		FilePath:   request.FilePath,
+		OutputPath: request.OutputPath,
+		Flattened:  request.Flatten,
+		Filled:     filled,
+		Fields:     formFields(f),
+	}, nil
+}
+
+// exportForm returns the form's fields and values as pdfcpu exports them
+func exportForm(data []byte, filePath string, conf *model.Configuration) (*form.Form, error) {
+	group, err := api.ExportForm(bytes.NewReader(data), filepath.Base(filePath), conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read form fields: %w", err)
+	}
+	if group == nil || len(group.Forms) == 0 {
+		return nil, fmt.Errorf("PDF has no form fields: %s", filePath)
+	}
+	return &group.Forms[0], nil
+}
+
+// formFields lists a form's fields in page order
+func formFields(f *form.Form) []FormField {
+	var fields []FormField
+	for _, tf := range f.TextFields {
+		fields = append(fields, FormField{Name: tf.Name, ID: tf.ID, Type: "text", Value: tf.Value, Pages: tf.Pages, Locked: tf.Locked, Multiline: tf.Multiline, MaxLength: tf.MaxLen})
+	}
+	for _, df := range f.DateFields {
+		fields = append(fields, FormField{Name: df.Name, ID: df.ID, Type: "date", Value: df.Value, Format: df.Format, Pages: df.Pages, Locked: df.Locked})
+	}
+	for _, cb := range f.CheckBoxes {
+		fields = append(fields, FormField{Name: cb.Name, ID: cb.ID, Type: "checkbox", Value: cb.Value, Pages: cb.Pages, Locked: cb.Locked})
+	}
+	for _, rb := range f.RadioButtonGroups {
+		fields = append(fields, FormField{Name: rb.Name, ID: rb.ID, Type: "radio", Value: rb.Value, Options: rb.Options, Pages: rb.Pages, Locked: rb.Locked})
+	}
+	for _, cb := range f.ComboBoxes {
+		fields = append(fields, FormField{Name: cb.Name, ID: cb.ID, Type: "combobox", Value: cb.Value, Options: cb.Options, Pages: cb.Pages, Locked: cb.Locked, Editable: cb.Editable})
+	}
+	for _, lb := range f.ListBoxes {
+		fields = append(fields, FormField{Name: lb.Name, ID: lb.ID, Type: "listbox", Value: lb.Values, Options: lb.Options, Pages: lb.Pages, Locked: lb.Locked, Multiple: lb.Multi})
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return firstPage(fields[i].Pages) < firstPage(fields[j].Pages)
+	})
+	return fields
+}
+
+// firstPage returns the first page a field appears on, for ordering fields
+func firstPage(pages []int) int {
+	if len(pages) == 0 {
+		return math.MaxInt
+	}
+	return slices.Min(pages)
+}
+
+// setFormValues sets each field named in values, by name or ID, checking the value suits the field,
+// and returns the names of the fields set
+func setFormValues(f *form.Form, values map[string]any) ([]string, error) {
+	names := make([]string, 0, len(values))
+	for key := range values {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, key := range names {
+		if err := setFormValue(f, key, values[key]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("cannot fill form: %s", strings.Join(problems, "; "))
+	}
+	return names, nil
+}
+
+// setFormValue sets the field named or identified by key
+func setFormValue(f *form.Form, key string, value any) error {
+	matches := func(id, name string) bool { return key == name || key == id }
+
+	for _, tf := range f.TextFields {
+		if matches(tf.ID, tf.Name) {
+			s, err := stringValue(key, value)
+			if err != nil {
+				return err
+			}
+			if tf.MaxLen > 0 && len([]rune(s)) > tf.MaxLen {
+				return fmt.Errorf("%s: value is longer than the field's %d characters", key, tf.MaxLen)
+			}
+			tf.Value = s
+			return nil
+		}
+	}
+	for _, df := range f.DateFields {
+		if matches(df.ID, df.Name) {
+			s, err := stringValue(key, value)
+			if err != nil {
+				return err
+			}
+			df.Value = s
+			return nil
+		}
+	}
+	for _, cb := range f.CheckBoxes {
+		if matches(cb.ID, cb.Name) {
+			b, err := boolValue(key, value)
+			if err != nil {
+				return err
+			}
+			cb.Value = b
+			return nil
+		}
+	}
+	for _, rb := range f.RadioButtonGroups {
+		if matches(rb.ID, rb.Name) {
+			s, err := optionValue(key, value, rb.Options)
+			if err != nil {
+				return err
+			}
+			rb.Value = s
+			return nil
+		}
+	}
+	for _, cb := range f.ComboBoxes {
+		if matches(cb.ID, cb.Name) {
+			options := cb.Options
+			if cb.Editable {
+				options = nil
+			}
+			s, err := optionValue(key, value, options)
+			if err != nil {
+				return err
+			}
+			cb.Value = s
+			return nil
+		}
+	}
+	for _, lb := range f.ListBoxes {
+		if matches(lb.ID, lb.Name) {
+			selected, err := optionValues(key, value, lb.Options)
+			if err != nil {
+				return err
+			}
+			if len(selected) > 1 && !lb.Multi {
+				return fmt.Errorf("%s: only one option can be selected", key)
+			}
+			lb.Values = selected
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: no such field", key)
+}
+
+// stringValue returns a text value, accepting numbers and booleans as their text
+func stringValue(key string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%s: expected text, got %T", key, value)
+	}
+}
+
+// boolValue returns a checkbox value, accepting common words for checked and unchecked
+func boolValue(key string, value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "on", "1", "x":
+			return true, nil
+		case "false", "no", "off", "0", "":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("%s: expected true or false, got %v", key, value)
+}
+
+// optionValue returns a value that must be one of options, when there are any
+func optionValue(key string, value any, options []string) (string, error) {
+	s, err := stringValue(key, value)
+	if err != nil {
+		return "", err
+	}
+	if s == "" || len(options) == 0 || slices.Contains(options, s) {
+		return s, nil
+	}
+	return "", fmt.Errorf("%s: %q is not one of: %s", key, s, strings.Join(options, ", "))
+}
+
+// optionValues returns the options selected by a single value or a list of values
+func optionValues(key string, value any, options []string) ([]string, error) {
+	list, ok := value.([]any)
+	if !ok {
+		list = []any{value}
+	}
+	var selected []string
+	for _, item := range list {
+		s, err := optionValue(key, item, options)
+		if err != nil {
+			return nil, err
+		}
+		if s != "" {
+			selected = append(selected, s)
+		}
+	}
+	return selected, nil
+}
+
+// newToolResultJSON creates a new tool result with JSON content
+func (t *PDFFormTool) newToolResultJSON(data any) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the PDF form tool
+func (t *PDFFormTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "List a form's fields",
+				Arguments: map[string]any{
+					"action":    "read",
+					"file_path": "/Users/username/forms/change-request.pdf",
+				},
+				ExpectedResult: "Returns each field's name, type, current value and, for radio buttons, combo boxes and list boxes, the allowed options",
+			},
+			{
+				Description: "Fill a form and flatten it for sign-off",
+				Arguments: map[string]any{
+					"action":      "fill",
+					"file_path":   "/Users/username/forms/change-request.pdf",
+					"values":      map[string]any{"requester": "Jo Bloggs", "risk": "Low", "approved": true},
+					"output_path": "/Users/username/forms/CR-1042.pdf",
+				},
+				ExpectedResult: "Writes CR-1042.pdf with the values drawn into the pages and no editable fields, and returns the fields filled",
+			},
+			{
+				Description: "Fill a form but keep it editable",
+				Arguments: map[string]any{
+					"action":    "fill",
+					"file_path": "/Users/username/forms/timesheet.pdf",
+					"values":    map[string]any{"week": "2026-W42"},
+					"flatten":   false,
+				},
+				ExpectedResult: "Writes timesheet_filled.pdf beside the form with the value set and the fields still editable",
+			},
+		},
+		CommonPatterns: []string{
+			"Read the form first, then fill it using the field names it returns",
+			"Keep the blank form as a template and write each filled copy to its own output_path",
+			"Use flatten: false for drafts that someone will finish by hand",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "PDF has no form fields",
+				Solution: "The PDF has no AcroForm fields. Forms that only look fillable, such as scanned forms, have no fields to fill. XFA-only forms are not supported.",
+			},
+			{
+				Problem:  "'no such field' or 'is not one of' errors",
+				Solution: "Field names must match exactly, including any dotted prefix. Read the form to get its names and, for choice fields, the allowed options. Nothing is written when any value is rejected.",
+			},
+			{
+				Problem:  "Flattened values are missing or look different",
+				Solution: "Flattening draws each field's appearance as the form defines it. Fields hidden in the form are not drawn. Fill with flatten: false and check the result in a viewer.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"action":      "'read' lists the fields. 'fill' sets them and writes a new PDF.",
+			"values":      "Keys are field names, or IDs, as returned by read. Text and date fields take strings, or numbers. Checkboxes take true or false, or 'yes' and 'no'. Radio buttons and combo boxes take one of their options, and list boxes one option or a list of them. Editable combo boxes take any text.",
+			"output_path": "Defaults to <name>_filled.pdf beside the form. The form itself is never overwritten.",
+			"flatten":     "Flattening draws each field's value into the page and removes the fields, as for a signed-off copy. Without it, the fields stay editable.",
+		},
+		WhenToUse:    "Use to find the fields of a PDF form and to produce filled copies of it, e.g. generating sign-off documents from a template.",
+		WhenNotToUse: "Don't use to extract a PDF's text (use pdf) or to edit documents that have no form fields.",
+	}
+}
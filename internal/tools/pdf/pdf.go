@@ -18,6 +18,7 @@ import (
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 )
 
@@ -85,12 +86,12 @@ func (t *PDFTool) Execute(ctx context.Context, logger *logrus.Logger, cache *syn
 	}).Debug("PDF processing parameters")
 
 	// Security check for input file access
-	if err := security.CheckFileAccess(request.FilePath); err != nil {
+	if err := workspace.CheckFileAccess(ctx, request.FilePath); err != nil {
 		return nil, err
 	}
 
 	// Security check for output directory access
-	if err := security.CheckFileAccess(request.OutputDir); err != nil {
+	if err := workspace.CheckFileAccess(ctx, request.OutputDir); err != nil {
 		return nil, err
 	}
 
@@ -113,7 +114,7 @@ func (t *PDFTool) Execute(ctx context.Context, logger *logrus.Logger, cache *syn
 	t.applyMemoryLimits(conf)
 
 	// Process the PDF
-	result, err := t.processPDF(logger, request, conf)
+	result, err := t.processPDF(ctx, logger, request, conf)
 	if err != nil {
 		return t.newToolResultJSON(map[string]any{
 			"error":     err.Error(),
@@ -180,7 +181,7 @@ func (t *PDFTool) ParseRequest(args map[string]any) (*PDFRequest, error) {
 }
 
 // processPDF handles the main PDF processing logic
-func (t *PDFTool) processPDF(logger *logrus.Logger, request *PDFRequest, conf *model.Configuration) (*PDFResponse, error) {
+func (t *PDFTool) processPDF(ctx context.Context, logger *logrus.Logger, request *PDFRequest, conf *model.Configuration) (*PDFResponse, error) {
 	// Ensure output directory exists
 	if err := os.MkdirAll(request.OutputDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
@@ -243,7 +244,7 @@ func (t *PDFTool) processPDF(logger *logrus.Logger, request *PDFRequest, conf *m
 	// Extract content from each page
 	logger.Debug("Extracting content from PDF pages")
 	for _, pageNum := range selectedPages {
-		pageContent, err := t.extractPageContent(request.FilePath, pageNum, conf, logger)
+		pageContent, err := t.extractPageContent(ctx, request.FilePath, pageNum, conf, logger)
 		if err != nil {
 			logger.WithError(err).WithField("page", pageNum).Error("Failed to extract content from page")
 			fmt.Fprintf(&markdownContent, "## Page %d\n\n*Content extraction failed: %v*\n\n", pageNum, err)
@@ -370,12 +371,37 @@ func (t *PDFTool) ParsePageSelection(pages string, maxPage int) ([]int, error) {
 	return result, nil
 }
 
+// ExtractText returns the text of each page of a PDF, in order, for tools that need a PDF's text
+// without the markdown file this tool writes
+func ExtractText(ctx context.Context, logger *logrus.Logger, filePath string) ([]string, error) {
+	t := &PDFTool{}
+	conf := model.NewDefaultConfiguration()
+	t.applyMemoryLimits(conf)
+	// Callers fall back to this when nothing better can read the document, so minor defects such as
+	// malformed dates are tolerated rather than failing the whole extraction
+	conf.ValidationMode = model.ValidationRelaxed
+
+	pageCount, err := api.PageCountFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page count: %w", err)
+	}
+	pages := make([]string, 0, pageCount)
+	for pageNum := 1; pageNum <= pageCount; pageNum++ {
+		content, err := t.extractPageContent(ctx, filePath, pageNum, conf, logger)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", pageNum, err)
+		}
+		pages = append(pages, t.processPageContent(content))
+	}
+	return pages, nil
+}
+
 // extractPageContent extracts content from a specific page
-func (t *PDFTool) extractPageContent(filePath string, pageNum int, conf *model.Configuration, logger *logrus.Logger) (string, error) {
+func (t *PDFTool) extractPageContent(ctx context.Context, filePath string, pageNum int, conf *model.Configuration, logger *logrus.Logger) (string, error) {
 	logger.WithField("page", pageNum).Debug("Starting text extraction for page")
 
-	// Create temporary directory for text extraction
-	tempDir, err := os.MkdirTemp("", "pdfcpu_text_*")
+	// Create temporary directory for text extraction in the session's scratch directory
+	tempDir, err := tools.ScratchSubdir(ctx, "pdfcpu_text_*")
 	if err != nil {
 		logger.WithError(err).Error("Failed to create temp directory for text extraction")
 		return "", fmt.Errorf("failed to create temp directory: %w", err)
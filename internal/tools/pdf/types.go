@@ -35,3 +35,55 @@ type PDFResponse struct {
 	// OutputDir is the directory where files were saved
 	OutputDir string `json:"output_dir"`
 }
+
+// PDFFormRequest represents a request to read or fill a PDF form
+type PDFFormRequest struct {
+	// Action is "read" to list the form's fields or "fill" to set them
+	Action string `json:"action"`
+
+	// FilePath is the absolute path to the PDF form
+	FilePath string `json:"file_path"`
+
+	// Values maps field names, or IDs, to the values to fill them with
+	Values map[string]any `json:"values,omitempty"`
+
+	// OutputPath is where the filled PDF is written
+	OutputPath string `json:"output_path,omitempty"`
+
+	// Flatten draws the values into the pages and removes the form fields
+	Flatten bool `json:"flatten"`
+}
+
+// FormField describes a field of a PDF form
+type FormField struct {
+	Name      string   `json:"name"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Value     any      `json:"value"`
+	Options   []string `json:"options,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	Pages     []int    `json:"pages,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+	Multiline bool     `json:"multiline,omitempty"`
+	Editable  bool     `json:"editable,omitempty"`
+	Multiple  bool     `json:"multiple,omitempty"`
+	Locked    bool     `json:"locked,omitempty"`
+}
+
+// PDFFormResponse represents the result of reading or filling a PDF form
+type PDFFormResponse struct {
+	// FilePath is the PDF form that was read
+	FilePath string `json:"file_path"`
+
+	// OutputPath is the filled PDF, when filling
+	OutputPath string `json:"output_path,omitempty"`
+
+	// Flattened reports whether the filled PDF's fields were flattened
+	Flattened bool `json:"flattened,omitempty"`
+
+	// Filled lists the fields that were set
+	Filled []string `json:"filled,omitempty"`
+
+	// Fields lists the form's fields with their values
+	Fields []FormField `json:"fields"`
+}
@@ -0,0 +1,147 @@
+package pipelines
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultTimeout is how long a whole pipeline may run, in seconds
+	defaultTimeout = 300
+	// maxTimeout is the longest timeout a pipeline may set, in seconds
+	maxTimeout = 60 * 60
+	// maxSteps is the most steps a pipeline may have
+	maxSteps = 20
+)
+
+var (
+	// namePattern matches valid pipeline tool names
+	namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+	// stepNamePattern matches step names, which templates use as field names
+	stepNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+)
+
+// Config represents the pipelines declared in pipelines.yaml
+type Config struct {
+	Pipelines map[string]Definition `yaml:"pipelines"`
+}
+
+// Definition declares a sequence of tool calls exposed as one tool
+type Definition struct {
+	Description string         `yaml:"description"`
+	Schema      map[string]any `yaml:"schema"`  // JSON Schema for the tool's arguments
+	Steps       []Step         `yaml:"steps"`   // tool calls, run in order
+	Output      string         `yaml:"output"`  // template for the result, default the last step's output
+	Timeout     int            `yaml:"timeout"` // timeout for all steps in seconds, default 300
+}
+
+// Step is one tool call of a pipeline. String values in its arguments are templates.
+type Step struct {
+	Name            string         `yaml:"name"` // name later steps refer to it by, default step1, step2...
+	Tool            string         `yaml:"tool"`
+	Arguments       map[string]any `yaml:"arguments"`
+	ContinueOnError bool           `yaml:"continue_on_error"` // run later steps when this one fails
+}
+
+// ConfigPath returns the path of pipelines.yaml
+func ConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "pipelines.yaml"), nil
+}
+
+// LoadConfig loads the pipeline configuration. A missing file declares no pipelines.
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &Config{Pipelines: make(map[string]Definition)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	if config.Pipelines == nil {
+		config.Pipelines = make(map[string]Definition)
+	}
+	return &config, nil
+}
+
+// validate validates a pipeline definition and sets defaults. Pipelines may not call pipelines, so
+// that one cannot call itself.
+func (d *Definition) validate(name string, pipelines map[string]Definition) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid name, use up to 64 letters, digits, underscores or hyphens")
+	}
+	if d.Description == "" {
+		return fmt.Errorf("description is required, as it tells agents when to use the tool")
+	}
+	if len(d.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	if len(d.Steps) > maxSteps {
+		return fmt.Errorf("a pipeline can have at most %d steps", maxSteps)
+	}
+	if d.Timeout <= 0 {
+		d.Timeout = defaultTimeout
+	}
+	if d.Timeout > maxTimeout {
+		return fmt.Errorf("timeout must be at most %d seconds", maxTimeout)
+	}
+
+	if d.Schema == nil {
+		d.Schema = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	if schemaType, _ := d.Schema["type"].(string); schemaType != "object" {
+		return fmt.Errorf("schema type must be object")
+	}
+
+	names := make(map[string]bool)
+	for i := range d.Steps {
+		step := &d.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step%d", i+1)
+		}
+		if !stepNamePattern.MatchString(step.Name) {
+			return fmt.Errorf("step %d: invalid name %q, use letters, digits and underscores, starting with a letter", i+1, step.Name)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("step %d: name %s is used by an earlier step", i+1, step.Name)
+		}
+		names[step.Name] = true
+		if step.Tool == "" {
+			return fmt.Errorf("step %s: tool is required", step.Name)
+		}
+		if _, ok := pipelines[step.Tool]; ok {
+			return fmt.Errorf("step %s: a pipeline cannot call the pipeline %s", step.Name, step.Tool)
+		}
+		if err := checkTemplates(step.Arguments); err != nil {
+			return fmt.Errorf("step %s: %w", step.Name, err)
+		}
+	}
+	if _, err := parseTemplate(d.Output); err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	return nil
+}
+
+// required returns the names of the arguments the schema requires
+func (d *Definition) required() []string {
+	values, _ := d.Schema["required"].([]any)
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,131 @@
+package pipelines
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// referencePattern matches a template that is only a reference, such as {{ .args.limit }}, which is
+// replaced with the value it refers to rather than its text so that numbers, lists and objects keep
+// their type. An argument referred to this way that was not given is left out.
+var referencePattern = regexp.MustCompile(`^\{\{-?\s*\.([a-zA-Z0-9_]+(?:\.[a-zA-Z0-9_]+)*)\s*-?\}\}$`)
+
+// errNoValue is returned for a reference to a value that does not exist
+var errNoValue = errors.New("no value")
+
+// templateFuncs are the functions templates can use besides Go's built-in ones
+var templateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"trim": strings.TrimSpace,
+}
+
+// parseTemplate parses a template, failing on references to missing values when it runs
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// checkTemplates parses every template in a step's arguments, so mistakes are found on loading
+func checkTemplates(value any) error {
+	switch value := value.(type) {
+	case string:
+		_, err := parseTemplate(value)
+		return err
+	case map[string]any:
+		for _, item := range value {
+			if err := checkTemplates(item); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if err := checkTemplates(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// render fills in the templates in a value of a step's arguments from data
+func render(value any, data map[string]any) (any, error) {
+	switch value := value.(type) {
+	case string:
+		if !strings.Contains(value, "{{") {
+			return value, nil
+		}
+		if match := referencePattern.FindStringSubmatch(strings.TrimSpace(value)); match != nil {
+			return lookup(data, match[1])
+		}
+		return renderString(value, data)
+	case map[string]any:
+		rendered := make(map[string]any, len(value))
+		for key, item := range value {
+			result, err := render(item, data)
+			if errors.Is(err, errNoValue) && isArgumentReference(item) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			rendered[key] = result
+		}
+		return rendered, nil
+	case []any:
+		rendered := make([]any, len(value))
+		for i, item := range value {
+			result, err := render(item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = result
+		}
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
+
+// renderString fills in a template as text
+func renderString(text string, data map[string]any) (string, error) {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// lookup returns the value at a dotted path of map keys in data
+func lookup(data map[string]any, path string) (any, error) {
+	var value any = data
+	for key := range strings.SplitSeq(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot look up %s in .%s: not an object", key, path)
+		}
+		if value, ok = object[key]; !ok {
+			return nil, fmt.Errorf("%w for .%s", errNoValue, path)
+		}
+	}
+	return value, nil
+}
+
+// isArgumentReference reports whether a value is only a reference to one of the pipeline's arguments
+func isArgumentReference(value any) bool {
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	match := referencePattern.FindStringSubmatch(strings.TrimSpace(text))
+	return match != nil && strings.HasPrefix(match[1], "args.")
+}
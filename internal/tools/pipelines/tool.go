@@ -0,0 +1,199 @@
+// Package pipelines exposes sequences of tool calls declared in ~/.mcp-devtools/pipelines.yaml as
+// tools, such as fetching a page, converting it, writing it to a file and linting the file. Each
+// step's arguments are templates that can use the pipeline's arguments and earlier steps' outputs,
+// so an agent makes one call for a common workflow instead of orchestrating each step.
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// PipelineTool implements the tools.Tool interface for a pipeline of tool calls
+type PipelineTool struct {
+	name        string
+	def         Definition
+	schema      json.RawMessage
+	annotations mcp.ToolAnnotation
+}
+
+// NewPipelineTool creates a tool for a pipeline definition, validating it and setting defaults.
+// pipelines are all the declared pipelines, which steps may not call. The tool's annotations are
+// worked out from its steps' tools, so those must already be registered.
+func NewPipelineTool(name string, def Definition, pipelines map[string]Definition) (*PipelineTool, error) {
+	if err := def.validate(name, pipelines); err != nil {
+		return nil, fmt.Errorf("pipeline '%s': %w", name, err)
+	}
+	schema, err := json.Marshal(def.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline '%s': invalid schema: %w", name, err)
+	}
+	return &PipelineTool{name: name, def: def, schema: schema, annotations: annotate(def.Steps)}, nil
+}
+
+// annotate works out a pipeline's annotations from those of its steps' tools. A step whose tool is
+// not enabled is assumed to change things, as nothing is known about it.
+func annotate(steps []Step) mcp.ToolAnnotation {
+	readOnly, destructive, openWorld := true, false, false
+	for _, step := range steps {
+		tool, ok := registry.GetTool(step.Tool)
+		if !ok {
+			readOnly, destructive, openWorld = false, true, true
+			continue
+		}
+		annotations := tool.Definition().Annotations
+		if annotations.ReadOnlyHint == nil || !*annotations.ReadOnlyHint {
+			// The destructive hint only means something for tools that change things
+			readOnly = false
+			destructive = destructive || annotations.DestructiveHint == nil || *annotations.DestructiveHint
+		}
+		openWorld = openWorld || annotations.OpenWorldHint == nil || *annotations.OpenWorldHint
+	}
+	return mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(readOnly),    // Only when every step is read-only
+		DestructiveHint: mcp.ToBoolPtr(destructive), // When any step may be destructive
+		IdempotentHint:  mcp.ToBoolPtr(false),       // Unknown, so assumed not
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),   // When any step talks to external systems
+	}
+}
+
+// Definition returns the tool's definition for MCP registration, advertising the pipeline's schema
+func (t *PipelineTool) Definition() mcp.Tool {
+	tool := mcp.NewToolWithRawSchema(t.name, t.def.Description, t.schema)
+	tool.Annotations = t.annotations
+	return tool
+}
+
+// Execute runs the steps in order and returns the pipeline's output. A failed step stops the
+// pipeline unless it is marked continue_on_error.
+func (t *PipelineTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	for _, name := range t.def.required() {
+		if _, ok := args[name]; !ok {
+			return nil, fmt.Errorf("missing required parameter: %s", name)
+		}
+	}
+
+	timeout := time.Duration(t.def.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	steps := make(map[string]any)
+	data := map[string]any{"args": args, "steps": steps}
+	output := ""
+	for i, step := range t.def.Steps {
+		start := time.Now()
+		result, err := t.runStep(ctx, logger, step, data, timeout)
+		logger.WithFields(logrus.Fields{
+			"pipeline": t.name,
+			"step":     step.Name,
+			"duration": time.Since(start).Round(time.Millisecond).String(),
+		}).Debug("Pipeline step finished")
+		if err != nil && !step.ContinueOnError {
+			return nil, fmt.Errorf("step %d of %d (%s, tool %s) failed: %w", i+1, len(t.def.Steps), step.Name, step.Tool, err)
+		}
+		steps[step.Name] = result
+		output, _ = result["output"].(string)
+	}
+
+	if t.def.Output != "" {
+		rendered, err := renderString(t.def.Output, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render output: %w", err)
+		}
+		output = rendered
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// runStep runs a step and returns its result for later templates: the step's output, as "json" too
+// when it is JSON, and its error when it failed
+func (t *PipelineTool) runStep(ctx context.Context, logger *logrus.Logger, step Step, data map[string]any, timeout time.Duration) (map[string]any, error) {
+	output, err := callStep(ctx, logger, step, data, timeout)
+	result := map[string]any{"output": output}
+	if err != nil {
+		result["error"] = err.Error()
+		return result, err
+	}
+	var parsed any
+	if json.Unmarshal([]byte(strings.TrimSpace(output)), &parsed) == nil {
+		result["json"] = parsed
+	}
+	return result, nil
+}
+
+// callStep renders a step's arguments and calls its tool
+func callStep(ctx context.Context, logger *logrus.Logger, step Step, data map[string]any, timeout time.Duration) (string, error) {
+	tool, err := toolcall.Enabled(step.Tool)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := render(step.Arguments, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render arguments: %w", err)
+	}
+	args, _ := rendered.(map[string]any)
+	return toolcall.Call(ctx, logger, step.Tool, tool, args, timeout)
+}
+
+// ProvideExtendedInfo provides extended help information for the pipeline, listing its steps
+func (t *PipelineTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	parameterDetails := make(map[string]string)
+	properties, _ := t.def.Schema["properties"].(map[string]any)
+	for name, property := range properties {
+		if details, ok := property.(map[string]any); ok {
+			description, _ := details["description"].(string)
+			parameterDetails[name] = description
+		}
+	}
+	steps := make([]string, 0, len(t.def.Steps))
+	for i, step := range t.def.Steps {
+		steps = append(steps, fmt.Sprintf("%d. %s calls %s", i+1, step.Name, step.Tool))
+	}
+	return &tools.ExtendedHelp{
+		CommonPatterns: []string{
+			"This tool runs a pipeline of tool calls configured by the user, in this order: " + strings.Join(steps, "; "),
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A step failed",
+				Solution: fmt.Sprintf("The error names the step and its tool. Check the arguments against the schema, or call that tool directly to see why it fails. The whole pipeline may run for up to %ds.", t.def.Timeout),
+			},
+		},
+		ParameterDetails: parameterDetails,
+		WhenToUse:        t.def.Description,
+	}
+}
+
+// RegisterConfigured loads pipelines.yaml and registers each pipeline as a tool. Invalid pipelines
+// are logged and skipped so that one mistake does not stop the others loading.
+func RegisterConfigured(logger *logrus.Logger) error {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load pipeline configuration: %w", err)
+	}
+
+	for name, def := range config.Pipelines {
+		tool, err := NewPipelineTool(name, def, config.Pipelines)
+		if err == nil {
+			err = registry.RegisterPipelineTool(tool)
+		}
+		if err != nil {
+			logger.WithError(err).WithField("pipeline", name).Warn("Skipping pipeline")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultTimeout is how long a plugin may run, in seconds
+	defaultTimeout = 30
+	// defaultMaxOutputBytes is how much of a plugin's output is returned
+	defaultMaxOutputBytes = 1024 * 1024
+)
+
+// namePattern matches valid plugin tool names
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Config represents the plugins declared in plugins.yaml
+type Config struct {
+	Plugins map[string]Definition `yaml:"plugins"`
+}
+
+// Definition declares an external executable exposed as a tool
+type Definition struct {
+	Description    string         `yaml:"description"`
+	Command        []string       `yaml:"command"`          // executable followed by its arguments
+	WorkingDir     string         `yaml:"working_dir"`      // directory to run in, default the server's
+	Env            []string       `yaml:"env"`              // names of server environment variables passed to the plugin
+	Timeout        int            `yaml:"timeout"`          // timeout in seconds, default 30
+	MaxOutputBytes int            `yaml:"max_output_bytes"` // output returned, default 1MB
+	MaxMemoryMB    int            `yaml:"max_memory_mb"`    // address space limit on Linux, default none
+	MaxCPUSeconds  int            `yaml:"max_cpu_seconds"`  // CPU time limit on Linux, default none
+	Schema         map[string]any `yaml:"schema"`           // JSON Schema for the tool's arguments
+	ReadOnly       bool           `yaml:"read_only"`        // the plugin does not change anything
+	Destructive    *bool          `yaml:"destructive"`      // default true unless read_only
+	OpenWorld      *bool          `yaml:"open_world"`       // default true
+}
+
+// ConfigPath returns the path of plugins.yaml
+func ConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "plugins.yaml"), nil
+}
+
+// LoadConfig loads and validates the plugin configuration. A missing file declares no plugins.
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &Config{Plugins: make(map[string]Definition)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	if config.Plugins == nil {
+		config.Plugins = make(map[string]Definition)
+	}
+	return &config, nil
+}
+
+// validate validates a plugin definition and sets defaults
+func (d *Definition) validate(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid name, use up to 64 letters, digits, underscores or hyphens")
+	}
+	if len(d.Command) == 0 || strings.TrimSpace(d.Command[0]) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if d.Description == "" {
+		return fmt.Errorf("description is required, as it tells agents when to use the tool")
+	}
+
+	d.Command[0] = expandHome(d.Command[0])
+	d.WorkingDir = expandHome(d.WorkingDir)
+	if d.Timeout <= 0 {
+		d.Timeout = defaultTimeout
+	}
+	if d.MaxOutputBytes <= 0 {
+		d.MaxOutputBytes = defaultMaxOutputBytes
+	}
+
+	if d.Schema == nil {
+		d.Schema = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	if schemaType, _ := d.Schema["type"].(string); schemaType != "object" {
+		return fmt.Errorf("schema type must be object")
+	}
+	return nil
+}
+
+// required returns the names of the arguments the schema requires
+func (d *Definition) required() []string {
+	values, _ := d.Schema["required"].([]any)
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
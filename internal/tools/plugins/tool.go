@@ -0,0 +1,218 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ProtocolVersion is the version of the request written to a plugin's stdin
+	ProtocolVersion = 1
+
+	// maxStderrBytes bounds the stderr kept for error messages and logs
+	maxStderrBytes = 64 * 1024
+	// stderrInError is how much of the end of stderr is included in an error
+	stderrInError = 2000
+)
+
+// passedEnv are the server environment variables every plugin receives
+var passedEnv = []string{"PATH", "HOME", "USER", "TMPDIR", "LANG", "LC_ALL"}
+
+// Request is the JSON written to a plugin's stdin
+type Request struct {
+	Version   int            `json:"version"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+	SessionID string         `json:"session_id,omitempty"`
+}
+
+// PluginTool implements the tools.Tool interface for an external executable
+type PluginTool struct {
+	name   string
+	def    Definition
+	schema json.RawMessage
+}
+
+// NewPluginTool creates a tool for a plugin definition, validating it and setting defaults
+func NewPluginTool(name string, def Definition) (*PluginTool, error) {
+	if err := def.validate(name); err != nil {
+		return nil, fmt.Errorf("plugin '%s': %w", name, err)
+	}
+	schema, err := json.Marshal(def.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s': invalid schema: %w", name, err)
+	}
+	return &PluginTool{name: name, def: def, schema: schema}, nil
+}
+
+// Definition returns the tool's definition for MCP registration, advertising the plugin's own schema
+func (t *PluginTool) Definition() mcp.Tool {
+	tool := mcp.NewToolWithRawSchema(t.name, t.def.Description, t.schema)
+
+	destructive := !t.def.ReadOnly
+	if t.def.Destructive != nil {
+		destructive = *t.def.Destructive
+	}
+	openWorld := true
+	if t.def.OpenWorld != nil {
+		openWorld = *t.def.OpenWorld
+	}
+	tool.Annotations = mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(t.def.ReadOnly), // As declared in plugins.yaml
+		DestructiveHint: mcp.ToBoolPtr(destructive),    // Assumed unless declared read-only
+		IdempotentHint:  mcp.ToBoolPtr(false),          // Unknown, so assumed not
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),      // Assumed unless declared otherwise
+	}
+	return tool
+}
+
+// Execute runs the plugin with the arguments on stdin and returns its stdout
+func (t *PluginTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	for _, name := range t.def.required() {
+		if _, ok := args[name]; !ok {
+			return nil, fmt.Errorf("missing required parameter: %s", name)
+		}
+	}
+
+	request := Request{Version: ProtocolVersion, Tool: t.name, Arguments: args}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		request.SessionID = session.SessionID()
+	}
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(t.def.Timeout)*time.Second)
+	defer cancel()
+
+	result, runErr := supervisor.Run(ctx, supervisor.Spec{
+		Name:      "plugin " + t.name,
+		Path:      t.def.Command[0],
+		Args:      t.def.Command[1:],
+		Dir:       t.def.WorkingDir,
+		Env:       t.environment(),
+		Stdin:     bytes.NewReader(input),
+		MaxStdout: t.def.MaxOutputBytes,
+		MaxStderr: maxStderrBytes,
+		Limits: supervisor.Limits{
+			MemoryBytes: int64(t.def.MaxMemoryMB) << 20,
+			CPUSeconds:  int64(t.def.MaxCPUSeconds),
+		},
+	})
+	if result == nil {
+		return nil, fmt.Errorf("failed to run plugin %s: %w", t.name, runErr)
+	}
+	logger.WithFields(logrus.Fields{
+		"plugin":   t.name,
+		"duration": result.Duration.Round(time.Millisecond).String(),
+		"bytes":    result.StdoutBytes,
+	}).Debug("Plugin finished")
+	if result.StderrBytes > 0 {
+		logger.WithField("plugin", t.name).Debugf("Plugin stderr: %s", result.Stderr)
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("plugin %s timed out after %ds", t.name, t.def.Timeout)
+	}
+	if runErr != nil {
+		if exitErr, ok := errors.AsType[*exec.ExitError](runErr); ok {
+			return nil, fmt.Errorf("plugin %s exited with status %d%s", t.name, exitErr.ExitCode(), stderrDetail(result.Stderr))
+		}
+		return nil, fmt.Errorf("failed to run plugin %s: %w", t.name, runErr)
+	}
+
+	output := strings.ToValidUTF8(result.Stdout, "�")
+	if result.StdoutTruncated() {
+		output += fmt.Sprintf("\n\n[Plugin output truncated: showing %d of %d bytes]", len(result.Stdout), result.StdoutBytes)
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// environment returns the plugin's environment: a few standard variables and those it declared
+func (t *PluginTool) environment() []string {
+	env := []string{"MCP_DEVTOOLS_PLUGIN=" + t.name}
+	for _, name := range slices.Concat(passedEnv, t.def.Env) {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// stderrDetail formats the end of a plugin's stderr for an error message
+func stderrDetail(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	if len(stderr) > stderrInError {
+		stderr = "..." + strings.ToValidUTF8(stderr[len(stderr)-stderrInError:], "")
+	}
+	return ": " + stderr
+}
+
+// ProvideExtendedInfo provides extended help information for the plugin
+func (t *PluginTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	parameterDetails := make(map[string]string)
+	properties, _ := t.def.Schema["properties"].(map[string]any)
+	for name, property := range properties {
+		if details, ok := property.(map[string]any); ok {
+			description, _ := details["description"].(string)
+			parameterDetails[name] = description
+		}
+	}
+	return &tools.ExtendedHelp{
+		CommonPatterns: []string{
+			"This tool runs an external plugin configured by the user, so its behaviour is defined by the plugin",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The plugin timed out or exited with an error",
+				Solution: fmt.Sprintf("Check the arguments against the schema. The plugin may run for up to %ds, and its error output is included in the error.", t.def.Timeout),
+			},
+		},
+		ParameterDetails: parameterDetails,
+		WhenToUse:        t.def.Description,
+	}
+}
+
+// RegisterConfigured loads plugins.yaml and registers each plugin as a tool. Invalid plugins are
+// logged and skipped so that one mistake does not stop the others loading.
+func RegisterConfigured(logger *logrus.Logger) error {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin configuration: %w", err)
+	}
+
+	for name, def := range config.Plugins {
+		tool, err := NewPluginTool(name, def)
+		if err == nil {
+			err = registry.RegisterPluginTool(tool)
+		}
+		if err != nil {
+			logger.WithError(err).WithField("plugin", name).Warn("Skipping plugin")
+		}
+	}
+	return nil
+}
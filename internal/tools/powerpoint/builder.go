@@ -0,0 +1,489 @@
+package powerpoint
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder for image dimensions
+	_ "image/jpeg" // Register JPEG decoder for image dimensions
+	_ "image/png"  // Register PNG decoder for image dimensions
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+)
+
+const (
+	maxSlides      = 200
+	maxBulletLevel = 3
+	maxChartPoints = 500
+	maxImageSize   = 25 * 1024 * 1024 // 25MB per embedded image
+)
+
+// Content area geometry (EMUs) shared by all content slides
+const (
+	marginX       = 838200
+	titleY        = 365125
+	titleHeight   = 1325563
+	contentY      = 1825625
+	contentWidth  = slideWidth - 2*marginX
+	contentHeight = 4351338
+	columnGap     = 304800
+)
+
+// media tracks an embedded image so it is only stored once
+type media struct {
+	name        string
+	extension   string
+	data        []byte
+	width       int
+	height      int
+	contentType string
+}
+
+// deckBuilder accumulates the parts of a presentation package
+type deckBuilder struct {
+	ctx      context.Context // The tool call's context, for checking access to images and Excel files
+	request  *PresentationRequest
+	baseDir  string
+	files    map[string][]byte
+	order    []string
+	media    map[string]*media
+	charts   int
+	notes    int
+	warnings []string
+}
+
+// buildPresentation renders the slides into a .pptx byte slice
+func buildPresentation(ctx context.Context, request *PresentationRequest, slides []Slide) ([]byte, []string, error) {
+	b := &deckBuilder{
+		ctx:     ctx,
+		request: request,
+		baseDir: filepath.Dir(request.OutputPath),
+		files:   make(map[string][]byte),
+		media:   make(map[string]*media),
+	}
+
+	slideRels := make([]string, 0, len(slides))
+	for i := range slides {
+		if err := b.addSlide(i+1, &slides[i]); err != nil {
+			return nil, b.warnings, fmt.Errorf("slide %d: %w", i+1, err)
+		}
+		slideRels = append(slideRels, fmt.Sprintf("slides/slide%d.xml", i+1))
+	}
+
+	b.add("_rels/.rels", rootRelsXML)
+	b.add("docProps/core.xml", b.corePropsXML())
+	b.add("docProps/app.xml", b.appPropsXML(len(slides)))
+	b.add("ppt/presentation.xml", b.presentationXML(len(slides)))
+	b.add("ppt/_rels/presentation.xml.rels", b.presentationRelsXML(slideRels))
+	b.add("ppt/slideMasters/slideMaster1.xml", slideMasterXML)
+	b.add("ppt/slideMasters/_rels/slideMaster1.xml.rels", slideMasterRelsXML)
+	b.add("ppt/slideLayouts/slideLayout1.xml", slideLayoutTitleXML)
+	b.add("ppt/slideLayouts/_rels/slideLayout1.xml.rels", slideLayoutRelsXML)
+	b.add("ppt/slideLayouts/slideLayout2.xml", slideLayoutContentXML)
+	b.add("ppt/slideLayouts/_rels/slideLayout2.xml.rels", slideLayoutRelsXML)
+	b.add("ppt/theme/theme1.xml", themeXML)
+	b.add("ppt/presProps.xml", presPropsXML)
+	b.add("ppt/viewProps.xml", viewPropsXML)
+	b.add("ppt/tableStyles.xml", tableStylesXML)
+	if b.notes > 0 {
+		b.add("ppt/notesMasters/notesMaster1.xml", notesMasterXML)
+		b.add("ppt/notesMasters/_rels/notesMaster1.xml.rels", notesMasterRelsXML)
+		b.add("ppt/theme/theme2.xml", themeXML)
+	}
+	// Add media in a stable order so identical input produces identical archives
+	mediaNames := make([]string, 0, len(b.media))
+	for _, m := range b.media {
+		b.files["ppt/media/"+m.name] = m.data
+		mediaNames = append(mediaNames, "ppt/media/"+m.name)
+	}
+	slices.Sort(mediaNames)
+	b.order = append(b.order, mediaNames...)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	// [Content_Types].xml must be the first entry in the archive
+	if err := writeZipEntry(zw, "[Content_Types].xml", []byte(b.contentTypesXML(len(slides)))); err != nil {
+		return nil, b.warnings, err
+	}
+	for _, name := range b.order {
+		if err := writeZipEntry(zw, name, b.files[name]); err != nil {
+			return nil, b.warnings, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, b.warnings, fmt.Errorf("failed to finalise presentation archive: %w", err)
+	}
+
+	return buf.Bytes(), b.warnings, nil
+}
+
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to presentation: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to presentation: %w", name, err)
+	}
+	return nil
+}
+
+func (b *deckBuilder) add(name, content string) {
+	b.files[name] = []byte(content)
+	b.order = append(b.order, name)
+}
+
+// addSlide renders a single slide, its relationships, notes and any chart part
+func (b *deckBuilder) addSlide(index int, slide *Slide) error {
+	layout := inferLayout(slide)
+	rels := []string{fmt.Sprintf(`<Relationship Id="rId1" Type="%sslideLayout" Target="../slideLayouts/slideLayout%d.xml"/>`, relBase, layoutIndex(layout))}
+
+	var shapes strings.Builder
+	shapeID := 2
+
+	if layout == "title" {
+		shapes.WriteString(textShape(shapeID, "Title", `<p:ph type="ctrTitle"/>`, marginX, 2130425, contentWidth, 1470025, `anchor="b"`, titleParagraphs(slide.Title, 4400, "ctr")))
+		shapeID++
+		if slide.Subtitle != "" {
+			shapes.WriteString(textShape(shapeID, "Subtitle", `<p:ph type="subTitle" idx="1"/>`, marginX*2, 3886200, contentWidth-2*marginX, 1752600, "", plainParagraphs(slide.Subtitle, 2400, "ctr")))
+			shapeID++
+		}
+	} else {
+		if slide.Title != "" {
+			shapes.WriteString(textShape(shapeID, "Title", `<p:ph type="title"/>`, marginX, titleY, contentWidth, titleHeight, `anchor="ctr"`, titleParagraphs(slide.Title, 0, "")))
+			shapeID++
+		}
+
+		hasVisual := slide.Image != "" || slide.Chart != nil
+		textWidth := int64(contentWidth)
+		visualX, visualWidth := int64(marginX), int64(contentWidth)
+		if hasVisual && len(slide.Bullets) > 0 {
+			textWidth = (contentWidth - columnGap) / 2
+			visualX = marginX + textWidth + columnGap
+			visualWidth = textWidth
+		}
+
+		if len(slide.Bullets) > 0 {
+			shapes.WriteString(textShape(shapeID, "Content", `<p:ph idx="1"/>`, marginX, contentY, textWidth, contentHeight, `<a:normAutofit/>`, bulletParagraphs(slide.Bullets)))
+			shapeID++
+		}
+
+		if slide.Image != "" {
+			rID := fmt.Sprintf("rId%d", len(rels)+1)
+			m, err := b.loadImage(slide.Image)
+			if err != nil {
+				b.warnings = append(b.warnings, fmt.Sprintf("slide %d: image skipped: %v", index, err))
+			} else {
+				rels = append(rels, fmt.Sprintf(`<Relationship Id="%s" Type="%simage" Target="../media/%s"/>`, rID, relBase, m.name))
+				x, y, w, h := fitImage(m.width, m.height, visualX, contentY, visualWidth, contentHeight)
+				shapes.WriteString(pictureShape(shapeID, rID, slide.Image, x, y, w, h))
+				shapeID++
+			}
+		}
+
+		if slide.Chart != nil {
+			if err := resolveChartData(b.ctx, slide.Chart); err != nil {
+				return err
+			}
+			b.charts++
+			chartName := fmt.Sprintf("chart%d.xml", b.charts)
+			b.add("ppt/charts/"+chartName, chartXML(slide.Chart))
+			rID := fmt.Sprintf("rId%d", len(rels)+1)
+			rels = append(rels, fmt.Sprintf(`<Relationship Id="%s" Type="%schart" Target="../charts/%s"/>`, rID, relBase, chartName))
+			shapes.WriteString(chartFrame(shapeID, rID, visualX, contentY, visualWidth, contentHeight))
+			shapeID++
+		}
+	}
+
+	if slide.Notes != "" {
+		b.notes++
+		rID := fmt.Sprintf("rId%d", len(rels)+1)
+		rels = append(rels, fmt.Sprintf(`<Relationship Id="%s" Type="%snotesSlide" Target="../notesSlides/notesSlide%d.xml"/>`, rID, relBase, index))
+		b.add(fmt.Sprintf("ppt/notesSlides/notesSlide%d.xml", index), notesSlideXML(slide.Notes))
+		b.add(fmt.Sprintf("ppt/notesSlides/_rels/notesSlide%d.xml.rels", index), xmlHeader+`<Relationships xmlns="`+nsRelationships+`">`+
+			`<Relationship Id="rId1" Type="`+relBase+`notesMaster" Target="../notesMasters/notesMaster1.xml"/>`+
+			fmt.Sprintf(`<Relationship Id="rId2" Type="%sslide" Target="../slides/slide%d.xml"/>`, relBase, index)+
+			`</Relationships>`)
+	}
+
+	b.add(fmt.Sprintf("ppt/slides/slide%d.xml", index), xmlHeader+`<p:sld `+nsPresentation+`><p:cSld><p:spTree>`+emptyGroupXML+shapes.String()+`</p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:sld>`)
+	b.add(fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", index), xmlHeader+`<Relationships xmlns="`+nsRelationships+`">`+strings.Join(rels, "")+`</Relationships>`)
+	return nil
+}
+
+// inferLayout picks a layout when none was specified
+func inferLayout(slide *Slide) string {
+	layout := strings.ToLower(strings.TrimSpace(slide.Layout))
+	if layout != "" {
+		return layout
+	}
+	if slide.Subtitle != "" && len(slide.Bullets) == 0 && slide.Image == "" && slide.Chart == nil {
+		return "title"
+	}
+	return "bullets"
+}
+
+// layoutIndex maps a layout name to its slide layout part
+func layoutIndex(layout string) int {
+	if layout == "title" {
+		return 1
+	}
+	return 2
+}
+
+// loadImage reads, validates and de-duplicates an image for embedding
+func (b *deckBuilder) loadImage(path string) (*media, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(b.baseDir, path)
+	}
+	path = filepath.Clean(path)
+	if m, ok := b.media[path]; ok {
+		return m, nil
+	}
+	if err := workspace.CheckFileAccess(b.ctx, path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if info.Size() > maxImageSize {
+		return nil, fmt.Errorf("%s is %d bytes, maximum is %d", path, info.Size(), maxImageSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a supported image (png, jpeg or gif): %w", path, err)
+	}
+
+	extension := format
+	contentType := "image/" + format
+	if format == "jpeg" {
+		extension = "jpeg"
+	}
+
+	m := &media{
+		name:        fmt.Sprintf("image%d.%s", len(b.media)+1, extension),
+		extension:   extension,
+		data:        data,
+		width:       cfg.Width,
+		height:      cfg.Height,
+		contentType: contentType,
+	}
+	b.media[path] = m
+	return m, nil
+}
+
+// fitImage scales an image to fit a bounding box whilst preserving aspect ratio, centred
+func fitImage(width, height int, boxX, boxY, boxW, boxH int64) (x, y, w, h int64) {
+	if width <= 0 || height <= 0 {
+		return boxX, boxY, boxW, boxH
+	}
+	scale := min(float64(boxW)/float64(width), float64(boxH)/float64(height))
+	w = int64(float64(width) * scale)
+	h = int64(float64(height) * scale)
+	return boxX + (boxW-w)/2, boxY + (boxH-h)/2, w, h
+}
+
+// textShape renders a text placeholder shape at an explicit position
+func textShape(id int, name, placeholder string, x, y, w, h int64, bodyPr, paragraphs string) string {
+	bodyAttrs, bodyChildren := "", ""
+	if strings.HasPrefix(bodyPr, "<") {
+		bodyChildren = bodyPr
+	} else {
+		bodyAttrs = bodyPr
+	}
+	if bodyAttrs != "" {
+		bodyAttrs = " " + bodyAttrs
+	}
+	return fmt.Sprintf(`<p:sp><p:nvSpPr><p:cNvPr id="%d" name="%s %d"/><p:cNvSpPr><a:spLocks noGrp="1"/></p:cNvSpPr><p:nvPr>%s</p:nvPr></p:nvSpPr>`+
+		`<p:spPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm></p:spPr>`+
+		`<p:txBody><a:bodyPr%s>%s</a:bodyPr><a:lstStyle/>%s</p:txBody></p:sp>`,
+		id, name, id-1, placeholder, x, y, w, h, bodyAttrs, bodyChildren, paragraphs)
+}
+
+// pictureShape renders an embedded picture
+func pictureShape(id int, rID, description string, x, y, w, h int64) string {
+	return fmt.Sprintf(`<p:pic><p:nvPicPr><p:cNvPr id="%d" name="Picture %d" descr="%s"/><p:cNvPicPr><a:picLocks noChangeAspect="1"/></p:cNvPicPr><p:nvPr/></p:nvPicPr>`+
+		`<p:blipFill><a:blip r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></p:blipFill>`+
+		`<p:spPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr></p:pic>`,
+		id, id-1, escapeXML(filepath.Base(description)), rID, x, y, w, h)
+}
+
+// chartFrame renders the graphic frame that hosts a chart part
+func chartFrame(id int, rID string, x, y, w, h int64) string {
+	return fmt.Sprintf(`<p:graphicFrame><p:nvGraphicFramePr><p:cNvPr id="%d" name="Chart %d"/><p:cNvGraphicFramePr/><p:nvPr/></p:nvGraphicFramePr>`+
+		`<p:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></p:xfrm>`+
+		`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/chart"><c:chart xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" r:id="%s"/></a:graphicData></a:graphic></p:graphicFrame>`,
+		id, id-1, x, y, w, h, rID)
+}
+
+// titleParagraphs renders a title, optionally with an explicit size and alignment
+func titleParagraphs(text string, size int, align string) string {
+	pPr := ""
+	if align != "" {
+		pPr = fmt.Sprintf(`<a:pPr algn="%s"/>`, align)
+	}
+	rPr := `<a:rPr lang="en-GB" dirty="0"/>`
+	if size > 0 {
+		rPr = fmt.Sprintf(`<a:rPr lang="en-GB" sz="%d" dirty="0"/>`, size)
+	}
+	return fmt.Sprintf(`<a:p>%s<a:r>%s<a:t>%s</a:t></a:r></a:p>`, pPr, rPr, escapeXML(text))
+}
+
+// plainParagraphs renders multi-line text without bullets
+func plainParagraphs(text string, size int, align string) string {
+	var b strings.Builder
+	for line := range strings.SplitSeq(text, "\n") {
+		fmt.Fprintf(&b, `<a:p><a:pPr marL="0" indent="0" algn="%s"><a:buNone/></a:pPr><a:r><a:rPr lang="en-GB" sz="%d" dirty="0"/><a:t>%s</a:t></a:r></a:p>`, align, size, escapeXML(line))
+	}
+	return b.String()
+}
+
+// bulletParagraphs renders bullets with indentation levels
+func bulletParagraphs(bullets []Bullet) string {
+	var b strings.Builder
+	for _, bullet := range bullets {
+		level := max(0, min(bullet.Level, maxBulletLevel))
+		pPr := ""
+		if level > 0 {
+			pPr = fmt.Sprintf(`<a:pPr lvl="%d"/>`, level)
+		}
+		fmt.Fprintf(&b, `<a:p>%s<a:r><a:rPr lang="en-GB" dirty="0"/><a:t>%s</a:t></a:r></a:p>`, pPr, escapeXML(bullet.Text))
+	}
+	return b.String()
+}
+
+// notesSlideXML renders the speaker notes page for a slide
+func notesSlideXML(notes string) string {
+	var paragraphs strings.Builder
+	for line := range strings.SplitSeq(notes, "\n") {
+		fmt.Fprintf(&paragraphs, `<a:p><a:r><a:rPr lang="en-GB" dirty="0"/><a:t>%s</a:t></a:r></a:p>`, escapeXML(line))
+	}
+	return xmlHeader + `<p:notes ` + nsPresentation + `><p:cSld><p:spTree>` + emptyGroupXML +
+		`<p:sp><p:nvSpPr><p:cNvPr id="2" name="Slide Image Placeholder 1"/><p:cNvSpPr><a:spLocks noGrp="1" noRot="1" noChangeAspect="1"/></p:cNvSpPr><p:nvPr><p:ph type="sldImg"/></p:nvPr></p:nvSpPr>` +
+		`<p:spPr><a:xfrm><a:off x="381000" y="685800"/><a:ext cx="6096000" cy="3429000"/></a:xfrm></p:spPr></p:sp>` +
+		`<p:sp><p:nvSpPr><p:cNvPr id="3" name="Notes Placeholder 2"/><p:cNvSpPr><a:spLocks noGrp="1"/></p:cNvSpPr><p:nvPr><p:ph type="body" idx="1"/></p:nvPr></p:nvSpPr>` +
+		`<p:spPr><a:xfrm><a:off x="685800" y="4343400"/><a:ext cx="5486400" cy="4114800"/></a:xfrm></p:spPr>` +
+		`<p:txBody><a:bodyPr/><a:lstStyle/>` + paragraphs.String() + `</p:txBody></p:sp>` +
+		`</p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:notes>`
+}
+
+func (b *deckBuilder) presentationXML(slideCount int) string {
+	var s strings.Builder
+	s.WriteString(xmlHeader + `<p:presentation ` + nsPresentation + ` saveSubsetFonts="1">`)
+	s.WriteString(`<p:sldMasterIdLst><p:sldMasterId id="2147483648" r:id="rId1"/></p:sldMasterIdLst>`)
+	if b.notes > 0 {
+		fmt.Fprintf(&s, `<p:notesMasterIdLst><p:notesMasterId r:id="rId%d"/></p:notesMasterIdLst>`, slideCount+2)
+	}
+	s.WriteString(`<p:sldIdLst>`)
+	for i := range slideCount {
+		fmt.Fprintf(&s, `<p:sldId id="%d" r:id="rId%d"/>`, 256+i, i+2)
+	}
+	s.WriteString(`</p:sldIdLst>`)
+	fmt.Fprintf(&s, `<p:sldSz cx="%d" cy="%d"/><p:notesSz cx="6858000" cy="9144000"/>`, slideWidth, slideHeight)
+	s.WriteString(`</p:presentation>`)
+	return s.String()
+}
+
+func (b *deckBuilder) presentationRelsXML(slides []string) string {
+	var s strings.Builder
+	s.WriteString(xmlHeader + `<Relationships xmlns="` + nsRelationships + `">`)
+	fmt.Fprintf(&s, `<Relationship Id="rId1" Type="%sslideMaster" Target="slideMasters/slideMaster1.xml"/>`, relBase)
+	for i, target := range slides {
+		fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%sslide" Target="%s"/>`, i+2, relBase, target)
+	}
+	next := len(slides) + 2
+	if b.notes > 0 {
+		fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%snotesMaster" Target="notesMasters/notesMaster1.xml"/>`, next, relBase)
+	}
+	fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%spresProps" Target="presProps.xml"/>`, next+1, relBase)
+	fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%sviewProps" Target="viewProps.xml"/>`, next+2, relBase)
+	fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%stheme" Target="theme/theme1.xml"/>`, next+3, relBase)
+	fmt.Fprintf(&s, `<Relationship Id="rId%d" Type="%stableStyles" Target="tableStyles.xml"/>`, next+4, relBase)
+	s.WriteString(`</Relationships>`)
+	return s.String()
+}
+
+func (b *deckBuilder) contentTypesXML(slideCount int) string {
+	const ctBase = "application/vnd.openxmlformats-officedocument."
+	var s strings.Builder
+	s.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	s.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	s.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	seen := map[string]bool{}
+	for _, m := range b.media {
+		if !seen[m.extension] {
+			seen[m.extension] = true
+			fmt.Fprintf(&s, `<Default Extension="%s" ContentType="%s"/>`, m.extension, m.contentType)
+		}
+	}
+	override := func(part, contentType string) {
+		fmt.Fprintf(&s, `<Override PartName="/%s" ContentType="%s"/>`, part, contentType)
+	}
+	override("ppt/presentation.xml", ctBase+"presentationml.presentation.main+xml")
+	override("ppt/slideMasters/slideMaster1.xml", ctBase+"presentationml.slideMaster+xml")
+	override("ppt/slideLayouts/slideLayout1.xml", ctBase+"presentationml.slideLayout+xml")
+	override("ppt/slideLayouts/slideLayout2.xml", ctBase+"presentationml.slideLayout+xml")
+	override("ppt/theme/theme1.xml", ctBase+"theme+xml")
+	override("ppt/presProps.xml", ctBase+"presentationml.presProps+xml")
+	override("ppt/viewProps.xml", ctBase+"presentationml.viewProps+xml")
+	override("ppt/tableStyles.xml", ctBase+"presentationml.tableStyles+xml")
+	override("docProps/core.xml", "application/vnd.openxmlformats-package.core-properties+xml")
+	override("docProps/app.xml", ctBase+"extended-properties+xml")
+	for i := 1; i <= slideCount; i++ {
+		override(fmt.Sprintf("ppt/slides/slide%d.xml", i), ctBase+"presentationml.slide+xml")
+	}
+	if b.notes > 0 {
+		override("ppt/notesMasters/notesMaster1.xml", ctBase+"presentationml.notesMaster+xml")
+		override("ppt/theme/theme2.xml", ctBase+"theme+xml")
+		for _, name := range b.order {
+			if strings.HasPrefix(name, "ppt/notesSlides/notesSlide") && strings.HasSuffix(name, ".xml") {
+				override(name, ctBase+"presentationml.notesSlide+xml")
+			}
+		}
+	}
+	for i := 1; i <= b.charts; i++ {
+		override(fmt.Sprintf("ppt/charts/chart%d.xml", i), "application/vnd.openxmlformats-officedocument.drawingml.chart+xml")
+	}
+	s.WriteString(`</Types>`)
+	return s.String()
+}
+
+func (b *deckBuilder) corePropsXML() string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	author := b.request.Author
+	if author == "" {
+		author = "mcp-devtools"
+	}
+	return xmlHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:dcmitype="http://purl.org/dc/dcmitype/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<dc:title>` + escapeXML(b.request.Title) + `</dc:title><dc:creator>` + escapeXML(author) + `</dc:creator>` +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + now + `</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">` + now + `</dcterms:modified>` +
+		`</cp:coreProperties>`
+}
+
+func (b *deckBuilder) appPropsXML(slideCount int) string {
+	return xmlHeader + `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">` +
+		fmt.Sprintf(`<Application>mcp-devtools</Application><PresentationFormat>Widescreen</PresentationFormat><Slides>%d</Slides><Notes>%d</Notes>`, slideCount, b.notes) +
+		`</Properties>`
+}
+
+// escapeXML escapes text for inclusion in XML element content and attributes
+func escapeXML(text string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
@@ -0,0 +1,236 @@
+package powerpoint
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/xuri/excelize/v2"
+)
+
+// supportedChartTypes lists the chart types that can be rendered
+var supportedChartTypes = []string{"bar", "column", "line", "pie"}
+
+// resolveChartData populates categories and series from an Excel range when requested
+// and validates that the chart has usable, aligned data.
+func resolveChartData(ctx context.Context, chart *Chart) error {
+	chart.Type = strings.ToLower(strings.TrimSpace(chart.Type))
+	if chart.Type == "" {
+		chart.Type = "column"
+	}
+	if !slices.Contains(supportedChartTypes, chart.Type) {
+		return fmt.Errorf("unsupported chart type '%s': use one of %s", chart.Type, strings.Join(supportedChartTypes, ", "))
+	}
+
+	if chart.ExcelFile != "" {
+		if err := loadChartFromExcel(ctx, chart); err != nil {
+			return err
+		}
+	}
+
+	if len(chart.Categories) == 0 || len(chart.Series) == 0 {
+		return fmt.Errorf("chart requires categories and at least one series (or excel_file with excel_range)")
+	}
+	if len(chart.Categories) > maxChartPoints {
+		return fmt.Errorf("chart has %d categories, maximum is %d - summarise the data first", len(chart.Categories), maxChartPoints)
+	}
+	for i, s := range chart.Series {
+		if len(s.Values) != len(chart.Categories) {
+			return fmt.Errorf("series %d (%s) has %d values but there are %d categories", i, s.Name, len(s.Values), len(chart.Categories))
+		}
+		if chart.Series[i].Name == "" {
+			chart.Series[i].Name = fmt.Sprintf("Series %d", i+1)
+		}
+	}
+	if chart.Type == "pie" && len(chart.Series) > 1 {
+		chart.Series = chart.Series[:1]
+	}
+	return nil
+}
+
+// loadChartFromExcel reads a rectangular range where the first row contains series
+// names and the first column contains category labels
+func loadChartFromExcel(ctx context.Context, chart *Chart) error {
+	if !filepath.IsAbs(chart.ExcelFile) {
+		return fmt.Errorf("chart excel_file must be an absolute path, got: %s", chart.ExcelFile)
+	}
+	if err := workspace.CheckFileAccess(ctx, chart.ExcelFile); err != nil {
+		return err
+	}
+	if chart.ExcelRange == "" {
+		return fmt.Errorf("chart excel_range is required when excel_file is set (e.g., 'A1:C6')")
+	}
+
+	f, err := excelize.OpenFile(chart.ExcelFile)
+	if err != nil {
+		return fmt.Errorf("failed to open excel file %s: %w", chart.ExcelFile, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	sheet := chart.ExcelSheet
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+
+	startCol, startRow, endCol, endRow, err := parseCellRange(chart.ExcelRange)
+	if err != nil {
+		return err
+	}
+	if endCol-startCol < 1 || endRow-startRow < 1 {
+		return fmt.Errorf("excel_range %s must include a header row and a category column plus at least one data cell", chart.ExcelRange)
+	}
+
+	cell := func(col, row int) (string, error) {
+		name, err := excelize.CoordinatesToCellName(col, row)
+		if err != nil {
+			return "", err
+		}
+		return f.GetCellValue(sheet, name)
+	}
+
+	chart.Categories = nil
+	chart.Series = nil
+	for col := startCol + 1; col <= endCol; col++ {
+		name, err := cell(col, startRow)
+		if err != nil {
+			return fmt.Errorf("failed to read series header: %w", err)
+		}
+		chart.Series = append(chart.Series, ChartSeries{Name: strings.TrimSpace(name)})
+	}
+
+	for row := startRow + 1; row <= endRow; row++ {
+		category, err := cell(startCol, row)
+		if err != nil {
+			return fmt.Errorf("failed to read category: %w", err)
+		}
+		chart.Categories = append(chart.Categories, strings.TrimSpace(category))
+		for i := range chart.Series {
+			raw, err := cell(startCol+1+i, row)
+			if err != nil {
+				return fmt.Errorf("failed to read value: %w", err)
+			}
+			value, err := parseNumeric(raw)
+			if err != nil {
+				return fmt.Errorf("non-numeric value '%s' in sheet '%s' row %d - chart data must be numeric", raw, sheet, row)
+			}
+			chart.Series[i].Values = append(chart.Series[i].Values, value)
+		}
+	}
+
+	return nil
+}
+
+// parseCellRange splits an A1-style range into 1-based coordinates
+func parseCellRange(cellRange string) (startCol, startRow, endCol, endRow int, err error) {
+	start, end, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(cellRange)), ":")
+	if !ok {
+		return 0, 0, 0, 0, fmt.Errorf("invalid excel_range '%s': expected format like 'A1:C6'", cellRange)
+	}
+	if startCol, startRow, err = excelize.CellNameToCoordinates(start); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid excel_range start '%s': %w", start, err)
+	}
+	if endCol, endRow, err = excelize.CellNameToCoordinates(end); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid excel_range end '%s': %w", end, err)
+	}
+	if endCol < startCol || endRow < startRow {
+		return 0, 0, 0, 0, fmt.Errorf("invalid excel_range '%s': end cell must be below and to the right of start cell", cellRange)
+	}
+	return startCol, startRow, endCol, endRow, nil
+}
+
+// parseNumeric parses spreadsheet values, tolerating thousands separators, currency and percentages
+func parseNumeric(raw string) (float64, error) {
+	cleaned := strings.TrimSpace(raw)
+	if cleaned == "" {
+		return 0, nil
+	}
+	percent := strings.HasSuffix(cleaned, "%")
+	cleaned = strings.NewReplacer(",", "", "$", "", "£", "", "€", "", "%", "").Replace(cleaned)
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent {
+		value /= 100
+	}
+	return value, nil
+}
+
+// chartXML renders a DrawingML chart part using literal (cached) data
+func chartXML(chart *Chart) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	b.WriteString(`<c:roundedCorners val="0"/><c:chart>`)
+	if chart.Title != "" {
+		fmt.Fprintf(&b, `<c:title><c:tx><c:rich><a:bodyPr/><a:p><a:r><a:t>%s</a:t></a:r></a:p></c:rich></c:tx><c:overlay val="0"/></c:title><c:autoTitleDeleted val="0"/>`, escapeXML(chart.Title))
+	} else {
+		b.WriteString(`<c:autoTitleDeleted val="1"/>`)
+	}
+	b.WriteString(`<c:plotArea><c:layout/>`)
+
+	switch chart.Type {
+	case "pie":
+		b.WriteString(`<c:pieChart><c:varyColors val="1"/>`)
+		writeChartSeries(&b, chart)
+		b.WriteString(`<c:firstSliceAng val="0"/></c:pieChart>`)
+	case "line":
+		b.WriteString(`<c:lineChart><c:grouping val="standard"/><c:varyColors val="0"/>`)
+		writeChartSeries(&b, chart)
+		b.WriteString(`<c:marker val="1"/><c:axId val="500000001"/><c:axId val="500000002"/></c:lineChart>`)
+		writeChartAxes(&b, "b", "l")
+	default:
+		barDir := "col"
+		catPos, valPos := "b", "l"
+		if chart.Type == "bar" {
+			barDir = "bar"
+			catPos, valPos = "l", "b"
+		}
+		fmt.Fprintf(&b, `<c:barChart><c:barDir val="%s"/><c:grouping val="clustered"/><c:varyColors val="0"/>`, barDir)
+		writeChartSeries(&b, chart)
+		b.WriteString(`<c:gapWidth val="150"/><c:axId val="500000001"/><c:axId val="500000002"/></c:barChart>`)
+		writeChartAxes(&b, catPos, valPos)
+	}
+
+	b.WriteString(`</c:plotArea>`)
+	if len(chart.Series) > 1 || chart.Type == "pie" {
+		b.WriteString(`<c:legend><c:legendPos val="r"/><c:overlay val="0"/></c:legend>`)
+	}
+	b.WriteString(`<c:plotVisOnly val="1"/></c:chart></c:chartSpace>`)
+	return b.String()
+}
+
+// writeChartSeries writes each series with literal category and value caches
+func writeChartSeries(b *strings.Builder, chart *Chart) {
+	for i, s := range chart.Series {
+		fmt.Fprintf(b, `<c:ser><c:idx val="%d"/><c:order val="%d"/>`, i, i)
+		fmt.Fprintf(b, `<c:tx><c:v>%s</c:v></c:tx>`, escapeXML(s.Name))
+
+		fmt.Fprintf(b, `<c:cat><c:strLit><c:ptCount val="%d"/>`, len(chart.Categories))
+		for j, cat := range chart.Categories {
+			fmt.Fprintf(b, `<c:pt idx="%d"><c:v>%s</c:v></c:pt>`, j, escapeXML(cat))
+		}
+		b.WriteString(`</c:strLit></c:cat>`)
+
+		fmt.Fprintf(b, `<c:val><c:numLit><c:formatCode>General</c:formatCode><c:ptCount val="%d"/>`, len(s.Values))
+		for j, v := range s.Values {
+			fmt.Fprintf(b, `<c:pt idx="%d"><c:v>%s</c:v></c:pt>`, j, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		b.WriteString(`</c:numLit></c:val>`)
+
+		if chart.Type == "line" {
+			b.WriteString(`<c:smooth val="0"/>`)
+		}
+		b.WriteString(`</c:ser>`)
+	}
+}
+
+// writeChartAxes writes a category and value axis pair
+func writeChartAxes(b *strings.Builder, catPos, valPos string) {
+	fmt.Fprintf(b, `<c:catAx><c:axId val="500000001"/><c:scaling><c:orientation val="minMax"/></c:scaling><c:delete val="0"/><c:axPos val="%s"/><c:numFmt formatCode="General" sourceLinked="0"/><c:tickLblPos val="nextTo"/><c:crossAx val="500000002"/><c:crosses val="autoZero"/><c:auto val="1"/><c:lblAlgn val="ctr"/><c:lblOffset val="100"/></c:catAx>`, catPos)
+	fmt.Fprintf(b, `<c:valAx><c:axId val="500000002"/><c:scaling><c:orientation val="minMax"/></c:scaling><c:delete val="0"/><c:axPos val="%s"/><c:majorGridlines/><c:numFmt formatCode="General" sourceLinked="0"/><c:tickLblPos val="nextTo"/><c:crossAx val="500000001"/><c:crosses val="autoZero"/><c:crossBetween val="between"/></c:valAx>`, valPos)
+}
@@ -0,0 +1,137 @@
+package powerpoint
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// markdownImagePattern matches ![alt](path) image references
+	markdownImagePattern = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+	// markdownBulletPattern matches unordered and ordered list items, capturing indentation
+	markdownBulletPattern = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// markdownInlinePattern matches inline emphasis and code markers that don't render on slides
+	markdownInlinePattern = regexp.MustCompile("(\\*\\*|__|`)")
+	// markdownLinkPattern matches [text](url) links, keeping only the text
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+)
+
+// parseMarkdownSlides converts a markdown outline into slides.
+//
+// Each "# " or "## " heading starts a new slide, as does a "---" separator line.
+// List items become bullets (indent by two spaces per level), standalone image
+// references become image slides, and any lines after a "Notes:" line are used
+// as speaker notes. A first slide containing only a heading and plain text is
+// rendered as a title slide with the text as its subtitle.
+func parseMarkdownSlides(markdown string) []Slide {
+	var slides []Slide
+	var current *Slide
+	var paragraphs []string
+	var notes []string
+	inNotes := false
+	inFence := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if len(notes) > 0 {
+			current.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+		}
+		if len(current.Bullets) == 0 && current.Image == "" && len(paragraphs) > 0 && len(slides) == 0 {
+			current.Layout = "title"
+			current.Subtitle = strings.Join(paragraphs, "\n")
+		} else {
+			for _, p := range paragraphs {
+				current.Bullets = append(current.Bullets, Bullet{Text: p})
+			}
+		}
+		if current.Title != "" || current.Subtitle != "" || len(current.Bullets) > 0 || current.Image != "" || current.Notes != "" {
+			slides = append(slides, *current)
+		}
+		current = nil
+		paragraphs = nil
+		notes = nil
+		inNotes = false
+	}
+
+	ensure := func() {
+		if current == nil {
+			current = &Slide{}
+		}
+	}
+
+	for line := range strings.SplitSeq(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+
+		if inNotes && !inFence && !isSlideBoundary(trimmed) {
+			notes = append(notes, line)
+			continue
+		}
+
+		if inFence {
+			if trimmed != "" {
+				ensure()
+				paragraphs = append(paragraphs, trimmed)
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			continue
+		case trimmed == "---" || trimmed == "***":
+			flush()
+		case strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## "):
+			flush()
+			current = &Slide{Title: cleanInline(strings.TrimLeft(trimmed, "# "))}
+		case strings.HasPrefix(trimmed, "#"):
+			// Deeper headings become emphasised bullets within the current slide
+			ensure()
+			current.Bullets = append(current.Bullets, Bullet{Text: cleanInline(strings.TrimLeft(trimmed, "# "))})
+		case strings.EqualFold(trimmed, "notes:") || strings.EqualFold(trimmed, "note:"):
+			ensure()
+			inNotes = true
+		case markdownImagePattern.MatchString(trimmed):
+			ensure()
+			match := markdownImagePattern.FindStringSubmatch(trimmed)
+			if current.Image == "" {
+				current.Image = match[2]
+				if current.Title == "" && match[1] != "" {
+					current.Title = match[1]
+				}
+			}
+		case markdownBulletPattern.MatchString(line):
+			ensure()
+			match := markdownBulletPattern.FindStringSubmatch(line)
+			indent := len(strings.ReplaceAll(match[1], "\t", "  "))
+			current.Bullets = append(current.Bullets, Bullet{
+				Text:  cleanInline(match[2]),
+				Level: min(indent/2, maxBulletLevel),
+			})
+		default:
+			ensure()
+			paragraphs = append(paragraphs, cleanInline(trimmed))
+		}
+	}
+	flush()
+
+	return slides
+}
+
+// isSlideBoundary reports whether a line starts a new slide
+func isSlideBoundary(trimmed string) bool {
+	return trimmed == "---" || trimmed == "***" || strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ")
+}
+
+// cleanInline strips inline markdown formatting that has no meaning on a slide
+func cleanInline(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+	text = markdownInlinePattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(text)
+}
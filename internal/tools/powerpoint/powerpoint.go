@@ -0,0 +1,237 @@
+package powerpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// PowerPointTool generates PowerPoint (.pptx) presentations
+type PowerPointTool struct{}
+
+// init registers the PowerPoint tool
+func init() {
+	registry.Register(&PowerPointTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *PowerPointTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"powerpoint",
+		mcp.WithDescription(`Generate PowerPoint (.pptx) slide decks from a markdown outline or structured slides. Supports title slides, bullet slides (nested levels), images, speaker notes and simple bar/column/line/pie charts with inline data or data read from an Excel range.
+
+Markdown: each '# ' or '## ' heading starts a slide, '---' also separates slides, list items become bullets (indent two spaces per level), '![alt](/abs/path.png)' adds an image, and lines after 'Notes:' become speaker notes.
+
+Use 'slides' instead of 'markdown' when you need charts or explicit layouts. If you have problems, call get_tool_help with tool_name="powerpoint".`),
+		mcp.WithString("output_path",
+			mcp.Required(),
+			mcp.Description("Absolute path for the .pptx file (e.g., /Users/name/project/deck.pptx)"),
+		),
+		mcp.WithString("title",
+			mcp.Description("Presentation title stored in document properties"),
+		),
+		mcp.WithString("author",
+			mcp.Description("Presentation author stored in document properties"),
+		),
+		mcp.WithString("markdown",
+			mcp.Description("Markdown outline to convert into slides (alternative to slides)"),
+		),
+		mcp.WithArray("slides",
+			mcp.Description("Structured slides: [{layout?: 'title'|'bullets'|'image'|'chart', title, subtitle?, bullets?: [{text, level?}], image?: '/abs/path.png', chart?: {type: 'bar'|'column'|'line'|'pie', title?, categories, series: [{name, values}]} or {type, excel_file, excel_sheet?, excel_range: 'A1:C6'}, notes?}]"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Overwrite output_path if it already exists"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),   // Writes a presentation file
+		mcp.WithDestructiveHintAnnotation(true), // Can overwrite an existing file when overwrite is true
+		mcp.WithIdempotentHintAnnotation(true),  // Same input produces the same deck
+		mcp.WithOpenWorldHintAnnotation(false),  // Local file operations only
+	)
+}
+
+// Execute builds the presentation and writes it to disk
+func (t *PowerPointTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	request, err := parseRequest(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := workspace.CheckFileAccess(ctx, request.OutputPath); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+
+	if !request.Overwrite {
+		if _, err := os.Stat(request.OutputPath); err == nil {
+			return nil, fmt.Errorf("output file %s already exists - set overwrite to true to replace it", request.OutputPath)
+		}
+	}
+
+	slides := request.Slides
+	if len(slides) == 0 {
+		slides = parseMarkdownSlides(request.Markdown)
+	}
+	if len(slides) == 0 {
+		return nil, fmt.Errorf("no slides found - provide 'slides' or a 'markdown' outline with at least one heading")
+	}
+	if len(slides) > maxSlides {
+		return nil, fmt.Errorf("presentation has %d slides, maximum is %d", len(slides), maxSlides)
+	}
+	if request.Title == "" {
+		request.Title = slides[0].Title
+	}
+
+	logger.WithFields(logrus.Fields{
+		"output_path": request.OutputPath,
+		"slides":      len(slides),
+	}).Info("Generating PowerPoint presentation")
+
+	data, warnings, err := buildPresentation(ctx, request, slides)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(request.OutputPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(request.OutputPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write presentation: %w", err)
+	}
+
+	response := PresentationResponse{
+		OutputPath: request.OutputPath,
+		SlideCount: len(slides),
+		SizeBytes:  len(data),
+		Warnings:   warnings,
+	}
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseRequest extracts and validates tool arguments
+func parseRequest(args map[string]any) (*PresentationRequest, error) {
+	request := &PresentationRequest{}
+
+	outputPath, _ := args["output_path"].(string)
+	outputPath = strings.TrimSpace(outputPath)
+	if outputPath == "" {
+		return nil, fmt.Errorf("missing required parameter: output_path")
+	}
+	if !filepath.IsAbs(outputPath) {
+		return nil, fmt.Errorf("output_path must be an absolute path (e.g., /Users/name/project/deck.pptx), got: %s", outputPath)
+	}
+	if !strings.EqualFold(filepath.Ext(outputPath), ".pptx") {
+		return nil, fmt.Errorf("output_path must end in .pptx, got: %s", outputPath)
+	}
+	request.OutputPath = filepath.Clean(outputPath)
+
+	request.Title, _ = args["title"].(string)
+	request.Author, _ = args["author"].(string)
+	request.Markdown, _ = args["markdown"].(string)
+	request.Overwrite, _ = args["overwrite"].(bool)
+
+	if rawSlides, ok := args["slides"]; ok && rawSlides != nil {
+		// Round-trip through JSON to map loosely typed arguments onto the slide structs
+		encoded, err := json.Marshal(rawSlides)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slides parameter: %w", err)
+		}
+		if err := json.Unmarshal(encoded, &request.Slides); err != nil {
+			return nil, fmt.Errorf("invalid slides parameter: %w", err)
+		}
+	}
+
+	if len(request.Slides) == 0 && strings.TrimSpace(request.Markdown) == "" {
+		return nil, fmt.Errorf("either 'slides' or 'markdown' must be provided")
+	}
+
+	return request, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the PowerPoint tool
+func (t *PowerPointTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Create a deck from a markdown outline",
+				Arguments: map[string]any{
+					"output_path": "/Users/name/project/update.pptx",
+					"markdown":    "# Quarterly Update\nEngineering team\n\n## Highlights\n- Shipped v2.0\n  - 40% faster builds\n- Hired two engineers\n\nNotes:\nMention the migration timeline.",
+				},
+				ExpectedResult: "Writes a two-slide deck: a title slide and a bullet slide with speaker notes",
+			},
+			{
+				Description: "Chart slide using data from an Excel range",
+				Arguments: map[string]any{
+					"output_path": "/Users/name/project/sales.pptx",
+					"slides": []map[string]any{
+						{
+							"title": "Sales by Region",
+							"chart": map[string]any{
+								"type":        "column",
+								"excel_file":  "/Users/name/project/sales.xlsx",
+								"excel_sheet": "Summary",
+								"excel_range": "A1:C5",
+							},
+						},
+					},
+				},
+				ExpectedResult: "Writes a slide with a column chart - row 1 holds series names and column A holds categories",
+			},
+			{
+				Description: "Structured slides with an image and inline chart data",
+				Arguments: map[string]any{
+					"output_path": "/Users/name/project/arch.pptx",
+					"slides": []map[string]any{
+						{"layout": "title", "title": "Architecture Review", "subtitle": "March 2026"},
+						{"title": "System Overview", "image": "/Users/name/project/diagram.png", "notes": "Walk through each component"},
+						{"title": "Latency", "chart": map[string]any{"type": "line", "categories": []string{"Jan", "Feb", "Mar"}, "series": []map[string]any{{"name": "p95 ms", "values": []float64{120, 95, 80}}}}},
+					},
+				},
+				ExpectedResult: "Writes a three-slide deck with a title slide, image slide and line chart",
+			},
+		},
+		CommonPatterns: []string{
+			"Draft content as markdown first, then switch to structured slides only for charts",
+			"Combine bullets with an image or chart on one slide - bullets go on the left, the visual on the right",
+			"Generate chart data with the excel tool and reference it via excel_file/excel_range",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Image missing from a slide",
+				Solution: "Check the warnings in the response. Images must be png, jpeg or gif and readable; relative paths are resolved against the output directory",
+			},
+			{
+				Problem:  "Chart series and categories mismatch error",
+				Solution: "Each series needs exactly one value per category. For Excel sources, include the header row and category column in excel_range",
+			},
+			{
+				Problem:  "Output file already exists",
+				Solution: "Set overwrite to true or choose a different output_path",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"output_path": "Absolute path ending in .pptx. Parent directories are created if needed",
+			"markdown":    "Outline where '# '/'## ' headings and '---' start slides. The first slide becomes a title slide when it only has plain text",
+			"slides":      "Array of slide objects. Layout is inferred when omitted: title when only a subtitle is set, otherwise bullets",
+			"overwrite":   "Defaults to false to avoid replacing existing presentations",
+		},
+		WhenToUse:    "Producing shareable slide decks from notes, reports or analysis results, including simple charts",
+		WhenNotToUse: "Editing existing presentations, complex animations or precise design templates",
+	}
+}
@@ -0,0 +1,57 @@
+package powerpoint
+
+// PresentationRequest represents the parsed parameters for building a presentation
+type PresentationRequest struct {
+	OutputPath string  `json:"output_path"`
+	Title      string  `json:"title,omitempty"`
+	Author     string  `json:"author,omitempty"`
+	Markdown   string  `json:"markdown,omitempty"`
+	Slides     []Slide `json:"slides,omitempty"`
+	Overwrite  bool    `json:"overwrite,omitempty"`
+}
+
+// Slide represents a single slide in the deck
+type Slide struct {
+	// Layout is one of "title", "bullets", "image", "chart" (inferred when empty)
+	Layout   string   `json:"layout,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Subtitle string   `json:"subtitle,omitempty"`
+	Bullets  []Bullet `json:"bullets,omitempty"`
+	Image    string   `json:"image,omitempty"`
+	Chart    *Chart   `json:"chart,omitempty"`
+	Notes    string   `json:"notes,omitempty"`
+}
+
+// Bullet represents a bullet point with an indentation level (0-based)
+type Bullet struct {
+	Text  string `json:"text"`
+	Level int    `json:"level,omitempty"`
+}
+
+// Chart describes a simple chart, either inline data or sourced from an Excel range
+type Chart struct {
+	// Type is one of "bar", "column", "line", "pie"
+	Type       string        `json:"type"`
+	Title      string        `json:"title,omitempty"`
+	Categories []string      `json:"categories,omitempty"`
+	Series     []ChartSeries `json:"series,omitempty"`
+
+	// Excel source - first row is series names, first column is categories
+	ExcelFile  string `json:"excel_file,omitempty"`
+	ExcelSheet string `json:"excel_sheet,omitempty"`
+	ExcelRange string `json:"excel_range,omitempty"`
+}
+
+// ChartSeries is a named series of values aligned with the chart categories
+type ChartSeries struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+}
+
+// PresentationResponse is returned after a deck has been written
+type PresentationResponse struct {
+	OutputPath string   `json:"output_path"`
+	SlideCount int      `json:"slide_count"`
+	SizeBytes  int      `json:"size_bytes"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
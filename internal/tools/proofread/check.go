@@ -0,0 +1,325 @@
+package proofread
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minWordLength is the shortest word spell checked, as most shorter ones are abbreviations
+	minWordLength = 3
+	// maxContext is the most characters of a line returned around an issue
+	maxContext = 120
+	// maxUnknown is the most distinct unknown words listed
+	maxUnknown = 50
+)
+
+var (
+	// chunk is a run of text between spaces and masked text
+	chunk = regexp.MustCompile("[^\\s\x00]+")
+	// wordPattern is a word, including apostrophes within it
+	wordPattern = regexp.MustCompile(`[A-Za-z]+(?:['’][A-Za-z]+)*['’]?`)
+	// identifier matches chunks that are code, paths, versions, handles, flags or word parts rather
+	// than prose, including words with emphasis inside them such as **E**xponents
+	identifier = regexp.MustCompile(`[0-9_/\\@=<>{}|$%^#~*]|::|[A-Za-z]\.[A-Za-z]|\(\)|^-\w`)
+)
+
+// misused are words that are usually a mistake for another word or phrase
+var misused = map[string]string{
+	"alot":         "a lot",
+	"irregardless": "regardless",
+	"everytime":    "every time",
+	"infact":       "in fact",
+	"incase":       "in case",
+	"aswell":       "as well",
+}
+
+// misusedPairs are pairs of words that are usually a mistake for another phrase
+var misusedPairs = map[string]string{
+	"could of":  "could have",
+	"should of": "should have",
+	"would of":  "would have",
+	"must of":   "must have",
+	"might of":  "might have",
+	"per say":   "per se",
+}
+
+// allowedRepeats are words that are correct twice in a row, as in "that that" and "had had"
+var allowedRepeats = []string{"that", "had"}
+
+// consonantSounds are beginnings of words that start with a vowel letter but a consonant sound, so
+// take "a" rather than "an"
+var consonantSounds = []string{"uni", "use", "usa", "usu", "uti", "ura", "ure", "uri", "url", "uro", "eu", "ewe", "one", "once", "ubiq", "uk"}
+
+// silentH are beginnings of words whose h is silent, so take "an" rather than "a"
+var silentH = []string{"hour", "honest", "honor", "honour", "heir"}
+
+// token is a word and where it is
+type token struct {
+	text   string
+	line   int
+	offset int
+	// adjacent reports whether only spaces separate the word from the one before it
+	adjacent bool
+}
+
+// checker finds issues in text and gathers them across files
+type checker struct {
+	lookup      *lookup
+	checks      []string
+	british     bool
+	capitalised bool
+	maxIssues   int
+
+	words   int
+	counts  map[string]int
+	issues  []Issue
+	unknown map[string]int
+}
+
+// newChecker returns a checker for the requested checks
+func newChecker(l *lookup, checks []string, british, capitalised bool, maxIssues int) *checker {
+	c := &checker{lookup: l, checks: checks, british: british, capitalised: capitalised, maxIssues: maxIssues, counts: map[string]int{}, unknown: map[string]int{}}
+	for _, check := range checks {
+		c.counts[checkKinds[check]] = 0
+	}
+	return c
+}
+
+// checkKinds maps each check to the kind of issue it reports
+var checkKinds = map[string]string{"spelling": "spelling", "variants": "variant", "grammar": "grammar"}
+
+// check looks for issues in content, masking Markdown that is not prose when markdown is set
+func (c *checker) check(file, content string, markdown bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var prose []string
+	if markdown {
+		prose = maskMarkdown(lines)
+	} else {
+		prose = make([]string, len(lines))
+		for i, line := range lines {
+			prose[i] = maskText(line)
+		}
+	}
+	var prev *token
+	for i, line := range prose {
+		if strings.TrimSpace(line) == "" {
+			// A paragraph break separates words
+			prev = nil
+			continue
+		}
+		end := 0
+		for _, span := range chunk.FindAllStringIndex(line, -1) {
+			text := line[span[0]:span[1]]
+			gap := line[end:span[0]]
+			end = span[1]
+			// Emphasis and punctuation around a chunk do not make it code
+			if core := strings.Trim(text, "*_~\"'()[]“”‘’.,;:!?"); identifier.MatchString(core) || hasUnicodeLetter(core) {
+				prev = nil
+				continue
+			}
+			// A chunk is adjacent to the previous one when only spaces, on this line or the last,
+			// separate them
+			adjacent := prev != nil && !strings.ContainsRune(gap, masked) && (span[0] > 0 || prev.line == i-1)
+			words := wordPattern.FindAllStringIndex(text, -1)
+			for j, w := range words {
+				t := &token{text: text[w[0]:w[1]], line: i, offset: span[0] + w[0], adjacent: adjacent && j == 0 && w[0] == 0}
+				c.word(file, lines, prev, t)
+				prev = t
+				if j < len(words)-1 || w[1] != len(text) {
+					// Punctuation ends a run of neighbouring words
+					prev = nil
+				}
+			}
+			if len(words) == 0 {
+				prev = nil
+			}
+		}
+	}
+}
+
+// word checks one word, given the word before it when they are neighbours
+func (c *checker) word(file string, lines []string, prev, t *token) {
+	c.words++
+	lower := strings.ToLower(t.text)
+	if slices.Contains(c.checks, "grammar") {
+		if prev != nil && t.adjacent {
+			pair := strings.ToLower(prev.text) + " " + lower
+			if fix, ok := misusedPairs[pair]; ok {
+				c.add(file, lines, prev, prev.text+" "+t.text, "grammar", fmt.Sprintf("%q is usually a mistake for %q", pair, fix), matchCase(prev.text, fix))
+				return
+			}
+			if strings.ToLower(prev.text) == lower && !slices.Contains(allowedRepeats, lower) {
+				c.add(file, lines, prev, prev.text+" "+t.text, "grammar", fmt.Sprintf("%q is repeated", t.text), prev.text)
+				return
+			}
+			if article := c.article(prev.text, t.text); article != "" {
+				c.add(file, lines, prev, prev.text+" "+t.text, "grammar", fmt.Sprintf("Use %q before %q", strings.ToLower(article), t.text), article+" "+t.text)
+			}
+		}
+		if fix, ok := misused[lower]; ok {
+			c.add(file, lines, t, t.text, "grammar", fmt.Sprintf("%q is usually a mistake for %q", t.text, fix), matchCase(t.text, fix))
+			return
+		}
+	}
+	if isAcronym(t.text) || isMixedCase(t.text) {
+		return
+	}
+	if slices.Contains(c.checks, "variants") {
+		// Possessives are looked up without their ending, as in organisation's
+		stem, ending := lower, ""
+		for _, possessive := range []string{"'s", "’s", "'", "’"} {
+			if before, ok := strings.CutSuffix(lower, possessive); ok {
+				stem, ending = before, possessive
+				break
+			}
+		}
+		if replacement, ok := c.lookup.variant(stem); ok {
+			message := fmt.Sprintf("American spelling; the British spelling is %q", replacement)
+			if !c.british {
+				message = fmt.Sprintf("British spelling; the American spelling is %q", replacement)
+			}
+			c.add(file, lines, t, t.text, "variant", message, matchCase(t.text, replacement+ending))
+			return
+		}
+	}
+	if !slices.Contains(c.checks, "spelling") || utf8.RuneCountInString(strings.Trim(t.text, "'’")) < minWordLength {
+		return
+	}
+	if !c.capitalised && unicode.IsUpper(rune(t.text[0])) {
+		return
+	}
+	if c.lookup.known(t.text) {
+		return
+	}
+	c.unknown[strings.Trim(lower, "'’")]++
+	if c.add(file, lines, t, t.text, "spelling", fmt.Sprintf("%q is not in the dictionary", t.text)) {
+		c.issues[len(c.issues)-1].Suggestions = c.lookup.suggest(strings.Trim(t.text, "'’"))
+	}
+}
+
+// article returns the article that should replace a or an before word, or "" when it is right
+func (c *checker) article(article, word string) string {
+	lower := strings.ToLower(article)
+	if (lower != "a" && lower != "an") || isAcronym(word) || len(word) < 2 {
+		return ""
+	}
+	next := strings.ToLower(word)
+	vowel := strings.ContainsRune("aeiou", rune(next[0]))
+	startsWith := func(prefix string) bool { return strings.HasPrefix(next, prefix) }
+	if vowel && slices.ContainsFunc(consonantSounds, startsWith) {
+		vowel = false
+	}
+	if slices.ContainsFunc(silentH, startsWith) {
+		vowel = true
+	}
+	switch {
+	case lower == "a" && vowel:
+		return matchCase(article, "an")
+	case lower == "an" && !vowel && next[0] != 'h':
+		// Before other words starting with h, "an" is an older but accepted style
+		return matchCase(article, "a")
+	}
+	return ""
+}
+
+// add records an issue, returning false when the issue limit has been reached
+func (c *checker) add(file string, lines []string, t *token, text, kind, message string, suggestions ...string) bool {
+	c.counts[kind]++
+	if len(c.issues) >= c.maxIssues {
+		return false
+	}
+	line := lines[t.line]
+	c.issues = append(c.issues, Issue{
+		File:        file,
+		Line:        t.line + 1,
+		Column:      utf8.RuneCountInString(line[:t.offset]) + 1,
+		Kind:        kind,
+		Text:        text,
+		Message:     message,
+		Suggestions: suggestions,
+		Context:     excerpt(line, t.offset),
+	})
+	return true
+}
+
+// truncated reports whether issues were left out
+func (c *checker) truncated() bool {
+	total := 0
+	for _, count := range c.counts {
+		total += count
+	}
+	return total > len(c.issues)
+}
+
+// unknownWords lists the misspelt words, most frequent first
+func (c *checker) unknownWords() []UnknownWord {
+	words := slices.Collect(maps.Keys(c.unknown))
+	slices.SortFunc(words, func(a, b string) int {
+		return cmp.Or(c.unknown[b]-c.unknown[a], strings.Compare(a, b))
+	})
+	var out []UnknownWord
+	for _, word := range words[:min(len(words), maxUnknown)] {
+		out = append(out, UnknownWord{Word: word, Count: c.unknown[word]})
+	}
+	return out
+}
+
+// excerpt returns the line around a byte offset, trimmed of surrounding space and shortened to
+// maxContext characters
+func excerpt(line string, offset int) string {
+	runes := []rune(line)
+	at := utf8.RuneCountInString(line[:offset])
+	start, end := 0, len(runes)
+	if len(runes) > maxContext {
+		start = max(0, at-maxContext/2)
+		end = min(len(runes), start+maxContext)
+		start = max(0, end-maxContext)
+	}
+	text := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		text = "…" + text
+	}
+	if end < len(runes) {
+		text += "…"
+	}
+	return text
+}
+
+// isAcronym reports whether a word is in capitals, such as API or URLs
+func isAcronym(word string) bool {
+	upper := 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return upper > 1 && upper >= len(word)-1
+}
+
+// isMixedCase reports whether a word has a capital after its first letter, as in camelCase names
+func isMixedCase(word string) bool {
+	for _, r := range word[1:] {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnicodeLetter reports whether text has letters outside ASCII, which the dictionary does not
+// cover
+func hasUnicodeLetter(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
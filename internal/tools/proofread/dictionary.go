@@ -0,0 +1,457 @@
+package proofread
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sammcj/m2e/pkg/converter"
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// wordList is the shared British and American word list, most common first where known. It is
+// generated by gen_words.go, which words.txt names its sources and their licences in.
+//
+//go:generate go run gen_words.go
+//go:embed words.txt
+var wordList string
+
+// headwordList is the Hunspell dictionary's headwords with the affix flags prefixes are checked
+// against, also generated by gen_words.go
+//
+//go:embed headwords.txt
+var headwordList string
+
+// projectDictionaryNames are the files searched for project words, from the checked file's directory
+// up to the repository root. cspell files are read for their words and ignoreWords.
+var projectDictionaryNames = []string{".proofread-words", "cspell.json", ".cspell.json"}
+
+// britishAlso are American spellings that are also correct British English in some senses, such as
+// the verb license and the noun practice, so they are not reported in British English text
+var britishAlso = []string{
+	"advisor", "analog", "artifact", "artifacts", "burned", "dialog", "dialogs", "draft", "dreamed",
+	"inquire", "inquiry", "judgment", "learned", "leaped", "license", "licensed", "licenses",
+	"licensing", "meter", "meters", "practice", "practices", "spelled", "tire", "tires", "toward",
+}
+
+// americanAlso are British spellings that are also accepted in American English
+var americanAlso = []string{
+	"adviser", "analogue", "burnt", "catalogue", "dialogue", "draught", "dreamt", "enquire",
+	"enquiry", "judgement", "leapt", "spelt", "towards",
+}
+
+// baseWords maps each shared word to its rank, where lower is more common
+var baseWords = sync.OnceValue(func() map[string]int {
+	words := map[string]int{}
+	for line := range strings.Lines(wordList) {
+		if word := strings.TrimSpace(line); word != "" && !strings.HasPrefix(word, "#") {
+			words[word] = len(words)
+		}
+	}
+	return words
+})
+
+// headwords maps each dictionary headword to its affix flags
+var headwords = sync.OnceValue(func() map[string]string {
+	words := map[string]string{}
+	for line := range strings.Lines(headwordList) {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			word, flags, _ := strings.Cut(line, "/")
+			words[word] = flags
+		}
+	}
+	return words
+})
+
+// americanToBritish is the murican_to_english dictionary in lower case, without phrases and notes
+var americanToBritish = sync.OnceValue(func() map[string]string {
+	spellings := map[string]string{}
+	dictionaries, err := converter.LoadDictionaries()
+	if err != nil {
+		return spellings
+	}
+	for american, british := range dictionaries.AmericanToBritish {
+		american, british = strings.ToLower(american), strings.ToLower(british)
+		if isWord(american) && isWord(british) && american != british {
+			spellings[american] = british
+		}
+	}
+	return spellings
+})
+
+// languages are the dictionaries for each supported language, built on first use
+var languages = map[string]func() *dictionary{
+	"en-GB": sync.OnceValue(func() *dictionary { return newDictionary(true) }),
+	"en-US": sync.OnceValue(func() *dictionary { return newDictionary(false) }),
+}
+
+// izeEnding matches words spelt with -ize in American English and -ise in British English, such as
+// organize, organised and organisation, capturing the stem, the letter that differs and the ending
+var izeEnding = regexp.MustCompile(`^([a-z]{3,})i([sz])(e|es|ed|ing|er|ers|ation|ations|able)$`)
+
+// dictionary holds the words accepted in one language, and the other language's spellings with
+// their replacements
+type dictionary struct {
+	british  bool
+	words    map[string]int
+	variants map[string]string
+
+	// spellings maps this language's spellings that are not dictionary headwords, such as colour, to
+	// the headwords they are spelt as in American English
+	spellings map[string]string
+}
+
+// newDictionary builds the British or American dictionary
+func newDictionary(british bool) *dictionary {
+	base := baseWords()
+	d := &dictionary{british: british, words: make(map[string]int, len(base)+2000), variants: map[string]string{}, spellings: map[string]string{}}
+	for word, rank := range base {
+		d.words[word] = rank
+	}
+	add := func(word string) {
+		if _, ok := d.words[word]; !ok {
+			d.words[word] = len(d.words)
+		}
+	}
+	for us, gb := range americanToBritish() {
+		switch {
+		case british:
+			add(gb)
+			d.spellings[gb] = us
+			if slices.Contains(britishAlso, us) {
+				add(us)
+			} else {
+				d.variants[us] = gb
+			}
+		default:
+			add(us)
+			if slices.Contains(americanAlso, gb) {
+				add(gb)
+			} else {
+				d.variants[gb] = us
+			}
+		}
+	}
+	return d
+}
+
+// lookup checks words against a language's dictionary and a project's own words, remembering
+// the suggestions made for each misspelling
+type lookup struct {
+	*dictionary
+	custom      map[string]bool
+	suggestions map[string][]string
+}
+
+// has reports whether the lower-case word is in the dictionary or the project's words
+func (l *lookup) has(word string) bool {
+	if _, ok := l.words[word]; ok {
+		return true
+	}
+	return l.custom[word]
+}
+
+// contractions are the endings removed before looking up words such as it's, they're and users'
+var contractions = []string{"n't", "'s", "'re", "'ve", "'ll", "'d", "'m", "'"}
+
+// irregularContractions are contractions whose stem is not a word
+var irregularContractions = []string{"can't", "won't", "shan't", "ain't"}
+
+// known reports whether a word, in any case, is spelt correctly
+func (l *lookup) known(word string) bool {
+	word = strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+	if l.has(word) || slices.Contains(irregularContractions, word) {
+		return true
+	}
+	// The word list has -ize spellings, which British English spells -ise
+	if stem, letter, ending := izeParts(word); l.british && letter == "s" && l.derived(stem+"iz"+ending, 1) {
+		return true
+	}
+	for _, ending := range contractions {
+		if stem, ok := strings.CutSuffix(word, ending); ok && stem != "" {
+			return !strings.Contains(stem, "'") && l.derived(stem, 2)
+		}
+	}
+	return l.derived(word, 2)
+}
+
+// variant returns the replacement for a lower-case word spelt the other language's way
+func (l *lookup) variant(word string) (string, bool) {
+	if l.custom[word] {
+		return "", false
+	}
+	if replacement, ok := l.variants[word]; ok {
+		return replacement, true
+	}
+	stem, letter, ending := izeParts(word)
+	switch {
+	case l.british && letter == "z" && l.derived(word, 1):
+		return stem + "is" + ending, true
+	case !l.british && letter == "s" && !l.has(word) && l.derived(stem+"iz"+ending, 1):
+		return stem + "iz" + ending, true
+	}
+	return "", false
+}
+
+// izeParts splits a word ending in -ise or -ize, or one of their forms, into its stem, s or z, and
+// ending. Words such as size, prize and capsize, spelt the same in both languages, give no parts.
+func izeParts(word string) (stem, letter, ending string) {
+	match := izeEnding.FindStringSubmatch(word)
+	if match == nil || strings.HasSuffix(match[1], "s") || strings.HasSuffix(match[1], "pr") {
+		return "", "", ""
+	}
+	return match[1], match[2], match[3]
+}
+
+// suffixes are endings removed to find a word's stem, with the endings the stem may have instead
+var suffixes = []struct {
+	suffix  string
+	endings []string
+}{
+	{"iness", []string{"y"}},
+	{"ness", []string{""}},
+	{"ment", []string{""}},
+	{"less", []string{""}},
+	{"ful", []string{""}},
+	{"ably", []string{"able", "e", ""}},
+	{"able", []string{"e", ""}},
+	{"ibly", []string{"ible"}},
+	{"ally", []string{"", "al"}},
+	{"ily", []string{"y"}},
+	{"ly", []string{""}},
+	{"ies", []string{"y"}},
+	{"ied", []string{"y"}},
+	{"ier", []string{"y"}},
+	{"iest", []string{"y"}},
+	{"ing", []string{"", "e"}},
+	{"ed", []string{"", "e"}},
+	{"er", []string{"", "e"}},
+	{"est", []string{"", "e"}},
+	{"es", []string{""}},
+	{"s", []string{""}},
+}
+
+// prefixes are beginnings removed to find a word's stem, with the Hunspell flags of the headwords
+// that take them. The dictionary has flags for re-, un-, de- and dis-. The other prefixes take any
+// headword, except super-, which only takes nouns, as in superset, so supercede is not read as
+// super- and cede.
+var prefixes = []struct {
+	prefix string
+	flags  string
+}{
+	{"un", "U"}, {"re", "A"}, {"de", "C"}, {"dis", "E"}, {"super", "M"},
+	{"non", ""}, {"pre", ""}, {"sub", ""}, {"over", ""}, {"under", ""}, {"mis", ""}, {"co", ""},
+	{"inter", ""}, {"multi", ""}, {"auto", ""}, {"micro", ""}, {"meta", ""},
+}
+
+// derived reports whether a word is a known stem with common prefixes and suffixes, looking through
+// at most depth of them
+func (l *lookup) derived(word string, depth int) bool {
+	return l.derivedFrom(word, depth, l.has, true)
+}
+
+// derivedFrom reports whether a word is a stem accepted by base with common suffixes, and with
+// withPrefix, a prefix, looking through at most depth of them. A prefix is only accepted on a
+// headword that takes it, so misspellings such as recived and supercede are not read as a prefix
+// and a word.
+func (l *lookup) derivedFrom(word string, depth int, base func(string) bool, withPrefix bool) bool {
+	if base(word) {
+		return true
+	}
+	if depth == 0 {
+		return false
+	}
+	for _, s := range suffixes {
+		stem, ok := strings.CutSuffix(word, s.suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		for _, ending := range s.endings {
+			if l.derivedFrom(stem+ending, depth-1, base, withPrefix) {
+				return true
+			}
+		}
+		// Doubled consonants, as in stopped and running
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] && strings.ContainsAny(s.suffix[:1], "aeiou") && base(stem[:n-1]) {
+			return true
+		}
+	}
+	if !withPrefix {
+		return false
+	}
+	for _, p := range prefixes {
+		takesPrefix := func(stem string) bool { return l.takesPrefix(stem, p.flags) }
+		if stem, ok := strings.CutPrefix(word, p.prefix); ok && len(stem) > 2 && l.derivedFrom(stem, depth-1, takesPrefix, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// takesPrefix reports whether a stem is a dictionary headword with one of a prefix's flags, or any
+// headword when the prefix has none
+func (l *lookup) takesPrefix(stem, flags string) bool {
+	if headword, ok := l.spellings[stem]; ok {
+		stem = headword
+	}
+	headwordFlags, ok := headwords()[stem]
+	return ok && (flags == "" || strings.ContainsAny(headwordFlags, flags))
+}
+
+// maxSuggestions is the most replacements offered for a word
+const maxSuggestions = 5
+
+// suggest returns likely corrections for a misspelt word, most common first, in the word's case
+func (l *lookup) suggest(word string) []string {
+	lower := strings.ToLower(word)
+	found, ok := l.suggestions[lower]
+	if !ok {
+		candidates := map[string]bool{}
+		collect := func(edit []byte) {
+			if l.hasBytes(edit) {
+				candidates[string(edit)] = true
+			}
+		}
+		edits([]byte(lower), collect)
+		// Two edits away is only tried for short words, as the candidates grow quickly
+		if len(candidates) == 0 && len(lower) <= 10 {
+			edits([]byte(lower), func(edit []byte) {
+				edits(edit, collect)
+			})
+		}
+		found = slices.Collect(maps.Keys(candidates))
+		slices.SortFunc(found, func(a, b string) int {
+			return cmp.Or(l.rank(a)-l.rank(b), strings.Compare(a, b))
+		})
+		found = found[:min(len(found), maxSuggestions)]
+		l.suggestions[lower] = found
+	}
+	matched := make([]string, len(found))
+	for i, suggestion := range found {
+		matched[i] = matchCase(word, suggestion)
+	}
+	return matched
+}
+
+// hasBytes is has for a word being built, without copying it
+func (l *lookup) hasBytes(word []byte) bool {
+	if _, ok := l.words[string(word)]; ok {
+		return true
+	}
+	return l.custom[string(word)]
+}
+
+// rank orders words by how common they are, with project words first
+func (l *lookup) rank(word string) int {
+	if rank, ok := l.words[word]; ok {
+		return rank
+	}
+	return -1
+}
+
+// edits calls fn with each string one deletion, transposition, replacement or insertion from word.
+// The slice fn is given is reused, so fn must copy it to keep it.
+func edits(word []byte, fn func([]byte)) {
+	edit := make([]byte, 0, len(word)+1)
+	for i := range len(word) + 1 {
+		if i < len(word) {
+			fn(append(append(edit[:0], word[:i]...), word[i+1:]...))
+		}
+		if i < len(word)-1 {
+			edit = append(edit[:0], word...)
+			edit[i], edit[i+1] = edit[i+1], edit[i]
+			fn(edit)
+		}
+		for c := byte('a'); c <= 'z'; c++ {
+			if i < len(word) {
+				edit = append(edit[:0], word...)
+				edit[i] = c
+				fn(edit)
+			}
+			fn(append(append(append(edit[:0], word[:i]...), c), word[i:]...))
+		}
+	}
+}
+
+// matchCase gives a replacement the case of the word it replaces: lower, Capitalised or UPPER
+func matchCase(word, replacement string) string {
+	switch {
+	case word == strings.ToUpper(word) && len(word) > 1:
+		return strings.ToUpper(replacement)
+	case word[:1] == strings.ToUpper(word[:1]):
+		return strings.ToUpper(replacement[:1]) + replacement[1:]
+	default:
+		return replacement
+	}
+}
+
+// isWord reports whether s is a single word of ASCII letters
+func isWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// readDictionary reads project words from a cspell JSON file or a file with one word per line
+func readDictionary(path string) ([]string, error) {
+	if err := security.CheckFileAccess(path); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var config struct {
+			Words       []string `json:"words"`
+			IgnoreWords []string `json:"ignoreWords"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return append(config.Words, config.IgnoreWords...), nil
+	}
+	var words []string
+	for line := range strings.Lines(string(data)) {
+		if word := strings.TrimSpace(line); word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
+
+// findProjectDictionaries returns the project dictionary files in dir and its parents, stopping at
+// the repository root
+func findProjectDictionaries(dir string) []string {
+	var found []string
+	for {
+		for _, name := range projectDictionaryNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				found = append(found, path)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return found
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return found
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,230 @@
+//go:build ignore
+
+// gen_words regenerates words.txt, the proofread tool's word list, and headwords.txt, the dictionary
+// headwords it accepts prefixes on, with go generate. It reads its sources from the module cache,
+// downloading the modules if needed.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/m2e/pkg/converter"
+)
+
+// The sources are read from pinned module versions so the list is reproducible
+const (
+	// vocabModule holds a copy of the BERT vocabulary, which ranks words by how common they are
+	vocabModule = "github.com/daulet/tokenizers@v1.27.0"
+	vocabFile   = "test/data/bert-base-uncased.json"
+
+	// dictionaryModule holds a copy of the SCOWL en_US-web Hunspell dictionary, which Vale checks
+	// spelling with by default
+	dictionaryModule = "github.com/errata-ai/vale/v3@v3.21.0"
+	dictionaryFile   = "internal/spell/data/en_US-web.dic"
+
+	// headwordFlags are the Hunspell affix flags kept in headwords.txt: the re-, un-, de- and dis-
+	// prefixes, and the possessive, which marks nouns
+	headwordFlags = "ACEUM"
+)
+
+// dictionaryLicence credits the Hunspell dictionary in the headers of both generated files
+const dictionaryLicence = `#   The dictionary is built from SCOWL (Spell Checker Oriented Word Lists,
+#   http://wordlist.aspell.net), Copyright Kevin Atkinson, and is distributed under the SCOWL
+#   licence: "Permission to use, copy, modify, distribute and sell these word lists, the associated
+#   scripts, the output created from the scripts, and its documentation for any purpose is hereby
+#   granted without fee, provided that the above copyright notice appears in all copies and that
+#   both that copyright notice and this permission notice appear in supporting documentation. Kevin
+#   Atkinson makes no representations about the suitability of this array for any purpose. It is
+#   provided "as is" without express or implied warranty." The word lists SCOWL is built from keep
+#   their own notices, listed in SCOWL's Copyright file. Read from the copy Vale ships,
+#   ` + dictionaryFile + ` in ` + dictionaryModule + `.
+`
+
+const header = `# English words spelt the same in British and American English, most common first, for the
+# proofread tool. Words spelt differently come from the murican_to_english dictionary, and -ize
+# words are also accepted with -ise in British English.
+#
+# Generated by gen_words.go. Do not edit: add terms to extra_words.txt and run go generate.
+#
+# Sources, in this order:
+#
+# - The whole words of the BERT-Base, Uncased vocabulary, in its order. Copyright 2018 The Google
+#   AI Language Team Authors, licensed under the Apache License, Version 2.0
+#   (http://www.apache.org/licenses/LICENSE-2.0). Read from ` + vocabFile + `
+#   in ` + vocabModule + `.
+# - The lower-case stems of the en_US-web Hunspell dictionary, without their affix flags, in
+#   alphabetical order.
+` + dictionaryLicence + `# - The project's own terms from extra_words.txt.
+`
+
+const headwordsHeader = `# The lower-case headwords of the en_US-web Hunspell dictionary, for the proofread tool, with the
+# affix flags it uses after a slash: A, U, C and E for headwords that take the re-, un-, de- and
+# dis- prefixes, and M for nouns, which have a possessive. Prefixes are only accepted on headwords,
+# so a misspelling such as recived is not read as re- and a word.
+#
+# Generated by gen_words.go. Do not edit: run go generate.
+#
+# Source:
+#
+# - The lower-case stems of the en_US-web Hunspell dictionary, in alphabetical order.
+` + dictionaryLicence
+
+var (
+	// wordToken matches vocabulary entries and dictionary stems that are whole lower-case words of
+	// three or more letters, leaving out sub-word pieces, abbreviations and proper nouns
+	wordToken = regexp.MustCompile(`^[a-z]{3,}$`)
+
+	// twoLetterWords are the two-letter words kept, as most two-letter entries are abbreviations
+	twoLetterWords = strings.Fields("am an as at be by do go he if in is it me my no of oh ok on or so to up us we ox ad ah ha hi ma pa yo id ex")
+
+	// commonMistakes are real but rare words that are almost always a mistake in technical writing,
+	// such as cant for can't
+	commonMistakes = strings.Fields("cant wont wether")
+
+	// iseEnding matches British -ise spellings, which the tool accepts from their -ize forms
+	iseEnding = regexp.MustCompile(`^(.{3,})is(e|es|ed|ing|er|ers|ation|ations|able)$`)
+)
+
+func main() {
+	vocab, err := readVocabulary()
+	if err != nil {
+		log.Fatalf("reading the BERT vocabulary: %v", err)
+	}
+	headwords, err := readDictionary()
+	if err != nil {
+		log.Fatalf("reading the Hunspell dictionary: %v", err)
+	}
+	extra, err := os.ReadFile("extra_words.txt")
+	if err != nil {
+		log.Fatalf("reading extra_words.txt: %v", err)
+	}
+
+	var words []string
+	seen := map[string]bool{}
+	add := func(word string) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	stems := slices.Collect(maps.Keys(headwords))
+	slices.Sort(stems)
+	for _, word := range slices.Concat(vocab, stems) {
+		if wordToken.MatchString(word) || slices.Contains(twoLetterWords, word) {
+			add(word)
+		}
+	}
+	for line := range strings.Lines(string(extra)) {
+		if !strings.HasPrefix(line, "#") {
+			for _, word := range strings.Fields(line) {
+				add(strings.ToLower(word))
+			}
+		}
+	}
+
+	// Spellings that differ between British and American English come from murican_to_english
+	dictionaries, err := converter.LoadDictionaries()
+	if err != nil {
+		log.Fatalf("loading the murican_to_english dictionary: %v", err)
+	}
+	variants := map[string]bool{}
+	for american, british := range dictionaries.AmericanToBritish {
+		variants[strings.ToLower(american)] = true
+		variants[strings.ToLower(british)] = true
+	}
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	for _, word := range words {
+		if variants[word] || slices.Contains(commonMistakes, word) {
+			continue
+		}
+		if match := iseEnding.FindStringSubmatch(word); match != nil && seen[match[1]+"iz"+match[2]] {
+			continue
+		}
+		out.WriteString(word + "\n")
+	}
+	if err := os.WriteFile("words.txt", out.Bytes(), 0600); err != nil {
+		log.Fatalf("writing words.txt: %v", err)
+	}
+
+	out.Reset()
+	out.WriteString(headwordsHeader)
+	for _, stem := range stems {
+		if !wordToken.MatchString(stem) && !slices.Contains(twoLetterWords, stem) {
+			continue
+		}
+		out.WriteString(stem)
+		if flags := headwords[stem]; flags != "" {
+			out.WriteString("/" + flags)
+		}
+		out.WriteString("\n")
+	}
+	if err := os.WriteFile("headwords.txt", out.Bytes(), 0600); err != nil {
+		log.Fatalf("writing headwords.txt: %v", err)
+	}
+}
+
+// moduleFile returns the contents of a file in a module version
+func moduleFile(module, name string) ([]byte, error) {
+	output, err := exec.Command("go", "mod", "download", "-json", module).Output()
+	if err != nil {
+		return nil, err
+	}
+	var downloaded struct{ Dir string }
+	if err := json.Unmarshal(output, &downloaded); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(downloaded.Dir, name))
+}
+
+// readVocabulary returns the BERT vocabulary in its order
+func readVocabulary() ([]string, error) {
+	data, err := moduleFile(vocabModule, vocabFile)
+	if err != nil {
+		return nil, err
+	}
+	var tokenizer struct {
+		Model struct {
+			Vocab map[string]int `json:"vocab"`
+		} `json:"model"`
+	}
+	if err := json.Unmarshal(data, &tokenizer); err != nil {
+		return nil, err
+	}
+	vocab := tokenizer.Model.Vocab
+	return slices.SortedFunc(maps.Keys(vocab), func(a, b string) int { return vocab[a] - vocab[b] }), nil
+}
+
+// readDictionary returns the stems of a Hunspell dictionary, without the word count on its first line,
+// with the headwordFlags of all their entries in headwordFlags order
+func readDictionary() (map[string]string, error) {
+	data, err := moduleFile(dictionaryModule, dictionaryFile)
+	if err != nil {
+		return nil, err
+	}
+	stems := map[string]string{}
+	for i, line := range strings.Split(string(data), "\n") {
+		stem, flags, _ := strings.Cut(strings.TrimSpace(line), "/")
+		if i == 0 || stem == "" {
+			continue
+		}
+		var kept strings.Builder
+		for _, flag := range headwordFlags {
+			if strings.ContainsRune(flags, flag) || strings.ContainsRune(stems[stem], flag) {
+				kept.WriteRune(flag)
+			}
+		}
+		stems[stem] = kept.String()
+	}
+	return stems, nil
+}
@@ -0,0 +1,179 @@
+package proofread
+
+import (
+	"regexp"
+	"strings"
+)
+
+// masked replaces text that is not prose. It is not a space, so words either side of masked text
+// are not treated as neighbours, and it keeps every position in the line unchanged.
+const masked = '\x00'
+
+var (
+	// fence opens or closes a fenced code block
+	fence = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	// listItem starts a bullet or numbered list item
+	listItem = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+	// referenceDefinition defines a reference-style link target
+	referenceDefinition = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*\S`)
+
+	// markdownSpans are inline Markdown that is not prose: link targets, reference labels, HTML
+	// tags, autolinks, heading IDs and entities
+	markdownSpans = []*regexp.Regexp{
+		regexp.MustCompile(`\]\([^)]*\)`),
+		regexp.MustCompile(`\]\[[^\]]*\]`),
+		regexp.MustCompile(`</?[A-Za-z][^>]*>`),
+		regexp.MustCompile(`<[a-z]+:[^>\s]+>`),
+		regexp.MustCompile(`\{#[^}]*\}`),
+		regexp.MustCompile(`&#?\w+;`),
+	}
+
+	// textSpans are URLs and email addresses, masked in plain text as well as Markdown
+	textSpans = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:https?|ftp|file|ssh|git)://[^\s<>()\[\]]+`),
+		regexp.MustCompile(`(?i)\bwww\.[^\s<>()\[\]]+`),
+		regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`),
+	}
+)
+
+// maskMarkdown masks front matter, code blocks, HTML comments, inline code and other Markdown that
+// is not prose, returning lines of the same lengths
+func maskMarkdown(lines []string) []string {
+	out := make([]string, len(lines))
+	var fenceMarker string
+	inComment, prevBlank, prevCode := false, true, false
+	lastText := ""
+	start := 0
+	if len(lines) > 0 && (lines[0] == "---" || lines[0] == "+++") {
+		for i := 1; i < len(lines); i++ {
+			if lines[i] == lines[0] || (lines[0] == "---" && lines[i] == "...") {
+				for j := range i + 1 {
+					out[j] = mask(lines[j])
+				}
+				start = i + 1
+				break
+			}
+		}
+	}
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		blank := strings.TrimSpace(line) == ""
+		switch {
+		case fenceMarker != "":
+			out[i] = mask(line)
+			if match := fence.FindStringSubmatch(line); match != nil && match[1][0] == fenceMarker[0] && len(match[1]) >= len(fenceMarker) && strings.TrimSpace(line[len(match[0]):]) == "" {
+				fenceMarker = ""
+			}
+			continue
+		case fence.MatchString(line):
+			fenceMarker = fence.FindStringSubmatch(line)[1]
+			out[i] = mask(line)
+			continue
+		case !blank && isIndented(line) && (prevCode || prevBlank && !listItem.MatchString(lastText) && !isIndented(lastText)):
+			// An indented code block, which in a list would be a continuation paragraph
+			out[i] = mask(line)
+			prevCode, prevBlank = true, false
+			continue
+		case referenceDefinition.MatchString(line):
+			out[i] = mask(line)
+			prevCode, prevBlank = false, false
+			continue
+		}
+		line, inComment = maskComments(line, inComment)
+		line = maskInlineCode(line)
+		for _, span := range markdownSpans {
+			line = maskMatches(span, line)
+		}
+		out[i] = maskText(line)
+		if !blank {
+			lastText = lines[i]
+		}
+		prevCode, prevBlank = false, blank
+	}
+	return out
+}
+
+// maskText masks URLs and email addresses
+func maskText(line string) string {
+	for _, span := range textSpans {
+		line = maskMatches(span, line)
+	}
+	return line
+}
+
+// maskComments masks HTML comments, which may span lines, reporting whether one is still open at
+// the end of the line
+func maskComments(line string, open bool) (string, bool) {
+	var b strings.Builder
+	for line != "" {
+		if open {
+			end := strings.Index(line, "-->")
+			if end < 0 {
+				b.WriteString(mask(line))
+				return b.String(), true
+			}
+			b.WriteString(mask(line[:end+3]))
+			line, open = line[end+3:], false
+			continue
+		}
+		begin := strings.Index(line, "<!--")
+		if begin < 0 {
+			b.WriteString(line)
+			break
+		}
+		b.WriteString(line[:begin])
+		line, open = line[begin:], true
+	}
+	return b.String(), open
+}
+
+// maskInlineCode masks code spans, which open and close with backtick runs of the same length
+func maskInlineCode(line string) string {
+	b := []byte(line)
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(b) && b[i+run] == '`' {
+			run++
+		}
+		end := strings.Index(string(b[i+run:]), strings.Repeat("`", run))
+		if end < 0 {
+			i += run
+			continue
+		}
+		end += i + 2*run
+		for j := i; j < end; j++ {
+			b[j] = masked
+		}
+		i = end
+	}
+	return string(b)
+}
+
+// maskMatches masks each match of re in line
+func maskMatches(re *regexp.Regexp, line string) string {
+	matches := re.FindAllStringIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+	b := []byte(line)
+	for _, m := range matches {
+		for j := m[0]; j < m[1]; j++ {
+			b[j] = masked
+		}
+	}
+	return string(b)
+}
+
+// mask replaces every byte of s
+func mask(s string) string {
+	return strings.Repeat(string(masked), len(s))
+}
+
+// isIndented reports whether a line is indented enough to be code: four spaces or a tab
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
@@ -0,0 +1,395 @@
+package proofread
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LanguageEnvVar sets the default language
+	LanguageEnvVar = "PROOFREAD_LANGUAGE"
+
+	defaultLanguage  = "en-GB"
+	defaultMaxIssues = 100
+	maxIssuesLimit   = 1000
+	// maxFiles is the most files checked in a directory
+	maxFiles = 500
+	// maxFileBytes is the largest file checked
+	maxFileBytes = 1024 * 1024
+)
+
+var (
+	// checks are the kinds of checking available
+	checks = []string{"spelling", "variants", "grammar"}
+
+	// markdownExtensions are checked as Markdown, and textExtensions as plain text
+	markdownExtensions = []string{".md", ".markdown", ".mdx"}
+	textExtensions     = []string{".txt", ".rst", ".adoc"}
+
+	// skippedDirs are dependency and build output directories not checked
+	skippedDirs = []string{"node_modules", "vendor", "dist", "build", "target", "__pycache__", "venv", "coverage"}
+)
+
+// ProofreadTool checks spelling and grammar in documentation
+type ProofreadTool struct{}
+
+// init registers the proofread tool
+func init() {
+	registry.Register(&ProofreadTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ProofreadTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"proofread",
+		mcp.WithDescription(`Check spelling and common grammar mistakes in documentation, returning each issue with its line, column and suggested fixes.
+
+Checks British (en-GB) or American (en-US) spelling, reporting the other language's spellings separately, and uses project dictionaries (.proofread-words, cspell.json) for project terms. Markdown is understood: code blocks, inline code, links, URLs and front matter are not checked.
+
+Read-only: reports issues without changing files. Use murican_to_english to convert American spellings in place.`),
+		mcp.WithString("text",
+			mcp.Description("Text to check, instead of path"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of a file to check, or a directory whose Markdown and text files are checked"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Spelling to check against: en-GB or en-US (default: en-GB, or "+LanguageEnvVar+")"),
+			mcp.Enum("en-GB", "en-US"),
+		),
+		mcp.WithString("format",
+			mcp.Description("How to read the text: markdown or text (default: markdown, except for .txt, .rst and .adoc files)"),
+			mcp.Enum("markdown", "text"),
+		),
+		mcp.WithString("checks",
+			mcp.Description("Comma-separated checks: spelling, variants (the other language's spellings), grammar (default: all)"),
+		),
+		mcp.WithString("words",
+			mcp.Description("Comma-separated extra words to accept, such as product names"),
+		),
+		mcp.WithString("dictionary",
+			mcp.Description("Absolute path of a word list (one word per line) or cspell.json to accept words from, as well as any project dictionaries found"),
+		),
+		mcp.WithBoolean("check_capitalised",
+			mcp.Description("Also spell check words starting with a capital, which are often names (default: false)"),
+		),
+		mcp.WithNumber("max_issues",
+			mcp.Description(fmt.Sprintf("Most issues to return (default: %d, max: %d)", defaultMaxIssues, maxIssuesLimit)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads text and files
+		mcp.WithDestructiveHintAnnotation(false), // Changes nothing
+		mcp.WithIdempotentHintAnnotation(true),   // The same text gives the same issues
+		mcp.WithOpenWorldHintAnnotation(false),   // Uses embedded dictionaries and local files only
+	)
+}
+
+// Execute checks the text or files
+func (t *ProofreadTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	text, _ := args["text"].(string)
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(text) == "" && strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("either text or path is required")
+	}
+	if text != "" && path != "" {
+		return nil, fmt.Errorf("provide text or path, not both")
+	}
+	language, _ := args["language"].(string)
+	language = cmp.Or(language, os.Getenv(LanguageEnvVar), defaultLanguage)
+	build, ok := languages[language]
+	if !ok {
+		return nil, fmt.Errorf("language must be en-GB or en-US, got: %q", language)
+	}
+	format, _ := args["format"].(string)
+	if format != "" && format != "markdown" && format != "text" {
+		return nil, fmt.Errorf("format must be markdown or text, got: %q", format)
+	}
+	selected, err := list(args, "checks", checks)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		selected = checks
+	}
+	maxIssues := defaultMaxIssues
+	if value, ok := args["max_issues"].(float64); ok {
+		if value < 1 {
+			return nil, fmt.Errorf("max_issues must be at least 1, got: %v", value)
+		}
+		maxIssues = min(int(value), maxIssuesLimit)
+	}
+	capitalised, _ := args["check_capitalised"].(bool)
+
+	result := &Result{Language: language}
+	l := &lookup{dictionary: build(), custom: map[string]bool{}, suggestions: map[string][]string{}}
+	if words, _ := args["words"].(string); words != "" {
+		for word := range strings.SplitSeq(words, ",") {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+				l.custom[word] = true
+			}
+		}
+	}
+	var dictionaries []string
+	if dictionary, _ := args["dictionary"].(string); dictionary != "" {
+		resolved, err := workspace.ResolveToolPath(ctx, "dictionary", dictionary)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(resolved); err != nil {
+			return nil, fmt.Errorf("cannot access dictionary: %w", err)
+		}
+		dictionaries = append(dictionaries, resolved)
+	}
+
+	var root string
+	var isDir bool
+	if path != "" {
+		if root, err = workspace.ResolveToolPath(ctx, "path", path); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access path: %w", err)
+		}
+		isDir = info.IsDir()
+		dir := root
+		if !isDir {
+			dir = filepath.Dir(root)
+		}
+		for _, found := range findProjectDictionaries(dir) {
+			if !slices.Contains(dictionaries, found) {
+				dictionaries = append(dictionaries, found)
+			}
+		}
+	}
+	for _, dictionary := range dictionaries {
+		words, err := readDictionary(dictionary)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Could not read the dictionary %s: %v", dictionary, err))
+			continue
+		}
+		for _, word := range words {
+			l.custom[strings.ToLower(strings.TrimSpace(word))] = true
+		}
+		result.Dictionaries = append(result.Dictionaries, dictionary)
+	}
+	logger.WithFields(logrus.Fields{"language": language, "path": root, "checks": selected}).Debug("Proofreading")
+
+	c := newChecker(l, selected, language == "en-GB", capitalised, maxIssues)
+	switch {
+	case root == "":
+		c.check("", text, format != "text")
+	case !isDir:
+		content, err := readFile(root)
+		if err != nil {
+			return nil, err
+		}
+		c.check(root, content, isMarkdown(root, format))
+		result.Files = 1
+	default:
+		files, warnings, err := findFiles(root)
+		if err != nil {
+			return nil, err
+		}
+		result.Warnings = append(result.Warnings, warnings...)
+		for _, file := range files {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			content, err := readFile(file)
+			if err != nil {
+				result.Warnings = append(result.Warnings, err.Error())
+				continue
+			}
+			relative, _ := filepath.Rel(root, file)
+			c.check(relative, content, isMarkdown(file, format))
+			result.Files++
+		}
+		if result.Files == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("No Markdown or text files (%s) were found.", strings.Join(slices.Concat(markdownExtensions, textExtensions), ", ")))
+		}
+	}
+	result.Words = c.words
+	result.Counts = c.counts
+	result.Issues = c.issues
+	if result.Issues == nil {
+		result.Issues = []Issue{}
+	}
+	if c.truncated() {
+		result.Truncated = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Only the first %d issues are listed. Raise max_issues, add project words to a dictionary or check fewer files to see the rest.", maxIssues))
+	}
+	result.Unknown = c.unknownWords()
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// The context of each issue is quoted from the input, so scan it
+	sourceCtx := security.SourceContext{
+		Tool:        "proofread",
+		ContentType: "text",
+	}
+	if root != "" {
+		sourceCtx.URL = "file://" + root
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// findFiles returns the Markdown and text files under root, leaving out hidden and dependency
+// directories, with warnings for files left out
+func findFiles(root string) ([]string, []string, error) {
+	var files, warnings []string
+	extensions := slices.Concat(markdownExtensions, textExtensions)
+	skipped := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			warnings = append(warnings, fmt.Sprintf("Could not read %s: %v", path, err))
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !slices.Contains(extensions, strings.ToLower(filepath.Ext(name))) {
+			return nil
+		}
+		if security.CheckFileAccess(path) != nil {
+			skipped++
+			return nil
+		}
+		if len(files) == maxFiles {
+			warnings = append(warnings, fmt.Sprintf("Only the first %d files were checked. Check a subdirectory to see the rest.", maxFiles))
+			return filepath.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if skipped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files were not checked because the security policy denies access to them.", skipped))
+	}
+	return files, warnings, nil
+}
+
+// readFile reads a file to check, refusing large ones
+func readFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if info.Size() > maxFileBytes {
+		return "", fmt.Errorf("%s was not checked because it is larger than %d MB", path, maxFileBytes/1024/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// isMarkdown reports whether a file is checked as Markdown, which it is unless it has a plain text
+// extension or format says otherwise
+func isMarkdown(path, format string) bool {
+	if format != "" {
+		return format == "markdown"
+	}
+	return !slices.Contains(textExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// list reads a comma-separated parameter whose entries must be among allowed
+func list(args map[string]any, key string, allowed []string) ([]string, error) {
+	raw, _ := args[key].(string)
+	var values []string
+	for value := range strings.SplitSeq(raw, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(allowed, value) {
+			return nil, fmt.Errorf("unknown %s entry %q: must be one of %s", key, value, strings.Join(allowed, ", "))
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the proofread tool
+func (t *ProofreadTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Before committing or publishing documentation, READMEs, changelogs or release notes, and after converting documents with process_document. Finding typos across a docs directory, or American spellings in a British English project.",
+		WhenNotToUse: "Converting American spellings in place - use murican_to_english. Checking code identifiers or comments in source files - the dictionary is for prose. Markdown structure, links and front matter - use markdown.",
+		CommonPatterns: []string{
+			"Check a docs directory, then add the project's own terms from unknown to a .proofread-words file at the repository root",
+			"Check a single file with checks='spelling' while writing",
+			"Use checks='variants' with language='en-GB' to find American spellings before running murican_to_english",
+			"Check a draft before writing it to a file by passing it as text",
+		},
+		ParameterDetails: map[string]string{
+			"language":          "en-GB and en-US share one dictionary. Words spelt differently come from the murican_to_english dictionary: the other language's spellings are variant issues, not spelling issues. Spellings that are correct in both in some senses, such as license (the British verb) and towards, are not reported.",
+			"checks":            "spelling: words not in the dictionary, with suggestions one or two letters away. variants: the other language's spellings. grammar: repeated words (the the), a/an before vowel and consonant sounds, and common mistakes such as 'could of' and 'alot'.",
+			"dictionary":        "Project dictionaries are found automatically: .proofread-words (one word per line, # for comments), cspell.json and .cspell.json (words and ignoreWords) in the checked file's directory and its parents, up to the repository root.",
+			"check_capitalised": "Capitalised words are usually names, so they are skipped unless this is set. Variants are reported in any case.",
+			"format":            "Markdown checking skips front matter, fenced and indented code blocks, inline code, link targets, HTML tags and comments, URLs and email addresses. Words with digits, underscores, paths, dotted names, camelCase and acronyms are skipped in both formats.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Check a project's documentation",
+				Arguments:      map[string]any{"path": "/home/user/project/docs"},
+				ExpectedResult: "Issues in each Markdown and text file with line, column and suggestions, counts by kind, and the unknown words by frequency",
+			},
+			{
+				Description:    "Check a draft in American English",
+				Arguments:      map[string]any{"text": "The the colour of the button is configurable.", "language": "en-US"},
+				ExpectedResult: "A grammar issue for the repeated word and a variant issue suggesting color",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Many project terms are reported as spelling issues",
+				Solution: "Add the words in unknown to a .proofread-words file at the repository root, or pass them in words.",
+			},
+			{
+				Problem:  "Code is being spell checked",
+				Solution: "Put code in fenced code blocks or inline code, or use format='markdown' for files without a Markdown extension.",
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package proofread
+
+// Result lists the issues found in the text or files checked
+type Result struct {
+	// Language is the spelling checked against: en-GB or en-US
+	Language string `json:"language"`
+	// Files is the number of files checked, when a path was given
+	Files int `json:"files,omitempty"`
+	// Dictionaries are the project dictionaries whose words were accepted
+	Dictionaries []string `json:"dictionaries,omitempty"`
+	Words        int      `json:"words"`
+	// Counts is the number of issues of each kind, including any left out of Issues
+	Counts    map[string]int `json:"counts"`
+	Issues    []Issue        `json:"issues"`
+	Truncated bool           `json:"truncated,omitempty"`
+	// Unknown lists each misspelt word with how often it appears, most frequent first, to help
+	// build a project dictionary
+	Unknown  []UnknownWord `json:"unknown,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// Issue is a possible mistake at a position in the text
+type Issue struct {
+	File string `json:"file,omitempty"`
+	// Line and Column are 1-based, with Column counted in characters
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Kind is spelling, variant (the other language's spelling) or grammar
+	Kind    string `json:"kind"`
+	Text    string `json:"text"`
+	Message string `json:"message"`
+	// Suggestions are replacements for Text, best first
+	Suggestions []string `json:"suggestions,omitempty"`
+	Context     string   `json:"context"`
+}
+
+// UnknownWord is a word not in the dictionary
+type UnknownWord struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
@@ -7,10 +7,15 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
+// authRequestTimeout limits each request to the authorisation server
+const authRequestTimeout = 30 * time.Second
+
 // ServerMetadata holds OAuth authorisation server metadata.
 type ServerMetadata struct {
 	Issuer                            string   `json:"issuer"`
@@ -24,6 +29,11 @@ type ServerMetadata struct {
 	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
 }
 
+// authHTTPClient returns the client used for requests to the authorisation server
+func authHTTPClient() *http.Client {
+	return httpclient.NewHTTPClientWithProxy(authRequestTimeout)
+}
+
 // FetchServerMetadata fetches OAuth authorisation server metadata.
 // Follows RFC 8414 and MCP spec for discovery.
 func FetchServerMetadata(ctx context.Context, serverURL string) (*ServerMetadata, error) {
@@ -66,7 +76,7 @@ func fetchMetadataFromURL(ctx context.Context, metadataURL string) (*ServerMetad
 	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := authHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +168,7 @@ func (p *Provider) RefreshToken(ctx context.Context) error {
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := authHTTPClient().Do(req)
 	if err != nil {
 		logrus.WithError(err).Error("auth: refresh request failed")
 		return err
@@ -321,7 +321,7 @@ func (p *Provider) registerClient(ctx context.Context, metadata *ServerMetadata)
 	req.Header.Set("Content-Type", "application/json")
 
 	logrus.Debug("auth: sending registration request")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := authHTTPClient().Do(req)
 	if err != nil {
 		logrus.WithError(err).Error("auth: registration request failed")
 		return nil, err
@@ -432,7 +432,7 @@ func (p *Provider) ExchangeCode(ctx context.Context, code string) error {
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := authHTTPClient().Do(req)
 	if err != nil {
 		logrus.WithError(err).Error("auth: token exchange request failed")
 		return err
@@ -93,3 +93,17 @@ func (pm *ProxyManager) IsInitialised() bool {
 	defer pm.initialiseMu.Unlock()
 	return pm.initialised
 }
+
+// Close closes all upstream connections, stopping any stdio upstream processes. It does not wait for
+// an initialisation in progress, which may be waiting on an OAuth flow.
+func (pm *ProxyManager) Close() error {
+	if !pm.initialiseMu.TryLock() {
+		return nil
+	}
+	defer pm.initialiseMu.Unlock()
+
+	if pm.manager == nil {
+		return nil
+	}
+	return pm.manager.Close()
+}
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+// upstreamCheckTimeout bounds how long each upstream is given to respond
+const upstreamCheckTimeout = 10 * time.Second
+
+// CheckPrerequisites reports whether the proxy is configured and each upstream can be reached. Any
+// HTTP response counts as reachable, since upstreams may reject requests without a session or token.
+func (t *ProxyTool) CheckPrerequisites(ctx context.Context) []tools.PrerequisiteCheck {
+	config, err := ParseConfig()
+	if err != nil {
+		return []tools.PrerequisiteCheck{{
+			Name:        "configuration",
+			Status:      tools.PrerequisiteFailed,
+			Detail:      err.Error(),
+			Remediation: "Set PROXY_URL to an upstream MCP server URL, or PROXY_UPSTREAMS to a JSON array of upstreams",
+		}}
+	}
+
+	client := httpclient.NewHTTPClientWithProxy(upstreamCheckTimeout)
+	checks := make([]tools.PrerequisiteCheck, 0, len(config.Upstreams))
+	for _, upstream := range config.Upstreams {
+		if upstream.IsStdio() {
+			checks = append(checks, checkStdioUpstream(upstream.Name, upstream.Command))
+			continue
+		}
+		checks = append(checks, checkUpstream(ctx, client, upstream.Name, upstream.URL))
+	}
+	return checks
+}
+
+// checkStdioUpstream confirms a stdio upstream's command can be found. The process is not started, as
+// starting some servers has side effects.
+func checkStdioUpstream(name, command string) tools.PrerequisiteCheck {
+	check := tools.PrerequisiteCheck{Name: "upstream " + name}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = fmt.Sprintf("command %s not found", command)
+		check.Remediation = "Install the upstream server, or correct its command in PROXY_UPSTREAMS"
+		return check
+	}
+	check.Status = tools.PrerequisiteOK
+	check.Detail = "runs " + path + " over stdio"
+	return check
+}
+
+// checkUpstream makes a request to an upstream to confirm it can be reached
+func checkUpstream(ctx context.Context, client *http.Client, name, rawURL string) tools.PrerequisiteCheck {
+	check := tools.PrerequisiteCheck{Name: "upstream " + name}
+
+	// Only the scheme and host are shown, as URLs may carry credentials
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = fmt.Sprintf("invalid URL: %v", err)
+		check.Remediation = "Correct the upstream URL in PROXY_URL or PROXY_UPSTREAMS"
+		return check
+	}
+	host := parsed.Scheme + "://" + parsed.Host
+
+	requestCtx, cancel := context.WithTimeout(ctx, upstreamCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = fmt.Sprintf("invalid URL: %v", err)
+		check.Remediation = "Correct the upstream URL in PROXY_URL or PROXY_UPSTREAMS"
+		return check
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Drop the URL from the error, as it may carry credentials
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			err = urlErr.Err
+		}
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = fmt.Sprintf("%s cannot be reached: %v", host, err)
+		check.Remediation = "Check the upstream is running and reachable from this machine, and HTTPS_PROXY if you use a proxy"
+		return check
+	}
+	_ = resp.Body.Close()
+
+	check.Status = tools.PrerequisiteOK
+	check.Detail = fmt.Sprintf("%s responded with HTTP %d", host, resp.StatusCode)
+	return check
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/diagnostics"
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/telemetry"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/aggregator"
 	"github.com/sirupsen/logrus"
 )
 
@@ -101,15 +104,7 @@ func RegisterUpstreamTools(ctx context.Context, fastPath bool) bool {
 	registered := 0
 	for i := range tools {
 		tool := &tools[i]
-		dynamicTool := &DynamicProxyTool{
-			toolName:         tool.Name,
-			originalToolName: tool.OriginalName,
-			upstreamName:     tool.UpstreamName,
-			description:      tool.Description,
-			inputSchema:      tool.InputSchema,
-			manager:          manager,
-		}
-		registry.RegisterProxiedTool(dynamicTool)
+		registry.RegisterProxiedTool(newDynamicProxyTool(tool, manager))
 		registered++
 
 		logger.WithFields(logrus.Fields{
@@ -133,8 +128,10 @@ func RegisterUpstreamTools(ctx context.Context, fastPath bool) bool {
 // notifications to connected clients when tools are added via AddTool().
 //
 // This avoids blocking server startup while still making upstream tools available as
-// soon as the connection succeeds.
-func RegisterUpstreamToolsAsync(ctx context.Context, mcpSrv *mcpserver.MCPServer, mainLogger *logrus.Logger, transport string) {
+// soon as the connection succeeds. cached names the tools RegisterCachedUpstreamTools advertised
+// from the last run, which are removed if the upstreams no longer offer them, and the tool list is
+// cached for the next run.
+func RegisterUpstreamToolsAsync(ctx context.Context, mcpSrv *mcpserver.MCPServer, mainLogger *logrus.Logger, transport string, cached []string) {
 	// Quick pre-checks before spawning goroutine (avoid needless goroutines)
 	if !isProxyEnabled() {
 		mainLogger.Debug("Proxy: not enabled in ENABLE_ADDITIONAL_TOOLS, skipping async upstream registration")
@@ -169,14 +166,7 @@ func RegisterUpstreamToolsAsync(ctx context.Context, mcpSrv *mcpserver.MCPServer
 		registered := 0
 		for i := range upstreamTools {
 			tool := &upstreamTools[i]
-			dynamicTool := &DynamicProxyTool{
-				toolName:         tool.Name,
-				originalToolName: tool.OriginalName,
-				upstreamName:     tool.UpstreamName,
-				description:      tool.Description,
-				inputSchema:      tool.InputSchema,
-				manager:          manager,
-			}
+			dynamicTool := newDynamicProxyTool(tool, manager)
 
 			// Register in our internal registry (for GetTool lookups)
 			registry.RegisterProxiedTool(dynamicTool)
@@ -212,9 +202,7 @@ func RegisterUpstreamToolsAsync(ctx context.Context, mcpSrv *mcpserver.MCPServer
 				telemetry.EndToolSpan(span, err)
 
 				if err != nil {
-					if errorLogger := tools.GetGlobalErrorLogger(); errorLogger != nil && errorLogger.IsEnabled() {
-						errorLogger.LogToolError(name, args, err, transport)
-					}
+					tools.GetGlobalErrorLogger().LogToolError(name, args, err, transport, diagnostics.SessionID(toolCtx))
 					return nil, fmt.Errorf("tool execution failed: %w", err)
 				}
 				return result, nil
@@ -227,9 +215,26 @@ func RegisterUpstreamToolsAsync(ctx context.Context, mcpSrv *mcpserver.MCPServer
 			}).Info("Proxy: async-registered upstream tool on running server")
 		}
 
+		// Withdraw cached tools the upstreams no longer offer
+		var stale []string
+		for _, name := range cached {
+			if !slices.ContainsFunc(upstreamTools, func(tool aggregator.AggregatedTool) bool { return tool.Name == name }) {
+				registry.UnregisterProxiedTool(name)
+				stale = append(stale, name)
+			}
+		}
+		if len(stale) > 0 {
+			mcpSrv.DeleteTools(stale...)
+		}
+
+		if err := saveToolList(upstreamTools); err != nil {
+			logger.WithError(err).Debug("Proxy: failed to cache upstream tool list")
+		}
+
 		logger.WithFields(logrus.Fields{
 			"count":      len(upstreamTools),
 			"registered": registered,
+			"withdrawn":  len(stale),
 		}).Info("Proxy: async upstream tool registration complete")
 	}()
 }
@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/aggregator"
+	"github.com/sirupsen/logrus"
+)
+
+// toolListPath returns the file the upstream tool list is cached in. The name is derived from the
+// upstream configuration, so a changed configuration never advertises another upstream's tools.
+func toolListPath() (string, error) {
+	config, err := ParseConfig()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(os.Getenv("PROXY_UPSTREAMS") + "\n" + os.Getenv("PROXY_URL")))
+	return filepath.Join(config.CacheDir, fmt.Sprintf("tools-%x.json", sum[:8])), nil
+}
+
+// saveToolList caches the upstream tool list, so the next start can advertise it straight away
+func saveToolList(tools []aggregator.AggregatedTool) error {
+	path, err := toolListPath()
+	if err != nil {
+		return err
+	}
+	if err := EnsureCacheDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return fmt.Errorf("failed to encode upstream tool list: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write upstream tool list: %w", err)
+	}
+	return nil
+}
+
+// loadToolList reads the upstream tool list cached by an earlier run
+func loadToolList() ([]aggregator.AggregatedTool, error) {
+	path, err := toolListPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tools []aggregator.AggregatedTool
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return nil, fmt.Errorf("failed to parse upstream tool list: %w", err)
+	}
+	return tools, nil
+}
+
+// RegisterCachedUpstreamTools registers the upstream tools found by the last run, so they are
+// advertised as soon as the server starts rather than once the upstreams have connected, which can
+// wait on an OAuth flow. Connecting is left to RegisterUpstreamToolsAsync or the first call to one
+// of the tools. It returns the names of the tools registered.
+func RegisterCachedUpstreamTools(logger *logrus.Logger) []string {
+	if !isProxyEnabled() || (os.Getenv("PROXY_UPSTREAMS") == "" && os.Getenv("PROXY_URL") == "") {
+		return nil
+	}
+	upstreamTools, err := loadToolList()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.WithError(err).Debug("Proxy: could not read cached upstream tool list")
+		}
+		return nil
+	}
+
+	manager := GetGlobalProxyManager()
+	names := make([]string, 0, len(upstreamTools))
+	for i := range upstreamTools {
+		registry.RegisterProxiedTool(newDynamicProxyTool(&upstreamTools[i], manager))
+		names = append(names, upstreamTools[i].Name)
+	}
+	logger.WithField("count", len(names)).Debug("Proxy: registered cached upstream tools")
+	return names
+}
+
+// newDynamicProxyTool exposes an upstream tool as a tool of its own
+func newDynamicProxyTool(tool *aggregator.AggregatedTool, manager *ProxyManager) *DynamicProxyTool {
+	return &DynamicProxyTool{
+		toolName:         tool.Name,
+		originalToolName: tool.OriginalName,
+		upstreamName:     tool.UpstreamName,
+		description:      tool.Description,
+		inputSchema:      tool.InputSchema,
+		manager:          manager,
+	}
+}
@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/aggregator"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRegisterCachedUpstreamTools(t *testing.T) {
+	t.Setenv("ENABLE_ADDITIONAL_TOOLS", "proxy")
+	t.Setenv("PROXY_CACHE_DIR", t.TempDir())
+	t.Setenv("PROXY_UPSTREAMS", `[{"name":"docs","url":"https://docs.example.com/mcp"}]`)
+	tools.ResetEnabledToolsCache()
+	t.Cleanup(tools.ResetEnabledToolsCache)
+	logger := logrus.New()
+
+	if names := RegisterCachedUpstreamTools(logger); len(names) != 0 {
+		t.Fatalf("expected no tools before the list is cached, got %v", names)
+	}
+
+	cached := []aggregator.AggregatedTool{{
+		Name:         "docs:search",
+		OriginalName: "search",
+		UpstreamName: "docs",
+		Description:  "Search the docs",
+		InputSchema:  map[string]any{"type": "object"},
+	}}
+	if err := saveToolList(cached); err != nil {
+		t.Fatalf("failed to save tool list: %v", err)
+	}
+	names := RegisterCachedUpstreamTools(logger)
+	if len(names) != 1 || names[0] != "docs:search" {
+		t.Fatalf("expected the cached tool to be registered, got %v", names)
+	}
+	tool, ok := registry.GetTool("docs:search")
+	if !ok {
+		t.Fatal("cached tool is not in the registry")
+	}
+	if description := tool.Definition().Description; description != "Search the docs" {
+		t.Errorf("unexpected description %q", description)
+	}
+
+	registry.UnregisterProxiedTool("docs:search")
+	if _, ok := registry.GetTool("docs:search"); ok {
+		t.Error("withdrawn tool is still in the registry")
+	}
+
+	// A list cached for other upstreams is not used
+	t.Setenv("PROXY_UPSTREAMS", `[{"name":"other","url":"https://other.example.com/mcp"}]`)
+	if names := RegisterCachedUpstreamTools(logger); len(names) != 0 {
+		t.Errorf("expected no tools for a changed configuration, got %v", names)
+	}
+}
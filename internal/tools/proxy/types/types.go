@@ -14,13 +14,21 @@ import (
 type UpstreamConfig struct {
 	Name         string            `json:"name"`
 	URL          string            `json:"url"`
-	Transport    string            `json:"transport"` // http-first, sse-first, http-only, sse-only
+	Transport    string            `json:"transport"`      // http-first, sse-first, http-only, sse-only, stdio
+	Command      string            `json:"command"`        // executable for a stdio upstream, instead of url
+	Args         []string          `json:"args,omitempty"` // arguments for the stdio upstream's command
+	Env          map[string]string `json:"env,omitempty"`  // extra environment for the stdio upstream
 	OAuth        *OAuthConfig      `json:"oauth,omitempty"`
 	Headers      map[string]string `json:"headers,omitempty"`
 	IgnoreTools  []string          `json:"ignore_tools,omitempty"`
 	IncludeTools []string          `json:"include_tools,omitempty"`
 }
 
+// IsStdio reports whether the upstream is a child process spoken to over stdio rather than a URL.
+func (u *UpstreamConfig) IsStdio() bool {
+	return u.Command != ""
+}
+
 // OAuthConfig holds OAuth-specific configuration.
 type OAuthConfig struct {
 	ClientID     string `json:"client_id,omitempty"`
@@ -74,9 +82,23 @@ func (c *ProxyConfig) Validate() error {
 		}
 		seenNames[upstream.Name] = true
 
+		// Stdio upstreams are spawned from a command rather than reached at a URL
+		if upstream.IsStdio() {
+			if upstream.URL != "" {
+				return fmt.Errorf("upstream %s: set either url or command, not both", upstream.Name)
+			}
+			if upstream.Transport != "" && upstream.Transport != "stdio" {
+				return fmt.Errorf("upstream %s: transport must be stdio or unset for a command", upstream.Name)
+			}
+			continue
+		}
+		if upstream.Transport == "stdio" {
+			return fmt.Errorf("upstream %s: command is required for the stdio transport", upstream.Name)
+		}
+
 		// Check URL is present
 		if upstream.URL == "" {
-			return fmt.Errorf("upstream %d: URL is required", i)
+			return fmt.Errorf("upstream %d: URL or command is required", i)
 		}
 
 		// Check URL scheme
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sammcj/mcp-devtools/internal/credentials"
 	"github.com/sammcj/mcp-devtools/internal/tools/proxy/auth"
 	"github.com/sammcj/mcp-devtools/internal/tools/proxy/types"
 	"github.com/sirupsen/logrus"
@@ -36,6 +37,11 @@ type ToolInfo struct {
 
 // NewConnection creates a new upstream connection.
 func NewConnection(config *types.UpstreamConfig, cacheDir string, callbackPort int) (*Connection, error) {
+	// Stdio upstreams are local processes, so need no OAuth
+	if config.IsStdio() {
+		return &Connection{config: config, cacheDir: cacheDir}, nil
+	}
+
 	// Create OAuth provider
 	serverHash := types.UpstreamHash(config)
 
@@ -45,6 +51,12 @@ func NewConnection(config *types.UpstreamConfig, cacheDir string, callbackPort i
 			ClientID:     config.OAuth.ClientID,
 			ClientSecret: config.OAuth.ClientSecret,
 		}
+		// Fall back to a client secret stored with `mcp-devtools credentials set`
+		if staticClientInfo.ClientSecret == "" {
+			if secret, err := credentials.Get(credentials.ProxyClientSecret(config.Name)); err == nil {
+				staticClientInfo.ClientSecret = secret
+			}
+		}
 	}
 
 	authProvider := auth.NewProvider(&auth.ProviderConfig{
@@ -73,6 +85,10 @@ func (c *Connection) Connect(ctx context.Context) error {
 		return nil
 	}
 
+	if c.config.IsStdio() {
+		return c.connectStdio(ctx)
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"name": c.config.Name,
 		"url":  c.config.URL,
@@ -174,6 +190,29 @@ func (c *Connection) Connect(ctx context.Context) error {
 	return fmt.Errorf("failed to connect: %w", err)
 }
 
+// connectStdio starts a stdio upstream's process, which is restarted if it crashes. The caller must
+// hold connMu.
+func (c *Connection) connectStdio(ctx context.Context) error {
+	logrus.WithFields(logrus.Fields{
+		"name":    c.config.Name,
+		"command": c.config.Command,
+	}).Info("starting stdio upstream server")
+
+	transport := NewStdioTransport(&StdioConfig{
+		Name:    c.config.Name,
+		Command: c.config.Command,
+		Args:    c.config.Args,
+		Env:     c.config.Env,
+	})
+	if err := transport.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	c.transport = transport
+	c.connected = true
+	return nil
+}
+
 // authenticateAndConnect performs OAuth authentication and connects.
 func (c *Connection) authenticateAndConnect(ctx context.Context, useSSE bool, transportConfig *Config) error {
 	// Start callback server
@@ -232,6 +271,9 @@ func (c *Connection) authenticateAndConnect(ctx context.Context, useSSE bool, tr
 
 // Port returns the OAuth callback port (needed for auth provider access).
 func (c *Connection) Port() int {
+	if c.authProvider == nil {
+		return 0
+	}
 	return c.authProvider.Port()
 }
 
@@ -335,6 +377,8 @@ func (c *Connection) ExecuteTool(ctx context.Context, toolName string, args map[
 		transportType = "HTTP"
 	} else if _, ok := c.transport.(*SSETransport); ok {
 		transportType = "SSE"
+	} else if _, ok := c.transport.(*StdioTransport); ok {
+		transportType = "stdio"
 	}
 
 	logrus.WithFields(logrus.Fields{
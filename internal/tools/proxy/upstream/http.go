@@ -1,18 +1,23 @@
 package upstream
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 
-	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
+// maxEventSize is the largest event read from a response event stream
+const maxEventSize = 10 * 1024 * 1024
+
 // HTTPTransport implements the Streamable HTTP transport for MCP.
 type HTTPTransport struct {
 	config    *Config
@@ -24,9 +29,8 @@ type HTTPTransport struct {
 func NewHTTPTransport(cfg *Config) *HTTPTransport {
 	logrus.WithField("url", cfg.ServerURL).Debug("creating HTTP transport")
 
-	// Create HTTP client with OTEL instrumentation
-	client := &http.Client{}
-	telemetry.WrapHTTPClient(client)
+	// Responses are streamed for as long as the connection is open, so neither time nor size is limited
+	client := httpclient.New(httpclient.Options{MaxResponseBytes: -1})
 
 	return &HTTPTransport{
 		config: cfg,
@@ -111,34 +115,11 @@ func (t *HTTPTransport) SendReceive(ctx context.Context, msg *Message) (*Message
 		"bytes": len(data),
 	}).Debug("HTTP: creating POST request")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.ServerURL, bytes.NewReader(data))
-	if err != nil {
-		logrus.WithError(err).Debug("HTTP: create request failed")
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-
-	// Add custom headers
-	for k, v := range t.config.Headers {
-		req.Header.Set(k, v)
-	}
-
-	// Add authorisation header if auth provider is available
-	if t.config.AuthProvider != nil {
-		token, err := t.config.AuthProvider.GetAccessToken(ctx)
-		if err == nil && token != "" {
-			req.Header.Set("Authorization", "Bearer "+token)
-			logrus.Debug("HTTP: added authorisation header")
-		}
-	}
-
 	logrus.WithField("bytes", len(data)).Debug("HTTP: sending POST request")
-	resp, err := t.client.Do(req)
+	resp, err := t.post(ctx, data)
 	if err != nil {
 		logrus.WithError(err).Debug("HTTP: POST request failed")
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -168,6 +149,12 @@ func (t *HTTPTransport) SendReceive(ctx context.Context, msg *Message) (*Message
 		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
 	}
 
+	// The server may stream its response, sending its own requests, such as sampling, before it
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		logrus.Debug("HTTP: reading response event stream")
+		return t.readResponseStream(ctx, resp.Body, msg.ID)
+	}
+
 	logrus.Debug("HTTP: decoding response JSON")
 	// Parse response
 	var response Message
@@ -180,6 +167,101 @@ func (t *HTTPTransport) SendReceive(ctx context.Context, msg *Message) (*Message
 	return &response, nil
 }
 
+// readResponseStream reads a response event stream until the response to the request with id arrives.
+// Requests the server sends on the stream are relayed to the client making the call and answered.
+func (t *HTTPTransport) readResponseStream(ctx context.Context, body io.Reader, id any) (*Message, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	var event strings.Builder
+
+	// handleEvent returns the response to the request if the event holds it
+	handleEvent := func() *Message {
+		_, data := parseEvent(event.String())
+		event.Reset()
+		var msg Message
+		if data == "" || json.Unmarshal([]byte(data), &msg) != nil {
+			return nil
+		}
+		switch {
+		case msg.IsRequest():
+			t.answerRequest(ctx, &msg)
+		case msg.ID != nil && fmt.Sprint(msg.ID) == fmt.Sprint(id):
+			return &msg
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if response := handleEvent(); response != nil {
+				logrus.WithField("id", response.ID).Debug("HTTP: received streamed response")
+				return response, nil
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, ":") {
+			event.WriteString(line)
+			event.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read response stream: %w", err)
+	}
+	if response := handleEvent(); response != nil {
+		return response, nil
+	}
+	return nil, fmt.Errorf("response stream ended without a response")
+}
+
+// answerRequest answers a request the server sent while handling a call made with ctx, by POSTing the
+// response to the server.
+func (t *HTTPTransport) answerRequest(ctx context.Context, msg *Message) {
+	response := answerRequest(ctx, t.config.ServerURL, msg)
+	data, err := json.Marshal(response)
+	if err != nil {
+		logrus.WithError(err).Debug("HTTP: failed to marshal answer to server request")
+		return
+	}
+	resp, err := t.post(ctx, data)
+	if err != nil {
+		logrus.WithError(err).WithField("method", msg.Method).Debug("HTTP: failed to answer server request")
+		return
+	}
+	resp.Body.Close()
+}
+
+// post POSTs JSON-RPC data to the server.
+func (t *HTTPTransport) post(ctx context.Context, data []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.ServerURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	// Add custom headers
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	// Add authorisation header if auth provider is available
+	if t.config.AuthProvider != nil {
+		token, err := t.config.AuthProvider.GetAccessToken(ctx)
+		if err == nil && token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+			logrus.Debug("HTTP: added authorisation header")
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
 // Close closes the HTTP transport.
 func (t *HTTPTransport) Close() error {
 	t.closeOnce.Do(func() {
@@ -0,0 +1,154 @@
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// errNoClient is returned when an upstream request cannot be relayed because no client call is waiting
+// on the upstream.
+var errNoClient = errors.New("no client call is waiting on this upstream")
+
+// clientCapabilities are the capabilities the proxy declares to upstreams that it initialises. Sampling
+// and elicitation requests are relayed to the client that made the call the upstream is handling.
+var clientCapabilities = map[string]any{
+	"sampling":    map[string]any{},
+	"elicitation": map[string]any{},
+}
+
+// answerRequest answers a request an upstream server sends while handling a call made with ctx.
+// Sampling and elicitation requests are relayed to the client that made the call, and its answer is
+// returned to the upstream.
+func answerRequest(ctx context.Context, name string, msg *Message) *Message {
+	response := &Message{JSONRPC: "2.0", ID: msg.ID}
+
+	var result any
+	var err error
+	switch msg.Method {
+	case "ping":
+		response.Result = json.RawMessage("{}")
+		return response
+	case "roots/list":
+		response.Result = json.RawMessage(`{"roots":[]}`)
+		return response
+	case string(mcp.MethodSamplingCreateMessage):
+		result, err = relaySampling(ctx, msg.Params)
+	case string(mcp.MethodElicitationCreate):
+		result, err = relayElicitation(ctx, msg.Params)
+	default:
+		response.Error = &RPCError{Code: mcp.METHOD_NOT_FOUND, Message: "method not supported by mcp-devtools proxy: " + msg.Method}
+		return response
+	}
+
+	logger := logrus.WithFields(logrus.Fields{"name": name, "method": msg.Method})
+	if err != nil {
+		logger.WithError(err).Debug("failed to relay upstream request to client")
+		response.Error = &RPCError{Code: mcp.INTERNAL_ERROR, Message: err.Error()}
+		return response
+	}
+	if response.Result, err = json.Marshal(result); err != nil {
+		response.Error = &RPCError{Code: mcp.INTERNAL_ERROR, Message: fmt.Sprintf("failed to marshal client result: %v", err)}
+		return response
+	}
+	logger.Debug("relayed upstream request to client")
+	return response
+}
+
+// relaySampling asks the client to sample from its LLM on the upstream's behalf.
+func relaySampling(ctx context.Context, params json.RawMessage) (*mcp.CreateMessageResult, error) {
+	session, err := clientSession(ctx, func(capabilities mcp.ClientCapabilities) bool { return capabilities.Sampling != nil }, "sampling")
+	if err != nil {
+		return nil, err
+	}
+	sampler, ok := session.(mcpserver.SessionWithSampling)
+	if !ok {
+		return nil, fmt.Errorf("client does not support sampling")
+	}
+	request := mcp.CreateMessageRequest{Request: mcp.Request{Method: string(mcp.MethodSamplingCreateMessage)}}
+	if err := json.Unmarshal(params, &request.CreateMessageParams); err != nil {
+		return nil, fmt.Errorf("invalid sampling request: %w", err)
+	}
+	return sampler.RequestSampling(ctx, request)
+}
+
+// relayElicitation asks the client to collect input from its user on the upstream's behalf.
+func relayElicitation(ctx context.Context, params json.RawMessage) (*mcp.ElicitationResult, error) {
+	session, err := clientSession(ctx, func(capabilities mcp.ClientCapabilities) bool { return capabilities.Elicitation != nil }, "elicitation")
+	if err != nil {
+		return nil, err
+	}
+	elicitor, ok := session.(mcpserver.SessionWithElicitation)
+	if !ok {
+		return nil, fmt.Errorf("client does not support elicitation")
+	}
+	request := mcp.ElicitationRequest{Request: mcp.Request{Method: string(mcp.MethodElicitationCreate)}}
+	if err := json.Unmarshal(params, &request.Params); err != nil {
+		return nil, fmt.Errorf("invalid elicitation request: %w", err)
+	}
+	if err := request.Params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid elicitation request: %w", err)
+	}
+	return elicitor.RequestElicitation(ctx, request)
+}
+
+// clientSession returns the session of the client that made the call in ctx, checking that the client
+// declared the capability if it reported its capabilities.
+func clientSession(ctx context.Context, supports func(mcp.ClientCapabilities) bool, capability string) (mcpserver.ClientSession, error) {
+	if ctx == nil {
+		return nil, errNoClient
+	}
+	session := mcpserver.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, errNoClient
+	}
+	if withInfo, ok := session.(mcpserver.SessionWithClientInfo); ok && !supports(withInfo.GetClientCapabilities()) {
+		return nil, fmt.Errorf("client does not support %s", capability)
+	}
+	return session, nil
+}
+
+// activeCalls tracks the contexts of requests in flight on a transport whose upstream requests are not
+// tied to the call that caused them, so that they can be relayed to the client of the latest call.
+type activeCalls struct {
+	mu    sync.Mutex
+	next  int
+	calls map[int]context.Context
+}
+
+// add records a call made with ctx and returns a function that removes it.
+func (a *activeCalls) add(ctx context.Context) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.calls == nil {
+		a.calls = make(map[int]context.Context)
+	}
+	a.next++
+	id := a.next
+	a.calls[id] = ctx
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.calls, id)
+	}
+}
+
+// latest returns the context of the most recent call in flight made by a client, or nil if there is none.
+func (a *activeCalls) latest() context.Context {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var latest context.Context
+	latestID := 0
+	for id, ctx := range a.calls {
+		if id > latestID && mcpserver.ClientSessionFromContext(ctx) != nil {
+			latest, latestID = ctx, id
+		}
+	}
+	return latest
+}
@@ -12,7 +12,7 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -41,6 +41,9 @@ type SSETransport struct {
 	pending   map[any]chan *Message
 	pendingMu sync.RWMutex
 
+	// calls tracks requests in flight, whose clients receive the server's sampling and elicitation requests
+	calls activeCalls
+
 	// connCtx is the long-lived context for the SSE connection
 	// This is separate from individual request contexts to keep the connection alive
 	connCtx    context.Context
@@ -52,9 +55,8 @@ func NewSSETransport(cfg *Config) *SSETransport {
 	// Create long-lived context for SSE connection (separate from request contexts)
 	connCtx, connCancel := context.WithCancel(context.Background())
 
-	// Create HTTP client with OTEL instrumentation
-	client := &http.Client{}
-	telemetry.WrapHTTPClient(client)
+	// Responses are streamed for as long as the connection is open, so neither time nor size is limited
+	client := httpclient.New(httpclient.Options{MaxResponseBytes: -1})
 
 	return &SSETransport{
 		config:        cfg,
@@ -175,11 +177,8 @@ func (t *SSETransport) readEvents(body io.ReadCloser) {
 	}
 }
 
-// processEvent processes a single SSE event.
-func (t *SSETransport) processEvent(eventData string) {
-	var eventType string
-	var data string
-
+// parseEvent returns the type and data of a single SSE event.
+func parseEvent(eventData string) (eventType, data string) {
 	for line := range strings.SplitSeq(eventData, "\n") {
 		if after, ok := strings.CutPrefix(line, "event:"); ok {
 			eventType = strings.TrimSpace(after)
@@ -187,7 +186,12 @@ func (t *SSETransport) processEvent(eventData string) {
 			data = strings.TrimSpace(after)
 		}
 	}
+	return eventType, data
+}
 
+// processEvent processes a single SSE event.
+func (t *SSETransport) processEvent(eventData string) {
+	eventType, data := parseEvent(eventData)
 	if data == "" {
 		return
 	}
@@ -211,6 +215,13 @@ func (t *SSETransport) processEvent(eventData string) {
 		return
 	}
 
+	// Requests from the server, such as sampling, are answered by POSTing the response back. They wait
+	// on the client, so must not hold up reading responses.
+	if msg.IsRequest() {
+		go t.answerRequest(&msg)
+		return
+	}
+
 	// If this is a response (has ID), deliver it to the waiting request
 	if msg.ID != nil {
 		t.pendingMu.RLock()
@@ -279,6 +290,8 @@ func (t *SSETransport) SendReceive(ctx context.Context, msg *Message) (*Message,
 		return nil, fmt.Errorf("not connected: endpoint not received from server")
 	}
 
+	defer t.calls.add(ctx)()
+
 	// Create response channel for this request
 	responseChan := make(chan *Message, 1)
 	t.pendingMu.Lock()
@@ -293,34 +306,9 @@ func (t *SSETransport) SendReceive(ctx context.Context, msg *Message) (*Message,
 		close(responseChan)
 	}()
 
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal message: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers
-	for k, v := range t.config.Headers {
-		req.Header.Set(k, v)
-	}
-
-	// Add authorisation header if auth provider is available
-	if t.config.AuthProvider != nil {
-		token, err := t.config.AuthProvider.GetAccessToken(ctx)
-		if err == nil && token != "" {
-			req.Header.Set("Authorization", "Bearer "+token)
-		}
-	}
-
-	resp, err := t.client.Do(req)
+	resp, err := t.post(ctx, endpoint, msg)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -361,6 +349,63 @@ func (t *SSETransport) SendReceive(ctx context.Context, msg *Message) (*Message,
 	}
 }
 
+// answerRequest answers a request sent by the server over the SSE stream. SSE requests are not tied to
+// the call that caused them, so sampling and elicitation are relayed to the client of the latest call
+// in flight.
+func (t *SSETransport) answerRequest(msg *Message) {
+	ctx := t.calls.latest()
+	response := answerRequest(ctx, t.config.ServerURL, msg)
+
+	t.endpointMu.RLock()
+	endpoint := t.endpoint
+	t.endpointMu.RUnlock()
+
+	postCtx := t.connCtx
+	if ctx != nil {
+		postCtx = ctx
+	}
+	resp, err := t.post(postCtx, endpoint, response)
+	if err != nil {
+		logrus.WithError(err).WithField("method", msg.Method).Debug("SSE failed to answer server request")
+		return
+	}
+	resp.Body.Close()
+}
+
+// post POSTs a JSON-RPC message to the endpoint.
+func (t *SSETransport) post(ctx context.Context, endpoint *url.URL, msg *Message) (*http.Response, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add custom headers
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	// Add authorisation header if auth provider is available
+	if t.config.AuthProvider != nil {
+		token, err := t.config.AuthProvider.GetAccessToken(ctx)
+		if err == nil && token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
 // Close closes the SSE transport.
 func (t *SSETransport) Close() error {
 	t.closeOnce.Do(func() {
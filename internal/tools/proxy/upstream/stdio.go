@@ -0,0 +1,410 @@
+package upstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// Restart and shutdown timings for stdio upstreams
+const (
+	// initialiseTimeout bounds the initialize handshake with a newly started process
+	initialiseTimeout = 30 * time.Second
+	// minRestartDelay and maxRestartDelay bound the backoff between restarts of a crashed process
+	minRestartDelay = time.Second
+	maxRestartDelay = 30 * time.Second
+	// stableRunTime is how long a process must run before a crash restarts it without backing off
+	stableRunTime = time.Minute
+	// stopTimeout is how long a process has to exit after its stdin is closed before it is killed
+	stopTimeout = 2 * time.Second
+)
+
+// ErrNotRunning is returned while a stdio upstream's process is down and waiting to be restarted.
+var ErrNotRunning = errors.New("upstream process is not running")
+
+// StdioConfig holds configuration for a stdio upstream.
+type StdioConfig struct {
+	Name    string
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+// StdioTransport runs an upstream MCP server as a child process and exchanges newline delimited
+// JSON-RPC messages over its stdin and stdout. The process is restarted if it exits unexpectedly.
+type StdioTransport struct {
+	config   *StdioConfig
+	nextID   atomic.Int64
+	restarts atomic.Int64
+	calls    activeCalls
+
+	mu      sync.Mutex
+	process *stdioProcess
+	closed  bool
+	done    chan struct{}
+}
+
+// stdioProcess is one run of the upstream's process
+type stdioProcess struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	started time.Time
+	exited  chan struct{}
+	stopped atomic.Bool // stopped on purpose, so not restarted
+
+	writeMu sync.Mutex
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *Message
+}
+
+// NewStdioTransport creates a new stdio transport.
+func NewStdioTransport(cfg *StdioConfig) *StdioTransport {
+	return &StdioTransport{
+		config: cfg,
+		done:   make(chan struct{}),
+	}
+}
+
+// Start starts the process and completes the MCP initialize handshake.
+func (t *StdioTransport) Start(ctx context.Context) error {
+	logrus.WithFields(logrus.Fields{
+		"name":    t.config.Name,
+		"command": t.config.Command,
+	}).Debug("stdio transport starting")
+	return t.spawn(ctx)
+}
+
+// spawn starts a new run of the process, replacing any previous one.
+func (t *StdioTransport) spawn(ctx context.Context) error {
+	// The process outlives the context, which only bounds the handshake
+	cmd := exec.Command(t.config.Command, t.config.Args...)
+	cmd.Env = os.Environ()
+	for _, key := range slices.Sorted(maps.Keys(t.config.Env)) {
+		cmd.Env = append(cmd.Env, key+"="+t.config.Env[key])
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	// The process's stderr is logged, never passed to ours, as that would corrupt stdio mode
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", t.config.Command, err)
+	}
+
+	process := &stdioProcess{
+		cmd:     cmd,
+		stdin:   stdin,
+		started: time.Now(),
+		exited:  make(chan struct{}),
+		pending: make(map[string]chan *Message),
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		_ = cmd.Process.Kill()
+		return ErrClosed
+	}
+	t.process = process
+	t.mu.Unlock()
+
+	go t.readMessages(process, stdout)
+	go t.logStderr(stderr)
+	go func() {
+		// Wait closes the pipes once the process exits, ending the readers even if a child of the
+		// process still holds them open
+		err := process.cmd.Wait()
+		close(process.exited)
+		t.handleExit(process, err)
+	}()
+
+	if err := t.initialise(ctx); err != nil {
+		stopProcess(process)
+		return fmt.Errorf("initialize handshake failed: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name": t.config.Name,
+		"pid":  cmd.Process.Pid,
+	}).Info("stdio upstream started")
+	return nil
+}
+
+// initialise performs the MCP initialize handshake with the process.
+func (t *StdioTransport) initialise(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, initialiseTimeout)
+	defer cancel()
+
+	params, err := json.Marshal(map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"capabilities":    clientCapabilities,
+		"clientInfo": map[string]any{
+			"name":    "mcp-devtools-proxy",
+			"version": "1.0.0",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := t.SendReceive(ctx, &Message{JSONRPC: "2.0", ID: "initialize", Method: "initialize", Params: params})
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return fmt.Errorf("initialize error: %s", response.Error.Message)
+	}
+	return t.notify(&Message{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// SendReceive sends a JSON-RPC request to the process and waits for its response.
+func (t *StdioTransport) SendReceive(ctx context.Context, msg *Message) (*Message, error) {
+	process, err := t.current()
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests are renumbered so that concurrent callers reusing an ID get their own responses
+	id := fmt.Sprintf("mcp-devtools-%d", t.nextID.Add(1))
+	request := *msg
+	request.ID = id
+	defer t.calls.add(ctx)()
+
+	responses := make(chan *Message, 1)
+	process.pendingMu.Lock()
+	process.pending[id] = responses
+	process.pendingMu.Unlock()
+	defer func() {
+		process.pendingMu.Lock()
+		delete(process.pending, id)
+		process.pendingMu.Unlock()
+	}()
+
+	if err := process.write(&request); err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-responses:
+		response.ID = msg.ID
+		return response, nil
+	case <-process.exited:
+		// The response may have arrived just before the process exited
+		select {
+		case response := <-responses:
+			response.ID = msg.ID
+			return response, nil
+		default:
+		}
+		return nil, fmt.Errorf("%w: exited while handling %s", ErrNotRunning, msg.Method)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// notify sends a JSON-RPC notification to the process.
+func (t *StdioTransport) notify(msg *Message) error {
+	process, err := t.current()
+	if err != nil {
+		return err
+	}
+	return process.write(msg)
+}
+
+// current returns the running process.
+func (t *StdioTransport) current() (*stdioProcess, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrClosed
+	}
+	if t.process == nil {
+		return nil, ErrNotRunning
+	}
+	select {
+	case <-t.process.exited:
+		return nil, fmt.Errorf("%w, it will be restarted", ErrNotRunning)
+	default:
+		return t.process, nil
+	}
+}
+
+// write sends a message to the process as one line of JSON.
+func (p *stdioProcess) write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to upstream process: %w", err)
+	}
+	return nil
+}
+
+// readMessages dispatches messages from the process until its stdout closes.
+func (t *StdioTransport) readMessages(process *stdioProcess, stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			t.handleLine(process, line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handleLine handles one line of output from the process.
+func (t *StdioTransport) handleLine(process *stdioProcess, line []byte) {
+	var msg Message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// Some servers print banners to stdout, which are not part of the protocol
+		logrus.WithField("name", t.config.Name).Debugf("stdio upstream wrote non-JSON output: %.200s", line)
+		return
+	}
+
+	switch {
+	case msg.Method == "" && msg.ID != nil:
+		id := fmt.Sprint(msg.ID)
+		process.pendingMu.Lock()
+		responses, ok := process.pending[id]
+		process.pendingMu.Unlock()
+		if ok {
+			responses <- &msg
+		} else {
+			logrus.WithFields(logrus.Fields{"name": t.config.Name, "id": id}).Debug("stdio upstream sent a response to an unknown request")
+		}
+	case msg.IsRequest():
+		// Relayed requests wait on the client, so must not hold up reading responses
+		go t.answerRequest(process, &msg)
+	default:
+		logrus.WithFields(logrus.Fields{"name": t.config.Name, "method": msg.Method}).Debug("stdio upstream sent a notification")
+	}
+}
+
+// answerRequest answers a request from the process. Stdio requests are not tied to the call that caused
+// them, so sampling and elicitation are relayed to the client of the latest call in flight.
+func (t *StdioTransport) answerRequest(process *stdioProcess, msg *Message) {
+	response := answerRequest(t.calls.latest(), t.config.Name, msg)
+	if err := process.write(response); err != nil {
+		logrus.WithError(err).WithField("name", t.config.Name).Debug("failed to answer stdio upstream request")
+	}
+}
+
+// logStderr logs the process's stderr line by line.
+func (t *StdioTransport) logStderr(stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		logrus.WithField("name", t.config.Name).Debugf("stdio upstream stderr: %s", scanner.Text())
+	}
+}
+
+// handleExit restarts the process after an unexpected exit.
+func (t *StdioTransport) handleExit(process *stdioProcess, err error) {
+	t.mu.Lock()
+	closed := t.closed
+	current := t.process == process
+	t.mu.Unlock()
+	if closed || !current || process.stopped.Load() {
+		return
+	}
+
+	logrus.WithError(err).WithFields(logrus.Fields{
+		"name":   t.config.Name,
+		"uptime": time.Since(process.started).Round(time.Second).String(),
+	}).Warn("stdio upstream exited unexpectedly, restarting")
+
+	delay := minRestartDelay
+	if time.Since(process.started) < stableRunTime {
+		// A process that keeps crashing soon after starting is restarted less often
+		delay = min(maxRestartDelay, minRestartDelay<<min(t.restarts.Load(), 5))
+	} else {
+		t.restarts.Store(0)
+	}
+	go t.restart(delay)
+}
+
+// restart starts the process again after delay, backing off further while it fails to start.
+func (t *StdioTransport) restart(delay time.Duration) {
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-time.After(delay):
+		}
+
+		attempt := t.restarts.Add(1)
+		err := t.spawn(context.Background())
+		if err == nil || errors.Is(err, ErrClosed) {
+			return
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"name":    t.config.Name,
+			"attempt": attempt,
+		}).Warn("failed to restart stdio upstream")
+		delay = min(maxRestartDelay, delay*2)
+	}
+}
+
+// Restarts returns how many times the process has been restarted.
+func (t *StdioTransport) Restarts() int64 {
+	return t.restarts.Load()
+}
+
+// Close stops the process without restarting it.
+func (t *StdioTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	process := t.process
+	t.mu.Unlock()
+
+	if process != nil {
+		stopProcess(process)
+	}
+	logrus.WithField("name", t.config.Name).Debug("stdio transport closed")
+	return nil
+}
+
+// stopProcess closes the process's stdin, which asks MCP servers to exit, and kills it if it does not.
+func stopProcess(process *stdioProcess) {
+	process.stopped.Store(true)
+	_ = process.stdin.Close()
+	select {
+	case <-process.exited:
+	case <-time.After(stopTimeout):
+		_ = process.cmd.Process.Kill()
+		<-process.exited
+	}
+}
@@ -0,0 +1,366 @@
+package regextest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultMaxMatches is how many matches are listed by default
+	defaultMaxMatches = 100
+
+	// maxMatchesLimit is the most matches that can be listed
+	maxMatchesLimit = 1000
+
+	// maxTextSize is the largest sample text accepted
+	maxTextSize = 1024 * 1024
+
+	// maxPatternLength is the longest pattern accepted
+	maxPatternLength = 10_000
+
+	// highlightStart and highlightEnd mark matches in the highlighted text
+	highlightStart = "«"
+	highlightEnd   = "»"
+)
+
+// validFlags are the RE2 flags that can be set with the flags parameter
+const validFlags = "imsU"
+
+// unsupportedSyntax explains common PCRE features that RE2 rejects
+var unsupportedSyntax = []struct {
+	marker string
+	hint   string
+}{
+	{"(?=", "Lookaheads are not supported. Match the following text and use a capture group for the part you need."},
+	{"(?!", "Negative lookaheads are not supported. Match more broadly and filter the matches, or list the allowed alternatives."},
+	{"(?<=", "Lookbehinds are not supported. Match the preceding text and use a capture group for the part you need."},
+	{"(?<!", "Negative lookbehinds are not supported. Match more broadly and filter the matches."},
+	{"(?>", "Atomic groups are not supported, and are not needed: RE2 never backtracks."},
+	{"++", "Possessive quantifiers are not supported, and are not needed: RE2 never backtracks."},
+	{"*+", "Possessive quantifiers are not supported, and are not needed: RE2 never backtracks."},
+}
+
+// backreference finds \1 style backreferences, which RE2 does not support
+var backreference = regexp.MustCompile(`\\[1-9]`)
+
+// RegexTestTool tests Go (RE2) regular expressions against sample text
+type RegexTestTool struct{}
+
+// Group is a capture group within a match
+type Group struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Match is one match of the pattern
+type Match struct {
+	Text   string  `json:"text"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+	Groups []Group `json:"groups,omitempty"`
+}
+
+// SecurityRuleCheck reports whether the pattern would work as a regex rule in security.yaml
+type SecurityRuleCheck struct {
+	Accepted bool     `json:"accepted"`
+	YAML     string   `json:"yaml,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Response is the result of testing a pattern
+type Response struct {
+	Pattern      string             `json:"pattern"`
+	Valid        bool               `json:"valid"`
+	Error        string             `json:"error,omitempty"`
+	Hints        []string           `json:"hints,omitempty"`
+	GroupNames   []string           `json:"group_names,omitempty"`
+	MatchCount   int                `json:"match_count"`
+	Truncated    bool               `json:"truncated,omitempty"`
+	Matches      []Match            `json:"matches"`
+	Highlighted  string             `json:"highlighted,omitempty"`
+	Replaced     *string            `json:"replaced,omitempty"`
+	SecurityRule *SecurityRuleCheck `json:"security_rule"`
+}
+
+// init registers the regex_test tool
+func init() {
+	registry.Register(&RegexTestTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (r *RegexTestTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"regex_test",
+		mcp.WithDescription(`Tests a Go (RE2) regular expression against sample text. Returns every match with its position and capture groups, the text with matches highlighted in «», an optional replacement result, and whether the pattern would be accepted as a regex rule in security.yaml.
+
+RE2 has no lookarounds or backreferences; invalid patterns are reported with hints rather than failing.`),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Regular expression in Go (RE2) syntax"),
+		),
+		mcp.WithString("text",
+			mcp.Description("Sample text to match against. Omit to only check the pattern"),
+		),
+		mcp.WithString("flags",
+			mcp.Description("Flags to apply: i (case-insensitive), m (^ and $ match at line breaks), s (. matches newlines), U (ungreedy)"),
+		),
+		mcp.WithString("replace",
+			mcp.Description("Replacement to apply to every match, using $1 or ${name} for groups"),
+		),
+		mcp.WithNumber("max_matches",
+			mcp.Description(fmt.Sprintf("Most matches to list (default: %d, max: %d)", defaultMaxMatches, maxMatchesLimit)),
+		),
+		// Read-only annotations for pattern testing
+		mcp.WithReadOnlyHintAnnotation(true),     // Doesn't modify environment
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(true),   // The same pattern and text give the same result
+		mcp.WithOpenWorldHintAnnotation(false),   // Works on the given text, no external interactions
+	)
+}
+
+// Execute tests the pattern against the text
+func (r *RegexTestTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	pattern, ok := args["pattern"].(string)
+	if !ok || pattern == "" {
+		return nil, fmt.Errorf("missing required parameter 'pattern'. Example: {\"pattern\": \"(\\\\d+)-(\\\\d+)\", \"text\": \"10-20\"}")
+	}
+	if len(pattern) > maxPatternLength {
+		return nil, fmt.Errorf("pattern exceeds maximum length of %d characters", maxPatternLength)
+	}
+
+	text, hasText := args["text"].(string)
+	if len(text) > maxTextSize {
+		return nil, fmt.Errorf("text is %d bytes, more than the maximum of %d", len(text), maxTextSize)
+	}
+
+	flags, _ := args["flags"].(string)
+	for _, flag := range flags {
+		if !strings.ContainsRune(validFlags, flag) {
+			return nil, fmt.Errorf("invalid flag '%c': flags must be any of i, m, s and U", flag)
+		}
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+
+	maxMatches := defaultMaxMatches
+	if raw, ok := args["max_matches"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxMatchesLimit || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'max_matches' parameter: must be a whole number from 1 to %d", maxMatchesLimit)
+		}
+		maxMatches = int(value)
+	}
+
+	logger.WithField("pattern_length", len(pattern)).Debug("Executing regex_test")
+
+	response := &Response{Pattern: pattern, Matches: []Match{}}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		response.Error = err.Error()
+		response.Hints = syntaxHints(pattern)
+		response.SecurityRule = &SecurityRuleCheck{
+			Warnings: []string{"The security engine rejects rules with invalid regex patterns, and comments them out of security.yaml when it loads the file."},
+		}
+		return marshalResponse(response)
+	}
+	response.Valid = true
+	for i, name := range re.SubexpNames() {
+		if i > 0 {
+			response.GroupNames = append(response.GroupNames, name)
+		}
+	}
+
+	if hasText {
+		findMatches(response, re, text, maxMatches)
+		if replace, ok := args["replace"].(string); ok {
+			replaced := re.ReplaceAllString(text, replace)
+			response.Replaced = &replaced
+		}
+	}
+
+	response.SecurityRule = checkSecurityRule(re, pattern, text, hasText)
+	return marshalResponse(response)
+}
+
+// findMatches records up to maxMatches matches of the pattern in text, and the text with every match
+// highlighted
+func findMatches(response *Response, re *regexp.Regexp, text string, maxMatches int) {
+	names := re.SubexpNames()
+	all := re.FindAllStringSubmatchIndex(text, -1)
+	response.MatchCount = len(all)
+	response.Truncated = len(all) > maxMatches
+
+	var highlighted strings.Builder
+	previous := 0
+	for i, indexes := range all {
+		start, end := indexes[0], indexes[1]
+		highlighted.WriteString(text[previous:start])
+		highlighted.WriteString(highlightStart + text[start:end] + highlightEnd)
+		previous = end
+		if i >= maxMatches {
+			continue
+		}
+
+		line := strings.Count(text[:start], "\n") + 1
+		lineStart := strings.LastIndex(text[:start], "\n") + 1
+		match := Match{
+			Text:   text[start:end],
+			Start:  start,
+			End:    end,
+			Line:   line,
+			Column: utf8.RuneCountInString(text[lineStart:start]) + 1,
+		}
+		for group := 1; group < len(names); group++ {
+			groupStart, groupEnd := indexes[2*group], indexes[2*group+1]
+			if groupStart < 0 {
+				// The group did not take part in this match
+				continue
+			}
+			match.Groups = append(match.Groups, Group{
+				Index: group,
+				Name:  names[group],
+				Text:  text[groupStart:groupEnd],
+				Start: groupStart,
+				End:   groupEnd,
+			})
+		}
+		response.Matches = append(response.Matches, match)
+	}
+	highlighted.WriteString(text[previous:])
+	if len(all) > 0 {
+		response.Highlighted = highlighted.String()
+	}
+}
+
+// checkSecurityRule reports whether the pattern would work as a regex rule in security.yaml: that the
+// security engine accepts it, how to write it in YAML, and anything that would make the rule misbehave
+func checkSecurityRule(re *regexp.Regexp, pattern, text string, hasText bool) *SecurityRuleCheck {
+	check := &SecurityRuleCheck{Accepted: true}
+
+	matcher, err := security.NewRegexMatcher(pattern)
+	if err != nil {
+		check.Accepted = false
+		check.Warnings = append(check.Warnings, fmt.Sprintf("The security engine rejects the pattern: %v", err))
+		return check
+	}
+
+	// Single-quoted YAML keeps backslashes as they are, so only single quotes need escaping
+	check.YAML = "regex: '" + strings.ReplaceAll(pattern, "'", "''") + "'"
+	var parsed struct {
+		Regex string `yaml:"regex"`
+	}
+	if err := yaml.Unmarshal([]byte(check.YAML), &parsed); err != nil || parsed.Regex != pattern {
+		check.YAML = ""
+		check.Warnings = append(check.Warnings, "The pattern cannot be written as a single-line YAML string. Remove line breaks from it.")
+	}
+
+	if re.MatchString("") {
+		check.Warnings = append(check.Warnings, "The pattern matches empty text, so as a rule it would match all content.")
+	}
+
+	if hasText {
+		start := time.Now()
+		matched := matcher.Match(text)
+		if elapsed := time.Since(start); elapsed >= security.DefaultRegexTimeout {
+			check.Warnings = append(check.Warnings, fmt.Sprintf("Matching the sample text took %s, and the security engine treats patterns that take longer than %s as not matching. Simplify the pattern or anchor it.", elapsed.Round(time.Millisecond), security.DefaultRegexTimeout))
+		} else if !matched && re.MatchString(text) {
+			check.Warnings = append(check.Warnings, "The security engine did not match the sample text within its time limit.")
+		}
+	}
+	return check
+}
+
+// syntaxHints explains why a pattern using common PCRE features is rejected by RE2
+func syntaxHints(pattern string) []string {
+	var hints []string
+	for _, syntax := range unsupportedSyntax {
+		if strings.Contains(pattern, syntax.marker) {
+			hints = append(hints, syntax.hint)
+		}
+	}
+	if backreference.MatchString(pattern) {
+		hints = append(hints, "Backreferences such as \\1 are not supported. Match each alternative explicitly, or check that groups are equal after matching.")
+	}
+	return hints
+}
+
+// marshalResponse returns the response as indented JSON
+func marshalResponse(response *Response) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the regex_test tool
+func (r *RegexTestTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Before putting a regular expression in Go code, a security.yaml rule, a config file or a search, to confirm what it matches and what its groups capture.",
+		WhenNotToUse: "Patterns for other regex engines that rely on lookarounds or backreferences (PCRE, JavaScript, Python) - results here only reflect Go's RE2 engine.",
+		CommonPatterns: []string{
+			"Check captures: include a representative sample and read each match's groups",
+			"Check what must not match: include negative examples in the text and confirm they are not highlighted",
+			"Test a substitution with 'replace' before using regexp.ReplaceAllString",
+			"Author security.yaml rules: copy the 'yaml' line from security_rule into the rule's patterns",
+			"Match across lines with flags 'm' or 's'",
+		},
+		ParameterDetails: map[string]string{
+			"pattern":     "Go RE2 syntax. Named groups are written (?P<name>...) or (?<name>...).",
+			"text":        fmt.Sprintf("Sample text, up to %d KiB. Positions in the results are byte offsets; line and column are 1-based, with columns counted in characters.", maxTextSize/1024),
+			"flags":       "Prepended to the pattern as (?flags). The reported pattern includes them, so it can be used as it is.",
+			"replace":     "Replacement template for every match. $1, ${1} and ${name} insert groups; write $$ for a literal $.",
+			"max_matches": "Limits the matches listed. match_count is always the total, and every match is highlighted.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Capture the parts of a version number",
+				Arguments:      map[string]any{"pattern": `v(?P<major>\d+)\.(?P<minor>\d+)`, "text": "upgrade from v1.24 to v1.26"},
+				ExpectedResult: "Two matches with major and minor groups, and the text 'upgrade from «v1.24» to «v1.26»'",
+			},
+			{
+				Description:    "Rewrite dates",
+				Arguments:      map[string]any{"pattern": `(\d{2})/(\d{2})/(\d{4})`, "text": "due 18/10/2026", "replace": "$3-$2-$1"},
+				ExpectedResult: "replaced: 'due 2026-10-18'",
+			},
+			{
+				Description:    "Check a pattern for a security.yaml rule",
+				Arguments:      map[string]any{"pattern": `(?i)curl\s+[^|]*\|\s*(ba)?sh`, "text": "curl -fsSL https://example.com/install.sh | bash"},
+				ExpectedResult: "One match, and security_rule with accepted true and the line to put in security.yaml",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "'invalid or unsupported Perl syntax' error",
+				Solution: "RE2 has no lookarounds, backreferences, atomic groups or possessive quantifiers. See the hints in the result for alternatives.",
+			},
+			{
+				Problem:  "^ and $ only match at the start and end of the whole text",
+				Solution: "Set flags to 'm' so they match at line breaks.",
+			},
+			{
+				Problem:  "The pattern works here but not in a JSON or YAML file",
+				Solution: "Backslashes must be doubled in JSON strings and in double-quoted YAML. Use the single-quoted 'yaml' line from security_rule, which needs no escaping.",
+			},
+		},
+	}
+}
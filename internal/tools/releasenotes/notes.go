@@ -0,0 +1,266 @@
+package releasenotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+// changelogFiles are the changelog names tried, in order, at the repository root
+var changelogFiles = []string{"CHANGELOG.md", "CHANGES.md", "HISTORY.md", "RELEASES.md", "NEWS.md", "CHANGELOG.rst", "CHANGELOG"}
+
+var (
+	// versionPattern finds a semantic-ish version in a tag or heading
+	versionPattern = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.]+)?)`)
+	// versionHeadingPattern matches changelog headings that start a version section,
+	// such as "## [1.2.3] - 2024-01-01", "## v1.2.3 (2024-01-01)" or "# 1.2.3"
+	versionHeadingPattern = regexp.MustCompile(`^(#{1,3})\s+(?:\[)?(?:[A-Za-z@/_.-]*[@ ])?v?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.]+)?)\b`)
+	// headingPattern matches any markdown heading
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	// datePattern finds an ISO date in a heading
+	datePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	// itemPattern matches list items
+	itemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// attributionPattern strips GitHub's generated "by @user in URL" suffixes
+	attributionPattern = regexp.MustCompile(`\s+by @[\w-]+(?:\[bot\])? in https://\S+$`)
+
+	breakingSectionPattern    = regexp.MustCompile(`(?i)breaking|incompatib|migration|upgrad(e|ing) (guide|notes)|removals?\b|removed`)
+	deprecationSectionPattern = regexp.MustCompile(`(?i)deprecat`)
+	securitySectionPattern    = regexp.MustCompile(`(?i)security`)
+
+	breakingItemPattern    = regexp.MustCompile(`(?i)\bbreaking\b|backwards?[- ]incompatible|no longer support|^(\*\*)?(removed?|drop(ped|s)?)\b|^\w+(\([^)]*\))?!:`)
+	deprecationItemPattern = regexp.MustCompile(`(?i)deprecat`)
+	securityItemPattern    = regexp.MustCompile(`(?i)\bsecurity\b|CVE-\d{4}-\d+|GHSA-[a-z0-9-]+|vulnerab`)
+)
+
+// release is a single version's notes from GitHub Releases or a changelog
+type release struct {
+	Version    string
+	Tag        string
+	Date       string
+	URL        string
+	Body       string
+	Prerelease bool
+}
+
+// githubRelease is a release returned by the GitHub REST API
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+}
+
+// cacheEntry stores fetched releases for a repository
+type cacheEntry struct {
+	releases  []release
+	fetchedAt time.Time
+}
+
+// githubHeaders returns request headers for the GitHub API, adding GITHUB_TOKEN (or the stored
+// github-token credential) when set to raise the rate limit from 60 to 5,000 requests an hour
+func githubHeaders() map[string]string {
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if token := credentials.Lookup("GITHUB_TOKEN", credentials.GitHubToken); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return headers
+}
+
+// fetchReleases returns GitHub Releases for a repository, newest first. Pages are fetched until
+// one contains a release at or below stopAt, or maxReleasePages is reached.
+func (t *ReleaseNotesTool) fetchReleases(logger *logrus.Logger, cache *sync.Map, repo repository, stopAt string) ([]release, error) {
+	cacheKey := "release_notes:releases:" + repo.String() + ":" + stopAt
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < cacheTTL {
+			return entry.releases, nil
+		}
+	}
+
+	var releases []release
+	for page := 1; page <= maxReleasePages; page++ {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=%d&page=%d", repo, releasesPerPage, page)
+		body, err := packageversions.MakeRequestWithLogger(t.httpClient(), logger, "GET", apiURL, githubHeaders())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch GitHub releases for %s: %w", repo, err)
+		}
+		var items []githubRelease
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse GitHub releases for %s: %w", repo, err)
+		}
+
+		reachedStop := false
+		for _, item := range items {
+			if item.Draft {
+				continue
+			}
+			version := extractVersion(item.TagName)
+			if version == "" {
+				version = extractVersion(item.Name)
+			}
+			releases = append(releases, release{
+				Version:    version,
+				Tag:        item.TagName,
+				Date:       dateOnly(item.PublishedAt),
+				URL:        item.HTMLURL,
+				Body:       item.Body,
+				Prerelease: item.Prerelease || strings.Contains(version, "-"),
+			})
+			if stopAt != "" && version != "" {
+				if cmp, err := packageversions.CompareVersions(version, stopAt); err == nil && cmp <= 0 {
+					reachedStop = true
+				}
+			}
+		}
+		if len(items) < releasesPerPage || reachedStop || stopAt == "" {
+			break
+		}
+	}
+
+	cache.Store(cacheKey, cacheEntry{releases: releases, fetchedAt: time.Now()})
+	return releases, nil
+}
+
+// fetchChangelog returns the versions in the repository's changelog file, and the file's URL
+func (t *ReleaseNotesTool) fetchChangelog(logger *logrus.Logger, cache *sync.Map, repo repository) ([]release, string, error) {
+	cacheKey := "release_notes:changelog:" + repo.String()
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < cacheTTL {
+			source := ""
+			if len(entry.releases) > 0 {
+				source = entry.releases[0].URL
+			}
+			return entry.releases, source, nil
+		}
+	}
+
+	for _, name := range changelogFiles {
+		rawURL := fmt.Sprintf("https://raw.githubusercontent.com/%s/HEAD/%s", repo, name)
+		body, err := packageversions.MakeRequestWithLogger(t.httpClient(), logger, "GET", rawURL, map[string]string{"Accept": "text/plain"})
+		if err != nil {
+			if strings.Contains(err.Error(), "status code: 404") {
+				continue
+			}
+			return nil, "", fmt.Errorf("failed to fetch %s from %s: %w", name, repo, err)
+		}
+		pageURL := fmt.Sprintf("https://github.com/%s/blob/HEAD/%s", repo, name)
+		releases := parseChangelog(string(body), pageURL)
+		cache.Store(cacheKey, cacheEntry{releases: releases, fetchedAt: time.Now()})
+		return releases, pageURL, nil
+	}
+	return nil, "", fmt.Errorf("no changelog file found in %s (tried %s)", repo, strings.Join(changelogFiles, ", "))
+}
+
+// parseChangelog splits a markdown changelog into per-version sections
+func parseChangelog(text, pageURL string) []release {
+	var releases []release
+	var current *release
+	var body strings.Builder
+	level := 0
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(body.String())
+			releases = append(releases, *current)
+		}
+		body.Reset()
+	}
+
+	for line := range strings.Lines(text) {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if match := versionHeadingPattern.FindStringSubmatch(trimmed); match != nil && (current == nil || len(match[1]) <= level) {
+			flush()
+			level = len(match[1])
+			current = &release{
+				Version:    match[2],
+				Tag:        match[2],
+				Date:       datePattern.FindString(trimmed),
+				URL:        pageURL,
+				Prerelease: strings.Contains(match[2], "-"),
+			}
+			continue
+		}
+		if current != nil {
+			body.WriteString(trimmed)
+			body.WriteString("\n")
+		}
+	}
+	flush()
+	return releases
+}
+
+// extractVersion returns the version in a tag such as v1.2.3, pkg@1.2.3 or release-1.2
+func extractVersion(tag string) string {
+	if match := versionPattern.FindStringSubmatch(tag); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// dateOnly trims an RFC 3339 timestamp to its date
+func dateOnly(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
+
+// filterReleases keeps releases after from (exclusive) up to to (inclusive), newest first.
+// For monorepos with tags such as pkg@1.2.3, releases tagged for the package are preferred.
+func filterReleases(releases []release, pkg, from, to string, includePrereleases bool) []release {
+	if pkg != "" {
+		var tagged []release
+		for _, r := range releases {
+			if strings.HasPrefix(r.Tag, pkg+"@") || strings.HasPrefix(r.Tag, pkg+"-v") {
+				tagged = append(tagged, r)
+			}
+		}
+		if len(tagged) > 0 {
+			releases = tagged
+		}
+	}
+
+	var filtered []release
+	for _, r := range releases {
+		if r.Version == "" {
+			continue
+		}
+		if r.Prerelease && !includePrereleases && r.Version != to {
+			continue
+		}
+		if from != "" {
+			if cmp, err := packageversions.CompareVersions(r.Version, from); err != nil || cmp <= 0 {
+				continue
+			}
+		}
+		if to != "" {
+			if cmp, err := packageversions.CompareVersions(r.Version, to); err != nil || cmp > 0 {
+				continue
+			}
+		}
+		filtered = append(filtered, r)
+	}
+
+	slices.SortStableFunc(filtered, func(a, b release) int {
+		cmp, err := packageversions.CompareVersions(b.Version, a.Version)
+		if err != nil {
+			return 0
+		}
+		return cmp
+	})
+	return filtered
+}
@@ -0,0 +1,288 @@
+package releasenotes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cacheTTL               = 1 * time.Hour
+	releasesPerPage        = 100
+	maxReleasePages        = 5
+	defaultMaxReleases     = 20
+	maxMaxReleases         = 100
+	defaultItemsPerRelease = 5
+	maxItemLength          = 200
+	maxOutputChars         = 40000
+	sourceAuto             = "auto"
+	sourceReleases         = "releases"
+	sourceChangelog        = "changelog"
+)
+
+// packageNamePattern restricts package names to characters used by the supported registries
+var packageNamePattern = regexp.MustCompile(`^@?[A-Za-z0-9][A-Za-z0-9_.~/-]*$`)
+
+// ReleaseNotesTool condenses release notes between two versions of a package
+type ReleaseNotesTool struct {
+	client packageversions.HTTPClient
+}
+
+// init registers the release notes tool
+func init() {
+	registry.Register(&ReleaseNotesTool{})
+}
+
+// NewReleaseNotesTool creates a release notes tool that uses the given HTTP client
+func NewReleaseNotesTool(client packageversions.HTTPClient) *ReleaseNotesTool {
+	return &ReleaseNotesTool{client: client}
+}
+
+// httpClient returns the configured client, defaulting to the shared rate-limited client
+func (t *ReleaseNotesTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return packageversions.DefaultHTTPClient
+	}
+	return t.client
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ReleaseNotesTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"release_notes",
+		mcp.WithDescription(`Summarise what changed between two versions of a package or GitHub repository, with breaking changes, deprecations and security fixes listed first.
+
+Finds the package's GitHub repository from its registry (npm, PyPI, crates.io or the Go module proxy), then reads GitHub Releases, falling back to CHANGELOG.md. Use before upgrading a dependency to see what needs attention.`),
+		mcp.WithString("ecosystem",
+			mcp.Required(),
+			mcp.Description("Where the package is published, or github for an owner/repo"),
+			mcp.Enum("npm", "python", "go", "rust", "github"),
+		),
+		mcp.WithString("package",
+			mcp.Required(),
+			mcp.Description("Package name (e.g. react, requests, serde, github.com/spf13/cobra) or owner/repo for github"),
+		),
+		mcp.WithString("from_version",
+			mcp.Description("Version you are upgrading from (exclusive). Omit to start from the earliest release"),
+		),
+		mcp.WithString("to_version",
+			mcp.Description("Version you are upgrading to (inclusive). Omit for the latest release"),
+		),
+		mcp.WithString("source",
+			mcp.Description("Where to read notes from: auto tries GitHub Releases then the changelog (default: auto)"),
+			mcp.Enum(sourceAuto, sourceReleases, sourceChangelog),
+		),
+		mcp.WithNumber("max_releases",
+			mcp.Description(fmt.Sprintf("Maximum releases to summarise, newest first (default: %d, max: %d)", defaultMaxReleases, maxMaxReleases)),
+		),
+		mcp.WithNumber("items_per_release",
+			mcp.Description(fmt.Sprintf("Other changes listed per release after highlights (default: %d)", defaultItemsPerRelease)),
+		),
+		mcp.WithBoolean("include_prereleases",
+			mcp.Description("Include alpha, beta and release candidate versions (default: false)"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads registry metadata and release notes
+		mcp.WithDestructiveHintAnnotation(false), // No side effects
+		mcp.WithIdempotentHintAnnotation(true),   // Same range gives the same notes until a new release
+		mcp.WithOpenWorldHintAnnotation(true),    // Fetches from package registries and GitHub
+	)
+}
+
+// Execute fetches and condenses release notes
+func (t *ReleaseNotesTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	ecosystem, _ := args["ecosystem"].(string)
+	if ecosystem == "" {
+		return nil, fmt.Errorf("missing required parameter: ecosystem")
+	}
+	pkg, _ := args["package"].(string)
+	pkg = strings.TrimSpace(pkg)
+	if pkg == "" {
+		return nil, fmt.Errorf("missing required parameter: package")
+	}
+	if !packageNamePattern.MatchString(pkg) && !strings.HasPrefix(pkg, "https://github.com/") {
+		return nil, fmt.Errorf("invalid package name: %s", pkg)
+	}
+
+	from := cleanVersionArg(args["from_version"])
+	to := cleanVersionArg(args["to_version"])
+	if from != "" && to != "" {
+		if cmp, err := packageversions.CompareVersions(from, to); err == nil && cmp >= 0 {
+			return nil, fmt.Errorf("from_version (%s) must be older than to_version (%s)", from, to)
+		}
+	}
+
+	source := sourceAuto
+	if s, ok := args["source"].(string); ok && s != "" {
+		if s != sourceAuto && s != sourceReleases && s != sourceChangelog {
+			return nil, fmt.Errorf("source must be one of: auto, releases, changelog")
+		}
+		source = s
+	}
+
+	maxReleases := defaultMaxReleases
+	if v, ok := args["max_releases"].(float64); ok {
+		if v < 1 || v > maxMaxReleases {
+			return nil, fmt.Errorf("max_releases must be between 1 and %d", maxMaxReleases)
+		}
+		maxReleases = int(v)
+	}
+	itemsPerRelease := defaultItemsPerRelease
+	if v, ok := args["items_per_release"].(float64); ok {
+		if v < 1 {
+			return nil, fmt.Errorf("items_per_release must be at least 1")
+		}
+		itemsPerRelease = int(v)
+	}
+	includePrereleases, _ := args["include_prereleases"].(bool)
+
+	repo, err := t.resolveRepository(logger, ecosystem, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"package": pkg,
+		"repo":    repo.String(),
+		"from":    from,
+		"to":      to,
+		"source":  source,
+	}).Debug("Fetching release notes")
+
+	// Monorepo tags are prefixed with the package name, e.g. @scope/pkg@1.2.3
+	tagPrefix := ""
+	if ecosystem != "github" {
+		tagPrefix = pkg
+	}
+
+	var releases []release
+	var sourceLabel string
+	if source != sourceChangelog {
+		all, err := t.fetchReleases(logger, cache, repo, from)
+		if err != nil && source == sourceReleases {
+			return nil, err
+		}
+		if err != nil {
+			logger.WithError(err).Debug("GitHub releases unavailable, falling back to changelog")
+		}
+		releases = filterReleases(all, tagPrefix, from, to, includePrereleases)
+		sourceLabel = fmt.Sprintf("GitHub Releases (https://github.com/%s/releases)", repo)
+	}
+	if len(releases) == 0 && source != sourceReleases {
+		all, pageURL, err := t.fetchChangelog(logger, cache, repo)
+		if err != nil {
+			if source == sourceChangelog {
+				return nil, err
+			}
+			return nil, fmt.Errorf("no releases found for %s in GitHub Releases and %w", repo, err)
+		}
+		releases = filterReleases(all, "", from, to, includePrereleases)
+		sourceLabel = "Changelog (" + pageURL + ")"
+	}
+
+	truncated := 0
+	if len(releases) > maxReleases {
+		truncated = len(releases) - maxReleases
+		releases = releases[:maxReleases]
+	}
+
+	result := summary{
+		Package: pkg,
+		Repo:    repo.String(),
+		Source:  sourceLabel,
+		From:    from,
+		To:      to,
+	}
+	for _, r := range releases {
+		result.Releases = append(result.Releases, condense(r))
+	}
+
+	output := result.render(itemsPerRelease)
+	if truncated > 0 {
+		output += fmt.Sprintf("\n%d older releases in the range were not summarised - raise max_releases or set from_version to see them\n", truncated)
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// cleanVersionArg normalises an optional version argument such as "v1.2.3" or "^1.2.3"
+func cleanVersionArg(value any) string {
+	version, _ := value.(string)
+	version = strings.TrimSpace(packageversions.CleanVersion(strings.TrimSpace(version)))
+	return strings.TrimPrefix(version, "v")
+}
+
+// ProvideExtendedInfo provides detailed usage information for the release notes tool
+func (t *ReleaseNotesTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Check what changed before a major npm upgrade",
+				Arguments: map[string]any{
+					"ecosystem":    "npm",
+					"package":      "eslint",
+					"from_version": "8.57.0",
+					"to_version":   "9.0.0",
+				},
+				ExpectedResult: "Breaking changes and deprecations across the range, then highlights for each release",
+			},
+			{
+				Description: "Review recent releases of a GitHub repository",
+				Arguments: map[string]any{
+					"ecosystem":    "github",
+					"package":      "hashicorp/terraform",
+					"max_releases": 5,
+				},
+				ExpectedResult: "The five newest releases condensed to their notable changes",
+			},
+			{
+				Description: "Read a Python package's changelog file",
+				Arguments: map[string]any{
+					"ecosystem":    "python",
+					"package":      "requests",
+					"from_version": "2.31.0",
+					"source":       "changelog",
+				},
+				ExpectedResult: "Versions after 2.31.0 parsed from the repository's changelog",
+			},
+		},
+		CommonPatterns: []string{
+			"Use search_packages first to find the latest version, then release_notes from your current version to it",
+			"Read the Breaking changes section before upgrading, then follow the release links for details",
+			"Set source to changelog for projects that publish GitHub Releases without notes",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No GitHub repository found in the registry metadata",
+				Solution: "Use ecosystem 'github' with the owner/repo directly.",
+			},
+			{
+				Problem:  "GitHub API rate limit errors",
+				Solution: "Set GITHUB_TOKEN in the server environment to raise the limit from 60 to 5,000 requests an hour.",
+			},
+			{
+				Problem:  "Releases from other packages appear for a monorepo",
+				Solution: "Tags prefixed with the package name (pkg@1.2.3) are preferred automatically. If the repository uses plain tags for several packages, narrow the version range.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"ecosystem":           "npm, python, go and rust resolve the GitHub repository from registry metadata. github takes owner/repo or a github.com URL.",
+			"from_version":        "Exclusive lower bound. Prefixes such as v, ^ and ~ are ignored.",
+			"to_version":          "Inclusive upper bound. Defaults to the newest release.",
+			"source":              "auto uses GitHub Releases and falls back to CHANGELOG.md, CHANGES.md, HISTORY.md and similar files when there are no matching releases.",
+			"items_per_release":   "Limits ordinary changes per release. Breaking changes, deprecations and security fixes are always listed.",
+			"include_prereleases": "Pre-release versions are skipped unless this is true or the version is to_version.",
+		},
+		WhenToUse:    "Use before upgrading a dependency to find breaking changes, deprecations and security fixes between your version and the target.",
+		WhenNotToUse: "Don't use to find the latest version (use search_packages) or for API documentation (use the package documentation tools).",
+	}
+}
@@ -0,0 +1,171 @@
+package releasenotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+// githubRepoPattern extracts owner and repository from GitHub URLs in any of the forms
+// registries use, such as git+https://github.com/o/r.git or git@github.com:o/r
+var githubRepoPattern = regexp.MustCompile(`github\.com[/:]([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)`)
+
+// ownerRepoPattern matches an owner/repo reference
+var ownerRepoPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// repository identifies a GitHub repository
+type repository struct {
+	Owner string
+	Name  string
+}
+
+// String returns the owner/name form of the repository
+func (r repository) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// parseGitHubURL extracts a repository from a URL or reference that points at GitHub
+func parseGitHubURL(raw string) (repository, bool) {
+	match := githubRepoPattern.FindStringSubmatch(raw)
+	if match == nil {
+		return repository{}, false
+	}
+	name := strings.TrimSuffix(match[2], ".git")
+	if name == "" {
+		return repository{}, false
+	}
+	return repository{Owner: match[1], Name: name}, true
+}
+
+// resolveRepository finds the GitHub repository for a package using its registry metadata
+func (t *ReleaseNotesTool) resolveRepository(logger *logrus.Logger, ecosystem, pkg string) (repository, error) {
+	switch ecosystem {
+	case "github":
+		pkg = strings.TrimSuffix(strings.TrimPrefix(pkg, "https://github.com/"), "/")
+		if repo, ok := parseGitHubURL(pkg); ok {
+			return repo, nil
+		}
+		if !ownerRepoPattern.MatchString(pkg) {
+			return repository{}, fmt.Errorf("package must be owner/repo for the github ecosystem, got: %s", pkg)
+		}
+		owner, name, _ := strings.Cut(pkg, "/")
+		return repository{Owner: owner, Name: name}, nil
+	case "npm":
+		return t.resolveFromRegistry(logger, "https://registry.npmjs.org/"+pkg+"/latest", pkg, npmRepositoryURLs)
+	case "python":
+		return t.resolveFromRegistry(logger, "https://pypi.org/pypi/"+url.PathEscape(pkg)+"/json", pkg, pythonRepositoryURLs)
+	case "rust":
+		return t.resolveFromRegistry(logger, "https://crates.io/api/v1/crates/"+url.PathEscape(pkg), pkg, rustRepositoryURLs)
+	case "go":
+		if repo, ok := parseGitHubURL(pkg); ok {
+			return repo, nil
+		}
+		return t.resolveFromRegistry(logger, "https://proxy.golang.org/"+strings.ToLower(pkg)+"/@latest", pkg, goRepositoryURLs)
+	default:
+		return repository{}, fmt.Errorf("unsupported ecosystem: %s", ecosystem)
+	}
+}
+
+// resolveFromRegistry fetches registry metadata and returns the first GitHub repository URL in it
+func (t *ReleaseNotesTool) resolveFromRegistry(logger *logrus.Logger, apiURL, pkg string, candidates func([]byte) []string) (repository, error) {
+	body, err := packageversions.MakeRequestWithLogger(t.httpClient(), logger, "GET", apiURL, nil)
+	if err != nil {
+		return repository{}, fmt.Errorf("failed to look up %s: %w", pkg, err)
+	}
+	for _, candidate := range candidates(body) {
+		if repo, ok := parseGitHubURL(candidate); ok {
+			return repo, nil
+		}
+	}
+	return repository{}, fmt.Errorf("no GitHub repository found in the registry metadata for %s - pass ecosystem 'github' with the owner/repo instead", pkg)
+}
+
+// npmRepositoryURLs returns repository candidates from npm package metadata
+func npmRepositoryURLs(body []byte) []string {
+	var info struct {
+		Repository json.RawMessage `json:"repository"`
+		Homepage   string          `json:"homepage"`
+		Bugs       struct {
+			URL string `json:"url"`
+		} `json:"bugs"`
+	}
+	if json.Unmarshal(body, &info) != nil {
+		return nil
+	}
+	var urls []string
+	// repository is either a string shorthand or an object with a url
+	var repoURL string
+	if json.Unmarshal(info.Repository, &repoURL) == nil {
+		if !strings.Contains(repoURL, ":") && ownerRepoPattern.MatchString(repoURL) {
+			repoURL = "github.com/" + repoURL
+		}
+		urls = append(urls, repoURL)
+	} else {
+		var repoObject struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(info.Repository, &repoObject) == nil {
+			urls = append(urls, repoObject.URL)
+		}
+	}
+	return append(urls, info.Homepage, info.Bugs.URL)
+}
+
+// pythonRepositoryURLs returns repository candidates from PyPI package metadata
+func pythonRepositoryURLs(body []byte) []string {
+	var info struct {
+		Info struct {
+			HomePage    string            `json:"home_page"`
+			ProjectURLs map[string]string `json:"project_urls"`
+		} `json:"info"`
+	}
+	if json.Unmarshal(body, &info) != nil {
+		return nil
+	}
+	var urls []string
+	// Prefer links that are labelled as source code over homepages and documentation
+	for _, label := range []string{"source", "source code", "repository", "code", "github", "changelog", "changes", "release notes", "homepage"} {
+		for key, value := range info.Info.ProjectURLs {
+			if strings.EqualFold(key, label) {
+				urls = append(urls, value)
+			}
+		}
+	}
+	for _, value := range info.Info.ProjectURLs {
+		urls = append(urls, value)
+	}
+	return append(urls, info.Info.HomePage)
+}
+
+// rustRepositoryURLs returns repository candidates from crates.io metadata
+func rustRepositoryURLs(body []byte) []string {
+	var info struct {
+		Crate struct {
+			Repository string `json:"repository"`
+			Homepage   string `json:"homepage"`
+		} `json:"crate"`
+	}
+	if json.Unmarshal(body, &info) != nil {
+		return nil
+	}
+	return []string{info.Crate.Repository, info.Crate.Homepage}
+}
+
+// goRepositoryURLs returns repository candidates from the Go module proxy, which records
+// the VCS origin for modules fetched with recent Go versions
+func goRepositoryURLs(body []byte) []string {
+	var info struct {
+		Origin struct {
+			URL string `json:"URL"`
+		} `json:"Origin"`
+	}
+	if json.Unmarshal(body, &info) != nil {
+		return nil
+	}
+	return []string{info.Origin.URL}
+}
@@ -0,0 +1,228 @@
+package releasenotes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// noteKind classifies a change
+type noteKind int
+
+const (
+	kindChange noteKind = iota
+	kindBreaking
+	kindDeprecation
+	kindSecurity
+)
+
+// note is a single change extracted from release notes
+type note struct {
+	Kind    noteKind
+	Version string
+	Text    string
+}
+
+// condensedRelease is a release reduced to its notable changes
+type condensedRelease struct {
+	release
+	Notes      []note
+	TotalItems int
+}
+
+// summary is the condensed view of a version range
+type summary struct {
+	Package  string
+	Repo     string
+	Source   string
+	From     string
+	To       string
+	Releases []condensedRelease
+}
+
+// condense extracts list items from a release body and classifies them using both the section
+// they appear under and their own wording
+func condense(r release) condensedRelease {
+	result := condensedRelease{release: r}
+	section := kindChange
+	var prose []string
+
+	for line := range strings.Lines(r.Body) {
+		line = strings.TrimRight(line, "\r\n")
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			section = sectionKind(match[2])
+			continue
+		}
+		// Bold lines such as "**Breaking Changes**" are often used as headings
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "**") && strings.HasSuffix(trimmed, "**") && len(trimmed) < 80 {
+			section = sectionKind(trimmed)
+			continue
+		}
+
+		match := itemPattern.FindStringSubmatch(line)
+		if match == nil {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && len(prose) < 3 && !strings.HasPrefix(trimmed, "<") && !strings.HasPrefix(trimmed, "|") {
+				prose = append(prose, trimmed)
+			}
+			continue
+		}
+		// Skip nested items, which are usually detail for the parent item
+		if strings.HasPrefix(line, "   ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		text := cleanItem(match[1])
+		if text == "" || strings.HasPrefix(text, "Full Changelog") || strings.HasPrefix(text, "@") && strings.Contains(text, "made their first contribution") {
+			continue
+		}
+		result.TotalItems++
+
+		kind := section
+		if kind == kindChange {
+			kind = itemKind(text)
+		}
+		result.Notes = append(result.Notes, note{Kind: kind, Version: r.Version, Text: text})
+	}
+
+	// Releases written as prose rather than lists are summarised by their first lines
+	if result.TotalItems == 0 {
+		for _, line := range prose {
+			result.Notes = append(result.Notes, note{Kind: itemKind(line), Version: r.Version, Text: truncate(line)})
+		}
+		result.TotalItems = len(result.Notes)
+	}
+	return result
+}
+
+// sectionKind classifies a heading
+func sectionKind(heading string) noteKind {
+	switch {
+	case breakingSectionPattern.MatchString(heading):
+		return kindBreaking
+	case deprecationSectionPattern.MatchString(heading):
+		return kindDeprecation
+	case securitySectionPattern.MatchString(heading):
+		return kindSecurity
+	}
+	return kindChange
+}
+
+// itemKind classifies a list item that is not under a classified heading
+func itemKind(text string) noteKind {
+	switch {
+	case breakingItemPattern.MatchString(text):
+		return kindBreaking
+	case deprecationItemPattern.MatchString(text):
+		return kindDeprecation
+	case securityItemPattern.MatchString(text):
+		return kindSecurity
+	}
+	return kindChange
+}
+
+// cleanItem strips attribution suffixes and truncates a list item
+func cleanItem(text string) string {
+	text = strings.TrimSpace(attributionPattern.ReplaceAllString(text, ""))
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "[ ] "), "[x] ")
+	return truncate(text)
+}
+
+// truncate shortens text to maxItemLength characters
+func truncate(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxItemLength {
+		return text
+	}
+	return string(runes[:maxItemLength-1]) + "…"
+}
+
+// render formats the summary as markdown, with breaking changes, deprecations and security
+// fixes gathered across the range before the per-release highlights
+func (s summary) render(maxItemsPerRelease int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Release notes: %s\n\n", s.Package)
+	fmt.Fprintf(&sb, "- Repository: https://github.com/%s\n", s.Repo)
+	fmt.Fprintf(&sb, "- Source: %s\n", s.Source)
+	fmt.Fprintf(&sb, "- Range: %s → %s (%d releases)\n", valueOr(s.From, "earliest"), valueOr(s.To, "latest"), len(s.Releases))
+
+	if len(s.Releases) == 0 {
+		sb.WriteString("\nNo releases found in this range.\n")
+		return sb.String()
+	}
+
+	for _, section := range []struct {
+		kind  noteKind
+		title string
+	}{
+		{kindBreaking, "Breaking changes"},
+		{kindDeprecation, "Deprecations"},
+		{kindSecurity, "Security"},
+	} {
+		var items []string
+		for _, r := range s.Releases {
+			for _, n := range r.Notes {
+				if n.Kind == section.kind {
+					items = append(items, fmt.Sprintf("- **%s**: %s\n", n.Version, n.Text))
+				}
+			}
+		}
+		if len(items) > 0 {
+			fmt.Fprintf(&sb, "\n## %s (%d)\n\n%s", section.title, len(items), strings.Join(items, ""))
+		}
+	}
+
+	sb.WriteString("\n## Releases\n")
+	for _, r := range s.Releases {
+		fmt.Fprintf(&sb, "\n### %s", r.Version)
+		if r.Date != "" {
+			fmt.Fprintf(&sb, " (%s)", r.Date)
+		}
+		sb.WriteString("\n\n")
+
+		shown := 0
+		for _, n := range r.Notes {
+			if n.Kind != kindChange {
+				continue
+			}
+			if shown >= maxItemsPerRelease {
+				break
+			}
+			fmt.Fprintf(&sb, "- %s\n", n.Text)
+			shown++
+		}
+		highlighted := len(r.Notes) - countKind(r.Notes, kindChange)
+		if remaining := r.TotalItems - shown - highlighted; remaining > 0 {
+			fmt.Fprintf(&sb, "- … %d more changes: %s\n", remaining, r.URL)
+		}
+		if shown == 0 && highlighted == 0 {
+			fmt.Fprintf(&sb, "- No notes: %s\n", r.URL)
+		} else if shown == 0 {
+			sb.WriteString("- See highlighted changes above\n")
+		}
+	}
+
+	output := sb.String()
+	if len(output) > maxOutputChars {
+		cut := strings.LastIndex(output[:maxOutputChars], "\n")
+		output = output[:cut] + "\n\n… output truncated - narrow the version range to see more\n"
+	}
+	return output
+}
+
+// countKind counts notes of a kind
+func countKind(notes []note, kind noteKind) int {
+	count := 0
+	for _, n := range notes {
+		if n.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
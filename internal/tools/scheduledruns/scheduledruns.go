@@ -0,0 +1,284 @@
+package scheduledruns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/scheduler"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 200
+)
+
+// ScheduledRunsTool reports the scheduler's schedules and run history, and reads run outputs
+type ScheduledRunsTool struct{}
+
+// init registers the scheduled runs tool
+func init() {
+	registry.Register(&ScheduledRunsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ScheduledRunsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"scheduled_runs",
+		mcp.WithDescription(`Inspect tool calls the server runs on a schedule, such as nightly exports or weekly vulnerability scans, configured by the user in ~/.mcp-devtools/schedules.yaml, and tool calls triggered through the server's webhooks.
+
+Functions:
+- schedules: configured schedules with their next and latest runs
+- history: recent runs, newest first, with their status and output artifact
+- output: read a run's saved output in parts`),
+		mcp.WithString("function",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("schedules", "history", "output"),
+		),
+		mcp.WithString("schedule",
+			mcp.Description("history: only list runs of this schedule or hook"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("history: most runs to return (default: %d, max: %d)", defaultHistoryLimit, maxHistoryLimit)),
+		),
+		mcp.WithString("run_id",
+			mcp.Description("output: the run whose output to read, from history"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("output: byte offset to start reading from (default: 0)"),
+		),
+		mcp.WithNumber("length",
+			mcp.Description("output: maximum number of bytes to return (default and max: the output page size)"),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the configuration, history and outputs
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // Results change as schedules run
+		mcp.WithOpenWorldHintAnnotation(false),   // Local files only
+	)
+}
+
+// Execute runs the requested function
+func (t *ScheduledRunsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	function, _ := args["function"].(string)
+	logger.WithField("function", function).Debug("Inspecting scheduled runs")
+	switch function {
+	case "":
+		return nil, fmt.Errorf("missing required parameter: function")
+	case "schedules":
+		response, err := schedules()
+		if err != nil {
+			return nil, err
+		}
+		return encode(response)
+	case "history":
+		response, err := history(args)
+		if err != nil {
+			return nil, err
+		}
+		return encode(response)
+	case "output":
+		return output(ctx, args)
+	default:
+		return nil, fmt.Errorf("unknown function: %s", function)
+	}
+}
+
+// schedules describes the configured schedules, including invalid ones
+func schedules() (*SchedulesResponse, error) {
+	configPath, err := scheduler.ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := scheduler.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	response := &SchedulesResponse{Running: scheduler.Active(), ConfigPath: configPath, Schedules: []ScheduleInfo{}}
+	if !response.Running {
+		response.Warnings = append(response.Warnings, "The scheduler is not running in this server. Schedules only run while the server uses the http or sse transport.")
+	}
+	if len(config.Schedules) == 0 {
+		response.Warnings = append(response.Warnings, "No schedules are configured.")
+	}
+
+	valid, invalid := config.Validated()
+	enabled := registry.GetEnabledTools()
+	now := time.Now()
+	for _, schedule := range valid {
+		info := ScheduleInfo{
+			Name:        schedule.Name,
+			Schedule:    schedule.Cron,
+			Explanation: schedule.Explain(),
+			Timezone:    schedule.Timezone,
+			Tool:        schedule.Tool,
+			Arguments:   schedule.Arguments,
+			Disabled:    schedule.Disabled,
+		}
+		if info.Timezone == "" {
+			info.Timezone = time.Local.String()
+		}
+		if next, ok := schedule.Next(now); ok && !schedule.Disabled {
+			info.NextRun = next.Format(time.RFC3339)
+		}
+		if _, ok := enabled[schedule.Tool]; !ok {
+			info.Warning = fmt.Sprintf("Tool %s is not enabled, so runs will fail. Add it to ENABLE_ADDITIONAL_TOOLS.", schedule.Tool)
+		}
+		if runs, err := scheduler.History(schedule.Name, 1); err == nil && len(runs) > 0 {
+			info.LastRun = &runs[0]
+		}
+		response.Schedules = append(response.Schedules, info)
+	}
+	for name, err := range invalid {
+		response.Invalid = append(response.Invalid, InvalidInfo{Name: name, Error: err.Error()})
+	}
+	slices.SortFunc(response.Invalid, func(a, b InvalidInfo) int { return strings.Compare(a.Name, b.Name) })
+	return response, nil
+}
+
+// history lists recent runs, newest first
+func history(args map[string]any) (*HistoryResponse, error) {
+	limit := defaultHistoryLimit
+	if value, ok := args["limit"].(float64); ok {
+		if value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("limit must be a whole number of at least 1, got: %v", value)
+		}
+		limit = min(int(value), maxHistoryLimit)
+	}
+	name, _ := args["schedule"].(string)
+	runs, err := scheduler.History(strings.TrimSpace(name), limit)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []scheduler.Run{}
+	}
+	return &HistoryResponse{Runs: runs}, nil
+}
+
+// output returns part of a run's saved output
+func output(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	runID, _ := args["run_id"].(string)
+	runID = strings.TrimSpace(runID)
+	if runID == "" {
+		return nil, fmt.Errorf("missing required parameter: run_id")
+	}
+	run, err := scheduler.FindRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.ArtifactID == "" {
+		if run.Status == scheduler.StatusFailed {
+			return nil, fmt.Errorf("run %s failed and has no output: %s", runID, run.Error)
+		}
+		return nil, fmt.Errorf("run %s produced no output", runID)
+	}
+	artifact, err := artifacts.Get(artifacts.WithSession(ctx, artifacts.ScheduledSession), run.ArtifactID)
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return nil, fmt.Errorf("the output of run %s has expired; outputs are kept for ARTIFACTS_TTL_HOURS (default 7 days)", runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Each part stays within the page size, so reading an output is never paginated or summarised again
+	pageSize := int64(tools.OutputPageSize())
+	if pageSize == 0 {
+		pageSize = artifact.Size
+	}
+	offset := int64(0)
+	if value, ok := args["offset"].(float64); ok && value > 0 {
+		offset = int64(value)
+	}
+	length := pageSize
+	if value, ok := args["length"].(float64); ok && value >= 1 {
+		length = min(int64(value), pageSize)
+	}
+	if offset >= artifact.Size && artifact.Size > 0 {
+		return nil, fmt.Errorf("offset %d is beyond the end of the output of run %s, which is %d bytes", offset, runID, artifact.Size)
+	}
+	data, err := artifacts.Read(artifact, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	end := offset + int64(len(data))
+	// Leave a character cut in half for the next part
+	if end < artifact.Size {
+		for i := len(data) - 1; i > 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					data = data[:i]
+					end = offset + int64(i)
+				}
+				break
+			}
+		}
+	}
+
+	var text strings.Builder
+	text.Write(data)
+	if end < artifact.Size {
+		fmt.Fprintf(&text, "\n\n[Run %s: showing bytes %d-%d of %d. Call scheduled_runs output with offset %d for the next part]", runID, offset+1, end, artifact.Size, end)
+	}
+	return mcp.NewToolResultText(text.String()), nil
+}
+
+// encode formats a response as indented JSON
+func encode(response any) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the scheduled runs tool
+func (t *ScheduledRunsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "When the user asks about the results of a recurring job run by this server, such as last night's export or this week's vulnerability scan, or whether a scheduled job is working.",
+		WhenNotToUse: "To create or change schedules - the user edits schedules.yaml and restarts the server. To run a tool now - call the tool directly.",
+		CommonPatterns: []string{
+			"Call history with a schedule name to find its latest run, then output with its run_id to read the result",
+			"Call schedules to check when a job next runs and whether its latest run failed",
+		},
+		ParameterDetails: map[string]string{
+			"function": "schedules reads schedules.yaml. history lists recorded runs. output reads a run's saved output.",
+			"run_id":   "The id of a run from history, e.g. 20261018-020000-3f9a1c0e.",
+			"offset":   "Byte offset given at the end of the previous part.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "See the latest runs of a nightly export",
+				Arguments:      map[string]any{"function": "history", "schedule": "nightly-docs-export", "limit": 5},
+				ExpectedResult: "Up to five runs with their start time, duration, status, error and output artifact",
+			},
+			{
+				Description:    "Read a run's output",
+				Arguments:      map[string]any{"function": "output", "run_id": "20261018-020000-3f9a1c0e"},
+				ExpectedResult: "The first part of the output the scheduled tool call returned",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The scheduler is not running",
+				Solution: "Schedules only run while the server uses the http or sse transport. With stdio, the server only runs while a client is connected.",
+			},
+			{
+				Problem:  "Runs fail because the tool is not enabled",
+				Solution: "Scheduled tools must be enabled in the server, e.g. by adding them to ENABLE_ADDITIONAL_TOOLS.",
+			},
+		},
+	}
+}
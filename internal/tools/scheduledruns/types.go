@@ -0,0 +1,37 @@
+package scheduledruns
+
+import "github.com/sammcj/mcp-devtools/internal/scheduler"
+
+// SchedulesResponse is the result of the schedules function
+type SchedulesResponse struct {
+	Running    bool           `json:"scheduler_running"`
+	ConfigPath string         `json:"config_path"`
+	Schedules  []ScheduleInfo `json:"schedules"`
+	Invalid    []InvalidInfo  `json:"invalid,omitempty"`
+	Warnings   []string       `json:"warnings,omitempty"`
+}
+
+// ScheduleInfo describes a configured schedule and its latest run
+type ScheduleInfo struct {
+	Name        string         `json:"name"`
+	Schedule    string         `json:"schedule"`
+	Explanation string         `json:"explanation"`
+	Timezone    string         `json:"timezone"`
+	Tool        string         `json:"tool"`
+	Arguments   map[string]any `json:"arguments,omitempty"`
+	Disabled    bool           `json:"disabled,omitempty"`
+	NextRun     string         `json:"next_run,omitempty"`
+	LastRun     *scheduler.Run `json:"last_run,omitempty"`
+	Warning     string         `json:"warning,omitempty"`
+}
+
+// InvalidInfo describes a schedule that is skipped because its configuration is invalid
+type InvalidInfo struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// HistoryResponse is the result of the history function
+type HistoryResponse struct {
+	Runs []scheduler.Run `json:"runs"`
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultScratchQuotaMB is the most disk space, in MB, one session's scratch directory may use
+	DefaultScratchQuotaMB = 1024
+
+	// ScratchQuotaEnvVar overrides DefaultScratchQuotaMB
+	ScratchQuotaEnvVar = "SCRATCH_QUOTA_MB"
+
+	// scratchLocalSession is the scratch directory used outside an MCP session
+	scratchLocalSession = "local"
+
+	// staleScratchAge is how long another process's scratch directory must go unused before it is
+	// treated as left behind by a crash and removed
+	staleScratchAge = 24 * time.Hour
+)
+
+// ErrScratchQuotaExceeded is returned when a session's scratch directory is already at its quota
+var ErrScratchQuotaExceeded = errors.New("scratch directory quota exceeded")
+
+var (
+	scratchMu   sync.Mutex
+	scratchRoot string
+)
+
+// ScratchDir returns the calling session's scratch directory, for temporary files that only need to
+// live as long as the session. It is created on first use, removed when the session ends or the server
+// shuts down, and returns ErrScratchQuotaExceeded once the session's files reach the quota.
+func ScratchDir(ctx context.Context) (string, error) {
+	scratchMu.Lock()
+	defer scratchMu.Unlock()
+
+	root, err := scratchRootLocked()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, scratchSession(ctx))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	// Mark the process's directory as in use, so other processes do not treat it as stale
+	now := time.Now()
+	_ = os.Chtimes(root, now, now)
+
+	quota := ScratchQuota()
+	if usage := dirSize(dir); usage >= quota {
+		return "", fmt.Errorf("%w: the session is using %d of %d bytes, set %s to raise it", ErrScratchQuotaExceeded, usage, quota, ScratchQuotaEnvVar)
+	}
+	return dir, nil
+}
+
+// ScratchFile creates a new file in the calling session's scratch directory, as os.CreateTemp does
+func ScratchFile(ctx context.Context, pattern string) (*os.File, error) {
+	dir, err := ScratchDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, pattern)
+}
+
+// ScratchSubdir creates a new directory in the calling session's scratch directory, as os.MkdirTemp
+// does. Callers that are done with it before the session ends should remove it.
+func ScratchSubdir(ctx context.Context, pattern string) (string, error) {
+	dir, err := ScratchDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(dir, pattern)
+}
+
+// ScratchQuota returns the most disk space, in bytes, one session's scratch directory may use
+func ScratchQuota() int64 {
+	if value := os.Getenv(ScratchQuotaEnvVar); value != "" {
+		if mb, err := strconv.ParseInt(value, 10, 64); err == nil && mb > 0 {
+			return mb * 1024 * 1024
+		}
+	}
+	return DefaultScratchQuotaMB * 1024 * 1024
+}
+
+// RegisterScratch removes each session's scratch directory when the session ends
+func RegisterScratch(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		RemoveScratch(session.SessionID())
+	})
+}
+
+// RemoveScratch removes a session's scratch directory
+func RemoveScratch(sessionID string) {
+	scratchMu.Lock()
+	defer scratchMu.Unlock()
+	if scratchRoot != "" {
+		_ = os.RemoveAll(filepath.Join(scratchRoot, safeSessionName(sessionID)))
+	}
+}
+
+// CleanupScratch removes every scratch directory of this process, on shutdown
+func CleanupScratch() {
+	scratchMu.Lock()
+	defer scratchMu.Unlock()
+	if scratchRoot != "" {
+		_ = os.RemoveAll(scratchRoot)
+		scratchRoot = ""
+	}
+}
+
+// scratchRootLocked returns this process's scratch directory, creating it and removing directories
+// left behind by processes that did not shut down cleanly on first use. Caller must hold scratchMu.
+func scratchRootLocked() (string, error) {
+	if scratchRoot != "" {
+		return scratchRoot, nil
+	}
+	parent := filepath.Join(os.TempDir(), "mcp-devtools-scratch")
+	if err := os.MkdirAll(parent, 0700); err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	root, err := os.MkdirTemp(parent, strconv.Itoa(os.Getpid())+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+
+	if entries, err := os.ReadDir(parent); err == nil {
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > staleScratchAge {
+				_ = os.RemoveAll(filepath.Join(parent, entry.Name()))
+			}
+		}
+	}
+
+	scratchRoot = root
+	return root, nil
+}
+
+// scratchSession returns the name of the calling session's scratch directory
+func scratchSession(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return safeSessionName(session.SessionID())
+	}
+	return scratchLocalSession
+}
+
+// safeSessionName reduces a session ID to characters that are safe in a directory name
+func safeSessionName(sessionID string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, sessionID)
+	if name == "" {
+		return scratchLocalSession
+	}
+	return name
+}
+
+// dirSize returns the total size of the files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
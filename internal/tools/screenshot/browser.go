@@ -0,0 +1,221 @@
+package screenshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+// browserCandidates are executable names searched on PATH, in order of preference
+var browserCandidates = []string{
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"chrome",
+	"headless_shell",
+	"microsoft-edge",
+}
+
+// macBrowserPaths are application bundle paths checked on macOS
+var macBrowserPaths = []string{
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge",
+}
+
+// findBrowser locates a Chromium-based browser, preferring SCREENSHOT_BROWSER_PATH
+func findBrowser() (string, error) {
+	if path := os.Getenv(BrowserPathEnvVar); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%s is set to %s but it cannot be accessed: %w", BrowserPathEnvVar, path, err)
+		}
+		return path, nil
+	}
+	for _, name := range browserCandidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		for _, path := range macBrowserPaths {
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Chromium-based browser found - install Chromium or Google Chrome, or set %s to the browser executable", BrowserPathEnvVar)
+}
+
+// validateURL checks the scheme and domain of a URL the browser will load
+func validateURL(rawURL string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme: %s (only http and https are supported)", parsed.Scheme)
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("URL has no host: %s", rawURL)
+	}
+	if err := security.CheckDomainAccess(parsed.Hostname()); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// resolveRedirects follows HTTP redirects before the browser loads the page so every
+// hop is checked against the domain deny list. The browser is given the final URL.
+func resolveRedirects(ctx context.Context, target *url.URL) (*url.URL, error) {
+	client := httpclient.NewHTTPClientWithProxy(preflightTimeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to unsupported scheme: %s", req.URL.Scheme)
+		}
+		return security.CheckDomainAccess(req.URL.Hostname())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", target.String(), err)
+	}
+	_ = resp.Body.Close()
+
+	return resp.Request.URL, nil
+}
+
+// capture renders a URL with headless Chromium and returns the PNG bytes and the hosts the network
+// policy blocked while the page loaded
+func capture(ctx context.Context, logger *logrus.Logger, browser string, opts *options) ([]byte, []string, error) {
+	// Each capture gets its own profile so no cookies or storage leak between runs
+	workDir, err := tools.ScratchSubdir(ctx, "screenshot-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	proxy, err := startBrowserProxy()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start browser proxy: %w", err)
+	}
+	defer proxy.Close()
+
+	outputFile := filepath.Join(workDir, "screenshot.png")
+	args := []string{
+		"--headless=new",
+		"--disable-gpu",
+		"--hide-scrollbars",
+		"--mute-audio",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-extensions",
+		"--disable-background-networking",
+		"--disable-sync",
+		"--user-data-dir=" + filepath.Join(workDir, "profile"),
+		fmt.Sprintf("--window-size=%d,%d", opts.width, opts.height),
+		fmt.Sprintf("--force-device-scale-factor=%g", opts.scale),
+		fmt.Sprintf("--virtual-time-budget=%d", opts.waitMS),
+		"--user-agent=" + userAgent,
+		"--screenshot=" + outputFile,
+		// All traffic, including to loopback addresses, goes through the policy-checked proxy. The
+		// browser resolves no names itself and does not use QUIC or WebRTC UDP, which a proxy cannot carry.
+		"--proxy-server=http://" + proxy.Address(),
+		"--proxy-bypass-list=<-loopback>",
+		"--host-resolver-rules=MAP * ~NOTFOUND , EXCLUDE 127.0.0.1",
+		"--disable-quic",
+		"--force-webrtc-ip-handling-policy=disable_non_proxied_udp",
+	}
+	if opts.darkMode {
+		args = append(args, "--force-dark-mode", "--blink-settings=preferredColorScheme=0")
+	}
+	// Chromium refuses to start its sandbox as root, which is common in containers
+	if os.Geteuid() == 0 {
+		args = append(args, "--no-sandbox")
+	}
+	args = append(args, opts.url)
+
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout)
+	defer cancel()
+
+	logger.WithFields(logrus.Fields{
+		"browser": browser,
+		"url":     opts.url,
+	}).Debug("Capturing screenshot")
+
+	cmd := exec.CommandContext(ctx, browser, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, nil, fmt.Errorf("browser timed out after %s rendering %s", captureTimeout, opts.url)
+		}
+		return nil, nil, fmt.Errorf("browser failed: %w: %s", err, lastLines(stderr.String(), 5))
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("browser did not produce a screenshot: %s", lastLines(stderr.String(), 5))
+	}
+	blocked := proxy.Blocked()
+	if len(blocked) > 0 {
+		logger.WithField("hosts", blocked).Debug("Network policy blocked browser requests")
+	}
+	return data, blocked, nil
+}
+
+// lastLines returns the final n non-empty lines of browser output for error messages
+func lastLines(output string, n int) string {
+	var lines []string
+	for line := range strings.SplitSeq(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "; ")
+}
+
+// CheckPrerequisites reports whether a Chromium-based browser is available
+func (t *ScreenshotTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	check := tools.PrerequisiteCheck{Name: "browser"}
+	path, err := findBrowser()
+	if err != nil {
+		check.Status = tools.PrerequisiteFailed
+		check.Detail = err.Error()
+		check.Remediation = fmt.Sprintf("Set %s to the path of a Chromium-based browser", BrowserPathEnvVar)
+		if os.Getenv(BrowserPathEnvVar) == "" {
+			check.Detail = "no Chromium-based browser found"
+			check.Remediation = fmt.Sprintf("Install Chromium or Google Chrome, or set %s to the browser executable", BrowserPathEnvVar)
+		}
+		return []tools.PrerequisiteCheck{check}
+	}
+	check.Status = tools.PrerequisiteOK
+	check.Detail = "found at " + path
+	return []tools.PrerequisiteCheck{check}
+}
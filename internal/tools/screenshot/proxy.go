@@ -0,0 +1,212 @@
+package screenshot
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+)
+
+// hopHeaders are only meaningful between the browser and this proxy, so they are not forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// browserProxy is a local HTTP proxy the browser sends all its traffic through, so that the page,
+// its redirects and every sub-resource are checked against the network policy like any other
+// outbound request, and reach the network through the configured upstream proxy
+type browserProxy struct {
+	listener net.Listener
+	server   *http.Server
+	client   *http.Client
+
+	mu      sync.Mutex
+	blocked []string
+	tunnels map[net.Conn]struct{}
+}
+
+// startBrowserProxy starts a proxy listening on a random loopback port
+func startBrowserProxy() (*browserProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &browserProxy{
+		listener: listener,
+		client:   httpclient.New(httpclient.Options{MaxRetries: -1, MaxResponseBytes: -1}),
+		tunnels:  make(map[net.Conn]struct{}),
+	}
+	// Redirects are returned to the browser, which requests each hop through the proxy again
+	p.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = p.server.Serve(listener) }()
+	return p, nil
+}
+
+// Address returns the proxy's host:port
+func (p *browserProxy) Address() string {
+	return p.listener.Addr().String()
+}
+
+// Blocked returns the hosts the network policy refused, in the order first refused
+func (p *browserProxy) Blocked() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.blocked)
+}
+
+// Close stops the proxy and closes any open tunnels
+func (p *browserProxy) Close() {
+	_ = p.server.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for conn := range p.tunnels {
+		_ = conn.Close()
+	}
+	p.tunnels = nil
+}
+
+func (p *browserProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.URL.Hostname()
+	if r.Method == http.MethodConnect {
+		host, _, _ = net.SplitHostPort(r.Host)
+	}
+	// Sub-resources are held to the same domain deny list as the page itself
+	if err := security.CheckDomainAccess(host); err != nil {
+		p.fail(w, host, &httpclient.PolicyError{Err: err})
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r, host)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "only proxy requests are accepted", http.StatusBadRequest)
+		return
+	}
+	p.forward(w, r, host)
+}
+
+// forward sends a plain HTTP request on through the shared HTTP client
+func (p *browserProxy) forward(w http.ResponseWriter, r *http.Request, host string) {
+	req := r.Clone(r.Context())
+	req.RequestURI = ""
+	for _, header := range hopHeaders {
+		req.Header.Del(header)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.fail(w, host, err)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	for _, header := range hopHeaders {
+		resp.Header.Del(header)
+	}
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel relays a CONNECT tunnel, which carries HTTPS and WebSocket traffic
+func (p *browserProxy) tunnel(w http.ResponseWriter, r *http.Request, host string) {
+	upstream, err := httpclient.DialTunnel(r.Context(), r.Host)
+	if err != nil {
+		p.fail(w, host, err)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = upstream.Close()
+		http.Error(w, "tunnelling is not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		return
+	}
+	if !p.track(conn, upstream) {
+		return
+	}
+	defer p.untrack(conn, upstream)
+
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	// Either side closing ends the tunnel
+	<-done
+}
+
+// fail answers a request the proxy could not complete, recording hosts the network policy refused
+func (p *browserProxy) fail(w http.ResponseWriter, host string, err error) {
+	var policyErr *httpclient.PolicyError
+	if errors.As(err, &policyErr) {
+		p.mu.Lock()
+		if !slices.Contains(p.blocked, host) {
+			p.blocked = append(p.blocked, host)
+		}
+		p.mu.Unlock()
+		http.Error(w, policyErr.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+// track records an open tunnel's connections so Close can end it. It closes them and reports false
+// when the proxy is already closed.
+func (p *browserProxy) track(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tunnels == nil {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		return false
+	}
+	for _, conn := range conns {
+		p.tunnels[conn] = struct{}{}
+	}
+	return true
+}
+
+// untrack closes a finished tunnel's connections
+func (p *browserProxy) untrack(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range conns {
+		_ = conn.Close()
+		delete(p.tunnels, conn)
+	}
+}
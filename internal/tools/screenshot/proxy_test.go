@@ -0,0 +1,153 @@
+package screenshot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// withAccessControl installs a security manager with the given access controls for the test
+func withAccessControl(t *testing.T, access security.AccessControl) {
+	t.Helper()
+	manager, err := security.NewSecurityManagerWithRules(&security.SecurityRules{
+		Settings:      security.Settings{Enabled: true},
+		AccessControl: access,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := security.GlobalSecurityManager
+	security.GlobalSecurityManager = manager
+	t.Cleanup(func() { security.GlobalSecurityManager = previous })
+}
+
+// proxyClient returns a client that sends every request through the browser proxy
+func proxyClient(t *testing.T, proxy *browserProxy) *http.Client {
+	t.Helper()
+	proxyURL, err := url.Parse("http://" + proxy.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		Timeout:   10 * time.Second,
+	}
+}
+
+// connect opens a CONNECT tunnel through the proxy and returns the connection and response status
+func connect(t *testing.T, proxy *browserProxy, address string) (net.Conn, *bufio.Reader, int) {
+	t.Helper()
+	conn, err := net.Dial("tcp", proxy.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", address, address)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, reader, resp.StatusCode
+}
+
+func TestBrowserProxy_ForwardsAllowedRequests(t *testing.T) {
+	withAccessControl(t, security.AccessControl{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("page"))
+	}))
+	defer server.Close()
+
+	proxy, err := startBrowserProxy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+
+	resp, err := proxyClient(t, proxy).Get(server.URL + "/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "page" {
+		t.Fatalf("expected the redirected page, got %d %q", resp.StatusCode, body)
+	}
+
+	// Tunnels carry the browser's HTTPS traffic
+	conn, reader, status := connect(t, proxy, server.Listener.Addr().String())
+	defer func() { _ = conn.Close() }()
+	if status != http.StatusOK {
+		t.Fatalf("expected the tunnel to open, got %d", status)
+	}
+	_, _ = fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\n\r\n", server.Listener.Addr())
+	tunnelled, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(tunnelled.Body)
+	if string(body) != "page" {
+		t.Fatalf("expected the page through the tunnel, got %q", body)
+	}
+
+	if blocked := proxy.Blocked(); len(blocked) != 0 {
+		t.Fatalf("expected nothing blocked, got %v", blocked)
+	}
+}
+
+func TestBrowserProxy_BlocksByPolicy(t *testing.T) {
+	withAccessControl(t, security.AccessControl{
+		BlockPrivateNetworks: true,
+		DenyDomains:          []string{"denied.test"},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request should not reach the server")
+	}))
+	defer server.Close()
+
+	proxy, err := startBrowserProxy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+
+	resp, err := proxyClient(t, proxy).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || !strings.Contains(string(body), "private networks are blocked") {
+		t.Fatalf("expected a policy refusal, got %d %q", resp.StatusCode, body)
+	}
+
+	conn, _, status := connect(t, proxy, server.Listener.Addr().String())
+	_ = conn.Close()
+	if status != http.StatusForbidden {
+		t.Fatalf("expected the tunnel to be refused, got %d", status)
+	}
+
+	// The domain deny list applies to sub-resources as well as the page
+	conn, _, status = connect(t, proxy, "cdn.denied.test:443")
+	_ = conn.Close()
+	if status != http.StatusForbidden {
+		t.Fatalf("expected the denied domain to be refused, got %d", status)
+	}
+
+	if blocked := proxy.Blocked(); !slices.Equal(blocked, []string{"127.0.0.1", "cdn.denied.test"}) {
+		t.Fatalf("expected the blocked hosts to be recorded once each, got %v", blocked)
+	}
+}
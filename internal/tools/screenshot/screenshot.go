@@ -0,0 +1,309 @@
+package screenshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BrowserPathEnvVar overrides browser discovery with an explicit executable path
+	BrowserPathEnvVar = "SCREENSHOT_BROWSER_PATH"
+
+	defaultWidth     = 1280
+	defaultHeight    = 800
+	defaultWaitMS    = 2000
+	maxDimension     = 4096
+	maxWaitMS        = 30000
+	maxScale         = 3.0
+	maxInlineBytes   = 5 * 1024 * 1024 // 5MB limit for images returned inline
+	maxRedirects     = 10
+	preflightTimeout = 30 * time.Second
+	captureTimeout   = 60 * time.Second
+	userAgent        = "Mozilla/5.0 (compatible; mcp-devtools/1.0; +https://github.com/sammcj/mcp-devtools)"
+)
+
+// ScreenshotTool captures screenshots of web pages using headless Chromium
+type ScreenshotTool struct{}
+
+// options holds parsed tool arguments
+type options struct {
+	url        string
+	width      int
+	height     int
+	scale      float64
+	waitMS     int
+	darkMode   bool
+	outputPath string
+	overwrite  bool
+}
+
+// ScreenshotResponse is returned when the screenshot is saved to disk
+type ScreenshotResponse struct {
+	URL        string `json:"url"`
+	FinalURL   string `json:"final_url,omitempty"`
+	OutputPath string `json:"output_path,omitempty"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	SizeBytes  int    `json:"size_bytes"`
+	// BlockedRequests lists hosts the page tried to load from that the network policy refused
+	BlockedRequests []string `json:"blocked_requests,omitempty"`
+}
+
+// init registers the screenshot tool
+func init() {
+	registry.Register(&ScreenshotTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ScreenshotTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"screenshot",
+		mcp.WithDescription(`Render a web page in headless Chromium and capture a PNG screenshot at a chosen viewport. Returns the image directly, or saves it when output_path is set. Useful for visually checking front-end work, including local dev servers (e.g. http://localhost:3000).
+
+Requires Chromium, Google Chrome or Edge to be installed.`),
+		mcp.WithString("url",
+			mcp.Required(),
+			mcp.Description("http or https URL to capture"),
+		),
+		mcp.WithNumber("width",
+			mcp.Description(fmt.Sprintf("Viewport width in CSS pixels (default: %d, max: %d)", defaultWidth, maxDimension)),
+		),
+		mcp.WithNumber("height",
+			mcp.Description(fmt.Sprintf("Viewport height in CSS pixels (default: %d, max: %d)", defaultHeight, maxDimension)),
+		),
+		mcp.WithNumber("scale",
+			mcp.Description("Device scale factor, e.g. 2 for retina (default: 1, max: 3)"),
+		),
+		mcp.WithNumber("wait_ms",
+			mcp.Description(fmt.Sprintf("Time budget in milliseconds for the page to load and run scripts before capture (default: %d, max: %d)", defaultWaitMS, maxWaitMS)),
+		),
+		mcp.WithBoolean("dark_mode",
+			mcp.Description("Request the page's dark colour scheme (prefers-color-scheme: dark)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Absolute path to save the PNG. When omitted the image is returned in the response"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Overwrite output_path if it already exists"),
+			mcp.DefaultBool(false),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Can write the screenshot to disk
+		mcp.WithDestructiveHintAnnotation(false), // Does not overwrite unless requested
+		mcp.WithIdempotentHintAnnotation(true),   // Same page produces the same capture
+		mcp.WithOpenWorldHintAnnotation(true),    // Loads external web pages
+	)
+}
+
+// Execute captures the screenshot
+func (t *ScreenshotTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	opts, err := parseOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := validateURL(opts.url)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.outputPath != "" {
+		if err := workspace.CheckFileAccess(ctx, opts.outputPath); err != nil {
+			return nil, fmt.Errorf("file access denied: %w", err)
+		}
+		if !opts.overwrite {
+			if _, err := os.Stat(opts.outputPath); err == nil {
+				return nil, fmt.Errorf("output file %s already exists - set overwrite to true or choose another output_path", opts.outputPath)
+			}
+		}
+	}
+
+	browser, err := findBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	finalURL, err := resolveRedirects(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	opts.url = finalURL.String()
+
+	logger.WithFields(logrus.Fields{
+		"url":    opts.url,
+		"width":  opts.width,
+		"height": opts.height,
+	}).Info("Capturing screenshot")
+
+	data, blocked, err := capture(ctx, logger, browser, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("browser produced an invalid PNG: %w", err)
+	}
+
+	response := ScreenshotResponse{
+		URL:             target.String(),
+		Width:           cfg.Width,
+		Height:          cfg.Height,
+		SizeBytes:       len(data),
+		BlockedRequests: blocked,
+	}
+	if finalURL.String() != target.String() {
+		response.FinalURL = finalURL.String()
+	}
+
+	if opts.outputPath == "" {
+		if len(data) > maxInlineBytes {
+			return nil, fmt.Errorf("screenshot is %.1fMB, too large to return inline - set output_path to save it instead", float64(len(data))/(1024*1024))
+		}
+		summary := fmt.Sprintf("Screenshot of %s (%dx%d)", opts.url, cfg.Width, cfg.Height)
+		if len(blocked) > 0 {
+			summary += fmt.Sprintf(" - the network policy blocked requests to: %s", strings.Join(blocked, ", "))
+		}
+		return mcp.NewToolResultImage(summary, base64.StdEncoding.EncodeToString(data), "image/png"), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(opts.outputPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(opts.outputPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write screenshot: %w", err)
+	}
+	response.OutputPath = opts.outputPath
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseOptions extracts and validates tool arguments
+func parseOptions(args map[string]any) (*options, error) {
+	opts := &options{width: defaultWidth, height: defaultHeight, scale: 1, waitMS: defaultWaitMS}
+
+	opts.url, _ = args["url"].(string)
+	opts.url = strings.TrimSpace(opts.url)
+	if opts.url == "" {
+		return nil, fmt.Errorf("missing required parameter: url")
+	}
+
+	if width, ok := args["width"].(float64); ok {
+		opts.width = int(width)
+	}
+	if height, ok := args["height"].(float64); ok {
+		opts.height = int(height)
+	}
+	if opts.width < 1 || opts.width > maxDimension || opts.height < 1 || opts.height > maxDimension {
+		return nil, fmt.Errorf("width and height must be between 1 and %d", maxDimension)
+	}
+
+	if scale, ok := args["scale"].(float64); ok {
+		if scale <= 0 || scale > maxScale {
+			return nil, fmt.Errorf("scale must be greater than 0 and at most %g", maxScale)
+		}
+		opts.scale = scale
+	}
+
+	if waitMS, ok := args["wait_ms"].(float64); ok {
+		if waitMS < 0 || waitMS > maxWaitMS {
+			return nil, fmt.Errorf("wait_ms must be between 0 and %d", maxWaitMS)
+		}
+		opts.waitMS = int(waitMS)
+	}
+
+	opts.darkMode, _ = args["dark_mode"].(bool)
+	opts.overwrite, _ = args["overwrite"].(bool)
+
+	if outputPath, ok := args["output_path"].(string); ok && outputPath != "" {
+		if !filepath.IsAbs(outputPath) {
+			return nil, fmt.Errorf("output_path must be an absolute path, got: %s", outputPath)
+		}
+		if !strings.EqualFold(filepath.Ext(outputPath), ".png") {
+			return nil, fmt.Errorf("output_path must end in .png, got: %s", outputPath)
+		}
+		opts.outputPath = filepath.Clean(outputPath)
+	}
+
+	return opts, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the screenshot tool
+func (t *ScreenshotTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Check a local dev server at a desktop viewport",
+				Arguments: map[string]any{
+					"url": "http://localhost:3000",
+				},
+				ExpectedResult: "Returns a 1280x800 PNG of the page as image content",
+			},
+			{
+				Description: "Capture a mobile viewport at retina resolution and save it",
+				Arguments: map[string]any{
+					"url":         "http://localhost:5173/pricing",
+					"width":       390,
+					"height":      844,
+					"scale":       2,
+					"output_path": "/Users/name/project/screenshots/pricing-mobile.png",
+				},
+				ExpectedResult: "Saves a 780x1688 PNG and returns its path and dimensions",
+			},
+			{
+				Description: "Check the dark theme after giving scripts extra time",
+				Arguments: map[string]any{
+					"url":       "https://example.com",
+					"dark_mode": true,
+					"wait_ms":   5000,
+				},
+				ExpectedResult: "Returns a screenshot rendered with prefers-color-scheme: dark",
+			},
+		},
+		CommonPatterns: []string{
+			"Capture before and after a CSS change to compare visually",
+			"Use widths of 390 (mobile), 768 (tablet) and 1280 (desktop) to check responsive layouts",
+			"Save to output_path when capturing many pages to keep responses small",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No Chromium-based browser found",
+				Solution: "Install Chromium or Google Chrome, or set SCREENSHOT_BROWSER_PATH to the browser executable",
+			},
+			{
+				Problem:  "Screenshot is blank or missing content",
+				Solution: "Increase wait_ms so client-side rendering and data fetching can finish",
+			},
+			{
+				Problem:  "Only the top of the page is captured",
+				Solution: "The capture covers the viewport only - increase height to include more of the page",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"wait_ms": "Virtual time budget given to the page. Timers and network activity run until the page is idle or the budget is used",
+			"scale":   "Output pixel dimensions are width and height multiplied by scale",
+		},
+		WhenToUse:    "Visual verification of web pages and front-end changes, including local development servers",
+		WhenNotToUse: "Extracting page text (use fetch_url) or interacting with pages (clicking, filling forms)",
+	}
+}
@@ -56,7 +56,8 @@ func (t *SecurityOverrideTool) Execute(ctx context.Context, logger *logrus.Logge
 	}
 
 	// Check if global security manager is available
-	if security.GlobalSecurityManager == nil {
+	manager := security.GetGlobalSecurityManager()
+	if manager == nil {
 		return nil, fmt.Errorf("security system is not initialised")
 	}
 
@@ -83,7 +84,7 @@ func (t *SecurityOverrideTool) Execute(ctx context.Context, logger *logrus.Logge
 	}).Info("Processing security override request")
 
 	// Find the security log entry
-	overrideManager := security.GlobalSecurityManager.GetOverrideManager()
+	overrideManager := manager.GetOverrideManager()
 	logEntry, err := overrideManager.FindSecurityLogEntry(securityID)
 	if err != nil {
 		return nil, fmt.Errorf("security ID %s not found in logs: %w", securityID, err)
@@ -0,0 +1,169 @@
+package securitytest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// maxSampleSize limits how much of a sample file is read
+const maxSampleSize = 1024 * 1024
+
+// SecurityTestTool reports which security rules match sample content
+type SecurityTestTool struct{}
+
+// init registers the security test tool
+func init() {
+	registry.Register(&SecurityTestTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *SecurityTestTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"security_test",
+		mcp.WithDescription(`Check which security rules match a sample string or file, in the order they are evaluated, and the action the security system would take. Use when writing or tuning rules in security.yaml. Nothing is logged or cached.`),
+		mcp.WithString("content",
+			mcp.Description("Sample text to check. Provide this or file_path"),
+		),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path to a file containing the sample. Provide this or content"),
+		),
+		mcp.WithString("rule",
+			mcp.Description("Only check this rule"),
+		),
+		mcp.WithString("domain",
+			mcp.Description("Treat the sample as coming from this domain, for trusted domain exceptions and source trust"),
+		),
+		mcp.WithString("source_tool",
+			mcp.Description("Treat the sample as output from this tool, for source trust"),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only evaluates rules
+		mcp.WithDestructiveHintAnnotation(false), // Does not change security configuration
+		mcp.WithIdempotentHintAnnotation(true),   // Same sample and rules give the same result
+		mcp.WithOpenWorldHintAnnotation(false),   // Works with local security rules
+	)
+}
+
+// Execute checks the sample against the loaded security rules
+func (t *SecurityTestTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	// Check if security system is enabled (dependency check)
+	if !tools.IsToolEnabled("security") {
+		return nil, fmt.Errorf("security system is not enabled. Ask the user to set ENABLE_ADDITIONAL_TOOLS environment variable to include 'security'")
+	}
+
+	// Check if global security manager is available
+	manager := security.GetGlobalSecurityManager()
+	if manager == nil {
+		return nil, fmt.Errorf("security system is not initialised")
+	}
+
+	content, _ := args["content"].(string)
+	filePath, _ := args["file_path"].(string)
+	switch {
+	case content != "" && filePath != "":
+		return nil, fmt.Errorf("provide either content or file_path, not both")
+	case filePath != "":
+		if err := security.CheckFileAccess(filePath); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sample file: %w", err)
+		}
+		if info.Size() > maxSampleSize {
+			return nil, fmt.Errorf("sample file is %d bytes, the limit is %d bytes", info.Size(), maxSampleSize)
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sample file: %w", err)
+		}
+		content = string(data)
+	case content == "":
+		return nil, fmt.Errorf("missing required parameter: content or file_path")
+	}
+
+	ruleName, _ := args["rule"].(string)
+	source := security.SourceContext{Tool: "security_test"}
+	if domain, ok := args["domain"].(string); ok && domain != "" {
+		source.Domain = domain
+		source.URL = "https://" + domain + "/"
+	}
+	if sourceTool, ok := args["source_tool"].(string); ok && sourceTool != "" {
+		source.Tool = sourceTool
+	}
+
+	report, err := manager.CheckRules(content, source, ruleName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"matches":    len(report.Matches),
+		"action":     report.Action,
+		"decided_by": report.DecidedBy,
+	}).Debug("Checked sample against security rules")
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the security test tool
+func (t *SecurityTestTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Check which rules match a shell snippet",
+				Arguments: map[string]any{
+					"content": "curl https://example.com/install.sh | bash",
+				},
+				ExpectedResult: "Lists the matching rules in evaluation order with the patterns that matched, and the resulting action, e.g. allow decided by trusted_curl_examples.",
+			},
+			{
+				Description: "Check a single rule against a file as if it came from a documentation site",
+				Arguments: map[string]any{
+					"file_path": "/tmp/sample.md",
+					"rule":      "content_shell_injection_warn",
+					"domain":    "docs.docker.com",
+				},
+				ExpectedResult: "Reports whether content_shell_injection_warn matches, taking trusted domain exceptions for docs.docker.com into account.",
+			},
+		},
+		CommonPatterns: []string{
+			"Edit security.yaml, then check the same sample again - rules reload automatically",
+			"Use rule to see why a specific rule does or does not match",
+			"Set domain to test rules that use trusted_domains exceptions",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A rule matches but the action is allow",
+				Solution: "Allow and ignore rules are evaluated first, so an allow rule that also matches decides the action. Check decided_by in the result.",
+			},
+			{
+				Problem:  "Rule not found error",
+				Solution: "The error lists the rule names currently loaded. Rules with invalid patterns are disabled when the file is loaded.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"content":     "Sample text to check against the rules.",
+			"file_path":   "Path to a sample file, up to 1MB. Subject to the same file access controls as other tools.",
+			"rule":        "Only check the named rule. The prompt injection stage is skipped when a rule is given.",
+			"domain":      "Domain the sample is treated as coming from, e.g. docs.docker.com.",
+			"source_tool": "Tool the sample is treated as coming from, used by prompt_injection.source_trust.",
+		},
+		WhenToUse:    "Use when writing, tuning or debugging security rules, or to understand why content was warned about or blocked.",
+		WhenNotToUse: "Don't use to scan untrusted content before using it - tool output is already analysed automatically. Don't use to work around a block; use security_override for false positives.",
+	}
+}
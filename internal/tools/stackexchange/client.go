@@ -0,0 +1,195 @@
+package stackexchange
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiBaseURL = "https://api.stackexchange.com/2.3"
+	// apiKeyEnvVar holds an optional app key, which raises the daily quota from 300 to 10,000 requests
+	apiKeyEnvVar = "STACKEXCHANGE_API_KEY"
+	// maxBackoffWait is the longest a request waits for a backoff the API asked for before giving up
+	maxBackoffWait = 15 * time.Second
+	// maxResponseBytes limits the size of an API response
+	maxResponseBytes = 10 * 1024 * 1024
+)
+
+// defaultClient is the shared HTTP client, which counts calls against USAGE_LIMIT_STACKEXCHANGE
+var defaultClient = sync.OnceValue(func() packageversions.HTTPClient {
+	return httpclient.New(httpclient.Options{Timeout: 30 * time.Second})
+})
+
+// httpClient returns the configured client, defaulting to the shared client
+func (t *StackExchangeTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return defaultClient()
+	}
+	return t.client
+}
+
+// call makes a GET request to an API method and decodes its items into out. Responses are cached for
+// cacheTTL, and the backoff the API asks for after a request is waited out before the next request
+// to the same method.
+func (t *StackExchangeTool) call(ctx context.Context, logger *logrus.Logger, cache *sync.Map, method, path string, params url.Values, out any) (bool, error) {
+	cacheKey := "stackexchange:" + path + "?" + params.Encode()
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < cacheTTL {
+			return entry.hasMore, decodeItems(entry.items, out)
+		}
+	}
+
+	if err := t.waitForBackoff(ctx, method); err != nil {
+		return false, err
+	}
+
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	if key := credentials.Lookup(apiKeyEnvVar, credentials.StackExchangeKey); key != "" {
+		query.Set("key", key)
+	}
+	reqURL := apiBaseURL + "/" + path + "?" + query.Encode()
+	parsed, err := url.Parse(reqURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if err := security.CheckDomainAccess(parsed.Hostname()); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return false, security.FormatSecurityBlockError(secErr)
+		}
+		return false, err
+	}
+
+	logger.WithFields(logrus.Fields{"method": method, "path": path}).Debug("Calling Stack Exchange API")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mcp-devtools/1.0")
+	resp, err := t.httpClient().Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call the Stack Exchange API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return false, fmt.Errorf("failed to read Stack Exchange API response: %w", err)
+	}
+	// The API compresses every response, whether or not it was asked to
+	if bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		if body, err = gunzip(body); err != nil {
+			return false, fmt.Errorf("failed to decompress Stack Exchange API response: %w", err)
+		}
+	}
+
+	var response apiResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		if resp.StatusCode >= 300 {
+			return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return false, fmt.Errorf("failed to parse Stack Exchange API response: %w", err)
+	}
+	t.record(method, response)
+	if response.ErrorID != 0 {
+		return false, apiError(response)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	cache.Store(cacheKey, cacheEntry{items: response.Items, hasMore: response.HasMore, fetchedAt: time.Now()})
+	return response.HasMore, decodeItems(response.Items, out)
+}
+
+// waitForBackoff waits until a method may be called again, or fails if that is more than
+// maxBackoffWait away
+func (t *StackExchangeTool) waitForBackoff(ctx context.Context, method string) error {
+	t.mu.Lock()
+	until := t.backoffUntil[method]
+	t.mu.Unlock()
+
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxBackoffWait {
+		return fmt.Errorf("the Stack Exchange API asked for no more %s requests for %s, try again later", method, wait.Round(time.Second))
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// record keeps the quota left and any backoff the API asked for
+func (t *StackExchangeTool) record(method string, response apiResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if response.QuotaMax > 0 {
+		t.quota = quota{remaining: response.QuotaRemaining, max: response.QuotaMax}
+	}
+	if response.Backoff > 0 {
+		if t.backoffUntil == nil {
+			t.backoffUntil = make(map[string]time.Time)
+		}
+		t.backoffUntil[method] = time.Now().Add(time.Duration(response.Backoff) * time.Second)
+	}
+}
+
+// currentQuota returns the quota reported by the last response, if any
+func (t *StackExchangeTool) currentQuota() quota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.quota
+}
+
+// apiError describes an error the API returned, suggesting a key when the quota is used up
+func apiError(response apiResponse) error {
+	err := fmt.Errorf("the Stack Exchange API returned error %d (%s): %s", response.ErrorID, response.ErrorName, response.ErrorMessage)
+	if response.ErrorName == "throttle_violation" && credentials.Lookup(apiKeyEnvVar, credentials.StackExchangeKey) == "" {
+		return fmt.Errorf("%w - set %s to raise the daily quota from 300 to 10,000 requests", err, apiKeyEnvVar)
+	}
+	return err
+}
+
+// decodeItems decodes a response's items, which may be absent when there are none
+func decodeItems(items json.RawMessage, out any) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(items, out); err != nil {
+		return fmt.Errorf("failed to parse Stack Exchange API items: %w", err)
+	}
+	return nil
+}
+
+// gunzip decompresses a gzip body
+func gunzip(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(io.LimitReader(reader, maxResponseBytes))
+}
@@ -0,0 +1,131 @@
+package stackexchange
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// results holds everything needed to render a response
+type results struct {
+	site          string
+	query         string
+	questions     []question
+	answers       map[int][]answer
+	hasMore       bool
+	maxBodyLength int
+	quota         quota
+	logger        *logrus.Logger
+}
+
+// render returns questions and their answers as markdown
+func render(r results) string {
+	var b strings.Builder
+	switch {
+	case r.query == "":
+		// A single question read by id needs no heading of its own
+	case len(r.questions) == 0:
+		fmt.Fprintf(&b, "No questions found on %s for: %s\n", r.site, r.query)
+	default:
+		more := ""
+		if r.hasMore {
+			more = " (more available)"
+		}
+		fmt.Fprintf(&b, "Found %d questions on %s for: %s%s\n", len(r.questions), r.site, r.query, more)
+	}
+
+	for i, q := range r.questions {
+		if b.Len() > 0 {
+			b.WriteString("\n---\n\n")
+		}
+		title := html.UnescapeString(q.Title)
+		if r.query != "" {
+			title = fmt.Sprintf("%d. %s", i+1, title)
+		}
+		fmt.Fprintf(&b, "# %s\n\n", title)
+		fmt.Fprintf(&b, "%s\n", q.Link)
+		fmt.Fprintf(&b, "Score %d · %d answers · %d views · asked %s by %s", q.Score, q.AnswerCount, q.ViewCount, formatDate(q.CreationDate), displayName(q.Owner))
+		if len(q.Tags) > 0 {
+			fmt.Fprintf(&b, " · tags: %s", strings.Join(q.Tags, ", "))
+		}
+		b.WriteString("\n")
+		if q.ClosedReason != "" {
+			fmt.Fprintf(&b, "Closed as %s\n", q.ClosedReason)
+		}
+		fmt.Fprintf(&b, "\n%s\n", r.body(q.Body, q.Link))
+
+		for _, a := range r.answers[q.QuestionID] {
+			label := "Answer"
+			if a.IsAccepted {
+				label = "Accepted answer"
+			}
+			link := answerLink(q.Link, a.AnswerID)
+			fmt.Fprintf(&b, "\n## %s (score %d, by %s, %s)\n\n%s\n\n%s\n", label, a.Score, displayName(a.Owner), formatDate(a.CreationDate), link, r.body(a.Body, link))
+		}
+		if q.AnswerCount == 0 {
+			b.WriteString("\n_No answers yet._\n")
+		}
+	}
+
+	if len(r.questions) > 0 {
+		b.WriteString("\n---\n\nPosts are by Stack Exchange users, licensed under CC BY-SA. Credit the author and link to the post when reusing them.\n")
+	}
+	if r.quota.max > 0 {
+		fmt.Fprintf(&b, "API quota: %d of %d requests left today\n", r.quota.remaining, r.quota.max)
+	}
+	return b.String()
+}
+
+// body converts a post's HTML body to markdown, cutting it at maxBodyLength characters. Posts are
+// converted as they are, without the clean-up fetch_url gives web pages, which drops lines that look
+// like navigation.
+func (r results) body(body, link string) string {
+	markdown, err := htmltomarkdown.ConvertString(body)
+	if err != nil {
+		r.logger.WithError(err).Debug("Failed to convert post to markdown, returning its text")
+		markdown = html.UnescapeString(body)
+	}
+	markdown = strings.TrimSpace(markdown)
+	if len(markdown) <= r.maxBodyLength {
+		return markdown
+	}
+
+	cut := r.maxBodyLength
+	for cut > 0 && markdown[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	if newline := strings.LastIndex(markdown[:cut], "\n"); newline > r.maxBodyLength/2 {
+		cut = newline
+	}
+	return fmt.Sprintf("%s\n\n[Truncated: showing %d of %d characters. Full post: %s]", strings.TrimSpace(markdown[:cut]), cut, len(markdown), link)
+}
+
+// answerLink returns the short link to an answer on the question's site
+func answerLink(questionLink string, answerID int) string {
+	parsed, err := url.Parse(questionLink)
+	if err != nil || parsed.Host == "" {
+		return questionLink
+	}
+	return fmt.Sprintf("%s://%s/a/%d", parsed.Scheme, parsed.Host, answerID)
+}
+
+// displayName returns a post author's name, which the API HTML-escapes
+func displayName(owner user) string {
+	if owner.DisplayName == "" {
+		return "a deleted user"
+	}
+	return html.UnescapeString(owner.DisplayName)
+}
+
+// formatDate formats a Unix timestamp from the API as a date
+func formatDate(unix int64) string {
+	if unix == 0 {
+		return "unknown date"
+	}
+	return time.Unix(unix, 0).UTC().Format("2006-01-02")
+}
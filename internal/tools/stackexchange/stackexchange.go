@@ -0,0 +1,399 @@
+package stackexchange
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cacheTTL                  = 1 * time.Hour
+	defaultSite               = "stackoverflow"
+	defaultLimit              = 5
+	maxLimit                  = 20
+	defaultAnswersPerQuestion = 2
+	maxAnswersPerQuestion     = 5
+	defaultMaxBodyLength      = 3000
+	minMaxBodyLength          = 200
+	// maxAnswersPerRequest is the largest page size the API allows
+	maxAnswersPerRequest = 100
+	// bodyFilter is the built-in API filter that adds the HTML body to questions and answers
+	bodyFilter = "withbody"
+)
+
+// Sort orders for questions
+const (
+	sortRelevance = "relevance"
+	sortVotes     = "votes"
+	sortActivity  = "activity"
+	sortCreation  = "creation"
+)
+
+// API methods, which the API sets a backoff for separately
+const (
+	searchMethod          = "search/advanced"
+	questionsMethod       = "questions"
+	questionAnswersMethod = "questions/answers"
+	answersMethod         = "answers"
+)
+
+// sitePattern matches Stack Exchange site parameters such as stackoverflow, serverfault or unix
+var sitePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*$`)
+
+// StackExchangeTool searches Stack Overflow and other Stack Exchange sites and returns questions with
+// their best answers as markdown
+type StackExchangeTool struct {
+	client packageversions.HTTPClient
+
+	mu sync.Mutex
+	// backoffUntil is when each API method may next be called, as asked for by the API
+	backoffUntil map[string]time.Time
+	quota        quota
+}
+
+// init registers the Stack Exchange tool
+func init() {
+	registry.Register(&StackExchangeTool{})
+}
+
+// NewStackExchangeTool creates a Stack Exchange tool that uses the given HTTP client
+func NewStackExchangeTool(client packageversions.HTTPClient) *StackExchangeTool {
+	return &StackExchangeTool{client: client}
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *StackExchangeTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"stackexchange",
+		mcp.WithDescription(`Search Stack Overflow or another Stack Exchange site and return the top questions with their accepted and most-voted answers as markdown.
+
+Use for error messages, library quirks and "how do I" questions where a community answer is likely. Pass question_id to read the answers to a question you already have.`),
+		mcp.WithString("query",
+			mcp.Description("Words to search question titles and bodies for, such as an error message. Required unless question_id is given"),
+		),
+		mcp.WithNumber("question_id",
+			mcp.Description("Read this question and its answers instead of searching"),
+		),
+		mcp.WithString("site",
+			mcp.Description("Stack Exchange site to search, e.g. stackoverflow, serverfault, superuser, unix, askubuntu (default: stackoverflow)"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Only return questions with all of these tags, e.g. [\"go\", \"goroutine\"]"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("accepted_only",
+			mcp.Description("Only return questions with an accepted answer (default: false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Order of questions (default: relevance)"),
+			mcp.Enum(sortRelevance, sortVotes, sortActivity, sortCreation),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Questions to return (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithNumber("answers_per_question",
+			mcp.Description(fmt.Sprintf("Answers to return per question, accepted answer first, then by votes (default: %d, max: %d)", defaultAnswersPerQuestion, maxAnswersPerQuestion)),
+		),
+		mcp.WithNumber("max_body_length",
+			mcp.Description(fmt.Sprintf("Characters of each question and answer to return (default: %d)", defaultMaxBodyLength)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads public questions and answers
+		mcp.WithDestructiveHintAnnotation(false), // No side effects
+		mcp.WithIdempotentHintAnnotation(true),   // Same search gives the same results until posts change
+		mcp.WithOpenWorldHintAnnotation(true),    // Calls the Stack Exchange API
+	)
+}
+
+// Execute searches for questions, or reads one, and returns them with their answers
+func (t *StackExchangeTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	query, _ := args["query"].(string)
+	query = strings.TrimSpace(query)
+	questionID := 0
+	if v, ok := args["question_id"].(float64); ok {
+		if v < 1 || v != float64(int(v)) {
+			return nil, fmt.Errorf("question_id must be a positive whole number, got: %v", v)
+		}
+		questionID = int(v)
+	}
+	if query == "" && questionID == 0 {
+		return nil, fmt.Errorf("missing required parameter: query (or question_id)")
+	}
+
+	site := defaultSite
+	if s, ok := args["site"].(string); ok && strings.TrimSpace(s) != "" {
+		site = strings.ToLower(strings.TrimSpace(s))
+		if !sitePattern.MatchString(site) {
+			return nil, fmt.Errorf("invalid site: %s", site)
+		}
+	}
+
+	sort := sortRelevance
+	if s, ok := args["sort"].(string); ok && s != "" {
+		if !slices.Contains([]string{sortRelevance, sortVotes, sortActivity, sortCreation}, s) {
+			return nil, fmt.Errorf("sort must be one of: relevance, votes, activity, creation")
+		}
+		sort = s
+	}
+
+	tags, err := parseTags(args["tags"])
+	if err != nil {
+		return nil, err
+	}
+	acceptedOnly, _ := args["accepted_only"].(bool)
+
+	limit, err := intArg(args, "limit", defaultLimit, 1, maxLimit)
+	if err != nil {
+		return nil, err
+	}
+	answersPerQuestion, err := intArg(args, "answers_per_question", defaultAnswersPerQuestion, 0, maxAnswersPerQuestion)
+	if err != nil {
+		return nil, err
+	}
+	maxBodyLength, err := intArg(args, "max_body_length", defaultMaxBodyLength, minMaxBodyLength, 100000)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"query":       query,
+		"question_id": questionID,
+		"site":        site,
+		"tags":        tags,
+	}).Debug("Searching Stack Exchange")
+
+	var questions []question
+	var hasMore bool
+	if questionID != 0 {
+		params := url.Values{"site": {site}, "filter": {bodyFilter}}
+		if _, err := t.call(ctx, logger, cache, questionsMethod, "questions/"+strconv.Itoa(questionID), params, &questions); err != nil {
+			return nil, err
+		}
+		if len(questions) == 0 {
+			return nil, fmt.Errorf("question %d not found on %s", questionID, site)
+		}
+	} else {
+		params := url.Values{
+			"site":     {site},
+			"q":        {query},
+			"sort":     {sort},
+			"order":    {"desc"},
+			"pagesize": {strconv.Itoa(limit)},
+			"filter":   {bodyFilter},
+		}
+		if len(tags) > 0 {
+			params.Set("tagged", strings.Join(tags, ";"))
+		}
+		if acceptedOnly {
+			params.Set("accepted", "True")
+		}
+		if hasMore, err = t.call(ctx, logger, cache, searchMethod, searchMethod, params, &questions); err != nil {
+			return nil, err
+		}
+	}
+
+	answers, err := t.fetchAnswers(ctx, logger, cache, site, questions, answersPerQuestion)
+	if err != nil {
+		return nil, err
+	}
+
+	output := render(results{
+		site:          site,
+		query:         query,
+		questions:     questions,
+		answers:       answers,
+		logger:        logger,
+		hasMore:       hasMore,
+		maxBodyLength: maxBodyLength,
+		quota:         t.currentQuota(),
+	})
+
+	// Questions and answers are written by the public, so scan them before returning them
+	sourceCtx := security.SourceContext{
+		Tool:        "stackexchange",
+		URL:         apiBaseURL,
+		ContentType: "text",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// fetchAnswers returns up to perQuestion answers for each question, its accepted answer first and then
+// the most voted. Answers are fetched for all the questions at once, and accepted answers with too few
+// votes to be among them are fetched by id.
+func (t *StackExchangeTool) fetchAnswers(ctx context.Context, logger *logrus.Logger, cache *sync.Map, site string, questions []question, perQuestion int) (map[int][]answer, error) {
+	byQuestion := make(map[int][]answer)
+	var ids []string
+	for _, q := range questions {
+		if q.AnswerCount > 0 {
+			ids = append(ids, strconv.Itoa(q.QuestionID))
+		}
+	}
+	if perQuestion == 0 || len(ids) == 0 {
+		return byQuestion, nil
+	}
+
+	var answers []answer
+	params := url.Values{
+		"site":     {site},
+		"sort":     {"votes"},
+		"order":    {"desc"},
+		"pagesize": {strconv.Itoa(maxAnswersPerRequest)},
+		"filter":   {bodyFilter},
+	}
+	if _, err := t.call(ctx, logger, cache, questionAnswersMethod, "questions/"+strings.Join(ids, ";")+"/answers", params, &answers); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, q := range questions {
+		if q.AcceptedAnswerID != 0 && !slices.ContainsFunc(answers, func(a answer) bool { return a.AnswerID == q.AcceptedAnswerID }) {
+			missing = append(missing, strconv.Itoa(q.AcceptedAnswerID))
+		}
+	}
+	if len(missing) > 0 {
+		var accepted []answer
+		params := url.Values{"site": {site}, "filter": {bodyFilter}}
+		if _, err := t.call(ctx, logger, cache, answersMethod, "answers/"+strings.Join(missing, ";"), params, &accepted); err != nil {
+			return nil, err
+		}
+		answers = append(answers, accepted...)
+	}
+
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], a)
+	}
+	for id, list := range byQuestion {
+		slices.SortStableFunc(list, func(a, b answer) int {
+			if a.IsAccepted != b.IsAccepted {
+				if a.IsAccepted {
+					return -1
+				}
+				return 1
+			}
+			return b.Score - a.Score
+		})
+		byQuestion[id] = list[:min(len(list), perQuestion)]
+	}
+	return byQuestion, nil
+}
+
+// parseTags reads the tags argument, as an array or a comma-separated string
+func parseTags(value any) ([]string, error) {
+	var raw []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		raw = strings.Split(v, ",")
+	case []string:
+		raw = v
+	case []any:
+		for _, item := range v {
+			tag, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("tags must be an array of strings")
+			}
+			raw = append(raw, tag)
+		}
+	default:
+		return nil, fmt.Errorf("tags must be an array of strings")
+	}
+
+	var tags []string
+	for _, tag := range raw {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// intArg reads a whole-number argument within a range, or returns its default
+func intArg(args map[string]any, key string, fallback, lowest, highest int) (int, error) {
+	v, ok := args[key].(float64)
+	if !ok {
+		return fallback, nil
+	}
+	if v < float64(lowest) || v > float64(highest) || v != float64(int(v)) {
+		return 0, fmt.Errorf("%s must be a whole number from %d to %d, got: %v", key, lowest, highest, v)
+	}
+	return int(v), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the Stack Exchange tool
+func (t *StackExchangeTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Find answers to an error message",
+				Arguments: map[string]any{
+					"query": "context deadline exceeded (Client.Timeout exceeded while awaiting headers)",
+					"tags":  []string{"go"},
+				},
+				ExpectedResult: "The five most relevant Go questions, each with its accepted and top-voted answers",
+			},
+			{
+				Description: "Search another Stack Exchange site for answered questions",
+				Arguments: map[string]any{
+					"query":         "systemd service restart on failure",
+					"site":          "unix",
+					"accepted_only": true,
+				},
+				ExpectedResult: "Questions from Unix & Linux with an accepted answer",
+			},
+			{
+				Description: "Read the answers to a known question",
+				Arguments: map[string]any{
+					"question_id":          11227809,
+					"answers_per_question": 3,
+				},
+				ExpectedResult: "The question and its three best answers",
+			},
+		},
+		CommonPatterns: []string{
+			"Quote the distinctive part of an error message as the query, without paths or ids from your machine",
+			"Add the language or library as a tag to leave out unrelated questions",
+			"Check an answer's date and score against the version you use; older accepted answers may be out of date",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "throttle_violation errors",
+				Solution: "Without a key the API allows 300 requests a day from each IP address. Set STACKEXCHANGE_API_KEY, or store it with: mcp-devtools credentials set stackexchange-key, to raise this to 10,000.",
+			},
+			{
+				Problem:  "No questions found",
+				Solution: "Shorten the query to the key words, remove tags, or sort by votes.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"site":                 "The site's API name: stackoverflow, serverfault, superuser, askubuntu, unix, dba, security, softwareengineering and so on.",
+			"tags":                 "Questions must have every tag listed.",
+			"answers_per_question": "0 returns questions only. The accepted answer is always first when there is one.",
+			"max_body_length":      "Longer questions and answers are cut at a line break, with a link to the full post.",
+		},
+		WhenToUse:    "Use for programming errors, tool behaviour and how-to questions that other developers are likely to have asked.",
+		WhenNotToUse: "Don't use for official API references (use docs_lookup) or for current news and releases (use internet_search or release_notes).",
+	}
+}
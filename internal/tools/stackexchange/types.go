@@ -0,0 +1,64 @@
+package stackexchange
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// apiResponse is the wrapper around every Stack Exchange API response
+type apiResponse struct {
+	Items          json.RawMessage `json:"items"`
+	HasMore        bool            `json:"has_more"`
+	QuotaMax       int             `json:"quota_max"`
+	QuotaRemaining int             `json:"quota_remaining"`
+	// Backoff is the number of seconds to wait before calling the same method again
+	Backoff      int    `json:"backoff"`
+	ErrorID      int    `json:"error_id"`
+	ErrorName    string `json:"error_name"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// user is the author of a question or answer
+type user struct {
+	DisplayName string `json:"display_name"`
+}
+
+// question is a question as returned with the withbody filter
+type question struct {
+	QuestionID       int      `json:"question_id"`
+	Title            string   `json:"title"`
+	Link             string   `json:"link"`
+	Score            int      `json:"score"`
+	AnswerCount      int      `json:"answer_count"`
+	ViewCount        int      `json:"view_count"`
+	AcceptedAnswerID int      `json:"accepted_answer_id"`
+	Tags             []string `json:"tags"`
+	CreationDate     int64    `json:"creation_date"`
+	ClosedReason     string   `json:"closed_reason"`
+	Body             string   `json:"body"`
+	Owner            user     `json:"owner"`
+}
+
+// answer is an answer as returned with the withbody filter
+type answer struct {
+	AnswerID     int    `json:"answer_id"`
+	QuestionID   int    `json:"question_id"`
+	Score        int    `json:"score"`
+	IsAccepted   bool   `json:"is_accepted"`
+	CreationDate int64  `json:"creation_date"`
+	Body         string `json:"body"`
+	Owner        user   `json:"owner"`
+}
+
+// quota is the number of API requests left today
+type quota struct {
+	remaining int
+	max       int
+}
+
+// cacheEntry stores the items of an API response
+type cacheEntry struct {
+	items     json.RawMessage
+	hasMore   bool
+	fetchedAt time.Time
+}
@@ -0,0 +1,342 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+)
+
+const (
+	// OutputSummariseEnvVar enables summarising oversized outputs instead of returning their first page
+	OutputSummariseEnvVar = "TOOL_OUTPUT_SUMMARISE"
+
+	// Limits on what a summary includes
+	summarySampleRows     = 5
+	summaryMaxHeadings    = 50
+	summaryMaxKeys        = 30
+	summarySampleLines    = 20
+	summaryMaxSampleBytes = 500
+	// delimitedSampleLines is how many lines are checked when detecting CSV or TSV
+	delimitedSampleLines = 20
+)
+
+// LimitOutput applies the configured handling to a tool result larger than the page size: a summary
+// when TOOL_OUTPUT_SUMMARISE is enabled, otherwise the first page when fetch_more is enabled. Other
+// results are returned unchanged.
+func LimitOutput(ctx context.Context, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if os.Getenv(OutputSummariseEnvVar) != "true" {
+		return Paginate(ctx, toolName, result)
+	}
+	return Summarise(ctx, toolName, result)
+}
+
+// Summarise replaces a text result larger than the page size with a summary of its structure and
+// samples of its content. The full output is saved as an artifact of the session, and can also be read
+// in pages with fetch_more when that tool is enabled. Results that would not be paginated are
+// returned unchanged.
+func Summarise(ctx context.Context, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	pageSize := OutputPageSize()
+	text, ok := oversizedText(result, pageSize)
+	if !ok {
+		return result
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "[Output from %s summarised: %d bytes in %d lines, more than the %d byte limit]\n\n",
+		toolName, len(text), strings.Count(text, "\n")+1, pageSize)
+	description, format := describeOutput(text)
+	summary.WriteString(description)
+
+	summary.WriteString("\n")
+	artifact, err := artifacts.Save(ctx, toolName, toolName+"-output"+format.extension, format.contentType,
+		"Full output summarised for size", []byte(text))
+	if err == nil {
+		fmt.Fprintf(&summary, "Full output: %s (artifact %s, read it with the artifacts tool if enabled)\n", artifact.Path, artifact.ID)
+	} else {
+		fmt.Fprintf(&summary, "Full output could not be saved: %v\n", err)
+	}
+	if token, ok := storeForFetchMore(ctx, toolName, text); ok {
+		fmt.Fprintf(&summary, "To read it in pages from the start, call fetch_more with continuation_token %q\n", token)
+	}
+
+	// The summary is kept within the page size, which samples of very wide rows could exceed
+	content := summary.String()
+	if len(content) > pageSize {
+		content = content[:pageEnd(content, 0, pageSize)]
+	}
+	return &mcp.CallToolResult{
+		Result:  result.Result,
+		Content: []mcp.Content{mcp.NewTextContent(content)},
+	}
+}
+
+// oversizedText returns the text of a result that is larger than the page size and can be paginated
+func oversizedText(result *mcp.CallToolResult, pageSize int) (string, bool) {
+	if pageSize == 0 || result == nil || result.IsError || result.StructuredContent != nil {
+		return "", false
+	}
+	texts := make([]string, 0, len(result.Content))
+	size := 0
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			return "", false
+		}
+		texts = append(texts, text.Text)
+		size += len(text.Text)
+	}
+	if size <= pageSize {
+		return "", false
+	}
+	return strings.Join(texts, "\n"), true
+}
+
+// storeForFetchMore keeps an output for fetch_more from its start, when pagination is enabled
+func storeForFetchMore(ctx context.Context, toolName, text string) (string, bool) {
+	continuationsMu.Lock()
+	defer continuationsMu.Unlock()
+	if !paginationEnabled {
+		return "", false
+	}
+
+	token, err := newContinuationToken()
+	if err != nil {
+		return "", false
+	}
+	entry := &continuation{tool: toolName, session: sessionID(ctx), text: text, lastRead: time.Now()}
+	if len(entry.text) > maxStoredOutputBytes {
+		entry.text = entry.text[:pageEnd(entry.text, 0, maxStoredOutputBytes)]
+		entry.truncated = true
+	}
+	storeContinuationLocked(token, entry)
+	return token, true
+}
+
+// outputFormat is the detected format of an output, used when saving it
+type outputFormat struct {
+	contentType string
+	extension   string
+}
+
+// describeOutput summarises JSON, CSV or TSV, or plain text and Markdown, returning the format found
+func describeOutput(text string) (string, outputFormat) {
+	trimmed := strings.TrimSpace(text)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return describeJSON([]byte(trimmed)), outputFormat{"application/json", ".json"}
+	}
+	if rows, ok := parseDelimited(trimmed, ','); ok {
+		return describeDelimited(rows, ','), outputFormat{"text/csv", ".csv"}
+	}
+	if rows, ok := parseDelimited(trimmed, '\t'); ok {
+		return describeDelimited(rows, '\t'), outputFormat{"text/tab-separated-values", ".tsv"}
+	}
+	return describeText(text), outputFormat{"text/plain", ".txt"}
+}
+
+// describeJSON reports the shape of a JSON document: the keys of objects and the length of arrays,
+// with samples of array elements
+func describeJSON(data []byte) string {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return describeText(string(data))
+	}
+
+	var out strings.Builder
+	switch v := value.(type) {
+	case map[string]any:
+		keys := sortedKeys(v)
+		fmt.Fprintf(&out, "Format: JSON object with %d keys\n", len(keys))
+		for _, key := range keys[:min(len(keys), summaryMaxKeys)] {
+			fmt.Fprintf(&out, "- %s: %s\n", key, describeJSONValue(v[key]))
+		}
+		if len(keys) > summaryMaxKeys {
+			fmt.Fprintf(&out, "- ... %d more keys\n", len(keys)-summaryMaxKeys)
+		}
+		// Large arrays are usually the bulk of the output, so the largest is sampled
+		if key, items := largestArray(v); key != "" {
+			fmt.Fprintf(&out, "\nSample of %s:\n", key)
+			writeJSONSamples(&out, items)
+		}
+	case []any:
+		fmt.Fprintf(&out, "Format: JSON array of %d items\n", len(v))
+		if len(v) > 0 {
+			fmt.Fprintf(&out, "Items: %s\n", describeJSONValue(v[0]))
+		}
+		out.WriteString("\nSample:\n")
+		writeJSONSamples(&out, v)
+	}
+	return out.String()
+}
+
+// describeJSONValue describes a JSON value in a few words, e.g. "array of 120 objects"
+func describeJSONValue(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := sortedKeys(v)
+		if len(keys) > summaryMaxKeys {
+			return fmt.Sprintf("object with %d keys (%s, ...)", len(keys), strings.Join(keys[:summaryMaxKeys], ", "))
+		}
+		return fmt.Sprintf("object with keys %s", strings.Join(keys, ", "))
+	case []any:
+		if len(v) == 0 {
+			return "empty array"
+		}
+		if item, ok := v[0].(map[string]any); ok {
+			keys := sortedKeys(item)
+			return fmt.Sprintf("array of %d objects with keys %s", len(v), strings.Join(keys[:min(len(keys), summaryMaxKeys)], ", "))
+		}
+		return fmt.Sprintf("array of %d items", len(v))
+	case string:
+		if len(v) > summaryMaxSampleBytes {
+			return fmt.Sprintf("string of %d bytes", len(v))
+		}
+		return fmt.Sprintf("%q", v)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// largestArray returns the key of the longest array in an object
+func largestArray(object map[string]any) (string, []any) {
+	var largestKey string
+	var largest []any
+	for _, key := range sortedKeys(object) {
+		if items, ok := object[key].([]any); ok && len(items) > len(largest) {
+			largestKey, largest = key, items
+		}
+	}
+	return largestKey, largest
+}
+
+// writeJSONSamples writes the first items of an array, and the last if there are more
+func writeJSONSamples(out *strings.Builder, items []any) {
+	for i, item := range items[:min(len(items), summarySampleRows)] {
+		fmt.Fprintf(out, "[%d] %s\n", i, compactJSON(item))
+	}
+	if skipped := len(items) - summarySampleRows - 1; skipped > 0 {
+		fmt.Fprintf(out, "... %d more items\n", skipped)
+	}
+	if len(items) > summarySampleRows {
+		fmt.Fprintf(out, "[%d] %s\n", len(items)-1, compactJSON(items[len(items)-1]))
+	}
+}
+
+// compactJSON marshals a value on one line, truncated to summaryMaxSampleBytes
+func compactJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return truncateSample(string(data))
+}
+
+// parseDelimited parses text as CSV or TSV if its first lines have a consistent number of fields
+func parseDelimited(text string, delimiter rune) ([][]string, bool) {
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if !strings.ContainsRune(firstLine, delimiter) {
+		return nil, false
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil || len(rows) < 2 {
+		return nil, false
+	}
+
+	columns := len(rows[0])
+	consistent := 0
+	sample := rows[:min(len(rows), delimitedSampleLines)]
+	for _, row := range sample {
+		if len(row) == columns {
+			consistent++
+		}
+	}
+	return rows, columns > 1 && consistent*10 >= len(sample)*8
+}
+
+// describeDelimited reports the size and columns of CSV or TSV, with sample rows
+func describeDelimited(rows [][]string, delimiter rune) string {
+	format := "CSV"
+	separator := ","
+	if delimiter == '\t' {
+		format = "TSV"
+		separator = "\t"
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Format: %s, %d rows (including the header) by %d columns\n", format, len(rows), len(rows[0]))
+	fmt.Fprintf(&out, "Columns: %s\n", truncateSample(strings.Join(rows[0], ", ")))
+	fmt.Fprintf(&out, "\nFirst %d rows:\n", min(len(rows)-1, summarySampleRows))
+	for _, row := range rows[1:min(len(rows), summarySampleRows+1)] {
+		out.WriteString(truncateSample(strings.Join(row, separator)) + "\n")
+	}
+	if len(rows) > summarySampleRows+1 {
+		fmt.Fprintf(&out, "\nLast row:\n%s\n", truncateSample(strings.Join(rows[len(rows)-1], separator)))
+	}
+	return out.String()
+}
+
+// describeText reports the headings and first lines of plain text or Markdown
+func describeText(text string) string {
+	lines := strings.Split(text, "\n")
+
+	var headings []string
+	for _, line := range lines {
+		// Markdown headings are one or more # followed by a space
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") && strings.HasPrefix(strings.TrimLeft(trimmed, "#"), " ") {
+			headings = append(headings, truncateSample(trimmed))
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("Format: text\n")
+	if len(headings) > 0 {
+		fmt.Fprintf(&out, "\nHeadings (%d):\n", len(headings))
+		for _, heading := range headings[:min(len(headings), summaryMaxHeadings)] {
+			out.WriteString(heading + "\n")
+		}
+		if len(headings) > summaryMaxHeadings {
+			fmt.Fprintf(&out, "... %d more headings\n", len(headings)-summaryMaxHeadings)
+		}
+	}
+	fmt.Fprintf(&out, "\nFirst %d lines:\n", min(len(lines), summarySampleLines))
+	for _, line := range lines[:min(len(lines), summarySampleLines)] {
+		out.WriteString(truncateSample(line) + "\n")
+	}
+	return out.String()
+}
+
+// truncateSample shortens a sample to summaryMaxSampleBytes without splitting a character
+func truncateSample(sample string) string {
+	if len(sample) <= summaryMaxSampleBytes {
+		return sample
+	}
+	return sample[:pageEnd(sample, 0, summaryMaxSampleBytes)] + "..."
+}
+
+// sortedKeys returns an object's keys in order
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	// taskDirName is the directory in the project that holds the task file
+	taskDirName = ".mcp-devtools"
+
+	// taskFileName is the name of the task file
+	taskFileName = "tasks.json"
+
+	// lockRetryDelay is how often a busy task file lock is retried
+	lockRetryDelay = 50 * time.Millisecond
+
+	// lockTimeout is how long to wait for another process to release the task file
+	lockTimeout = 5 * time.Second
+)
+
+// taskFilePath returns the task file for a project directory
+func taskFilePath(projectDir string) string {
+	return filepath.Join(projectDir, taskDirName, taskFileName)
+}
+
+// readTasks reads a project's task list, which is empty if the file does not exist yet
+func readTasks(ctx context.Context, path string) (*taskList, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &taskList{NextID: 1}, nil
+	}
+
+	lock := flock.New(path + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+	locked, err := lock.TryRLockContext(lockCtx, lockRetryDelay)
+	if err != nil || !locked {
+		return nil, fmt.Errorf("task file %s is locked by another process, try again shortly", path)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	return loadTaskFile(path)
+}
+
+// updateTasks applies update to a project's task list while holding an exclusive lock, and saves the
+// result if update succeeds
+func updateTasks(ctx context.Context, path string, update func(list *taskList) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create task directory: %w", err)
+	}
+
+	lock := flock.New(path + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(lockCtx, lockRetryDelay)
+	if err != nil || !locked {
+		return fmt.Errorf("task file %s is locked by another process, try again shortly", path)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	list, err := loadTaskFile(path)
+	if err != nil {
+		return err
+	}
+	if err := update(list); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+
+	// Write to a temporary file and rename it so a crash never leaves a partly written task list
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save task file: %w", err)
+	}
+	return nil
+}
+
+// loadTaskFile reads and parses a task file. Caller must hold the file's lock.
+func loadTaskFile(path string) (*taskList, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &taskList{NextID: 1}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	}
+
+	var list taskList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse task file %s: %w", path, err)
+	}
+	for _, task := range list.Tasks {
+		list.NextID = max(list.NextID, task.ID+1)
+	}
+	return &list, nil
+}
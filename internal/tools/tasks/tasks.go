@@ -0,0 +1,451 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxTasks is the most tasks a project's task list can hold
+	maxTasks = 1000
+
+	// maxTitleLength is the longest task title accepted
+	maxTitleLength = 200
+
+	// maxDescriptionLength is the longest task description accepted
+	maxDescriptionLength = 4000
+)
+
+// TasksTool manages a task list stored in the project directory, so long-running work survives
+// conversation resets
+type TasksTool struct{}
+
+// init registers the tasks tool
+func init() {
+	registry.Register(&TasksTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *TasksTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"tasks",
+		mcp.WithDescription(`Track a project's tasks in a file in the project directory, so multi-step work survives conversation resets. Add tasks with dependencies, update their status, and list them by status. A task cannot start or finish until the tasks it depends on are done or cancelled.`),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("'add' a task, 'update' a task, or 'list' tasks"),
+			mcp.Enum("add", "update", "list"),
+		),
+		mcp.WithString("project_dir",
+			mcp.Description("Absolute path to the project directory (default: the server's working directory)"),
+		),
+		mcp.WithNumber("id",
+			mcp.Description("Task ID, required for 'update'"),
+		),
+		mcp.WithString("title",
+			mcp.Description("Short task title, required for 'add'"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Details, acceptance criteria or notes"),
+		),
+		mcp.WithString("status",
+			mcp.Description("New status for 'update', or only list tasks with this status"),
+			mcp.Enum(statuses...),
+		),
+		mcp.WithArray("depends_on",
+			mcp.Description("IDs of tasks that must be finished first. Replaces the task's dependencies on 'update'"),
+			mcp.WithNumberItems(),
+		),
+		// Task list annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Adds and updates tasks in the project's task file
+		mcp.WithDestructiveHintAnnotation(false), // Tasks are never deleted, only cancelled
+		mcp.WithIdempotentHintAnnotation(false),  // Adding the same task twice creates two tasks
+		mcp.WithOpenWorldHintAnnotation(false),   // Local task file only
+	)
+}
+
+// Execute adds, updates or lists tasks
+func (t *TasksTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action, _ := args["action"].(string)
+
+	path, err := resolveTaskFile(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{"action": action, "file": path}).Debug("Managing tasks")
+
+	var result any
+	switch action {
+	case "add":
+		result, err = t.add(ctx, path, args)
+	case "update":
+		result, err = t.update(ctx, path, args)
+	case "list":
+		result, err = t.list(ctx, path, args)
+	default:
+		return nil, fmt.Errorf("invalid or missing 'action' parameter: must be 'add', 'update' or 'list'")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// add creates a new pending task
+func (t *TasksTool) add(ctx context.Context, path string, args map[string]any) (*TaskResponse, error) {
+	title, err := parseText(args, "title", maxTitleLength)
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		return nil, fmt.Errorf("missing required parameter 'title' for 'add'")
+	}
+	description, err := parseText(args, "description", maxDescriptionLength)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, _, err := parseIDs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TaskResponse
+	err = updateTasks(ctx, path, func(list *taskList) error {
+		if len(list.Tasks) >= maxTasks {
+			return fmt.Errorf("the task list already holds the maximum of %d tasks", maxTasks)
+		}
+		for _, id := range dependsOn {
+			if findTask(list, id) == nil {
+				return fmt.Errorf("'depends_on' refers to task %d, which does not exist", id)
+			}
+		}
+
+		now := time.Now()
+		task := Task{
+			ID:          list.NextID,
+			Title:       title,
+			Description: description,
+			Status:      StatusPending,
+			DependsOn:   dependsOn,
+			Created:     now,
+			Updated:     now,
+		}
+		list.Tasks = append(list.Tasks, task)
+		list.NextID++
+
+		response = TaskResponse{Task: viewTask(list, task), File: path}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// update changes a task's status, title, description or dependencies
+func (t *TasksTool) update(ctx context.Context, path string, args map[string]any) (*TaskResponse, error) {
+	idValue, ok := args["id"].(float64)
+	if !ok || idValue < 1 || idValue != float64(int(idValue)) {
+		return nil, fmt.Errorf("missing or invalid 'id' parameter for 'update': must be a task ID from 'list'")
+	}
+	id := int(idValue)
+
+	status, err := parseStatus(args)
+	if err != nil {
+		return nil, err
+	}
+	title, err := parseText(args, "title", maxTitleLength)
+	if err != nil {
+		return nil, err
+	}
+	description, err := parseText(args, "description", maxDescriptionLength)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, setDependencies, err := parseIDs(args)
+	if err != nil {
+		return nil, err
+	}
+	_, setDescription := args["description"]
+	if status == "" && title == "" && !setDescription && !setDependencies {
+		return nil, fmt.Errorf("nothing to update: provide 'status', 'title', 'description' or 'depends_on'")
+	}
+
+	var response TaskResponse
+	err = updateTasks(ctx, path, func(list *taskList) error {
+		task := findTask(list, id)
+		if task == nil {
+			return fmt.Errorf("task %d does not exist. Use action 'list' to see the project's tasks", id)
+		}
+
+		if setDependencies {
+			for _, dependency := range dependsOn {
+				if dependency == id {
+					return fmt.Errorf("task %d cannot depend on itself", id)
+				}
+				if findTask(list, dependency) == nil {
+					return fmt.Errorf("'depends_on' refers to task %d, which does not exist", dependency)
+				}
+				if dependsOnTask(list, dependency, id) {
+					return fmt.Errorf("task %d cannot depend on task %d, which already depends on it", id, dependency)
+				}
+			}
+			task.DependsOn = dependsOn
+		}
+		if status == StatusInProgress || status == StatusDone {
+			if blockedBy := unfinishedDependencies(list, *task); len(blockedBy) > 0 {
+				return fmt.Errorf("task %d cannot be marked %s until tasks %v are done or cancelled", id, status, blockedBy)
+			}
+		}
+
+		if status != "" {
+			task.Status = status
+		}
+		if title != "" {
+			task.Title = title
+		}
+		if setDescription {
+			task.Description = description
+		}
+		task.Updated = time.Now()
+
+		response = TaskResponse{Task: viewTask(list, *task), File: path}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// list returns the project's tasks, optionally only those with one status
+func (t *TasksTool) list(ctx context.Context, path string, args map[string]any) (*ListResponse, error) {
+	status, err := parseStatus(args)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := readTasks(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListResponse{Tasks: []TaskView{}, Counts: map[string]int{}, File: path}
+	for _, task := range list.Tasks {
+		response.Counts[task.Status]++
+		if status == "" || task.Status == status {
+			response.Tasks = append(response.Tasks, viewTask(list, task))
+		}
+	}
+	return response, nil
+}
+
+// resolveTaskFile returns the task file for the requested project directory
+func resolveTaskFile(ctx context.Context, args map[string]any) (string, error) {
+	projectDir, _ := args["project_dir"].(string)
+	projectDir = strings.TrimSpace(projectDir)
+	if projectDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory, provide 'project_dir': %w", err)
+		}
+		projectDir = cwd
+	}
+	if !filepath.IsAbs(projectDir) {
+		return "", fmt.Errorf("project_dir must be an absolute path, got: %s", projectDir)
+	}
+	projectDir = filepath.Clean(projectDir)
+
+	info, err := os.Stat(projectDir)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("project_dir %s is not a directory", projectDir)
+	}
+
+	path := taskFilePath(projectDir)
+	if err := workspace.CheckFileAccess(ctx, path); err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+	return path, nil
+}
+
+// parseText returns an optional string parameter, trimmed and checked against a maximum length
+func parseText(args map[string]any, name string, maxLength int) (string, error) {
+	value, exists := args[name]
+	if !exists || value == nil {
+		return "", nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid '%s' parameter: must be a string", name)
+	}
+	text = strings.TrimSpace(text)
+	if len(text) > maxLength {
+		return "", fmt.Errorf("'%s' exceeds maximum length of %d characters", name, maxLength)
+	}
+	return text, nil
+}
+
+// parseStatus returns the optional status parameter
+func parseStatus(args map[string]any) (string, error) {
+	value, exists := args["status"]
+	if !exists || value == nil {
+		return "", nil
+	}
+	status, ok := value.(string)
+	if !ok || !slices.Contains(statuses, status) {
+		return "", fmt.Errorf("invalid 'status' parameter: must be one of %s", strings.Join(statuses, ", "))
+	}
+	return status, nil
+}
+
+// parseIDs returns the depends_on task IDs, sorted without duplicates, and whether the parameter was
+// provided
+func parseIDs(args map[string]any) ([]int, bool, error) {
+	value, exists := args["depends_on"]
+	if !exists || value == nil {
+		return nil, false, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, true, fmt.Errorf("invalid 'depends_on' parameter: must be an array of task IDs")
+	}
+
+	var ids []int
+	for _, item := range items {
+		id, ok := item.(float64)
+		if !ok || id < 1 || id != float64(int(id)) {
+			return nil, true, fmt.Errorf("invalid 'depends_on' parameter: %v is not a task ID", item)
+		}
+		ids = append(ids, int(id))
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), true, nil
+}
+
+// findTask returns the task with an ID, or nil if there is none
+func findTask(list *taskList, id int) *Task {
+	for i := range list.Tasks {
+		if list.Tasks[i].ID == id {
+			return &list.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// dependsOnTask reports whether a task depends on another, directly or through other tasks
+func dependsOnTask(list *taskList, from, to int) bool {
+	visited := map[int]bool{}
+	pending := []int{from}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if id == to {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if task := findTask(list, id); task != nil {
+			pending = append(pending, task.DependsOn...)
+		}
+	}
+	return false
+}
+
+// unfinishedDependencies returns the IDs of a task's dependencies that are neither done nor cancelled
+func unfinishedDependencies(list *taskList, task Task) []int {
+	var blockedBy []int
+	for _, id := range task.DependsOn {
+		if dependency := findTask(list, id); dependency != nil && dependency.Status != StatusDone && dependency.Status != StatusCancelled {
+			blockedBy = append(blockedBy, id)
+		}
+	}
+	return blockedBy
+}
+
+// viewTask returns a task with its unfinished dependencies
+func viewTask(list *taskList, task Task) TaskView {
+	view := TaskView{Task: task}
+	if task.Status != StatusDone && task.Status != StatusCancelled {
+		view.BlockedBy = unfinishedDependencies(list, task)
+	}
+	return view
+}
+
+// ProvideExtendedInfo provides detailed usage information for the tasks tool
+func (t *TasksTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Plan a change as tasks, with the tests depending on the implementation",
+				Arguments: map[string]any{
+					"action":      "add",
+					"project_dir": "/Users/me/src/api",
+					"title":       "Add integration tests for the retry middleware",
+					"depends_on":  []int{1},
+				},
+				ExpectedResult: "The new task with its ID, and blocked_by listing task 1 until it is done.",
+			},
+			{
+				Description: "Start work on a task",
+				Arguments: map[string]any{
+					"action":      "update",
+					"project_dir": "/Users/me/src/api",
+					"id":          1,
+					"status":      "in_progress",
+				},
+				ExpectedResult: "The updated task.",
+			},
+			{
+				Description: "See what is left to do after a conversation reset",
+				Arguments: map[string]any{
+					"action":      "list",
+					"project_dir": "/Users/me/src/api",
+					"status":      "pending",
+				},
+				ExpectedResult: "Pending tasks with the tasks blocking them, and a count of tasks in each status.",
+			},
+		},
+		CommonPatterns: []string{
+			"Break long work into tasks at the start, then list pending and in_progress tasks when resuming",
+			"Mark a task in_progress when starting it and done when finished, so the next conversation knows where to pick up",
+			"Cancel tasks that are no longer needed rather than leaving them pending",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A task cannot be marked in_progress or done",
+				Solution: "Tasks it depends on are unfinished. Finish or cancel them first, or update the task's depends_on.",
+			},
+			{
+				Problem:  "Tasks from an earlier conversation are missing",
+				Solution: "Tasks are stored per project directory. Pass the same absolute project_dir used when they were added.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"project_dir": "Tasks are stored in .mcp-devtools/tasks.json in this directory. Add the file to .gitignore, or commit it to share the plan.",
+			"status":      "pending, in_progress, done or cancelled. New tasks are pending.",
+			"depends_on":  "On 'update', replaces the task's dependencies. Pass an empty array to remove them.",
+		},
+		WhenToUse:    "Multi-step work likely to outlast the conversation, or that several conversations will pick up in turn.",
+		WhenNotToUse: "Short tasks finished within one conversation, or tracking work the team shares in an issue tracker.",
+	}
+}
@@ -0,0 +1,50 @@
+package tasks
+
+import "time"
+
+// Task statuses
+const (
+	StatusPending    = "pending"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// statuses lists the valid task statuses in the order tasks move through them
+var statuses = []string{StatusPending, StatusInProgress, StatusDone, StatusCancelled}
+
+// Task is an entry in a project's task list
+type Task struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	DependsOn   []int     `json:"depends_on,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// TaskView is a task as returned to the agent, with the dependencies that are not yet finished
+type TaskView struct {
+	Task
+	BlockedBy []int `json:"blocked_by,omitempty"`
+}
+
+// TaskResponse is the result of adding or updating a task
+type TaskResponse struct {
+	Task TaskView `json:"task"`
+	File string   `json:"file"`
+}
+
+// ListResponse is the result of listing a project's tasks
+type ListResponse struct {
+	Tasks  []TaskView     `json:"tasks"`
+	Counts map[string]int `json:"counts"`
+	File   string         `json:"file"`
+}
+
+// taskList is the task file stored in the project directory
+type taskList struct {
+	NextID int    `json:"next_id"`
+	Tasks  []Task `json:"tasks"`
+}
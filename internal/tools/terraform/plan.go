@@ -0,0 +1,438 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// actionOrder lists plan actions in the order they are reported
+var actionOrder = []string{"replace", "delete", "create", "update", "read"}
+
+// actionLabels are the headings used for each action
+var actionLabels = map[string]string{
+	"create":  "Create",
+	"update":  "Update in place",
+	"delete":  "Destroy",
+	"replace": "Replace",
+	"read":    "Read",
+}
+
+// actionReasons translates action_reason values into short explanations
+var actionReasons = map[string]string{
+	"replace_because_tainted":              "tainted",
+	"replace_because_cannot_update":        "cannot update in place",
+	"replace_by_request":                   "replacement requested",
+	"replace_by_triggers":                  "replace_triggered_by",
+	"delete_because_no_resource_config":    "removed from configuration",
+	"delete_because_wrong_repetition":      "count/for_each changed",
+	"delete_because_count_index":           "count reduced",
+	"delete_because_each_key":              "for_each key removed",
+	"delete_because_no_module":             "module removed",
+	"delete_because_no_move_target":        "moved target missing",
+	"read_because_config_unknown":          "config unknown until apply",
+	"read_because_dependency_pending":      "dependency pending",
+	"read_because_check_nested_resource":   "check block",
+	"delete_because_moved_resource_target": "moved",
+}
+
+// costResourcePattern matches resource types that usually drive cloud spend
+var costResourcePattern = regexp.MustCompile(`^(aws_(instance|db_instance|rds_cluster|rds_cluster_instance|nat_gateway|eks_cluster|eks_node_group|elasticache_\w+|lb|alb|elb|autoscaling_group|launch_template|opensearch_domain|elasticsearch_domain|redshift_cluster|msk_cluster|dynamodb_table|ebs_volume|eip|kinesis_stream|sagemaker_\w+|emr_cluster|ecs_service|lambda_function|efs_file_system|fsx_\w+|vpc_endpoint|memorydb_cluster|docdb_cluster_instance|neptune_cluster_instance)|azurerm_(\w*virtual_machine\w*|kubernetes_cluster\w*|sql_\w+|mssql_\w+|postgresql_\w+|mysql_\w+|redis_cache|app_service_plan|service_plan|cosmosdb_account|public_ip|nat_gateway|application_gateway|firewall|managed_disk)|google_(compute_instance\w*|compute_disk|container_cluster|container_node_pool|sql_database_instance|redis_instance|compute_router_nat|bigtable_instance|spanner_instance|alloydb_\w+|filestore_instance))$`)
+
+// costAttributes are the attributes that determine the price of cost-relevant resources
+var costAttributes = []string{
+	"instance_type", "instance_class", "node_type", "machine_type", "vm_size", "size",
+	"sku", "sku_name", "tier", "allocated_storage", "max_allocated_storage", "storage_type",
+	"iops", "throughput", "desired_capacity", "desired_size", "min_size", "max_size",
+	"instance_count", "node_count", "num_cache_nodes", "multi_az", "billing_mode",
+	"read_capacity", "write_capacity", "memory_size", "disk_size_gb", "storage_gb", "engine",
+}
+
+// attributeChange is a single changed attribute in an update
+type attributeChange struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// summaryOptions controls how much detail a summary includes
+type summaryOptions struct {
+	filter        string
+	maxResources  int
+	maxAttributes int
+}
+
+// summarisePlan renders a concise markdown summary of a plan
+func summarisePlan(plan *showJSON, opts summaryOptions) string {
+	var sb strings.Builder
+	sb.WriteString("# Terraform plan")
+	if plan.TerraformVersion != "" {
+		fmt.Fprintf(&sb, " (Terraform %s)", plan.TerraformVersion)
+	}
+	sb.WriteString("\n\n")
+	if plan.Errored {
+		sb.WriteString("**The plan errored.** It may be incomplete and cannot be applied.\n\n")
+	}
+
+	byAction := make(map[string][]resourceChange)
+	unchanged := 0
+	for _, rc := range plan.ResourceChanges {
+		action := actionOf(rc.Change.Actions)
+		if action == "no-op" {
+			unchanged++
+			continue
+		}
+		if opts.filter != "" && !strings.Contains(rc.Address, opts.filter) {
+			continue
+		}
+		byAction[action] = append(byAction[action], rc)
+	}
+
+	fmt.Fprintf(&sb, "**Plan:** %d to add, %d to change, %d to replace, %d to destroy",
+		len(byAction["create"]), len(byAction["update"]), len(byAction["replace"]), len(byAction["delete"]))
+	if n := len(byAction["read"]); n > 0 {
+		fmt.Fprintf(&sb, ", %d to read", n)
+	}
+	fmt.Fprintf(&sb, " (%d unchanged)", unchanged)
+	if opts.filter != "" {
+		fmt.Fprintf(&sb, " - filtered to addresses containing %q", opts.filter)
+	}
+	sb.WriteString("\n")
+
+	imports := 0
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change.Importing != nil {
+			imports++
+		}
+	}
+	if imports > 0 {
+		fmt.Fprintf(&sb, "%d resources will be imported.\n", imports)
+	}
+
+	if len(byAction) == 0 {
+		sb.WriteString("\nNo changes. Infrastructure matches the configuration.\n")
+	}
+
+	listed := 0
+	for _, action := range actionOrder {
+		changes := byAction[action]
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s (%d)\n\n", actionLabels[action], len(changes))
+		for _, rc := range changes {
+			if listed >= opts.maxResources {
+				fmt.Fprintf(&sb, "- … more changes not shown (limit %d) - use filter to narrow the summary\n", opts.maxResources)
+				break
+			}
+			listed++
+			sb.WriteString(changeLine(rc, action, opts.maxAttributes))
+		}
+	}
+
+	if drift := driftLines(plan.ResourceDrift, opts); len(drift) > 0 {
+		fmt.Fprintf(&sb, "\n## Drift (changed outside Terraform) (%d)\n\n", len(drift))
+		sb.WriteString(strings.Join(drift, ""))
+	}
+
+	if cost := costLinesForPlan(plan.ResourceChanges, opts.filter); len(cost) > 0 {
+		sb.WriteString("\n## Cost-relevant changes\n\n| Address | Action | Key attributes |\n|---|---|---|\n")
+		sb.WriteString(strings.Join(cost, ""))
+	}
+
+	if len(plan.OutputChanges) > 0 {
+		var outputs []string
+		for _, name := range sortedKeys(plan.OutputChanges) {
+			oc := plan.OutputChanges[name]
+			action := actionOf(oc.Actions)
+			if action == "no-op" {
+				continue
+			}
+			line := fmt.Sprintf("- `%s`: %s", name, action)
+			if isSensitive(oc.AfterSensitive) || isSensitive(oc.BeforeSensitive) {
+				line += " (sensitive)"
+			} else if isUnknown(oc.AfterUnknown) {
+				line += " (known after apply)"
+			} else if action != "delete" {
+				line += " → " + renderValue(oc.After)
+			}
+			outputs = append(outputs, line+"\n")
+		}
+		if len(outputs) > 0 {
+			fmt.Fprintf(&sb, "\n## Output changes (%d)\n\n", len(outputs))
+			sb.WriteString(strings.Join(outputs, ""))
+		}
+	}
+
+	return sb.String()
+}
+
+// changeLine renders a planned change with its reason and changed attributes
+func changeLine(rc resourceChange, action string, maxAttributes int) string {
+	line := fmt.Sprintf("- `%s`", rc.Address)
+	var notes []string
+	if reason := actionReasons[rc.ActionReason]; reason != "" {
+		notes = append(notes, reason)
+	} else if rc.ActionReason != "" {
+		notes = append(notes, rc.ActionReason)
+	}
+	if len(rc.Change.ReplacePaths) > 0 {
+		var paths []string
+		for _, p := range rc.Change.ReplacePaths {
+			paths = append(paths, joinPath(p))
+		}
+		notes = append(notes, "forced by: "+strings.Join(paths, ", "))
+	}
+	if rc.Change.Importing != nil {
+		notes = append(notes, "import id "+rc.Change.Importing.ID)
+	}
+	if len(notes) > 0 {
+		line += " (" + strings.Join(notes, "; ") + ")"
+	}
+	line += "\n"
+
+	if action == "update" || action == "replace" {
+		line += attributeLines(changedAttributes(rc.Change), maxAttributes)
+	}
+	return line
+}
+
+// driftLines renders resources that changed outside Terraform
+func driftLines(drift []resourceChange, opts summaryOptions) []string {
+	var lines []string
+	for _, rc := range drift {
+		if opts.filter != "" && !strings.Contains(rc.Address, opts.filter) {
+			continue
+		}
+		action := actionOf(rc.Change.Actions)
+		if action == "no-op" {
+			continue
+		}
+		label := "modified"
+		if action == "delete" {
+			label = "deleted outside Terraform"
+		}
+		line := fmt.Sprintf("- `%s`: %s\n", rc.Address, label)
+		if action == "update" {
+			line += attributeLines(changedAttributes(rc.Change), opts.maxAttributes)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// attributeLines renders changed attributes as an indented list
+func attributeLines(changes []attributeChange, maxAttributes int) string {
+	var sb strings.Builder
+	for i, c := range changes {
+		if i >= maxAttributes {
+			fmt.Fprintf(&sb, "  - … %d more attributes\n", len(changes)-maxAttributes)
+			break
+		}
+		fmt.Fprintf(&sb, "  - %s: %s → %s\n", c.Path, c.Before, c.After)
+	}
+	return sb.String()
+}
+
+// costLinesForPlan renders cost-relevant resources that the plan creates, changes or destroys
+func costLinesForPlan(changes []resourceChange, filter string) []string {
+	var lines []string
+	for _, rc := range changes {
+		action := actionOf(rc.Change.Actions)
+		if action == "no-op" || action == "read" || !costResourcePattern.MatchString(rc.Type) {
+			continue
+		}
+		if filter != "" && !strings.Contains(rc.Address, filter) {
+			continue
+		}
+		before, _ := rc.Change.Before.(map[string]any)
+		after, _ := rc.Change.After.(map[string]any)
+		unknown, _ := rc.Change.AfterUnknown.(map[string]any)
+
+		var attrs []string
+		for _, name := range costAttributes {
+			b, hasBefore := before[name]
+			a, hasAfter := after[name]
+			switch {
+			case action == "delete" && hasBefore && b != nil:
+				attrs = append(attrs, name+"="+renderValue(b))
+			case unknown[name] == true:
+				attrs = append(attrs, name+"=(known after apply)")
+			case hasAfter && a != nil && hasBefore && b != nil && !reflect.DeepEqual(a, b):
+				attrs = append(attrs, name+"="+renderValue(b)+" → "+renderValue(a))
+			case hasAfter && a != nil:
+				attrs = append(attrs, name+"="+renderValue(a))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("| `%s` | %s | %s |\n", rc.Address, action, strings.ReplaceAll(strings.Join(attrs, ", "), "|", "\\|")))
+	}
+	return lines
+}
+
+// actionOf collapses a plan action list into a single action name
+func actionOf(actions []string) string {
+	switch {
+	case len(actions) == 2 && slices.Contains(actions, "create") && slices.Contains(actions, "delete"):
+		return "replace"
+	case len(actions) == 1:
+		return actions[0]
+	case len(actions) == 0:
+		return "no-op"
+	default:
+		return strings.Join(actions, "+")
+	}
+}
+
+// changedAttributes compares before and after values, descending into nested objects
+func changedAttributes(c valueChange) []attributeChange {
+	var changes []attributeChange
+	diffValues("", c.Before, c.After, c.AfterUnknown, c.BeforeSensitive, c.AfterSensitive, 0, &changes)
+	return changes
+}
+
+// diffValues records differences between two values, up to two levels of nesting
+func diffValues(path string, before, after, unknown, beforeSensitive, afterSensitive any, depth int, changes *[]attributeChange) {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	unknownMap, _ := unknown.(map[string]any)
+
+	if depth < 2 && (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := make(map[string]bool)
+		for k := range beforeMap {
+			keys[k] = true
+		}
+		for k := range afterMap {
+			keys[k] = true
+		}
+		for k := range unknownMap {
+			keys[k] = true
+		}
+		names := make([]string, 0, len(keys))
+		for k := range keys {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			diffValues(joinKey(path, k), beforeMap[k], afterMap[k], unknownMap[k], child(beforeSensitive, k), child(afterSensitive, k), depth+1, changes)
+		}
+		return
+	}
+
+	if isUnknown(unknown) {
+		*changes = append(*changes, attributeChange{Path: path, Before: sensitiveOr(beforeSensitive, before), After: "(known after apply)"})
+		return
+	}
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	if isSensitive(beforeSensitive) || isSensitive(afterSensitive) {
+		*changes = append(*changes, attributeChange{Path: path, Before: "(sensitive)", After: "(sensitive)"})
+		return
+	}
+	*changes = append(*changes, attributeChange{Path: path, Before: renderValue(before), After: renderValue(after)})
+}
+
+// child returns the nested sensitivity or unknown marker for a key
+func child(tree any, key string) any {
+	switch v := tree.(type) {
+	case bool:
+		return v
+	case map[string]any:
+		return v[key]
+	}
+	return nil
+}
+
+// isSensitive reports whether a sensitivity marker marks the whole value as sensitive
+func isSensitive(marker any) bool {
+	b, ok := marker.(bool)
+	return ok && b
+}
+
+// isUnknown reports whether any part of a value is unknown until apply
+func isUnknown(marker any) bool {
+	switch v := marker.(type) {
+	case bool:
+		return v
+	case map[string]any:
+		for _, item := range v {
+			if isUnknown(item) {
+				return true
+			}
+		}
+	case []any:
+		return slices.ContainsFunc(v, isUnknown)
+	}
+	return false
+}
+
+// sensitiveOr renders a value unless it is marked sensitive
+func sensitiveOr(marker, value any) string {
+	if isSensitive(marker) {
+		return "(sensitive)"
+	}
+	return renderValue(value)
+}
+
+// renderValue renders a value as compact JSON, abbreviating large values
+func renderValue(v any) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	text := string(data)
+	if len(text) <= maxValueLength {
+		return text
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		return fmt.Sprintf("{…%d keys}", len(val))
+	case []any:
+		return fmt.Sprintf("[…%d items]", len(val))
+	case string:
+		return string([]rune(text)[:maxValueLength-2]) + "…\""
+	}
+	return text[:maxValueLength] + "…"
+}
+
+// joinKey appends an object key to an attribute path
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// joinPath renders a replace path such as ["ebs_block_device", 0, "size"]
+func joinPath(path []any) string {
+	var sb strings.Builder
+	for _, part := range path {
+		switch p := part.(type) {
+		case float64:
+			fmt.Fprintf(&sb, "[%d]", int(p))
+		default:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			fmt.Fprint(&sb, p)
+		}
+	}
+	return sb.String()
+}
+
+// sortedKeys returns map keys in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
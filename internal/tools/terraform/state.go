@@ -0,0 +1,232 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stateResourceInfo is a resource instance gathered from either state representation
+type stateResourceInfo struct {
+	Address string
+	Module  string
+	Mode    string
+	Type    string
+	Values  map[string]any
+}
+
+// stateOutputInfo is an output gathered from either state representation
+type stateOutputInfo struct {
+	Value     any
+	Sensitive bool
+}
+
+// summariseState renders a concise markdown summary of a state
+func summariseState(title string, version string, resources []stateResourceInfo, outputs map[string]stateOutputInfo, opts summaryOptions) string {
+	var sb strings.Builder
+	sb.WriteString("# " + title)
+	if version != "" {
+		fmt.Fprintf(&sb, " (Terraform %s)", version)
+	}
+	sb.WriteString("\n\n")
+
+	byType := make(map[string]int)
+	byModule := make(map[string]int)
+	managed, data := 0, 0
+	var filtered []stateResourceInfo
+	for _, r := range resources {
+		if opts.filter != "" && !strings.Contains(r.Address, opts.filter) {
+			continue
+		}
+		filtered = append(filtered, r)
+		if r.Mode == "data" {
+			data++
+			continue
+		}
+		managed++
+		byType[r.Type]++
+		module := r.Module
+		if module == "" {
+			module = "(root)"
+		}
+		byModule[module]++
+	}
+
+	fmt.Fprintf(&sb, "**Resources:** %d managed, %d data sources", managed, data)
+	if opts.filter != "" {
+		fmt.Fprintf(&sb, " - filtered to addresses containing %q", opts.filter)
+	}
+	sb.WriteString("\n")
+
+	if len(byType) > 0 {
+		sb.WriteString("\n## By type\n\n| Type | Count |\n|---|---|\n")
+		for i, name := range sortedKeys(byType) {
+			if i >= opts.maxResources {
+				fmt.Fprintf(&sb, "| … %d more types | |\n", len(byType)-opts.maxResources)
+				break
+			}
+			fmt.Fprintf(&sb, "| `%s` | %d |\n", name, byType[name])
+		}
+	}
+
+	if len(byModule) > 1 {
+		sb.WriteString("\n## By module\n\n| Module | Resources |\n|---|---|\n")
+		for _, name := range sortedKeys(byModule) {
+			fmt.Fprintf(&sb, "| `%s` | %d |\n", name, byModule[name])
+		}
+	}
+
+	var cost []string
+	for _, r := range filtered {
+		if r.Mode == "data" || !costResourcePattern.MatchString(r.Type) {
+			continue
+		}
+		var attrs []string
+		for _, name := range costAttributes {
+			if v, ok := r.Values[name]; ok && v != nil {
+				attrs = append(attrs, name+"="+renderValue(v))
+			}
+		}
+		cost = append(cost, fmt.Sprintf("| `%s` | %s |\n", r.Address, strings.ReplaceAll(strings.Join(attrs, ", "), "|", "\\|")))
+	}
+	if len(cost) > 0 {
+		fmt.Fprintf(&sb, "\n## Cost-relevant resources (%d)\n\n| Address | Key attributes |\n|---|---|\n", len(cost))
+		for i, line := range cost {
+			if i >= opts.maxResources {
+				fmt.Fprintf(&sb, "| … %d more | |\n", len(cost)-opts.maxResources)
+				break
+			}
+			sb.WriteString(line)
+		}
+	}
+
+	if len(outputs) > 0 {
+		fmt.Fprintf(&sb, "\n## Outputs (%d)\n\n", len(outputs))
+		for _, name := range sortedKeys(outputs) {
+			out := outputs[name]
+			value := renderValue(out.Value)
+			if out.Sensitive {
+				value = "(sensitive)"
+			}
+			fmt.Fprintf(&sb, "- `%s`: %s\n", name, value)
+		}
+	}
+
+	return sb.String()
+}
+
+// showStateResources flattens the values representation into resource instances
+func showStateResources(values *stateValues) ([]stateResourceInfo, map[string]stateOutputInfo) {
+	var resources []stateResourceInfo
+	var walk func(m stateModule)
+	walk = func(m stateModule) {
+		for _, r := range m.Resources {
+			resources = append(resources, stateResourceInfo{
+				Address: r.Address,
+				Module:  m.Address,
+				Mode:    r.Mode,
+				Type:    r.Type,
+				Values:  maskSensitive(r.Values, r.SensitiveValues),
+			})
+		}
+		for _, child := range m.ChildModules {
+			walk(child)
+		}
+	}
+	walk(values.RootModule)
+
+	outputs := make(map[string]stateOutputInfo, len(values.Outputs))
+	for name, out := range values.Outputs {
+		outputs[name] = stateOutputInfo{Value: out.Value, Sensitive: out.Sensitive}
+	}
+	return resources, outputs
+}
+
+// rawStateResources flattens a terraform.tfstate file into resource instances
+func rawStateResources(state *showJSON) ([]stateResourceInfo, map[string]stateOutputInfo) {
+	var resources []stateResourceInfo
+	for _, r := range state.Resources {
+		base := r.Type + "." + r.Name
+		if r.Mode == "data" {
+			base = "data." + base
+		}
+		if r.Module != "" {
+			base = r.Module + "." + base
+		}
+		for _, inst := range r.Instances {
+			address := base
+			switch key := inst.IndexKey.(type) {
+			case float64:
+				address += fmt.Sprintf("[%d]", int(key))
+			case string:
+				address += fmt.Sprintf("[%q]", key)
+			}
+			values := inst.Attributes
+			if len(inst.SensitiveAttributes) > 0 {
+				values = maskRawSensitive(values, inst.SensitiveAttributes)
+			}
+			resources = append(resources, stateResourceInfo{
+				Address: address,
+				Module:  r.Module,
+				Mode:    r.Mode,
+				Type:    r.Type,
+				Values:  values,
+			})
+		}
+	}
+
+	outputs := make(map[string]stateOutputInfo, len(state.Outputs))
+	for name, out := range state.Outputs {
+		outputs[name] = stateOutputInfo{Value: out.Value, Sensitive: out.Sensitive}
+	}
+	return resources, outputs
+}
+
+// maskSensitive replaces top-level values marked sensitive in the values representation
+func maskSensitive(values map[string]any, sensitive any) map[string]any {
+	marks, ok := sensitive.(map[string]any)
+	if !ok || len(marks) == 0 {
+		return values
+	}
+	masked := make(map[string]any, len(values))
+	for k, v := range values {
+		if isSensitive(marks[k]) {
+			v = "(sensitive)"
+		}
+		masked[k] = v
+	}
+	return masked
+}
+
+// maskRawSensitive replaces top-level attributes named in a tfstate sensitive_attributes list.
+// Both the legacy path-step format and the newer value-mark format are recognised.
+func maskRawSensitive(values map[string]any, sensitive []any) map[string]any {
+	names := make(map[string]bool)
+	for _, entry := range sensitive {
+		var steps []any
+		switch e := entry.(type) {
+		case []any:
+			steps = e
+		case map[string]any:
+			steps, _ = e["value"].([]any)
+		}
+		if len(steps) == 0 {
+			continue
+		}
+		if step, ok := steps[0].(map[string]any); ok {
+			if name, ok := step["value"].(string); ok {
+				names[name] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return values
+	}
+	masked := make(map[string]any, len(values))
+	for k, v := range values {
+		if names[k] {
+			v = "(sensitive)"
+		}
+		masked[k] = v
+	}
+	return masked
+}
@@ -0,0 +1,258 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BinaryEnvVar overrides the terraform or tofu CLI used to convert binary plan files
+	BinaryEnvVar = "TERRAFORM_BINARY"
+
+	commandTimeout       = 120 * time.Second
+	maxFileSize          = 100 * 1024 * 1024
+	defaultMaxResources  = 100
+	defaultMaxAttributes = 10
+	maxValueLength       = 80
+)
+
+// TerraformTool summarises Terraform and OpenTofu plans and state
+type TerraformTool struct{}
+
+// init registers the terraform tool
+func init() {
+	registry.Register(&TerraformTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *TerraformTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"terraform",
+		mcp.WithDescription(`Summarise a Terraform or OpenTofu plan or state file without loading the full JSON.
+
+Accepts the output of 'terraform show -json', a terraform.tfstate file, or a binary plan file from 'terraform plan -out' (converted with the terraform or tofu CLI).
+
+Plans report counts of resources to add, change, replace and destroy, destructive changes with the attributes forcing replacement, changed attributes for updates, drift detected outside Terraform and cost-relevant resources such as instances, databases and NAT gateways. State reports resources by type and module, cost-relevant resources and outputs. Sensitive values are always masked.`),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to a plan JSON, state JSON, terraform.tfstate or binary plan file"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Only include resources whose address contains this text (e.g. module.network or aws_instance)"),
+		),
+		mcp.WithNumber("max_resources",
+			mcp.Description(fmt.Sprintf("Maximum resources listed in detail (default: %d)", defaultMaxResources)),
+		),
+		mcp.WithNumber("max_attributes",
+			mcp.Description(fmt.Sprintf("Maximum changed attributes shown per resource (default: %d)", defaultMaxAttributes)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads plan and state files
+		mcp.WithDestructiveHintAnnotation(false), // Never applies or modifies infrastructure
+		mcp.WithIdempotentHintAnnotation(true),   // Same file gives the same summary
+		mcp.WithOpenWorldHintAnnotation(false),   // Works on local files only
+	)
+}
+
+// Execute summarises the plan or state file
+func (t *TerraformTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", path)
+	}
+	path = filepath.Clean(path)
+	if err := workspace.CheckFileAccess(ctx, path); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+
+	opts := summaryOptions{maxResources: defaultMaxResources, maxAttributes: defaultMaxAttributes}
+	if filter, ok := args["filter"].(string); ok {
+		opts.filter = strings.TrimSpace(filter)
+	}
+	if v, ok := args["max_resources"].(float64); ok {
+		if v < 1 {
+			return nil, fmt.Errorf("max_resources must be at least 1")
+		}
+		opts.maxResources = int(v)
+	}
+	if v, ok := args["max_attributes"].(float64); ok {
+		if v < 1 {
+			return nil, fmt.Errorf("max_attributes must be at least 1")
+		}
+		opts.maxAttributes = int(v)
+	}
+
+	data, err := readInput(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var show showJSON
+	if err := json.Unmarshal(data, &show); err != nil {
+		return nil, fmt.Errorf("%s is not a Terraform JSON plan or state file: %w", path, err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path":   path,
+		"format": show.FormatVersion,
+	}).Debug("Summarising Terraform file")
+
+	var summary string
+	switch {
+	case show.ResourceChanges != nil || show.PlannedValues != nil:
+		summary = summarisePlan(&show, opts)
+	case show.Values != nil:
+		resources, outputs := showStateResources(show.Values)
+		summary = summariseState("Terraform state", show.TerraformVersion, resources, outputs, opts)
+	case show.Resources != nil || show.Version >= 3:
+		resources, outputs := rawStateResources(&show)
+		summary = summariseState(fmt.Sprintf("Terraform state (serial %d)", show.Serial), show.TerraformVersion, resources, outputs, opts)
+	case show.FormatVersion != "":
+		summary = "# Terraform state\n\nThe state is empty.\n"
+	default:
+		return nil, fmt.Errorf("%s does not look like Terraform plan or state JSON - expected output from 'terraform show -json' or a terraform.tfstate file", path)
+	}
+
+	return mcp.NewToolResultText(summary), nil
+}
+
+// readInput reads a JSON file, converting binary plan files with the Terraform CLI
+func readInput(ctx context.Context, path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%s is larger than %dMB", path, maxFileSize/(1024*1024))
+	}
+
+	// Binary plan files are zip archives
+	if bytes.HasPrefix(data, []byte("PK")) {
+		return showPlan(ctx, path)
+	}
+	return data, nil
+}
+
+// showPlan converts a binary plan file to JSON by running `terraform show -json` in the
+// plan's directory, which must be initialised with the providers used by the plan
+func showPlan(ctx context.Context, path string) ([]byte, error) {
+	binary, err := findBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binary, "show", "-json", "-no-color", filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s show timed out after %s", filepath.Base(binary), commandTimeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("%s show failed (run 'terraform init' in %s, or pass the output of 'terraform show -json' instead): %s", filepath.Base(binary), cmd.Dir, message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// findBinary locates the Terraform CLI, preferring TERRAFORM_BINARY, then terraform, then tofu
+func findBinary() (string, error) {
+	if binary := os.Getenv(BinaryEnvVar); binary != "" {
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return "", fmt.Errorf("%s is set to %s but it cannot be found: %w", BinaryEnvVar, binary, err)
+		}
+		return path, nil
+	}
+	for _, name := range []string{"terraform", "tofu"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("binary plan files need the terraform or tofu CLI - install one, set %s, or pass the output of 'terraform show -json' instead", BinaryEnvVar)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the terraform tool
+func (t *TerraformTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Review a plan before apply",
+				Arguments: map[string]any{
+					"path": "/Users/name/infra/plan.json",
+				},
+				ExpectedResult: "Counts of adds, changes, replacements and destroys, destructive changes first, drift and cost-relevant resources",
+			},
+			{
+				Description: "Focus on one module in a large plan",
+				Arguments: map[string]any{
+					"path":   "/Users/name/infra/tfplan",
+					"filter": "module.database",
+				},
+				ExpectedResult: "The same summary limited to resources under module.database",
+			},
+			{
+				Description: "Summarise what a state file manages",
+				Arguments: map[string]any{
+					"path": "/Users/name/infra/terraform.tfstate",
+				},
+				ExpectedResult: "Resource counts by type and module, cost-relevant resources with sizes, and outputs",
+			},
+		},
+		CommonPatterns: []string{
+			"Run 'terraform plan -out=tfplan' then 'terraform show -json tfplan > plan.json' and summarise plan.json",
+			"Check the Replace and Destroy sections first - they list the attributes forcing replacement",
+			"Use filter to drill into a module or resource type when the summary is truncated",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Binary plan file fails to convert",
+				Solution: "The plan's directory must be initialised with 'terraform init'. Alternatively run 'terraform show -json' yourself and pass the JSON file.",
+			},
+			{
+				Problem:  "Values show as (known after apply)",
+				Solution: "Terraform cannot know these values until apply, for example IDs and generated addresses.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"path":           "Absolute path. JSON files are detected as plans or state automatically; zip-format binary plans are converted with the terraform or tofu CLI.",
+			"filter":         "Substring matched against resource addresses, e.g. 'module.vpc', 'aws_db_instance' or 'aws_instance.web[0]'.",
+			"max_resources":  "Limits the number of resources listed in detail. Counts always cover the whole plan.",
+			"max_attributes": "Limits changed attributes shown for each update or replacement.",
+		},
+		WhenToUse:    "Use to review a plan or state without putting the full JSON in context, especially to spot destroys, replacements, drift and changes to expensive resources.",
+		WhenNotToUse: "Don't use to run plan or apply - this tool only reads files. For provider and module documentation use terraform_documentation.",
+	}
+}
@@ -0,0 +1,99 @@
+package terraform
+
+// showJSON covers both `terraform show -json` outputs: plans (resource_changes) and
+// state (values)
+type showJSON struct {
+	FormatVersion    string                  `json:"format_version"`
+	TerraformVersion string                  `json:"terraform_version"`
+	ResourceChanges  []resourceChange        `json:"resource_changes"`
+	ResourceDrift    []resourceChange        `json:"resource_drift"`
+	OutputChanges    map[string]valueChange  `json:"output_changes"`
+	PlannedValues    *stateValues            `json:"planned_values"`
+	Values           *stateValues            `json:"values"`
+	Errored          bool                    `json:"errored"`
+	Outputs          map[string]rawStateItem `json:"outputs"` // raw tfstate files only
+
+	// Raw terraform.tfstate fields
+	Version   int           `json:"version"`
+	Serial    int           `json:"serial"`
+	Resources []rawResource `json:"resources"`
+}
+
+// resourceChange is a planned change, or detected drift, for a single resource instance
+type resourceChange struct {
+	Address       string      `json:"address"`
+	ModuleAddress string      `json:"module_address"`
+	Mode          string      `json:"mode"`
+	Type          string      `json:"type"`
+	Name          string      `json:"name"`
+	ProviderName  string      `json:"provider_name"`
+	Change        valueChange `json:"change"`
+	ActionReason  string      `json:"action_reason"`
+}
+
+// valueChange describes before and after values for a resource or output
+type valueChange struct {
+	Actions         []string `json:"actions"`
+	Before          any      `json:"before"`
+	After           any      `json:"after"`
+	AfterUnknown    any      `json:"after_unknown"`
+	BeforeSensitive any      `json:"before_sensitive"`
+	AfterSensitive  any      `json:"after_sensitive"`
+	ReplacePaths    [][]any  `json:"replace_paths"`
+	Importing       *struct {
+		ID string `json:"id"`
+	} `json:"importing"`
+}
+
+// stateValues is the values representation used by state and planned values
+type stateValues struct {
+	Outputs    map[string]stateOutput `json:"outputs"`
+	RootModule stateModule            `json:"root_module"`
+}
+
+// stateOutput is an output value in the values representation
+type stateOutput struct {
+	Sensitive bool `json:"sensitive"`
+	Value     any  `json:"value"`
+}
+
+// stateModule is a module and its resources in the values representation
+type stateModule struct {
+	Address      string          `json:"address"`
+	Resources    []stateResource `json:"resources"`
+	ChildModules []stateModule   `json:"child_modules"`
+}
+
+// stateResource is a resource instance in the values representation
+type stateResource struct {
+	Address         string         `json:"address"`
+	Mode            string         `json:"mode"`
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	ProviderName    string         `json:"provider_name"`
+	Values          map[string]any `json:"values"`
+	SensitiveValues any            `json:"sensitive_values"`
+}
+
+// rawResource is a resource in a terraform.tfstate file
+type rawResource struct {
+	Module    string        `json:"module"`
+	Mode      string        `json:"mode"`
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Provider  string        `json:"provider"`
+	Instances []rawInstance `json:"instances"`
+}
+
+// rawInstance is a resource instance in a terraform.tfstate file
+type rawInstance struct {
+	IndexKey            any            `json:"index_key"`
+	Attributes          map[string]any `json:"attributes"`
+	SensitiveAttributes []any          `json:"sensitive_attributes"`
+}
+
+// rawStateItem is an output in a terraform.tfstate file
+type rawStateItem struct {
+	Value     any  `json:"value"`
+	Sensitive bool `json:"sensitive"`
+}
@@ -35,7 +35,7 @@ type Client struct {
 // NewClient creates a new Terraform Registry API client with proxy support
 func NewClient(logger *logrus.Logger) *Client {
 	return &Client{
-		httpClient: httpclient.NewHTTPClientWithProxyAndLogger(defaultTimeout, logger),
+		httpClient: httpclient.New(httpclient.Options{Timeout: defaultTimeout, Logger: logger, Cache: true}),
 		logger:     logger,
 		ops:        security.NewOperations("terraform_documentation"),
 	}
@@ -0,0 +1,159 @@
+package testrunner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// frameworks are the supported test frameworks, in the order they are detected
+var frameworks = []string{"go", "pytest", "jest"}
+
+// detect picks the test framework for a project directory from the files in it. A directory in a
+// Go module without its own marker files is tested with go test.
+func detect(dir string) (string, error) {
+	switch {
+	case exists(filepath.Join(dir, "go.mod")):
+		return "go", nil
+	case usesPytest(dir):
+		return "pytest", nil
+	case usesJest(dir):
+		return "jest", nil
+	}
+	for current := filepath.Dir(dir); ; current = filepath.Dir(current) {
+		if exists(filepath.Join(current, "go.mod")) {
+			return "go", nil
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	return "", fmt.Errorf("could not detect a test framework: no go.mod, pytest configuration or jest configuration found. Set framework to go, pytest or jest")
+}
+
+// usesPytest reports whether a directory has pytest configuration or tests
+func usesPytest(dir string) bool {
+	for _, name := range []string{"pytest.ini", "conftest.py"} {
+		if exists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	for _, config := range []struct{ name, section string }{
+		{"pyproject.toml", "[tool.pytest"},
+		{"setup.cfg", "[tool:pytest]"},
+		{"tox.ini", "[pytest]"},
+	} {
+		if data, err := os.ReadFile(filepath.Join(dir, config.name)); err == nil && strings.Contains(string(data), config.section) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesJest reports whether a directory has a jest configuration or depends on jest
+func usesJest(dir string) bool {
+	for _, ext := range []string{"js", "ts", "mjs", "cjs", "json"} {
+		if exists(filepath.Join(dir, "jest.config."+ext)) {
+			return true
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	return err == nil && strings.Contains(string(data), `"jest"`)
+}
+
+// build returns the command running a framework's tests. targets select packages or files and
+// filter selects tests by name. Frameworks that write their results to a file write them in
+// reportDir, a private temporary directory.
+func build(framework, dir string, targets []string, filter, reportDir string) (command, error) {
+	env := append(os.Environ(), "NO_COLOR=1", "CI=true")
+	switch framework {
+	case "go":
+		binary, err := exec.LookPath("go")
+		if err != nil {
+			return command{}, fmt.Errorf("go is not installed or not on PATH")
+		}
+		args := []string{"test", "-json"}
+		if filter != "" {
+			args = append(args, "-run="+filter)
+		}
+		if len(targets) == 0 {
+			targets = []string{"./..."}
+		}
+		return command{binary: binary, args: append(args, targets...), env: env}, nil
+
+	case "pytest":
+		binary, args, err := findPytest(dir)
+		if err != nil {
+			return command{}, err
+		}
+		report := filepath.Join(reportDir, "pytest.xml")
+		// xunit1 reports include each test's file
+		args = append(args, "-q", "--junitxml="+report, "-o", "junit_family=xunit1")
+		if filter != "" {
+			args = append(args, "-k", filter)
+		}
+		return command{binary: binary, args: append(args, targets...), env: env, report: report}, nil
+
+	case "jest":
+		binary, err := findNodeBinary(dir, "jest")
+		if err != nil {
+			return command{}, err
+		}
+		report := filepath.Join(reportDir, "jest.json")
+		args := []string{"--ci", "--json", "--outputFile=" + report, "--testLocationInResults"}
+		if filter != "" {
+			args = append(args, "-t", filter)
+		}
+		return command{binary: binary, args: append(args, targets...), env: append(env, "FORCE_COLOR=0"), report: report}, nil
+	}
+	return command{}, fmt.Errorf("unknown framework %q: must be go, pytest or jest", framework)
+}
+
+// findPytest prefers the project's virtual environment, then pytest on PATH, then a Python
+// interpreter with pytest installed as a module
+func findPytest(dir string) (string, []string, error) {
+	python := filepath.Join("bin", "python")
+	if runtime.GOOS == "windows" {
+		python = filepath.Join("Scripts", "python.exe")
+	}
+	for _, venv := range []string{".venv", "venv", "env"} {
+		if candidate := filepath.Join(dir, venv, python); exists(candidate) {
+			return candidate, []string{"-m", "pytest"}, nil
+		}
+	}
+	if binary, err := exec.LookPath("pytest"); err == nil {
+		return binary, nil, nil
+	}
+	for _, name := range []string{"python3", "python"} {
+		if binary, err := exec.LookPath(name); err == nil {
+			return binary, []string{"-m", "pytest"}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("pytest was not found: no .venv or venv in the project, and neither pytest nor python is on PATH")
+}
+
+// findNodeBinary finds a package's executable in node_modules/.bin in dir or a parent directory,
+// as workspaces often install dependencies at the repository root
+func findNodeBinary(dir, name string) (string, error) {
+	if runtime.GOOS == "windows" {
+		name += ".cmd"
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		if candidate := filepath.Join(current, "node_modules", ".bin", name); exists(candidate) {
+			return candidate, nil
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	return "", fmt.Errorf("%s is not installed in node_modules for %s - run npm install first", name, dir)
+}
+
+// exists reports whether a file or directory exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,249 @@
+package testrunner
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// goLogPattern matches t.Error and t.Log lines, which go test indents and prefixes with the
+	// file and line, such as "    parse_test.go:42: got 3, want 4"
+	goLogPattern = regexp.MustCompile(`^\s+([\w.\-/\\]+\.go):(\d+): (.*)$`)
+
+	// goFramePattern matches a test file's frame in a panic stack trace
+	goFramePattern = regexp.MustCompile(`^\s+(\S+_test\.go):(\d+)`)
+
+	// goBuildErrorPattern matches compiler and vet errors, such as "./parse.go:12:3: undefined: x"
+	goBuildErrorPattern = regexp.MustCompile(`^(\S+\.go):(\d+)(?::\d+)?: (.*)$`)
+)
+
+// goEvent is a line of go test -json output
+type goEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+	// ImportPath and FailedBuild describe build failures, reported as events since Go 1.24
+	ImportPath  string `json:"ImportPath"`
+	FailedBuild string `json:"FailedBuild"`
+}
+
+// goParser collects failures from go test -json output as it is read. Output is only kept for
+// tests that are still running, so passing tests with verbose logs cost nothing.
+type goParser struct {
+	maxLogLines int
+	counts      Counts
+	failures    []Failure
+	// logs holds the output of running tests and packages, keyed by package and test
+	logs map[string]*logBuffer
+	// builds holds build output by import path
+	builds map[string]*logBuffer
+	// failedTests counts failed tests by package, to tell test failures from package failures
+	failedTests map[string]int
+	// order lists running tests in the order they started
+	order []string
+	// other holds lines that were not JSON events
+	other *logBuffer
+}
+
+// newGoParser returns a parser keeping up to maxLogLines lines of output per failure
+func newGoParser(maxLogLines int) *goParser {
+	return &goParser{
+		maxLogLines: maxLogLines,
+		logs:        map[string]*logBuffer{},
+		builds:      map[string]*logBuffer{},
+		failedTests: map[string]int{},
+		other:       newLogBuffer(maxLogLines),
+	}
+}
+
+// read parses go test -json output until r is closed
+func (p *goParser) read(r io.Reader) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			p.line(strings.TrimRight(line, "\r\n"))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// line handles one line of output
+func (p *goParser) line(line string) {
+	var e goEvent
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &e) != nil || e.Action == "" {
+		if strings.TrimSpace(line) != "" {
+			p.other.add(line)
+		}
+		return
+	}
+
+	key := e.Package + "\x00" + e.Test
+	switch e.Action {
+	case "build-output":
+		p.buffer(p.builds, e.ImportPath).add(strings.TrimRight(e.Output, "\n"))
+	case "run":
+		p.order = append(p.order, key)
+		p.buffer(p.logs, key)
+	case "output":
+		if e.Test != "" && isGoMarker(e.Output) {
+			return
+		}
+		p.buffer(p.logs, key).add(strings.TrimRight(e.Output, "\n"))
+	case "pass", "skip":
+		if e.Test != "" {
+			p.counts.Total++
+			if e.Action == "pass" {
+				p.counts.Passed++
+			} else {
+				p.counts.Skipped++
+			}
+		}
+		p.finish(key)
+	case "fail":
+		if e.Test != "" {
+			p.counts.Total++
+			p.counts.Failed++
+			p.failedTests[e.Package]++
+			p.testFailed(e)
+		} else if p.failedTests[e.Package] == 0 {
+			p.packageFailed(e)
+		}
+		p.finish(key)
+	}
+}
+
+// buffer returns the log buffer for key, creating it when needed
+func (p *goParser) buffer(buffers map[string]*logBuffer, key string) *logBuffer {
+	b := buffers[key]
+	if b == nil {
+		b = newLogBuffer(p.maxLogLines)
+		buffers[key] = b
+	}
+	return b
+}
+
+// finish forgets a test or package that has completed
+func (p *goParser) finish(key string) {
+	delete(p.logs, key)
+	for i, running := range p.order {
+		if running == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// testFailed records a failed test. A test whose subtests failed is left out, as the subtests
+// already explain it.
+func (p *goParser) testFailed(e goEvent) {
+	for _, f := range p.failures {
+		if f.Suite == e.Package && strings.HasPrefix(f.Test, e.Test+"/") {
+			return
+		}
+	}
+	f := Failure{Suite: e.Package, Test: e.Test, Duration: e.Elapsed}
+	if log := p.logs[e.Package+"\x00"+e.Test]; log != nil {
+		f.Output = log.String()
+		f.File, f.Line, f.Message = goLocation(log.lines())
+	}
+	p.failures = append(p.failures, f)
+}
+
+// packageFailed records a package that failed without a failing test, such as one that did not
+// build or that panicked outside a test
+func (p *goParser) packageFailed(e goEvent) {
+	f := Failure{Suite: e.Package, Duration: e.Elapsed}
+	if e.FailedBuild != "" {
+		f.Message = "build failed"
+		if build := p.builds[e.FailedBuild]; build != nil {
+			f.Output = build.String()
+			for _, line := range build.lines() {
+				if match := goBuildErrorPattern.FindStringSubmatch(line); match != nil {
+					f.File, f.Message = match[1], match[3]
+					f.Line, _ = strconv.Atoi(match[2])
+					break
+				}
+			}
+		}
+		p.failures = append(p.failures, f)
+		return
+	}
+
+	var lines []string
+	if log := p.logs[e.Package+"\x00"]; log != nil {
+		for _, line := range log.lines() {
+			if !isGoSummary(line) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	f.Output = strings.Join(lines, "\n")
+	f.File, f.Line, f.Message = goLocation(lines)
+	if f.Message == "" {
+		f.Message = "package failed"
+	}
+	p.failures = append(p.failures, f)
+}
+
+// unfinished lists the tests still running, as package/test
+func (p *goParser) unfinished() []string {
+	var tests []string
+	for _, key := range p.order {
+		pkg, test, _ := strings.Cut(key, "\x00")
+		if test != "" {
+			tests = append(tests, pkg+"/"+test)
+		}
+	}
+	return tests
+}
+
+// goLocation finds where a test failed: the test file's frame in a panic's stack trace, or else
+// the last t.Error or t.Fatal line, as t.Log lines before it look the same
+func goLocation(lines []string) (file string, line int, message string) {
+	for i, text := range lines {
+		if !strings.HasPrefix(text, "panic: ") {
+			continue
+		}
+		for _, frame := range lines[i+1:] {
+			if match := goFramePattern.FindStringSubmatch(frame); match != nil {
+				line, _ = strconv.Atoi(match[2])
+				return match[1], line, text
+			}
+		}
+		return "", 0, text
+	}
+	for _, text := range slices.Backward(lines) {
+		if match := goLogPattern.FindStringSubmatch(text); match != nil {
+			line, _ = strconv.Atoi(match[2])
+			return match[1], line, match[3]
+		}
+	}
+	return "", 0, ""
+}
+
+// isGoMarker reports whether a line of test output is one of go test's progress markers
+func isGoMarker(output string) bool {
+	for _, prefix := range []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "--- PASS", "--- FAIL", "--- SKIP"} {
+		if strings.HasPrefix(strings.TrimSpace(output), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGoSummary reports whether a line of package output is go test's result summary
+func isGoSummary(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "FAIL" || trimmed == "PASS" || strings.HasPrefix(trimmed, "FAIL\t") ||
+		strings.HasPrefix(trimmed, "ok  \t") || strings.HasPrefix(trimmed, "coverage:")
+}
@@ -0,0 +1,105 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jsFramePattern matches a stack frame's location, such as "at Object.<anonymous> (/app/sum.test.js:4:17)"
+var jsFramePattern = regexp.MustCompile(`\(?((?:[A-Za-z]:)?[^\s():]+):(\d+):\d+\)?`)
+
+// jestReport is the part of jest --json output that describes results
+type jestReport struct {
+	NumTotalTests   int         `json:"numTotalTests"`
+	NumPassedTests  int         `json:"numPassedTests"`
+	NumFailedTests  int         `json:"numFailedTests"`
+	NumPendingTests int         `json:"numPendingTests"`
+	NumTodoTests    int         `json:"numTodoTests"`
+	TestResults     []jestSuite `json:"testResults"`
+}
+
+// jestSuite is the result of one test file
+type jestSuite struct {
+	Name             string          `json:"name"`
+	Status           string          `json:"status"`
+	Message          string          `json:"message"`
+	AssertionResults []jestAssertion `json:"assertionResults"`
+}
+
+// jestAssertion is the result of one test
+type jestAssertion struct {
+	FullName        string   `json:"fullName"`
+	Status          string   `json:"status"`
+	Duration        float64  `json:"duration"`
+	FailureMessages []string `json:"failureMessages"`
+	Location        *struct {
+		Line int `json:"line"`
+	} `json:"location"`
+}
+
+// readJest parses the report written by jest --json --outputFile
+func readJest(path, dir string, maxLogLines int) (Counts, []Failure, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Counts{}, nil, err
+	}
+	var report jestReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Counts{}, nil, fmt.Errorf("failed to parse jest report: %w", err)
+	}
+
+	counts := Counts{
+		Total:   report.NumTotalTests,
+		Passed:  report.NumPassedTests,
+		Failed:  report.NumFailedTests,
+		Skipped: report.NumPendingTests + report.NumTodoTests,
+	}
+	var failures []Failure
+	for _, suite := range report.TestResults {
+		file := relativeTo(dir, suite.Name)
+		failed := 0
+		for _, a := range suite.AssertionResults {
+			if a.Status != "failed" {
+				continue
+			}
+			failed++
+			message := strings.Join(a.FailureMessages, "\n\n")
+			f := Failure{Suite: file, Test: a.FullName, File: file, Message: firstLine(message), Output: clip(message, maxLogLines), Duration: a.Duration / 1000}
+			if line := jsFailureLine(message, suite.Name); line > 0 {
+				f.Line = line
+			} else if a.Location != nil {
+				f.Line = a.Location.Line
+			}
+			failures = append(failures, f)
+		}
+		// A file that fails to load, such as one with a syntax error, has no test results
+		if suite.Status == "failed" && failed == 0 {
+			failures = append(failures, Failure{Suite: file, File: file, Message: firstLine(suite.Message), Output: clip(suite.Message, maxLogLines)})
+		}
+	}
+	return counts, failures, nil
+}
+
+// jsFailureLine returns the line of the first stack frame in the test file
+func jsFailureLine(message, file string) int {
+	for _, match := range jsFramePattern.FindAllStringSubmatch(message, -1) {
+		if match[1] == file {
+			line, _ := strconv.Atoi(match[2])
+			return line
+		}
+	}
+	return 0
+}
+
+// relativeTo returns path relative to dir when it is inside it
+func relativeTo(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package testrunner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pythonLocationPattern matches the location lines that end each entry of a pytest traceback, such
+// as "tests/test_parse.py:42: AssertionError"
+var pythonLocationPattern = regexp.MustCompile(`(?m)^(\S+\.py):(\d+): `)
+
+// junitReport is the root of a JUnit XML report, which is either <testsuites> or a single <testsuite>
+type junitReport struct {
+	XMLName xml.Name
+	junitSuite
+}
+
+// junitSuite is a <testsuite>. Some tools nest suites.
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+// junitCase is a <testcase>
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      string        `xml:"line,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure"`
+	Error     *junitProblem `xml:"error"`
+	Skipped   *junitProblem `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+	SystemErr string        `xml:"system-err"`
+}
+
+// junitProblem is a <failure>, <error> or <skipped> element
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// readJUnit parses a JUnit XML report, as written by pytest --junitxml
+func readJUnit(path string, maxLogLines int) (Counts, []Failure, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Counts{}, nil, err
+	}
+	var report junitReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return Counts{}, nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+	}
+
+	var counts Counts
+	var failures []Failure
+	var walk func(suite junitSuite)
+	walk = func(suite junitSuite) {
+		for _, c := range suite.Cases {
+			counts.Total++
+			problem := c.Failure
+			if problem == nil {
+				problem = c.Error
+			}
+			switch {
+			case problem != nil:
+				counts.Failed++
+				failures = append(failures, junitFailure(c, problem, maxLogLines))
+			case c.Skipped != nil:
+				counts.Skipped++
+			default:
+				counts.Passed++
+			}
+		}
+		for _, nested := range suite.Suites {
+			walk(nested)
+		}
+	}
+	walk(report.junitSuite)
+	return counts, failures, nil
+}
+
+// junitFailure describes a failed test case. The line is taken from the traceback where possible,
+// as the report's line attribute is where the test is defined rather than where it failed.
+func junitFailure(c junitCase, problem *junitProblem, maxLogLines int) Failure {
+	f := Failure{Suite: c.Classname, Test: c.Name, File: c.File, Message: firstLine(problem.Message)}
+	f.Duration, _ = strconv.ParseFloat(c.Time, 64)
+	if f.Message == "" {
+		f.Message = firstLine(problem.Text)
+	}
+
+	for _, match := range pythonLocationPattern.FindAllStringSubmatch(problem.Text, -1) {
+		// The last entry in the test's own file is closest to the failure
+		if f.File == "" || match[1] == c.File {
+			f.File = match[1]
+			f.Line, _ = strconv.Atoi(match[2])
+		}
+	}
+	if f.Line == 0 && c.Line != "" {
+		// pytest numbers lines from zero in the line attribute
+		if line, err := strconv.Atoi(c.Line); err == nil {
+			f.Line = line + 1
+		}
+	}
+
+	output := strings.TrimSpace(problem.Text)
+	for _, section := range []struct{ name, text string }{{"stdout", c.SystemOut}, {"stderr", c.SystemErr}} {
+		if text := strings.TrimSpace(section.text); text != "" {
+			output += "\n\nCaptured " + section.name + ":\n" + text
+		}
+	}
+	f.Output = clip(output, maxLogLines)
+	return f
+}
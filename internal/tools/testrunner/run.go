@@ -0,0 +1,201 @@
+package testrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/procgroup"
+)
+
+const (
+	// maxLineLength is the longest line of test output kept. Longer lines are cut.
+	maxLineLength = 1000
+
+	// outputTailBytes is how much of the end of a command's unparsed output is kept
+	outputTailBytes = 64 * 1024
+)
+
+// ansiPattern matches terminal colour and cursor escape sequences
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// command is a test command ready to run
+type command struct {
+	binary string
+	args   []string
+	env    []string
+	// report is a file the command writes its results to, for frameworks whose structured output
+	// does not go to stdout
+	report string
+}
+
+// String formats the command for display
+func (c command) String() string {
+	return strings.Join(append([]string{c.binary}, c.args...), " ")
+}
+
+// outcome is how a command finished
+type outcome struct {
+	exitCode int
+	timedOut bool
+	duration time.Duration
+	// output is the end of everything the command wrote that was not parsed as it ran
+	output string
+}
+
+// execute runs a command in dir. When stdout is set it is given the command's standard output to
+// parse as it is written; otherwise standard output is kept with standard error in outcome.output.
+func execute(ctx context.Context, dir string, c command, timeout time.Duration, stdout func(io.Reader)) (outcome, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.binary, c.args...)
+	cmd.Dir = dir
+	cmd.Env = c.env
+	// Tests often start processes of their own, which must stop with the run
+	procgroup.Set(cmd)
+	output := &tailBuffer{limit: outputTailBytes}
+	cmd.Stderr = output
+
+	var pipe io.ReadCloser
+	var err error
+	if stdout != nil {
+		if pipe, err = cmd.StdoutPipe(); err != nil {
+			return outcome{}, fmt.Errorf("failed to read test output: %w", err)
+		}
+	} else {
+		cmd.Stdout = output
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return outcome{}, fmt.Errorf("failed to start %s: %w", c.binary, err)
+	}
+	if pipe != nil {
+		stdout(pipe)
+		// Drain anything the parser left so the command is not blocked writing
+		_, _ = io.Copy(io.Discard, pipe)
+	}
+	runErr := cmd.Wait()
+
+	result := outcome{duration: time.Since(start), output: output.String()}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.timedOut = true
+		result.exitCode = -1
+		return result, nil
+	}
+	if err := context.Cause(ctx); err != nil {
+		return result, err
+	}
+	if runErr != nil {
+		exitErr, ok := errors.AsType[*exec.ExitError](runErr)
+		if !ok {
+			return result, fmt.Errorf("failed to run %s: %w", c.binary, runErr)
+		}
+		result.exitCode = exitErr.ExitCode()
+	}
+	return result, nil
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+// Write records p, dropping the oldest output beyond the limit
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+// String returns the recorded output
+func (b *tailBuffer) String() string {
+	return strings.ToValidUTF8(string(b.buf), "�")
+}
+
+// logBuffer keeps the first and last lines of a log, counting those dropped in between, so that
+// both the start of a failure and its final error survive when a test logs a lot
+type logBuffer struct {
+	head    []string
+	tail    []string
+	headMax int
+	tailMax int
+	dropped int
+}
+
+// newLogBuffer returns a buffer keeping at most maxLines lines: a third from the start and the rest
+// from the end
+func newLogBuffer(maxLines int) *logBuffer {
+	headMax := max(1, maxLines/3)
+	return &logBuffer{headMax: headMax, tailMax: max(1, maxLines-headMax)}
+}
+
+// add records a line
+func (b *logBuffer) add(line string) {
+	line = cleanLine(line)
+	if len(b.head) < b.headMax {
+		b.head = append(b.head, line)
+		return
+	}
+	b.tail = append(b.tail, line)
+	if len(b.tail) > b.tailMax {
+		b.tail = b.tail[1:]
+		b.dropped++
+	}
+}
+
+// addText records each line of text
+func (b *logBuffer) addText(text string) {
+	for line := range strings.Lines(strings.TrimRight(text, "\n")) {
+		b.add(strings.TrimRight(line, "\r\n"))
+	}
+}
+
+// lines returns the recorded lines
+func (b *logBuffer) lines() []string {
+	return append(append([]string{}, b.head...), b.tail...)
+}
+
+// String joins the recorded lines, marking where lines were dropped
+func (b *logBuffer) String() string {
+	if b.dropped == 0 {
+		return strings.Join(b.lines(), "\n")
+	}
+	marker := fmt.Sprintf("... [%d lines omitted] ...", b.dropped)
+	return strings.Join(append(append(append([]string{}, b.head...), marker), b.tail...), "\n")
+}
+
+// clip cuts text to maxLines lines in the same way as logBuffer
+func clip(text string, maxLines int) string {
+	b := newLogBuffer(maxLines)
+	b.addText(text)
+	return b.String()
+}
+
+// cleanLine removes colour codes and cuts a line to maxLineLength
+func cleanLine(line string) string {
+	line = ansiPattern.ReplaceAllString(line, "")
+	if len(line) > maxLineLength {
+		line = strings.ToValidUTF8(line[:maxLineLength], "") + " ..."
+	}
+	return line
+}
+
+// firstLine returns the first non-empty line of text without colour codes
+func firstLine(text string) string {
+	for line := range strings.Lines(text) {
+		if line = strings.TrimSpace(line); line != "" {
+			return cleanLine(line)
+		}
+	}
+	return ""
+}
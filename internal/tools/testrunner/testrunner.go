@@ -0,0 +1,342 @@
+package testrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultTimeout is how long tests may run by default, in seconds
+	defaultTimeout = 300
+
+	// maxTimeout is the longest tests may run, in seconds
+	maxTimeout = 1800
+
+	// defaultMaxFailures is how many failures are reported by default
+	defaultMaxFailures = 20
+
+	// maxMaxFailures is the most failures that can be reported
+	maxMaxFailures = 100
+
+	// defaultMaxLogLines is how many lines of output are kept for each failure by default
+	defaultMaxLogLines = 60
+
+	// maxMaxLogLines is the most lines of output that can be kept for each failure
+	maxMaxLogLines = 500
+)
+
+// RunTestsTool runs a project's tests and reports failures
+type RunTestsTool struct{}
+
+// init registers the run_tests tool
+func init() {
+	registry.Register(&RunTestsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *RunTestsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"run_tests",
+		mcp.WithDescription(`Run a project's tests with go test, pytest or jest and get the failures back with their file, line, message and captured output, instead of pages of raw test output.
+
+The framework is detected from go.mod, pytest configuration or jest in package.json. Results are read from structured output (go test -json, JUnit XML from pytest, jest --json), so counts and failures are exact. Use target to choose packages or files and filter to choose tests by name.`),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path to the project directory to run tests in"),
+		),
+		mcp.WithString("framework",
+			mcp.Description("Test framework (default: detected)"),
+			mcp.Enum("auto", "go", "pytest", "jest"),
+			mcp.DefaultString("auto"),
+		),
+		mcp.WithString("target",
+			mcp.Description("What to test, space separated: Go package patterns (default ./...), pytest files, directories or node IDs, or jest test path patterns"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Only run tests matching this: a go test -run regex, a pytest -k expression, or a jest -t name pattern"),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("Seconds before the run is stopped (default: %d, max: %d). Failures so far are still reported", defaultTimeout, maxTimeout)),
+		),
+		mcp.WithNumber("max_failures",
+			mcp.Description(fmt.Sprintf("Most failures to report (default: %d, max: %d)", defaultMaxFailures, maxMaxFailures)),
+		),
+		mcp.WithNumber("max_log_lines",
+			mcp.Description(fmt.Sprintf("Most lines of output per failure, keeping the start and end (default: %d, max: %d)", defaultMaxLogLines, maxMaxLogLines)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Runs the project's tests, which may write files such as caches
+		mcp.WithDestructiveHintAnnotation(false), // Only runs test commands, not arbitrary ones
+		mcp.WithIdempotentHintAnnotation(false),  // Flaky tests can give different results
+		mcp.WithOpenWorldHintAnnotation(true),    // Tests and module downloads may use the network
+	)
+}
+
+// options are the validated parameters
+type options struct {
+	framework   string
+	targets     []string
+	filter      string
+	timeout     time.Duration
+	maxFailures int
+	maxLogLines int
+}
+
+// Execute runs the tests and returns the result
+func (t *RunTestsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("missing required parameter: path")
+	}
+	opts, err := parseOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := workspace.ResolveToolPath(ctx, "path", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory. Pass the project directory as path and the file as target", dir)
+	}
+
+	if opts.framework == "auto" {
+		if opts.framework, err = detect(dir); err != nil {
+			return nil, err
+		}
+	}
+	reportDir, err := os.MkdirTemp("", "mcp-run-tests-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a directory for test reports: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(reportDir) }()
+	cmd, err := build(opts.framework, dir, opts.targets, opts.filter, reportDir)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"dir":     dir,
+		"command": cmd.String(),
+	}).Debug("Running tests")
+
+	result, err := run(ctx, dir, cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep comparisons such as "got <nil>" readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := strings.TrimSuffix(buffer.String(), "\n")
+
+	// Test output can include anything the code under test printed, so scan it
+	sourceCtx := security.SourceContext{
+		Tool:        "run_tests",
+		URL:         "file://" + dir,
+		ContentType: "test_output",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// run executes the test command and builds the result from its structured output
+func run(ctx context.Context, dir string, cmd command, opts options) (*Result, error) {
+	result := &Result{Framework: opts.framework, Dir: dir, Command: cmd.String()}
+
+	var parser *goParser
+	var stdout func(io.Reader)
+	if opts.framework == "go" {
+		parser = newGoParser(opts.maxLogLines)
+		stdout = parser.read
+	}
+	done, err := execute(ctx, dir, cmd, opts.timeout, stdout)
+	if err != nil {
+		return nil, err
+	}
+	result.ExitCode = done.exitCode
+	result.TimedOut = done.timedOut
+	result.Duration = done.duration.Round(time.Millisecond).String()
+
+	var notes []string
+	switch {
+	case parser != nil:
+		result.Counts, result.Failures = parser.counts, parser.failures
+		if done.timedOut {
+			result.Unfinished = parser.unfinished()
+		}
+		// Anything go test printed outside its JSON events, such as module download errors
+		done.output = strings.TrimSpace(parser.other.String() + "\n" + done.output)
+	case cmd.report != "":
+		var readErr error
+		if opts.framework == "pytest" {
+			result.Counts, result.Failures, readErr = readJUnit(cmd.report, opts.maxLogLines)
+		} else {
+			result.Counts, result.Failures, readErr = readJest(cmd.report, dir, opts.maxLogLines)
+		}
+		if readErr != nil && !os.IsNotExist(readErr) {
+			notes = append(notes, readErr.Error())
+		}
+	}
+	if result.Failures == nil {
+		result.Failures = []Failure{}
+	}
+
+	result.Passed = done.exitCode == 0 && !done.timedOut && result.Counts.Failed == 0 && len(result.Failures) == 0
+	if done.timedOut {
+		notes = append(notes, fmt.Sprintf("The run was stopped after %s. Failures and counts are for the tests that finished.", opts.timeout))
+	}
+	if !result.Passed && (len(result.Failures) == 0 || done.timedOut) {
+		// Nothing structured explains the result, so show how the command ended
+		result.Output = clip(done.output, opts.maxLogLines)
+	}
+	if result.Counts.Total == 0 && !done.timedOut && len(result.Failures) == 0 {
+		notes = append(notes, "No tests were run. Check target and filter.")
+	}
+	if len(result.Failures) > opts.maxFailures {
+		notes = append(notes, fmt.Sprintf("Showing %d of %d failures.", opts.maxFailures, len(result.Failures)))
+		result.Failures = result.Failures[:opts.maxFailures]
+	}
+	result.Note = strings.Join(notes, " ")
+	return result, nil
+}
+
+// parseOptions reads and validates the parameters other than path
+func parseOptions(args map[string]any) (options, error) {
+	opts := options{
+		framework:   "auto",
+		timeout:     defaultTimeout * time.Second,
+		maxFailures: defaultMaxFailures,
+		maxLogLines: defaultMaxLogLines,
+	}
+	if framework, ok := args["framework"].(string); ok && framework != "" {
+		if framework != "auto" && !slices.Contains(frameworks, framework) {
+			return opts, fmt.Errorf("invalid framework %q: must be auto, go, pytest or jest", framework)
+		}
+		opts.framework = framework
+	}
+
+	// Values are passed as single arguments, so refusing a leading dash is enough to stop them
+	// being read as options such as go test -exec
+	if target, ok := args["target"].(string); ok {
+		opts.targets = strings.Fields(target)
+	}
+	for _, target := range opts.targets {
+		if strings.HasPrefix(target, "-") {
+			return opts, fmt.Errorf("invalid target %q: targets cannot start with '-'", target)
+		}
+	}
+	if filter, ok := args["filter"].(string); ok {
+		opts.filter = strings.TrimSpace(filter)
+		if strings.HasPrefix(opts.filter, "-") {
+			return opts, fmt.Errorf("invalid filter %q: filters cannot start with '-'", opts.filter)
+		}
+	}
+
+	for name, limit := range map[string]struct {
+		target *int
+		max    int
+	}{"max_failures": {&opts.maxFailures, maxMaxFailures}, "max_log_lines": {&opts.maxLogLines, maxMaxLogLines}} {
+		raw, ok := args[name]
+		if !ok {
+			continue
+		}
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > float64(limit.max) || value != float64(int(value)) {
+			return opts, fmt.Errorf("invalid '%s' parameter: must be a whole number from 1 to %d", name, limit.max)
+		}
+		*limit.target = int(value)
+	}
+	if raw, ok := args["timeout"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 1 || value > maxTimeout {
+			return opts, fmt.Errorf("invalid 'timeout' parameter: must be from 1 to %d seconds", maxTimeout)
+		}
+		opts.timeout = time.Duration(value * float64(time.Second))
+	}
+	return opts, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the run_tests tool
+func (t *RunTestsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Running a Go, Python or JavaScript project's tests after a change, or re-running one failing test while fixing it, when the failures matter more than the full output.",
+		WhenNotToUse: "Benchmarks, coverage reports, or test frameworks other than go test, pytest and jest. Watching for changes - the tool runs the tests once.",
+		CommonPatterns: []string{
+			"Run the whole suite once, then re-run a single failure with target and filter while fixing it",
+			"Go: target ./internal/parser/... with filter '^TestParse$' to run one test",
+			"pytest: target tests/test_api.py::test_login, or filter 'login and not slow'",
+			"jest: target src/utils with filter 'formats dates'",
+		},
+		ParameterDetails: map[string]string{
+			"path":          "The directory tests run in: the module or package root for Go, the directory with pytest configuration or package.json for pytest and jest. Python's .venv, venv or env in this directory is used when present; jest is run from node_modules/.bin in this directory or a parent.",
+			"framework":     "Detected from go.mod (also in a parent directory), then pytest.ini, conftest.py or pytest sections in pyproject.toml, setup.cfg or tox.ini, then jest.config.* or jest in package.json.",
+			"target":        "Split on spaces and passed after the options. Values cannot start with '-', so extra flags cannot be passed.",
+			"filter":        "Go: -run regex, with / separating subtests. pytest: -k expression. jest: -t pattern matched against the full test name.",
+			"timeout":       "The command and any processes it started are stopped at the timeout. Go reports which tests were still running in unfinished.",
+			"max_log_lines": "Output is kept from the start and, mostly, the end of each failure, so the first assertion and the final error both appear.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Run a Go module's tests",
+				Arguments:      map[string]any{"path": "/home/user/service"},
+				ExpectedResult: "Counts of passed, failed and skipped tests, and each failure with package, test, file, line, message and output",
+			},
+			{
+				Description: "Re-run one failing pytest test",
+				Arguments: map[string]any{
+					"path":   "/home/user/api",
+					"target": "tests/test_auth.py",
+					"filter": "test_expired_token",
+				},
+				ExpectedResult: "The test's result, with the assertion and traceback location if it still fails",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No tests were run",
+				Solution: "Check target is relative to path and filter matches test names. For Go, filter is a regex matched against each part of the test name.",
+			},
+			{
+				Problem:  "The result has output but no failures",
+				Solution: "The tests did not start, for example because of a missing dependency, a configuration error, or pytest not being installed. The output shows the error.",
+			},
+			{
+				Problem:  "Build failures are reported without a file",
+				Solution: "Go before 1.24 writes build errors outside its JSON output, so they are shown in output instead.",
+			},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package testrunner
+
+// Result is the outcome of a test run
+type Result struct {
+	Framework string `json:"framework"`
+	Dir       string `json:"dir"`
+	Command   string `json:"command"`
+	Passed    bool   `json:"passed"`
+	ExitCode  int    `json:"exit_code"`
+	Duration  string `json:"duration"`
+	TimedOut  bool   `json:"timed_out,omitempty"`
+	Counts    Counts `json:"counts"`
+	// Failures lists failed tests, and packages or files that failed without a failing test, such
+	// as build and import errors
+	Failures []Failure `json:"failures"`
+	// Unfinished lists tests that were running when the run timed out
+	Unfinished []string `json:"unfinished,omitempty"`
+	// Output is the end of the command's output, when failures do not explain the result
+	Output string `json:"output,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Counts are the tests run by outcome. Go subtests are counted as tests.
+type Counts struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// Failure is a failed test, or a package or file that could not be built or loaded
+type Failure struct {
+	// Suite is the Go package, pytest module or jest test file
+	Suite string `json:"suite"`
+	Test  string `json:"test,omitempty"`
+	File  string `json:"file,omitempty"`
+	Line  int    `json:"line,omitempty"`
+	// Message is the first line of the failure, such as the assertion
+	Message string `json:"message,omitempty"`
+	// Output is the test's log and failure detail, with long output cut in the middle
+	Output   string  `json:"output,omitempty"`
+	Duration float64 `json:"duration_seconds,omitempty"`
+}
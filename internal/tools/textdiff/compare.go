@@ -0,0 +1,110 @@
+package textdiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultContext is how many unchanged lines surround each change in a unified diff
+	defaultContext = 3
+
+	// maxContext is the most lines of context that can be asked for
+	maxContext = 100
+)
+
+// DiffResponse is the result of comparing two texts
+type DiffResponse struct {
+	Mode      string       `json:"mode"`
+	Identical bool         `json:"identical"`
+	Additions int          `json:"additions"`
+	Deletions int          `json:"deletions"`
+	Diff      string       `json:"diff,omitempty"`
+	Changes   []JSONChange `json:"changes,omitempty"`
+	Truncated bool         `json:"truncated,omitempty"`
+}
+
+// diffText compares the two texts
+func diffText(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+	mode := "unified"
+	if raw, ok := args["mode"].(string); ok && raw != "" {
+		mode = raw
+	}
+	if mode != "unified" && mode != "words" && mode != "json" {
+		return nil, fmt.Errorf("invalid mode '%s': must be unified, words or json", mode)
+	}
+
+	contextLines := defaultContext
+	if raw, ok := args["context"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value < 0 || value > maxContext || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'context' parameter: must be a whole number from 0 to %d", maxContext)
+		}
+		contextLines = int(value)
+	}
+
+	original, originalLabel, originalWarning, err := loadText(ctx, args, "original", "original_file")
+	if err != nil {
+		return nil, err
+	}
+	modified, modifiedLabel, modifiedWarning, err := loadText(ctx, args, "modified", "modified_file")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"mode":          mode,
+		"original_size": len(original),
+		"modified_size": len(modified),
+	}).Debug("Comparing text")
+
+	response := &DiffResponse{Mode: mode}
+	switch mode {
+	case "unified":
+		response.Diff, response.Additions, response.Deletions = UnifiedDiff(original, modified, originalLabel, modifiedLabel, contextLines)
+		response.Identical = original == modified
+	case "words":
+		response.Diff, response.Additions, response.Deletions, err = wordDiff(original, modified)
+		if err != nil {
+			return nil, err
+		}
+		response.Identical = original == modified
+		if response.Identical {
+			response.Diff = ""
+		}
+	case "json":
+		response.Changes, response.Truncated, err = jsonDiff(original, modified)
+		if err != nil {
+			return nil, err
+		}
+		response.Identical = len(response.Changes) == 0
+		for _, change := range response.Changes {
+			switch change.Op {
+			case "added":
+				response.Additions++
+			case "removed":
+				response.Deletions++
+			default:
+				response.Additions++
+				response.Deletions++
+			}
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := string(jsonBytes)
+	for _, warning := range []*security.SecurityResult{modifiedWarning, originalWarning} {
+		if warning != nil {
+			output = security.FormatSecurityWarningPrefix(warning) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
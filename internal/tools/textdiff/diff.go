@@ -0,0 +1,287 @@
+package textdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// noNewline marks a line in a unified diff that has no line ending
+	noNewline = "\\ No newline at end of file\n"
+
+	// maxWordTokens is the most words and spaces a word diff compares, as word matching slows
+	// quadratically on long, very different texts
+	maxWordTokens = 50_000
+
+	// maxJSONChanges is the most changes a JSON diff lists
+	maxJSONChanges = 1000
+)
+
+// wordToken splits text into words, runs of whitespace and single punctuation characters
+var wordToken = regexp.MustCompile(`\s+|[\p{L}\p{N}_]+|[^\s\p{L}\p{N}_]`)
+
+// identifierKey is an object key that can be written as .key in a path
+var identifierKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// UnifiedDiff returns a unified diff of two texts with the given lines of context, and the number of
+// lines added and removed
+func UnifiedDiff(original, modified, originalLabel, modifiedLabel string, context int) (string, int, int) {
+	a, b := splitLines(original), splitLines(modified)
+	if slices.Equal(a, b) {
+		return "", 0, 0
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", originalLabel, modifiedLabel)
+	additions, deletions := 0, 0
+	for _, group := range difflib.NewMatcher(a, b).GetGroupedOpCodes(context) {
+		first, last := group[0], group[len(group)-1]
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(first.I1, last.I2), hunkRange(first.J1, last.J2))
+		for _, op := range group {
+			if op.Tag == 'e' {
+				writeLines(&out, ' ', a[op.I1:op.I2])
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				writeLines(&out, '-', a[op.I1:op.I2])
+				deletions += op.I2 - op.I1
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				writeLines(&out, '+', b[op.J1:op.J2])
+				additions += op.J2 - op.J1
+			}
+		}
+	}
+	return out.String(), additions, deletions
+}
+
+// hunkRange formats the start and length of a hunk's lines, e.g. 3,4, leaving out a length of one
+func hunkRange(start, stop int) string {
+	length := stop - start
+	switch length {
+	case 0:
+		// An empty range names the line before it
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// writeLines writes lines with a diff prefix, marking a last line that has no line ending
+func writeLines(out *strings.Builder, prefix byte, lines []string) {
+	for _, line := range lines {
+		out.WriteByte(prefix)
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n" + noNewline)
+		}
+	}
+}
+
+// wordDiff returns the modified text with removed words marked [-like this-] and added words {+like
+// this+}, as git diff --word-diff does, and the number of words added and removed
+func wordDiff(original, modified string) (string, int, int, error) {
+	a := wordToken.FindAllString(original, -1)
+	b := wordToken.FindAllString(modified, -1)
+	if len(a)+len(b) > maxWordTokens {
+		return "", 0, 0, fmt.Errorf("the texts have %d words and spaces between them, more than the %d a word diff compares. Use the unified mode instead", len(a)+len(b), maxWordTokens)
+	}
+
+	var out strings.Builder
+	additions, deletions := 0, 0
+	// Automatic junk detection would ignore common tokens such as spaces, which words need to line up
+	for _, op := range difflib.NewMatcherWithJunk(a, b, false, nil).GetOpCodes() {
+		removed, added := strings.Join(a[op.I1:op.I2], ""), strings.Join(b[op.J1:op.J2], "")
+		switch op.Tag {
+		case 'e':
+			out.WriteString(removed)
+			continue
+		case 'r', 'd':
+			out.WriteString("[-" + removed + "-]")
+			deletions += countWords(a[op.I1:op.I2])
+		}
+		if op.Tag == 'r' || op.Tag == 'i' {
+			out.WriteString("{+" + added + "+}")
+			additions += countWords(b[op.J1:op.J2])
+		}
+	}
+	return out.String(), additions, deletions, nil
+}
+
+// countWords counts the tokens that are not whitespace
+func countWords(tokens []string) int {
+	count := 0
+	for _, token := range tokens {
+		if strings.TrimSpace(token) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// JSONChange is one difference between two JSON documents
+type JSONChange struct {
+	Path     string          `json:"path"`
+	Op       string          `json:"op"`
+	Original json.RawMessage `json:"original,omitempty"`
+	Modified json.RawMessage `json:"modified,omitempty"`
+}
+
+// jsonDiff lists the differences between two JSON documents by path, reporting whether the list was
+// cut short
+func jsonDiff(original, modified string) ([]JSONChange, bool, error) {
+	a, err := decodeJSON(original)
+	if err != nil {
+		return nil, false, fmt.Errorf("original is not valid JSON: %w", err)
+	}
+	b, err := decodeJSON(modified)
+	if err != nil {
+		return nil, false, fmt.Errorf("modified is not valid JSON: %w", err)
+	}
+
+	changes := []JSONChange{}
+	truncated := compareJSON(".", a, b, &changes)
+	return changes, truncated, nil
+}
+
+// decodeJSON decodes a single JSON document, keeping numbers exact
+func decodeJSON(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected content after the JSON document")
+	}
+	return value, nil
+}
+
+// compareJSON records the differences between two values at a path, returning true once the change
+// limit is reached
+func compareJSON(path string, a, b any, changes *[]JSONChange) bool {
+	record := func(change JSONChange) bool {
+		if len(*changes) == maxJSONChanges {
+			return true
+		}
+		*changes = append(*changes, change)
+		return false
+	}
+
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for key := range av {
+			keys = append(keys, key)
+		}
+		for key := range bv {
+			if _, exists := av[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			aItem, inA := av[key]
+			bItem, inB := bv[key]
+			keyPath := joinPath(path, key)
+			var full bool
+			switch {
+			case !inA:
+				full = record(JSONChange{Path: keyPath, Op: "added", Modified: rawJSON(bItem)})
+			case !inB:
+				full = record(JSONChange{Path: keyPath, Op: "removed", Original: rawJSON(aItem)})
+			default:
+				full = compareJSON(keyPath, aItem, bItem, changes)
+			}
+			if full {
+				return true
+			}
+		}
+		return false
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(av), len(bv)) {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			var full bool
+			switch {
+			case i >= len(av):
+				full = record(JSONChange{Path: itemPath, Op: "added", Modified: rawJSON(bv[i])})
+			case i >= len(bv):
+				full = record(JSONChange{Path: itemPath, Op: "removed", Original: rawJSON(av[i])})
+			default:
+				full = compareJSON(itemPath, av[i], bv[i], changes)
+			}
+			if full {
+				return true
+			}
+		}
+		return false
+	default:
+		if equalScalars(a, b) {
+			return false
+		}
+	}
+	return record(JSONChange{Path: path, Op: "changed", Original: rawJSON(a), Modified: rawJSON(b)})
+}
+
+// equalScalars reports whether two JSON scalars are equal, comparing numbers by value so 1.0 equals 1
+func equalScalars(a, b any) bool {
+	an, aIsNumber := a.(json.Number)
+	bn, bIsNumber := b.(json.Number)
+	if aIsNumber && bIsNumber {
+		ar, aOK := new(big.Rat).SetString(an.String())
+		br, bOK := new(big.Rat).SetString(bn.String())
+		if aOK && bOK {
+			return ar.Cmp(br) == 0
+		}
+		return an == bn
+	}
+	switch a.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	switch b.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return a == b
+}
+
+// joinPath appends an object key to a jq-style path, quoting keys that are not identifiers
+func joinPath(path, key string) string {
+	if identifierKey.MatchString(key) {
+		return strings.TrimSuffix(path, ".") + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+// rawJSON encodes a value for a change, keeping numbers as written
+func rawJSON(value any) json.RawMessage {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(bytes.TrimSpace(out.Bytes()))
+}
@@ -0,0 +1,67 @@
+package textdiff
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+)
+
+// maxInputSize is the largest text or file either action accepts
+const maxInputSize = 2 * 1024 * 1024
+
+// loadText reads text given inline under textKey or as a file path under fileKey, returning it with a
+// label naming where it came from and any security warning about a file's content
+func loadText(ctx context.Context, args map[string]any, textKey, fileKey string) (string, string, *security.SecurityResult, error) {
+	text, hasText := args[textKey].(string)
+	file, hasFile := args[fileKey].(string)
+	switch {
+	case hasText && hasFile:
+		return "", "", nil, fmt.Errorf("provide either '%s' or '%s', not both", textKey, fileKey)
+	case hasText:
+		if len(text) > maxInputSize {
+			return "", "", nil, fmt.Errorf("'%s' is %d bytes, more than the maximum of %d", textKey, len(text), maxInputSize)
+		}
+		return text, textKey, nil, nil
+	case !hasFile || file == "":
+		return "", "", nil, fmt.Errorf("missing required parameter: provide '%s' (inline text) or '%s' (a file path)", textKey, fileKey)
+	}
+
+	path, err := workspace.ResolveToolPath(ctx, fileKey, file)
+	if err != nil {
+		return "", "", nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to read %s: %w", fileKey, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", "", nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > maxInputSize {
+		return "", "", nil, fmt.Errorf("%s is %d bytes, more than the maximum of %d", path, info.Size(), maxInputSize)
+	}
+
+	safeFile, err := security.NewOperations(toolName).SafeFileRead(path)
+	if err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return "", "", nil, security.FormatSecurityBlockError(secErr)
+		}
+		return "", "", nil, fmt.Errorf("failed to read %s: %w", fileKey, err)
+	}
+	var warning *security.SecurityResult
+	if safeFile.SecurityResult != nil && safeFile.SecurityResult.Action == security.ActionWarn {
+		warning = safeFile.SecurityResult
+	}
+	return string(safeFile.Content), path, warning, nil
+}
+
+// splitLines splits text into lines, each keeping its line ending. The last line has none if the text
+// does not end with a newline.
+func splitLines(text string) []string {
+	return slices.Collect(strings.Lines(text))
+}
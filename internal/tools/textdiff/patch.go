@@ -0,0 +1,326 @@
+package textdiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sirupsen/logrus"
+)
+
+// hunkHeader matches a hunk header such as @@ -12,4 +12,5 @@, where a count of one may be left out
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// HunkResult describes where a hunk was applied
+type HunkResult struct {
+	Hunk   int  `json:"hunk"`
+	Line   int  `json:"line"`
+	Offset int  `json:"offset,omitempty"`
+	Fuzzy  bool `json:"fuzzy,omitempty"`
+}
+
+// ApplyResponse is the result of applying a patch
+type ApplyResponse struct {
+	Content      string       `json:"content"`
+	HunksApplied int          `json:"hunks_applied"`
+	Additions    int          `json:"additions"`
+	Deletions    int          `json:"deletions"`
+	Hunks        []HunkResult `json:"hunks"`
+}
+
+// patchLine is one line of a hunk
+type patchLine struct {
+	kind      byte
+	text      string
+	noNewline bool
+}
+
+// hunk is one hunk of a unified diff
+type hunk struct {
+	header   string
+	oldStart int
+	oldCount int
+	newCount int
+	lines    []patchLine
+}
+
+// applyPatchText applies the patch to the original text
+func applyPatchText(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+	patch, ok := args["patch"].(string)
+	if !ok || patch == "" {
+		return nil, fmt.Errorf("missing required parameter 'patch': a unified diff starting with a hunk header such as '@@ -1,3 +1,4 @@'")
+	}
+	if len(patch) > maxInputSize {
+		return nil, fmt.Errorf("'patch' is %d bytes, more than the maximum of %d", len(patch), maxInputSize)
+	}
+
+	original, _, warning, err := loadText(ctx, args, "original", "original_file")
+	if err != nil {
+		return nil, err
+	}
+
+	hunks, err := parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"original_size": len(original),
+		"hunks":         len(hunks),
+	}).Debug("Applying patch")
+
+	response, err := applyPatch(original, hunks)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	output := string(jsonBytes)
+	if warning != nil {
+		output = security.FormatSecurityWarningPrefix(warning) + output
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// parsePatch reads the hunks of a single-file unified diff, skipping file headers and other lines
+// before the first hunk. Hunk line counts mark where a hunk ends, and a hunk that is empty, or
+// longer or shorter than its header says, is rejected.
+func parsePatch(patch string) ([]*hunk, error) {
+	var hunks []*hunk
+	var current *hunk
+	oldSeen, newSeen := 0, 0
+	lineNumber := 0
+	for line := range strings.Lines(patch) {
+		lineNumber++
+		content := strings.TrimRight(line, "\r\n")
+		complete := current == nil || (oldSeen >= current.oldCount && newSeen >= current.newCount)
+
+		if strings.HasPrefix(content, "@@") {
+			match := hunkHeader.FindStringSubmatch(content)
+			if match == nil {
+				return nil, fmt.Errorf("line %d of the patch is not a valid hunk header: %q. Hunk headers need line numbers, e.g. '@@ -12,4 +12,5 @@'", lineNumber, content)
+			}
+			if current != nil {
+				if err := checkHunkLength(current, oldSeen, newSeen); err != nil {
+					return nil, err
+				}
+			}
+			current = &hunk{
+				header:   content,
+				oldStart: atoi(match[1]),
+				oldCount: countOrOne(match[2]),
+				newCount: countOrOne(match[4]),
+			}
+			hunks = append(hunks, current)
+			oldSeen, newSeen = 0, 0
+			continue
+		}
+
+		if current == nil || complete {
+			// File headers and other lines between hunks
+			if strings.HasPrefix(content, "--- ") && len(hunks) > 0 {
+				return nil, fmt.Errorf("line %d of the patch starts a second file. A patch can only change one file", lineNumber)
+			}
+			if current != nil && strings.HasPrefix(content, `\`) {
+				markNoNewline(current)
+			}
+			// The "-- " line git format-patch puts before its signature
+			if current == nil || content == "" || content == "-- " || !strings.ContainsAny(content[:1], " +-") {
+				continue
+			}
+			return nil, fmt.Errorf("line %d of the patch is past the end of hunk %q, whose header counts %d original and %d new lines. Fix the counts in the header", lineNumber, current.header, current.oldCount, current.newCount)
+		}
+
+		switch {
+		case content == "":
+			// Some editors strip the space from blank context lines
+			current.lines = append(current.lines, patchLine{kind: ' '})
+			oldSeen++
+			newSeen++
+		case content[0] == '\\':
+			markNoNewline(current)
+		case content[0] == ' ', content[0] == '-', content[0] == '+':
+			current.lines = append(current.lines, patchLine{kind: content[0], text: content[1:]})
+			if content[0] != '+' {
+				oldSeen++
+			}
+			if content[0] != '-' {
+				newSeen++
+			}
+		default:
+			return nil, fmt.Errorf("line %d of the patch, in hunk %q, must start with ' ', '-' or '+': %q", lineNumber, current.header, content)
+		}
+	}
+
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("the patch has no hunks. A unified diff needs at least one hunk header such as '@@ -1,3 +1,4 @@'")
+	}
+	if err := checkHunkLength(current, oldSeen, newSeen); err != nil {
+		return nil, err
+	}
+	return hunks, nil
+}
+
+// checkHunkLength checks that a hunk has lines, and that its context and removed lines, and its
+// context and added lines, number what its header says
+func checkHunkLength(h *hunk, oldSeen, newSeen int) error {
+	if len(h.lines) == 0 {
+		return fmt.Errorf("hunk %q has no lines. A hunk needs the context, removed and added lines its header counts", h.header)
+	}
+	if oldSeen != h.oldCount || newSeen != h.newCount {
+		return fmt.Errorf("hunk %q has %d original and %d new lines, but its header counts %d and %d. Fix the counts in the header", h.header, oldSeen, newSeen, h.oldCount, h.newCount)
+	}
+	return nil
+}
+
+// markNoNewline records that the last line of a hunk has no line ending
+func markNoNewline(h *hunk) {
+	if len(h.lines) > 0 {
+		h.lines[len(h.lines)-1].noNewline = true
+	}
+}
+
+// countOrOne parses a hunk range length, which is one when left out
+func countOrOne(value string) int {
+	if value == "" {
+		return 1
+	}
+	return atoi(value)
+}
+
+// atoi parses digits already matched by hunkHeader
+func atoi(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+// applyPatch applies hunks in order to the original text. Each hunk is matched exactly where its
+// header says, or the nearest place after the previous hunk, then with trailing whitespace ignored.
+func applyPatch(original string, hunks []*hunk) (*ApplyResponse, error) {
+	lines := splitLines(original)
+	lineEnding := "\n"
+	if strings.Contains(original, "\r\n") {
+		lineEnding = "\r\n"
+	}
+
+	response := &ApplyResponse{Hunks: []HunkResult{}}
+	var out strings.Builder
+	next, offset := 0, 0
+	for i, h := range hunks {
+		var old []string
+		for _, line := range h.lines {
+			if line.kind != '+' {
+				old = append(old, line.text)
+			}
+		}
+
+		// A range with no old lines names the line before the insertion
+		expected := h.oldStart - 1
+		if len(old) == 0 {
+			expected = h.oldStart
+		}
+		expected = min(max(expected+offset, next), len(lines))
+
+		fuzzy := false
+		position := findLines(lines, old, expected, next, exactLine)
+		if position < 0 {
+			fuzzy = true
+			position = findLines(lines, old, expected, next, looseLine)
+		}
+		if position < 0 {
+			return nil, hunkMismatch(i+1, h, lines, old, expected)
+		}
+
+		for _, line := range lines[next:position] {
+			out.WriteString(line)
+		}
+		cursor := position
+		for _, line := range h.lines {
+			switch line.kind {
+			case ' ':
+				// Context keeps the original's text, including its line ending
+				out.WriteString(lines[cursor])
+				cursor++
+			case '-':
+				cursor++
+				response.Deletions++
+			case '+':
+				out.WriteString(line.text)
+				if !line.noNewline {
+					out.WriteString(lineEnding)
+				}
+				response.Additions++
+			}
+		}
+
+		offset = position - (h.oldStart - 1)
+		if len(old) == 0 {
+			offset = position - h.oldStart
+		}
+		response.Hunks = append(response.Hunks, HunkResult{Hunk: i + 1, Line: position + 1, Offset: offset, Fuzzy: fuzzy})
+		next = cursor
+	}
+	for _, line := range lines[next:] {
+		out.WriteString(line)
+	}
+
+	response.Content = out.String()
+	response.HunksApplied = len(hunks)
+	return response, nil
+}
+
+// exactLine reports whether a line of the text matches a line of the patch, ignoring line endings
+func exactLine(line, want string) bool {
+	return strings.TrimRight(line, "\r\n") == strings.TrimSuffix(want, "\r")
+}
+
+// looseLine reports whether a line of the text matches a line of the patch, ignoring trailing whitespace
+func looseLine(line, want string) bool {
+	return strings.TrimRight(line, " \t\r\n") == strings.TrimRight(want, " \t\r")
+}
+
+// findLines returns where old appears in lines, searching outwards from expected but never before
+// earliest, or -1 if it is not found
+func findLines(lines, old []string, expected, earliest int, equal func(line, want string) bool) int {
+	latest := len(lines) - len(old)
+	matchesAt := func(position int) bool {
+		for j, want := range old {
+			if !equal(lines[position+j], want) {
+				return false
+			}
+		}
+		return true
+	}
+	for distance := 0; expected-distance >= earliest || expected+distance <= latest; distance++ {
+		if after := expected + distance; after >= earliest && after <= latest && matchesAt(after) {
+			return after
+		}
+		if before := expected - distance; distance > 0 && before >= earliest && before <= latest && matchesAt(before) {
+			return before
+		}
+	}
+	return -1
+}
+
+// hunkMismatch explains why a hunk does not apply, naming the first line that differs where the hunk
+// was expected
+func hunkMismatch(index int, h *hunk, lines, old []string, expected int) error {
+	for j, want := range old {
+		position := expected + j
+		if position >= len(lines) {
+			return fmt.Errorf("hunk %d (%s) does not apply: it expects line %d to be %q, but the text has only %d lines", index, h.header, position+1, want, len(lines))
+		}
+		if !looseLine(lines[position], want) {
+			return fmt.Errorf("hunk %d (%s) does not apply: line %d is %q, expected %q, and the hunk's lines were not found elsewhere", index, h.header, position+1, strings.TrimRight(lines[position], "\r\n"), want)
+		}
+	}
+	return fmt.Errorf("hunk %d (%s) does not apply: its lines were not found after the previous hunk", index, h.header)
+}
@@ -0,0 +1,171 @@
+package textdiff
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// toolName is the text_diff tool's name, used for security checks on the files it reads
+const toolName = "text_diff"
+
+// The text_diff tool's actions
+const (
+	actionDiff  = "diff"
+	actionApply = "apply"
+)
+
+// TextDiffTool compares two texts or applies a unified diff to text, without writing anything
+type TextDiffTool struct{}
+
+// init registers the text_diff tool
+func init() {
+	registry.Register(&TextDiffTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (d *TextDiffTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		toolName,
+		mcp.WithDescription(`Compares or patches text, given inline or as file paths, without writing anything. Actions:
+- diff: compare original and modified text (default). Modes:
+  - unified: a unified diff (default), which the apply action can apply
+  - words: the modified text with removed words marked [-like this-] and added words {+like this+}
+  - json: the differences between two JSON documents listed by path, ignoring formatting and key order
+- apply: apply a unified diff to the original text and return the patched text. Hunks are found near their stated line numbers if the text has moved, and every hunk must apply or nothing is returned.
+
+Useful for checking generated content against an existing file, or checking a patch applies, before writing it.`),
+		mcp.WithString("action",
+			mcp.Description("Action to perform (default: diff)"),
+			mcp.Enum(actionDiff, actionApply),
+		),
+		mcp.WithString("original",
+			mcp.Description("Original text, or for apply the text to patch. Use this or original_file"),
+		),
+		mcp.WithString("original_file",
+			mcp.Description("Absolute path of a file holding the original text. The file is not changed"),
+		),
+		mcp.WithString("modified",
+			mcp.Description("For diff: modified text. Use this or modified_file"),
+		),
+		mcp.WithString("modified_file",
+			mcp.Description("For diff: absolute path of a file holding the modified text"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("For diff: how to compare the texts"),
+			mcp.Enum("unified", "words", "json"),
+			mcp.DefaultString("unified"),
+		),
+		mcp.WithNumber("context",
+			mcp.Description(fmt.Sprintf("For diff: unchanged lines around each change in unified mode (default: %d, max: %d)", defaultContext, maxContext)),
+		),
+		mcp.WithString("patch",
+			mcp.Description("For apply (required): unified diff for a single file, as produced by the diff action, diff -u or git diff"),
+		),
+		// Read-only annotations for comparing and patching text in memory
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the given files and returns text, never writes files
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(true),   // The same texts and patch give the same result
+		mcp.WithOpenWorldHintAnnotation(false),   // Works on local text, no external interactions
+	)
+}
+
+// Execute runs the requested action
+func (d *TextDiffTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionDiff
+	if raw, ok := args["action"].(string); ok && raw != "" {
+		action = raw
+	}
+	switch action {
+	case actionDiff:
+		return diffText(ctx, logger, args)
+	case actionApply:
+		return applyPatchText(ctx, logger, args)
+	default:
+		return nil, fmt.Errorf("invalid action '%s': must be diff or apply", action)
+	}
+}
+
+// ProvideExtendedInfo provides detailed usage information for the text_diff tool
+func (d *TextDiffTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Checking generated or edited content against an existing file before writing it, reviewing what changed between two versions of a config or document, comparing two API responses, or applying a unified diff in memory to check that it applies and to see the result before writing the file with another tool.",
+		WhenNotToUse: "Comparing commits or branches in a git repository - use git diff. Patches that change several files, or binary patches - apply them with git apply. Binary files are not supported. To change a file directly, use an edit tool.",
+		CommonPatterns: []string{
+			"Preview an edit: original_file as the current file, modified as the new content",
+			"Compare prose with mode 'words', where a whole paragraph is often one line",
+			"Compare API responses or config with mode 'json', which ignores formatting and key order",
+			"Check a patch applies: action 'apply' with original_file as the current file and the patch",
+			"Round trip: diff in unified mode, then apply the diff to reproduce the modified text",
+			"Inspect the hunks list of an apply result for offsets or fuzzy matches before trusting it",
+		},
+		ParameterDetails: map[string]string{
+			"action":        "diff (default) needs original and modified. apply needs original and patch.",
+			"original":      fmt.Sprintf("Inline original text, up to %d MiB. Give either original or original_file.", maxInputSize/1024/1024),
+			"original_file": "Absolute path of the original file, or a path relative to the client's workspace root. Subject to the security framework's file access rules. The file is only read.",
+			"modified":      fmt.Sprintf("Inline modified text, up to %d MiB. Give either modified or modified_file.", maxInputSize/1024/1024),
+			"modified_file": "Absolute path of the modified file, or a path relative to the client's workspace root.",
+			"mode":          "unified counts changed lines, words counts changed words, and json counts changed values. In json mode a changed value counts as one addition and one deletion.",
+			"context":       "Only used in unified mode. 0 gives only the changed lines.",
+			"patch":         "A unified diff for one file. --- and +++ headers and lines such as 'diff --git' before the first hunk are ignored. Hunks must be in order of position.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Preview changes to a file",
+				Arguments:      map[string]any{"original_file": "/project/config.yaml", "modified": "port: 8080\nhost: 0.0.0.0\n"},
+				ExpectedResult: "A unified diff with --- /project/config.yaml and +++ modified headers",
+			},
+			{
+				Description:    "Word diff of a sentence",
+				Arguments:      map[string]any{"original": "The quick brown fox", "modified": "The quick red fox", "mode": "words"},
+				ExpectedResult: "diff: 'The quick [-brown-]{+red+} fox'",
+			},
+			{
+				Description:    "Structural JSON diff",
+				Arguments:      map[string]any{"original": `{"name": "api", "replicas": 2}`, "modified": `{"replicas": 3, "name": "api", "debug": true}`, "mode": "json"},
+				ExpectedResult: "Changes: .debug added, .replicas changed from 2 to 3",
+			},
+			{
+				Description:    "Apply a one-line change",
+				Arguments:      map[string]any{"action": "apply", "original": "port: 80\nhost: localhost\n", "patch": "@@ -1,2 +1,2 @@\n-port: 80\n+port: 8080\n host: localhost\n"},
+				ExpectedResult: "content: 'port: 8080\\nhost: localhost\\n' with one hunk applied at line 1",
+			},
+			{
+				Description:    "Check a patch against a file",
+				Arguments:      map[string]any{"action": "apply", "original_file": "/project/main.go", "patch": "--- a/main.go\n+++ b/main.go\n@@ -10,3 +10,4 @@\n..."},
+				ExpectedResult: "The patched file content, or an error naming the hunk and line that do not match",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Every line shows as changed",
+				Solution: "The texts probably use different line endings (CRLF and LF) or indentation. Normalise them before comparing.",
+			},
+			{
+				Problem:  "Word diff is too large",
+				Solution: fmt.Sprintf("Word diffs compare at most %d words and spaces. Use unified mode for large texts.", maxWordTokens),
+			},
+			{
+				Problem:  "JSON array changes are reported item by item",
+				Solution: "Arrays are compared by index, so an item inserted near the start changes every later index. Use unified mode on pretty-printed JSON to see insertions.",
+			},
+			{
+				Problem:  "Hunk does not apply",
+				Solution: "The text has changed since the patch was made, or the patch's context lines are wrong. The error names the first line that differs; regenerate the patch with the diff action against the current text.",
+			},
+			{
+				Problem:  "A hunk is reported with an offset",
+				Solution: "Its lines were found away from the line numbers in its header, usually because earlier lines were added or removed. Check the result is as intended.",
+			},
+			{
+				Problem:  "A hunk is reported as fuzzy",
+				Solution: "Its lines matched only when trailing whitespace was ignored. Context lines keep the text's own whitespace, but added lines are used as written in the patch.",
+			},
+		},
+	}
+}
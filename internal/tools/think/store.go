@@ -0,0 +1,293 @@
+package think
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ThinkPersistEnvVar enables saving scratchpads to disk, so they survive restarts
+	ThinkPersistEnvVar = "THINK_PERSIST"
+
+	// maxStoredThoughts is how many of a session's most recent thoughts are kept
+	maxStoredThoughts = 500
+
+	// scratchpadTTL is how long a scratchpad is kept after its last thought
+	scratchpadTTL = 7 * 24 * time.Hour
+
+	// lockRetryDelay is how often a busy scratchpad lock is retried
+	lockRetryDelay = 20 * time.Millisecond
+
+	// lockTimeout is how long to wait for another process to release a scratchpad
+	lockTimeout = 2 * time.Second
+)
+
+var (
+	// storeMu serialises access to scratchpads within the process; file locks serialise processes
+	storeMu sync.Mutex
+
+	// memoryScratchpads holds scratchpads kept in memory, keyed by scratchpad name: every scratchpad
+	// when persistence is off, and those that could not be saved when it is on. Caller must hold storeMu.
+	memoryScratchpads = map[string][]Thought{}
+)
+
+// persistenceEnabled reports whether scratchpads are saved to disk
+func persistenceEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(ThinkPersistEnvVar))
+	return enabled
+}
+
+// scratchpadDir returns the directory scratchpads are stored in
+func scratchpadDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "thoughts"), nil
+}
+
+// scratchpadName names the calling session's scratchpad. Every stdio server has the same session ID,
+// so stdio clients, and calls outside an MCP session, get a scratchpad per workspace instead: separate
+// projects keep separate thoughts, and a project's thoughts survive client and server restarts.
+func scratchpadName(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" && session.SessionID() != "stdio" {
+		return safeSessionName(session.SessionID())
+	}
+	dir := ""
+	if roots := workspace.Roots(ctx); len(roots) > 0 {
+		dir = roots[0]
+	} else if cwd, err := os.Getwd(); err == nil {
+		dir = cwd
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return "workspace-" + hex.EncodeToString(sum[:8])
+}
+
+// loadThoughts returns the calling session's thoughts, oldest first
+func loadThoughts(ctx context.Context, logger *logrus.Logger) []Thought {
+	name := scratchpadName(ctx)
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	if thoughts, inMemory := memoryScratchpads[name]; inMemory || !persistenceEnabled() {
+		return thoughts
+	}
+	thoughts, err := readLockedScratchpad(ctx, name)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to read saved thoughts")
+		return nil
+	}
+	return thoughts
+}
+
+// appendThought numbers a thought, checks what it revises and adds it to the calling session's
+// scratchpad, returning the stored thought. A scratchpad that cannot be saved is kept in memory from
+// then on, so a disk problem never stops thoughts being recorded.
+func appendThought(ctx context.Context, logger *logrus.Logger, thought Thought) (Thought, error) {
+	name := scratchpadName(ctx)
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	if thoughts, inMemory := memoryScratchpads[name]; inMemory || !persistenceEnabled() {
+		return appendInMemory(name, thoughts, thought)
+	}
+
+	var saved []Thought
+	stored, err := func() (Thought, error) {
+		path, unlock, err := lockScratchpad(ctx, name)
+		if err != nil {
+			return thought, err
+		}
+		defer unlock()
+
+		if saved, err = readScratchpad(path); err != nil {
+			return thought, err
+		}
+		thoughts, stored, err := addThought(saved, thought)
+		if err != nil {
+			return stored, err
+		}
+		if err := writeScratchpad(path, thoughts); err != nil {
+			return stored, err
+		}
+		removeExpiredScratchpads(filepath.Dir(path))
+		return stored, nil
+	}()
+	if _, invalid := err.(revisionError); err == nil || invalid {
+		return stored, err
+	}
+
+	logger.WithError(err).Warn("Failed to save thought, keeping this session's thoughts in memory")
+	return appendInMemory(name, saved, thought)
+}
+
+// appendInMemory adds a thought to a scratchpad kept in memory. Caller must hold storeMu.
+func appendInMemory(name string, thoughts []Thought, thought Thought) (Thought, error) {
+	thoughts, stored, err := addThought(thoughts, thought)
+	if err != nil {
+		return stored, err
+	}
+	memoryScratchpads[name] = thoughts
+
+	// Drop scratchpads of sessions that have stopped thinking
+	for other, otherThoughts := range memoryScratchpads {
+		if len(otherThoughts) > 0 && time.Since(otherThoughts[len(otherThoughts)-1].Timestamp) > scratchpadTTL {
+			delete(memoryScratchpads, other)
+		}
+	}
+	return stored, nil
+}
+
+// revisionError reports a thought that revises a thought that does not exist
+type revisionError struct {
+	revises, latest int
+}
+
+func (e revisionError) Error() string {
+	return fmt.Sprintf("invalid 'revises' parameter: thought %d does not exist, the latest thought is %d. Use action 'get_thoughts' to see the recorded thoughts", e.revises, e.latest)
+}
+
+// addThought numbers a thought and appends it to a scratchpad's thoughts, keeping the most recent
+// maxStoredThoughts
+func addThought(thoughts []Thought, thought Thought) ([]Thought, Thought, error) {
+	thought.Number = 1
+	if len(thoughts) > 0 {
+		thought.Number = thoughts[len(thoughts)-1].Number + 1
+	}
+	if thought.Revises != 0 && (thought.Revises < 1 || thought.Revises >= thought.Number) {
+		return thoughts, thought, revisionError{revises: thought.Revises, latest: thought.Number - 1}
+	}
+
+	thoughts = append(thoughts, thought)
+	if len(thoughts) > maxStoredThoughts {
+		thoughts = thoughts[len(thoughts)-maxStoredThoughts:]
+	}
+	return thoughts, thought, nil
+}
+
+// lockScratchpad takes an exclusive lock on a scratchpad file, which other server processes may
+// share, and returns its path and a function that releases the lock
+func lockScratchpad(ctx context.Context, name string) (string, func(), error) {
+	dir, err := scratchpadDir()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", nil, fmt.Errorf("failed to create thoughts directory: %w", err)
+	}
+	path := filepath.Join(dir, name+".json")
+
+	lock := flock.New(path + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(lockCtx, lockRetryDelay)
+	if err != nil || !locked {
+		return "", nil, fmt.Errorf("scratchpad %s is locked by another process", path)
+	}
+	return path, func() { _ = lock.Unlock() }, nil
+}
+
+// readLockedScratchpad reads a saved scratchpad under a shared lock. Caller must hold storeMu.
+func readLockedScratchpad(ctx context.Context, name string) ([]Thought, error) {
+	dir, err := scratchpadDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+".json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	lock := flock.New(path + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+	locked, err := lock.TryRLockContext(lockCtx, lockRetryDelay)
+	if err != nil || !locked {
+		return nil, fmt.Errorf("scratchpad %s is locked by another process", path)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return readScratchpad(path)
+}
+
+// readScratchpad reads a scratchpad file, which is empty if it does not exist yet. Caller must hold
+// the file's lock.
+func readScratchpad(path string) ([]Thought, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read thoughts: %w", err)
+	}
+	var pad scratchpad
+	if err := json.Unmarshal(data, &pad); err != nil {
+		return nil, fmt.Errorf("failed to parse thoughts in %s: %w", path, err)
+	}
+	return pad.Thoughts, nil
+}
+
+// writeScratchpad saves a scratchpad file. Caller must hold the file's lock.
+func writeScratchpad(path string, thoughts []Thought) error {
+	data, err := json.MarshalIndent(scratchpad{Thoughts: thoughts}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal thoughts: %w", err)
+	}
+
+	// Write to a temporary file and rename it so a crash never leaves a partly written scratchpad
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to save thought: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save thought: %w", err)
+	}
+	return nil
+}
+
+// removeExpiredScratchpads removes scratchpads with no new thoughts within scratchpadTTL, and their
+// lock files. Caller must hold storeMu.
+func removeExpiredScratchpads(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > scratchpadTTL {
+			path := filepath.Join(dir, entry.Name())
+			_ = os.Remove(path)
+			_ = os.Remove(path + ".lock")
+		}
+	}
+}
+
+// safeSessionName reduces a session ID to characters that are safe in a file name
+func safeSessionName(sessionID string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, sessionID)
+}
@@ -2,8 +2,10 @@ package think
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,7 +16,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// ThinkTool implements a simple thinking tool for structured reasoning
+// ThinkTool implements a scratchpad of numbered thoughts for structured reasoning
 type ThinkTool struct{}
 
 const (
@@ -48,10 +50,10 @@ func (t *ThinkTool) Definition() mcp.Tool {
 	maxLen := getMaxThoughtLength()
 
 	// Build description, conditionally including sequential_thinking reference
-	desc := `A scratchpad for reasoning when you're stuck on a problem or decision after attempting it normally. Does not retrieve information or modify anything - just records a short thought. Only use for complex problems or persistent issues, not routine decisions or first-pass reasoning. State what you need to reason about and why. 1-2 concise sentences, no code.`
+	desc := `A scratchpad for reasoning when you're stuck on a problem or decision after attempting it normally. Does not retrieve information or modify anything - just records a short, numbered thought in the session's scratchpad. A thought can revise an earlier one or start a labelled branch. Only use for complex problems or persistent issues, not routine decisions or first-pass reasoning. State what you need to reason about and why. 1-2 concise sentences, no code.`
 
 	if _, ok := registry.GetTool("sequential_thinking"); ok {
-		desc += "\n\nFor multi-step reasoning, revision, or branching analysis, use sequential_thinking instead."
+		desc += "\n\nFor a long analysis that works through many steps, use sequential_thinking instead."
 	}
 
 	// Build thought parameter description, conditionally referencing sequential_thinking
@@ -63,38 +65,92 @@ func (t *ThinkTool) Definition() mcp.Tool {
 	return mcp.NewTool(
 		"think",
 		mcp.WithDescription(desc),
+		mcp.WithString("action",
+			mcp.Description("'think' (default) records a numbered thought. 'get_thoughts' returns this session's recorded thoughts, e.g. to pick up a line of reasoning."),
+			mcp.Enum("think", "get_thoughts"),
+		),
 		mcp.WithString("thought",
-			mcp.Required(),
 			mcp.MaxLength(maxLen),
-			mcp.Description(thoughtDesc),
+			mcp.Description(thoughtDesc+" Required for 'think'."),
 		),
 		mcp.WithString("how_hard",
 			mcp.Description("How hard to think about the problem. Options: 'hard' (default), 'harder', 'ultra'."),
 			mcp.Enum("hard", "harder", "ultra"),
 		),
-		// Read-only annotations for internal thought processing tool
-		mcp.WithReadOnlyHintAnnotation(true),     // Only processes thoughts internally, doesn't modify environment
-		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
-		mcp.WithIdempotentHintAnnotation(true),   // Stateless: same input produces same output
-		mcp.WithOpenWorldHintAnnotation(false),   // No external interactions, internal processing only
+		mcp.WithNumber("revises",
+			mcp.Description("Number of an earlier thought this one revises"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Label for an alternative line of reasoning. With 'get_thoughts', only returns that branch."),
+		),
+		// Annotations for a scratchpad kept in memory or on local disk
+		mcp.WithReadOnlyHintAnnotation(false),    // Records thoughts in the session's scratchpad
+		mcp.WithDestructiveHintAnnotation(false), // Only appends thoughts, never removes them
+		mcp.WithIdempotentHintAnnotation(false),  // Each call records a new numbered thought
+		mcp.WithOpenWorldHintAnnotation(false),   // At most writes to a local file, no external interactions
 	)
 }
 
 // Execute executes the think tool
 func (t *ThinkTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := "think"
+	if actionValue, exists := args["action"]; exists {
+		actionStr, ok := actionValue.(string)
+		if !ok || (actionStr != "think" && actionStr != "get_thoughts") {
+			return nil, fmt.Errorf("invalid 'action' parameter: must be 'think' (default) or 'get_thoughts'")
+		}
+		action = actionStr
+	}
+
+	if action == "get_thoughts" {
+		branch, _ := args["branch"].(string)
+		return t.getThoughts(ctx, logger, strings.TrimSpace(branch))
+	}
+
 	// Parse and validate parameters
 	request, err := t.parseRequest(args)
 	if err != nil {
 		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	// Create response with timestamp
-	response := &ThinkResponse{
+	thought, err := appendThought(ctx, logger, Thought{
 		Thought:   request.Thought,
+		HowHard:   request.HowHard,
+		Revises:   request.Revises,
+		Branch:    request.Branch,
 		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"number":  thought.Number,
+		"revises": thought.Revises,
+		"branch":  thought.Branch,
+	}).Debug("Recorded thought")
+
+	return t.newToolResultText(thought)
+}
+
+// getThoughts returns the session's recorded thoughts, optionally only those on one branch
+func (t *ThinkTool) getThoughts(ctx context.Context, logger *logrus.Logger, branch string) (*mcp.CallToolResult, error) {
+	thoughts := loadThoughts(ctx, logger)
+	response := GetThoughtsResponse{Thoughts: []Thought{}}
+	for _, thought := range thoughts {
+		if thought.Branch != "" && !slices.Contains(response.Branches, thought.Branch) {
+			response.Branches = append(response.Branches, thought.Branch)
+		}
+		if branch == "" || thought.Branch == branch {
+			response.Thoughts = append(response.Thoughts, thought)
+		}
 	}
 
-	return t.newToolResultText(request.HowHard, response.Thought)
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal thoughts: %w", err)
+	}
+	return mcp.NewToolResultText(string(data)), nil
 }
 
 // parseRequest parses and validates the tool arguments
@@ -129,20 +185,51 @@ func (t *ThinkTool) parseRequest(args map[string]any) (*ThinkRequest, error) {
 		}
 	}
 
+	// Parse revises (optional), the number of an earlier thought
+	revises := 0
+	if revisesValue, exists := args["revises"]; exists {
+		revisesNum, ok := revisesValue.(float64)
+		if !ok || revisesNum < 1 || revisesNum != float64(int(revisesNum)) {
+			return nil, fmt.Errorf("invalid 'revises' parameter: must be the number of an earlier thought (e.g., 2)")
+		}
+		revises = int(revisesNum)
+	}
+
+	// Parse branch (optional)
+	branch := ""
+	if branchValue, exists := args["branch"]; exists {
+		branchStr, ok := branchValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'branch' parameter: must be a string label (e.g., \"alternative-cache\")")
+		}
+		branch = strings.TrimSpace(branchStr)
+	}
+
 	return &ThinkRequest{
 		Thought: thought,
 		HowHard: howHard,
+		Revises: revises,
+		Branch:  branch,
 	}, nil
 }
 
 // newToolResultText creates a new tool result with text content
-func (t *ThinkTool) newToolResultText(howHard, thought string) (*mcp.CallToolResult, error) {
+func (t *ThinkTool) newToolResultText(thought Thought) (*mcp.CallToolResult, error) {
 	var toolName string
-	if howHard == "ultra" {
+	if thought.HowHard == "ultra" {
 		toolName = "ultrathink"
 	} else {
-		toolName = fmt.Sprintf("think %s", howHard)
+		toolName = fmt.Sprintf("think %s", thought.HowHard)
 	}
-	formattedThought := fmt.Sprintf("I should use the %s tool on this problem: %s", toolName, thought)
+
+	label := fmt.Sprintf("Thought %d", thought.Number)
+	if thought.Revises != 0 {
+		label += fmt.Sprintf(" (revises thought %d)", thought.Revises)
+	}
+	if thought.Branch != "" {
+		label += fmt.Sprintf(" (branch: %s)", thought.Branch)
+	}
+
+	formattedThought := fmt.Sprintf("%s: I should use the %s tool on this problem: %s", label, toolName, thought.Thought)
 	return mcp.NewToolResultText(formattedThought), nil
 }
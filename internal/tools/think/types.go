@@ -6,10 +6,27 @@ import "time"
 type ThinkRequest struct {
 	Thought string `json:"thought"`
 	HowHard string `json:"how_hard"`
+	Revises int    `json:"revises,omitempty"`
+	Branch  string `json:"branch,omitempty"`
 }
 
-// ThinkResponse represents the output of the think tool
-type ThinkResponse struct {
+// Thought is a thought recorded in a session's scratchpad
+type Thought struct {
+	Number    int       `json:"number"`
 	Thought   string    `json:"thought"`
+	HowHard   string    `json:"how_hard,omitempty"`
+	Revises   int       `json:"revises,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// GetThoughtsResponse represents the output of the get_thoughts action
+type GetThoughtsResponse struct {
+	Thoughts []Thought `json:"thoughts"`
+	Branches []string  `json:"branches,omitempty"`
+}
+
+// scratchpad is the file a session's thoughts are stored in
+type scratchpad struct {
+	Thoughts []Thought `json:"thoughts"`
+}
@@ -0,0 +1,454 @@
+package timetool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	// Embed the IANA time zone database so zones resolve on Windows and minimal containers without one
+	_ "time/tzdata"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/utils/cron"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultCronRuns is how many upcoming runs of a cron expression are listed by default
+	defaultCronRuns = 5
+
+	// maxCronRuns is the most upcoming runs of a cron expression that can be listed
+	maxCronRuns = 50
+
+	// maxBusinessDays is the most business days that can be added or counted, about 40 years
+	maxBusinessDays = 10_000
+
+	// maxTimezones is the most time zones a single call can show
+	maxTimezones = 25
+)
+
+// timeLayouts are the formats accepted for times without an explicit offset, tried in order
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// TimeTool reports and converts times across time zones, does business-day arithmetic and explains cron
+// expressions
+type TimeTool struct{}
+
+// init registers the time tool
+func init() {
+	registry.Register(&TimeTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *TimeTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"time",
+		mcp.WithDescription(`Current time in IANA time zones, conversion between zones, business-day arithmetic and cron schedules.
+
+Actions: now (current time in timezones), convert (a time from timezone into timezones), business_days (add days to date, or count business days from date to end_date, skipping weekends and holidays), cron (explain expression and list its next runs in timezone).`),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("now", "convert", "business_days", "cron"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone, e.g. Australia/Melbourne: the zone of 'time' for convert, and the zone for business_days and cron (default: UTC)"),
+		),
+		mcp.WithArray("timezones",
+			mcp.Description("IANA time zones to show the time in for now and convert (default: UTC)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("time",
+			mcp.Description("Time to convert, e.g. 2026-03-01T09:30:00Z or '2026-03-01 09:30' in timezone (default: now)"),
+		),
+		mcp.WithString("date",
+			mcp.Description("Start date for business_days, YYYY-MM-DD (default: today)"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Business days to add to date; negative goes backwards"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("End date to count business days to, YYYY-MM-DD"),
+		),
+		mcp.WithArray("holidays",
+			mcp.Description("Dates to skip as non-working days, YYYY-MM-DD"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("expression",
+			mcp.Description("Cron expression for cron, e.g. '30 9 * * 1-5' or '@daily'"),
+		),
+		mcp.WithNumber("count",
+			mcp.Description(fmt.Sprintf("Upcoming cron runs to list (default: %d, max: %d)", defaultCronRuns, maxCronRuns)),
+		),
+		// Read-only annotations for time calculations
+		mcp.WithReadOnlyHintAnnotation(true),     // Doesn't modify environment
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(false),  // The current time changes between calls
+		mcp.WithOpenWorldHintAnnotation(false),   // Uses the embedded time zone database, no external interactions
+	)
+}
+
+// Execute runs the requested time operation
+func (t *TimeTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action, _ := args["action"].(string)
+	logger.WithField("action", action).Debug("Executing time tool")
+
+	loc, err := parseLocation(args["timezone"])
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	var result any
+	switch action {
+	case "now":
+		result, err = t.now(now, args)
+	case "convert":
+		result, err = t.convert(now, loc, args)
+	case "business_days":
+		result, err = t.businessDays(now, loc, args)
+	case "cron":
+		result, err = t.cron(now, loc, args)
+	default:
+		return nil, fmt.Errorf("invalid or missing 'action' parameter: must be 'now', 'convert', 'business_days' or 'cron'")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// now returns the current time in each requested time zone
+func (t *TimeTool) now(now time.Time, args map[string]any) (*TimesResponse, error) {
+	locations, err := parseLocations(args["timezones"])
+	if err != nil {
+		return nil, err
+	}
+	response := &TimesResponse{}
+	for _, loc := range locations {
+		response.Times = append(response.Times, zonedTime(now.In(loc)))
+	}
+	return response, nil
+}
+
+// convert returns a time, read in the source time zone unless it has an offset, in each requested time
+// zone
+func (t *TimeTool) convert(now time.Time, loc *time.Location, args map[string]any) (*TimesResponse, error) {
+	source := now.In(loc)
+	if text, ok := args["time"].(string); ok && strings.TrimSpace(text) != "" && !strings.EqualFold(strings.TrimSpace(text), "now") {
+		parsed, err := parseTime(strings.TrimSpace(text), loc)
+		if err != nil {
+			return nil, err
+		}
+		source = parsed
+	}
+
+	locations, err := parseLocations(args["timezones"])
+	if err != nil {
+		return nil, err
+	}
+	sourceTime := zonedTime(source)
+	response := &TimesResponse{Source: &sourceTime}
+	for _, target := range locations {
+		response.Times = append(response.Times, zonedTime(source.In(target)))
+	}
+	return response, nil
+}
+
+// businessDays adds business days to a date, or counts the business days between two dates
+func (t *TimeTool) businessDays(now time.Time, loc *time.Location, args map[string]any) (*BusinessDaysResponse, error) {
+	start := civilDate(now.In(loc))
+	if text, ok := args["date"].(string); ok && strings.TrimSpace(text) != "" {
+		parsed, err := time.Parse(time.DateOnly, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'date' parameter: use YYYY-MM-DD, e.g. 2026-03-02")
+		}
+		start = parsed
+	}
+
+	holidays, err := parseHolidays(args["holidays"])
+	if err != nil {
+		return nil, err
+	}
+	isBusinessDay := func(day time.Time) bool {
+		weekday := day.Weekday()
+		return weekday != time.Saturday && weekday != time.Sunday && !holidays[day.Format(time.DateOnly)]
+	}
+
+	response := &BusinessDaysResponse{Start: start.Format(time.DateOnly), StartWeekday: start.Weekday().String()}
+
+	endText, hasEnd := args["end_date"].(string)
+	daysValue, hasDays := args["days"].(float64)
+	switch {
+	case hasEnd && strings.TrimSpace(endText) != "":
+		end, err := time.Parse(time.DateOnly, strings.TrimSpace(endText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'end_date' parameter: use YYYY-MM-DD, e.g. 2026-03-31")
+		}
+		span := int(end.Sub(start).Hours() / 24)
+		if span > maxBusinessDays*2 || span < -maxBusinessDays*2 {
+			return nil, fmt.Errorf("dates are too far apart: at most %d calendar days", maxBusinessDays*2)
+		}
+
+		// Both dates are included, as with spreadsheet NETWORKDAYS, and the count is negative if
+		// end_date is before date
+		first, last, sign := start, end, 1
+		if end.Before(start) {
+			first, last, sign = end, start, -1
+		}
+		count := 0
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			if isBusinessDay(day) {
+				count++
+			}
+		}
+		response.End = end.Format(time.DateOnly)
+		response.EndWeekday = end.Weekday().String()
+		response.BusinessDays = sign * count
+		response.CalendarDays = span
+
+	case hasDays:
+		if daysValue != float64(int(daysValue)) || daysValue > maxBusinessDays || daysValue < -maxBusinessDays {
+			return nil, fmt.Errorf("invalid 'days' parameter: must be a whole number from -%d to %d", maxBusinessDays, maxBusinessDays)
+		}
+		days := int(daysValue)
+		step := 1
+		if days < 0 {
+			step = -1
+		}
+
+		// The start date is not counted, so 1 business day after a Friday is the following Monday
+		day := start
+		for remaining := days * step; remaining > 0; {
+			day = day.AddDate(0, 0, step)
+			if isBusinessDay(day) {
+				remaining--
+			}
+		}
+		response.End = day.Format(time.DateOnly)
+		response.EndWeekday = day.Weekday().String()
+		response.BusinessDays = days
+		response.CalendarDays = int(day.Sub(start).Hours() / 24)
+
+	default:
+		return nil, fmt.Errorf("business_days needs either 'days' to add to the date, or 'end_date' to count business days to")
+	}
+
+	return response, nil
+}
+
+// cron explains a cron expression and lists its next runs
+func (t *TimeTool) cron(now time.Time, loc *time.Location, args map[string]any) (*CronResponse, error) {
+	expression, _ := args["expression"].(string)
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("missing required parameter 'expression' for cron, e.g. '30 9 * * 1-5'")
+	}
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	count := defaultCronRuns
+	if value, ok := args["count"].(float64); ok {
+		if value < 1 || value > maxCronRuns || value != float64(int(value)) {
+			return nil, fmt.Errorf("invalid 'count' parameter: must be a whole number from 1 to %d", maxCronRuns)
+		}
+		count = int(value)
+	}
+
+	response := &CronResponse{
+		Expression:  strings.TrimSpace(expression),
+		Fields:      schedule.Fields(),
+		Explanation: schedule.Explain(),
+		Timezone:    loc.String(),
+		NextRuns:    []string{},
+	}
+	next := now.In(loc)
+	for range count {
+		var ok bool
+		if next, ok = schedule.Next(next); !ok {
+			break
+		}
+		response.NextRuns = append(response.NextRuns, next.Format("2006-01-02T15:04:05Z07:00 Mon"))
+	}
+	if len(response.NextRuns) == 0 {
+		response.Explanation += ". This schedule does not run in the next five years"
+	}
+	return response, nil
+}
+
+// parseLocation loads an IANA time zone, defaulting to UTC
+func parseLocation(value any) (*time.Location, error) {
+	name, _ := value.(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	if strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone '%s': use an IANA name such as Europe/London, America/New_York or UTC", name)
+	}
+	return loc, nil
+}
+
+// parseLocations loads a list of IANA time zones, defaulting to UTC
+func parseLocations(value any) ([]*time.Location, error) {
+	items, ok := value.([]any)
+	if !ok || len(items) == 0 {
+		if value != nil && !ok {
+			return nil, fmt.Errorf("invalid 'timezones' parameter: must be an array of IANA time zone names")
+		}
+		return []*time.Location{time.UTC}, nil
+	}
+	if len(items) > maxTimezones {
+		return nil, fmt.Errorf("too many time zones: at most %d", maxTimezones)
+	}
+
+	locations := make([]*time.Location, 0, len(items))
+	for _, item := range items {
+		loc, err := parseLocation(item)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+// parseTime parses a time with an offset, or a local time in loc
+func parseTime(text string, loc *time.Location) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, text); err == nil {
+		return parsed, nil
+	}
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid 'time' parameter '%s': use RFC 3339 (2026-03-01T09:30:00+11:00) or 'YYYY-MM-DD HH:MM' in timezone", text)
+}
+
+// parseHolidays parses holiday dates into a set
+func parseHolidays(value any) (map[string]bool, error) {
+	holidays := map[string]bool{}
+	if value == nil {
+		return holidays, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'holidays' parameter: must be an array of YYYY-MM-DD dates")
+	}
+	for _, item := range items {
+		text, _ := item.(string)
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday '%v': use YYYY-MM-DD", item)
+		}
+		holidays[date.Format(time.DateOnly)] = true
+	}
+	return holidays, nil
+}
+
+// civilDate returns the calendar date of a time, as midnight UTC
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// zonedTime describes a time in its time zone
+func zonedTime(t time.Time) ZonedTime {
+	abbreviation, _ := t.Zone()
+	return ZonedTime{
+		Timezone:     t.Location().String(),
+		Time:         t.Format(time.RFC3339),
+		Display:      t.Format("Mon 2 Jan 2006 15:04"),
+		Abbreviation: abbreviation,
+		UTCOffset:    t.Format("-07:00"),
+		DST:          t.IsDST(),
+	}
+}
+
+// ProvideExtendedInfo provides detailed usage information for the time tool
+func (t *TimeTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Current time for a distributed team",
+				Arguments: map[string]any{
+					"action":    "now",
+					"timezones": []string{"Australia/Melbourne", "Europe/London", "America/New_York"},
+				},
+				ExpectedResult: "The current time, abbreviation, UTC offset and whether daylight saving applies in each zone.",
+			},
+			{
+				Description: "When a maintenance window in Sydney falls for other regions",
+				Arguments: map[string]any{
+					"action":    "convert",
+					"time":      "2026-03-14 22:00",
+					"timezone":  "Australia/Sydney",
+					"timezones": []string{"UTC", "America/Los_Angeles"},
+				},
+				ExpectedResult: "The source time and the same moment in UTC and Los Angeles.",
+			},
+			{
+				Description: "Date ten business days after a change freeze, skipping a public holiday",
+				Arguments: map[string]any{
+					"action":   "business_days",
+					"date":     "2026-12-18",
+					"days":     10,
+					"holidays": []string{"2026-12-25", "2026-12-28", "2027-01-01"},
+				},
+				ExpectedResult: "The end date and its weekday, and the calendar days it spans.",
+			},
+			{
+				Description: "Check when a scheduled job runs",
+				Arguments: map[string]any{
+					"action":     "cron",
+					"expression": "30 9 * * 1-5",
+					"timezone":   "Europe/Berlin",
+				},
+				ExpectedResult: `"At 09:30 on Monday through Friday", with the next 5 run times in Berlin time.`,
+			},
+		},
+		CommonPatterns: []string{
+			"Convert a proposed meeting or deployment time into every affected team's zone",
+			"Count business days between dates for SLA and estimate discussions",
+			"Check a cron expression before committing it, including which time zone it runs in",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Unknown time zone error",
+				Solution: "Use IANA names such as Europe/London or America/New_York, not abbreviations like EST or AEDT, which are ambiguous.",
+			},
+			{
+				Problem:  "Cron expression rejected with the wrong number of fields",
+				Solution: "Only standard five-field cron expressions are supported. Remove the seconds (and year) fields used by Quartz or Spring schedules.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"time":     "A time with an offset (RFC 3339) is converted as is; without an offset it is read in 'timezone'.",
+			"holidays": "Public holidays vary by country and region, so pass the ones that apply. Weekends are always Saturday and Sunday.",
+			"end_date": "Both date and end_date are counted if they are business days, as with spreadsheet NETWORKDAYS.",
+		},
+		WhenToUse:    "Scheduling across time zones, runbook times, business-day deadlines, and checking cron schedules.",
+		WhenNotToUse: "Date arithmetic with calendar days or durations - use the calc tool, which handles those alongside other units.",
+	}
+}
@@ -0,0 +1,36 @@
+package timetool
+
+// ZonedTime is a time in one time zone
+type ZonedTime struct {
+	Timezone     string `json:"timezone"`
+	Time         string `json:"time"`
+	Display      string `json:"display"`
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+	DST          bool   `json:"dst"`
+}
+
+// TimesResponse is the result of the now and convert actions
+type TimesResponse struct {
+	Source *ZonedTime  `json:"source,omitempty"`
+	Times  []ZonedTime `json:"times"`
+}
+
+// BusinessDaysResponse is the result of the business_days action
+type BusinessDaysResponse struct {
+	Start        string `json:"start"`
+	StartWeekday string `json:"start_weekday"`
+	End          string `json:"end"`
+	EndWeekday   string `json:"end_weekday"`
+	BusinessDays int    `json:"business_days"`
+	CalendarDays int    `json:"calendar_days"`
+}
+
+// CronResponse is the result of the cron action
+type CronResponse struct {
+	Expression  string   `json:"expression"`
+	Fields      string   `json:"fields"`
+	Explanation string   `json:"explanation"`
+	Timezone    string   `json:"timezone"`
+	NextRuns    []string `json:"next_runs"`
+}
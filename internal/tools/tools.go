@@ -45,3 +45,39 @@ type TroubleshootingTip struct {
 	Problem  string `json:"problem"`
 	Solution string `json:"solution"`
 }
+
+// PrerequisiteChecker is an optional interface that tools can implement to report whether the
+// programs, credentials and services they depend on are available, for the doctor command
+type PrerequisiteChecker interface {
+	CheckPrerequisites(ctx context.Context) []PrerequisiteCheck
+}
+
+// MemoryIntensive is an optional interface for tools whose calls can need far more memory than
+// others, such as converting documents. The server refuses the calls it reports while it is close
+// to its memory limit, rather than letting the garbage collector thrash.
+type MemoryIntensive interface {
+	IsMemoryIntensive(args map[string]any) bool
+}
+
+// HeavyWorkload is an optional interface for tools whose calls can keep the CPU busy for a long
+// time, such as converting documents. Calls it names a work queue for wait for one of that queue's
+// workers, so concurrent agents share the server rather than overload it. Calls it returns "" for
+// run at once.
+type HeavyWorkload interface {
+	WorkQueue(args map[string]any) string
+}
+
+// Prerequisite check statuses
+const (
+	PrerequisiteOK      = "ok"
+	PrerequisiteWarning = "warning" // The tool works with reduced functionality
+	PrerequisiteFailed  = "failed"  // The tool will not work
+)
+
+// PrerequisiteCheck is the result of checking one prerequisite of a tool
+type PrerequisiteCheck struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Detail      string `json:"detail"`
+	Remediation string `json:"remediation,omitempty"` // What to do when the status is not ok
+}
@@ -0,0 +1,294 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// formats are the data formats the tool reads and writes
+var formats = []string{"json", "yaml", "toml"}
+
+// formatFromExtension returns the format a file's extension suggests, or "" if it suggests none
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	}
+	return ""
+}
+
+// detectFormat guesses the format of data that has no file extension to go by. Data starting with {
+// or [ is tried as JSON, then anything that parses as TOML is TOML, and everything else is YAML.
+func detectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "json"
+	}
+	var table map[string]any
+	if len(trimmed) > 0 {
+		if _, err := toml.Decode(string(data), &table); err == nil {
+			return "toml"
+		}
+	}
+	return "yaml"
+}
+
+// decode parses data in a format into values a query can run on. JSON and YAML can hold several
+// documents, each of which is a separate value.
+func decode(data []byte, format string) ([]any, error) {
+	var values []any
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		for {
+			var value any
+			if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid JSON: %w", err)
+			}
+			values = append(values, value)
+		}
+	case "yaml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var value any
+			if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid YAML: %w", err)
+			}
+			values = append(values, value)
+		}
+	case "toml":
+		var table map[string]any
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, fmt.Errorf("invalid TOML: %w", err)
+		}
+		values = append(values, table)
+	default:
+		return nil, fmt.Errorf("unsupported format '%s': must be one of %v", format, formats)
+	}
+
+	for i, value := range values {
+		values[i] = normalise(value)
+	}
+	return values, nil
+}
+
+// normalise converts decoded values to the types queries work with: maps with string keys, slices,
+// strings, booleans, nil and numbers as int, float64 or *big.Int. Dates and times become strings.
+func normalise(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalise(item)
+		}
+		return v
+	case map[any]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[fmt.Sprint(key)] = normalise(item)
+		}
+		return object
+	case []any:
+		for i, item := range v {
+			v[i] = normalise(item)
+		}
+		return v
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = normalise(item)
+		}
+		return items
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return normaliseInt(n)
+		}
+		if n, ok := new(big.Int).SetString(v.String(), 10); ok {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case int64:
+		return normaliseInt(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return normaliseInt(int64(v))
+		}
+		return new(big.Int).SetUint64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case nil, bool, int, float64, string, *big.Int:
+		return v
+	case fmt.Stringer:
+		// TOML local dates and times
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// normaliseInt returns an int64 as an int, or as a *big.Int where int is too small to hold it
+func normaliseInt(n int64) any {
+	if n >= math.MinInt && n <= math.MaxInt {
+		return int(n)
+	}
+	return big.NewInt(n)
+}
+
+// encode writes query results in a format. Raw strings are written as they are, without quotes.
+func encode(results []any, format string, raw bool) (string, error) {
+	var out strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			if format == "yaml" {
+				out.WriteString("---\n")
+			} else if format == "toml" {
+				return "", fmt.Errorf("the query produced %d results, but TOML can only hold one table. Wrap the query in [...] and convert to JSON or YAML, or select a single table", len(results))
+			}
+		}
+
+		if text, ok := result.(string); ok && raw {
+			out.WriteString(text)
+			out.WriteString("\n")
+			continue
+		}
+
+		var err error
+		switch format {
+		case "json":
+			err = encodeJSON(&out, result)
+		case "yaml":
+			err = encodeYAML(&out, result)
+		case "toml":
+			err = encodeTOML(&out, result)
+		default:
+			err = fmt.Errorf("unsupported format '%s': must be one of %v", format, formats)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return out.String(), nil
+}
+
+// encodeJSON writes a value as indented JSON
+func encodeJSON(out *strings.Builder, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// encodeYAML writes a value as YAML
+func encodeYAML(out *strings.Builder, value any) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlValue(value)); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+// yamlValue replaces big integers with YAML integer nodes, which would otherwise be written as strings
+func yamlValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = yamlValue(item)
+		}
+		return object
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = yamlValue(item)
+		}
+		return items
+	case *big.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}
+	default:
+		return v
+	}
+}
+
+// encodeTOML writes a value as TOML, which must be a table
+func encodeTOML(out *strings.Builder, value any) error {
+	table, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("TOML can only hold a table, but the result is %s. Convert to JSON or YAML instead", describeType(value))
+	}
+	if err := checkTOML(table, ""); err != nil {
+		return err
+	}
+	encoder := toml.NewEncoder(out)
+	encoder.Indent = ""
+	if err := encoder.Encode(table); err != nil {
+		return fmt.Errorf("failed to write TOML: %w", err)
+	}
+	return nil
+}
+
+// checkTOML reports values TOML cannot hold, naming where they are
+func checkTOML(value any, path string) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("TOML has no null, but %s is null. Remove it with del(%s) or convert to JSON or YAML", path, path)
+	case *big.Int:
+		return fmt.Errorf("%s is %s, which is too large for a TOML integer", path, v)
+	case map[string]any:
+		for key, item := range v {
+			if err := checkTOML(item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := checkTOML(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describeType names the type of a query result, e.g. "an array"
+func describeType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	default:
+		return "a number"
+	}
+}
@@ -0,0 +1,305 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxInputSize is the largest file or inline input that can be loaded
+	maxInputSize = 10 * 1024 * 1024
+
+	// maxOutputSize is the most output a query can produce
+	maxOutputSize = 10 * 1024 * 1024
+
+	// maxResults is the most results a query can produce
+	maxResults = 10_000
+
+	// maxQueryLength is the longest query accepted
+	maxQueryLength = 10_000
+
+	// queryTimeout stops queries that would run forever, such as repeat(.)
+	queryTimeout = 10 * time.Second
+)
+
+// TransformTool queries and converts JSON, YAML and TOML with jq expressions
+type TransformTool struct{}
+
+// init registers the transform tool
+func init() {
+	registry.Register(&TransformTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *TransformTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"transform",
+		mcp.WithDescription(`Queries and converts JSON, YAML and TOML with jq expressions, without reading the whole document into the conversation. Loads a file or inline input, runs a jq query (default: .) and writes the results as JSON, YAML or TOML.
+
+Examples: query '.dependencies | keys' on package.json; query '.services[].image' on docker-compose.yml; convert a TOML file to YAML with output_format.`),
+		mcp.WithString("file",
+			mcp.Description("Path of a JSON, YAML or TOML file to load. Relative paths resolve against the workspace root"),
+		),
+		mcp.WithString("input",
+			mcp.Description("Inline JSON, YAML or TOML to load instead of a file"),
+		),
+		mcp.WithString("input_format",
+			mcp.Description("Format of the input (default: from the file extension, or detected)"),
+			mcp.Enum(formats...),
+		),
+		mcp.WithString("query",
+			mcp.Description("jq query to run, e.g. '.items[] | select(.enabled) | .name' (default: .)"),
+		),
+		mcp.WithString("output_format",
+			mcp.Description("Format to write the results in (default: json)"),
+			mcp.Enum(formats...),
+		),
+		mcp.WithBoolean("raw",
+			mcp.Description("Write string results without quotes, like jq -r (default: false)"),
+		),
+		// Read-only annotations for querying data
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads files, never writes them
+		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithIdempotentHintAnnotation(true),   // The same input and query give the same result
+		mcp.WithOpenWorldHintAnnotation(false),   // Works on local files and input, no external interactions
+	)
+}
+
+// Execute loads the input, runs the query and writes the results
+func (t *TransformTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	query := "."
+	if queryRaw, ok := args["query"]; ok {
+		text, ok := queryRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'query' parameter: must be a string, e.g. \".items[].name\"")
+		}
+		if text != "" {
+			query = text
+		}
+	}
+	if len(query) > maxQueryLength {
+		return nil, fmt.Errorf("query exceeds maximum length of %d characters", maxQueryLength)
+	}
+
+	inputFormat, err := formatParam(args, "input_format")
+	if err != nil {
+		return nil, err
+	}
+	outputFormat, err := formatParam(args, "output_format")
+	if err != nil {
+		return nil, err
+	}
+	if outputFormat == "" {
+		outputFormat = "json"
+	}
+	raw, _ := args["raw"].(bool)
+
+	data, source, warning, err := loadInput(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if inputFormat == "" {
+		inputFormat = formatFromExtension(source)
+	}
+	if inputFormat == "" {
+		inputFormat = detectFormat(data)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"source":        source,
+		"input_format":  inputFormat,
+		"output_format": outputFormat,
+	}).Debug("Executing transform")
+
+	values, err := decode(data, inputFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", source, err)
+	}
+
+	results, err := runQuery(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := encode(results, outputFormat, raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) > maxOutputSize {
+		return nil, fmt.Errorf("output is %d bytes, more than the maximum of %d. Narrow the query to select less", len(output), maxOutputSize)
+	}
+	if warning != nil && warning.Action == security.ActionWarn {
+		output = security.FormatSecurityWarningPrefix(warning) + output
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// formatParam reads an optional format parameter
+func formatParam(args map[string]any, name string) (string, error) {
+	raw, ok := args[name]
+	if !ok {
+		return "", nil
+	}
+	format, ok := raw.(string)
+	if !ok || !slices.Contains(formats, format) {
+		return "", fmt.Errorf("invalid '%s' parameter: must be one of %v", name, formats)
+	}
+	return format, nil
+}
+
+// loadInput reads the file or inline input, returning it with a description of where it came from
+// and any security warning about its content
+func loadInput(ctx context.Context, args map[string]any) ([]byte, string, *security.SecurityResult, error) {
+	file, hasFile := args["file"].(string)
+	input, hasInput := args["input"].(string)
+	switch {
+	case hasFile && hasInput:
+		return nil, "", nil, fmt.Errorf("provide either 'file' or 'input', not both")
+	case hasInput:
+		if len(input) > maxInputSize {
+			return nil, "", nil, fmt.Errorf("input is %d bytes, more than the maximum of %d", len(input), maxInputSize)
+		}
+		return []byte(input), "input", nil, nil
+	case !hasFile || file == "":
+		return nil, "", nil, fmt.Errorf("missing required parameter. Provide either 'file' (e.g., {\"file\": \"/path/to/config.yaml\"}) or 'input' (e.g., {\"input\": \"{\\\"a\\\": 1}\"})")
+	}
+
+	path, err := workspace.ResolveToolPath(ctx, "file", file)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, "", nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > maxInputSize {
+		return nil, "", nil, fmt.Errorf("%s is %d bytes, more than the maximum of %d", path, info.Size(), maxInputSize)
+	}
+
+	safeFile, err := security.NewOperations("transform").SafeFileRead(path)
+	if err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return nil, "", nil, security.FormatSecurityBlockError(secErr)
+		}
+		return nil, "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return safeFile.Content, path, safeFile.SecurityResult, nil
+}
+
+// runQuery runs a jq query on each value in turn and collects the results
+func runQuery(ctx context.Context, query string, values []any) ([]any, error) {
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query '%s': %w", query, err)
+	}
+	// No environment loader is given, so $ENV and env are empty rather than exposing the server's environment
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query '%s': %w", query, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	var results []any
+	for _, value := range values {
+		iter := code.RunWithContext(ctx, value)
+		for {
+			result, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := result.(error); ok {
+				var haltErr *gojq.HaltError
+				if errors.As(err, &haltErr) && haltErr.Value() == nil {
+					return results, nil
+				}
+				if errors.Is(err, context.DeadlineExceeded) {
+					return nil, fmt.Errorf("query did not finish within %s", queryTimeout)
+				}
+				return nil, fmt.Errorf("query failed: %w", err)
+			}
+			if len(results) == maxResults {
+				return nil, fmt.Errorf("query produced more than %d results. Narrow the query, or use limit(n; ...) or first(...)", maxResults)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the transform tool
+func (t *TransformTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Reading parts of large JSON, YAML or TOML files, such as package manifests, API responses, Kubernetes manifests, docker-compose and CI configs, and converting between the three formats. Much cheaper than reading the whole file into the conversation.",
+		WhenNotToUse: "Editing files in place (the tool never writes files - write its output with a file editing tool), or formats other than JSON, YAML and TOML.",
+		CommonPatterns: []string{
+			"List keys: query 'keys' or '.dependencies | keys'",
+			"Pick fields from every item: '.items[] | {name, version}'",
+			"Filter: '.services | to_entries[] | select(.value.ports) | .key'",
+			"Count: '.items | length'",
+			"Convert formats: leave query empty and set output_format",
+			"Plain strings for further use: set raw to true",
+		},
+		ParameterDetails: map[string]string{
+			"file":          fmt.Sprintf("Path of the file to load, up to %d MiB. The format comes from the extension (.json, .yaml, .yml, .toml) unless input_format is set.", maxInputSize/1024/1024),
+			"input":         "Inline data to load instead of a file.",
+			"input_format":  "Overrides the detected format. Without an extension, data starting with { or [ is read as JSON, data that parses as TOML as TOML, and anything else as YAML.",
+			"query":         "A jq query, run with gojq. Multi-document YAML and JSON streams run the query on each document. $ENV, input and inputs are not available.",
+			"output_format": "json (default), yaml or toml. Several results become a JSON stream or multi-document YAML. TOML can only hold a single table without nulls.",
+			"raw":           "Writes string results without quotes, one per line.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "List a project's dependencies",
+				Arguments:      map[string]any{"file": "/path/to/package.json", "query": ".dependencies | keys"},
+				ExpectedResult: `["express", "react"]`,
+			},
+			{
+				Description:    "Images used by a docker-compose file",
+				Arguments:      map[string]any{"file": "/path/to/docker-compose.yml", "query": ".services[].image", "raw": true},
+				ExpectedResult: "One image name per line",
+			},
+			{
+				Description:    "Convert a TOML config to YAML",
+				Arguments:      map[string]any{"file": "/path/to/config.toml", "output_format": "yaml"},
+				ExpectedResult: "The same data as YAML",
+			},
+			{
+				Description:    "Query inline JSON",
+				Arguments:      map[string]any{"input": `{"users": [{"name": "a", "admin": true}, {"name": "b"}]}`, "query": "[.users[] | select(.admin) | .name]"},
+				ExpectedResult: `["a"]`,
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "'cannot iterate over' or 'expected an object' errors",
+				Solution: "The query expected a different shape. Run 'type' or 'keys' first to see the structure at that point.",
+			},
+			{
+				Problem:  "Converting to TOML fails",
+				Solution: "TOML needs a table at the top level and has no null. Wrap arrays in an object, e.g. '{items: .}', or remove nulls with 'del(..|nulls)'.",
+			},
+			{
+				Problem:  "YAML dates or times come back as strings",
+				Solution: "Dates and times are read as strings so they can be compared and converted to JSON. Use jq's date functions, e.g. 'fromdateiso8601', to work with them.",
+			},
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package usagetool
+
+// Response is the result of the usage tool
+type Response struct {
+	Mode      string         `json:"mode"`
+	ResetsAt  string         `json:"resets_at"`
+	Providers []ProviderInfo `json:"providers"`
+	Notes     []string       `json:"notes,omitempty"`
+}
+
+// ProviderInfo describes a provider's usage today and on earlier days
+type ProviderInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Today       int        `json:"today"`
+	Limit       int        `json:"limit,omitempty"`
+	Remaining   *int       `json:"remaining,omitempty"`
+	Status      string     `json:"status"`
+	History     []DayCount `json:"history,omitempty"`
+}
+
+// DayCount is the number of calls made to a provider on a day
+type DayCount struct {
+	Day   string `json:"day"`
+	Calls int    `json:"calls"`
+}
@@ -0,0 +1,160 @@
+package usagetool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/usage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDays = 7
+	maxDays     = 30
+
+	// Provider statuses
+	statusOK        = "ok"
+	statusWarning   = "nearly used up"
+	statusExhausted = "used up"
+	statusUncapped  = "no limit"
+)
+
+// UsageTool reports the calls made to metered external APIs and the budget left today
+type UsageTool struct{}
+
+// init registers the usage tool
+func init() {
+	registry.Register(&UsageTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *UsageTool) Definition() mcp.Tool {
+	names := make([]string, 0, len(usage.Providers))
+	for _, provider := range usage.Providers {
+		names = append(names, provider.Name)
+	}
+	return mcp.NewTool(
+		"usage",
+		mcp.WithDescription(`Report how many calls this server made today to external APIs with a usage quota (`+strings.Join(names, ", ")+`), the daily limit the user set for each and how many calls remain. Use before a large batch of searches or API lookups, or when a tool fails because a usage limit is reached.`),
+		mcp.WithString("provider",
+			mcp.Description("Only report this provider"),
+			mcp.Enum(names...),
+		),
+		mcp.WithNumber("days",
+			mcp.Description(fmt.Sprintf("Days of history to include, today first (default: %d, max: %d)", defaultDays, maxDays)),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the usage counts
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // Counts change as calls are made
+		mcp.WithOpenWorldHintAnnotation(false),   // Local counts only
+	)
+}
+
+// Execute reports the usage of each provider
+func (t *UsageTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	days := defaultDays
+	if value, ok := args["days"].(float64); ok {
+		if value < 1 || value != float64(int(value)) {
+			return nil, fmt.Errorf("days must be a whole number of at least 1, got: %v", value)
+		}
+		days = min(int(value), maxDays)
+	}
+	filter, _ := args["provider"].(string)
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter != "" && !slices.ContainsFunc(usage.Providers, func(p usage.Provider) bool { return p.Name == filter }) {
+		return nil, fmt.Errorf("unknown provider: %s", filter)
+	}
+
+	counts, err := usage.Counts(days)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	response := Response{Mode: "block", ResetsAt: usage.ResetTime(now).Format(time.RFC3339), Providers: []ProviderInfo{}}
+	if !usage.Blocking() {
+		response.Mode = "warn"
+	}
+
+	capped := false
+	for _, provider := range usage.Providers {
+		if filter != "" && provider.Name != filter {
+			continue
+		}
+		info := ProviderInfo{Name: provider.Name, Description: provider.Description, Status: statusUncapped}
+		for i := range days {
+			day := now.AddDate(0, 0, -i).Format("2006-01-02")
+			info.History = append(info.History, DayCount{Day: day, Calls: counts[day][provider.Name]})
+		}
+		info.Today = info.History[0].Calls
+		if days == 1 {
+			info.History = nil
+		}
+		if limit := usage.Limit(provider.Name); limit > 0 {
+			capped = true
+			remaining := max(0, limit-info.Today)
+			info.Limit, info.Remaining = limit, &remaining
+			switch {
+			case remaining == 0:
+				info.Status = statusExhausted
+			case info.Today*100 >= limit*usage.WarnPercent():
+				info.Status = statusWarning
+			default:
+				info.Status = statusOK
+			}
+		}
+		response.Providers = append(response.Providers, info)
+	}
+
+	if !capped {
+		response.Notes = append(response.Notes, fmt.Sprintf("No usage limits are set, calls are only counted. The user can set a daily limit with %s<PROVIDER>, e.g. %sBRAVE=1000", usage.LimitEnvVarPrefix, usage.LimitEnvVarPrefix))
+	}
+	if response.Mode == "warn" {
+		response.Notes = append(response.Notes, "Calls over a limit are still made and only logged, as USAGE_LIMIT_MODE is warn")
+	}
+	response.Notes = append(response.Notes, "Days start at midnight UTC. Cached responses are not counted.")
+
+	logger.WithField("providers", len(response.Providers)).Debug("Reported API usage")
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the usage tool
+func (t *UsageTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "Before many internet searches or GitHub lookups on a shared server, or when a tool fails with 'daily usage limit reached', to see what budget is left and when it resets.",
+		WhenNotToUse: "To check a provider's own account quota - counts only cover calls made by this server and the user's other mcp-devtools servers.",
+		CommonPatterns: []string{
+			"Check remaining before a batch of searches and spread the work out if the budget is low",
+			"After a limit is reached, tell the user when it resets instead of retrying",
+		},
+		ParameterDetails: map[string]string{
+			"provider": "brave covers Brave web, image, news, video and local search. github covers the GitHub tool and release lookups. osv covers vulnerability checks during package version cooldowns. gemini counts gemini-agent runs.",
+			"days":     "History is kept for 30 days.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Budget left for Brave Search today",
+				Arguments:      map[string]any{"provider": "brave", "days": 1},
+				ExpectedResult: "Today's count, the daily limit, the calls remaining and when the count resets",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A tool fails with 'daily usage limit reached'",
+				Solution: "The user's USAGE_LIMIT_<PROVIDER> cap is used up. Wait for the reset at midnight UTC, or ask the user to raise the limit or set USAGE_LIMIT_MODE=warn.",
+			},
+		},
+	}
+}
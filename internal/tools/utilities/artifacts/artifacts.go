@@ -0,0 +1,137 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// The artifacts tool's actions
+const (
+	actionList = "list"
+	actionGet  = "get"
+)
+
+// ArtifactsTool lists and reads the artifacts saved by tools during the calling session
+type ArtifactsTool struct{}
+
+// init registers the artifacts tool
+func init() {
+	registry.Register(&ArtifactsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ArtifactsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"artifacts",
+		mcp.WithDescription(`Find and read large outputs saved by tools in this session, such as summarised results, converted documents and exports.
+
+Actions:
+- list: list the session's artifacts, newest first (default)
+- get: read an artifact by the id given in its summary or by list. Large artifacts are read in parts using offset.`),
+		mcp.WithString("action",
+			mcp.Description("Action to perform (default: list)"),
+			mcp.Enum(actionList, actionGet),
+		),
+		mcp.WithString("tool",
+			mcp.Description("For list: only list artifacts saved by this tool"),
+		),
+		mcp.WithString("id",
+			mcp.Description("For get (required): the artifact id"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("For get: byte offset to start reading from (default: 0)"),
+		),
+		mcp.WithNumber("length",
+			mcp.Description(fmt.Sprintf("For get: maximum number of bytes to return (default and max: TOOL_OUTPUT_PAGE_SIZE, %d unless configured)", tools.DefaultOutputPageSize)),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only lists and reads saved artifacts
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // The list changes as tools save artifacts
+		mcp.WithOpenWorldHintAnnotation(false),   // Local files only
+	)
+}
+
+// Execute runs the requested action
+func (t *ArtifactsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionList
+	if value, ok := args["action"].(string); ok && value != "" {
+		action = value
+	}
+	switch action {
+	case actionList:
+		return listArtifacts(ctx, logger, args)
+	case actionGet:
+		return getArtifact(ctx, logger, args)
+	default:
+		return nil, fmt.Errorf("invalid action: %s. Must be one of: list, get", action)
+	}
+}
+
+// ProvideExtendedInfo provides detailed usage information for the artifacts tool
+func (t *ArtifactsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description:    "List every artifact saved in this session",
+				Arguments:      map[string]any{},
+				ExpectedResult: "The ID, tool, name, content type, size, expiry and file path of each artifact, newest first.",
+			},
+			{
+				Description: "List only the artifacts saved by the excel tool",
+				Arguments: map[string]any{
+					"tool": "excel",
+				},
+				ExpectedResult: "The excel tool's artifacts from this session.",
+			},
+			{
+				Description: "Read the start of a summarised output",
+				Arguments: map[string]any{
+					"action": "get",
+					"id":     "20261018-101500-3f9a1c0e",
+				},
+				ExpectedResult: "The first part of the artifact, ending with the offset to read the next part from if there is more.",
+			},
+			{
+				Description: "Read the next part of an artifact",
+				Arguments: map[string]any{
+					"action": "get",
+					"id":     "20261018-101500-3f9a1c0e",
+					"offset": 102400,
+				},
+				ExpectedResult: "The artifact's content from byte 102400.",
+			},
+		},
+		CommonPatterns: []string{
+			"List artifacts, then read the one needed with action get using its id",
+			"Read only the parts the task needs - the summary often says where the relevant content is",
+			"Binary artifacts return their metadata and file path, for the user or another tool to open",
+			"Give the user an artifact's path when they want to open the full output themselves",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "artifact not found in this session",
+				Solution: "Artifacts expire after ARTIFACTS_TTL_HOURS (default 7 days) and are only visible to the session that saved them. Call the original tool again.",
+			},
+			{
+				Problem:  "An artifact from an earlier session is missing",
+				Solution: "Each session only sees its own artifacts. The user can find every artifact under the listed directory until it expires.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"action": "list (default) or get. get needs id.",
+			"tool":   "A tool name such as excel or process_document.",
+			"id":     "The id from a summarised output or from list, e.g. 20261018-101500-3f9a1c0e.",
+			"offset": "Byte offset given at the end of the previous part.",
+			"length": "Bytes to return, capped at the output page size.",
+		},
+		WhenToUse:    "After a tool output was summarised or saved, to find the full output again and read it.",
+		WhenNotToUse: "To list or read files in the project - use the filesystem tool instead.",
+	}
+}
@@ -0,0 +1,94 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	savedartifacts "github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// getArtifact returns part of an artifact's content, or its metadata if it is not text
+func getArtifact(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+	id, _ := args["id"].(string)
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("missing required parameter: id")
+	}
+
+	artifact, err := savedartifacts.Get(ctx, id)
+	if errors.Is(err, savedartifacts.ErrNotFound) {
+		return nil, fmt.Errorf("artifact %q not found in this session, it may have expired. Use the list action to see the available artifacts", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !artifact.IsText() {
+		// Binary content cannot be returned usefully as text, so the agent is given its location
+		jsonBytes, err := json.MarshalIndent(artifact, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal artifact: %w", err)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Artifact %s is %s and cannot be returned as text. Its file is at the path below.\n\n%s", id, artifact.ContentType, jsonBytes)), nil
+	}
+
+	// Each part stays within the page size, so reading an artifact is never paginated or summarised again
+	pageSize := int64(tools.OutputPageSize())
+	if pageSize == 0 {
+		pageSize = artifact.Size
+	}
+	offset := int64(0)
+	if value, ok := args["offset"].(float64); ok && value > 0 {
+		offset = int64(value)
+	}
+	length := pageSize
+	if value, ok := args["length"].(float64); ok && value >= 1 {
+		length = min(int64(value), pageSize)
+	}
+	if offset >= artifact.Size && artifact.Size > 0 {
+		return nil, fmt.Errorf("offset %d is beyond the end of artifact %s, which is %d bytes", offset, id, artifact.Size)
+	}
+
+	data, err := savedartifacts.Read(artifact, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	data = trimPartialRune(data, offset+int64(len(data)) < artifact.Size)
+	end := offset + int64(len(data))
+
+	logger.WithFields(logrus.Fields{
+		"artifact": id,
+		"bytes":    len(data),
+	}).Debug("Read artifact")
+
+	var text strings.Builder
+	text.Write(data)
+	if end < artifact.Size {
+		fmt.Fprintf(&text, "\n\n[Artifact %s: showing bytes %d-%d of %d. Call artifacts with action get and offset %d for the next part]", id, offset+1, end, artifact.Size, end)
+	}
+	return mcp.NewToolResultText(text.String()), nil
+}
+
+// trimPartialRune drops an incomplete UTF-8 character from the end of a part that is followed by more
+// content, so the next part starts with it whole
+func trimPartialRune(data []byte, more bool) []byte {
+	if !more {
+		return data
+	}
+	for i := len(data) - 1; i >= max(0, len(data)-utf8.UTFMax); i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) && i > 0 {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
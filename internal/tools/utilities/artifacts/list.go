@@ -0,0 +1,47 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	savedartifacts "github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sirupsen/logrus"
+)
+
+// Response is the result of the list action
+type Response struct {
+	Artifacts []savedartifacts.Artifact `json:"artifacts"`
+	Directory string                    `json:"directory,omitempty"`
+}
+
+// listArtifacts lists the calling session's artifacts, newest first
+func listArtifacts(ctx context.Context, logger *logrus.Logger, args map[string]any) (*mcp.CallToolResult, error) {
+	toolFilter, _ := args["tool"].(string)
+	toolFilter = strings.TrimSpace(toolFilter)
+
+	saved, err := savedartifacts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := Response{Artifacts: []savedartifacts.Artifact{}}
+	for _, artifact := range saved {
+		if toolFilter == "" || artifact.Tool == toolFilter {
+			response.Artifacts = append(response.Artifacts, artifact)
+		}
+	}
+	if dir, err := savedartifacts.Dir(); err == nil {
+		response.Directory = dir
+	}
+
+	logger.WithField("artifacts", len(response.Artifacts)).Debug("Listed artifacts")
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifacts: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
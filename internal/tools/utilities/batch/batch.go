@@ -0,0 +1,226 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	toolName = "batch"
+
+	maxCalls           = 50
+	defaultConcurrency = 4
+	maxConcurrency     = 10
+	// Timeouts of each call, in seconds
+	defaultTimeout = 120
+	maxTimeout     = 600
+)
+
+// BatchTool runs several read-only tool calls concurrently and returns their results together
+type BatchTool struct{}
+
+// init registers the batch tool
+func init() {
+	registry.Register(&BatchTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *BatchTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		toolName,
+		mcp.WithDescription(`Run several read-only tool calls at once and get all their results in one response, e.g. checking the versions of 20 packages or searching several sources. Calls run concurrently; each succeeds or fails on its own. Only tools marked read-only can be batched.`),
+		mcp.WithArray("calls",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf(`Tool calls to run, at most %d, e.g. [{"tool": "search_packages", "arguments": {"ecosystem": "npm", "query": "react"}}]`, maxCalls)),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tool":      map[string]any{"type": "string", "description": "Name of an enabled read-only tool"},
+					"arguments": map[string]any{"type": "object", "description": "The tool's arguments"},
+				},
+				"required": []string{"tool"},
+			}),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("Most calls to run at once (default: %d, max: %d)", defaultConcurrency, maxConcurrency)),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("Seconds each call may take (default: %d, max: %d)", defaultTimeout, maxTimeout)),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only read-only tools can be batched
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // Depends on the tools called
+		mcp.WithOpenWorldHintAnnotation(true),    // Batched tools may call external services
+	)
+}
+
+// Execute runs the calls and aggregates their results
+func (t *BatchTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	calls, err := parseCalls(args)
+	if err != nil {
+		return nil, err
+	}
+	concurrency, err := intArg(args, "concurrency", defaultConcurrency, maxConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := intArg(args, "timeout", defaultTimeout, maxTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	response := Response{Calls: len(calls), Results: make([]CallResult, len(calls))}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			callStart := time.Now()
+			output, err := run(ctx, logger, call, time.Duration(timeout)*time.Second)
+			result := CallResult{Index: i, Tool: call.Tool, OK: err == nil, DurationMs: time.Since(callStart).Milliseconds()}
+			if err != nil {
+				result.Error = err.Error()
+			} else if output != "" {
+				result.Output = encodeOutput(output)
+			}
+			response.Results[i] = result
+		})
+	}
+	wg.Wait()
+
+	for _, result := range response.Results {
+		if result.OK {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	response.DurationMs = time.Since(start).Milliseconds()
+	logger.WithFields(logrus.Fields{"calls": response.Calls, "failed": response.Failed}).Debug("Ran batch")
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseCalls reads and checks the calls of a batch
+func parseCalls(args map[string]any) ([]Call, error) {
+	raw, ok := args["calls"].([]any)
+	if !ok || len(raw) == 0 {
+		return nil, errors.New("calls is required: a list of {\"tool\": name, \"arguments\": {...}}")
+	}
+	if len(raw) > maxCalls {
+		return nil, fmt.Errorf("a batch can have at most %d calls, got %d", maxCalls, len(raw))
+	}
+	calls := make([]Call, 0, len(raw))
+	for i, item := range raw {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("call %d must be an object with tool and arguments", i)
+		}
+		name, _ := entry["tool"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("call %d has no tool", i)
+		}
+		arguments, ok := entry["arguments"].(map[string]any)
+		if entry["arguments"] != nil && !ok {
+			return nil, fmt.Errorf("arguments of call %d must be an object", i)
+		}
+		calls = append(calls, Call{Tool: name, Arguments: arguments})
+	}
+	return calls, nil
+}
+
+// intArg reads a whole number argument of at least 1, capped at max
+func intArg(args map[string]any, name string, defaultValue, maxValue int) (int, error) {
+	value, ok := args[name].(float64)
+	if !ok {
+		return defaultValue, nil
+	}
+	if value < 1 || value != float64(int(value)) {
+		return 0, fmt.Errorf("%s must be a whole number of at least 1, got: %v", name, value)
+	}
+	return min(int(value), maxValue), nil
+}
+
+// run makes one call of a batch
+func run(ctx context.Context, logger *logrus.Logger, call Call, timeout time.Duration) (string, error) {
+	if call.Tool == toolName {
+		return "", errors.New("a batch cannot call batch")
+	}
+	tool, err := toolcall.Enabled(call.Tool)
+	if err != nil {
+		return "", err
+	}
+	// Writes are left to direct calls, so the client can still ask the user to approve each one
+	if !toolcall.IsReadOnly(tool) {
+		return "", fmt.Errorf("tool %s is not read-only, call it directly", call.Tool)
+	}
+	return toolcall.Call(ctx, logger, call.Tool, tool, call.Arguments, timeout)
+}
+
+// encodeOutput embeds JSON output as it is and other output as a JSON string
+func encodeOutput(output string) json.RawMessage {
+	if trimmed := strings.TrimSpace(output); json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	encoded, _ := json.Marshal(output)
+	return encoded
+}
+
+// ProvideExtendedInfo provides detailed usage information for the batch tool
+func (t *BatchTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "When several independent read-only calls are needed and none depends on another's result, e.g. the latest versions of many packages, several URLs, or searches for several terms.",
+		WhenNotToUse: "When a call needs the result of an earlier one, or to change files or other state - call write tools directly so the user can approve them.",
+		CommonPatterns: []string{
+			"Fan out lookups of the same tool with different arguments in one batch instead of one call at a time",
+			"Mix tools, e.g. fetch a project's README and look up its dependencies' versions together",
+			"Retry only the failed calls of a batch, by index, in a new batch",
+		},
+		ParameterDetails: map[string]string{
+			"calls":       fmt.Sprintf("Up to %d calls. Results come back in the same order, each with ok, output or error. JSON output is embedded as JSON.", maxCalls),
+			"concurrency": "Calls also wait for their tool's own rate limit, so calls to a rate limited API such as internet search run no faster by raising this.",
+			"timeout":     "Applies to each call separately. A call that times out fails without affecting the others.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description: "Check package versions in several ecosystems at once",
+				Arguments: map[string]any{
+					"calls": []map[string]any{
+						{"tool": "search_packages", "arguments": map[string]any{"ecosystem": "npm", "query": "react"}},
+						{"tool": "search_packages", "arguments": map[string]any{"ecosystem": "go", "query": "golang.org/x/net"}},
+						{"tool": "search_packages", "arguments": map[string]any{"ecosystem": "docker", "query": "postgres"}},
+					},
+				},
+				ExpectedResult: "Each package's latest version, in its own result in the order given",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A call fails with 'is not read-only, call it directly'",
+				Solution: "Only tools that do not change anything can be batched. Make that call on its own.",
+			},
+			{
+				Problem:  "A call fails with 'is not enabled'",
+				Solution: "Batched tools must be enabled on the server like any other call. Check the tool's name in the tool list.",
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package batch
+
+import "encoding/json"
+
+// Call is one tool call of a batch
+type Call struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// Response is the result of a batch
+type Response struct {
+	Calls      int          `json:"calls"`
+	Succeeded  int          `json:"succeeded"`
+	Failed     int          `json:"failed"`
+	DurationMs int64        `json:"duration_ms"`
+	Results    []CallResult `json:"results"`
+}
+
+// CallResult is the outcome of one call, in the order the calls were given
+type CallResult struct {
+	Index      int    `json:"index"`
+	Tool       string `json:"tool"`
+	OK         bool   `json:"ok"`
+	DurationMs int64  `json:"duration_ms"`
+	// Output is the tool's output, embedded as JSON when it is JSON
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
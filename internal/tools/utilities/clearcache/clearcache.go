@@ -0,0 +1,102 @@
+package clearcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+// ClearCacheTool removes web pages and documentation cached by the HTTP response cache
+type ClearCacheTool struct{}
+
+// Response is the result of clear_cache
+type Response struct {
+	Removed    int    `json:"removed"`
+	FreedBytes int64  `json:"freed_bytes"`
+	Host       string `json:"host,omitempty"`
+	Directory  string `json:"directory,omitempty"`
+}
+
+// init registers the clear cache tool
+func init() {
+	registry.Register(&ClearCacheTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ClearCacheTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"clear_cache",
+		mcp.WithDescription(`Clear cached web pages and documentation, so the next fetch gets them from the server again. Use when a page has changed but the cached copy is still returned.`),
+		mcp.WithString("host",
+			mcp.Description("Only clear responses from this host, e.g. docs.example.com"),
+		),
+		// Cache management annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Removes cached responses
+		mcp.WithDestructiveHintAnnotation(false), // Cached responses are fetched again when needed
+		mcp.WithIdempotentHintAnnotation(true),   // Clearing an empty cache does nothing
+		mcp.WithOpenWorldHintAnnotation(false),   // Local files only
+	)
+}
+
+// Execute removes cached responses, optionally only those from one host
+func (t *ClearCacheTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	host, _ := args["host"].(string)
+	host = strings.TrimSpace(host)
+
+	removed, freed, err := httpclient.ClearCache(host)
+	if err != nil {
+		return nil, err
+	}
+
+	response := Response{Removed: removed, FreedBytes: freed, Host: host}
+	if dir, err := httpclient.CacheDir(); err == nil {
+		response.Directory = dir
+	}
+
+	logger.WithFields(logrus.Fields{"removed": removed, "host": host}).Debug("Cleared HTTP response cache")
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the clear cache tool
+func (t *ClearCacheTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Clear every cached response",
+				Arguments:      map[string]any{},
+				ExpectedResult: "The number of responses removed and the disk space freed.",
+			},
+			{
+				Description: "Clear only the cached pages from one documentation site",
+				Arguments: map[string]any{
+					"host": "docs.example.com",
+				},
+				ExpectedResult: "The number of responses from docs.example.com removed.",
+			},
+		},
+		CommonPatterns: []string{
+			"Clear a host's cached pages, then fetch the page again to see the latest version",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "A fetched page is out of date",
+				Solution: "Cached pages are reused while the server says they are fresh. Clear the page's host and fetch it again.",
+			},
+		},
+		WhenToUse:    "When fetch_url or a documentation tool returns an outdated copy of a page.",
+		WhenNotToUse: "Routinely before fetching - cached pages are revalidated with the server once they are no longer fresh.",
+	}
+}
@@ -0,0 +1,209 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	serverdiagnostics "github.com/sammcj/mcp-devtools/internal/diagnostics"
+	"github.com/sammcj/mcp-devtools/internal/logging"
+	"github.com/sammcj/mcp-devtools/internal/memusage"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workpool"
+	"github.com/sirupsen/logrus"
+)
+
+// Limits on the number of log lines and errors returned
+const (
+	defaultLines  = 50
+	maxLines      = 500
+	defaultErrors = 20
+	maxErrors     = 200
+)
+
+// DiagnosticsTool returns recent log entries and tool errors for the calling session
+type DiagnosticsTool struct{}
+
+// Response is the result of get_diagnostics
+type Response struct {
+	SessionID    string                    `json:"session_id,omitempty"`
+	RecentErrors []tools.ToolErrorLogEntry `json:"recent_errors"`
+	LogEntries   []serverdiagnostics.Entry `json:"log_entries"`
+	Memory       memusage.Stats            `json:"memory"`
+	WorkQueues   []workpool.Stats          `json:"work_queues,omitempty"`
+	Processes    []supervisor.Info         `json:"processes,omitempty"`
+	Notes        []string                  `json:"notes,omitempty"`
+}
+
+// init registers the diagnostics tool
+func init() {
+	registry.Register(&DiagnosticsTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *DiagnosticsTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"get_diagnostics",
+		mcp.WithDescription(`Get the recent tool errors and server log entries for this session. Use after a "tool execution failed" response to find out why, before retrying or asking the user to check the logs.`),
+		mcp.WithNumber("lines",
+			mcp.Description(fmt.Sprintf("Maximum number of recent log entries to return (default: %d, max: %d)", defaultLines, maxLines)),
+		),
+		mcp.WithNumber("errors",
+			mcp.Description(fmt.Sprintf("Maximum number of recent tool errors to return (default: %d, max: %d)", defaultErrors, maxErrors)),
+		),
+		mcp.WithString("tool",
+			mcp.Description("Only return errors and log entries from this tool"),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads recent logs held in memory
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // Results change as more calls are made
+		mcp.WithOpenWorldHintAnnotation(false),   // Local server state only
+	)
+}
+
+// Execute returns the recent errors and log entries for the calling session
+func (t *DiagnosticsTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	lines := intArg(args, "lines", defaultLines, maxLines)
+	errorLimit := intArg(args, "errors", defaultErrors, maxErrors)
+	toolFilter, _ := args["tool"].(string)
+	toolFilter = strings.TrimSpace(toolFilter)
+
+	// Each session only sees its own errors, as other sessions may belong to other users
+	sessionID := serverdiagnostics.SessionID(ctx)
+	response := Response{
+		SessionID:    sessionID,
+		RecentErrors: []tools.ToolErrorLogEntry{},
+		LogEntries:   []serverdiagnostics.Entry{},
+	}
+
+	// Filtering happens before limiting, so the limits apply to the chosen tool
+	for _, entry := range tools.GetGlobalErrorLogger().RecentErrors(sessionID, maxErrors) {
+		if toolFilter == "" || entry.ToolName == toolFilter {
+			response.RecentErrors = append(response.RecentErrors, entry)
+		}
+	}
+	response.RecentErrors = response.RecentErrors[max(0, len(response.RecentErrors)-errorLimit):]
+
+	if serverdiagnostics.Enabled() {
+		for _, entry := range serverdiagnostics.RecentEntries(sessionID, maxLines) {
+			if toolFilter == "" || entry.Tool == toolFilter {
+				response.LogEntries = append(response.LogEntries, entry)
+			}
+		}
+		response.LogEntries = response.LogEntries[max(0, len(response.LogEntries)-lines):]
+	} else {
+		response.Notes = append(response.Notes, "Log entries are not being captured by this server, only tool errors are available")
+	}
+
+	// Memory figures explain calls refused as low on memory, and slow calls while the GC thrashes
+	response.Memory = memusage.Read()
+	response.WorkQueues = workpool.Snapshot()
+	response.Processes = supervisor.Snapshot()
+	if response.Memory.Throttling {
+		response.Notes = append(response.Notes, "The server is close to its memory limit, so memory intensive calls such as process_document are refused until memory use falls")
+	}
+
+	response.Notes = append(response.Notes, notes(logger, toolFilter)...)
+
+	logger.WithFields(logrus.Fields{
+		"errors":      len(response.RecentErrors),
+		"log_entries": len(response.LogEntries),
+	}).Debug("Returned diagnostics")
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal diagnostics: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// notes explains how to get more detail than the current log level gives
+func notes(logger *logrus.Logger, tool string) []string {
+	// The level that applies is the server's, or the tool's own when overridden
+	level := logger.GetLevel()
+	if serverLogger := registry.GetLogger(); serverLogger != nil {
+		level = serverLogger.GetLevel()
+	}
+	if tool != "" {
+		if toolLevel, ok := logging.ParseLevel(os.Getenv(logging.ToolLevelEnvVar(tool))); ok {
+			level = toolLevel
+		}
+	}
+
+	var result []string
+	if level < logrus.InfoLevel {
+		// The variable is named in full, as JSON escapes angle brackets in a LOG_LEVEL_<TOOL> placeholder
+		variable := "the tool's LOG_LEVEL_ variable, e.g. LOG_LEVEL_EXCEL=info"
+		if tool != "" {
+			variable = logging.ToolLevelEnvVar(tool) + "=info"
+		}
+		result = append(result, fmt.Sprintf("Only warnings and errors are logged. For more detail, ask the user to set %s (or LOG_LEVEL=info) and restart the server", variable))
+	}
+	if !tools.GetGlobalErrorLogger().IsEnabled() {
+		result = append(result, "Errors are only kept in memory since the server started. Set LOG_TOOL_ERRORS=true to also keep them, with their arguments, in the tool error log")
+	}
+	if dir, err := logging.Dir(); err == nil {
+		result = append(result, "Full logs are in "+filepath.Join(dir, logging.LogFileName))
+	}
+	return result
+}
+
+// intArg reads a positive integer argument, falling back to def and capped at limit
+func intArg(args map[string]any, name string, def, limit int) int {
+	value, ok := args[name].(float64)
+	if !ok || value < 1 {
+		return def
+	}
+	return min(int(value), limit)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the diagnostics tool
+func (t *DiagnosticsTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Find out why the last tool call failed",
+				Arguments:   map[string]any{},
+				ExpectedResult: "Recent errors for this session with the tool name and error message, and the log entries the server and tools wrote " +
+					"during this session's calls.",
+			},
+			{
+				Description: "Show only the excel tool's errors and log entries",
+				Arguments: map[string]any{
+					"tool":  "excel",
+					"lines": 100,
+				},
+				ExpectedResult: "Up to 100 log entries and 20 errors from the excel tool in this session.",
+			},
+		},
+		CommonPatterns: []string{
+			"Call after a 'tool execution failed' response, then correct the arguments and retry",
+			"Filter by tool when several tools have been called in the session",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No log entries are returned",
+				Solution: "Only warnings and errors are logged by default. Ask the user to set LOG_LEVEL_<TOOL>=info for the tool, e.g. LOG_LEVEL_EXCEL=info, and restart the server.",
+			},
+			{
+				Problem:  "An error from another session or client is not shown",
+				Solution: "Each session only sees its own errors and log entries. The user can read every session's logs with 'mcp-devtools logs tail'.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"lines":  "Most recent log entries to return, oldest first. Only entries at info level and above that the server's log level lets through are kept.",
+			"errors": "Most recent tool errors to return, oldest first. Arguments are not included.",
+			"tool":   "Tool name as called, e.g. excel or internet_search.",
+		},
+		WhenToUse:    "After a tool call fails and the error message alone does not explain why.",
+		WhenNotToUse: "To inspect another session's calls, or logs from before the server started.",
+	}
+}
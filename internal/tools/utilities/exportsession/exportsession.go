@@ -0,0 +1,101 @@
+package exportsession
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/recording"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportSessionTool exports the calling session's recorded tool calls as a transcript
+type ExportSessionTool struct{}
+
+// init registers the export session tool
+func init() {
+	registry.Register(&ExportSessionTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *ExportSessionTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		recording.ExportToolName,
+		mcp.WithDescription(`Export a transcript of the tool calls made in this session, with their arguments and results, as Markdown or JSON. Use when the user wants a record of what was done to attach to a pull request, an incident timeline or a handover. Requires the server to record sessions.`),
+		mcp.WithString("format",
+			mcp.Description("Transcript format (default: markdown)"),
+			mcp.Enum("markdown", "json"),
+		),
+		mcp.WithBoolean("include_results",
+			mcp.Description("Include each call's result, not only its arguments (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads the session's recording
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything
+		mcp.WithIdempotentHintAnnotation(false),  // Results change as more calls are made
+		mcp.WithOpenWorldHintAnnotation(false),   // Local server state only
+	)
+}
+
+// Execute returns the transcript of the calling session
+func (t *ExportSessionTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	if !recording.Enabled() {
+		return nil, fmt.Errorf("session recording is not enabled. Ask the user to set %s=true and restart the server; calls are recorded from then on", recording.EnvVar)
+	}
+	format, _ := args["format"].(string)
+	includeResults := true
+	if value, ok := args["include_results"].(bool); ok {
+		includeResults = value
+	}
+
+	// Each session only exports its own calls, as other sessions may belong to other users
+	session := recording.SessionID(ctx)
+	calls, err := recording.Load(session)
+	if err != nil && !errors.Is(err, recording.ErrNotFound) {
+		return nil, err
+	}
+	transcript, err := recording.Export(session, calls, format, includeResults)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"session": session, "calls": len(calls)}).Debug("Exported session")
+	return mcp.NewToolResultText(transcript), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the export session tool
+func (t *ExportSessionTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		WhenToUse:    "When the user asks for a record of the tool calls made in this conversation, e.g. to attach to a pull request, a bug report or an incident timeline.",
+		WhenNotToUse: "To find out why a tool call failed - use get_diagnostics. To read a large saved output - use artifacts.",
+		CommonPatterns: []string{
+			"Export as markdown and write the transcript to a file with the filesystem tool for the user to attach",
+			"Export as json with include_results false for a compact list of the calls and their arguments",
+		},
+		ParameterDetails: map[string]string{
+			"format":          "markdown gives a summary and a section per call. json gives the recorded calls for other tools to process.",
+			"include_results": "Results are truncated to 64 KiB each when recorded. Leave them out for a shorter transcript.",
+		},
+		Examples: []tools.ToolExample{
+			{
+				Description:    "Transcript to attach to a pull request",
+				Arguments:      map[string]any{"format": "markdown"},
+				ExpectedResult: "A Markdown transcript with the session's start and end, the tools used, and each call's arguments and result",
+			},
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "Session recording is not enabled",
+				Solution: "The server must be started with SESSION_RECORDING=true. Calls made before then are not recorded.",
+			},
+			{
+				Problem:  "Secrets appear as redaction markers",
+				Solution: "Arguments and results are recorded with secrets redacted, so a transcript can be shared safely.",
+			},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package fetchmore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// FetchMoreTool returns the next part of a tool output that was too large for one response
+type FetchMoreTool struct{}
+
+// init registers the fetch more tool
+func init() {
+	registry.Register(&FetchMoreTool{})
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *FetchMoreTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"fetch_more",
+		mcp.WithDescription(`Get the next part of a tool output that was truncated for size. Only call when a tool output ends with a continuation_token and you need the rest.`),
+		mcp.WithString("continuation_token",
+			mcp.Required(),
+			mcp.Description("The continuation_token given at the end of the truncated output"),
+		),
+		// Read-only tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Reads an output held by the server
+		mcp.WithDestructiveHintAnnotation(false), // Does not change anything outside the server
+		mcp.WithIdempotentHintAnnotation(false),  // Each call returns the following part
+		mcp.WithOpenWorldHintAnnotation(false),   // Local server state only
+	)
+}
+
+// Execute returns the next part of the output for the continuation token
+func (t *FetchMoreTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	token, _ := args["continuation_token"].(string)
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("missing required parameter: continuation_token")
+	}
+
+	page, err := tools.FetchMore(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("bytes", len(page)).Debug("Returned next part of paginated output")
+	return mcp.NewToolResultText(page), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the fetch more tool
+func (t *FetchMoreTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Read the next part of a large directory tree",
+				Arguments: map[string]any{
+					"continuation_token": "3f9a1c0e5b7d4a2e8c6f1b0d9e7a5c3b",
+				},
+				ExpectedResult: "The next part of the output, ending with the same continuation_token if there is more, or an end of output marker.",
+			},
+		},
+		CommonPatterns: []string{
+			"Read only as many parts as the task needs - narrowing the original call (a smaller range, path or filter) is often better than reading everything",
+			"The same continuation_token is used for every part of an output",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "continuation token not found",
+				Solution: "Outputs are kept for 30 minutes after they were last read and dropped once read to the end. Call the original tool again.",
+			},
+		},
+		WhenToUse:    "When a tool output ends with '[Output from ... truncated' and the rest is needed.",
+		WhenNotToUse: "When the part already returned answers the question.",
+	}
+}
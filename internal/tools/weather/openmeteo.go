@@ -0,0 +1,268 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultForecastURL  = "https://api.open-meteo.com/v1/forecast"
+	defaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+	// Open-Meteo's commercial API, used by default when an API key is set
+	customerForecastURL  = "https://customer-api.open-meteo.com/v1/forecast"
+	customerGeocodingURL = "https://customer-geocoding-api.open-meteo.com/v1/search"
+
+	// forecastURLEnvVar and geocodingURLEnvVar point the tool at another Open-Meteo compatible API,
+	// such as a self-hosted instance
+	forecastURLEnvVar  = "WEATHER_FORECAST_URL"
+	geocodingURLEnvVar = "WEATHER_GEOCODING_URL"
+	// apiKeyEnvVar holds an optional Open-Meteo API key for its commercial API
+	apiKeyEnvVar = "OPEN_METEO_API_KEY"
+
+	// maxResponseBytes limits the size of an API response
+	maxResponseBytes = 10 * 1024 * 1024
+)
+
+// Variables requested from the forecast API, matching the fields of forecastResponse
+const (
+	currentVariables = "temperature_2m,apparent_temperature,relative_humidity_2m,precipitation,weather_code,wind_speed_10m,wind_direction_10m,wind_gusts_10m"
+	dailyVariables   = "weather_code,temperature_2m_min,temperature_2m_max,precipitation_probability_max,precipitation_sum,wind_speed_10m_max,wind_gusts_10m_max,uv_index_max,sunrise,sunset"
+	hourlyVariables  = "temperature_2m,apparent_temperature,precipitation_probability,precipitation,weather_code,wind_speed_10m,wind_direction_10m,wind_gusts_10m"
+)
+
+// place is a geocoded location
+type place struct {
+	Name        string   `json:"name"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+	Elevation   *float64 `json:"elevation"`
+	Timezone    string   `json:"timezone"`
+	Country     string   `json:"country"`
+	CountryCode string   `json:"country_code"`
+	Admin1      string   `json:"admin1"`
+	Admin2      string   `json:"admin2"`
+	Population  int      `json:"population"`
+}
+
+// forecastResponse is the forecast API's response
+type forecastResponse struct {
+	Latitude             float64           `json:"latitude"`
+	Longitude            float64           `json:"longitude"`
+	Elevation            *float64          `json:"elevation"`
+	Timezone             string            `json:"timezone"`
+	TimezoneAbbreviation string            `json:"timezone_abbreviation"`
+	CurrentUnits         map[string]string `json:"current_units"`
+	Current              *struct {
+		Time                string   `json:"time"`
+		Temperature         *float64 `json:"temperature_2m"`
+		ApparentTemperature *float64 `json:"apparent_temperature"`
+		Humidity            *float64 `json:"relative_humidity_2m"`
+		Precipitation       *float64 `json:"precipitation"`
+		WeatherCode         *int     `json:"weather_code"`
+		WindSpeed           *float64 `json:"wind_speed_10m"`
+		WindDirection       *float64 `json:"wind_direction_10m"`
+		WindGusts           *float64 `json:"wind_gusts_10m"`
+	} `json:"current"`
+	DailyUnits map[string]string `json:"daily_units"`
+	Daily      *struct {
+		Time                     []string   `json:"time"`
+		WeatherCode              []*int     `json:"weather_code"`
+		TemperatureMin           []*float64 `json:"temperature_2m_min"`
+		TemperatureMax           []*float64 `json:"temperature_2m_max"`
+		PrecipitationProbability []*float64 `json:"precipitation_probability_max"`
+		Precipitation            []*float64 `json:"precipitation_sum"`
+		WindSpeed                []*float64 `json:"wind_speed_10m_max"`
+		WindGusts                []*float64 `json:"wind_gusts_10m_max"`
+		UVIndex                  []*float64 `json:"uv_index_max"`
+		Sunrise                  []string   `json:"sunrise"`
+		Sunset                   []string   `json:"sunset"`
+	} `json:"daily"`
+	HourlyUnits map[string]string `json:"hourly_units"`
+	Hourly      *struct {
+		Time                     []string   `json:"time"`
+		Temperature              []*float64 `json:"temperature_2m"`
+		ApparentTemperature      []*float64 `json:"apparent_temperature"`
+		PrecipitationProbability []*float64 `json:"precipitation_probability"`
+		Precipitation            []*float64 `json:"precipitation"`
+		WeatherCode              []*int     `json:"weather_code"`
+		WindSpeed                []*float64 `json:"wind_speed_10m"`
+		WindDirection            []*float64 `json:"wind_direction_10m"`
+		WindGusts                []*float64 `json:"wind_gusts_10m"`
+	} `json:"hourly"`
+}
+
+// cacheEntry stores a fetched response body
+type cacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
+}
+
+// endpoints returns the forecast and geocoding API URLs and the API key, if one is set
+func endpoints() (forecastURL, geocodingURL, key string) {
+	key = credentials.Lookup(apiKeyEnvVar, credentials.OpenMeteoKey)
+	forecastURL, geocodingURL = defaultForecastURL, defaultGeocodingURL
+	if key != "" {
+		forecastURL, geocodingURL = customerForecastURL, customerGeocodingURL
+	}
+	if u := strings.TrimSpace(os.Getenv(forecastURLEnvVar)); u != "" {
+		forecastURL = u
+	}
+	if u := strings.TrimSpace(os.Getenv(geocodingURLEnvVar)); u != "" {
+		geocodingURL = u
+	}
+	return forecastURL, geocodingURL, key
+}
+
+// geocode returns the places matching a location such as "Wellington" or "Springfield, Illinois",
+// most populous first. Open-Meteo only searches place names, so the parts after the first comma are
+// matched against each place's region and country.
+func (t *WeatherTool) geocode(ctx context.Context, logger *logrus.Logger, cache *sync.Map, location string, limit int) ([]place, error) {
+	name, rest, _ := strings.Cut(location, ",")
+	name = strings.TrimSpace(name)
+	var qualifiers []string
+	for q := range strings.SplitSeq(rest, ",") {
+		if q = strings.ToLower(strings.TrimSpace(q)); q != "" {
+			qualifiers = append(qualifiers, q)
+		}
+	}
+	count := limit
+	if len(qualifiers) > 0 {
+		// Ask for more places, as some are filtered out
+		count = maxGeocodeResults
+	}
+
+	_, geocodingURL, key := endpoints()
+	params := url.Values{"name": {name}, "count": {fmt.Sprint(count)}, "language": {"en"}, "format": {"json"}}
+	var response struct {
+		Results []place `json:"results"`
+	}
+	if err := t.fetchJSON(ctx, logger, cache, geocodingURL, params, key, geocodeTTL, &response); err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", location, err)
+	}
+
+	var places []place
+	for _, p := range response.Results {
+		if matchesAll(p, qualifiers) {
+			places = append(places, p)
+		}
+		if len(places) == limit {
+			break
+		}
+	}
+	return places, nil
+}
+
+// matchesAll reports whether each qualifier is part of a place's region, district, country or
+// country code
+func matchesAll(p place, qualifiers []string) bool {
+	for _, q := range qualifiers {
+		found := false
+		for _, field := range []string{p.Admin1, p.Admin2, p.Country, p.CountryCode} {
+			if field != "" && strings.Contains(strings.ToLower(field), q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// forecast fetches a forecast with the given parameters added to the location and units
+func (t *WeatherTool) forecast(ctx context.Context, logger *logrus.Logger, cache *sync.Map, req request, params url.Values) (forecastResponse, error) {
+	forecastURL, _, key := endpoints()
+	params.Set("latitude", fmt.Sprintf("%.4f", req.latitude))
+	params.Set("longitude", fmt.Sprintf("%.4f", req.longitude))
+	params.Set("timezone", "auto")
+	params.Set("current", currentVariables)
+	if req.imperial {
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+		params.Set("precipitation_unit", "inch")
+	}
+	var response forecastResponse
+	if err := t.fetchJSON(ctx, logger, cache, forecastURL, params, key, forecastTTL, &response); err != nil {
+		return forecastResponse{}, fmt.Errorf("failed to fetch the forecast: %w", err)
+	}
+	return response, nil
+}
+
+// fetchJSON makes a GET request and decodes its JSON body into out. Responses are cached for ttl. The
+// API key is added to the request only, never to cache keys, logs or errors.
+func (t *WeatherTool) fetchJSON(ctx context.Context, logger *logrus.Logger, cache *sync.Map, endpoint string, params url.Values, key string, ttl time.Duration, out any) error {
+	cacheKey := "weather:" + endpoint + "?" + params.Encode()
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < ttl {
+			return json.Unmarshal(entry.body, out)
+		}
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if err := security.CheckDomainAccess(parsed.Hostname()); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return security.FormatSecurityBlockError(secErr)
+		}
+		return err
+	}
+
+	query := maps.Clone(params)
+	if key != "" {
+		query.Set("apikey", key)
+	}
+	logger.WithFields(logrus.Fields{"endpoint": endpoint, "params": params.Encode()}).Debug("Fetching weather data")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mcp-devtools/1.0")
+	resp, err := t.httpClient().Do(req)
+	if err != nil {
+		// A url.Error repeats the request URL, API key and all
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request to %s failed: %w", parsed.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", parsed.Host, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Open-Meteo explains rejected requests, such as dates outside its range, in the body
+		var response struct {
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(body, &response) == nil && response.Reason != "" {
+			return fmt.Errorf("%s returned status code %d: %s", parsed.Host, resp.StatusCode, response.Reason)
+		}
+		return fmt.Errorf("%s returned status code %d", parsed.Host, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", parsed.Host, err)
+	}
+	cache.Store(cacheKey, cacheEntry{body: body, fetchedAt: time.Now()})
+	return nil
+}
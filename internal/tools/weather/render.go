@@ -0,0 +1,309 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dryRainChance is the rain chance in percent below which an hour without forecast precipitation
+// counts as dry
+const dryRainChance = 30
+
+// weatherCodes describes the WMO weather interpretation codes the forecast API uses
+var weatherCodes = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Freezing fog",
+	51: "Light drizzle",
+	53: "Drizzle",
+	55: "Heavy drizzle",
+	56: "Light freezing drizzle",
+	57: "Freezing drizzle",
+	61: "Light rain",
+	63: "Rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Freezing rain",
+	71: "Light snow",
+	73: "Snow",
+	75: "Heavy snow",
+	77: "Snow grains",
+	80: "Light showers",
+	81: "Showers",
+	82: "Violent showers",
+	85: "Light snow showers",
+	86: "Snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+// compassPoints are the directions wind comes from, clockwise from north
+var compassPoints = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// renderPlaces lists the places matching a location
+func renderPlaces(location string, places []place) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Places Matching %q\n\n", location)
+	for i, p := range places {
+		fmt.Fprintf(&b, "%d. **%s**: %.4f, %.4f", i+1, p.label(), p.Latitude, p.Longitude)
+		if p.Elevation != nil {
+			fmt.Fprintf(&b, ", elevation %s m", formatNumber(*p.Elevation))
+		}
+		if p.Timezone != "" {
+			fmt.Fprintf(&b, ", time zone %s", p.Timezone)
+		}
+		if p.Population > 0 {
+			fmt.Fprintf(&b, ", population %d", p.Population)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nPass a place's latitude and longitude, or add its region or country to location, to get its forecast.\n")
+	return b.String()
+}
+
+// label names a place with its region and country, e.g. Springfield, Illinois, United States
+func (p place) label() string {
+	parts := []string{p.Name}
+	for _, part := range []string{p.Admin1, p.Country} {
+		if part != "" && !slices.Contains(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderForecast formats a forecast as markdown. chosen is the place looked up, or nil for coordinates,
+// and others are the other places that matched its name.
+func renderForecast(chosen *place, others []place, f forecastResponse) string {
+	var b strings.Builder
+	if chosen != nil {
+		fmt.Fprintf(&b, "# Weather for %s\n\n", chosen.label())
+	} else {
+		fmt.Fprintf(&b, "# Weather at %.4f, %.4f\n\n", f.Latitude, f.Longitude)
+	}
+	fmt.Fprintf(&b, "Forecast for %.4f, %.4f", f.Latitude, f.Longitude)
+	if f.Elevation != nil {
+		fmt.Fprintf(&b, ", elevation %s m", formatNumber(*f.Elevation))
+	}
+	fmt.Fprintf(&b, ". Times are local (%s", f.Timezone)
+	if f.TimezoneAbbreviation != "" && f.TimezoneAbbreviation != f.Timezone {
+		fmt.Fprintf(&b, ", %s", f.TimezoneAbbreviation)
+	}
+	b.WriteString(").\n")
+	if len(others) > 0 {
+		labels := make([]string, len(others))
+		for i, p := range others {
+			labels[i] = p.label()
+		}
+		fmt.Fprintf(&b, "\nOther places matched: %s. Add the region or country to location, or give latitude and longitude, for one of them.\n", strings.Join(labels, "; "))
+	}
+
+	if c := f.Current; c != nil {
+		u := f.CurrentUnits
+		fmt.Fprintf(&b, "\n## Now\n\n%s: %s, %s (feels like %s), humidity %s, wind %s",
+			formatTime(c.Time), describe(c.WeatherCode),
+			withUnit(c.Temperature, u["temperature_2m"]), withUnit(c.ApparentTemperature, u["apparent_temperature"]),
+			withUnit(c.Humidity, u["relative_humidity_2m"]), wind(c.WindSpeed, c.WindDirection, u["wind_speed_10m"]))
+		if c.WindGusts != nil {
+			fmt.Fprintf(&b, " gusting to %s", withUnit(c.WindGusts, u["wind_gusts_10m"]))
+		}
+		fmt.Fprintf(&b, ", precipitation %s.\n", withUnit(c.Precipitation, u["precipitation"]))
+	}
+
+	if d := f.Daily; d != nil && len(d.Time) > 0 {
+		u := f.DailyUnits
+		b.WriteString("\n## Daily Forecast\n\n")
+		b.WriteString("| Date | Conditions | Temperature | Rain chance | Precipitation | Wind | Gusts | UV index | Daylight |\n")
+		b.WriteString("|------|------------|-------------|-------------|---------------|------|-------|----------|----------|\n")
+		for i, day := range d.Time {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s–%s |\n",
+				formatDay(day), describe(at(d.WeatherCode, i)),
+				span(at(d.TemperatureMin, i), at(d.TemperatureMax, i), u["temperature_2m_max"]),
+				withUnit(at(d.PrecipitationProbability, i), u["precipitation_probability_max"]),
+				withUnit(at(d.Precipitation, i), u["precipitation_sum"]),
+				withUnit(at(d.WindSpeed, i), u["wind_speed_10m_max"]),
+				withUnit(at(d.WindGusts, i), u["wind_gusts_10m_max"]),
+				withUnit(at(d.UVIndex, i), ""),
+				clock(atString(d.Sunrise, i)), clock(atString(d.Sunset, i)))
+		}
+	}
+
+	if h := f.Hourly; h != nil && len(h.Time) > 0 {
+		u := f.HourlyUnits
+		b.WriteString("\n## Hourly Forecast\n\n")
+		b.WriteString("| Time | Conditions | Temperature | Feels like | Rain chance | Precipitation | Wind | Gusts |\n")
+		b.WriteString("|------|------------|-------------|------------|-------------|---------------|------|-------|\n")
+		for i, hour := range h.Time {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				formatTime(hour), describe(at(h.WeatherCode, i)),
+				withUnit(at(h.Temperature, i), u["temperature_2m"]),
+				withUnit(at(h.ApparentTemperature, i), u["apparent_temperature"]),
+				withUnit(at(h.PrecipitationProbability, i), u["precipitation_probability"]),
+				withUnit(at(h.Precipitation, i), u["precipitation"]),
+				wind(at(h.WindSpeed, i), at(h.WindDirection, i), u["wind_speed_10m"]),
+				withUnit(at(h.WindGusts, i), u["wind_gusts_10m"]))
+		}
+		b.WriteString("\n" + summariseHours(f) + "\n")
+	}
+
+	b.WriteString("\nWeather data by Open-Meteo.com (CC BY 4.0).\n")
+	return b.String()
+}
+
+// summariseHours summarises an hourly forecast's window: its temperature range, rain, strongest wind
+// and how many hours are dry
+func summariseHours(f forecastResponse) string {
+	h, u := f.Hourly, f.HourlyUnits
+	var parts []string
+	if low, high := lowest(h.Temperature), highest(h.Temperature); low != nil {
+		parts = append(parts, span(low, high, u["temperature_2m"]))
+	}
+	if chance := highest(h.PrecipitationProbability); chance != nil {
+		parts = append(parts, "rain chance up to "+withUnit(chance, u["precipitation_probability"]))
+	}
+	total, known := 0.0, false
+	for _, v := range h.Precipitation {
+		if v != nil {
+			total += *v
+			known = true
+		}
+	}
+	if known {
+		total = math.Round(total*100) / 100
+		parts = append(parts, withUnit(&total, u["precipitation"])+" of precipitation in total")
+	}
+	if speed := highest(h.WindSpeed); speed != nil {
+		windPart := "wind up to " + withUnit(speed, u["wind_speed_10m"])
+		if gusts := highest(h.WindGusts); gusts != nil {
+			windPart += ", gusts up to " + withUnit(gusts, u["wind_gusts_10m"])
+		}
+		parts = append(parts, windPart)
+	}
+	dry := 0
+	for i := range h.Time {
+		precipitation, chance := at(h.Precipitation, i), at(h.PrecipitationProbability, i)
+		if precipitation != nil && *precipitation == 0 && (chance == nil || *chance < dryRainChance) {
+			dry++
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d of %d hours dry (no precipitation forecast and a rain chance under %d%%)", dry, len(h.Time), dryRainChance))
+	return "**Window summary:** " + strings.Join(parts, "; ") + "."
+}
+
+// describe returns the conditions a weather code stands for
+func describe(code *int) string {
+	if code == nil {
+		return "–"
+	}
+	if description, ok := weatherCodes[*code]; ok {
+		return description
+	}
+	return fmt.Sprintf("Weather code %d", *code)
+}
+
+// wind formats a wind speed with the compass point it blows from
+func wind(speed, direction *float64, unit string) string {
+	text := withUnit(speed, unit)
+	if speed != nil && direction != nil && *speed > 0 {
+		index := int((*direction+22.5)/45) % len(compassPoints)
+		text += " " + compassPoints[index]
+	}
+	return text
+}
+
+// withUnit formats a value with its unit, or a dash when there is no value. Degrees and percentages
+// follow the number directly.
+func withUnit(value *float64, unit string) string {
+	if value == nil {
+		return "–"
+	}
+	switch {
+	case unit == "":
+		return formatNumber(*value)
+	case unit == "%" || strings.HasPrefix(unit, "°"):
+		return formatNumber(*value) + unit
+	default:
+		return formatNumber(*value) + " " + unit
+	}
+}
+
+// span formats a range of values, e.g. 9.1 to 14.6°C
+func span(low, high *float64, unit string) string {
+	if low == nil || high == nil {
+		return "–"
+	}
+	return formatNumber(*low) + " to " + withUnit(high, unit)
+}
+
+// formatNumber formats a number without trailing zeros
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatTime formats a local time such as 2026-10-20T06:00 as 2026-10-20 06:00
+func formatTime(value string) string {
+	return strings.Replace(value, "T", " ", 1)
+}
+
+// formatDay formats a date with its weekday, e.g. Tue 2026-10-20
+func formatDay(value string) string {
+	day, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return value
+	}
+	return day.Format("Mon 2006-01-02")
+}
+
+// clock returns the time of day of a local time such as 2026-10-20T06:41
+func clock(value string) string {
+	if _, hour, ok := strings.Cut(value, "T"); ok {
+		return hour
+	}
+	return "–"
+}
+
+// at returns the ith value of a series, or nil when the series is too short
+func at[T any](values []*T, i int) *T {
+	if i < len(values) {
+		return values[i]
+	}
+	return nil
+}
+
+// atString returns the ith value of a series of strings, or an empty string
+func atString(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// lowest returns the smallest value of a series, ignoring missing values
+func lowest(values []*float64) *float64 {
+	var result *float64
+	for _, v := range values {
+		if v != nil && (result == nil || *v < *result) {
+			result = v
+		}
+	}
+	return result
+}
+
+// highest returns the largest value of a series, ignoring missing values
+func highest(values []*float64) *float64 {
+	var result *float64
+	for _, v := range values {
+		if v != nil && (result == nil || *v > *result) {
+			result = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,369 @@
+// Package weather implements the weather tool, which looks up places and their current conditions,
+// daily and hourly forecasts from Open-Meteo or a compatible API.
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// forecastTTL is how long forecasts are cached. Open-Meteo updates its forecasts hourly at most.
+	forecastTTL = 30 * time.Minute
+	// geocodeTTL is how long place lookups are cached
+	geocodeTTL = 24 * time.Hour
+
+	defaultDays           = 3
+	maxDays               = 16
+	defaultHours          = 24
+	maxWindowHours        = 7 * 24
+	defaultGeocodeResults = 5
+	maxGeocodeResults     = 10
+
+	actionForecast = "forecast"
+	actionHourly   = "hourly"
+	actionGeocode  = "geocode"
+
+	dateLayout = "2006-01-02"
+	hourLayout = "2006-01-02T15:04"
+)
+
+// WeatherTool looks up places and their weather forecasts
+type WeatherTool struct {
+	client packageversions.HTTPClient
+}
+
+// request is a weather lookup for one location
+type request struct {
+	location  string
+	latitude  float64
+	longitude float64
+	// coordinates records whether latitude and longitude were given rather than looked up
+	coordinates bool
+	days        int
+	// start and end are the forecast window in the location's local time, when hasWindow is set
+	start, end time.Time
+	hasWindow  bool
+	imperial   bool
+}
+
+// init registers the weather tool
+func init() {
+	registry.Register(&WeatherTool{})
+}
+
+// NewWeatherTool creates a weather tool that uses the given HTTP client
+func NewWeatherTool(client packageversions.HTTPClient) *WeatherTool {
+	return &WeatherTool{client: client}
+}
+
+// httpClient returns the configured client, defaulting to the shared rate-limited client
+func (t *WeatherTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return packageversions.DefaultHTTPClient
+	}
+	return t.client
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *WeatherTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"weather",
+		mcp.WithDescription(`Weather forecasts for a place or coordinates, for planning outdoor and field work.
+
+Actions:
+- forecast: current conditions and a daily forecast (default: 3 days, up to 16)
+- hourly: current conditions and an hourly forecast for a time window (default: the next 24 hours), with a summary of the window
+- geocode: find places matching a name, with their coordinates and time zones
+
+Times are local to the location. Windows are given as start and end, e.g. 2026-10-20T06:00 to 2026-10-20T18:00.`),
+		mcp.WithString("action",
+			mcp.Description("What to return (default: forecast)"),
+			mcp.Enum(actionForecast, actionHourly, actionGeocode),
+		),
+		mcp.WithString("location",
+			mcp.Description("Place name, optionally with its region or country after commas, e.g. Wellington, Springfield, Illinois or Perth, AU. Required unless latitude and longitude are given"),
+		),
+		mcp.WithNumber("latitude",
+			mcp.Description("Latitude in decimal degrees, with longitude, instead of location"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Description("Longitude in decimal degrees, with latitude, instead of location"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description(fmt.Sprintf("For forecast, days to forecast from today (default: %d, max: %d)", defaultDays, maxDays)),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the forecast window in local time: a date (YYYY-MM-DD) or a date and hour (YYYY-MM-DDTHH:MM)"),
+		),
+		mcp.WithString("end",
+			mcp.Description(fmt.Sprintf("End of the forecast window in local time (default: the end of the start day). Windows can be up to %d days for forecast and %d hours for hourly", maxDays, maxWindowHours)),
+		),
+		mcp.WithString("units",
+			mcp.Description("metric (°C, km/h, mm) or imperial (°F, mph, inches) (default: metric)"),
+			mcp.Enum("metric", "imperial"),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads forecasts
+		mcp.WithDestructiveHintAnnotation(false), // No side effects
+		mcp.WithIdempotentHintAnnotation(false),  // Forecasts are updated over time
+		mcp.WithOpenWorldHintAnnotation(true),    // Calls the Open-Meteo APIs
+	)
+}
+
+// Execute runs the requested lookup
+func (t *WeatherTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionForecast
+	if a, ok := args["action"].(string); ok && a != "" {
+		action = a
+	}
+	if action != actionForecast && action != actionHourly && action != actionGeocode {
+		return nil, fmt.Errorf("action must be one of: forecast, hourly, geocode")
+	}
+	req, err := parseRequest(args, action)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"action":   action,
+		"location": req.location,
+	}).Debug("Looking up weather")
+
+	if action == actionGeocode {
+		places, err := t.geocode(ctx, logger, cache, req.location, maxGeocodeResults)
+		if err != nil {
+			return nil, err
+		}
+		if len(places) == 0 {
+			return nil, noPlaceError(req.location)
+		}
+		return mcp.NewToolResultText(renderPlaces(req.location, places)), nil
+	}
+
+	var chosen *place
+	var others []place
+	if !req.coordinates {
+		places, err := t.geocode(ctx, logger, cache, req.location, defaultGeocodeResults)
+		if err != nil {
+			return nil, err
+		}
+		if len(places) == 0 {
+			return nil, noPlaceError(req.location)
+		}
+		chosen, others = &places[0], places[1:]
+		req.latitude, req.longitude = chosen.Latitude, chosen.Longitude
+	}
+
+	params := url.Values{}
+	if action == actionForecast {
+		params.Set("daily", dailyVariables)
+		if req.hasWindow {
+			params.Set("start_date", req.start.Format(dateLayout))
+			params.Set("end_date", req.end.Format(dateLayout))
+		} else {
+			params.Set("forecast_days", fmt.Sprint(req.days))
+		}
+	} else {
+		params.Set("hourly", hourlyVariables)
+		if req.hasWindow {
+			params.Set("start_hour", req.start.Format(hourLayout))
+			params.Set("end_hour", req.end.Format(hourLayout))
+		} else {
+			params.Set("forecast_hours", fmt.Sprint(defaultHours))
+		}
+	}
+	forecast, err := t.forecast(ctx, logger, cache, req, params)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(renderForecast(chosen, others, forecast)), nil
+}
+
+// parseRequest validates the arguments for an action
+func parseRequest(args map[string]any, action string) (request, error) {
+	req := request{days: defaultDays}
+	req.location, _ = args["location"].(string)
+	req.location = strings.TrimSpace(req.location)
+
+	latitude, hasLatitude := args["latitude"].(float64)
+	longitude, hasLongitude := args["longitude"].(float64)
+	switch {
+	case hasLatitude != hasLongitude:
+		return req, fmt.Errorf("latitude and longitude must be given together")
+	case hasLatitude && action != actionGeocode:
+		if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return req, fmt.Errorf("latitude must be between -90 and 90, and longitude between -180 and 180")
+		}
+		req.latitude, req.longitude, req.coordinates = latitude, longitude, true
+	case req.location == "":
+		if action == actionGeocode {
+			return req, fmt.Errorf("missing required parameter: location")
+		}
+		return req, fmt.Errorf("missing required parameter: location (or latitude and longitude)")
+	}
+	if name, _, _ := strings.Cut(req.location, ","); !req.coordinates && len(strings.TrimSpace(name)) < 2 {
+		return req, fmt.Errorf("location must start with a place name of at least two characters")
+	}
+
+	if v, ok := args["days"].(float64); ok {
+		if v < 1 || v > maxDays || v != float64(int(v)) {
+			return req, fmt.Errorf("days must be a whole number from 1 to %d", maxDays)
+		}
+		req.days = int(v)
+	}
+	if units, ok := args["units"].(string); ok && units != "" {
+		switch units {
+		case "metric":
+		case "imperial":
+			req.imperial = true
+		default:
+			return req, fmt.Errorf("units must be metric or imperial")
+		}
+	}
+	return req, parseWindow(&req, args, action)
+}
+
+// parseWindow reads the start and end of a forecast window. A window without an end covers the
+// start day, or the 24 hours from a start hour for an hourly forecast.
+func parseWindow(req *request, args map[string]any, action string) error {
+	startRaw, _ := args["start"].(string)
+	endRaw, _ := args["end"].(string)
+	startRaw, endRaw = strings.TrimSpace(startRaw), strings.TrimSpace(endRaw)
+	if startRaw == "" {
+		if endRaw != "" {
+			return fmt.Errorf("end needs a start")
+		}
+		return nil
+	}
+	if action == actionGeocode {
+		return nil
+	}
+
+	start, startHasTime, err := parseLocalTime("start", startRaw)
+	if err != nil {
+		return err
+	}
+	end := start
+	endHasTime := startHasTime
+	if endRaw != "" {
+		if end, endHasTime, err = parseLocalTime("end", endRaw); err != nil {
+			return err
+		}
+	} else if action == actionHourly && startHasTime {
+		end = start.Add(time.Duration(defaultHours-1) * time.Hour)
+	}
+	if action == actionHourly {
+		// A date alone covers its whole day
+		if !endHasTime {
+			end = end.Add(23 * time.Hour)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("end %s is before start %s", end.Format(hourLayout), start.Format(hourLayout))
+		}
+		if end.Sub(start) >= maxWindowHours*time.Hour {
+			return fmt.Errorf("the window is longer than %d hours: use the forecast action for longer periods", maxWindowHours)
+		}
+	} else {
+		start, end = start.Truncate(24*time.Hour), end.Truncate(24*time.Hour)
+		if end.Before(start) {
+			return fmt.Errorf("end %s is before start %s", end.Format(dateLayout), start.Format(dateLayout))
+		}
+		if end.Sub(start) >= maxDays*24*time.Hour {
+			return fmt.Errorf("the window is longer than %d days", maxDays)
+		}
+	}
+	req.start, req.end, req.hasWindow = start, end, true
+	return nil
+}
+
+// parseLocalTime parses a date, or a date and time, reporting whether it had a time. Minutes are
+// dropped, as forecasts are hourly.
+func parseLocalTime(name, value string) (time.Time, bool, error) {
+	for _, layout := range []string{hourLayout, "2006-01-02T15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Truncate(time.Hour), true, nil
+		}
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid %s %q: use YYYY-MM-DD or YYYY-MM-DDTHH:MM", name, value)
+}
+
+// noPlaceError explains that no place matched a location
+func noPlaceError(location string) error {
+	return fmt.Errorf("no place found matching %q: check the spelling, drop the region or country, or give latitude and longitude", location)
+}
+
+// ProvideExtendedInfo provides detailed usage information for the weather tool
+func (t *WeatherTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Get the next few days' forecast for a town",
+				Arguments: map[string]any{
+					"location": "Queenstown, New Zealand",
+				},
+				ExpectedResult: "Current conditions and a three-day forecast of conditions, temperatures, rain, wind, UV and daylight",
+			},
+			{
+				Description: "Check a working window at a site by its coordinates",
+				Arguments: map[string]any{
+					"action":    "hourly",
+					"latitude":  -33.8688,
+					"longitude": 151.2093,
+					"start":     "2026-10-20T06:00",
+					"end":       "2026-10-20T18:00",
+				},
+				ExpectedResult: "Hour-by-hour conditions from 06:00 to 18:00 local time, with the window's temperature range, rain, strongest gusts and dry hours",
+			},
+			{
+				Description: "Work out which place a name refers to",
+				Arguments: map[string]any{
+					"action":   "geocode",
+					"location": "Springfield",
+				},
+				ExpectedResult: "Places called Springfield with their region, country, coordinates and time zone",
+			},
+		},
+		CommonPatterns: []string{
+			"Use hourly with a start and end for go/no-go decisions on a shift or job",
+			"Use geocode first when a place name is ambiguous, then pass its latitude and longitude",
+			"Give latitude and longitude for sites away from towns; forecasts are for the exact point",
+			"Pair with the time tool to convert the location's local times for people in other time zones",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "The forecast is for the wrong place",
+				Solution: "Add the region or country after a comma, e.g. Perth, Scotland, or use geocode and pass the latitude and longitude of the right place.",
+			},
+			{
+				Problem:  "No place found",
+				Solution: "Only place names are searched, not street addresses or postcodes. Use the nearest town, or the site's coordinates.",
+			},
+			{
+				Problem:  "A window's dates are out of range",
+				Solution: "Forecasts reach 16 days ahead, and the whole window must fall within that. The error from Open-Meteo gives the allowed dates.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"location": "A town or city name. Parts after a comma are matched against the place's region and country, e.g. Portland, Oregon or Perth, AU.",
+			"start":    "Local time at the location. For forecast only the date is used; for hourly a date alone covers the whole day.",
+			"end":      "Inclusive. Defaults to the end of the start day, or 24 hours after a start hour for hourly.",
+			"units":    "Imperial gives °F, mph and inches; metric gives °C, km/h and mm.",
+		},
+		WhenToUse:    "Use to plan outdoor or field work, travel and site visits around the weather, or to check current conditions at a location.",
+		WhenNotToUse: "Don't use for severe weather warnings, aviation or marine safety decisions; use the official warnings of the national weather service.",
+	}
+}
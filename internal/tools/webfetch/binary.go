@@ -0,0 +1,158 @@
+package webfetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// conversionTimeout limits how long a fetched document's conversion may take, including its wait for a
+// document worker. It matches process_document's default processing timeout.
+const conversionTimeout = 5 * time.Minute
+
+// documentExtensions maps the content types process_document can convert to their file extensions
+var documentExtensions = map[string]string{
+	"application/pdf":    ".pdf",
+	"application/msword": ".doc",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+	"application/vnd.ms-excel": ".xls",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.ms-powerpoint":                                             ".ppt",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"application/rtf": ".rtf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/bmp":       ".bmp",
+	"image/tiff":      ".tiff",
+}
+
+// binaryContentType returns the media type of a response that cannot be shown as text. Responses
+// without a useful content type are identified by the URL's extension, then by their first bytes.
+func binaryContentType(target *url.URL, resp *security.SafeHTTPResponse) (string, bool) {
+	contentType := mediaType(resp.ContentType)
+	if contentType == "" || contentType == "application/octet-stream" || contentType == "binary/octet-stream" {
+		if byExtension := mime.TypeByExtension(strings.ToLower(path.Ext(target.Path))); byExtension != "" {
+			contentType = mediaType(byExtension)
+		} else {
+			contentType = mediaType(http.DetectContentType(resp.Content))
+		}
+	}
+	return contentType, DetectContentType(contentType, string(resp.Content)).IsBinary
+}
+
+// mediaType strips parameters such as the charset from a content type
+func mediaType(contentType string) string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// binaryFileName names a downloaded file after the server's suggested file name, or the URL's last
+// path segment, adding an extension for its content type when it has none
+func binaryFileName(target *url.URL, header http.Header, contentType string) string {
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	name := path.Base(target.Path)
+	if name == "." || name == "/" {
+		name = "download"
+	}
+	if path.Ext(name) == "" {
+		name += extensionFor(contentType)
+	}
+	return name
+}
+
+// extensionFor returns the usual file extension for a content type
+func extensionFor(contentType string) string {
+	if extension, ok := documentExtensions[contentType]; ok {
+		return extension
+	}
+	if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ".bin"
+}
+
+// isDocument reports whether process_document can convert a file, going by its extension
+func isDocument(name string) bool {
+	extension := strings.ToLower(path.Ext(name))
+	for _, known := range documentExtensions {
+		if extension == known || (extension == ".jpeg" && known == ".jpg") || (extension == ".tif" && known == ".tiff") {
+			return true
+		}
+	}
+	return false
+}
+
+// saveBinary saves a binary response as an artifact of the session and returns the text to show in
+// its place. Documents are converted to markdown by process_document when convert is set and the tool
+// is enabled, otherwise the text says where the file was saved.
+func saveBinary(ctx context.Context, logger *logrus.Logger, target *url.URL, resp *security.SafeHTTPResponse, contentType string, convert bool) (string, *artifacts.Artifact, string, error) {
+	name := binaryFileName(target, resp.Headers, contentType)
+	artifact, err := artifacts.Save(ctx, "fetch_url", name, contentType, "Downloaded from "+target.Redacted(), resp.Content)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to save %s content: %w", contentType, err)
+	}
+
+	saved := fmt.Sprintf("Saved %d bytes of %s to %s (artifact %s).", artifact.Size, contentType, artifact.Path, artifact.ID)
+	if !isDocument(artifact.Name) {
+		return saved + " The content is binary and cannot be shown as text.", artifact, "", nil
+	}
+	processor, err := toolcall.Enabled("process_document")
+	if err != nil {
+		return saved + " Enable process_document to convert it to markdown, or pass the path to another tool.", artifact, "", nil
+	}
+	if !convert {
+		return saved + " Convert it to markdown with process_document.", artifact, "", nil
+	}
+
+	markdown, err := convertDocument(ctx, logger, processor, artifact.Path)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to convert fetched document")
+		return fmt.Sprintf("%s It could not be converted to markdown: %v", saved, err), artifact, "", nil
+	}
+	return markdown, artifact, "process_document", nil
+}
+
+// convertDocument runs process_document on a saved file and returns the markdown it produced. The
+// call goes through toolcall, so it is authorised for the caller and waits for a document worker and
+// memory headroom like a conversion the client asked for.
+func convertDocument(ctx context.Context, logger *logrus.Logger, processor tools.Tool, path string) (string, error) {
+	output, err := toolcall.Call(ctx, logger, "process_document", processor, map[string]any{
+		"source":             path,
+		"return_inline_only": true,
+	}, conversionTimeout)
+	if err != nil {
+		return "", err
+	}
+	if output == "" {
+		return "", fmt.Errorf("process_document returned no content")
+	}
+
+	var converted struct {
+		Content string `json:"content"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(output), &converted); err != nil {
+		// Results that are not JSON, such as summaries of very large documents, are returned as they are
+		return output, nil
+	}
+	if converted.Error != "" {
+		return "", errors.New(converted.Error)
+	}
+	return converted.Content, nil
+}
@@ -36,8 +36,8 @@ type WebClient struct {
 
 // NewWebClient creates a new web client with proper timeouts, context support and proxy configuration
 func NewWebClient() *WebClient {
-	// Use shared HTTP client factory with proxy support
-	client := httpclient.NewHTTPClientWithProxy(DefaultTimeout)
+	// Use shared HTTP client factory with proxy support, caching pages so repeat fetches are fast
+	client := httpclient.New(httpclient.Options{Timeout: DefaultTimeout, Cache: true})
 
 	// Configure redirect handling
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
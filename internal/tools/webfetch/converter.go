@@ -270,8 +270,9 @@ func FilterHTMLByFragment(logger *logrus.Logger, htmlContent string, fragment st
 	return wrappedHTML, nil
 }
 
-// ProcessContent determines how to process content based on its type
-func ProcessContent(logger *logrus.Logger, response *FetchURLResponse, raw bool, fragment string) (string, error) {
+// ProcessContent determines how to process content based on its type. With readable set, HTML pages
+// are reduced to their main content before conversion, unless a fragment selects part of the page.
+func ProcessContent(logger *logrus.Logger, response *FetchURLResponse, raw bool, fragment string, readable bool) (string, error) {
 	if response.Content == "" {
 		return "", nil
 	}
@@ -290,6 +291,7 @@ func ProcessContent(logger *logrus.Logger, response *FetchURLResponse, raw bool,
 		"is_text":      contentInfo.IsText,
 		"is_binary":    contentInfo.IsBinary,
 		"fragment":     fragment,
+		"readable":     readable,
 	}).Debug("Processing content based on detected type")
 
 	// Handle different content types
@@ -308,6 +310,13 @@ func ProcessContent(logger *logrus.Logger, response *FetchURLResponse, raw bool,
 			} else {
 				contentToConvert = filteredContent
 			}
+		} else if readable {
+			mainContent, err := ExtractMainContent(logger, response.Content)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to extract main content, using full content")
+			} else {
+				contentToConvert = mainContent
+			}
 		}
 
 		// Convert HTML to markdown
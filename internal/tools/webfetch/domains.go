@@ -0,0 +1,270 @@
+package webfetch
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/secrets"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"gopkg.in/yaml.v3"
+)
+
+// FetchConfigEnvVar overrides the path of the fetch configuration file
+const FetchConfigEnvVar = "FETCH_CONFIG_PATH"
+
+// FetchConfig customises requests to particular domains
+type FetchConfig struct {
+	Domains []DomainRule `yaml:"domains"`
+}
+
+// DomainRule sets the user agent, headers and cookies sent to the domains it matches
+type DomainRule struct {
+	Match       string            `yaml:"match"`        // "example.com", "*.example.com" for it and its subdomains, or "*"
+	UserAgent   string            `yaml:"user_agent"`   // replaces the default user agent
+	Headers     map[string]string `yaml:"headers"`      // extra headers, values may be secret references
+	Cookies     map[string]string `yaml:"cookies"`      // cookies to send, values may be secret references
+	CookiesFile string            `yaml:"cookies_file"` // Netscape cookies.txt, as browser extensions export
+	KeepCookies bool              `yaml:"keep_cookies"` // resend cookies the site sets for the rest of the session
+}
+
+// sessionCookies holds cookies set by sites whose rule keeps them, until the server stops
+var sessionCookies = struct {
+	sync.Mutex
+	byHost map[string]map[string]*http.Cookie
+}{byHost: make(map[string]map[string]*http.Cookie)}
+
+// fetchConfigPath returns the path of the fetch configuration file
+func fetchConfigPath() (string, error) {
+	if path := os.Getenv(FetchConfigEnvVar); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "fetch.yaml"), nil
+}
+
+// LoadFetchConfig loads the fetch configuration, which is empty if there is no file
+func LoadFetchConfig() (*FetchConfig, error) {
+	path, err := fetchConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &FetchConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fetch config %s: %w", path, err)
+	}
+
+	var config FetchConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse fetch config %s: %w", path, err)
+	}
+	for i, rule := range config.Domains {
+		if strings.TrimSpace(rule.Match) == "" {
+			return nil, fmt.Errorf("fetch config %s: domain %d: match is required", path, i+1)
+		}
+		config.Domains[i].Match = strings.ToLower(strings.TrimSpace(rule.Match))
+	}
+	return &config, nil
+}
+
+// matches reports whether the rule applies to a host
+func (r DomainRule) matches(host string) bool {
+	host = strings.ToLower(host)
+	if r.Match == "*" {
+		return true
+	}
+	if after, ok := strings.CutPrefix(r.Match, "*."); ok {
+		return host == after || strings.HasSuffix(host, "."+after)
+	}
+	return host == r.Match
+}
+
+// RequestHeaders returns the headers to send to a URL from the rules that match its host, applied in
+// order so later rules override earlier ones. Values may hold secret references such as
+// ${keychain:item}. It returns nil when no rule matches, and whether the site's cookies should be kept.
+func (c *FetchConfig) RequestHeaders(target *url.URL) (map[string]string, bool, error) {
+	var headers map[string]string
+	var cookies []*http.Cookie
+	keep := false
+
+	for _, rule := range c.Domains {
+		if !rule.matches(target.Hostname()) {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		if rule.UserAgent != "" {
+			headers["User-Agent"] = rule.UserAgent
+		}
+		for name, value := range rule.Headers {
+			resolved, err := secrets.Resolve(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("fetch config: header %s for %s: %w", name, rule.Match, err)
+			}
+			headers[http.CanonicalHeaderKey(name)] = resolved
+		}
+		if rule.CookiesFile != "" {
+			fileCookies, err := readCookiesFile(rule.CookiesFile, target)
+			if err != nil {
+				return nil, false, err
+			}
+			cookies = append(cookies, fileCookies...)
+		}
+		for name, value := range rule.Cookies {
+			resolved, err := secrets.Resolve(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("fetch config: cookie %s for %s: %w", name, rule.Match, err)
+			}
+			cookies = append(cookies, &http.Cookie{Name: name, Value: resolved})
+		}
+		keep = keep || rule.KeepCookies
+	}
+	if headers == nil {
+		return nil, false, nil
+	}
+
+	if keep {
+		cookies = append(cookies, keptCookies(target)...)
+	}
+	if cookie := cookieHeader(cookies); cookie != "" {
+		headers["Cookie"] = cookie
+	}
+	return headers, keep, nil
+}
+
+// cookieHeader joins cookies into a Cookie header, later cookies replacing earlier ones of the same name
+func cookieHeader(cookies []*http.Cookie) string {
+	byName := make(map[string]int)
+	var unique []*http.Cookie
+	for _, cookie := range cookies {
+		if i, ok := byName[cookie.Name]; ok {
+			unique[i] = cookie
+			continue
+		}
+		byName[cookie.Name] = len(unique)
+		unique = append(unique, cookie)
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Name < unique[j].Name })
+	parts := make([]string, 0, len(unique))
+	for _, cookie := range unique {
+		parts = append(parts, (&http.Cookie{Name: cookie.Name, Value: cookie.Value}).String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// readCookiesFile returns the unexpired cookies in a Netscape cookies.txt file that apply to a URL
+func readCookiesFile(path string, target *url.URL) ([]*http.Cookie, error) {
+	if strings.HasPrefix(path, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	if err := security.CheckFileAccess(path); err != nil {
+		return nil, fmt.Errorf("cookies file access denied: %w", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookies file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	host := strings.ToLower(target.Hostname())
+	requestPath := target.EscapedPath()
+	if requestPath == "" {
+		requestPath = "/"
+	}
+	now := time.Now().Unix()
+
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Only line endings are trimmed, as an empty value leaves the line ending in a tab
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Browsers mark HttpOnly cookies with a prefix on an otherwise commented line
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+		domain, subdomains, cookiePath, secure, expires, name, value := strings.ToLower(fields[0]), fields[1] == "TRUE", fields[2], fields[3] == "TRUE", fields[4], fields[5], fields[6]
+
+		domain = strings.TrimPrefix(domain, ".")
+		if host != domain && !(subdomains && strings.HasSuffix(host, "."+domain)) {
+			continue
+		}
+		if !strings.HasPrefix(requestPath, cookiePath) {
+			continue
+		}
+		if secure && target.Scheme != "https" {
+			continue
+		}
+		// An expiry of 0 marks a session cookie
+		if expiry, err := strconv.ParseInt(expires, 10, 64); err == nil && expiry != 0 && expiry < now {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookies file: %w", err)
+	}
+	return cookies, nil
+}
+
+// keptCookies returns the cookies a site set earlier in the session
+func keptCookies(target *url.URL) []*http.Cookie {
+	sessionCookies.Lock()
+	defer sessionCookies.Unlock()
+	now := time.Now()
+	var cookies []*http.Cookie
+	for _, cookie := range sessionCookies.byHost[strings.ToLower(target.Hostname())] {
+		if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			continue
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
+
+// keepCookies stores the cookies a site set in its response, for the rest of the session
+func keepCookies(target *url.URL, header http.Header) {
+	set := (&http.Response{Header: header}).Cookies()
+	if len(set) == 0 {
+		return
+	}
+	host := strings.ToLower(target.Hostname())
+	sessionCookies.Lock()
+	defer sessionCookies.Unlock()
+	if sessionCookies.byHost[host] == nil {
+		sessionCookies.byHost[host] = make(map[string]*http.Cookie)
+	}
+	for _, cookie := range set {
+		if cookie.MaxAge < 0 {
+			delete(sessionCookies.byHost[host], cookie.Name)
+			continue
+		}
+		if cookie.MaxAge > 0 {
+			cookie.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+		sessionCookies.byHost[host][cookie.Name] = cookie
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sammcj/mcp-devtools/internal/security"
 	"github.com/sammcj/mcp-devtools/internal/tools"
@@ -40,6 +41,8 @@ Response includes detailed pagination information:
 - remaining_lines: Number of lines remaining after current chunk
 - next_chunk_preview: Preview of what comes next
 
+PDFs, office documents, images and other binary content are saved as artifacts, and documents are converted to markdown with process_document when that tool is enabled.
+
 This tool is useful for fetching web content - for example to get documentation, information from blog posts, implementation guidelines and content from search results.
 `),
 		mcp.WithString("url",
@@ -57,9 +60,17 @@ This tool is useful for fetching web content - for example to get documentation,
 		mcp.WithBoolean("raw",
 			mcp.Description("Return raw HTML content without markdown conversion (default: false)"),
 		),
-		// Read-only annotations for web content fetching tool
-		mcp.WithReadOnlyHintAnnotation(true),     // Only fetches content, doesn't modify environment
-		mcp.WithDestructiveHintAnnotation(false), // No destructive operations
+		mcp.WithBoolean("readability",
+			mcp.Description("Return only the page's main content, without navigation, adverts, banners and footers (default: true). Set false for the whole page"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("convert",
+			mcp.Description("Convert documents such as PDFs to markdown with process_document, when it is enabled, instead of only saving them (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(false),    // Saves binary responses as artifacts and converts documents
+		mcp.WithDestructiveHintAnnotation(false), // Only adds new artifact files
 		mcp.WithIdempotentHintAnnotation(true),   // Same URL returns same content
 		mcp.WithOpenWorldHintAnnotation(true),    // Fetches from external URLs
 	)
@@ -83,9 +94,32 @@ func (t *FetchURLTool) Execute(ctx context.Context, logger *logrus.Logger, cache
 		"fragment":    request.fragment,
 	}).Debug("Fetch URL parameters")
 
+	// Apply the user agent, headers and cookies configured for the URL's domain
+	config, err := LoadFetchConfig()
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	headers, keep, err := config.RequestHeaders(target)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use security helper for safe HTTP GET
 	ops := security.NewOperations("webfetch")
-	safeResp, err := ops.SafeHTTPGet(ctx, request.URL)
+	var safeResp *security.SafeHTTPResponse
+	if headers != nil {
+		logger.WithField("headers", len(headers)).Debug("Applying configured headers for domain")
+		safeResp, err = ops.SafeHTTPGetWithHeaders(ctx, request.URL, headers)
+	} else {
+		safeResp, err = ops.SafeHTTPGet(ctx, request.URL)
+	}
+	if err == nil && keep {
+		keepCookies(target, safeResp.Headers)
+	}
 	if err != nil {
 		// Handle security errors properly
 		if secErr, ok := err.(*security.SecurityError); ok {
@@ -111,11 +145,22 @@ func (t *FetchURLTool) Execute(ctx context.Context, logger *logrus.Logger, cache
 		EndLine:     len(strings.Split(string(safeResp.Content), "\n")),
 	}
 
-	// Process the content (convert HTML to markdown, handle different content types, filter by fragment)
-	processedContent, err := ProcessContent(logger, response, request.Raw, request.fragment)
-	if err != nil {
-		logger.WithError(err).Warn("Failed to process content, returning raw content")
-		processedContent = response.Content
+	// Binary content is saved to a file rather than returned as text, and documents are converted
+	var processedContent, convertedBy string
+	var artifact *artifacts.Artifact
+	if contentType, binary := binaryContentType(target, safeResp); binary && safeResp.StatusCode < 400 {
+		processedContent, artifact, convertedBy, err = saveBinary(ctx, logger, target, safeResp, contentType, request.Convert)
+		if err != nil {
+			return nil, err
+		}
+		response.ContentType = contentType
+	} else {
+		// Process the content (convert HTML to markdown, handle different content types, filter by fragment)
+		processedContent, err = ProcessContent(logger, response, request.Raw, request.fragment, request.Readability)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to process content, returning raw content")
+			processedContent = response.Content
+		}
 	}
 
 	// Handle security warnings from the helper
@@ -127,6 +172,8 @@ func (t *FetchURLTool) Execute(ctx context.Context, logger *logrus.Logger, cache
 
 	// Apply pagination
 	paginatedResponse := t.applyPagination(response, processedContent, request)
+	paginatedResponse.Artifact = artifact
+	paginatedResponse.ConvertedBy = convertedBy
 
 	// Add security notice to response if needed
 	if securityNotice != "" {
@@ -156,6 +203,12 @@ func (t *FetchURLTool) Execute(ctx context.Context, logger *logrus.Logger, cache
 		if paginatedResponse.StatusCode != 200 {
 			responseMap["status_code"] = paginatedResponse.StatusCode
 		}
+		if artifact != nil {
+			responseMap["artifact"] = artifact
+		}
+		if convertedBy != "" {
+			responseMap["converted_by"] = convertedBy
+		}
 
 		logger.WithFields(logrus.Fields{
 			"url":              request.URL,
@@ -206,11 +259,13 @@ func (t *FetchURLTool) parseRequest(args map[string]any) (*FetchURLRequest, erro
 	parsedURL := parseURL(url)
 
 	request := &FetchURLRequest{
-		URL:        parsedURL.URLWithoutFragment,
-		fragment:   parsedURL.Fragment,
-		MaxLength:  6000,  // Default
-		StartIndex: 0,     // Default
-		Raw:        false, // Default
+		URL:         parsedURL.URLWithoutFragment,
+		fragment:    parsedURL.Fragment,
+		MaxLength:   6000,  // Default
+		StartIndex:  0,     // Default
+		Raw:         false, // Default
+		Convert:     true,  // Default
+		Readability: true,  // Default
 	}
 
 	// Parse max_length (optional)
@@ -239,6 +294,16 @@ func (t *FetchURLTool) parseRequest(args map[string]any) (*FetchURLRequest, erro
 		request.Raw = rawRaw
 	}
 
+	// Parse readability (optional)
+	if readability, ok := args["readability"].(bool); ok {
+		request.Readability = readability
+	}
+
+	// Parse convert (optional)
+	if convert, ok := args["convert"].(bool); ok {
+		request.Convert = convert
+	}
+
 	return request, nil
 }
 
@@ -451,6 +516,7 @@ func (t *FetchURLTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"Start with default settings first to get a preview of content structure",
 			"For long documents: use pagination (start with default, then continue with start_index)",
 			"Use raw=true for HTML parsing or when markdown conversion breaks the structure",
+			"Set readability=false if the main content extraction drops something you need, such as a site's navigation",
 			"Increase max_length for comprehensive content, decrease for quick previews",
 			"Combine with internet search results to fetch full content from interesting URLs",
 			"Use with memory tool to store important content for later reference",
@@ -474,7 +540,7 @@ func (t *FetchURLTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			},
 			{
 				Problem:  "Authentication required or access denied errors",
-				Solution: "The content requires login or API keys. This tool only fetches publicly accessible content - private or authenticated content cannot be accessed.",
+				Solution: "The content requires login or API keys. Headers, cookies or a cookies.txt exported from a signed-in browser can be configured for the domain in ~/.mcp-devtools/fetch.yaml (or FETCH_CONFIG_PATH).",
 			},
 			{
 				Problem:  "Content is truncated unexpectedly",
@@ -485,9 +551,11 @@ func (t *FetchURLTool) ProvideExtendedInfo() *tools.ExtendedHelp {
 			"url":         "Must be a complete HTTP/HTTPS URL. Can include a fragment identifier (e.g., #section-id) to filter to a specific section. Tool will attempt to add 'https://' if no protocol is specified. Does not support FTP, file://, or other protocols.",
 			"max_length":  "Controls how much content to return (1 to 1,000,000 characters). Default is 6,000. Use larger values for comprehensive content, smaller for previews.",
 			"start_index": "Character position to start reading from (0-based). Use for pagination when content is longer than max_length. Default is 0 (start of content).",
+			"readability": "When true (default), HTML pages are reduced to their main content, such as the article or the documentation page's body, dropping navigation, sidebars, adverts, cookie banners and footers. Ignored when the URL has a fragment or raw is true. Set false when the content you need is outside the main content, e.g. a site's navigation links.",
+			"convert":     "When true (default) and process_document is enabled, PDFs, office documents and images are converted to markdown after being saved. When false, or when process_document is not enabled, the response only says where the file was saved.",
 			"raw":         "When true, returns raw HTML without markdown conversion (fragment filtering is not applied). When false (default), converts HTML to clean markdown format for easier reading and analysis, with fragment filtering applied when a URL fragment is present.",
 		},
 		WhenToUse:    "Use to fetch and process web content for analysis, extract information from documentation, get full text from search results, or read blog posts and articles. Use URL fragments to extract specific sections and reduce token usage. Ideal for content that needs to be analysed or processed by AI.",
-		WhenNotToUse: "Don't use for downloading files, accessing authenticated content, scraping data that requires JavaScript execution, or fetching binary content like images or PDFs.",
+		WhenNotToUse: "Don't use for accessing authenticated content not configured for its domain, scraping data that requires JavaScript execution, or downloading large files you do not need to read.",
 	}
 }
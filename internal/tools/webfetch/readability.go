@@ -0,0 +1,174 @@
+package webfetch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+)
+
+const (
+	// minReadableLength is the least text main content must have to be used instead of the whole page
+	minReadableLength = 200
+
+	// minParagraphLength is the least text a paragraph must have to count towards its container's score
+	minParagraphLength = 25
+)
+
+var (
+	// noiseSelector matches elements that never hold a page's main content
+	noiseSelector = strings.Join([]string{
+		"script", "style", "noscript", "iframe", "template", "nav", "aside", "form", "dialog",
+		"[role=navigation]", "[role=banner]", "[role=contentinfo]", "[role=complementary]", "[role=search]",
+		"[role=dialog]", "[aria-hidden=true]", "[hidden]",
+	}, ", ")
+
+	// unlikelyPattern matches the classes and IDs of navigation, adverts, banners and other boilerplate
+	unlikelyPattern = regexp.MustCompile(`(?i)(^|[\s_-])(nav|navbar|menu|sidebar|side-bar|breadcrumbs?|footer|masthead|header|ad|ads|advert|advertisement|sponsored?|promo|cookie|consent|gdpr|banner|social|share|sharing|related|recommended|comments?|newsletter|subscribe|signup|popup|modal|skip-link|pagination|pager)($|[\s_-])`)
+
+	// likelyPattern matches the classes and IDs of containers that usually hold the main content, which
+	// are kept even when they also match unlikelyPattern
+	likelyPattern = regexp.MustCompile(`(?i)(article|content|main|post|entry|story|body|markdown|prose|docs?|text)`)
+
+	// paragraphSelector matches the elements whose text scores their containers
+	paragraphSelector = "p, pre, blockquote, td, dd, li"
+)
+
+// ExtractMainContent returns the main content of an HTML page, such as an article or a documentation
+// page's body, without its navigation, adverts, banners and footers. It keeps the page's title as a
+// heading when the content has none. The whole page, less its boilerplate, is returned when no part of
+// it stands out as the main content.
+func ExtractMainContent(logger *logrus.Logger, htmlContent string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+
+	doc.Find(noiseSelector).Remove()
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		if isUnlikely(s) {
+			s.Remove()
+		}
+	})
+
+	body := doc.Find("body")
+	article := semanticMain(doc)
+	if article == nil {
+		article = highestScoring(body)
+	}
+	if article == nil || textLength(article) < minReadableLength {
+		logger.Debug("No main content found, using the page without its boilerplate")
+		article = body
+	} else {
+		// An article's own header holds its title, which would otherwise be dropped with the page's headers
+		article.Find("header").Each(func(_ int, header *goquery.Selection) {
+			if header.Find("h1, h2").Length() > 0 {
+				header.ReplaceWithSelection(header.Contents())
+			}
+		})
+	}
+
+	content, err := article.Html()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract main content: %w", err)
+	}
+	if title != "" && article.Find("h1").Length() == 0 {
+		content = "<h1>" + html.EscapeString(title) + "</h1>\n" + content
+	}
+
+	logger.WithFields(logrus.Fields{
+		"original_size":  len(htmlContent),
+		"extracted_size": len(content),
+		"element":        goquery.NodeName(article),
+	}).Debug("Extracted main content")
+
+	return fmt.Sprintf("<!DOCTYPE html><html><body>%s</body></html>", content), nil
+}
+
+// isUnlikely reports whether an element's class or ID marks it as boilerplate
+func isUnlikely(s *goquery.Selection) bool {
+	switch goquery.NodeName(s) {
+	case "body", "main", "article", "table", "tbody", "tr", "td", "th", "pre", "code":
+		return false
+	}
+	class, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	names := class + " " + id
+	return unlikelyPattern.MatchString(names) && !likelyPattern.MatchString(names)
+}
+
+// semanticMain returns the element the page marks as its main content, when there is exactly one
+func semanticMain(doc *goquery.Document) *goquery.Selection {
+	for _, selector := range []string{"main", "[role=main]", "article"} {
+		found := doc.Find(selector)
+		if found.Length() == 1 && textLength(found) >= minReadableLength {
+			return found
+		}
+	}
+	return nil
+}
+
+// highestScoring returns the container whose paragraphs hold the most prose, in the manner of
+// Readability: each paragraph scores its parent, and half as much its grandparent, by its length and
+// commas, and each container's score is reduced by the share of its text that is links
+func highestScoring(body *goquery.Selection) *goquery.Selection {
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+	add := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 {
+			return
+		}
+		node := s.Get(0)
+		if _, ok := scores[node]; !ok {
+			order = append(order, node)
+		}
+		scores[node] += score
+	}
+
+	body.Find(paragraphSelector).Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		add(p.Parent(), score)
+		add(p.Parent().Parent(), score/2)
+	})
+
+	var best *goquery.Selection
+	bestScore := 0.0
+	// Containers are compared in document order, so ties go to the first
+	for _, node := range order {
+		s := body.FindNodes(node)
+		if s.Length() == 0 {
+			continue
+		}
+		score := scores[node] * (1 - linkDensity(s))
+		if score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	return best
+}
+
+// linkDensity returns the share of an element's text that is inside links
+func linkDensity(s *goquery.Selection) float64 {
+	total := textLength(s)
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(strings.TrimSpace(a.Text()))
+	})
+	return min(float64(links)/float64(total), 1)
+}
+
+// textLength returns the length of an element's text, without surrounding whitespace
+func textLength(s *goquery.Selection) int {
+	return len(strings.TrimSpace(s.Text()))
+}
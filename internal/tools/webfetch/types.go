@@ -1,12 +1,16 @@
 package webfetch
 
+import "github.com/sammcj/mcp-devtools/internal/artifacts"
+
 // FetchURLRequest represents the parameters for the fetch-url tool
 type FetchURLRequest struct {
-	URL        string `json:"url"`
-	fragment   string // Internal field populated from URL fragment parsing, not user-provided
-	MaxLength  int    `json:"max_length,omitempty"`
-	StartIndex int    `json:"start_index,omitempty"`
-	Raw        bool   `json:"raw,omitempty"`
+	URL         string `json:"url"`
+	fragment    string // Internal field populated from URL fragment parsing, not user-provided
+	MaxLength   int    `json:"max_length,omitempty"`
+	StartIndex  int    `json:"start_index,omitempty"`
+	Raw         bool   `json:"raw,omitempty"`
+	Readability bool   `json:"readability,omitempty"`
+	Convert     bool   `json:"convert,omitempty"`
 }
 
 // FetchURLResponse represents the response from the fetch-url tool
@@ -24,6 +28,11 @@ type FetchURLResponse struct {
 	NextChunkPreview string `json:"next_chunk_preview,omitempty"`
 	RemainingLines   int    `json:"remaining_lines"`
 	Message          string `json:"message,omitempty"`
+
+	// Artifact is where binary content, such as a PDF or an image, was saved
+	Artifact *artifacts.Artifact `json:"artifact,omitempty"`
+	// ConvertedBy names the tool that converted a saved document to the returned content
+	ConvertedBy string `json:"converted_by,omitempty"`
 }
 
 // ContentTypeInfo represents information about detected content type
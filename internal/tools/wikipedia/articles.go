@@ -0,0 +1,251 @@
+package wikipedia
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+)
+
+// noiseSelector matches parts of an article that mean nothing without the page around them: references,
+// navigation boxes, maintenance notices, images and the infobox, whose facts the facts action returns
+const noiseSelector = "style, link, script, meta, figure, img, .gallery, .thumb, sup.reference, sup.mw-ref, .mw-ref, " +
+	".mw-references-wrap, ol.references, .reflist, .refbegin, .navbox, .navbox-styles, .metadata, .ambox, .hatnote, " +
+	".shortdescription, .mw-editsection, .mw-empty-elt, .infobox, .sidebar, .noprint"
+
+// summaryResponse is a page summary from the REST API
+type summaryResponse struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Extract      string `json:"extract"`
+	WikibaseItem string `json:"wikibase_item"`
+	ContentURLs  struct {
+		Desktop struct {
+			Page string `json:"page"`
+		} `json:"desktop"`
+	} `json:"content_urls"`
+	Coordinates *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coordinates"`
+}
+
+// searchResponse is a list of pages from the search API
+type searchResponse struct {
+	Pages []struct {
+		Key         string `json:"key"`
+		Title       string `json:"title"`
+		Excerpt     string `json:"excerpt"`
+		Description string `json:"description"`
+	} `json:"pages"`
+}
+
+// fetchSummary returns an article's summary, explaining a missing article
+func (t *WikipediaTool) fetchSummary(logger *logrus.Logger, cache *sync.Map, req request) (*summaryResponse, error) {
+	var summary summaryResponse
+	summaryURL := siteURL(req.language) + "/api/rest_v1/page/summary/" + pageKey(req.title)
+	err := t.fetchJSON(logger, cache, summaryURL, &summary)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("no article titled %q on %s.wikipedia.org, use action search to find its title", req.title, req.language)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch summary of %s: %w", req.title, err)
+	}
+	return &summary, nil
+}
+
+// summary returns an article's description and lead paragraph
+func (t *WikipediaTool) summary(logger *logrus.Logger, cache *sync.Map, req request) (string, error) {
+	summary, err := t.fetchSummary(logger, cache, req)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", summary.Title)
+	if summary.Description != "" {
+		fmt.Fprintf(&b, "_%s_\n\n", summary.Description)
+	}
+	if summary.Type == "disambiguation" {
+		b.WriteString("This is a disambiguation page: the title refers to more than one subject. Use a more specific title, or action search.\n\n")
+	}
+	if extract := strings.TrimSpace(summary.Extract); extract != "" {
+		b.WriteString(extract + "\n\n")
+	}
+	fmt.Fprintf(&b, "Source: %s\n", summary.ContentURLs.Desktop.Page)
+	if summary.WikibaseItem != "" {
+		fmt.Fprintf(&b, "Wikidata: %s\n", summary.WikibaseItem)
+	}
+	if summary.Coordinates != nil {
+		fmt.Fprintf(&b, "Coordinates: %.5f, %.5f\n", summary.Coordinates.Lat, summary.Coordinates.Lon)
+	}
+	return b.String(), nil
+}
+
+// article returns an article, or one of its sections, as markdown
+func (t *WikipediaTool) article(logger *logrus.Logger, cache *sync.Map, req request) (string, error) {
+	pageURL := siteURL(req.language) + "/wiki/" + pageKey(req.title)
+	body, err := t.fetch(logger, cache, siteURL(req.language)+"/api/rest_v1/page/html/"+pageKey(req.title), "text/html")
+	if isNotFound(err) {
+		return "", fmt.Errorf("no article titled %q on %s.wikipedia.org, use action search to find its title", req.title, req.language)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch article %s: %w", req.title, err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse article %s: %w", req.title, err)
+	}
+	title := strings.TrimSpace(doc.Find("head title").First().Text())
+	if title == "" {
+		title = req.title
+	}
+
+	content := doc.Find("body")
+	cleanArticle(content, pageURL)
+	headings := sectionHeadings(content)
+
+	if req.section != "" {
+		section, heading, err := findSection(content, req.section)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w (sections: %s)", title, err, strings.Join(headings, ", "))
+		}
+		content = section
+		title += " § " + heading
+		pageURL += "#" + url.PathEscape(strings.ReplaceAll(heading, " ", "_"))
+	}
+
+	markdown, err := articleMarkdown(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert article %s to markdown: %w", req.title, err)
+	}
+
+	hint := "Raise max_length to see more"
+	if req.section == "" && len(headings) > 0 {
+		hint = "Ask for one section with section: " + strings.Join(headings, ", ")
+	}
+	return fmt.Sprintf("# %s\n\nSource: %s\n\n%s", title, pageURL, truncate(markdown, req.maxLength, hint)), nil
+}
+
+// cleanArticle removes page furniture from Parsoid HTML, turns links to other articles into plain text
+// and makes the remaining links absolute
+func cleanArticle(content *goquery.Selection, pageURL string) {
+	content.Find(noiseSelector).Remove()
+	content.Find(`a[rel~="mw:WikiLink"]`).Each(func(_ int, a *goquery.Selection) {
+		a.ReplaceWithHtml(html.EscapeString(a.Text()))
+	})
+	if base, err := url.Parse(pageURL); err == nil {
+		content.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			if ref, err := url.Parse(a.AttrOr("href", "")); err == nil {
+				a.SetAttr("href", base.ResolveReference(ref).String())
+			}
+		})
+	}
+	// Sections such as References are empty once their lists are removed
+	content.Find("section").Each(func(_ int, section *goquery.Selection) {
+		rest := section.Clone()
+		rest.ChildrenFiltered("h2, h3, h4, h5, h6, .mw-heading").Remove()
+		if strings.TrimSpace(rest.Text()) == "" {
+			section.Remove()
+		}
+	})
+}
+
+// sectionHeadings returns the article's top-level section headings
+func sectionHeadings(content *goquery.Selection) []string {
+	var headings []string
+	content.Find("h2").Each(func(_ int, h *goquery.Selection) {
+		if text := strings.TrimSpace(h.Text()); text != "" {
+			headings = append(headings, text)
+		}
+	})
+	return headings
+}
+
+// findSection returns the section under a heading, matched ignoring case, with its subsections
+func findSection(content *goquery.Selection, name string) (*goquery.Selection, string, error) {
+	var found *goquery.Selection
+	heading := ""
+	content.Find("h2, h3, h4, h5, h6").EachWithBreak(func(_ int, h *goquery.Selection) bool {
+		if text := strings.TrimSpace(h.Text()); strings.EqualFold(text, name) {
+			found = h.Closest("section")
+			heading = text
+			return false
+		}
+		return true
+	})
+	if found == nil || found.Length() == 0 {
+		return nil, "", fmt.Errorf("section not found: %s", name)
+	}
+	return found, heading, nil
+}
+
+// articleMarkdown converts article HTML to markdown, keeping its tables
+func articleMarkdown(content *goquery.Selection) (string, error) {
+	conv := converter.NewConverter(
+		converter.WithPlugins(
+			base.NewBasePlugin(),
+			commonmark.NewCommonmarkPlugin(),
+			table.NewTablePlugin(table.WithSpanCellBehavior(table.SpanBehaviorMirror)),
+		),
+	)
+	var b strings.Builder
+	for _, node := range content.Nodes {
+		markup, err := goquery.OuterHtml(goquery.NewDocumentFromNode(node).Selection)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(markup + "\n")
+	}
+	markdown, err := conv.ConvertString(b.String())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(markdown), nil
+}
+
+// search returns articles matching a query
+func (t *WikipediaTool) search(logger *logrus.Logger, cache *sync.Map, req request) (string, error) {
+	params := url.Values{"q": {req.query}, "limit": {fmt.Sprint(req.limit)}}
+	var results searchResponse
+	if err := t.fetchJSON(logger, cache, siteURL(req.language)+"/w/rest.php/v1/search/page?"+params.Encode(), &results); err != nil {
+		return "", fmt.Errorf("failed to search %s.wikipedia.org: %w", req.language, err)
+	}
+	if len(results.Pages) == 0 {
+		return fmt.Sprintf("No articles found on %s.wikipedia.org for: %s\n", req.language, req.query), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d articles on %s.wikipedia.org for: %s\n\n", len(results.Pages), req.language, req.query)
+	for i, page := range results.Pages {
+		fmt.Fprintf(&b, "%d. **%s**", i+1, page.Title)
+		if page.Description != "" {
+			fmt.Fprintf(&b, " - %s", page.Description)
+		}
+		fmt.Fprintf(&b, "\n   %s/wiki/%s\n", siteURL(req.language), pageKey(page.Key))
+		if excerpt := plainText(page.Excerpt); excerpt != "" {
+			fmt.Fprintf(&b, "   %s...\n", excerpt)
+		}
+	}
+	return b.String(), nil
+}
+
+// plainText returns the text of an HTML fragment, such as a search excerpt with its matches highlighted
+func plainText(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
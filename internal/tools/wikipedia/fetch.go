@@ -0,0 +1,84 @@
+package wikipedia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+// userAgent identifies the tool to Wikimedia, whose API policy asks clients for a name and a contact URL
+const userAgent = "mcp-devtools/1.0 (https://github.com/sammcj/mcp-devtools)"
+
+// cacheEntry stores a fetched response body
+type cacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
+}
+
+// siteURL returns the root URL of a Wikipedia language edition
+func siteURL(language string) string {
+	return "https://" + language + ".wikipedia.org"
+}
+
+// pageKey returns a title as it appears in article URLs, with underscores for spaces
+func pageKey(title string) string {
+	return url.PathEscape(strings.ReplaceAll(strings.TrimSpace(title), " ", "_"))
+}
+
+// fetch returns the body of a URL, cached for cacheTTL
+func (t *WikipediaTool) fetch(logger *logrus.Logger, cache *sync.Map, rawURL, accept string) ([]byte, error) {
+	cacheKey := "wikipedia:" + rawURL
+	if cached, ok := cache.Load(cacheKey); ok {
+		if entry, ok := cached.(cacheEntry); ok && time.Since(entry.fetchedAt) < cacheTTL {
+			return entry.body, nil
+		}
+	}
+
+	body, err := packageversions.MakeRequestWithLogger(t.httpClient(), logger, "GET", rawURL, map[string]string{
+		"Accept":     accept,
+		"User-Agent": userAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(cacheKey, cacheEntry{body: body, fetchedAt: time.Now()})
+	return body, nil
+}
+
+// fetchJSON fetches a URL and decodes its JSON body into out
+func (t *WikipediaTool) fetchJSON(logger *logrus.Logger, cache *sync.Map, rawURL string, out any) error {
+	body, err := t.fetch(logger, cache, rawURL, "application/json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", rawURL, err)
+	}
+	return nil
+}
+
+// isNotFound reports whether a request failed because the page does not exist
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "status code: 404")
+}
+
+// truncate cuts text at maxLength characters, at a line break where there is one in its second half
+func truncate(text string, maxLength int, hint string) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	cut := maxLength
+	for cut > 0 && text[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	if newline := strings.LastIndex(text[:cut], "\n"); newline > maxLength/2 {
+		cut = newline
+	}
+	return fmt.Sprintf("%s\n\n[Truncated: showing %d of %d characters. %s]\n", strings.TrimSpace(text[:cut]), cut, len(text), hint)
+}
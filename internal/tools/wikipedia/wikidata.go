@@ -0,0 +1,323 @@
+package wikipedia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	wikidataURL = "https://www.wikidata.org"
+	// maxLabelIDs is the most ids wbgetentities accepts in one request
+	maxLabelIDs = 50
+	// maxValuesPerProperty is the most values listed for one property
+	maxValuesPerProperty = 10
+)
+
+// entityResponse is the JSON of Wikidata items from Special:EntityData or wbgetentities
+type entityResponse struct {
+	Entities map[string]entity `json:"entities"`
+}
+
+// entity is a Wikidata item or property
+type entity struct {
+	ID           string                 `json:"id"`
+	Labels       map[string]languageVal `json:"labels"`
+	Descriptions map[string]languageVal `json:"descriptions"`
+	Claims       map[string][]claim     `json:"claims"`
+}
+
+// languageVal is a label or description in one language
+type languageVal struct {
+	Value string `json:"value"`
+}
+
+// claim is a statement about an item
+type claim struct {
+	Rank     string `json:"rank"`
+	MainSnak struct {
+		SnakType  string `json:"snaktype"`
+		DataType  string `json:"datatype"`
+		DataValue struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"datavalue"`
+	} `json:"mainsnak"`
+}
+
+// facts returns the statements on the Wikidata item for an article's subject
+func (t *WikipediaTool) facts(logger *logrus.Logger, cache *sync.Map, req request) (string, error) {
+	id := req.wikidataID
+	if id == "" {
+		summary, err := t.fetchSummary(logger, cache, req)
+		if err != nil {
+			return "", err
+		}
+		if summary.WikibaseItem == "" {
+			return "", fmt.Errorf("%s has no Wikidata item", summary.Title)
+		}
+		id = summary.WikibaseItem
+	}
+
+	var response entityResponse
+	err := t.fetchJSON(logger, cache, wikidataURL+"/wiki/Special:EntityData/"+id+".json", &response)
+	if isNotFound(err) {
+		return "", fmt.Errorf("no Wikidata item %s", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Wikidata item %s: %w", id, err)
+	}
+	// A merged item is returned under the id it was merged into
+	var item entity
+	for _, e := range response.Entities {
+		item = e
+	}
+	if item.ID == "" {
+		return "", fmt.Errorf("no Wikidata item %s", id)
+	}
+
+	properties := make([]string, 0, len(item.Claims))
+	identifiers := 0
+	for property, claims := range item.Claims {
+		if len(claims) > 0 && claims[0].MainSnak.DataType == "external-id" && !req.identifiers {
+			identifiers++
+			continue
+		}
+		properties = append(properties, property)
+	}
+	slices.SortFunc(properties, func(a, b string) int { return idNumber(a) - idNumber(b) })
+
+	// Properties, and items and units given as values, are shown by their labels
+	ids := slices.Clone(properties)
+	for _, property := range properties {
+		for _, c := range rankedClaims(item.Claims[property]) {
+			if ref := entityRef(c); ref != "" && !slices.Contains(ids, ref) {
+				ids = append(ids, ref)
+			}
+		}
+	}
+	labels, err := t.labels(logger, cache, ids, req.language)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s)\n\n", inLanguage(item.Labels, req.language, item.ID), item.ID)
+	if description := inLanguage(item.Descriptions, req.language, ""); description != "" {
+		fmt.Fprintf(&b, "_%s_\n\n", description)
+	}
+	fmt.Fprintf(&b, "Source: %s/wiki/%s\n\n", wikidataURL, item.ID)
+
+	for _, property := range properties {
+		var values []string
+		for _, c := range rankedClaims(item.Claims[property]) {
+			if value := formatClaim(c, labels); value != "" && !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		more := ""
+		if len(values) > maxValuesPerProperty {
+			more = fmt.Sprintf(" and %d more", len(values)-maxValuesPerProperty)
+			values = values[:maxValuesPerProperty]
+		}
+		fmt.Fprintf(&b, "- **%s** (%s): %s%s\n", labelOr(labels, property), property, strings.Join(values, "; "), more)
+	}
+	if identifiers > 0 {
+		fmt.Fprintf(&b, "\n%d identifiers in other databases left out; set include_identifiers to list them.\n", identifiers)
+	}
+	return truncate(b.String(), req.maxLength, "Raise max_length to see more"), nil
+}
+
+// labels returns the labels of items and properties in a language, falling back to English
+func (t *WikipediaTool) labels(logger *logrus.Logger, cache *sync.Map, ids []string, language string) (map[string]string, error) {
+	labels := make(map[string]string, len(ids))
+	languages := language
+	if language != defaultLanguage {
+		languages += "|" + defaultLanguage
+	}
+	for batch := range slices.Chunk(ids, maxLabelIDs) {
+		params := url.Values{
+			"action":           {"wbgetentities"},
+			"ids":              {strings.Join(batch, "|")},
+			"props":            {"labels"},
+			"languages":        {languages},
+			"languagefallback": {"1"},
+			"format":           {"json"},
+		}
+		var response entityResponse
+		if err := t.fetchJSON(logger, cache, wikidataURL+"/w/api.php?"+params.Encode(), &response); err != nil {
+			return nil, fmt.Errorf("failed to fetch Wikidata labels: %w", err)
+		}
+		for id, e := range response.Entities {
+			if label := inLanguage(e.Labels, language, ""); label != "" {
+				labels[id] = label
+			}
+		}
+	}
+	return labels, nil
+}
+
+// rankedClaims returns an item's preferred statements for a property, or its normal ones when none are
+// preferred. Deprecated statements, which record values known to be wrong, are left out.
+func rankedClaims(claims []claim) []claim {
+	var preferred, normal []claim
+	for _, c := range claims {
+		switch c.Rank {
+		case "preferred":
+			preferred = append(preferred, c)
+		case "normal":
+			normal = append(normal, c)
+		}
+	}
+	if len(preferred) > 0 {
+		return preferred
+	}
+	return normal
+}
+
+// entityRef returns the id of the item a statement refers to, as its value or its value's unit
+func entityRef(c claim) string {
+	if c.MainSnak.SnakType != "value" {
+		return ""
+	}
+	switch c.MainSnak.DataValue.Type {
+	case "wikibase-entityid":
+		var v struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(c.MainSnak.DataValue.Value, &v)
+		return v.ID
+	case "quantity":
+		var v struct {
+			Unit string `json:"unit"`
+		}
+		_ = json.Unmarshal(c.MainSnak.DataValue.Value, &v)
+		if unit := strings.TrimPrefix(v.Unit, "http://www.wikidata.org/entity/"); unit != v.Unit {
+			return unit
+		}
+	}
+	return ""
+}
+
+// formatClaim returns a statement's value as text
+func formatClaim(c claim, labels map[string]string) string {
+	switch c.MainSnak.SnakType {
+	case "somevalue":
+		return "unknown"
+	case "novalue":
+		return "none"
+	}
+
+	raw := c.MainSnak.DataValue.Value
+	switch c.MainSnak.DataValue.Type {
+	case "string":
+		var v string
+		_ = json.Unmarshal(raw, &v)
+		return v
+	case "wikibase-entityid":
+		id := entityRef(c)
+		if label, ok := labels[id]; ok {
+			return fmt.Sprintf("%s (%s)", label, id)
+		}
+		return id
+	case "monolingualtext":
+		var v struct {
+			Text     string `json:"text"`
+			Language string `json:"language"`
+		}
+		_ = json.Unmarshal(raw, &v)
+		return fmt.Sprintf("%s (%s)", v.Text, v.Language)
+	case "quantity":
+		var v struct {
+			Amount string `json:"amount"`
+		}
+		_ = json.Unmarshal(raw, &v)
+		amount := strings.TrimPrefix(v.Amount, "+")
+		if unit := entityRef(c); unit != "" {
+			return amount + " " + labelOr(labels, unit)
+		}
+		return amount
+	case "time":
+		var v struct {
+			Time      string `json:"time"`
+			Precision int    `json:"precision"`
+		}
+		_ = json.Unmarshal(raw, &v)
+		return formatTime(v.Time, v.Precision)
+	case "globecoordinate":
+		var v struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		}
+		_ = json.Unmarshal(raw, &v)
+		return fmt.Sprintf("%.5f, %.5f", v.Latitude, v.Longitude)
+	}
+	return ""
+}
+
+// formatTime formats a Wikidata time, such as +1952-03-11T00:00:00Z, to its precision: 11 for a day,
+// 10 for a month, 9 for a year, 8 for a decade and less for a century or more
+func formatTime(value string, precision int) string {
+	bce := strings.HasPrefix(value, "-")
+	date, _, _ := strings.Cut(strings.TrimLeft(value, "+-"), "T")
+	parts := strings.Split(date, "-")
+	if len(parts) != 3 {
+		return value
+	}
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return value
+	}
+
+	var formatted string
+	switch {
+	case precision >= 11:
+		formatted = fmt.Sprintf("%d-%s-%s", year, parts[1], parts[2])
+	case precision == 10:
+		formatted = fmt.Sprintf("%d-%s", year, parts[1])
+	case precision == 9:
+		formatted = strconv.Itoa(year)
+	case precision == 8:
+		formatted = fmt.Sprintf("%ds", year/10*10)
+	default:
+		formatted = fmt.Sprintf("c. %d", year)
+	}
+	if bce {
+		formatted += " BCE"
+	}
+	return formatted
+}
+
+// inLanguage returns a label or description in a language, falling back to English and then to fallback
+func inLanguage(values map[string]languageVal, language, fallback string) string {
+	if v, ok := values[language]; ok && v.Value != "" {
+		return v.Value
+	}
+	if v, ok := values[defaultLanguage]; ok && v.Value != "" {
+		return v.Value
+	}
+	return fallback
+}
+
+// labelOr returns an id's label, or the id when it has none
+func labelOr(labels map[string]string, id string) string {
+	if label, ok := labels[id]; ok {
+		return label
+	}
+	return id
+}
+
+// idNumber returns the number in an id such as P31, for sorting properties in the order they were created
+func idNumber(id string) int {
+	n, _ := strconv.Atoi(id[1:])
+	return n
+}
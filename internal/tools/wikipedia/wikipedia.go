@@ -0,0 +1,292 @@
+package wikipedia
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/packageversions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cacheTTL         = 1 * time.Hour
+	defaultLanguage  = "en"
+	defaultMaxLength = 20000
+	minMaxLength     = 500
+	maxMaxLength     = 200000
+	defaultLimit     = 5
+	maxLimit         = 20
+	actionSummary    = "summary"
+	actionArticle    = "article"
+	actionFacts      = "facts"
+	actionSearch     = "search"
+)
+
+// languagePattern matches Wikipedia language editions such as en, de, zh-yue, simple and be-tarask
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$|^simple$`)
+
+// wikidataIDPattern matches Wikidata item ids such as Q42
+var wikidataIDPattern = regexp.MustCompile(`^Q[1-9][0-9]*$`)
+
+// WikipediaTool looks up Wikipedia articles and Wikidata facts
+type WikipediaTool struct {
+	client packageversions.HTTPClient
+}
+
+// request is a lookup in one language edition
+type request struct {
+	title       string
+	query       string
+	language    string
+	section     string
+	wikidataID  string
+	identifiers bool
+	limit       int
+	maxLength   int
+}
+
+// init registers the Wikipedia tool
+func init() {
+	registry.Register(&WikipediaTool{})
+}
+
+// NewWikipediaTool creates a Wikipedia tool that uses the given HTTP client
+func NewWikipediaTool(client packageversions.HTTPClient) *WikipediaTool {
+	return &WikipediaTool{client: client}
+}
+
+// httpClient returns the configured client, defaulting to the shared rate-limited client
+func (t *WikipediaTool) httpClient() packageversions.HTTPClient {
+	if t.client == nil {
+		return packageversions.DefaultHTTPClient
+	}
+	return t.client
+}
+
+// Definition returns the tool's definition for MCP registration
+func (t *WikipediaTool) Definition() mcp.Tool {
+	return mcp.NewTool(
+		"wikipedia",
+		mcp.WithDescription(`Look up encyclopaedic information from Wikipedia and Wikidata.
+
+Actions:
+- summary: an article's lead paragraph, description and Wikidata id
+- article: the full article, or one section of it, as markdown
+- facts: structured facts about the article's subject from Wikidata, such as dates, places and figures
+- search: find article titles matching a query
+
+Cheaper and more reliable than fetching Wikipedia pages, which are mostly navigation and references.`),
+		mcp.WithString("action",
+			mcp.Description("What to return (default: summary)"),
+			mcp.Enum(actionSummary, actionArticle, actionFacts, actionSearch),
+		),
+		mcp.WithString("title",
+			mcp.Description("Article title, e.g. Alan Turing or Go (programming language). Redirects are followed. Required for summary and article"),
+		),
+		mcp.WithString("query",
+			mcp.Description("Words to search articles for. Required for search"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Wikipedia language edition, e.g. en, de, ja, simple (default: en). Also the language of Wikidata labels"),
+		),
+		mcp.WithString("section",
+			mcp.Description("For article, only return the section with this heading, e.g. History"),
+		),
+		mcp.WithString("wikidata_id",
+			mcp.Description("For facts, a Wikidata item id such as Q42, instead of a title"),
+		),
+		mcp.WithBoolean("include_identifiers",
+			mcp.Description("For facts, include identifiers in other databases, such as VIAF or IMDb ids (default: false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("For search, results to return (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithNumber("max_length",
+			mcp.Description(fmt.Sprintf("Maximum characters to return for article and facts (default: %d, max: %d)", defaultMaxLength, maxMaxLength)),
+		),
+		// Tool annotations
+		mcp.WithReadOnlyHintAnnotation(true),     // Only reads public articles and data
+		mcp.WithDestructiveHintAnnotation(false), // No side effects
+		mcp.WithIdempotentHintAnnotation(true),   // Same lookup gives the same result until the article is edited
+		mcp.WithOpenWorldHintAnnotation(true),    // Calls the Wikipedia and Wikidata APIs
+	)
+}
+
+// Execute runs the requested lookup
+func (t *WikipediaTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	action := actionSummary
+	if a, ok := args["action"].(string); ok && a != "" {
+		action = a
+	}
+
+	req := request{language: defaultLanguage, maxLength: defaultMaxLength, limit: defaultLimit}
+	req.title, _ = args["title"].(string)
+	req.title = strings.TrimSpace(req.title)
+	req.query, _ = args["query"].(string)
+	req.query = strings.TrimSpace(req.query)
+	req.section, _ = args["section"].(string)
+	req.section = strings.TrimSpace(req.section)
+	req.identifiers, _ = args["include_identifiers"].(bool)
+
+	if l, ok := args["language"].(string); ok && strings.TrimSpace(l) != "" {
+		req.language = strings.ToLower(strings.TrimSpace(l))
+		if !languagePattern.MatchString(req.language) {
+			return nil, fmt.Errorf("invalid language: %s (use a Wikipedia language code such as en, de or zh-yue)", req.language)
+		}
+	}
+	if id, ok := args["wikidata_id"].(string); ok && strings.TrimSpace(id) != "" {
+		req.wikidataID = strings.ToUpper(strings.TrimSpace(id))
+		if !wikidataIDPattern.MatchString(req.wikidataID) {
+			return nil, fmt.Errorf("invalid wikidata_id: %s (expected an item id such as Q42)", req.wikidataID)
+		}
+	}
+	if v, ok := args["max_length"].(float64); ok {
+		if v < minMaxLength || v > maxMaxLength {
+			return nil, fmt.Errorf("max_length must be between %d and %d", minMaxLength, maxMaxLength)
+		}
+		req.maxLength = int(v)
+	}
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > maxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		req.limit = int(v)
+	}
+
+	switch action {
+	case actionSummary, actionArticle:
+		if req.title == "" {
+			return nil, fmt.Errorf("missing required parameter: title")
+		}
+	case actionFacts:
+		if req.title == "" && req.wikidataID == "" {
+			return nil, fmt.Errorf("missing required parameter: title (or wikidata_id)")
+		}
+	case actionSearch:
+		if req.query == "" {
+			req.query = req.title
+		}
+		if req.query == "" {
+			return nil, fmt.Errorf("missing required parameter: query")
+		}
+	default:
+		return nil, fmt.Errorf("action must be one of: summary, article, facts, search")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"action":   action,
+		"title":    req.title,
+		"language": req.language,
+	}).Debug("Looking up Wikipedia")
+
+	var output string
+	var err error
+	switch action {
+	case actionSummary:
+		output, err = t.summary(logger, cache, req)
+	case actionArticle:
+		output, err = t.article(logger, cache, req)
+	case actionFacts:
+		output, err = t.facts(logger, cache, req)
+	case actionSearch:
+		output, err = t.search(logger, cache, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Articles are written by the public, so scan them before returning them
+	sourceCtx := security.SourceContext{
+		Tool:        "wikipedia",
+		URL:         siteURL(req.language),
+		ContentType: "text",
+	}
+	if analysis, err := security.AnalyseContent(output, sourceCtx); err == nil {
+		switch analysis.Action {
+		case security.ActionBlock:
+			return nil, security.FormatSecurityBlockErrorFromResult(analysis)
+		case security.ActionWarn:
+			output = security.FormatSecurityWarningPrefix(analysis) + output
+		}
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// ProvideExtendedInfo provides detailed usage information for the Wikipedia tool
+func (t *WikipediaTool) ProvideExtendedInfo() *tools.ExtendedHelp {
+	return &tools.ExtendedHelp{
+		Examples: []tools.ToolExample{
+			{
+				Description: "Get a short summary of a subject",
+				Arguments: map[string]any{
+					"title": "Kubernetes",
+				},
+				ExpectedResult: "The article's description and lead paragraph, with its link and Wikidata id",
+			},
+			{
+				Description: "Read one section of an article in another language",
+				Arguments: map[string]any{
+					"action":   "article",
+					"title":    "Deutsche Bahn",
+					"section":  "Geschichte",
+					"language": "de",
+				},
+				ExpectedResult: "The History section of the German article as markdown",
+			},
+			{
+				Description: "Get structured facts about a city",
+				Arguments: map[string]any{
+					"action": "facts",
+					"title":  "Wellington",
+				},
+				ExpectedResult: "Wikidata facts such as country, population, coordinates and time zone, with their values' item ids",
+			},
+			{
+				Description: "Find the right article title",
+				Arguments: map[string]any{
+					"action": "search",
+					"query":  "raft consensus algorithm",
+				},
+				ExpectedResult: "Matching article titles with their descriptions and excerpts",
+			},
+		},
+		CommonPatterns: []string{
+			"Start with summary; read the article only when the lead paragraph is not enough",
+			"Use search first when the exact title is unknown or the summary is a disambiguation page",
+			"Use facts for dates, figures and relationships rather than parsing them out of article text",
+			"When an article is truncated, ask for one of the sections listed at the end",
+		},
+		Troubleshooting: []tools.TroubleshootingTip{
+			{
+				Problem:  "No article with that title",
+				Solution: "Titles are case-sensitive after the first letter and must match the article's name. Use action search to find it.",
+			},
+			{
+				Problem:  "The summary is a disambiguation page",
+				Solution: "The title refers to several subjects. Use a more specific title, such as Mercury (planet), or search.",
+			},
+			{
+				Problem:  "Section not found",
+				Solution: "The error lists the article's section headings; headings are in the article's language.",
+			},
+		},
+		ParameterDetails: map[string]string{
+			"action":              "summary is the cheapest. article returns markdown without references, navigation boxes or images. facts reads the subject's Wikidata item.",
+			"language":            "Any Wikipedia language edition. Labels in facts use this language, falling back to English.",
+			"section":             "Matched against the article's headings, ignoring case. Subsections are included.",
+			"wikidata_id":         "Skips the article lookup for facts. Useful for subjects without an article in the chosen language.",
+			"include_identifiers": "Wikidata items often have dozens of ids in other databases; they are left out unless asked for.",
+		},
+		WhenToUse:    "Use for encyclopaedic background: what something is, its history, and facts such as dates, locations and figures.",
+		WhenNotToUse: "Don't use for current events or fast-changing data (use internet_search), or for technical reference documentation (use docs_lookup).",
+	}
+}
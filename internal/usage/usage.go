@@ -0,0 +1,270 @@
+// Package usage counts the calls made to external APIs that consume a paid or rate limited quota,
+// such as Brave Search or the GitHub API, and refuses or warns about calls once a daily cap set with
+// USAGE_LIMIT_<PROVIDER> is reached, so a shared server does not use up a team's quota. Counts are
+// kept in ~/.mcp-devtools/usage.json so that they survive restarts and are shared by every server
+// process of the user.
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LimitEnvVarPrefix is followed by a provider's name in upper case to set its daily cap
+	LimitEnvVarPrefix = "USAGE_LIMIT_"
+
+	// ModeEnvVar sets what happens when a cap is reached: "block" (default) or "warn"
+	ModeEnvVar = "USAGE_LIMIT_MODE"
+
+	// WarnPercentEnvVar overrides defaultWarnPercent
+	WarnPercentEnvVar = "USAGE_WARN_PERCENT"
+
+	// defaultWarnPercent is the share of a cap, in percent, after which a warning is logged
+	defaultWarnPercent = 80
+
+	// keepDays is how many days of counts are kept
+	keepDays = 30
+
+	// dayFormat keys the counts of each day, which start at midnight UTC
+	dayFormat = "2006-01-02"
+
+	// lockTimeout bounds how long a call waits for another process to finish updating usage.json
+	lockTimeout = 2 * time.Second
+
+	// lockRetryDelay is how often a busy usage.json lock is retried
+	lockRetryDelay = 20 * time.Millisecond
+)
+
+// Provider is an external API whose calls are counted
+type Provider struct {
+	Name        string
+	Description string
+	hosts       []string
+}
+
+// Providers are the metered APIs. Calls to their hosts through the shared HTTP client are counted
+// automatically; providers without hosts are counted by the tools that call them.
+var Providers = []Provider{
+	{Name: "alphavantage", Description: "Alpha Vantage market data API", hosts: []string{"www.alphavantage.co"}},
+	{Name: "brave", Description: "Brave Search API", hosts: []string{"api.search.brave.com"}},
+	{Name: "gemini", Description: "Gemini CLI runs by the gemini-agent tool"},
+	{Name: "github", Description: "GitHub REST API", hosts: []string{"api.github.com", "uploads.github.com"}},
+	{Name: "osv", Description: "OSV vulnerability database API", hosts: []string{"api.osv.dev"}},
+	{Name: "stackexchange", Description: "Stack Exchange API", hosts: []string{"api.stackexchange.com"}},
+}
+
+// ErrLimitReached is returned, wrapped, for a call refused because its provider's cap is reached
+var ErrLimitReached = errors.New("daily usage limit reached")
+
+// store is the content of usage.json
+type store struct {
+	Days map[string]map[string]int `json:"days"`
+}
+
+var (
+	// mu serialises reads and writes of usage.json within the process, the file lock taken by Consume
+	// serialises updates across processes
+	mu sync.Mutex
+	// warned records the day of each warning given, so each is given once a day
+	warned = make(map[string]string)
+)
+
+func init() {
+	// Count the calls every client built by the shared HTTP client factory makes to metered APIs
+	httpclient.SetUsageMeter(meter{})
+}
+
+// meter counts requests made through the shared HTTP client
+type meter struct{}
+
+func (meter) Consume(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, provider := range Providers {
+		if slices.Contains(provider.hosts, host) {
+			return Consume(provider.Name)
+		}
+	}
+	return nil
+}
+
+// Path returns the path of usage.json
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "usage.json"), nil
+}
+
+// Limit returns a provider's daily cap, 0 when it has none
+func Limit(provider string) int {
+	limit, err := strconv.Atoi(os.Getenv(LimitEnvVarPrefix + strings.ToUpper(provider)))
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// Blocking reports whether calls over a cap are refused rather than only warned about
+func Blocking() bool {
+	return !strings.EqualFold(strings.TrimSpace(os.Getenv(ModeEnvVar)), "warn")
+}
+
+// WarnPercent returns the share of a cap, in percent, after which a provider is nearly used up
+func WarnPercent() int {
+	if percent, err := strconv.Atoi(os.Getenv(WarnPercentEnvVar)); err == nil && percent > 0 && percent <= 100 {
+		return percent
+	}
+	return defaultWarnPercent
+}
+
+// Consume counts a call to a provider. When the provider's cap is reached it returns an error
+// wrapping ErrLimitReached without counting the call, unless USAGE_LIMIT_MODE is warn. The count is
+// updated under a file lock, so calls from other server processes of the user are not lost. Failing
+// to lock or save the count does not stop the call.
+func Consume(provider string) error {
+	now := time.Now().UTC()
+	today := now.Format(dayFormat)
+	limit := Limit(provider)
+
+	mu.Lock()
+	defer mu.Unlock()
+	path, err := Path()
+	if err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil
+	}
+	lock := flock.New(path + ".lock")
+	lockCtx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	if locked, err := lock.TryLockContext(lockCtx, lockRetryDelay); err != nil || !locked {
+		logrus.WithError(err).Debug("Failed to lock API usage, the call is not counted")
+		return nil
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data := load(path)
+	counts := data.Days[today]
+	if counts == nil {
+		counts = make(map[string]int)
+		data.Days[today] = counts
+	}
+
+	if limit > 0 && counts[provider] >= limit {
+		if Blocking() {
+			resetIn := ResetTime(now).Sub(now).Round(time.Minute)
+			return fmt.Errorf("%w: the %s cap of %d calls a day is used up, it resets in %s. The server's %s%s sets the cap",
+				ErrLimitReached, provider, limit, resetIn, LimitEnvVarPrefix, strings.ToUpper(provider))
+		}
+		warn(provider+" over", provider, today, fmt.Sprintf("Usage of %s is over its cap of %d calls a day", provider, limit))
+	} else if limit > 0 && (counts[provider]+1)*100 >= limit*WarnPercent() {
+		warn(provider+" nearly", provider, today, fmt.Sprintf("Usage of %s has reached %d of its cap of %d calls a day", provider, counts[provider]+1, limit))
+	}
+
+	counts[provider]++
+	if err := save(path, data, now); err != nil {
+		logrus.WithError(err).Debug("Failed to save API usage")
+	}
+	return nil
+}
+
+// warn logs a warning about a provider's usage, once a day for each key
+func warn(key, provider, today, message string) {
+	if warned[key] == today {
+		return
+	}
+	warned[key] = today
+	logrus.WithField("provider", provider).Warn(message)
+}
+
+// Counts returns the calls counted for each provider on each of the last days days, keyed by
+// day, e.g. "2026-10-18"
+func Counts(days int) (map[string]map[string]int, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	data := load(path)
+	mu.Unlock()
+
+	result := make(map[string]map[string]int)
+	now := time.Now().UTC()
+	for i := range days {
+		day := now.AddDate(0, 0, -i).Format(dayFormat)
+		result[day] = maps.Clone(data.Days[day])
+		if result[day] == nil {
+			result[day] = make(map[string]int)
+		}
+	}
+	return result, nil
+}
+
+// ResetTime returns when the counts of the day of t reset, at the next midnight UTC
+func ResetTime(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// load reads usage.json, returning empty counts when it is missing or cannot be read. Caller must
+// hold mu.
+func load(path string) *store {
+	data := &store{}
+	if raw, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(raw, data)
+	}
+	if data.Days == nil {
+		data.Days = make(map[string]map[string]int)
+	}
+	return data
+}
+
+// save writes usage.json, dropping days older than keepDays. It is replaced in one step so other
+// processes never read a partly written file. Caller must hold mu.
+func save(path string, data *store, now time.Time) error {
+	oldest := now.AddDate(0, 0, -keepDays).Format(dayFormat)
+	for day := range data.Days {
+		if day < oldest {
+			delete(data.Days, day)
+		}
+	}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), ".usage-*.json")
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(raw)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+// Package cron parses standard five-field cron expressions, finds when they next run and describes
+// them in English. It is shared by the time tool and the scheduler.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the @ shorthands cron accepts for common schedules
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes one of the five fields of a cron expression
+type cronField struct {
+	name  string
+	unit  string
+	min   int
+	max   int
+	names []string
+}
+
+// cronFields are the fields of a cron expression in order
+var cronFields = []cronField{
+	{name: "minute", unit: "minute", min: 0, max: 59},
+	{name: "hour", unit: "hour", min: 0, max: 23},
+	{name: "day-of-month", unit: "day", min: 1, max: 31},
+	{name: "month", unit: "month", min: 1, max: 12, names: []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}},
+	{name: "day-of-week", unit: "day", min: 0, max: 6, names: []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}},
+}
+
+// Schedule is a parsed five-field cron expression
+type Schedule struct {
+	// fields are the expression's fields as written, after expanding macros
+	fields [5]string
+	// allowed holds a bit for each value each field matches
+	allowed [5]uint64
+	// domStar and dowStar record whether the day fields are unrestricted, which changes how they combine
+	domStar bool
+	dowStar bool
+}
+
+// Parse parses a standard five-field cron expression or an @ macro such as @daily
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+
+	parts := strings.Fields(expression)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields (minute hour day-of-month month day-of-week) or be a macro such as @daily, got %d fields. Schedulers with a seconds field, such as Quartz, are not supported", len(parts))
+	}
+
+	schedule := &Schedule{}
+	for i, part := range parts {
+		allowed, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		schedule.fields[i] = part
+		schedule.allowed[i] = allowed
+	}
+	schedule.domStar = strings.HasPrefix(parts[2], "*")
+	schedule.dowStar = strings.HasPrefix(parts[4], "*")
+	return schedule, nil
+}
+
+// parseCronField parses one field, e.g. */15, 1-5 or MON,WED,FRI, into a bit for each matching value
+func parseCronField(text string, field cronField) (uint64, error) {
+	var allowed uint64
+	for part := range strings.SplitSeq(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step '%s' in %s field '%s'", stepText, field.name, text)
+			}
+			step = n
+		}
+
+		low, high := field.min, field.max
+		switch {
+		case rangeText == "*":
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = parseCronValue(lowText, field); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highText, field); err != nil {
+				return 0, err
+			}
+			if high < low {
+				return 0, fmt.Errorf("range '%s' in %s field runs backwards", rangeText, field.name)
+			}
+		default:
+			value, err := parseCronValue(rangeText, field)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			// A single value with a step, e.g. 5/15, runs from that value to the end of the range
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			allowed |= 1 << value
+		}
+	}
+	return allowed, nil
+}
+
+// parseCronValue parses a number or, for months and days of the week, a three-letter name
+func parseCronValue(text string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if name != "" && strings.EqualFold(text, name[:3]) {
+			return i, nil
+		}
+	}
+	value, err := strconv.Atoi(text)
+	// Day of week 7 is Sunday, as in most cron implementations
+	if err == nil && field.name == "day-of-week" && value == 7 {
+		return 0, nil
+	}
+	if err != nil || value < field.min || value > field.max {
+		return 0, fmt.Errorf("invalid %s '%s': must be %d-%d", field.name, text, field.min, field.max)
+	}
+	return value, nil
+}
+
+// matches reports whether a field allows a value
+func (s *Schedule) matches(field, value int) bool {
+	return s.allowed[field]&(1<<value) != 0
+}
+
+// dayMatches reports whether the schedule runs on a date. When both day fields are restricted, cron
+// runs on days matching either.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.matches(2, t.Day())
+	dow := s.matches(4, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time after t that the schedule runs, in t's location, or false if it does not
+// run within the next five years
+func (s *Schedule) Next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !s.matches(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.matches(1, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.matches(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Fields returns the expression's five fields, with any macro such as @daily expanded
+func (s *Schedule) Fields() string {
+	return strings.Join(s.fields[:], " ")
+}
+
+// Explain describes the schedule in English, e.g. "At 09:30 on Monday through Friday"
+func (s *Schedule) Explain() string {
+	minute, hour := s.fields[0], s.fields[1]
+
+	var when string
+	switch {
+	case minute == "*" && hour == "*":
+		when = "Every minute"
+	case strings.HasPrefix(minute, "*/") && hour == "*":
+		when = fmt.Sprintf("Every %s minutes", strings.TrimPrefix(minute, "*/"))
+	case isSingleValue(minute) && hour == "*":
+		when = fmt.Sprintf("At minute %s past every hour", minute)
+	case isSingleValue(minute) && allSingleValues(hour):
+		var times []string
+		m, _ := strconv.Atoi(minute)
+		for h := range strings.SplitSeq(hour, ",") {
+			hourValue, _ := strconv.Atoi(h)
+			times = append(times, fmt.Sprintf("%02d:%02d", hourValue, m))
+		}
+		when = "At " + joinEnglish(times)
+	default:
+		hourText := describeCronField(hour, cronFields[1])
+		if !strings.HasPrefix(hourText, "every") {
+			hourText = "hour " + hourText
+		}
+		when = fmt.Sprintf("At minute %s past %s", describeCronField(minute, cronFields[0]), hourText)
+	}
+
+	var days []string
+	if !s.domStar {
+		days = append(days, "on day-of-month "+describeCronField(s.fields[2], cronFields[2]))
+	}
+	if !s.dowStar {
+		days = append(days, "on "+describeCronField(s.fields[4], cronFields[4]))
+	}
+	if len(days) > 0 {
+		when += " " + strings.Join(days, " or ")
+	}
+	if s.fields[3] != "*" {
+		when += " in " + describeCronField(s.fields[3], cronFields[3])
+	}
+	return when
+}
+
+// describeCronField describes a field in English, e.g. "Monday through Friday" or "every 2 hours"
+func describeCronField(text string, field cronField) string {
+	var parts []string
+	for part := range strings.SplitSeq(text, ",") {
+		rangeText, step, hasStep := strings.Cut(part, "/")
+		var rangeDesc string
+		switch {
+		case rangeText == "*":
+			rangeDesc = ""
+		case strings.Contains(rangeText, "-"):
+			low, high, _ := strings.Cut(rangeText, "-")
+			rangeDesc = cronValueName(low, field) + " through " + cronValueName(high, field)
+		default:
+			rangeDesc = cronValueName(rangeText, field)
+			if hasStep {
+				rangeDesc = "from " + rangeDesc
+			}
+		}
+
+		switch {
+		case hasStep && rangeDesc == "":
+			parts = append(parts, fmt.Sprintf("every %s %ss", step, field.unit))
+		case hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss %s", step, field.unit, rangeDesc))
+		case rangeDesc == "":
+			parts = append(parts, "every "+field.unit)
+		default:
+			parts = append(parts, rangeDesc)
+		}
+	}
+	return joinEnglish(parts)
+}
+
+// cronValueName returns the name of a month or day of the week, or the value itself for other fields
+func cronValueName(text string, field cronField) string {
+	if field.names == nil {
+		return text
+	}
+	if value, err := parseCronValue(text, field); err == nil {
+		return field.names[value]
+	}
+	return text
+}
+
+// isSingleValue reports whether a field is a single number
+func isSingleValue(text string) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil
+}
+
+// allSingleValues reports whether a field is a list of single numbers
+func allSingleValues(text string) bool {
+	for part := range strings.SplitSeq(text, ",") {
+		if !isSingleValue(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// joinEnglish joins items as an English list, e.g. "a, b and c"
+func joinEnglish(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
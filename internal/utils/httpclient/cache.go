@@ -0,0 +1,464 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheMaxMBEnvVar overrides DefaultCacheMaxMB, with 0 disabling the response cache
+	CacheMaxMBEnvVar = "HTTP_CACHE_MAX_MB"
+
+	// DefaultCacheMaxMB is the most disk space, in MB, the response cache may use
+	DefaultCacheMaxMB = 256
+
+	// cacheEntryFraction limits a single cached response to this fraction of the cache size
+	cacheEntryFraction = 10
+
+	// cacheMaxHeuristicFreshness caps how long a response without explicit freshness information is
+	// reused without revalidating it, based on how long ago it was last modified
+	cacheMaxHeuristicFreshness = time.Hour
+
+	// cacheHitHeader is set on responses served from the cache
+	cacheHitHeader = "X-From-Cache"
+)
+
+// cacheKeyHeaders are the request headers that select between different representations of a URL
+var cacheKeyHeaders = []string{"Accept", "Accept-Encoding", "Accept-Language"}
+
+// cacheMu serialises writes and eviction across clients
+var cacheMu sync.Mutex
+
+// cacheEntry is the metadata stored alongside a cached response body
+type cacheEntry struct {
+	URL      string            `json:"url"`
+	Host     string            `json:"host"`
+	Status   int               `json:"status"`
+	Header   http.Header       `json:"header"`
+	Vary     map[string]string `json:"vary,omitempty"`
+	StoredAt time.Time         `json:"stored_at"`
+}
+
+// CacheDir returns the directory the response cache is stored in
+func CacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".mcp-devtools", "cache", "http"), nil
+}
+
+// ClearCache removes cached responses, only those from host when it is not empty, and returns how many
+// were removed and the disk space freed
+func ClearCache(host string) (int, int64, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	removed, freed := 0, int64(0)
+	for _, file := range entries {
+		key, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if host != "" {
+			entry, err := readCacheEntry(dir, key)
+			if err != nil || !strings.EqualFold(entry.Host, host) {
+				continue
+			}
+		}
+		freed += removeCacheEntry(dir, key)
+		removed++
+	}
+	return removed, freed, nil
+}
+
+// cacheTransport serves GET responses from an on-disk cache, revalidating them with the server using
+// their ETag and Last-Modified validators once they are no longer fresh
+type cacheTransport struct {
+	next     http.RoundTripper
+	dir      string
+	maxBytes int64
+}
+
+// newCacheTransport wraps next with the response cache, or returns next if the cache is disabled
+func newCacheTransport(next http.RoundTripper) http.RoundTripper {
+	maxMB := envInt(CacheMaxMBEnvVar, DefaultCacheMaxMB)
+	if maxMB == 0 {
+		return next
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		return next
+	}
+	return &cacheTransport{next: next, dir: dir, maxBytes: int64(maxMB) * 1024 * 1024}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isCacheableRequest(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	entry, err := readCacheEntry(t.dir, key)
+	if err != nil || !entry.matches(req) {
+		entry = nil
+	}
+
+	if entry != nil && entry.isFresh(time.Now()) && !requestDirective(req, "no-cache") {
+		if resp, err := t.cachedResponse(req, key, entry); err == nil {
+			return resp, nil
+		}
+		entry = nil
+	}
+
+	outgoing := req
+	if entry != nil {
+		outgoing = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		// The 304 carries the response's updated freshness information and validators
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if value := resp.Header.Get(name); value != "" {
+				entry.Header.Set(name, value)
+			}
+		}
+		entry.StoredAt = time.Now()
+		t.writeEntry(key, entry, nil)
+		return t.cachedResponse(req, key, entry)
+	}
+
+	if !isCacheableResponse(resp) {
+		if entry != nil {
+			cacheMu.Lock()
+			removeCacheEntry(t.dir, key)
+			cacheMu.Unlock()
+		}
+		return resp, nil
+	}
+	return t.store(req, key, resp)
+}
+
+// store saves resp to the cache if it is small enough, returning a response that reads the same body
+func (t *cacheTransport) store(req *http.Request, key string, resp *http.Response) (*http.Response, error) {
+	maxEntry := t.maxBytes / cacheEntryFraction
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEntry+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > maxEntry {
+		// Too large to cache, so pass it through with the part already read put back in front
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	entry := &cacheEntry{
+		URL:      req.URL.String(),
+		Host:     req.URL.Hostname(),
+		Status:   resp.StatusCode,
+		Header:   resp.Header.Clone(),
+		Vary:     varyValues(req, resp.Header),
+		StoredAt: time.Now(),
+	}
+	t.writeEntry(key, entry, body)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// cachedResponse builds a response to req from a cached entry
+func (t *cacheTransport) cachedResponse(req *http.Request, key string, entry *cacheEntry) (*http.Response, error) {
+	bodyPath := filepath.Join(t.dir, key+".body")
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil, err
+	}
+	// Mark the entry as recently used, so eviction removes the least recently used entries first
+	now := time.Now()
+	_ = os.Chtimes(bodyPath, now, now)
+
+	header := entry.Header.Clone()
+	header.Set(cacheHitHeader, "1")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// writeEntry saves an entry's metadata, and its body unless body is nil, then evicts the least
+// recently used entries while the cache is over its size limit
+func (t *cacheTransport) writeEntry(key string, entry *cacheEntry, body []byte) {
+	metadata, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return
+	}
+	if body != nil {
+		if err := writeFileAtomic(filepath.Join(t.dir, key+".body"), body); err != nil {
+			return
+		}
+	}
+	if err := writeFileAtomic(filepath.Join(t.dir, key+".json"), metadata); err != nil {
+		return
+	}
+	t.evictLocked()
+}
+
+// evictLocked removes the least recently used entries until the cache fits its size limit. Caller must
+// hold cacheMu.
+func (t *cacheTransport) evictLocked() {
+	files, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+
+	type usage struct {
+		key  string
+		used time.Time
+		size int64
+	}
+	var entries []usage
+	total := int64(0)
+	for _, file := range files {
+		key, ok := strings.CutSuffix(file.Name(), ".body")
+		if !ok {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, usage{key: key, used: info.ModTime(), size: info.Size()})
+		total += info.Size()
+	}
+	if total <= t.maxBytes {
+		return
+	}
+
+	slices.SortFunc(entries, func(a, b usage) int {
+		return a.used.Compare(b.used)
+	})
+	for _, entry := range entries {
+		if total <= t.maxBytes {
+			return
+		}
+		total -= removeCacheEntry(t.dir, entry.key)
+	}
+}
+
+// isFresh reports whether the entry can be served without revalidating it with the server
+func (e *cacheEntry) isFresh(now time.Time) bool {
+	cacheControl := e.Header.Get("Cache-Control")
+	if hasDirective(cacheControl, "no-cache") {
+		return false
+	}
+	age := now.Sub(e.StoredAt)
+
+	if maxAge, ok := directiveValue(cacheControl, "max-age"); ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil {
+			return age < time.Duration(seconds)*time.Second
+		}
+	}
+	if expires := e.Header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return false
+		}
+		date, err := http.ParseTime(e.Header.Get("Date"))
+		if err != nil {
+			date = e.StoredAt
+		}
+		return age < expiresAt.Sub(date)
+	}
+	if lastModified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+		// Heuristic freshness: a tenth of the time since the response last changed
+		return age < min(e.StoredAt.Sub(lastModified)/10, cacheMaxHeuristicFreshness)
+	}
+	return false
+}
+
+// matches reports whether the entry was stored for a request with the same values of the headers the
+// response varies on
+func (e *cacheEntry) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// isCacheableRequest reports whether req may be answered from, or stored in, the cache. Requests with
+// credentials are never cached, as their responses may be specific to one user.
+func isCacheableRequest(req *http.Request) bool {
+	return (req.Method == http.MethodGet || req.Method == "") &&
+		req.Header.Get("Authorization") == "" &&
+		req.Header.Get("Cookie") == "" &&
+		req.Header.Get("Range") == "" &&
+		!requestDirective(req, "no-store")
+}
+
+// isCacheableResponse reports whether resp may be stored in the cache
+func isCacheableResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	cacheControl := resp.Header.Get("Cache-Control")
+	if hasDirective(cacheControl, "no-store") || resp.Header.Get("Vary") == "*" || resp.Header.Get("Set-Cookie") != "" {
+		return false
+	}
+	_, hasMaxAge := directiveValue(cacheControl, "max-age")
+	return hasMaxAge ||
+		resp.Header.Get("ETag") != "" ||
+		resp.Header.Get("Last-Modified") != "" ||
+		resp.Header.Get("Expires") != ""
+}
+
+// cacheKey returns the name an entry for req is stored under
+func cacheKey(req *http.Request) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.URL.String()))
+	for _, name := range cacheKeyHeaders {
+		hash.Write([]byte("\n" + req.Header.Get(name)))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// varyValues returns the values req has for each header named in the response's Vary header
+func varyValues(req *http.Request, header http.Header) map[string]string {
+	values := map[string]string{}
+	for _, vary := range header.Values("Vary") {
+		for name := range strings.SplitSeq(vary, ",") {
+			if name = http.CanonicalHeaderKey(strings.TrimSpace(name)); name != "" {
+				values[name] = req.Header.Get(name)
+			}
+		}
+	}
+	return values
+}
+
+// requestDirective reports whether req's Cache-Control header contains directive
+func requestDirective(req *http.Request, directive string) bool {
+	return hasDirective(req.Header.Get("Cache-Control"), directive)
+}
+
+// hasDirective reports whether a Cache-Control header value contains directive
+func hasDirective(cacheControl, directive string) bool {
+	_, ok := directiveValue(cacheControl, directive)
+	return ok
+}
+
+// directiveValue returns the value of directive in a Cache-Control header value
+func directiveValue(cacheControl, directive string) (string, bool) {
+	for part := range strings.SplitSeq(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(name, directive) {
+			return strings.Trim(value, `"`), true
+		}
+	}
+	return "", false
+}
+
+// readCacheEntry reads the metadata of the entry stored under key
+func readCacheEntry(dir, key string) (*cacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// removeCacheEntry removes the entry stored under key, returning the disk space freed
+func removeCacheEntry(dir, key string) int64 {
+	freed := int64(0)
+	for _, suffix := range []string{".body", ".json"} {
+		path := filepath.Join(dir, key+suffix)
+		if info, err := os.Stat(path); err == nil {
+			freed += info.Size()
+		}
+		_ = os.Remove(path)
+	}
+	return freed
+}
+
+// writeFileAtomic writes data to path through a temporary file, so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
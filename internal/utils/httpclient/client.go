@@ -0,0 +1,372 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	// CABundleEnvVar names a PEM file of additional CA certificates to trust alongside the system roots
+	CABundleEnvVar = "HTTP_CA_BUNDLE"
+
+	// MaxRetriesEnvVar overrides DefaultMaxRetries
+	MaxRetriesEnvVar = "HTTP_MAX_RETRIES"
+
+	// MaxResponseMBEnvVar overrides DefaultMaxResponseMB
+	MaxResponseMBEnvVar = "HTTP_MAX_RESPONSE_MB"
+
+	// DefaultMaxRetries is how many times a safe request is retried after a transient failure
+	DefaultMaxRetries = 2
+
+	// DefaultMaxResponseMB is the largest response body, in MB, a client will read
+	DefaultMaxResponseMB = 100
+
+	// retryBaseDelay is the backoff before the first retry, doubled for each retry after it
+	retryBaseDelay = 500 * time.Millisecond
+
+	// retryMaxDelay is the longest a client will wait before retrying, including for Retry-After
+	retryMaxDelay = 30 * time.Second
+
+	// maxIdleConnsPerHost is the number of idle connections kept open to each host
+	maxIdleConnsPerHost = 10
+)
+
+// ErrResponseTooLarge is returned when reading a response body larger than the client's limit
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// Options configures a client created by New
+type Options struct {
+	// Timeout limits each request, including retries and reading the body. Zero means no limit.
+	Timeout time.Duration
+
+	// MaxRetries is how many times GET, HEAD and OPTIONS requests are retried after connection errors
+	// and 429, 502, 503 or 504 responses. Zero uses HTTP_MAX_RETRIES and a negative value disables retries.
+	MaxRetries int
+
+	// MaxResponseBytes limits the size of response bodies. Zero uses HTTP_MAX_RESPONSE_MB and a
+	// negative value removes the limit, for streaming responses.
+	MaxResponseBytes int64
+
+	// Cache stores GET responses on disk, under ~/.mcp-devtools/cache/http, and serves repeat requests
+	// from it while they are fresh, revalidating them with their ETag or Last-Modified after that.
+	// Requests with Authorization or Cookie headers are never cached.
+	Cache bool
+
+	// DisableKeepAlives gives the client its own transport that opens a new connection per request,
+	// instead of sharing the pooled transport
+	DisableKeepAlives bool
+
+	// Logger receives the client's debug and warning messages, defaulting to the standard logger
+	Logger *logrus.Logger
+}
+
+// transportKey identifies the environment a shared transport was configured from
+type transportKey struct {
+	proxyURL string
+	noProxy  string
+	caBundle string
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[transportKey]*http.Transport{}
+)
+
+// New creates an HTTP client configured from the environment. Clients share a pooled transport that
+// uses the HTTPS_PROXY/HTTP_PROXY proxy, bypassed for hosts in NO_PROXY, and trusts the certificates in
+// HTTP_CA_BUNDLE. Requests and connections are checked against the network policy, safe requests are
+// retried with exponential backoff, honouring Retry-After, requests to APIs with a usage quota are
+// counted, responses are optionally cached on disk and response bodies are limited in size. The
+// transport is wrapped with OTEL instrumentation when tracing is enabled.
+func New(opts Options) *http.Client {
+	logger := opts.Logger
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	var transport http.RoundTripper = telemetry.WrapHTTPTransport(baseTransport(logger, opts.DisableKeepAlives))
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = envInt(MaxRetriesEnvVar, DefaultMaxRetries)
+	}
+	if maxRetries > 0 {
+		transport = &retryTransport{next: transport, maxRetries: maxRetries, logger: logger}
+	}
+	transport = &usageTransport{next: transport}
+	if opts.Cache {
+		transport = newCacheTransport(transport)
+	}
+	transport = &policyTransport{next: transport}
+
+	maxResponseBytes := opts.MaxResponseBytes
+	if maxResponseBytes == 0 {
+		maxResponseBytes = int64(envInt(MaxResponseMBEnvVar, DefaultMaxResponseMB)) * 1024 * 1024
+	}
+	if maxResponseBytes > 0 {
+		transport = &limitTransport{next: transport, limit: maxResponseBytes}
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}
+}
+
+// baseTransport returns the shared transport for the current environment, or a new unpooled one
+func baseTransport(logger *logrus.Logger, disableKeepAlives bool) *http.Transport {
+	key := transportKey{
+		proxyURL: getProxyURL(),
+		noProxy:  getNoProxy(),
+		caBundle: os.Getenv(CABundleEnvVar),
+	}
+	if disableKeepAlives {
+		transport := newTransport(key, logger)
+		transport.DisableKeepAlives = true
+		return transport
+	}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+	transport := newTransport(key, logger)
+	transports[key] = transport
+	return transport
+}
+
+// newTransport creates a transport configured with the proxy and CA bundle in key
+func newTransport(key transportKey, logger *logrus.Logger) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	// Connections to the proxy itself are exempt from the network policy's address checks, which
+	// apply to the servers requests are sent to
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	guardedDialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkDialAddress}
+	proxyAddress := ""
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if proxyAddress != "" && address == proxyAddress {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guardedDialer.DialContext(ctx, network, address)
+	}
+
+	if key.proxyURL != "" {
+		if parsed, err := url.Parse(key.proxyURL); err == nil {
+			proxyAddress = proxyDialAddress(parsed)
+			proxyFunc := (&httpproxy.Config{
+				HTTPProxy:  key.proxyURL,
+				HTTPSProxy: key.proxyURL,
+				NoProxy:    key.noProxy,
+			}).ProxyFunc()
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyFunc(req.URL)
+			}
+			logger.WithField("proxy_url", redactProxyCredentials(key.proxyURL)).Debug("HTTP client configured with proxy")
+		} else {
+			logger.WithError(err).WithField("proxy_url", redactProxyCredentials(key.proxyURL)).Warn("Failed to parse proxy URL, using direct connection")
+		}
+	}
+
+	if key.caBundle != "" {
+		pool, err := loadCABundle(key.caBundle)
+		if err != nil {
+			logger.WithError(err).WithField("path", key.caBundle).Warn("Failed to load CA bundle, using system certificates only")
+		} else {
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	return transport
+}
+
+// proxyDialAddress returns the host:port the transport dials to reach the proxy
+func proxyDialAddress(proxy *url.URL) string {
+	if proxy.Host == "" {
+		// A proxy given without a scheme, e.g. proxy.example.com:8080, parses as an opaque URL
+		if reparsed, err := url.Parse("http://" + proxy.String()); err == nil {
+			proxy = reparsed
+		}
+	}
+	if port := proxy.Port(); port != "" {
+		return net.JoinHostPort(proxy.Hostname(), port)
+	}
+	if proxy.Scheme == "https" {
+		return net.JoinHostPort(proxy.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxy.Hostname(), "80")
+}
+
+// loadCABundle returns the system certificate pool with the certificates in path added
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// getNoProxy returns the hosts that bypass the proxy
+func getNoProxy() string {
+	if noProxy := os.Getenv("NO_PROXY"); noProxy != "" {
+		return noProxy
+	}
+	return os.Getenv("no_proxy")
+}
+
+// envInt returns the non-negative integer in the environment variable name, or fallback
+func envInt(name string, fallback int) int {
+	if value := os.Getenv(name); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// retryTransport retries safe requests after connection errors and transient error responses
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	logger     *logrus.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+		delay := retryDelay(attempt, resp)
+		if delay > retryMaxDelay {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+		}
+
+		fields := logrus.Fields{"url": req.URL.Redacted(), "attempt": attempt + 1, "delay": delay}
+		if err != nil {
+			t.logger.WithError(err).WithFields(fields).Debug("HTTP request failed, retrying")
+		} else {
+			t.logger.WithField("status", resp.StatusCode).WithFields(fields).Debug("HTTP request failed, retrying")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable reports whether req can safely be sent again
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, "":
+		return req.Body == nil || req.Body == http.NoBody
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a request that returned resp and err is worth retrying
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns how long to wait before retrying, from the response's Retry-After header if it
+// has one, or else exponential backoff with jitter
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(retryAfter); err == nil {
+				return max(time.Until(date), 0)
+			}
+		}
+	}
+	backoff := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// limitTransport limits the size of response bodies
+type limitTransport struct {
+	next  http.RoundTripper
+	limit int64
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: t.limit}
+	return resp, nil
+}
+
+// limitedBody returns ErrResponseTooLarge once more than its limit has been read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// A body of exactly the limit is fine, so only fail if there is more to read
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+)
+
+// NetworkPolicy decides which hosts and addresses clients created by this package may connect to
+type NetworkPolicy interface {
+	// CheckHost returns an error if requests to host, a domain name or IP address, are not allowed
+	CheckHost(host string) error
+
+	// CheckAddress returns an error if connections to addr are not allowed. It is called with the
+	// address a host name resolved to, so it also applies when DNS points at a blocked address.
+	CheckAddress(addr netip.Addr) error
+}
+
+var (
+	policyMu      sync.RWMutex
+	networkPolicy NetworkPolicy
+)
+
+// SetNetworkPolicy sets the policy every client checks outbound requests against, nil for none
+func SetNetworkPolicy(policy NetworkPolicy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	networkPolicy = policy
+}
+
+// currentPolicy returns the network policy, or nil if none is set
+func currentPolicy() NetworkPolicy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return networkPolicy
+}
+
+// PolicyError is returned, wrapped, for a request or connection the network policy does not allow
+type PolicyError struct {
+	Err error
+}
+
+func (e *PolicyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// policyTransport blocks requests, including each redirect, to hosts the network policy does not allow
+type policyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if policy := currentPolicy(); policy != nil {
+		if err := policy.CheckHost(req.URL.Hostname()); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, &PolicyError{Err: err}
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// checkDialAddress blocks connections to addresses the network policy does not allow, once the host
+// name has been resolved
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	policy := currentPolicy()
+	if policy == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return nil
+	}
+	if err := policy.CheckAddress(addr.Unmap()); err != nil {
+		return &PolicyError{Err: err}
+	}
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/sammcj/mcp-devtools/internal/telemetry"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,56 +21,15 @@ var ProxyEnvironmentVariables = []string{
 // NewHTTPClientWithProxy creates an HTTP client with optional proxy support
 // Only configures proxy if environment variables are set
 // Uses standard proxy environment variables in order of preference
-// Automatically wraps the transport with OTEL instrumentation if tracing is enabled
+// See New for the CA bundle, retry and response size settings it also applies
 func NewHTTPClientWithProxy(timeout time.Duration) *http.Client {
-	client := &http.Client{
-		Timeout: timeout,
-	}
-
-	// Start with default transport
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-
-	// Configure proxy if environment variables are set
-	if proxyURL := getProxyURL(); proxyURL != "" {
-		if parsedProxy, err := url.Parse(proxyURL); err == nil {
-			transport.Proxy = http.ProxyURL(parsedProxy)
-		}
-	}
-
-	// Wrap transport with OTEL instrumentation (noop if tracing disabled)
-	client.Transport = telemetry.WrapHTTPTransport(transport)
-
-	return client
+	return New(Options{Timeout: timeout})
 }
 
 // NewHTTPClientWithProxyAndLogger creates an HTTP client with optional proxy support and logging
-// Automatically wraps the transport with OTEL instrumentation if tracing is enabled
+// See New for the CA bundle, retry and response size settings it also applies
 func NewHTTPClientWithProxyAndLogger(timeout time.Duration, logger *logrus.Logger) *http.Client {
-	client := &http.Client{
-		Timeout: timeout,
-	}
-
-	// Start with default transport
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-
-	// Configure proxy if environment variables are set
-	if proxyURL := getProxyURL(); proxyURL != "" {
-		if parsedProxy, err := url.Parse(proxyURL); err == nil {
-			transport.Proxy = http.ProxyURL(parsedProxy)
-			if logger != nil {
-				logger.WithField("proxy_url", redactProxyCredentials(proxyURL)).Debug("HTTP client configured with proxy")
-			}
-		} else {
-			if logger != nil {
-				logger.WithError(err).WithField("proxy_url", redactProxyCredentials(proxyURL)).Warn("Failed to parse proxy URL, using direct connection")
-			}
-		}
-	}
-
-	// Wrap transport with OTEL instrumentation (noop if tracing disabled)
-	client.Transport = telemetry.WrapHTTPTransport(transport)
-
-	return client
+	return New(Options{Timeout: timeout, Logger: logger})
 }
 
 // getProxyURL returns the first valid proxy URL from environment variables
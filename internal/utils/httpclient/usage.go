@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync"
+)
+
+// UsageMeter counts requests to APIs with a usage quota and refuses them once a cap is reached
+type UsageMeter interface {
+	// Consume records a request to host, a domain name or IP address, returning an error if the
+	// request must not be sent
+	Consume(host string) error
+}
+
+var (
+	meterMu    sync.RWMutex
+	usageMeter UsageMeter
+)
+
+// SetUsageMeter sets the meter every client counts outbound requests with, nil for none
+func SetUsageMeter(meter UsageMeter) {
+	meterMu.Lock()
+	defer meterMu.Unlock()
+	usageMeter = meter
+}
+
+// currentMeter returns the usage meter, or nil if none is set
+func currentMeter() UsageMeter {
+	meterMu.RLock()
+	defer meterMu.RUnlock()
+	return usageMeter
+}
+
+// usageTransport counts each request that is not served from the cache, including redirects, and
+// blocks requests the usage meter refuses. Retries of a request are not counted again.
+type usageTransport struct {
+	next http.RoundTripper
+}
+
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if meter := currentMeter(); meter != nil {
+		if err := meter.Consume(req.URL.Hostname()); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
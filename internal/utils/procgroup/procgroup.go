@@ -0,0 +1,18 @@
+// Package procgroup runs commands in their own process group, so that cancelling a command also
+// stops the processes it started, such as the test binaries go test builds and runs
+package procgroup
+
+import (
+	"os/exec"
+	"time"
+)
+
+// WaitDelay is how long to wait for a cancelled command's output to close before giving up on it
+const WaitDelay = 5 * time.Second
+
+// Set makes cmd start in a new process group that is killed as a whole when its context is
+// cancelled. It must be called before the command is started.
+func Set(cmd *exec.Cmd) {
+	setGroup(cmd)
+	cmd.WaitDelay = WaitDelay
+}
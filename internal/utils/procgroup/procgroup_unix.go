@@ -0,0 +1,20 @@
+//go:build !windows
+
+package procgroup
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setGroup starts the command as the leader of a new process group and kills the group on cancel
+func setGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		// A negative PID signals every process in the group
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package procgroup
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setGroup starts the command in a new process group. Windows has no signal for a whole group, so
+// cancelling kills the command itself, and WaitDelay stops waiting for output held open by children.
+func setGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
@@ -0,0 +1,192 @@
+// Package workpool limits how many CPU and memory heavy tool calls, such as document conversion
+// and large spreadsheet reads, run at once. Each kind of work has its own queue with a fixed
+// number of workers: calls beyond that wait their turn in order, and calls beyond the queue's
+// depth are refused, so concurrent agents on a shared server slow down rather than overload it.
+package workpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// WorkersEnvVar sets the workers of every queue, and is followed by _<QUEUE> to set one queue's
+	WorkersEnvVar = "WORK_QUEUE_WORKERS"
+
+	// DepthEnvVar sets how many calls may wait in every queue, and is followed by _<QUEUE> to set one
+	// queue's
+	DepthEnvVar = "WORK_QUEUE_DEPTH"
+
+	// defaultDepth is how many calls may wait in a queue when DepthEnvVar is not set
+	defaultDepth = 16
+)
+
+// The queues tools name for their heavy calls
+const (
+	Documents    = "documents"
+	Spreadsheets = "spreadsheets"
+)
+
+// ErrQueueFull is returned for calls made while their queue already has as many calls waiting as its depth
+var ErrQueueFull = errors.New("work queue is full")
+
+var (
+	queuesMu sync.Mutex
+	queues   = map[string]*queue{}
+)
+
+// Stats describe a queue
+type Stats struct {
+	Queue   string `json:"queue"`
+	Workers int    `json:"workers"`
+	Depth   int    `json:"depth"`
+	Running int    `json:"running"`
+	Waiting int    `json:"waiting"`
+}
+
+// queue hands its workers to waiting calls in the order they arrived
+type queue struct {
+	name    string
+	workers int
+	depth   int
+
+	mu      sync.Mutex
+	running int
+	waiting []*waiter
+}
+
+// waiter is a call waiting for a worker
+type waiter struct {
+	ready chan struct{} // Closed when the call is given a worker
+	moved chan struct{} // Signalled when the call moves up the queue
+}
+
+// Acquire waits for a worker in the named queue and returns a function that frees it. While the call
+// waits, wait is called with its position in the queue, counting from 1, each time that changes.
+// Calls beyond the queue's depth return ErrQueueFull, and calls cancelled while waiting leave the
+// queue and return the context's error.
+func Acquire(ctx context.Context, name string, wait func(position int)) (release func(), err error) {
+	q := getQueue(name)
+
+	q.mu.Lock()
+	if q.running < q.workers {
+		q.running++
+		q.mu.Unlock()
+		return sync.OnceFunc(q.release), nil
+	}
+	if len(q.waiting) >= q.depth {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w: %d %s calls are running and %d waiting, so this call was refused; retry shortly, or set %s_%s to allow more waiting calls",
+			ErrQueueFull, q.running, name, len(q.waiting), DepthEnvVar, strings.ToUpper(name))
+	}
+	w := &waiter{ready: make(chan struct{}), moved: make(chan struct{}, 1)}
+	q.waiting = append(q.waiting, w)
+	position := len(q.waiting)
+	q.mu.Unlock()
+
+	for {
+		if wait != nil {
+			wait(position)
+		}
+		select {
+		case <-w.ready:
+			return sync.OnceFunc(q.release), nil
+		case <-w.moved:
+			q.mu.Lock()
+			position = slices.Index(q.waiting, w) + 1
+			q.mu.Unlock()
+			if position == 0 {
+				// Given a worker since it moved
+				<-w.ready
+				return sync.OnceFunc(q.release), nil
+			}
+		case <-ctx.Done():
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			if i := slices.Index(q.waiting, w); i >= 0 {
+				q.waiting = slices.Delete(q.waiting, i, i+1)
+				q.signalMoved(i)
+				return nil, ctx.Err()
+			}
+			// Given a worker as the call was cancelled, so pass it on
+			q.releaseLocked()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Snapshot returns the state of the queues used so far, sorted by name
+func Snapshot() []Stats {
+	queuesMu.Lock()
+	defer queuesMu.Unlock()
+	var stats []Stats
+	for _, q := range queues {
+		q.mu.Lock()
+		stats = append(stats, Stats{Queue: q.name, Workers: q.workers, Depth: q.depth, Running: q.running, Waiting: len(q.waiting)})
+		q.mu.Unlock()
+	}
+	slices.SortFunc(stats, func(a, b Stats) int { return strings.Compare(a.Queue, b.Queue) })
+	return stats
+}
+
+// getQueue returns the named queue, configuring it from the environment on first use
+func getQueue(name string) *queue {
+	queuesMu.Lock()
+	defer queuesMu.Unlock()
+	q, ok := queues[name]
+	if !ok {
+		suffix := "_" + strings.ToUpper(name)
+		q = &queue{
+			name:    name,
+			workers: envInt(WorkersEnvVar+suffix, envInt(WorkersEnvVar, max(1, runtime.NumCPU()/2), 1), 1),
+			depth:   envInt(DepthEnvVar+suffix, envInt(DepthEnvVar, defaultDepth, 0), 0),
+		}
+		queues[name] = q
+	}
+	return q
+}
+
+// release frees a worker
+func (q *queue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.releaseLocked()
+}
+
+// releaseLocked hands a freed worker to the first waiting call. The caller must hold q.mu.
+func (q *queue) releaseLocked() {
+	if len(q.waiting) == 0 {
+		q.running--
+		return
+	}
+	next := q.waiting[0]
+	q.waiting = q.waiting[1:]
+	close(next.ready)
+	q.signalMoved(0)
+}
+
+// signalMoved tells the calls from index from onwards that they have moved up the queue. The caller
+// must hold q.mu.
+func (q *queue) signalMoved(from int) {
+	for _, w := range q.waiting[from:] {
+		select {
+		case w.moved <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// envInt returns an integer environment variable of at least minimum, or def when it is unset or invalid
+func envInt(name string, def, minimum int) int {
+	if value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name))); err == nil && value >= minimum {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,52 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
+	"github.com/sammcj/mcp-devtools/internal/security"
+)
+
+// CheckFileAccess checks that the calling session may access a path. The security policy must allow
+// it and, when the caller's OAuth access policy limits their directories, it must lie within one of
+// them. Symbolic links are resolved first, so a link cannot lead out of an allowed directory. Tools
+// check paths from their parameters with this rather than security.CheckFileAccess.
+func CheckFileAccess(ctx context.Context, path string) error {
+	if err := security.CheckFileAccess(path); err != nil {
+		return err
+	}
+	dirs := access.AllowedDirectories(ctx)
+	if dirs == nil {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	real := realPath(abs)
+	for _, dir := range dirs {
+		if IsWithin(abs, dir) && IsWithin(real, realPath(dir)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("access denied: %s is outside the directories your access policy allows", path)
+}
+
+// realPath resolves the symbolic links in a path, or in its nearest existing parent when the path
+// does not exist yet, such as a file about to be written
+func realPath(path string) string {
+	rest := ""
+	for dir := filepath.Clean(path); ; {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
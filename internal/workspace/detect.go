@@ -0,0 +1,76 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// projectMarkers are the files whose presence identifies each project type. Patterns may use
+// filepath.Match wildcards.
+var projectMarkers = map[string][]string{
+	"docker":    {"Dockerfile", "Containerfile", "compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"},
+	"go":        {"go.mod", "go.work"},
+	"node":      {"package.json"},
+	"python":    {"pyproject.toml", "requirements.txt", "setup.py", "Pipfile"},
+	"rust":      {"Cargo.toml"},
+	"terraform": {"*.tf", "*.tf.json", ".terraform.lock.hcl"},
+}
+
+// skippedDirs are subdirectories not looked in when detecting project types, as they hold
+// dependencies or tool state rather than the project's own files
+var skippedDirs = []string{"node_modules", "vendor", "target", "dist", "build"}
+
+// ProjectTypes returns the project types, such as "go" or "terraform", of the given directories,
+// sorted. Each directory and its immediate subdirectories are looked in, so that a repository with
+// its infrastructure in a terraform/ directory is detected as both.
+func ProjectTypes(dirs []string) []string {
+	found := make(map[string]bool)
+	for _, dir := range dirs {
+		detectProjectTypes(dir, found)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name) {
+				continue
+			}
+			detectProjectTypes(filepath.Join(dir, name), found)
+		}
+	}
+
+	types := make([]string, 0, len(found))
+	for projectType := range found {
+		types = append(types, projectType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// detectProjectTypes adds the project types whose marker files are in dir to found
+func detectProjectTypes(dir string, found map[string]bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for projectType, markers := range projectMarkers {
+		if found[projectType] {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if slices.ContainsFunc(markers, func(marker string) bool {
+				matched, _ := filepath.Match(marker, entry.Name())
+				return matched
+			}) {
+				found[projectType] = true
+				break
+			}
+		}
+	}
+}
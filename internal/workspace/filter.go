@@ -0,0 +1,101 @@
+package workspace
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ToolFilterEnvVar enables hiding tools that do not suit the workspace's project types
+	ToolFilterEnvVar = "WORKSPACE_TOOL_FILTER"
+	// ToolRulesEnvVar overrides the project types tools are listed for, as "tool=type|type,...". A
+	// tool with no types is always listed.
+	ToolRulesEnvVar = "WORKSPACE_TOOL_RULES"
+)
+
+// defaultToolRules are the project types each ecosystem-specific tool is listed for. Tools without
+// a rule are always listed.
+var defaultToolRules = map[string][]string{
+	"aceternity_ui":           {"node"},
+	"containers":              {"docker"},
+	"dep_graph":               {"go", "node"},
+	"magic_ui":                {"node"},
+	"shadcn":                  {"node"},
+	"terraform":               {"terraform"},
+	"terraform_documentation": {"terraform"},
+}
+
+// IsToolFilterEnabled reports whether tools are listed according to the workspace's project types
+func IsToolFilterEnabled() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(ToolFilterEnvVar)))
+	return value == "true" || value == "1"
+}
+
+// ToolRules returns the project types each tool is listed for, the defaults merged with any set in
+// WORKSPACE_TOOL_RULES. Tool names are normalised to lower case with underscores.
+func ToolRules() map[string][]string {
+	rules := maps.Clone(defaultToolRules)
+	for entry := range strings.SplitSeq(os.Getenv(ToolRulesEnvVar), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+		var types []string
+		for projectType := range strings.SplitSeq(value, "|") {
+			if projectType = strings.ToLower(strings.TrimSpace(projectType)); projectType != "" {
+				types = append(types, projectType)
+			}
+		}
+		rules[normaliseToolName(name)] = types
+	}
+	return rules
+}
+
+// FilterTools hides tools that do not suit the project types of the calling session's workspace,
+// for use with server.WithToolFilter. The workspace is the client's roots when it has already
+// shared them, otherwise the server's working directory. Tools are only hidden from listings, so
+// they can still be called, and nothing is hidden when no project type is detected.
+func FilterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if !IsToolFilterEnabled() {
+		return tools
+	}
+	dirs := cachedRoots(ctx)
+	if len(dirs) == 0 {
+		dir, err := os.Getwd()
+		if err != nil {
+			return tools
+		}
+		dirs = []string{dir}
+	}
+	projectTypes := ProjectTypes(dirs)
+	if len(projectTypes) == 0 {
+		return tools
+	}
+
+	rules := ToolRules()
+	listed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		types := rules[normaliseToolName(tool.Name)]
+		if len(types) == 0 || slices.ContainsFunc(types, func(projectType string) bool {
+			return slices.Contains(projectTypes, projectType)
+		}) {
+			listed = append(listed, tool)
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"project_types": projectTypes,
+		"hidden":        len(tools) - len(listed),
+	}).Debug("Filtered tools for workspace")
+	return listed
+}
+
+// normaliseToolName puts a tool name in the form rules are looked up by
+func normaliseToolName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "-", "_"))
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package workspace
+
+// comparablePath returns a clean path in the form used to compare it with other paths.
+func comparablePath(path string) string {
+	return path
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package workspace
+
+import "strings"
+
+// longPathPrefix marks a Windows path that bypasses the MAX_PATH limit
+const longPathPrefix = `\\?\`
+
+// comparablePath returns a clean path in the form used to compare it with other paths. Windows
+// paths are case-insensitive and may be given with the long path prefix, which Go's os package adds
+// itself when needed.
+func comparablePath(path string) string {
+	return strings.ToLower(strings.TrimPrefix(path, longPathPrefix))
+}
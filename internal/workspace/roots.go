@@ -0,0 +1,208 @@
+// Package workspace tracks the workspace roots that MCP clients share with the server, so that when
+// one instance serves several editor workspaces, tools can resolve relative paths and limit file access
+// to the workspace of the session making the call.
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sirupsen/logrus"
+)
+
+// rootsRequestTimeout bounds how long a tool call waits for the client to list its roots
+const rootsRequestTimeout = 5 * time.Second
+
+var (
+	sessionRoots   = make(map[string][]string)
+	sessionRootsMu sync.RWMutex
+)
+
+// Register wires roots tracking into the MCP server: cached roots are dropped when a client reports
+// its roots changed or its session ends
+func Register(hooks *server.Hooks, srv *server.MCPServer) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		Forget(session.SessionID())
+	})
+	srv.AddNotificationHandler(mcp.MethodNotificationRootsListChanged, func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			Forget(session.SessionID())
+		}
+	})
+}
+
+// Forget drops the cached roots for a session, so they are requested again on the next tool call
+func Forget(sessionID string) {
+	sessionRootsMu.Lock()
+	defer sessionRootsMu.Unlock()
+	delete(sessionRoots, sessionID)
+}
+
+// Roots returns the local directories the calling session's client has shared as workspace roots.
+// They are requested from the client on first use and cached for the session. It returns nil when
+// there is no session, the client does not support roots, or the request fails.
+func Roots(ctx context.Context) []string {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	rootsSession, ok := session.(server.SessionWithRoots)
+	if !ok {
+		return nil
+	}
+	// Only ask clients that declared the roots capability, others would reject the request
+	if infoSession, ok := session.(server.SessionWithClientInfo); ok && infoSession.GetClientCapabilities().Roots == nil {
+		return nil
+	}
+
+	sessionID := session.SessionID()
+	sessionRootsMu.RLock()
+	roots, cached := sessionRoots[sessionID]
+	sessionRootsMu.RUnlock()
+	if cached {
+		return roots
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, rootsRequestTimeout)
+	defer cancel()
+	result, err := rootsSession.ListRoots(requestCtx, mcp.ListRootsRequest{})
+	if err != nil {
+		// Not cached, so a client that was busy is asked again on the next call
+		logrus.WithError(err).WithField("session", sessionID).Debug("Failed to list client workspace roots")
+		return nil
+	}
+
+	roots = RootPaths(result.Roots)
+	sessionRootsMu.Lock()
+	sessionRoots[sessionID] = roots
+	sessionRootsMu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"session": sessionID,
+		"roots":   roots,
+	}).Debug("Loaded client workspace roots")
+	return roots
+}
+
+// cachedRoots returns the calling session's roots if they have already been requested, without
+// asking the client. Handlers the client waits on before it answers requests, such as tools/list
+// over stdio, cannot wait for a roots request.
+func cachedRoots(ctx context.Context) []string {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	sessionRootsMu.RLock()
+	defer sessionRootsMu.RUnlock()
+	return sessionRoots[session.SessionID()]
+}
+
+// RootPaths converts file:// root URIs to local directory paths, skipping roots that are not local
+func RootPaths(roots []mcp.Root) []string {
+	var paths []string
+	for _, root := range roots {
+		parsed, err := url.Parse(root.URI)
+		if err != nil || parsed.Scheme != "file" || parsed.Path == "" {
+			continue
+		}
+		if parsed.Host != "" && parsed.Host != "localhost" {
+			continue
+		}
+		path := parsed.Path
+		// file:///C:/work becomes /C:/work
+		if runtime.GOOS == "windows" {
+			path = strings.TrimPrefix(path, "/")
+		}
+		paths = append(paths, filepath.Clean(filepath.FromSlash(path)))
+	}
+	return paths
+}
+
+// ResolvePath makes a relative path absolute against the calling session's first workspace root.
+// Absolute paths, and relative paths when the session has no roots, are returned unchanged.
+func ResolvePath(ctx context.Context, path string) string {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+	if roots := Roots(ctx); len(roots) > 0 {
+		return filepath.Join(roots[0], path)
+	}
+	return path
+}
+
+// ResolveToolPath resolves a tool's path parameter for use: relative paths are resolved against the
+// calling session's first workspace root, the result must be absolute, and CheckFileAccess must allow
+// it. name is the parameter name used in errors.
+func ResolveToolPath(ctx context.Context, name, path string) (string, error) {
+	resolved := ResolvePath(ctx, path)
+	if !filepath.IsAbs(resolved) {
+		return "", fmt.Errorf("%s must be an absolute path, or relative to a workspace root shared by the client, got: %s", name, path)
+	}
+	resolved = filepath.Clean(resolved)
+	if err := CheckFileAccess(ctx, resolved); err != nil {
+		if secErr, ok := err.(*security.SecurityError); ok {
+			return "", security.FormatSecurityBlockError(secErr)
+		}
+		return "", err
+	}
+	return resolved, nil
+}
+
+// ScopeDirectories returns the session's roots that lie within the allowed directories, for use as
+// the allowed directories of a call. Roots narrow access and never widen it, so roots outside every
+// allowed directory are ignored. When no roots remain the allowed directories are returned unchanged.
+func ScopeDirectories(roots, allowed []string) []string {
+	var scoped []string
+	for _, root := range roots {
+		for _, dir := range allowed {
+			if IsWithin(root, dir) {
+				scoped = append(scoped, root)
+				break
+			}
+		}
+	}
+	if len(scoped) == 0 {
+		return allowed
+	}
+	return scoped
+}
+
+// LimitDirectories returns the parts of the allowed directories that also lie within the limit
+// directories. Unlike ScopeDirectories it never falls back to the allowed directories, so an empty
+// result means no access.
+func LimitDirectories(limit, allowed []string) []string {
+	var limited []string
+	for _, dir := range allowed {
+		for _, limitDir := range limit {
+			var within string
+			switch {
+			case IsWithin(dir, limitDir):
+				within = filepath.Clean(dir)
+			case IsWithin(limitDir, dir):
+				within = filepath.Clean(limitDir)
+			default:
+				continue
+			}
+			if !slices.Contains(limited, within) {
+				limited = append(limited, within)
+			}
+		}
+	}
+	return limited
+}
+
+// IsWithin reports whether path is dir or inside it, comparing paths the way the platform does
+func IsWithin(path, dir string) bool {
+	path, dir = comparablePath(filepath.Clean(path)), comparablePath(filepath.Clean(dir))
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,25 +19,41 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/bench"
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/diagnostics"
+	"github.com/sammcj/mcp-devtools/internal/doctor"
+	"github.com/sammcj/mcp-devtools/internal/logging"
+	"github.com/sammcj/mcp-devtools/internal/memusage"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
 	oauthclient "github.com/sammcj/mcp-devtools/internal/oauth/client"
 	oauthserver "github.com/sammcj/mcp-devtools/internal/oauth/server"
 	"github.com/sammcj/mcp-devtools/internal/oauth/types"
+	"github.com/sammcj/mcp-devtools/internal/recording"
 	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/scheduler"
+	"github.com/sammcj/mcp-devtools/internal/secrets"
 	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
 	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
 	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 	"go.opentelemetry.io/otel/propagation"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 
 	// Import all tool packages to register them
 	_ "github.com/sammcj/mcp-devtools/internal/imports"
 	coderename "github.com/sammcj/mcp-devtools/internal/tools/code_rename"
+	"github.com/sammcj/mcp-devtools/internal/tools/pipelines"
+	"github.com/sammcj/mcp-devtools/internal/tools/plugins"
 	"github.com/sammcj/mcp-devtools/internal/tools/proxy"
 )
 
-// Version information (set during build)
+// Version information, set during build with -ldflags or read from the embedded build info
 var (
 	Version   = "dev"
 	Commit    = "none"
@@ -46,7 +63,7 @@ var (
 // Global resources that need cleanup
 // Using atomic operations to prevent race conditions between signal handlers and cleanup
 var (
-	debugLogFile      atomic.Pointer[os.File]
+	debugLogFile      atomic.Pointer[logging.RotatingFile]
 	isStdioMode       atomic.Bool
 	telemetryShutdown func() error
 	metricsShutdown   func() error
@@ -60,31 +77,18 @@ const (
 // parseLogLevel parses the LOG_LEVEL environment variable and returns the appropriate logrus level.
 // Defaults to WarnLevel if not set or invalid.
 func parseLogLevel() logrus.Level {
-	logLevelStr := os.Getenv("LOG_LEVEL")
-	if logLevelStr == "" {
-		return logrus.WarnLevel // Default to warn
-	}
-
-	// Normalise to lowercase for comparison
-	logLevelStr = strings.ToLower(strings.TrimSpace(logLevelStr))
-
-	switch logLevelStr {
-	case "debug":
-		return logrus.DebugLevel
-	case "info":
-		return logrus.InfoLevel
-	case "warn", "warning":
-		return logrus.WarnLevel
-	case "error":
-		return logrus.ErrorLevel
-	case "fatal":
-		return logrus.FatalLevel
-	case "panic":
-		return logrus.PanicLevel
-	default:
-		// Invalid value, default to warn
-		return logrus.WarnLevel
+	// Invalid or unset values default to warn
+	level, _ := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	return level
+}
+
+// openLogFile opens the rotating application log file in ~/.mcp-devtools/logs
+func openLogFile(config logging.Config) (*logging.RotatingFile, error) {
+	logDir, err := logging.Dir()
+	if err != nil {
+		return nil, err
 	}
+	return logging.OpenRotatingFile(filepath.Join(logDir, logging.LogFileName), config.Rotation)
 }
 
 // setMemoryLimit configures the Go runtime memory limit
@@ -105,6 +109,39 @@ func setMemoryLimit() {
 	debug.SetMemoryLimit(memLimit)
 }
 
+// applyBuildInfo fills in version information that was not set with -ldflags
+// from the module and VCS metadata the Go toolchain embeds in every binary, so
+// `go install` and plain `go build` binaries report where they came from.
+func applyBuildInfo(info *debug.BuildInfo) {
+	if info == nil {
+		return
+	}
+
+	if Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = strings.TrimPrefix(info.Main.Version, "v")
+	}
+
+	var revision, modified string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			if BuildDate == "unknown" && setting.Value != "" {
+				BuildDate = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value
+		}
+	}
+	if Commit == "none" && revision != "" {
+		Commit = revision[:min(len(revision), 7)]
+		if modified == "true" {
+			Commit += "-dirty"
+		}
+	}
+}
+
 // newToolHandler builds the MCP handler for a registered tool. Tool execution
 // failures (missing parameters, invalid input, unsupported options, etc.) are
 // returned as tool results with isError set rather than Go errors. Returning a
@@ -136,8 +173,28 @@ func newToolHandler(name, transport string, logger *logrus.Logger) func(context.
 		// Start telemetry span for tool execution
 		spanCtx, span := telemetry.StartToolSpan(toolCtx, name, args)
 
-		// Execute tool with error recovery
-		result, err := currentTool.Execute(spanCtx, registry.GetLogger(), registry.GetCache(), args)
+		// Heavy calls wait for a worker in their queue, memory intensive calls are refused while the
+		// server is close to its memory limit, and calls that allocate more than their budget are logged
+		var result *mcp.CallToolResult
+		var progressToken mcp.ProgressToken
+		if request.Params.Meta != nil {
+			progressToken = request.Params.Meta.ProgressToken
+		}
+		release, err := toolcall.Queue(spanCtx, currentTool, args, progressToken)
+		if err == nil {
+			err = toolcall.CheckMemory(currentTool, args)
+		}
+		if err == nil {
+			toolLogger := logging.ForTool(registry.GetLogger(), name)
+			allocated := memusage.Allocated()
+			result, err = currentTool.Execute(spanCtx, toolLogger, registry.GetCache(), args)
+			memusage.CheckBudget(toolLogger, name, allocated)
+		}
+		if release != nil {
+			release()
+		}
+		sessionID := diagnostics.SessionID(toolCtx)
+		diagnostics.RecordCall(sessionID, name, startTime)
 
 		// Calculate duration for metrics
 		durationMs := float64(time.Since(startTime).Milliseconds())
@@ -160,15 +217,32 @@ func newToolHandler(name, transport string, logger *logrus.Logger) func(context.
 				logger.WithError(err).Errorf("Tool execution failed: %s", name)
 			}
 
-			// Log tool error to file if enabled
-			if errorLogger := tools.GetGlobalErrorLogger(); errorLogger != nil && errorLogger.IsEnabled() {
-				errorLogger.LogToolError(name, args, err, transport)
-			}
+			// Record the error for get_diagnostics, and log it to file if enabled
+			tools.GetGlobalErrorLogger().LogToolError(name, args, err, transport, sessionID)
 
-			return mcp.NewToolResultError(fmt.Sprintf("tool execution failed: %s", err)), nil
+			errorResult := mcp.NewToolResultError(fmt.Sprintf("tool execution failed: %s", err))
+			security.RedactToolResult(name, errorResult)
+			recordCall(toolCtx, name, args, startTime, errorResult, logger)
+			return errorResult, nil
 		}
 
-		return result, nil
+		// Remove hidden characters first so they cannot split secrets, then replace secrets
+		// before the result reaches the model
+		security.SanitiseToolResult(name, result)
+		security.RedactToolResult(name, result)
+
+		// The full result is recorded, as the client can read every page of it
+		recordCall(toolCtx, name, args, startTime, result, logger)
+
+		// Oversized outputs are paginated or summarised after redaction, so stored and saved outputs never hold secrets
+		return tools.LimitOutput(toolCtx, name, result), nil
+	}
+}
+
+// recordCall records a tool call in the session's recording when SESSION_RECORDING is enabled
+func recordCall(ctx context.Context, name string, args map[string]any, start time.Time, result *mcp.CallToolResult, logger *logrus.Logger) {
+	if err := recording.Record(ctx, name, args, start, result); err != nil {
+		logger.WithError(err).Warn("Failed to record tool call")
 	}
 }
 
@@ -176,6 +250,15 @@ func main() {
 	// Set memory limit for the Go application
 	setMemoryLimit()
 
+	// Fall back to embedded build metadata for version information not set at build time
+	if info, ok := debug.ReadBuildInfo(); ok {
+		applyBuildInfo(info)
+	}
+
+	// Resolve ${env:...}, ${file:...} and ${keychain:...} secret references in the environment before
+	// flags and tools read it, the errors are logged once logging is configured
+	secretsErr := secrets.ResolveEnvironment()
+
 	// Create context with signal handling for graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -222,7 +305,7 @@ func main() {
 			},
 			&cli.StringFlag{
 				Name:  "auth-token",
-				Usage: "Authentication token for Streamable HTTP transport (optional)",
+				Usage: "Authentication token for HTTP and SSE transports (optional)",
 			},
 			&cli.StringFlag{
 				Name:  "endpoint-path",
@@ -232,7 +315,7 @@ func main() {
 			&cli.DurationFlag{
 				Name:  "session-timeout",
 				Value: 30 * time.Minute,
-				Usage: "Session timeout for Streamable HTTP transport",
+				Usage: "Session timeout for Streamable HTTP transport, also setting the heartbeat interval for SSE",
 			},
 			// OAuth 2.0/2.1 flags
 			&cli.BoolFlag{
@@ -271,6 +354,11 @@ func main() {
 				Usage:   "Require HTTPS for OAuth endpoints (disable only for development)",
 				Sources: cli.EnvVars("OAUTH_REQUIRE_HTTPS", "MCP_OAUTH_REQUIRE_HTTPS"),
 			},
+			&cli.StringFlag{
+				Name:    "oauth-access-policy",
+				Usage:   "Path to a YAML policy mapping token claims to per-user tools, directories and quotas",
+				Sources: cli.EnvVars("OAUTH_ACCESS_POLICY", "MCP_OAUTH_ACCESS_POLICY"),
+			},
 			// OAuth Client Browser Authentication flags
 			&cli.BoolFlag{
 				Name:    "oauth-browser-auth",
@@ -346,77 +434,278 @@ func main() {
 					return handleSecurityConfigValidate(cmd)
 				},
 			},
+			{
+				Name:      "security-test",
+				Usage:     "Show which security rules match a sample string or file and the resulting action",
+				ArgsUsage: "[sample text]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "file",
+						Usage: "Read the sample from a file instead of the command line",
+					},
+					&cli.StringFlag{
+						Name:  "rule",
+						Usage: "Only check this rule",
+					},
+					&cli.StringFlag{
+						Name:  "domain",
+						Usage: "Treat the sample as coming from this domain, for trusted domain exceptions and source trust",
+					},
+					&cli.StringFlag{
+						Name:  "tool",
+						Usage: "Treat the sample as output from this tool, for source trust",
+					},
+					&cli.StringFlag{
+						Name:  "config-path",
+						Usage: "Path to security configuration file (default: ~/.mcp-devtools/security.yaml)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// Errors are printed as the logger is not set up for CLI commands
+					if err := handleSecurityTest(cmd); err != nil {
+						fmt.Printf("❌ %v\n", err)
+						return err
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "tools",
+				Usage: "Inspect the available tools and why they are or are not enabled",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "List every tool, whether it is enabled, and why not if it is disabled",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							handleToolsList()
+							return nil
+						},
+					},
+					{
+						Name:      "describe",
+						Usage:     "Show a tool's status and input schema",
+						ArgsUsage: "<tool name>",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleToolsDescribe(cmd.Args().First()))
+						},
+					},
+				},
+			},
+			{
+				Name:  "credentials",
+				Usage: "Manage the tokens and secrets stored in the OS keyring for tools and the proxy",
+				Commands: []*cli.Command{
+					{
+						Name:      "set",
+						Usage:     "Store a credential, reading its value from stdin or a prompt",
+						ArgsUsage: "<name>",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleCredentialsSet(cmd.Args().First()))
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List the names of the stored credentials",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleCredentialsList())
+						},
+					},
+					{
+						Name:      "delete",
+						Usage:     "Delete a stored credential",
+						ArgsUsage: "<name>",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleCredentialsDelete(cmd.Args().First()))
+						},
+					},
+				},
+			},
+			{
+				Name:  "doctor",
+				Usage: "Check the programs, credentials and services the enabled tools depend on",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Also check tools that are not enabled",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return handleDoctor(ctx, cmd.Bool("all"))
+				},
+			},
+			{
+				Name:  "bench",
+				Usage: "Time representative tool workloads to catch performance regressions between releases",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "iterations",
+						Value: 5,
+						Usage: "Number of timed runs of each workload",
+					},
+					&cli.StringSliceFlag{
+						Name:  "workload",
+						Usage: "Only run the named workloads (default: all)",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "Save the results as JSON to this file, for use as a later baseline",
+					},
+					&cli.StringFlag{
+						Name:  "baseline",
+						Usage: "Compare against results saved with --output and fail on regressions",
+					},
+					&cli.FloatFlag{
+						Name:  "threshold",
+						Value: 20,
+						Usage: "Percentage increase in time or allocations over the baseline that counts as a regression",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// Errors are printed as the logger is not set up for CLI commands
+					if err := handleBench(ctx, cmd); err != nil {
+						fmt.Printf("❌ %v\n", err)
+						return err
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "logs",
+				Usage: "Read the server's log files",
+				Commands: []*cli.Command{
+					{
+						Name:  "tail",
+						Usage: "Show the last lines of the application log, a tool's log or the tool error log",
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:    "lines",
+								Aliases: []string{"n"},
+								Value:   50,
+								Usage:   "Number of lines to show",
+							},
+							&cli.BoolFlag{
+								Name:    "follow",
+								Aliases: []string{"f"},
+								Usage:   "Keep showing lines as they are written, until interrupted",
+							},
+							&cli.StringFlag{
+								Name:  "tool",
+								Usage: "Show this tool's own log, written when LOG_TOOL_FILES=true",
+							},
+							&cli.BoolFlag{
+								Name:  "errors",
+								Usage: "Show the tool error log, written when LOG_TOOL_ERRORS=true",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							// Errors are printed as the logger is not set up for CLI commands
+							if err := handleLogsTail(ctx, cmd); err != nil {
+								fmt.Printf("❌ %v\n", err)
+								return err
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "sessions",
+				Usage: "Export the tool calls recorded when SESSION_RECORDING=true",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "List the recorded sessions, most recent first",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleSessionsList())
+						},
+					},
+					{
+						Name:      "export",
+						Usage:     "Print or save a session's transcript, to attach to a pull request or incident timeline",
+						ArgsUsage: "<session id>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "markdown",
+								Usage: "Transcript format: markdown or json",
+							},
+							&cli.BoolFlag{
+								Name:  "no-results",
+								Usage: "Leave out the results of the calls",
+							},
+							&cli.StringFlag{
+								Name:    "output",
+								Aliases: []string{"o"},
+								Usage:   "Save the transcript to this file instead of printing it",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return printError(handleSessionsExport(cmd))
+						},
+					},
+				},
+			},
 		},
 		Action: func(cliCtx context.Context, cmd *cli.Command) error {
 			// Get transport settings
 			transport := cmd.String("transport")
 			port := cmd.String("port")
-			baseURL := cmd.String("base-url")
 
 			// Track stdio mode for error handling (atomic to prevent races with signal handlers)
 			isStdioMode.Store(transport == "stdio")
 
 			// Configure logger - ALWAYS use file logging to avoid breaking stdio protocol
-			homeDir, err := os.UserHomeDir()
+			logConfig := logging.ConfigFromEnv()
+			logger.SetFormatter(logConfig.Formatter())
+			logrus.SetFormatter(logConfig.Formatter())
+			logLevel := parseLogLevel()
+			logFile, err := openLogFile(logConfig)
 			if err == nil {
-				logDir := filepath.Join(homeDir, ".mcp-devtools", "logs")
-				if err := os.MkdirAll(logDir, 0700); err == nil {
-					logFile := filepath.Join(logDir, "mcp-devtools.log")
-					if file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err == nil {
-						// Store file handle for cleanup
-						debugLogFile.Store(file)
-						// Configure loggers for file output
-						logger.SetOutput(file)
-						logrus.SetOutput(file)
-						// Apply LOG_LEVEL setting (stdio mode uses warn level minimum)
-						logLevel := parseLogLevel()
-						if isStdioMode.Load() && logLevel < logrus.WarnLevel {
-							logLevel = logrus.WarnLevel // Minimum warn level for stdio mode
-						}
-						logger.SetLevel(logLevel)
-						logrus.SetLevel(logLevel)
-						logger.WithField("level", logLevel.String()).Debug("Logging configured")
-					} else {
-						// Critical: Cannot create log file - use io.Discard in stdio mode to prevent protocol breakage
-						if isStdioMode.Load() {
-							logger.SetOutput(io.Discard)
-							logrus.SetOutput(io.Discard)
-						} else {
-							// Non-stdio mode can fallback to stderr
-							logger.SetOutput(os.Stderr)
-							logrus.SetOutput(os.Stderr)
-						}
-						logLevel := parseLogLevel()
-						logger.SetLevel(logLevel)
-						logrus.SetLevel(logLevel)
-					}
-				} else {
-					// Critical: Cannot create log directory
-					if isStdioMode.Load() {
-						logger.SetOutput(io.Discard)
-						logrus.SetOutput(io.Discard)
-					} else {
-						logger.SetOutput(os.Stderr)
-						logrus.SetOutput(os.Stderr)
-					}
-					logLevel := parseLogLevel()
-					logger.SetLevel(logLevel)
-					logrus.SetLevel(logLevel)
+				// Store file handle for cleanup
+				debugLogFile.Store(logFile)
+				// Configure loggers for file output
+				logger.SetOutput(logFile)
+				logrus.SetOutput(logFile)
+				// Apply LOG_LEVEL setting (stdio mode uses warn level minimum)
+				if isStdioMode.Load() && logLevel < logrus.WarnLevel {
+					logLevel = logrus.WarnLevel // Minimum warn level for stdio mode
 				}
+				logger.SetLevel(logLevel)
+				logrus.SetLevel(logLevel)
+				logging.ConfigureTools(logConfig, filepath.Dir(logFile.Path()))
+				logger.WithField("level", logLevel.String()).Debug("Logging configured")
 			} else {
-				// Critical: Cannot get home directory
+				// Critical: Cannot create log file - use io.Discard in stdio mode to prevent protocol breakage
 				if isStdioMode.Load() {
 					logger.SetOutput(io.Discard)
 					logrus.SetOutput(io.Discard)
 				} else {
+					// Non-stdio mode can fallback to stderr
 					logger.SetOutput(os.Stderr)
 					logrus.SetOutput(os.Stderr)
 				}
-				logLevel := parseLogLevel()
 				logger.SetLevel(logLevel)
 				logrus.SetLevel(logLevel)
 			}
 
+			if secretsErr != nil {
+				logger.WithError(secretsErr).Warn("Failed to resolve secret references, the affected environment variables are unset")
+			}
+
+			// Register external plugin executables declared in plugins.yaml alongside the built-in tools,
+			// then the pipelines declared in pipelines.yaml, whose steps may call either
+			registerPlugins(logger)
+			registerPipelines(logger)
+
+			// Capture recent log entries in memory for get_diagnostics, before tools create their loggers
+			if _, ok := registry.GetTool("get_diagnostics"); ok {
+				diagnostics.Enable(logger)
+			}
+			// Oversized outputs can only be paginated when they can be read with fetch_more
+			if _, ok := registry.GetTool("fetch_more"); ok {
+				tools.EnablePagination()
+			}
+
 			// Initialise tool error logger after logging is configured
 			if err := tools.InitGlobalErrorLogger(logger); err != nil {
 				logger.WithError(err).Debug("Failed to initialise tool error logger")
@@ -446,16 +735,23 @@ func main() {
 				}
 			}
 
-			// Initialise security system (if enabled) - after logging is configured
+			// Sample memory use for get_diagnostics and the memory metrics, and log when memory
+			// intensive calls start and stop being refused
+			memusage.Start(cliCtx, logger)
+
+			// Initialise security system (if enabled) in the background - after logging is configured.
+			// Compiling the rules is slow, and tool calls wait for it to finish.
 			logger.Debug("Initialising security system")
-			if err := security.InitGlobalSecurityManager(); err != nil {
-				logger.WithError(err).Debug("Security initialisation failed")
-				if transport != "stdio" {
-					logger.WithError(err).Warn("Failed to initialise security system")
+			security.StartGlobalSecurityManager(func(err error) {
+				if err != nil {
+					logger.WithError(err).Debug("Security initialisation failed")
+					if transport != "stdio" {
+						logger.WithError(err).Warn("Failed to initialise security system")
+					}
+					return
 				}
-			} else {
 				logger.Debug("Security system initialised successfully")
-			}
+			})
 
 			// Only log startup info for non-stdio transports
 			if transport != "stdio" {
@@ -465,7 +761,23 @@ func main() {
 
 			// Create MCP server
 			logger.Debug("Creating MCP server")
-			mcpSrv := mcpserver.NewMCPServer("mcp-devtools", "MCP DevTools Server")
+			// Track client workspace roots per session for multi-project use, apply any per-user
+			// OAuth access policy to tool listings and calls, and list only the tools that suit
+			// the workspace's project types when WORKSPACE_TOOL_FILTER is set
+			hooks := &mcpserver.Hooks{}
+			mcpSrv := mcpserver.NewMCPServer("mcp-devtools", "MCP DevTools Server",
+				mcpserver.WithHooks(hooks),
+				mcpserver.WithToolFilter(access.FilterTools),
+				mcpserver.WithToolFilter(workspace.FilterTools),
+				mcpserver.WithToolHandlerMiddleware(access.Middleware),
+			)
+			workspace.Register(hooks, mcpSrv)
+			tools.RegisterScratch(hooks)
+			tools.RegisterAgentCost(hooks)
+
+			// Advertise the upstream proxy tools found by the last run straight away, as connecting
+			// to the upstreams can take a while
+			cachedProxyTools := proxy.RegisterCachedUpstreamTools(logger)
 
 			enabledTools := registry.GetEnabledTools()
 			logger.WithField("tool_count", len(enabledTools)).Debug("MCP server created, registering tools")
@@ -485,7 +797,15 @@ func main() {
 
 			// Register upstream proxy tools asynchronously (avoids blocking startup for OAuth)
 			// mcp-go will automatically notify connected clients via tools/list_changed
-			proxy.RegisterUpstreamToolsAsync(cliCtx, mcpSrv, logger, transport)
+			proxy.RegisterUpstreamToolsAsync(cliCtx, mcpSrv, logger, transport, cachedProxyTools)
+
+			// Run the tool calls declared in schedules.yaml while serving an HTTP transport, as a
+			// stdio server only runs while a client is connected
+			if transport != "stdio" {
+				if err := scheduler.Start(cliCtx, logger); err != nil {
+					logger.WithError(err).Warn("Failed to start scheduler")
+				}
+			}
 
 			// Handle browser-based OAuth authentication if enabled
 			if cmd.Bool("oauth-browser-auth") {
@@ -530,8 +850,7 @@ func main() {
 				return mcpserver.ServeStdio(mcpSrv)
 			case "sse":
 				logger.WithField("port", port).Debug("Starting SSE server")
-				sseServer := mcpserver.NewSSEServer(mcpSrv, mcpserver.WithBaseURL(baseURL+"/sse"))
-				return sseServer.Start(":" + port)
+				return startSSEServer(cliCtx, cmd, mcpSrv, logger)
 			case "http":
 				logger.WithField("port", port).Debug("Starting HTTP server")
 				return startStreamableHTTPServer(cliCtx, cmd, mcpSrv, logger)
@@ -574,6 +893,7 @@ func performCleanup(logger *logrus.Logger) {
 		// (stdio mode: no output allowed; non-stdio: logger might write to this file)
 		_ = file.Close()
 	}
+	logging.Close()
 
 	// Close the tool error logger if it was initialised
 	if errorLogger := tools.GetGlobalErrorLogger(); errorLogger != nil {
@@ -586,6 +906,17 @@ func performCleanup(logger *logrus.Logger) {
 	// Stop LSP client cleanup routine and close all cached LSP clients
 	// Uses Debug level logging internally - won't output in stdio mode
 	coderename.StopCleanupRoutine(registry.GetCache(), logger)
+
+	// Stop any other supervised processes still running, and the processes they started
+	supervisor.StopAll(2 * time.Second)
+
+	// Remove the per-session scratch directories
+	tools.CleanupScratch()
+
+	// Close upstream proxy connections, stopping stdio upstream processes
+	if err := proxy.GetGlobalProxyManager().Close(); err != nil {
+		logger.WithError(err).Debug("Failed to close proxy upstreams")
+	}
 }
 
 // startStreamableHTTPServer configures and starts the Streamable HTTP server with graceful shutdown
@@ -614,6 +945,15 @@ func startStreamableHTTPServer(ctx context.Context, cmd *cli.Command, mcpServer
 
 	// Check if OAuth is enabled
 	oauthEnabled := cmd.Bool("oauth-enabled")
+	if !oauthEnabled && cmd.String("oauth-access-policy") != "" {
+		return fmt.Errorf("oauth-access-policy requires oauth-enabled")
+	}
+
+	// Serve the hooks declared in hooks.yaml alongside the MCP endpoint
+	hooks, err := scheduler.StartHooks(ctx, authToken, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to start hooks")
+	}
 	if oauthEnabled {
 		// Configure OAuth 2.1
 		oauthConfig := &types.OAuth2Config{
@@ -641,6 +981,19 @@ func startStreamableHTTPServer(ctx context.Context, cmd *cli.Command, mcpServer
 		// Use OAuth middleware
 		opts = append(opts, mcpserver.WithHTTPContextFunc(createOAuthMiddleware(oauthServer, logger)))
 
+		// Scope tools, directories and quotas to each user when an access policy is configured
+		if policyPath := cmd.String("oauth-access-policy"); policyPath != "" {
+			policy, err := access.LoadPolicy(policyPath)
+			if err != nil {
+				return fmt.Errorf("invalid OAuth access policy: %w", err)
+			}
+			access.SetPolicy(policy)
+			logger.WithFields(logrus.Fields{
+				"users":  len(policy.Users),
+				"groups": len(policy.Groups),
+			}).Info("OAuth access policy enabled")
+		}
+
 		logger.Info("OAuth 2.1 authentication enabled")
 		logger.Infof("OAuth issuer: %s", oauthConfig.Issuer)
 		logger.Infof("OAuth audience: %s", oauthConfig.Audience)
@@ -659,6 +1012,11 @@ func startStreamableHTTPServer(ctx context.Context, cmd *cli.Command, mcpServer
 		// Register the main MCP endpoint
 		mux.Handle(endpointPath, httpServer)
 
+		// Hooks authenticate with their own bearer tokens rather than OAuth
+		if hooks != nil {
+			mux.Handle(scheduler.HooksPathPrefix, hooks)
+		}
+
 		// Start the server with custom mux and security timeouts
 		logger.Infof("OAuth endpoints available at %s/.well-known/", fullBaseURL)
 		server := &http.Server{
@@ -710,18 +1068,27 @@ func startStreamableHTTPServer(ctx context.Context, cmd *cli.Command, mcpServer
 	}
 
 	// Add heartbeat interval for keep-alive
-	heartbeatInterval := 30 * time.Second
-	if sessionTimeout > 0 {
-		// Set heartbeat to 1/4 of session timeout
-		heartbeatInterval = sessionTimeout / 4
-	}
+	heartbeatInterval := keepAliveInterval(sessionTimeout)
 	opts = append(opts, mcpserver.WithHeartbeatInterval(heartbeatInterval))
 
 	// Add logger
 	opts = append(opts, mcpserver.WithLogger(&logrusAdapter{logger: logger}))
 
+	// The default server only routes the MCP endpoint, so hooks need a server with their own route
+	var hookServer *http.Server
+	if hooks != nil {
+		hookServer = &http.Server{MaxHeaderBytes: 1 << 20}
+		opts = append(opts, mcpserver.WithStreamableHTTPServer(hookServer))
+	}
+
 	// Create streamable HTTP server
 	httpServer := mcpserver.NewStreamableHTTPServer(mcpServer, opts...)
+	if hookServer != nil {
+		mux := http.NewServeMux()
+		mux.Handle(endpointPath, httpServer)
+		mux.Handle(scheduler.HooksPathPrefix, hooks)
+		hookServer.Handler = mux
+	}
 
 	logger.Infof("Heartbeat interval: %v", heartbeatInterval)
 	logger.Info("Server supports multiple simultaneous connections")
@@ -735,6 +1102,99 @@ func startStreamableHTTPServer(ctx context.Context, cmd *cli.Command, mcpServer
 	return httpServer.Start(":" + port)
 }
 
+// startSSEServer starts the legacy SSE transport with the same token authentication, header validation
+// and heartbeats as the Streamable HTTP transport, and stops it gracefully on shutdown
+func startSSEServer(ctx context.Context, cmd *cli.Command, mcpServer *mcpserver.MCPServer, logger *logrus.Logger) error {
+	port := cmd.String("port")
+	authToken := cmd.String("auth-token")
+	baseURL := cmd.String("base-url")
+
+	// OAuth is only implemented for Streamable HTTP, so refuse to serve SSE without it rather than
+	// silently leaving the server open
+	if cmd.Bool("oauth-enabled") {
+		return fmt.Errorf("oauth-enabled is only supported with the http transport")
+	}
+
+	logger.Infof("Starting SSE server on port %s", port)
+
+	// The SSE stream stays open for the whole session, so there is no write timeout
+	server := &http.Server{
+		Addr:              ":" + port,
+		ReadTimeout:       30 * time.Second,  // Prevent slow loris attacks
+		ReadHeaderTimeout: 10 * time.Second,  // Bound header reads on the long-lived stream
+		IdleTimeout:       120 * time.Second, // Close idle connections
+		MaxHeaderBytes:    1 << 20,           // 1MB max header size
+	}
+
+	heartbeatInterval := keepAliveInterval(cmd.Duration("session-timeout"))
+	opts := []mcpserver.SSEOption{
+		// Clients POST messages to the endpoint URL sent on the stream, which must include the port
+		mcpserver.WithBaseURL(fmt.Sprintf("%s:%s/sse", baseURL, port)),
+		mcpserver.WithHTTPServer(server),
+		mcpserver.WithKeepAlive(true),
+		mcpserver.WithKeepAliveInterval(heartbeatInterval),
+		// Validates the protocol version and Origin headers, and the token when one is set
+		mcpserver.WithSSEContextFunc(mcpserver.SSEContextFunc(createAuthMiddleware(authToken, logger))),
+	}
+	if authToken != "" {
+		logger.Info("Legacy token authentication enabled")
+	}
+
+	sseServer := mcpserver.NewSSEServer(mcpServer, opts...)
+	server.Handler = sseServer
+
+	// Serve the hooks declared in hooks.yaml alongside the SSE endpoints
+	hooks, err := scheduler.StartHooks(ctx, authToken, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to start hooks")
+	}
+	if hooks != nil {
+		mux := http.NewServeMux()
+		mux.Handle(scheduler.HooksPathPrefix, hooks)
+		mux.Handle("/", sseServer)
+		server.Handler = mux
+	}
+
+	logger.Infof("Heartbeat interval: %v", heartbeatInterval)
+
+	// Start server in goroutine to allow graceful shutdown
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := sseServer.Start(server.Addr); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	// Wait for context cancellation or server error
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("SSE server failed: %w", err)
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received, stopping SSE server")
+	}
+
+	// Graceful shutdown with timeout, closing open SSE sessions so that their streams end
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := sseServer.Shutdown(shutdownCtx); err != nil {
+		logger.WithError(err).Error("SSE server shutdown failed")
+		return err
+	}
+
+	logger.Info("SSE server stopped gracefully")
+	return nil
+}
+
+// keepAliveInterval returns the heartbeat interval for HTTP transports, a quarter of the session
+// timeout when one is set
+func keepAliveInterval(sessionTimeout time.Duration) time.Duration {
+	if sessionTimeout > 0 {
+		return sessionTimeout / 4
+	}
+	return 30 * time.Second
+}
+
 // extractTraceContext extracts W3C Trace Context from HTTP request headers
 // This enables distributed tracing across HTTP boundaries
 func extractTraceContext(ctx context.Context, req *http.Request) context.Context {
@@ -1103,6 +1563,15 @@ func handleSecurityConfigDiff(cmd *cli.Command) error {
 		if userRules.Settings.SizeExceededBehaviour == "" && defaultRules.Settings.SizeExceededBehaviour != "" {
 			fmt.Printf("🆕 New setting available: size_exceeded_behaviour (default: %s)\n", defaultRules.Settings.SizeExceededBehaviour)
 		}
+		if userRules.Redaction.Enabled == nil && len(userRules.Redaction.DisabledRules) == 0 {
+			fmt.Printf("🆕 New setting available: redaction (secrets are redacted from tool output by default)\n")
+		}
+		if userRules.Unicode.Mode == "" {
+			fmt.Printf("🆕 New setting available: unicode_sanitisation (flag or strip zero-width, bidi and lookalike characters in tool output)\n")
+		}
+		if userRules.Injection.Enabled == nil && userRules.Injection.Action == "" {
+			fmt.Printf("🆕 New setting available: prompt_injection (fetched content is scored for prompt injection and warns by default)\n")
+		}
 	}
 
 	// Offer to update if requested
@@ -1117,13 +1586,17 @@ func handleSecurityConfigDiff(cmd *cli.Command) error {
 		}
 		fmt.Printf("📦 Backup created: %s\n", backupPath)
 
-		// Write the default config (user will need to manually merge customizations)
-		if err := os.WriteFile(configPath, []byte(defaultConfig), 0600); err != nil {
-			return fmt.Errorf("failed to update config: %w", err)
+		// Merge the new defaults into the user config, keeping the user's changes
+		_, report, err := security.MergeConfigUpdate(configPath, userConfigData, defaultConfig)
+		if err != nil {
+			return fmt.Errorf("failed to merge config: %w", err)
 		}
 
 		fmt.Printf("✅ Configuration updated: %s\n", configPath)
-		fmt.Println("⚠️  Note: Any custom rules have been backed up. Please review and re-add them manually if needed.")
+		for _, line := range report.Lines() {
+			fmt.Printf("   • %s\n", line)
+		}
+		fmt.Println("⚠️  Note: A summary of these changes has been added to the top of the config file.")
 	} else {
 		fmt.Println("\n💡 To update your configuration with new defaults, run:")
 		fmt.Printf("   mcp-devtools security-config-diff --update\n")
@@ -1133,6 +1606,403 @@ func handleSecurityConfigDiff(cmd *cli.Command) error {
 	return nil
 }
 
+// initToolRegistry parses the tool enablement environment variables without logging, for CLI commands.
+// Unlike serving, it also runs the slow checks of whether tools can run, so that their status is accurate.
+func initToolRegistry() {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	registry.Init(logger)
+	registry.CheckAvailability()
+	registerPlugins(logger)
+	registerPipelines(logger)
+}
+
+// registerPlugins registers the configured plugins as tools when plugins are enabled
+func registerPlugins(logger *logrus.Logger) {
+	if !tools.IsToolEnabled("plugins") {
+		return
+	}
+	if err := plugins.RegisterConfigured(logger); err != nil {
+		logger.WithError(err).Warn("Failed to register plugins")
+	}
+}
+
+// registerPipelines registers the configured pipelines as tools when pipelines are enabled
+func registerPipelines(logger *logrus.Logger) {
+	if !tools.IsToolEnabled("pipelines") {
+		return
+	}
+	if err := pipelines.RegisterConfigured(logger); err != nil {
+		logger.WithError(err).Warn("Failed to register pipelines")
+	}
+}
+
+// handleToolsList prints every known tool and whether it is enabled
+func handleToolsList() {
+	initToolRegistry()
+	statuses := registry.GetToolStatuses()
+
+	enabled, nameWidth := 0, len("TOOL")
+	for _, status := range statuses {
+		if status.Enabled {
+			enabled++
+		}
+		nameWidth = max(nameWidth, len(status.Name))
+	}
+
+	fmt.Printf("🧰 %d tools, %d enabled\n\n", len(statuses), enabled)
+	fmt.Printf("%-*s  %-8s  %s\n", nameWidth, "TOOL", "STATUS", "REASON")
+	for _, status := range statuses {
+		state := "disabled"
+		if status.Enabled {
+			state = "enabled"
+		}
+		fmt.Printf("%-*s  %-8s  %s\n", nameWidth, status.Name, state, status.Reason)
+	}
+}
+
+// handleToolsDescribe prints a tool's status and the definition it registers with MCP clients
+func handleToolsDescribe(name string) error {
+	if name == "" {
+		return fmt.Errorf("provide a tool name, e.g. mcp-devtools tools describe internet_search")
+	}
+	initToolRegistry()
+
+	status, ok := registry.GetToolStatus(name)
+	if !ok {
+		return fmt.Errorf("unknown tool %s, run 'mcp-devtools tools list' to see the available tools", name)
+	}
+
+	state := "disabled"
+	if status.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("🔧 %s: %s (%s)\n", status.Name, state, status.Reason)
+	if status.Tool == nil {
+		return nil
+	}
+
+	definition, err := json.MarshalIndent(status.Tool.Definition(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool definition: %w", err)
+	}
+	fmt.Printf("\n%s\n", definition)
+	return nil
+}
+
+// printError prints a CLI command's error, as the logger is not set up for CLI commands
+func printError(err error) error {
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+	return err
+}
+
+// handleCredentialsSet stores a credential in the OS keyring. The value is read from stdin so that it
+// stays out of shell history, with a prompt that does not echo it when stdin is a terminal.
+func handleCredentialsSet(name string) error {
+	if name == "" {
+		return fmt.Errorf("provide a credential name, e.g. mcp-devtools credentials set %s", credentials.GitHubToken)
+	}
+
+	var value string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Printf("🔑 Value for %s: ", name)
+		data, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("failed to read value: %w", err)
+		}
+		value = string(data)
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read value from stdin: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+
+	if err := credentials.Set(name, value); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Stored credential %s\n", name)
+	return nil
+}
+
+// handleCredentialsList prints the names of the stored credentials
+func handleCredentialsList() error {
+	names, err := credentials.List()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("No credentials stored, add one with: mcp-devtools credentials set <name>\n")
+		return nil
+	}
+	for _, name := range names {
+		fmt.Printf("🔑 %s\n", name)
+	}
+	return nil
+}
+
+// handleCredentialsDelete deletes a credential from the OS keyring
+func handleCredentialsDelete(name string) error {
+	if name == "" {
+		return fmt.Errorf("provide the name of the credential to delete")
+	}
+	if err := credentials.Delete(name); err != nil {
+		return err
+	}
+	fmt.Printf("🗑️  Deleted credential %s\n", name)
+	return nil
+}
+
+// handleSessionsList prints the recorded sessions
+func handleSessionsList() error {
+	sessions, err := recording.List()
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Printf("No sessions recorded, set %s=true when starting the server to record them\n", recording.EnvVar)
+		return nil
+	}
+	for _, session := range sessions {
+		calls := fmt.Sprintf("%d calls", session.Calls)
+		if session.Calls == 1 {
+			calls = "1 call"
+		}
+		fmt.Printf("%s  %s  %s\n", session.UpdatedAt.Local().Format("2006-01-02 15:04"), session.ID, calls)
+	}
+	return nil
+}
+
+// handleSessionsExport prints a session's transcript or saves it to a file
+func handleSessionsExport(cmd *cli.Command) error {
+	id := cmd.Args().First()
+	if id == "" {
+		return fmt.Errorf("provide the session id, see: mcp-devtools sessions list")
+	}
+	calls, err := recording.Load(id)
+	if err != nil {
+		return err
+	}
+	transcript, err := recording.Export(id, calls, cmd.String("format"), !cmd.Bool("no-results"))
+	if err != nil {
+		return err
+	}
+	if output := cmd.String("output"); output != "" {
+		if err := os.WriteFile(output, []byte(transcript), 0600); err != nil {
+			return fmt.Errorf("failed to save transcript: %w", err)
+		}
+		fmt.Printf("📝 Saved the transcript of %d calls to %s\n", len(calls), output)
+		return nil
+	}
+	fmt.Print(transcript)
+	return nil
+}
+
+// handleDoctor prints the prerequisite checks of the enabled tools, returning an error when any failed
+func handleDoctor(ctx context.Context, all bool) error {
+	initToolRegistry()
+	results := doctor.Run(ctx, all)
+
+	fmt.Printf("🩺 Checking tool prerequisites\n\n")
+	icons := map[string]string{
+		tools.PrerequisiteOK:      "✅",
+		tools.PrerequisiteWarning: "⚠️ ",
+		tools.PrerequisiteFailed:  "❌",
+	}
+	for _, result := range results {
+		fmt.Printf("%s %s - %s: %s\n", icons[result.Status], result.Tool, result.Name, result.Detail)
+		if result.Remediation != "" && result.Status != tools.PrerequisiteOK {
+			fmt.Printf("   → %s\n", result.Remediation)
+		}
+	}
+
+	passed, warnings, failed := doctor.Summarise(results)
+	if len(results) == 0 {
+		fmt.Printf("No enabled tools have prerequisites to check\n")
+	}
+	fmt.Printf("\n%d passed, %d warnings, %d failed\n", passed, warnings, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d prerequisite checks failed", failed)
+	}
+	return nil
+}
+
+// handleBench runs the benchmark workloads and prints their timings, comparing against a baseline if given
+func handleBench(ctx context.Context, cmd *cli.Command) error {
+	initToolRegistry()
+
+	workloads := bench.Workloads()
+	if names := cmd.StringSlice("workload"); len(names) > 0 {
+		var selected []bench.Workload
+		for _, name := range names {
+			index := slices.IndexFunc(workloads, func(w bench.Workload) bool { return w.Name == name })
+			if index < 0 {
+				available := make([]string, 0, len(workloads))
+				for _, w := range workloads {
+					available = append(available, w.Name)
+				}
+				return fmt.Errorf("unknown workload %s (available workloads: %s)", name, strings.Join(available, ", "))
+			}
+			selected = append(selected, workloads[index])
+		}
+		workloads = selected
+	}
+
+	var baseline *bench.Report
+	if baselinePath := cmd.String("baseline"); baselinePath != "" {
+		var err error
+		if baseline, err = bench.LoadReport(baselinePath); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("⏱️  Running %d workloads, %d iterations each\n\n", len(workloads), cmd.Int("iterations"))
+	report, err := bench.Run(ctx, workloads, cmd.Int("iterations"), Version)
+	if err != nil {
+		return err
+	}
+
+	nameWidth := len("WORKLOAD")
+	for _, result := range report.Results {
+		nameWidth = max(nameWidth, len(result.Name))
+	}
+	fmt.Printf("%-*s  %12s  %12s  %12s  %12s\n", nameWidth, "WORKLOAD", "MEDIAN", "MIN", "BYTES/OP", "ALLOCS/OP")
+	for _, result := range report.Results {
+		if result.Skipped != "" {
+			fmt.Printf("%-*s  skipped: %s\n", nameWidth, result.Name, result.Skipped)
+			continue
+		}
+		fmt.Printf("%-*s  %12s  %12s  %12d  %12d\n", nameWidth, result.Name,
+			time.Duration(result.NsPerOp).Round(time.Microsecond), time.Duration(result.MinNs).Round(time.Microsecond),
+			result.BytesPerOp, result.AllocsPerOp)
+	}
+
+	if outputPath := cmd.String("output"); outputPath != "" {
+		if err := bench.SaveReport(report, outputPath); err != nil {
+			return err
+		}
+		fmt.Printf("\n💾 Results saved to %s\n", outputPath)
+	}
+
+	if baseline == nil {
+		return nil
+	}
+	regressions := bench.Compare(baseline, report, cmd.Float("threshold"))
+	if len(regressions) == 0 {
+		fmt.Printf("\n✅ No regressions over %.0f%% against %s\n", cmd.Float("threshold"), baseline.Version)
+		return nil
+	}
+	fmt.Printf("\n📉 Regressions against %s:\n", baseline.Version)
+	for _, regression := range regressions {
+		fmt.Printf("   %s %s: %.0f → %.0f (+%.1f%%)\n", regression.Name, regression.Metric, regression.Baseline, regression.Current, regression.Change)
+	}
+	return fmt.Errorf("%d regressions over %.0f%%", len(regressions), cmd.Float("threshold"))
+}
+
+// handleLogsTail prints the end of a log file. It runs as a separate command rather than in the
+// server, so writing to stdout cannot interfere with a stdio transport.
+func handleLogsTail(ctx context.Context, cmd *cli.Command) error {
+	logDir, err := logging.Dir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(logDir, logging.LogFileName)
+	switch {
+	case cmd.Bool("errors") && cmd.String("tool") != "":
+		return fmt.Errorf("--errors and --tool cannot be used together")
+	case cmd.Bool("errors"):
+		path = filepath.Join(logDir, "tool-errors.log")
+	case cmd.String("tool") != "":
+		path = logging.ToolLogPath(logDir, cmd.String("tool"))
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no log file at %s", path)
+	}
+
+	return logging.Tail(ctx, path, cmd.Int("lines"), cmd.Bool("follow"), os.Stdout)
+}
+
+// handleSecurityTest reports which security rules match a sample and the resulting action
+func handleSecurityTest(cmd *cli.Command) error {
+	// Get config path
+	configPath := cmd.String("config-path")
+	if configPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configPath = fmt.Sprintf("%s/.mcp-devtools/security.yaml", homeDir)
+	}
+
+	// Use the default rules when the user has no config yet
+	configData, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		configPath = "(default configuration)"
+		configData = []byte(security.GenerateDefaultConfig())
+	} else if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Read the sample
+	sample := strings.Join(cmd.Args().Slice(), " ")
+	if filePath := cmd.String("file"); filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read sample file: %w", err)
+		}
+		sample = string(data)
+	}
+	if sample == "" {
+		return fmt.Errorf("provide sample text as an argument or with --file")
+	}
+
+	source := security.SourceContext{
+		Domain: cmd.String("domain"),
+		Tool:   cmd.String("tool"),
+	}
+	if source.Domain != "" {
+		source.URL = "https://" + source.Domain + "/"
+	}
+
+	report, err := security.CheckRulesFromConfig(configData, sample, source, cmd.String("rule"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧪 Testing %d byte sample against %s\n", len(sample), configPath)
+	fmt.Println()
+
+	if report.SizeLimit != "" {
+		fmt.Printf("📏 Size limit: %s\n", report.SizeLimit)
+	}
+	if len(report.Matches) == 0 {
+		fmt.Println("No rules matched")
+	}
+	for i, match := range report.Matches {
+		fmt.Printf("%d. %s (action: %s, priority: %d)\n", i+1, match.Rule, match.Action, match.Priority)
+		fmt.Printf("   %s\n", match.Description)
+		for _, pattern := range match.Patterns {
+			fmt.Printf("   matched %s\n", pattern)
+		}
+	}
+	if report.PromptInjection != "" {
+		fmt.Printf("🛡️  Prompt injection: %s\n", report.PromptInjection)
+	}
+
+	fmt.Println()
+	if report.DecidedBy != "" {
+		fmt.Printf("Result: %s (decided by %s)\n", report.Action, report.DecidedBy)
+	} else {
+		fmt.Printf("Result: %s\n", report.Action)
+	}
+	return nil
+}
+
 // handleSecurityConfigValidate validates the security configuration file
 func handleSecurityConfigValidate(cmd *cli.Command) error {
 	// Get config path
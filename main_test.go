@@ -1,16 +1,24 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/sammcj/mcp-devtools/internal/registry"
 	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
 )
 
 // fakeTool is a minimal tools.Tool used to drive newToolHandler in tests.
@@ -110,3 +118,112 @@ func TestNewToolHandler_SuccessPassesResultThrough(t *testing.T) {
 		t.Errorf("expected result text %q, got %q", "ok", got)
 	}
 }
+
+// The SSE server must advertise a message endpoint clients can reach, and stop when its context is
+// cancelled rather than running until the process is killed.
+func TestStartSSEServer_ServesAndShutsDownGracefully(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	_ = listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := &cli.Command{
+		Name: "test",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "port"},
+			&cli.StringFlag{Name: "base-url", Value: "http://localhost"},
+			&cli.StringFlag{Name: "auth-token"},
+			&cli.DurationFlag{Name: "session-timeout"},
+			&cli.BoolFlag{Name: "oauth-enabled"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return startSSEServer(ctx, cmd, mcpserver.NewMCPServer("test", "1.0"), quietLogger())
+		},
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Run(ctx, []string{"test", "--port", port}) }()
+
+	var resp *http.Response
+	for range 50 {
+		if resp, err = http.Get("http://127.0.0.1:" + port + "/sse/sse"); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("SSE server did not start: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("failed to read endpoint event: %v", err)
+	}
+	endpoint, _ := reader.ReadString('\n')
+	if !strings.HasPrefix(endpoint, "data: http://localhost:"+port+"/sse/message?sessionId=") {
+		t.Errorf("expected the endpoint to include the port, got %q", endpoint)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("SSE server did not shut down")
+	}
+}
+
+func TestStartSSEServer_RejectsOAuth(t *testing.T) {
+	cmd := &cli.Command{
+		Name:  "test",
+		Flags: []cli.Flag{&cli.BoolFlag{Name: "oauth-enabled"}},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return startSSEServer(ctx, cmd, mcpserver.NewMCPServer("test", "1.0"), quietLogger())
+		},
+	}
+	if err := cmd.Run(context.Background(), []string{"test", "--oauth-enabled"}); err == nil {
+		t.Error("expected an error enabling OAuth with the SSE transport")
+	}
+}
+
+func TestApplyBuildInfo(t *testing.T) {
+	setVersion := func(version, commit, buildDate string) {
+		Version, Commit, BuildDate = version, commit, buildDate
+	}
+	defer setVersion(Version, Commit, BuildDate)
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/sammcj/mcp-devtools", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	// Defaults are replaced with the embedded metadata
+	setVersion("dev", "none", "unknown")
+	applyBuildInfo(info)
+	if Version != "1.2.3" || Commit != "0123456-dirty" || BuildDate != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected embedded version information, got %q %q %q", Version, Commit, BuildDate)
+	}
+
+	// Values set with -ldflags take precedence
+	setVersion("9.9.9", "abcdef0", "2026-10-01T00:00:00Z")
+	applyBuildInfo(info)
+	if Version != "9.9.9" || Commit != "abcdef0" || BuildDate != "2026-10-01T00:00:00Z" {
+		t.Errorf("expected -ldflags version information to be kept, got %q %q %q", Version, Commit, BuildDate)
+	}
+
+	// A development build without VCS metadata keeps the defaults
+	setVersion("dev", "none", "unknown")
+	applyBuildInfo(&debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+	if Version != "dev" || Commit != "none" || BuildDate != "unknown" {
+		t.Errorf("expected default version information, got %q %q %q", Version, Commit, BuildDate)
+	}
+}
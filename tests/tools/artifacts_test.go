@@ -0,0 +1,135 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	artifactstool "github.com/sammcj/mcp-devtools/internal/tools/utilities/artifacts"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+// artifactSession returns a context for a tool call from the named session
+func artifactSession(sessionID string) context.Context {
+	return mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), mcpserver.NewInProcessSession(sessionID, nil))
+}
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestArtifacts_ListAndGetBySession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(tools.OutputPageSizeEnvVar, "1000")
+	ctx := artifactSession("artifacts-a")
+
+	var lines []string
+	for i := range 300 {
+		lines = append(lines, fmt.Sprintf("row %03d ✓", i))
+	}
+	content := strings.Join(lines, "\n")
+	saved, err := artifacts.Save(ctx, "excel", "../sheet.csv", "text/csv", "Exported sheet", []byte(content))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "sheet.csv", saved.Name)
+	info, err := os.Stat(saved.Path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The session's artifacts are listed, and other sessions see none of them
+	tool := &artifactstool.ArtifactsTool{}
+	result, err := tool.Execute(ctx, quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	var listed artifactstool.Response
+	testutils.AssertNoError(t, json.Unmarshal([]byte(pageText(t, result)), &listed))
+	testutils.AssertEqual(t, 1, len(listed.Artifacts))
+	testutils.AssertEqual(t, saved.ID, listed.Artifacts[0].ID)
+
+	other := artifactSession("artifacts-b")
+	result, err = tool.Execute(other, quietLogger(), &sync.Map{}, map[string]any{"action": "list"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, json.Unmarshal([]byte(pageText(t, result)), &listed))
+	testutils.AssertEqual(t, 0, len(listed.Artifacts))
+	_, err = tool.Execute(other, quietLogger(), &sync.Map{}, map[string]any{"action": "get", "id": saved.ID})
+	testutils.AssertError(t, err)
+
+	// Reading in parts returns the whole content without splitting characters
+	var read strings.Builder
+	offset := 0.0
+	for range 20 {
+		result, err := tool.Execute(ctx, quietLogger(), &sync.Map{}, map[string]any{"action": "get", "id": saved.ID, "offset": offset})
+		testutils.AssertNoError(t, err)
+		part := pageText(t, result)
+		body, footer, more := strings.Cut(part, "\n\n[Artifact ")
+		read.WriteString(body)
+		if !more {
+			break
+		}
+		_, err = fmt.Sscanf(footer[strings.Index(footer, "offset ")+len("offset "):], "%f", &offset)
+		testutils.AssertNoError(t, err)
+	}
+	testutils.AssertEqual(t, content, read.String())
+
+	// IDs cannot reach outside the session directory
+	_, err = artifacts.Get(ctx, "../artifacts-b")
+	testutils.AssertError(t, err)
+
+	_, err = tool.Execute(ctx, quietLogger(), &sync.Map{}, map[string]any{"action": "get"})
+	testutils.AssertErrorContains(t, err, "missing required parameter: id")
+	_, err = tool.Execute(ctx, quietLogger(), &sync.Map{}, map[string]any{"action": "delete", "id": saved.ID})
+	testutils.AssertErrorContains(t, err, "invalid action")
+}
+
+func TestArtifacts_ExpiredAreRemoved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := artifactSession("artifacts-expiry")
+
+	saved, err := artifacts.Save(ctx, "excel", "old.txt", "text/plain", "", []byte("old"))
+	testutils.AssertNoError(t, err)
+
+	// Back-date the artifact's expiry
+	metadataPath := filepath.Join(filepath.Dir(saved.Path), "metadata.json")
+	saved.ExpiresAt = time.Now().Add(-time.Minute)
+	data, err := json.Marshal(saved)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, os.WriteFile(metadataPath, data, 0600))
+
+	_, err = artifacts.Get(ctx, saved.ID)
+	testutils.AssertError(t, err)
+	// Tool caches in the store are left to their tools
+	cacheDir, err := artifacts.CacheDir("process_document")
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, os.MkdirAll(cacheDir, 0700))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(cacheDir, "entry.json"), []byte("{}"), 0600))
+
+	artifacts.Cleanup()
+	_, err = os.Stat(filepath.Dir(saved.Path))
+	testutils.AssertTrue(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cacheDir, "entry.json"))
+	testutils.AssertNoError(t, err)
+}
+
+func TestArtifacts_SummarisedOutputIsSaved(t *testing.T) {
+	output := strings.Repeat("line of output\n", 500)
+	summary := summarise(t, output)
+
+	matches := fullOutputPattern.FindStringSubmatch(summary)
+	testutils.AssertEqual(t, 2, len(matches))
+	saved, err := artifacts.List(artifactSession("summarise"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(saved))
+	testutils.AssertEqual(t, matches[1], saved[0].Path)
+	testutils.AssertEqual(t, "excel", saved[0].Tool)
+}
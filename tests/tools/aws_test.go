@@ -5,7 +5,9 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sammcj/mcp-devtools/internal/tools/aws_documentation"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -569,6 +571,30 @@ func TestAWSDocumentationTool_Execute_GetServicePricingAction(t *testing.T) {
 			},
 			expectedError: "filter at index 0 is not a valid object",
 		},
+		{
+			name: "invalid region code",
+			args: map[string]any{
+				"action":       "get_service_pricing",
+				"service_code": "AmazonEC2",
+				"region":       "US East (N. Virginia)",
+			},
+			expectedError: "region must be an AWS region code",
+		},
+		{
+			name: "instance_type duplicates filter",
+			args: map[string]any{
+				"action":        "get_service_pricing",
+				"service_code":  "AmazonEC2",
+				"instance_type": "m7g.large",
+				"filters": []any{
+					map[string]any{
+						"field": "instanceType",
+						"value": "t3.micro",
+					},
+				},
+			},
+			expectedError: "instanceType is already set in filters",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -580,3 +606,27 @@ func TestAWSDocumentationTool_Execute_GetServicePricingAction(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSDocumentationTool_Execute_SearchUsesCache(t *testing.T) {
+	tool := &aws.AWSDocumentationTool{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel) // Suppress output during tests
+	cache := &sync.Map{}
+
+	cache.Store("aws_documentation:search:5:s3 versioning", aws.CacheEntry{
+		Data: []aws.SearchResult{
+			{RankOrder: 1, URL: "https://docs.aws.amazon.com/AmazonS3/latest/userguide/Versioning.html", Title: "Cached result"},
+		},
+		Timestamp: time.Now(),
+	})
+
+	result, err := tool.Execute(context.Background(), logger, cache, map[string]any{
+		"action":        "search",
+		"search_phrase": "S3 Versioning",
+	})
+	require.NoError(t, err)
+
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "Cached result")
+}
@@ -0,0 +1,109 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools/utilities/batch"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+// readOnlyEchoTool is echoArgsTool marked read-only, waiting for its context to end when asked to
+type readOnlyEchoTool struct {
+	*echoArgsTool
+}
+
+func (t readOnlyEchoTool) Definition() mcp.Tool {
+	definition := t.echoArgsTool.Definition()
+	definition.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+	return definition
+}
+
+func (t readOnlyEchoTool) Execute(ctx context.Context, logger *logrus.Logger, cache *sync.Map, args map[string]any) (*mcp.CallToolResult, error) {
+	if _, ok := args["hang"]; ok {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return t.echoArgsTool.Execute(ctx, logger, cache, args)
+}
+
+// runBatch registers the tools a batch calls and runs it
+func runBatch(t *testing.T, args map[string]any) (batch.Response, error) {
+	t.Helper()
+	t.Setenv("ENABLE_ADDITIONAL_TOOLS", "batch,batch-echo,batch-write")
+	registry.Init(quietLogger())
+	registry.Register(readOnlyEchoTool{&echoArgsTool{MockTool: testutils.NewMockTool("batch-echo")}})
+	registry.Register(&echoArgsTool{MockTool: testutils.NewMockTool("batch-write")})
+	registry.Register(testutils.NewMockTool("batch-not-enabled"))
+
+	var response batch.Response
+	result, err := (&batch.BatchTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return response, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return response, nil
+}
+
+func TestBatch_RunsCallsIndependently(t *testing.T) {
+	calls := []any{
+		map[string]any{"tool": "batch-echo", "arguments": map[string]any{"package": "react"}},
+		map[string]any{"tool": "batch-echo", "arguments": map[string]any{"fail": "not found"}},
+		map[string]any{"tool": "batch-write", "arguments": map[string]any{}},
+		map[string]any{"tool": "batch-not-enabled"},
+		map[string]any{"tool": "batch"},
+		map[string]any{"tool": "batch-echo", "arguments": map[string]any{"panic": true}},
+		map[string]any{"tool": "batch-echo", "arguments": map[string]any{"hang": true}},
+		map[string]any{"tool": "batch-echo"},
+	}
+	response, err := runBatch(t, map[string]any{"calls": calls, "concurrency": float64(3), "timeout": float64(1)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 8, response.Calls)
+	testutils.AssertEqual(t, 2, response.Succeeded)
+	testutils.AssertEqual(t, 6, response.Failed)
+
+	results := response.Results
+	for i, result := range results {
+		testutils.AssertEqual(t, i, result.Index)
+	}
+	// JSON output is embedded as JSON
+	testutils.AssertTrue(t, results[0].OK)
+	var output map[string]any
+	testutils.AssertNoError(t, json.Unmarshal(results[0].Output, &output))
+	testutils.AssertEqual(t, "react", output["package"])
+	testutils.AssertEqual(t, "not found", results[1].Error)
+	testutils.AssertEqual(t, "tool batch-write is not read-only, call it directly", results[2].Error)
+	testutils.AssertEqual(t, "tool batch-not-enabled is not enabled", results[3].Error)
+	testutils.AssertEqual(t, "a batch cannot call batch", results[4].Error)
+	testutils.AssertEqual(t, "tool batch-echo panicked: tool bug", results[5].Error)
+	testutils.AssertTrue(t, strings.HasPrefix(results[6].Error, "timed out after 1s"))
+	testutils.AssertTrue(t, results[7].OK)
+	testutils.AssertEqual(t, "{}", string(results[7].Output))
+}
+
+func TestBatch_InvalidArguments(t *testing.T) {
+	_, err := runBatch(t, map[string]any{})
+	testutils.AssertErrorContains(t, err, "calls is required")
+
+	_, err = runBatch(t, map[string]any{"calls": []any{map[string]any{"arguments": map[string]any{}}}})
+	testutils.AssertErrorContains(t, err, "call 0 has no tool")
+
+	_, err = runBatch(t, map[string]any{"calls": []any{map[string]any{"tool": "batch-echo", "arguments": "react"}}})
+	testutils.AssertErrorContains(t, err, "arguments of call 0 must be an object")
+
+	tooMany := make([]any, 51)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"tool": "batch-echo"}
+	}
+	_, err = runBatch(t, map[string]any{"calls": tooMany})
+	testutils.AssertErrorContains(t, err, "at most 50 calls")
+
+	_, err = runBatch(t, map[string]any{"calls": []any{map[string]any{"tool": "batch-echo"}}, "concurrency": float64(0)})
+	testutils.AssertErrorContains(t, err, "concurrency must be a whole number")
+}
@@ -0,0 +1,280 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/buildtargets"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// buildTargetsMakefile has a self-documented target, a commented one, an include and rules that
+// are not targets
+const buildTargetsMakefile = `VERSION ?= dev
+-include mk/*.mk
+
+##@ Build
+build: deps | out ## Compile the binary
+	@echo building $(VERSION)
+
+# Fetch modules
+deps:
+	@echo deps
+
+out:
+	@mkdir -p out
+
+loud:
+	@for i in $$(seq 1 50); do echo line $$i; done
+
+fail:
+	@echo broken >&2; exit 2
+
+slow:
+	@sleep 30
+
+%.o: %.c
+	cc -c $<
+
+.PHONY: build deps loud fail slow
+`
+
+// runBuildTargets calls the build_targets tool and decodes its result into out
+func runBuildTargets(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&buildtargets.BuildTargetsTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+// findTarget returns the named target of a build file
+func findTarget(t *testing.T, buildFile buildtargets.BuildFile, name string) buildtargets.Target {
+	t.Helper()
+	for _, target := range buildFile.Targets {
+		if target.Name == name {
+			return target
+		}
+	}
+	t.Fatalf("target %s not found in %s", name, buildFile.File)
+	return buildtargets.Target{}
+}
+
+func TestBuildTargets_Makefile(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Makefile":    buildTargetsMakefile,
+		"mk/lint.mk":  "lint: ## Run linters\n\t@echo lint\n",
+		"mk/notes.md": "not: included\n",
+	})
+
+	var out buildtargets.ListResult
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "list", "path": dir}, &out))
+	testutils.AssertEqual(t, 1, len(out.BuildFiles))
+	makefile := out.BuildFiles[0]
+	testutils.AssertEqual(t, "make", makefile.System)
+	testutils.AssertEqual(t, "Makefile", makefile.File)
+	// The first target make reads is in the included file
+	testutils.AssertEqual(t, "lint", makefile.Default)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"mk/lint.mk"}, makefile.Includes))
+	testutils.AssertEqual(t, 7, len(makefile.Targets))
+
+	build := findTarget(t, makefile, "build")
+	testutils.AssertEqual(t, "Compile the binary", build.Description)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"deps", "out"}, build.Dependencies))
+	testutils.AssertEqual(t, 5, build.Line)
+	testutils.AssertEqual(t, "Fetch modules", findTarget(t, makefile, "deps").Description)
+	testutils.AssertEqual(t, "mk/lint.mk", findTarget(t, makefile, "lint").File)
+	testutils.AssertFalse(t, out.RunEnabled)
+}
+
+func TestBuildTargets_Taskfile(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Taskfile.yml": `version: '3'
+includes:
+  docs: ./docs
+  tools:
+    taskfile: ./tools/Taskfile.yml
+    flatten: true
+  secret:
+    taskfile: ./secret.yml
+    internal: true
+  remote: https://example.com/Taskfile.yml
+tasks:
+  default: task --list
+  build:
+    desc: Build the service
+    deps: [generate, {task: fmt}]
+    aliases: [b]
+  generate:
+    summary: |
+      Generate code
+      from the protobuf definitions
+  setup:
+    internal: true
+    cmds: [echo setup]
+`,
+		"docs/Taskfile.yml":  "version: '3'\ntasks:\n  serve:\n    desc: Serve the docs\n    deps: [build, ':generate']\n  build: echo docs\n",
+		"tools/Taskfile.yml": "version: '3'\ntasks:\n  install:\n    desc: Install tools\n",
+		"secret.yml":         "version: '3'\ntasks:\n  hidden: echo\n",
+	})
+
+	var out buildtargets.ListResult
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "list", "path": dir}, &out))
+	taskfile := out.BuildFiles[0]
+	testutils.AssertEqual(t, "task", taskfile.System)
+	testutils.AssertEqual(t, "default", taskfile.Default)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"docs/Taskfile.yml", "tools/Taskfile.yml"}, taskfile.Includes))
+
+	var names []string
+	for _, target := range taskfile.Targets {
+		names = append(names, target.Name)
+	}
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"default", "build", "generate", "docs:serve", "docs:build", "install"}, names))
+
+	build := findTarget(t, taskfile, "build")
+	testutils.AssertEqual(t, "Build the service", build.Description)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"generate", "fmt"}, build.Dependencies))
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"b"}, build.Aliases))
+	testutils.AssertEqual(t, 13, build.Line)
+	testutils.AssertEqual(t, "Generate code", findTarget(t, taskfile, "generate").Description)
+
+	serve := findTarget(t, taskfile, "docs:serve")
+	testutils.AssertEqual(t, "docs/Taskfile.yml", serve.File)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"docs:build", "generate"}, serve.Dependencies))
+}
+
+func TestBuildTargets_Justfile(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"justfile": `set shell := ["bash", "-c"]
+alias b := build
+version := "1.0"
+export TAG := ` + "`git describe --tags`" + `
+
+# Build with a profile
+build profile='release' *flags: clean (compile profile) && notify
+    cargo build --profile {{profile}} {{flags}}
+
+[doc("Remove build outputs")]
+[no-cd, group('clean')]
+clean:
+    rm -rf target
+
+[private]
+compile profile:
+    echo {{profile}}
+
+_helper:
+    echo
+
+@notify:
+    echo done
+`,
+	})
+
+	var out buildtargets.ListResult
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "list", "path": filepath.Join(dir, "justfile")}, &out))
+	justfile := out.BuildFiles[0]
+	testutils.AssertEqual(t, "just", justfile.System)
+	testutils.AssertEqual(t, "build", justfile.Default)
+	testutils.AssertEqual(t, 3, len(justfile.Targets))
+
+	build := findTarget(t, justfile, "build")
+	testutils.AssertEqual(t, "Build with a profile", build.Description)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"profile='release'", "*flags"}, build.Parameters))
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"clean", "compile", "notify"}, build.Dependencies))
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"b"}, build.Aliases))
+	testutils.AssertEqual(t, 7, build.Line)
+	testutils.AssertEqual(t, "Remove build outputs", findTarget(t, justfile, "clean").Description)
+	findTarget(t, justfile, "notify")
+}
+
+func TestBuildTargets_Run(t *testing.T) {
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make is not installed")
+	}
+	dir := writeProject(t, map[string]string{"Makefile": buildTargetsMakefile})
+
+	var out buildtargets.RunResult
+	err := runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "build"}, &out)
+	testutils.AssertErrorContains(t, err, buildtargets.AllowRunEnvVar)
+
+	t.Setenv(buildtargets.AllowRunEnvVar, "true")
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{
+		"function":  "run",
+		"path":      dir,
+		"target":    "build",
+		"variables": []any{"VERSION=1.2.0"},
+	}, &out))
+	testutils.AssertTrue(t, out.Succeeded)
+	testutils.AssertEqual(t, "make", out.System)
+	testutils.AssertTrue(t, strings.HasSuffix(out.Command, "make -f Makefile build VERSION=1.2.0"))
+	testutils.AssertEqual(t, "deps\nbuilding 1.2.0", out.Output)
+
+	out = buildtargets.RunResult{}
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "loud", "max_output_lines": float64(9)}, &out))
+	testutils.AssertEqual(t, 50, out.OutputLines)
+	testutils.AssertTrue(t, strings.HasPrefix(out.Output, "line 1\nline 2\nline 3\n... [41 lines omitted] ...\nline 45\n"))
+	testutils.AssertTrue(t, strings.HasSuffix(out.Output, "line 50"))
+
+	out = buildtargets.RunResult{}
+	testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "fail"}, &out))
+	testutils.AssertFalse(t, out.Succeeded)
+	testutils.AssertEqual(t, 2, out.ExitCode)
+	testutils.AssertTrue(t, strings.HasPrefix(out.Output, "broken\n"))
+
+	if runtime.GOOS != "windows" {
+		out = buildtargets.RunResult{}
+		testutils.AssertNoError(t, runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "slow", "timeout": float64(1)}, &out))
+		testutils.AssertTrue(t, out.TimedOut)
+		testutils.AssertFalse(t, out.Succeeded)
+	}
+}
+
+func TestBuildTargets_Errors(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Makefile": "build:\n\t@echo make\n",
+		"justfile": "build:\n    echo just\n",
+		"notes.md": "",
+	})
+	t.Setenv(buildtargets.AllowRunEnvVar, "true")
+
+	var out buildtargets.RunResult
+	err := runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "deploy"}, &out)
+	testutils.AssertErrorContains(t, err, "not found. Available targets: build")
+
+	err = runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "build"}, &out)
+	testutils.AssertErrorContains(t, err, "more than one build file")
+
+	err = runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "build", "system": "make", "arguments": []any{"x"}}, &out)
+	testutils.AssertErrorContains(t, err, "take no arguments")
+
+	err = runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "build", "system": "make", "variables": []any{"$(shell id)"}}, &out)
+	testutils.AssertErrorContains(t, err, "expected NAME=value")
+
+	err = runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "--eval=x"}, &out)
+	testutils.AssertErrorContains(t, err, "cannot start with '-'")
+
+	err = runBuildTargets(t, map[string]any{"function": "list", "path": filepath.Join(dir, "notes.md")}, &out)
+	testutils.AssertErrorContains(t, err, "not a Makefile")
+
+	err = runBuildTargets(t, map[string]any{"function": "list", "path": t.TempDir()}, &out)
+	testutils.AssertErrorContains(t, err, "no Makefile, Taskfile or justfile")
+
+	err = runBuildTargets(t, map[string]any{"function": "list", "path": dir, "system": "bazel"}, &out)
+	testutils.AssertErrorContains(t, err, "invalid system")
+
+	testutils.AssertNoError(t, os.Remove(filepath.Join(dir, "justfile")))
+	err = runBuildTargets(t, map[string]any{"function": "run", "path": dir, "target": "build", "timeout": float64(0)}, &out)
+	testutils.AssertErrorContains(t, err, "timeout")
+}
@@ -0,0 +1,150 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/calc"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// calcResult is the response to a single calc expression
+type calcResult struct {
+	calc.Result
+	Note string `json:"note"`
+}
+
+// runCalc evaluates one expression with the calc tool
+func runCalc(t *testing.T, args map[string]any) (calcResult, error) {
+	t.Helper()
+	var response calcResult
+	result, err := (&calc.CalcTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return response, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return response, nil
+}
+
+func TestCalc_Expressions(t *testing.T) {
+	tests := []struct {
+		expression  string
+		result      string
+		approximate bool
+	}{
+		// Exact decimal arithmetic
+		{"0.1 + 0.2", "0.3", false},
+		{"1 / 3", "0.3333333333", true},
+		{"2^64", "18446744073709551616", false},
+		{"2^-2", "0.25", false},
+		{"(10 + 5) / 4", "3.75", false},
+		{"1_000_000 * 1.5e-3", "1500", false},
+		{"-2^2", "-4", false},
+		{"7 % 3", "1", false},
+		{"2^0.5", "1.4142135624", true},
+
+		// Byte sizes and transfer rates
+		{"1536 MiB to GiB", "1.5 GiB", false},
+		{"3 * 512 GiB to TB", "1.6492674417 TB", true},
+		{"2 GiB", "2 GiB", false},
+		{"1.5 TB / 100 Mbps to h", "33.3333333333 h", true},
+		{"500 GB / 2", "250 GB", false},
+		{"10 GB / 5 s", "2 GB/s", false},
+		{"1 TB / 1 Gbit/s", "2.2222222222 h", true},
+		{"100 GB / 1 GB", "100", false},
+
+		// Durations
+		{"90 min to h", "1.5 h", false},
+		{"100 min % 1 h", "40 min", false},
+		{"36 h", "36 h", false},
+		{"2 weeks to days", "14 d", false},
+
+		// Dates
+		{"2026-03-01 + 90 days", "2026-05-30 (Saturday)", false},
+		{"2026-01-31 + 1 month", "2026-02-28 (Saturday)", false},
+		{"2026-03-31 - 1 month", "2026-02-28 (Saturday)", false},
+		{"2026-01-31 + 13 months", "2027-02-28 (Sunday)", false},
+		{"2026-01-31 + 2 months", "2026-03-31 (Tuesday)", false},
+		{"2024-02-29 + 1 year", "2025-02-28 (Friday)", false},
+		{"2026-12-25 - 2026-10-18", "68 d", false},
+		{"2026-03-01T09:00:00Z + 90 min", "2026-03-01T10:30:00Z (Sunday)", false},
+		{"2026-03-01 - 2 weeks", "2026-02-15 (Sunday)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			response, err := runCalc(t, map[string]any{"expression": tt.expression})
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tt.result, response.Result.Result)
+			testutils.AssertEqual(t, tt.approximate, response.Approximate)
+			testutils.AssertEqual(t, "", response.Note)
+		})
+	}
+}
+
+func TestCalc_Currencies(t *testing.T) {
+	response, err := runCalc(t, map[string]any{"expression": "100 GBP to USD"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "135 USD", response.Result.Result)
+	testutils.AssertTrue(t, testutils.Contains(response.Note, "static rates"))
+
+	response, err = runCalc(t, map[string]any{"expression": "0.023 USD/GB * 40 TB"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "920 USD", response.Result.Result)
+
+	_, err = runCalc(t, map[string]any{"expression": "100 USD to GB"})
+	testutils.AssertErrorContains(t, err, "measure different things")
+}
+
+func TestCalc_Today(t *testing.T) {
+	response, err := runCalc(t, map[string]any{"expression": "today + 1 d"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly), response.Value)
+}
+
+func TestCalc_BatchAndPrecision(t *testing.T) {
+	result, err := (&calc.CalcTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{
+		"expressions": []any{"1 / 3", "2 GiB to MiB"},
+		"precision":   float64(3),
+	})
+	testutils.AssertNoError(t, err)
+
+	var response struct {
+		Results []calc.Result `json:"results"`
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	testutils.AssertEqual(t, 2, len(response.Results))
+	testutils.AssertEqual(t, "0.333", response.Results[0].Value)
+	testutils.AssertEqual(t, "2048", response.Results[1].Value)
+	testutils.AssertEqual(t, "MiB", response.Results[1].Unit)
+}
+
+func TestCalc_Errors(t *testing.T) {
+	tests := []struct {
+		args     map[string]any
+		contains string
+	}{
+		{map[string]any{}, "missing required parameter"},
+		{map[string]any{"expression": ""}, "cannot be empty"},
+		{map[string]any{"expression": "1 / 0"}, "division by zero"},
+		{map[string]any{"expression": "1 GB + 1 h"}, "measure different things"},
+		{map[string]any{"expression": "2026-01-01 + 2026-02-01"}, "cannot add two dates"},
+		{map[string]any{"expression": "2026-01-01 + 5 GB"}, "only durations"},
+		{map[string]any{"expression": "5 furlongs"}, "unknown unit"},
+		{map[string]any{"expression": "(1 + 2"}, "closing parenthesis"},
+		{map[string]any{"expression": "2^100000"}, "out of range"},
+		{map[string]any{"expression": "1e99999"}, "out of range"},
+		{map[string]any{"expression": "1 GB to"}, "expected a unit"},
+		{map[string]any{"expression": "1 # 2"}, "unexpected character"},
+		{map[string]any{"expression": "1 + 1", "precision": float64(-1)}, "invalid 'precision'"},
+	}
+
+	for _, tt := range tests {
+		_, err := runCalc(t, tt.args)
+		testutils.AssertErrorContains(t, err, tt.contains)
+	}
+}
@@ -0,0 +1,190 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/checklinks"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runCheckLinks calls the check_links tool with a cache and decodes its result into out
+func runCheckLinks(t *testing.T, cache *sync.Map, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&checklinks.CheckLinksTool{}).Execute(context.Background(), quietLogger(), cache, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+// linkServer serves pages that work, are missing, refuse checks, have moved, or only answer GET,
+// counting the requests for each path
+func linkServer(t *testing.T) (*httptest.Server, map[string]*atomic.Int32) {
+	counts := map[string]*atomic.Int32{}
+	for _, path := range []string{"/ok", "/missing", "/private", "/moved", "/get-only"} {
+		counts[path] = &atomic.Int32{}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count, ok := counts[r.URL.Path]; ok {
+			count.Add(1)
+		}
+		switch r.URL.Path {
+		case "/ok":
+		case "/private":
+			w.WriteHeader(http.StatusForbidden)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, counts
+}
+
+// linksProject writes a repository with Markdown docs and a generated site linking to server
+func linksProject(t *testing.T, server string) string {
+	return writeProject(t, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		"README.md": "# Project\n",
+		"docs/guide.md": "# Guide\n\n## Install\n\n" +
+			"See [install](#install), [nowhere](#nowhere), [other](other.md#usage), [bad anchor](other.md#gone), [gone](gone.md) and [readme](/README.md).\n\n" +
+			"Visit " + server + "/ok, [missing](" + server + "/missing) and <" + server + "/private>.\n\n" +
+			"Mail <team@example.com>, see [example](https://example.com/nowhere) or [moved](" + server + "/moved#top).\n\n" +
+			"```\n" + server + "/missing in code\n```\n",
+		"docs/other.md": "# Other\n\n## Usage\n\n[again](" + server + "/missing) and [head](" + server + "/get-only)\n",
+		"site/index.html": "<html><head><link rel=\"preconnect\" href=\"" + server + "/missing\">\n<link rel=\"stylesheet\" href=\"/css/site.css\"></head>\n" +
+			"<body><h1 id=\"top-title\">Site</h1>\n<a href=\"/about\">About</a> <a href=\"blog/\">Blog</a> <a href=\"#top-title\">Up</a>\n" +
+			"  <img src=\"logo.png\"> <a href=\"about.html#team\">Team</a></body></html>\n",
+		"site/about.html": "<p><a name=\"people\">People</a></p>\n",
+		"site/blog/.keep": "",
+	})
+}
+
+func TestCheckLinks_Internal(t *testing.T) {
+	dir := linksProject(t, "http://127.0.0.1:1")
+	var result checklinks.Result
+	testutils.AssertNoError(t, runCheckLinks(t, &sync.Map{}, map[string]any{"path": filepath.Join(dir, "docs"), "external": false}, &result))
+	testutils.AssertEqual(t, 2, result.Files)
+	testutils.AssertEqual(t, 6, result.Internal)
+	testutils.AssertEqual(t, 0, result.External)
+
+	var got []string
+	for _, problem := range result.Problems {
+		got = append(got, problem.File+":"+problem.Status+":"+problem.Link)
+	}
+	testutils.AssertEqual(t, "guide.md:missing-anchor:#nowhere guide.md:missing-anchor:other.md#gone guide.md:missing-file:gone.md", strings.Join(got, " "))
+	testutils.AssertEqual(t, 5, result.Problems[0].Line)
+	testutils.AssertEqual(t, 36, result.Problems[0].Column)
+	testutils.AssertEqual(t, `No heading or ID "#gone" in other.md`, result.Problems[1].Message)
+
+	// HTML links starting with / are resolved from the site, and directories need an index page
+	result = checklinks.Result{}
+	testutils.AssertNoError(t, runCheckLinks(t, &sync.Map{}, map[string]any{"path": filepath.Join(dir, "site")}, &result))
+	testutils.AssertEqual(t, 2, result.Files)
+	got = nil
+	for _, problem := range result.Problems {
+		got = append(got, problem.Status+":"+problem.Link)
+	}
+	testutils.AssertEqual(t, "missing-file:/css/site.css missing-file:blog/ missing-file:logo.png missing-anchor:about.html#team", strings.Join(got, " "))
+	testutils.AssertEqual(t, 4, result.Problems[1].Line)
+	testutils.AssertEqual(t, 5, result.Problems[3].Line)
+	testutils.AssertEqual(t, 33, result.Problems[3].Column)
+}
+
+func TestCheckLinks_External(t *testing.T) {
+	server, counts := linkServer(t)
+	dir := linksProject(t, server.URL)
+	cache := &sync.Map{}
+	args := map[string]any{"path": filepath.Join(dir, "docs"), "report_redirects": true}
+
+	var result checklinks.Result
+	testutils.AssertNoError(t, runCheckLinks(t, cache, args, &result))
+	testutils.AssertEqual(t, 5, result.External)
+	testutils.AssertEqual(t, 0, result.Cached)
+	// mailto: and example.com are skipped
+	testutils.AssertEqual(t, 2, result.Skipped)
+	testutils.AssertEqual(t, 2, result.Counts["http-error"])
+	testutils.AssertEqual(t, 1, result.Counts["unverified"])
+	testutils.AssertEqual(t, 1, result.Counts["redirect"])
+
+	external := map[string]checklinks.Problem{}
+	for _, problem := range result.Problems {
+		if strings.HasPrefix(problem.Link, server.URL) {
+			external[problem.File+" "+strings.TrimPrefix(problem.Link, server.URL)] = problem
+		}
+	}
+	testutils.AssertEqual(t, 4, len(external))
+	testutils.AssertEqual(t, 404, external["guide.md /missing"].Code)
+	testutils.AssertEqual(t, "HTTP 404 Not Found", external["other.md /missing"].Message)
+	testutils.AssertEqual(t, "unverified", external["guide.md /private"].Status)
+	testutils.AssertEqual(t, server.URL+"/ok", external["guide.md /moved#top"].Location)
+
+	// Each URL is requested once, though linked twice, and falls back to GET when HEAD fails. /ok is
+	// also where /moved ends up.
+	testutils.AssertEqual(t, int32(2), counts["/ok"].Load())
+	testutils.AssertEqual(t, int32(2), counts["/get-only"].Load())
+	testutils.AssertEqual(t, int32(2), counts["/missing"].Load())
+
+	// Outcomes are cached, and redirects are only reported when asked for
+	result = checklinks.Result{}
+	testutils.AssertNoError(t, runCheckLinks(t, cache, map[string]any{"path": filepath.Join(dir, "docs")}, &result))
+	testutils.AssertEqual(t, 5, result.Cached)
+	testutils.AssertEqual(t, 0, result.Counts["redirect"])
+	testutils.AssertEqual(t, int32(2), counts["/missing"].Load())
+
+	result = checklinks.Result{}
+	testutils.AssertNoError(t, runCheckLinks(t, cache, map[string]any{"path": filepath.Join(dir, "docs"), "refresh": true, "ignore": "127.0.0.1"}, &result))
+	testutils.AssertEqual(t, 0, result.External)
+	testutils.AssertEqual(t, 8, result.Skipped)
+}
+
+func TestCheckLinks_NetworkPolicy(t *testing.T) {
+	server, counts := linkServer(t)
+	dir := linksProject(t, server.URL)
+	withNetworkPolicy(t, nil, security.AccessControl{BlockPrivateNetworks: true})
+
+	var result checklinks.Result
+	testutils.AssertNoError(t, runCheckLinks(t, &sync.Map{}, map[string]any{"path": filepath.Join(dir, "docs", "other.md")}, &result))
+	testutils.AssertEqual(t, 2, result.Counts["blocked"])
+	testutils.AssertTrue(t, strings.Contains(result.Problems[0].Message, "security policy"))
+	testutils.AssertEqual(t, int32(0), counts["/missing"].Load())
+}
+
+func TestCheckLinks_Errors(t *testing.T) {
+	dir := linksProject(t, "http://127.0.0.1:1")
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"no path", map[string]any{}, "missing required parameter: path"},
+		{"relative path", map[string]any{"path": "docs"}, "must be an absolute path"},
+		{"missing path", map[string]any{"path": filepath.Join(dir, "missing")}, "cannot access path"},
+		{"not a page", map[string]any{"path": filepath.Join(dir, ".git", "HEAD")}, "is not a Markdown or HTML file"},
+		{"bad concurrency", map[string]any{"path": dir, "concurrency": float64(0)}, "concurrency must be a whole number"},
+		{"fractional timeout", map[string]any{"path": dir, "timeout": float64(1.5)}, "timeout must be a whole number"},
+		{"bad ignore entry", map[string]any{"path": dir, "ignore": "github.com/org"}, `ignore entry "github.com/org"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out map[string]any
+			testutils.AssertErrorContains(t, runCheckLinks(t, &sync.Map{}, tt.args, &out), tt.want)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/utilities/clearcache"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestClearCache_RemovesCachedResponses(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write([]byte("cached page"))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Cache: true})
+	for _, path := range []string{"/a", "/b"} {
+		resp, err := client.Get(server.URL + path)
+		testutils.AssertNoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	dir, err := httpclient.CacheDir()
+	testutils.AssertNoError(t, err)
+	files, err := filepath.Glob(filepath.Join(dir, "*.body"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(files))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(files[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	tool := &clearcache.ClearCacheTool{}
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+
+	var response clearcache.Response
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	testutils.AssertEqual(t, 2, response.Removed)
+	testutils.AssertTrue(t, response.FreedBytes > 0)
+	testutils.AssertEqual(t, dir, response.Directory)
+
+	files, err = filepath.Glob(filepath.Join(dir, "*"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(files))
+}
@@ -0,0 +1,170 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/clipboard"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// setupFakeClipboard points the clipboard tool at commands that keep the clipboard in a file,
+// returning the file
+func setupFakeClipboard(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake clipboard commands require a POSIX shell")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "clipboard")
+	testutils.AssertNoError(t, os.WriteFile(file, nil, 0600))
+	paste := filepath.Join(dir, "paste")
+	testutils.AssertNoError(t, os.WriteFile(paste, []byte("#!/bin/sh\ncat \""+file+"\"\n"), 0700))
+	copier := filepath.Join(dir, "copy")
+	testutils.AssertNoError(t, os.WriteFile(copier, []byte("#!/bin/sh\ncat > \""+file+"\"\n"), 0700))
+	t.Setenv(clipboard.ReadCommandEnvVar, paste)
+	t.Setenv(clipboard.WriteCommandEnvVar, copier)
+	t.Setenv(clipboard.MaxBytesEnvVar, "")
+	return file
+}
+
+// runClipboard calls the clipboard tool, returning its text and the result decoded from it
+func runClipboard(t *testing.T, args map[string]any) (string, clipboard.Result, error) {
+	t.Helper()
+	var decoded clipboard.Result
+	result, err := (&clipboard.ClipboardTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return "", decoded, err
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	testutils.AssertNoError(t, json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &decoded))
+	return text, decoded, nil
+}
+
+func TestClipboard_WriteAndRead(t *testing.T) {
+	file := setupFakeClipboard(t)
+
+	_, written, err := runClipboard(t, map[string]any{"function": "write", "content": "first line\nsecond line\n"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 23, written.Bytes)
+	testutils.AssertEqual(t, 2, written.Lines)
+	testutils.AssertEqual(t, "copy", written.Command)
+	data, err := os.ReadFile(file)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "first line\nsecond line\n", string(data))
+
+	_, read, err := runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "first line\nsecond line\n", read.Content)
+	testutils.AssertEqual(t, 23, read.Bytes)
+	testutils.AssertEqual(t, 2, read.Lines)
+	testutils.AssertEqual(t, "paste", read.Command)
+	testutils.AssertFalse(t, read.Truncated)
+}
+
+func TestClipboard_ReadLimits(t *testing.T) {
+	file := setupFakeClipboard(t)
+
+	// é is two bytes, so a limit of two would cut it in half
+	testutils.AssertNoError(t, os.WriteFile(file, []byte("héllo"), 0600))
+	_, read, err := runClipboard(t, map[string]any{"function": "read", "max_bytes": float64(2)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "h", read.Content)
+	testutils.AssertEqual(t, 6, read.Bytes)
+	testutils.AssertTrue(t, read.Truncated)
+	testutils.AssertEqual(t, 1, len(read.Warnings))
+
+	_, read, err = runClipboard(t, map[string]any{"function": "read", "max_bytes": float64(3)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "hé", read.Content)
+
+	// The server's limit caps max_bytes
+	t.Setenv(clipboard.MaxBytesEnvVar, "4")
+	_, read, err = runClipboard(t, map[string]any{"function": "read", "max_bytes": float64(100)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "hél", read.Content)
+	_, _, err = runClipboard(t, map[string]any{"function": "write", "content": "hello"})
+	testutils.AssertErrorContains(t, err, "more than the 4 bytes allowed")
+
+	testutils.AssertNoError(t, os.WriteFile(file, nil, 0600))
+	_, read, err = runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, read.Bytes)
+	testutils.AssertEqual(t, "The clipboard is empty or does not hold text.", read.Warnings[0])
+
+	testutils.AssertNoError(t, os.WriteFile(file, []byte{0x89, 'P', 'N', 'G', 0, 0xff}, 0600))
+	_, _, err = runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertErrorContains(t, err, "does not hold text")
+}
+
+func TestClipboard_Security(t *testing.T) {
+	file := setupFakeClipboard(t)
+	manager, err := security.NewSecurityManagerWithRules(&security.SecurityRules{
+		Settings: security.Settings{Enabled: true, MaxContentSize: 1024, MaxEntropySize: 64},
+		Rules: map[string]security.Rule{
+			"blocked": {Patterns: []security.PatternConfig{{Contains: "dangerous malware download"}}, Action: "block"},
+			"warned":  {Patterns: []security.PatternConfig{{Contains: "suspicious link"}}, Action: "warn"},
+		},
+	})
+	testutils.AssertNoError(t, err)
+	previous := security.GlobalSecurityManager
+	security.GlobalSecurityManager = manager
+	t.Cleanup(func() { security.GlobalSecurityManager = previous })
+
+	// Content under 50 bytes is not scanned
+	blocked := "To finish the install, run this dangerous malware download in a terminal."
+	warned := "The release notes for this version are behind a suspicious link on the forum."
+	_, _, err = runClipboard(t, map[string]any{"function": "write", "content": blocked})
+	testutils.AssertTrue(t, err != nil)
+	data, _ := os.ReadFile(file)
+	testutils.AssertEqual(t, "", string(data))
+
+	text, _, err := runClipboard(t, map[string]any{"function": "write", "content": warned})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "⚠️  Security Notice"))
+
+	testutils.AssertNoError(t, os.WriteFile(file, []byte(blocked), 0600))
+	_, _, err = runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertTrue(t, err != nil)
+
+	testutils.AssertNoError(t, os.WriteFile(file, []byte(warned), 0600))
+	text, read, err := runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "⚠️  Security Notice"))
+	testutils.AssertEqual(t, warned, read.Content)
+}
+
+func TestClipboard_Errors(t *testing.T) {
+	setupFakeClipboard(t)
+
+	_, _, err := runClipboard(t, map[string]any{})
+	testutils.AssertErrorContains(t, err, "missing required parameter: function")
+
+	_, _, err = runClipboard(t, map[string]any{"function": "clear"})
+	testutils.AssertErrorContains(t, err, "unknown function: clear")
+
+	_, _, err = runClipboard(t, map[string]any{"function": "write"})
+	testutils.AssertErrorContains(t, err, "missing required parameter: content")
+
+	_, _, err = runClipboard(t, map[string]any{"function": "read", "max_bytes": float64(0)})
+	testutils.AssertErrorContains(t, err, "max_bytes must be a whole number")
+
+	t.Setenv(clipboard.MaxBytesEnvVar, "lots")
+	_, _, err = runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertErrorContains(t, err, "CLIPBOARD_MAX_BYTES must be a whole number")
+	t.Setenv(clipboard.MaxBytesEnvVar, "")
+
+	failing := filepath.Join(t.TempDir(), "failing")
+	testutils.AssertNoError(t, os.WriteFile(failing, []byte("#!/bin/sh\necho 'Error: Can'\"'\"'t open display' >&2\nexit 1\n"), 0700))
+	t.Setenv(clipboard.ReadCommandEnvVar, failing)
+	_, _, err = runClipboard(t, map[string]any{"function": "read"})
+	testutils.AssertErrorContains(t, err, "failing failed: Error: Can't open display")
+}
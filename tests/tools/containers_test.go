@@ -0,0 +1,179 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/containers"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// fakeDockerScript answers the subset of docker commands used by the tool and records
+// its arguments so tests can check what was run
+const fakeDockerScript = `#!/bin/sh
+echo "$@" >> "$(dirname "$0")/calls.log"
+case "$1" in
+  images) echo '{"Repository":"nginx","Tag":"1.27","ID":"abc123","Size":"190MB","CreatedSince":"2 weeks ago"}' ;;
+  ps) echo '{"Names":"web","ID":"c0ffee","Image":"nginx:1.27","State":"running","Status":"Up 2 hours","Ports":"0.0.0.0:8080->80/tcp"}' ;;
+  image) echo '[{"Id":"sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef","RepoTags":["nginx:1.27"],"Os":"linux","Architecture":"arm64","Size":1000,"Config":{"User":"","Env":["PATH=/usr/bin","DB_PASSWORD=hunter2"],"Labels":{"maintainer":"nginx"},"ExposedPorts":{"80/tcp":{}},"Cmd":["nginx","-g","daemon off;"]}}]' ;;
+  history) printf '%s\n%s\n' '{"CreatedBy":"CMD [\"nginx\"]","Size":"0B"}' '{"CreatedBy":"/bin/sh -c #(nop) ADD file:abc in /","Size":"97MB"}' ;;
+  logs) echo "line one"; echo "line two" >&2 ;;
+  run) echo "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef" ;;
+  stop) echo "$2" ;;
+esac
+`
+
+func setupFakeDocker(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "docker")
+	testutils.AssertNoError(t, os.WriteFile(script, []byte(fakeDockerScript), 0700))
+	t.Setenv(containers.RuntimeEnvVar, script)
+	return dir
+}
+
+func runContainers(t *testing.T, args map[string]any) (string, error) {
+	t.Helper()
+	tool := &containers.ContainersTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestContainersTool_Definition(t *testing.T) {
+	tool := &containers.ContainersTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "containers", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, containers.AllowManageEnvVar))
+}
+
+func TestContainersTool_ReadOnlyFunctions(t *testing.T) {
+	setupFakeDocker(t)
+
+	output, err := runContainers(t, map[string]any{"function": "list_images"})
+	testutils.AssertNoError(t, err)
+	var images []containers.ImageSummary
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &images))
+	testutils.AssertEqual(t, "nginx", images[0].Repository)
+	testutils.AssertEqual(t, "2 weeks ago", images[0].Created)
+
+	output, err = runContainers(t, map[string]any{"function": "list_containers", "all": true})
+	testutils.AssertNoError(t, err)
+	var list []containers.ContainerSummary
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &list))
+	testutils.AssertEqual(t, "web", list[0].Name)
+
+	output, err = runContainers(t, map[string]any{"function": "inspect_image", "image": "nginx:1.27"})
+	testutils.AssertNoError(t, err)
+	var details containers.ImageDetails
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &details))
+	testutils.AssertEqual(t, "0123456789ab", details.ID)
+	testutils.AssertEqual(t, "linux/arm64", details.Platform)
+	testutils.AssertEqual(t, "DB_PASSWORD=[REDACTED]", details.Env[1])
+	testutils.AssertEqual(t, "80/tcp", details.ExposedPorts[0])
+	testutils.AssertEqual(t, 2, len(details.Layers))
+	testutils.AssertEqual(t, "ADD file:abc in /", details.Layers[0].CreatedBy)
+
+	output, err = runContainers(t, map[string]any{"function": "logs", "container": "web", "tail": float64(5), "since": "10m"})
+	testutils.AssertNoError(t, err)
+	var logs containers.LogsResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &logs))
+	testutils.AssertEqual(t, 2, logs.Lines)
+	testutils.AssertTrue(t, testutils.Contains(logs.Logs, "line two"))
+}
+
+func TestContainersTool_Validation(t *testing.T) {
+	setupFakeDocker(t)
+
+	_, err := runContainers(t, map[string]any{"function": "inspect_image", "image": "--privileged"})
+	testutils.AssertErrorContains(t, err, "invalid image reference")
+
+	_, err = runContainers(t, map[string]any{"function": "logs", "container": "web", "since": "yesterday"})
+	testutils.AssertErrorContains(t, err, "relative duration")
+
+	_, err = runContainers(t, map[string]any{"function": "logs", "container": "web", "tail": float64(5000)})
+	testutils.AssertErrorContains(t, err, "tail must be between")
+}
+
+func TestContainersTool_ManageRequiresOptIn(t *testing.T) {
+	dir := setupFakeDocker(t)
+
+	_, err := runContainers(t, map[string]any{"function": "stop", "container": "web"})
+	testutils.AssertErrorContains(t, err, containers.AllowManageEnvVar+"=true")
+
+	t.Setenv(containers.AllowManageEnvVar, "true")
+
+	_, err = runContainers(t, map[string]any{"function": "run", "image": "nginx:1.27", "ports": []any{"8080:80 --privileged"}})
+	testutils.AssertErrorContains(t, err, "invalid port mapping")
+
+	output, err := runContainers(t, map[string]any{"function": "run", "image": "nginx:1.27", "name": "web2",
+		"ports": []any{"8080:80"}, "env": []any{"MODE=dev"}, "command": []any{"--help"}})
+	testutils.AssertNoError(t, err)
+	var response containers.ActionResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &response))
+	testutils.AssertEqual(t, "deadbeefdead", response.Container)
+
+	calls, err := os.ReadFile(filepath.Join(dir, "calls.log"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(calls), "run --detach --name web2 --publish 8080:80 --env MODE=dev nginx:1.27 --help"))
+}
+
+func TestContainersTool_Dockerfile(t *testing.T) {
+	dockerfile := `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.26
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+WORKDIR /src
+# dependencies first for caching
+COPY go.mod go.sum ./
+RUN go mod download && \
+    go build -o /app .
+
+FROM build AS test
+RUN go test ./...
+
+FROM alpine
+ENV API_TOKEN=changeme
+COPY --from=build /app /app
+EXPOSE 8080 9090
+ENTRYPOINT ["/app"]
+`
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(dockerfile), 0600))
+
+	output, err := runContainers(t, map[string]any{"function": "dockerfile", "path": path})
+	testutils.AssertNoError(t, err)
+
+	var summary containers.DockerfileSummary
+	testutils.AssertNoError(t, json.Unmarshal([]byte(output), &summary))
+	testutils.AssertEqual(t, "docker/dockerfile:1", summary.Syntax)
+	testutils.AssertEqual(t, "GO_VERSION=1.26", summary.GlobalArgs[0])
+	testutils.AssertEqual(t, 3, len(summary.Stages))
+	testutils.AssertEqual(t, "$BUILDPLATFORM", summary.Stages[0].Platform)
+	testutils.AssertEqual(t, 1, summary.Stages[0].Instructions["RUN"])
+	testutils.AssertEqual(t, "build", summary.Stages[1].BaseStage)
+	testutils.AssertEqual(t, 13, summary.Stages[2].Line)
+	testutils.AssertEqual(t, "build", summary.Stages[2].CopiesFrom[0])
+	testutils.AssertEqual(t, 2, len(summary.Stages[2].Exposes))
+	testutils.AssertEqual(t, "stage 2", summary.FinalStage)
+
+	warnings := strings.Join(summary.Warnings, "\n")
+	testutils.AssertTrue(t, strings.Contains(warnings, "ENV API_TOKEN may bake a secret"))
+	testutils.AssertTrue(t, strings.Contains(warnings, "base image alpine is not pinned"))
+	testutils.AssertTrue(t, strings.Contains(warnings, "final stage runs as root"))
+	testutils.AssertFalse(t, strings.Contains(warnings, "golang"))
+}
@@ -0,0 +1,209 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/csvtool"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const ordersCSV = `order_id,customer,amount,paid,ordered,status
+1,Alice,120.50,true,2026-01-05,open
+2,Bob,80,false,2026-01-07,closed
+3,Carol,,true,2026-02-11,open
+4,Alice,1500,true,2026-03-01T09:30:00Z,open
+5,Dan,42.25,NA,2026-03-02,closed
+`
+
+// writeCSVFile writes content to a file in a temporary directory and returns its path
+func writeCSVFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// runCSV calls the csv tool and decodes its JSON response into response
+func runCSV(t *testing.T, args map[string]any, response any) error {
+	t.Helper()
+	result, err := (&csvtool.CSVTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), response))
+	return nil
+}
+
+// columnByName finds a column's profile by name
+func columnByName(t *testing.T, columns []csvtool.ColumnProfile, name string) csvtool.ColumnProfile {
+	t.Helper()
+	for _, column := range columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	t.Fatalf("column %q not in profile", name)
+	return csvtool.ColumnProfile{}
+}
+
+func TestCSV_Profile(t *testing.T) {
+	path := writeCSVFile(t, "orders.csv", ordersCSV)
+
+	var response csvtool.ProfileResponse
+	testutils.AssertNoError(t, runCSV(t, map[string]any{"function": "profile", "path": path, "sample_rows": float64(2)}, &response))
+	testutils.AssertEqual(t, ",", response.Delimiter)
+	testutils.AssertEqual(t, 5, response.Rows)
+	testutils.AssertEqual(t, 6, len(response.Columns))
+	testutils.AssertEqual(t, 2, len(response.SampleRows))
+	testutils.AssertEqual(t, "Alice", response.SampleRows[0][1])
+
+	id := columnByName(t, response.Columns, "order_id")
+	testutils.AssertEqual(t, "integer", id.Type)
+	testutils.AssertEqual(t, float64(1), id.Min)
+	testutils.AssertEqual(t, float64(5), id.Max)
+	testutils.AssertEqual(t, 3.0, *id.Mean)
+
+	amount := columnByName(t, response.Columns, "amount")
+	testutils.AssertEqual(t, "float", amount.Type)
+	testutils.AssertEqual(t, 1, amount.Nulls)
+	testutils.AssertEqual(t, 0.2, amount.NullRate)
+	testutils.AssertEqual(t, 42.25, amount.Min)
+	testutils.AssertEqual(t, float64(1500), amount.Max)
+	testutils.AssertEqual(t, 435.6875, *amount.Mean)
+
+	paid := columnByName(t, response.Columns, "paid")
+	testutils.AssertEqual(t, "boolean", paid.Type)
+	testutils.AssertEqual(t, 1, paid.Nulls)
+
+	ordered := columnByName(t, response.Columns, "ordered")
+	testutils.AssertEqual(t, "datetime", ordered.Type)
+	testutils.AssertEqual(t, "2026-01-05", ordered.Min)
+	testutils.AssertEqual(t, "2026-03-02", ordered.Max)
+
+	customer := columnByName(t, response.Columns, "customer")
+	testutils.AssertEqual(t, "string", customer.Type)
+	testutils.AssertEqual(t, 4, customer.Distinct)
+	testutils.AssertEqual(t, "Alice", customer.TopValues[0].Value)
+	testutils.AssertEqual(t, 2, customer.TopValues[0].Count)
+}
+
+func TestCSV_DelimiterAndHeader(t *testing.T) {
+	path := writeCSVFile(t, "data.tsv", "a\tb;c\n1\t2;3\n4\t5;6\n")
+
+	var response csvtool.ProfileResponse
+	testutils.AssertNoError(t, runCSV(t, map[string]any{"function": "profile", "path": path}, &response))
+	testutils.AssertEqual(t, "tab", response.Delimiter)
+	testutils.AssertEqual(t, "b;c", response.Columns[1].Name)
+
+	// Without a header every row is data and columns are numbered
+	response = csvtool.ProfileResponse{}
+	testutils.AssertNoError(t, runCSV(t, map[string]any{"function": "profile", "path": path, "has_header": false, "delimiter": ";"}, &response))
+	testutils.AssertEqual(t, 3, response.Rows)
+	testutils.AssertEqual(t, "column_1", response.Columns[0].Name)
+	testutils.AssertEqual(t, "a\tb", response.SampleRows[0][0])
+
+	// Short rows are padded and counted
+	path = writeCSVFile(t, "ragged.csv", "x,y\n1,2\n3\n")
+	response = csvtool.ProfileResponse{}
+	testutils.AssertNoError(t, runCSV(t, map[string]any{"function": "profile", "path": path}, &response))
+	testutils.AssertEqual(t, 1, response.RaggedRows)
+	testutils.AssertEqual(t, 1, columnByName(t, response.Columns, "y").Nulls)
+}
+
+func TestCSV_FilterInline(t *testing.T) {
+	path := writeCSVFile(t, "orders.csv", ordersCSV)
+
+	var response csvtool.FilterResponse
+	testutils.AssertNoError(t, runCSV(t, map[string]any{
+		"function": "filter",
+		"path":     path,
+		"columns":  []any{"customer", "amount"},
+		"where": []any{
+			map[string]any{"column": "status", "op": "eq", "value": "open"},
+			map[string]any{"column": "amount", "op": "gte", "value": float64(100)},
+		},
+	}, &response))
+	testutils.AssertEqual(t, 5, response.RowsScanned)
+	testutils.AssertEqual(t, 2, response.RowsMatched)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"customer", "amount"}, response.Columns))
+	testutils.AssertTrue(t, reflect.DeepEqual([][]string{{"Alice", "120.50"}, {"Alice", "1500"}}, response.Rows))
+	testutils.AssertFalse(t, response.Truncated)
+
+	// Any-match with a limit reports the rows left out
+	response = csvtool.FilterResponse{}
+	testutils.AssertNoError(t, runCSV(t, map[string]any{
+		"function": "filter",
+		"path":     path,
+		"columns":  []any{"order_id"},
+		"match":    "any",
+		"limit":    float64(2),
+		"where": []any{
+			map[string]any{"column": "amount", "op": "empty"},
+			map[string]any{"column": "ordered", "op": "lt", "value": "2026-02-01"},
+		},
+	}, &response))
+	testutils.AssertEqual(t, 3, response.RowsMatched)
+	testutils.AssertTrue(t, reflect.DeepEqual([][]string{{"1"}, {"2"}}, response.Rows))
+	testutils.AssertTrue(t, response.Truncated)
+}
+
+func TestCSV_FilterToFile(t *testing.T) {
+	path := writeCSVFile(t, "orders.csv", ordersCSV)
+	outputPath := filepath.Join(t.TempDir(), "out", "open.csv")
+	args := map[string]any{
+		"function":    "filter",
+		"path":        path,
+		"columns":     []any{"order_id", "customer"},
+		"where":       []any{map[string]any{"column": "customer", "op": "regex", "value": "^(Alice|Carol)$"}},
+		"output_path": outputPath,
+	}
+
+	var response csvtool.FilterResponse
+	testutils.AssertNoError(t, runCSV(t, args, &response))
+	testutils.AssertEqual(t, outputPath, response.OutputPath)
+	testutils.AssertEqual(t, 3, response.RowsWritten)
+	testutils.AssertEqual(t, 0, len(response.Rows))
+
+	written, err := os.ReadFile(outputPath)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "order_id,customer\n1,Alice\n3,Carol\n4,Alice\n", string(written))
+
+	// An existing output file is only replaced with overwrite
+	err = runCSV(t, args, &response)
+	testutils.AssertErrorContains(t, err, "already exists")
+	args["overwrite"] = true
+	testutils.AssertNoError(t, runCSV(t, args, &response))
+}
+
+func TestCSV_Errors(t *testing.T) {
+	path := writeCSVFile(t, "orders.csv", ordersCSV)
+	var response csvtool.FilterResponse
+
+	err := runCSV(t, map[string]any{"function": "filter", "path": path, "columns": []any{"missing"}}, &response)
+	testutils.AssertErrorContains(t, err, `unknown column "missing"`)
+
+	err = runCSV(t, map[string]any{"function": "filter", "path": path, "where": []any{map[string]any{"column": "amount", "op": "between", "value": "1"}}}, &response)
+	testutils.AssertErrorContains(t, err, "invalid op")
+
+	err = runCSV(t, map[string]any{"function": "filter", "path": path, "where": []any{map[string]any{"column": "amount", "op": "gt"}}}, &response)
+	testutils.AssertErrorContains(t, err, "needs a value")
+
+	err = runCSV(t, map[string]any{"function": "filter", "path": path, "output_path": path}, &response)
+	testutils.AssertErrorContains(t, err, "must differ from path")
+
+	err = runCSV(t, map[string]any{"function": "profile", "path": "relative.csv"}, &response)
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	err = runCSV(t, map[string]any{"function": "profile", "path": writeCSVFile(t, "bad.csv", "a,b\n\"unterminated,1\n")}, &response)
+	testutils.AssertErrorContains(t, err, "invalid CSV on line")
+
+	err = runCSV(t, map[string]any{"function": "profile", "path": writeCSVFile(t, "empty.csv", "")}, &response)
+	testutils.AssertErrorContains(t, err, "empty")
+}
@@ -0,0 +1,144 @@
+package tools_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/database"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// setupDatabaseConfig writes a config with a writable and a read-only connection to
+// the same SQLite database
+func setupDatabaseConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "app.db")
+	config := fmt.Sprintf(`connections:
+  writer:
+    driver: sqlite
+    dsn: %s
+    allow_writes: true
+  reader:
+    driver: sqlite
+    dsn: %s
+    description: "Read-only | app data"
+    max_rows: 2
+`, dbPath, dbPath)
+	configPath := filepath.Join(dir, "databases.yaml")
+	testutils.AssertNoError(t, os.WriteFile(configPath, []byte(config), 0600))
+	t.Setenv(database.ConfigPathEnvVar, configPath)
+}
+
+func runDatabase(t *testing.T, args map[string]any) (string, error) {
+	t.Helper()
+	tool := &database.DatabaseTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestDatabaseTool_Definition(t *testing.T) {
+	tool := &database.DatabaseTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "database", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, "read-only"))
+}
+
+func TestDatabaseTool_WriteAndQuery(t *testing.T) {
+	setupDatabaseConfig(t)
+
+	_, err := runDatabase(t, map[string]any{"function": "query", "connection": "writer",
+		"sql": "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, note TEXT)"})
+	testutils.AssertNoError(t, err)
+
+	for i, name := range []string{"Ada", "Grace", "Linus"} {
+		output, err := runDatabase(t, map[string]any{"function": "query", "connection": "writer",
+			"sql": "INSERT INTO users (id, name, note) VALUES (?, ?, ?)", "params": []any{float64(i + 1), name, nil}})
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, testutils.Contains(output, "Rows affected: 1"))
+	}
+
+	output, err := runDatabase(t, map[string]any{"function": "query", "connection": "reader",
+		"sql": "SELECT id, name, note FROM users WHERE name <> 'x;y' ORDER BY id -- trailing comment"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "2 rows (truncated: row limit of 2 reached"))
+	testutils.AssertTrue(t, testutils.Contains(output, "| id | name | note |"))
+	testutils.AssertTrue(t, testutils.Contains(output, "| 1 | Ada | NULL |"))
+	testutils.AssertFalse(t, testutils.Contains(output, "Linus"))
+
+	output, err = runDatabase(t, map[string]any{"function": "query", "connection": "reader",
+		"sql": "SELECT name FROM users WHERE id = ?", "params": []any{float64(3)}, "format": "csv"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "1 row\n\nname\nLinus\n"))
+
+	output, err = runDatabase(t, map[string]any{"function": "schema", "connection": "reader"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "| users | table |"))
+
+	output, err = runDatabase(t, map[string]any{"function": "schema", "connection": "reader", "table": "users"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "| name | TEXT | YES |"))
+
+	output, err = runDatabase(t, map[string]any{"function": "list_connections"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "| reader | sqlite | read-only | Read-only \\| app data |"))
+	testutils.AssertTrue(t, testutils.Contains(output, "| writer | sqlite | read-write |"))
+	testutils.AssertFalse(t, testutils.Contains(output, "app.db"))
+}
+
+func TestDatabaseTool_ReadOnlyGuard(t *testing.T) {
+	setupDatabaseConfig(t)
+
+	_, err := runDatabase(t, map[string]any{"function": "query", "connection": "writer",
+		"sql": "CREATE TABLE items (id INTEGER)"})
+	testutils.AssertNoError(t, err)
+
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"DELETE FROM items", "DELETE statements are not allowed on a read-only connection"},
+		{"WITH gone AS (DELETE FROM items RETURNING id) SELECT * FROM gone", "DELETE is not allowed"},
+		{"SELECT 1; DROP TABLE items", "multiple statements are not allowed"},
+		{"SELECT 'it''s'; /* hidden */ DROP TABLE items", "multiple statements are not allowed"},
+		{"SELECT 'unterminated", "unterminated quoted string"},
+		{"  ", "missing required parameter: sql"},
+	}
+	for _, tt := range tests {
+		_, err := runDatabase(t, map[string]any{"function": "query", "connection": "reader", "sql": tt.sql})
+		testutils.AssertErrorContains(t, err, tt.expected)
+	}
+
+	// Keywords inside literals do not trip the guard
+	output, err := runDatabase(t, map[string]any{"function": "query", "connection": "reader",
+		"sql": "SELECT 'DELETE FROM items; DROP TABLE items' AS text"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "DELETE FROM items; DROP TABLE items"))
+
+	_, err = runDatabase(t, map[string]any{"function": "query", "connection": "missing", "sql": "SELECT 1"})
+	testutils.AssertErrorContains(t, err, "available: reader, writer")
+}
+
+func TestDatabaseTool_InvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "databases.yaml")
+	testutils.AssertNoError(t, os.WriteFile(configPath, []byte("connections:\n  bad:\n    driver: oracle\n    dsn: x\n"), 0600))
+	t.Setenv(database.ConfigPathEnvVar, configPath)
+
+	_, err := runDatabase(t, map[string]any{"function": "list_connections"})
+	testutils.AssertErrorContains(t, err, "unsupported driver 'oracle'")
+
+	t.Setenv(database.ConfigPathEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err = runDatabase(t, map[string]any{"function": "list_connections"})
+	testutils.AssertErrorContains(t, err, "no database configuration found")
+}
@@ -0,0 +1,242 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/depgraph"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const npmLockfile = `{
+  "name": "web",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "web",
+      "version": "1.0.0",
+      "workspaces": ["packages/ui"],
+      "dependencies": {"express": "^4.18.0", "ui": "*"},
+      "devDependencies": {"jest": "^29.0.0"}
+    },
+    "node_modules/express": {"version": "4.18.2", "dependencies": {"debug": "2.6.9", "qs": "6.11.0"}},
+    "node_modules/debug": {"version": "2.6.9", "dependencies": {"ms": "2.0.0"}},
+    "node_modules/ms": {"version": "2.0.0"},
+    "node_modules/qs": {"version": "6.11.0"},
+    "node_modules/jest": {"version": "29.7.0", "dev": true, "dependencies": {"debug": "^4.3.1"}},
+    "node_modules/jest/node_modules/debug": {"version": "4.3.4", "dev": true, "dependencies": {"ms": "2.1.2"}},
+    "node_modules/jest/node_modules/ms": {"version": "2.1.2", "dev": true},
+    "node_modules/ui": {"resolved": "packages/ui", "link": true},
+    "packages/ui": {"name": "ui", "version": "0.1.0", "dependencies": {"qs": "^6.0.0"}}
+  }
+}`
+
+const pnpmLockfile = `lockfileVersion: '9.0'
+
+importers:
+  .:
+    dependencies:
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+      ui:
+        specifier: workspace:*
+        version: link:packages/ui
+    devDependencies:
+      typescript:
+        specifier: ^5.0.0
+        version: 5.4.5
+  packages/ui:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+
+packages:
+  loose-envify@1.4.0:
+    resolution: {integrity: sha512-x}
+  react@18.2.0:
+    resolution: {integrity: sha512-x}
+  react-dom@18.2.0:
+    resolution: {integrity: sha512-x}
+  typescript@5.4.5:
+    resolution: {integrity: sha512-x}
+
+snapshots:
+  loose-envify@1.4.0: {}
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+  react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+  typescript@5.4.5: {}
+`
+
+// writeProject writes files, keyed by path relative to a temporary project directory, and
+// returns the directory
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		testutils.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		testutils.AssertNoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+// runDepGraph calls the dep_graph tool and decodes its result into out
+func runDepGraph(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&depgraph.DepGraphTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+func TestDepGraph_NPMSummary(t *testing.T) {
+	dir := writeProject(t, map[string]string{"package-lock.json": npmLockfile})
+
+	var summary depgraph.Summary
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "summary", "path": dir}, &summary))
+	testutils.AssertEqual(t, "npm", summary.Ecosystem)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"ui@0.1.0", "web@1.0.0"}, summary.Roots))
+	testutils.AssertEqual(t, 7, summary.Packages)
+	testutils.AssertEqual(t, 3, summary.DevPackages)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"express@4.18.2", "jest@29.7.0", "qs@6.11.0"}, summary.Direct))
+	testutils.AssertEqual(t, 2, len(summary.Duplicates))
+	testutils.AssertEqual(t, "debug", summary.Duplicates[0].Name)
+}
+
+func TestDepGraph_NPMWhy(t *testing.T) {
+	dir := writeProject(t, map[string]string{"package-lock.json": npmLockfile})
+
+	// Nested node_modules resolve before the top level, so each ms comes from a different debug
+	var why depgraph.WhyResult
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "why", "path": dir, "package": "ms"}, &why))
+	testutils.AssertEqual(t, 2, len(why.Matches))
+	testutils.AssertTrue(t, reflect.DeepEqual([][]string{{"web@1.0.0", "express@4.18.2", "debug@2.6.9", "ms@2.0.0"}}, why.Matches[0].Paths))
+	testutils.AssertTrue(t, reflect.DeepEqual([][]string{{"web@1.0.0", "jest@29.7.0", "debug@4.3.4", "ms@2.1.2"}}, why.Matches[1].Paths))
+	testutils.AssertFalse(t, why.Matches[0].Dev)
+	testutils.AssertTrue(t, why.Matches[1].Dev)
+
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "why", "path": dir, "package": "express"}, &why))
+	testutils.AssertTrue(t, why.Matches[0].Direct)
+
+	err := runDepGraph(t, map[string]any{"function": "why", "path": dir, "package": "deb"}, &why)
+	testutils.AssertErrorContains(t, err, "Similar packages: debug")
+}
+
+func TestDepGraph_NPMDependents(t *testing.T) {
+	dir := writeProject(t, map[string]string{"package-lock.json": npmLockfile})
+
+	// The workspace's dependency is found through its link in node_modules
+	var list depgraph.ListResult
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "dependents", "path": dir, "package": "qs", "depth": float64(0)}, &list))
+	testutils.AssertEqual(t, 1, len(list.Matches))
+	testutils.AssertTrue(t, reflect.DeepEqual([]depgraph.Related{
+		{Package: "express@4.18.2", Depth: 1},
+		{Package: "ui@0.1.0", Depth: 1},
+		{Package: "web@1.0.0", Depth: 2, Via: "express@4.18.2"},
+	}, list.Matches[0].Packages))
+
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "dependencies", "path": dir, "package": "express@4.18.2"}, &list))
+	testutils.AssertEqual(t, 2, list.Matches[0].Count)
+}
+
+func TestDepGraph_PNPM(t *testing.T) {
+	dir := writeProject(t, map[string]string{"pnpm-lock.yaml": pnpmLockfile})
+
+	var summary depgraph.Summary
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "summary", "path": filepath.Join(dir, "pnpm-lock.yaml")}, &summary))
+	testutils.AssertEqual(t, "pnpm", summary.Ecosystem)
+	testutils.AssertEqual(t, 2, len(summary.Roots))
+	testutils.AssertEqual(t, 4, summary.Packages)
+	// typescript is only reachable through devDependencies
+	testutils.AssertEqual(t, 1, summary.DevPackages)
+
+	// Peer dependency suffixes are merged, and react is reached through the linked workspace too
+	var why depgraph.WhyResult
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "why", "path": dir, "package": "react"}, &why))
+	testutils.AssertEqual(t, 2, why.Matches[0].TotalPaths)
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"packages/ui", "react@18.2.0"}, why.Matches[0].Paths[0]))
+}
+
+func TestDepGraph_Graph(t *testing.T) {
+	dir := writeProject(t, map[string]string{"pnpm-lock.yaml": pnpmLockfile})
+
+	var graph depgraph.GraphResult
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "graph", "path": dir, "include_dev": false}, &graph))
+	testutils.AssertEqual(t, "mermaid", graph.Format)
+	testutils.AssertEqual(t, 5, graph.Nodes)
+	testutils.AssertTrue(t, strings.HasPrefix(graph.Graph, "flowchart LR\n"))
+	testutils.AssertFalse(t, strings.Contains(graph.Graph, "typescript"))
+
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "graph", "path": dir, "package": "loose-envify", "reverse": true, "depth": float64(1), "format": "dot"}, &graph))
+	testutils.AssertEqual(t, 3, graph.Nodes)
+	testutils.AssertTrue(t, strings.Contains(graph.Graph, `"react@18.2.0" -> "loose-envify@1.4.0";`))
+	testutils.AssertTrue(t, strings.Contains(graph.Graph, `"loose-envify@1.4.0" [label="loose-envify@1.4.0", shape=box];`))
+
+	err := runDepGraph(t, map[string]any{"function": "graph", "path": dir, "reverse": true}, &graph)
+	testutils.AssertErrorContains(t, err, "reverse needs a package")
+}
+
+func TestDepGraph_GoLocalReplacements(t *testing.T) {
+	// Local replacements are read from disk, so the graph is built without the module proxy
+	dir := writeProject(t, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require example.com/lib v0.0.0
+
+require example.com/util v0.0.0 // indirect
+
+replace (
+	example.com/lib => ./lib
+	example.com/util => ./util
+)
+`,
+		"lib/go.mod":  "module example.com/lib\n\ngo 1.22\n\nrequire example.com/util v0.0.0\n",
+		"util/go.mod": "module example.com/util\n\ngo 1.22\n",
+	})
+
+	var why depgraph.WhyResult
+	testutils.AssertNoError(t, runDepGraph(t, map[string]any{"function": "why", "path": filepath.Join(dir, "go.mod"), "package": "example.com/util"}, &why))
+	testutils.AssertEqual(t, "go", why.Ecosystem)
+	testutils.AssertEqual(t, 0, len(why.Warnings))
+	testutils.AssertFalse(t, why.Matches[0].Direct)
+	testutils.AssertTrue(t, reflect.DeepEqual([][]string{{"example.com/app", "example.com/lib@v0.0.0", "example.com/util@v0.0.0"}}, why.Matches[0].Paths))
+}
+
+func TestDepGraph_Errors(t *testing.T) {
+	dir := writeProject(t, map[string]string{"package-lock.json": `{"lockfileVersion": 1, "dependencies": {}}`, "other.txt": ""})
+	var out map[string]any
+
+	err := runDepGraph(t, map[string]any{"function": "summary", "path": dir}, &out)
+	testutils.AssertErrorContains(t, err, "lockfileVersion 1")
+
+	err = runDepGraph(t, map[string]any{"function": "summary", "path": filepath.Join(dir, "other.txt")}, &out)
+	testutils.AssertErrorContains(t, err, "unsupported file")
+
+	err = runDepGraph(t, map[string]any{"function": "summary", "path": dir, "ecosystem": "go"}, &out)
+	testutils.AssertErrorContains(t, err, "no go manifest")
+
+	err = runDepGraph(t, map[string]any{"function": "why", "path": dir}, &out)
+	testutils.AssertErrorContains(t, err, "package")
+
+	err = runDepGraph(t, map[string]any{"function": "graph", "path": dir, "depth": float64(11)}, &out)
+	testutils.AssertErrorContains(t, err, "depth")
+}
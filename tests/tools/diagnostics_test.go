@@ -0,0 +1,98 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	serverdiagnostics "github.com/sammcj/mcp-devtools/internal/diagnostics"
+	"github.com/sammcj/mcp-devtools/internal/logging"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/utilities/diagnostics"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+// diagnosticsSession returns a context for a tool call from the named session
+func diagnosticsSession(sessionID string) context.Context {
+	session := mcpserver.NewInProcessSession(sessionID, nil)
+	return mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), session)
+}
+
+// callDiagnosticsTool simulates a session's call to a tool that logs a warning and fails
+func callDiagnosticsTool(base *logrus.Logger, sessionID, tool, message string) {
+	start := time.Now()
+	logging.ForTool(base, tool).Warn(message)
+	serverdiagnostics.RecordCall(sessionID, tool, start)
+	tools.GetGlobalErrorLogger().LogToolError(tool, map[string]any{"secret": "x"}, errors.New(message), "http", sessionID)
+}
+
+func TestDiagnosticsTool_Definition(t *testing.T) {
+	tool := &diagnostics.DiagnosticsTool{}
+	definition := tool.Definition()
+	testutils.AssertEqual(t, "get_diagnostics", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+}
+
+func TestDiagnosticsTool_ReturnsOnlyCallingSession(t *testing.T) {
+	defer logging.Close()
+	base := logrus.New()
+	base.SetOutput(io.Discard)
+	base.SetLevel(logrus.InfoLevel)
+	serverdiagnostics.Enable(base)
+	defer serverdiagnostics.Reset()
+
+	callDiagnosticsTool(base, "diag-session-a", "diag_tool_one", "session a failure")
+	callDiagnosticsTool(base, "diag-session-b", "diag_tool_one", "session b failure")
+	callDiagnosticsTool(base, "diag-session-a", "diag_tool_two", "second session a failure")
+
+	tool := &diagnostics.DiagnosticsTool{}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	result, err := tool.Execute(diagnosticsSession("diag-session-a"), logger, &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	response := parseDiagnostics(t, result)
+
+	testutils.AssertEqual(t, "diag-session-a", response.SessionID)
+	testutils.AssertEqual(t, 2, len(response.RecentErrors))
+	testutils.AssertEqual(t, "session a failure", response.RecentErrors[0].Error)
+	testutils.AssertEqual(t, "second session a failure", response.RecentErrors[1].Error)
+	testutils.AssertEqual(t, 0, len(response.RecentErrors[0].Arguments))
+	testutils.AssertEqual(t, 2, len(response.LogEntries))
+	testutils.AssertEqual(t, "diag_tool_one", response.LogEntries[0].Tool)
+	testutils.AssertEqual(t, "session a failure", response.LogEntries[0].Message)
+
+	// Filtering by tool applies to both errors and log entries
+	result, err = tool.Execute(diagnosticsSession("diag-session-a"), logger, &sync.Map{}, map[string]any{
+		"tool":  "diag_tool_two",
+		"lines": float64(10),
+	})
+	testutils.AssertNoError(t, err)
+	response = parseDiagnostics(t, result)
+	testutils.AssertEqual(t, 1, len(response.RecentErrors))
+	testutils.AssertEqual(t, 1, len(response.LogEntries))
+	testutils.AssertEqual(t, "second session a failure", response.LogEntries[0].Message)
+
+	// A session that has made no calls sees nothing
+	result, err = tool.Execute(diagnosticsSession("diag-session-c"), logger, &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	response = parseDiagnostics(t, result)
+	testutils.AssertEqual(t, 0, len(response.RecentErrors))
+	testutils.AssertEqual(t, 0, len(response.LogEntries))
+}
+
+func parseDiagnostics(t *testing.T, result *mcp.CallToolResult) diagnostics.Response {
+	t.Helper()
+	text, ok := result.Content[0].(mcp.TextContent)
+	testutils.AssertTrue(t, ok)
+	var response diagnostics.Response
+	testutils.AssertNoError(t, json.Unmarshal([]byte(text.Text), &response))
+	return response
+}
@@ -0,0 +1,240 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/diagram"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const diagramSpec = `openapi: 3.0.3
+info:
+  title: Shop API
+paths:
+  /orders:
+    post:
+      tags: [orders]
+      summary: Place an order
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewOrder'
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+        '400':
+          description: Invalid order
+  /customers/{id}:
+    get:
+      tags: [customers]
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Customer'
+components:
+  schemas:
+    NewOrder:
+      type: object
+      properties:
+        items:
+          type: array
+          items:
+            $ref: '#/components/schemas/Item'
+        customer:
+          $ref: '#/components/schemas/Customer'
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/NewOrder'
+        - type: object
+          properties:
+            id:
+              type: string
+              format: uuid
+            status:
+              $ref: '#/components/schemas/Status'
+    Item:
+      type: object
+      properties:
+        sku:
+          type: string
+        quantity:
+          type: integer
+    Status:
+      type: string
+      enum: [pending, shipped]
+    Customer:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+// runDiagram calls the mermaid_diagram tool and decodes its result
+func runDiagram(t *testing.T, args map[string]any) (*diagram.Result, error) {
+	t.Helper()
+	result, err := (&diagram.MermaidDiagramTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var out diagram.Result
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return &out, nil
+}
+
+func TestMermaidDiagram_Calls(t *testing.T) {
+	input := `# checkout
+web.Handler.Checkout -> orders.Service.Create
+orders.Service.Create -> db.Store.Insert: INSERT orders
+db.Store.Insert --> orders.Service.Create: id
+orders.Service.Create ~> events.Bus.Publish: order.created
+orders.Service.Create -> db.Store.Insert: INSERT items`
+
+	sequence, err := runDiagram(t, map[string]any{"source": "calls", "input": input, "title": "Checkout"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "sequence", sequence.Diagram)
+	testutils.AssertEqual(t, 4, sequence.Nodes)
+	testutils.AssertEqual(t, 5, sequence.Edges)
+	for _, want := range []string{
+		"---\ntitle: Checkout\n---\nsequenceDiagram",
+		"participant p0 as web.Handler",
+		"participant p1 as orders.Service",
+		"p0->>p1: Create()",
+		"p1->>p2: INSERT orders",
+		"p2-->>p1: id",
+		"p1-)p3: order.created",
+	} {
+		testutils.AssertTrue(t, strings.Contains(sequence.Mermaid, want))
+	}
+
+	// A call graph has a node for each function
+	flow, err := runDiagram(t, map[string]any{"source": "calls", "diagram": "flow", "input": input, "direction": "LR"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(flow.Mermaid, "flowchart LR"))
+	testutils.AssertEqual(t, 4, flow.Nodes)
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `n1["orders.Service.Create"]`))
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `n1 -->|"INSERT orders"| n2`))
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `n1 -->|"INSERT items"| n2`))
+
+	// Repeated calls are drawn once and counted
+	repeated, err := runDiagram(t, map[string]any{"source": "calls", "diagram": "flow", "input": "main -> load\nmain -> load\nmain -> load"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, repeated.Edges)
+	testutils.AssertTrue(t, strings.Contains(repeated.Mermaid, `n0 -->|"(3 calls)"| n1`))
+
+	class, err := runDiagram(t, map[string]any{"source": "calls", "diagram": "class", "input": `[
+		{"from": "api.Server.Handle", "to": "auth.Checker.Verify", "return": "claims"},
+		{"from": "api.Server.Handle", "to": "auth.Checker.Verify"},
+		{"from": "api.Server.Handle", "to": "auth.Checker.Refresh"}
+	]`})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, class.Nodes)
+	testutils.AssertEqual(t, 1, class.Edges)
+	for _, want := range []string{"c0 : +Handle()", "c1 : +Verify()", "c1 : +Refresh()", "c0 ..> c1"} {
+		testutils.AssertTrue(t, strings.Contains(class.Mermaid, want))
+	}
+
+	// Names with dots that are not members stay whole
+	hosts, err := runDiagram(t, map[string]any{"source": "calls", "input": "app.example.com -> db.internal: query", "split_members": false})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(hosts.Mermaid, "participant p0 as app.example.com"))
+}
+
+func TestMermaidDiagram_OpenAPI(t *testing.T) {
+	dir := writeProject(t, map[string]string{"openapi.yaml": diagramSpec})
+	path := filepath.Join(dir, "openapi.yaml")
+
+	class, err := runDiagram(t, map[string]any{"source": "openapi", "path": path})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "class", class.Diagram)
+	testutils.AssertEqual(t, 5, class.Nodes)
+	for _, want := range []string{
+		"classDiagram\n  direction TD",
+		`class c3["Order"]`,
+		"c3 : +uuid id",
+		"<<enumeration>> c4",
+		"c4 : pending",
+		"c2 <|-- c3",
+		`c2 --> "*" c1 : items`,
+		"c3 --> c4 : status",
+	} {
+		testutils.AssertTrue(t, strings.Contains(class.Mermaid, want))
+	}
+
+	// A filter keeps only the schemas the matching operations use
+	filtered, err := runDiagram(t, map[string]any{"source": "openapi", "input": diagramSpec, "filter": "customers"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, filtered.Nodes)
+	testutils.AssertTrue(t, strings.Contains(filtered.Mermaid, `class c0["Customer"]`))
+
+	flow, err := runDiagram(t, map[string]any{"source": "openapi", "diagram": "flow", "input": diagramSpec})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `n0["Shop API"]`))
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `{{"orders"}}`))
+	testutils.AssertTrue(t, strings.Contains(flow.Mermaid, `("POST /orders<br/>Place an order")`))
+
+	sequence, err := runDiagram(t, map[string]any{"source": "openapi", "diagram": "sequence", "input": diagramSpec, "filter": "/orders"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(sequence.Mermaid, "p0->>p1: POST /orders (NewOrder)"))
+	testutils.AssertTrue(t, strings.Contains(sequence.Mermaid, "p1-->>p0: 201 Order, 400"))
+}
+
+func TestMermaidDiagram_Directory(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"main.go":               "package main\n",
+		"internal/app/app.go":   "package app\n",
+		"internal/app/deep/x":   "",
+		"node_modules/left/pad": "",
+		".git/HEAD":             "",
+	})
+
+	result, err := runDiagram(t, map[string]any{"source": "directory", "path": dir, "max_depth": float64(2)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "flow", result.Diagram)
+	testutils.AssertTrue(t, strings.Contains(result.Mermaid, `["internal/"]`))
+	testutils.AssertTrue(t, strings.Contains(result.Mermaid, `["app/"]`))
+	testutils.AssertTrue(t, strings.Contains(result.Mermaid, `("main.go")`))
+	// Deeper levels, hidden entries and dependencies are left out
+	testutils.AssertFalse(t, strings.Contains(result.Mermaid, "app.go"))
+	testutils.AssertFalse(t, strings.Contains(result.Mermaid, "node_modules"))
+	testutils.AssertFalse(t, strings.Contains(result.Mermaid, ".git"))
+	testutils.AssertEqual(t, 4, result.Nodes)
+}
+
+func TestMermaidDiagram_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"unknown source", map[string]any{"source": "uml"}, "source must be one of"},
+		{"directory as sequence", map[string]any{"source": "directory", "diagram": "sequence", "path": dir}, "only be drawn as a flow"},
+		{"no calls", map[string]any{"source": "calls"}, "input is required"},
+		{"bad call line", map[string]any{"source": "calls", "input": "a -> b\nnot a call"}, "line 2 is not a call"},
+		{"call without callee", map[string]any{"source": "calls", "input": `[{"from": "a"}]`}, "needs both from and to"},
+		{"no spec", map[string]any{"source": "openapi"}, "path is required"},
+		{"relative path", map[string]any{"source": "directory", "path": "src"}, "must be an absolute path"},
+		{"spec is a directory", map[string]any{"source": "openapi", "path": dir}, "is a directory"},
+		{"empty spec", map[string]any{"source": "openapi", "input": "openapi: 3.0.0"}, "no paths or schemas"},
+		{"filter matches nothing", map[string]any{"source": "openapi", "input": diagramSpec, "filter": "/invoices"}, "no operations"},
+		{"bad direction", map[string]any{"source": "calls", "input": "a -> b", "direction": "up"}, "direction must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runDiagram(t, tt.args)
+			testutils.AssertErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docsindex"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runDocsTool calls the docs_index tool and decodes its result into out
+func runDocsTool(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	tool := &docsindex.DocsIndexTool{}
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+// docsProject writes a docs directory with front matter, converted and unconverted documents,
+// text files and a dependency directory, using a fresh index
+func docsProject(t *testing.T) string {
+	t.Setenv(docsindex.IndexPathEnvVar, filepath.Join(t.TempDir(), "index.db"))
+	return writeProject(t, map[string]string{
+		"guide.md": "---\ntitle: User Guide\ntags: [setup, networking]\ndescription: Getting started with the server\n---\n\n" +
+			"# Guide\n\nIntro text.\n\n## Configuring Retries\n\nThe client retries failed requests with exponential backoff.\n\n" +
+			"```yaml\nretries: 3\n```\n\n## Logging\n\nLogs are written to a file.\n",
+		"reference/api.md":           "# API\n\n## Connection Pool\n\nThe connection pool holds idle connections for reuse.\n\n## Timeouts\n\nRequests time out after thirty seconds.\n",
+		"manual.pdf":                 "%PDF-1.4",
+		"manual.md":                  "# Manual\n\nThe appliance supports firmware upgrades over the network.\n",
+		"scan.docx":                  "binary",
+		"notes.txt":                  "Deployment notes.\n\nRollback uses the previous release tag.\n",
+		"node_modules/pkg/README.md": "# Package\n\nretries everywhere\n",
+		"main.go":                    "package main\n",
+	})
+}
+
+func TestDocsIndex_IndexAndSearch(t *testing.T) {
+	dir := docsProject(t)
+
+	var index docsindex.IndexResult
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "index", "path": dir}, &index))
+	testutils.AssertEqual(t, 4, index.Indexed)
+	testutils.AssertEqual(t, 4, index.Files)
+	testutils.AssertEqual(t, 1, len(index.Unconverted))
+	testutils.AssertEqual(t, filepath.Join(dir, "scan.docx"), index.Unconverted[0])
+	testutils.AssertEqual(t, 1, len(index.Roots))
+	testutils.AssertEqual(t, 4, index.Roots[0].Files)
+
+	t.Run("stemmed terms rank the heading first", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "search", "query": "configure retry"}, &result))
+		testutils.AssertEqual(t, "all", result.Match)
+		testutils.AssertEqual(t, 1, len(result.Results))
+		hit := result.Results[0]
+		testutils.AssertEqual(t, filepath.Join(dir, "guide.md"), hit.Path)
+		testutils.AssertEqual(t, "User Guide", hit.Title)
+		testutils.AssertEqual(t, "Configuring Retries", hit.Heading)
+		testutils.AssertEqual(t, 11, hit.Line)
+		testutils.AssertTrue(t, strings.Contains(hit.Snippet, "**retries**"))
+		testutils.AssertFalse(t, hit.Stale)
+	})
+
+	t.Run("phrase", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": `"connection pool"`}, &result))
+		testutils.AssertEqual(t, 1, len(result.Results))
+		testutils.AssertEqual(t, "Connection Pool", result.Results[0].Heading)
+	})
+
+	t.Run("converted document has its source", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "firmware"}, &result))
+		testutils.AssertEqual(t, 1, len(result.Results))
+		testutils.AssertEqual(t, filepath.Join(dir, "manual.pdf"), result.Results[0].Source)
+	})
+
+	t.Run("text file and prefix", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "rollb*"}, &result))
+		testutils.AssertEqual(t, 1, len(result.Results))
+		testutils.AssertEqual(t, filepath.Join(dir, "notes.txt"), result.Results[0].Path)
+	})
+
+	t.Run("front matter description and tag", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "started", "tag": "networking"}, &result))
+		testutils.AssertEqual(t, 1, len(result.Results))
+		testutils.AssertEqual(t, filepath.Join(dir, "guide.md"), result.Results[0].Path)
+
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "started", "tag": "billing"}, &result))
+		testutils.AssertEqual(t, 0, len(result.Results))
+	})
+
+	t.Run("falls back to any term", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "timeouts kubernetes"}, &result))
+		testutils.AssertEqual(t, "any", result.Match)
+		testutils.AssertEqual(t, "Timeouts", result.Results[0].Heading)
+		testutils.AssertEqual(t, 1, len(result.Warnings))
+	})
+
+	t.Run("path filter and dependencies left out", func(t *testing.T) {
+		var result docsindex.SearchResult
+		testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "retries", "path": filepath.Join(dir, "reference")}, &result))
+		testutils.AssertEqual(t, 0, len(result.Results))
+	})
+}
+
+func TestDocsIndex_Incremental(t *testing.T) {
+	dir := docsProject(t)
+	var index docsindex.IndexResult
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "index", "path": dir}, &index))
+
+	// Change one file, delete another and add a third
+	guide := filepath.Join(dir, "guide.md")
+	testutils.AssertNoError(t, os.WriteFile(guide, []byte("# Guide\n\nCircuit breakers stop retries.\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	testutils.AssertNoError(t, os.Chtimes(guide, later, later))
+	testutils.AssertNoError(t, os.Remove(filepath.Join(dir, "notes.txt")))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(dir, "faq.md"), []byte("# FAQ\n"), 0600))
+
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "index", "path": dir}, &index))
+	testutils.AssertEqual(t, 2, index.Indexed)
+	testutils.AssertEqual(t, 2, index.Unchanged)
+	testutils.AssertEqual(t, 1, index.Removed)
+	testutils.AssertEqual(t, 4, index.Files)
+
+	var result docsindex.SearchResult
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "backoff"}, &result))
+	testutils.AssertEqual(t, 0, len(result.Results))
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "circuit"}, &result))
+	testutils.AssertEqual(t, 1, len(result.Results))
+
+	// Edited again without indexing, the hit is stale
+	testutils.AssertNoError(t, os.WriteFile(guide, []byte("# Guide\n\nCircuit breakers stop retries quickly.\n"), 0600))
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "circuit"}, &result))
+	testutils.AssertTrue(t, result.Results[0].Stale)
+	testutils.AssertEqual(t, 1, len(result.Warnings))
+
+	// Indexing a subdirectory leaves the parent root in place
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "index", "path": filepath.Join(dir, "reference")}, &index))
+	testutils.AssertEqual(t, 2, len(index.Roots))
+
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"action": "remove", "path": dir}, &index))
+	testutils.AssertEqual(t, 4, index.Removed)
+	testutils.AssertEqual(t, 0, len(index.Roots))
+	testutils.AssertNoError(t, runDocsTool(t, map[string]any{"query": "circuit"}, &result))
+	testutils.AssertEqual(t, 0, len(result.Results))
+	testutils.AssertEqual(t, 1, len(result.Warnings))
+	testutils.AssertTrue(t, strings.HasPrefix(result.Warnings[0], "Nothing has been indexed yet"))
+}
+
+func TestDocsIndex_Errors(t *testing.T) {
+	dir := docsProject(t)
+	var out any
+
+	err := runDocsTool(t, map[string]any{"action": "index"}, &out)
+	testutils.AssertErrorContains(t, err, "missing required parameter: path")
+
+	err = runDocsTool(t, map[string]any{"action": "index", "path": "docs"}, &out)
+	testutils.AssertErrorContains(t, err, "must be an absolute path")
+
+	err = runDocsTool(t, map[string]any{"action": "index", "path": filepath.Join(dir, "manual.pdf")}, &out)
+	testutils.AssertErrorContains(t, err, "process_document")
+
+	err = runDocsTool(t, map[string]any{"query": "  "}, &out)
+	testutils.AssertErrorContains(t, err, "missing required parameter: query")
+
+	err = runDocsTool(t, map[string]any{"query": "**"}, &out)
+	testutils.AssertErrorContains(t, err, "at least one word")
+
+	err = runDocsTool(t, map[string]any{"query": "retry", "limit": 0.5}, &out)
+	testutils.AssertErrorContains(t, err, "limit must be a whole number")
+
+	err = runDocsTool(t, map[string]any{"action": "rebuild", "path": dir}, &out)
+	testutils.AssertErrorContains(t, err, "invalid action")
+}
@@ -0,0 +1,290 @@
+package tools_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docslookup"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// docsLookupHTTPClient serves canned responses by the longest matching URL prefix and 404 for anything else
+type docsLookupHTTPClient struct {
+	responses map[string]string
+	requested []string
+}
+
+func (c *docsLookupHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	c.requested = append(c.requested, url)
+	match := ""
+	for prefix := range c.responses {
+		if strings.HasPrefix(url, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(c.responses[match])), Header: http.Header{}}, nil
+}
+
+const docsLookupGoPage = `<html><body><div class="Documentation-content js-docContent">
+<section class="Documentation-overview"><h3 id="pkg-overview">Overview <a class="Documentation-idLink" href="#pkg-overview">¶</a></h3><p>Package widget builds widgets.</p></section>
+<section class="Documentation-functions">
+<div class="Documentation-function"><h4 id="New" data-kind="function">func <a href="/src/new.go">New</a></h4>
+<div class="Documentation-declaration"><pre>func New(name string) *Widget</pre></div><p>New returns a named widget.</p></div>
+</section>
+<section class="Documentation-types">
+<div class="Documentation-type"><h4 id="Client" data-kind="type">type Client</h4>
+<div class="Documentation-declaration"><pre>type Client struct{}</pre></div><p>Client talks to the widget service.</p>
+<div class="Documentation-typeFunc"><h4 id="NewClient" data-kind="function">func NewClient</h4><p>NewClient returns a client.</p></div>
+<div class="Documentation-typeMethod"><h4 id="Client.Close" data-kind="method">func (*Client) Close</h4><p>Close closes the client.</p></div>
+<div class="Documentation-typeMethod"><h4 id="Client.Do" data-kind="method">func (*Client) Do</h4>
+<div class="Documentation-declaration"><pre>func (c *Client) Do(req *Request) error</pre></div><p>Do sends a search request.</p></div>
+</div>
+<div class="Documentation-type"><h4 id="Server" data-kind="type">type Server</h4><p>Server serves widgets.</p>
+<div class="Documentation-typeMethod"><h4 id="Server.Close" data-kind="method">func (*Server) Close</h4><p>Close stops the server.</p></div>
+</div>
+</section>
+</div></body></html>`
+
+const docsLookupRustAll = `<html><body><section id="main-content"><h1>List of all items</h1>
+<h3 id="structs">Structs</h3><ul class="all-items"><li><a href="struct.Client.html">Client</a></li><li><a href="de/struct.Reader.html">de::Reader</a></li></ul>
+</section></body></html>`
+
+const docsLookupRustClient = `<html><body><section id="main-content">
+<div class="main-heading"><h1>Struct <span>Client</span></h1><a class="src" href="../src/lib.rs.html">Source</a></div>
+<pre class="rust item-decl"><code>pub struct Client { /* private fields */ }</code></pre>
+<details class="toggle top-doc" open><summary class="hideme"><span>Expand description</span></summary><div class="docblock"><p>A client for the widget service.</p></div></details>
+<h2 id="implementations">Implementations</h2><div id="implementations-list"><details class="toggle implementors-toggle" open><summary><section class="impl"><h3>impl Client</h3></section></summary><div class="impl-items">
+<details class="toggle method-toggle" open><summary><section id="method.send" class="method"><h4 class="code-header">pub fn send(&amp;self, body: &amp;str) -&gt; Result&lt;()&gt;</h4></section></summary><div class="docblock"><p>Sends a body to the service.</p></div></details>
+<section id="method.close" class="method"><h4 class="code-header">pub fn close(self)</h4></section>
+</div></details></div>
+<h2 id="trait-implementations">Trait Implementations</h2><div id="trait-implementations-list"><section id="method.clone" class="method"><h4>fn clone(&amp;self) -&gt; Client</h4></section></div>
+</section></body></html>`
+
+const docsLookupSphinxPage = `<html><body><div role="main">
+<section id="api"><h1>API<a class="headerlink" href="#api">¶</a></h1>
+<dl class="py function"><dt class="sig sig-object py" id="widget.get"><span class="sig-name">widget.get</span>(<em>url</em>)<a class="headerlink" href="#widget.get">¶</a></dt><dd><p>Fetch a widget from a URL.</p></dd></dl>
+<dl class="py function"><dt class="sig sig-object py" id="widget.put"><span class="sig-name">widget.put</span>(<em>url</em>)</dt><dd><p>Store a widget.</p></dd></dl>
+</section></div></body></html>`
+
+// docsLookupInventory builds a Sphinx objects.inv listing the given "name domain:role priority uri display" lines
+func docsLookupInventory(t *testing.T, lines ...string) string {
+	t.Helper()
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	_, err := writer.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, writer.Close())
+	return "# Sphinx inventory version 2\n# Project: widget\n# Version: 1.0\n# The remainder of this file is compressed using zlib.\n" + compressed.String()
+}
+
+func runDocsLookup(t *testing.T, client *docsLookupHTTPClient, args map[string]any) (string, error) {
+	t.Helper()
+	tool := docslookup.NewDocsLookupTool(client)
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestDocsLookupTool_Definition(t *testing.T) {
+	tool := &docslookup.DocsLookupTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "docs_lookup", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, !*definition.Annotations.DestructiveHint)
+	testutils.AssertTrue(t, *definition.Annotations.OpenWorldHint)
+	testutils.AssertEqual(t, "source", strings.Join(definition.InputSchema.Required, ","))
+}
+
+func TestDocsLookupTool_Validation(t *testing.T) {
+	client := &docsLookupHTTPClient{}
+
+	_, err := runDocsLookup(t, client, map[string]any{"package": "net/http"})
+	testutils.AssertErrorContains(t, err, "missing required parameter: source")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "go"})
+	testutils.AssertErrorContains(t, err, "missing required parameter: package")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "npm", "package": "react"})
+	testutils.AssertErrorContains(t, err, "source must be one of")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "rust", "package": "../etc"})
+	testutils.AssertErrorContains(t, err, "invalid crate name")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "go", "package": "net/http", "max_length": float64(10)})
+	testutils.AssertErrorContains(t, err, "max_length must be between")
+
+	testutils.AssertEqual(t, 0, len(client.requested))
+}
+
+func TestDocsLookupTool_GoOverview(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{"https://pkg.go.dev/example.com/widget": docsLookupGoPage}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/widget", "version": "v1.2.0"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "https://pkg.go.dev/example.com/widget@v1.2.0", client.requested[0])
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Package example.com/widget\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "Package widget builds widgets."))
+	testutils.AssertTrue(t, !strings.Contains(text, "¶"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Functions and types\n\n`New`, `Client`, `NewClient`, `Server`"))
+	testutils.AssertTrue(t, !strings.Contains(text, "Client.Do"))
+}
+
+func TestDocsLookupTool_GoSymbol(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{"https://pkg.go.dev/example.com/widget": docsLookupGoPage}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/widget", "symbol": "Do"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# example.com/widget.Client.Do\n\nSource: https://pkg.go.dev/example.com/widget#Client.Do"))
+	testutils.AssertTrue(t, strings.Contains(text, "func (c *Client) Do(req *Request) error"))
+	testutils.AssertTrue(t, strings.Contains(text, "Do sends a search request."))
+	testutils.AssertTrue(t, !strings.Contains(text, "Client talks to"))
+
+	// A type is returned without its methods' docs, which are listed instead
+	text, err = runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/widget", "symbol": "client"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(text, "Client talks to the widget service."))
+	testutils.AssertTrue(t, !strings.Contains(text, "Do sends a search request."))
+	testutils.AssertTrue(t, strings.Contains(text, "## Constructors\n\n`NewClient`"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Methods\n\n`Client.Close`, `Client.Do`"))
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/widget", "symbol": "Close"})
+	testutils.AssertErrorContains(t, err, "use one of: Client.Close, Server.Close")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/widget", "symbol": "Missing"})
+	testutils.AssertErrorContains(t, err, "symbol not found: Missing")
+}
+
+func TestDocsLookupTool_MDN(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{
+		"https://developer.mozilla.org/api/v1/search": `{"documents": [
+			{"mdn_url": "/en-US/docs/Web/API/AbortSignal", "title": "AbortSignal", "summary": "Represents a signal object."},
+			{"mdn_url": "/en-US/docs/Web/API/AbortSignal/timeout_static", "title": "AbortSignal: timeout() static method", "summary": "Returns a signal that aborts after a time."},
+			{"mdn_url": "/en-US/docs/Web/API/AbortSignal/timeout", "title": "AbortSignal.timeout()", "summary": "Alias."}
+		]}`,
+		"https://developer.mozilla.org/en-US/docs/Web/API/AbortSignal/timeout/index.json": `{"doc": {"title": "AbortSignal.timeout()", "mdn_url": "/en-US/docs/Web/API/AbortSignal/timeout", "body": [
+			{"type": "prose", "value": {"id": null, "title": null, "content": "<p>The <code>timeout()</code> method returns a signal. See <a href=\"/en-US/docs/Web/API/AbortController\">AbortController</a>.</p>"}},
+			{"type": "prose", "value": {"id": "syntax", "title": "Syntax", "content": "<div class=\"code-example\"><div class=\"example-header\"><span class=\"language-name\">js</span></div><pre class=\"brush: js\"><code>AbortSignal.timeout(time)</code></pre></div>"}},
+			{"type": "browser_compatibility", "value": {"id": "browser_compatibility", "title": "Browser compatibility", "query": "api.AbortSignal.timeout_static"}}
+		]}}`,
+	}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "mdn", "symbol": "AbortSignal.timeout"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# AbortSignal.timeout()\n\nSource: https://developer.mozilla.org/en-US/docs/Web/API/AbortSignal/timeout\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Syntax"))
+	testutils.AssertTrue(t, strings.Contains(text, "AbortSignal.timeout(time)"))
+	testutils.AssertTrue(t, strings.Contains(text, "(https://developer.mozilla.org/en-US/docs/Web/API/AbortController)"))
+	testutils.AssertTrue(t, !strings.Contains(text, "Browser compatibility"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Related\n\n- [AbortSignal](https://developer.mozilla.org/en-US/docs/Web/API/AbortSignal): Represents a signal object."))
+}
+
+func TestDocsLookupTool_PythonInventory(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{
+		"https://pypi.org/pypi/widget/json": `{"info": {"name": "widget", "version": "1.0.0", "summary": "Widgets for Python", "requires_python": ">=3.9",
+			"description": "# widget\n\n## Installing\n\npip install widget\n\n## Usage\n\n` + "```python\\n# not a heading\\n```" + `\n\nCall get.\n\n### Options\n\nSome options.\n\n## Licence\n\nMIT",
+			"description_content_type": "text/markdown", "project_urls": {"Documentation": "https://widget.readthedocs.io/", "Source": "https://github.com/acme/widget"}}}`,
+		// The project has no stable version, so the latest docs are used
+		"https://widget.readthedocs.io/en/latest/objects.inv": docsLookupInventory(t,
+			"widget py:module 0 api.html#module-$ -",
+			"widget.get py:function 1 api.html#$ -",
+			"widget.put py:function 1 api.html#$ -",
+			"quickstart std:label -1 quickstart.html#$ Quickstart guide",
+		),
+		"https://widget.readthedocs.io/en/latest/api.html": docsLookupSphinxPage,
+	}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "python", "package": "widget"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# widget 1.0.0\n\nSource: https://pypi.org/project/widget/1.0.0/"))
+	testutils.AssertTrue(t, strings.Contains(text, "- Documentation: https://widget.readthedocs.io/"))
+
+	text, err = runDocsLookup(t, client, map[string]any{"source": "python", "package": "widget", "symbol": "get"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# widget.get\n\nSource: https://widget.readthedocs.io/en/latest/api.html#widget.get"))
+	testutils.AssertTrue(t, strings.Contains(text, "Fetch a widget from a URL."))
+	testutils.AssertTrue(t, !strings.Contains(text, "Store a widget."))
+
+	// Symbols missing from the inventory are looked for in the description's headings
+	text, err = runDocsLookup(t, client, map[string]any{"source": "python", "package": "widget", "symbol": "usage"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(text, "Call get.\n\n### Options\n\nSome options."))
+	testutils.AssertTrue(t, !strings.Contains(text, "Licence"))
+}
+
+func TestDocsLookupTool_ReadTheDocs(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{
+		"https://docs.example.com/3/objects.inv":      docsLookupInventory(t, "widget.put py:function 1 library/api.html#$ -"),
+		"https://docs.example.com/3/library/api.html": docsLookupSphinxPage,
+		"https://widget.readthedocs.io/en/stable/":    `<html><head><title>Widget documentation</title></head><body><nav>Menu</nav><main><h1>Widget</h1><p>Widget builds widgets for every occasion, with a simple API and sensible defaults for most projects. It supports Python 3.9 and later and runs anywhere.</p></main></body></html>`,
+	}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "readthedocs", "package": "https://docs.example.com/3/index.html", "symbol": "put"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# widget.put\n\nSource: https://docs.example.com/3/library/api.html#widget.put"))
+	testutils.AssertTrue(t, strings.Contains(text, "Store a widget."))
+
+	text, err = runDocsLookup(t, client, map[string]any{"source": "readthedocs", "package": "widget"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Widget documentation\n\nSource: https://widget.readthedocs.io/en/stable/"))
+	testutils.AssertTrue(t, strings.Contains(text, "sensible defaults"))
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "readthedocs", "package": "widget", "symbol": "get"})
+	testutils.AssertErrorContains(t, err, "no objects.inv inventory found")
+}
+
+func TestDocsLookupTool_Rust(t *testing.T) {
+	client := &docsLookupHTTPClient{responses: map[string]string{
+		"https://docs.rs/widget-rs/0.3.1/widget_rs/all.html":           docsLookupRustAll,
+		"https://docs.rs/widget-rs/0.3.1/widget_rs/struct.Client.html": docsLookupRustClient,
+	}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "rust", "package": "widget-rs", "version": "0.3.1", "symbol": "Client"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# widget_rs::Client\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "pub struct Client"))
+	testutils.AssertTrue(t, strings.Contains(text, "A client for the widget service."))
+	testutils.AssertTrue(t, !strings.Contains(text, "Sends a body"))
+	testutils.AssertTrue(t, !strings.Contains(text, "Expand description"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Methods\n\n`send`, `close`\n"))
+
+	text, err = runDocsLookup(t, client, map[string]any{"source": "rust", "package": "widget-rs", "version": "0.3.1", "symbol": "widget_rs::Client::send"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# widget_rs::Client::send\n\nSource: https://docs.rs/widget-rs/0.3.1/widget_rs/struct.Client.html#method.send"))
+	testutils.AssertTrue(t, strings.Contains(text, "```\npub fn send(&self, body: &str) -> Result<()>\n```"))
+	testutils.AssertTrue(t, strings.Contains(text, "Sends a body to the service."))
+	testutils.AssertTrue(t, !strings.Contains(text, "A client for the widget service."))
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "rust", "package": "widget-rs", "version": "0.3.1", "symbol": "Client::missing"})
+	testutils.AssertErrorContains(t, err, "Client has no method, variant or field named missing (has: send, close)")
+
+	_, err = runDocsLookup(t, client, map[string]any{"source": "rust", "package": "widget-rs", "version": "0.4.0", "symbol": "Client"})
+	testutils.AssertErrorContains(t, err, "version 0.4.0 of crate widget-rs not found on docs.rs")
+}
+
+func TestDocsLookupTool_Truncation(t *testing.T) {
+	long := strings.Repeat("<p>"+strings.Repeat("word ", 30)+"</p>", 40)
+	client := &docsLookupHTTPClient{responses: map[string]string{
+		"https://pkg.go.dev/example.com/long": `<div class="Documentation-content"><section class="Documentation-overview">` + long + `</section></div>`,
+	}}
+
+	text, err := runDocsLookup(t, client, map[string]any{"source": "go", "package": "example.com/long", "max_length": float64(1000)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(text, "[Truncated: showing "))
+	testutils.AssertTrue(t, len(text) < 1400)
+}
@@ -0,0 +1,58 @@
+package tools_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// deviceEchoPython is a fake Python with docling that reports the device it was asked for as
+// unavailable, as the conversion script does when falling back to the CPU
+const deviceEchoPython = `#!/bin/sh
+[ "$2" = process ] || exit 0
+while [ $# -gt 0 ]; do
+	[ "$1" = --device ] && device=$2
+	shift
+done
+printf '{"success": true, "content": "# Converted", "processing_info": {"hardware_acceleration": "cpu", "device_fallback": "%s was requested but is not available (available: cpu), so cpu was used"}}' "$device"
+`
+
+func TestDocumentProcessing_RequestedDeviceFallsBack(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	python := filepath.Join(t.TempDir(), "python")
+	testutils.AssertNoError(t, os.WriteFile(python, []byte(deviceEchoPython), 0700))
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", python)
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv("DOCLING_HARDWARE_ACCELERATION", "mps")
+	t.Setenv(docprocessing.EnvServeURL, "")
+
+	source := filepath.Join(t.TempDir(), "notes.md")
+	testutils.AssertNoError(t, os.WriteFile(source, []byte("# Notes\n"), 0600))
+	convert := func(args map[string]any) string {
+		t.Helper()
+		args["source"] = source
+		args["return_inline_only"] = true
+		result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, args)
+		testutils.AssertNoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// The configured device is used unless the call asks for another
+	text := convert(map[string]any{})
+	testutils.AssertTrue(t, strings.Contains(text, `"device_fallback": "mps was requested`))
+	text = convert(map[string]any{"hardware_acceleration": "CUDA"})
+	testutils.AssertTrue(t, strings.Contains(text, `"device_fallback": "cuda was requested`))
+	testutils.AssertTrue(t, strings.Contains(text, `"hardware_acceleration": "cpu"`))
+
+	_, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"source": source, "hardware_acceleration": "tpu"})
+	testutils.AssertErrorContains(t, err, "must be one of: auto, mps, cuda, cpu")
+}
@@ -0,0 +1,77 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/artifacts"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestDocumentProcessing_CachesByContent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("DOCLING_PYTHON_PATH", filepath.Join(t.TempDir(), "missing", "python"))
+	t.Setenv("DOCLING_CACHE_DIR", "")
+	t.Setenv("DOCLING_CACHE_ENABLED", "true")
+	t.Setenv(docprocessing.EnvServeURL, "")
+
+	call := func(args map[string]any) map[string]any {
+		t.Helper()
+		result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, args)
+		testutils.AssertNoError(t, err)
+		var response map[string]any
+		testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+		return response
+	}
+	convert := func(source string, extra map[string]any) map[string]any {
+		t.Helper()
+		args := map[string]any{"source": source, "return_inline_only": true}
+		for key, value := range extra {
+			args[key] = value
+		}
+		return call(args)
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "notes.md")
+	testutils.AssertNoError(t, os.WriteFile(source, []byte("# Notes\n\nFirst draft\n"), 0600))
+	testutils.AssertEqual(t, false, convert(source, nil)["cache_hit"])
+	testutils.AssertEqual(t, true, convert(source, nil)["cache_hit"])
+
+	// A copy with the same content is served from the cache, under its own name
+	copied := filepath.Join(dir, "copy.md")
+	data, err := os.ReadFile(source)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, os.WriteFile(copied, data, 0600))
+	response := convert(copied, nil)
+	testutils.AssertEqual(t, true, response["cache_hit"])
+	testutils.AssertEqual(t, copied, response["source"])
+
+	// Editing the file, or forcing, converts it again
+	testutils.AssertNoError(t, os.WriteFile(source, []byte("# Notes\n\nSecond draft\n"), 0600))
+	response = convert(source, nil)
+	testutils.AssertEqual(t, false, response["cache_hit"])
+	testutils.AssertTrue(t, strings.Contains(response["content"].(string), "Second draft"))
+	testutils.AssertEqual(t, false, convert(source, map[string]any{"force": true})["cache_hit"])
+	testutils.AssertEqual(t, false, convert(source, map[string]any{"ocr_languages": []any{"fr"}, "enable_ocr": true})["cache_hit"])
+
+	// Entries are kept in the artifact store, and can be reported on and cleared
+	artifactDir, err := artifacts.Dir()
+	testutils.AssertNoError(t, err)
+	stats := call(map[string]any{"cache": "stats"})["cache"].(map[string]any)
+	testutils.AssertTrue(t, strings.HasPrefix(stats["directory"].(string), artifactDir))
+	testutils.AssertEqual(t, float64(3), stats["total_files"])
+	stats = call(map[string]any{"cache": "clear"})["cache"].(map[string]any)
+	testutils.AssertEqual(t, float64(0), stats["total_files"])
+	testutils.AssertEqual(t, false, convert(copied, nil)["cache_hit"])
+
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"cache": "purge"})
+	testutils.AssertErrorContains(t, err, "invalid cache action")
+}
@@ -0,0 +1,102 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const chunkedManual = `Read this first.
+
+# Installation
+
+Download the installer.
+
+## Linux
+
+Run the script:
+
+` + "```sh\n# not a heading\n./install.sh\n```" + `
+
+### Troubleshooting
+
+Check the logs.
+
+# Usage & Configuration
+
+Start the server.
+`
+
+func TestDocumentProcessing_SavesChunksByHeading(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", filepath.Join(t.TempDir(), "missing", "python"))
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv(docprocessing.EnvServeURL, "")
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "manual.md")
+	testutils.AssertNoError(t, os.WriteFile(source, []byte(chunkedManual), 0600))
+	saveTo := filepath.Join(dir, "out", "manual.md")
+
+	// A stale chunk from an earlier conversion is replaced, and other files are left alone
+	chunkDir := filepath.Join(dir, "out", "manual_chunks")
+	testutils.AssertNoError(t, os.MkdirAll(chunkDir, 0700))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(chunkDir, "009-old.md"), []byte("old"), 0600))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(chunkDir, "notes.txt"), []byte("mine"), 0600))
+
+	result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":      source,
+		"save_to":     saveTo,
+		"chunk_level": float64(2),
+	})
+	testutils.AssertNoError(t, err)
+	var response struct {
+		SavePath string                   `json:"save_path"`
+		Chunks   docprocessing.ChunkIndex `json:"chunks"`
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	testutils.AssertEqual(t, saveTo, response.SavePath)
+	testutils.AssertEqual(t, chunkDir, response.Chunks.Directory)
+
+	var names []string
+	total := 0
+	for _, chunk := range response.Chunks.Chunks {
+		names = append(names, filepath.Base(chunk.File))
+		testutils.AssertTrue(t, chunk.Tokens > 0)
+		total += chunk.Tokens
+	}
+	testutils.AssertEqual(t, "001-introduction.md 002-installation.md 003-linux.md 004-usage-configuration.md", strings.Join(names, " "))
+	testutils.AssertEqual(t, total, response.Chunks.TotalTokens)
+
+	// Headings in code blocks and below the chunk level stay within their section
+	linux, err := os.ReadFile(filepath.Join(chunkDir, "003-linux.md"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(string(linux), "## Linux\n"))
+	testutils.AssertTrue(t, strings.Contains(string(linux), "# not a heading"))
+	testutils.AssertTrue(t, strings.Contains(string(linux), "### Troubleshooting"))
+
+	index, err := os.ReadFile(response.Chunks.Index)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(index), "\n  - [Linux](003-linux.md) (about "))
+	testutils.AssertTrue(t, strings.Contains(string(index), "\n- [Usage & Configuration](004-usage-configuration.md)"))
+
+	_, err = os.Stat(filepath.Join(chunkDir, "009-old.md"))
+	testutils.AssertTrue(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(chunkDir, "notes.txt"))
+	testutils.AssertNoError(t, err)
+
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source": source, "chunk_level": float64(2), "return_inline_only": true,
+	})
+	testutils.AssertErrorContains(t, err, "cannot be used with return_inline_only")
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source": source, "chunk_level": float64(7),
+	})
+	testutils.AssertErrorContains(t, err, "heading level from 1 to 6")
+}
@@ -0,0 +1,117 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// writeFigureDocument writes a Word document with a described figure and one only numbered "Picture 2"
+func writeFigureDocument(t *testing.T, path string) {
+	t.Helper()
+	figure := func(id, descr string) string {
+		return `<w:p><w:r><w:drawing><wp:inline><wp:docPr id="1" name="` + descr + `" descr="` + descr + `"/>
+<a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="` + id + `"/></pic:blipFill></pic:pic></a:graphicData></a:graphic>
+</wp:inline></w:drawing></w:r></w:p>`
+	}
+	writeZip(t, path, map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+ xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+ xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+ xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"
+ xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Design</w:t></w:r></w:p>
+` + figure("rId1", "Architecture diagram") + figure("rId2", "Picture 2") + `
+</w:body></w:document>`,
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image2.jpeg"/>
+</Relationships>`,
+		"word/media/image1.png":  "png-data",
+		"word/media/image2.jpeg": "jpeg-data",
+	})
+}
+
+func TestDocumentProcessing_SavesFiguresWithRelativeLinks(t *testing.T) {
+	// A vision model that describes every figure the same way
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-1",
+			"object":  "chat.completion",
+			"model":   "vision",
+			"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": `"A bar chart of sales by quarter"`}}},
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", filepath.Join(t.TempDir(), "missing", "python"))
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv(docprocessing.EnvServeURL, "")
+	t.Setenv(docprocessing.EnvOpenAIAPIBase, server.URL)
+	t.Setenv(docprocessing.EnvOpenAIModel, "vision")
+	t.Setenv(docprocessing.EnvOpenAIAPIKey, "test-key")
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "design.docx")
+	writeFigureDocument(t, source)
+	// A figure left by an earlier conversion is replaced
+	imageDir := filepath.Join(dir, "design_images")
+	testutils.AssertNoError(t, os.MkdirAll(imageDir, 0700))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(imageDir, "figure-9.png"), []byte("old"), 0600))
+
+	result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":          source,
+		"describe_images": true,
+	})
+	testutils.AssertNoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	data, err := os.ReadFile(filepath.Join(imageDir, "figure-1.png"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "png-data", string(data))
+	data, err = os.ReadFile(filepath.Join(imageDir, "figure-2.jpg"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "jpeg-data", string(data))
+	_, err = os.Stat(filepath.Join(imageDir, "figure-9.png"))
+	testutils.AssertTrue(t, os.IsNotExist(err))
+
+	// Only the figure with a generic label is described
+	testutils.AssertEqual(t, 1, requests)
+	markdown, err := os.ReadFile(filepath.Join(dir, "design.md"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(markdown), "![Architecture diagram](design_images/figure-1.png)"))
+	testutils.AssertTrue(t, strings.Contains(string(markdown), "![A bar chart of sales by quarter](design_images/figure-2.jpg)"))
+	testutils.AssertTrue(t, strings.Contains(text, `"image_dir": "`+imageDir+`"`))
+	testutils.AssertTrue(t, !strings.Contains(text, "alt_text_error"))
+
+	// Inline results keep placeholders unless an image directory is given
+	text = convertNatively(t, source)
+	testutils.AssertTrue(t, strings.Contains(text, `\u003c!-- image --\u003e`))
+
+	figures := filepath.Join(t.TempDir(), "figures")
+	result, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":             source,
+		"image_dir":          figures,
+		"return_inline_only": true,
+	})
+	testutils.AssertNoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	testutils.AssertTrue(t, strings.Contains(text, "![Architecture diagram]("+filepath.Join(figures, "figure-1.png")+")"))
+
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"source": source, "image_dir": "figures"})
+	testutils.AssertErrorContains(t, err, "image_dir must be a fully qualified absolute path")
+}
@@ -0,0 +1,116 @@
+package tools_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/xuri/excelize/v2"
+)
+
+// writeZip writes an office document made of the given XML parts
+func writeZip(t *testing.T, path string, parts map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	testutils.AssertNoError(t, err)
+	archive := zip.NewWriter(file)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		testutils.AssertNoError(t, err)
+		_, err = w.Write([]byte(content))
+		testutils.AssertNoError(t, err)
+	}
+	testutils.AssertNoError(t, archive.Close())
+	testutils.AssertNoError(t, file.Close())
+}
+
+// convertNatively converts a document with docling unavailable and returns the tool's JSON result
+func convertNatively(t *testing.T, source string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", filepath.Join(t.TempDir(), "missing", "python"))
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv(docprocessing.EnvServeURL, "")
+
+	result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":             source,
+		"return_inline_only": true,
+	})
+	testutils.AssertNoError(t, err)
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestDocumentProcessing_NativeWordDocument(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "plan.docx")
+	writeZip(t, source, map[string]string{"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Release Plan</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Ship the </w:t></w:r><w:r><w:t>beta</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>Write notes</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Task</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Owner</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>Build | test</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Sam</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`})
+
+	text := convertNatively(t, source)
+	for _, want := range []string{`# Release Plan`, `Ship the beta`, `- Write notes`, `| Task | Owner |`, `| Build \\| test | Sam |`, `"processing_method": "native"`, `docling is unavailable`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %s", want, text)
+		}
+	}
+}
+
+func TestDocumentProcessing_NativePresentationAndWorkbook(t *testing.T) {
+	dir := t.TempDir()
+	slide := func(title, body string) string {
+		return `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + title + `</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:nvSpPr><p:nvPr><p:ph idx="1"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + body + `</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:sld>`
+	}
+	deck := filepath.Join(dir, "deck.pptx")
+	// Slides are ordered by number, not by name
+	writeZip(t, deck, map[string]string{
+		"ppt/slides/slide10.xml": slide("Wrap Up", "Questions"),
+		"ppt/slides/slide2.xml":  slide("Roadmap", "Q3 launch"),
+		"ppt/slides/slide1.xml":  slide("Welcome", "Agenda"),
+	})
+	text := convertNatively(t, deck)
+	welcome, roadmap, wrapUp := strings.Index(text, "## Slide 1: Welcome"), strings.Index(text, "## Slide 2: Roadmap"), strings.Index(text, "## Slide 3: Wrap Up")
+	testutils.AssertTrue(t, welcome >= 0 && welcome < roadmap && roadmap < wrapUp)
+	testutils.AssertTrue(t, strings.Contains(text, "Q3 launch"))
+
+	workbook := excelize.NewFile()
+	testutils.AssertNoError(t, workbook.SetSheetRow("Sheet1", "A1", &[]any{"Region", "Sales"}))
+	testutils.AssertNoError(t, workbook.SetSheetRow("Sheet1", "A2", &[]any{"North", 42}))
+	book := filepath.Join(dir, "sales.xlsx")
+	testutils.AssertNoError(t, workbook.SaveAs(book))
+	text = convertNatively(t, book)
+	testutils.AssertTrue(t, strings.Contains(text, "## Sheet1"))
+	testutils.AssertTrue(t, strings.Contains(text, "| North | 42 |"))
+}
+
+func TestDocumentProcessing_NativeRejectsUnsupportedSources(t *testing.T) {
+	text := convertNatively(t, "https://example.com/report.pdf")
+	testutils.AssertTrue(t, strings.Contains(text, "only reads local files"))
+
+	// The fallback can be turned off, leaving the tool unavailable
+	t.Setenv(docprocessing.NativeFallbackEnvVar, "false")
+	_, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"source": "https://example.com/report.pdf"})
+	testutils.AssertErrorContains(t, err, "docling is not installed")
+}
+
+func TestDocumentProcessing_NativePDF(t *testing.T) {
+	source, err := filepath.Abs(filepath.Join("..", "docprocessing", "test-complex-pdf.pdf"))
+	testutils.AssertNoError(t, err)
+	if _, err := os.Stat(source); err != nil {
+		t.Skip("PDF fixture not found")
+	}
+	text := convertNatively(t, source)
+	testutils.AssertTrue(t, strings.Contains(text, "## Page 1"))
+	testutils.AssertTrue(t, strings.Contains(text, `"processing_method": "native"`))
+}
@@ -0,0 +1,118 @@
+package tools_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// fakePython writes a script standing in for a Python interpreter, which can import docling when
+// withDocling is true
+func fakePython(t *testing.T, path string, withDocling bool) string {
+	t.Helper()
+	testutils.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	status := "1"
+	if withDocling {
+		status = "0"
+	}
+	testutils.AssertNoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexit "+status+"\n"), 0700))
+	return path
+}
+
+// fakeVenv makes a virtualenv in dir with a fake Python
+func fakeVenv(t *testing.T, dir string, withDocling bool) string {
+	t.Helper()
+	python := fakePython(t, filepath.Join(dir, ".venv", "bin", "python"), withDocling)
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(dir, ".venv", "pyvenv.cfg"), []byte("home = /usr/bin\n"), 0600))
+	return python
+}
+
+func TestResolvePythonEnvironment_FindsTheDocumentsProject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", "")
+	t.Setenv("VIRTUAL_ENV", "")
+	t.Setenv("CONDA_PREFIX", "")
+
+	project := t.TempDir()
+	python := fakeVenv(t, project, true)
+	docs := filepath.Join(project, "docs", "reports")
+	testutils.AssertNoError(t, os.MkdirAll(docs, 0700))
+
+	// The nearest project's virtualenv is used for documents below it
+	env, err := docprocessing.ResolvePythonEnvironment(docs, "", "/usr/bin/python3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, python, env.Path)
+	testutils.AssertEqual(t, docprocessing.PythonSourceVirtualenv, env.Source)
+	testutils.AssertEqual(t, project, env.Project)
+
+	// A nearer virtualenv without docling is skipped and reported
+	fakeVenv(t, docs, false)
+	env, err = docprocessing.ResolvePythonEnvironment(docs, "", "/usr/bin/python3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, python, env.Path)
+	testutils.AssertEqual(t, 1, len(env.Skipped))
+
+	// Discovery can be turned off
+	t.Setenv(docprocessing.PythonDiscoveryEnvVar, "false")
+	env, err = docprocessing.ResolvePythonEnvironment(docs, "", "/usr/bin/python3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "/usr/bin/python3", env.Path)
+	testutils.AssertEqual(t, docprocessing.PythonSourceDefault, env.Source)
+}
+
+func TestResolvePythonEnvironment_UsesCondaEnvironmentsAndTheEnvVar(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", "")
+	t.Setenv("VIRTUAL_ENV", "")
+	t.Setenv("CONDA_PREFIX", "")
+
+	envs := t.TempDir()
+	t.Setenv("CONDA_ENVS_PATH", envs)
+	python := fakePython(t, filepath.Join(envs, "reports", "bin", "python"), true)
+	project := t.TempDir()
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(project, "environment.yml"), []byte("name: reports\ndependencies:\n  - python=3.12\n"), 0600))
+
+	env, err := docprocessing.ResolvePythonEnvironment(project, "", "/usr/bin/python3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, python, env.Path)
+	testutils.AssertEqual(t, docprocessing.PythonSourceConda, env.Source)
+
+	// DOCLING_PYTHON_PATH always wins over discovery
+	t.Setenv("DOCLING_PYTHON_PATH", "/opt/docling/bin/python")
+	env, err = docprocessing.ResolvePythonEnvironment(project, "", "/opt/docling/bin/python")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, docprocessing.PythonSourceEnvVar, env.Source)
+}
+
+func TestResolvePythonEnvironment_ChecksPythonPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	dir := t.TempDir()
+	python := fakePython(t, filepath.Join(dir, "python3.12"), true)
+
+	env, err := docprocessing.ResolvePythonEnvironment("", python, "/usr/bin/python3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, python, env.Path)
+	testutils.AssertEqual(t, docprocessing.PythonSourceArgument, env.Source)
+
+	_, err = docprocessing.ResolvePythonEnvironment("", "python3", "/usr/bin/python3")
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	// Only interpreters may be named
+	_, err = docprocessing.ResolvePythonEnvironment("", fakePython(t, filepath.Join(dir, "rm"), true), "/usr/bin/python3")
+	testutils.AssertErrorContains(t, err, "Python interpreter")
+
+	_, err = docprocessing.ResolvePythonEnvironment("", fakePython(t, filepath.Join(dir, "python-nodocling"), false), "/usr/bin/python3")
+	testutils.AssertErrorContains(t, err, "docling could not be imported")
+}
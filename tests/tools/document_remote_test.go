@@ -0,0 +1,78 @@
+package tools_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestDocumentProcessing_ConvertsWithDoclingServe(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, "/v1/convert/source", r.URL.Path)
+		testutils.AssertEqual(t, "secret", r.Header.Get("X-Api-Key"))
+		testutils.AssertNoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"document":{"filename":"report.pdf","md_content":"# Report\n\nQuarterly results"},"status":"success","errors":[],"processing_time":1.5}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(docprocessing.EnvServeURL, server.URL+"/")
+	t.Setenv(docprocessing.EnvServeAPIKey, "secret")
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+
+	source := filepath.Join(t.TempDir(), "report.pdf")
+	testutils.AssertNoError(t, os.WriteFile(source, []byte("%PDF-1.4 test"), 0600))
+
+	tool := &docprocessing.DocumentProcessorTool{}
+	result, err := tool.Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":             source,
+		"return_inline_only": true,
+		"enable_ocr":         true,
+	})
+	testutils.AssertNoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	testutils.AssertTrue(t, strings.Contains(text, "Quarterly results"))
+	testutils.AssertTrue(t, strings.Contains(text, "docling-serve"))
+
+	// The file is sent as base64 with the tool's options
+	sources := received["sources"].([]any)
+	file := sources[0].(map[string]any)
+	testutils.AssertEqual(t, "file", file["kind"])
+	testutils.AssertEqual(t, "report.pdf", file["filename"])
+	testutils.AssertEqual(t, base64.StdEncoding.EncodeToString([]byte("%PDF-1.4 test")), file["base64_string"])
+	testutils.AssertEqual(t, true, received["options"].(map[string]any)["do_ocr"])
+}
+
+func TestDocumentProcessing_ReportsDoclingServeErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"Invalid API key"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	t.Setenv(docprocessing.EnvServeURL, server.URL)
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+
+	tool := &docprocessing.DocumentProcessorTool{}
+	result, err := tool.Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":             "https://example.com/report.pdf",
+		"return_inline_only": true,
+	})
+	testutils.AssertNoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	testutils.AssertTrue(t, strings.Contains(text, "HTTP 401"))
+	testutils.AssertTrue(t, strings.Contains(text, docprocessing.EnvServeAPIKey))
+
+	// Only http and https URLs are accepted
+	t.Setenv(docprocessing.EnvServeURL, "localhost:5001")
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"source": "https://example.com/report.pdf"})
+	testutils.AssertErrorContains(t, err, docprocessing.EnvServeURL)
+}
@@ -0,0 +1,82 @@
+package tools_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// tablePython is a fake Python with docling that extracts one table with merged cells, in the format
+// it was asked for
+const tablePython = `#!/bin/sh
+[ "$2" = process ] || exit 0
+while [ $# -gt 0 ]; do
+	[ "$1" = --table-format ] && format=$2
+	shift
+done
+[ "$format" = csv ] || exit 1
+cat <<'EOF'
+{"success": true, "content": "# Accounts\n\n| Item | 2024 |\n|---|---|\n| Revenue | 1,200 |\n", "processing_info": {},
+ "tables": [{"id": "table_1", "caption": "Income", "headers": ["Item", "2024"], "rows": [["Revenue", "1,200"]],
+  "csv": "Item,2024\nRevenue,\"1,200\"", "page_number": 2, "confidence": 0.82, "confidence_source": "structure", "merged_cells": 1}]}
+EOF
+`
+
+func TestDocumentProcessing_SavesTablesAsCSV(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	python := filepath.Join(t.TempDir(), "python")
+	testutils.AssertNoError(t, os.WriteFile(python, []byte(tablePython), 0700))
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCLING_PYTHON_PATH", python)
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv(docprocessing.EnvServeURL, "")
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "accounts.md")
+	testutils.AssertNoError(t, os.WriteFile(source, []byte("# Accounts\n"), 0600))
+	// A table left by an earlier conversion is replaced
+	tableDir := filepath.Join(dir, "accounts_tables")
+	testutils.AssertNoError(t, os.MkdirAll(tableDir, 0700))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(tableDir, "table-7.csv"), []byte("old\n"), 0600))
+
+	result, err := (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":       source,
+		"table_format": "CSV",
+	})
+	testutils.AssertNoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	csv, err := os.ReadFile(filepath.Join(tableDir, "table-1.csv"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Item,2024\nRevenue,\"1,200\"\n", string(csv))
+	_, err = os.Stat(filepath.Join(tableDir, "table-7.csv"))
+	testutils.AssertTrue(t, os.IsNotExist(err))
+
+	// The saved result reports each table's file and confidence, but not its cells
+	testutils.AssertTrue(t, strings.Contains(text, `"file": "`+filepath.Join(tableDir, "table-1.csv")+`"`))
+	testutils.AssertTrue(t, strings.Contains(text, `"confidence": 0.82`))
+	testutils.AssertTrue(t, strings.Contains(text, `"merged_cells": 1`))
+	testutils.AssertTrue(t, !strings.Contains(text, `"rows"`))
+
+	// Inline results carry the table's CSV instead of a file
+	result, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"source":             source,
+		"table_format":       "csv",
+		"return_inline_only": true,
+	})
+	testutils.AssertNoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	testutils.AssertTrue(t, strings.Contains(text, `"csv": "Item,2024\nRevenue,\"1,200\""`))
+	testutils.AssertTrue(t, !strings.Contains(text, `"file"`))
+
+	_, err = (&docprocessing.DocumentProcessorTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"source": source, "table_format": "xlsx"})
+	testutils.AssertErrorContains(t, err, "must be one of: markdown, html, csv")
+}
@@ -0,0 +1,208 @@
+package tools_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/encode"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runEncode calls the encode tool and decodes its JSON response
+func runEncode(t *testing.T, args map[string]any) (*encode.Response, error) {
+	t.Helper()
+	result, err := (&encode.EncodeTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var response encode.Response
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return &response, nil
+}
+
+// b64url encodes bytes as unpadded base64url, as JWKs use
+func b64url(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestEncode_TextFormats(t *testing.T) {
+	cases := []struct {
+		format string
+		action string
+		input  string
+		output string
+	}{
+		{"base64", "encode", "hello world", "aGVsbG8gd29ybGQ="},
+		{"base64", "decode", "aGVsbG8g\nd29ybGQ", "hello world"},
+		{"base64url", "encode", "??>>", "Pz8-Pg"},
+		{"base64", "decode", "Pz8-Pg==", "??>>"},
+		{"url", "encode", "a&b=c d", "a%26b%3Dc+d"},
+		{"url", "decode", "a%26b%3Dc+d", "a&b=c d"},
+		{"url_path", "encode", "my file.txt", "my%20file.txt"},
+		{"html", "encode", `<a href="x">Tom & Jerry's</a>`, "&lt;a href=&#34;x&#34;&gt;Tom &amp; Jerry&#39;s&lt;/a&gt;"},
+		{"html", "decode", "caf&eacute; &copy; &#x1F600;", "café © 😀"},
+		{"hex", "encode", "hi", "6869"},
+		{"hex", "decode", "0x68:69", "hi"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format+"_"+tc.action, func(t *testing.T) {
+			response, err := runEncode(t, map[string]any{"format": tc.format, "action": tc.action, "input": tc.input})
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tc.output, response.Output)
+			testutils.AssertEqual(t, "", response.OutputEncoding)
+		})
+	}
+}
+
+func TestEncode_BinaryAndGzip(t *testing.T) {
+	// Bytes that are not UTF-8 are returned as base64
+	response, err := runEncode(t, map[string]any{"format": "hex", "action": "decode", "input": "ff fe 00"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "base64", response.OutputEncoding)
+	testutils.AssertEqual(t, "//4A", response.Output)
+	testutils.AssertEqual(t, 3, response.OutputBytes)
+
+	text := strings.Repeat("compressible text ", 100)
+	compressed, err := runEncode(t, map[string]any{"format": "gzip", "action": "encode", "input": text})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "base64", compressed.OutputEncoding)
+	testutils.AssertTrue(t, len(compressed.Output) < len(text))
+
+	response, err = runEncode(t, map[string]any{"format": "gzip", "action": "decode", "input": compressed.Output})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, text, response.Output)
+
+	_, err = runEncode(t, map[string]any{"format": "gzip", "action": "decode", "input": "aGVsbG8="})
+	testutils.AssertErrorContains(t, err, "not gzip data")
+}
+
+func TestEncode_Validation(t *testing.T) {
+	_, err := runEncode(t, map[string]any{"format": "rot13", "action": "encode", "input": "x"})
+	testutils.AssertErrorContains(t, err, "invalid format")
+
+	_, err = runEncode(t, map[string]any{"format": "base64", "input": "x"})
+	testutils.AssertErrorContains(t, err, "must be encode or decode")
+
+	_, err = runEncode(t, map[string]any{"format": "jwt", "action": "encode", "input": "x"})
+	testutils.AssertErrorContains(t, err, "only decodes")
+
+	_, err = runEncode(t, map[string]any{"format": "hex", "action": "decode", "input": "xyz"})
+	testutils.AssertErrorContains(t, err, "not valid hex")
+
+	_, err = runEncode(t, map[string]any{"format": "base64", "action": "decode", "input": "x", "jwks": "{}"})
+	testutils.AssertErrorContains(t, err, "only used with the jwt format")
+}
+
+func TestEncode_JWTDecode(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": expires,
+		"iat": int64(1700000000),
+		"big": json.Number("12345678901234567890"),
+	}).SignedString([]byte("secret"))
+	testutils.AssertNoError(t, err)
+
+	response, err := runEncode(t, map[string]any{"format": "jwt", "input": "Bearer " + token})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "decode", response.Action)
+	testutils.AssertEqual(t, "HS256", response.JWT.Header["alg"])
+	payload := response.JWT.Payload.(map[string]any)
+	testutils.AssertEqual(t, "user-1", payload["sub"])
+	testutils.AssertEqual(t, "2023-11-14T22:13:20Z", response.JWT.Times.IssuedAt)
+	testutils.AssertFalse(t, response.JWT.Times.Expired)
+	testutils.AssertTrue(t, response.JWT.Times.ExpiresIn != "")
+	testutils.AssertTrue(t, response.JWT.Signed)
+	testutils.AssertTrue(t, response.JWT.Verification == nil)
+	testutils.AssertTrue(t, strings.Contains(response.Note, "not verified"))
+
+	// Large numbers are kept exactly
+	result, err := (&encode.EncodeTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{"format": "jwt", "input": token})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(result.Content[0].(mcp.TextContent).Text, `"big": 12345678901234567890`))
+
+	unsigned := b64url([]byte(`{"alg":"none"}`)) + "." + b64url([]byte(`{"exp":1000}`)) + "."
+	response, err = runEncode(t, map[string]any{"format": "jwt", "input": unsigned})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.JWT.Signed)
+	testutils.AssertTrue(t, response.JWT.Times.Expired)
+	testutils.AssertEqual(t, 1, len(response.JWT.Warnings))
+
+	_, err = runEncode(t, map[string]any{"format": "jwt", "input": "a.b.c.d.e"})
+	testutils.AssertErrorContains(t, err, "encrypted JWT")
+}
+
+func TestEncode_JWTVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutils.AssertNoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutils.AssertNoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	testutils.AssertNoError(t, err)
+
+	ecPoint, err := ecKey.PublicKey.Bytes()
+	testutils.AssertNoError(t, err)
+	keySet := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": %q}
+	]}`,
+		b64url(rsaKey.N.Bytes()), b64url(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64url(ecPoint[1:33]), b64url(ecPoint[33:]),
+		b64url(edPublic),
+	)
+
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "user-1"})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		testutils.AssertNoError(t, err)
+		return signed
+	}
+
+	for name, token := range map[string]string{
+		"rsa":     sign(jwt.SigningMethodRS256, "rsa", rsaKey),
+		"ecdsa":   sign(jwt.SigningMethodES256, "ec", ecKey),
+		"ed25519": sign(jwt.SigningMethodEdDSA, "ed", edPrivate),
+	} {
+		t.Run(name, func(t *testing.T) {
+			response, err := runEncode(t, map[string]any{"format": "jwt", "input": token, "jwks": keySet})
+			testutils.AssertNoError(t, err)
+			testutils.AssertTrue(t, response.JWT.Verification.Verified)
+			testutils.AssertEqual(t, "", response.JWT.Verification.Error)
+		})
+	}
+
+	// A token signed by another key, or naming a missing key, is not verified
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutils.AssertNoError(t, err)
+	response, err := runEncode(t, map[string]any{"format": "jwt", "input": sign(jwt.SigningMethodRS256, "rsa", otherKey), "jwks": keySet})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.JWT.Verification.Verified)
+	testutils.AssertTrue(t, strings.Contains(response.JWT.Verification.Error, "signature is invalid"))
+
+	response, err = runEncode(t, map[string]any{"format": "jwt", "input": sign(jwt.SigningMethodRS256, "missing", rsaKey), "jwks": keySet})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.JWT.Verification.Verified)
+	testutils.AssertTrue(t, strings.Contains(response.JWT.Verification.Error, `no key with kid "missing"`))
+
+	// A key's alg cannot be overridden by the token
+	response, err = runEncode(t, map[string]any{"format": "jwt", "input": sign(jwt.SigningMethodRS512, "rsa", rsaKey), "jwks": keySet})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.JWT.Verification.Verified)
+	testutils.AssertTrue(t, strings.Contains(response.JWT.Verification.Error, "is for algorithm RS256"))
+}
@@ -0,0 +1,204 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/feed"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+	<title>Example Status</title>
+	<link>https://status.example.com/</link>
+	<atom:link href="https://status.example.com/history.rss" rel="self"/>
+	<description>Incident history</description>
+	<item>
+		<title>Degraded API latency</title>
+		<link>/incidents/2</link>
+		<description>&lt;p&gt;We are &lt;b&gt;investigating&lt;/b&gt; slow responses&amp;nbsp;from the API.&lt;/p&gt;</description>
+		<pubDate>Thu, 15 Oct 2026 09:30:00 +0000</pubDate>
+		<guid>incident-2</guid>
+		<dc:creator>Ops Team</dc:creator>
+	</item>
+	<item>
+		<title>Scheduled maintenance</title>
+		<link>https://status.example.com/incidents/1</link>
+		<description>Database upgrade</description>
+		<pubDate>Mon, 5 Oct 2026 22:00 GMT</pubDate>
+	</item>
+	<item>
+		<title>Undated notice</title>
+	</item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Release notes from tool</title>
+	<link rel="alternate" href="https://github.com/owner/tool/releases"/>
+	<updated>2026-10-16T12:00:00Z</updated>
+	<entry>
+		<id>tag:github.com,2008:Repository/1/v1.2.0</id>
+		<title>v1.2.0</title>
+		<link rel="alternate" href="https://github.com/owner/tool/releases/tag/v1.2.0"/>
+		<updated>2026-10-16T12:00:00Z</updated>
+		<content type="html">&lt;h2&gt;Features&lt;/h2&gt;&lt;ul&gt;&lt;li&gt;Faster builds&lt;/li&gt;&lt;/ul&gt;</content>
+		<author><name>maintainer</name></author>
+	</entry>
+	<entry>
+		<id>tag:github.com,2008:Repository/1/v1.1.0</id>
+		<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">v1.1.0 <em>LTS</em></div></title>
+		<link href="https://github.com/owner/tool/releases/tag/v1.1.0"/>
+		<published>2026-09-01T08:00:00Z</published>
+		<updated>2026-10-10T08:00:00Z</updated>
+		<summary>Backported fixes</summary>
+	</entry>
+</feed>`
+
+const jsonFeed = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Engineering Blog",
+	"home_page_url": "https://blog.example.com/",
+	"items": [
+		{"id": "1", "url": "https://blog.example.com/old", "title": "Old post", "content_text": "Old", "date_published": "2026-01-01T00:00:00Z"},
+		{"id": "2", "url": "https://blog.example.com/new", "title": "New post", "content_html": "<p>Fresh news</p>", "date_published": "2026-10-17T00:00:00+01:00", "tags": ["go"], "authors": [{"name": "Sam"}]}
+	]
+}`
+
+// feedServer serves the test feeds and counts the requests for each
+func feedServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/history.rss":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = w.Write([]byte(rssFeed))
+		case "/releases.atom":
+			w.Header().Set("Content-Type", "application/atom+xml")
+			_, _ = w.Write([]byte(atomFeed))
+		case "/feed.json":
+			w.Header().Set("Content-Type", "application/feed+json")
+			_, _ = w.Write([]byte(jsonFeed))
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><body>Not a feed</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// readFeed runs the feed tool and decodes its result
+func readFeed(t *testing.T, cache *sync.Map, args map[string]any) feed.Result {
+	t.Helper()
+	result, err := (&feed.FeedTool{}).Execute(t.Context(), quietLogger(), cache, args)
+	testutils.AssertNoError(t, err)
+	var decoded feed.Result
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &decoded))
+	return decoded
+}
+
+func TestFeed_ParsesRSS(t *testing.T) {
+	server, _ := feedServer(t)
+	result := readFeed(t, &sync.Map{}, map[string]any{"url": server.URL + "/history.rss"})
+
+	testutils.AssertEqual(t, "rss", result.Format)
+	testutils.AssertEqual(t, "Example Status", result.Title)
+	testutils.AssertEqual(t, "https://status.example.com/", result.Link)
+	testutils.AssertEqual(t, 3, result.Total)
+	testutils.AssertEqual(t, 3, len(result.Entries))
+
+	latest := result.Entries[0]
+	testutils.AssertEqual(t, "Degraded API latency", latest.Title)
+	testutils.AssertEqual(t, server.URL+"/incidents/2", latest.Link)
+	testutils.AssertEqual(t, "incident-2", latest.ID)
+	testutils.AssertEqual(t, "We are investigating slow responses from the API.", latest.Summary)
+	testutils.AssertEqual(t, "Ops Team", strings.Join(latest.Authors, ","))
+	testutils.AssertTrue(t, latest.Published.Equal(time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)))
+
+	// Dates without seconds are understood, and undated entries come last
+	testutils.AssertTrue(t, result.Entries[1].Published.Equal(time.Date(2026, 10, 5, 22, 0, 0, 0, time.UTC)))
+	testutils.AssertEqual(t, "Undated notice", result.Entries[2].Title)
+
+	// Entries are filtered by date, leaving out those without one
+	result = readFeed(t, &sync.Map{}, map[string]any{"url": server.URL + "/history.rss", "since": "2026-10-10T00:00:00Z"})
+	testutils.AssertEqual(t, 1, result.Matched)
+	testutils.AssertEqual(t, 1, result.Undated)
+	testutils.AssertEqual(t, "Degraded API latency", result.Entries[0].Title)
+}
+
+func TestFeed_ParsesAtomAndJSONFeeds(t *testing.T) {
+	server, _ := feedServer(t)
+
+	result := readFeed(t, &sync.Map{}, map[string]any{"url": server.URL + "/releases.atom", "limit": float64(1)})
+	testutils.AssertEqual(t, "atom", result.Format)
+	testutils.AssertEqual(t, "https://github.com/owner/tool/releases", result.Link)
+	testutils.AssertEqual(t, 2, result.Matched)
+	testutils.AssertTrue(t, result.Truncated)
+	testutils.AssertEqual(t, "v1.2.0", result.Entries[0].Title)
+	testutils.AssertEqual(t, "Features Faster builds", result.Entries[0].Summary)
+	testutils.AssertEqual(t, "maintainer", strings.Join(result.Entries[0].Authors, ","))
+
+	// An entry updated after since is returned, even though it was published before
+	result = readFeed(t, &sync.Map{}, map[string]any{"url": server.URL + "/releases.atom", "since": "2026-10-01"})
+	testutils.AssertEqual(t, 2, result.Matched)
+	testutils.AssertEqual(t, "v1.1.0 LTS", result.Entries[1].Title)
+	testutils.AssertEqual(t, "Backported fixes", result.Entries[1].Summary)
+
+	result = readFeed(t, &sync.Map{}, map[string]any{"url": server.URL + "/feed.json"})
+	testutils.AssertEqual(t, "json", result.Format)
+	testutils.AssertEqual(t, "Engineering Blog", result.Title)
+	testutils.AssertEqual(t, "New post", result.Entries[0].Title)
+	testutils.AssertEqual(t, "Fresh news", result.Entries[0].Summary)
+	testutils.AssertEqual(t, "go", strings.Join(result.Entries[0].Categories, ","))
+	testutils.AssertEqual(t, "Sam", strings.Join(result.Entries[0].Authors, ","))
+	testutils.AssertEqual(t, "Old post", result.Entries[1].Title)
+}
+
+func TestFeed_CachesFeeds(t *testing.T) {
+	server, requests := feedServer(t)
+	cache := &sync.Map{}
+	args := map[string]any{"url": server.URL + "/feed.json"}
+
+	result := readFeed(t, cache, args)
+	testutils.AssertTrue(t, !result.Cached)
+	result = readFeed(t, cache, map[string]any{"url": server.URL + "/feed.json", "since": "2026-06-01"})
+	testutils.AssertTrue(t, result.Cached)
+	testutils.AssertEqual(t, 1, result.Matched)
+	testutils.AssertEqual(t, int32(1), requests.Load())
+
+	result = readFeed(t, cache, map[string]any{"url": server.URL + "/feed.json", "refresh": true})
+	testutils.AssertTrue(t, !result.Cached)
+	testutils.AssertEqual(t, int32(2), requests.Load())
+}
+
+func TestFeed_RejectsBadInput(t *testing.T) {
+	server, _ := feedServer(t)
+	tool := &feed.FeedTool{}
+
+	_, err := tool.Execute(t.Context(), quietLogger(), &sync.Map{}, map[string]any{"url": server.URL + "/page"})
+	testutils.AssertErrorContains(t, err, "not an RSS, Atom or JSON feed")
+
+	_, err = tool.Execute(t.Context(), quietLogger(), &sync.Map{}, map[string]any{"url": server.URL + "/missing.rss"})
+	testutils.AssertErrorContains(t, err, "HTTP 404")
+
+	_, err = tool.Execute(t.Context(), quietLogger(), &sync.Map{}, map[string]any{"url": server.URL + "/feed.json", "since": "last week"})
+	testutils.AssertErrorContains(t, err, "since must be")
+
+	_, err = tool.Execute(t.Context(), quietLogger(), &sync.Map{}, map[string]any{"url": "ftp://example.com/feed"})
+	testutils.AssertErrorContains(t, err, "http or https")
+}
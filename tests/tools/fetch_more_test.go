@@ -0,0 +1,103 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/internal/tools/utilities/fetchmore"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+var continuationTokenPattern = regexp.MustCompile(`continuation_token "([0-9a-f]+)"`)
+
+// pageText returns the text of a single text content result
+func pageText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	testutils.AssertEqual(t, 1, len(result.Content))
+	text, ok := result.Content[0].(mcp.TextContent)
+	testutils.AssertTrue(t, ok)
+	return text.Text
+}
+
+// stripFooter removes the pagination marker from the end of a page
+func stripFooter(page string) string {
+	if index := strings.LastIndex(page, "\n\n["); index >= 0 {
+		return page[:index]
+	}
+	return page
+}
+
+func TestFetchMore_ReadsPaginatedOutputInOrder(t *testing.T) {
+	tools.EnablePagination()
+	t.Setenv(tools.OutputPageSizeEnvVar, "1000")
+
+	var lines []string
+	for i := range 200 {
+		lines = append(lines, fmt.Sprintf("entry %03d ✓", i))
+	}
+	full := strings.Join(lines, "\n")
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), mcpserver.NewInProcessSession("fetch-more-a", nil))
+
+	first := pageText(t, tools.Paginate(ctx, "directory_tree", mcp.NewToolResultText(full)))
+	matches := continuationTokenPattern.FindStringSubmatch(first)
+	testutils.AssertEqual(t, 2, len(matches))
+	token := matches[1]
+	testutils.AssertTrue(t, len(first) < 1200)
+	// Pages end on a line break when one is near the limit
+	testutils.AssertTrue(t, strings.HasSuffix(stripFooter(first), "\n"))
+
+	// Another session cannot read the output
+	other := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), mcpserver.NewInProcessSession("fetch-more-b", nil))
+	_, err := tools.FetchMore(other, token)
+	testutils.AssertError(t, err)
+
+	tool := &fetchmore.FetchMoreTool{}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	reassembled := stripFooter(first)
+	for range 100 {
+		result, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{"continuation_token": token})
+		testutils.AssertNoError(t, err)
+		page := pageText(t, result)
+		reassembled += stripFooter(page)
+		if strings.Contains(page, "[End of output from directory_tree") {
+			break
+		}
+		testutils.AssertTrue(t, strings.Contains(page, token))
+	}
+	testutils.AssertEqual(t, full, reassembled)
+
+	// The output is dropped once read to the end
+	_, err = tool.Execute(ctx, logger, &sync.Map{}, map[string]any{"continuation_token": token})
+	testutils.AssertError(t, err)
+}
+
+func TestFetchMore_LeavesSmallAndNonTextResults(t *testing.T) {
+	tools.EnablePagination()
+	t.Setenv(tools.OutputPageSizeEnvVar, "100")
+
+	small := mcp.NewToolResultText("short output")
+	testutils.AssertEqual(t, small, tools.Paginate(context.Background(), "calculator", small))
+
+	failed := mcp.NewToolResultError(strings.Repeat("x", 500))
+	testutils.AssertEqual(t, failed, tools.Paginate(context.Background(), "calculator", failed))
+
+	image := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent(strings.Repeat("x", 500)),
+		mcp.NewImageContent("aGVsbG8=", "image/png"),
+	}}
+	testutils.AssertEqual(t, image, tools.Paginate(context.Background(), "screenshot", image))
+
+	t.Setenv(tools.OutputPageSizeEnvVar, "0")
+	large := mcp.NewToolResultText(strings.Repeat("x", 500))
+	testutils.AssertEqual(t, large, tools.Paginate(context.Background(), "calculator", large))
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/filesystem"
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseAllowedDirectories(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+
+	// Semicolons separate entries on every platform
+	dirs := filesystem.ParseAllowedDirectories(first + " ; " + second + ";")
+	if !slices.Equal(dirs, []string{first, second}) {
+		t.Errorf("expected both directories from a semicolon separated list, got %v", dirs)
+	}
+
+	// Without semicolons the platform's list separator is used
+	dirs = filesystem.ParseAllowedDirectories(first + string(os.PathListSeparator) + second)
+	if !slices.Equal(dirs, []string{first, second}) {
+		t.Errorf("expected both directories from a %q separated list, got %v", os.PathListSeparator, dirs)
+	}
+
+	if runtime.GOOS == "windows" {
+		// A single Windows path must not be split at its drive letter
+		dirs = filesystem.ParseAllowedDirectories(`C:\work`)
+		if !slices.Equal(dirs, []string{`C:\work`}) {
+			t.Errorf("expected a single directory, got %v", dirs)
+		}
+	}
+}
+
+func TestFileSystemTool_PathComparisonFollowsPlatform(t *testing.T) {
+	tempDir := t.TempDir()
+	tool := setupFilesystemTool(tempDir)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testFile := filepath.Join(tempDir, "notes.txt")
+	if err := os.WriteFile(testFile, []byte("hello"), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	read := func(path string) error {
+		_, err := tool.Execute(context.Background(), logger, &sync.Map{}, map[string]any{
+			"function": "read_file",
+			"options":  map[string]any{"path": path},
+		})
+		return err
+	}
+
+	// A sibling directory sharing the allowed directory's name as a prefix is outside it
+	if err := read(tempDir + "-sibling" + string(filepath.Separator) + "notes.txt"); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("expected access to a sibling directory to be denied, got %v", err)
+	}
+
+	// Windows paths are case-insensitive and may use the long path prefix
+	upper := strings.ToUpper(testFile)
+	if runtime.GOOS == "windows" {
+		if err := read(upper); err != nil {
+			t.Errorf("expected a differently cased path to be allowed, got %v", err)
+		}
+		if err := read(`\\?\` + testFile); err != nil {
+			t.Errorf("expected a long path prefixed path to be allowed, got %v", err)
+		}
+	} else if upper != testFile {
+		if err := read(upper); err == nil || !strings.Contains(err.Error(), "access denied") {
+			t.Errorf("expected a differently cased path to be denied, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/finance"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// financeHTTPClient serves canned responses by exact URL and 404 for anything else
+type financeHTTPClient struct {
+	responses map[string]string
+	requested []*http.Request
+	// err, when set, is returned for every request, as a transport failure
+	err error
+}
+
+func (c *financeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req)
+	if c.err != nil {
+		return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: c.err}
+	}
+	body, ok := c.responses[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message":"not found"}`)), Header: http.Header{}}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+}
+
+func runFinance(t *testing.T, client *financeHTTPClient, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	tool := finance.NewFinanceTool(client)
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return nil, err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	var response map[string]any
+	testutils.AssertNoError(t, json.Unmarshal([]byte(text.Text), &response))
+	return response, nil
+}
+
+func TestFinanceTool_Definition(t *testing.T) {
+	definition := finance.NewFinanceTool(nil).Definition()
+	testutils.AssertEqual(t, "finance", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, *definition.Annotations.OpenWorldHint)
+}
+
+func TestFinanceTool_Validation(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "")
+	t.Setenv("ALPHAVANTAGE_API_KEY", "")
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"unknown action", map[string]any{"action": "buy"}, "action must be one of"},
+		{"invalid base", map[string]any{"base": "dollars"}, "invalid base"},
+		{"invalid symbol", map[string]any{"symbols": []any{"EUR", "E1"}}, "invalid currency code"},
+		{"too many symbols", map[string]any{"symbols": "AUD,CAD,CHF,CNY,EUR,GBP,HKD,ISK,JPY,NZD,THB"}, "too many symbols"},
+		{"only the base", map[string]any{"base": "EUR", "symbols": []any{"eur"}}, "only lists the base currency"},
+		{"invalid date", map[string]any{"date": "17/10/2025"}, "use YYYY-MM-DD"},
+		{"future date", map[string]any{"date": "2999-01-01"}, "in the future"},
+		{"convert without to", map[string]any{"action": "convert", "from": "NZD"}, "missing required parameters"},
+		{"negative amount", map[string]any{"action": "convert", "amount": float64(-5), "from": "NZD", "to": "ISK"}, "cannot be negative"},
+		{"history without start", map[string]any{"action": "history", "symbols": []any{"THB"}}, "missing required parameter: start"},
+		{"history too long", map[string]any{"action": "history", "symbols": []any{"THB"}, "start": "2023-01-01", "end": "2025-01-01"}, "longer than 366 days"},
+		{"history backwards", map[string]any{"action": "history", "symbols": []any{"THB"}, "start": "2025-02-01", "end": "2025-01-01"}, "is after end"},
+		{"quote without symbols", map[string]any{"action": "quote"}, "missing required parameter: symbols"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &financeHTTPClient{}
+			_, err := runFinance(t, client, tt.args)
+			testutils.AssertErrorContains(t, err, tt.want)
+			testutils.AssertEqual(t, 0, len(client.requested))
+		})
+	}
+
+	t.Setenv("FINANCE_FX_PROVIDER", "bank")
+	_, err := runFinance(t, &financeHTTPClient{}, map[string]any{})
+	testutils.AssertErrorContains(t, err, "unknown FINANCE_FX_PROVIDER")
+}
+
+func TestFinanceTool_RatesSharedWithCalc(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "")
+	client := &financeHTTPClient{responses: map[string]string{
+		"https://api.frankfurter.dev/v1/latest?base=USD&symbols=THB%2CISK": `{"amount": 1.0, "base": "USD", "date": "2025-10-17", "rates": {"ISK": 122.04, "THB": 32.5}}`,
+	}}
+
+	response, err := runFinance(t, client, map[string]any{"symbols": []any{"thb", "ISK", "USD"}})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "2025-10-17", response["date"])
+	rates := response["rates"].(map[string]any)
+	testutils.AssertEqual(t, "32.5", rates["THB"])
+	testutils.AssertEqual(t, "122.04", rates["ISK"])
+	testutils.AssertEqual(t, "1", rates["USD"])
+	testutils.AssertTrue(t, testutils.Contains(response["note"].(string), "calc tool now uses these rates"))
+
+	// calc converts the currencies at the rates just fetched, including ones it has no static rate for
+	result, err := runCalc(t, map[string]any{"expression": "1000 THB to USD"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "30.7692307692 USD", result.Result.Result)
+	testutils.AssertEqual(t, "Currency conversions use live rates: Frankfurter (European Central Bank reference rates) for 2025-10-17.", result.Note)
+}
+
+func TestFinanceTool_ConvertOnDate(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "")
+	client := &financeHTTPClient{responses: map[string]string{
+		// A Saturday, for which Frankfurter returns the Friday's rates
+		"https://api.frankfurter.dev/v1/2025-10-18?base=NZD&symbols=ISK": `{"amount": 1.0, "base": "NZD", "date": "2025-10-17", "rates": {"ISK": 70.13}}`,
+	}}
+
+	response, err := runFinance(t, client, map[string]any{"action": "convert", "amount": float64(2.5), "from": "nzd", "to": "isk", "date": "2025-10-18"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "2.5", response["amount"])
+	testutils.AssertEqual(t, "70.13", response["rate"])
+	testutils.AssertEqual(t, "175.325", response["result"])
+	testutils.AssertEqual(t, "2025-10-17", response["date"])
+	note := response["note"].(string)
+	testutils.AssertTrue(t, testutils.Contains(note, "No rates were published on 2025-10-18"))
+	// Past rates are not shared with calc
+	testutils.AssertTrue(t, !testutils.Contains(note, "calc"))
+
+	response, err = runFinance(t, client, map[string]any{"action": "convert", "amount": float64(12), "from": "ISK", "to": "ISK"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "12.00", response["result"])
+	testutils.AssertEqual(t, 1, len(client.requested))
+
+	_, err = runFinance(t, client, map[string]any{"action": "convert", "from": "NZD", "to": "XAU", "date": "2025-10-17"})
+	testutils.AssertErrorContains(t, err, "no Frankfurter rates for NZD, XAU")
+}
+
+func TestFinanceTool_History(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "")
+	client := &financeHTTPClient{responses: map[string]string{
+		"https://api.frankfurter.dev/v1/2025-10-01..2025-10-06?base=USD&symbols=THB": `{"amount": 1.0, "base": "USD", "start_date": "2025-10-01", "end_date": "2025-10-06",
+			"rates": {"2025-10-01": {"THB": 32.4}, "2025-10-02": {"THB": 32.1}, "2025-10-03": {"THB": 32.9}, "2025-10-06": {"THB": 32.562}}}`,
+	}}
+
+	response, err := runFinance(t, client, map[string]any{"action": "history", "symbols": []any{"THB"}, "start": "2025-10-01", "end": "2025-10-06"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 4, len(response["rates"].(map[string]any)))
+	summary := response["summary"].(map[string]any)["THB"].(map[string]any)
+	testutils.AssertEqual(t, "32.4", summary["first"])
+	testutils.AssertEqual(t, "32.562", summary["last"])
+	testutils.AssertEqual(t, "32.1", summary["min"])
+	testutils.AssertEqual(t, "32.9", summary["max"])
+	testutils.AssertEqual(t, "0.50", summary["change_percent"])
+}
+
+func TestFinanceTool_AlphaVantage(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "alphavantage")
+	t.Setenv("ALPHAVANTAGE_API_KEY", "test-key")
+	client := &financeHTTPClient{responses: map[string]string{
+		"https://www.alphavantage.co/query?apikey=test-key&from_currency=ISK&function=CURRENCY_EXCHANGE_RATE&to_currency=THB": `{"Realtime Currency Exchange Rate": {
+			"1. From_Currency Code": "ISK", "3. To_Currency Code": "THB", "5. Exchange Rate": "0.26630000", "6. Last Refreshed": "2025-10-17 21:15:01", "7. Time Zone": "UTC"}}`,
+		"https://www.alphavantage.co/query?apikey=test-key&function=GLOBAL_QUOTE&symbol=ACME": `{"Global Quote": {"01. symbol": "ACME", "02. open": "101.2000", "03. high": "104.0000",
+			"04. low": "100.5000", "05. price": "103.5500", "06. volume": "1234567", "07. latest trading day": "2025-10-17", "08. previous close": "101.0000",
+			"09. change": "2.5500", "10. change percent": "2.5248%"}}`,
+		"https://www.alphavantage.co/query?apikey=test-key&function=GLOBAL_QUOTE&symbol=NOPE":  `{"Global Quote": {}}`,
+		"https://www.alphavantage.co/query?apikey=test-key&function=GLOBAL_QUOTE&symbol=LIMIT": `{"Information": "Our standard API rate limit is 25 requests per day."}`,
+	}}
+
+	response, err := runFinance(t, client, map[string]any{"action": "convert", "amount": float64(1000), "from": "ISK", "to": "THB"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Alpha Vantage", response["provider"])
+	testutils.AssertEqual(t, "0.2663", response["rate"])
+	testutils.AssertEqual(t, "266.30", response["result"])
+	testutils.AssertEqual(t, "2025-10-17", response["date"])
+
+	_, err = runFinance(t, client, map[string]any{"base": "ISK"})
+	testutils.AssertErrorContains(t, err, "symbols are required with the Alpha Vantage provider")
+
+	response, err = runFinance(t, client, map[string]any{"action": "quote", "symbols": []any{"acme", "NOPE"}})
+	testutils.AssertNoError(t, err)
+	quote := response["quotes"].([]any)[0].(map[string]any)
+	testutils.AssertEqual(t, "ACME", quote["symbol"])
+	testutils.AssertEqual(t, "103.55", quote["price"])
+	testutils.AssertEqual(t, "2.5248", quote["change_percent"])
+	testutils.AssertEqual(t, "1234567", quote["volume"])
+	testutils.AssertEqual(t, "NOPE", response["not_found"].([]any)[0])
+
+	// Refusals arrive with status 200, and are reported rather than cached as data
+	_, err = runFinance(t, client, map[string]any{"action": "quote", "symbols": []any{"LIMIT"}})
+	testutils.AssertErrorContains(t, err, "request refused by Alpha Vantage: Our standard API rate limit")
+
+	// The key never appears in errors, even when the transport's error repeats the URL
+	client.err = errors.New("connection refused")
+	_, err = runFinance(t, client, map[string]any{"action": "quote", "symbols": []any{"OTHER"}})
+	testutils.AssertErrorContains(t, err, "connection refused")
+	testutils.AssertTrue(t, !testutils.Contains(err.Error(), "test-key"))
+}
+
+func TestFinanceTool_QuoteNeedsKey(t *testing.T) {
+	t.Setenv("FINANCE_FX_PROVIDER", "")
+	t.Setenv("ALPHAVANTAGE_API_KEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	client := &financeHTTPClient{}
+	_, err := runFinance(t, client, map[string]any{"action": "quote", "symbols": []any{"ACME"}})
+	testutils.AssertErrorContains(t, err, "ALPHAVANTAGE_API_KEY")
+	testutils.AssertEqual(t, 0, len(client.requested))
+}
@@ -0,0 +1,225 @@
+package tools_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/forge"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const forgeBitbucketDiff = `diff --git a/src/app.go b/src/app.go
+index 1111111..2222222 100644
+--- a/src/app.go
++++ b/src/app.go
+@@ -1,3 +1,4 @@
+ package app
+-var x = 1
++var x = 2
++var y = 3
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1 @@
++# New
+`
+
+// newForgeServer serves canned API responses keyed by escaped path and records request headers
+func newForgeServer(t *testing.T, kind string, responses map[string]string) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var lastHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastHeaders = r.Header.Clone()
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not Found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(forge.HostsEnvVar, server.URL+"="+kind)
+	t.Setenv(forge.TokenEnvVar(strings.TrimPrefix(server.URL, "http://")), "secret-token")
+	return server, &lastHeaders
+}
+
+func runForge(t *testing.T, args map[string]any) (string, error) {
+	t.Helper()
+	tool := &forge.ForgeTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestForgeTool_Definition(t *testing.T) {
+	tool := &forge.ForgeTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "forge", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, forge.HostsEnvVar))
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, forge.AllowWriteEnvVar))
+}
+
+func TestForge_ParseRepository(t *testing.T) {
+	t.Setenv(forge.HostsEnvVar, "gitlab.example.com=gitlab,https://git.example.com/bitbucket=bitbucket")
+	hosts, err := forge.LoadHosts()
+	testutils.AssertNoError(t, err)
+
+	tests := []struct {
+		input  string
+		host   string
+		kind   string
+		path   string
+		number int
+	}{
+		{"sammcj/mcp-devtools", "github.com", forge.KindGitHub, "sammcj/mcp-devtools", 0},
+		{"https://github.com/sammcj/mcp-devtools/pull/12", "github.com", forge.KindGitHub, "sammcj/mcp-devtools", 12},
+		{"gitlab.example.com/platform/backend/api", "gitlab.example.com", forge.KindGitLab, "platform/backend/api", 0},
+		{"https://gitlab.example.com/platform/api/-/merge_requests/42", "gitlab.example.com", forge.KindGitLab, "platform/api", 42},
+		{"https://gitlab.com/group/project.git", "gitlab.com", forge.KindGitLab, "group/project", 0},
+		{"https://git.example.com/bitbucket/projects/PLAT/repos/api/pull-requests/7/overview", "git.example.com", forge.KindBitbucket, "PLAT/api", 7},
+		{"https://git.example.com/bitbucket/users/sam/repos/dotfiles", "git.example.com", forge.KindBitbucket, "~sam/dotfiles", 0},
+		{"git.example.com/bitbucket/PLAT/api", "git.example.com", forge.KindBitbucket, "PLAT/api", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repo, err := forge.ParseRepository(tt.input, hosts)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tt.host, repo.Host.Name)
+			testutils.AssertEqual(t, tt.kind, repo.Host.Kind)
+			testutils.AssertEqual(t, tt.path, repo.Path)
+			testutils.AssertEqual(t, tt.number, repo.Number)
+		})
+	}
+
+	_, err = forge.ParseRepository("https://git.unknown.com/a/b", hosts)
+	testutils.AssertErrorContains(t, err, "unknown host git.unknown.com")
+	_, err = forge.ParseRepository("gitlab.example.com/../secrets", hosts)
+	testutils.AssertError(t, err)
+}
+
+func TestForge_LoadHostsValidation(t *testing.T) {
+	testutils.AssertEqual(t, "FORGE_TOKEN_GITLAB_EXAMPLE_COM_8443", forge.TokenEnvVar("gitlab.example.com:8443"))
+
+	t.Setenv(forge.HostsEnvVar, "gitlab.example.com=gitea")
+	_, err := forge.LoadHosts()
+	testutils.AssertErrorContains(t, err, "invalid FORGE_HOSTS entry")
+
+	t.Setenv(forge.HostsEnvVar, "")
+	t.Setenv(forge.TokenEnvVar("gitlab.com"), "")
+	t.Setenv("GITLAB_TOKEN", "fallback")
+	hosts, err := forge.LoadHosts()
+	testutils.AssertNoError(t, err)
+	for _, host := range hosts {
+		if host.Name == "gitlab.com" {
+			testutils.AssertEqual(t, "fallback", host.Token)
+		}
+	}
+}
+
+func TestForgeTool_GitLab(t *testing.T) {
+	server, headers := newForgeServer(t, forge.KindGitLab, map[string]string{
+		"/api/v4/projects/platform%2Fapi/issues": `[
+			{"iid": 12, "title": "Login fails | proxy", "state": "opened", "author": {"username": "sam"}, "labels": ["bug"], "user_notes_count": 3, "web_url": "https://gitlab.example.com/platform/api/-/issues/12", "updated_at": "2026-10-01T10:00:00Z"}
+		]`,
+		"/api/v4/projects/platform%2Fapi/repository/commits/main": `{"id": "abc123"}`,
+		"/api/v4/projects/platform%2Fapi/repository/commits/abc123/statuses": `[
+			{"name": "lint", "status": "success"},
+			{"name": "test", "status": "failed", "target_url": "https://ci.example.com/1"},
+			{"name": "flaky", "status": "failed", "allow_failure": true},
+			{"name": "deploy", "status": "running"}
+		]`,
+	})
+	repository := strings.TrimPrefix(server.URL, "http://") + "/platform/api"
+
+	output, err := runForge(t, map[string]any{"function": "list_issues", "repository": repository})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, `Login fails \| proxy`))
+	testutils.AssertTrue(t, testutils.Contains(output, "| open | @sam | bug | 3 | 2026-10-01 |"))
+	testutils.AssertEqual(t, "secret-token", (*headers).Get("PRIVATE-TOKEN"))
+
+	output, err = runForge(t, map[string]any{
+		"function":   "get_commit_status",
+		"repository": repository,
+		"options":    map[string]any{"ref": "main"},
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "- Commit: abc123"))
+	testutils.AssertTrue(t, testutils.Contains(output, "State: **failure** (1 failing, 1 pending"))
+	// Failing checks are listed first
+	testutils.AssertTrue(t, strings.Index(output, "| test |") < strings.Index(output, "| lint |"))
+
+	_, err = runForge(t, map[string]any{"function": "get_issue", "repository": repository, "options": map[string]any{"number": float64(99)}})
+	testutils.AssertErrorContains(t, err, "check the repository path")
+}
+
+func TestForgeTool_Bitbucket(t *testing.T) {
+	server, headers := newForgeServer(t, forge.KindBitbucket, map[string]string{
+		"/rest/api/1.0/projects/PLAT/repos/api/pull-requests/7.diff": forgeBitbucketDiff,
+		"/rest/api/1.0/projects/PLAT/repos/api/pull-requests/7": `{
+			"id": 7, "title": "Bump x", "state": "OPEN", "author": {"user": {"name": "sam"}},
+			"fromRef": {"displayId": "feature/x", "latestCommit": "0123456789abcdef0123456789abcdef01234567"},
+			"toRef": {"displayId": "main"},
+			"reviewers": [{"user": {"name": "alex"}, "status": "NEEDS_WORK"}]
+		}`,
+		"/rest/api/1.0/projects/PLAT/repos/api/pull-requests/7/activities": `{"isLastPage": true, "values": [
+			{"action": "COMMENTED", "commentAction": "ADDED", "commentAnchor": {"path": "src/app.go", "line": 2},
+			 "comment": {"id": 5, "text": "Why 2?", "author": {"name": "alex"}, "comments": [{"id": 6, "text": "Spec says so", "author": {"name": "sam"}}]}},
+			{"action": "APPROVED"}
+		]}`,
+		"/rest/build-status/1.0/commits/0123456789abcdef0123456789abcdef01234567": `{"isLastPage": true, "values": [
+			{"state": "SUCCESSFUL", "key": "build", "name": "Build"},
+			{"state": "INPROGRESS", "key": "e2e"}
+		]}`,
+	})
+	base := server.URL + "/projects/PLAT/repos/api/pull-requests/7"
+
+	output, err := runForge(t, map[string]any{"function": "get_pull_request_diff", "repository": base})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "2 files on this page, +3 -1"))
+	testutils.AssertTrue(t, testutils.Contains(output, "`src/app.go` (modified, +2 -1)"))
+	testutils.AssertTrue(t, testutils.Contains(output, "`docs/new.md` (added, +1 -0)"))
+	testutils.AssertEqual(t, "Bearer secret-token", (*headers).Get("Authorization"))
+
+	output, err = runForge(t, map[string]any{"function": "get_review_comments", "repository": base})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "**@alex** changes requested"))
+	testutils.AssertTrue(t, testutils.Contains(output, "**@alex** (line 2"))
+	testutils.AssertTrue(t, testutils.Contains(output, "  - reply: **@sam**"))
+
+	// Without a ref the pull request's head commit is checked
+	output, err = runForge(t, map[string]any{"function": "get_commit_status", "repository": base})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, testutils.Contains(output, "State: **pending**"))
+	testutils.AssertTrue(t, testutils.Contains(output, "| e2e | in progress |"))
+
+	_, err = runForge(t, map[string]any{"function": "list_issues", "repository": base})
+	testutils.AssertTrue(t, errors.Is(err, forge.ErrUnsupported))
+}
+
+func TestForgeTool_Validation(t *testing.T) {
+	t.Setenv(forge.AllowWriteEnvVar, "")
+	_, err := runForge(t, map[string]any{"function": "create_issue", "repository": "gitlab.com/group/project", "options": map[string]any{"title": "x"}})
+	testutils.AssertErrorContains(t, err, forge.AllowWriteEnvVar)
+
+	_, err = runForge(t, map[string]any{"function": "get_pull_request", "repository": "gitlab.com/group/project"})
+	testutils.AssertErrorContains(t, err, "number is required")
+
+	_, err = runForge(t, map[string]any{"function": "search_code", "repository": "gitlab.com/group/project"})
+	testutils.AssertErrorContains(t, err, "options.query is required")
+
+	_, err = runForge(t, map[string]any{"function": "list_issues", "repository": "gitlab.com/group/project", "options": map[string]any{"page": float64(0)}})
+	testutils.AssertErrorContains(t, err, "options.page must be 1 or greater")
+}
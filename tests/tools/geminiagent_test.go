@@ -1,12 +1,30 @@
 package tools_test
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools"
 	"github.com/sammcj/mcp-devtools/internal/tools/geminiagent"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// fakeGeminiScript answers like the gemini CLI with JSON output, or like an older CLI without it when
+// OLD_GEMINI is set
+const fakeGeminiScript = `#!/bin/sh
+case "$*" in
+  *output-format*)
+    if [ -n "$OLD_GEMINI" ]; then echo "Unknown argument: output-format" >&2; exit 1; fi
+    echo '{"response":"Looks good","stats":{"models":{"gemini-2.5-pro":{"tokens":{"prompt":1000,"candidates":100,"cached":200,"thoughts":100,"total":1200}}}}}' ;;
+  *) echo "Plain answer" ;;
+esac
+`
+
 func TestGeminiAgentTool_Definition(t *testing.T) {
 	tool := &geminiagent.GeminiTool{}
 	def := tool.Definition()
@@ -14,3 +32,52 @@ func TestGeminiAgentTool_Definition(t *testing.T) {
 	assert.NotNil(t, def)
 	assert.Equal(t, "gemini-agent", def.GetName())
 }
+
+func TestGeminiAgentTool_CostTracking(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gemini script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gemini"), []byte(fakeGeminiScript), 0700))
+	t.Setenv("PATH", dir)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(tools.AgentCostLimitEnvVar, "0.005")
+	t.Setenv(tools.AgentModelPricesEnvVar, "")
+	ctx := artifactSession("gemini-cost")
+	tool := &geminiagent.GeminiTool{}
+	args := map[string]any{"prompt": "Review the change"}
+
+	result, err := tool.Execute(ctx, quietLogger(), &sync.Map{}, args)
+	require.NoError(t, err)
+	assert.Equal(t, "Looks good", result.Content[0].(mcp.TextContent).Text)
+	usage := result.Meta.AdditionalFields[tools.AgentUsageMetaKey].(map[string]any)
+	models := usage["models"].([]tools.AgentUsage)
+	require.Len(t, models, 1)
+	assert.Equal(t, "gemini-2.5-pro", models[0].Model)
+	assert.Equal(t, 1000, models[0].InputTokens)
+	// Thinking is counted as output
+	assert.Equal(t, 200, models[0].OutputTokens)
+	assert.False(t, models[0].Estimated)
+	assert.InDelta(t, 0.00325, usage["cost_usd"].(float64), 1e-9)
+	assert.Equal(t, 1, usage["session"].(tools.AgentSessionUsage).Calls)
+
+	// The second call takes the session over its limit, so the third is refused
+	result, err = tool.Execute(ctx, quietLogger(), &sync.Map{}, args)
+	require.NoError(t, err)
+	session := result.Meta.AdditionalFields[tools.AgentUsageMetaKey].(map[string]any)["session"].(tools.AgentSessionUsage)
+	assert.InDelta(t, 0.0065, session.CostUSD, 1e-9)
+	assert.Equal(t, 0.005, session.LimitUSD)
+	_, err = tool.Execute(ctx, quietLogger(), &sync.Map{}, args)
+	require.ErrorContains(t, err, "AGENT_SESSION_COST_LIMIT")
+
+	// Other sessions have their own budget, and an older CLI has its tokens estimated
+	t.Setenv("OLD_GEMINI", "1")
+	t.Setenv(tools.AgentModelPricesEnvVar, "gemini-2.5-pro=0/0")
+	result, err = tool.Execute(artifactSession("gemini-cost-other"), quietLogger(), &sync.Map{}, args)
+	require.NoError(t, err)
+	assert.Equal(t, "Plain answer\n", result.Content[0].(mcp.TextContent).Text)
+	models = result.Meta.AdditionalFields[tools.AgentUsageMetaKey].(map[string]any)["models"].([]tools.AgentUsage)
+	assert.True(t, models[0].Estimated)
+	assert.Equal(t, 5, models[0].InputTokens)
+	assert.Equal(t, 0.0, *models[0].CostUSD)
+}
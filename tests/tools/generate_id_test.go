@@ -0,0 +1,141 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/generateid"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// runGenerateID calls the generate_id tool and decodes its JSON response into out
+func runGenerateID(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&generateid.GenerateIDTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+func TestGenerateID_UUIDs(t *testing.T) {
+	for _, tt := range []struct {
+		kind    string
+		version uuid.Version
+	}{{"uuid_v4", 4}, {"uuid_v7", 7}} {
+		var response generateid.GenerateResponse
+		testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": tt.kind, "count": float64(20)}, &response))
+		testutils.AssertEqual(t, 20, len(response.Values))
+
+		for _, value := range response.Values {
+			id, err := uuid.Parse(value)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tt.version, id.Version())
+		}
+		testutils.AssertEqual(t, 20, len(slices.Compact(slices.Sorted(slices.Values(response.Values)))))
+	}
+}
+
+func TestGenerateID_ULIDsAreOrdered(t *testing.T) {
+	var response generateid.GenerateResponse
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "ulid", "count": float64(100)}, &response))
+	testutils.AssertEqual(t, 100, len(response.Values))
+	testutils.AssertEqual(t, 80, response.EntropyBits)
+
+	ulidPattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	for i, value := range response.Values {
+		testutils.AssertTrue(t, ulidPattern.MatchString(value))
+		if i > 0 {
+			testutils.AssertTrue(t, value > response.Values[i-1])
+		}
+	}
+}
+
+func TestGenerateID_Nanoid(t *testing.T) {
+	var response generateid.GenerateResponse
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "nanoid"}, &response))
+	testutils.AssertEqual(t, 21, len(response.Values[0]))
+	testutils.AssertEqual(t, 126, response.EntropyBits)
+	testutils.AssertTrue(t, regexp.MustCompile(`^[A-Za-z0-9_-]+$`).MatchString(response.Values[0]))
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "nanoid", "length": float64(500), "alphabet": "ABC"}, &response))
+	value := response.Values[0]
+	testutils.AssertEqual(t, 500, len(value))
+	testutils.AssertTrue(t, strings.Trim(value, "ABC") == "")
+	// Every character of a small alphabet appears in a long ID
+	for _, c := range "ABC" {
+		testutils.AssertTrue(t, strings.ContainsRune(value, c))
+	}
+
+	err := runGenerateID(t, map[string]any{"type": "nanoid", "alphabet": "AAB"}, &response)
+	testutils.AssertErrorContains(t, err, "appears more than once")
+	err = runGenerateID(t, map[string]any{"type": "uuid_v4", "alphabet": "AB"}, &response)
+	testutils.AssertErrorContains(t, err, "only used with nanoid")
+}
+
+func TestGenerateID_Secrets(t *testing.T) {
+	var response generateid.GenerateResponse
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "hex", "length": float64(16)}, &response))
+	decoded, err := hex.DecodeString(response.Values[0])
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 16, len(decoded))
+	testutils.AssertEqual(t, 128, response.EntropyBits)
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "base64"}, &response))
+	decoded, err = base64.StdEncoding.DecodeString(response.Values[0])
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 32, len(decoded))
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "base64url", "length": float64(32)}, &response))
+	testutils.AssertEqual(t, 43, len(response.Values[0]))
+	_, err = base64.RawURLEncoding.DecodeString(response.Values[0])
+	testutils.AssertNoError(t, err)
+
+	for _, args := range []map[string]any{
+		{"type": "hex", "length": float64(0)},
+		{"type": "hex", "length": float64(2048)},
+		{"type": "hex", "count": float64(1.5)},
+		{"type": "ulid", "length": float64(10)},
+		{"type": "hex", "input": "secret"},
+		{"type": "md5", "input": "secret"},
+	} {
+		testutils.AssertError(t, runGenerateID(t, args, &response))
+	}
+}
+
+func TestGenerateID_Hashes(t *testing.T) {
+	var response generateid.HashResponse
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "sha256", "input": "abc"}, &response))
+	testutils.AssertEqual(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", response.Hash)
+	testutils.AssertEqual(t, 3, response.InputBytes)
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "sha512", "input": ""}, &response))
+	testutils.AssertTrue(t, strings.HasPrefix(response.Hash, "cf83e1357eefb8bdf1542850d66d8007"))
+
+	testutils.AssertNoError(t, runGenerateID(t, map[string]any{"type": "bcrypt", "input": "correct horse battery staple"}, &response))
+	testutils.AssertEqual(t, "bcrypt", response.Encoding)
+	testutils.AssertNoError(t, bcrypt.CompareHashAndPassword([]byte(response.Hash), []byte("correct horse battery staple")))
+	cost, err := bcrypt.Cost([]byte(response.Hash))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, bcrypt.DefaultCost, cost)
+
+	err = runGenerateID(t, map[string]any{"type": "bcrypt", "input": strings.Repeat("a", 73)}, &response)
+	testutils.AssertErrorContains(t, err, "first 72 bytes")
+	err = runGenerateID(t, map[string]any{"type": "bcrypt", "input": "x", "cost": float64(4)}, &response)
+	testutils.AssertErrorContains(t, err, "'cost'")
+	err = runGenerateID(t, map[string]any{"type": "sha256"}, &response)
+	testutils.AssertErrorContains(t, err, "missing required parameter 'input'")
+}
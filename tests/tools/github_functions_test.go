@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/github"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitStatusMarkdown(t *testing.T) {
+	status := &github.CommitStatus{
+		Repository:  "owner/repo",
+		Ref:         "main",
+		SHA:         "abc123",
+		State:       "failure",
+		Failing:     1,
+		Pending:     1,
+		Passing:     1,
+		TotalChecks: 3,
+		Checks: []github.StatusCheck{
+			{Name: "unit tests", Kind: "check", Status: "completed", Conclusion: "failure", Description: "2 | 3 failed", URL: "https://example.com/1"},
+			{Name: "lint", Kind: "check", Status: "in_progress"},
+			{Name: "ci/jenkins", Kind: "status", Status: "completed", Conclusion: "success"},
+		},
+		Page: github.Page{Page: 1, PerPage: 3, NextPage: 2, LastPage: 4},
+	}
+
+	output := status.Markdown()
+	assert.Contains(t, output, "# CI status: owner/repo@main")
+	assert.Contains(t, output, "- Commit: abc123")
+	assert.Contains(t, output, "- State: **failure** (1 failing, 1 pending, 1 passing of 3 checks)")
+	assert.Contains(t, output, `| unit tests | failure | 2 \| 3 failed [link](https://example.com/1) |`)
+	assert.Contains(t, output, "| lint | in progress |  |")
+	assert.Contains(t, output, "Page 1 of 4. Set options.page to 2 for more checks.")
+
+	empty := &github.CommitStatus{Repository: "owner/repo", Ref: "abc123", SHA: "abc123", State: "none"}
+	output = empty.Markdown()
+	assert.Contains(t, output, "**none**")
+	assert.NotContains(t, output, "Commit:")
+}
+
+func TestPullRequestDiffMarkdown(t *testing.T) {
+	diff := &github.PullRequestDiff{
+		Repository: "owner/repo",
+		Number:     42,
+		Files: []github.PullRequestFile{
+			{Filename: "README.md", Status: "modified", Additions: 2, Deletions: 1, Patch: "@@ -1 +1,2 @@\n-Old\n+New\n+```go"},
+			{Filename: "new.go", PreviousFilename: "old.go", Status: "renamed", Additions: 1, Patch: "@@ -1 +1 @@\n+x", PatchTruncated: true},
+			{Filename: "logo.png", Status: "added"},
+		},
+		Page: github.Page{Page: 2, PerPage: 3},
+	}
+
+	output := diff.Markdown()
+	assert.Contains(t, output, "# Pull request diff: owner/repo#42")
+	assert.Contains(t, output, "3 files on this page, +3 -1")
+	// Patches containing a fence get a longer fence
+	assert.Contains(t, output, "````diff\n@@ -1 +1,2 @@")
+	assert.Contains(t, output, "Renamed from `old.go`")
+	assert.Contains(t, output, "Patch truncated")
+	assert.Contains(t, output, "No text diff")
+	assert.Contains(t, output, "Page 2 (last page).")
+}
+
+func TestReviewCommentsMarkdown(t *testing.T) {
+	comments := &github.ReviewComments{
+		Repository: "owner/repo",
+		Number:     7,
+		Reviews: []github.Review{
+			{Login: "alice", State: "CHANGES_REQUESTED", Body: "Needs tests", SubmittedAt: "2026-03-01T10:00:00Z"},
+		},
+		Comments: []github.ReviewComment{
+			{ID: 1, Login: "alice", Path: "main.go", Line: 10, Body: "Handle the error", CreatedAt: "2026-03-01T10:00:00Z"},
+			{ID: 2, Login: "bob", Path: "util.go", Line: 4, Outdated: true, Body: "Typo", CreatedAt: "2026-03-01T11:00:00Z"},
+			{ID: 3, InReplyTo: 1, Login: "bob", Path: "main.go", Line: 10, Body: "Done\nin abc123", CreatedAt: "2026-03-02T09:00:00Z"},
+		},
+	}
+
+	output := comments.Markdown()
+	assert.Contains(t, output, "- **@alice** changes requested (2026-03-01): Needs tests")
+	assert.Contains(t, output, "### `main.go`\n\n- **@alice** (line 10, 2026-03-01): Handle the error\n  - reply: **@bob** (line 10, 2026-03-02): Done\n    in abc123\n")
+	assert.Contains(t, output, "**@bob** (line 4, outdated, 2026-03-01): Typo")
+	assert.Less(t, strings.Index(output, "main.go"), strings.Index(output, "util.go"))
+}
+
+func TestIssueListMarkdown(t *testing.T) {
+	list := &github.IssueList{
+		Repository: "owner/repo",
+		State:      "open",
+		Labels:     []string{"bug"},
+		Items: []github.IssueSummary{
+			{Number: 12, Title: "Crash when | appears\nin input", State: "open", Login: "alice", Labels: []string{"bug", "p1"}, Comments: 3, HTMLURL: "https://github.com/owner/repo/issues/12", UpdatedAt: "2026-03-01T10:00:00Z"},
+		},
+		Page: github.Page{Page: 1, PerPage: 1, NextPage: 2},
+	}
+
+	output := list.Markdown()
+	assert.Contains(t, output, "# Issues: owner/repo (open)")
+	assert.Contains(t, output, `| [#12](https://github.com/owner/repo/issues/12) | Crash when \| appears in input | open | @alice | bug, p1 | 3 | 2026-03-01 |`)
+	assert.Contains(t, output, "Page 1. Set options.page to 2 for more issues.")
+}
+
+func TestGitHubTool_NewFunctionValidation(t *testing.T) {
+	t.Setenv("GITHUB_AUTH_METHOD", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv(github.GitHubAllowWriteEnvVar, "")
+
+	tool := &github.GitHubTool{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		errorMsg string
+	}{
+		{
+			name:     "search_code requires a query",
+			args:     map[string]any{"function": "search_code", "repository": "owner/repo"},
+			errorMsg: "query parameter is required",
+		},
+		{
+			name:     "search_code requires authentication",
+			args:     map[string]any{"function": "search_code", "options": map[string]any{"query": "TODO"}},
+			errorMsg: "requires authentication",
+		},
+		{
+			name:     "create_issue is disabled by default",
+			args:     map[string]any{"function": "create_issue", "repository": "owner/repo", "options": map[string]any{"title": "Bug"}},
+			errorMsg: "GITHUB_ALLOW_WRITE=true",
+		},
+		{
+			name:     "get_pull_request_diff requires a number",
+			args:     map[string]any{"function": "get_pull_request_diff", "repository": "owner/repo"},
+			errorMsg: "pull request number is required",
+		},
+		{
+			name:     "get_review_comments rejects page zero",
+			args:     map[string]any{"function": "get_review_comments", "repository": "https://github.com/owner/repo/pull/1", "options": map[string]any{"page": float64(0)}},
+			errorMsg: "options.page must be 1 or greater",
+		},
+		{
+			name:     "get_commit_status requires a ref",
+			args:     map[string]any{"function": "get_commit_status", "repository": "owner/repo"},
+			errorMsg: "options.ref is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.Execute(context.Background(), logger, &sync.Map{}, tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	t.Run("create_issue requires a title when enabled", func(t *testing.T) {
+		t.Setenv(github.GitHubAllowWriteEnvVar, "true")
+		_, err := tool.Execute(context.Background(), logger, &sync.Map{}, map[string]any{"function": "create_issue", "repository": "owner/repo"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "options.title is required")
+	})
+}
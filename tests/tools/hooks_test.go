@@ -0,0 +1,168 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/scheduler"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const testHooks = `hooks:
+  - name: convert-docs
+    tool: schedule-echo
+    arguments:
+      format: markdown
+    parameters: [path, pages]
+  - name: own-token
+    tool: schedule-echo
+    token_env: TEST_HOOK_TOKEN
+  - name: overrides
+    tool: schedule-echo
+    arguments: {path: /srv/docs}
+    parameters: [path]
+  - name: missing-token
+    tool: schedule-echo
+    token_env: TEST_HOOK_TOKEN_UNSET
+`
+
+// setupHooks writes hooks.yaml to a temporary home directory and serves its hooks
+func setupHooks(t *testing.T) *httptest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ENABLE_ADDITIONAL_TOOLS", "schedule-echo")
+	t.Setenv("TEST_HOOK_TOKEN", "hook-secret")
+	registry.Init(quietLogger())
+	registry.Register(&echoArgsTool{MockTool: testutils.NewMockTool("schedule-echo")})
+
+	path, err := scheduler.HooksConfigPath()
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(testHooks), 0600))
+	config, err := scheduler.LoadHooksConfig(path)
+	testutils.AssertNoError(t, err)
+
+	hooks := scheduler.NewHooks(config, "server-secret", quietLogger())
+	testutils.AssertEqual(t, "convert-docs,own-token", strings.Join(hooks.Names(), ","))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hooks.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle(scheduler.HooksPathPrefix, hooks)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// callHook sends a request to a hook and decodes its JSON response
+func callHook(t *testing.T, method, url, token, body string) (int, map[string]any) {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	testutils.AssertNoError(t, err)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	testutils.AssertNoError(t, err)
+	defer func() { _ = response.Body.Close() }()
+	var decoded map[string]any
+	testutils.AssertNoError(t, json.NewDecoder(response.Body).Decode(&decoded))
+	return response.StatusCode, decoded
+}
+
+func TestHooks_TriggerRun(t *testing.T) {
+	server := setupHooks(t)
+
+	status, body := callHook(t, http.MethodPost, server.URL+"/hooks/convert-docs", "server-secret", `{"path": "docs/guide.pdf", "pages": 3}`)
+	testutils.AssertEqual(t, http.StatusAccepted, status)
+	testutils.AssertEqual(t, scheduler.StatusQueued, body["status"])
+	statusURL, _ := body["status_url"].(string)
+	testutils.AssertTrue(t, strings.HasPrefix(statusURL, "/hooks/convert-docs/runs/"))
+
+	// Poll until the run is in the history
+	var run map[string]any
+	for range 100 {
+		status, run = callHook(t, http.MethodGet, server.URL+statusURL, "server-secret", "")
+		testutils.AssertEqual(t, http.StatusOK, status)
+		if run["status"] == scheduler.StatusSucceeded {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	testutils.AssertEqual(t, scheduler.StatusSucceeded, run["status"])
+	testutils.AssertEqual(t, "convert-docs", run["hook"])
+
+	// The fixed arguments and the request's parameters reach the tool
+	runs, err := scheduler.History("convert-docs", 1)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(runs))
+	text, err := runScheduledRuns(t, map[string]any{"function": "output", "run_id": runs[0].ID})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, `{"format":"markdown","pages":3,"path":"docs/guide.pdf"}`, text)
+
+	// A run can only be read through its own hook
+	status, _ = callHook(t, http.MethodGet, server.URL+"/hooks/own-token/runs/"+runs[0].ID, "hook-secret", "")
+	testutils.AssertEqual(t, http.StatusNotFound, status)
+}
+
+func TestHooks_Rejected(t *testing.T) {
+	server := setupHooks(t)
+
+	tests := []struct {
+		name, method, path, token, body string
+		status                          int
+		message                         string
+	}{
+		{"no token", http.MethodPost, "/hooks/convert-docs", "", "", http.StatusUnauthorized, "bearer token"},
+		{"wrong token", http.MethodPost, "/hooks/convert-docs", "hook-secret", "", http.StatusUnauthorized, "bearer token"},
+		{"server token for hook with its own", http.MethodPost, "/hooks/own-token", "server-secret", "", http.StatusUnauthorized, "bearer token"},
+		{"unknown hook", http.MethodPost, "/hooks/missing", "server-secret", "", http.StatusNotFound, "hook not found"},
+		{"invalid hook", http.MethodPost, "/hooks/overrides", "server-secret", "", http.StatusNotFound, "hook not found"},
+		{"parameter not allowed", http.MethodPost, "/hooks/convert-docs", "server-secret", `{"format": "html"}`, http.StatusBadRequest, "accepts: path, pages"},
+		{"no parameters allowed", http.MethodPost, "/hooks/own-token", "hook-secret", `{"path": "x"}`, http.StatusBadRequest, "accepts no parameters"},
+		{"not an object", http.MethodPost, "/hooks/convert-docs", "server-secret", `["path"]`, http.StatusBadRequest, "JSON object"},
+		{"too large", http.MethodPost, "/hooks/convert-docs", "server-secret", `{"path": "` + strings.Repeat("a", 70*1024) + `"}`, http.StatusBadRequest, "larger than"},
+		{"wrong method", http.MethodGet, "/hooks/convert-docs", "server-secret", "", http.StatusMethodNotAllowed, "use POST"},
+		{"unknown run", http.MethodGet, "/hooks/convert-docs/runs/20261018-020000-00000000", "server-secret", "", http.StatusNotFound, "run not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := callHook(t, tt.method, server.URL+tt.path, tt.token, tt.body)
+			testutils.AssertEqual(t, tt.status, status)
+			message, _ := body["error"].(string)
+			testutils.AssertTrue(t, strings.Contains(message, tt.message))
+		})
+	}
+}
+
+func TestHooks_Config(t *testing.T) {
+	t.Setenv("TEST_HOOK_TOKEN", "hook-secret")
+	config := &scheduler.HooksConfig{Hooks: []scheduler.Hook{
+		{Name: "no-token", Job: scheduler.Job{Tool: "schedule-echo"}},
+		{Name: "own-token", TokenEnv: "TEST_HOOK_TOKEN", Job: scheduler.Job{Tool: "schedule-echo"}},
+		{Name: "overrides", Parameters: []string{"path"}, Job: scheduler.Job{Tool: "schedule-echo", Arguments: map[string]any{"path": "/srv"}}},
+		{Name: "own-token", TokenEnv: "TEST_HOOK_TOKEN", Job: scheduler.Job{Tool: "schedule-echo"}},
+	}}
+
+	valid, invalid := config.Validated("")
+	testutils.AssertEqual(t, 1, len(valid))
+	testutils.AssertEqual(t, 600, valid[0].Timeout)
+	testutils.AssertErrorContains(t, invalid["no-token"], "has no token")
+	testutils.AssertErrorContains(t, invalid["overrides"], "also a fixed argument")
+
+	valid, _ = config.Validated("server-secret")
+	testutils.AssertEqual(t, 2, len(valid))
+
+	loaded, err := scheduler.LoadHooksConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(loaded.Hooks))
+}
@@ -0,0 +1,203 @@
+package tools_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/imagetool"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// testEXIF builds a big-endian EXIF block with Make, Orientation and a GPS location
+func testEXIF(orientation uint16) []byte {
+	var b bytes.Buffer
+	be := binary.BigEndian
+	entry := func(tag, typ uint16, count, value uint32) {
+		_ = binary.Write(&b, be, tag)
+		_ = binary.Write(&b, be, typ)
+		_ = binary.Write(&b, be, count)
+		_ = binary.Write(&b, be, value)
+	}
+	rationals := func(values ...uint32) {
+		for _, v := range values {
+			_ = binary.Write(&b, be, v)
+			_ = binary.Write(&b, be, uint32(1))
+		}
+	}
+
+	b.WriteString("MM\x00\x2A")
+	_ = binary.Write(&b, be, uint32(8))
+	// IFD0 at 8: three entries, ends at 50
+	_ = binary.Write(&b, be, uint16(3))
+	entry(0x010F, 2, 5, 50)                      // Make -> offset 50
+	entry(0x0112, 3, 1, uint32(orientation)<<16) // Orientation inline
+	entry(0x8825, 4, 1, 56)                      // GPS IFD pointer
+	_ = binary.Write(&b, be, uint32(0))          // no next IFD
+	b.WriteString("Test\x00\x00")                // Make value, padded to 56
+	// GPS IFD at 56: four entries, ends at 110
+	_ = binary.Write(&b, be, uint16(4))
+	entry(0x0001, 2, 2, uint32('S')<<24) // GPSLatitudeRef inline
+	entry(0x0002, 5, 3, 110)             // GPSLatitude -> offset 110
+	entry(0x0003, 2, 2, uint32('E')<<24) // GPSLongitudeRef inline
+	entry(0x0004, 5, 3, 134)             // GPSLongitude -> offset 134
+	_ = binary.Write(&b, be, uint32(0))
+	rationals(33, 51, 0)
+	rationals(151, 12, 36)
+	return b.Bytes()
+}
+
+// writeTestJPEG writes a 40x20 JPEG with an EXIF APP1 segment
+func writeTestJPEG(t *testing.T, path string, orientation uint16) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := range 40 {
+		for y := range 20 {
+			img.Set(x, y, color.RGBA{uint8(x * 6), uint8(y * 12), 128, 255})
+		}
+	}
+	var encoded bytes.Buffer
+	testutils.AssertNoError(t, jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 95}))
+
+	exif := append([]byte("Exif\x00\x00"), testEXIF(orientation)...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(exif)+2))
+	app1 = append(app1, exif...)
+
+	data := slices.Concat(encoded.Bytes()[:2], app1, encoded.Bytes()[2:])
+	testutils.AssertNoError(t, os.WriteFile(path, data, 0600))
+}
+
+func executeImage(t *testing.T, args map[string]any, out any) {
+	t.Helper()
+	tool := &imagetool.ImageTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	testutils.AssertNoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(textContent.Text), out))
+}
+
+func TestImageTool_Definition(t *testing.T) {
+	tool := &imagetool.ImageTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "image", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, "strip metadata"))
+}
+
+func TestImageTool_InfoReadsEXIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	writeTestJPEG(t, path, 6)
+
+	var info imagetool.ImageInfo
+	executeImage(t, map[string]any{"function": "info", "path": path}, &info)
+
+	testutils.AssertEqual(t, "jpeg", info.Format)
+	testutils.AssertEqual(t, 40, info.Width)
+	testutils.AssertEqual(t, 20, info.Height)
+	testutils.AssertEqual(t, 6, info.Orientation)
+	testutils.AssertEqual(t, "Test", info.EXIF["Make"])
+	testutils.AssertTrue(t, slices.Contains(info.Metadata, "exif"))
+	testutils.AssertNotNil(t, info.GPS)
+	testutils.AssertEqual(t, -33.85, info.GPS.Latitude)
+	testutils.AssertEqual(t, 151.21, info.GPS.Longitude)
+	testutils.AssertEqual(t, 1, len(info.Warnings))
+}
+
+func TestImageTool_StripMetadata(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+	writeTestJPEG(t, path, 1)
+
+	var result imagetool.ProcessResult
+	executeImage(t, map[string]any{"function": "strip_metadata", "path": path}, &result)
+
+	testutils.AssertEqual(t, filepath.Join(dir, "photo-stripped.jpg"), result.OutputPath)
+	testutils.AssertTrue(t, slices.Contains(result.RemovedMetadata, "exif"))
+	testutils.AssertTrue(t, result.SizeBytes < int(result.OriginalSizeBytes))
+
+	var info imagetool.ImageInfo
+	executeImage(t, map[string]any{"function": "info", "path": result.OutputPath}, &info)
+	testutils.AssertEqual(t, 0, len(info.EXIF))
+	testutils.AssertEqual(t, 40, info.Width)
+}
+
+func TestImageTool_ResizeAppliesOrientation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+	writeTestJPEG(t, path, 6)
+
+	var result imagetool.ProcessResult
+	executeImage(t, map[string]any{"function": "resize", "path": path, "width": float64(10)}, &result)
+
+	// Orientation 6 rotates the 40x20 image to 20x40 before scaling to width 10
+	testutils.AssertEqual(t, 10, result.Width)
+	testutils.AssertEqual(t, 20, result.Height)
+	testutils.AssertEqual(t, filepath.Join(dir, "photo-resized.jpg"), result.OutputPath)
+
+	executeImage(t, map[string]any{"function": "resize", "path": path, "width": float64(8), "height": float64(8), "fit": "cover", "output_path": filepath.Join(dir, "thumb.png"), "format": "png"}, &result)
+	testutils.AssertEqual(t, 8, result.Width)
+	testutils.AssertEqual(t, 8, result.Height)
+	testutils.AssertEqual(t, "png", result.Format)
+}
+
+func TestImageTool_ConvertAndCompress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "diagram.png")
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	img.Set(3, 3, color.NRGBA{255, 0, 0, 128})
+	f, err := os.Create(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, png.Encode(f, img))
+	_ = f.Close()
+
+	var result imagetool.ProcessResult
+	executeImage(t, map[string]any{"function": "convert", "path": path, "format": "webp"}, &result)
+	testutils.AssertEqual(t, filepath.Join(dir, "diagram.webp"), result.OutputPath)
+
+	var info imagetool.ImageInfo
+	executeImage(t, map[string]any{"function": "info", "path": result.OutputPath}, &info)
+	testutils.AssertEqual(t, "webp", info.Format)
+	testutils.AssertEqual(t, 16, info.Width)
+
+	executeImage(t, map[string]any{"function": "compress", "path": path, "format": "jpeg", "quality": float64(40)}, &result)
+	testutils.AssertEqual(t, filepath.Join(dir, "diagram-compressed.jpg"), result.OutputPath)
+	testutils.AssertEqual(t, "jpeg", result.Format)
+}
+
+func TestImageTool_Validation(t *testing.T) {
+	tool := &imagetool.ImageTool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+	writeTestJPEG(t, path, 1)
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"function": "info", "path": "photo.jpg"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "convert", "path": path})
+	testutils.AssertErrorContains(t, err, "convert requires format")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "resize", "path": path})
+	testutils.AssertErrorContains(t, err, "width, height or both")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "compress", "path": path, "output_path": path})
+	testutils.AssertErrorContains(t, err, "already exists")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "compress", "path": path, "quality": float64(0)})
+	testutils.AssertErrorContains(t, err, "quality must be between 1 and 100")
+}
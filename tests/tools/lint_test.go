@@ -0,0 +1,212 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/lint"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// fakeRuff reports an unused import in app.py and removes it when fixing stdin
+const fakeRuff = `#!/bin/sh
+case "$*" in
+*--output-format=json*)
+  echo '[{"code":"F401","message":"` + "`os`" + ` imported but unused","filename":"'"$PWD"'/app.py","location":{"row":1,"column":8},"fix":{"applicability":"safe"}},{"code":null,"message":"SyntaxError: Expected an expression","filename":"'"$PWD"'/broken.py","location":{"row":2,"column":5},"fix":null}]'
+  exit 1;;
+*--fix*)
+  grep -v '^import os$'
+  exit 0;;
+esac
+exit 2
+`
+
+// fakeESLint reports a fixable rule in app.js and replaces var with let when fixing stdin
+const fakeESLint = `#!/bin/sh
+case "$*" in
+*--stdin*)
+  fixed=$(sed 's/var /let /')
+  printf '[{"filePath":"x","messages":[],"output":%s}]' "$(printf '%s\n' "$fixed" | node -e 'let s="";process.stdin.on("data",d=>s+=d).on("end",()=>process.stdout.write(JSON.stringify(s)))')"
+  exit 0;;
+*)
+  echo '[{"filePath":"'"$PWD"'/src/app.js","messages":[{"ruleId":"no-var","severity":2,"message":"Unexpected var, use let or const instead.","line":1,"column":1,"fix":{"range":[0,3],"text":"let"}},{"ruleId":"no-console","severity":1,"message":"Unexpected console statement.","line":2,"column":1}]}]'
+  exit 1;;
+esac
+`
+
+// fakePrettier lists app.js as unformatted and adds semicolons when formatting stdin
+const fakePrettier = `#!/bin/sh
+case "$*" in
+*--list-different*)
+  echo src/app.js
+  echo '[error] src/bad.js: SyntaxError: Unexpected token (3:5)' >&2
+  exit 2;;
+*--stdin-filepath*)
+  sed 's/)$/);/'
+  exit 0;;
+esac
+`
+
+// runLint calls the lint tool and decodes its result
+func runLint(t *testing.T, args map[string]any) (lint.Result, error) {
+	t.Helper()
+	var out lint.Result
+	result, err := (&lint.LintTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return out, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return out, nil
+}
+
+// linterStatus returns the status of a linter in a result
+func linterStatus(out lint.Result, name string) string {
+	for _, l := range out.Linters {
+		if l.Name == name {
+			return l.Status
+		}
+	}
+	return ""
+}
+
+func TestLint_Gofmt(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt is not installed")
+	}
+	unformatted := "package calc\n\nfunc Add(a,b int) int {\nreturn a+b\n}\n"
+	dir := writeProject(t, map[string]string{
+		"go.mod":               "module example.com/calc\n\ngo 1.22\n",
+		"calc.go":              unformatted,
+		"ok.go":                "package calc\n",
+		"bad/bad.go":           "package bad\n\nfunc {\n",
+		"vendor/x/x.go":        "package x\nfunc X(){}\n",
+		"testdata/input/in.go": "package in\nfunc In(){}\n",
+	})
+
+	out, err := runLint(t, map[string]any{"path": dir, "linters": "gofmt"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "ran", linterStatus(out, "gofmt"))
+	testutils.AssertEqual(t, lint.Counts{Total: 2, Errors: 1, Warnings: 1, Fixable: 1}, out.Counts)
+	testutils.AssertEqual(t, "bad/bad.go", out.Diagnostics[0].File)
+	testutils.AssertEqual(t, "syntax", out.Diagnostics[0].Rule)
+	testutils.AssertEqual(t, 3, out.Diagnostics[0].Line)
+	testutils.AssertEqual(t, "calc.go", out.Diagnostics[1].File)
+	testutils.AssertTrue(t, out.Diagnostics[1].Fixable)
+
+	out, err = runLint(t, map[string]any{"path": dir, "linters": "gofmt", "fix": "preview"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(out.Changes))
+	testutils.AssertEqual(t, "calc.go", out.Changes[0].File)
+	testutils.AssertTrue(t, strings.Contains(out.Changes[0].Diff, "+func Add(a, b int) int {"))
+	testutils.AssertFalse(t, out.Applied)
+	data, _ := os.ReadFile(filepath.Join(dir, "calc.go"))
+	testutils.AssertEqual(t, unformatted, string(data))
+
+	out, err = runLint(t, map[string]any{"path": dir, "linters": "gofmt", "target": "calc.go", "fix": "apply"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, out.Applied)
+	testutils.AssertEqual(t, 0, out.Counts.Total)
+	data, _ = os.ReadFile(filepath.Join(dir, "calc.go"))
+	testutils.AssertEqual(t, "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n", string(data))
+}
+
+func TestLint_Ruff(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as ruff")
+	}
+	dir := writeProject(t, map[string]string{
+		"pyproject.toml": "[project]\nname = \"app\"\n",
+		"app.py":         "import os\nprint('hi')\n",
+		"broken.py":      "x = (\n",
+	})
+	writeExecutable(t, filepath.Join(dir, ".venv", "bin", "ruff"), fakeRuff)
+
+	out, err := runLint(t, map[string]any{"path": dir, "fix": "preview"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(out.Linters))
+	testutils.AssertEqual(t, "ran", out.Linters[0].Status)
+	testutils.AssertEqual(t, lint.Counts{Total: 2, Errors: 1, Warnings: 1, Fixable: 1}, out.Counts)
+	testutils.AssertEqual(t, "F401", out.Diagnostics[0].Rule)
+	testutils.AssertEqual(t, "app.py", out.Diagnostics[0].File)
+	testutils.AssertEqual(t, "error", out.Diagnostics[1].Severity)
+	testutils.AssertEqual(t, 1, len(out.Changes))
+	testutils.AssertEqual(t, "--- a/app.py\n+++ b/app.py\n@@ -1,2 +1 @@\n-import os\n print('hi')\n", out.Changes[0].Diff)
+}
+
+func TestLint_JavaScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as eslint and prettier")
+	}
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+	original := "var x = f()\nconsole.log(x)\n"
+	dir := writeProject(t, map[string]string{
+		"package.json":     `{"name": "web"}`,
+		"eslint.config.js": "export default [];\n",
+		"src/app.js":       original,
+	})
+	writeExecutable(t, filepath.Join(dir, "node_modules", ".bin", "eslint"), fakeESLint)
+	writeExecutable(t, filepath.Join(dir, "node_modules", ".bin", "prettier"), fakePrettier)
+
+	out, err := runLint(t, map[string]any{"path": dir, "target": "src", "fix": "preview"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "ran", linterStatus(out, "eslint"))
+	testutils.AssertEqual(t, "ran", linterStatus(out, "prettier"))
+	testutils.AssertEqual(t, lint.Counts{Total: 4, Errors: 2, Warnings: 2, Fixable: 2}, out.Counts)
+	testutils.AssertEqual(t, "no-var", out.Diagnostics[1].Rule)
+	testutils.AssertEqual(t, "src/bad.js", out.Diagnostics[3].File)
+	testutils.AssertEqual(t, 3, out.Diagnostics[3].Line)
+
+	// prettier formats eslint's fix
+	testutils.AssertEqual(t, 1, len(out.Changes))
+	testutils.AssertTrue(t, reflect.DeepEqual([]string{"eslint", "prettier"}, out.Changes[0].Linters))
+	testutils.AssertTrue(t, strings.Contains(out.Changes[0].Diff, "+let x = f();\n+console.log(x);\n"))
+	data, _ := os.ReadFile(filepath.Join(dir, "src", "app.js"))
+	testutils.AssertEqual(t, original, string(data))
+}
+
+func TestLint_Detection(t *testing.T) {
+	dir := writeProject(t, map[string]string{"package.json": `{"name": "web"}`, "README.md": ""})
+
+	out, err := runLint(t, map[string]any{"path": dir})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(out.Linters))
+	testutils.AssertEqual(t, "not_installed", linterStatus(out, "eslint"))
+	testutils.AssertEqual(t, "not_installed", linterStatus(out, "prettier"))
+	testutils.AssertEqual(t, 0, len(out.Diagnostics))
+
+	_, err = runLint(t, map[string]any{"path": t.TempDir()})
+	testutils.AssertErrorContains(t, err, "no Go, Python or JavaScript project")
+}
+
+func TestLint_Errors(t *testing.T) {
+	dir := writeProject(t, map[string]string{"go.mod": "module example.com/calc\n", "calc.go": "package calc\n"})
+
+	_, err := runLint(t, map[string]any{"path": dir, "target": "--fix"})
+	testutils.AssertErrorContains(t, err, "cannot start with '-'")
+
+	_, err = runLint(t, map[string]any{"path": dir, "target": "../other"})
+	testutils.AssertErrorContains(t, err, "must be inside")
+
+	_, err = runLint(t, map[string]any{"path": dir, "linters": "gofmt,pylint"})
+	testutils.AssertErrorContains(t, err, "unknown linter")
+
+	_, err = runLint(t, map[string]any{"path": dir, "fix": "yes"})
+	testutils.AssertErrorContains(t, err, "invalid fix")
+
+	_, err = runLint(t, map[string]any{"path": dir, "max_diagnostics": float64(0)})
+	testutils.AssertErrorContains(t, err, "max_diagnostics")
+
+	_, err = runLint(t, map[string]any{"path": filepath.Join(dir, "calc.go")})
+	testutils.AssertErrorContains(t, err, "not a directory")
+}
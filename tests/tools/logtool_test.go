@@ -0,0 +1,180 @@
+package tools_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/logtool"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// writeLogFile writes content to a log file in a temporary directory and returns its path
+func writeLogFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	testutils.AssertNoError(t, os.WriteFile(path, content, 0600))
+	return path
+}
+
+// runAnalyseLogs calls the analyse_logs tool and decodes its report
+func runAnalyseLogs(t *testing.T, args map[string]any) (*logtool.Report, error) {
+	t.Helper()
+	result, err := (&logtool.AnalyseLogsTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var report logtool.Report
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+	return &report, nil
+}
+
+const jsonLog = `{"time":"2026-01-05T09:00:10Z","level":"info","msg":"server started on :8080"}
+{"time":"2026-01-05T09:01:00Z","level":"error","msg":"query failed: timeout after 30s","request_id":"a1b2"}
+{"time":"2026-01-05T09:02:30Z","level":"error","msg":"query failed: timeout after 45s"}
+{"time":"2026-01-05T09:20:00Z","level":"warn","msg":"slow request to 10.0.0.12:5432"}
+{"time":"2026-01-05T10:15:00Z","level":50,"msg":"user 4f1c9a2e-8b7d-4e6f-9a1b-2c3d4e5f6a7b not found"}
+not json at all
+`
+
+func TestAnalyseLogs_JSON(t *testing.T) {
+	path := writeLogFile(t, "app.log", []byte(jsonLog))
+
+	report, err := runAnalyseLogs(t, map[string]any{"path": path})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "json", report.Format)
+	testutils.AssertEqual(t, 6, report.Lines)
+	testutils.AssertEqual(t, 6, report.Entries)
+	testutils.AssertEqual(t, 1, report.Unparsed)
+	testutils.AssertEqual(t, 3, report.Levels["error"])
+	testutils.AssertEqual(t, 1, report.Levels["unknown"])
+	testutils.AssertEqual(t, "2026-01-05T09:00:10Z", report.FirstTime)
+	testutils.AssertEqual(t, "2026-01-05T10:15:00Z", report.LastTime)
+
+	// Both timeouts share a signature
+	testutils.AssertEqual(t, 2, report.DistinctSignatures)
+	testutils.AssertEqual(t, "query failed: timeout after <n>s", report.Signatures[0].Signature)
+	testutils.AssertEqual(t, 2, report.Signatures[0].Count)
+	testutils.AssertEqual(t, "2026-01-05T09:01:00Z", report.Signatures[0].FirstSeen)
+	testutils.AssertEqual(t, "2026-01-05T09:02:30Z", report.Signatures[0].LastSeen)
+	testutils.AssertEqual(t, "query failed: timeout after 30s", report.Signatures[0].Example)
+	testutils.AssertEqual(t, "user <uuid> not found", report.Signatures[1].Signature)
+
+	// A span of 75 minutes fits 24 five-minute buckets
+	testutils.AssertEqual(t, 16, len(report.Timeline))
+	testutils.AssertEqual(t, "2026-01-05T09:00:00Z", report.Timeline[0].Start)
+	testutils.AssertEqual(t, 2, report.Timeline[0].Errors)
+	testutils.AssertEqual(t, 3, report.Timeline[0].Entries)
+}
+
+func TestAnalyseLogs_Filters(t *testing.T) {
+	path := writeLogFile(t, "app.log", []byte(jsonLog))
+
+	report, err := runAnalyseLogs(t, map[string]any{
+		"path":            path,
+		"since":           "2026-01-05T09:01:30Z",
+		"until":           "2026-01-05T10:00:00Z",
+		"min_level":       "warn",
+		"signature_level": "warn",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, report.Matched)
+	testutils.AssertEqual(t, 1, report.Levels["warn"])
+	testutils.AssertEqual(t, "slow request to <ip>", report.Signatures[1].Signature)
+
+	report, err = runAnalyseLogs(t, map[string]any{"path": path, "include": "query", "exclude": "45s"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, report.Matched)
+}
+
+func TestAnalyseLogs_Formats(t *testing.T) {
+	tests := []struct {
+		name, content, format string
+		errors, entries       int
+	}{
+		{
+			name:    "logfmt",
+			content: "ts=2026-01-05T09:00:00Z level=info msg=\"started\"\nts=2026-01-05T09:00:01Z level=error msg=\"dial tcp: connection refused\" attempt=3\n",
+			format:  "logfmt", errors: 1, entries: 2,
+		},
+		{
+			name:    "syslog",
+			content: "<11>Jan  5 09:00:00 web1 sshd[123]: error: PAM authentication failed\nJan  5 09:00:02 web1 cron[456]: (root) CMD (run-parts)\n",
+			format:  "syslog", errors: 1, entries: 2,
+		},
+		{
+			name:    "access",
+			content: "10.0.0.1 - - [05/Jan/2026:09:00:00 +0000] \"GET /api/users/42 HTTP/1.1\" 200 512 \"-\" \"curl\"\n10.0.0.2 - - [05/Jan/2026:09:00:01 +0000] \"POST /api/users/7?x=1 HTTP/1.1\" 502 0 \"-\" \"curl\"\n",
+			format:  "access", errors: 1, entries: 2,
+		},
+		{
+			name:    "plain with stack trace",
+			content: "2026-01-05 09:00:00,123 ERROR [main] Failed to connect\n    at com.example.Db.connect(Db.java:42)\nCaused by: java.net.ConnectException\n2026-01-05 09:00:05,000 INFO [main] Retrying\n",
+			format:  "plain", errors: 1, entries: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := runAnalyseLogs(t, map[string]any{"path": writeLogFile(t, "test.log", []byte(tt.content))})
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tt.format, report.Format)
+			testutils.AssertEqual(t, tt.entries, report.Entries)
+			testutils.AssertEqual(t, tt.errors, report.Levels["error"]+report.Levels["fatal"])
+			testutils.AssertTrue(t, report.FirstTime != "")
+		})
+	}
+}
+
+func TestAnalyseLogs_AccessSummary(t *testing.T) {
+	var log strings.Builder
+	for i := range 5 {
+		status := "200"
+		if i%2 == 1 {
+			status = "500"
+		}
+		log.WriteString("10.0.0.1 - - [05/Jan/2026:09:00:0" + string(rune('0'+i)) + " +0000] \"GET /orders/" + string(rune('0'+i)) + " HTTP/1.1\" " + status + " 100\n")
+	}
+
+	// Compressed logs are read directly
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write([]byte(log.String()))
+	testutils.AssertNoError(t, gz.Close())
+	path := writeLogFile(t, "access.log.gz", compressed.Bytes())
+
+	report, err := runAnalyseLogs(t, map[string]any{"path": path})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "access", report.Format)
+	testutils.AssertEqual(t, 3, report.Access.Statuses["200"])
+	testutils.AssertEqual(t, 2, report.Access.Statuses["500"])
+	testutils.AssertEqual(t, int64(500), report.Access.ResponseBytes)
+	testutils.AssertEqual(t, "/orders/<n>", report.Access.TopPaths[0].Path)
+	testutils.AssertEqual(t, 2, report.Access.TopPaths[0].Errors)
+	testutils.AssertEqual(t, "GET /orders/<n> <n>", report.Signatures[0].Signature)
+}
+
+func TestAnalyseLogs_Errors(t *testing.T) {
+	path := writeLogFile(t, "app.log", []byte(jsonLog))
+
+	_, err := runAnalyseLogs(t, map[string]any{"path": "app.log"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	_, err = runAnalyseLogs(t, map[string]any{"path": path, "since": "yesterday"})
+	testutils.AssertErrorContains(t, err, "invalid 'since' parameter")
+
+	_, err = runAnalyseLogs(t, map[string]any{"path": path, "since": "2026-01-06", "until": "2026-01-05"})
+	testutils.AssertErrorContains(t, err, "until is before since")
+
+	_, err = runAnalyseLogs(t, map[string]any{"path": path, "include": "("})
+	testutils.AssertErrorContains(t, err, "invalid 'include' regex")
+
+	_, err = runAnalyseLogs(t, map[string]any{"path": path, "min_level": "loud"})
+	testutils.AssertErrorContains(t, err, "invalid 'min_level' parameter")
+}
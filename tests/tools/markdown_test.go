@@ -0,0 +1,223 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/markdown"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runMarkdown calls the markdown tool and decodes its result into out
+func runMarkdown(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&markdown.MarkdownTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+// readText reads a file written by a test
+func readText(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	testutils.AssertNoError(t, err)
+	return string(data)
+}
+
+const markdownGuide = `---
+title: Guide
+---
+# Guide
+
+Read https://example.com/docs, <https://example.com> or [the site](https://example.com).
+
+## Getting ` + "`Started`" + `
+
+See [setup](#setup-steps), [nowhere](#nowhere), [install](other.md#install), [missing heading](other.md#missing), [gone](gone.md), [readme](/README.md) and [Custom](other.md#custom).
+
+#### Too Deep
+
+` + "```bash" + `
+# Not a heading, and https://example.com is code
+` + "```" + `
+
+Setup Steps {#setup-steps}
+--------------------------
+
+This line is long enough to go past a limit of sixty characters when it is checked.
+
+# Second Title
+`
+
+// markdownProject writes a repository with a docs directory to lint
+func markdownProject(t *testing.T) string {
+	return writeProject(t, map[string]string{
+		".git/HEAD":           "ref: refs/heads/main\n",
+		"README.md":           "# Project\n",
+		"docs/guide.md":       markdownGuide,
+		"docs/other.md":       "# Other\n\n## Install\n\n<a id=\"custom\"></a>\n",
+		"docs/.drafts/old.md": "# Old\n\n[gone](gone.md)\n",
+	})
+}
+
+func TestMarkdown_Lint(t *testing.T) {
+	dir := markdownProject(t)
+	var result markdown.LintResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "lint", "path": filepath.Join(dir, "docs")}, &result))
+	testutils.AssertEqual(t, 2, result.Files)
+
+	var got []string
+	for _, issue := range result.Issues {
+		testutils.AssertEqual(t, "guide.md", issue.File)
+		got = append(got, issue.Rule)
+	}
+	testutils.AssertEqual(t, "bare-url broken-anchor broken-anchor broken-link heading-increment multiple-h1", strings.Join(got, " "))
+	testutils.AssertEqual(t, 6, result.Issues[0].Line)
+	testutils.AssertEqual(t, 6, result.Issues[0].Column)
+	testutils.AssertTrue(t, strings.Contains(result.Issues[0].Message, "https://example.com/docs;"))
+	testutils.AssertTrue(t, strings.Contains(result.Issues[1].Message, `"#nowhere"`))
+	testutils.AssertTrue(t, strings.Contains(result.Issues[2].Message, `"#missing" in other.md`))
+	testutils.AssertTrue(t, strings.Contains(result.Issues[3].Message, "gone.md"))
+	testutils.AssertEqual(t, 12, result.Issues[4].Line)
+	testutils.AssertEqual(t, "Heading level 4 follows level 2; use level 3", result.Issues[4].Message)
+	_, checked := result.Counts["line-length"]
+	testutils.AssertFalse(t, checked)
+
+	// Line length is checked once a limit is given
+	result = markdown.LintResult{}
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{
+		"function":        "lint",
+		"path":            filepath.Join(dir, "docs", "guide.md"),
+		"rules":           "line-length",
+		"max_line_length": float64(60),
+	}, &result))
+	testutils.AssertEqual(t, 3, result.Counts["line-length"])
+	testutils.AssertEqual(t, 6, result.Issues[0].Line)
+	testutils.AssertEqual(t, 61, result.Issues[0].Column)
+	testutils.AssertEqual(t, 10, result.Issues[1].Line)
+	testutils.AssertEqual(t, 21, result.Issues[2].Line)
+
+	// Issues past max_issues are counted but not listed
+	result = markdown.LintResult{}
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "lint", "path": filepath.Join(dir, "docs"), "max_issues": float64(2)}, &result))
+	testutils.AssertEqual(t, 2, len(result.Issues))
+	testutils.AssertEqual(t, 2, result.Counts["broken-anchor"])
+	testutils.AssertTrue(t, result.Truncated)
+}
+
+func TestMarkdown_TOC(t *testing.T) {
+	dir := markdownProject(t)
+	path := filepath.Join(dir, "docs", "guide.md")
+
+	var preview markdown.TOCResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "toc", "path": path, "preview": true}, &preview))
+	testutils.AssertEqual(t, "inserted", preview.Placement)
+	testutils.AssertEqual(t, "- [Getting `Started`](#getting-started)\n- [Setup Steps](#setup-steps)", preview.TOC)
+	testutils.AssertTrue(t, preview.Changed)
+	testutils.AssertFalse(t, preview.Written)
+	testutils.AssertTrue(t, strings.Contains(preview.Diff, "+## Table of Contents"))
+	testutils.AssertEqual(t, markdownGuide, readText(t, path))
+
+	// Deeper headings are nested under the nearest shallower one
+	var written markdown.TOCResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "toc", "path": path, "max_level": float64(4)}, &written))
+	testutils.AssertTrue(t, written.Written)
+	testutils.AssertEqual(t, 3, written.Headings)
+	want := "## Table of Contents\n\n- [Getting `Started`](#getting-started)\n  - [Too Deep](#too-deep)\n- [Setup Steps](#setup-steps)\n\n## Getting `Started`\n"
+	testutils.AssertTrue(t, strings.Contains(readText(t, path), want))
+
+	// The table of contents is then found under its heading and left alone when up to date
+	var again markdown.TOCResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "toc", "path": path, "max_level": float64(4)}, &again))
+	testutils.AssertEqual(t, "heading", again.Placement)
+	testutils.AssertFalse(t, again.Changed)
+	testutils.AssertFalse(t, again.Written)
+
+	// Between comments, the old list is replaced
+	markers := filepath.Join(dir, "markers.md")
+	testutils.AssertNoError(t, os.WriteFile(markers, []byte("# Title\r\n\r\n<!-- toc -->\r\n- [Old](#old)\r\n<!-- tocstop -->\r\n\r\n## New\r\n"), 0600))
+	var replaced markdown.TOCResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "toc", "path": markers}, &replaced))
+	testutils.AssertEqual(t, "markers", replaced.Placement)
+	testutils.AssertEqual(t, "# Title\r\n\r\n<!-- toc -->\r\n\r\n- [New](#new)\r\n\r\n<!-- tocstop -->\r\n\r\n## New\r\n", readText(t, markers))
+}
+
+func TestMarkdown_FrontMatter(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"posts/one.md":     "---\ntitle: One\ndate: 2024-05-01\ntags: [go]\n---\n# One\n",
+		"posts/two.md":     "+++\ntitle = \"Two\"\ndate = 2024-05-02\n+++\n# Two\n",
+		"posts/three.md":   "---\ntitle: Three\ndate: May\n---\n# Three\n",
+		"posts/four.md":    "---\ntitle: [Four\n---\n# Four\n",
+		"posts/five.md":    "# Five has no front matter\n",
+		"schema/post.json": `{"type": "object", "required": ["title", "date"], "properties": {"title": {"type": "string"}, "date": {"type": "string", "pattern": "^\\d{4}-\\d{2}-\\d{2}$"}}}`,
+	})
+	posts := filepath.Join(dir, "posts")
+
+	var plain markdown.FrontMatterResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "front_matter", "path": posts}, &plain))
+	testutils.AssertEqual(t, 5, plain.Files)
+	testutils.AssertEqual(t, 3, plain.Valid)
+	testutils.AssertEqual(t, "five.md", plain.Issues[0].File)
+	testutils.AssertEqual(t, "No front matter", plain.Issues[0].Message)
+	testutils.AssertEqual(t, "four.md", plain.Issues[1].File)
+	testutils.AssertEqual(t, 2, plain.Issues[1].Line)
+	testutils.AssertTrue(t, strings.HasPrefix(plain.Issues[1].Message, "invalid YAML"))
+	testutils.AssertEqual(t, 3, plain.Fields["title"])
+	testutils.AssertEqual(t, 1, plain.Fields["tags"])
+
+	// Dates are checked as written, in YAML and TOML
+	var checked markdown.FrontMatterResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{"function": "front_matter", "path": posts, "schema_path": filepath.Join(dir, "schema", "post.json")}, &checked))
+	testutils.AssertEqual(t, 2, checked.Valid)
+	testutils.AssertEqual(t, 3, checked.Invalid)
+	testutils.AssertTrue(t, strings.Contains(checked.Issues[0].Message, "missing properties"))
+	testutils.AssertEqual(t, "three.md", checked.Issues[2].File)
+	testutils.AssertTrue(t, strings.Contains(checked.Issues[2].Message, `"May" does not match`))
+
+	// Schemas can be written in YAML
+	var yamlSchema markdown.FrontMatterResult
+	testutils.AssertNoError(t, runMarkdown(t, map[string]any{
+		"function": "front_matter",
+		"path":     filepath.Join(posts, "one.md"),
+		"schema":   "type: object\nproperties:\n  tags:\n    type: array\n    minItems: 2\n",
+	}, &yamlSchema))
+	testutils.AssertEqual(t, 1, yamlSchema.Invalid)
+	testutils.AssertTrue(t, strings.Contains(yamlSchema.Issues[0].Message, "minItems"))
+}
+
+func TestMarkdown_Errors(t *testing.T) {
+	dir := markdownProject(t)
+	guide := filepath.Join(dir, "docs", "guide.md")
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"unknown function", map[string]any{"function": "format", "path": guide}, "function must be one of"},
+		{"no path", map[string]any{"function": "lint"}, "missing required parameter: path"},
+		{"relative path", map[string]any{"function": "lint", "path": "docs"}, "must be an absolute path"},
+		{"missing path", map[string]any{"function": "lint", "path": filepath.Join(dir, "missing.md")}, "cannot access path"},
+		{"unknown rule", map[string]any{"function": "lint", "path": guide, "rules": "spelling"}, `unknown rules entry "spelling"`},
+		{"toc of a directory", map[string]any{"function": "toc", "path": dir}, "is a directory"},
+		{"bad level", map[string]any{"function": "toc", "path": guide, "max_level": float64(7)}, "max_level must be a heading level"},
+		{"levels reversed", map[string]any{"function": "toc", "path": guide, "min_level": float64(3), "max_level": float64(2)}, "must not be more than max_level"},
+		{"no headings", map[string]any{"function": "toc", "path": filepath.Join(dir, "README.md")}, "no headings of level 2 to 3"},
+		{"invalid schema", map[string]any{"function": "front_matter", "path": guide, "schema": `{"type": 5}`}, "invalid schema"},
+		{"both schemas", map[string]any{"function": "front_matter", "path": guide, "schema": "{}", "schema_path": guide}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out map[string]any
+			testutils.AssertErrorContains(t, runMarkdown(t, tt.args, &out), tt.want)
+		})
+	}
+}
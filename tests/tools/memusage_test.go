@@ -0,0 +1,97 @@
+package tools_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/memusage"
+	"github.com/sammcj/mcp-devtools/internal/telemetry"
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools/excel"
+	"github.com/sammcj/mcp-devtools/internal/tools/utilities/diagnostics"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+// allocationSink keeps test allocations reachable so they are not optimised away
+var allocationSink []byte
+
+func TestMemusage_ExcelReadsOfLargeWorkbooksAreMemoryIntensive(t *testing.T) {
+	dir := t.TempDir()
+	large := filepath.Join(dir, "large.xlsx")
+	small := filepath.Join(dir, "small.xlsx")
+	testutils.AssertNoError(t, os.WriteFile(large, make([]byte, 6<<20), 0600))
+	testutils.AssertNoError(t, os.WriteFile(small, make([]byte, 1024), 0600))
+
+	tool := &excel.ExcelTool{}
+	testutils.AssertTrue(t, tool.IsMemoryIntensive(map[string]any{"function": "read_all_data", "filepath": large}))
+	testutils.AssertTrue(t, tool.IsMemoryIntensive(map[string]any{"function": "read_data", "filepath": large}))
+	testutils.AssertFalse(t, tool.IsMemoryIntensive(map[string]any{"function": "read_data", "filepath": small}))
+	testutils.AssertFalse(t, tool.IsMemoryIntensive(map[string]any{"function": "write_data", "filepath": large}))
+	testutils.AssertFalse(t, tool.IsMemoryIntensive(map[string]any{"function": "read_data", "filepath": filepath.Join(dir, "missing.xlsx")}))
+}
+
+func TestMemusage_RefusesMemoryIntensiveCallsNearLimit(t *testing.T) {
+	dir := t.TempDir()
+	workbook := filepath.Join(dir, "large.xlsx")
+	testutils.AssertNoError(t, os.WriteFile(workbook, make([]byte, 6<<20), 0600))
+	heavy := map[string]any{"function": "read_all_data", "filepath": workbook}
+	light := map[string]any{"function": "get_workbook_metadata", "filepath": workbook}
+
+	// A limit four times the memory in use, throttled at 10%, is already exceeded without
+	// pressuring the garbage collector
+	previous := debug.SetMemoryLimit(int64(memusage.Read().InUseBytes) * 4)
+	defer debug.SetMemoryLimit(previous)
+	t.Setenv(memusage.ThrottleEnvVar, "10")
+
+	rejected := memusage.Read().RejectedCalls
+	err := toolcall.CheckMemory(&excel.ExcelTool{}, heavy)
+	testutils.AssertTrue(t, errors.Is(err, memusage.ErrLowMemory))
+	testutils.AssertErrorContains(t, err, "memory limit")
+	testutils.AssertEqual(t, "memory", telemetry.CategoriseToolError(err))
+	testutils.AssertNoError(t, toolcall.CheckMemory(&excel.ExcelTool{}, light))
+
+	stats := memusage.Read()
+	testutils.AssertTrue(t, stats.Throttling)
+	testutils.AssertEqual(t, rejected+1, stats.RejectedCalls)
+	testutils.AssertTrue(t, stats.PeakInUseBytes >= stats.InUseBytes)
+
+	// get_diagnostics reports the figures and why calls are being refused
+	result, err := (&diagnostics.DiagnosticsTool{}).Execute(diagnosticsSession("memusage-session"), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	response := parseDiagnostics(t, result)
+	testutils.AssertTrue(t, response.Memory.Throttling)
+	testutils.AssertTrue(t, response.Memory.LimitBytes > 0)
+	testutils.AssertTrue(t, strings.Contains(strings.Join(response.Notes, "\n"), "close to its memory limit"))
+
+	// Throttling can be turned off
+	t.Setenv(memusage.ThrottleEnvVar, "0")
+	testutils.AssertNoError(t, toolcall.CheckMemory(&excel.ExcelTool{}, heavy))
+}
+
+func TestMemusage_LogsCallsOverBudget(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	t.Setenv(memusage.BudgetEnvVar, "1")
+
+	overBudget := memusage.Read().OverBudgetCalls
+	start := memusage.Allocated()
+	allocationSink = make([]byte, 1024)
+	memusage.CheckBudget(logger, "small_tool", start)
+	testutils.AssertEqual(t, 0, logs.Len())
+
+	start = memusage.Allocated()
+	allocationSink = make([]byte, 4<<20)
+	memusage.CheckBudget(logger, "large_tool", start)
+	testutils.AssertTrue(t, strings.Contains(logs.String(), "exceeded its memory budget"))
+	testutils.AssertTrue(t, strings.Contains(logs.String(), "tool=large_tool"))
+	testutils.AssertEqual(t, overBudget+1, memusage.Read().OverBudgetCalls)
+	allocationSink = nil
+}
@@ -0,0 +1,239 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/tools/netdiag"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runNetDiag calls the netdiag tool and decodes its result into out
+func runNetDiag(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&netdiag.NetDiagTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+func TestNetDiag_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var out netdiag.TLSResult
+	testutils.AssertNoError(t, runNetDiag(t, map[string]any{"function": "tls", "host": server.URL}, &out))
+	testutils.AssertEqual(t, "127.0.0.1", out.Host)
+	testutils.AssertEqual(t, "127.0.0.1", out.ServerName)
+	testutils.AssertEqual(t, "TLS 1.3", out.Version)
+	testutils.AssertEqual(t, 1, len(out.Chain))
+	testutils.AssertTrue(t, out.Chain[0].DaysRemaining > 0)
+	testutils.AssertTrue(t, len(out.Chain[0].SHA256) == 64)
+	testutils.AssertFalse(t, out.Verified)
+	testutils.AssertTrue(t, out.VerifyError != "")
+	testutils.AssertEqual(t, 1, len(out.Warnings))
+	testutils.AssertEqual(t, "The certificate is self-signed.", out.Warnings[0])
+
+	// The test certificate is issued for example.com, so another name fails the hostname check
+	var named netdiag.TLSResult
+	testutils.AssertNoError(t, runNetDiag(t, map[string]any{"function": "tls", "host": server.URL, "server_name": "api.test"}, &named))
+	testutils.AssertEqual(t, "api.test", named.ServerName)
+	testutils.AssertEqual(t, "The certificate is not valid for api.test.", named.Warnings[0])
+}
+
+func TestNetDiag_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutils.AssertNoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	testutils.AssertNoError(t, listener.Close())
+
+	var out netdiag.TCPResult
+	testutils.AssertNoError(t, runNetDiag(t, map[string]any{"function": "tcp", "host": "127.0.0.1", "port": float64(port)}, &out))
+	testutils.AssertFalse(t, out.Reachable)
+	testutils.AssertEqual(t, 1, len(out.Attempts))
+	testutils.AssertTrue(t, out.Attempts[0].Error != "")
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	testutils.AssertNoError(t, err)
+	defer func() { _ = listener.Close() }()
+	address := listener.Addr().String()
+
+	var open netdiag.TCPResult
+	testutils.AssertNoError(t, runNetDiag(t, map[string]any{"function": "tcp", "host": address}, &open))
+	testutils.AssertTrue(t, open.Reachable)
+	testutils.AssertEqual(t, netdiag.TCPAttempt{Address: address, Connected: true, Duration: open.Attempts[0].Duration}, open.Attempts[0])
+}
+
+func TestNetDiag_NetworkPolicy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	parsed, err := url.Parse(server.URL)
+	testutils.AssertNoError(t, err)
+	port, err := strconv.Atoi(parsed.Port())
+	testutils.AssertNoError(t, err)
+
+	withNetworkPolicy(t, nil, security.AccessControl{BlockPrivateNetworks: true, DenyDomains: []string{"blocked.example.com"}})
+	var out netdiag.TCPResult
+	err = runNetDiag(t, map[string]any{"function": "tls", "host": server.URL}, &out)
+	testutils.AssertErrorContains(t, err, "private networks are blocked")
+
+	err = runNetDiag(t, map[string]any{"function": "dns", "host": "blocked.example.com"}, &out)
+	testutils.AssertErrorContains(t, err, "domain deny list")
+
+	err = runNetDiag(t, map[string]any{"function": "dns", "host": "example.com", "resolver": "10.0.0.53"}, &out)
+	testutils.AssertErrorContains(t, err, "private networks are blocked")
+
+	// Host names are checked against the addresses they resolve to
+	testutils.AssertNoError(t, runNetDiag(t, map[string]any{"function": "tcp", "host": "localhost", "port": float64(port)}, &out))
+	testutils.AssertFalse(t, out.Reachable)
+	testutils.AssertTrue(t, len(out.Attempts) > 0)
+	for _, attempt := range out.Attempts {
+		testutils.AssertTrue(t, strings.Contains(attempt.Error, "private networks are blocked"))
+	}
+}
+
+func TestNetDiag_Errors(t *testing.T) {
+	var out map[string]any
+	err := runNetDiag(t, map[string]any{"function": "ping", "host": "example.com"}, &out)
+	testutils.AssertErrorContains(t, err, "function must be one of")
+
+	err = runNetDiag(t, map[string]any{"function": "dns", "host": "example.com; rm -rf /"}, &out)
+	testutils.AssertErrorContains(t, err, "invalid host")
+
+	err = runNetDiag(t, map[string]any{"function": "dns", "host": "example.com", "record_types": "A,SOA"}, &out)
+	testutils.AssertErrorContains(t, err, "unknown record_types entry")
+
+	err = runNetDiag(t, map[string]any{"function": "tcp", "host": "example.com"}, &out)
+	testutils.AssertErrorContains(t, err, "port is required")
+
+	err = runNetDiag(t, map[string]any{"function": "tcp", "host": "example.com", "port": float64(70000)}, &out)
+	testutils.AssertErrorContains(t, err, "port must be a whole number")
+
+	err = runNetDiag(t, map[string]any{"function": "tls", "host": "example.com:0"}, &out)
+	testutils.AssertErrorContains(t, err, "invalid port")
+}
+
+// newRDAPServers starts a bootstrap server listing the .test TLD and 198.51.100.0/24 and an RDAP
+// server for them, and returns the RDAP server's request count
+func newRDAPServers(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/rdap/domain/example.test":
+			_, _ = w.Write([]byte(`{
+				"ldhName": "EXAMPLE.TEST", "handle": "D123", "status": ["active"],
+				"events": [{"eventAction": "registration", "eventDate": "2020-01-02T00:00:00Z"}, {"eventAction": "expiration", "eventDate": "2099-01-02T00:00:00Z"}],
+				"nameservers": [{"ldhName": "NS1.EXAMPLE.TEST"}],
+				"secureDNS": {"delegationSigned": true},
+				"entities": [{"roles": ["registrar"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar"]]],
+					"entities": [{"roles": ["abuse"], "vcardArray": ["vcard", [["email", {}, "text", "abuse@registrar.test"]]]}]}]
+			}`))
+		case "/rdap/ip/198.51.100.7":
+			_, _ = w.Write([]byte(`{
+				"handle": "NET-198-51-100-0-1", "name": "DOC-NET", "type": "ASSIGNED", "country": "AU",
+				"startAddress": "198.51.100.0", "endAddress": "198.51.100.255",
+				"cidr0_cidrs": [{"v4prefix": "198.51.100.0", "length": 24}],
+				"entities": [{"roles": ["registrant"], "vcardArray": ["vcard", [["fn", {}, "text", "Documentation Networks"], ["adr", {"cc": "AU"}, "text", ""]]]}]
+			}`))
+		case "/rdap/domain/limited.test":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(registry.Close)
+	bootstrap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dns.json":
+			_, _ = w.Write([]byte(`{"services": [[["test"], ["` + registry.URL + `/rdap"]]]}`))
+		case "/ipv4.json":
+			_, _ = w.Write([]byte(`{"services": [[["198.51.100.0/24"], ["` + registry.URL + `/rdap/"]]]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(bootstrap.Close)
+	t.Setenv(netdiag.BootstrapURLEnvVar, bootstrap.URL)
+	return &requests
+}
+
+func TestNetDiag_Whois(t *testing.T) {
+	requests := newRDAPServers(t)
+	t.Setenv(netdiag.AbuseIPDBKeyEnvVar, "")
+	cache := &sync.Map{}
+	whois := func(args map[string]any) (netdiag.WhoisResult, error) {
+		var out netdiag.WhoisResult
+		args["function"] = "whois"
+		result, err := (&netdiag.NetDiagTool{}).Execute(context.Background(), quietLogger(), cache, args)
+		if err != nil {
+			return out, err
+		}
+		testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+		return out, nil
+	}
+
+	// A subdomain has no record of its own, so its registered domain is shown
+	out, err := whois(map[string]any{"host": "https://www.example.test/login"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "rdap", out.Protocol)
+	testutils.AssertEqual(t, "example.test", out.Name)
+	testutils.AssertEqual(t, "Example Registrar", out.Registrar)
+	testutils.AssertEqual(t, "abuse@registrar.test", out.AbuseEmail)
+	testutils.AssertEqual(t, "2099-01-02T00:00:00Z", out.Expires)
+	testutils.AssertTrue(t, *out.DaysUntilExpiry > 365)
+	testutils.AssertEqual(t, "ns1.example.test", out.Nameservers[0])
+	testutils.AssertTrue(t, *out.DNSSEC)
+	testutils.AssertTrue(t, strings.Contains(out.Note, "parent domain example.test"))
+	testutils.AssertFalse(t, out.Cached)
+	testutils.AssertEqual(t, int32(2), requests.Load())
+
+	// Repeat lookups are served from the cache
+	out, err = whois(map[string]any{"host": "www.example.test"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, out.Cached)
+	testutils.AssertEqual(t, int32(2), requests.Load())
+
+	out, err = whois(map[string]any{"host": "198.51.100.7", "reputation": true})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "ip", out.Kind)
+	testutils.AssertEqual(t, "DOC-NET", out.Name)
+	testutils.AssertEqual(t, "198.51.100.0 - 198.51.100.255", out.Network.Range)
+	testutils.AssertEqual(t, "198.51.100.0/24", out.Network.CIDRs[0])
+	testutils.AssertEqual(t, "Documentation Networks", out.Contacts[0].Name)
+	testutils.AssertEqual(t, "AU", out.Contacts[0].Country)
+	testutils.AssertTrue(t, out.Reputation == nil)
+	testutils.AssertTrue(t, strings.Contains(out.Note, netdiag.AbuseIPDBKeyEnvVar))
+
+	_, err = whois(map[string]any{"host": "10.1.2.3"})
+	testutils.AssertErrorContains(t, err, "private or reserved address")
+}
+
+func TestNetDiag_WhoisRateLimit(t *testing.T) {
+	requests := newRDAPServers(t)
+
+	var out netdiag.WhoisResult
+	err := runNetDiag(t, map[string]any{"function": "whois", "host": "limited.test"}, &out)
+	testutils.AssertErrorContains(t, err, "rate limiting lookups")
+	testutils.AssertEqual(t, int32(1), requests.Load())
+
+	// The server is not asked again until its Retry-After has passed
+	err = runNetDiag(t, map[string]any{"function": "whois", "host": "limited.test"}, &out)
+	testutils.AssertErrorContains(t, err, "rate limiting lookups")
+	testutils.AssertEqual(t, int32(1), requests.Load())
+}
@@ -0,0 +1,172 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// withNetworkPolicy installs a security manager with the given access controls for the test
+func withNetworkPolicy(t *testing.T, trusted []string, access security.AccessControl) {
+	t.Helper()
+	manager, err := security.NewSecurityManagerWithRules(&security.SecurityRules{
+		Settings:       security.Settings{Enabled: true},
+		TrustedDomains: trusted,
+		AccessControl:  access,
+	})
+	testutils.AssertNoError(t, err)
+
+	previous := security.GlobalSecurityManager
+	security.GlobalSecurityManager = manager
+	t.Cleanup(func() { security.GlobalSecurityManager = previous })
+}
+
+func TestNetworkPolicy_BlocksPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	parsed, err := url.Parse(server.URL)
+	testutils.AssertNoError(t, err)
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxRetries: -1})
+
+	withNetworkPolicy(t, nil, security.AccessControl{})
+	resp, err := client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	_ = resp.Body.Close()
+
+	withNetworkPolicy(t, nil, security.AccessControl{BlockPrivateNetworks: true})
+	_, err = client.Get(server.URL)
+	testutils.AssertErrorContains(t, err, "private networks are blocked")
+
+	// Host names are checked against the addresses they resolve to
+	_, err = client.Get("http://localhost:" + parsed.Port())
+	testutils.AssertErrorContains(t, err, "private networks are blocked")
+
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("169.254.169.254"), "private networks are blocked")
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("10.1.2.3"), "private networks are blocked")
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("fd00::1"), "private networks are blocked")
+	testutils.AssertNoError(t, security.CheckNetworkHost("203.0.113.10"))
+}
+
+func TestNetworkPolicy_AllowlistOnly(t *testing.T) {
+	withNetworkPolicy(t, []string{"example.com", "*.docs.test"}, security.AccessControl{
+		AllowlistOnly: true,
+		DenyDomains:   []string{"blocked.example.com"},
+	})
+
+	testutils.AssertNoError(t, security.CheckNetworkHost("example.com"))
+	testutils.AssertNoError(t, security.CheckNetworkHost("www.example.com"))
+	testutils.AssertNoError(t, security.CheckNetworkHost("api.docs.test"))
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("example.org"), "not in trusted_domains")
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("notexample.com"), "not in trusted_domains")
+
+	// The deny list still applies to subdomains of trusted domains
+	testutils.AssertErrorContains(t, security.CheckNetworkHost("blocked.example.com"), "domain deny list")
+}
+
+func TestNetworkPolicy_ChecksRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	targetURL, err := url.Parse(target.URL)
+	testutils.AssertNoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+targetURL.Port()+"/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	withNetworkPolicy(t, []string{"127.0.0.1"}, security.AccessControl{AllowlistOnly: true})
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxRetries: -1})
+	_, err = client.Get(target.URL)
+	testutils.AssertNoError(t, err)
+
+	_, err = client.Get(server.URL)
+	testutils.AssertErrorContains(t, err, "localhost is not in trusted_domains")
+}
+
+func TestNetworkPolicy_DialTunnel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutils.AssertNoError(t, err)
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("hello"))
+			_ = conn.Close()
+		}
+	}()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	testutils.AssertNoError(t, err)
+	ctx := context.Background()
+
+	withNetworkPolicy(t, []string{"127.0.0.1"}, security.AccessControl{AllowlistOnly: true})
+	conn, err := httpclient.DialTunnel(ctx, listener.Addr().String())
+	testutils.AssertNoError(t, err)
+	greeting, err := io.ReadAll(conn)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "hello", string(greeting))
+	_ = conn.Close()
+
+	_, err = httpclient.DialTunnel(ctx, "localhost:"+port)
+	var policyErr *httpclient.PolicyError
+	testutils.AssertTrue(t, errors.As(err, &policyErr))
+	testutils.AssertErrorContains(t, err, "localhost is not in trusted_domains")
+
+	// Resolved addresses are checked too when connecting directly
+	withNetworkPolicy(t, nil, security.AccessControl{BlockPrivateNetworks: true})
+	_, err = httpclient.DialTunnel(ctx, "localhost:"+port)
+	testutils.AssertTrue(t, errors.As(err, &policyErr))
+	testutils.AssertErrorContains(t, err, "private networks are blocked")
+}
+
+func TestNetworkPolicy_DialTunnelThroughProxy(t *testing.T) {
+	var connected string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		connected = r.Host
+		conn, buffered, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		// The far end's first bytes arrive with the proxy's response
+		_, _ = buffered.WriteString("HTTP/1.1 200 Connection Established\r\n\r\ntunnelled")
+		_ = buffered.Flush()
+		_ = conn.Close()
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	testutils.AssertNoError(t, err)
+	proxyURL.User = url.UserPassword("user", "secret")
+	t.Setenv("HTTPS_PROXY", proxyURL.String())
+	t.Setenv("NO_PROXY", "")
+
+	withNetworkPolicy(t, []string{"example.test"}, security.AccessControl{AllowlistOnly: true})
+	conn, err := httpclient.DialTunnel(context.Background(), "example.test:443")
+	testutils.AssertNoError(t, err)
+	data, err := io.ReadAll(conn)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "tunnelled", string(data))
+	testutils.AssertEqual(t, "example.test:443", connected)
+	_ = conn.Close()
+
+	// The host is checked before the proxy is contacted
+	_, err = httpclient.DialTunnel(context.Background(), "example.org:443")
+	testutils.AssertErrorContains(t, err, "not in trusted_domains")
+}
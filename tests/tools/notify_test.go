@@ -0,0 +1,198 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/notify"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// setupFakeNotifier points the desktop channel at a script that records its last two arguments
+// in a file, returning the file
+func setupFakeNotifier(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake notification commands require a POSIX shell")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notifications")
+	script := filepath.Join(dir, "notifier")
+	testutils.AssertNoError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s|%s' \"$1\" \"$2\" > \""+file+"\"\n"), 0700))
+	t.Setenv(notify.DesktopCommandEnvVar, script)
+	for _, envVar := range []string{notify.ChannelsEnvVar, notify.SlackWebhookEnvVar, notify.TeamsWebhookEnvVar, notify.WebhookEnvVar,
+		notify.TitleTemplateEnvVar, notify.MessageTemplateEnvVar, notify.RateLimitEnvVar} {
+		t.Setenv(envVar, "")
+	}
+	return file
+}
+
+// runNotify calls a notify tool, returning the result decoded from its text
+func runNotify(t *testing.T, tool *notify.NotifyTool, args map[string]any) (notify.Result, error) {
+	t.Helper()
+	var decoded notify.Result
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return decoded, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &decoded))
+	return decoded, nil
+}
+
+func TestNotify_Desktop(t *testing.T) {
+	file := setupFakeNotifier(t)
+
+	result, err := runNotify(t, &notify.NotifyTool{}, map[string]any{
+		"message":  "All 412 tests passed",
+		"status":   "success",
+		"project":  "api",
+		"duration": float64(253),
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "✅ Task complete - api", result.Title)
+	testutils.AssertEqual(t, "All 412 tests passed (took 4m13s)", result.Message)
+	testutils.AssertEqual(t, 1, len(result.Deliveries))
+	testutils.AssertEqual(t, "desktop", result.Deliveries[0].Channel)
+	testutils.AssertTrue(t, result.Deliveries[0].Sent)
+	data, err := os.ReadFile(file)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "✅ Task complete - api|All 412 tests passed (took 4m13s)", string(data))
+
+	// The server can change the templates
+	t.Setenv(notify.TitleTemplateEnvVar, "[{{.Status}}] {{.Title}}")
+	t.Setenv(notify.MessageTemplateEnvVar, "{{.Message}} on {{.Host}}")
+	host, _ := os.Hostname()
+	result, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Build finished", "title": "CI"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "[info] CI", result.Title)
+	testutils.AssertEqual(t, "Build finished on "+host, result.Message)
+
+	t.Setenv(notify.MessageTemplateEnvVar, "{{.Missing}}")
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Build finished"})
+	testutils.AssertErrorContains(t, err, "NOTIFY_MESSAGE_TEMPLATE could not be rendered")
+	t.Setenv(notify.MessageTemplateEnvVar, "")
+
+	result, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": strings.Repeat("a", 3000)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2000, len([]rune(result.Message)))
+	testutils.AssertTrue(t, strings.HasSuffix(result.Message, "…"))
+	testutils.AssertEqual(t, 1, len(result.Warnings))
+}
+
+func TestNotify_Webhooks(t *testing.T) {
+	setupFakeNotifier(t)
+	var mu sync.Mutex
+	bodies := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(notify.SlackWebhookEnvVar, server.URL+"/slack")
+	t.Setenv(notify.TeamsWebhookEnvVar, server.URL+"/teams")
+	t.Setenv(notify.WebhookEnvVar, server.URL+"/generic")
+
+	result, err := runNotify(t, &notify.NotifyTool{}, map[string]any{
+		"message":  "The migration needs a decision",
+		"status":   "attention",
+		"project":  "shop",
+		"channels": "slack, teams,webhook,slack",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 3, len(result.Deliveries))
+	for _, delivery := range result.Deliveries {
+		testutils.AssertTrue(t, delivery.Sent)
+	}
+	testutils.AssertEqual(t, "*🔔 Input needed - shop*\nThe migration needs a decision", bodies["/slack"]["text"].(string))
+	teams, _ := json.Marshal(bodies["/teams"])
+	testutils.AssertTrue(t, strings.Contains(string(teams), `"contentType":"application/vnd.microsoft.card.adaptive"`))
+	testutils.AssertTrue(t, strings.Contains(string(teams), `"text":"The migration needs a decision"`))
+	testutils.AssertEqual(t, "attention", bodies["/generic"]["status"].(string))
+	testutils.AssertEqual(t, "shop", bodies["/generic"]["project"].(string))
+
+	// The server's default channels are used when none are named
+	t.Setenv(notify.ChannelsEnvVar, "webhook")
+	result, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "webhook", result.Deliveries[0].Channel)
+
+	// A failed channel is reported without stopping the others, and without its URL
+	t.Setenv(notify.SlackWebhookEnvVar, server.URL+"/broken")
+	result, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "slack,desktop"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, result.Deliveries[0].Sent)
+	testutils.AssertEqual(t, "HTTP 403: invalid_token", result.Deliveries[0].Error)
+	testutils.AssertTrue(t, result.Deliveries[1].Sent)
+
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "slack"})
+	testutils.AssertErrorContains(t, err, "the notification was not sent: slack: HTTP 403")
+
+	server.Close()
+	t.Setenv(notify.WebhookEnvVar, server.URL+"/secret-token")
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "webhook"})
+	testutils.AssertTrue(t, err != nil)
+	testutils.AssertFalse(t, strings.Contains(err.Error(), "secret-token"))
+}
+
+func TestNotify_RateLimit(t *testing.T) {
+	setupFakeNotifier(t)
+	t.Setenv(notify.RateLimitEnvVar, "2")
+
+	tool := &notify.NotifyTool{}
+	for range 2 {
+		_, err := runNotify(t, tool, map[string]any{"message": "Done"})
+		testutils.AssertNoError(t, err)
+	}
+	_, err := runNotify(t, tool, map[string]any{"message": "Done"})
+	testutils.AssertErrorContains(t, err, "too many notifications, try again in 30s")
+
+	t.Setenv(notify.RateLimitEnvVar, "often")
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done"})
+	testutils.AssertErrorContains(t, err, "NOTIFY_RATE_LIMIT must be a whole number")
+}
+
+func TestNotify_Errors(t *testing.T) {
+	setupFakeNotifier(t)
+
+	_, err := runNotify(t, &notify.NotifyTool{}, map[string]any{})
+	testutils.AssertErrorContains(t, err, "missing required parameter: message")
+
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "status": "urgent"})
+	testutils.AssertErrorContains(t, err, "status must be one of")
+
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "email"})
+	testutils.AssertErrorContains(t, err, "unknown channel: email")
+
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "slack"})
+	testutils.AssertErrorContains(t, err, "slack is not configured; the server must set NOTIFY_SLACK_WEBHOOK_URL")
+
+	t.Setenv(notify.TeamsWebhookEnvVar, "file:///etc/passwd")
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "channels": "teams"})
+	testutils.AssertErrorContains(t, err, "NOTIFY_TEAMS_WEBHOOK_URL must be an http or https URL")
+
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done", "duration": float64(-1)})
+	testutils.AssertErrorContains(t, err, "duration must be a number of seconds")
+
+	failing := filepath.Join(t.TempDir(), "failing")
+	testutils.AssertNoError(t, os.WriteFile(failing, []byte("#!/bin/sh\necho 'Cannot connect to the notification daemon' >&2\nexit 1\n"), 0700))
+	t.Setenv(notify.DesktopCommandEnvVar, failing)
+	_, err = runNotify(t, &notify.NotifyTool{}, map[string]any{"message": "Done"})
+	testutils.AssertErrorContains(t, err, "desktop: failing failed: Cannot connect to the notification daemon")
+}
@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
+	"github.com/sammcj/mcp-devtools/internal/oauth/types"
+	"github.com/sammcj/mcp-devtools/internal/tools/transform"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const accessPolicy = `default:
+  tools: [internet_search]
+  calls_per_hour: 2
+users:
+  alice:
+    tools: ["*"]
+    deny_tools: [security_override]
+groups:
+  developers:
+    tools: [filesystem, internet_search]
+    allowed_directories: [/srv/projects]
+  reviewers:
+    tools: [filesystem]
+    allowed_directories: [/srv/reviews]
+    calls_per_hour: 100
+`
+
+// claimsContext returns a context carrying OAuth claims, as set by the HTTP transport
+func claimsContext(subject string, groups ...string) context.Context {
+	claims := &types.TokenClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: subject}, Groups: groups}
+	return context.WithValue(context.Background(), types.OAuthClaimsKey, claims)
+}
+
+// usePolicy enforces an access policy for the duration of a test
+func usePolicy(t *testing.T, data string) *access.Policy {
+	t.Helper()
+	policy, err := access.ParsePolicy([]byte(data))
+	require.NoError(t, err)
+	access.SetPolicy(policy)
+	t.Cleanup(func() { access.SetPolicy(nil) })
+	return policy
+}
+
+func TestOAuthAccessPolicy_Permissions(t *testing.T) {
+	policy := usePolicy(t, accessPolicy)
+
+	// User entries take precedence over groups
+	alice := policy.PermissionsFor(&types.TokenClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}, Groups: []string{"reviewers"}})
+	assert.True(t, alice.AllowsTool("filesystem"))
+	assert.False(t, alice.AllowsTool("security_override"))
+	assert.Nil(t, alice.AllowedDirectories())
+	assert.Equal(t, 0, alice.CallsPerHour())
+
+	// Matching groups are combined, and any unlimited quota wins
+	bob := policy.PermissionsFor(&types.TokenClaims{Username: "bob", Authorities: []string{"developers", "reviewers"}})
+	assert.Equal(t, "bob", bob.Identity)
+	assert.True(t, bob.AllowsTool("internet_search"))
+	assert.False(t, bob.AllowsTool("shell"))
+	assert.Equal(t, []string{"/srv/projects", "/srv/reviews"}, bob.AllowedDirectories())
+	assert.Equal(t, 0, bob.CallsPerHour())
+
+	// Everyone else, including unauthenticated callers, gets the default rule
+	for _, claims := range []*types.TokenClaims{{Username: "carol"}, nil} {
+		other := policy.PermissionsFor(claims)
+		assert.True(t, other.AllowsTool("internet_search"))
+		assert.False(t, other.AllowsTool("filesystem"))
+		assert.Equal(t, 2, other.CallsPerHour())
+	}
+}
+
+func TestOAuthAccessPolicy_Validation(t *testing.T) {
+	_, err := access.ParsePolicy([]byte("default:\n  calls_per_hour: -1\n"))
+	assert.Error(t, err)
+
+	_, err = access.ParsePolicy([]byte("groups:\n  devs:\n    allowed_directories: [relative/dir]\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "group devs")
+}
+
+func TestOAuthAccessPolicy_FilterAndMiddleware(t *testing.T) {
+	usePolicy(t, accessPolicy)
+
+	tools := []mcp.Tool{{Name: "filesystem"}, {Name: "internet_search"}, {Name: "shell"}}
+	filtered := access.FilterTools(claimsContext("dave", "developers"), tools)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "filesystem", filtered[0].Name)
+	assert.Len(t, tools, 3, "the server's tool list must not be modified")
+
+	calls := 0
+	handler := access.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+	call := func(ctx context.Context, name string) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.True(t, call(claimsContext("dave", "developers"), "shell").IsError)
+	assert.Equal(t, 0, calls)
+
+	// The default rule allows two calls an hour per user
+	carol := claimsContext("carol")
+	assert.False(t, call(carol, "internet_search").IsError)
+	assert.False(t, call(carol, "internet_search").IsError)
+	result := call(carol, "internet_search")
+	assert.True(t, result.IsError)
+	assert.Contains(t, getTextContent(result), "quota of 2 calls per hour")
+	assert.False(t, call(claimsContext("erin"), "internet_search").IsError, "quotas are per user")
+
+	// Without a policy every call is allowed
+	access.SetPolicy(nil)
+	assert.False(t, call(carol, "shell").IsError)
+	assert.Len(t, access.FilterTools(carol, tools), 3)
+}
+
+func TestOAuthAccessPolicy_FilesystemDirectories(t *testing.T) {
+	allowed := t.TempDir()
+	project := filepath.Join(allowed, "project")
+	require.NoError(t, os.MkdirAll(project, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(allowed, "secret.txt"), []byte("secret"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "readme.txt"), []byte("readme"), 0600))
+
+	usePolicy(t, `default:
+  tools: [filesystem]
+  allowed_directories: [`+project+`]
+users:
+  admin:
+    tools: ["*"]
+  outsider:
+    tools: [filesystem]
+    allowed_directories: [/nonexistent/elsewhere]
+`)
+	tool := setupFilesystemTool(allowed)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	read := func(ctx context.Context, path string) (*mcp.CallToolResult, error) {
+		return tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+			"function": "read_file",
+			"options":  map[string]any{"path": path},
+		})
+	}
+
+	user := claimsContext("dave")
+	_, err := read(user, filepath.Join(allowed, "secret.txt"))
+	assert.Error(t, err)
+	// Relative paths resolve against the user's directory
+	result, err := read(user, "readme.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "readme", getTextContent(result))
+
+	result, err = read(claimsContext("admin"), filepath.Join(allowed, "secret.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", getTextContent(result))
+
+	// Policy directories never widen access beyond the configured allowed directories
+	_, err = read(claimsContext("outsider"), filepath.Join(project, "readme.txt"))
+	assert.Error(t, err)
+}
+
+func TestOAuthAccessPolicy_DirectoriesLimitEveryTool(t *testing.T) {
+	allowed := t.TempDir()
+	project := filepath.Join(allowed, "project")
+	require.NoError(t, os.MkdirAll(project, 0700))
+	secret := filepath.Join(allowed, "secret.json")
+	require.NoError(t, os.WriteFile(secret, []byte(`{"token": "secret"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "config.json"), []byte(`{"name": "demo"}`), 0600))
+	// A link inside the user's directory must not lead out of it
+	require.NoError(t, os.Symlink(secret, filepath.Join(project, "link.json")))
+
+	usePolicy(t, `default:
+  tools: ["*"]
+  allowed_directories: [`+project+`]
+users:
+  admin:
+    tools: ["*"]
+`)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	transformFile := func(ctx context.Context, path string) error {
+		_, err := (&transform.TransformTool{}).Execute(ctx, logger, &sync.Map{}, map[string]any{"file": path, "query": "."})
+		return err
+	}
+
+	user := claimsContext("dave")
+	require.NoError(t, transformFile(user, filepath.Join(project, "config.json")))
+	assert.ErrorContains(t, transformFile(user, secret), "outside the directories your access policy allows")
+	assert.Error(t, transformFile(user, filepath.Join(project, "link.json")))
+	assert.NoError(t, transformFile(claimsContext("admin"), secret))
+
+	// Files that do not exist yet, such as outputs, are checked against their nearest parent
+	assert.NoError(t, workspace.CheckFileAccess(user, filepath.Join(project, "out", "report.csv")))
+	assert.Error(t, workspace.CheckFileAccess(user, filepath.Join(allowed, "out", "report.csv")))
+}
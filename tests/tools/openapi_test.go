@@ -0,0 +1,244 @@
+package tools_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/openapi"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const testOpenAPISpec = `openapi: 3.0.3
+info:
+  title: Shop API
+  version: 1.2.0
+servers:
+  - url: https://api.example.com
+security:
+  - bearerAuth: []
+paths:
+  /orders:
+    get:
+      tags: [orders]
+      summary: List orders
+      operationId: listOrders
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [pending, paid]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Order'
+    post:
+      tags: [orders]
+      summary: Create an order
+      operationId: createOrder
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        "201":
+          description: Created
+  /orders/{orderId}:
+    parameters:
+      - name: orderId
+        in: path
+        required: true
+        schema:
+          type: string
+          format: uuid
+    get:
+      tags: [orders]
+      summary: Get an order
+      responses:
+        "200":
+          description: OK
+  /users:
+    get:
+      tags: [users]
+      summary: List users
+      deprecated: true
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id, items]
+      properties:
+        id:
+          type: string
+          format: uuid
+          readOnly: true
+        items:
+          type: array
+          items:
+            $ref: '#/components/schemas/OrderItem'
+        parent:
+          $ref: '#/components/schemas/Order'
+    OrderItem:
+      type: object
+      properties:
+        sku:
+          type: string
+          description: Stock keeping unit
+`
+
+const testSwaggerSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy API", "version": "0.9"},
+  "host": "legacy.example.com",
+  "basePath": "/v1",
+  "paths": {
+    "/pets": {
+      "post": {
+        "summary": "Add a pet",
+        "consumes": ["application/json"],
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}},
+          {"name": "dryRun", "in": "query", "type": "boolean"}
+        ],
+        "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"name": {"type": "string"}}}
+  }
+}`
+
+func writeSpec(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func runOpenAPI(t *testing.T, args map[string]any) string {
+	t.Helper()
+	tool := &openapi.OpenAPITool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	testutils.AssertNoError(t, err)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text
+}
+
+func TestOpenAPITool_Definition(t *testing.T) {
+	tool := &openapi.OpenAPITool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "openapi", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, "Swagger 2.0"))
+}
+
+func TestOpenAPITool_SummaryAndList(t *testing.T) {
+	source := writeSpec(t, "openapi.yaml", testOpenAPISpec)
+
+	summary := runOpenAPI(t, map[string]any{"function": "summary", "source": source})
+	testutils.AssertTrue(t, testutils.Contains(summary, "# Shop API (v1.2.0)"))
+	testutils.AssertTrue(t, testutils.Contains(summary, "4 endpoints · 2 schemas"))
+	testutils.AssertTrue(t, testutils.Contains(summary, "- orders (3)"))
+
+	list := runOpenAPI(t, map[string]any{"function": "list_endpoints", "source": source, "path": "/orders", "method": "get"})
+	testutils.AssertTrue(t, testutils.Contains(list, "Found 2 endpoints"))
+	testutils.AssertTrue(t, testutils.Contains(list, "`GET /orders/{orderId}` - Get an order"))
+
+	list = runOpenAPI(t, map[string]any{"function": "list_endpoints", "source": source, "tag": "users"})
+	testutils.AssertTrue(t, testutils.Contains(list, "(deprecated)"))
+
+	list = runOpenAPI(t, map[string]any{"function": "list_endpoints", "source": source, "query": "createorder"})
+	testutils.AssertTrue(t, testutils.Contains(list, "`POST /orders`"))
+}
+
+func TestOpenAPITool_GetEndpointAndSchema(t *testing.T) {
+	source := writeSpec(t, "openapi.yaml", testOpenAPISpec)
+
+	detail := runOpenAPI(t, map[string]any{"function": "get_endpoint", "source": source, "method": "POST", "path": "/orders"})
+	testutils.AssertTrue(t, testutils.Contains(detail, "### Request Body (required) - application/json"))
+	testutils.AssertTrue(t, testutils.Contains(detail, "id: string(uuid) // read-only"))
+	testutils.AssertTrue(t, testutils.Contains(detail, "sku?: string // Stock keeping unit"))
+	testutils.AssertTrue(t, testutils.Contains(detail, "parent?: Order\n"))
+	testutils.AssertTrue(t, testutils.Contains(detail, "auth: bearerAuth"))
+
+	// Concrete paths match templates and path-level parameters are included
+	detail = runOpenAPI(t, map[string]any{"function": "get_endpoint", "source": source, "path": "/orders/abc-123"})
+	testutils.AssertTrue(t, testutils.Contains(detail, "| orderId | path | string(uuid) | yes |"))
+
+	schema := runOpenAPI(t, map[string]any{"function": "get_schema", "source": source, "name": "orderitem"})
+	testutils.AssertTrue(t, testutils.Contains(schema, "## OrderItem"))
+
+	tool := &openapi.OpenAPITool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"function": "get_endpoint", "source": source, "method": "DELETE", "path": "/orders"})
+	testutils.AssertErrorContains(t, err, "available: GET /orders, POST /orders")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "get_schema", "source": source, "name": "Item"})
+	testutils.AssertErrorContains(t, err, "did you mean: OrderItem")
+}
+
+func TestOpenAPITool_SwaggerFromURL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testSwaggerSpec))
+	}))
+	defer server.Close()
+
+	tool := &openapi.OpenAPITool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	args := map[string]any{"function": "get_endpoint", "source": server.URL + "/swagger.json", "method": "POST", "path": "/pets"}
+
+	result, err := tool.Execute(ctx, logger, cache, args)
+	testutils.AssertNoError(t, err)
+	text, _ := mcp.AsTextContent(result.Content[0])
+	testutils.AssertTrue(t, testutils.Contains(text.Text, "| dryRun | query | boolean |"))
+	testutils.AssertTrue(t, testutils.Contains(text.Text, "Pet {\n  name?: string\n}"))
+
+	// The parsed spec is served from the cache on the second call
+	_, err = tool.Execute(ctx, logger, cache, args)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, requests)
+}
+
+func TestOpenAPITool_Validation(t *testing.T) {
+	tool := &openapi.OpenAPITool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"function": "summary", "source": "spec.yaml"})
+	testutils.AssertErrorContains(t, err, "absolute file path")
+
+	notSpec := writeSpec(t, "config.yaml", "name: not-a-spec\n")
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "summary", "source": notSpec})
+	testutils.AssertErrorContains(t, err, "not an OpenAPI or Swagger spec")
+
+	source := writeSpec(t, "openapi.yaml", testOpenAPISpec)
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"function": "get_endpoint", "source": source})
+	testutils.AssertErrorContains(t, err, "missing required parameter: path")
+}
@@ -0,0 +1,146 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/sammcj/mcp-devtools/internal/tools/pdf"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// changeRequestForm describes a one page form with a text field, a checkbox and a radio button group,
+// as pdfcpu creates it
+const changeRequestForm = `{
+	"paper": "A4P",
+	"origin": "LowerLeft",
+	"fonts": {
+		"input": {"name": "Helvetica", "size": 12},
+		"label": {"name": "Helvetica", "size": 12}
+	},
+	"pages": {
+		"1": {
+			"content": {
+				"textfield": [{"id": "requester", "value": "", "pos": [100, 700], "width": 150}],
+				"checkbox": [{"id": "approved", "value": false, "pos": [100, 650], "width": 12}],
+				"radiobuttongroup": [{"id": "risk", "value": "", "pos": [100, 600], "width": 12,
+					"buttons": {"values": ["Low", "High"], "label": {"value": "risk", "width": 40, "gap": 5}}}]
+			}
+		}
+	}
+}`
+
+// writeForm creates the change request form
+func writeForm(t *testing.T, path string) {
+	t.Helper()
+	out, err := os.Create(path)
+	testutils.AssertNoError(t, err)
+	defer func() { _ = out.Close() }()
+	testutils.AssertNoError(t, api.Create(nil, strings.NewReader(changeRequestForm), out, nil))
+}
+
+// executeForm runs the pdf_form tool and decodes its result
+func executeForm(t *testing.T, args map[string]any) pdf.PDFFormResponse {
+	t.Helper()
+	result, err := (&pdf.PDFFormTool{}).Execute(t.Context(), quietLogger(), nil, args)
+	testutils.AssertNoError(t, err)
+	var response pdf.PDFFormResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return response
+}
+
+func TestPDFForm_ReadsAndFillsFields(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "change-request.pdf")
+	writeForm(t, source)
+
+	response := executeForm(t, map[string]any{"action": "read", "file_path": source})
+	fieldTypes := map[string]string{}
+	for _, field := range response.Fields {
+		fieldTypes[field.Name] = field.Type
+	}
+	testutils.AssertEqual(t, "text", fieldTypes["requester"])
+	testutils.AssertEqual(t, "checkbox", fieldTypes["approved"])
+	testutils.AssertEqual(t, "radio", fieldTypes["risk"])
+
+	// An editable copy keeps its fields, with the new values
+	values := map[string]any{"requester": "Jo Bloggs", "approved": true, "risk": "Low"}
+	response = executeForm(t, map[string]any{"action": "fill", "file_path": source, "values": values, "flatten": false})
+	testutils.AssertEqual(t, filepath.Join(dir, "change-request_filled.pdf"), response.OutputPath)
+	testutils.AssertEqual(t, 3, len(response.Filled))
+
+	response = executeForm(t, map[string]any{"action": "read", "file_path": response.OutputPath})
+	for _, field := range response.Fields {
+		switch field.Name {
+		case "requester":
+			testutils.AssertEqual(t, "Jo Bloggs", field.Value)
+		case "approved":
+			testutils.AssertEqual(t, true, field.Value)
+		case "risk":
+			testutils.AssertEqual(t, "Low", field.Value)
+		}
+	}
+
+	// A flattened copy has no fields left, and draws their appearances on the page
+	flat := filepath.Join(dir, "signed", "CR-1042.pdf")
+	response = executeForm(t, map[string]any{"action": "fill", "file_path": source, "values": values, "output_path": flat})
+	testutils.AssertTrue(t, response.Flattened)
+
+	ctx, err := api.ReadContextFile(flat)
+	testutils.AssertNoError(t, err)
+	_, hasForm := ctx.RootDict.Find("AcroForm")
+	testutils.AssertTrue(t, !hasForm)
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	testutils.AssertNoError(t, err)
+	_, hasAnnots := pageDict.Find("Annots")
+	testutils.AssertTrue(t, !hasAnnots)
+	resources, err := ctx.DereferenceDict(pageDict["Resources"])
+	testutils.AssertNoError(t, err)
+	xobjects, err := ctx.DereferenceDict(resources["XObject"])
+	testutils.AssertNoError(t, err)
+	drawn := 0
+	for name := range xobjects {
+		if strings.HasPrefix(name, "FlatField") {
+			drawn++
+		}
+	}
+	// The text field, the checkbox and both radio buttons, each in its current state
+	testutils.AssertEqual(t, 4, drawn)
+	_, err = (&pdf.PDFFormTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"action": "read", "file_path": flat})
+	testutils.AssertErrorContains(t, err, "form")
+
+	// The page's own content is kept, wrapped so it cannot move the fields drawn over it
+	contents, ok := pageDict["Contents"].(types.Array)
+	testutils.AssertTrue(t, ok)
+	testutils.AssertTrue(t, len(contents) >= 3)
+}
+
+func TestPDFForm_RejectsBadValues(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "change-request.pdf")
+	writeForm(t, source)
+
+	_, err := (&pdf.PDFFormTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"action":    "fill",
+		"file_path": source,
+		"values":    map[string]any{"risk": "Medium", "reviewer": "Sam", "approved": "maybe"},
+	})
+	testutils.AssertErrorContains(t, err, `risk: "Medium" is not one of: Low, High`)
+	testutils.AssertErrorContains(t, err, "reviewer: no such field")
+	testutils.AssertErrorContains(t, err, "approved: expected true or false")
+	_, statErr := os.Stat(filepath.Join(dir, "change-request_filled.pdf"))
+	testutils.AssertTrue(t, os.IsNotExist(statErr))
+
+	_, err = (&pdf.PDFFormTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{
+		"action": "fill", "file_path": source, "values": map[string]any{"risk": "Low"}, "output_path": source,
+	})
+	testutils.AssertErrorContains(t, err, "output_path must differ from file_path")
+
+	_, err = (&pdf.PDFFormTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"action": "fill", "file_path": source})
+	testutils.AssertErrorContains(t, err, "values is required for fill")
+}
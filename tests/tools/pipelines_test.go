@@ -0,0 +1,160 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools/pipelines"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// setupPipelineTools registers the tools pipeline steps call
+func setupPipelineTools(t *testing.T) {
+	t.Helper()
+	t.Setenv("ENABLE_ADDITIONAL_TOOLS", "pipelines,pipe-echo,pipe-write")
+	registry.Init(quietLogger())
+	registry.Register(readOnlyEchoTool{&echoArgsTool{MockTool: testutils.NewMockTool("pipe-echo")}})
+	registry.Register(&echoArgsTool{MockTool: testutils.NewMockTool("pipe-write")})
+}
+
+func TestPipelines_PassesOutputsBetweenSteps(t *testing.T) {
+	setupPipelineTools(t)
+	tool, err := pipelines.NewPipelineTool("check_package", pipelines.Definition{
+		Description: "Look up a package and report it",
+		Schema: map[string]any{
+			"type":     "object",
+			"required": []any{"name"},
+		},
+		Steps: []pipelines.Step{
+			{Name: "lookup", Tool: "pipe-echo", Arguments: map[string]any{
+				"package": "{{ .args.name }}",
+				"limit":   "{{ .args.limit }}",
+				"page":    "{{ .args.page }}",
+				"label":   `{{ .args.name | printf "%s-label" }}{{ with index .args "suffix" }}-{{ . }}{{ end }}`,
+			}},
+			{Tool: "pipe-echo", Arguments: map[string]any{
+				"previous": "{{ .steps.lookup.json.package }}",
+				"limit":    "{{ .steps.lookup.json.limit }}",
+				"lines":    []any{"{{ .steps.lookup.json.label | trim }}"},
+			}},
+		},
+		Output: `{{ .steps.lookup.json.package }} then {{ .steps.step2.output }}`,
+	}, nil)
+	testutils.AssertNoError(t, err)
+
+	definition := tool.Definition()
+	testutils.AssertEqual(t, "check_package", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertFalse(t, *definition.Annotations.DestructiveHint)
+
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{"name": "react", "limit": float64(5)})
+	testutils.AssertNoError(t, err)
+	// References keep their type, and an argument that was not given is left out
+	testutils.AssertEqual(t, `react then {"limit":5,"lines":["react-label"],"previous":"react"}`, result.Content[0].(mcp.TextContent).Text)
+
+	_, err = tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertErrorContains(t, err, "missing required parameter: name")
+}
+
+func TestPipelines_StepFailures(t *testing.T) {
+	setupPipelineTools(t)
+	steps := []pipelines.Step{
+		{Name: "first", Tool: "pipe-echo", Arguments: map[string]any{"fail": "registry unavailable"}},
+		{Name: "second", Tool: "pipe-write", Arguments: map[string]any{"after": "{{ .steps.first.error }}"}},
+	}
+	tool, err := pipelines.NewPipelineTool("fails", pipelines.Definition{Description: "Fails", Steps: steps}, nil)
+	testutils.AssertNoError(t, err)
+	// A step that writes makes the whole pipeline a writer
+	testutils.AssertFalse(t, *tool.Definition().Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, *tool.Definition().Annotations.DestructiveHint)
+
+	_, err = tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertErrorContains(t, err, "step 1 of 2 (first, tool pipe-echo) failed: registry unavailable")
+
+	steps[0].ContinueOnError = true
+	tool, err = pipelines.NewPipelineTool("continues", pipelines.Definition{Description: "Continues", Steps: steps}, nil)
+	testutils.AssertNoError(t, err)
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, `{"after":"registry unavailable"}`, result.Content[0].(mcp.TextContent).Text)
+
+	tool, err = pipelines.NewPipelineTool("missing", pipelines.Definition{
+		Description: "Refers to a step that has not run",
+		Steps:       []pipelines.Step{{Tool: "pipe-echo", Arguments: map[string]any{"text": "{{ .steps.later.output }} and more"}}},
+	}, nil)
+	testutils.AssertNoError(t, err)
+	_, err = tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertErrorContains(t, err, "failed to render arguments")
+}
+
+func TestPipelines_InvalidDefinitions(t *testing.T) {
+	setupPipelineTools(t)
+	declared := map[string]pipelines.Definition{"other_pipeline": {}}
+	tests := []struct {
+		name     string
+		def      pipelines.Definition
+		expected string
+	}{
+		{"no description", pipelines.Definition{Steps: []pipelines.Step{{Tool: "pipe-echo"}}}, "description is required"},
+		{"no steps", pipelines.Definition{Description: "Nothing"}, "at least one step is required"},
+		{"no tool", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Name: "a"}}}, "step a: tool is required"},
+		{"duplicate step", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Name: "a", Tool: "pipe-echo"}, {Name: "a", Tool: "pipe-echo"}}}, "name a is used by an earlier step"},
+		{"invalid step name", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Name: "fetch-page", Tool: "pipe-echo"}}}, "invalid name"},
+		{"calls a pipeline", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Tool: "other_pipeline"}}}, "cannot call the pipeline other_pipeline"},
+		{"bad template", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Tool: "pipe-echo", Arguments: map[string]any{"a": "{{ .args.x "}}}}, "step step1"},
+		{"bad output", pipelines.Definition{Description: "x", Steps: []pipelines.Step{{Tool: "pipe-echo"}}, Output: "{{ end }}"}, "output:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pipelines.NewPipelineTool("invalid", tt.def, declared)
+			testutils.AssertErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPipelines_RegisterConfigured(t *testing.T) {
+	setupPipelineTools(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path, err := pipelines.ConfigPath()
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	config := `pipelines:
+  echo_twice:
+    description: "Echo an argument twice"
+    steps:
+      - tool: pipe-echo
+        arguments: {text: "{{ .args.text }}"}
+      - tool: pipe-echo
+        arguments: {text: "{{ .steps.step1.json.text }}"}
+  pipe-write:
+    description: "Clashes with a tool"
+    steps:
+      - tool: pipe-echo
+`
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(config), 0600))
+	testutils.AssertNoError(t, pipelines.RegisterConfigured(quietLogger()))
+
+	tool, ok := registry.GetEnabledTools()["echo_twice"]
+	testutils.AssertTrue(t, ok)
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{"text": "hi"})
+	testutils.AssertNoError(t, err)
+	var output map[string]any
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output))
+	testutils.AssertEqual(t, "hi", output["text"])
+
+	status, ok := registry.GetToolStatus("echo_twice")
+	testutils.AssertTrue(t, ok)
+	testutils.AssertEqual(t, "pipeline declared in pipelines.yaml", status.Reason)
+
+	// A pipeline named after an existing tool is skipped rather than replacing it
+	status, ok = registry.GetToolStatus("pipe-write")
+	testutils.AssertTrue(t, ok)
+	testutils.AssertFalse(t, status.Reason == "pipeline declared in pipelines.yaml")
+}
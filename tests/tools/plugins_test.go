@@ -0,0 +1,125 @@
+package tools_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/plugins"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// writePlugin writes an executable shell script plugin and returns its path
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	testutils.AssertNoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700))
+	return path
+}
+
+func TestPlugins_PassesArgumentsOnStdin(t *testing.T) {
+	t.Setenv("PLUGIN_TEST_TOKEN", "token-value")
+	t.Setenv("PLUGIN_TEST_HIDDEN", "hidden-value")
+	path := writePlugin(t, `cat; echo; echo "token=$PLUGIN_TEST_TOKEN hidden=$PLUGIN_TEST_HIDDEN"`)
+
+	tool, err := plugins.NewPluginTool("echo_plugin", plugins.Definition{
+		Description: "Echo the request",
+		Command:     []string{path},
+		Env:         []string{"PLUGIN_TEST_TOKEN"},
+		ReadOnly:    true,
+		Schema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+			"required":   []any{"text"},
+		},
+	})
+	testutils.AssertNoError(t, err)
+
+	definition := tool.Definition()
+	testutils.AssertEqual(t, "echo_plugin", definition.Name)
+	testutils.AssertTrue(t, strings.Contains(string(definition.RawInputSchema), `"required":["text"]`))
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertFalse(t, *definition.Annotations.DestructiveHint)
+
+	result, err := tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{"text": "hello"})
+	testutils.AssertNoError(t, err)
+	output := pageText(t, result)
+	testutils.AssertTrue(t, strings.Contains(output, `{"version":1,"tool":"echo_plugin","arguments":{"text":"hello"}}`))
+	// Only declared environment variables reach the plugin
+	testutils.AssertTrue(t, strings.Contains(output, "token=token-value hidden=\n"))
+
+	_, err = tool.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertError(t, err)
+}
+
+func TestPlugins_FailuresAndLimits(t *testing.T) {
+	failing, err := plugins.NewPluginTool("failing", plugins.Definition{
+		Description: "Always fails",
+		Command:     []string{writePlugin(t, `echo "ticket not found" >&2; exit 3`)},
+	})
+	testutils.AssertNoError(t, err)
+	_, err = failing.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertError(t, err)
+	testutils.AssertEqual(t, "plugin failing exited with status 3: ticket not found", err.Error())
+
+	slow, err := plugins.NewPluginTool("slow", plugins.Definition{
+		Description: "Never finishes",
+		Command:     []string{writePlugin(t, `exec sleep 30`)},
+		Timeout:     1,
+	})
+	testutils.AssertNoError(t, err)
+	_, err = slow.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertError(t, err)
+	testutils.AssertTrue(t, strings.Contains(err.Error(), "timed out after 1s"))
+
+	verbose, err := plugins.NewPluginTool("verbose", plugins.Definition{
+		Description:    "Writes too much",
+		Command:        []string{writePlugin(t, `i=0; while [ $i -lt 1000 ]; do echo "line $i"; i=$((i+1)); done`)},
+		MaxOutputBytes: 100,
+	})
+	testutils.AssertNoError(t, err)
+	result, err := verbose.Execute(context.Background(), quietLogger(), &sync.Map{}, map[string]any{})
+	testutils.AssertNoError(t, err)
+	output := pageText(t, result)
+	testutils.AssertTrue(t, strings.HasPrefix(output, "line 0\n"))
+	testutils.AssertTrue(t, strings.Contains(output, "[Plugin output truncated: showing 100 of 8890 bytes]"))
+}
+
+func TestPlugins_InvalidDefinitions(t *testing.T) {
+	_, err := plugins.NewPluginTool("no_command", plugins.Definition{Description: "x"})
+	testutils.AssertError(t, err)
+	_, err = plugins.NewPluginTool("bad name!", plugins.Definition{Description: "x", Command: []string{"true"}})
+	testutils.AssertError(t, err)
+	_, err = plugins.NewPluginTool("bad_schema", plugins.Definition{
+		Description: "x",
+		Command:     []string{"true"},
+		Schema:      map[string]any{"type": "string"},
+	})
+	testutils.AssertError(t, err)
+}
+
+func TestPlugins_LoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugins.yaml")
+	config, err := plugins.LoadConfig(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(config.Plugins))
+
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(`plugins:
+  ticket:
+    description: Look up a ticket
+    command: ["~/bin/ticket", "--json"]
+    schema:
+      type: object
+      properties:
+        key: {type: string}
+`), 0600))
+	config, err = plugins.LoadConfig(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(config.Plugins))
+	tool, err := plugins.NewPluginTool("ticket", config.Plugins["ticket"])
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(tool.Definition().RawInputSchema), `"key":{"type":"string"}`))
+}
@@ -0,0 +1,183 @@
+package tools_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/powerpoint"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/xuri/excelize/v2"
+)
+
+// readPptxParts returns the contents of every part in a generated .pptx
+func readPptxParts(t *testing.T, path string) map[string]string {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	testutils.AssertNoError(t, err)
+	defer func() { _ = r.Close() }()
+
+	parts := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		testutils.AssertNoError(t, err)
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		testutils.AssertNoError(t, err)
+		parts[f.Name] = string(data)
+	}
+	if len(r.File) == 0 || r.File[0].Name != "[Content_Types].xml" {
+		t.Error("Expected [Content_Types].xml to be the first archive entry")
+	}
+	return parts
+}
+
+func executePowerPoint(t *testing.T, args map[string]any) powerpoint.PresentationResponse {
+	t.Helper()
+	_ = os.Setenv("ENABLE_ADDITIONAL_TOOLS", "powerpoint")
+	defer func() { _ = os.Unsetenv("ENABLE_ADDITIONAL_TOOLS") }()
+
+	tool := &powerpoint.PowerPointTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNotNil(t, result)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	var response powerpoint.PresentationResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestPowerPointTool_Definition(t *testing.T) {
+	tool := &powerpoint.PowerPointTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "powerpoint", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, ".pptx"))
+	testutils.AssertTrue(t, testutils.Contains(strings.Join(definition.InputSchema.Required, ","), "output_path"))
+}
+
+func TestPowerPointTool_Markdown(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "deck.pptx")
+	response := executePowerPoint(t, map[string]any{
+		"output_path": output,
+		"markdown":    "# Quarterly Update\nEngineering & Ops\n\n## Highlights\n- Shipped **v2.0**\n  - Faster builds\n- Hired two engineers\n\nNotes:\nMention the migration timeline.\n\n## Next Steps\n1. Plan Q3",
+	})
+
+	testutils.AssertEqual(t, 3, response.SlideCount)
+	parts := readPptxParts(t, output)
+
+	for _, name := range []string{"ppt/presentation.xml", "ppt/slides/slide1.xml", "ppt/slides/slide3.xml", "ppt/notesSlides/notesSlide2.xml", "docProps/core.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Expected part %s in presentation", name)
+		}
+	}
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/slides/slide1.xml"], "Engineering &amp; Ops"))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/slides/slide2.xml"], `<a:pPr lvl="1"/><a:r><a:rPr lang="en-GB" dirty="0"/><a:t>Faster builds</a:t>`))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/slides/slide2.xml"], "<a:t>Shipped v2.0</a:t>"))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/notesSlides/notesSlide2.xml"], "Mention the migration timeline."))
+	testutils.AssertTrue(t, strings.Contains(parts["docProps/core.xml"], "<dc:title>Quarterly Update</dc:title>"))
+
+	info, err := os.Stat(output)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestPowerPointTool_InlineChartAndImage(t *testing.T) {
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "diagram.png")
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	img.Set(1, 1, color.Black)
+	f, err := os.Create(imagePath)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, png.Encode(f, img))
+	_ = f.Close()
+
+	output := filepath.Join(dir, "chart.pptx")
+	response := executePowerPoint(t, map[string]any{
+		"output_path": output,
+		"slides": []any{
+			map[string]any{"title": "Overview", "image": imagePath, "bullets": []any{map[string]any{"text": "Components"}}},
+			map[string]any{"title": "Latency", "chart": map[string]any{
+				"type":       "line",
+				"categories": []any{"Jan", "Feb"},
+				"series":     []any{map[string]any{"name": "p95", "values": []any{120, 95.5}}},
+			}},
+			map[string]any{"title": "Missing", "image": filepath.Join(dir, "missing.png")},
+		},
+	})
+
+	testutils.AssertEqual(t, 3, response.SlideCount)
+	testutils.AssertEqual(t, 1, len(response.Warnings))
+	parts := readPptxParts(t, output)
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/slides/slide1.xml"], "<p:pic>"))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/slides/_rels/slide1.xml.rels"], "../media/image1.png"))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/charts/chart1.xml"], "<c:lineChart>"))
+	testutils.AssertTrue(t, strings.Contains(parts["ppt/charts/chart1.xml"], "<c:v>95.5</c:v>"))
+	testutils.AssertTrue(t, strings.Contains(parts["[Content_Types].xml"], `Extension="png"`))
+}
+
+func TestPowerPointTool_ExcelChart(t *testing.T) {
+	dir := t.TempDir()
+	workbook := filepath.Join(dir, "sales.xlsx")
+	f := excelize.NewFile()
+	rows := [][]any{{"Region", "Q1", "Q2"}, {"North", 100, 120}, {"South", "1,250", 90}}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		testutils.AssertNoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	testutils.AssertNoError(t, f.SaveAs(workbook))
+	_ = f.Close()
+
+	output := filepath.Join(dir, "sales.pptx")
+	executePowerPoint(t, map[string]any{
+		"output_path": output,
+		"slides": []any{map[string]any{"title": "Sales", "chart": map[string]any{
+			"type":        "bar",
+			"excel_file":  workbook,
+			"excel_range": "A1:C3",
+		}}},
+	})
+
+	chart := readPptxParts(t, output)["ppt/charts/chart1.xml"]
+	testutils.AssertTrue(t, strings.Contains(chart, `<c:barDir val="bar"/>`))
+	testutils.AssertTrue(t, strings.Contains(chart, "<c:v>Q2</c:v>"))
+	testutils.AssertTrue(t, strings.Contains(chart, "<c:v>South</c:v>"))
+	testutils.AssertTrue(t, strings.Contains(chart, "<c:v>1250</c:v>"))
+}
+
+func TestPowerPointTool_Validation(t *testing.T) {
+	tool := &powerpoint.PowerPointTool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	dir := t.TempDir()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"output_path": "deck.pptx", "markdown": "# Hi"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"output_path": filepath.Join(dir, "deck.pptx")})
+	testutils.AssertErrorContains(t, err, "either 'slides' or 'markdown'")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{
+		"output_path": filepath.Join(dir, "bad.pptx"),
+		"slides":      []any{map[string]any{"title": "Bad", "chart": map[string]any{"type": "radar", "categories": []any{"a"}, "series": []any{map[string]any{"name": "x", "values": []any{1}}}}}},
+	})
+	testutils.AssertErrorContains(t, err, "unsupported chart type")
+
+	existing := filepath.Join(dir, "existing.pptx")
+	testutils.AssertNoError(t, os.WriteFile(existing, []byte("x"), 0600))
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"output_path": existing, "markdown": "# Hi"})
+	testutils.AssertErrorContains(t, err, "already exists")
+}
@@ -0,0 +1,193 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/proofread"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runProofread calls the proofread tool and decodes its result
+func runProofread(t *testing.T, args map[string]any) (*proofread.Result, error) {
+	t.Helper()
+	result, err := (&proofread.ProofreadTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var out proofread.Result
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return &out, nil
+}
+
+// findIssue returns the issue for text, failing the test when there is none
+func findIssue(t *testing.T, result *proofread.Result, text string) proofread.Issue {
+	t.Helper()
+	for _, issue := range result.Issues {
+		if issue.Text == text {
+			return issue
+		}
+	}
+	t.Fatalf("no issue for %q in %+v", text, result.Issues)
+	return proofread.Issue{}
+}
+
+const proofreadMarkdown = "---\ntitle: Teh guide\n---\n" +
+	"# Configuring the servcie\n" +
+	"\n" +
+	"You will recieve a message when the the job is done. It's an useful feature that saves a hour.\n" +
+	"You could of called `recieveAll()` or read https://example.com/recieve instead.\n" +
+	"\n" +
+	"```go\n" +
+	"func recieve() {} // teh\n" +
+	"```\n" +
+	"\n" +
+	"The organization's colour is grey, and alot of people like it.\n"
+
+func TestProofread_Markdown(t *testing.T) {
+	result, err := runProofread(t, map[string]any{"text": proofreadMarkdown})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "en-GB", result.Language)
+	testutils.AssertEqual(t, 2, result.Counts["spelling"])
+
+	service := findIssue(t, result, "servcie")
+	testutils.AssertEqual(t, 4, service.Line)
+	testutils.AssertEqual(t, 19, service.Column)
+	testutils.AssertEqual(t, "service", service.Suggestions[0])
+
+	receive := findIssue(t, result, "recieve")
+	testutils.AssertEqual(t, 6, receive.Line)
+	testutils.AssertEqual(t, "receive", receive.Suggestions[0])
+	testutils.AssertEqual(t, "spelling", receive.Kind)
+
+	testutils.AssertEqual(t, "the", findIssue(t, result, "the the").Suggestions[0])
+	testutils.AssertEqual(t, "a useful", findIssue(t, result, "an useful").Suggestions[0])
+	testutils.AssertEqual(t, "an hour", findIssue(t, result, "a hour").Suggestions[0])
+	testutils.AssertEqual(t, "could have", findIssue(t, result, "could of").Suggestions[0])
+	testutils.AssertEqual(t, "a lot", findIssue(t, result, "alot").Suggestions[0])
+	testutils.AssertEqual(t, 5, result.Counts["grammar"])
+
+	// British English reports American spellings, keeping their case and possessive
+	variant := findIssue(t, result, "organization's")
+	testutils.AssertEqual(t, "variant", variant.Kind)
+	testutils.AssertEqual(t, "organisation's", variant.Suggestions[0])
+	testutils.AssertEqual(t, 1, result.Counts["variant"])
+
+	// Front matter, code, URLs and capitalised words are not checked
+	testutils.AssertEqual(t, 1, result.Unknown[0].Count)
+	for _, issue := range result.Issues {
+		testutils.AssertTrue(t, issue.Line != 2 && issue.Line != 8)
+		testutils.AssertTrue(t, issue.Text != "Teh" && issue.Text != "recieveAll")
+	}
+}
+
+func TestProofread_Languages(t *testing.T) {
+	text := "The colour of the organisation's logo is grey. We organize and analyze things."
+
+	british, err := runProofread(t, map[string]any{"text": text, "checks": "variants"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(british.Issues))
+	testutils.AssertEqual(t, "organise", findIssue(t, british, "organize").Suggestions[0])
+	testutils.AssertEqual(t, "analyse", findIssue(t, british, "analyze").Suggestions[0])
+	_, checked := british.Counts["spelling"]
+	testutils.AssertFalse(t, checked)
+
+	american, err := runProofread(t, map[string]any{"text": text, "language": "en-US"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 3, american.Counts["variant"])
+	testutils.AssertEqual(t, "color", findIssue(t, american, "colour").Suggestions[0])
+	testutils.AssertEqual(t, "organization's", findIssue(t, american, "organisation's").Suggestions[0])
+	testutils.AssertEqual(t, "gray", findIssue(t, american, "grey").Suggestions[0])
+	testutils.AssertEqual(t, 0, american.Counts["spelling"])
+
+	// Words correct in both, such as the British verb license, are not reported
+	both, err := runProofread(t, map[string]any{"text": "We license the software towards the end of the year."})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(both.Issues))
+}
+
+func TestProofread_PrefixedWords(t *testing.T) {
+	// Prefixes are only accepted on dictionary headwords that take them
+	correct := "We reconfigured the unset options, disconnected the subprocess and recoloured the superset of nonzero, preloaded, overridden values."
+	result, err := runProofread(t, map[string]any{"text": correct, "checks": "spelling"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(result.Issues))
+
+	for word, suggestion := range map[string]string{"recived": "received", "recomend": "recommend", "supercede": "supersede"} {
+		t.Run(word, func(t *testing.T) {
+			result, err := runProofread(t, map[string]any{"text": "We " + word + " it.", "checks": "spelling"})
+			testutils.AssertNoError(t, err)
+			issue := findIssue(t, result, word)
+			testutils.AssertEqual(t, "spelling", issue.Kind)
+			testutils.AssertTrue(t, slices.Contains(issue.Suggestions, suggestion))
+		})
+	}
+}
+
+func TestProofread_ProjectDictionaries(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".git/HEAD":             "ref: refs/heads/main\n",
+		".proofread-words":      "# Project terms\nfrobnicator\n",
+		"docs/cspell.json":      `{"words": ["quuxify"], "ignoreWords": ["zorbly"]}`,
+		"docs/guide.md":         "Run the frobnicator to quuxify zorbly inputs, then blarg them.\n",
+		"docs/notes.txt":        "Some notes with a mistaek in `code`.\n",
+		"docs/.drafts/draft.md": "Hidden drafts are not checked: wrnog.\n",
+		"node_modules/x/README": "Dependencies are not checked: wrnog.\n",
+	})
+
+	result, err := runProofread(t, map[string]any{"path": filepath.Join(dir, "docs")})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, result.Files)
+	testutils.AssertEqual(t, 2, len(result.Dictionaries))
+	testutils.AssertEqual(t, 2, len(result.Issues))
+	blarg := findIssue(t, result, "blarg")
+	testutils.AssertEqual(t, "guide.md", blarg.File)
+	// Plain text files are not read as Markdown, so backticks do not hide words
+	mistake := findIssue(t, result, "mistaek")
+	testutils.AssertEqual(t, "notes.txt", mistake.File)
+	testutils.AssertEqual(t, "mistake", mistake.Suggestions[0])
+
+	// Words can also be given directly
+	single, err := runProofread(t, map[string]any{"path": filepath.Join(dir, "docs", "guide.md"), "words": "Blarg"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, single.Files)
+	testutils.AssertEqual(t, 0, len(single.Issues))
+}
+
+func TestProofread_MaxIssues(t *testing.T) {
+	result, err := runProofread(t, map[string]any{"text": "wrnog speling evrywhere in thsi text", "max_issues": float64(2)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(result.Issues))
+	testutils.AssertEqual(t, 4, result.Counts["spelling"])
+	testutils.AssertTrue(t, result.Truncated)
+	testutils.AssertEqual(t, 4, len(result.Unknown))
+}
+
+func TestProofread_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"nothing to check", map[string]any{}, "either text or path is required"},
+		{"both", map[string]any{"text": "hello", "path": dir}, "not both"},
+		{"unknown language", map[string]any{"text": "hello", "language": "fr"}, "language must be"},
+		{"unknown check", map[string]any{"text": "hello", "checks": "spelling,style"}, `unknown checks entry "style"`},
+		{"bad format", map[string]any{"text": "hello", "format": "html"}, "format must be"},
+		{"relative path", map[string]any{"path": "docs"}, "must be an absolute path"},
+		{"missing path", map[string]any{"path": filepath.Join(dir, "missing.md")}, "cannot access path"},
+		{"missing dictionary", map[string]any{"text": "hello", "dictionary": filepath.Join(dir, "words.txt")}, "dictionary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runProofread(t, tt.args)
+			testutils.AssertErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/sammcj/mcp-devtools/internal/tools/proxy"
@@ -327,3 +331,36 @@ func TestParseConfig_SimplifiedFormat_IncludeTools(t *testing.T) {
 		t.Errorf("expected first include pattern 'get_*', got %s", upstream.IncludeTools[0])
 	}
 }
+
+func TestProxyTool_CheckPrerequisites(t *testing.T) {
+	// Any HTTP response means the upstream is reachable
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer upstream.Close()
+
+	t.Setenv("PROXY_UPSTREAMS", "")
+	t.Setenv("PROXY_URL", upstream.URL+"/mcp?token=secret")
+	checks := (&proxy.ProxyTool{}).CheckPrerequisites(context.Background())
+	if len(checks) != 1 || checks[0].Status != "ok" {
+		t.Fatalf("expected one passing check, got %+v", checks)
+	}
+	if strings.Contains(checks[0].Detail, "secret") {
+		t.Errorf("check detail should not include the upstream URL's query: %s", checks[0].Detail)
+	}
+
+	upstream.Close()
+	checks = (&proxy.ProxyTool{}).CheckPrerequisites(context.Background())
+	if len(checks) != 1 || checks[0].Status != "failed" || checks[0].Remediation == "" {
+		t.Fatalf("expected one failing check with remediation, got %+v", checks)
+	}
+	if strings.Contains(checks[0].Detail, "secret") {
+		t.Errorf("check detail should not include the upstream URL's query: %s", checks[0].Detail)
+	}
+
+	t.Setenv("PROXY_URL", "")
+	checks = (&proxy.ProxyTool{}).CheckPrerequisites(context.Background())
+	if len(checks) != 1 || checks[0].Name != "configuration" || checks[0].Status != "failed" {
+		t.Fatalf("expected a failing configuration check, got %+v", checks)
+	}
+}
@@ -181,7 +181,7 @@ func TestRegisterUpstreamToolsAsync_NotEnabled(t *testing.T) {
 	srv := mcpserver.NewMCPServer("test", "1.0")
 
 	// Should return immediately without spawning a goroutine
-	proxy.RegisterUpstreamToolsAsync(t.Context(), srv, logger, "stdio")
+	proxy.RegisterUpstreamToolsAsync(t.Context(), srv, logger, "stdio", nil)
 
 	// Give a brief window for any goroutine to run (it shouldn't)
 	time.Sleep(50 * time.Millisecond)
@@ -201,7 +201,7 @@ func TestRegisterUpstreamToolsAsync_EnabledButNotConfigured(t *testing.T) {
 	srv := mcpserver.NewMCPServer("test", "1.0")
 
 	// Should return immediately without spawning a goroutine
-	proxy.RegisterUpstreamToolsAsync(t.Context(), srv, logger, "stdio")
+	proxy.RegisterUpstreamToolsAsync(t.Context(), srv, logger, "stdio", nil)
 
 	time.Sleep(50 * time.Millisecond)
 }
@@ -223,7 +223,7 @@ func TestRegisterUpstreamToolsAsync_EnabledAndConfigured(t *testing.T) {
 	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
 	defer cancel()
 
-	proxy.RegisterUpstreamToolsAsync(ctx, srv, logger, "stdio")
+	proxy.RegisterUpstreamToolsAsync(ctx, srv, logger, "stdio", nil)
 
 	// Wait for the goroutine to hit the timeout and exit
 	time.Sleep(3 * time.Second)
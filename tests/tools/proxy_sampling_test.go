@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/types"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/upstream"
+)
+
+// testSampler answers sampling requests with a fixed reply
+type testSampler struct{}
+
+func (testSampler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	prompt := request.Messages[0].Content.(map[string]any)["text"]
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent(fmt.Sprintf("reply to %v", prompt))},
+		Model:           "test-model",
+	}, nil
+}
+
+// testElicitor accepts every elicitation request
+type testElicitor struct{}
+
+func (testElicitor) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]any{"ok": request.Params.Message == "proceed?"},
+	}}, nil
+}
+
+// clientContext returns a context for a call made by a client, which declares sampling and elicitation
+// support if capable
+func clientContext(capable bool) context.Context {
+	session := mcpserver.NewInProcessSessionWithHandlers("proxy-test", testSampler{}, testElicitor{}, nil)
+	if capable {
+		session.SetClientCapabilities(mcp.ClientCapabilities{Sampling: &mcp.SamplingCapability{}, Elicitation: &mcp.ElicitationCapability{}})
+	}
+	return mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), session)
+}
+
+func TestStdioUpstream_RelaysSamplingAndElicitation(t *testing.T) {
+	conn, err := upstream.NewConnection(stdioUpstreamConfig(t), t.TempDir(), 3334)
+	if err != nil {
+		t.Fatalf("NewConnection failed: %v", err)
+	}
+	if err := conn.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	text, err := callText(clientContext(true), conn, "ask", "hello")
+	if err != nil {
+		t.Fatalf("ask failed: %v", err)
+	}
+	if !strings.HasPrefix(text, "sampled: ") || !strings.Contains(text, `"text":"reply to hello"`) || !strings.Contains(text, `"model":"test-model"`) {
+		t.Errorf("expected the client's sampling reply, got %q", text)
+	}
+
+	text, err = callText(clientContext(true), conn, "confirm", "proceed?")
+	if err != nil {
+		t.Fatalf("confirm failed: %v", err)
+	}
+	if !strings.Contains(text, `"action":"accept"`) || !strings.Contains(text, `"ok":true`) {
+		t.Errorf("expected the client's elicitation reply, got %q", text)
+	}
+
+	// Without a capable client to relay to, the upstream receives an error
+	text, err = callText(context.Background(), conn, "ask", "hello")
+	if err != nil {
+		t.Fatalf("ask without a client failed: %v", err)
+	}
+	if !strings.Contains(text, "error no client call is waiting") {
+		t.Errorf("expected an error for the upstream, got %q", text)
+	}
+	text, err = callText(clientContext(false), conn, "ask", "hello")
+	if err != nil {
+		t.Fatalf("ask from an incapable client failed: %v", err)
+	}
+	if !strings.Contains(text, "error client does not support sampling") {
+		t.Errorf("expected an error for the upstream, got %q", text)
+	}
+}
+
+func TestHTTPUpstream_RelaysStreamedSampling(t *testing.T) {
+	answers := make(chan upstream.Message, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg upstream.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			// Connectivity check
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if msg.Method == "" {
+			answers <- msg
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		// Stream a sampling request, then the tool result built from the client's answer
+		w.Header().Set("Content-Type", "text/event-stream")
+		request := `{"jsonrpc":"2.0","id":7,"method":"sampling/createMessage","params":{"messages":[{"role":"user","content":{"type":"text","text":"hi"}}],"maxTokens":5}}`
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", request)
+		w.(http.Flusher).Flush()
+		answer := <-answers
+		result, _ := json.Marshal(map[string]any{"content": []map[string]any{{"type": "text", "text": string(answer.Result)}}})
+		response, _ := json.Marshal(upstream.Message{JSONRPC: "2.0", ID: msg.ID, Result: result})
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", response)
+	}))
+	defer server.Close()
+
+	conn, err := upstream.NewConnection(&types.UpstreamConfig{Name: "http-test", URL: server.URL, Transport: "http-only"}, t.TempDir(), 3334)
+	if err != nil {
+		t.Fatalf("NewConnection failed: %v", err)
+	}
+	if err := conn.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	text, err := callText(clientContext(true), conn, "ask", "hi")
+	if err != nil {
+		t.Fatalf("ask failed: %v", err)
+	}
+	if !strings.Contains(text, `"text":"reply to hi"`) {
+		t.Errorf("expected the client's sampling reply, got %q", text)
+	}
+}
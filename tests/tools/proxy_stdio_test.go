@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/types"
+	"github.com/sammcj/mcp-devtools/internal/tools/proxy/upstream"
+)
+
+// stdioServerEnvVar makes the test binary act as a stdio MCP server for TestHelperStdioServer
+const stdioServerEnvVar = "MCP_DEVTOOLS_TEST_STDIO_SERVER"
+
+// TestHelperStdioServer is not a real test: run with stdioServerEnvVar set, it is a minimal stdio MCP
+// server with an echo tool that exits when asked to crash, and ask and confirm tools that send sampling
+// and elicitation requests to the client
+func TestHelperStdioServer(t *testing.T) {
+	if os.Getenv(stdioServerEnvVar) != "1" {
+		t.Skip("helper process")
+	}
+
+	// Banners on stdout and logs on stderr must not break the proxy
+	fmt.Println("starting test server")
+	fmt.Fprintln(os.Stderr, "test server log line")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request upstream.Message
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil || request.ID == nil {
+			continue
+		}
+		var result any
+		switch request.Method {
+		case "initialize":
+			result = map[string]any{"protocolVersion": "2025-03-26", "capabilities": map[string]any{"tools": map[string]any{}}, "serverInfo": map[string]any{"name": "test", "version": "1"}}
+		case "tools/list":
+			result = map[string]any{"tools": []map[string]any{{"name": "echo", "inputSchema": map[string]any{"type": "object"}}}}
+		case "tools/call":
+			var params struct {
+				Name      string            `json:"name"`
+				Arguments map[string]string `json:"arguments"`
+			}
+			_ = json.Unmarshal(request.Params, &params)
+			if params.Arguments["text"] == "crash" {
+				os.Exit(1)
+			}
+			text := "echo: " + params.Arguments["text"]
+			switch params.Name {
+			case "ask":
+				text = "sampled: " + askClient(scanner, "sampling/createMessage", map[string]any{
+					"messages":  []map[string]any{{"role": "user", "content": map[string]any{"type": "text", "text": params.Arguments["text"]}}},
+					"maxTokens": 10,
+				})
+			case "confirm":
+				text = "elicited: " + askClient(scanner, "elicitation/create", map[string]any{
+					"message":         params.Arguments["text"],
+					"requestedSchema": map[string]any{"type": "object", "properties": map[string]any{"ok": map[string]any{"type": "boolean"}}},
+				})
+			}
+			result = map[string]any{"content": []map[string]any{{"type": "text", "text": text}}}
+		}
+		data, _ := json.Marshal(result)
+		response, _ := json.Marshal(upstream.Message{JSONRPC: "2.0", ID: request.ID, Result: data})
+		fmt.Println(string(response))
+	}
+	os.Exit(0)
+}
+
+// askClient sends a request to the client from TestHelperStdioServer and returns the result or error it
+// answers with
+func askClient(scanner *bufio.Scanner, method string, params any) string {
+	data, _ := json.Marshal(params)
+	request, _ := json.Marshal(upstream.Message{JSONRPC: "2.0", ID: "ask-1", Method: method, Params: data})
+	fmt.Println(string(request))
+	for scanner.Scan() {
+		var response upstream.Message
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil || response.ID != "ask-1" {
+			continue
+		}
+		if response.Error != nil {
+			return "error " + response.Error.Message
+		}
+		return string(response.Result)
+	}
+	return "no answer"
+}
+
+// stdioUpstreamConfig returns an upstream that runs TestHelperStdioServer
+func stdioUpstreamConfig(t *testing.T) *types.UpstreamConfig {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find test binary: %v", err)
+	}
+	return &types.UpstreamConfig{
+		Name:    "stdio-test",
+		Command: executable,
+		Args:    []string{"-test.run=^TestHelperStdioServer$"},
+		Env:     map[string]string{stdioServerEnvVar: "1"},
+	}
+}
+
+// echoText calls the echo tool and returns its text
+func echoText(ctx context.Context, conn *upstream.Connection, text string) (string, error) {
+	return callText(ctx, conn, "echo", text)
+}
+
+// callText calls a tool with a text argument and returns its text
+func callText(ctx context.Context, conn *upstream.Connection, tool, text string) (string, error) {
+	msg, err := conn.ExecuteTool(ctx, tool, map[string]any{"text": text})
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(msg.Result, &result); err != nil || len(result.Content) == 0 {
+		return "", fmt.Errorf("unexpected result %s", msg.Result)
+	}
+	return result.Content[0].Text, nil
+}
+
+func TestStdioUpstream_ProxiesAndRestarts(t *testing.T) {
+	ctx := context.Background()
+	conn, err := upstream.NewConnection(stdioUpstreamConfig(t), t.TempDir(), 3334)
+	if err != nil {
+		t.Fatalf("NewConnection failed: %v", err)
+	}
+	if err := conn.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.FetchTools(ctx); err != nil {
+		t.Fatalf("FetchTools failed: %v", err)
+	}
+	if tools := conn.GetTools(); len(tools) != 1 || tools[0].Name != "echo" {
+		t.Fatalf("expected the echo tool, got %+v", tools)
+	}
+
+	text, err := echoText(ctx, conn, "hello")
+	if err != nil || text != "echo: hello" {
+		t.Fatalf("expected echo of hello, got %q, %v", text, err)
+	}
+
+	// A crash fails the call in progress, then the process is restarted
+	if _, err := echoText(ctx, conn, "crash"); !errors.Is(err, upstream.ErrNotRunning) {
+		t.Fatalf("expected a not running error from the crash, got %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		text, err = echoText(ctx, conn, "again")
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, upstream.ErrNotRunning) || time.Now().After(deadline) {
+			t.Fatalf("expected the upstream to restart, got %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if text != "echo: again" {
+		t.Errorf("expected echo of again after restart, got %q", text)
+	}
+}
+
+func TestStdioUpstream_FailsToStartMissingCommand(t *testing.T) {
+	config := &types.UpstreamConfig{Name: "missing", Command: "mcp-devtools-no-such-command"}
+	conn, err := upstream.NewConnection(config, t.TempDir(), 3334)
+	if err != nil {
+		t.Fatalf("NewConnection failed: %v", err)
+	}
+	if err := conn.Connect(context.Background()); err == nil {
+		t.Error("expected an error starting a missing command")
+	}
+}
+
+func TestParseConfig_StdioUpstreams(t *testing.T) {
+	t.Setenv("PROXY_UPSTREAMS", `[
+		{"name": "fs", "command": "npx", "args": ["-y", "server-filesystem"], "env": {"TOKEN": "x"}},
+		{"name": "remote", "url": "https://mcp.example.com/mcp"}
+	]`)
+	config, err := proxy.ParseConfig()
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if !config.Upstreams[0].IsStdio() || config.Upstreams[1].IsStdio() {
+		t.Errorf("expected only the first upstream to be stdio")
+	}
+	if strings.Join(config.Upstreams[0].Args, " ") != "-y server-filesystem" || config.Upstreams[0].Env["TOKEN"] != "x" {
+		t.Errorf("unexpected stdio upstream %+v", config.Upstreams[0])
+	}
+
+	invalid := map[string]string{
+		"url and command":         `[{"name": "a", "url": "https://x", "command": "npx"}]`,
+		"stdio without command":   `[{"name": "a", "transport": "stdio"}]`,
+		"command with http":       `[{"name": "a", "command": "npx", "transport": "http-only"}]`,
+		"neither url nor command": `[{"name": "a"}]`,
+	}
+	for name, upstreams := range invalid {
+		t.Setenv("PROXY_UPSTREAMS", upstreams)
+		if _, err := proxy.ParseConfig(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
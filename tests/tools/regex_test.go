@@ -0,0 +1,109 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/regextest"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runRegexTest calls the regex_test tool and decodes its JSON response
+func runRegexTest(t *testing.T, args map[string]any) (*regextest.Response, error) {
+	t.Helper()
+	result, err := (&regextest.RegexTestTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var response regextest.Response
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return &response, nil
+}
+
+func TestRegexTest_MatchesAndGroups(t *testing.T) {
+	response, err := runRegexTest(t, map[string]any{
+		"pattern": `v(?P<major>\d+)\.(?P<minor>\d+)(-rc(\d+))?`,
+		"text":    "upgrade from v1.24 to\nv1.26-rc2",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, response.Valid)
+	testutils.AssertEqual(t, 2, response.MatchCount)
+	testutils.AssertEqual(t, 4, len(response.GroupNames))
+	testutils.AssertEqual(t, "major", response.GroupNames[0])
+
+	first := response.Matches[0]
+	testutils.AssertEqual(t, "v1.24", first.Text)
+	testutils.AssertEqual(t, 13, first.Start)
+	testutils.AssertEqual(t, 14, first.Column)
+	// Optional groups that did not take part are left out
+	testutils.AssertEqual(t, 2, len(first.Groups))
+	testutils.AssertEqual(t, "24", first.Groups[1].Text)
+
+	second := response.Matches[1]
+	testutils.AssertEqual(t, 2, second.Line)
+	testutils.AssertEqual(t, 1, second.Column)
+	testutils.AssertEqual(t, 4, len(second.Groups))
+	testutils.AssertEqual(t, "2", second.Groups[3].Text)
+
+	testutils.AssertEqual(t, "upgrade from «v1.24» to\n«v1.26-rc2»", response.Highlighted)
+	testutils.AssertTrue(t, response.SecurityRule.Accepted)
+}
+
+func TestRegexTest_FlagsReplaceAndLimits(t *testing.T) {
+	response, err := runRegexTest(t, map[string]any{"pattern": "^error", "flags": "im", "text": "ok\nERROR one\nError two"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "(?im)^error", response.Pattern)
+	testutils.AssertEqual(t, 2, response.MatchCount)
+
+	response, err = runRegexTest(t, map[string]any{"pattern": `(\d{2})/(\d{2})/(\d{4})`, "text": "due 18/10/2026", "replace": "$3-$2-$1"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "due 2026-10-18", *response.Replaced)
+
+	response, err = runRegexTest(t, map[string]any{"pattern": `\w`, "text": "abcdef", "max_matches": float64(2)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 6, response.MatchCount)
+	testutils.AssertEqual(t, 2, len(response.Matches))
+	testutils.AssertTrue(t, response.Truncated)
+	testutils.AssertEqual(t, "«a»«b»«c»«d»«e»«f»", response.Highlighted)
+
+	_, err = runRegexTest(t, map[string]any{"pattern": "a", "flags": "x"})
+	testutils.AssertErrorContains(t, err, "invalid flag")
+}
+
+func TestRegexTest_UnsupportedSyntax(t *testing.T) {
+	response, err := runRegexTest(t, map[string]any{"pattern": `password(?!_hash)`, "text": "password=1"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.Valid)
+	testutils.AssertTrue(t, strings.Contains(response.Error, "invalid or unsupported Perl syntax"))
+	testutils.AssertEqual(t, 1, len(response.Hints))
+	testutils.AssertTrue(t, strings.Contains(response.Hints[0], "Negative lookaheads"))
+	testutils.AssertFalse(t, response.SecurityRule.Accepted)
+
+	response, err = runRegexTest(t, map[string]any{"pattern": `(a)\1`})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.Valid)
+	testutils.AssertTrue(t, strings.Contains(response.Hints[0], "Backreferences"))
+}
+
+func TestRegexTest_SecurityRule(t *testing.T) {
+	response, err := runRegexTest(t, map[string]any{"pattern": `(?i)curl\s+[^|]*\|\s*(ba)?sh`, "text": "curl -fsSL https://example.com/i.sh | bash"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, response.MatchCount)
+	testutils.AssertEqual(t, `regex: '(?i)curl\s+[^|]*\|\s*(ba)?sh'`, response.SecurityRule.YAML)
+	testutils.AssertEqual(t, 0, len(response.SecurityRule.Warnings))
+
+	// Single quotes are doubled in YAML
+	response, err = runRegexTest(t, map[string]any{"pattern": `it's`})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, `regex: 'it''s'`, response.SecurityRule.YAML)
+
+	// A pattern that matches empty text would match everything
+	response, err = runRegexTest(t, map[string]any{"pattern": `(secret)?`})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(response.SecurityRule.Warnings))
+	testutils.AssertTrue(t, strings.Contains(response.SecurityRule.Warnings[0], "match all content"))
+}
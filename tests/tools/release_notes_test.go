@@ -0,0 +1,149 @@
+package tools_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/releasenotes"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// releaseNotesHTTPClient serves canned responses by URL prefix and 404 for anything else
+type releaseNotesHTTPClient struct {
+	responses map[string]string
+}
+
+func (c *releaseNotesHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	for prefix, body := range c.responses {
+		if strings.HasPrefix(url, prefix) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+}
+
+const releaseNotesGitHubReleases = `[
+  {"tag_name": "v3.0.0-rc.1", "name": "v3.0.0-rc.1", "prerelease": true, "body": "- Preview of 3.0", "html_url": "https://github.com/acme/widget/releases/tag/v3.0.0-rc.1", "published_at": "2026-03-01T00:00:00Z"},
+  {"tag_name": "v2.1.0", "name": "v2.1.0", "body": "## What's Changed\n* Add streaming API by @alice in https://github.com/acme/widget/pull/42\n* Deprecate Client.fetch in favour of Client.get\n* Fix CVE-2026-1234 in header parsing\n\n**Full Changelog**: https://github.com/acme/widget/compare/v2.0.0...v2.1.0", "html_url": "https://github.com/acme/widget/releases/tag/v2.1.0", "published_at": "2026-02-01T00:00:00Z"},
+  {"tag_name": "v2.0.0", "name": "v2.0.0", "body": "### Breaking Changes\n- Drop Node 18\n- Rename widget() to createWidget()\n\n### Features\n- New theme support\n- feat!: config is now async", "html_url": "https://github.com/acme/widget/releases/tag/v2.0.0", "published_at": "2026-01-01T00:00:00Z"},
+  {"tag_name": "v1.9.0", "name": "v1.9.0", "body": "- Old change", "html_url": "https://github.com/acme/widget/releases/tag/v1.9.0", "published_at": "2025-12-01T00:00:00Z"},
+  {"tag_name": "v1.8.0", "draft": true, "body": "- Draft"}
+]`
+
+const releaseNotesChangelog = `# Changelog
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.2.0] - 2026-02-10
+
+### Removed
+- The legacy exporter
+
+### Added
+- CSV output
+
+## [1.1.0] - 2026-01-05
+
+### Security
+- Upgrade yaml parser
+
+## 1.0.0 - 2025-12-01
+
+Initial release.
+`
+
+func runReleaseNotes(t *testing.T, client *releaseNotesHTTPClient, args map[string]any) (string, error) {
+	t.Helper()
+	tool := releasenotes.NewReleaseNotesTool(client)
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestReleaseNotesTool_Definition(t *testing.T) {
+	tool := &releasenotes.ReleaseNotesTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "release_notes", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+}
+
+func TestReleaseNotesTool_GitHubReleases(t *testing.T) {
+	client := &releaseNotesHTTPClient{responses: map[string]string{
+		"https://registry.npmjs.org/widget/latest":  `{"repository": {"type": "git", "url": "git+https://github.com/acme/widget.git"}}`,
+		"https://api.github.com/repos/acme/widget/": releaseNotesGitHubReleases,
+	}}
+
+	output, err := runReleaseNotes(t, client, map[string]any{
+		"ecosystem":    "npm",
+		"package":      "widget",
+		"from_version": "^1.9.0",
+	})
+	testutils.AssertNoError(t, err)
+
+	testutils.AssertTrue(t, strings.Contains(output, "Repository: https://github.com/acme/widget"))
+	testutils.AssertTrue(t, strings.Contains(output, "Range: 1.9.0 → latest (2 releases)"))
+	testutils.AssertTrue(t, strings.Contains(output, "## Breaking changes (3)"))
+	testutils.AssertTrue(t, strings.Contains(output, "- **2.0.0**: Drop Node 18"))
+	testutils.AssertTrue(t, strings.Contains(output, "- **2.0.0**: feat!: config is now async"))
+	testutils.AssertTrue(t, strings.Contains(output, "- **2.1.0**: Deprecate Client.fetch in favour of Client.get"))
+	testutils.AssertTrue(t, strings.Contains(output, "- **2.1.0**: Fix CVE-2026-1234 in header parsing"))
+	testutils.AssertTrue(t, strings.Contains(output, "- Add streaming API\n"))
+	testutils.AssertTrue(t, strings.Contains(output, "### 2.1.0 (2026-02-01)"))
+
+	// Prereleases, drafts and versions outside the range are excluded
+	testutils.AssertFalse(t, strings.Contains(output, "3.0.0"))
+	testutils.AssertFalse(t, strings.Contains(output, "Old change"))
+	testutils.AssertFalse(t, strings.Contains(output, "Draft"))
+	testutils.AssertFalse(t, strings.Contains(output, "Full Changelog"))
+}
+
+func TestReleaseNotesTool_ChangelogFallback(t *testing.T) {
+	client := &releaseNotesHTTPClient{responses: map[string]string{
+		"https://api.github.com/repos/acme/exporter/releases":             `[]`,
+		"https://raw.githubusercontent.com/acme/exporter/HEAD/CHANGES.md": releaseNotesChangelog,
+	}}
+
+	output, err := runReleaseNotes(t, client, map[string]any{
+		"ecosystem":    "github",
+		"package":      "acme/exporter",
+		"from_version": "1.0.0",
+		"to_version":   "v1.2.0",
+	})
+	testutils.AssertNoError(t, err)
+
+	testutils.AssertTrue(t, strings.Contains(output, "Source: Changelog (https://github.com/acme/exporter/blob/HEAD/CHANGES.md)"))
+	testutils.AssertTrue(t, strings.Contains(output, "- **1.2.0**: The legacy exporter"))
+	testutils.AssertTrue(t, strings.Contains(output, "## Security (1)"))
+	testutils.AssertTrue(t, strings.Contains(output, "### 1.2.0 (2026-02-10)"))
+	testutils.AssertTrue(t, strings.Contains(output, "- CSV output"))
+	testutils.AssertFalse(t, strings.Contains(output, "Work in progress"))
+	testutils.AssertFalse(t, strings.Contains(output, "Initial release"))
+}
+
+func TestReleaseNotesTool_Validation(t *testing.T) {
+	client := &releaseNotesHTTPClient{responses: map[string]string{
+		"https://pypi.org/pypi/internal-lib/json": `{"info": {"home_page": "https://git.example.com/internal-lib", "project_urls": {}}}`,
+	}}
+
+	_, err := runReleaseNotes(t, client, map[string]any{"ecosystem": "github", "package": "acme/widget", "from_version": "2.0.0", "to_version": "1.0.0"})
+	testutils.AssertErrorContains(t, err, "must be older than")
+
+	_, err = runReleaseNotes(t, client, map[string]any{"ecosystem": "github", "package": "not a repo"})
+	testutils.AssertErrorContains(t, err, "invalid package name")
+
+	_, err = runReleaseNotes(t, client, map[string]any{"ecosystem": "python", "package": "internal-lib"})
+	testutils.AssertErrorContains(t, err, "no GitHub repository found")
+}
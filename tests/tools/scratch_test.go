@@ -0,0 +1,79 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestScratch_PerSessionDirectoriesAreRemovedWhenSessionsEnd(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	tools.CleanupScratch()
+	t.Cleanup(tools.CleanupScratch)
+
+	hooks := &mcpserver.Hooks{}
+	srv := mcpserver.NewMCPServer("test", "1.0", mcpserver.WithHooks(hooks))
+	tools.RegisterScratch(hooks)
+
+	sessionA := mcpserver.NewInProcessSession("scratch-a", nil)
+	sessionB := mcpserver.NewInProcessSession("scratch-b", nil)
+	testutils.AssertNoError(t, srv.RegisterSession(context.Background(), sessionA))
+	testutils.AssertNoError(t, srv.RegisterSession(context.Background(), sessionB))
+	ctxA := srv.WithContext(context.Background(), sessionA)
+	ctxB := srv.WithContext(context.Background(), sessionB)
+
+	dirA, err := tools.ScratchDir(ctxA)
+	testutils.AssertNoError(t, err)
+	dirB, err := tools.ScratchDir(ctxB)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, dirA != dirB)
+
+	file, err := tools.ScratchFile(ctxA, "intermediate-*.html")
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, file.Close())
+	testutils.AssertEqual(t, dirA, filepath.Dir(file.Name()))
+
+	subdir, err := tools.ScratchSubdir(ctxB, "work-*")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, dirB, filepath.Dir(subdir))
+
+	// Ending a session removes only its own directory
+	srv.UnregisterSession(context.Background(), "scratch-a")
+	_, err = os.Stat(dirA)
+	testutils.AssertTrue(t, os.IsNotExist(err))
+	_, err = os.Stat(subdir)
+	testutils.AssertNoError(t, err)
+
+	// Shutting down removes the rest
+	tools.CleanupScratch()
+	_, err = os.Stat(dirB)
+	testutils.AssertTrue(t, os.IsNotExist(err))
+}
+
+func TestScratch_EnforcesQuota(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv(tools.ScratchQuotaEnvVar, "1")
+	tools.CleanupScratch()
+	t.Cleanup(tools.CleanupScratch)
+
+	ctx := context.Background()
+	file, err := tools.ScratchFile(ctx, "large-*")
+	testutils.AssertNoError(t, err)
+	_, err = file.Write(make([]byte, 1024*1024))
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, file.Close())
+
+	_, err = tools.ScratchFile(ctx, "more-*")
+	testutils.AssertTrue(t, errors.Is(err, tools.ErrScratchQuotaExceeded))
+
+	// Freeing space makes the directory usable again
+	testutils.AssertNoError(t, os.Remove(file.Name()))
+	_, err = tools.ScratchDir(ctx)
+	testutils.AssertNoError(t, err)
+}
@@ -0,0 +1,127 @@
+package tools_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/screenshot"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// fakeBrowser writes a script that copies a fixed PNG to the --screenshot path,
+// standing in for headless Chromium. It records its arguments, one per line, in a
+// file named args next to the script.
+func fakeBrowser(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake browser script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "fixture.png")
+	f, err := os.Create(pngPath)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, 32, 24))))
+	_ = f.Close()
+
+	script := filepath.Join(dir, "chromium")
+	content := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$@\" > \"$(dirname \"$0\")/args\"\nfor arg in \"$@\"; do\n  case \"$arg\" in\n    --screenshot=*) cp %q \"${arg#--screenshot=}\" ;;\n  esac\ndone\n", pngPath)
+	testutils.AssertNoError(t, os.WriteFile(script, []byte(content), 0700))
+	return script
+}
+
+func TestScreenshotTool_Definition(t *testing.T) {
+	tool := &screenshot.ScreenshotTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "screenshot", definition.Name)
+	testutils.AssertTrue(t, testutils.Contains(definition.Description, "headless Chromium"))
+}
+
+func TestScreenshotTool_InlineAndSaved(t *testing.T) {
+	t.Setenv(screenshot.BrowserPathEnvVar, fakeBrowser(t))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("<html><body>Hello</body></html>"))
+	}))
+	defer server.Close()
+
+	tool := &screenshot.ScreenshotTool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+
+	result, err := tool.Execute(ctx, logger, cache, map[string]any{"url": server.URL + "/old"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(result.Content))
+	imageContent, ok := result.Content[1].(mcp.ImageContent)
+	if !ok {
+		t.Fatal("Expected ImageContent")
+	}
+	testutils.AssertEqual(t, "image/png", imageContent.MIMEType)
+	decoded, err := base64.StdEncoding.DecodeString(imageContent.Data)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, len(decoded) > 0)
+
+	output := filepath.Join(t.TempDir(), "shots", "page.png")
+	result, err = tool.Execute(ctx, logger, cache, map[string]any{"url": server.URL + "/old", "output_path": output})
+	testutils.AssertNoError(t, err)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	var response screenshot.ScreenshotResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	testutils.AssertEqual(t, output, response.OutputPath)
+	testutils.AssertEqual(t, server.URL+"/new", response.FinalURL)
+	testutils.AssertEqual(t, 32, response.Width)
+
+	// The browser's own traffic goes through the policy-checked local proxy
+	args, err := os.ReadFile(filepath.Join(filepath.Dir(os.Getenv(screenshot.BrowserPathEnvVar)), "args"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(args), "--proxy-server=http://127.0.0.1:"))
+	testutils.AssertTrue(t, strings.Contains(string(args), "--proxy-bypass-list=<-loopback>\n"))
+	testutils.AssertTrue(t, strings.Contains(string(args), "--host-resolver-rules=MAP * ~NOTFOUND , EXCLUDE 127.0.0.1\n"))
+
+	info, err := os.Stat(output)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"url": server.URL, "output_path": output})
+	testutils.AssertErrorContains(t, err, "already exists")
+}
+
+func TestScreenshotTool_Validation(t *testing.T) {
+	tool := &screenshot.ScreenshotTool{}
+	ctx := testutils.CreateTestContext()
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"url": "file:///etc/passwd"})
+	testutils.AssertErrorContains(t, err, "unsupported URL scheme")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"url": "https://example.com", "width": float64(10000)})
+	testutils.AssertErrorContains(t, err, "width and height must be between")
+
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"url": "https://example.com", "output_path": "shot.png"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	t.Setenv(screenshot.BrowserPathEnvVar, filepath.Join(t.TempDir(), "missing-browser"))
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"url": "https://example.com"})
+	testutils.AssertErrorContains(t, err, screenshot.BrowserPathEnvVar)
+}
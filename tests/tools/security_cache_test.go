@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := security.NewCache(2, time.Hour)
+
+	cache.Set("a", &security.SecurityResult{Action: security.ActionAllow})
+	cache.Set("b", &security.SecurityResult{Action: security.ActionWarn})
+	_, found := cache.Get("a") // a is now more recently used than b
+	require.True(t, found)
+	cache.Set("c", &security.SecurityResult{Action: security.ActionBlock})
+
+	_, found = cache.Get("b")
+	assert.False(t, found, "least recently used entry should be evicted")
+	_, found = cache.Get("a")
+	assert.True(t, found)
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
+
+func TestSecurityCache_Expiry(t *testing.T) {
+	cache := security.NewCache(10, 10*time.Millisecond)
+	cache.Set("a", &security.SecurityResult{Action: security.ActionAllow})
+
+	time.Sleep(20 * time.Millisecond)
+	_, found := cache.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestSecurityCache_ResultsAreCopies(t *testing.T) {
+	cache := security.NewCache(10, time.Hour)
+	original := &security.SecurityResult{
+		Action:   security.ActionWarn,
+		Message:  "Security Warning",
+		Analysis: &security.ThreatAnalysis{RiskFactors: []string{"prompt injection"}},
+	}
+	cache.Set("a", original)
+
+	// Changing the stored or returned result must not change what is cached
+	original.Action = security.ActionAllow
+	cached, found := cache.Get("a")
+	require.True(t, found)
+	cached.Message = "tampered"
+	cached.Analysis.RiskFactors[0] = "tampered"
+
+	cached, found = cache.Get("a")
+	require.True(t, found)
+	assert.Equal(t, security.ActionWarn, cached.Action)
+	assert.Equal(t, "Security Warning", cached.Message)
+	assert.Equal(t, "prompt injection", cached.Analysis.RiskFactors[0])
+}
+
+func TestSecurityCache_Clear(t *testing.T) {
+	cache := security.NewCache(10, time.Hour)
+	cache.Set("a", &security.SecurityResult{Action: security.ActionWarn, ID: "sec_warn_1"})
+
+	cache.Clear()
+	_, found := cache.Get("a")
+	assert.False(t, found, "results cached before the cache was cleared must not be reused")
+
+	// Entries stored after clearing are served as normal
+	cache.Set("a", &security.SecurityResult{Action: security.ActionWarn, ID: "sec_warn_2"})
+	cached, found := cache.Get("a")
+	require.True(t, found)
+	assert.Equal(t, "sec_warn_2", cached.ID)
+}
+
+func TestSecurityCache_KeyIncludesSource(t *testing.T) {
+	content := "same content"
+	fetch := security.SourceContext{URL: "https://example.com/a", Domain: "example.com", Tool: "fetch_url"}
+	search := security.SourceContext{URL: "https://example.com/a", Domain: "example.com", Tool: "internet_search"}
+
+	assert.Equal(t, security.GenerateCacheKey(content, fetch), security.GenerateCacheKey(content, fetch))
+	assert.NotEqual(t, security.GenerateCacheKey(content, fetch), security.GenerateCacheKey(content, search))
+	// Field boundaries are part of the key
+	assert.NotEqual(t,
+		security.GenerateCacheKey("ab", security.SourceContext{URL: "c"}),
+		security.GenerateCacheKey("a", security.SourceContext{URL: "bc"}))
+}
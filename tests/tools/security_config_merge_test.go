@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const mergeBaseConfig = `version: "1.0"
+settings:
+  enabled: true
+  max_scan_size: 512
+  threat_threshold: 0.7
+trusted_domains:
+  - github.com
+rules:
+  shell_injection:
+    description: "Shell injection"
+    patterns:
+      - contains: "curl | sh"
+    action: warn
+  old_rule:
+    description: "Old rule"
+    patterns:
+      - contains: "old"
+    action: warn
+  dropped_by_user:
+    description: "Rule the user deleted"
+    patterns:
+      - contains: "dropped"
+    action: warn
+`
+
+// The user tightened the threshold, changed an action, added a rule and deleted a default
+const mergeUserConfig = `# My security settings
+version: "1.0"
+settings:
+  enabled: true
+  max_scan_size: 512
+  threat_threshold: 0.5 # stricter than the default
+trusted_domains:
+  - github.com
+rules:
+  shell_injection:
+    description: "Shell injection"
+    patterns:
+      - contains: "curl | sh"
+    action: block
+  old_rule:
+    description: "Old rule"
+    patterns:
+      - contains: "old"
+    action: warn
+  my_rule:
+    description: "My own rule"
+    patterns:
+      - contains: "internal.example.com"
+    action: block
+`
+
+// The new default changes the threshold and scan size, adds a rule and a section, and drops old_rule
+const mergeLatestConfig = `version: "1.0"
+settings:
+  enabled: true
+  max_scan_size: 1024
+  threat_threshold: 0.8
+trusted_domains:
+  - github.com
+  - pkg.go.dev
+rules:
+  shell_injection:
+    description: "Shell injection"
+    patterns:
+      - contains: "curl | sh"
+      - contains: "wget | sh"
+    action: warn
+  dropped_by_user:
+    description: "Rule the user deleted"
+    patterns:
+      - contains: "dropped"
+    action: warn
+  new_rule:
+    description: "New rule"
+    patterns:
+      - contains: "new"
+    action: warn
+prompt_injection:
+  action: warn
+`
+
+func TestSecurityConfigMerge_ThreeWay(t *testing.T) {
+	merged, report, err := security.MergeConfig([]byte(mergeBaseConfig), []byte(mergeUserConfig), []byte(mergeLatestConfig))
+	require.NoError(t, err)
+
+	rules, err := security.ValidateSecurityConfig(merged)
+	require.NoError(t, err)
+
+	// Values the user did not change follow the new default
+	assert.Equal(t, 1024, rules.Settings.MaxScanSize)
+	assert.Equal(t, []string{"github.com", "pkg.go.dev"}, rules.TrustedDomains)
+	// User changes are kept
+	assert.InDelta(t, 0.5, rules.Settings.ThreatThreshold, 0.001)
+	assert.Equal(t, "block", rules.Rules["shell_injection"].Action)
+	assert.Contains(t, rules.Rules, "my_rule")
+	// New defaults are added, but not ones the user deleted
+	assert.Contains(t, rules.Rules, "new_rule")
+	assert.NotContains(t, rules.Rules, "dropped_by_user")
+	assert.Equal(t, "warn", rules.Injection.Action)
+	// Removed defaults are kept and marked
+	assert.Contains(t, rules.Rules, "old_rule")
+	assert.Contains(t, string(merged), "# old_rule is no longer in the default configuration")
+
+	assert.True(t, report.HasBase)
+	assert.ElementsMatch(t, []string{"rules.new_rule", "prompt_injection"}, report.Added)
+	assert.ElementsMatch(t, []string{"settings.max_scan_size", "trusted_domains", "rules.shell_injection.patterns"}, report.Updated)
+	// Only user changes where the default also moved are reported
+	assert.Equal(t, []string{"settings.threat_threshold"}, report.Kept)
+	assert.Equal(t, []string{"rules.old_rule"}, report.Removed)
+	assert.Equal(t, []string{"rules.dropped_by_user"}, report.Declined)
+
+	// Comments are preserved and the summary is written at the top
+	text := string(merged)
+	assert.True(t, strings.HasPrefix(text, "# --- security-config-diff --update summary ---"))
+	assert.Contains(t, text, "# My security settings")
+	assert.Contains(t, text, "# stricter than the default")
+	assert.Contains(t, text, "Added new defaults: rules.new_rule, prompt_injection")
+}
+
+func TestSecurityConfigMerge_WithoutBase(t *testing.T) {
+	merged, report, err := security.MergeConfig(nil, []byte(mergeUserConfig), []byte(mergeLatestConfig))
+	require.NoError(t, err)
+
+	rules, err := security.ValidateSecurityConfig(merged)
+	require.NoError(t, err)
+
+	// Without the previous default every difference is treated as the user's change
+	assert.False(t, report.HasBase)
+	assert.Equal(t, 512, rules.Settings.MaxScanSize)
+	assert.Contains(t, rules.Rules, "new_rule")
+	assert.Contains(t, rules.Rules, "dropped_by_user")
+	assert.Contains(t, rules.Rules, "old_rule")
+	assert.Empty(t, report.Removed)
+	assert.Contains(t, report.Lines()[len(report.Lines())-1], "No record of the previous default")
+}
+
+func TestSecurityConfigMerge_RepeatedMergeReplacesSummary(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "security.yaml")
+	require.NoError(t, os.WriteFile(security.BaseConfigPath(configPath), []byte(mergeBaseConfig), 0600))
+
+	merged, _, err := security.MergeConfigUpdate(configPath, []byte(mergeUserConfig), mergeLatestConfig)
+	require.NoError(t, err)
+	saved, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(merged), string(saved))
+
+	// The new default becomes the base, so merging again changes nothing
+	base, err := os.ReadFile(security.BaseConfigPath(configPath))
+	require.NoError(t, err)
+	assert.Equal(t, mergeLatestConfig, string(base))
+
+	again, report, err := security.MergeConfigUpdate(configPath, merged, mergeLatestConfig)
+	require.NoError(t, err)
+	assert.False(t, report.Changed())
+	assert.Equal(t, 1, strings.Count(string(again), "# --- security-config-diff --update summary ---"))
+
+	var first, second map[string]any
+	require.NoError(t, yaml.Unmarshal(merged, &first))
+	require.NoError(t, yaml.Unmarshal(again, &second))
+	assert.Equal(t, first, second)
+}
+
+func TestSecurityConfigMerge_KeepsBaseWhenSaveFails(t *testing.T) {
+	dir := t.TempDir()
+	// A directory where the config file should be stops the merged config being saved
+	configPath := filepath.Join(dir, "security.yaml")
+	require.NoError(t, os.Mkdir(configPath, 0700))
+	require.NoError(t, os.WriteFile(security.BaseConfigPath(configPath), []byte(mergeBaseConfig), 0600))
+
+	_, _, err := security.MergeConfigUpdate(configPath, []byte(mergeUserConfig), mergeLatestConfig)
+	require.Error(t, err)
+
+	// The user config still matches the old default, so the old default stays its base
+	base, err := os.ReadFile(security.BaseConfigPath(configPath))
+	require.NoError(t, err)
+	assert.Equal(t, mergeBaseConfig, string(base))
+}
@@ -0,0 +1,205 @@
+package tools_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/stackexchange"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// stackExchangeHTTPClient serves canned API responses by path, gzipped as the API sends them
+type stackExchangeHTTPClient struct {
+	responses map[string]string
+	requested []*http.Request
+}
+
+func (c *stackExchangeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req)
+	body, ok := c.responses[strings.TrimPrefix(req.URL.Path, "/2.3/")]
+	status := http.StatusOK
+	if !ok {
+		body = `{"error_id": 404, "error_name": "no_method", "error_message": "no such method"}`
+		status = http.StatusNotFound
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte(body))
+	_ = writer.Close()
+	return &http.Response{StatusCode: status, Body: io.NopCloser(&compressed), Header: http.Header{"Content-Encoding": {"gzip"}}}, nil
+}
+
+const stackExchangeQuestions = `{"items": [
+	{"question_id": 1, "title": "How do I stop a goroutine &amp; wait?", "link": "https://stackoverflow.com/questions/1/how-do-i-stop-a-goroutine",
+	 "score": 42, "answer_count": 3, "view_count": 1000, "accepted_answer_id": 13, "tags": ["go", "goroutine"], "creation_date": 1700000000,
+	 "body": "<p>I start a <code>goroutine</code> and need to stop it.</p>", "owner": {"display_name": "Jo &amp; Sam"}},
+	{"question_id": 2, "title": "Unanswered question", "link": "https://stackoverflow.com/questions/2/unanswered",
+	 "score": 0, "answer_count": 0, "view_count": 5, "tags": ["go"], "creation_date": 1700000000, "body": "<p>Nobody knows.</p>", "owner": {}}
+], "has_more": true, "quota_max": 300, "quota_remaining": 297}`
+
+const stackExchangeAnswers = `{"items": [
+	{"answer_id": 11, "question_id": 1, "score": 90, "is_accepted": false, "creation_date": 1700000100, "body": "<p>Use a context.</p><pre><code>ctx, cancel := context.WithCancel(ctx)</code></pre>", "owner": {"display_name": "Ana"}},
+	{"answer_id": 12, "question_id": 1, "score": 10, "is_accepted": false, "creation_date": 1700000200, "body": "<p>Close a channel.</p>", "owner": {"display_name": "Ben"}}
+], "has_more": false, "quota_max": 300, "quota_remaining": 296}`
+
+const stackExchangeAccepted = `{"items": [
+	{"answer_id": 13, "question_id": 1, "score": 2, "is_accepted": true, "creation_date": 1700000300, "body": "<p>Use a done channel.</p>", "owner": {"display_name": "Cy"}}
+], "has_more": false, "quota_max": 300, "quota_remaining": 295}`
+
+func runStackExchange(t *testing.T, tool *stackexchange.StackExchangeTool, args map[string]any) (string, error) {
+	t.Helper()
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestStackExchangeTool_Definition(t *testing.T) {
+	tool := &stackexchange.StackExchangeTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "stackexchange", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, !*definition.Annotations.DestructiveHint)
+	testutils.AssertTrue(t, *definition.Annotations.OpenWorldHint)
+}
+
+func TestStackExchangeTool_Validation(t *testing.T) {
+	client := &stackExchangeHTTPClient{}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	_, err := runStackExchange(t, tool, map[string]any{})
+	testutils.AssertErrorContains(t, err, "missing required parameter: query")
+
+	_, err = runStackExchange(t, tool, map[string]any{"query": "goroutine", "site": "../admin"})
+	testutils.AssertErrorContains(t, err, "invalid site")
+
+	_, err = runStackExchange(t, tool, map[string]any{"query": "goroutine", "sort": "random"})
+	testutils.AssertErrorContains(t, err, "sort must be one of")
+
+	_, err = runStackExchange(t, tool, map[string]any{"query": "goroutine", "limit": float64(50)})
+	testutils.AssertErrorContains(t, err, "limit must be a whole number from 1 to 20")
+
+	_, err = runStackExchange(t, tool, map[string]any{"question_id": float64(-1)})
+	testutils.AssertErrorContains(t, err, "question_id must be a positive whole number")
+
+	testutils.AssertEqual(t, 0, len(client.requested))
+}
+
+func TestStackExchangeTool_Search(t *testing.T) {
+	t.Setenv("STACKEXCHANGE_API_KEY", "test-key")
+	client := &stackExchangeHTTPClient{responses: map[string]string{
+		"search/advanced":     stackExchangeQuestions,
+		"questions/1/answers": stackExchangeAnswers,
+		"answers/13":          stackExchangeAccepted,
+	}}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	text, err := runStackExchange(t, tool, map[string]any{
+		"query":         "stop goroutine",
+		"tags":          []any{"Go", " goroutine "},
+		"accepted_only": true,
+	})
+	testutils.AssertNoError(t, err)
+
+	// Answers are only fetched for questions that have any, and the accepted answer is fetched by id
+	testutils.AssertEqual(t, 3, len(client.requested))
+	search := client.requested[0].URL.Query()
+	testutils.AssertEqual(t, "stop goroutine", search.Get("q"))
+	testutils.AssertEqual(t, "go;goroutine", search.Get("tagged"))
+	testutils.AssertEqual(t, "True", search.Get("accepted"))
+	testutils.AssertEqual(t, "stackoverflow", search.Get("site"))
+	testutils.AssertEqual(t, "test-key", search.Get("key"))
+	testutils.AssertEqual(t, "/2.3/questions/1/answers", client.requested[1].URL.Path)
+	testutils.AssertEqual(t, "/2.3/answers/13", client.requested[2].URL.Path)
+
+	testutils.AssertTrue(t, strings.HasPrefix(text, "Found 2 questions on stackoverflow for: stop goroutine (more available)\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "# 1. How do I stop a goroutine & wait?"))
+	testutils.AssertTrue(t, strings.Contains(text, "asked 2023-11-14 by Jo & Sam · tags: go, goroutine"))
+	testutils.AssertTrue(t, strings.Contains(text, "I start a `goroutine` and need to stop it."))
+
+	// The accepted answer comes first despite its score, then the most voted, up to two per question
+	accepted := strings.Index(text, "## Accepted answer (score 2, by Cy, 2023-11-14)\n\nhttps://stackoverflow.com/a/13")
+	top := strings.Index(text, "## Answer (score 90, by Ana")
+	testutils.AssertTrue(t, accepted > 0 && top > accepted)
+	testutils.AssertTrue(t, strings.Contains(text, "ctx, cancel := context.WithCancel(ctx)"))
+	testutils.AssertTrue(t, !strings.Contains(text, "Close a channel."))
+
+	testutils.AssertTrue(t, strings.Contains(text, "# 2. Unanswered question"))
+	testutils.AssertTrue(t, strings.Contains(text, "by a deleted user"))
+	testutils.AssertTrue(t, strings.Contains(text, "_No answers yet._"))
+	testutils.AssertTrue(t, strings.Contains(text, "CC BY-SA"))
+	testutils.AssertTrue(t, strings.HasSuffix(text, "API quota: 295 of 300 requests left today\n"))
+}
+
+func TestStackExchangeTool_QuestionByID(t *testing.T) {
+	t.Setenv("STACKEXCHANGE_API_KEY", "test-key")
+	client := &stackExchangeHTTPClient{responses: map[string]string{
+		"questions/1":         stackExchangeQuestions,
+		"questions/1/answers": stackExchangeAnswers,
+		"answers/13":          stackExchangeAccepted,
+	}}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	text, err := runStackExchange(t, tool, map[string]any{"question_id": float64(1), "answers_per_question": float64(1), "max_body_length": float64(200)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# How do I stop a goroutine & wait?\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "## Accepted answer"))
+	testutils.AssertTrue(t, !strings.Contains(text, "Use a context."))
+}
+
+func TestStackExchangeTool_Truncation(t *testing.T) {
+	t.Setenv("STACKEXCHANGE_API_KEY", "test-key")
+	long := strings.Repeat("<p>A long paragraph about goroutines and channels.</p>", 20)
+	client := &stackExchangeHTTPClient{responses: map[string]string{
+		"questions/3": `{"items": [{"question_id": 3, "title": "Long", "link": "https://stackoverflow.com/questions/3/long", "answer_count": 0, "body": "` + long + `"}]}`,
+	}}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	text, err := runStackExchange(t, tool, map[string]any{"question_id": float64(3), "max_body_length": float64(300)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(text, "[Truncated: showing"))
+	testutils.AssertTrue(t, strings.Contains(text, "Full post: https://stackoverflow.com/questions/3/long]"))
+}
+
+func TestStackExchangeTool_Errors(t *testing.T) {
+	t.Setenv("STACKEXCHANGE_API_KEY", "test-key")
+	client := &stackExchangeHTTPClient{responses: map[string]string{
+		"search/advanced": `{"error_id": 502, "error_name": "throttle_violation", "error_message": "too many requests from this IP"}`,
+		"questions/4":     `{"items": [], "quota_max": 300, "quota_remaining": 290}`,
+	}}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	_, err := runStackExchange(t, tool, map[string]any{"query": "goroutine"})
+	testutils.AssertErrorContains(t, err, "error 502 (throttle_violation): too many requests from this IP")
+
+	_, err = runStackExchange(t, tool, map[string]any{"question_id": float64(4)})
+	testutils.AssertErrorContains(t, err, "question 4 not found on stackoverflow")
+}
+
+func TestStackExchangeTool_Backoff(t *testing.T) {
+	t.Setenv("STACKEXCHANGE_API_KEY", "test-key")
+	client := &stackExchangeHTTPClient{responses: map[string]string{
+		"search/advanced": `{"items": [], "backoff": 60, "quota_max": 300, "quota_remaining": 290}`,
+	}}
+	tool := stackexchange.NewStackExchangeTool(client)
+
+	text, err := runStackExchange(t, tool, map[string]any{"query": "goroutine"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "No questions found on stackoverflow for: goroutine\n"))
+
+	// The API asked for a minute's pause, which is too long to wait for
+	_, err = runStackExchange(t, tool, map[string]any{"query": "channels"})
+	testutils.AssertErrorContains(t, err, "asked for no more search/advanced requests")
+	testutils.AssertEqual(t, 1, len(client.requested))
+}
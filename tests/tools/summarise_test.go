@@ -0,0 +1,91 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+var fullOutputPattern = regexp.MustCompile(`Full output: (\S+)`)
+
+// summarise returns the summary of an output with summarisation enabled and a small page size
+func summarise(t *testing.T, output string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(tools.OutputSummariseEnvVar, "true")
+	t.Setenv(tools.OutputPageSizeEnvVar, "2000")
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), mcpserver.NewInProcessSession("summarise", nil))
+	return pageText(t, tools.LimitOutput(ctx, "excel", mcp.NewToolResultText(output)))
+}
+
+func TestSummarise_CSV(t *testing.T) {
+	rows := []string{"id,region,units"}
+	for i := range 500 {
+		rows = append(rows, fmt.Sprintf("%d,EMEA,%d", i, i*2))
+	}
+	full := strings.Join(rows, "\n")
+
+	summary := summarise(t, full)
+	testutils.AssertTrue(t, len(summary) <= 2000)
+	testutils.AssertTrue(t, strings.Contains(summary, "Format: CSV, 501 rows (including the header) by 3 columns"))
+	testutils.AssertTrue(t, strings.Contains(summary, "Columns: id, region, units"))
+	testutils.AssertTrue(t, strings.Contains(summary, "0,EMEA,0\n"))
+	testutils.AssertTrue(t, strings.Contains(summary, "Last row:\n499,EMEA,998"))
+	testutils.AssertFalse(t, strings.Contains(summary, "250,EMEA,500"))
+
+	// The full output is saved privately for other tools to read
+	matches := fullOutputPattern.FindStringSubmatch(summary)
+	testutils.AssertEqual(t, 2, len(matches))
+	saved, err := os.ReadFile(matches[1])
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, full, string(saved))
+	info, err := os.Stat(matches[1])
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestSummarise_JSON(t *testing.T) {
+	items := make([]string, 0, 100)
+	for i := range 100 {
+		items = append(items, fmt.Sprintf(`{"name":"file%d.go","size":%d}`, i, i*100))
+	}
+	full := fmt.Sprintf(`{"root":"/repo","files":[%s]}`, strings.Join(items, ","))
+
+	summary := summarise(t, full)
+	testutils.AssertTrue(t, strings.Contains(summary, "Format: JSON object with 2 keys"))
+	testutils.AssertTrue(t, strings.Contains(summary, "- files: array of 100 objects with keys name, size"))
+	testutils.AssertTrue(t, strings.Contains(summary, `- root: "/repo"`))
+	testutils.AssertTrue(t, strings.Contains(summary, `[0] {"name":"file0.go","size":0}`))
+	testutils.AssertTrue(t, strings.Contains(summary, `[99] {"name":"file99.go","size":9900}`))
+}
+
+func TestSummarise_Markdown(t *testing.T) {
+	var doc strings.Builder
+	for i := range 20 {
+		fmt.Fprintf(&doc, "## Section %d\n\n%s\n\n", i, strings.Repeat("Some body text. ", 20))
+	}
+
+	summary := summarise(t, "# Guide\n\n"+doc.String())
+	testutils.AssertTrue(t, strings.Contains(summary, "Format: text"))
+	testutils.AssertTrue(t, strings.Contains(summary, "Headings (21):\n# Guide\n## Section 0\n"))
+	testutils.AssertTrue(t, strings.Contains(summary, "## Section 19\n"))
+}
+
+func TestSummarise_LeavesSmallAndErrorResults(t *testing.T) {
+	t.Setenv(tools.OutputSummariseEnvVar, "true")
+	t.Setenv(tools.OutputPageSizeEnvVar, "100")
+
+	small := mcp.NewToolResultText("short")
+	testutils.AssertTrue(t, tools.LimitOutput(context.Background(), "excel", small) == small)
+
+	failed := mcp.NewToolResultError(strings.Repeat("x", 500))
+	testutils.AssertTrue(t, tools.LimitOutput(context.Background(), "excel", failed) == failed)
+}
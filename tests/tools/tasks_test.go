@@ -0,0 +1,109 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/tasks"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runTasks calls the tasks tool and decodes its JSON response into out
+func runTasks(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&tasks.TasksTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+func TestTasks_AddUpdateAndList(t *testing.T) {
+	projectDir := t.TempDir()
+
+	var first, second tasks.TaskResponse
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "add", "project_dir": projectDir, "title": "Add retry middleware"}, &first))
+	testutils.AssertEqual(t, 1, first.Task.ID)
+	testutils.AssertEqual(t, tasks.StatusPending, first.Task.Status)
+	testutils.AssertEqual(t, filepath.Join(projectDir, ".mcp-devtools", "tasks.json"), first.File)
+
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "add", "project_dir": projectDir, "title": "Test retry middleware", "depends_on": []any{float64(1)}}, &second))
+	testutils.AssertEqual(t, 2, second.Task.ID)
+	testutils.AssertEqual(t, 1, len(second.Task.BlockedBy))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(first.File)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// A task cannot start until its dependencies are finished
+	var updated tasks.TaskResponse
+	err := runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(2), "status": "in_progress"}, &updated)
+	testutils.AssertErrorContains(t, err, "until tasks [1] are done or cancelled")
+
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(1), "status": "done"}, &updated))
+	testutils.AssertEqual(t, tasks.StatusDone, updated.Task.Status)
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(2), "status": "in_progress"}, &updated))
+	testutils.AssertEqual(t, 0, len(updated.Task.BlockedBy))
+
+	var listed tasks.ListResponse
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "list", "project_dir": projectDir, "status": "in_progress"}, &listed))
+	testutils.AssertEqual(t, 1, len(listed.Tasks))
+	testutils.AssertEqual(t, "Test retry middleware", listed.Tasks[0].Title)
+	testutils.AssertEqual(t, 1, listed.Counts[tasks.StatusDone])
+	testutils.AssertEqual(t, 1, listed.Counts[tasks.StatusInProgress])
+}
+
+func TestTasks_RejectsInvalidDependencies(t *testing.T) {
+	projectDir := t.TempDir()
+
+	var response tasks.TaskResponse
+	err := runTasks(t, map[string]any{"action": "add", "project_dir": projectDir, "title": "Deploy", "depends_on": []any{float64(3)}}, &response)
+	testutils.AssertErrorContains(t, err, "task 3, which does not exist")
+
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "add", "project_dir": projectDir, "title": "Build"}, &response))
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "add", "project_dir": projectDir, "title": "Deploy", "depends_on": []any{float64(1)}}, &response))
+
+	err = runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(1), "depends_on": []any{float64(2)}}, &response)
+	testutils.AssertErrorContains(t, err, "already depends on it")
+	err = runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(1), "depends_on": []any{float64(1)}}, &response)
+	testutils.AssertErrorContains(t, err, "cannot depend on itself")
+
+	// Failed updates leave the task list unchanged
+	var listed tasks.ListResponse
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "list", "project_dir": projectDir}, &listed))
+	testutils.AssertEqual(t, 2, len(listed.Tasks))
+	testutils.AssertEqual(t, 0, len(listed.Tasks[0].DependsOn))
+}
+
+func TestTasks_ValidatesParameters(t *testing.T) {
+	projectDir := t.TempDir()
+	var response tasks.TaskResponse
+
+	err := runTasks(t, map[string]any{"action": "add", "project_dir": projectDir}, &response)
+	testutils.AssertErrorContains(t, err, "missing required parameter 'title'")
+
+	err = runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(1)}, &response)
+	testutils.AssertErrorContains(t, err, "nothing to update")
+
+	err = runTasks(t, map[string]any{"action": "update", "project_dir": projectDir, "id": float64(1), "status": "finished"}, &response)
+	testutils.AssertErrorContains(t, err, "invalid 'status' parameter")
+
+	err = runTasks(t, map[string]any{"action": "list", "project_dir": "relative/path"}, &response)
+	testutils.AssertErrorContains(t, err, "must be an absolute path")
+
+	// Listing a project without tasks does not create the task file
+	var listed tasks.ListResponse
+	testutils.AssertNoError(t, runTasks(t, map[string]any{"action": "list", "project_dir": projectDir}, &listed))
+	testutils.AssertEqual(t, 0, len(listed.Tasks))
+	_, err = os.Stat(filepath.Join(projectDir, ".mcp-devtools"))
+	testutils.AssertTrue(t, os.IsNotExist(err))
+}
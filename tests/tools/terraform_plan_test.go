@@ -0,0 +1,196 @@
+package tools_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/terraform"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const terraformPlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.5",
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "change": {
+        "actions": ["update"],
+        "before": {"instance_type": "t3.small", "ami": "ami-1", "tags": {"Environment": "dev", "Name": "web"}},
+        "after": {"instance_type": "t3.large", "ami": "ami-1", "tags": {"Environment": "staging", "Name": "web"}},
+        "after_unknown": {}
+      }
+    },
+    {
+      "address": "aws_db_instance.main",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "action_reason": "replace_because_cannot_update",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"engine_version": "15.4", "instance_class": "db.t3.medium", "password": "hunter2", "id": "db-1"},
+        "after": {"engine_version": "16.2", "instance_class": "db.t3.medium", "password": "hunter3"},
+        "after_unknown": {"id": true},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true},
+        "replace_paths": [["engine_version"]]
+      }
+    },
+    {
+      "address": "module.network.aws_nat_gateway.this[0]",
+      "module_address": "module.network",
+      "mode": "managed",
+      "type": "aws_nat_gateway",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"connectivity_type": "public"}, "after_unknown": {"id": true}}
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["no-op"], "before": {"bucket": "logs"}, "after": {"bucket": "logs"}}
+    }
+  ],
+  "resource_drift": [
+    {
+      "address": "aws_security_group.web",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "web",
+      "change": {"actions": ["update"], "before": {"description": "web"}, "after": {"description": "edited in console"}}
+    }
+  ],
+  "output_changes": {
+    "db_endpoint": {"actions": ["update"], "before": "old", "after": null, "after_unknown": true}
+  }
+}`
+
+const terraformStateFile = `{
+  "version": 4,
+  "terraform_version": "1.9.5",
+  "serial": 42,
+  "outputs": {
+    "db_password": {"value": "hunter2", "type": "string", "sensitive": true},
+    "vpc_id": {"value": "vpc-123", "type": "string"}
+  },
+  "resources": [
+    {
+      "mode": "managed", "type": "aws_instance", "name": "web", "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"index_key": 0, "attributes": {"instance_type": "t3.large", "id": "i-1"}},
+        {"index_key": 1, "attributes": {"instance_type": "t3.large", "id": "i-2"}}
+      ]
+    },
+    {
+      "module": "module.network", "mode": "managed", "type": "aws_vpc", "name": "this", "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"attributes": {"cidr_block": "10.0.0.0/16"}}]
+    },
+    {
+      "mode": "data", "type": "aws_ami", "name": "ubuntu", "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"attributes": {"id": "ami-1"}}]
+    }
+  ]
+}`
+
+func runTerraform(t *testing.T, args map[string]any) (string, error) {
+	t.Helper()
+	tool := &terraform.TerraformTool{}
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func writeTerraformFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestTerraformTool_Definition(t *testing.T) {
+	tool := &terraform.TerraformTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "terraform", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+}
+
+func TestTerraformTool_Plan(t *testing.T) {
+	path := writeTerraformFile(t, "plan.json", terraformPlanJSON)
+
+	output, err := runTerraform(t, map[string]any{"path": path})
+	testutils.AssertNoError(t, err)
+
+	testutils.AssertTrue(t, strings.Contains(output, "Terraform 1.9.5"))
+	testutils.AssertTrue(t, strings.Contains(output, "**Plan:** 1 to add, 1 to change, 1 to replace, 0 to destroy (1 unchanged)"))
+	testutils.AssertTrue(t, strings.Contains(output, "`aws_db_instance.main` (cannot update in place; forced by: engine_version)"))
+	testutils.AssertTrue(t, strings.Contains(output, `instance_type: "t3.small" → "t3.large"`))
+	testutils.AssertTrue(t, strings.Contains(output, `tags.Environment: "dev" → "staging"`))
+	testutils.AssertTrue(t, strings.Contains(output, "password: (sensitive) → (sensitive)"))
+	testutils.AssertTrue(t, strings.Contains(output, `id: "db-1" → (known after apply)`))
+	testutils.AssertFalse(t, strings.Contains(output, "hunter"))
+	testutils.AssertFalse(t, strings.Contains(output, "ami:"))
+
+	// Replacements are listed before creates and updates
+	testutils.AssertTrue(t, strings.Index(output, "## Replace") < strings.Index(output, "## Create"))
+
+	testutils.AssertTrue(t, strings.Contains(output, "## Drift"))
+	testutils.AssertTrue(t, strings.Contains(output, `description: "web" → "edited in console"`))
+
+	testutils.AssertTrue(t, strings.Contains(output, "## Cost-relevant changes"))
+	testutils.AssertTrue(t, strings.Contains(output, "| `module.network.aws_nat_gateway.this[0]` | create |"))
+	testutils.AssertTrue(t, strings.Contains(output, "`db_endpoint`: update (known after apply)"))
+}
+
+func TestTerraformTool_PlanFilter(t *testing.T) {
+	path := writeTerraformFile(t, "plan.json", terraformPlanJSON)
+
+	output, err := runTerraform(t, map[string]any{"path": path, "filter": "module.network"})
+	testutils.AssertNoError(t, err)
+
+	testutils.AssertTrue(t, strings.Contains(output, "1 to add, 0 to change, 0 to replace"))
+	testutils.AssertFalse(t, strings.Contains(output, "aws_instance.web"))
+	testutils.AssertFalse(t, strings.Contains(output, "## Drift"))
+}
+
+func TestTerraformTool_State(t *testing.T) {
+	path := writeTerraformFile(t, "terraform.tfstate", terraformStateFile)
+
+	output, err := runTerraform(t, map[string]any{"path": path})
+	testutils.AssertNoError(t, err)
+
+	testutils.AssertTrue(t, strings.Contains(output, "serial 42"))
+	testutils.AssertTrue(t, strings.Contains(output, "**Resources:** 3 managed, 1 data sources"))
+	testutils.AssertTrue(t, strings.Contains(output, "| `aws_instance` | 2 |"))
+	testutils.AssertTrue(t, strings.Contains(output, "| `module.network` | 1 |"))
+	testutils.AssertTrue(t, strings.Contains(output, "| `aws_instance.web[1]` | instance_type=\"t3.large\" |"))
+	testutils.AssertTrue(t, strings.Contains(output, "`db_password`: (sensitive)"))
+	testutils.AssertFalse(t, strings.Contains(output, "hunter2"))
+}
+
+func TestTerraformTool_InvalidInput(t *testing.T) {
+	_, err := runTerraform(t, map[string]any{"path": "plan.json"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+
+	path := writeTerraformFile(t, "other.json", `{"name": "not terraform"}`)
+	_, err = runTerraform(t, map[string]any{"path": path})
+	testutils.AssertErrorContains(t, err, "does not look like Terraform")
+
+	path = writeTerraformFile(t, "broken.json", `{`)
+	_, err = runTerraform(t, map[string]any{"path": path})
+	testutils.AssertErrorContains(t, err, "is not a Terraform JSON")
+}
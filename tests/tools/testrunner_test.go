@@ -0,0 +1,198 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/testrunner"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+const goTestFile = `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {}
+
+func TestSub(t *testing.T) {
+	t.Log("checking")
+	t.Errorf("got %d, want %d", 3, 4)
+}
+
+func TestTable(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {})
+	t.Run("bad", func(t *testing.T) { t.Fatal("boom") })
+}
+
+func TestSkip(t *testing.T) { t.Skip("later") }
+`
+
+// fakePytest writes a JUnit report like pytest's to the path given with --junitxml
+const fakePytest = `#!/bin/sh
+for a in "$@"; do case "$a" in --junitxml=*) out="${a#--junitxml=}";; esac; done
+cat > "$out" <<'XML'
+<?xml version="1.0" encoding="utf-8"?>
+<testsuites><testsuite name="pytest" tests="2">
+<testcase classname="tests.test_calc" file="tests/test_calc.py" line="0" name="test_add" time="0.001"/>
+<testcase classname="tests.test_calc" file="tests/test_calc.py" line="3" name="test_sub" time="0.002"><failure message="assert 1 == 2">def test_sub():
+&gt;       assert 1 == 2
+E       assert 1 == 2
+
+tests/test_calc.py:5: AssertionError</failure><system-out>debug</system-out></testcase>
+</testsuite></testsuites>
+XML
+exit 1
+`
+
+// fakeJest writes a report like jest's to the path given with --outputFile
+const fakeJest = `#!/bin/sh
+for a in "$@"; do case "$a" in --outputFile=*) out="${a#--outputFile=}";; esac; done
+cat > "$out" <<JSON
+{"numTotalTests":2,"numPassedTests":1,"numFailedTests":1,"numPendingTests":0,"numTodoTests":0,"testResults":[
+{"name":"$PWD/sum.test.js","status":"failed","message":"","assertionResults":[
+{"fullName":"sum adds","status":"passed","duration":1,"failureMessages":[]},
+{"fullName":"sum subtracts","status":"failed","duration":4,"failureMessages":["Error: expect(received).toBe(expected)\n    at Object.<anonymous> ($PWD/sum.test.js:7:17)"],"location":{"line":6}}]}]}
+JSON
+exit 1
+`
+
+// runTests calls the run_tests tool and decodes its result
+func runTests(t *testing.T, args map[string]any) (testrunner.Result, error) {
+	t.Helper()
+	var out testrunner.Result
+	result, err := (&testrunner.RunTestsTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return out, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return out, nil
+}
+
+func TestRunTests_Go(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	dir := writeProject(t, map[string]string{
+		"go.mod":       "module example.com/calc\n\ngo 1.22\n",
+		"calc_test.go": goTestFile,
+	})
+
+	out, err := runTests(t, map[string]any{"path": dir})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "go", out.Framework)
+	testutils.AssertFalse(t, out.Passed)
+	testutils.AssertEqual(t, testrunner.Counts{Total: 6, Passed: 2, Failed: 3, Skipped: 1}, out.Counts)
+
+	// TestTable is left out, as its failing subtest explains it
+	testutils.AssertEqual(t, 2, len(out.Failures))
+	testutils.AssertEqual(t, "TestSub", out.Failures[0].Test)
+	testutils.AssertEqual(t, "calc_test.go", out.Failures[0].File)
+	testutils.AssertEqual(t, 9, out.Failures[0].Line)
+	testutils.AssertEqual(t, "got 3, want 4", out.Failures[0].Message)
+	testutils.AssertTrue(t, strings.Contains(out.Failures[0].Output, "checking"))
+	testutils.AssertEqual(t, "TestTable/bad", out.Failures[1].Test)
+	testutils.AssertEqual(t, "boom", out.Failures[1].Message)
+
+	out, err = runTests(t, map[string]any{"path": dir, "filter": "^TestAdd$"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, out.Passed)
+	testutils.AssertEqual(t, 1, out.Counts.Total)
+
+	out, err = runTests(t, map[string]any{"path": dir, "filter": "NoSuchTest"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(out.Note, "No tests were run"))
+}
+
+func TestRunTests_Pytest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the Python interpreter")
+	}
+	dir := writeProject(t, map[string]string{"conftest.py": ""})
+	writeExecutable(t, filepath.Join(dir, ".venv", "bin", "python"), fakePytest)
+
+	out, err := runTests(t, map[string]any{"path": dir, "filter": "calc"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "pytest", out.Framework)
+	testutils.AssertTrue(t, strings.HasSuffix(out.Command, "-k calc"))
+	testutils.AssertEqual(t, testrunner.Counts{Total: 2, Passed: 1, Failed: 1}, out.Counts)
+	testutils.AssertEqual(t, 1, len(out.Failures))
+	f := out.Failures[0]
+	testutils.AssertEqual(t, "test_sub", f.Test)
+	testutils.AssertEqual(t, "tests/test_calc.py", f.File)
+	testutils.AssertEqual(t, 5, f.Line)
+	testutils.AssertEqual(t, "assert 1 == 2", f.Message)
+	testutils.AssertTrue(t, strings.Contains(f.Output, "Captured stdout:\ndebug"))
+}
+
+func TestRunTests_Jest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as jest")
+	}
+	dir := writeProject(t, map[string]string{"package.json": `{"devDependencies": {"jest": "^29.0.0"}}`})
+	writeExecutable(t, filepath.Join(dir, "node_modules", ".bin", "jest"), fakeJest)
+
+	out, err := runTests(t, map[string]any{"path": dir})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "jest", out.Framework)
+	testutils.AssertEqual(t, testrunner.Counts{Total: 2, Passed: 1, Failed: 1}, out.Counts)
+	testutils.AssertEqual(t, 1, len(out.Failures))
+	f := out.Failures[0]
+	testutils.AssertEqual(t, "sum subtracts", f.Test)
+	testutils.AssertEqual(t, "sum.test.js", f.File)
+	testutils.AssertEqual(t, 7, f.Line)
+	testutils.AssertEqual(t, "Error: expect(received).toBe(expected)", f.Message)
+}
+
+func TestRunTests_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as jest")
+	}
+	dir := writeProject(t, map[string]string{"jest.config.js": ""})
+	writeExecutable(t, filepath.Join(dir, "node_modules", ".bin", "jest"), "#!/bin/sh\necho starting\nsleep 30\n")
+
+	out, err := runTests(t, map[string]any{"path": dir, "timeout": float64(1)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, out.TimedOut)
+	testutils.AssertFalse(t, out.Passed)
+	testutils.AssertTrue(t, strings.Contains(out.Output, "starting"))
+	testutils.AssertTrue(t, strings.Contains(out.Note, "stopped after 1s"))
+}
+
+func TestRunTests_Errors(t *testing.T) {
+	dir := writeProject(t, map[string]string{"go.mod": "module example.com/calc\n", "readme.txt": ""})
+
+	_, err := runTests(t, map[string]any{"path": dir, "target": "./... -exec=evil"})
+	testutils.AssertErrorContains(t, err, "cannot start with '-'")
+
+	_, err = runTests(t, map[string]any{"path": dir, "filter": "-v"})
+	testutils.AssertErrorContains(t, err, "cannot start with '-'")
+
+	_, err = runTests(t, map[string]any{"path": dir, "timeout": float64(0)})
+	testutils.AssertErrorContains(t, err, "timeout")
+
+	_, err = runTests(t, map[string]any{"path": dir, "max_failures": float64(1.5)})
+	testutils.AssertErrorContains(t, err, "max_failures")
+
+	_, err = runTests(t, map[string]any{"path": dir, "framework": "mocha"})
+	testutils.AssertErrorContains(t, err, "invalid framework")
+
+	_, err = runTests(t, map[string]any{"path": filepath.Join(dir, "readme.txt")})
+	testutils.AssertErrorContains(t, err, "not a directory")
+
+	_, err = runTests(t, map[string]any{"path": t.TempDir()})
+	testutils.AssertErrorContains(t, err, "could not detect")
+}
+
+// writeExecutable writes a script that the tool runs in place of a test runner
+func writeExecutable(t *testing.T, path, script string) {
+	t.Helper()
+	testutils.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(script), 0700))
+}
@@ -0,0 +1,204 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/textdiff"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runDiffText calls the text_diff tool's diff action and decodes its JSON response
+func runDiffText(t *testing.T, args map[string]any) (*textdiff.DiffResponse, error) {
+	t.Helper()
+	result, err := (&textdiff.TextDiffTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var response textdiff.DiffResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return &response, nil
+}
+
+// runApplyPatchText calls the text_diff tool's apply action and decodes its JSON response
+func runApplyPatchText(t *testing.T, args map[string]any) (*textdiff.ApplyResponse, error) {
+	t.Helper()
+	args["action"] = "apply"
+	result, err := (&textdiff.TextDiffTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return nil, err
+	}
+	var response textdiff.ApplyResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return &response, nil
+}
+
+func TestDiffText_Unified(t *testing.T) {
+	response, err := runDiffText(t, map[string]any{
+		"original": "one\ntwo\nthree\nfour\nfive\nsix\n",
+		"modified": "one\n2\nthree\nfour\nfive\nsix\nseven\n",
+		"context":  float64(1),
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, response.Identical)
+	testutils.AssertEqual(t, 2, response.Additions)
+	testutils.AssertEqual(t, 1, response.Deletions)
+	testutils.AssertEqual(t, "--- original\n+++ modified\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n@@ -6 +6,2 @@\n six\n+seven\n", response.Diff)
+
+	response, err = runDiffText(t, map[string]any{"original": "same\n", "modified": "same\n"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, response.Identical)
+	testutils.AssertEqual(t, "", response.Diff)
+
+	// A missing final newline is marked as diff -u does
+	response, err = runDiffText(t, map[string]any{"original": "a\nb\n", "modified": "a\nb"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "--- original\n+++ modified\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n", response.Diff)
+}
+
+func TestDiffText_Words(t *testing.T) {
+	response, err := runDiffText(t, map[string]any{
+		"original": "The quick brown fox jumps.",
+		"modified": "The quick red fox leaps!",
+		"mode":     "words",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "The quick [-brown-]{+red+} fox [-jumps.-]{+leaps!+}", response.Diff)
+	testutils.AssertEqual(t, 3, response.Additions)
+	testutils.AssertEqual(t, 3, response.Deletions)
+}
+
+func TestDiffText_JSON(t *testing.T) {
+	response, err := runDiffText(t, map[string]any{
+		"original": `{"name": "api", "replicas": 2, "ports": [80, 443], "labels": {"team a": "x"}, "ratio": 1}`,
+		"modified": `{"ratio": 1.0, "replicas": 3, "name": "api", "ports": [80], "labels": {"team a": "y"}, "debug": true}`,
+		"mode":     "json",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 4, len(response.Changes))
+	testutils.AssertEqual(t, ".debug", response.Changes[0].Path)
+	testutils.AssertEqual(t, "added", response.Changes[0].Op)
+	testutils.AssertEqual(t, `.labels["team a"]`, response.Changes[1].Path)
+	testutils.AssertEqual(t, ".ports[1]", response.Changes[2].Path)
+	testutils.AssertEqual(t, "removed", response.Changes[2].Op)
+	testutils.AssertEqual(t, "443", string(response.Changes[2].Original))
+	testutils.AssertEqual(t, ".replicas", response.Changes[3].Path)
+	testutils.AssertEqual(t, "changed", response.Changes[3].Op)
+
+	response, err = runDiffText(t, map[string]any{"original": `[1, {"a": 1}]`, "modified": "[1,{\"a\":1}]\n", "mode": "json"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, response.Identical)
+
+	_, err = runDiffText(t, map[string]any{"original": `{"a": 1}`, "modified": `{"a": 1} {}`, "mode": "json"})
+	testutils.AssertErrorContains(t, err, "modified is not valid JSON")
+}
+
+func TestDiffText_Files(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	testutils.AssertNoError(t, os.WriteFile(path, []byte("port: 80\n"), 0600))
+
+	response, err := runDiffText(t, map[string]any{"original_file": path, "modified": "port: 8080\n"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(response.Diff, "--- "+path+"\n+++ modified\n"))
+
+	_, err = runDiffText(t, map[string]any{"original": "a", "original_file": path, "modified": "b"})
+	testutils.AssertErrorContains(t, err, "not both")
+
+	_, err = runDiffText(t, map[string]any{"original_file": "relative.txt", "modified": "b"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+}
+
+func TestTextDiff_Actions(t *testing.T) {
+	response, err := runDiffText(t, map[string]any{"action": "diff", "original": "a\n", "modified": "b\n"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "--- original\n+++ modified\n@@ -1 +1 @@\n-a\n+b\n", response.Diff)
+
+	_, err = runDiffText(t, map[string]any{"action": "merge", "original": "a\n", "modified": "b\n"})
+	testutils.AssertErrorContains(t, err, "invalid action 'merge'")
+}
+
+func TestApplyPatchText_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		original string
+		modified string
+	}{
+		{"change", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n", "one\n2\nthree\nfour\nfive\nsix\nseven\n8\nnine\n"},
+		{"remove newline", "a\nb\n", "a\nb"},
+		{"add newline", "a\nb", "a\nc\n"},
+		{"from empty", "", "new\nfile\n"},
+		{"to empty", "old\nfile\n", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := runDiffText(t, map[string]any{"original": tc.original, "modified": tc.modified, "context": float64(1)})
+			testutils.AssertNoError(t, err)
+			applied, err := runApplyPatchText(t, map[string]any{"original": tc.original, "patch": diff.Diff})
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, tc.modified, applied.Content)
+			testutils.AssertEqual(t, diff.Additions, applied.Additions)
+			testutils.AssertEqual(t, diff.Deletions, applied.Deletions)
+		})
+	}
+}
+
+func TestApplyPatchText_FormatPatch(t *testing.T) {
+	patch := "From 1234 Mon Sep 17 00:00:00 2001\nSubject: [PATCH] Edit\n---\n f | 2 +-\n\ndiff --git a/f b/f\n--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n-a\n+A\n b\n-- \n2.45.0\n"
+	response, err := runApplyPatchText(t, map[string]any{"original": "a\nb\n", "patch": patch})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "A\nb\n", response.Content)
+}
+
+func TestApplyPatchText_OffsetsAndFuzz(t *testing.T) {
+	patch := "diff --git a/f b/f\n--- a/f\n+++ b/f\n@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n"
+
+	// Two lines added above the hunk move it down
+	response, err := runApplyPatchText(t, map[string]any{"original": "x\ny\na\nb\nc\nd\n", "patch": patch})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "x\ny\na\nb\nC\nd\n", response.Content)
+	testutils.AssertEqual(t, 1, response.HunksApplied)
+	testutils.AssertEqual(t, 4, response.Hunks[0].Line)
+	testutils.AssertEqual(t, 2, response.Hunks[0].Offset)
+	testutils.AssertFalse(t, response.Hunks[0].Fuzzy)
+
+	// Trailing whitespace and CRLF line endings in the text are kept
+	response, err = runApplyPatchText(t, map[string]any{"original": "a\r\nb  \r\nc\r\nd\r\n", "patch": patch})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "a\r\nb  \r\nC\r\nd\r\n", response.Content)
+	testutils.AssertTrue(t, response.Hunks[0].Fuzzy)
+}
+
+func TestApplyPatchText_Errors(t *testing.T) {
+	_, err := runApplyPatchText(t, map[string]any{"original": "a\nb\n", "patch": "@@ -1,2 +1,2 @@\n a\n-x\n+y\n"})
+	testutils.AssertErrorContains(t, err, `hunk 1 (@@ -1,2 +1,2 @@) does not apply: line 2 is "b", expected "x"`)
+
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": "just some text\n"})
+	testutils.AssertErrorContains(t, err, "no hunks")
+
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": "@@ edit @@\n-a\n+b\n"})
+	testutils.AssertErrorContains(t, err, "not a valid hunk header")
+
+	twoFiles := "--- a/one\n+++ b/one\n@@ -1 +1 @@\n-a\n+b\n--- a/two\n+++ b/two\n@@ -1 +1 @@\n-a\n+b\n"
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": twoFiles})
+	testutils.AssertErrorContains(t, err, "second file")
+
+	// Header counts must match the hunk's lines
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": "@@ -1,999999999 +1 @@\n-a\n+b\n"})
+	testutils.AssertErrorContains(t, err, `hunk "@@ -1,999999999 +1 @@" has 1 original and 1 new lines, but its header counts 999999999 and 1`)
+
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\nb\n", "patch": "@@ -1 +1 @@\n-a\n+A\n b\n"})
+	testutils.AssertErrorContains(t, err, "line 4 of the patch is past the end of hunk")
+
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": "@@ -1 +1 @@\n"})
+	testutils.AssertErrorContains(t, err, `hunk "@@ -1 +1 @@" has no lines`)
+
+	_, err = runApplyPatchText(t, map[string]any{"original": "a\n", "patch": "@@ -1 +1 @@\n@@ -1 +1 @@\n-a\n+b\n"})
+	testutils.AssertErrorContains(t, err, "has no lines")
+}
@@ -1,11 +1,16 @@
 package tools_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/sammcj/mcp-devtools/internal/tools/think"
 	"github.com/sammcj/mcp-devtools/tests/testutils"
 )
@@ -29,6 +34,7 @@ func TestThinkTool_Definition(t *testing.T) {
 }
 
 func TestThinkTool_Execute_ValidInput(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -60,13 +66,14 @@ func TestThinkTool_Execute_ValidInput(t *testing.T) {
 		t.Errorf("Expected content type 'text', got: %s", textContent.Type)
 	}
 
-	expectedText := "I should use the think hard tool on this problem: This is a test thought"
+	expectedText := "Thought 1: I should use the think hard tool on this problem: This is a test thought"
 	if textContent.Text != expectedText {
 		t.Errorf("Expected result to be '%s', got: %s", expectedText, textContent.Text)
 	}
 }
 
 func TestThinkTool_Execute_EmptyThought(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -83,6 +90,7 @@ func TestThinkTool_Execute_EmptyThought(t *testing.T) {
 }
 
 func TestThinkTool_Execute_MissingThought(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -97,6 +105,7 @@ func TestThinkTool_Execute_MissingThought(t *testing.T) {
 }
 
 func TestThinkTool_Execute_InvalidThoughtType(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -113,6 +122,7 @@ func TestThinkTool_Execute_InvalidThoughtType(t *testing.T) {
 }
 
 func TestThinkTool_Execute_LongThought(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -146,6 +156,7 @@ func TestThinkTool_Execute_LongThought(t *testing.T) {
 }
 
 func TestThinkTool_Execute_ExcessivelyLongThought(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -186,6 +197,7 @@ func TestThinkTool_Execute_CustomMaxLengthEnvironmentVariable(t *testing.T) {
 		t.Fatalf("Failed to set environment variable: %v", err)
 	}
 
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -222,6 +234,7 @@ func TestThinkTool_Execute_CustomMaxLengthEnvironmentVariable(t *testing.T) {
 }
 
 func TestThinkTool_Execute_HowHardParameter(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -244,7 +257,7 @@ func TestThinkTool_Execute_HowHardParameter(t *testing.T) {
 		t.Fatal("Expected TextContent, got different type")
 	}
 
-	expectedText := "I should use the think harder tool on this problem: This is a complex problem"
+	expectedText := "Thought 1: I should use the think harder tool on this problem: This is a complex problem"
 	if textContent.Text != expectedText {
 		t.Errorf("Expected result to be '%s', got: %s", expectedText, textContent.Text)
 	}
@@ -266,7 +279,7 @@ func TestThinkTool_Execute_HowHardParameter(t *testing.T) {
 		t.Fatal("Expected TextContent, got different type")
 	}
 
-	expectedText = "I should use the ultrathink tool on this problem: This is an extremely complex problem"
+	expectedText = "Thought 2: I should use the ultrathink tool on this problem: This is an extremely complex problem"
 	if textContent.Text != expectedText {
 		t.Errorf("Expected result to be '%s', got: %s", expectedText, textContent.Text)
 	}
@@ -288,13 +301,14 @@ func TestThinkTool_Execute_HowHardParameter(t *testing.T) {
 		t.Fatal("Expected TextContent, got different type")
 	}
 
-	expectedText = "I should use the think hard tool on this problem: This is a standard problem"
+	expectedText = "Thought 3: I should use the think hard tool on this problem: This is a standard problem"
 	if textContent.Text != expectedText {
 		t.Errorf("Expected result to be '%s', got: %s", expectedText, textContent.Text)
 	}
 }
 
 func TestThinkTool_Execute_InvalidHowHardParameter(t *testing.T) {
+	isolateThoughts(t)
 	tool := &think.ThinkTool{}
 	logger := testutils.CreateTestLogger()
 	cache := testutils.CreateTestCache()
@@ -322,3 +336,141 @@ func TestThinkTool_Execute_InvalidHowHardParameter(t *testing.T) {
 	testutils.AssertError(t, err)
 	testutils.AssertErrorContains(t, err, "invalid 'how_hard' parameter")
 }
+
+func TestThinkTool_Execute_RevisionsAndBranches(t *testing.T) {
+	isolateThoughts(t)
+	tool := &think.ThinkTool{}
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	ctx := testutils.CreateTestContext()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"thought": "Cache the parsed config"})
+	testutils.AssertNoError(t, err)
+
+	result, err := tool.Execute(ctx, logger, cache, map[string]any{"thought": "Config changes at runtime, so reload it instead", "revises": float64(1)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Thought 2 (revises thought 1): I should use the think hard tool on this problem: Config changes at runtime, so reload it instead", result.Content[0].(mcp.TextContent).Text)
+
+	result, err = tool.Execute(ctx, logger, cache, map[string]any{"thought": "Watch the file for changes", "revises": float64(1), "branch": "watcher"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Thought 3 (revises thought 1) (branch: watcher): I should use the think hard tool on this problem: Watch the file for changes", result.Content[0].(mcp.TextContent).Text)
+
+	// Only recorded thoughts can be revised
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"thought": "Revisit the cache", "revises": float64(4)})
+	testutils.AssertErrorContains(t, err, "thought 4 does not exist")
+	_, err = tool.Execute(ctx, logger, cache, map[string]any{"thought": "Revisit the cache", "revises": 1.5})
+	testutils.AssertErrorContains(t, err, "invalid 'revises' parameter")
+}
+
+func TestThinkTool_Execute_GetThoughts(t *testing.T) {
+	home := isolateThoughts(t)
+	t.Setenv(think.ThinkPersistEnvVar, "true")
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	server := mcpserver.NewMCPServer("test", "1.0")
+	ctx := server.WithContext(testutils.CreateTestContext(), mcpserver.NewInProcessSession("stdio", nil))
+
+	tool := &think.ThinkTool{}
+	for _, args := range []map[string]any{
+		{"thought": "Check the retry budget"},
+		{"thought": "Retries hide the real failure", "branch": "no-retries"},
+		{"thought": "Backoff needs jitter", "how_hard": "harder"},
+	} {
+		_, err := tool.Execute(ctx, logger, cache, args)
+		testutils.AssertNoError(t, err)
+	}
+
+	// Stdio sessions all share one session ID, so they get a scratchpad per workspace
+	paths, err := filepath.Glob(filepath.Join(home, ".mcp-devtools", "thoughts", "workspace-*.json"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(paths))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(paths[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// A new tool instance, as after a restart, recovers the session's thoughts from disk
+	getThoughts := func(args map[string]any) think.GetThoughtsResponse {
+		t.Helper()
+		result, err := (&think.ThinkTool{}).Execute(ctx, logger, cache, args)
+		testutils.AssertNoError(t, err)
+		var response think.GetThoughtsResponse
+		testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+		return response
+	}
+
+	response := getThoughts(map[string]any{"action": "get_thoughts"})
+	testutils.AssertEqual(t, 3, len(response.Thoughts))
+	testutils.AssertEqual(t, 3, response.Thoughts[2].Number)
+	testutils.AssertEqual(t, "harder", response.Thoughts[2].HowHard)
+	testutils.AssertEqual(t, 1, len(response.Branches))
+	testutils.AssertEqual(t, "no-retries", response.Branches[0])
+
+	response = getThoughts(map[string]any{"action": "get_thoughts", "branch": "no-retries"})
+	testutils.AssertEqual(t, 1, len(response.Thoughts))
+	testutils.AssertEqual(t, "Retries hide the real failure", response.Thoughts[0].Thought)
+
+	// Other sessions have their own scratchpad
+	otherCtx := server.WithContext(testutils.CreateTestContext(), mcpserver.NewInProcessSession("other", nil))
+	result, err := tool.Execute(otherCtx, logger, cache, map[string]any{"action": "get_thoughts"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, `{"thoughts":[]}`, result.Content[0].(mcp.TextContent).Text)
+}
+
+// isolateThoughts points the home and working directories at a temporary directory so thoughts
+// recorded by a test, in memory or on disk, do not leak into other tests
+func isolateThoughts(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(think.ThinkPersistEnvVar, "")
+	t.Chdir(home)
+	return home
+}
+
+func TestThinkTool_Execute_KeepsThoughtsInMemoryByDefault(t *testing.T) {
+	home := isolateThoughts(t)
+	tool := &think.ThinkTool{}
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	ctx := testutils.CreateTestContext()
+
+	_, err := tool.Execute(ctx, logger, cache, map[string]any{"thought": "Check the retry budget"})
+	testutils.AssertNoError(t, err)
+	result, err := tool.Execute(ctx, logger, cache, map[string]any{"thought": "Retries hide the real failure"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Thought 2: I should use the think hard tool on this problem: Retries hide the real failure", result.Content[0].(mcp.TextContent).Text)
+
+	if _, err := os.Stat(filepath.Join(home, ".mcp-devtools", "thoughts")); !os.IsNotExist(err) {
+		t.Errorf("Expected no thoughts directory without %s, got: %v", think.ThinkPersistEnvVar, err)
+	}
+}
+
+func TestThinkTool_Execute_FallsBackToMemoryWhenSavingFails(t *testing.T) {
+	home := isolateThoughts(t)
+	t.Setenv(think.ThinkPersistEnvVar, "true")
+	tool := &think.ThinkTool{}
+	logger := testutils.CreateTestLogger()
+	cache := testutils.CreateTestCache()
+	ctx := testutils.CreateTestContext()
+
+	// A file where the thoughts directory should be stops scratchpads being saved
+	testutils.AssertNoError(t, os.MkdirAll(filepath.Join(home, ".mcp-devtools"), 0700))
+	testutils.AssertNoError(t, os.WriteFile(filepath.Join(home, ".mcp-devtools", "thoughts"), nil, 0600))
+
+	for i, thought := range []string{"Check the retry budget", "Retries hide the real failure"} {
+		result, err := tool.Execute(ctx, logger, cache, map[string]any{"thought": thought})
+		testutils.AssertNoError(t, err)
+		if !strings.HasPrefix(result.Content[0].(mcp.TextContent).Text, fmt.Sprintf("Thought %d:", i+1)) {
+			t.Errorf("Expected thought %d to be recorded, got: %s", i+1, result.Content[0].(mcp.TextContent).Text)
+		}
+	}
+
+	result, err := tool.Execute(ctx, logger, cache, map[string]any{"action": "get_thoughts"})
+	testutils.AssertNoError(t, err)
+	var response think.GetThoughtsResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	testutils.AssertEqual(t, 2, len(response.Thoughts))
+}
@@ -0,0 +1,146 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/timetool"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runTime calls the time tool and decodes its JSON response into out
+func runTime(t *testing.T, args map[string]any, out any) error {
+	t.Helper()
+	result, err := (&timetool.TimeTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), out))
+	return nil
+}
+
+func TestTime_Now(t *testing.T) {
+	var response timetool.TimesResponse
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "now", "timezones": []any{"UTC", "Asia/Kolkata"}}, &response))
+	testutils.AssertEqual(t, 2, len(response.Times))
+	testutils.AssertEqual(t, "+05:30", response.Times[1].UTCOffset)
+
+	utc, err := time.Parse(time.RFC3339, response.Times[0].Time)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, time.Since(utc) < time.Minute)
+
+	err = runTime(t, map[string]any{"action": "now", "timezones": []any{"AEST"}}, &response)
+	testutils.AssertErrorContains(t, err, "unknown time zone 'AEST'")
+}
+
+func TestTime_Convert(t *testing.T) {
+	var response timetool.TimesResponse
+	testutils.AssertNoError(t, runTime(t, map[string]any{
+		"action":    "convert",
+		"time":      "2026-01-15 09:00",
+		"timezone":  "Australia/Melbourne",
+		"timezones": []any{"UTC", "America/New_York"},
+	}, &response))
+	testutils.AssertEqual(t, "2026-01-15T09:00:00+11:00", response.Source.Time)
+	testutils.AssertTrue(t, response.Source.DST)
+	testutils.AssertEqual(t, "2026-01-14T22:00:00Z", response.Times[0].Time)
+	testutils.AssertEqual(t, "2026-01-14T17:00:00-05:00", response.Times[1].Time)
+	testutils.AssertEqual(t, "EST", response.Times[1].Abbreviation)
+
+	// A time with an offset ignores the source time zone
+	testutils.AssertNoError(t, runTime(t, map[string]any{
+		"action":    "convert",
+		"time":      "2026-07-01T12:00:00Z",
+		"timezone":  "Asia/Tokyo",
+		"timezones": []any{"Europe/London"},
+	}, &response))
+	testutils.AssertEqual(t, "2026-07-01T13:00:00+01:00", response.Times[0].Time)
+}
+
+func TestTime_BusinessDays(t *testing.T) {
+	var response timetool.BusinessDaysResponse
+
+	// One business day after a Friday is the next Monday
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "business_days", "date": "2026-03-06", "days": float64(1)}, &response))
+	testutils.AssertEqual(t, "2026-03-09", response.End)
+	testutils.AssertEqual(t, "Monday", response.EndWeekday)
+	testutils.AssertEqual(t, 3, response.CalendarDays)
+
+	// Holidays are skipped, and negative days go backwards
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "business_days", "date": "2026-12-24", "days": float64(2), "holidays": []any{"2026-12-25", "2026-12-28"}}, &response))
+	testutils.AssertEqual(t, "2026-12-30", response.End)
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "business_days", "date": "2026-03-09", "days": float64(-1)}, &response))
+	testutils.AssertEqual(t, "2026-03-06", response.End)
+
+	// Counting includes both dates
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "business_days", "date": "2026-03-02", "end_date": "2026-03-13"}, &response))
+	testutils.AssertEqual(t, 10, response.BusinessDays)
+	testutils.AssertEqual(t, 11, response.CalendarDays)
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "business_days", "date": "2026-03-13", "end_date": "2026-03-02", "holidays": []any{"2026-03-09"}}, &response))
+	testutils.AssertEqual(t, -9, response.BusinessDays)
+
+	err := runTime(t, map[string]any{"action": "business_days", "date": "2026-03-02"}, &response)
+	testutils.AssertErrorContains(t, err, "needs either 'days'")
+}
+
+func TestTime_Cron(t *testing.T) {
+	tests := []struct {
+		expression  string
+		explanation string
+	}{
+		{"30 9 * * 1-5", "At 09:30 on Monday through Friday"},
+		{"*/15 * * * *", "Every 15 minutes"},
+		{"0 9,17 * * *", "At 09:00 and 17:00"},
+		{"0 */2 * * *", "At minute 0 past every 2 hours"},
+		{"0 0 1 * *", "At 00:00 on day-of-month 1"},
+		{"0 8 1-7 * MON", "At 08:00 on day-of-month 1 through 7 or on Monday"},
+		{"15 3 * JAN,JUL *", "At 03:15 in January and July"},
+		{"@hourly", "At minute 0 past every hour"},
+	}
+	for _, tt := range tests {
+		var response timetool.CronResponse
+		testutils.AssertNoError(t, runTime(t, map[string]any{"action": "cron", "expression": tt.expression, "count": float64(1)}, &response))
+		testutils.AssertEqual(t, tt.explanation, response.Explanation)
+		testutils.AssertEqual(t, 1, len(response.NextRuns))
+	}
+}
+
+func TestTime_CronNextRuns(t *testing.T) {
+	var response timetool.CronResponse
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "cron", "expression": "30 9 * * 1-5", "timezone": "Europe/Berlin", "count": float64(10)}, &response))
+	testutils.AssertEqual(t, "Europe/Berlin", response.Timezone)
+	testutils.AssertEqual(t, 10, len(response.NextRuns))
+
+	previous := time.Now()
+	for _, run := range response.NextRuns {
+		next, err := time.Parse("2006-01-02T15:04:05Z07:00 Mon", run)
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, next.After(previous))
+		local := next.In(mustLoadLocation(t, "Europe/Berlin"))
+		testutils.AssertEqual(t, 9, local.Hour())
+		testutils.AssertEqual(t, 30, local.Minute())
+		testutils.AssertTrue(t, local.Weekday() != time.Saturday && local.Weekday() != time.Sunday)
+		previous = next
+	}
+
+	// A schedule that never matches a real date has no runs
+	testutils.AssertNoError(t, runTime(t, map[string]any{"action": "cron", "expression": "0 0 30 2 *"}, &response))
+	testutils.AssertEqual(t, 0, len(response.NextRuns))
+
+	for _, expression := range []string{"0 0 * *", "61 * * * *", "0 0 * * 1-9", "5-1 * * * *", "* * * * * *"} {
+		err := runTime(t, map[string]any{"action": "cron", "expression": expression}, &response)
+		testutils.AssertError(t, err)
+	}
+}
+
+// mustLoadLocation loads an IANA time zone or fails the test
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	testutils.AssertNoError(t, err)
+	return loc
+}
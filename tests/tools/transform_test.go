@@ -0,0 +1,136 @@
+package tools_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/transform"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runTransform calls the transform tool and returns its text output
+func runTransform(t *testing.T, args map[string]any) (string, error) {
+	t.Helper()
+	result, err := (&transform.TransformTool{}).Execute(context.Background(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return "", err
+	}
+	return result.Content[0].(mcp.TextContent).Text, nil
+}
+
+func TestTransform_QueryFiles(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "docker-compose.yml")
+	testutils.AssertNoError(t, os.WriteFile(compose, []byte(`services:
+  web:
+    image: nginx:1.27
+    ports: ["80:80"]
+  db:
+    image: postgres:17
+`), 0600))
+
+	output, err := runTransform(t, map[string]any{"file": compose, "query": "[.services[].image] | sort | .[]", "raw": true})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "nginx:1.27\npostgres:17\n", output)
+
+	output, err = runTransform(t, map[string]any{"file": compose, "query": ".services | to_entries[] | select(.value.ports) | .key"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "\"web\"\n", output)
+
+	manifest := filepath.Join(dir, "Cargo.toml")
+	testutils.AssertNoError(t, os.WriteFile(manifest, []byte(`[package]
+name = "demo"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"
+tokio = { version = "1", features = ["full"] }
+`), 0600))
+	output, err = runTransform(t, map[string]any{"file": manifest, "query": ".dependencies | keys"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "[\n  \"serde\",\n  \"tokio\"\n]\n", output)
+
+	_, err = runTransform(t, map[string]any{"file": "relative/config.json"})
+	testutils.AssertErrorContains(t, err, "absolute path")
+}
+
+func TestTransform_Convert(t *testing.T) {
+	input := `{"name": "demo", "replicas": 3, "big": 12345678901234567890, "ratio": 0.5, "tags": ["a", "b"], "limits": {"cpu": "500m"}}`
+
+	output, err := runTransform(t, map[string]any{"input": input, "output_format": "yaml"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(output, "replicas: 3\n"))
+	testutils.AssertTrue(t, strings.Contains(output, "big: 12345678901234567890\n"))
+	testutils.AssertTrue(t, strings.Contains(output, "limits:\n  cpu: 500m\n"))
+
+	output, err = runTransform(t, map[string]any{"input": input, "query": "del(.big)", "output_format": "toml"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(output, "name = \"demo\"\n"))
+	testutils.AssertTrue(t, strings.Contains(output, "[limits]\ncpu = \"500m\"\n"))
+
+	// Large integers survive a round trip through JSON
+	output, err = runTransform(t, map[string]any{"input": input, "query": ".big"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "12345678901234567890\n", output)
+
+	// TOML cannot hold nulls, arrays at the top level or several results
+	_, err = runTransform(t, map[string]any{"input": `{"a": null}`, "output_format": "toml"})
+	testutils.AssertErrorContains(t, err, ".a is null")
+	_, err = runTransform(t, map[string]any{"input": `[1, 2]`, "output_format": "toml"})
+	testutils.AssertErrorContains(t, err, "can only hold a table")
+	_, err = runTransform(t, map[string]any{"input": `{"a": {}, "b": {}}`, "query": ".a, .b", "output_format": "toml"})
+	testutils.AssertErrorContains(t, err, "2 results")
+}
+
+func TestTransform_DetectsInlineFormats(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"port": 8080}`, "8080\n"},
+		{"port = 8080\n", "8080\n"},
+		{"port: 8080\n", "8080\n"},
+	}
+	for _, tt := range tests {
+		output, err := runTransform(t, map[string]any{"input": tt.input, "query": ".port"})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, tt.want, output)
+	}
+
+	// Each YAML document is queried in turn
+	output, err := runTransform(t, map[string]any{"input": "kind: Service\n---\nkind: Deployment\n", "query": ".kind", "raw": true})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Service\nDeployment\n", output)
+
+	// YAML timestamps become strings
+	output, err = runTransform(t, map[string]any{"input": "released: 2026-03-01T09:30:00Z\n", "query": ".released | fromdateiso8601"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "1772357400\n", output)
+}
+
+func TestTransform_QueryErrors(t *testing.T) {
+	_, err := runTransform(t, map[string]any{"input": `{"a": 1}`, "query": ".a |"})
+	testutils.AssertErrorContains(t, err, "invalid query")
+
+	_, err = runTransform(t, map[string]any{"input": `{"a": 1}`, "query": ".a[]"})
+	testutils.AssertErrorContains(t, err, "query failed")
+
+	_, err = runTransform(t, map[string]any{"input": `{"a": 1}`, "query": "range(20000)"})
+	testutils.AssertErrorContains(t, err, "more than 10000 results")
+
+	// The server's environment is not visible to queries
+	t.Setenv("TRANSFORM_TEST_SECRET", "hunter2")
+	output, err := runTransform(t, map[string]any{"input": `{}`, "query": "$ENV.TRANSFORM_TEST_SECRET"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "null\n", output)
+
+	_, err = runTransform(t, map[string]any{"input": "{}", "file": "/tmp/x.json"})
+	testutils.AssertErrorContains(t, err, "not both")
+	_, err = runTransform(t, map[string]any{"input": "not: [valid", "input_format": "yaml"})
+	testutils.AssertErrorContains(t, err, "invalid YAML")
+}
@@ -0,0 +1,138 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/usagetool"
+	"github.com/sammcj/mcp-devtools/internal/usage"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// runUsage calls the usage tool and returns its decoded response
+func runUsage(t *testing.T, args map[string]any) (usagetool.Response, error) {
+	t.Helper()
+	var response usagetool.Response
+	result, err := (&usagetool.UsageTool{}).Execute(t.Context(), quietLogger(), &sync.Map{}, args)
+	if err != nil {
+		return response, err
+	}
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return response, nil
+}
+
+func TestUsage_BlocksAtLimit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USAGE_LIMIT_BRAVE", "3")
+	t.Setenv(usage.ModeEnvVar, "")
+
+	for range 3 {
+		testutils.AssertNoError(t, usage.Consume("brave"))
+	}
+	err := usage.Consume("brave")
+	testutils.AssertTrue(t, errors.Is(err, usage.ErrLimitReached))
+	testutils.AssertErrorContains(t, err, "USAGE_LIMIT_BRAVE")
+	// Providers without a cap are only counted
+	testutils.AssertNoError(t, usage.Consume("github"))
+
+	counts, err := usage.Counts(1)
+	testutils.AssertNoError(t, err)
+	today := time.Now().UTC().Format("2006-01-02")
+	testutils.AssertEqual(t, 3, counts[today]["brave"])
+	testutils.AssertEqual(t, 1, counts[today]["github"])
+
+	response, err := runUsage(t, map[string]any{"provider": "brave", "days": float64(1)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "block", response.Mode)
+	testutils.AssertEqual(t, 1, len(response.Providers))
+	brave := response.Providers[0]
+	testutils.AssertEqual(t, 3, brave.Today)
+	testutils.AssertEqual(t, 3, brave.Limit)
+	testutils.AssertEqual(t, 0, *brave.Remaining)
+	testutils.AssertEqual(t, "used up", brave.Status)
+	testutils.AssertEqual(t, 0, len(brave.History))
+}
+
+func TestUsage_WaitsForOtherProcesses(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	testutils.AssertNoError(t, usage.Consume("github"))
+	path, err := usage.Path()
+	testutils.AssertNoError(t, err)
+
+	// Another server process holds the lock while it updates the counts
+	lock := flock.New(path + ".lock")
+	testutils.AssertNoError(t, lock.Lock())
+	done := make(chan error)
+	go func() { done <- usage.Consume("github") }()
+	select {
+	case <-done:
+		t.Fatal("Consume did not wait for the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	testutils.AssertNoError(t, lock.Unlock())
+	testutils.AssertNoError(t, <-done)
+
+	counts, err := usage.Counts(1)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, counts[time.Now().UTC().Format("2006-01-02")]["github"])
+}
+
+func TestUsage_WarnMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USAGE_LIMIT_OSV", "2")
+	t.Setenv(usage.ModeEnvVar, "warn")
+
+	for range 3 {
+		testutils.AssertNoError(t, usage.Consume("osv"))
+	}
+
+	response, err := runUsage(t, map[string]any{})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "warn", response.Mode)
+	testutils.AssertEqual(t, len(usage.Providers), len(response.Providers))
+	for _, provider := range response.Providers {
+		testutils.AssertEqual(t, 7, len(provider.History))
+		switch provider.Name {
+		case "osv":
+			testutils.AssertEqual(t, 3, provider.Today)
+			testutils.AssertEqual(t, 0, *provider.Remaining)
+			testutils.AssertEqual(t, "used up", provider.Status)
+		default:
+			testutils.AssertEqual(t, "no limit", provider.Status)
+			testutils.AssertTrue(t, provider.Remaining == nil)
+		}
+	}
+}
+
+func TestUsage_NearlyUsedUp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USAGE_LIMIT_GITHUB", "10")
+	t.Setenv(usage.WarnPercentEnvVar, "50")
+
+	for range 5 {
+		testutils.AssertNoError(t, usage.Consume("github"))
+	}
+	response, err := runUsage(t, map[string]any{"provider": "github"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 5, *response.Providers[0].Remaining)
+	testutils.AssertEqual(t, "nearly used up", response.Providers[0].Status)
+}
+
+func TestUsage_InvalidArguments(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := runUsage(t, map[string]any{"provider": "openai"})
+	testutils.AssertErrorContains(t, err, "unknown provider: openai")
+	_, err = runUsage(t, map[string]any{"days": float64(0)})
+	testutils.AssertErrorContains(t, err, "days must be a whole number")
+}
+
+func TestUsage_ResetTime(t *testing.T) {
+	reset := usage.ResetTime(time.Date(2026, 10, 18, 23, 59, 0, 0, time.FixedZone("AEDT", 11*60*60)))
+	testutils.AssertEqual(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), reset)
+}
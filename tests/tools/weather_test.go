@@ -0,0 +1,262 @@
+package tools_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/weather"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// weatherHTTPClient serves canned responses by host and path, as forecast queries are long, and
+// records each request so tests can check its parameters
+type weatherHTTPClient struct {
+	responses map[string]string
+	requested []*http.Request
+	// err, when set, is returned for every request, as a transport failure
+	err error
+}
+
+func (c *weatherHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req)
+	if c.err != nil {
+		return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: c.err}
+	}
+	body, ok := c.responses[req.URL.Host+req.URL.Path]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(`{"error": true, "reason": "Parameter 'start_date' is out of allowed range"}`)), Header: http.Header{}}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+}
+
+// query returns the parameters of the ith request
+func (c *weatherHTTPClient) query(i int) url.Values {
+	return c.requested[i].URL.Query()
+}
+
+const springfieldPlaces = `{"results": [
+	{"name": "Springfield", "latitude": 37.21533, "longitude": -93.29824, "elevation": 394, "timezone": "America/Chicago", "country": "United States", "country_code": "US", "admin1": "Missouri", "admin2": "Greene", "population": 169176},
+	{"name": "Springfield", "latitude": 39.80172, "longitude": -89.64371, "elevation": 182, "timezone": "America/Chicago", "country": "United States", "country_code": "US", "admin1": "Illinois", "admin2": "Sangamon", "population": 114394},
+	{"name": "Springfield", "latitude": 42.10148, "longitude": -72.58981, "elevation": 21, "timezone": "America/New_York", "country": "United States", "country_code": "US", "admin1": "Massachusetts", "admin2": "Hampden", "population": 155929}
+]}`
+
+const dailyForecast = `{"latitude": 39.8, "longitude": -89.64, "elevation": 182, "timezone": "America/Chicago", "timezone_abbreviation": "GMT-5",
+	"current_units": {"temperature_2m": "°C", "apparent_temperature": "°C", "relative_humidity_2m": "%", "precipitation": "mm", "wind_speed_10m": "km/h", "wind_gusts_10m": "km/h"},
+	"current": {"time": "2026-10-18T09:00", "temperature_2m": 11.4, "apparent_temperature": 9.2, "relative_humidity_2m": 71, "precipitation": 0, "weather_code": 2, "wind_speed_10m": 14.8, "wind_direction_10m": 200, "wind_gusts_10m": 31.3},
+	"daily_units": {"temperature_2m_max": "°C", "precipitation_probability_max": "%", "precipitation_sum": "mm", "wind_speed_10m_max": "km/h", "wind_gusts_10m_max": "km/h"},
+	"daily": {"time": ["2026-10-18", "2026-10-19"], "weather_code": [2, 63], "temperature_2m_min": [6.1, 8], "temperature_2m_max": [16.2, 12.5],
+		"precipitation_probability_max": [5, 85], "precipitation_sum": [0, 12.4], "wind_speed_10m_max": [18.4, 27], "wind_gusts_10m_max": [38.2, null],
+		"uv_index_max": [3.85, 1.2], "sunrise": ["2026-10-18T07:08", "2026-10-19T07:09"], "sunset": ["2026-10-18T18:16", "2026-10-19T18:14"]}}`
+
+const hourlyForecast = `{"latitude": -33.875, "longitude": 151.25, "elevation": 39, "timezone": "Australia/Sydney", "timezone_abbreviation": "GMT+11",
+	"hourly_units": {"temperature_2m": "°F", "apparent_temperature": "°F", "precipitation_probability": "%", "precipitation": "inch", "wind_speed_10m": "mp/h", "wind_gusts_10m": "mp/h"},
+	"hourly": {"time": ["2026-10-20T06:00", "2026-10-20T07:00", "2026-10-20T08:00"], "temperature_2m": [59.1, 61.3, 64], "apparent_temperature": [57, 59.4, 62.2],
+		"precipitation_probability": [10, 45, 20], "precipitation": [0, 0, 0.02], "weather_code": [1, 3, 80],
+		"wind_speed_10m": [6.2, 8.1, 12.4], "wind_direction_10m": [90, 135, 0], "wind_gusts_10m": [11.2, 15.8, 24.6]}}`
+
+func runWeather(t *testing.T, client *weatherHTTPClient, args map[string]any) (string, error) {
+	t.Helper()
+	tool := weather.NewWeatherTool(client)
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+// withoutWeatherKey points the tool at Open-Meteo's free API with no key
+func withoutWeatherKey(t *testing.T) {
+	t.Helper()
+	t.Setenv("OPEN_METEO_API_KEY", "")
+	t.Setenv("WEATHER_FORECAST_URL", "")
+	t.Setenv("WEATHER_GEOCODING_URL", "")
+	t.Setenv("HOME", t.TempDir())
+}
+
+func TestWeatherTool_Definition(t *testing.T) {
+	definition := weather.NewWeatherTool(nil).Definition()
+	testutils.AssertEqual(t, "weather", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, *definition.Annotations.OpenWorldHint)
+}
+
+func TestWeatherTool_Validation(t *testing.T) {
+	withoutWeatherKey(t)
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"unknown action", map[string]any{"action": "radar", "location": "Wellington"}, "action must be one of"},
+		{"no location", map[string]any{}, "missing required parameter: location (or latitude and longitude)"},
+		{"latitude alone", map[string]any{"latitude": float64(-41.29)}, "must be given together"},
+		{"latitude out of range", map[string]any{"latitude": float64(95), "longitude": float64(10)}, "latitude must be between -90 and 90"},
+		{"short name", map[string]any{"location": "X, NZ"}, "at least two characters"},
+		{"too many days", map[string]any{"location": "Wellington", "days": float64(17)}, "days must be a whole number from 1 to 16"},
+		{"fractional days", map[string]any{"location": "Wellington", "days": 2.5}, "days must be a whole number"},
+		{"unknown units", map[string]any{"location": "Wellington", "units": "kelvin"}, "units must be metric or imperial"},
+		{"end without start", map[string]any{"location": "Wellington", "end": "2026-10-20"}, "end needs a start"},
+		{"invalid start", map[string]any{"location": "Wellington", "start": "20/10/2026"}, "use YYYY-MM-DD or YYYY-MM-DDTHH:MM"},
+		{"backwards window", map[string]any{"action": "hourly", "location": "Wellington", "start": "2026-10-20T12:00", "end": "2026-10-20T06:00"}, "is before start"},
+		{"hourly window too long", map[string]any{"action": "hourly", "location": "Wellington", "start": "2026-10-20", "end": "2026-10-27"}, "longer than 168 hours"},
+		{"daily window too long", map[string]any{"location": "Wellington", "start": "2026-10-20", "end": "2026-11-05"}, "longer than 16 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &weatherHTTPClient{}
+			_, err := runWeather(t, client, tt.args)
+			testutils.AssertErrorContains(t, err, tt.want)
+			testutils.AssertEqual(t, 0, len(client.requested))
+		})
+	}
+}
+
+func TestWeatherTool_Geocode(t *testing.T) {
+	withoutWeatherKey(t)
+	client := &weatherHTTPClient{responses: map[string]string{
+		"geocoding-api.open-meteo.com/v1/search": springfieldPlaces,
+	}}
+
+	text, err := runWeather(t, client, map[string]any{"action": "geocode", "location": "Springfield"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Springfield", client.query(0).Get("name"))
+	testutils.AssertEqual(t, "10", client.query(0).Get("count"))
+	testutils.AssertTrue(t, testutils.Contains(text, `# Places Matching "Springfield"`))
+	testutils.AssertTrue(t, testutils.Contains(text, "1. **Springfield, Missouri, United States**: 37.2153, -93.2982, elevation 394 m, time zone America/Chicago, population 169176"))
+	testutils.AssertTrue(t, testutils.Contains(text, "3. **Springfield, Massachusetts, United States**"))
+
+	// Only the name is searched; the region and country after it filter the results
+	text, err = runWeather(t, client, map[string]any{"action": "geocode", "location": "Springfield, illinois, US"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Springfield", client.query(1).Get("name"))
+	testutils.AssertTrue(t, testutils.Contains(text, "1. **Springfield, Illinois, United States**"))
+	testutils.AssertTrue(t, !testutils.Contains(text, "Missouri"))
+
+	_, err = runWeather(t, client, map[string]any{"action": "geocode", "location": "Springfield, Tasmania"})
+	testutils.AssertErrorContains(t, err, `no place found matching "Springfield, Tasmania"`)
+}
+
+func TestWeatherTool_DailyForecast(t *testing.T) {
+	withoutWeatherKey(t)
+	client := &weatherHTTPClient{responses: map[string]string{
+		"geocoding-api.open-meteo.com/v1/search": springfieldPlaces,
+		"api.open-meteo.com/v1/forecast":         dailyForecast,
+	}}
+
+	text, err := runWeather(t, client, map[string]any{"location": "Springfield, Illinois", "days": float64(2)})
+	testutils.AssertNoError(t, err)
+	query := client.query(1)
+	testutils.AssertEqual(t, "39.8017", query.Get("latitude"))
+	testutils.AssertEqual(t, "-89.6437", query.Get("longitude"))
+	testutils.AssertEqual(t, "auto", query.Get("timezone"))
+	testutils.AssertEqual(t, "2", query.Get("forecast_days"))
+	testutils.AssertEqual(t, "", query.Get("hourly"))
+	testutils.AssertEqual(t, "", query.Get("temperature_unit"))
+
+	testutils.AssertTrue(t, testutils.Contains(text, "# Weather for Springfield, Illinois, United States"))
+	testutils.AssertTrue(t, testutils.Contains(text, "Forecast for 39.8000, -89.6400, elevation 182 m. Times are local (America/Chicago, GMT-5)."))
+	testutils.AssertTrue(t, testutils.Contains(text, "2026-10-18 09:00: Partly cloudy, 11.4°C (feels like 9.2°C), humidity 71%, wind 14.8 km/h S gusting to 31.3 km/h, precipitation 0 mm."))
+	testutils.AssertTrue(t, testutils.Contains(text, "| Sun 2026-10-18 | Partly cloudy | 6.1 to 16.2°C | 5% | 0 mm | 18.4 km/h | 38.2 km/h | 3.85 | 07:08–18:16 |"))
+	testutils.AssertTrue(t, testutils.Contains(text, "| Mon 2026-10-19 | Rain | 8 to 12.5°C | 85% | 12.4 mm | 27 km/h | – | 1.2 | 07:09–18:14 |"))
+	testutils.AssertTrue(t, testutils.Contains(text, "Weather data by Open-Meteo.com (CC BY 4.0)."))
+
+	// A window asks for its dates instead of a number of days
+	_, err = runWeather(t, client, map[string]any{"latitude": 39.8, "longitude": -89.64, "start": "2026-10-19T14:00", "end": "2026-10-21"})
+	testutils.AssertNoError(t, err)
+	query = client.query(2)
+	testutils.AssertEqual(t, "2026-10-19", query.Get("start_date"))
+	testutils.AssertEqual(t, "2026-10-21", query.Get("end_date"))
+	testutils.AssertEqual(t, "", query.Get("forecast_days"))
+
+	// Open-Meteo's reason for rejecting a request is passed on
+	delete(client.responses, "api.open-meteo.com/v1/forecast")
+	_, err = runWeather(t, client, map[string]any{"latitude": 39.8, "longitude": -89.64, "start": "2027-01-01"})
+	testutils.AssertErrorContains(t, err, "returned status code 400: Parameter 'start_date' is out of allowed range")
+}
+
+func TestWeatherTool_HourlyWindow(t *testing.T) {
+	withoutWeatherKey(t)
+	client := &weatherHTTPClient{responses: map[string]string{
+		"api.open-meteo.com/v1/forecast": hourlyForecast,
+	}}
+
+	text, err := runWeather(t, client, map[string]any{
+		"action":    "hourly",
+		"latitude":  -33.8688,
+		"longitude": 151.2093,
+		"start":     "2026-10-20T06:30",
+		"end":       "2026-10-20T08:00",
+		"units":     "imperial",
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(client.requested))
+	query := client.query(0)
+	testutils.AssertEqual(t, "2026-10-20T06:00", query.Get("start_hour"))
+	testutils.AssertEqual(t, "2026-10-20T08:00", query.Get("end_hour"))
+	testutils.AssertEqual(t, "", query.Get("daily"))
+	testutils.AssertEqual(t, "fahrenheit", query.Get("temperature_unit"))
+	testutils.AssertEqual(t, "mph", query.Get("wind_speed_unit"))
+	testutils.AssertEqual(t, "inch", query.Get("precipitation_unit"))
+
+	testutils.AssertTrue(t, testutils.Contains(text, "# Weather at -33.8750, 151.2500"))
+	testutils.AssertTrue(t, !testutils.Contains(text, "## Now"))
+	testutils.AssertTrue(t, testutils.Contains(text, "| 2026-10-20 07:00 | Overcast | 61.3°F | 59.4°F | 45% | 0 inch | 8.1 mp/h SE | 15.8 mp/h |"))
+	testutils.AssertTrue(t, testutils.Contains(text, "| 2026-10-20 08:00 | Light showers | 64°F | 62.2°F | 20% | 0.02 inch | 12.4 mp/h N | 24.6 mp/h |"))
+	testutils.AssertTrue(t, testutils.Contains(text, "**Window summary:** 59.1 to 64°F; rain chance up to 45%; 0.02 inch of precipitation in total; wind up to 12.4 mp/h, gusts up to 24.6 mp/h; 1 of 3 hours dry"))
+
+	// Without a window the next 24 hours are forecast, and a start date alone covers its day
+	_, err = runWeather(t, client, map[string]any{"action": "hourly", "latitude": -33.8688, "longitude": 151.2093})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "24", client.query(1).Get("forecast_hours"))
+	_, err = runWeather(t, client, map[string]any{"action": "hourly", "latitude": -33.8688, "longitude": 151.2093, "start": "2026-10-21"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "2026-10-21T00:00", client.query(2).Get("start_hour"))
+	testutils.AssertEqual(t, "2026-10-21T23:00", client.query(2).Get("end_hour"))
+}
+
+func TestWeatherTool_APIKey(t *testing.T) {
+	withoutWeatherKey(t)
+	t.Setenv("OPEN_METEO_API_KEY", "test-key")
+	client := &weatherHTTPClient{responses: map[string]string{
+		"customer-api.open-meteo.com/v1/forecast": hourlyForecast,
+	}}
+	cache := testutils.CreateTestCache()
+	tool := weather.NewWeatherTool(client)
+	args := map[string]any{"action": "hourly", "latitude": -33.8688, "longitude": 151.2093}
+
+	_, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), cache, args)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "test-key", client.query(0).Get("apikey"))
+	cache.Range(func(key, _ any) bool {
+		testutils.AssertTrue(t, !testutils.Contains(key.(string), "test-key"))
+		return true
+	})
+
+	// A repeated lookup is served from the cache
+	_, err = tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), cache, args)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, len(client.requested))
+
+	// The key never appears in errors, even when the transport's error repeats the URL
+	client.err = errors.New("connection refused")
+	_, err = runWeather(t, client, map[string]any{"latitude": -33.8688, "longitude": 151.2093})
+	testutils.AssertErrorContains(t, err, "connection refused")
+	testutils.AssertTrue(t, !testutils.Contains(err.Error(), "test-key"))
+
+	// A self-hosted instance is used instead when configured
+	client.err = nil
+	client.responses["weather.internal.example/v1/forecast"] = hourlyForecast
+	t.Setenv("WEATHER_FORECAST_URL", "https://weather.internal.example/v1/forecast")
+	_, err = runWeather(t, client, args)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "weather.internal.example", client.requested[len(client.requested)-1].URL.Host)
+}
@@ -0,0 +1,108 @@
+package tools_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/oauth/access"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/internal/tools/webfetch"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// fetchBinary serves body with a content type and returns fetch_url's decoded response
+func fetchBinary(t *testing.T, path, contentType string, body []byte) webfetch.FetchURLResponse {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	result, err := (&webfetch.FetchURLTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"url": server.URL + path})
+	testutils.AssertNoError(t, err)
+	var response webfetch.FetchURLResponse
+	testutils.AssertNoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	return response
+}
+
+func TestFetchURL_SavesBinaryContentAsArtifact(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(webfetch.FetchConfigEnvVar, filepath.Join(t.TempDir(), "fetch.yaml"))
+	body := []byte("PK\x03\x04\x00\x00\x00\x00binary\x00\xff\xfe")
+
+	response := fetchBinary(t, "/releases/tool.zip", "application/zip", body)
+	testutils.AssertTrue(t, response.Artifact != nil)
+	testutils.AssertEqual(t, "tool.zip", response.Artifact.Name)
+	testutils.AssertEqual(t, "application/zip", response.ContentType)
+	testutils.AssertEqual(t, "", response.ConvertedBy)
+	testutils.AssertTrue(t, strings.Contains(response.Content, fmt.Sprintf("Saved %d bytes of application/zip to %s", len(body), response.Artifact.Path)))
+
+	saved, err := os.ReadFile(response.Artifact.Path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, bytes.Equal(body, saved))
+
+	// Without a useful content type, the type is worked out from the content and named to match
+	response = fetchBinary(t, "/download", "application/octet-stream", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"))
+	testutils.AssertEqual(t, "application/pdf", response.ContentType)
+	testutils.AssertEqual(t, "download.pdf", response.Artifact.Name)
+}
+
+func TestFetchURL_ConvertsDocumentsWithProcessDocument(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(webfetch.FetchConfigEnvVar, filepath.Join(t.TempDir(), "fetch.yaml"))
+	t.Setenv("DOCLING_PYTHON_PATH", filepath.Join(t.TempDir(), "missing", "python"))
+	t.Setenv("DOCLING_CACHE_ENABLED", "false")
+	t.Setenv(docprocessing.EnvServeURL, "")
+	t.Setenv("ENABLE_ADDITIONAL_TOOLS", "process_document")
+	registry.Init(quietLogger())
+	registry.Register(&docprocessing.DocumentProcessorTool{})
+
+	source := filepath.Join(t.TempDir(), "plan.docx")
+	writeZip(t, source, map[string]string{"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Release Plan</w:t></w:r></w:p>
+<w:p><w:r><w:t>Ship the beta</w:t></w:r></w:p>
+</w:body></w:document>`})
+	body, err := os.ReadFile(source)
+	testutils.AssertNoError(t, err)
+	docx := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+	response := fetchBinary(t, "/files/plan", docx, body)
+	testutils.AssertEqual(t, "process_document", response.ConvertedBy)
+	testutils.AssertEqual(t, "plan.docx", response.Artifact.Name)
+	testutils.AssertTrue(t, strings.Contains(response.Content, "# Release Plan"))
+	testutils.AssertTrue(t, strings.Contains(response.Content, "Ship the beta"))
+
+	// With convert off, the document is only saved
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", docx)
+		w.Header().Set("Content-Disposition", `attachment; filename="Release Plan.docx"`)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	result, err := (&webfetch.FetchURLTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"url": server.URL, "convert": false})
+	testutils.AssertNoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	testutils.AssertTrue(t, strings.Contains(text, "Release_Plan.docx"))
+	testutils.AssertTrue(t, strings.Contains(text, "Convert it to markdown with process_document"))
+	testutils.AssertTrue(t, !strings.Contains(text, "converted_by"))
+
+	// A caller who may not use process_document cannot convert documents through fetch_url
+	policy, err := access.ParsePolicy([]byte("default:\n  tools: [fetch_url]\n"))
+	testutils.AssertNoError(t, err)
+	access.SetPolicy(policy)
+	t.Cleanup(func() { access.SetPolicy(nil) })
+	response = fetchBinary(t, "/files/plan", docx, body)
+	testutils.AssertEqual(t, "", response.ConvertedBy)
+	testutils.AssertTrue(t, strings.Contains(response.Content, "not permitted to use the process_document tool"))
+}
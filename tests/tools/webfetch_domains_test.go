@@ -0,0 +1,89 @@
+package tools_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/webfetch"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// writeFetchConfig writes a fetch configuration and points the fetch tool at it
+func writeFetchConfig(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fetch.yaml")
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(config), 0600))
+	t.Setenv(webfetch.FetchConfigEnvVar, path)
+	return dir
+}
+
+func TestFetchURL_AppliesDomainHeadersAndCookies(t *testing.T) {
+	// A site that only serves clients sending its token, and sets a session cookie
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Internal-Token") != "s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ua=" + r.UserAgent() + "\ncookie=" + r.Header.Get("Cookie")))
+	}))
+	defer server.Close()
+	host, _ := url.Parse(server.URL)
+
+	t.Setenv("INTERNAL_TOKEN", "s3cret")
+	dir := writeFetchConfig(t, "")
+	cookiesFile := filepath.Join(dir, "cookies.txt")
+	testutils.AssertNoError(t, os.WriteFile(cookiesFile, []byte(strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		host.Hostname() + "\tFALSE\t/\tFALSE\t0\tsso\tfrom-browser",
+		"#HttpOnly_" + host.Hostname() + "\tFALSE\t/\tFALSE\t0\tcsrf\tx1",
+		host.Hostname() + "\tFALSE\t/admin\tFALSE\t0\tadmin\tno",
+		host.Hostname() + "\tFALSE\t/\tFALSE\t1\texpired\tno",
+		"other.example\tTRUE\t/\tFALSE\t0\telsewhere\tno",
+	}, "\n")), 0600))
+	writeFetchConfig(t, `domains:
+  - match: "*"
+    user_agent: generic-agent
+  - match: `+host.Hostname()+`
+    user_agent: internal-agent/2.0
+    headers:
+      x-internal-token: ${env:INTERNAL_TOKEN}
+    cookies:
+      team: platform
+    cookies_file: `+cookiesFile+`
+    keep_cookies: true
+`)
+
+	fetch := func() string {
+		t.Helper()
+		result, err := (&webfetch.FetchURLTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"url": server.URL + "/docs"})
+		testutils.AssertNoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// The more specific rule overrides the user agent, and only cookies for the host and path are sent
+	text := fetch()
+	testutils.AssertTrue(t, strings.Contains(text, "ua=internal-agent/2.0"))
+	testutils.AssertTrue(t, strings.Contains(text, "cookie=csrf=x1; sso=from-browser; team=platform"))
+
+	// The session cookie the site set is sent with later requests
+	text = fetch()
+	testutils.AssertTrue(t, strings.Contains(text, "cookie=csrf=x1; session=abc; sso=from-browser; team=platform"))
+
+	// Without a matching rule, the site refuses the request
+	writeFetchConfig(t, "domains:\n  - match: \"*.example.com\"\n    user_agent: other\n")
+	text = fetch()
+	testutils.AssertTrue(t, !strings.Contains(text, "ua="))
+
+	writeFetchConfig(t, "domains:\n  - user_agent: missing-match\n")
+	_, err := (&webfetch.FetchURLTool{}).Execute(t.Context(), quietLogger(), nil, map[string]any{"url": server.URL})
+	testutils.AssertErrorContains(t, err, "match is required")
+}
@@ -289,7 +289,7 @@ func TestProcessContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := webfetch.ProcessContent(logger, tt.response, tt.raw, "")
+			result, err := webfetch.ProcessContent(logger, tt.response, tt.raw, "", false)
 
 			if tt.expectError {
 				testutils.AssertError(t, err)
@@ -761,7 +761,7 @@ func TestProcessContentWithFragment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := webfetch.ProcessContent(logger, tt.response, false, tt.fragment)
+			result, err := webfetch.ProcessContent(logger, tt.response, false, tt.fragment, false)
 			testutils.AssertNoError(t, err)
 
 			for _, expected := range tt.expectContains {
@@ -921,3 +921,67 @@ func TestFilterHTMLByFragmentEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// Test ProcessContent with main content extraction
+func TestProcessContentWithReadability(t *testing.T) {
+	logger := testutils.CreateTestLogger()
+	prose := strings.Repeat("Rolling deployments replace instances gradually, so capacity stays up while the new version is checked. ", 4)
+
+	tests := []struct {
+		name           string
+		content        string
+		expectContains []string
+		notContains    []string
+	}{
+		{
+			name: "semantic main element",
+			content: `<html><head><title>Deploying Safely</title></head><body>
+				<div class="site-menu"><a href="/">Docs</a><a href="/blog">Blog</a></div>
+				<main><h2>Rolling Deployments</h2><p>` + prose + `</p>
+				<div class="share-buttons">Share this page on social media today</div></main>
+				<div id="cookie-consent">We use cookies to improve your experience here</div>
+			</body></html>`,
+			expectContains: []string{"# Deploying Safely", "Rolling Deployments", "capacity stays up"},
+			notContains:    []string{"Blog", "Share this page", "We use cookies"},
+		},
+		{
+			name: "highest scoring container without semantic markup",
+			content: `<html><body>
+				<div id="sidebar"><ul><li><a href="/a">A very long link in the sidebar list one</a></li><li><a href="/b">A very long link in the sidebar list two</a></li></ul></div>
+				<div class="related"><p>Related: ten tips for faster builds, and more from the archive</p></div>
+				<div><p><a href="/x">Another article you may enjoy reading, part one</a></p><p><a href="/y">Another article you may enjoy reading, part two</a></p></div>
+				<div class="layout"><div class="post-body"><h1>Release Notes</h1><p>` + prose + `</p><p>` + prose + `</p></div>
+				<div class="ad-slot"><p>Buy our hosting, now with thirty percent off, today only</p></div></div>
+			</body></html>`,
+			expectContains: []string{"# Release Notes", "capacity stays up"},
+			notContains:    []string{"sidebar list", "ten tips", "you may enjoy", "Buy our hosting"},
+		},
+		{
+			name: "page without main content keeps everything but boilerplate",
+			content: `<html><body><nav>Navigation links</nav>
+				<h1>Status</h1><p>All systems are operational right now</p></body></html>`,
+			expectContains: []string{"Status", "All systems are operational"},
+			notContains:    []string{"Navigation links"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &webfetch.FetchURLResponse{ContentType: "text/html", StatusCode: 200, Content: tt.content}
+			result, err := webfetch.ProcessContent(logger, response, false, "", true)
+			testutils.AssertNoError(t, err)
+
+			for _, expected := range tt.expectContains {
+				if !testutils.Contains(result, expected) {
+					t.Errorf("Expected result to contain '%s', got: %s", expected, result)
+				}
+			}
+
+			for _, unexpected := range tt.notContains {
+				if testutils.Contains(result, unexpected) {
+					t.Errorf("Expected result NOT to contain '%s', got: %s", unexpected, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package tools_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/tools/wikipedia"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// wikipediaHTTPClient serves canned responses by exact URL and 404 for anything else
+type wikipediaHTTPClient struct {
+	responses map[string]string
+	requested []*http.Request
+}
+
+func (c *wikipediaHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req)
+	body, ok := c.responses[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+}
+
+const wikipediaSummary = `{"type": "standard", "title": "Widget Bay", "description": "Bay in New Zealand",
+	"extract": "Widget Bay is a bay on the coast of Wellington.", "wikibase_item": "Q100",
+	"content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Widget_Bay"}}, "coordinates": {"lat": -41.3, "lon": 174.8}}`
+
+const wikipediaArticle = `<!DOCTYPE html><html><head><title>Widget Bay</title><link rel="stylesheet" href="/x.css"></head><body>
+<section data-mw-section-id="0"><div class="hatnote">For the town, see Widget.</div>
+<table class="infobox"><tr><th>Area</th><td>12 km²</td></tr></table>
+<p>Widget Bay is a bay near <a rel="mw:WikiLink" href="./Wellington">Wellington</a>.<sup class="mw-ref reference"><a href="#cite_note-1">[1]</a></sup></p></section>
+<section data-mw-section-id="1"><h2 id="History">History</h2><p>The bay was named in 1840. See <a rel="mw:ExtLink" href="https://example.org/history">the archive</a>.</p>
+<section data-mw-section-id="2"><h3 id="Surveys">Surveys</h3><p>It was first surveyed in 1850.</p></section></section>
+<section data-mw-section-id="3"><h2 id="Tides">Tides</h2><table class="wikitable"><tr><th>Month</th><th>Range</th></tr><tr><td>January</td><td>1.4 m</td></tr></table></section>
+<section data-mw-section-id="4"><h2 id="References">References</h2><div class="mw-references-wrap"><ol class="references"><li>A source</li></ol></div></section>
+</body></html>`
+
+const wikipediaEntity = `{"entities": {"Q100": {"id": "Q100",
+	"labels": {"en": {"value": "Widget Bay"}}, "descriptions": {"en": {"value": "bay in New Zealand"}},
+	"claims": {
+		"P31": [{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "wikibase-item", "datavalue": {"type": "wikibase-entityid", "value": {"id": "Q39594"}}}}],
+		"P2046": [{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "quantity", "datavalue": {"type": "quantity", "value": {"amount": "+12", "unit": "http://www.wikidata.org/entity/Q712226"}}}}],
+		"P571": [{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "time", "datavalue": {"type": "time", "value": {"time": "+1840-00-00T00:00:00Z", "precision": 9}}}}],
+		"P1082": [
+			{"rank": "deprecated", "mainsnak": {"snaktype": "value", "datatype": "quantity", "datavalue": {"type": "quantity", "value": {"amount": "+9999", "unit": "1"}}}},
+			{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "quantity", "datavalue": {"type": "quantity", "value": {"amount": "+300", "unit": "1"}}}},
+			{"rank": "preferred", "mainsnak": {"snaktype": "value", "datatype": "quantity", "datavalue": {"type": "quantity", "value": {"amount": "+350", "unit": "1"}}}}
+		],
+		"P625": [{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "globe-coordinate", "datavalue": {"type": "globecoordinate", "value": {"latitude": -41.3, "longitude": 174.8}}}}],
+		"P214": [{"rank": "normal", "mainsnak": {"snaktype": "value", "datatype": "external-id", "datavalue": {"type": "string", "value": "12345"}}}]
+	}}}}`
+
+const wikipediaLabels = `{"entities": {
+	"P31": {"labels": {"en": {"value": "instance of"}}},
+	"P571": {"labels": {"en": {"value": "inception"}}},
+	"P625": {"labels": {"en": {"value": "coordinate location"}}},
+	"P1082": {"labels": {"en": {"value": "population"}}},
+	"P2046": {"labels": {"en": {"value": "area"}}},
+	"Q39594": {"labels": {"en": {"value": "bay"}}},
+	"Q712226": {"labels": {"en": {"value": "square kilometre"}}}
+}}`
+
+func runWikipedia(t *testing.T, client *wikipediaHTTPClient, args map[string]any) (string, error) {
+	t.Helper()
+	tool := wikipedia.NewWikipediaTool(client)
+	result, err := tool.Execute(testutils.CreateTestContext(), testutils.CreateTestLogger(), testutils.CreateTestCache(), args)
+	if err != nil {
+		return "", err
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return text.Text, nil
+}
+
+func TestWikipediaTool_Definition(t *testing.T) {
+	tool := &wikipedia.WikipediaTool{}
+	definition := tool.Definition()
+
+	testutils.AssertEqual(t, "wikipedia", definition.Name)
+	testutils.AssertTrue(t, *definition.Annotations.ReadOnlyHint)
+	testutils.AssertTrue(t, !*definition.Annotations.DestructiveHint)
+	testutils.AssertTrue(t, *definition.Annotations.OpenWorldHint)
+}
+
+func TestWikipediaTool_Validation(t *testing.T) {
+	client := &wikipediaHTTPClient{}
+
+	_, err := runWikipedia(t, client, map[string]any{})
+	testutils.AssertErrorContains(t, err, "missing required parameter: title")
+
+	_, err = runWikipedia(t, client, map[string]any{"action": "search"})
+	testutils.AssertErrorContains(t, err, "missing required parameter: query")
+
+	_, err = runWikipedia(t, client, map[string]any{"action": "edit", "title": "Widget Bay"})
+	testutils.AssertErrorContains(t, err, "action must be one of")
+
+	_, err = runWikipedia(t, client, map[string]any{"title": "Widget Bay", "language": "evil.example.com/"})
+	testutils.AssertErrorContains(t, err, "invalid language")
+
+	_, err = runWikipedia(t, client, map[string]any{"action": "facts", "wikidata_id": "P31"})
+	testutils.AssertErrorContains(t, err, "invalid wikidata_id")
+
+	testutils.AssertEqual(t, 0, len(client.requested))
+}
+
+func TestWikipediaTool_Summary(t *testing.T) {
+	client := &wikipediaHTTPClient{responses: map[string]string{
+		"https://de.wikipedia.org/api/rest_v1/page/summary/Widget_Bay": wikipediaSummary,
+	}}
+
+	text, err := runWikipedia(t, client, map[string]any{"title": "Widget Bay", "language": "DE"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Widget Bay\n\n_Bay in New Zealand_\n\nWidget Bay is a bay on the coast of Wellington.\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "Wikidata: Q100\nCoordinates: -41.30000, 174.80000"))
+	testutils.AssertTrue(t, strings.Contains(client.requested[0].Header.Get("User-Agent"), "github.com/sammcj/mcp-devtools"))
+
+	_, err = runWikipedia(t, client, map[string]any{"title": "Missing Bay", "language": "de"})
+	testutils.AssertErrorContains(t, err, `no article titled "Missing Bay" on de.wikipedia.org`)
+}
+
+func TestWikipediaTool_Article(t *testing.T) {
+	client := &wikipediaHTTPClient{responses: map[string]string{
+		"https://en.wikipedia.org/api/rest_v1/page/html/Widget_Bay": wikipediaArticle,
+	}}
+
+	text, err := runWikipedia(t, client, map[string]any{"action": "article", "title": "Widget Bay"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Widget Bay\n\nSource: https://en.wikipedia.org/wiki/Widget_Bay\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "Widget Bay is a bay near Wellington."))
+	testutils.AssertTrue(t, strings.Contains(text, "[the archive](https://example.org/history)"))
+	testutils.AssertTrue(t, strings.Contains(text, "| January | 1.4 m |"))
+	for _, removed := range []string{"[1]", "For the town", "12 km²", "References", "A source"} {
+		testutils.AssertTrue(t, !strings.Contains(text, removed))
+	}
+
+	// A section comes with its subsections
+	text, err = runWikipedia(t, client, map[string]any{"action": "article", "title": "Widget Bay", "section": "history"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Widget Bay § History\n\nSource: https://en.wikipedia.org/wiki/Widget_Bay#History\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "first surveyed in 1850"))
+	testutils.AssertTrue(t, !strings.Contains(text, "January"))
+
+	_, err = runWikipedia(t, client, map[string]any{"action": "article", "title": "Widget Bay", "section": "Economy"})
+	testutils.AssertErrorContains(t, err, "section not found: Economy (sections: History, Tides)")
+
+	// A truncated article lists its sections, so one can be asked for
+	long := strings.Repeat("<p>The bay is long and sheltered from the southerly wind.</p>", 20)
+	client.responses["https://en.wikipedia.org/api/rest_v1/page/html/Long_Bay"] = strings.Replace(wikipediaArticle, "<p>The bay was named in 1840.", long+"<p>", 1)
+	text, err = runWikipedia(t, client, map[string]any{"action": "article", "title": "Long Bay", "max_length": float64(500)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(text, "[Truncated: showing"))
+	testutils.AssertTrue(t, strings.Contains(text, "Ask for one section with section: History, Tides]"))
+}
+
+func TestWikipediaTool_Facts(t *testing.T) {
+	client := &wikipediaHTTPClient{responses: map[string]string{
+		"https://en.wikipedia.org/api/rest_v1/page/summary/Widget_Bay": wikipediaSummary,
+		"https://www.wikidata.org/wiki/Special:EntityData/Q100.json":   wikipediaEntity,
+		"https://www.wikidata.org/w/api.php?action=wbgetentities&format=json&ids=P31%7CP571%7CP625%7CP1082%7CP2046%7CQ39594%7CQ712226&languagefallback=1&languages=en&props=labels": wikipediaLabels,
+	}}
+
+	text, err := runWikipedia(t, client, map[string]any{"action": "facts", "title": "Widget Bay"})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "# Widget Bay (Q100)\n\n_bay in New Zealand_\n\nSource: https://www.wikidata.org/wiki/Q100\n"))
+
+	// Properties are listed in id order, with preferred values in place of normal ones
+	want := "- **instance of** (P31): bay (Q39594)\n" +
+		"- **inception** (P571): 1840\n" +
+		"- **coordinate location** (P625): -41.30000, 174.80000\n" +
+		"- **population** (P1082): 350\n" +
+		"- **area** (P2046): 12 square kilometre\n"
+	testutils.AssertTrue(t, strings.Contains(text, want))
+	testutils.AssertTrue(t, strings.Contains(text, "1 identifiers in other databases left out"))
+	testutils.AssertTrue(t, !strings.Contains(text, "12345"))
+}
+
+func TestWikipediaTool_Search(t *testing.T) {
+	client := &wikipediaHTTPClient{responses: map[string]string{
+		"https://en.wikipedia.org/w/rest.php/v1/search/page?limit=2&q=widget+bay": `{"pages": [
+			{"key": "Widget_Bay", "title": "Widget Bay", "description": "Bay in New Zealand", "excerpt": "<span class=\"searchmatch\">Widget</span> <span class=\"searchmatch\">Bay</span> is a bay"},
+			{"key": "Widget", "title": "Widget", "excerpt": "A town near the bay"}
+		]}`,
+	}}
+
+	text, err := runWikipedia(t, client, map[string]any{"action": "search", "query": "widget bay", "limit": float64(2)})
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.HasPrefix(text, "Found 2 articles on en.wikipedia.org for: widget bay\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "1. **Widget Bay** - Bay in New Zealand\n   https://en.wikipedia.org/wiki/Widget_Bay\n   Widget Bay is a bay...\n"))
+	testutils.AssertTrue(t, strings.Contains(text, "2. **Widget**\n"))
+}
@@ -0,0 +1,135 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/toolcall"
+	"github.com/sammcj/mcp-devtools/internal/tools/docprocessing"
+	"github.com/sammcj/mcp-devtools/internal/tools/excel"
+	"github.com/sammcj/mcp-devtools/internal/workpool"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// queueStats returns the named queue's stats
+func queueStats(t *testing.T, name string) workpool.Stats {
+	t.Helper()
+	for _, stats := range workpool.Snapshot() {
+		if stats.Queue == name {
+			return stats
+		}
+	}
+	t.Fatalf("queue %s not found", name)
+	return workpool.Stats{}
+}
+
+// waitForWaiting waits until the named queue has the given number of waiting calls
+func waitForWaiting(t *testing.T, name string, waiting int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for queueStats(t, name).Waiting != waiting {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiting calls in %s, got %+v", waiting, name, queueStats(t, name))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkpool_QueuesInOrderUpToDepth(t *testing.T) {
+	t.Setenv(workpool.WorkersEnvVar+"_TEST_ORDER", "1")
+	t.Setenv(workpool.DepthEnvVar+"_TEST_ORDER", "2")
+
+	release, err := workpool.Acquire(t.Context(), "test_order", nil)
+	testutils.AssertNoError(t, err)
+
+	var mu sync.Mutex
+	var positions []int
+	var started []string
+	var wg sync.WaitGroup
+	for i, name := range []string{"first", "second"} {
+		wg.Go(func() {
+			releaseNext, err := workpool.Acquire(t.Context(), "test_order", func(position int) {
+				if name == "second" {
+					mu.Lock()
+					positions = append(positions, position)
+					mu.Unlock()
+				}
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			started = append(started, name)
+			mu.Unlock()
+			releaseNext()
+		})
+		// Each call joins the queue before the next is made
+		waitForWaiting(t, "test_order", i+1)
+	}
+
+	// The queue is full, so a third call is refused
+	_, err = workpool.Acquire(t.Context(), "test_order", nil)
+	testutils.AssertTrue(t, errors.Is(err, workpool.ErrQueueFull))
+	testutils.AssertErrorContains(t, err, "WORK_QUEUE_DEPTH_TEST_ORDER")
+
+	stats := queueStats(t, "test_order")
+	testutils.AssertEqual(t, 1, stats.Workers)
+	testutils.AssertEqual(t, 1, stats.Running)
+	testutils.AssertEqual(t, 2, stats.Waiting)
+
+	release()
+	// Releasing twice frees one worker only
+	release()
+	wg.Wait()
+
+	testutils.AssertTrue(t, slices.Equal([]string{"first", "second"}, started))
+	testutils.AssertTrue(t, slices.Equal([]int{2, 1}, positions))
+	stats = queueStats(t, "test_order")
+	testutils.AssertEqual(t, 0, stats.Running)
+	testutils.AssertEqual(t, 0, stats.Waiting)
+}
+
+func TestWorkpool_CancelledCallsLeaveTheQueue(t *testing.T) {
+	t.Setenv(workpool.WorkersEnvVar+"_TEST_CANCEL", "1")
+
+	release, err := workpool.Acquire(t.Context(), "test_cancel", nil)
+	testutils.AssertNoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err = workpool.Acquire(ctx, "test_cancel", nil)
+	testutils.AssertTrue(t, errors.Is(err, context.DeadlineExceeded))
+	testutils.AssertEqual(t, 0, queueStats(t, "test_cancel").Waiting)
+}
+
+func TestWorkpool_ToolsNameTheirQueues(t *testing.T) {
+	dir := t.TempDir()
+	large := filepath.Join(dir, "large.xlsx")
+	testutils.AssertNoError(t, os.WriteFile(large, make([]byte, 6<<20), 0600))
+
+	testutils.AssertEqual(t, workpool.Documents, (&docprocessing.DocumentProcessorTool{}).WorkQueue(map[string]any{}))
+	testutils.AssertEqual(t, workpool.Spreadsheets, (&excel.ExcelTool{}).WorkQueue(map[string]any{"function": "read_all_data", "filepath": large}))
+	testutils.AssertEqual(t, "", (&excel.ExcelTool{}).WorkQueue(map[string]any{"function": "write_data", "filepath": large}))
+
+	// Calls that need no worker are not queued
+	release, err := toolcall.Queue(t.Context(), &excel.ExcelTool{}, map[string]any{"function": "write_data", "filepath": large}, nil)
+	testutils.AssertNoError(t, err)
+	release()
+	for _, stats := range workpool.Snapshot() {
+		testutils.AssertTrue(t, stats.Queue != workpool.Spreadsheets || stats.Running == 0)
+	}
+
+	release, err = toolcall.Queue(t.Context(), &excel.ExcelTool{}, map[string]any{"function": "read_all_data", "filepath": large}, "token")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1, queueStats(t, workpool.Spreadsheets).Running)
+	release()
+	testutils.AssertEqual(t, 0, queueStats(t, workpool.Spreadsheets).Running)
+}
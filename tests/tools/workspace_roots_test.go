@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rootsClient answers roots/list requests with a fixed set of directories
+type rootsClient struct {
+	dirs     []string
+	requests atomic.Int32
+}
+
+func (c *rootsClient) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	c.requests.Add(1)
+	result := &mcp.ListRootsResult{}
+	for _, dir := range c.dirs {
+		result.Roots = append(result.Roots, mcp.Root{URI: "file://" + filepath.ToSlash(dir), Name: filepath.Base(dir)})
+	}
+	return result, nil
+}
+
+// sessionContext returns a context for a tool call from a client sharing the given roots
+func sessionContext(t *testing.T, client *rootsClient, declareRoots bool) (context.Context, string) {
+	t.Helper()
+	sessionID := "workspace-test-" + t.Name()
+	session := mcpserver.NewInProcessSessionWithHandlers(sessionID, nil, nil, client)
+	if declareRoots {
+		session.SetClientCapabilities(mcp.ClientCapabilities{Roots: &struct {
+			ListChanged bool `json:"listChanged,omitempty"`
+		}{ListChanged: true}})
+	}
+	t.Cleanup(func() { workspace.Forget(sessionID) })
+	srv := mcpserver.NewMCPServer("test", "1.0")
+	return srv.WithContext(context.Background(), session), sessionID
+}
+
+func TestWorkspaceRoots_CachedPerSession(t *testing.T) {
+	root := t.TempDir()
+	client := &rootsClient{dirs: []string{root}}
+	ctx, sessionID := sessionContext(t, client, true)
+
+	assert.Equal(t, []string{root}, workspace.Roots(ctx))
+	assert.Equal(t, []string{root}, workspace.Roots(ctx))
+	assert.Equal(t, int32(1), client.requests.Load(), "roots should be requested once per session")
+
+	// A roots change drops the cache
+	other := t.TempDir()
+	client.dirs = []string{other}
+	workspace.Forget(sessionID)
+	assert.Equal(t, []string{other}, workspace.Roots(ctx))
+
+	assert.Equal(t, filepath.Join(other, "src/main.go"), workspace.ResolvePath(ctx, "src/main.go"))
+	assert.Equal(t, "/abs/path", workspace.ResolvePath(ctx, "/abs/path"))
+}
+
+func TestWorkspaceRoots_ResolveToolPath(t *testing.T) {
+	root := t.TempDir()
+	ctx, _ := sessionContext(t, &rootsClient{dirs: []string{root}}, true)
+
+	resolved, err := workspace.ResolveToolPath(ctx, "path", "docs/../README.md")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "README.md"), resolved)
+
+	// Without a workspace root, relative paths are rejected and named by their parameter
+	_, err = workspace.ResolveToolPath(context.Background(), "output_path", "out.csv")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output_path must be an absolute path")
+}
+
+func TestWorkspaceRoots_ClientWithoutRoots(t *testing.T) {
+	client := &rootsClient{dirs: []string{t.TempDir()}}
+	ctx, _ := sessionContext(t, client, false)
+
+	assert.Nil(t, workspace.Roots(ctx))
+	assert.Equal(t, int32(0), client.requests.Load(), "clients that did not declare roots must not be asked")
+	assert.Equal(t, "relative.txt", workspace.ResolvePath(ctx, "relative.txt"))
+	assert.Nil(t, workspace.Roots(context.Background()))
+}
+
+func TestWorkspaceRoots_RootPaths(t *testing.T) {
+	paths := workspace.RootPaths([]mcp.Root{
+		{URI: "file:///home/user/project%20one"},
+		{URI: "https://example.com/repo"},
+		{URI: "file://remote-host/share"},
+	})
+	assert.Equal(t, []string{filepath.FromSlash("/home/user/project one")}, paths)
+}
+
+func TestWorkspaceRoots_ScopeDirectoriesNeverWidens(t *testing.T) {
+	allowed := []string{"/home/user", "/srv/code"}
+	assert.Equal(t, []string{"/home/user/project"}, workspace.ScopeDirectories([]string{"/home/user/project", "/etc"}, allowed))
+	assert.Equal(t, allowed, workspace.ScopeDirectories([]string{"/etc"}, allowed))
+	assert.Equal(t, allowed, workspace.ScopeDirectories(nil, allowed))
+	// Prefix matches must be on path boundaries
+	assert.Equal(t, allowed, workspace.ScopeDirectories([]string{"/home/username"}, allowed))
+}
+
+func TestWorkspaceRoots_IsWithinFollowsPlatform(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Project")
+	assert.True(t, workspace.IsWithin(dir, dir))
+	assert.True(t, workspace.IsWithin(filepath.Join(dir, "src", "main.go"), dir))
+	assert.False(t, workspace.IsWithin(dir+"-other", dir))
+
+	// Clients on Windows often send roots with a lower case drive letter
+	lower := filepath.Join(strings.ToLower(dir), "src")
+	assert.Equal(t, runtime.GOOS == "windows", workspace.IsWithin(lower, dir))
+}
+
+func TestWorkspaceRoots_FilesystemToolScopedToSession(t *testing.T) {
+	allowed := t.TempDir()
+	project := filepath.Join(allowed, "project")
+	other := filepath.Join(allowed, "other")
+	require.NoError(t, os.MkdirAll(project, 0700))
+	require.NoError(t, os.MkdirAll(other, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "notes.txt"), []byte("other workspace"), 0600))
+
+	tool := setupFilesystemTool(allowed)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx, _ := sessionContext(t, &rootsClient{dirs: []string{project}}, true)
+
+	// Relative paths resolve against the session's workspace root
+	_, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+		"function": "write_file",
+		"options":  map[string]any{"path": "hello.txt", "content": "hi"},
+	})
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(project, "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hi", string(data))
+
+	// Other directories within the configured allowed directories are out of scope for this session
+	_, err = tool.Execute(ctx, logger, &sync.Map{}, map[string]any{
+		"function": "read_file",
+		"options":  map[string]any{"path": filepath.Join(other, "notes.txt")},
+	})
+	assert.Error(t, err)
+
+	result, err := tool.Execute(ctx, logger, &sync.Map{}, map[string]any{"function": "list_allowed_directories"})
+	require.NoError(t, err)
+	assert.Contains(t, getTextContent(result), project)
+	assert.NotContains(t, getTextContent(result), other)
+
+	// Calls without a session keep the configured allowed directories
+	result, err = tool.Execute(context.Background(), logger, &sync.Map{}, map[string]any{
+		"function": "read_file",
+		"options":  map[string]any{"path": filepath.Join(other, "notes.txt")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "other workspace", getTextContent(result))
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sammcj/mcp-devtools/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMarkers creates empty files in dir
+func writeMarkers(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+	}
+}
+
+// listedNames returns the names of the tools the workspace filter lists
+func listedNames(ctx context.Context, names ...string) []string {
+	var tools []mcp.Tool
+	for _, name := range names {
+		tools = append(tools, mcp.NewTool(name))
+	}
+	var listed []string
+	for _, tool := range workspace.FilterTools(ctx, tools) {
+		listed = append(listed, tool.Name)
+	}
+	return listed
+}
+
+func TestWorkspaceTools_ProjectTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeMarkers(t, dir, "go.mod", "infra/main.tf", "node_modules/left-pad/package.json", ".github/Dockerfile")
+
+	// Subdirectories are looked in, but not dependencies or hidden directories
+	assert.Equal(t, []string{"go", "terraform"}, workspace.ProjectTypes([]string{dir}))
+	assert.Empty(t, workspace.ProjectTypes([]string{filepath.Join(dir, "missing")}))
+}
+
+func TestWorkspaceTools_FilterUsesSessionRoots(t *testing.T) {
+	t.Setenv(workspace.ToolFilterEnvVar, "true")
+	goRoot := t.TempDir()
+	writeMarkers(t, goRoot, "go.mod")
+	ctx, _ := sessionContext(t, &rootsClient{dirs: []string{goRoot}}, true)
+	all := []string{"terraform", "shadcn", "dep_graph", "fetch_url"}
+
+	// Roots are not requested while listing, so the working directory is used until they are known
+	workingDir := t.TempDir()
+	t.Chdir(workingDir)
+	assert.Equal(t, all, listedNames(ctx, all...), "nothing is hidden when no project type is detected")
+
+	workspace.Roots(ctx)
+	assert.Equal(t, []string{"dep_graph", "fetch_url"}, listedNames(ctx, all...))
+
+	writeMarkers(t, workingDir, "package.json")
+	assert.Equal(t, []string{"shadcn", "dep_graph", "fetch_url"}, listedNames(context.Background(), all...))
+
+	t.Setenv(workspace.ToolFilterEnvVar, "false")
+	assert.Equal(t, all, listedNames(ctx, all...))
+}
+
+func TestWorkspaceTools_Rules(t *testing.T) {
+	t.Setenv(workspace.ToolFilterEnvVar, "true")
+	t.Setenv(workspace.ToolRulesEnvVar, "run-tests=go|Python, terraform=, broken")
+	dir := t.TempDir()
+	writeMarkers(t, dir, "pyproject.toml")
+	t.Chdir(dir)
+
+	rules := workspace.ToolRules()
+	assert.Equal(t, []string{"go", "python"}, rules["run_tests"])
+	assert.Empty(t, rules["terraform"])
+	assert.Equal(t, []string{"terraform"}, rules["terraform_documentation"])
+
+	assert.Equal(t, []string{"run_tests", "terraform"}, listedNames(context.Background(), "run_tests", "terraform", "terraform_documentation"))
+}
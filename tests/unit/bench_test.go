@@ -0,0 +1,88 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/bench"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestBench_RunMeasuresAndSkips(t *testing.T) {
+	runs := 0
+	workloads := []bench.Workload{
+		{
+			Name: "counting",
+			Setup: func(ctx context.Context, dir string) (func(ctx context.Context) error, error) {
+				return func(ctx context.Context) error {
+					runs++
+					_ = make([]byte, 1024)
+					return nil
+				}, nil
+			},
+		},
+		{
+			Name: "unavailable",
+			Setup: func(ctx context.Context, dir string) (func(ctx context.Context) error, error) {
+				return nil, &bench.SkipError{Reason: "missing prerequisite"}
+			},
+		},
+	}
+
+	report, err := bench.Run(context.Background(), workloads, 3, "test")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, len(report.Results))
+	// One warm-up run plus the timed runs
+	testutils.AssertEqual(t, 4, runs)
+	testutils.AssertEqual(t, 3, report.Results[0].Iterations)
+	testutils.AssertEqual(t, true, report.Results[0].MinNs <= report.Results[0].NsPerOp)
+	testutils.AssertEqual(t, "missing prerequisite", report.Results[1].Skipped)
+
+	failing := []bench.Workload{{
+		Name: "failing",
+		Setup: func(ctx context.Context, dir string) (func(ctx context.Context) error, error) {
+			return func(ctx context.Context) error { return errors.New("boom") }, nil
+		},
+	}}
+	_, err = bench.Run(context.Background(), failing, 1, "test")
+	testutils.AssertError(t, err)
+}
+
+func TestBench_CompareAndReports(t *testing.T) {
+	baseline := &bench.Report{Version: "v1", Results: []bench.Result{
+		{Name: "steady", NsPerOp: 1000, AllocsPerOp: 100},
+		{Name: "slower", NsPerOp: 1000, AllocsPerOp: 100},
+		{Name: "skipped", Skipped: "missing"},
+	}}
+	current := &bench.Report{Version: "v2", Results: []bench.Result{
+		{Name: "steady", NsPerOp: 1100, AllocsPerOp: 100},
+		{Name: "slower", NsPerOp: 1500, AllocsPerOp: 200},
+		{Name: "skipped", NsPerOp: 1000},
+		{Name: "new", NsPerOp: 1000},
+	}}
+
+	regressions := bench.Compare(baseline, current, 20)
+	testutils.AssertEqual(t, 2, len(regressions))
+	testutils.AssertEqual(t, "slower", regressions[0].Name)
+	testutils.AssertEqual(t, "ns_per_op", regressions[0].Metric)
+	testutils.AssertEqual(t, 50.0, regressions[0].Change)
+	testutils.AssertEqual(t, "allocs_per_op", regressions[1].Metric)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	testutils.AssertNoError(t, bench.SaveReport(current, path))
+	loaded, err := bench.LoadReport(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "v2", loaded.Version)
+	testutils.AssertEqual(t, 4, len(loaded.Results))
+}
+
+func TestBench_Workloads(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the benchmark workloads")
+	}
+	report, err := bench.Run(context.Background(), bench.Workloads(), 1, "test")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, len(bench.Workloads()), len(report.Results))
+}
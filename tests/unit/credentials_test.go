@@ -0,0 +1,77 @@
+package unit_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/zalando/go-keyring"
+)
+
+func TestCredentials_SetListGetDelete(t *testing.T) {
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	names, err := credentials.List()
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, len(names))
+
+	testutils.AssertNoError(t, credentials.Set("zeta-token", "first"))
+	testutils.AssertNoError(t, credentials.Set("alpha-token", "second"))
+	testutils.AssertNoError(t, credentials.Set("zeta-token", "replaced"))
+
+	names, err = credentials.List()
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, slices.Equal(names, []string{"alpha-token", "zeta-token"}))
+
+	value, err := credentials.Get("zeta-token")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "replaced", value)
+
+	// Only the names are written to disk, readable by the owner alone
+	indexPath := filepath.Join(home, ".mcp-devtools", "credentials.json")
+	data, err := os.ReadFile(indexPath)
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(data), "alpha-token"))
+	testutils.AssertFalse(t, strings.Contains(string(data), "second") || strings.Contains(string(data), "replaced"))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(indexPath)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	testutils.AssertNoError(t, credentials.Delete("zeta-token"))
+	_, err = credentials.Get("zeta-token")
+	testutils.AssertTrue(t, errors.Is(err, credentials.ErrNotFound))
+	err = credentials.Delete("zeta-token")
+	testutils.AssertTrue(t, errors.Is(err, credentials.ErrNotFound))
+
+	names, err = credentials.List()
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, slices.Equal(names, []string{"alpha-token"}))
+
+	testutils.AssertError(t, credentials.Set("", "value"))
+	testutils.AssertError(t, credentials.Set("empty-value", ""))
+}
+
+func TestCredentials_LookupPrefersEnvironment(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CREDENTIALS_TEST_TOKEN", "")
+
+	testutils.AssertEqual(t, "", credentials.Lookup("CREDENTIALS_TEST_TOKEN", "lookup-token"))
+
+	testutils.AssertNoError(t, credentials.Set("lookup-token", "from-keyring"))
+	testutils.AssertEqual(t, "from-keyring", credentials.Lookup("CREDENTIALS_TEST_TOKEN", "lookup-token"))
+
+	t.Setenv("CREDENTIALS_TEST_TOKEN", "from-env")
+	testutils.AssertEqual(t, "from-env", credentials.Lookup("CREDENTIALS_TEST_TOKEN", "lookup-token"))
+}
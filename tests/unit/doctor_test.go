@@ -0,0 +1,70 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/doctor"
+	"github.com/sammcj/mcp-devtools/internal/registry"
+	"github.com/sammcj/mcp-devtools/internal/tools"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// checkingTool is a mock tool that reports fixed prerequisite checks
+type checkingTool struct {
+	*testutils.MockTool
+	checks []tools.PrerequisiteCheck
+}
+
+func (t *checkingTool) CheckPrerequisites(_ context.Context) []tools.PrerequisiteCheck {
+	return t.checks
+}
+
+// resultsFor returns the results for the named tool
+func resultsFor(results []doctor.Result, tool string) []doctor.Result {
+	var matching []doctor.Result
+	for _, result := range results {
+		if result.Tool == tool {
+			matching = append(matching, result)
+		}
+	}
+	return matching
+}
+
+func TestDoctor_Run(t *testing.T) {
+	defer testutils.WithEnv(t, "ENABLE_ADDITIONAL_TOOLS", "doctor-enabled,doctor-unavailable")()
+
+	logger := testutils.CreateTestLogger()
+	registry.Init(logger)
+
+	registry.Register(&checkingTool{MockTool: testutils.NewMockTool("doctor-enabled"), checks: []tools.PrerequisiteCheck{
+		{Name: "binary", Status: tools.PrerequisiteOK, Detail: "found"},
+		{Name: "token", Status: tools.PrerequisiteWarning, Detail: "not set", Remediation: "set it"},
+	}})
+	registry.Register(&checkingTool{MockTool: testutils.NewMockTool("doctor-not-enabled"), checks: []tools.PrerequisiteCheck{
+		{Name: "binary", Status: tools.PrerequisiteFailed, Detail: "missing"},
+	}})
+	registry.RegisterUnavailable("doctor-unavailable", "python not found")
+	registry.RegisterUnavailable("doctor-unavailable-not-enabled", "python not found")
+
+	results := doctor.Run(context.Background(), false)
+	testutils.AssertEqual(t, 2, len(resultsFor(results, "doctor-enabled")))
+	testutils.AssertEqual(t, 0, len(resultsFor(results, "doctor-not-enabled")))
+	testutils.AssertEqual(t, 0, len(resultsFor(results, "doctor-unavailable-not-enabled")))
+
+	// Tools that are enabled but could not be created are failures
+	unavailable := resultsFor(results, "doctor-unavailable")
+	testutils.AssertEqual(t, 1, len(unavailable))
+	testutils.AssertEqual(t, tools.PrerequisiteFailed, unavailable[0].Status)
+	testutils.AssertEqual(t, "python not found", unavailable[0].Detail)
+
+	passed, warnings, failed := doctor.Summarise(resultsFor(results, "doctor-enabled"))
+	testutils.AssertEqual(t, 1, passed)
+	testutils.AssertEqual(t, 1, warnings)
+	testutils.AssertEqual(t, 0, failed)
+
+	// All also checks tools that are not enabled
+	results = doctor.Run(context.Background(), true)
+	testutils.AssertEqual(t, 1, len(resultsFor(results, "doctor-not-enabled")))
+	testutils.AssertEqual(t, 1, len(resultsFor(results, "doctor-unavailable-not-enabled")))
+}
@@ -0,0 +1,189 @@
+package unit_test
+
+import (
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/utils/httpclient"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+func TestHTTPClient_RetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxRetries: 2})
+	resp, err := client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "ok", string(body))
+	testutils.AssertEqual(t, int32(3), requests.Load())
+
+	// Requests with a body are not safe to send twice, so are never retried
+	requests.Store(0)
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	testutils.AssertNoError(t, err)
+	_ = resp.Body.Close()
+	testutils.AssertEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+	testutils.AssertEqual(t, int32(1), requests.Load())
+
+	// Once retries are exhausted the last response is returned
+	requests.Store(-10)
+	resp, err = client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	_ = resp.Body.Close()
+	testutils.AssertEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+	testutils.AssertEqual(t, int32(-7), requests.Load())
+}
+
+func TestHTTPClient_LimitsResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 1024)))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxResponseBytes: 100})
+	resp, err := client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	testutils.AssertTrue(t, errors.Is(err, httpclient.ErrResponseTooLarge))
+
+	// A body of exactly the limit is read in full
+	client = httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxResponseBytes: 1024})
+	resp, err = client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 1024, len(body))
+}
+
+func TestHTTPClient_TrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	t.Setenv(httpclient.CABundleEnvVar, "")
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxRetries: -1})
+	_, err := client.Get(server.URL)
+	testutils.AssertError(t, err)
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	testutils.AssertNoError(t, os.WriteFile(bundle, certificate, 0600))
+	t.Setenv(httpclient.CABundleEnvVar, bundle)
+
+	client = httpclient.New(httpclient.Options{Timeout: 10 * time.Second, MaxRetries: -1})
+	resp, err := client.Get(server.URL)
+	testutils.AssertNoError(t, err)
+	_ = resp.Body.Close()
+	testutils.AssertEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPClient_CachesResponses(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	var requests, revalidated atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Path == "/fresh" {
+			w.Header().Set("Cache-Control", "max-age=3600")
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("page " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Cache: true})
+	get := func(path string) (string, http.Header) {
+		t.Helper()
+		resp, err := client.Get(server.URL + path)
+		testutils.AssertNoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, http.StatusOK, resp.StatusCode)
+		return string(body), resp.Header
+	}
+
+	// A fresh response is served from the cache without contacting the server
+	body, _ := get("/fresh")
+	testutils.AssertEqual(t, "page /fresh", body)
+	body, header := get("/fresh")
+	testutils.AssertEqual(t, "page /fresh", body)
+	testutils.AssertEqual(t, "1", header.Get("X-From-Cache"))
+	testutils.AssertEqual(t, int32(1), requests.Load())
+
+	// A response with only a validator is revalidated, and a 304 returns the cached body
+	get("/etag")
+	body, header = get("/etag")
+	testutils.AssertEqual(t, "page /etag", body)
+	testutils.AssertEqual(t, "1", header.Get("X-From-Cache"))
+	testutils.AssertEqual(t, int32(3), requests.Load())
+	testutils.AssertEqual(t, int32(1), revalidated.Load())
+
+	// Clearing one host only removes its responses
+	removed, _, err := httpclient.ClearCache("other.example.com")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 0, removed)
+	removed, freed, err := httpclient.ClearCache("127.0.0.1")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, 2, removed)
+	testutils.AssertTrue(t, freed > 0)
+
+	get("/fresh")
+	testutils.AssertEqual(t, int32(4), requests.Load())
+}
+
+func TestHTTPClient_DoesNotCacheCredentialedRequests(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write([]byte("private"))
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Cache: true})
+	for range 2 {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		testutils.AssertNoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := client.Do(req)
+		testutils.AssertNoError(t, err)
+		_ = resp.Body.Close()
+	}
+	testutils.AssertEqual(t, int32(2), requests.Load())
+}
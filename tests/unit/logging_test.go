@@ -0,0 +1,183 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/logging"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRotatingFile_RotatesBySizeAndKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	file, err := logging.OpenRotatingFile(path, logging.RotationConfig{MaxSize: 100, MaxBackups: 2})
+	testutils.AssertNoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	line := strings.Repeat("x", 59) + "\n"
+	for i := range 5 {
+		_, err := fmt.Fprintf(file, "%d%s", i, line)
+		testutils.AssertNoError(t, err)
+	}
+
+	// Each write pushes the file past 100 bytes, so every line starts a new file
+	current, err := os.ReadFile(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "4"+line, string(current))
+	backup, err := os.ReadFile(path + ".1")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "3"+line, string(backup))
+	backup, err = os.ReadFile(path + ".2")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "2"+line, string(backup))
+	_, err = os.Stat(path + ".3")
+	testutils.AssertTrue(t, os.IsNotExist(err))
+
+	info, err := os.Stat(path)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestRotatingFile_RotatesStaleFileOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	testutils.AssertNoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+	old := time.Now().Add(-48 * time.Hour)
+	testutils.AssertNoError(t, os.Chtimes(path, old, old))
+
+	file, err := logging.OpenRotatingFile(path, logging.RotationConfig{Interval: 24 * time.Hour, MaxBackups: 1})
+	testutils.AssertNoError(t, err)
+	_, err = file.Write([]byte("new\n"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, file.Close())
+
+	current, _ := os.ReadFile(path)
+	backup, _ := os.ReadFile(path + ".1")
+	testutils.AssertEqual(t, "new\n", string(current))
+	testutils.AssertEqual(t, "old\n", string(backup))
+
+	_, err = file.Write([]byte("after close\n"))
+	testutils.AssertError(t, err)
+}
+
+func TestLogging_ConfigFromEnv(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_MAX_SIZE_MB", "2")
+	t.Setenv("LOG_MAX_BACKUPS", "not a number")
+	t.Setenv("LOG_ROTATE_INTERVAL", "24h")
+	t.Setenv("LOG_TOOL_FILES", "true")
+
+	config := logging.ConfigFromEnv()
+	testutils.AssertEqual(t, "json", config.Format)
+	testutils.AssertEqual(t, int64(2*1024*1024), config.Rotation.MaxSize)
+	testutils.AssertEqual(t, logging.DefaultMaxBackups, config.Rotation.MaxBackups)
+	testutils.AssertEqual(t, 24*time.Hour, config.Rotation.Interval)
+	testutils.AssertTrue(t, config.ToolFiles)
+	_, isJSON := config.Formatter().(*logrus.JSONFormatter)
+	testutils.AssertTrue(t, isJSON)
+}
+
+func TestLogging_ForToolAppliesLevelOverride(t *testing.T) {
+	defer logging.Close()
+	testutils.AssertEqual(t, "LOG_LEVEL_INTERNET_SEARCH", logging.ToolLevelEnvVar("internet_search"))
+	t.Setenv("LOG_LEVEL_TEST_DEBUG_TOOL", "debug")
+
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	base.SetLevel(logrus.WarnLevel)
+
+	logging.ForTool(base, "test_debug_tool").Debug("shown")
+	logging.ForTool(base, "test_quiet_tool").Debug("hidden")
+	testutils.AssertEqual(t, logging.ForTool(base, "test_debug_tool"), logging.ForTool(base, "test_debug_tool"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutils.AssertEqual(t, 1, len(lines))
+	var entry map[string]any
+	testutils.AssertNoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	testutils.AssertEqual(t, "shown", entry["msg"])
+	testutils.AssertEqual(t, "test_debug_tool", entry["tool"])
+}
+
+func TestLogging_ForToolWritesToolFiles(t *testing.T) {
+	defer logging.Close()
+	dir := t.TempDir()
+	logging.ConfigureTools(logging.Config{ToolFiles: true}, dir)
+	defer logging.ConfigureTools(logging.Config{}, "")
+
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetLevel(logrus.InfoLevel)
+	logging.ForTool(base, "test_file_tool").Info("to file")
+
+	testutils.AssertEqual(t, 0, buf.Len())
+	content, err := os.ReadFile(logging.ToolLogPath(dir, "test_file_tool"))
+	testutils.AssertNoError(t, err)
+	testutils.AssertTrue(t, strings.Contains(string(content), "to file"))
+}
+
+func TestLogging_TailFollowsAcrossRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	var lines []string
+	for i := range 10 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	testutils.AssertNoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	last, err := logging.LastLines(path, 3)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "line 7,line 8,line 9", strings.Join(last, ","))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- logging.Tail(ctx, path, 1, true, out) }()
+	waitForOutput(out, "line 9")
+
+	// The existing file is over the size limit, so it is rotated when opened
+	file, err := logging.OpenRotatingFile(path, logging.RotationConfig{MaxSize: 1, MaxBackups: 1})
+	testutils.AssertNoError(t, err)
+	defer func() { _ = file.Close() }()
+	_, _ = file.Write([]byte("after rotation\n"))
+
+	waitForOutput(out, "after rotation")
+	cancel()
+	testutils.AssertNoError(t, <-done)
+	testutils.AssertEqual(t, "line 9\nafter rotation\n", out.String())
+}
+
+// waitForOutput waits up to five seconds for the buffer to contain want
+func waitForOutput(out *syncBuffer, want string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), want) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for a writer and reader in different goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -373,3 +373,74 @@ func TestRegistry_DisabledByDefault_Tools(t *testing.T) {
 		}
 	})
 }
+
+func TestRegistry_GetToolStatuses(t *testing.T) {
+	defer testutils.WithEnv(t, "ENABLE_ADDITIONAL_TOOLS", "status-enabled")()
+	defer testutils.WithEnv(t, "DISABLED_TOOLS", "status-disabled")()
+
+	logger := testutils.CreateTestLogger()
+	registry.Init(logger)
+
+	registry.Register(testutils.NewMockTool("status-enabled"))
+	registry.Register(testutils.NewMockTool("status-not-enabled"))
+	registry.Register(testutils.NewMockTool("status-disabled"))
+	registry.RegisterUnavailable("status-unavailable", "missing prerequisite")
+
+	// Tools that are not enabled are still reported, with the reason
+	status, ok := registry.GetToolStatus("status-not-enabled")
+	testutils.AssertEqual(t, true, ok)
+	testutils.AssertEqual(t, false, status.Enabled)
+	testutils.AssertEqual(t, "not enabled by default, add status-not-enabled to ENABLE_ADDITIONAL_TOOLS", status.Reason)
+	testutils.AssertNotNil(t, status.Tool)
+
+	status, _ = registry.GetToolStatus("status_enabled")
+	testutils.AssertEqual(t, true, status.Enabled)
+	testutils.AssertEqual(t, "enabled by ENABLE_ADDITIONAL_TOOLS", status.Reason)
+
+	status, _ = registry.GetToolStatus("status-disabled")
+	testutils.AssertEqual(t, false, status.Enabled)
+	testutils.AssertEqual(t, "disabled by DISABLED_TOOLS", status.Reason)
+
+	status, _ = registry.GetToolStatus("status-unavailable")
+	testutils.AssertEqual(t, false, status.Enabled)
+	testutils.AssertEqual(t, "missing prerequisite", status.Reason)
+	testutils.AssertEqual(t, true, status.Tool == nil)
+
+	_, ok = registry.GetToolStatus("status-unknown")
+	testutils.AssertEqual(t, false, ok)
+
+	// Statuses are sorted by name
+	statuses := registry.GetToolStatuses()
+	for i := 1; i < len(statuses); i++ {
+		testutils.AssertEqual(t, true, statuses[i-1].Name < statuses[i].Name)
+	}
+}
+
+func TestRegistry_CheckAvailability(t *testing.T) {
+	defer testutils.WithEnv(t, "ENABLE_ADDITIONAL_TOOLS", "check-available,check-missing")()
+
+	logger := testutils.CreateTestLogger()
+	registry.Init(logger)
+
+	checked := 0
+	registry.Register(testutils.NewMockTool("check-available"))
+	registry.Register(testutils.NewMockTool("check-missing"))
+	registry.RegisterAvailabilityCheck("check-available", func() string { checked++; return "" })
+	registry.RegisterAvailabilityCheck("check-missing", func() string { checked++; return "helper not installed" })
+
+	// Checks are slow, so they are only run when asked for
+	testutils.AssertEqual(t, 0, checked)
+	_, ok := registry.GetTool("check-missing")
+	testutils.AssertEqual(t, true, ok)
+
+	registry.CheckAvailability()
+	testutils.AssertEqual(t, 2, checked)
+
+	_, ok = registry.GetTool("check-available")
+	testutils.AssertEqual(t, true, ok)
+	_, ok = registry.GetTool("check-missing")
+	testutils.AssertEqual(t, false, ok)
+	status, _ := registry.GetToolStatus("check-missing")
+	testutils.AssertEqual(t, false, status.Enabled)
+	testutils.AssertEqual(t, "helper not installed", status.Reason)
+}
@@ -0,0 +1,70 @@
+package unit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sammcj/mcp-devtools/internal/credentials"
+	"github.com/sammcj/mcp-devtools/internal/secrets"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+	"github.com/zalando/go-keyring"
+)
+
+func TestSecrets_ResolveReferences(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("HOME", t.TempDir())
+	testutils.AssertNoError(t, credentials.Set("github-token", "from-keychain"))
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	testutils.AssertNoError(t, os.WriteFile(secretFile, []byte("from-file\n"), 0600))
+	t.Setenv("SECRETS_TEST_SOURCE", "from-env")
+
+	value, err := secrets.Resolve("${env:SECRETS_TEST_SOURCE}")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "from-env", value)
+
+	value, err = secrets.Resolve("${file:" + secretFile + "}")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "from-file", value)
+
+	value, err = secrets.Resolve("${keychain:github-token}")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "from-keychain", value)
+
+	// References are replaced within a larger value and plain values are returned unchanged
+	value, err = secrets.Resolve("Bearer ${env:SECRETS_TEST_SOURCE}")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "Bearer from-env", value)
+
+	value, err = secrets.Resolve("plain-value")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "plain-value", value)
+
+	_, err = secrets.Resolve("${keychain:missing}")
+	testutils.AssertErrorContains(t, err, "credential not found: missing")
+	_, err = secrets.Resolve("${env:SECRETS_TEST_UNSET}")
+	testutils.AssertErrorContains(t, err, "environment variable is not set")
+	_, err = secrets.Resolve("${file:" + filepath.Join(t.TempDir(), "missing") + "}")
+	testutils.AssertError(t, err)
+}
+
+func TestSecrets_ResolveEnvironment(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("HOME", t.TempDir())
+	testutils.AssertNoError(t, credentials.Set("api-key", "resolved-key"))
+
+	t.Setenv("SECRETS_TEST_API_KEY", "${keychain:api-key}")
+	t.Setenv("SECRETS_TEST_BROKEN", "${keychain:missing}")
+	t.Setenv("SECRETS_TEST_PLAIN", "unchanged")
+
+	err := secrets.ResolveEnvironment()
+	testutils.AssertErrorContains(t, err, "SECRETS_TEST_BROKEN")
+
+	testutils.AssertEqual(t, "resolved-key", os.Getenv("SECRETS_TEST_API_KEY"))
+	testutils.AssertEqual(t, "unchanged", os.Getenv("SECRETS_TEST_PLAIN"))
+
+	// A variable whose reference cannot be resolved is unset rather than left holding the reference
+	_, set := os.LookupEnv("SECRETS_TEST_BROKEN")
+	testutils.AssertFalse(t, set)
+}
@@ -0,0 +1,176 @@
+package unit_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sammcj/mcp-devtools/internal/supervisor"
+	"github.com/sammcj/mcp-devtools/tests/testutils"
+)
+
+// requireShell skips tests that run shell scripts on Windows
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+}
+
+// processGone reports whether a process has exited, counting a zombie waiting to be reaped by init
+// as exited
+func processGone(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true
+	}
+	// The state follows the command name, which is in parentheses
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] == "Z"
+}
+
+func TestSupervisor_RunCapsOutput(t *testing.T) {
+	requireShell(t)
+	result, err := supervisor.Run(t.Context(), supervisor.Spec{
+		Name:      "test",
+		Path:      "sh",
+		Args:      []string{"-c", `printf 0123456789; printf abcdefghij >&2; read line; printf "$line" >&2; exit 3`},
+		Stdin:     strings.NewReader("XYZ\n"),
+		MaxStdout: 4,
+		MaxStderr: 5,
+	})
+	var exitErr *exec.ExitError
+	testutils.AssertTrue(t, errors.As(err, &exitErr))
+	testutils.AssertEqual(t, 3, result.ExitCode)
+	testutils.AssertEqual(t, "0123", result.Stdout)
+	testutils.AssertEqual(t, int64(10), result.StdoutBytes)
+	testutils.AssertTrue(t, result.StdoutTruncated())
+	// The end of the error output is kept
+	testutils.AssertEqual(t, "ijXYZ", result.Stderr)
+	testutils.AssertEqual(t, int64(13), result.StderrBytes)
+
+	result, err = supervisor.Run(t.Context(), supervisor.Spec{Name: "test", Path: "sh", Args: []string{"-c", "echo ok"}, MaxStdout: -1})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "ok\n", result.Stdout)
+	testutils.AssertFalse(t, result.StdoutTruncated())
+}
+
+func TestSupervisor_RunLeavesNoProcessesBehind(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads /proc")
+	}
+	// A background process started by the command is killed once the command exits
+	result, err := supervisor.Run(t.Context(), supervisor.Spec{Name: "test", Path: "sh", Args: []string{"-c", "sleep 30 >/dev/null & echo $!"}})
+	testutils.AssertNoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(result.Stdout))
+	testutils.AssertNoError(t, err)
+	deadline := time.Now().Add(5 * time.Second)
+	for !processGone(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("background process %d is still running", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Cancelling a command kills it and its children
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = supervisor.Run(ctx, supervisor.Spec{Name: "test", Path: "sh", Args: []string{"-c", "sleep 30 & sleep 30"}})
+	testutils.AssertTrue(t, err != nil)
+	testutils.AssertTrue(t, time.Since(start) < 10*time.Second)
+}
+
+func TestSupervisor_AppliesResourceLimits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only applied on Linux")
+	}
+	// The shell reads its limits after they are applied, as it waits for its input first
+	result, err := supervisor.Run(t.Context(), supervisor.Spec{
+		Name:   "test",
+		Path:   "sh",
+		Args:   []string{"-c", "read line; ulimit -v; ulimit -t; ulimit -n"},
+		Stdin:  strings.NewReader("go\n"),
+		Limits: supervisor.Limits{MemoryBytes: 512 << 20, CPUSeconds: 60, OpenFiles: 100},
+	})
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "524288\n60\n100\n", result.Stdout)
+}
+
+func TestSupervisor_StartAndStop(t *testing.T) {
+	requireShell(t)
+	process, err := supervisor.Start(supervisor.Spec{Name: "test-echo", Path: "cat"})
+	testutils.AssertNoError(t, err)
+
+	_, err = fmt.Fprintln(process.Stdin(), "hello")
+	testutils.AssertNoError(t, err)
+	line, err := bufio.NewReader(process.Stdout()).ReadString('\n')
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, "hello\n", line)
+
+	found := false
+	for _, info := range supervisor.Snapshot() {
+		found = found || (info.Name == "test-echo" && info.PID == process.PID())
+	}
+	testutils.AssertTrue(t, found)
+
+	process.Stop(time.Second)
+	select {
+	case <-process.Done():
+	default:
+		t.Fatal("expected the process to be done once stopped")
+	}
+	for _, info := range supervisor.Snapshot() {
+		testutils.AssertTrue(t, info.Name != "test-echo")
+	}
+	_ = process.Stdout().Close()
+}
+
+func TestSupervisor_RestartsCrashedProcesses(t *testing.T) {
+	requireShell(t)
+	var restarts atomic.Int32
+	process, err := supervisor.Start(supervisor.Spec{
+		Name: "test-crash",
+		Path: "sh",
+		Args: []string{"-c", "echo failing >&2; exit 1"},
+		Restart: supervisor.RestartPolicy{
+			MaxRestarts: 2,
+			Backoff:     10 * time.Millisecond,
+			OnRestart:   func(*supervisor.Process) { restarts.Add(1) },
+		},
+	})
+	testutils.AssertNoError(t, err)
+
+	select {
+	case <-process.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the process to give up after its restarts")
+	}
+	testutils.AssertEqual(t, int32(2), restarts.Load())
+	testutils.AssertEqual(t, 2, process.Restarts())
+	testutils.AssertTrue(t, process.Err() != nil)
+	testutils.AssertEqual(t, "failing\n", process.Stderr())
+
+	// Stopping a process waiting to restart ends it without waiting out the backoff
+	process, err = supervisor.Start(supervisor.Spec{
+		Name:    "test-backoff",
+		Path:    "sh",
+		Args:    []string{"-c", "exit 1"},
+		Restart: supervisor.RestartPolicy{MaxRestarts: 5, Backoff: time.Minute},
+	})
+	testutils.AssertNoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	process.Stop(time.Second)
+	testutils.AssertTrue(t, time.Since(start) < 10*time.Second)
+	testutils.AssertEqual(t, 0, process.Restarts())
+}